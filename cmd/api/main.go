@@ -10,12 +10,11 @@ import (
 	"time"
 
 	"github.com/devchuckcamp/goauthx"
-	"github.com/devchuckcamp/gocommerce/pricing"
 
+	"github.com/devchuckcamp/gocommerce-api/internal/app"
 	"github.com/devchuckcamp/gocommerce-api/internal/config"
 	"github.com/devchuckcamp/gocommerce-api/internal/database"
 	httpserver "github.com/devchuckcamp/gocommerce-api/internal/http"
-	"github.com/devchuckcamp/gocommerce-api/internal/repository"
 	"github.com/devchuckcamp/gocommerce-api/internal/services"
 )
 
@@ -75,100 +74,128 @@ func main() {
 		}
 	}
 
-	authService, err := goauthx.NewService(authxConfig, authStore)
+	application, err := app.New(cfg, db, authStore, seeder)
 	if err != nil {
-		log.Fatalf("Failed to create auth service: %v", err)
-	}
-
-	log.Println("Authentication service initialized")
-
-	// Initialize repositories
-	productRepo := repository.NewProductRepository(db.DB)
-	variantRepo := repository.NewVariantRepository(db.DB)
-	categoryRepo := repository.NewCategoryRepository(db.DB)
-	brandRepo := repository.NewBrandRepository(db.DB)
-	cartRepo := repository.NewCartRepository(db.DB)
-	orderRepo := repository.NewOrderRepository(db.DB)
-	promotionRepo := repository.NewPromotionRepository(db.DB)
-	productPriceRepo := repository.NewProductPriceRepository(db.DB)
-
-	log.Println("Repositories initialized")
-
-	// Initialize services
-	// Tax calculator (8.75% tax rate for example)
-	taxCalculator := services.NewSimpleTaxCalculator(0.0875)
-
-	// Create catalog service with sale price resolver
-	catalogService := services.NewCatalogService(
-		productRepo,
-		variantRepo,
-		categoryRepo,
-		brandRepo,
-	).WithSalePriceResolver(productPriceRepo)
-
-	// Create price resolver service for dynamic pricing
-	priceResolverService := pricing.NewPriceResolverService(
-		productPriceRepo,
-		productRepo,
-		variantRepo,
-	)
-	priceResolverAdapter := pricing.NewCartPriceResolverAdapter(priceResolverService)
-
-	// Create cart service with price resolver (no inventory service for now)
-	cartService := services.NewCartService(
-		cartRepo,
-		productRepo,
-		variantRepo,
-		nil, // inventoryService
-	).WithPriceResolver(priceResolverAdapter)
-
-	// Create pricing service (no shipping calculator for now)
-	pricingService := services.NewPricingService(
-		promotionRepo,
-		taxCalculator,
-		nil, // shippingCalculator
-	)
-
-	// Create order service (no inventory or payment gateway for now)
-	orderService := services.NewOrderService(
-		orderRepo,
-		pricingService.Service,
-		nil, // inventoryService
-		nil, // paymentGateway
-	)
-
-	log.Println("Domain services initialized")
-
-	// Create HTTP server
-	server := httpserver.NewServer(
-		authService,
-		authStore,
-		seeder,
-		catalogService,
-		cartService,
-		orderService,
-	)
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
 
 	// Setup HTTP server
 	httpSrv := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
-		Handler:      server.Router(),
+		Handler:      application.Server.Router(),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	// Terminate TLS here when certificates are configured, for deployments without a proxy
+	serverTLS, certManager, err := httpserver.NewTLSConfig(httpserver.TLSOptions{
+		CertFile:         cfg.Server.TLSCertFile,
+		KeyFile:          cfg.Server.TLSKeyFile,
+		AutocertDomains:  cfg.Server.AutocertDomains,
+		AutocertCacheDir: cfg.Server.AutocertCacheDir,
+		AutocertEmail:    cfg.Server.AutocertEmail,
+		MinVersion:       cfg.Server.TLSMinVersion,
+		HTTP2:            cfg.Server.HTTP2,
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+	httpserver.ConfigureServer(httpSrv, serverTLS, cfg.Server.HTTP2)
+
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Server starting on port %s", cfg.Server.Port)
-		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if serverTLS != nil {
+			log.Printf("Server starting on port %s (HTTPS, HTTP/2 %t)", cfg.Server.Port, cfg.Server.HTTP2)
+			err = httpSrv.ListenAndServeTLS("", "")
+		} else {
+			log.Printf("Server starting on port %s", cfg.Server.Port)
+			err = httpSrv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
+	// Plain HTTP redirects to HTTPS, and answers Let's Encrypt challenges with autocert
+	var redirectSrv *http.Server
+	if serverTLS != nil && cfg.Server.HTTPRedirectAddr != "" {
+		redirectSrv = &http.Server{
+			Addr:         cfg.Server.HTTPRedirectAddr,
+			Handler:      httpserver.RedirectToHTTPS(certManager, cfg.Server.Port),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+		go func() {
+			log.Printf("Redirecting HTTP on %s to HTTPS", cfg.Server.HTTPRedirectAddr)
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("HTTP redirect server failed: %v", err)
+			}
+		}()
+	}
+
+	// Diagnostics listen on their own private address so profiles never reach the public port.
+	// No write timeout: CPU profiles and traces stream for as long as they were asked to run.
+	var diagSrv *http.Server
+	if application.Diagnostics != nil {
+		diagTLS, _, err := httpserver.NewTLSConfig(httpserver.TLSOptions{
+			CertFile:     cfg.Diagnostics.TLSCertFile,
+			KeyFile:      cfg.Diagnostics.TLSKeyFile,
+			MinVersion:   cfg.Server.TLSMinVersion,
+			HTTP2:        cfg.Server.HTTP2,
+			ClientCAFile: cfg.Diagnostics.ClientCAFile,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure diagnostics TLS: %v", err)
+		}
+		diagSrv = &http.Server{
+			Addr:        cfg.Diagnostics.Addr,
+			Handler:     application.Diagnostics,
+			ReadTimeout: cfg.Server.ReadTimeout,
+			IdleTimeout: cfg.Server.IdleTimeout,
+		}
+		httpserver.ConfigureServer(diagSrv, diagTLS, cfg.Server.HTTP2)
+		go func() {
+			var err error
+			if diagTLS != nil {
+				log.Printf("Diagnostics (pprof, expvar) listening on %s (HTTPS, client certificates required: %t)", cfg.Diagnostics.Addr, cfg.Diagnostics.ClientCAFile != "")
+				err = diagSrv.ListenAndServeTLS("", "")
+			} else {
+				log.Printf("Diagnostics (pprof, expvar) listening on %s", cfg.Diagnostics.Addr)
+				err = diagSrv.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				log.Printf("Diagnostics server failed: %v", err)
+			}
+		}()
+	}
+
+	// Start background jobs and pick up webhooks stored but not processed before the last shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	application.Start(jobsCtx)
+
+	// SIGHUP reloads the runtime settings (maintenance mode, rate limits, tax rates and
+	// feature flags) from .env without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if _, err := application.RuntimeConfig.Reload(jobsCtx, services.ConfigChangeSourceSignal, nil); err != nil {
+				log.Printf("Failed to reload configuration: %v", err)
+			}
+		}
+	}()
+
 	log.Println("E-Commerce API is running")
-	log.Printf("API available at http://localhost:%s/api/v1", cfg.Server.Port)
-	log.Printf("Health check: http://localhost:%s/health", cfg.Server.Port)
+	scheme := "http"
+	if serverTLS != nil {
+		scheme = "https"
+	}
+	log.Printf("API available at %s://localhost:%s/api/v1", scheme, cfg.Server.Port)
+	log.Printf("Health check: %s://localhost:%s/health", scheme, cfg.Server.Port)
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -184,6 +211,19 @@ func main() {
 	if err := httpSrv.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	if redirectSrv != nil {
+		if err := redirectSrv.Shutdown(ctx); err != nil {
+			log.Printf("HTTP redirect server forced to shutdown: %v", err)
+		}
+	}
+	if diagSrv != nil {
+		// Don't wait out a running profile
+		diagSrv.Close()
+	}
+
+	// Let running jobs finish
+	stopJobs()
+	application.Wait()
 
 	log.Println("Server exited")
 }
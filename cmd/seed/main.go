@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/config"
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+func main() {
+	profileName := flag.String("profile", "demo", "seed profile: "+strings.Join(database.SeedProfileNames(), ", "))
+	categories := flag.Int("categories", -1, "override the profile's category count")
+	brands := flag.Int("brands", -1, "override the profile's brand count")
+	products := flag.Int("products", -1, "override the profile's product count")
+	customers := flag.Int("customers", -1, "override the profile's customer count")
+	orders := flag.Int("orders", -1, "override the profile's order count")
+	randSeed := flag.Int64("rand-seed", 0, "random seed; runs with the same profile and seed generate the same rows")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: seed [-profile name] [overrides]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	profile, err := database.LookupSeedProfile(*profileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	override(&profile.Categories, *categories)
+	override(&profile.Brands, *brands)
+	override(&profile.Products, *products)
+	override(&profile.Customers, *customers)
+	override(&profile.Orders, *orders)
+	if *randSeed != 0 {
+		profile.RandSeed = *randSeed
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Stop between batches on Ctrl+C; rows already inserted are kept and skipped next run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Println("Running gocommerce migrations...")
+	if err := db.RunCommerceMigrations(ctx); err != nil {
+		log.Fatalf("Failed to run gocommerce migrations: %v", err)
+	}
+
+	if err := db.SeedWithProfile(ctx, profile); err != nil {
+		log.Fatalf("Failed to seed database: %v", err)
+	}
+}
+
+// override replaces a profile count when its flag was given
+func override(count *int, flagValue int) {
+	if flagValue >= 0 {
+		*count = flagValue
+	}
+}
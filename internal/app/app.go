@@ -0,0 +1,1211 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce/inventory"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/payments"
+	"github.com/devchuckcamp/gocommerce/pricing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
+	"github.com/devchuckcamp/gocommerce-api/internal/config"
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	httpserver "github.com/devchuckcamp/gocommerce-api/internal/http"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/internal/startup"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+// App is the API wired together: the HTTP server and the background work that runs
+// beside it. cmd/api serves it; the end-to-end tests drive the same router.
+type App struct {
+	Server      *httpserver.Server
+	Jobs        *jobs.Runner
+	Scheduler   *jobs.Scheduler
+	Webhooks    *services.WebhookService
+	CacheWarmer *services.CacheWarmer
+	Diagnostics http.Handler // pprof and expvar for admins; nil unless DIAGNOSTICS_ENABLED
+
+	// RuntimeConfig reloads the settings applied without a restart; cmd/api reloads on SIGHUP
+	RuntimeConfig *services.RuntimeConfigService
+
+	cfg *config.Config
+}
+
+// New creates the repositories, services and HTTP server. The database must already be
+// migrated; nothing runs until Start is called. It returns an error when a startup check
+// fails and STARTUP_CHECKS is fail.
+func New(cfg *config.Config, db *database.DB, authStore goauthx.Store, authSeeder *goauthx.Seeder) (*App, error) {
+	// Verify the schema, secrets and providers first; STARTUP_CHECKS decides whether a
+	// failure stops the API or only marks it degraded
+	startupReport, err := startup.Run(context.Background(), startup.Mode(cfg.Startup.Checks), startupChecks(cfg, db))
+	if err != nil {
+		return nil, err
+	}
+
+	authService, err := goauthx.NewService(cfg.ToGoAuthXConfig(), authStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth service: %w", err)
+	}
+
+	log.Println("Authentication service initialized")
+
+	// New record IDs are random UUIDs, or sort by creation time for index locality
+	idStrategy, err := utils.ParseIDStrategy(cfg.Database.IDStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID strategy: %w", err)
+	}
+	utils.SetIDStrategy(idStrategy)
+
+	// Latency of the last 1000 statements, reported by GET /admin/system/status
+	queryLatency := database.NewQueryLatency(1000)
+	db.TrackLatency(queryLatency)
+
+	// Initialize repositories
+	productRepo := repository.NewProductRepository(db.DB)
+	variantRepo := repository.NewVariantRepository(db.DB)
+	categoryRepo := repository.NewCategoryRepository(db.DB)
+	brandRepo := repository.NewBrandRepository(db.DB)
+	cartRepo := repository.NewCartRepository(db.DB)
+	cartMetadataRepo := repository.NewCartMetadataRepository(db.DB)
+	guestSessionRepo := repository.NewGuestSessionRepository(db.DB)
+	customerRepo := repository.NewCustomerRepository(db.DB)
+	companyRepo := repository.NewCompanyRepository(db.DB)
+	quoteRepo := repository.NewQuoteRepository(db.DB)
+	invoiceRepo := repository.NewInvoiceRepository(db.DB)
+	recentlyViewedRepo := repository.NewRecentlyViewedRepository(db.DB)
+	orderRepo := repository.NewOrderRepository(db.DB)
+	promotionRepo := repository.NewPromotionRepository(db.DB)
+	productPriceRepo := repository.NewProductPriceRepository(db.DB)
+	deliverySlotRepo := repository.NewDeliverySlotRepository(db.DB)
+	shippingZoneRepo := repository.NewShippingZoneRepository(db.DB)
+	shippingMethodRepo := repository.NewShippingMethodRepository(db.DB)
+	calendarRepo := repository.NewCalendarRepository(db.DB)
+	paymentRepo := repository.NewPaymentRepository(db.DB)
+	paymentRetryRepo := repository.NewPaymentRetryRepository(db.DB)
+	disputeRepo := repository.NewDisputeRepository(db.DB)
+	refundRepo := repository.NewRefundRepository(db.DB)
+	storeCreditRepo := repository.NewStoreCreditRepository(db.DB)
+	pageRepo := repository.NewPageRepository(db.DB)
+	collectionRepo := repository.NewCollectionRepository(db.DB)
+	barcodeRepo := repository.NewBarcodeRepository(db.DB)
+	unitPriceRepo := repository.NewUnitPriceRepository(db.DB)
+	placementRepo := repository.NewPlacementRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	quotaRepo := repository.NewQuotaRepository(db.DB)
+	funnelRepo := repository.NewFunnelRepository(db.DB)
+	fulfillmentRepo := repository.NewFulfillmentRepository(db.DB)
+	posRepo := repository.NewPOSRepository(db.DB)
+	orderAttributionRepo := repository.NewOrderAttributionRepository(db.DB)
+	webhookEventRepo := repository.NewWebhookEventRepository(db.DB)
+	deadLetterRepo := repository.NewDeadLetterRepository(db.DB)
+	systemStatusRepo := repository.NewSystemStatusRepository(db.DB, queryLatency)
+	configChangeRepo := repository.NewConfigChangeRepository(db.DB)
+	catalogVersionRepo := repository.NewCatalogVersionRepository(db.DB)
+	orderEventRepo := repository.NewOrderEventRepository(db.DB)
+	orderStatusEventRepo := repository.NewOrderStatusEventRepository(db.DB)
+	purchaseLimitRepo := repository.NewPurchaseLimitRepository(db.DB)
+	checkoutRuleRepo := repository.NewCheckoutRuleRepository(db.DB)
+	pricingAnomalyRepo := repository.NewPricingAnomalyRepository(db.DB)
+	categoryCountRepo := repository.NewCategoryCountRepository(db.DB)
+	suggestionRepo := repository.NewSuggestionRepository(db.DB)
+	searchTermRepo := repository.NewSearchTermRepository(db.DB)
+	searchRuleRepo := repository.NewSearchRuleRepository(db.DB)
+	productMediaRepo := repository.NewProductMediaRepository(db.DB)
+	bulkArchiveRepo := repository.NewBulkArchiveRepository(db.DB)
+	shippingRestrictionRepo := repository.NewShippingRestrictionRepository(db.DB)
+	rentalRepo := repository.NewRentalRepository(db.DB)
+	appointmentRepo := repository.NewAppointmentRepository(db.DB)
+	donationRepo := repository.NewDonationRepository(db.DB)
+	orderBumpRepo := repository.NewOrderBumpRepository(db.DB)
+	wishlistRepo := repository.NewWishlistRepository(db.DB)
+	supportTicketRepo := repository.NewSupportTicketRepository(db.DB)
+	reviewRepo := repository.NewReviewRepository(db.DB)
+	questionRepo := repository.NewQuestionRepository(db.DB)
+	contentReportRepo := repository.NewContentReportRepository(db.DB)
+	waitingRoomRepo := repository.NewWaitingRoomRepository(db.DB)
+	loginSecurityRepo := repository.NewLoginSecurityRepository(db.DB)
+	retentionRepo := repository.NewRetentionRepository(db.DB)
+	orderArchiveRepo := repository.NewOrderArchiveRepository(db.DB)
+	consentRepo := repository.NewConsentRepository(db.DB)
+	inventoryHistoryRepo := repository.NewInventoryHistoryRepository(db.DB)
+
+	// Addresses and notes are encrypted at rest when field encryption keys are configured
+	var fieldCipher *fieldcrypt.Cipher
+	if len(cfg.Database.EncryptionKeys) > 0 {
+		keys, err := fieldcrypt.ParseKeys(cfg.Database.EncryptionKeys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse field encryption keys: %w", err)
+		}
+		if fieldCipher, err = fieldcrypt.New(keys...); err != nil {
+			return nil, fmt.Errorf("failed to create field cipher: %w", err)
+		}
+		orderRepo.WithCipher(fieldCipher)
+		disputeRepo.WithCipher(fieldCipher)
+		refundRepo.WithCipher(fieldCipher)
+		storeCreditRepo.WithCipher(fieldCipher)
+		fulfillmentRepo.WithCipher(fieldCipher)
+		log.Printf("Field encryption enabled with primary key %s", keys[0].ID)
+	}
+
+	// Cart, order and inventory writes that lose a serialization or deadlock race are run again
+	writeRetry := retry.Policy{
+		MaxAttempts: cfg.Database.WriteRetryAttempts,
+		BaseDelay:   cfg.Database.WriteRetryBaseDelay,
+		MaxDelay:    cfg.Database.WriteRetryMaxDelay,
+	}
+	cartRepo.WithRetry(writeRetry)
+	orderRepo.WithRetry(writeRetry)
+	inventoryHistoryRepo.WithRetry(writeRetry)
+
+	log.Println("Repositories initialized")
+
+	// Lock manager keeping scheduled tasks, webhook processing and stock changes to one replica
+	var lockManager lock.Manager = lock.NewLocalManager()
+	if cfg.Locks.Backend == "postgres" {
+		sqlDB, err := db.DB.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database handle for locks: %w", err)
+		}
+		lockManager = lock.NewPostgresManager(sqlDB)
+	}
+
+	// No stock tracking yet; plug an inventory.Service in here and it is locked per SKU,
+	// with every stock movement recorded for GET /admin/inventory/:sku/history
+	var stockService inventory.Service
+	inventoryService := services.LockInventory(services.RecordInventory(stockService, inventoryHistoryRepo), lockManager)
+
+	// External providers sit behind circuit breakers so an outage degrades checkout instead of failing it
+	breakerPolicy := breaker.Policy{
+		FailureThreshold: cfg.Providers.BreakerFailures,
+		Cooldown:         cfg.Providers.BreakerCooldown,
+	}
+	paymentBreaker := breaker.New("payments", breakerPolicy)
+	taxBreaker := breaker.New("tax", breakerPolicy)
+	shippingBreaker := breaker.New("shipping", breakerPolicy.Ignoring(services.ErrShippingZoneNotFound, services.ErrShippingMethodUnavailable))
+	circuitBreakers := []*breaker.Breaker{paymentBreaker, taxBreaker, shippingBreaker}
+
+	// Failed provider calls are retried with jittered backoff within a time budget
+	providerRetry := retry.Policy{
+		MaxAttempts: cfg.Providers.RetryAttempts,
+		BaseDelay:   cfg.Providers.RetryBaseDelay,
+		MaxDelay:    cfg.Providers.RetryMaxDelay,
+		Budget:      cfg.Providers.RetryBudget,
+	}
+
+	// Card tenders stay pending without a gateway, and while its breaker is open
+	var paymentGateway payments.Gateway
+	var mockGateway *services.MockPaymentGateway
+	if cfg.Payments.Provider == "mock" {
+		log.Printf("Using the mock payment gateway (mode %s); no real charges are made", cfg.Payments.Mock.Mode)
+		mockGateway = services.NewMockPaymentGateway(services.MockGatewayOptions{
+			Mode:         services.MockGatewayMode(cfg.Payments.Mock.Mode),
+			FailureRate:  cfg.Payments.Mock.FailureRate,
+			ErrorRate:    cfg.Payments.Mock.ErrorRate,
+			Latency:      cfg.Payments.Mock.Latency,
+			WebhookDelay: cfg.Payments.Mock.WebhookDelay,
+		})
+		paymentGateway = mockGateway
+	}
+	if paymentGateway != nil {
+		paymentGateway = services.NewBreakerGateway(paymentGateway, paymentBreaker).WithRetry(providerRetry)
+	}
+
+	// Initialize services
+	// Tax calculator (flat TAX_RATE; swap in a provider-backed tax.Calculator here). Both
+	// rates can be changed by reloading the configuration.
+	taxRounding, err := moneymath.ParseRounding(cfg.Currency.TaxRounding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tax rounding: %w", err)
+	}
+	primaryTax := services.NewSimpleTaxCalculator(cfg.Currency.TaxRate).WithRounding(taxRounding)
+	fallbackTax := services.NewSimpleTaxCalculator(cfg.Providers.FallbackTaxRate).WithRounding(taxRounding)
+	taxCalculator := services.NewFallbackTaxCalculator(primaryTax, fallbackTax, taxBreaker).WithRetry(providerRetry)
+
+	// Price rounding rules per currency, applied to price-table prices such as sale prices
+	// wherever they are resolved, and to prices converted into a display currency
+	roundingRules, err := services.ParsePriceRoundingRules(cfg.Currency.PriceRounding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price rounding rules: %w", err)
+	}
+	pricePolicy := services.NewPricePolicy(roundingRules)
+	roundedPrices := pricePolicy.RoundedPrices(productPriceRepo)
+
+	// Create catalog service with sale price resolver
+	catalogService := services.NewCatalogService(
+		productRepo,
+		variantRepo,
+		categoryRepo,
+		brandRepo,
+	).WithSalePriceResolver(roundedPrices)
+	if cfg.Cache.ProductTTL > 0 {
+		catalogService.WithProductCache(services.NewProductCache(cfg.Cache.ProductTTL), productPriceRepo)
+	}
+	if cfg.Cache.ListTTL > 0 {
+		catalogService.WithListCache(cfg.Cache.ListTTL)
+	}
+	// Searches that find nothing are retried with their spelling corrected against the
+	// words of the catalog's names, which the search-terms task keeps current
+	spellingService := services.NewSpellingService(searchTermRepo)
+	catalogService.WithSpelling(spellingService)
+	catalogService.SetSpellCorrection(cfg.Search.SpellCorrection)
+	// Merchandisers' synonyms and pinned/boosted products are applied to every keyword search
+	searchRuleService := services.NewSearchRuleService(searchRuleRepo, productRepo)
+	if cfg.Search.RulesCacheTTL > 0 {
+		searchRuleService.WithCache(cfg.Search.RulesCacheTTL)
+	}
+	catalogService.WithSearchRules(searchRuleService)
+	// Ordered galleries of images, videos and 3D models; products without one list their images
+	productMediaService := services.NewProductMediaService(productMediaRepo).
+		WithChangeHook(catalogService.InvalidateProducts)
+	catalogService.WithMedia(productMediaService)
+	// Product responses list resized, signed CDN URLs for each image when a resizing CDN
+	// is configured
+	if cfg.Images.CDNURL != "" {
+		imagePresets, err := services.ParseImagePresets(cfg.Images.Presets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse image presets: %w", err)
+		}
+		imageKey, err := hex.DecodeString(cfg.Images.Key)
+		if err != nil {
+			return nil, fmt.Errorf("IMAGE_SIGNING_KEY must be hex-encoded: %w", err)
+		}
+		imageSalt, err := hex.DecodeString(cfg.Images.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("IMAGE_SIGNING_SALT must be hex-encoded: %w", err)
+		}
+		catalogService.WithImages(services.NewImageService(cfg.Images.CDNURL, cfg.Images.SourceURL, imagePresets, imageKey, imageSalt))
+	}
+	cacheWarmer := services.NewCacheWarmer(catalogService, orderRepo)
+
+	// Suspicious price changes and orders are held for admin review; alerts are emailed when
+	// an SMTP server and recipients are configured
+	var pricingAlerts services.PricingAlertNotifier = services.LogPricingAlertNotifier{}
+	if cfg.Mail.SMTPHost != "" && len(cfg.Orders.PricingAlertEmails) > 0 {
+		pricingAlerts = services.NewSMTPPricingAlertNotifier(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From, cfg.Orders.PricingAlertEmails)
+	}
+	pricingAnomalyService := services.NewPricingAnomalyService(pricingAnomalyRepo, pricingAlerts, cfg.Orders.MaxPriceDrop)
+
+	// Per-category product counters for the category tree, updated as products are written
+	categoryCountService := services.NewCategoryCountService(categoryCountRepo, categoryRepo)
+
+	// Search box type-ahead over product, category and brand names
+	suggestionService := services.NewSuggestionService(suggestionRepo)
+
+	// Catalog change history; product and variant writes go through its Products() and
+	// Variants() repositories so every change is recorded. Writes, reverts included, are
+	// guarded against pricing mistakes, and approved changes are applied through history.
+	catalogHistoryService := services.NewCatalogHistoryService(
+		catalogVersionRepo,
+		pricingAnomalyService.GuardProducts(categoryCountService.Products(productRepo)),
+		pricingAnomalyService.GuardVariants(variantRepo),
+	).WithRevertHook(catalogService.InvalidateProducts)
+	pricingAnomalyService.WithCatalogWriters(catalogHistoryService.Products(), catalogHistoryService.Variants())
+
+	// Create price resolver service for dynamic pricing
+	priceResolverService := pricing.NewPriceResolverService(
+		roundedPrices,
+		productRepo,
+		variantRepo,
+	)
+	priceResolverAdapter := pricing.NewCartPriceResolverAdapter(priceResolverService)
+
+	// Create purchase limit service; limits are checked when items go into the cart and again at checkout
+	purchaseLimitService := services.NewPurchaseLimitService(purchaseLimitRepo, orderRepo)
+
+	// Create waiting room service when enabled; drop products can only be added to a cart with an admitted token
+	var waitingRoomService *services.WaitingRoomService
+	if cfg.WaitingRoom.Enabled {
+		waitingRoomService = services.NewWaitingRoomService(waitingRoomRepo, services.WaitingRoomPolicy{
+			Products:      cfg.WaitingRoom.Products,
+			AdmitBatch:    cfg.WaitingRoom.AdmitBatch,
+			AdmitInterval: cfg.WaitingRoom.AdmitInterval,
+			AccessTTL:     cfg.WaitingRoom.AccessTTL,
+		})
+	}
+
+	// Carts and orders are counted through the checkout funnel; drop-off alerts are
+	// attached once the job runner exists
+	funnelService := services.NewFunnelService(funnelRepo, services.FunnelPolicy{
+		Window:    cfg.Funnel.Window,
+		Baseline:  cfg.Funnel.Baseline,
+		MaxDrop:   cfg.Funnel.MaxDrop,
+		MinSample: int64(cfg.Funnel.MinSample),
+	})
+
+	// Products rented by the day, booked per SKU for the rental period chosen in the cart
+	rentalService := services.NewRentalService(rentalRepo, productRepo, variantRepo).WithLocker(lockManager)
+
+	// Service products booked into time slots, one seat per unit in the cart
+	appointmentService := services.NewAppointmentService(appointmentRepo, productRepo)
+
+	// Pay-what-you-want products, such as charity add-ons, priced at the amount chosen in the cart
+	donationService := services.NewDonationService(donationRepo, productRepo)
+
+	// Wishlists, shared as gift registries when public; units bought from a registry are
+	// marked so guests don't buy the same gift twice
+	wishlistService := services.NewWishlistService(wishlistRepo, productRepo)
+
+	// Create cart service with price resolver
+	cartService := services.NewCartService(
+		cartRepo,
+		productRepo,
+		variantRepo,
+		inventoryService,
+	).WithPriceResolver(priceResolverAdapter).
+		WithPurchaseLimits(purchaseLimitService).
+		WithFunnel(funnelService).
+		WithRentals(rentalService).
+		WithAppointments(appointmentService).
+		WithDonations(donationService).
+		WithWishlists(wishlistService)
+
+	// Client metadata on carts and cart items, limited to whitelisted keys and carried through to the order
+	cartMetadataService := services.NewCartMetadataService(cartMetadataRepo, services.MetadataPolicy{
+		AllowedKeys:    cfg.Metadata.AllowedKeys,
+		MaxKeys:        cfg.Metadata.MaxKeys,
+		MaxValueLength: cfg.Metadata.MaxValueLength,
+	})
+
+	// Signed guest session tokens let guests keep a cart, recently viewed products and
+	// orders, which move to the account they register or sign in to
+	recentlyViewedService := services.NewRecentlyViewedService(recentlyViewedRepo, productRepo)
+	guestSessionService := services.NewGuestSessionService(guestSessionRepo, cartService, cartRepo, []byte(cfg.Auth.JWTSecret), cfg.Auth.GuestSessionTTL).
+		WithRecentlyViewed(recentlyViewedService)
+
+	// Customer profiles, with avatars kept in media storage
+	mediaStorage := services.NewLocalMediaStorage(cfg.Media.Dir, cfg.Media.BaseURL)
+	customerService := services.NewCustomerService(customerRepo, mediaStorage, int64(cfg.Media.MaxAvatarSize))
+
+	// Product reviews, shown once moderated, with photos in the same media storage, and
+	// product Q&A; blocked words are masked in both
+	profanityFilter := services.NewProfanityFilter(cfg.Reviews.BlockedWords)
+	reviewService := services.NewReviewService(reviewRepo, productRepo, mediaStorage,
+		profanityFilter, cfg.Reviews.MaxPhotos, int64(cfg.Reviews.MaxPhotoSize))
+	questionService := services.NewQuestionService(questionRepo, productRepo, profanityFilter)
+
+	// Abuse reports on reviews, questions and answers hide the content once enough users
+	// report it, until a moderator looks at it
+	contentReportService := services.NewContentReportService(contentReportRepo, reviewRepo, questionRepo, cfg.Reviews.ReportHideThreshold)
+
+	// Create shipping zone service; it prices shipping from the destination's zone, or from
+	// the flat and table-rate shipping methods serving its country where no zone covers it
+	shippingMethodService := services.NewShippingMethodService(shippingMethodRepo)
+	shippingService := services.NewShippingZoneService(shippingZoneRepo).
+		WithMethods(shippingMethodService)
+
+	// Product shipping restrictions filter the shipping options offered and are checked again at checkout
+	shippingRestrictionService := services.NewShippingRestrictionService(shippingRestrictionRepo, shippingService)
+
+	// Create business calendar service for delivery estimates and working-day scheduled tasks
+	calendarService := services.NewCalendarService(calendarRepo)
+
+	// Exchange rates convert promotion minimum purchases into the cart currency, and catalog
+	// prices into a display currency
+	exchangeRates, err := services.ParseExchangeRates(cfg.Currency.ExchangeRates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+	currencyService := services.NewCurrencyService(cfg.Currency.Base, exchangeRates).
+		WithPricePolicy(pricePolicy)
+	catalogService.WithCurrencyService(currencyService)
+
+	// Checkout rules are evaluated on GET /cart/validate and again when the order is created
+	checkoutRuleService := services.NewCheckoutRuleService(checkoutRuleRepo).
+		WithCurrencyService(currencyService)
+
+	// Order bumps are offered on GET /cart/validate and added to the order when accepted
+	orderBumpService := services.NewOrderBumpService(orderBumpRepo, productRepo)
+
+	// Create pricing service with zone-based shipping rates, falling back to a flat rate
+	pricingService := services.NewPricingService(
+		promotionRepo,
+		taxCalculator,
+		services.NewFallbackRateCalculator(
+			shippingService,
+			services.NewFlatRateShipping(money.Money{Amount: cfg.Providers.FallbackShippingRate, Currency: cfg.Providers.Currency}),
+			shippingBreaker,
+		).WithRetry(providerRetry),
+	).WithCurrencyService(currencyService).
+		WithDonations(donationService)
+
+	// Order timeline: placement, status changes, shipments, refunds and staff notes
+	orderEventService := services.NewOrderEventService(orderEventRepo)
+
+	// Orders are reconciled before they are saved, so corrupted totals are never stored unnoticed
+	totalsCheck, err := services.ParseReconciliationMode(cfg.Orders.TotalsCheck)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order totals check: %w", err)
+	}
+	reconciledOrders := services.NewReconcilingOrderRepository(orderRepo, totalsCheck).WithEvents(orderEventService)
+
+	// Create order service; payments are charged per tender by the payment service, not here
+	orderService := services.NewOrderService(
+		reconciledOrders,
+		pricingService,
+		inventoryService,
+		nil,
+	).WithEvents(orderEventService).
+		WithPurchaseLimits(purchaseLimitService).
+		WithCheckoutRules(checkoutRuleService).
+		WithCartMetadata(cartMetadataService).
+		WithPricingAnomalies(pricingAnomalyService).
+		WithFunnel(funnelService).
+		WithRentals(rentalService).
+		WithAppointments(appointmentService).
+		WithWishlists(wishlistService)
+	pricingAnomalyService.WithOrderService(orderService)
+
+	// Support tickets customers open against their orders; staff see them on the order
+	supportTicketService := services.NewSupportTicketService(supportTicketRepo, orderService)
+
+	// Create delivery service for checkout slot selection
+	deliveryService := services.NewDeliveryService(deliverySlotRepo)
+
+	// Create address service (rule-based validator; swap in a provider-backed AddressValidator here)
+	addressService := services.NewAddressService(services.NewRuleBasedAddressValidator()).WithRetry(providerRetry)
+
+	// Create store credit service for customer wallets
+	storeCreditService := services.NewStoreCreditService(storeCreditRepo)
+
+	// Create consent service; marketing senders check Allows before contacting a customer
+	consentService := services.NewConsentService(consentRepo, cfg.Auth.ConsentPolicyVersion)
+
+	// Create payment service for card, split and store credit tenders
+	paymentService := services.NewPaymentService(paymentRepo, paymentGateway).
+		WithStoreCreditService(storeCreditService)
+
+	// Create payment retry service; orders with declined charges wait in payment_pending
+	paymentRetryService := services.NewPaymentRetryService(
+		paymentRetryRepo,
+		paymentService,
+		orderService,
+		services.PaymentRetryPolicy{
+			MaxAttempts: cfg.Payments.RetryMaxAttempts,
+			Window:      cfg.Payments.RetryWindow,
+		},
+	).WithDeliveryService(deliveryService)
+
+	// Create dispute service for chargebacks reported by gateway webhooks
+	disputeService := services.NewDisputeService(disputeRepo, paymentRepo)
+
+	// Create refund service; refunds go back to the order's payment tenders
+	refundService := services.NewRefundService(refundRepo, paymentService, paymentRepo, orderService).
+		WithEvents(orderEventService)
+
+	// Company accounts; buyers' orders above the approval threshold wait for an approver
+	companyService := services.NewCompanyService(companyRepo, orderService).
+		WithPaymentService(paymentService, paymentRetryService).
+		WithDeliveryService(deliveryService).
+		WithCurrencyService(currencyService)
+
+	// Net-terms invoicing for companies; rejected orders void their invoice
+	invoiceService := services.NewInvoiceService(invoiceRepo, companyService, orderService)
+	companyService.WithInvoiceService(invoiceService)
+
+	// Order cancellations by customers and staff refund the order's tenders and free its
+	// stock, delivery slot and invoice
+	orderCancellationService := services.NewOrderCancellationService(orderService, refundService).
+		WithDeliveryService(deliveryService).
+		WithInvoiceService(invoiceService).
+		WithAppointments(appointmentService)
+
+	// Staff move orders through the fulfillment workflow, kept in the order's status history;
+	// cancellations go through the cancellation service so they still refund and release the order
+	orderStatusService := services.NewOrderStatusService(orderService, orderCancellationService, orderStatusEventRepo)
+
+	// B2B quotes; buyers accept sent quotes by checking out at the negotiated prices
+	quoteService := services.NewQuoteService(quoteRepo, cfg.Quotes.Validity)
+
+	// Create page service for About/FAQ/policy content
+	pageService := services.NewPageService(pageRepo)
+
+	// Create collection service for product tags and merchandised landing pages
+	collectionService := services.NewCollectionService(collectionRepo, catalogService)
+
+	// Create unit price service for per kg/litre prices of variants sold by measure
+	unitPriceService := services.NewUnitPriceService(unitPriceRepo, variantRepo).
+		WithSalePriceResolver(roundedPrices)
+
+	// Create barcode service for variant GTINs scanned at the till and in the warehouse
+	barcodeService := services.NewBarcodeService(barcodeRepo, variantRepo, catalogService).
+		WithUnitPrices(unitPriceService)
+
+	// Create variant service for the SKUs behind size and color pickers; writes go through
+	// catalog history like other catalog changes
+	variantService := services.NewVariantService(catalogHistoryService.Variants(), productRepo).
+		WithChangeHook(catalogService.InvalidateProducts)
+
+	// Create placement service for scheduled banners and promo tiles
+	placementService := services.NewPlacementService(placementRepo)
+
+	// Create API key and fulfillment services for 3PL warehouse integrations
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+
+	// Daily and monthly request quotas of API keys and user roles, counted as requests
+	// are authenticated
+	quotaTiers, err := services.ParseQuotaTiers(cfg.Quotas.Tiers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse quota tiers: %w", err)
+	}
+	quotaService := services.NewQuotaService(quotaRepo, quotaTiers).WithAPIKeys(apiKeyService)
+	fulfillmentService := services.NewFulfillmentService(fulfillmentRepo, orderService).
+		WithEvents(orderEventService).
+		WithPricingAnomalies(pricingAnomalyService)
+
+	// Create POS service for in-store sales rung up at registers
+	// Order channel and UTM attribution for revenue reports per channel
+	orderAttributionService := services.NewOrderAttributionService(orderAttributionRepo)
+
+	posService := services.NewPOSService(posRepo, productRepo, variantRepo, orderService, paymentService).
+		WithPriceResolver(priceResolverAdapter).
+		WithAttribution(orderAttributionService)
+
+	// Background job runner for work done after the response is sent
+	jobRunner := jobs.NewRunner(cfg.Jobs.Workers, cfg.Jobs.QueueSize)
+
+	// Create webhook service; each provider verifies its own signatures at /webhooks/:provider
+	webhookService := services.NewWebhookService(webhookEventRepo, jobRunner).WithLocker(lockManager)
+	paymentWebhooks := services.NewPaymentWebhookProvider(cfg.Payments.WebhookSecret, disputeService).
+		WithPayments(paymentService, orderService).
+		WithPaymentRetryService(paymentRetryService)
+	if err := webhookService.RegisterProvider("payments", paymentWebhooks); err != nil {
+		return nil, fmt.Errorf("failed to register webhook provider: %w", err)
+	}
+	if mockGateway != nil {
+		mockGateway.WithWebhooks(webhookService, "payments", cfg.Payments.WebhookSecret)
+	}
+
+	// Create login security service; new-device alerts are emailed when an SMTP server is configured
+	var loginAlerts services.LoginAlertNotifier = services.LogLoginAlertNotifier{}
+	if cfg.Mail.SMTPHost != "" {
+		loginAlerts = services.NewSMTPLoginAlertNotifier(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	}
+	loginSecurityService := services.NewLoginSecurityService(loginSecurityRepo, services.LoginSecurityPolicy{
+		MaxFailures: cfg.Auth.LoginMaxFailures,
+		LockoutBase: cfg.Auth.LockoutBase,
+		LockoutMax:  cfg.Auth.LockoutMax,
+	})
+	if cfg.Auth.NewDeviceAlerts {
+		loginSecurityService.WithAlerts(loginAlerts, jobRunner)
+	}
+
+	// Checkout funnel drop-off alerts are posted to a webhook when one is configured
+	var funnelAlerts services.FunnelAlertNotifier = services.LogFunnelAlertNotifier{}
+	if cfg.Funnel.AlertWebhookURL != "" {
+		funnelAlerts = services.NewWebhookFunnelAlertNotifier(cfg.Funnel.AlertWebhookURL, cfg.Funnel.AlertWebhookSecret, cfg.Funnel.AlertTimeout).
+			WithRetry(providerRetry)
+	}
+	funnelService.WithAlerts(funnelAlerts, jobRunner)
+
+	// Order confirmations are emailed when an SMTP server is configured; customers and
+	// admins may resend them within the resend limits
+	var orderConfirmations services.OrderConfirmationNotifier = services.LogOrderConfirmationNotifier{}
+	if cfg.Mail.SMTPHost != "" {
+		orderConfirmations = services.NewSMTPOrderConfirmationNotifier(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	}
+	customerEmail := func(ctx context.Context, userID string) (string, error) {
+		user, err := authService.GetUserByID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		return user.Email, nil
+	}
+	orderConfirmationService := services.NewOrderConfirmationService(orderRepo, customerEmail, orderConfirmations, services.ConfirmationResendPolicy{
+		Cooldown:  cfg.Orders.ConfirmationResendCooldown,
+		MaxResend: cfg.Orders.ConfirmationResendLimit,
+		Window:    cfg.Orders.ConfirmationResendWindow,
+	}).WithRunner(jobRunner)
+	orderService.WithConfirmations(orderConfirmationService)
+
+	// Failed webhook processing, login alerts, funnel alerts and order confirmations are dead-lettered for replay; see GET /admin/dead-letters
+	deadLetterService := services.NewDeadLetterService(deadLetterRepo, jobRunner)
+	jobRunner.WithDeadLetter(deadLetterService.Record)
+	if err := deadLetterService.RegisterReplayer(services.WebhookJobKind, webhookService.ReplayDeadLetter); err != nil {
+		return nil, fmt.Errorf("failed to register dead letter replayer: %w", err)
+	}
+	if err := deadLetterService.RegisterReplayer(services.LoginAlertJobKind, loginSecurityService.ReplayAlert); err != nil {
+		return nil, fmt.Errorf("failed to register dead letter replayer: %w", err)
+	}
+	if err := deadLetterService.RegisterReplayer(services.FunnelAlertJobKind, funnelService.ReplayAlert); err != nil {
+		return nil, fmt.Errorf("failed to register dead letter replayer: %w", err)
+	}
+	if err := deadLetterService.RegisterReplayer(services.OrderConfirmationJobKind, orderConfirmationService.ReplayConfirmation); err != nil {
+		return nil, fmt.Errorf("failed to register dead letter replayer: %w", err)
+	}
+
+	// Create maintenance service for scheduled housekeeping
+	maintenanceService := services.NewMaintenanceService(cartRepo, productPriceRepo)
+	if fieldCipher != nil {
+		maintenanceService.WithFieldRekey(repository.NewFieldEncryptionRepository(db.DB, fieldCipher))
+	}
+
+	// Create retention service; old guest carts, webhook payloads and IP addresses are cleared on a schedule
+	retentionService := services.NewRetentionService(retentionRepo, services.RetentionPolicy{
+		GuestCarts:      cfg.Retention.GuestCarts,
+		WebhookPayloads: cfg.Retention.WebhookPayloads,
+		IPAddresses:     cfg.Retention.IPAddresses,
+	})
+
+	// Create order archive service; delivered orders past the archive age are moved out of the
+	// orders table on a schedule, and are still found by ID
+	orderArchiveService := services.NewOrderArchiveService(orderArchiveRepo, services.OrderArchivePolicy{
+		AfterMonths: cfg.Orders.ArchiveAfterMonths,
+		BatchSize:   cfg.Orders.ArchiveBatchSize,
+	})
+
+	// Create inventory history service; stock levels are snapshotted daily for shrinkage and stock-at-date reports
+	inventoryHistoryService := services.NewInventoryHistoryService(inventoryHistoryRepo)
+
+	// Customer order exports too large to return within the request are written to files by
+	// the job runner and downloaded through links signed with the JWT secret
+	orderExportService := services.NewOrderExportService(orderRepo, jobRunner, cfg.Orders.ExportDir, []byte(cfg.Auth.JWTSecret)).
+		WithSyncLimit(cfg.Orders.ExportSyncLimit).
+		WithTTL(cfg.Orders.ExportTTL)
+
+	// Recurring tasks run on the job runner; see GET /admin/schedules
+	scheduler := jobs.NewScheduler(jobRunner).WithLocker(lockManager).WithCalendar(calendarService)
+	if err := registerScheduledTasks(scheduler, cfg, catalogService, paymentRetryService, maintenanceService, webhookService, retentionService, orderArchiveService, inventoryHistoryService, categoryCountService, spellingService, quotaService, funnelService, orderExportService, waitingRoomService); err != nil {
+		return nil, fmt.Errorf("failed to register scheduled tasks: %w", err)
+	}
+
+	log.Println("Domain services initialized")
+
+	// Create bot guard for catalog endpoints (add WithReputationProvider and WithReputationRetry to consult an IP reputation service)
+	var botGuard *middleware.BotGuard
+	if cfg.Bots.Enabled {
+		botGuard = middleware.NewBotGuard(middleware.BotPolicy{
+			AllowUserAgents:     cfg.Bots.AllowUserAgents,
+			BlockUserAgents:     cfg.Bots.BlockUserAgents,
+			BlockEmptyUserAgent: cfg.Bots.BlockEmptyUserAgent,
+			ThrottlePerMinute:   cfg.Bots.ThrottlePerMinute,
+		})
+	}
+
+	// Create CAPTCHA guard for sign-up and checkout when a provider is configured
+	var captchaGuard *middleware.CaptchaGuard
+	if cfg.Captcha.Provider != "" {
+		captchaClient, err := services.NewCaptchaClient(cfg.Captcha.Provider, cfg.Captcha.Secret, cfg.Captcha.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		captchaClient.WithRetry(providerRetry)
+		captchaGuard = middleware.NewCaptchaGuard(captchaClient, middleware.CaptchaPolicy{
+			Routes:   cfg.Captcha.Routes,
+			FailOpen: cfg.Captcha.FailOpen,
+		})
+	}
+
+	// Create load shedder; catalog browsing is turned away first when the server is overloaded
+	var loadShedder *middleware.LoadShedder
+	if cfg.Load.SheddingEnabled {
+		loadShedder = middleware.NewLoadShedder(middleware.LoadPolicy{
+			MaxInFlight: cfg.Load.MaxInFlight,
+			MaxLatency:  cfg.Load.MaxLatency,
+		})
+	}
+
+	// Public catalog responses are cached in memory and tagged with surrogate keys; admin
+	// changes purge the affected keys here and, when a purge endpoint is set, at the CDN
+	var responseCache *middleware.ResponseCache
+	if cfg.Cache.ResponseTTL > 0 {
+		responseCache = middleware.NewResponseCache(cfg.Cache.ResponseTTL, cfg.Cache.ResponseMaxEntries)
+		if cfg.Cache.CDNPurgeURL != "" {
+			responseCache.WithPurger(services.NewCDNPurgeClient(cfg.Cache.CDNPurgeURL, cfg.Cache.CDNPurgeToken, cfg.Cache.CDNPurgeTimeout).WithRetry(providerRetry))
+		}
+	}
+
+	// Bulk archives of products and variants run on the job runner; each batch drops the
+	// archived products from the caches
+	bulkArchiveService := services.NewBulkArchiveService(
+		bulkArchiveRepo,
+		catalogHistoryService.Products(),
+		catalogHistoryService.Variants(),
+		jobRunner,
+	).WithChangeHook(func(productIDs ...string) {
+		catalogService.InvalidateProducts(productIDs...)
+		if responseCache == nil {
+			return
+		}
+		keys := []string{middleware.SurrogateKeyProducts}
+		for _, id := range productIDs {
+			keys = append(keys, "product:"+id)
+		}
+		if err := responseCache.Purge(context.Background(), keys...); err != nil {
+			log.Printf("Failed to purge archived products from the CDN: %v", err)
+		}
+	})
+
+	// Runtime settings reloaded from .env on SIGHUP or from the admin API; each change is
+	// audited and then applied to the components below
+	runtimeConfigService := services.NewRuntimeConfigService(configChangeRepo, runtimeSettings(cfg), func() (services.RuntimeSettings, error) {
+		reloaded, err := config.Reload()
+		if err != nil {
+			return services.RuntimeSettings{}, err
+		}
+		return runtimeSettings(reloaded), nil
+	}).OnChange(func(settings services.RuntimeSettings) {
+		if botGuard != nil {
+			botGuard.SetThrottle(settings.BotThrottlePerMinute)
+		}
+		if loadShedder != nil {
+			loadShedder.SetPolicy(middleware.LoadPolicy{
+				MaxInFlight: settings.LoadShedMaxInFlight,
+				MaxLatency:  settings.LoadShedMaxLatency,
+			})
+		}
+		primaryTax.SetRate(settings.TaxRate)
+		fallbackTax.SetRate(settings.FallbackTaxRate)
+		catalogService.SetSpellCorrection(settings.SpellCorrection)
+	})
+
+	// System status for the ops dashboard; the response cache is reported with the catalog caches
+	systemStatusService := services.NewSystemStatusService(systemStatusRepo, jobRunner, scheduler, webhookService, deadLetterService, catalogService).
+		WithStartupReport(startupReport)
+	if responseCache != nil {
+		systemStatusService.WithCache("responses", func() services.CacheStats {
+			stats := responseCache.Stats()
+			return services.CacheStats{Entries: stats.Entries, Hits: stats.Hits, Misses: stats.Misses}
+		})
+	}
+
+	// Create HTTP server
+	// Request inspector for local development; it counts each request's SQL statements
+	var inspector *middleware.RequestInspector
+	if cfg.Server.InspectorEnabled {
+		db.RecordQueries()
+		inspector = middleware.NewRequestInspector(cfg.Server.InspectorRequests)
+		log.Printf("Request inspector enabled at /debug/requests (last %d requests)", cfg.Server.InspectorRequests)
+	}
+
+	server := httpserver.NewServer(
+		authService,
+		authStore,
+		authSeeder,
+		loginSecurityService,
+		catalogService,
+		categoryCountService,
+		suggestionService,
+		searchRuleService,
+		productMediaService,
+		catalogHistoryService,
+		bulkArchiveService,
+		collectionService,
+		barcodeService,
+		unitPriceService,
+		variantService,
+		cartService,
+		cartMetadataService,
+		guestSessionService,
+		recentlyViewedService,
+		customerService,
+		reviewService,
+		questionService,
+		contentReportService,
+		companyService,
+		quoteService,
+		invoiceService,
+		purchaseLimitService,
+		checkoutRuleService,
+		orderBumpService,
+		pricingAnomalyService,
+		shippingRestrictionService,
+		rentalService,
+		appointmentService,
+		donationService,
+		wishlistService,
+		waitingRoomService,
+		orderService,
+		supportTicketService,
+		orderEventService,
+		orderAttributionService,
+		orderExportService,
+		orderConfirmationService,
+		orderCancellationService,
+		orderStatusService,
+		deliveryService,
+		addressService,
+		shippingService,
+		shippingMethodService,
+		calendarService,
+		paymentService,
+		paymentRetryService,
+		disputeService,
+		refundService,
+		storeCreditService,
+		consentService,
+		pageService,
+		placementService,
+		apiKeyService,
+		quotaService,
+		funnelService,
+		fulfillmentService,
+		posService,
+		webhookService,
+		deadLetterService,
+		systemStatusService,
+		runtimeConfigService,
+		retentionService,
+		orderArchiveService,
+		inventoryHistoryService,
+		scheduler,
+		cacheWarmer,
+		botGuard,
+		captchaGuard,
+		loadShedder,
+		responseCache,
+		circuitBreakers,
+		inspector,
+		middleware.LoggerOptions{
+			SampleRate:   cfg.Logging.SampleRate,
+			SampledPaths: cfg.Logging.SampledPaths,
+		},
+		cfg.Server.Mode,
+		cfg.Payments.WebhookSecret,
+		cfg.Payments.StoreCreditAutoApply,
+		cfg.Cache.WarmProducts,
+		cfg.Media.Dir,
+	)
+
+	var diagnostics http.Handler
+	if cfg.Diagnostics.Enabled {
+		diagnostics = httpserver.NewDiagnosticsRouter(middleware.NewAuthMiddleware(authService))
+	}
+
+	return &App{
+		Server:      server,
+		Jobs:        jobRunner,
+		Scheduler:   scheduler,
+		Webhooks:    webhookService,
+		CacheWarmer: cacheWarmer,
+		Diagnostics: diagnostics,
+
+		RuntimeConfig: runtimeConfigService,
+
+		cfg: cfg,
+	}, nil
+}
+
+// runtimeSettings picks the settings applied without a restart out of the configuration
+func runtimeSettings(cfg *config.Config) services.RuntimeSettings {
+	return services.RuntimeSettings{
+		MaintenanceMode:      cfg.Maintenance.Enabled,
+		MaintenanceMessage:   cfg.Maintenance.Message,
+		BotThrottlePerMinute: cfg.Bots.ThrottlePerMinute,
+		LoadShedMaxInFlight:  cfg.Load.MaxInFlight,
+		LoadShedMaxLatency:   cfg.Load.MaxLatency,
+		TaxRate:              cfg.Currency.TaxRate,
+		FallbackTaxRate:      cfg.Providers.FallbackTaxRate,
+		SpellCorrection:      cfg.Search.SpellCorrection,
+	}
+}
+
+// Start runs the background jobs: the job runner, webhooks stored but not processed before
+// the last shutdown, the scheduler when enabled, and the cache warm-up
+func (a *App) Start(ctx context.Context) {
+	a.Jobs.Start(ctx)
+	if requeued, err := a.Webhooks.RequeuePending(ctx); err != nil {
+		log.Printf("Failed to requeue pending webhooks: %v", err)
+	} else if requeued > 0 {
+		log.Printf("Requeued %d pending webhooks", requeued)
+	}
+	if a.cfg.Schedule.Enabled {
+		a.Scheduler.Start(ctx)
+	}
+
+	// Each replica caches in memory, so every new process warms its own caches
+	if a.cfg.Cache.WarmOnStart && (a.cfg.Cache.ProductTTL > 0 || a.cfg.Cache.ListTTL > 0) {
+		err := a.Jobs.Enqueue(jobs.Job{
+			Name: "cache-warm",
+			Run: func(ctx context.Context) error {
+				result, err := a.CacheWarmer.Warm(ctx, a.cfg.Cache.WarmProducts)
+				if err != nil {
+					return err
+				}
+				log.Printf("Warmed caches with %d products, %d categories and %d brands in %s",
+					result.Products, result.Categories, result.Brands, result.Duration)
+				return nil
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to queue cache warm-up: %v", err)
+		}
+	}
+}
+
+// Wait blocks until running jobs have finished after the context passed to Start is canceled
+func (a *App) Wait() {
+	a.Jobs.Wait()
+}
+
+// registerScheduledTasks registers the API's recurring tasks with the scheduler
+func registerScheduledTasks(
+	scheduler *jobs.Scheduler,
+	cfg *config.Config,
+	catalogService *services.CatalogService,
+	paymentRetryService *services.PaymentRetryService,
+	maintenanceService *services.MaintenanceService,
+	webhookService *services.WebhookService,
+	retentionService *services.RetentionService,
+	orderArchiveService *services.OrderArchiveService,
+	inventoryHistoryService *services.InventoryHistoryService,
+	categoryCountService *services.CategoryCountService,
+	spellingService *services.SpellingService,
+	quotaService *services.QuotaService,
+	funnelService *services.FunnelService,
+	orderExportService *services.OrderExportService,
+	waitingRoomService *services.WaitingRoomService,
+) error {
+	lastPriceCheck := time.Now()
+
+	tasks := []struct {
+		name, description, spec string
+		run                     func(ctx context.Context) error
+	}{
+		{
+			name:        "payment-retry-sweep",
+			description: "Cancel orders whose payment retry window has ended",
+			spec:        "@every " + cfg.Payments.RetrySweepInterval.String(),
+			run: func(ctx context.Context) error {
+				canceled, err := paymentRetryService.CancelExpired(ctx)
+				if canceled > 0 {
+					log.Printf("Canceled %d unpaid orders", canceled)
+				}
+				return err
+			},
+		},
+		{
+			name:        "cart-expiry",
+			description: "Delete carts past their expiry date",
+			spec:        cfg.Schedule.CartExpiry,
+			run: func(ctx context.Context) error {
+				deleted, err := maintenanceService.PurgeExpiredCarts(ctx)
+				if deleted > 0 {
+					log.Printf("Deleted %d expired carts", deleted)
+				}
+				return err
+			},
+		},
+		{
+			name:        "sale-prices",
+			description: "Deactivate sale and promotional prices whose window has ended",
+			spec:        cfg.Schedule.SalePrices,
+			run: func(ctx context.Context) error {
+				retired, err := maintenanceService.RetireEndedPrices(ctx)
+				if retired > 0 {
+					log.Printf("Deactivated %d ended prices", retired)
+				}
+				return err
+			},
+		},
+		{
+			name:        "price-cache",
+			description: "Drop cached products whose sale price started or ended",
+			spec:        cfg.Schedule.PriceCache,
+			run: func(ctx context.Context) error {
+				now := time.Now()
+				if _, err := catalogService.InvalidateScheduledPrices(ctx, lastPriceCheck, now); err != nil {
+					return err
+				}
+				lastPriceCheck = now
+				return nil
+			},
+		},
+		{
+			name:        "webhook-requeue",
+			description: "Queue stored webhooks still waiting to be processed",
+			spec:        cfg.Schedule.WebhookRequeue,
+			run: func(ctx context.Context) error {
+				_, err := webhookService.RequeuePending(ctx)
+				return err
+			},
+		},
+		{
+			name:        "data-retention",
+			description: "Anonymize guest carts, purge webhook payloads and redact IP addresses past their retention period",
+			spec:        cfg.Schedule.Retention,
+			run: func(ctx context.Context) error {
+				report, err := retentionService.Run(ctx, cfg.Retention.DryRun)
+				if err != nil {
+					return err
+				}
+				for _, rule := range report.Rules {
+					if rule.Affected == 0 {
+						continue
+					}
+					if report.DryRun {
+						log.Printf("Retention dry run: %s would change %d rows", rule.Rule, rule.Affected)
+					} else {
+						log.Printf("Retention: %s changed %d rows", rule.Rule, rule.Affected)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name:        "order-archive",
+			description: "Move delivered orders past the archive age out of the orders table",
+			spec:        cfg.Schedule.OrderArchive,
+			run: func(ctx context.Context) error {
+				report, err := orderArchiveService.Run(ctx, cfg.Orders.ArchiveDryRun)
+				if report != nil && report.Orders > 0 {
+					if report.DryRun {
+						log.Printf("Order archive dry run: would archive %d orders", report.Orders)
+					} else {
+						log.Printf("Archived %d delivered orders", report.Orders)
+					}
+				}
+				return err
+			},
+		},
+		{
+			name:        "inventory-snapshot",
+			description: "Record each active SKU's stock level for the day",
+			spec:        cfg.Schedule.InventorySnapshot,
+			run: func(ctx context.Context) error {
+				recorded, err := inventoryHistoryService.TakeSnapshots(ctx)
+				if recorded > 0 {
+					log.Printf("Recorded stock snapshots for %d SKUs", recorded)
+				}
+				return err
+			},
+		},
+		{
+			name:        "category-counts",
+			description: "Recount each category's active products, fixing counters that drifted",
+			spec:        cfg.Schedule.CategoryCounts,
+			run: func(ctx context.Context) error {
+				fixed, err := categoryCountService.Repair(ctx)
+				if fixed > 0 {
+					log.Printf("Fixed product counts of %d categories", fixed)
+				}
+				return err
+			},
+		},
+		{
+			name:        "search-terms",
+			description: "Rebuild the vocabulary misspelled searches are corrected against from product, category and brand names",
+			spec:        cfg.Schedule.SearchTerms,
+			run: func(ctx context.Context) error {
+				_, err := spellingService.RebuildTerms(ctx)
+				return err
+			},
+		},
+		{
+			name:        "quota-prune",
+			description: "Delete request quota counters from before last month",
+			spec:        cfg.Schedule.QuotaPrune,
+			run: func(ctx context.Context) error {
+				deleted, err := quotaService.Prune(ctx)
+				if deleted > 0 {
+					log.Printf("Deleted %d old quota counters", deleted)
+				}
+				return err
+			},
+		},
+		{
+			name:        "funnel-check",
+			description: "Compare checkout funnel conversion with its baseline and alert on drop-off",
+			spec:        cfg.Schedule.FunnelCheck,
+			run: func(ctx context.Context) error {
+				alerts, err := funnelService.Check(ctx)
+				for _, alert := range alerts {
+					log.Printf("Checkout funnel alert %s: %s to %s at %.1f%% against a %.1f%% baseline", alert.ID, alert.From, alert.To, alert.Rate*100, alert.BaselineRate*100)
+				}
+				return err
+			},
+		},
+		{
+			name:        "funnel-prune",
+			description: "Delete checkout funnel events older than the alert window and baseline",
+			spec:        cfg.Schedule.FunnelPrune,
+			run: func(ctx context.Context) error {
+				deleted, err := funnelService.Prune(ctx)
+				if deleted > 0 {
+					log.Printf("Deleted %d old checkout funnel events", deleted)
+				}
+				return err
+			},
+		},
+		{
+			name:        "order-export-prune",
+			description: "Delete expired customer order export files",
+			spec:        cfg.Schedule.OrderExportPrune,
+			run: func(ctx context.Context) error {
+				pruned, err := orderExportService.Prune(ctx)
+				if pruned > 0 {
+					log.Printf("Deleted %d expired order exports", pruned)
+				}
+				return err
+			},
+		},
+	}
+
+	for _, task := range tasks {
+		if err := scheduler.Register(task.name, task.description, task.spec, task.run); err != nil {
+			return fmt.Errorf("%s: %w", task.name, err)
+		}
+	}
+
+	// The waiting room only has a queue to admit when it is enabled
+	if waitingRoomService != nil {
+		err := scheduler.Register("waiting-room-admit", "Admit the next batch of each limited drop's waiting room", "@every "+cfg.WaitingRoom.AdmitInterval.String(), func(ctx context.Context) error {
+			admitted, err := waitingRoomService.AdmitNext(ctx)
+			if admitted > 0 {
+				log.Printf("Admitted %d customers from the waiting room", admitted)
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("waiting-room-admit: %w", err)
+		}
+	}
+
+	// Re-encryption only has work to do once field encryption keys are configured
+	if len(cfg.Database.EncryptionKeys) > 0 {
+		err := scheduler.Register("field-rekey", "Encrypt plaintext PII and rewrap values under the primary encryption key", cfg.Schedule.FieldRekey, func(ctx context.Context) error {
+			rekeyed, err := maintenanceService.RekeyFields(ctx)
+			if rekeyed > 0 {
+				log.Printf("Re-encrypted %d PII values", rekeyed)
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("field-rekey: %w", err)
+		}
+	}
+
+	// Secrets are only refreshed when variables reference a secrets manager. A rotated
+	// DB_DSN is used by new database connections; other values are read once at startup.
+	if cfg.Secrets.Resolver != nil {
+		err := scheduler.Register("secret-refresh", "Fetch referenced secrets again and report rotated ones", cfg.Schedule.SecretRefresh, func(ctx context.Context) error {
+			rotated, err := cfg.Secrets.Resolver.Refresh(ctx)
+			for _, key := range cfg.Secrets.Keys(rotated) {
+				if key == "DB_DSN" && cfg.Database.DSNSource != nil {
+					log.Printf("Secret for DB_DSN was rotated; new database connections use it")
+					continue
+				}
+				log.Printf("Secret for %s was rotated; restart the API to apply it", key)
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("secret-refresh: %w", err)
+		}
+	}
+	return nil
+}
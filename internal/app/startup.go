@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/config"
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/internal/startup"
+)
+
+// startupChecks lists the dependencies verified before serving: the schema and secrets
+// always, and the external providers that are configured when STARTUP_CHECK_PROVIDERS is on
+func startupChecks(cfg *config.Config, db *database.DB) []startup.Check {
+	checks := []startup.Check{
+		schemaCheck(db),
+		startup.SecretsCheck(requiredSecrets(cfg), config.LookupEnv),
+	}
+	if !cfg.Startup.CheckProviders {
+		return checks
+	}
+
+	timeout := cfg.Startup.ProviderTimeout
+	if cfg.Mail.SMTPHost != "" {
+		addr := net.JoinHostPort(cfg.Mail.SMTPHost, strconv.Itoa(cfg.Mail.SMTPPort))
+		checks = append(checks, startup.DialCheck("provider:smtp", addr, timeout))
+	}
+	urls := []struct{ name, url string }{
+		{"provider:cdn-purge", cfg.Cache.CDNPurgeURL},
+		{"provider:image-cdn", cfg.Images.CDNURL},
+		{"provider:captcha", services.CaptchaVerifyURL(cfg.Captcha.Provider)},
+	}
+	for _, provider := range urls {
+		if provider.url == "" {
+			continue
+		}
+		addr, err := startup.URLAddr(provider.url)
+		if err != nil {
+			checks = append(checks, startup.Check{Name: provider.name, Run: func(ctx context.Context) error { return err }})
+			continue
+		}
+		checks = append(checks, startup.DialCheck(provider.name, addr, timeout))
+	}
+	return checks
+}
+
+// schemaCheck fails when migrations are pending, or when the database was migrated by a
+// release newer than this one
+func schemaCheck(db *database.DB) startup.Check {
+	return startup.Check{
+		Name: "schema",
+		Run: func(ctx context.Context) error {
+			schema, err := db.SchemaStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if len(schema.Pending) > 0 {
+				return fmt.Errorf("%d migrations pending (%s); expected schema version %s", len(schema.Pending), strings.Join(schema.Pending, ", "), schema.Expected)
+			}
+			if len(schema.Unknown) > 0 {
+				return fmt.Errorf("database has migrations this build doesn't know (%s); expected schema version %s", strings.Join(schema.Unknown, ", "), schema.Expected)
+			}
+			return nil
+		},
+	}
+}
+
+// requiredSecrets lists the environment variables that must be set: those named in
+// STARTUP_REQUIRED_SECRETS, and the SMTP password when a username is configured
+func requiredSecrets(cfg *config.Config) []string {
+	secrets := append([]string(nil), cfg.Startup.RequiredSecrets...)
+	if cfg.Mail.SMTPUsername != "" {
+		secrets = append(secrets, "SMTP_PASSWORD")
+	}
+	return secrets
+}
@@ -0,0 +1,201 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned without calling the provider while the breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is where a breaker is in its cycle
+type State string
+
+const (
+	StateClosed   State = "closed"    // calls go through
+	StateOpen     State = "open"      // calls fail fast with ErrOpen
+	StateHalfOpen State = "half_open" // one trial call decides whether to close again
+)
+
+// Policy configures when a breaker opens and how long it stays open
+type Policy struct {
+	// FailureThreshold is how many consecutive failures open the breaker
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before a trial call is let through
+	Cooldown time.Duration
+	// Ignore lists errors that are answers from a healthy provider, such as "no rate for
+	// this address", rather than signs of an outage. They do not count as failures.
+	Ignore []error
+}
+
+// Ignoring returns a copy of the policy that also ignores the given errors
+func (p Policy) Ignoring(errs ...error) Policy {
+	p.Ignore = append(append([]error(nil), p.Ignore...), errs...)
+	return p
+}
+
+// Status describes a breaker for the admin API
+type Status struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Successes           int64      `json:"successes"`
+	Failures            int64      `json:"failures"`
+	Rejected            int64      `json:"rejected"`
+}
+
+// Breaker stops calling a provider that keeps failing, so callers fail fast or fall back
+// instead of waiting on every request for an outage to time out
+type Breaker struct {
+	name   string
+	policy Policy
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial call is in progress
+	status   Status
+}
+
+// New creates a closed breaker
+func New(name string, policy Policy) *Breaker {
+	if policy.FailureThreshold <= 0 {
+		policy.FailureThreshold = 5
+	}
+	if policy.Cooldown <= 0 {
+		policy.Cooldown = 30 * time.Second
+	}
+	return &Breaker{
+		name:   name,
+		policy: policy,
+		state:  StateClosed,
+	}
+}
+
+// Name returns the provider name the breaker was created with
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Do calls fn unless the breaker is open, and records whether it failed
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !b.allow() {
+		return ErrOpen
+	}
+
+	err := fn(ctx)
+	b.record(ctx, err)
+	return err
+}
+
+// Call is Do for functions that return a value
+func Call[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := b.Do(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Failed reports whether an error returned by Do means the provider could not answer,
+// which is when a caller should use its fallback
+func (b *Breaker) Failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrOpen) {
+		return true
+	}
+	return !b.ignored(err)
+}
+
+// Status returns the breaker's state and counters
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := b.status
+	status.Name = b.name
+	status.State = b.currentState(time.Now())
+	status.ConsecutiveFailures = b.failures
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// allow reports whether a call may go through, letting one trial call through once the
+// cooldown has passed
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState(time.Now()) {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		if !b.trial {
+			b.trial = true
+			b.state = StateHalfOpen
+			return true
+		}
+	}
+	b.status.Rejected++
+	return false
+}
+
+func (b *Breaker) record(ctx context.Context, err error) {
+	// The caller giving up says nothing about the provider
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		b.mu.Lock()
+		b.trial = false
+		b.mu.Unlock()
+		return
+	}
+
+	failed := err != nil && !b.ignored(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+
+	if !failed {
+		b.status.Successes++
+		b.failures = 0
+		b.state = StateClosed
+		return
+	}
+
+	b.status.Failures++
+	b.status.LastError = err.Error()
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.policy.FailureThreshold {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// currentState reports an open breaker whose cooldown has passed as half-open; callers hold b.mu
+func (b *Breaker) currentState(now time.Time) State {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.policy.Cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+func (b *Breaker) ignored(err error) bool {
+	for _, ignore := range b.policy.Ignore {
+		if errors.Is(err, ignore) {
+			return true
+		}
+	}
+	return false
+}
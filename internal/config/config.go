@@ -1,28 +1,84 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/devchuckcamp/goauthx"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Auth        AuthConfig
+	Mail        MailConfig
+	Payments    PaymentsConfig
+	Bots        BotsConfig
+	Captcha     CaptchaConfig
+	WaitingRoom WaitingRoomConfig
+	Load        LoadConfig
+	Providers   ProvidersConfig
+	Currency    CurrencyConfig
+	Metadata    MetadataConfig
+	Media       MediaConfig
+	Images      ImagesConfig
+	Quotes      QuotesConfig
+	Reviews     ReviewsConfig
+	Orders      OrdersConfig
+	Jobs        JobsConfig
+	Schedule    ScheduleConfig
+	Retention   RetentionConfig
+	Locks       LocksConfig
+	Cache       CacheConfig
+	Search      SearchConfig
+	Diagnostics DiagnosticsConfig
+	Logging     LoggingConfig
+	Startup     StartupConfig
+	Maintenance MaintenanceConfig
+	Secrets     SecretsConfig
+	Quotas      QuotasConfig
+	Funnel      FunnelConfig
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port         string
+	Mode         string // gin mode: release, debug or test
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// The request inspector at /debug/requests keeps the last InspectorRequests requests
+	// with their bodies and SQL; it is refused in release mode
+	InspectorEnabled  bool
+	InspectorRequests int
+
+	// TLS is terminated by the API itself when TLSCertFile and TLSKeyFile are set, or with
+	// certificates from Let's Encrypt for AutocertDomains, for deployments without a
+	// fronting proxy
+	TLSCertFile      string
+	TLSKeyFile       string
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+	TLSMinVersion    string // 1.2 or 1.3
+	HTTP2            bool   // offer HTTP/2 on the TLS listener
+	HTTPRedirectAddr string // plain HTTP listener redirecting to HTTPS and answering ACME challenges; empty disables
+}
+
+// TLSEnabled reports whether the API serves HTTPS itself
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" || len(s.AutocertDomains) > 0
 }
 
 // DatabaseConfig holds database connection configuration
@@ -32,6 +88,24 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+
+	// DSNSource looks up the DSN again when DB_DSN is a secret reference, so new
+	// connections pick up rotated credentials; nil when DB_DSN is set directly
+	DSNSource func(ctx context.Context) (string, error)
+
+	// EncryptionKeys encrypt PII columns, as id:base64secret entries. The first key
+	// encrypts new values; the rest only decrypt. Empty leaves PII in plaintext.
+	EncryptionKeys []string
+
+	// Cart, order and inventory writes that fail with a serialization failure or deadlock
+	// are run again, up to WriteRetryAttempts attempts in all
+	WriteRetryAttempts  int
+	WriteRetryBaseDelay time.Duration // wait before the first retry; doubles for each later one
+	WriteRetryMaxDelay  time.Duration
+
+	// IDStrategy is how new record IDs are made: uuidv4, or uuidv7 or ulid so they sort
+	// by creation time
+	IDStrategy string
 }
 
 // AuthConfig holds authentication configuration
@@ -45,6 +119,280 @@ type AuthConfig struct {
 	GoogleClientSecret string
 	GoogleRedirectURL  string
 	GoogleOAuthEnabled bool
+
+	// Progressive lockout: LoginMaxFailures failed logins in a row lock the account for
+	// LockoutBase, doubling with each further lock up to LockoutMax
+	LoginMaxFailures int
+	LockoutBase      time.Duration
+	LockoutMax       time.Duration
+	NewDeviceAlerts  bool // alert customers who sign in from a new device
+
+	// ConsentPolicyVersion is the privacy policy version shown with consent prompts;
+	// choices made under another version are reported as outdated
+	ConsentPolicyVersion string
+
+	// GuestSessionTTL is how long a guest session token is valid; guests renew it to keep
+	// their cart
+	GuestSessionTTL time.Duration
+}
+
+// MailConfig holds the SMTP server used for account emails; without a host they are logged
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// PaymentsConfig holds payment gateway, retry (dunning), webhook and store credit configuration
+type PaymentsConfig struct {
+	Provider             string // "" for no gateway, or mock
+	Mock                 MockPaymentsConfig
+	RetryMaxAttempts     int
+	RetryWindow          time.Duration
+	RetrySweepInterval   time.Duration
+	WebhookSecret        string
+	StoreCreditAutoApply bool
+}
+
+// MockPaymentsConfig tunes the mock gateway used with PAYMENTS_PROVIDER=mock
+type MockPaymentsConfig struct {
+	Mode         string // approve, decline or async
+	FailureRate  float64
+	ErrorRate    float64
+	Latency      time.Duration
+	WebhookDelay time.Duration
+}
+
+// JobsConfig holds settings for the background job runner
+type JobsConfig struct {
+	Workers   int
+	QueueSize int
+}
+
+// ScheduleConfig holds cron schedules for recurring tasks
+type ScheduleConfig struct {
+	Enabled           bool // when false, tasks only run when triggered from the admin API
+	CartExpiry        string
+	SalePrices        string
+	PriceCache        string
+	WebhookRequeue    string
+	FieldRekey        string // re-encrypts PII under the primary key; runs only with encryption keys
+	Retention         string
+	InventorySnapshot string
+	CategoryCounts    string
+	SearchTerms       string
+	SecretRefresh     string // re-fetches secrets; runs only when variables reference a secrets manager
+	QuotaPrune        string
+	FunnelCheck       string
+	FunnelPrune       string
+	OrderExportPrune  string
+	OrderArchive      string
+}
+
+// RetentionConfig holds how long personal and bulky data is kept; 0 keeps it forever
+type RetentionConfig struct {
+	GuestCarts      time.Duration
+	WebhookPayloads time.Duration
+	IPAddresses     time.Duration
+	DryRun          bool // the data-retention task only logs what it would change
+}
+
+// DiagnosticsConfig holds the admin-only pprof and expvar listener, kept off the public port
+type DiagnosticsConfig struct {
+	Enabled bool
+	Addr    string
+
+	// The listener serves HTTPS with TLSCertFile and TLSKeyFile, and with ClientCAFile only
+	// to clients presenting a certificate it signed (mutual TLS)
+	TLSCertFile  string
+	TLSKeyFile   string
+	ClientCAFile string
+}
+
+// QuotasConfig holds the daily and monthly request quotas of API keys and user roles
+type QuotasConfig struct {
+	Tiers []string // tier:daily:monthly entries; a tier is a role, or api_key for API keys
+}
+
+// FunnelConfig holds checkout funnel drop-off alerting
+type FunnelConfig struct {
+	Window             time.Duration // recent period whose conversion is checked
+	Baseline           time.Duration // period before it that conversion is compared with
+	MaxDrop            float64       // fraction a step may fall below its baseline before alerting
+	MinSample          int           // carts or orders entering a step before it is checked
+	AlertWebhookURL    string        // alerts are only logged without one
+	AlertWebhookSecret string
+	AlertTimeout       time.Duration
+}
+
+// LoggingConfig holds request log sampling; failed requests are always logged in full
+type LoggingConfig struct {
+	SampleRate   float64  // share of successful requests to SampledPaths logged
+	SampledPaths []string // high-volume path prefixes, after /api/vN, such as /catalog
+}
+
+// StartupConfig holds the dependency checks run before the API serves requests
+type StartupConfig struct {
+	Checks          string        // fail (refuse to start), degraded (start and report) or off
+	RequiredSecrets []string      // environment variables that must be set, beyond those features imply
+	CheckProviders  bool          // also connect to the configured external providers
+	ProviderTimeout time.Duration // per provider connection
+}
+
+// MaintenanceConfig holds maintenance mode, which closes the storefront API while admins
+// keep working
+type MaintenanceConfig struct {
+	Enabled bool
+	Message string // sent with the 503; empty sends a default
+}
+
+// LocksConfig selects how scheduled tasks and stock changes are kept to one replica
+type LocksConfig struct {
+	Backend string // postgres (advisory locks) or local (single replica only)
+}
+
+// CacheConfig holds catalog cache settings
+type CacheConfig struct {
+	ProductTTL   time.Duration // 0 disables the product detail cache
+	ListTTL      time.Duration // 0 disables the category and brand list caches
+	WarmOnStart  bool
+	WarmProducts int // best sellers loaded by a warm-up
+
+	ResponseTTL        time.Duration // 0 disables the public catalog response cache
+	ResponseMaxEntries int
+	CDNPurgeURL        string // surrogate keys are also purged here; empty purges only this process
+	CDNPurgeToken      string
+	CDNPurgeTimeout    time.Duration
+}
+
+// SearchConfig holds product search settings
+type SearchConfig struct {
+	SpellCorrection bool          // retry searches that find nothing with their spelling corrected
+	RulesCacheTTL   time.Duration // how long synonyms and search rules are cached; 0 loads them on every search
+}
+
+// BotsConfig holds bot detection settings for catalog endpoints
+type BotsConfig struct {
+	Enabled             bool
+	AllowUserAgents     []string
+	BlockUserAgents     []string
+	BlockEmptyUserAgent bool
+	ThrottlePerMinute   int
+}
+
+// CaptchaConfig holds the CAPTCHA challenge on sign-up and checkout
+type CaptchaConfig struct {
+	Provider string   // hcaptcha or turnstile; empty disables the challenge
+	Secret   string   // provider secret key used to verify responses
+	Routes   []string // register, checkout
+	FailOpen bool     // let requests through while the provider is unreachable
+	Timeout  time.Duration
+}
+
+// WaitingRoomConfig holds the queue for limited-drop products. Customers join a product's
+// queue and AdmitBatch of them are admitted every AdmitInterval.
+type WaitingRoomConfig struct {
+	Enabled       bool
+	Products      []string      // product IDs that require an admitted token to add to cart
+	AdmitBatch    int           // tickets admitted per product on each run
+	AdmitInterval time.Duration // how often a batch is admitted
+	AccessTTL     time.Duration // how long an admitted token can be used
+}
+
+// ProvidersConfig holds circuit breaker and fallback settings for external providers
+type ProvidersConfig struct {
+	BreakerFailures      int           // consecutive failures that open a provider's breaker
+	BreakerCooldown      time.Duration // how long a breaker stays open before a trial call
+	FallbackShippingRate int64         // flat shipping cost in cents while the shipping provider is down
+	FallbackTaxRate      float64       // flat tax rate while the tax provider is down
+	Currency             string
+	RetryAttempts        int           // attempts per provider call, including the first
+	RetryBaseDelay       time.Duration // wait before the first retry; doubles for each later one
+	RetryMaxDelay        time.Duration
+	RetryBudget          time.Duration // total time for all attempts of one provider call
+}
+
+// CurrencyConfig holds the exchange rates used to convert promotion minimum purchases
+// into the cart currency, the tax rate, and how prices and taxes are rounded
+type CurrencyConfig struct {
+	Base          string
+	ExchangeRates []string // CODE:rate entries, the units of CODE one unit of Base buys
+	PriceRounding []string // CODE:mode:value entries, e.g. CHF:nearest:5 or USD:charm:99
+	TaxRate       float64  // flat sales tax rate, e.g. 0.0875 for 8.75%
+	TaxRounding   string   // truncate, half_up or half_even for fractional cents of tax
+}
+
+// MetadataConfig limits the custom metadata clients attach to carts and cart items
+type MetadataConfig struct {
+	AllowedKeys    []string // keys clients may set; empty turns metadata off
+	MaxKeys        int      // per cart or item
+	MaxValueLength int      // bytes per value
+}
+
+// MediaConfig holds where uploaded media such as avatars are stored and served from
+type MediaConfig struct {
+	Dir           string // local directory uploads are written to
+	BaseURL       string // URL prefix of stored files; /media is served from Dir by the API
+	MaxAvatarSize int    // bytes
+}
+
+// ImagesConfig holds the imgproxy-compatible CDN product image renditions are served from
+type ImagesConfig struct {
+	CDNURL    string   // resizing CDN base URL; empty leaves renditions out of product responses
+	SourceURL string   // public URL prefix relative image paths such as /media/... are fetched from
+	Key       string   // hex-encoded URL signing key; empty sends unsigned URLs
+	Salt      string   // hex-encoded URL signing salt
+	Presets   []string // name:WIDTHxHEIGHT[:format] entries, e.g. thumb:160x160:webp
+}
+
+// QuotesConfig holds the B2B quote flow settings
+type QuotesConfig struct {
+	Validity time.Duration // how long sent quotes stay open when sales sets no date
+}
+
+// ReviewsConfig holds product review and Q&A settings
+type ReviewsConfig struct {
+	MaxPhotos           int      // photos per review; 0 turns photo uploads off
+	MaxPhotoSize        int      // bytes
+	BlockedWords        []string // masked in reviews, questions and answers on top of the built-in list
+	ReportHideThreshold int      // open reports that hide a review, question or answer; 0 never hides
+}
+
+// OrdersConfig holds order persistence settings
+type OrdersConfig struct {
+	TotalsCheck string // reject, flag or off for orders whose totals don't add up
+	// MaxPriceDrop is the largest share a price may be cut by in one change, or an order
+	// discounted by, before it is held for pricing review
+	MaxPriceDrop       float64
+	PricingAlertEmails []string // admins emailed about held changes and orders
+
+	// Customer order exports of more than ExportSyncLimit orders are written to ExportDir
+	// in the background and downloaded through a signed link for ExportTTL
+	ExportDir       string
+	ExportSyncLimit int
+	ExportTTL       time.Duration
+
+	// Customers and admins may resend an order's confirmation email once per
+	// ConfirmationResendCooldown, and at most ConfirmationResendLimit times per
+	// ConfirmationResendWindow
+	ConfirmationResendCooldown time.Duration
+	ConfirmationResendLimit    int
+	ConfirmationResendWindow   time.Duration
+
+	// Delivered orders not changed for ArchiveAfterMonths are moved to the archive table by
+	// the order-archive task, ArchiveBatchSize at a time; 0 months never archives
+	ArchiveAfterMonths int
+	ArchiveBatchSize   int
+	ArchiveDryRun      bool // the order-archive task only logs what it would move
+}
+
+// LoadConfig holds load-shedding thresholds for low-priority endpoints
+type LoadConfig struct {
+	SheddingEnabled bool
+	MaxInFlight     int           // 0 disables the in-flight check
+	MaxLatency      time.Duration // 0 disables the latency check
 }
 
 // Load loads configuration from environment variables
@@ -52,12 +400,29 @@ func Load() (*Config, error) {
 	// Try to load .env file (optional)
 	_ = godotenv.Load()
 
+	// Resolve secret references first; the getters below read the resolved values
+	secretsCfg, err := loadSecrets()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8080"),
-			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
-			IdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			Port:              getEnv("PORT", "8080"),
+			Mode:              getEnv("GIN_MODE", "release"),
+			ReadTimeout:       getDurationEnv("SERVER_READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:      getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			IdleTimeout:       getDurationEnv("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			InspectorEnabled:  getBoolEnv("DEBUG_INSPECTOR_ENABLED", false),
+			InspectorRequests: getIntEnv("DEBUG_INSPECTOR_REQUESTS", 100),
+			TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
+			TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
+			AutocertDomains:   getListEnv("TLS_AUTOCERT_DOMAINS", nil),
+			AutocertCacheDir:  getEnv("TLS_AUTOCERT_CACHE_DIR", "./certs"),
+			AutocertEmail:     getEnv("TLS_AUTOCERT_EMAIL", ""),
+			TLSMinVersion:     getEnv("TLS_MIN_VERSION", "1.2"),
+			HTTP2:             getBoolEnv("HTTP2_ENABLED", true),
+			HTTPRedirectAddr:  getEnv("HTTP_REDIRECT_ADDR", ""),
 		},
 		Database: DatabaseConfig{
 			Driver:          getEnv("DB_DRIVER", "postgres"),
@@ -65,20 +430,228 @@ func Load() (*Config, error) {
 			MaxOpenConns:    getIntEnv("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			EncryptionKeys:  getListEnv("FIELD_ENCRYPTION_KEYS", nil),
+			IDStrategy:      getEnv("ID_STRATEGY", "uuidv4"),
+
+			WriteRetryAttempts:  getIntEnv("DB_WRITE_RETRY_ATTEMPTS", 3),
+			WriteRetryBaseDelay: getDurationEnv("DB_WRITE_RETRY_BASE_DELAY", 20*time.Millisecond),
+			WriteRetryMaxDelay:  getDurationEnv("DB_WRITE_RETRY_MAX_DELAY", 200*time.Millisecond),
 		},
 		Auth: AuthConfig{
-			JWTSecret:          getEnv("JWT_SECRET", ""),
-			AccessTokenExpiry:  getDurationEnv("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
-			RefreshTokenExpiry: getDurationEnv("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
-			JWTIssuer:          getEnv("JWT_ISSUER", "gocommerce-api"),
-			JWTAudience:        getEnv("JWT_AUDIENCE", "gocommerce-api-users"),
-			GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-			GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-			GoogleRedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/auth/google/callback"),
-			GoogleOAuthEnabled: getEnv("GOOGLE_CLIENT_ID", "") != "" && getEnv("GOOGLE_CLIENT_SECRET", "") != "",
+			JWTSecret:            getEnv("JWT_SECRET", ""),
+			AccessTokenExpiry:    getDurationEnv("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
+			RefreshTokenExpiry:   getDurationEnv("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
+			JWTIssuer:            getEnv("JWT_ISSUER", "gocommerce-api"),
+			JWTAudience:          getEnv("JWT_AUDIENCE", "gocommerce-api-users"),
+			GoogleClientID:       getEnv("GOOGLE_CLIENT_ID", ""),
+			GoogleClientSecret:   getEnv("GOOGLE_CLIENT_SECRET", ""),
+			GoogleRedirectURL:    getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/auth/google/callback"),
+			GoogleOAuthEnabled:   getEnv("GOOGLE_CLIENT_ID", "") != "" && getEnv("GOOGLE_CLIENT_SECRET", "") != "",
+			LoginMaxFailures:     getIntEnv("LOGIN_MAX_FAILURES", 5),
+			LockoutBase:          getDurationEnv("LOGIN_LOCKOUT_BASE", time.Minute),
+			LockoutMax:           getDurationEnv("LOGIN_LOCKOUT_MAX", time.Hour),
+			NewDeviceAlerts:      getBoolEnv("LOGIN_NEW_DEVICE_ALERTS", true),
+			ConsentPolicyVersion: getEnv("CONSENT_POLICY_VERSION", ""),
+			GuestSessionTTL:      getDurationEnv("GUEST_SESSION_TTL", 30*24*time.Hour),
+		},
+		Mail: MailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getIntEnv("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "no-reply@gocommerce.local"),
+		},
+		Payments: PaymentsConfig{
+			Provider: getEnv("PAYMENTS_PROVIDER", ""),
+			Mock: MockPaymentsConfig{
+				Mode:         getEnv("MOCK_PAYMENTS_MODE", "approve"),
+				FailureRate:  getFloatEnv("MOCK_PAYMENTS_FAILURE_RATE", 0),
+				ErrorRate:    getFloatEnv("MOCK_PAYMENTS_ERROR_RATE", 0),
+				Latency:      getDurationEnv("MOCK_PAYMENTS_LATENCY", 0),
+				WebhookDelay: getDurationEnv("MOCK_PAYMENTS_WEBHOOK_DELAY", 3*time.Second),
+			},
+			RetryMaxAttempts:     getIntEnv("PAYMENT_RETRY_MAX_ATTEMPTS", 3),
+			RetryWindow:          getDurationEnv("PAYMENT_RETRY_WINDOW", 72*time.Hour),
+			RetrySweepInterval:   getDurationEnv("PAYMENT_RETRY_SWEEP_INTERVAL", 15*time.Minute),
+			WebhookSecret:        getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			StoreCreditAutoApply: getBoolEnv("STORE_CREDIT_AUTO_APPLY", true),
+		},
+		Bots: BotsConfig{
+			Enabled:             getBoolEnv("BOT_GUARD_ENABLED", true),
+			AllowUserAgents:     getListEnv("BOT_ALLOW_USER_AGENTS", []string{"googlebot", "bingbot", "duckduckbot", "applebot", "yandexbot", "baiduspider", "slurp"}),
+			BlockUserAgents:     getListEnv("BOT_BLOCK_USER_AGENTS", []string{"ahrefsbot", "semrushbot", "mj12bot", "dotbot", "petalbot", "scrapy", "httrack"}),
+			BlockEmptyUserAgent: getBoolEnv("BOT_BLOCK_EMPTY_USER_AGENT", false),
+			ThrottlePerMinute:   getIntEnv("BOT_THROTTLE_PER_MINUTE", 60),
+		},
+		Captcha: CaptchaConfig{
+			Provider: getEnv("CAPTCHA_PROVIDER", ""),
+			Secret:   getEnv("CAPTCHA_SECRET", ""),
+			Routes:   getListEnv("CAPTCHA_ROUTES", []string{"register"}),
+			FailOpen: getBoolEnv("CAPTCHA_FAIL_OPEN", false),
+			Timeout:  getDurationEnv("CAPTCHA_TIMEOUT", 3*time.Second),
+		},
+		WaitingRoom: WaitingRoomConfig{
+			Enabled:       getBoolEnv("WAITING_ROOM_ENABLED", false),
+			Products:      getListEnv("WAITING_ROOM_PRODUCTS", nil),
+			AdmitBatch:    getIntEnv("WAITING_ROOM_ADMIT_BATCH", 50),
+			AdmitInterval: getDurationEnv("WAITING_ROOM_ADMIT_INTERVAL", 30*time.Second),
+			AccessTTL:     getDurationEnv("WAITING_ROOM_ACCESS_TTL", 10*time.Minute),
+		},
+		Load: LoadConfig{
+			SheddingEnabled: getBoolEnv("LOAD_SHEDDING_ENABLED", true),
+			MaxInFlight:     getIntEnv("LOAD_SHED_MAX_IN_FLIGHT", 500),
+			MaxLatency:      getDurationEnv("LOAD_SHED_MAX_LATENCY", 2*time.Second),
+		},
+		Providers: ProvidersConfig{
+			BreakerFailures:      getIntEnv("BREAKER_FAILURE_THRESHOLD", 5),
+			BreakerCooldown:      getDurationEnv("BREAKER_COOLDOWN", 30*time.Second),
+			FallbackShippingRate: int64(getIntEnv("FALLBACK_SHIPPING_RATE", 999)),
+			FallbackTaxRate:      getFloatEnv("FALLBACK_TAX_RATE", 0.0875),
+			Currency:             getEnv("FALLBACK_CURRENCY", "USD"),
+			RetryAttempts:        getIntEnv("PROVIDER_RETRY_ATTEMPTS", 3),
+			RetryBaseDelay:       getDurationEnv("PROVIDER_RETRY_BASE_DELAY", 100*time.Millisecond),
+			RetryMaxDelay:        getDurationEnv("PROVIDER_RETRY_MAX_DELAY", time.Second),
+			RetryBudget:          getDurationEnv("PROVIDER_RETRY_BUDGET", 3*time.Second),
+		},
+		Currency: CurrencyConfig{
+			Base:          getEnv("BASE_CURRENCY", "USD"),
+			ExchangeRates: getListEnv("EXCHANGE_RATES", nil),
+			PriceRounding: getListEnv("PRICE_ROUNDING", nil),
+			TaxRate:       getFloatEnv("TAX_RATE", 0.0875),
+			TaxRounding:   getEnv("TAX_ROUNDING", "truncate"),
+		},
+		Metadata: MetadataConfig{
+			AllowedKeys:    getListEnv("METADATA_ALLOWED_KEYS", []string{"campaign_id", "gift_message", "personalization"}),
+			MaxKeys:        getIntEnv("METADATA_MAX_KEYS", 10),
+			MaxValueLength: getIntEnv("METADATA_MAX_VALUE_LENGTH", 500),
+		},
+		Media: MediaConfig{
+			Dir:           getEnv("MEDIA_DIR", "./media"),
+			BaseURL:       strings.TrimSuffix(getEnv("MEDIA_BASE_URL", "/media"), "/"),
+			MaxAvatarSize: getIntEnv("MEDIA_MAX_AVATAR_SIZE", 2<<20),
+		},
+		Images: ImagesConfig{
+			CDNURL:    strings.TrimSuffix(getEnv("IMAGE_CDN_URL", ""), "/"),
+			SourceURL: strings.TrimSuffix(getEnv("IMAGE_SOURCE_URL", ""), "/"),
+			Key:       getEnv("IMAGE_SIGNING_KEY", ""),
+			Salt:      getEnv("IMAGE_SIGNING_SALT", ""),
+			Presets:   getListEnv("IMAGE_PRESETS", []string{"thumb:160x160:webp", "card:480x480:webp", "zoom:1600x1600:webp"}),
+		},
+		Quotes: QuotesConfig{
+			Validity: getDurationEnv("QUOTE_VALIDITY", 30*24*time.Hour),
+		},
+		Reviews: ReviewsConfig{
+			MaxPhotos:           getIntEnv("REVIEW_MAX_PHOTOS", 5),
+			MaxPhotoSize:        getIntEnv("REVIEW_MAX_PHOTO_SIZE", 5<<20),
+			BlockedWords:        getListEnv("REVIEW_BLOCKED_WORDS", nil),
+			ReportHideThreshold: getIntEnv("REPORT_HIDE_THRESHOLD", 3),
+		},
+		Orders: OrdersConfig{
+			TotalsCheck:        getEnv("ORDER_TOTALS_CHECK", "reject"),
+			MaxPriceDrop:       getFloatEnv("PRICING_ALERT_MAX_DROP", 0.8),
+			PricingAlertEmails: getListEnv("PRICING_ALERT_EMAILS", nil),
+			ExportDir:          getEnv("ORDER_EXPORT_DIR", "./exports"),
+			ExportSyncLimit:    getIntEnv("ORDER_EXPORT_SYNC_LIMIT", 500),
+			ExportTTL:          getDurationEnv("ORDER_EXPORT_TTL", 24*time.Hour),
+
+			ConfirmationResendCooldown: getDurationEnv("ORDER_CONFIRMATION_RESEND_COOLDOWN", time.Minute),
+			ConfirmationResendLimit:    getIntEnv("ORDER_CONFIRMATION_RESEND_LIMIT", 5),
+			ConfirmationResendWindow:   getDurationEnv("ORDER_CONFIRMATION_RESEND_WINDOW", 24*time.Hour),
+
+			ArchiveAfterMonths: getIntEnv("ORDER_ARCHIVE_AFTER_MONTHS", 0),
+			ArchiveBatchSize:   getIntEnv("ORDER_ARCHIVE_BATCH_SIZE", 500),
+			ArchiveDryRun:      getBoolEnv("ORDER_ARCHIVE_DRY_RUN", false),
+		},
+		Jobs: JobsConfig{
+			Workers:   getIntEnv("JOB_WORKERS", 4),
+			QueueSize: getIntEnv("JOB_QUEUE_SIZE", 1000),
+		},
+		Schedule: ScheduleConfig{
+			Enabled:           getBoolEnv("SCHEDULER_ENABLED", true),
+			CartExpiry:        getEnv("SCHEDULE_CART_EXPIRY", "0 3 * * *"),
+			SalePrices:        getEnv("SCHEDULE_SALE_PRICES", "*/15 * * * *"),
+			PriceCache:        getEnv("SCHEDULE_PRICE_CACHE", "* * * * *"),
+			WebhookRequeue:    getEnv("SCHEDULE_WEBHOOK_REQUEUE", "*/5 * * * *"),
+			FieldRekey:        getEnv("SCHEDULE_FIELD_REKEY", "0 4 * * *"),
+			Retention:         getEnv("SCHEDULE_RETENTION", "30 3 * * *"),
+			InventorySnapshot: getEnv("SCHEDULE_INVENTORY_SNAPSHOT", "55 23 * * *"),
+			CategoryCounts:    getEnv("SCHEDULE_CATEGORY_COUNTS", "15 * * * *"),
+			SearchTerms:       getEnv("SCHEDULE_SEARCH_TERMS", "20 * * * *"),
+			SecretRefresh:     getEnv("SCHEDULE_SECRET_REFRESH", "*/5 * * * *"),
+			QuotaPrune:        getEnv("SCHEDULE_QUOTA_PRUNE", "50 0 * * *"),
+			FunnelCheck:       getEnv("SCHEDULE_FUNNEL_CHECK", "*/15 * * * *"),
+			FunnelPrune:       getEnv("SCHEDULE_FUNNEL_PRUNE", "40 2 * * *"),
+			OrderExportPrune:  getEnv("SCHEDULE_ORDER_EXPORT_PRUNE", "20 * * * *"),
+			OrderArchive:      getEnv("SCHEDULE_ORDER_ARCHIVE", "45 3 * * *"),
+		},
+		Retention: RetentionConfig{
+			GuestCarts:      getDurationEnv("RETENTION_GUEST_CARTS", 90*24*time.Hour),
+			WebhookPayloads: getDurationEnv("RETENTION_WEBHOOK_PAYLOADS", 30*24*time.Hour),
+			IPAddresses:     getDurationEnv("RETENTION_IP_ADDRESSES", 365*24*time.Hour),
+			DryRun:          getBoolEnv("RETENTION_DRY_RUN", false),
+		},
+		Cache: CacheConfig{
+			ProductTTL:   getDurationEnv("PRODUCT_CACHE_TTL", 5*time.Minute),
+			ListTTL:      getDurationEnv("CATALOG_LIST_CACHE_TTL", 5*time.Minute),
+			WarmOnStart:  getBoolEnv("CACHE_WARM_ON_START", true),
+			WarmProducts: getIntEnv("CACHE_WARM_PRODUCTS", 100),
+
+			ResponseTTL:        getDurationEnv("RESPONSE_CACHE_TTL", 0),
+			ResponseMaxEntries: getIntEnv("RESPONSE_CACHE_MAX_ENTRIES", 10000),
+			CDNPurgeURL:        getEnv("CDN_PURGE_URL", ""),
+			CDNPurgeToken:      getEnv("CDN_PURGE_TOKEN", ""),
+			CDNPurgeTimeout:    getDurationEnv("CDN_PURGE_TIMEOUT", 5*time.Second),
+		},
+		Search: SearchConfig{
+			SpellCorrection: getBoolEnv("SEARCH_SPELL_CORRECTION", true),
+			RulesCacheTTL:   getDurationEnv("SEARCH_RULES_CACHE_TTL", time.Minute),
+		},
+		Locks: LocksConfig{
+			Backend: getEnv("LOCK_BACKEND", defaultLockBackend(getEnv("DB_DRIVER", "postgres"))),
+		},
+		Diagnostics: DiagnosticsConfig{
+			Enabled: getBoolEnv("DIAGNOSTICS_ENABLED", false),
+			Addr:    getEnv("DIAGNOSTICS_ADDR", "127.0.0.1:6060"),
+
+			TLSCertFile:  getEnv("DIAGNOSTICS_TLS_CERT_FILE", ""),
+			TLSKeyFile:   getEnv("DIAGNOSTICS_TLS_KEY_FILE", ""),
+			ClientCAFile: getEnv("DIAGNOSTICS_CLIENT_CA_FILE", ""),
+		},
+		Logging: LoggingConfig{
+			SampleRate:   getFloatEnv("LOG_SAMPLE_RATE", 0.1),
+			SampledPaths: getListEnv("LOG_SAMPLED_PATHS", []string{"/catalog", "/content", "/health"}),
+		},
+		Startup: StartupConfig{
+			Checks:          getEnv("STARTUP_CHECKS", "fail"),
+			RequiredSecrets: getListEnv("STARTUP_REQUIRED_SECRETS", nil),
+			CheckProviders:  getBoolEnv("STARTUP_CHECK_PROVIDERS", false),
+			ProviderTimeout: getDurationEnv("STARTUP_PROVIDER_TIMEOUT", 3*time.Second),
+		},
+		Quotas: QuotasConfig{
+			Tiers: getListEnv("QUOTA_TIERS", nil),
+		},
+		Funnel: FunnelConfig{
+			Window:             getDurationEnv("FUNNEL_WINDOW", time.Hour),
+			Baseline:           getDurationEnv("FUNNEL_BASELINE", 7*24*time.Hour),
+			MaxDrop:            getFloatEnv("FUNNEL_ALERT_MAX_DROP", 0.5),
+			MinSample:          getIntEnv("FUNNEL_ALERT_MIN_SAMPLE", 50),
+			AlertWebhookURL:    getEnv("FUNNEL_ALERT_WEBHOOK_URL", ""),
+			AlertWebhookSecret: getEnv("FUNNEL_ALERT_WEBHOOK_SECRET", ""),
+			AlertTimeout:       getDurationEnv("FUNNEL_ALERT_TIMEOUT", 5*time.Second),
+		},
+		Maintenance: MaintenanceConfig{
+			Enabled: getBoolEnv("MAINTENANCE_MODE", false),
+			Message: getEnv("MAINTENANCE_MESSAGE", ""),
 		},
 	}
 
+	cfg.Secrets = secretsCfg
+	if ref, ok := secretsCfg.References["DB_DSN"]; ok {
+		cfg.Database.DSNSource = func(ctx context.Context) (string, error) {
+			return secretsCfg.Resolver.Resolve(ctx, ref)
+		}
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -86,6 +659,16 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// Reload reads the .env file again, its values replacing those already in the
+// environment, and loads the configuration from the result. Only the settings the API
+// applies at runtime take effect; the rest still need a restart.
+func Reload() (*Config, error) {
+	if err := godotenv.Overload(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+	return Load()
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Database.DSN == "" {
@@ -100,6 +683,86 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
 	}
 
+	switch c.Server.Mode {
+	case "release", "debug", "test":
+	default:
+		return fmt.Errorf("invalid GIN_MODE: %s (must be release, debug, or test)", c.Server.Mode)
+	}
+	if c.Server.InspectorEnabled && c.Server.Mode == "release" {
+		return fmt.Errorf("DEBUG_INSPECTOR_ENABLED requires GIN_MODE=debug; the inspector keeps request bodies in memory")
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.Server.TLSCertFile != "" && len(c.Server.AutocertDomains) > 0 {
+		return fmt.Errorf("set either TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS, not both")
+	}
+	if len(c.Server.AutocertDomains) > 0 && c.Server.AutocertCacheDir == "" {
+		return fmt.Errorf("TLS_AUTOCERT_DOMAINS requires TLS_AUTOCERT_CACHE_DIR")
+	}
+	if c.Server.TLSMinVersion != "1.2" && c.Server.TLSMinVersion != "1.3" {
+		return fmt.Errorf("invalid TLS_MIN_VERSION: %s (must be 1.2 or 1.3)", c.Server.TLSMinVersion)
+	}
+	if c.Server.HTTPRedirectAddr != "" && !c.Server.TLSEnabled() {
+		return fmt.Errorf("HTTP_REDIRECT_ADDR requires TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS")
+	}
+
+	if c.Auth.LoginMaxFailures < 1 {
+		return fmt.Errorf("LOGIN_MAX_FAILURES must be at least 1")
+	}
+	if c.Auth.LockoutBase <= 0 || c.Auth.LockoutMax < c.Auth.LockoutBase {
+		return fmt.Errorf("LOGIN_LOCKOUT_BASE must be positive and no longer than LOGIN_LOCKOUT_MAX")
+	}
+	if c.Auth.GuestSessionTTL < time.Minute {
+		return fmt.Errorf("GUEST_SESSION_TTL must be at least 1m")
+	}
+
+	if c.Quotes.Validity < time.Hour {
+		return fmt.Errorf("QUOTE_VALIDITY must be at least 1h")
+	}
+
+	if c.Currency.TaxRate < 0 || c.Currency.TaxRate >= 1 || c.Providers.FallbackTaxRate < 0 || c.Providers.FallbackTaxRate >= 1 {
+		return fmt.Errorf("TAX_RATE and FALLBACK_TAX_RATE must be at least 0 and below 1")
+	}
+
+	if c.Orders.MaxPriceDrop <= 0 || c.Orders.MaxPriceDrop >= 1 {
+		return fmt.Errorf("PRICING_ALERT_MAX_DROP must be between 0 and 1")
+	}
+	if c.Orders.ExportDir == "" || c.Orders.ExportSyncLimit < 1 || c.Orders.ExportTTL < time.Minute {
+		return fmt.Errorf("ORDER_EXPORT_DIR is required, ORDER_EXPORT_SYNC_LIMIT must be at least 1 and ORDER_EXPORT_TTL at least 1m")
+	}
+	if c.Orders.ConfirmationResendCooldown < 0 || c.Orders.ConfirmationResendLimit < 1 || c.Orders.ConfirmationResendWindow < c.Orders.ConfirmationResendCooldown {
+		return fmt.Errorf("ORDER_CONFIRMATION_RESEND_LIMIT must be at least 1 and ORDER_CONFIRMATION_RESEND_WINDOW no shorter than ORDER_CONFIRMATION_RESEND_COOLDOWN")
+	}
+
+	if c.Funnel.Window < time.Minute || c.Funnel.Baseline < c.Funnel.Window {
+		return fmt.Errorf("FUNNEL_WINDOW must be at least 1m and FUNNEL_BASELINE no shorter than it")
+	}
+	if c.Funnel.MaxDrop <= 0 || c.Funnel.MaxDrop >= 1 {
+		return fmt.Errorf("FUNNEL_ALERT_MAX_DROP must be between 0 and 1")
+	}
+
+	if c.Payments.RetryMaxAttempts < 1 {
+		return fmt.Errorf("PAYMENT_RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+
+	switch c.Payments.Provider {
+	case "":
+	case "mock":
+		// The mock settles processing charges with webhooks signed by this secret
+		if c.Payments.WebhookSecret == "" {
+			return fmt.Errorf("PAYMENTS_PROVIDER=mock requires PAYMENT_WEBHOOK_SECRET")
+		}
+		switch c.Payments.Mock.Mode {
+		case "approve", "decline", "async":
+		default:
+			return fmt.Errorf("invalid MOCK_PAYMENTS_MODE: %s (must be approve, decline, or async)", c.Payments.Mock.Mode)
+		}
+	default:
+		return fmt.Errorf("invalid PAYMENTS_PROVIDER: %s (must be mock, or empty for no gateway)", c.Payments.Provider)
+	}
+
 	validDrivers := map[string]bool{
 		"postgres":  true,
 		"mysql":     true,
@@ -109,9 +772,132 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid DB_DRIVER: %s (must be postgres, mysql, or sqlserver)", c.Database.Driver)
 	}
 
+	if len(c.Database.EncryptionKeys) > 0 {
+		keys, err := fieldcrypt.ParseKeys(c.Database.EncryptionKeys)
+		if err != nil {
+			return fmt.Errorf("invalid FIELD_ENCRYPTION_KEYS: %w", err)
+		}
+		if _, err := fieldcrypt.New(keys...); err != nil {
+			return fmt.Errorf("invalid FIELD_ENCRYPTION_KEYS: %w", err)
+		}
+	}
+
+	switch c.Captcha.Provider {
+	case "":
+	case "hcaptcha", "turnstile":
+		if c.Captcha.Secret == "" {
+			return fmt.Errorf("CAPTCHA_PROVIDER=%s requires CAPTCHA_SECRET", c.Captcha.Provider)
+		}
+		for _, route := range c.Captcha.Routes {
+			if route != "register" && route != "checkout" {
+				return fmt.Errorf("invalid CAPTCHA_ROUTES entry: %s (must be register or checkout)", route)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid CAPTCHA_PROVIDER: %s (must be hcaptcha, turnstile, or empty to disable)", c.Captcha.Provider)
+	}
+
+	if c.WaitingRoom.Enabled {
+		if c.WaitingRoom.AdmitBatch < 1 {
+			return fmt.Errorf("WAITING_ROOM_ADMIT_BATCH must be at least 1")
+		}
+		if c.WaitingRoom.AdmitInterval < time.Second {
+			return fmt.Errorf("WAITING_ROOM_ADMIT_INTERVAL must be at least 1s")
+		}
+		if c.WaitingRoom.AccessTTL <= 0 {
+			return fmt.Errorf("WAITING_ROOM_ACCESS_TTL must be positive")
+		}
+	}
+
+	if c.Metadata.MaxKeys < 1 || c.Metadata.MaxValueLength < 1 {
+		return fmt.Errorf("METADATA_MAX_KEYS and METADATA_MAX_VALUE_LENGTH must be at least 1")
+	}
+
+	if c.Media.Dir == "" {
+		return fmt.Errorf("MEDIA_DIR is required")
+	}
+	if c.Media.MaxAvatarSize < 1 {
+		return fmt.Errorf("MEDIA_MAX_AVATAR_SIZE must be at least 1")
+	}
+	if c.Reviews.MaxPhotos < 0 {
+		return fmt.Errorf("REVIEW_MAX_PHOTOS must not be negative")
+	}
+	if c.Reviews.MaxPhotoSize < 1 {
+		return fmt.Errorf("REVIEW_MAX_PHOTO_SIZE must be at least 1")
+	}
+	if c.Reviews.ReportHideThreshold < 0 {
+		return fmt.Errorf("REPORT_HIDE_THRESHOLD must not be negative")
+	}
+
+	if c.Cache.ResponseTTL > 0 && c.Cache.ResponseMaxEntries < 1 {
+		return fmt.Errorf("RESPONSE_CACHE_MAX_ENTRIES must be at least 1")
+	}
+
+	if (c.Images.Key == "") != (c.Images.Salt == "") {
+		return fmt.Errorf("IMAGE_SIGNING_KEY and IMAGE_SIGNING_SALT must be set together")
+	}
+
+	if c.Retention.GuestCarts < 0 || c.Retention.WebhookPayloads < 0 || c.Retention.IPAddresses < 0 {
+		return fmt.Errorf("RETENTION_* periods must not be negative (use 0 to keep data forever)")
+	}
+
+	if c.Orders.ArchiveAfterMonths < 0 {
+		return fmt.Errorf("ORDER_ARCHIVE_AFTER_MONTHS must not be negative (use 0 to never archive)")
+	}
+	if c.Orders.ArchiveBatchSize < 1 {
+		return fmt.Errorf("ORDER_ARCHIVE_BATCH_SIZE must be at least 1")
+	}
+
+	if c.Diagnostics.Enabled {
+		_, port, err := net.SplitHostPort(c.Diagnostics.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid DIAGNOSTICS_ADDR: %s (must be host:port)", c.Diagnostics.Addr)
+		}
+		if port == c.Server.Port {
+			return fmt.Errorf("DIAGNOSTICS_ADDR must use a different port than PORT")
+		}
+		if (c.Diagnostics.TLSCertFile == "") != (c.Diagnostics.TLSKeyFile == "") {
+			return fmt.Errorf("DIAGNOSTICS_TLS_CERT_FILE and DIAGNOSTICS_TLS_KEY_FILE must be set together")
+		}
+		if c.Diagnostics.ClientCAFile != "" && c.Diagnostics.TLSCertFile == "" {
+			return fmt.Errorf("DIAGNOSTICS_CLIENT_CA_FILE requires DIAGNOSTICS_TLS_CERT_FILE and DIAGNOSTICS_TLS_KEY_FILE")
+		}
+	}
+
+	if c.Logging.SampleRate < 0 || c.Logging.SampleRate > 1 {
+		return fmt.Errorf("LOG_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	switch c.Startup.Checks {
+	case "fail", "degraded", "off":
+	default:
+		return fmt.Errorf("invalid STARTUP_CHECKS: %s (must be fail, degraded, or off)", c.Startup.Checks)
+	}
+	if c.Startup.CheckProviders && c.Startup.ProviderTimeout <= 0 {
+		return fmt.Errorf("STARTUP_PROVIDER_TIMEOUT must be positive")
+	}
+
+	switch c.Locks.Backend {
+	case "local":
+	case "postgres":
+		if c.Database.Driver != "postgres" {
+			return fmt.Errorf("LOCK_BACKEND=postgres requires DB_DRIVER=postgres (use local for a single replica)")
+		}
+	default:
+		return fmt.Errorf("invalid LOCK_BACKEND: %s (must be postgres or local)", c.Locks.Backend)
+	}
+
 	return nil
 }
 
+// defaultLockBackend uses advisory locks where the database supports them
+func defaultLockBackend(driver string) string {
+	if driver == "postgres" {
+		return "postgres"
+	}
+	return "local"
+}
+
 // ToGoAuthXConfig converts our config to goauthx.Config
 func (c *Config) ToGoAuthXConfig() *goauthx.Config {
 	var driver goauthx.DatabaseDriver
@@ -159,14 +945,14 @@ func (c *Config) ToGoAuthXConfig() *goauthx.Config {
 
 // Helper functions
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := LookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
 func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := LookupEnv(key); value != "" {
 		if intVal, err := strconv.Atoi(value); err == nil {
 			return intVal
 		}
@@ -174,11 +960,42 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := LookupEnv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
+	if value := LookupEnv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
 	}
 	return defaultValue
 }
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := LookupEnv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+func getListEnv(key string, defaultValue []string) []string {
+	if value := LookupEnv(key); value != "" {
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
+	}
+	return defaultValue
+}
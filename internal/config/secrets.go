@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/secrets"
+)
+
+// SecretsConfig holds the secrets managers environment variables can point into. Any
+// variable may be set to a reference such as vault://secret/api#jwt_secret instead of
+// its value; references are resolved when the configuration is loaded.
+type SecretsConfig struct {
+	CacheTTL time.Duration // how long fetched secrets are used before they are fetched again
+	Timeout  time.Duration // per request to a secrets manager
+
+	VaultAddr      string
+	VaultToken     string
+	VaultNamespace string
+
+	AWSRegion      string // AWS keys are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	GCPAccessToken string // empty uses the instance metadata server
+
+	// References maps each environment variable set to a secret reference to the reference
+	References map[string]string
+	// Resolver resolves the references and refreshes them when secrets are rotated; nil
+	// when no variable is a reference
+	Resolver *secrets.Resolver
+}
+
+// Keys returns the environment variables set to any of the references, sorted
+func (s SecretsConfig) Keys(refs []string) []string {
+	wanted := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = true
+	}
+	keys := []string{}
+	for key, ref := range s.References {
+		if wanted[ref] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolvedEnv holds the values of the environment variables set to secret references,
+// as resolved by the last Load; the getters read them in place of the references
+var resolvedEnv atomic.Pointer[map[string]string]
+
+// LookupEnv returns an environment variable, resolved when it is a secret reference, as
+// the configuration sees it
+func LookupEnv(key string) string {
+	if resolved := resolvedEnv.Load(); resolved != nil {
+		if value, ok := (*resolved)[key]; ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+// loadSecrets finds the environment variables set to secret references and resolves them
+func loadSecrets() (SecretsConfig, error) {
+	cfg := SecretsConfig{
+		CacheTTL:       getDurationEnv("SECRETS_CACHE_TTL", 5*time.Minute),
+		Timeout:        getDurationEnv("SECRETS_TIMEOUT", 5*time.Second),
+		VaultAddr:      getEnv("VAULT_ADDR", ""),
+		VaultToken:     getEnv("VAULT_TOKEN", ""),
+		VaultNamespace: getEnv("VAULT_NAMESPACE", ""),
+		AWSRegion:      getEnv("AWS_REGION", ""),
+		GCPAccessToken: getEnv("GCP_ACCESS_TOKEN", ""),
+		References:     map[string]string{},
+	}
+	if cfg.CacheTTL < 0 || cfg.Timeout <= 0 {
+		return cfg, fmt.Errorf("SECRETS_CACHE_TTL must not be negative and SECRETS_TIMEOUT must be positive")
+	}
+
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		_, ok, err := secrets.ParseReference(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if ok {
+			cfg.References[key] = value
+		}
+	}
+	if len(cfg.References) == 0 {
+		resolvedEnv.Store(nil)
+		return cfg, nil
+	}
+
+	cfg.Resolver = secrets.NewResolver(cfg.CacheTTL)
+	if cfg.VaultAddr != "" {
+		cfg.Resolver.WithProvider(secrets.SchemeVault, secrets.NewVaultProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultNamespace, cfg.Timeout))
+	}
+	if cfg.AWSRegion != "" && os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		cfg.Resolver.WithProvider(secrets.SchemeAWS, secrets.NewAWSProvider(cfg.AWSRegion, secrets.AWSCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, cfg.Timeout))
+	}
+	cfg.Resolver.WithProvider(secrets.SchemeGCP, secrets.NewGCPProvider(cfg.GCPAccessToken, cfg.Timeout))
+
+	resolved := make(map[string]string, len(cfg.References))
+	for key, ref := range cfg.References {
+		value, err := cfg.Resolver.Resolve(context.Background(), ref)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to resolve %s: %w", key, err)
+		}
+		resolved[key] = value
+	}
+	resolvedEnv.Store(&resolved)
+	return cfg, nil
+}
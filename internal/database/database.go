@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"log"
 	"time"
@@ -20,19 +22,43 @@ type DB struct {
 	*gorm.DB
 }
 
+// sqlDriverNames are the database/sql drivers the GORM dialectors register
+var sqlDriverNames = map[string]string{
+	"postgres":  "pgx",
+	"mysql":     "mysql",
+	"sqlserver": "sqlserver",
+}
+
 // Connect establishes a database connection
 func Connect(cfg *config.DatabaseConfig) (*DB, error) {
 	var dialector gorm.Dialector
 
-	switch cfg.Driver {
-	case "postgres":
-		dialector = postgres.Open(cfg.DSN)
-	case "mysql":
-		dialector = mysql.Open(cfg.DSN)
-	case "sqlserver":
-		dialector = sqlserver.Open(cfg.DSN)
-	default:
-		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	if cfg.DSNSource != nil {
+		// The DSN comes from a secrets manager: look it up for every new connection, so
+		// rotated credentials are used as pooled connections expire
+		conn, err := openRotating(cfg.Driver, cfg.DSN, cfg.DSNSource)
+		if err != nil {
+			return nil, err
+		}
+		switch cfg.Driver {
+		case "postgres":
+			dialector = postgres.New(postgres.Config{DSN: cfg.DSN, Conn: conn})
+		case "mysql":
+			dialector = mysql.New(mysql.Config{DSN: cfg.DSN, Conn: conn})
+		case "sqlserver":
+			dialector = sqlserver.New(sqlserver.Config{DSN: cfg.DSN, Conn: conn})
+		}
+	} else {
+		switch cfg.Driver {
+		case "postgres":
+			dialector = postgres.Open(cfg.DSN)
+		case "mysql":
+			dialector = mysql.Open(cfg.DSN)
+		case "sqlserver":
+			dialector = sqlserver.Open(cfg.DSN)
+		default:
+			return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+		}
 	}
 
 	// Configure GORM
@@ -71,6 +97,48 @@ func Connect(cfg *config.DatabaseConfig) (*DB, error) {
 	return &DB{db}, nil
 }
 
+// openRotating opens a pool whose connections are made with the DSN dsnSource returns at
+// the time; dsn is the one resolved at startup
+func openRotating(driverName, dsn string, dsnSource func(ctx context.Context) (string, error)) (*sql.DB, error) {
+	name, ok := sqlDriverNames[driverName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver: %s", driverName)
+	}
+	base, err := sql.Open(name, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	drv := base.Driver()
+	_ = base.Close()
+
+	return sql.OpenDB(&rotatingConnector{driver: drv, dsnSource: dsnSource}), nil
+}
+
+// rotatingConnector makes each connection with the current DSN
+type rotatingConnector struct {
+	driver    driver.Driver
+	dsnSource func(ctx context.Context) (string, error)
+}
+
+func (c *rotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := c.dsnSource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DB_DSN: %w", err)
+	}
+	if dc, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return connector.Connect(ctx)
+	}
+	return c.driver.Open(dsn)
+}
+
+func (c *rotatingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	sqlDB, err := db.DB.DB()
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// QueryLatency keeps the durations of the most recent statements so their percentiles can
+// be reported; older statements drop out as new ones run
+type QueryLatency struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+// LatencyPercentiles summarizes the statements a QueryLatency holds
+type LatencyPercentiles struct {
+	Samples int
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// NewQueryLatency creates a QueryLatency keeping the last window statements
+func NewQueryLatency(window int) *QueryLatency {
+	if window <= 0 {
+		window = 1000
+	}
+	return &QueryLatency{samples: make([]time.Duration, window)}
+}
+
+// Observe records one statement's duration
+func (l *QueryLatency) Observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.samples[l.next] = d
+	l.next++
+	if l.next == len(l.samples) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// Percentiles returns the percentiles of the statements held, or zeroes before any ran
+func (l *QueryLatency) Percentiles() LatencyPercentiles {
+	l.mu.Lock()
+	n := l.next
+	if l.full {
+		n = len(l.samples)
+	}
+	sorted := append([]time.Duration(nil), l.samples[:n]...)
+	l.mu.Unlock()
+
+	if n == 0 {
+		return LatencyPercentiles{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(n-1)+0.5)]
+	}
+	return LatencyPercentiles{
+		Samples: n,
+		P50:     at(0.50),
+		P95:     at(0.95),
+		P99:     at(0.99),
+		Max:     sorted[n-1],
+	}
+}
+
+// TrackLatency records the duration of every statement to latency. Like RecordQueries it
+// wraps the configured logger, which GORM reports each statement to.
+func (db *DB) TrackLatency(latency *QueryLatency) {
+	db.Logger = &latencyRecorder{Interface: db.Logger, latency: latency}
+}
+
+// latencyRecorder is a GORM logger that feeds statement durations to a QueryLatency
+type latencyRecorder struct {
+	logger.Interface
+	latency *QueryLatency
+}
+
+// LogMode implements logger.Interface, keeping the recorder in place
+func (r *latencyRecorder) LogMode(level logger.LogLevel) logger.Interface {
+	return &latencyRecorder{Interface: r.Interface.LogMode(level), latency: r.latency}
+}
+
+// Trace implements logger.Interface
+func (r *latencyRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	r.latency.Observe(time.Since(begin))
+	r.Interface.Trace(ctx, begin, fc, err)
+}
+
+// ParamsFilter passes through to the wrapped logger, which GORM would otherwise not see
+func (r *latencyRecorder) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if filter, ok := r.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
@@ -0,0 +1,1696 @@
+package database
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce/migrations"
+)
+
+// localMigrations are additive migrations owned by this API (must stay backwards compatible).
+// Versions start at 900 to stay clear of the gocommerce example migrations.
+var localMigrations = []migrations.Migration{
+	{
+		Version: "900",
+		Name:    "add_cart_item_attributes",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE IF EXISTS cart_items
+				ADD COLUMN IF NOT EXISTS attributes JSONB;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE IF EXISTS cart_items
+				DROP COLUMN IF EXISTS attributes;
+			`)
+		},
+	},
+	{
+		Version: "901",
+		Name:    "create_delivery_slots",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS delivery_slots (
+					id VARCHAR(255) PRIMARY KEY,
+					region VARCHAR(20) NOT NULL,
+					starts_at TIMESTAMP NOT NULL,
+					ends_at TIMESTAMP NOT NULL,
+					capacity INT NOT NULL,
+					reserved INT NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT true,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					CHECK (reserved <= capacity)
+				);
+				CREATE INDEX IF NOT EXISTS idx_delivery_slots_region_starts_at ON delivery_slots(region, starts_at);
+
+				CREATE TABLE IF NOT EXISTS order_delivery_slots (
+					order_id VARCHAR(255) PRIMARY KEY,
+					slot_id VARCHAR(255) NOT NULL REFERENCES delivery_slots(id),
+					reserved_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_delivery_slots_slot_id ON order_delivery_slots(slot_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS order_delivery_slots;
+				DROP TABLE IF EXISTS delivery_slots;
+			`)
+		},
+	},
+	{
+		Version: "902",
+		Name:    "create_shipping_zones",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS shipping_zones (
+					id VARCHAR(255) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL,
+					country VARCHAR(2) NOT NULL,
+					state VARCHAR(100),
+					postal_code_patterns JSONB,
+					priority INT NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT true,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_shipping_zones_country ON shipping_zones(country);
+
+				CREATE TABLE IF NOT EXISTS shipping_zone_rates (
+					id VARCHAR(255) PRIMARY KEY,
+					zone_id VARCHAR(255) NOT NULL REFERENCES shipping_zones(id) ON DELETE CASCADE,
+					method_id VARCHAR(100) NOT NULL,
+					method_name VARCHAR(255) NOT NULL,
+					carrier VARCHAR(100),
+					service_level VARCHAR(100),
+					cost_amount BIGINT NOT NULL,
+					cost_currency VARCHAR(3) NOT NULL,
+					estimated_days_min INT NOT NULL DEFAULT 0,
+					estimated_days_max INT NOT NULL DEFAULT 0,
+					UNIQUE (zone_id, method_id)
+				);
+				CREATE INDEX IF NOT EXISTS idx_shipping_zone_rates_zone_id ON shipping_zone_rates(zone_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS shipping_zone_rates;
+				DROP TABLE IF EXISTS shipping_zones;
+			`)
+		},
+	},
+	{
+		Version: "903",
+		Name:    "create_order_payments",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_payments (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					tender_type VARCHAR(50) NOT NULL,
+					payment_method_id VARCHAR(255) NOT NULL,
+					amount BIGINT NOT NULL,
+					captured_amount BIGINT NOT NULL DEFAULT 0,
+					refunded_amount BIGINT NOT NULL DEFAULT 0,
+					currency VARCHAR(3) NOT NULL,
+					status VARCHAR(50) NOT NULL,
+					gateway_reference VARCHAR(255),
+					failure_reason TEXT,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_payments_order_id ON order_payments(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_payments;`)
+		},
+	},
+	{
+		Version: "904",
+		Name:    "create_order_payment_retries",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_payment_retries (
+					order_id VARCHAR(255) PRIMARY KEY,
+					state VARCHAR(50) NOT NULL,
+					failures INT NOT NULL DEFAULT 0,
+					last_failure_reason TEXT,
+					first_failed_at TIMESTAMP NOT NULL,
+					last_failed_at TIMESTAMP NOT NULL,
+					expires_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_payment_retries_state_expires_at ON order_payment_retries(state, expires_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_payment_retries;`)
+		},
+	},
+	{
+		Version: "905",
+		Name:    "create_disputes",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE INDEX IF NOT EXISTS idx_order_payments_gateway_reference ON order_payments(gateway_reference);
+
+				CREATE TABLE IF NOT EXISTS disputes (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					tender_id VARCHAR(255),
+					gateway_dispute_id VARCHAR(255) NOT NULL UNIQUE,
+					gateway_reference VARCHAR(255),
+					amount BIGINT NOT NULL DEFAULT 0,
+					currency VARCHAR(3),
+					reason VARCHAR(255),
+					status VARCHAR(50) NOT NULL,
+					evidence_status VARCHAR(50) NOT NULL DEFAULT 'not_submitted',
+					evidence_notes TEXT,
+					evidence_due_by TIMESTAMP,
+					evidence_submitted_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_disputes_order_id ON disputes(order_id);
+				CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes(status, evidence_status);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS disputes;
+				DROP INDEX IF EXISTS idx_order_payments_gateway_reference;
+			`)
+		},
+	},
+	{
+		Version: "906",
+		Name:    "create_order_refunds",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_refunds (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					amount BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					reason VARCHAR(50) NOT NULL,
+					note TEXT,
+					lines JSONB,
+					allocations JSONB,
+					created_by VARCHAR(255) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_refunds_order_id ON order_refunds(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_refunds;`)
+		},
+	},
+	{
+		Version: "907",
+		Name:    "create_store_credit",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS store_credit_balances (
+					user_id VARCHAR(255) NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (user_id, currency)
+				);
+
+				CREATE TABLE IF NOT EXISTS store_credit_transactions (
+					id VARCHAR(255) PRIMARY KEY,
+					user_id VARCHAR(255) NOT NULL,
+					type VARCHAR(20) NOT NULL,
+					amount BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					balance_after BIGINT NOT NULL,
+					reason VARCHAR(50),
+					note TEXT,
+					order_id VARCHAR(255),
+					created_by VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_store_credit_transactions_user_id ON store_credit_transactions(user_id, created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS store_credit_transactions;
+				DROP TABLE IF EXISTS store_credit_balances;
+			`)
+		},
+	},
+	{
+		Version: "908",
+		Name:    "create_content_pages",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS content_pages (
+					id VARCHAR(255) PRIMARY KEY,
+					slug VARCHAR(255) NOT NULL UNIQUE,
+					title VARCHAR(255) NOT NULL,
+					body TEXT,
+					format VARCHAR(20) NOT NULL DEFAULT 'markdown',
+					meta_description VARCHAR(500),
+					status VARCHAR(20) NOT NULL DEFAULT 'draft',
+					published_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_content_pages_status ON content_pages(status);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS content_pages;`)
+		},
+	},
+	{
+		Version: "909",
+		Name:    "create_placements",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS placements (
+					id VARCHAR(255) PRIMARY KEY,
+					slot VARCHAR(100) NOT NULL,
+					title VARCHAR(255) NOT NULL,
+					subtitle VARCHAR(500),
+					image_url VARCHAR(1000) NOT NULL,
+					image_alt VARCHAR(255),
+					target_url VARCHAR(1000) NOT NULL,
+					position INTEGER NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT true,
+					starts_at TIMESTAMP,
+					ends_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_placements_slot ON placements(slot, position);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS placements;`)
+		},
+	},
+	{
+		Version: "910",
+		Name:    "create_api_keys",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS api_keys (
+					id VARCHAR(255) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL,
+					prefix VARCHAR(20) NOT NULL,
+					key_hash VARCHAR(64) NOT NULL UNIQUE,
+					scopes VARCHAR(500) NOT NULL,
+					created_by VARCHAR(255),
+					last_used_at TIMESTAMP,
+					revoked_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS api_keys;`)
+		},
+	},
+	{
+		Version: "911",
+		Name:    "create_order_shipments",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_shipments (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					carrier VARCHAR(100) NOT NULL,
+					tracking_number VARCHAR(255) NOT NULL,
+					tracking_url VARCHAR(1000),
+					shipped_at TIMESTAMP NOT NULL,
+					api_key_id VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_shipments_order_id ON order_shipments(order_id);
+				CREATE INDEX IF NOT EXISTS idx_orders_fulfillment_feed ON orders(updated_at, id)
+					WHERE status IN ('paid', 'processing');
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP INDEX IF EXISTS idx_orders_fulfillment_feed;
+				DROP TABLE IF EXISTS order_shipments;
+			`)
+		},
+	},
+	{
+		Version: "912",
+		Name:    "create_webhook_events",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS webhook_events (
+					id VARCHAR(255) PRIMARY KEY,
+					provider VARCHAR(100) NOT NULL,
+					external_id VARCHAR(255),
+					event_type VARCHAR(255),
+					payload JSONB NOT NULL,
+					status VARCHAR(50) NOT NULL,
+					attempts INT NOT NULL DEFAULT 0,
+					last_error TEXT,
+					received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					processed_at TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_events_external_id ON webhook_events(provider, external_id)
+					WHERE external_id IS NOT NULL AND external_id <> '';
+				CREATE INDEX IF NOT EXISTS idx_webhook_events_status ON webhook_events(status, received_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS webhook_events;`)
+		},
+	},
+	{
+		Version: "913",
+		Name:    "align_orders_and_promotions_with_api_models",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// The example orders and promotions tables use column names the API models never
+			// had; add the model columns and relax the legacy ones the API does not write
+			return exec.Exec(ctx, `
+				ALTER TABLE IF EXISTS orders
+					ADD COLUMN IF NOT EXISTS items JSONB NOT NULL DEFAULT '[]',
+					ADD COLUMN IF NOT EXISTS subtotal BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS discount_total BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS tax_total BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS shipping_total BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS total BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+					ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP,
+					ADD COLUMN IF NOT EXISTS cancel_reason TEXT,
+					ALTER COLUMN subtotal_amount DROP NOT NULL,
+					ALTER COLUMN subtotal_currency DROP NOT NULL,
+					ALTER COLUMN discount_amount DROP NOT NULL,
+					ALTER COLUMN tax_amount DROP NOT NULL,
+					ALTER COLUMN shipping_amount DROP NOT NULL,
+					ALTER COLUMN total_amount DROP NOT NULL;
+				ALTER TABLE IF EXISTS promotions
+					ADD COLUMN IF NOT EXISTS type VARCHAR(20),
+					ADD COLUMN IF NOT EXISTS discount_percentage DECIMAL(5,2),
+					ADD COLUMN IF NOT EXISTS discount_amount BIGINT,
+					ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+					ADD COLUMN IF NOT EXISTS start_date TIMESTAMP,
+					ADD COLUMN IF NOT EXISTS end_date TIMESTAMP,
+					ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT true,
+					ADD COLUMN IF NOT EXISTS product_ids JSONB,
+					ADD COLUMN IF NOT EXISTS category_ids JSONB,
+					ALTER COLUMN discount_type DROP NOT NULL,
+					ALTER COLUMN value DROP NOT NULL;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			// Legacy NOT NULL constraints are left relaxed: rows written since may not satisfy them
+			return exec.Exec(ctx, `
+				ALTER TABLE IF EXISTS promotions
+					DROP COLUMN IF EXISTS category_ids,
+					DROP COLUMN IF EXISTS product_ids,
+					DROP COLUMN IF EXISTS active,
+					DROP COLUMN IF EXISTS end_date,
+					DROP COLUMN IF EXISTS start_date,
+					DROP COLUMN IF EXISTS currency,
+					DROP COLUMN IF EXISTS discount_amount,
+					DROP COLUMN IF EXISTS discount_percentage,
+					DROP COLUMN IF EXISTS type;
+				ALTER TABLE IF EXISTS orders
+					DROP COLUMN IF EXISTS cancel_reason,
+					DROP COLUMN IF EXISTS cancelled_at,
+					DROP COLUMN IF EXISTS currency,
+					DROP COLUMN IF EXISTS total,
+					DROP COLUMN IF EXISTS shipping_total,
+					DROP COLUMN IF EXISTS tax_total,
+					DROP COLUMN IF EXISTS discount_total,
+					DROP COLUMN IF EXISTS subtotal,
+					DROP COLUMN IF EXISTS items;
+			`)
+		},
+	},
+	{
+		Version: "914",
+		Name:    "create_catalog_versions",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS catalog_versions (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					version INT NOT NULL,
+					entity_type VARCHAR(20) NOT NULL,
+					entity_id VARCHAR(255) NOT NULL,
+					action VARCHAR(20) NOT NULL,
+					changed_by VARCHAR(255),
+					changes JSONB NOT NULL,
+					before_state JSONB,
+					after_state JSONB,
+					reverted_to INT,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_catalog_versions_product_version ON catalog_versions(product_id, version);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS catalog_versions;`)
+		},
+	},
+	{
+		Version: "915",
+		Name:    "create_order_events",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_events (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					type VARCHAR(50) NOT NULL,
+					message VARCHAR(500) NOT NULL,
+					from_status VARCHAR(50),
+					status VARCHAR(50),
+					amount BIGINT,
+					currency VARCHAR(3),
+					details JSONB,
+					internal BOOLEAN NOT NULL DEFAULT false,
+					created_by VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_events_order_id ON order_events(order_id, created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_events;`)
+		},
+	},
+	{
+		Version: "916",
+		Name:    "create_business_calendar",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS business_calendars (
+					id VARCHAR(50) PRIMARY KEY,
+					timezone VARCHAR(64) NOT NULL,
+					working_days VARCHAR(20) NOT NULL,
+					cutoff_time VARCHAR(5) NOT NULL,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS calendar_holidays (
+					date VARCHAR(10) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS calendar_holidays;
+				DROP TABLE IF EXISTS business_calendars;
+			`)
+		},
+	},
+	{
+		Version: "917",
+		Name:    "create_purchase_limits",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS purchase_limits (
+					product_id VARCHAR(255) PRIMARY KEY,
+					max_per_order INT NOT NULL DEFAULT 0,
+					max_per_customer INT NOT NULL DEFAULT 0,
+					period_days INT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS purchase_limits;`)
+		},
+	},
+	{
+		Version: "918",
+		Name:    "create_waiting_room_tickets",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS waiting_room_tickets (
+					product_id VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					token VARCHAR(64) NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					admitted_at TIMESTAMP,
+					expires_at TIMESTAMP,
+					PRIMARY KEY (product_id, user_id)
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_waiting_room_tickets_token ON waiting_room_tickets(token);
+				CREATE INDEX IF NOT EXISTS idx_waiting_room_tickets_queue ON waiting_room_tickets(product_id, status, joined_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS waiting_room_tickets;`)
+		},
+	},
+	{
+		Version: "919",
+		Name:    "create_login_security",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS login_lockouts (
+					email VARCHAR(255) PRIMARY KEY,
+					failed_attempts INT NOT NULL DEFAULT 0,
+					lockouts INT NOT NULL DEFAULT 0,
+					locked_until TIMESTAMP,
+					last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS login_devices (
+					user_id VARCHAR(255) NOT NULL,
+					fingerprint VARCHAR(64) NOT NULL,
+					user_agent VARCHAR(512),
+					ip_address VARCHAR(45),
+					first_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (user_id, fingerprint)
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS login_devices;
+				DROP TABLE IF EXISTS login_lockouts;
+			`)
+		},
+	},
+	{
+		Version: "920",
+		Name:    "create_consent_events",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS consent_events (
+					id VARCHAR(255) PRIMARY KEY,
+					user_id VARCHAR(255) NOT NULL,
+					purpose VARCHAR(50) NOT NULL,
+					granted BOOLEAN NOT NULL,
+					policy_version VARCHAR(50) NOT NULL,
+					source VARCHAR(50),
+					recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_consent_events_user ON consent_events(user_id, purpose, recorded_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS consent_events;`)
+		},
+	},
+	{
+		Version: "921",
+		Name:    "create_product_tags_and_collections",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS product_tags (
+					product_id VARCHAR(255) NOT NULL,
+					tag VARCHAR(100) NOT NULL,
+					PRIMARY KEY (product_id, tag)
+				);
+				CREATE INDEX IF NOT EXISTS idx_product_tags_tag ON product_tags(tag);
+				CREATE TABLE IF NOT EXISTS collections (
+					id VARCHAR(255) PRIMARY KEY,
+					slug VARCHAR(255) NOT NULL,
+					title VARCHAR(255) NOT NULL,
+					description TEXT,
+					type VARCHAR(20) NOT NULL,
+					rule TEXT,
+					status VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_slug ON collections(slug);
+				CREATE TABLE IF NOT EXISTS collection_products (
+					collection_id VARCHAR(255) NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+					product_id VARCHAR(255) NOT NULL,
+					position INT NOT NULL DEFAULT 0,
+					PRIMARY KEY (collection_id, product_id)
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS collection_products;
+				DROP TABLE IF EXISTS collections;
+				DROP TABLE IF EXISTS product_tags;
+			`)
+		},
+	},
+	{
+		Version: "922",
+		Name:    "create_inventory_snapshots",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS inventory_snapshots (
+					sku VARCHAR(100) NOT NULL,
+					snapshot_date VARCHAR(10) NOT NULL,
+					on_hand INT NOT NULL,
+					reserved INT NOT NULL,
+					available INT NOT NULL,
+					taken_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (sku, snapshot_date)
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS inventory_snapshots;`)
+		},
+	},
+	{
+		Version: "923",
+		Name:    "add_variants_barcode",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// GTIN padded to 14 digits; NULL for variants without a barcode
+			return exec.Exec(ctx, `ALTER TABLE variants ADD COLUMN IF NOT EXISTS barcode VARCHAR(14);`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `ALTER TABLE variants DROP COLUMN IF EXISTS barcode;`)
+		},
+	},
+	CreateUniqueIndexConcurrently("924", "idx_variants_barcode", "variants", "barcode"),
+	{
+		Version: "925",
+		Name:    "create_pos_sales",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS pos_sales (
+					order_id VARCHAR(255) PRIMARY KEY,
+					register_id VARCHAR(100) NOT NULL,
+					terminal_id VARCHAR(100),
+					api_key_id VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_pos_sales_register ON pos_sales(register_id, created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS pos_sales;`)
+		},
+	},
+	{
+		Version: "926",
+		Name:    "add_cart_and_order_metadata",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Client metadata; order item metadata is keyed by order item ID
+			return exec.Exec(ctx, `
+				ALTER TABLE carts ADD COLUMN IF NOT EXISTS metadata JSONB;
+				ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS metadata JSONB;
+				ALTER TABLE orders ADD COLUMN IF NOT EXISTS metadata JSONB;
+				ALTER TABLE orders ADD COLUMN IF NOT EXISTS item_metadata JSONB;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE orders DROP COLUMN IF EXISTS item_metadata;
+				ALTER TABLE orders DROP COLUMN IF EXISTS metadata;
+				ALTER TABLE cart_items DROP COLUMN IF EXISTS metadata;
+				ALTER TABLE carts DROP COLUMN IF EXISTS metadata;
+			`)
+		},
+	},
+	{
+		Version: "927",
+		Name:    "create_order_attributions",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_attributions (
+					order_id VARCHAR(255) PRIMARY KEY,
+					channel VARCHAR(20) NOT NULL,
+					utm_source VARCHAR(255),
+					utm_medium VARCHAR(255),
+					utm_campaign VARCHAR(255),
+					utm_term VARCHAR(255),
+					utm_content VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_attributions_created ON order_attributions(created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_attributions;`)
+		},
+	},
+	{
+		Version: "928",
+		Name:    "create_recently_viewed_products",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS recently_viewed_products (
+					user_id VARCHAR(255) NOT NULL,
+					product_id VARCHAR(255) NOT NULL,
+					viewed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (user_id, product_id)
+				);
+				CREATE INDEX IF NOT EXISTS idx_recently_viewed_products_user ON recently_viewed_products(user_id, viewed_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS recently_viewed_products;`)
+		},
+	},
+	{
+		Version: "929",
+		Name:    "create_customers",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS customers (
+					user_id VARCHAR(255) PRIMARY KEY,
+					first_name VARCHAR(100),
+					last_name VARCHAR(100),
+					phone VARCHAR(20),
+					birthday VARCHAR(10),
+					avatar_key VARCHAR(500),
+					avatar_url VARCHAR(1000),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS customers;`)
+		},
+	},
+	{
+		Version: "930",
+		Name:    "create_companies",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS companies (
+					id VARCHAR(255) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL,
+					approval_threshold_amount BIGINT,
+					approval_threshold_currency VARCHAR(3),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS company_members (
+					user_id VARCHAR(255) PRIMARY KEY,
+					company_id VARCHAR(255) NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+					role VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_company_members_company ON company_members(company_id);
+				CREATE TABLE IF NOT EXISTS company_addresses (
+					id VARCHAR(255) PRIMARY KEY,
+					company_id VARCHAR(255) NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+					label VARCHAR(100),
+					first_name VARCHAR(255) NOT NULL,
+					last_name VARCHAR(255) NOT NULL,
+					company VARCHAR(255),
+					address1 VARCHAR(255) NOT NULL,
+					address2 VARCHAR(255),
+					city VARCHAR(255) NOT NULL,
+					state VARCHAR(255) NOT NULL,
+					postal_code VARCHAR(20) NOT NULL,
+					country VARCHAR(100) NOT NULL,
+					phone VARCHAR(50),
+					created_by VARCHAR(255) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_company_addresses_company ON company_addresses(company_id);
+				CREATE TABLE IF NOT EXISTS company_orders (
+					order_id VARCHAR(255) PRIMARY KEY,
+					company_id VARCHAR(255) NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+					placed_by VARCHAR(255) NOT NULL,
+					total_amount BIGINT NOT NULL,
+					total_currency VARCHAR(3) NOT NULL,
+					approval_status VARCHAR(20) NOT NULL,
+					decided_by VARCHAR(255),
+					decided_at TIMESTAMP,
+					reason TEXT,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_company_orders_company ON company_orders(company_id, approval_status, created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS company_orders;
+				DROP TABLE IF EXISTS company_addresses;
+				DROP TABLE IF EXISTS company_members;
+				DROP TABLE IF EXISTS companies;
+			`)
+		},
+	},
+	{
+		Version: "931",
+		Name:    "create_quotes",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS quotes (
+					id VARCHAR(255) PRIMARY KEY,
+					user_id VARCHAR(255) NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					subtotal_amount BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					note TEXT,
+					sales_note TEXT,
+					valid_until TIMESTAMP,
+					sent_by VARCHAR(255),
+					sent_at TIMESTAMP,
+					order_id VARCHAR(255),
+					accepted_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_quotes_user ON quotes(user_id, created_at);
+				CREATE INDEX IF NOT EXISTS idx_quotes_status ON quotes(status, created_at);
+				CREATE TABLE IF NOT EXISTS quote_items (
+					id VARCHAR(255) PRIMARY KEY,
+					quote_id VARCHAR(255) NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+					position INTEGER NOT NULL,
+					product_id VARCHAR(255) NOT NULL,
+					variant_id VARCHAR(255),
+					sku VARCHAR(255) NOT NULL,
+					name VARCHAR(255) NOT NULL,
+					quantity INTEGER NOT NULL,
+					list_price_amount BIGINT NOT NULL,
+					quoted_price_amount BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					attributes JSONB
+				);
+				CREATE INDEX IF NOT EXISTS idx_quote_items_quote ON quote_items(quote_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS quote_items;
+				DROP TABLE IF EXISTS quotes;
+			`)
+		},
+	},
+	{
+		Version: "932",
+		Name:    "create_invoices",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE companies ADD COLUMN IF NOT EXISTS net_terms_days INTEGER NOT NULL DEFAULT 0;
+				CREATE TABLE IF NOT EXISTS invoices (
+					id VARCHAR(255) PRIMARY KEY,
+					number VARCHAR(50) NOT NULL UNIQUE,
+					order_id VARCHAR(255) NOT NULL UNIQUE,
+					company_id VARCHAR(255) NOT NULL REFERENCES companies(id),
+					placed_by VARCHAR(255) NOT NULL,
+					amount BIGINT NOT NULL,
+					amount_paid BIGINT NOT NULL DEFAULT 0,
+					currency VARCHAR(3) NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					net_terms_days INTEGER NOT NULL,
+					issued_at TIMESTAMP NOT NULL,
+					due_at TIMESTAMP NOT NULL,
+					paid_at TIMESTAMP,
+					voided_at TIMESTAMP,
+					void_reason TEXT,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_invoices_company ON invoices(company_id, created_at);
+				CREATE INDEX IF NOT EXISTS idx_invoices_status_due ON invoices(status, due_at);
+				CREATE TABLE IF NOT EXISTS invoice_payments (
+					id VARCHAR(255) PRIMARY KEY,
+					invoice_id VARCHAR(255) NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+					amount BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					reference VARCHAR(255),
+					received_at TIMESTAMP NOT NULL,
+					recorded_by VARCHAR(255) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_invoice_payments_invoice ON invoice_payments(invoice_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS invoice_payments;
+				DROP TABLE IF EXISTS invoices;
+				ALTER TABLE companies DROP COLUMN IF EXISTS net_terms_days;
+			`)
+		},
+	},
+	{
+		Version: "933",
+		Name:    "create_checkout_rules",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS checkout_rules (
+					id VARCHAR(255) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL,
+					type VARCHAR(30) NOT NULL,
+					product_id VARCHAR(255),
+					min_order_amount BIGINT,
+					min_order_currency VARCHAR(3),
+					countries JSONB,
+					multiple INTEGER NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT TRUE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_checkout_rules_active ON checkout_rules(is_active);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS checkout_rules;`)
+		},
+	},
+	{
+		Version: "934",
+		Name:    "create_shipping_restrictions",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE shipping_zone_rates ADD COLUMN IF NOT EXISTS air BOOLEAN NOT NULL DEFAULT FALSE;
+				CREATE TABLE IF NOT EXISTS shipping_restrictions (
+					product_id VARCHAR(255) PRIMARY KEY,
+					no_air BOOLEAN NOT NULL DEFAULT FALSE,
+					carriers JSONB,
+					no_po_box BOOLEAN NOT NULL DEFAULT FALSE,
+					min_age INT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS shipping_restrictions;
+				ALTER TABLE shipping_zone_rates DROP COLUMN IF EXISTS air;
+			`)
+		},
+	},
+	{
+		Version: "935",
+		Name:    "add_variants_unit",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Contents of the variant in unit_measure, for unit prices; NULL for none
+			return exec.Exec(ctx, `
+				ALTER TABLE variants ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(12,3);
+				ALTER TABLE variants ADD COLUMN IF NOT EXISTS unit_measure VARCHAR(10);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE variants DROP COLUMN IF EXISTS unit_measure;
+				ALTER TABLE variants DROP COLUMN IF EXISTS unit_quantity;
+			`)
+		},
+	},
+	{
+		Version: "936",
+		Name:    "create_pricing_anomalies",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Amounts are in currency; old_price_amount is only kept for a price change in
+			// the same currency
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS pricing_anomalies (
+					id VARCHAR(255) PRIMARY KEY,
+					kind VARCHAR(20) NOT NULL,
+					reason VARCHAR(30) NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					product_id VARCHAR(255),
+					variant_id VARCHAR(255),
+					order_id VARCHAR(255),
+					currency VARCHAR(3),
+					old_price_amount BIGINT,
+					new_price_amount BIGINT,
+					order_total_amount BIGINT,
+					item_value_amount BIGINT,
+					message TEXT NOT NULL,
+					change JSONB,
+					reviewed_by VARCHAR(255),
+					reviewed_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_pricing_anomalies_status ON pricing_anomalies(status, created_at);
+				CREATE INDEX IF NOT EXISTS idx_pricing_anomalies_order ON pricing_anomalies(order_id) WHERE order_id IS NOT NULL;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS pricing_anomalies;`)
+		},
+	},
+	{
+		Version: "937",
+		Name:    "create_category_product_counts",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Seeded from the products table; kept up to date by the API and repaired by the
+			// category-counts task
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS category_product_counts (
+					category_id VARCHAR(255) PRIMARY KEY,
+					product_count BIGINT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				INSERT INTO category_product_counts (category_id, product_count, updated_at)
+				SELECT category_id, COUNT(*), CURRENT_TIMESTAMP FROM products
+				WHERE status = 'active' AND category_id IS NOT NULL AND category_id <> ''
+				GROUP BY category_id
+				ON CONFLICT (category_id) DO NOTHING;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS category_product_counts;`)
+		},
+	},
+	{
+		Version: "938",
+		Name:    "enable_pg_trgm",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Trigram matching for search suggestions; pg_trgm ships with PostgreSQL and can
+			// be enabled by the database owner on managed services
+			return exec.Exec(ctx, `CREATE EXTENSION IF NOT EXISTS pg_trgm;`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP EXTENSION IF EXISTS pg_trgm;`)
+		},
+	},
+	// Name prefix matching for GET /catalog/search/suggest
+	CreateTrigramIndexConcurrently("939", "idx_products_name_trgm", "products", "name"),
+	CreateTrigramIndexConcurrently("940", "idx_categories_name_trgm", "categories", "name"),
+	CreateTrigramIndexConcurrently("941", "idx_brands_name_trgm", "brands", "name"),
+	{
+		Version: "942",
+		Name:    "create_search_terms",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Vocabulary for correcting zero-result searches, seeded from the catalog's names
+			// and rebuilt by the search-terms task
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS search_terms (
+					term VARCHAR(100) PRIMARY KEY
+				);
+				CREATE INDEX IF NOT EXISTS idx_search_terms_trgm ON search_terms USING gin (term gin_trgm_ops);
+				INSERT INTO search_terms (term)
+				SELECT DISTINCT word FROM (
+					SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') AS word FROM products WHERE status = 'active'
+					UNION ALL
+					SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') FROM categories WHERE is_active
+					UNION ALL
+					SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') FROM brands WHERE is_active
+				) words
+				WHERE length(word) BETWEEN 3 AND 100
+				ON CONFLICT (term) DO NOTHING;
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS search_terms;`)
+		},
+	},
+	{
+		Version: "943",
+		Name:    "create_search_rules",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Merchandiser-managed synonyms and pinned/boosted products for search queries
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS synonym_sets (
+					id VARCHAR(255) PRIMARY KEY,
+					terms JSONB NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS search_rules (
+					id VARCHAR(255) PRIMARY KEY,
+					query VARCHAR(255) NOT NULL,
+					pinned_product_ids JSONB,
+					boosted_product_ids JSONB,
+					is_active BOOLEAN NOT NULL DEFAULT TRUE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_search_rules_query ON search_rules(query);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS search_rules;
+				DROP TABLE IF EXISTS synonym_sets;
+			`)
+		},
+	},
+	{
+		Version: "944",
+		Name:    "create_product_media",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Ordered product galleries of images, videos and 3D models
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS product_media (
+					product_id VARCHAR(255) NOT NULL,
+					position INTEGER NOT NULL,
+					type VARCHAR(20) NOT NULL,
+					url TEXT NOT NULL,
+					poster_url TEXT,
+					alt VARCHAR(500),
+					mime_type VARCHAR(100),
+					PRIMARY KEY (product_id, position)
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS product_media;`)
+		},
+	},
+	{
+		Version: "945",
+		Name:    "add_variants_archived",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Archived variants stay on their product's history but can't be bought
+			return exec.Exec(ctx, `ALTER TABLE variants ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `ALTER TABLE variants DROP COLUMN IF EXISTS archived;`)
+		},
+	},
+	{
+		Version: "946",
+		Name:    "create_dead_letters",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Failed background jobs with the payload needed to replay them
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS dead_letters (
+					id VARCHAR(255) PRIMARY KEY,
+					kind VARCHAR(100) NOT NULL,
+					name VARCHAR(255) NOT NULL,
+					payload JSONB NOT NULL,
+					status VARCHAR(50) NOT NULL,
+					attempts INT NOT NULL DEFAULT 1,
+					last_error TEXT,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					replayed_at TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_dead_letters_status ON dead_letters(status, failed_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS dead_letters;`)
+		},
+	},
+	{
+		Version: "947",
+		Name:    "create_config_changes",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Audit trail of runtime settings changed by reloading the configuration
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS config_changes (
+					id VARCHAR(255) PRIMARY KEY,
+					setting VARCHAR(100) NOT NULL,
+					old_value TEXT NOT NULL,
+					new_value TEXT NOT NULL,
+					source VARCHAR(20) NOT NULL,
+					actor_id VARCHAR(255),
+					changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_config_changes_setting ON config_changes(setting, changed_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS config_changes;`)
+		},
+	},
+	{
+		Version: "948",
+		Name:    "create_quota_counters",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Daily and monthly request counts of API keys and users, and the quota tier a
+			// key can be put on, e.g. for partner integrations
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS quota_counters (
+					subject VARCHAR(255) NOT NULL,
+					period VARCHAR(10) NOT NULL,
+					period_start TIMESTAMP NOT NULL,
+					tier VARCHAR(50) NOT NULL,
+					requests BIGINT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (subject, period, period_start)
+				);
+				CREATE INDEX IF NOT EXISTS idx_quota_counters_period ON quota_counters(period, period_start, requests);
+				ALTER TABLE IF EXISTS api_keys
+					ADD COLUMN IF NOT EXISTS quota_tier VARCHAR(50) NOT NULL DEFAULT '';
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				ALTER TABLE IF EXISTS api_keys DROP COLUMN IF EXISTS quota_tier;
+				DROP TABLE IF EXISTS quota_counters;
+			`)
+		},
+	},
+	{
+		Version: "949",
+		Name:    "create_funnel_events",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Carts and orders reaching each checkout funnel stage, and alerts raised when a
+			// step converts far below its baseline
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS funnel_events (
+					stage VARCHAR(30) NOT NULL,
+					subject VARCHAR(255) NOT NULL,
+					occurred_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (stage, subject)
+				);
+				CREATE INDEX IF NOT EXISTS idx_funnel_events_occurred_at ON funnel_events(occurred_at);
+				CREATE TABLE IF NOT EXISTS funnel_alerts (
+					id VARCHAR(255) PRIMARY KEY,
+					from_stage VARCHAR(30) NOT NULL,
+					to_stage VARCHAR(30) NOT NULL,
+					entered BIGINT NOT NULL,
+					converted BIGINT NOT NULL,
+					rate NUMERIC(7,4) NOT NULL,
+					baseline_rate NUMERIC(7,4) NOT NULL,
+					window_start TIMESTAMP NOT NULL,
+					window_end TIMESTAMP NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_funnel_alerts_created_at ON funnel_alerts(created_at);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS funnel_alerts;
+				DROP TABLE IF EXISTS funnel_events;
+			`)
+		},
+	},
+	{
+		Version: "950",
+		Name:    "create_shipping_methods",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Shipping methods priced at a flat rate or by order subtotal (table rate), offered
+			// where no shipping zone covers the destination
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS shipping_methods (
+					id VARCHAR(100) PRIMARY KEY,
+					name VARCHAR(255) NOT NULL,
+					description TEXT,
+					carrier VARCHAR(100),
+					service_level VARCHAR(100),
+					air BOOLEAN NOT NULL DEFAULT false,
+					countries JSONB,
+					currency VARCHAR(3) NOT NULL,
+					flat_rate BIGINT NOT NULL,
+					rate_tiers JSONB,
+					free_shipping_min BIGINT,
+					estimated_days_min INT NOT NULL DEFAULT 0,
+					estimated_days_max INT NOT NULL DEFAULT 0,
+					sort_order INT NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT true,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_shipping_methods_is_active ON shipping_methods(is_active);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS shipping_methods;`)
+		},
+	},
+	{
+		Version: "951",
+		Name:    "create_rentals",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Products rented by the day, and the units of each SKU orders hold for their
+			// rental periods
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS rental_products (
+					product_id VARCHAR(255) PRIMARY KEY,
+					units INT NOT NULL,
+					sku_units JSONB,
+					min_days INT NOT NULL DEFAULT 1,
+					max_days INT NOT NULL DEFAULT 0,
+					buffer_days INT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS rental_bookings (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					sku VARCHAR(255) NOT NULL,
+					order_id VARCHAR(255) NOT NULL,
+					order_item_id VARCHAR(255),
+					quantity INT NOT NULL,
+					start_date DATE NOT NULL,
+					end_date DATE NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_rental_bookings_sku_dates ON rental_bookings(sku, start_date, end_date);
+				CREATE INDEX IF NOT EXISTS idx_rental_bookings_order_id ON rental_bookings(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS rental_bookings;
+				DROP TABLE IF EXISTS rental_products;
+			`)
+		},
+	},
+	{
+		Version: "952",
+		Name:    "create_appointments",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Service products booked into time slots with a number of seats, and the seats
+			// orders book
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS appointment_products (
+					product_id VARCHAR(255) PRIMARY KEY,
+					cancel_window_hours INT NOT NULL DEFAULT 0,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE TABLE IF NOT EXISTS appointment_slots (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					starts_at TIMESTAMP NOT NULL,
+					ends_at TIMESTAMP NOT NULL,
+					capacity INT NOT NULL,
+					booked INT NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT true,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					CHECK (booked >= 0 AND booked <= capacity)
+				);
+				CREATE INDEX IF NOT EXISTS idx_appointment_slots_product_starts ON appointment_slots(product_id, starts_at);
+				CREATE TABLE IF NOT EXISTS appointment_bookings (
+					id VARCHAR(255) PRIMARY KEY,
+					slot_id VARCHAR(255) NOT NULL,
+					product_id VARCHAR(255) NOT NULL,
+					order_id VARCHAR(255) NOT NULL,
+					order_item_id VARCHAR(255),
+					user_id VARCHAR(255) NOT NULL,
+					quantity INT NOT NULL,
+					starts_at TIMESTAMP NOT NULL,
+					ends_at TIMESTAMP NOT NULL,
+					cancel_by TIMESTAMP NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					canceled_at TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_appointment_bookings_slot_id ON appointment_bookings(slot_id);
+				CREATE INDEX IF NOT EXISTS idx_appointment_bookings_order_id ON appointment_bookings(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS appointment_bookings;
+				DROP TABLE IF EXISTS appointment_slots;
+				DROP TABLE IF EXISTS appointment_products;
+			`)
+		},
+	},
+	{
+		Version: "953",
+		Name:    "create_donation_products",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Pay-what-you-want products and the limits on the amount customers choose
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS donation_products (
+					product_id VARCHAR(255) PRIMARY KEY,
+					min_amount BIGINT NOT NULL,
+					max_amount BIGINT,
+					currency VARCHAR(3) NOT NULL,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS donation_products;`)
+		},
+	},
+	{
+		Version: "954",
+		Name:    "create_order_bumps",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Add-ons offered at checkout at a special price
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_bumps (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					headline VARCHAR(255) NOT NULL,
+					price BIGINT NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					position INTEGER NOT NULL DEFAULT 0,
+					is_active BOOLEAN NOT NULL DEFAULT TRUE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_bumps_active ON order_bumps(is_active, position);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_bumps;`)
+		},
+	},
+	{
+		Version: "955",
+		Name:    "create_wishlists",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Customers' wishlists, shared as gift registries when public, and the registry
+			// units orders bought
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS wishlists (
+					id VARCHAR(255) PRIMARY KEY,
+					user_id VARCHAR(255) NOT NULL,
+					name VARCHAR(255) NOT NULL,
+					is_public BOOLEAN NOT NULL DEFAULT FALSE,
+					share_token VARCHAR(64) UNIQUE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_wishlists_user_id ON wishlists(user_id);
+				CREATE TABLE IF NOT EXISTS wishlist_items (
+					id VARCHAR(255) PRIMARY KEY,
+					wishlist_id VARCHAR(255) NOT NULL,
+					product_id VARCHAR(255) NOT NULL,
+					variant_id VARCHAR(255),
+					quantity INT NOT NULL,
+					purchased INT NOT NULL DEFAULT 0,
+					note TEXT,
+					added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					CHECK (quantity > 0 AND purchased >= 0)
+				);
+				CREATE INDEX IF NOT EXISTS idx_wishlist_items_wishlist_id ON wishlist_items(wishlist_id);
+				CREATE TABLE IF NOT EXISTS wishlist_purchases (
+					id VARCHAR(255) PRIMARY KEY,
+					wishlist_id VARCHAR(255) NOT NULL,
+					item_id VARCHAR(255) NOT NULL,
+					order_id VARCHAR(255) NOT NULL,
+					order_item_id VARCHAR(255),
+					buyer_id VARCHAR(255) NOT NULL,
+					quantity INT NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					canceled_at TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_wishlist_purchases_item_id ON wishlist_purchases(item_id);
+				CREATE INDEX IF NOT EXISTS idx_wishlist_purchases_order_id ON wishlist_purchases(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS wishlist_purchases;
+				DROP TABLE IF EXISTS wishlist_items;
+				DROP TABLE IF EXISTS wishlists;
+			`)
+		},
+	},
+	{
+		Version: "956",
+		Name:    "create_support_tickets",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Issues customers open against their orders and the messages exchanged on them
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS support_tickets (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					subject VARCHAR(255) NOT NULL,
+					category VARCHAR(30) NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					closed_at TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_support_tickets_order_id ON support_tickets(order_id);
+				CREATE INDEX IF NOT EXISTS idx_support_tickets_user_id ON support_tickets(user_id);
+				CREATE INDEX IF NOT EXISTS idx_support_tickets_status_updated ON support_tickets(status, updated_at);
+				CREATE TABLE IF NOT EXISTS support_ticket_messages (
+					id VARCHAR(255) PRIMARY KEY,
+					ticket_id VARCHAR(255) NOT NULL,
+					author_id VARCHAR(255) NOT NULL,
+					staff BOOLEAN NOT NULL DEFAULT FALSE,
+					body TEXT NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_support_ticket_messages_ticket_id ON support_ticket_messages(ticket_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS support_ticket_messages;
+				DROP TABLE IF EXISTS support_tickets;
+			`)
+		},
+	},
+	{
+		Version: "957",
+		Name:    "create_archived_orders",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Delivered orders moved out of orders once old enough, with the columns the
+			// Order model maps; columns added to the model must be added here as well
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS archived_orders (
+					id VARCHAR(255) PRIMARY KEY,
+					order_number VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					status VARCHAR(50) NOT NULL,
+					items JSONB NOT NULL DEFAULT '[]',
+					shipping_address JSONB,
+					billing_address JSONB,
+					payment_method_id VARCHAR(255),
+					subtotal BIGINT NOT NULL DEFAULT 0,
+					discount_total BIGINT NOT NULL DEFAULT 0,
+					tax_total BIGINT NOT NULL DEFAULT 0,
+					shipping_total BIGINT NOT NULL DEFAULT 0,
+					total BIGINT NOT NULL DEFAULT 0,
+					currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+					notes TEXT,
+					ip_address VARCHAR(100),
+					user_agent TEXT,
+					cancelled_at TIMESTAMP,
+					cancel_reason TEXT,
+					metadata JSONB,
+					item_metadata JSONB,
+					created_at TIMESTAMP NOT NULL,
+					updated_at TIMESTAMP NOT NULL,
+					archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_archived_orders_order_number ON archived_orders(order_number);
+				CREATE INDEX IF NOT EXISTS idx_archived_orders_user_id ON archived_orders(user_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS archived_orders;`)
+		},
+	},
+	// Delivered orders due for archiving, least recently changed first
+	CreateIndexConcurrently("958", "idx_orders_status_updated_at", "orders", "status, updated_at"),
+	{
+		Version: "959",
+		Name:    "create_product_reviews",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Customer reviews of products, one per customer and product, shown once approved
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS product_reviews (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+					title VARCHAR(255),
+					body TEXT NOT NULL,
+					status VARCHAR(20) NOT NULL,
+					filtered BOOLEAN NOT NULL DEFAULT FALSE,
+					moderation_note TEXT,
+					moderated_by VARCHAR(255),
+					moderated_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_product_reviews_product_user ON product_reviews(product_id, user_id);
+				CREATE INDEX IF NOT EXISTS idx_product_reviews_user_id ON product_reviews(user_id);
+				CREATE INDEX IF NOT EXISTS idx_product_reviews_status ON product_reviews(status);
+				CREATE TABLE IF NOT EXISTS product_review_photos (
+					id VARCHAR(255) PRIMARY KEY,
+					review_id VARCHAR(255) NOT NULL REFERENCES product_reviews(id) ON DELETE CASCADE,
+					media_key VARCHAR(500) NOT NULL,
+					url VARCHAR(1000) NOT NULL,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_product_review_photos_review_id ON product_review_photos(review_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS product_review_photos;
+				DROP TABLE IF EXISTS product_reviews;
+			`)
+		},
+	},
+	{
+		Version: "960",
+		Name:    "create_product_questions_and_content_reports",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Product Q&A, and abuse reports on reviews, questions and answers that hide the
+			// content once enough users report it
+			return exec.Exec(ctx, `
+				ALTER TABLE product_reviews ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+				CREATE TABLE IF NOT EXISTS product_questions (
+					id VARCHAR(255) PRIMARY KEY,
+					product_id VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					body TEXT NOT NULL,
+					filtered BOOLEAN NOT NULL DEFAULT FALSE,
+					hidden BOOLEAN NOT NULL DEFAULT FALSE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_product_questions_product_id ON product_questions(product_id);
+				CREATE TABLE IF NOT EXISTS product_answers (
+					id VARCHAR(255) PRIMARY KEY,
+					question_id VARCHAR(255) NOT NULL REFERENCES product_questions(id) ON DELETE CASCADE,
+					user_id VARCHAR(255) NOT NULL,
+					staff BOOLEAN NOT NULL DEFAULT FALSE,
+					body TEXT NOT NULL,
+					filtered BOOLEAN NOT NULL DEFAULT FALSE,
+					hidden BOOLEAN NOT NULL DEFAULT FALSE,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_product_answers_question_id ON product_answers(question_id);
+				CREATE TABLE IF NOT EXISTS content_reports (
+					id VARCHAR(255) PRIMARY KEY,
+					content_type VARCHAR(20) NOT NULL,
+					content_id VARCHAR(255) NOT NULL,
+					user_id VARCHAR(255) NOT NULL,
+					reason VARCHAR(20) NOT NULL,
+					details TEXT,
+					status VARCHAR(20) NOT NULL,
+					resolved_by VARCHAR(255),
+					resolved_at TIMESTAMP,
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_content_reports_content_user ON content_reports(content_type, content_id, user_id);
+				CREATE INDEX IF NOT EXISTS idx_content_reports_status ON content_reports(status);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `
+				DROP TABLE IF EXISTS content_reports;
+				DROP TABLE IF EXISTS product_answers;
+				DROP TABLE IF EXISTS product_questions;
+				ALTER TABLE product_reviews DROP COLUMN IF EXISTS hidden;
+			`)
+		},
+	},
+	{
+		Version: "961",
+		Name:    "create_order_status_events",
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			// Status history of the changes staff make through the order workflow
+			return exec.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS order_status_events (
+					id VARCHAR(255) PRIMARY KEY,
+					order_id VARCHAR(255) NOT NULL,
+					from_status VARCHAR(50) NOT NULL,
+					to_status VARCHAR(50) NOT NULL,
+					reason TEXT,
+					changed_by VARCHAR(255),
+					created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+				CREATE INDEX IF NOT EXISTS idx_order_status_events_order_id ON order_status_events(order_id);
+			`)
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, `DROP TABLE IF EXISTS order_status_events;`)
+		},
+	},
+}
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/devchuckcamp/gocommerce/migrations"
+	"gorm.io/gorm/schema"
+)
+
+// Migration lint rules. A statement can opt out of one with a "-- lint:allow <rule>"
+// comment, which should say why the operation is safe.
+const (
+	LintDropInUse          = "drop-in-use"          // dropping a table or column the API still maps
+	LintRenameInUse        = "rename-in-use"        // renaming a table or column the API still maps
+	LintIndexNotConcurrent = "index-not-concurrent" // CREATE INDEX without CONCURRENTLY on a large table
+	LintColumnTypeChange   = "column-type-change"   // ALTER COLUMN ... TYPE rewrites a large table
+	LintSetNotNull         = "set-not-null"         // SET NOT NULL scans a large table under an exclusive lock
+	LintAddRequiredColumn  = "add-required-column"  // NOT NULL column without a default on a large table
+)
+
+const (
+	// defaultLargeTableRows is the estimated row count from which a table is large enough
+	// that locking it for a rewrite or index build would stall checkout
+	defaultLargeTableRows = 100000
+
+	lintAllowMarker         = "lint:allow"
+	lintStatementPreviewLen = 160
+)
+
+// LintViolation is an unsafe statement found in a pending migration
+type LintViolation struct {
+	Version   string
+	Name      string
+	Rule      string
+	Statement string
+	Reason    string
+}
+
+// MigrationLintError lists the violations that stopped the migrations from running
+type MigrationLintError struct {
+	Violations []LintViolation
+}
+
+func (e *MigrationLintError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d unsafe migration statement(s); split the change into expand and contract phases or add \"-- %s <rule>\" with a reason:", len(e.Violations), lintAllowMarker)
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  %s (%s) [%s] %s: %s", v.Version, v.Name, v.Rule, v.Reason, v.Statement)
+	}
+	return b.String()
+}
+
+// TableRowsFunc estimates the rows in a table; 0 for a table that doesn't exist yet
+type TableRowsFunc func(ctx context.Context, table string) (int64, error)
+
+// MigrationLinter rejects migrations that would break or stall the running API during a
+// rolling deploy, while old and new code share the database: dropping or renaming schema
+// the models still map, and statements that lock a large table for the length of a
+// rewrite or index build.
+type MigrationLinter struct {
+	inUse          map[string]map[string]bool // table -> mapped columns
+	tableRows      TableRowsFunc
+	largeTableRows int64
+}
+
+// NewMigrationLinter creates a linter for the tables and columns mapped by models
+func NewMigrationLinter(models []interface{}, tableRows TableRowsFunc) (*MigrationLinter, error) {
+	inUse := make(map[string]map[string]bool)
+	cache := &sync.Map{}
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		columns := make(map[string]bool)
+		for _, field := range s.Fields {
+			if field.DBName != "" {
+				columns[field.DBName] = true
+			}
+		}
+		inUse[s.Table] = columns
+	}
+
+	return &MigrationLinter{
+		inUse:          inUse,
+		tableRows:      tableRows,
+		largeTableRows: defaultLargeTableRows,
+	}, nil
+}
+
+// WithLargeTableRows sets the estimated row count from which a table counts as large
+func (l *MigrationLinter) WithLargeTableRows(rows int64) *MigrationLinter {
+	l.largeTableRows = rows
+	return l
+}
+
+// Lint records the statements each migration's Up would run, without running them, and
+// checks them. Migrations that branch on query results are linted on the empty-result path.
+func (l *MigrationLinter) Lint(ctx context.Context, pending []migrations.Migration) error {
+	var violations []LintViolation
+	for _, migration := range pending {
+		recorder := &recordingExecutor{}
+		if err := migration.Up(ctx, recorder); err != nil {
+			return fmt.Errorf("failed to record migration %s (%s): %w", migration.Version, migration.Name, err)
+		}
+		for _, statement := range recorder.statements {
+			found, err := l.lintStatement(ctx, statement)
+			if err != nil {
+				return err
+			}
+			for _, v := range found {
+				v.Version, v.Name = migration.Version, migration.Name
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &MigrationLintError{Violations: violations}
+	}
+	return nil
+}
+
+var (
+	alterTablePattern   = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([\w.]+) (.*)$`)
+	dropTablePattern    = regexp.MustCompile(`^DROP TABLE (?:IF EXISTS )?([\w.]+(?:, ?[\w.]+)*)`)
+	createIndexPattern  = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?.*? ON (?:ONLY )?([\w.]+)`)
+	dropColumnPattern   = regexp.MustCompile(`(?:^|, ?)DROP (?:COLUMN )?(?:IF EXISTS )?(\w+)`)
+	renameColumnPattern = regexp.MustCompile(`^RENAME (?:COLUMN )?(\w+) TO `)
+	renameTablePattern  = regexp.MustCompile(`^RENAME TO `)
+	columnTypePattern   = regexp.MustCompile(`ALTER (?:COLUMN )?\w+ (?:SET DATA )?TYPE `)
+	setNotNullPattern   = regexp.MustCompile(`ALTER (?:COLUMN )?\w+ SET NOT NULL`)
+	addColumnPattern    = regexp.MustCompile(`(?:^|, ?)ADD (?:COLUMN )?(?:IF NOT EXISTS )?(\w+) ([^,]*)`)
+	lineCommentPattern  = regexp.MustCompile(`--[^\n]*`)
+	whitespacePattern   = regexp.MustCompile(`\s+`)
+)
+
+// dropClauseKeywords follow DROP in ALTER TABLE clauses that don't drop a column
+var dropClauseKeywords = map[string]bool{"CONSTRAINT": true, "DEFAULT": true, "NOT": true, "EXPRESSION": true, "IDENTITY": true}
+
+// addClauseKeywords follow ADD in ALTER TABLE clauses that don't add a column
+var addClauseKeywords = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true, "EXCLUDE": true}
+
+// lintStatement checks one statement against every rule it doesn't opt out of
+func (l *MigrationLinter) lintStatement(ctx context.Context, statement string) ([]LintViolation, error) {
+	allowed := allowedRules(statement)
+	normalized := normalizeStatement(statement)
+	upper := strings.ToUpper(normalized)
+
+	var violations []LintViolation
+	add := func(rule, reason string) {
+		if !allowed[rule] {
+			violations = append(violations, LintViolation{Rule: rule, Statement: preview(normalized), Reason: reason})
+		}
+	}
+
+	if m := dropTablePattern.FindStringSubmatch(upper); m != nil {
+		for _, table := range strings.Split(m[1], ",") {
+			if table = tableName(table); l.inUse[table] != nil {
+				add(LintDropInUse, fmt.Sprintf("table %s is still mapped by a model", table))
+			}
+		}
+		return violations, nil
+	}
+
+	if m := createIndexPattern.FindStringSubmatch(upper); m != nil {
+		if m[1] == "" {
+			large, err := l.isLarge(ctx, tableName(m[2]))
+			if err != nil {
+				return nil, err
+			}
+			if large {
+				add(LintIndexNotConcurrent, fmt.Sprintf("building an index on %s blocks writes; use CREATE INDEX CONCURRENTLY (CreateIndexConcurrently)", tableName(m[2])))
+			}
+		}
+		return violations, nil
+	}
+
+	m := alterTablePattern.FindStringSubmatch(upper)
+	if m == nil {
+		return violations, nil
+	}
+	table, clauses := tableName(m[1]), m[2]
+	columns := l.inUse[table]
+
+	if renameTablePattern.MatchString(clauses) && columns != nil {
+		add(LintRenameInUse, fmt.Sprintf("table %s is still mapped by a model", table))
+	}
+	if rm := renameColumnPattern.FindStringSubmatch(clauses); rm != nil && columns[strings.ToLower(rm[1])] {
+		add(LintRenameInUse, fmt.Sprintf("column %s.%s is still mapped by a model; use RenameColumn", table, strings.ToLower(rm[1])))
+	}
+	for _, dm := range dropColumnPattern.FindAllStringSubmatch(clauses, -1) {
+		column := strings.ToLower(dm[1])
+		if !dropClauseKeywords[dm[1]] && columns[column] {
+			add(LintDropInUse, fmt.Sprintf("column %s.%s is still mapped by a model; remove it from the model a release before dropping it", table, column))
+		}
+	}
+
+	large, err := l.isLarge(ctx, table)
+	if err != nil || !large {
+		return violations, err
+	}
+	if columnTypePattern.MatchString(clauses) {
+		add(LintColumnTypeChange, fmt.Sprintf("changing a column type rewrites %s under an exclusive lock; add a new column instead", table))
+	}
+	if setNotNullPattern.MatchString(clauses) {
+		add(LintSetNotNull, fmt.Sprintf("SET NOT NULL scans %s under an exclusive lock; add a NOT VALID check constraint and validate it separately", table))
+	}
+	for _, am := range addColumnPattern.FindAllStringSubmatch(clauses, -1) {
+		if !addClauseKeywords[am[1]] && strings.Contains(am[2], "NOT NULL") && !strings.Contains(am[2], "DEFAULT") {
+			add(LintAddRequiredColumn, fmt.Sprintf("a NOT NULL column without a default cannot be added to %s while it has rows", table))
+		}
+	}
+	return violations, nil
+}
+
+func (l *MigrationLinter) isLarge(ctx context.Context, table string) (bool, error) {
+	if l.tableRows == nil {
+		return false, nil
+	}
+	rows, err := l.tableRows(ctx, table)
+	if err != nil {
+		return false, fmt.Errorf("failed to estimate rows in %s: %w", table, err)
+	}
+	return rows >= l.largeTableRows, nil
+}
+
+// allowedRules returns the rules a statement opts out of with "-- lint:allow <rule>"
+func allowedRules(statement string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, comment := range lineCommentPattern.FindAllString(statement, -1) {
+		fields := strings.Fields(strings.TrimPrefix(comment, "--"))
+		if len(fields) >= 2 && fields[0] == lintAllowMarker {
+			allowed[fields[1]] = true
+		}
+	}
+	return allowed
+}
+
+func normalizeStatement(statement string) string {
+	statement = lineCommentPattern.ReplaceAllString(statement, " ")
+	statement = strings.ReplaceAll(statement, `"`, "")
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(statement, " "))
+}
+
+// tableName drops any schema qualifier and returns the lower-case table name
+func tableName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+func preview(statement string) string {
+	if len(statement) > lintStatementPreviewLen {
+		return statement[:lintStatementPreviewLen] + "..."
+	}
+	return statement
+}
+
+// splitStatements splits a SQL script on semicolons outside quotes and $$ bodies
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	inQuote, inDollar, inComment := false, false, false
+
+	for i := 0; i < len(script); i++ {
+		ch := script[i]
+		switch {
+		case inComment:
+			if ch == '\n' {
+				inComment = false
+			}
+		case inQuote:
+			if ch == '\'' {
+				inQuote = false
+			}
+		case inDollar:
+			if ch == '$' && i+1 < len(script) && script[i+1] == '$' {
+				inDollar = false
+				current.WriteByte(ch)
+				i++
+			}
+		case ch == '-' && i+1 < len(script) && script[i+1] == '-':
+			inComment = true
+		case ch == '\'':
+			inQuote = true
+		case ch == '$' && i+1 < len(script) && script[i+1] == '$':
+			inDollar = true
+			current.WriteByte(ch)
+			i++
+		case ch == ';':
+			if s := strings.TrimSpace(current.String()); s != "" {
+				statements = append(statements, s)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteByte(ch)
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		statements = append(statements, s)
+	}
+	return statements
+}
+
+// recordingExecutor captures the statements a migration would run instead of running them
+type recordingExecutor struct {
+	statements []string
+}
+
+func (r *recordingExecutor) Exec(ctx context.Context, query string, args ...interface{}) error {
+	r.statements = append(r.statements, splitStatements(query)...)
+	return nil
+}
+
+func (r *recordingExecutor) Query(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (r *recordingExecutor) Begin(ctx context.Context) (migrations.Executor, error) {
+	return r, nil
+}
+
+func (r *recordingExecutor) Commit(ctx context.Context) error   { return nil }
+func (r *recordingExecutor) Rollback(ctx context.Context) error { return nil }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"github.com/devchuckcamp/gocommerce/migrations"
@@ -12,10 +13,76 @@ import (
 
 // RunCommerceMigrations runs gocommerce migrations using the migrations package
 func (db *DB) RunCommerceMigrations(ctx context.Context) error {
+	manager, executor, err := db.commerceMigrations()
+	if err != nil {
+		return err
+	}
+
+	// Refuse pending migrations that would break or stall the running API mid-deploy
+	if err := lintPendingMigrations(ctx, manager, executor); err != nil {
+		return err
+	}
+
+	// Run migrations
+	if err := manager.Up(ctx); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	log.Println("✓ gocommerce migrations completed successfully")
+	return nil
+}
+
+// SchemaStatus compares the migrations recorded in the database with those this build
+// registers
+type SchemaStatus struct {
+	Expected string   // latest migration version this build registers
+	Applied  int      // migrations recorded as applied
+	Pending  []string // versions registered but not applied
+	Unknown  []string // versions applied that this build doesn't register, e.g. by a newer release
+}
+
+// SchemaStatus reads how the database's schema matches this build's migrations
+func (db *DB) SchemaStatus(ctx context.Context) (*SchemaStatus, error) {
+	manager, _, err := db.commerceMigrations()
+	if err != nil {
+		return nil, err
+	}
+	status, err := manager.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration status: %w", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, list := range [][]migrations.Migration{migrations.PostgreSQLExampleMigrations, localMigrations} {
+		for _, migration := range list {
+			registered[migration.Version] = true
+		}
+	}
+
+	schema := &SchemaStatus{
+		Expected: localMigrations[len(localMigrations)-1].Version,
+		Applied:  len(status.Applied),
+		Pending:  []string{},
+		Unknown:  []string{},
+	}
+	for _, migration := range status.Pending {
+		schema.Pending = append(schema.Pending, migration.Version)
+	}
+	for _, migration := range status.Applied {
+		if !registered[migration.Version] {
+			schema.Unknown = append(schema.Unknown, migration.Version)
+		}
+	}
+	return schema, nil
+}
+
+// commerceMigrations creates a migration manager with the gocommerce and local migrations
+// registered
+func (db *DB) commerceMigrations() (*migrations.Manager, *gormExecutor, error) {
 	// Get underlying sql.DB for migrations
 	sqlDB, err := db.DB.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get sql.DB: %w", err)
+		return nil, nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
 	// Create migration executor
@@ -29,36 +96,14 @@ func (db *DB) RunCommerceMigrations(ctx context.Context) error {
 
 	// Register example migrations (creates tables for catalog, cart, orders, pricing)
 	if err := manager.RegisterMultiple(migrations.PostgreSQLExampleMigrations); err != nil {
-		return fmt.Errorf("failed to register migrations: %w", err)
+		return nil, nil, fmt.Errorf("failed to register migrations: %w", err)
 	}
 
 	// Local additive migrations for this API (must stay backwards compatible).
-	if err := manager.Register(migrations.Migration{
-		Version: "900",
-		Name:    "add_cart_item_attributes",
-		Up: func(ctx context.Context, exec migrations.Executor) error {
-			return exec.Exec(ctx, `
-				ALTER TABLE IF EXISTS cart_items
-				ADD COLUMN IF NOT EXISTS attributes JSONB;
-			`)
-		},
-		Down: func(ctx context.Context, exec migrations.Executor) error {
-			return exec.Exec(ctx, `
-				ALTER TABLE IF EXISTS cart_items
-				DROP COLUMN IF EXISTS attributes;
-			`)
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to register local migrations: %w", err)
-	}
-
-	// Run migrations
-	if err := manager.Up(ctx); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	if err := manager.RegisterMultiple(localMigrations); err != nil {
+		return nil, nil, fmt.Errorf("failed to register local migrations: %w", err)
 	}
-
-	log.Println("✓ gocommerce migrations completed successfully")
-	return nil
+	return manager, executor, nil
 }
 
 // SeedCommerce seeds the database with sample e-commerce data
@@ -160,6 +205,26 @@ func (db *DB) seedProductPrices() error {
 	return nil
 }
 
+// lintPendingMigrations runs the migration linter over the migrations not yet applied
+func lintPendingMigrations(ctx context.Context, manager *migrations.Manager, executor *gormExecutor) error {
+	status, err := manager.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list pending migrations: %w", err)
+	}
+	if len(status.Pending) == 0 {
+		return nil
+	}
+
+	linter, err := NewMigrationLinter(apiModels, executor.estimateRows)
+	if err != nil {
+		return err
+	}
+	return linter.Lint(ctx, status.Pending)
+}
+
+// concurrentIndexPattern matches CREATE and DROP INDEX CONCURRENTLY statements
+var concurrentIndexPattern = regexp.MustCompile(`(?i)^\s*(CREATE\s+(UNIQUE\s+)?|DROP\s+)INDEX\s+CONCURRENTLY\b`)
+
 // gormExecutor implements migrations.Executor interface for GORM
 type gormExecutor struct {
 	db *sql.DB
@@ -172,7 +237,9 @@ func newGormExecutor(db *sql.DB) *gormExecutor {
 
 func (e *gormExecutor) Exec(ctx context.Context, query string, args ...interface{}) error {
 	var err error
-	if e.tx != nil {
+	// Concurrent index builds cannot run inside a transaction block, so they bypass the
+	// migration's transaction; see CreateIndexConcurrently
+	if e.tx != nil && !concurrentIndexPattern.MatchString(query) {
 		_, err = e.tx.ExecContext(ctx, query, args...)
 	} else {
 		_, err = e.db.ExecContext(ctx, query, args...)
@@ -237,6 +304,16 @@ func (e *gormExecutor) Query(ctx context.Context, query string, args ...interfac
 	return result, rows.Err()
 }
 
+// estimateRows returns the planner's row estimate for a table, or 0 if it doesn't exist
+func (e *gormExecutor) estimateRows(ctx context.Context, table string) (int64, error) {
+	rows, err := e.Query(ctx, `SELECT GREATEST(reltuples, 0)::bigint AS estimate FROM pg_class WHERE oid = to_regclass($1)`, table)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	estimate, _ := rows[0]["estimate"].(int64)
+	return estimate, nil
+}
+
 func (e *gormExecutor) Begin(ctx context.Context) (migrations.Executor, error) {
 	tx, err := e.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -369,7 +446,8 @@ func (db *DB) Seed() error {
 		}
 	}
 
-	// Create sample variants for t-shirt
+	// Create sample variants for t-shirt, with EAN-13 barcodes stored as 14 digit GTINs
+	smallRedBarcode, mediumBlueBarcode := "04006381333931", "05012345678900"
 	variants := []Variant{
 		{
 			ID:         "var-1",
@@ -379,6 +457,7 @@ func (db *DB) Seed() error {
 			Price:      2999,
 			Currency:   "USD",
 			Attributes: `{"size": "S", "color": "Red"}`,
+			Barcode:    &smallRedBarcode,
 		},
 		{
 			ID:         "var-2",
@@ -388,6 +467,7 @@ func (db *DB) Seed() error {
 			Price:      2999,
 			Currency:   "USD",
 			Attributes: `{"size": "M", "color": "Blue"}`,
+			Barcode:    &mediumBlueBarcode,
 		},
 	}
 
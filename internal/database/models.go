@@ -7,6 +7,30 @@ import (
 	"github.com/devchuckcamp/gocommerce/money"
 )
 
+// apiModels lists every model the API reads or writes; the migration linter treats their
+// tables and columns as in use by the running code
+var apiModels = []interface{}{
+	&Product{}, &Variant{}, &Category{}, &Brand{}, &Cart{}, &CartItem{}, &Order{}, &ArchivedOrder{},
+	&ProductPrice{}, &Promotion{}, &DeliverySlot{}, &OrderDeliverySlot{}, &ShippingZone{},
+	&ShippingZoneRate{}, &ShippingMethod{}, &OrderPayment{}, &OrderPaymentRetry{}, &Dispute{}, &OrderRefund{},
+	&StoreCreditBalance{}, &StoreCreditTransaction{}, &ContentPage{}, &Placement{},
+	&APIKey{}, &OrderShipment{}, &WebhookEvent{}, &CatalogVersion{},
+	&OrderEvent{}, &BusinessCalendar{}, &CalendarHoliday{}, &PurchaseLimit{}, &WaitingRoomTicket{}, &LoginLockout{}, &LoginDevice{},
+	&ConsentEvent{}, &ProductTag{}, &Collection{}, &CollectionProduct{},
+	&InventoryLevel{}, &InventoryActivity{}, &InventorySnapshot{},
+	&POSSale{}, &OrderAttribution{}, &RecentlyViewedProduct{},
+	&Customer{}, &Company{}, &CompanyMember{}, &CompanyAddress{}, &CompanyOrder{},
+	&Quote{}, &QuoteItem{}, &Invoice{}, &InvoicePayment{}, &CheckoutRule{}, &ShippingRestriction{},
+	&RentalProduct{}, &RentalBooking{}, &AppointmentProduct{}, &AppointmentSlot{}, &AppointmentBooking{},
+	&DonationProduct{}, &OrderBump{}, &Wishlist{}, &WishlistItem{}, &WishlistPurchase{},
+	&SupportTicket{}, &SupportTicketMessage{},
+	&PricingAnomaly{}, &CategoryProductCount{}, &SearchTerm{},
+	&SynonymSet{}, &SearchRule{}, &ProductMedia{}, &DeadLetter{},
+	&ConfigChange{}, &QuotaCounter{}, &FunnelEvent{}, &FunnelAlert{},
+	&ProductReview{}, &ProductReviewPhoto{},
+	&ProductQuestion{}, &ProductAnswer{}, &ContentReport{}, &OrderStatusEvent{},
+}
+
 // Product represents a product in the database
 type Product struct {
 	ID          string    `gorm:"primaryKey;column:id;size:255"`
@@ -26,16 +50,20 @@ type Product struct {
 
 // Variant represents a product variant in the database
 type Variant struct {
-	ID         string    `gorm:"primaryKey;size:36"`
-	ProductID  string    `gorm:"size:36;index;not null"`
-	SKU        string    `gorm:"uniqueIndex;size:100;not null"`
-	Name       string    `gorm:"size:255;not null"`
-	Price      int64     `gorm:"not null"` // stored as cents
-	Currency   string    `gorm:"size:3;not null;default:'USD'"`
-	Attributes string    `gorm:"type:jsonb"` // JSON attributes like {"color": "red", "size": "L"}
-	ImageURL   string    `gorm:"size:500"`
-	CreatedAt  time.Time `gorm:"not null"`
-	UpdatedAt  time.Time `gorm:"not null"`
+	ID           string    `gorm:"primaryKey;size:36"`
+	ProductID    string    `gorm:"size:36;index;not null"`
+	SKU          string    `gorm:"uniqueIndex;size:100;not null"`
+	Name         string    `gorm:"size:255;not null"`
+	Price        int64     `gorm:"not null"` // stored as cents
+	Currency     string    `gorm:"size:3;not null;default:'USD'"`
+	Attributes   string    `gorm:"type:jsonb"` // JSON attributes like {"color": "red", "size": "L"}
+	ImageURL     string    `gorm:"size:500"`
+	Barcode      *string   `gorm:"size:14"`            // GTIN padded to 14 digits; unique when set
+	UnitQuantity *float64  `gorm:"type:numeric(12,3)"` // contents for unit pricing, e.g. 500 (g)
+	UnitMeasure  string    `gorm:"size:10"`
+	Archived     bool      `gorm:"not null;default:false"` // archived variants can't be bought
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
 }
 
 // Category represents a product category in the database
@@ -71,6 +99,7 @@ type Cart struct {
 	CreatedAt time.Time  `gorm:"column:created_at;not null"`
 	UpdatedAt time.Time  `gorm:"column:updated_at;not null"`
 	ExpiresAt *time.Time `gorm:"column:expires_at"`
+	Metadata  *string    `gorm:"column:metadata;type:jsonb"` // client metadata, written only by CartMetadataRepository
 }
 
 // CartItem represents a cart item row in the normalized cart_items table.
@@ -87,6 +116,7 @@ type CartItem struct {
 	Quantity      int       `gorm:"column:quantity;not null"`
 	Attributes    string    `gorm:"column:attributes;type:jsonb"`
 	AddedAt       time.Time `gorm:"column:added_at;not null"`
+	Metadata      *string   `gorm:"column:metadata;type:jsonb"` // client metadata, written only by CartMetadataRepository
 }
 
 // Order represents an order in the database
@@ -110,10 +140,19 @@ type Order struct {
 	UserAgent       string `gorm:"size:500"`
 	CancelledAt     *time.Time
 	CancelReason    string    `gorm:"type:text"`
+	Metadata        *string   `gorm:"column:metadata;type:jsonb"`      // cart metadata at checkout
+	ItemMetadata    *string   `gorm:"column:item_metadata;type:jsonb"` // cart item metadata by order item ID
 	CreatedAt       time.Time `gorm:"not null"`
 	UpdatedAt       time.Time `gorm:"not null"`
 }
 
+// ArchivedOrder is a delivered order moved out of the orders table once it is old enough,
+// keeping every column of Order
+type ArchivedOrder struct {
+	Order      `gorm:"embedded"`
+	ArchivedAt time.Time `gorm:"not null"`
+}
+
 // ProductPrice represents a time-bounded price for a product or variant
 type ProductPrice struct {
 	ID            string     `gorm:"primaryKey;column:id;size:255"`
@@ -153,6 +192,915 @@ type Promotion struct {
 	UpdatedAt          time.Time `gorm:"not null"`
 }
 
+// DeliverySlot represents a capacity-managed delivery window for a postcode region
+type DeliverySlot struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	Region    string    `gorm:"column:region;size:20;not null;index"` // postcode prefix, e.g. "941"
+	StartsAt  time.Time `gorm:"column:starts_at;not null"`
+	EndsAt    time.Time `gorm:"column:ends_at;not null"`
+	Capacity  int       `gorm:"column:capacity;not null"`
+	Reserved  int       `gorm:"column:reserved;not null;default:0"`
+	IsActive  bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// OrderDeliverySlot links an order to the delivery slot selected at checkout
+type OrderDeliverySlot struct {
+	OrderID    string    `gorm:"primaryKey;column:order_id;size:255"`
+	SlotID     string    `gorm:"column:slot_id;size:255;not null;index"`
+	ReservedAt time.Time `gorm:"column:reserved_at;not null"`
+}
+
+// ShippingZone represents a destination zone matched by country, state and postal code patterns
+type ShippingZone struct {
+	ID                 string    `gorm:"primaryKey;column:id;size:255"`
+	Name               string    `gorm:"column:name;size:255;not null"`
+	Country            string    `gorm:"column:country;size:2;not null;index"`
+	State              string    `gorm:"column:state;size:100"`
+	PostalCodePatterns string    `gorm:"column:postal_code_patterns;type:jsonb"` // JSON array of patterns
+	Priority           int       `gorm:"column:priority;not null;default:0"`
+	IsActive           bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt          time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt          time.Time `gorm:"column:updated_at;not null"`
+}
+
+// ShippingZoneRate represents a shipping method and its cost within a zone
+type ShippingZoneRate struct {
+	ID               string `gorm:"primaryKey;column:id;size:255"`
+	ZoneID           string `gorm:"column:zone_id;size:255;not null;index"`
+	MethodID         string `gorm:"column:method_id;size:100;not null"`
+	MethodName       string `gorm:"column:method_name;size:255;not null"`
+	Carrier          string `gorm:"column:carrier;size:100"`
+	ServiceLevel     string `gorm:"column:service_level;size:100"`
+	Air              bool   `gorm:"column:air;not null;default:false"`
+	CostAmount       int64  `gorm:"column:cost_amount;not null"`
+	CostCurrency     string `gorm:"column:cost_currency;size:3;not null"`
+	EstimatedDaysMin int    `gorm:"column:estimated_days_min;not null;default:0"`
+	EstimatedDaysMax int    `gorm:"column:estimated_days_max;not null;default:0"`
+}
+
+// ShippingMethod represents a shipping method offered in a set of countries at a flat or
+// table rate, for destinations no shipping zone covers
+type ShippingMethod struct {
+	ID               string    `gorm:"primaryKey;column:id;size:100"`
+	Name             string    `gorm:"column:name;size:255;not null"`
+	Description      string    `gorm:"column:description;type:text"`
+	Carrier          string    `gorm:"column:carrier;size:100"`
+	ServiceLevel     string    `gorm:"column:service_level;size:100"`
+	Air              bool      `gorm:"column:air;not null;default:false"`
+	Countries        string    `gorm:"column:countries;type:jsonb"` // JSON array of country codes
+	Currency         string    `gorm:"column:currency;size:3;not null"`
+	FlatRate         int64     `gorm:"column:flat_rate;not null"`
+	RateTiers        string    `gorm:"column:rate_tiers;type:jsonb"` // JSON array of {min_subtotal, cost} in cents
+	FreeShippingMin  *int64    `gorm:"column:free_shipping_min"`
+	EstimatedDaysMin int       `gorm:"column:estimated_days_min;not null;default:0"`
+	EstimatedDaysMax int       `gorm:"column:estimated_days_max;not null;default:0"`
+	SortOrder        int       `gorm:"column:sort_order;not null;default:0"`
+	IsActive         bool      `gorm:"column:is_active;not null;default:true;index"`
+	CreatedAt        time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null"`
+}
+
+// OrderPayment represents one tender used to pay an order
+type OrderPayment struct {
+	ID               string    `gorm:"primaryKey;column:id;size:255"`
+	OrderID          string    `gorm:"column:order_id;size:255;not null;index"`
+	TenderType       string    `gorm:"column:tender_type;size:50;not null"`
+	PaymentMethodID  string    `gorm:"column:payment_method_id;size:255;not null"`
+	Amount           int64     `gorm:"column:amount;not null"`
+	CapturedAmount   int64     `gorm:"column:captured_amount;not null;default:0"`
+	RefundedAmount   int64     `gorm:"column:refunded_amount;not null;default:0"`
+	Currency         string    `gorm:"column:currency;size:3;not null"`
+	Status           string    `gorm:"column:status;size:50;not null"`
+	GatewayReference string    `gorm:"column:gateway_reference;size:255"`
+	FailureReason    string    `gorm:"column:failure_reason;type:text"`
+	CreatedAt        time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null"`
+}
+
+// OrderPaymentRetry tracks failed charges for an order awaiting payment
+type OrderPaymentRetry struct {
+	OrderID           string    `gorm:"primaryKey;column:order_id;size:255"`
+	State             string    `gorm:"column:state;size:50;not null"`
+	Failures          int       `gorm:"column:failures;not null;default:0"`
+	LastFailureReason string    `gorm:"column:last_failure_reason;type:text"`
+	FirstFailedAt     time.Time `gorm:"column:first_failed_at;not null"`
+	LastFailedAt      time.Time `gorm:"column:last_failed_at;not null"`
+	ExpiresAt         time.Time `gorm:"column:expires_at;not null"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;not null"`
+}
+
+// Dispute represents a chargeback raised against an order payment
+type Dispute struct {
+	ID                  string     `gorm:"primaryKey;column:id;size:255"`
+	OrderID             string     `gorm:"column:order_id;size:255;not null;index"`
+	TenderID            string     `gorm:"column:tender_id;size:255"`
+	GatewayDisputeID    string     `gorm:"column:gateway_dispute_id;size:255;not null;uniqueIndex"`
+	GatewayReference    string     `gorm:"column:gateway_reference;size:255"`
+	Amount              int64      `gorm:"column:amount;not null;default:0"`
+	Currency            string     `gorm:"column:currency;size:3"`
+	Reason              string     `gorm:"column:reason;size:255"`
+	Status              string     `gorm:"column:status;size:50;not null"`
+	EvidenceStatus      string     `gorm:"column:evidence_status;size:50;not null"`
+	EvidenceNotes       string     `gorm:"column:evidence_notes;type:text"`
+	EvidenceDueBy       *time.Time `gorm:"column:evidence_due_by"`
+	EvidenceSubmittedAt *time.Time `gorm:"column:evidence_submitted_at"`
+	CreatedAt           time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// OrderRefund represents money returned to the customer for an order
+type OrderRefund struct {
+	ID          string    `gorm:"primaryKey;column:id;size:255"`
+	OrderID     string    `gorm:"column:order_id;size:255;not null;index"`
+	Amount      int64     `gorm:"column:amount;not null"`
+	Currency    string    `gorm:"column:currency;size:3;not null"`
+	Reason      string    `gorm:"column:reason;size:50;not null"`
+	Note        string    `gorm:"column:note;type:text"`
+	Lines       string    `gorm:"column:lines;type:jsonb"`       // JSON array of refunded lines
+	Allocations string    `gorm:"column:allocations;type:jsonb"` // JSON array of per-tender amounts
+	CreatedBy   string    `gorm:"column:created_by;size:255;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null"`
+}
+
+// StoreCreditBalance holds a customer's store credit balance in one currency
+type StoreCreditBalance struct {
+	UserID    string    `gorm:"primaryKey;column:user_id;size:255"`
+	Currency  string    `gorm:"primaryKey;column:currency;size:3"`
+	Balance   int64     `gorm:"column:balance;not null;default:0"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// StoreCreditTransaction is an entry in a customer's store credit ledger
+type StoreCreditTransaction struct {
+	ID           string    `gorm:"primaryKey;column:id;size:255"`
+	UserID       string    `gorm:"column:user_id;size:255;not null;index"`
+	Type         string    `gorm:"column:type;size:20;not null"`
+	Amount       int64     `gorm:"column:amount;not null"` // negative for redemptions
+	Currency     string    `gorm:"column:currency;size:3;not null"`
+	BalanceAfter int64     `gorm:"column:balance_after;not null"`
+	Reason       string    `gorm:"column:reason;size:50"`
+	Note         string    `gorm:"column:note;type:text"`
+	OrderID      string    `gorm:"column:order_id;size:255"`
+	CreatedBy    string    `gorm:"column:created_by;size:255"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null"`
+}
+
+// ContentPage represents a CMS page such as About, FAQ or a store policy
+type ContentPage struct {
+	ID              string     `gorm:"primaryKey;column:id;size:255"`
+	Slug            string     `gorm:"column:slug;size:255;not null;uniqueIndex"`
+	Title           string     `gorm:"column:title;size:255;not null"`
+	Body            string     `gorm:"column:body;type:text"`
+	Format          string     `gorm:"column:format;size:20;not null"`
+	MetaDescription string     `gorm:"column:meta_description;size:500"`
+	Status          string     `gorm:"column:status;size:20;not null;index"`
+	PublishedAt     *time.Time `gorm:"column:published_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// Placement represents a scheduled banner or promo tile in a storefront slot
+type Placement struct {
+	ID        string     `gorm:"primaryKey;column:id;size:255"`
+	Slot      string     `gorm:"column:slot;size:100;not null;index"`
+	Title     string     `gorm:"column:title;size:255;not null"`
+	Subtitle  string     `gorm:"column:subtitle;size:500"`
+	ImageURL  string     `gorm:"column:image_url;size:1000;not null"`
+	ImageAlt  string     `gorm:"column:image_alt;size:255"`
+	TargetURL string     `gorm:"column:target_url;size:1000;not null"`
+	Position  int        `gorm:"column:position;not null;default:0"`
+	IsActive  bool       `gorm:"column:is_active;not null;default:true"`
+	StartsAt  *time.Time `gorm:"column:starts_at"`
+	EndsAt    *time.Time `gorm:"column:ends_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// APIKey represents a scoped credential issued to an external integration
+type APIKey struct {
+	ID         string     `gorm:"primaryKey;column:id;size:255"`
+	Name       string     `gorm:"column:name;size:255;not null"`
+	Prefix     string     `gorm:"column:prefix;size:20;not null"`
+	KeyHash    string     `gorm:"column:key_hash;size:64;not null;uniqueIndex"`
+	Scopes     string     `gorm:"column:scopes;size:500;not null"` // comma separated
+	QuotaTier  string     `gorm:"column:quota_tier;size:50;not null;default:''"`
+	CreatedBy  string     `gorm:"column:created_by;size:255"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null"`
+}
+
+// OrderShipment represents a shipment confirmed by a fulfillment partner
+type OrderShipment struct {
+	ID             string    `gorm:"primaryKey;column:id;size:255"`
+	OrderID        string    `gorm:"column:order_id;size:255;not null;index"`
+	Carrier        string    `gorm:"column:carrier;size:100;not null"`
+	TrackingNumber string    `gorm:"column:tracking_number;size:255;not null"`
+	TrackingURL    string    `gorm:"column:tracking_url;size:1000"`
+	ShippedAt      time.Time `gorm:"column:shipped_at;not null"`
+	APIKeyID       string    `gorm:"column:api_key_id;size:255"`
+	CreatedAt      time.Time `gorm:"column:created_at;not null"`
+}
+
+// POSSale records the register and terminal an in-store order was rung up on
+type POSSale struct {
+	OrderID    string    `gorm:"primaryKey;column:order_id;size:255"`
+	RegisterID string    `gorm:"column:register_id;size:100;not null"`
+	TerminalID string    `gorm:"column:terminal_id;size:100"`
+	APIKeyID   string    `gorm:"column:api_key_id;size:255"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// OrderAttribution records the channel an order came through and its UTM campaign parameters
+type OrderAttribution struct {
+	OrderID     string    `gorm:"primaryKey;column:order_id;size:255"`
+	Channel     string    `gorm:"column:channel;size:20;not null"`
+	UTMSource   string    `gorm:"column:utm_source;size:255"`
+	UTMMedium   string    `gorm:"column:utm_medium;size:255"`
+	UTMCampaign string    `gorm:"column:utm_campaign;size:255"`
+	UTMTerm     string    `gorm:"column:utm_term;size:255"`
+	UTMContent  string    `gorm:"column:utm_content;size:255"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null"`
+}
+
+// RecentlyViewedProduct records when a user, or a guest session, last viewed a product
+type RecentlyViewedProduct struct {
+	UserID    string    `gorm:"primaryKey;column:user_id;size:255"`
+	ProductID string    `gorm:"primaryKey;column:product_id;size:255"`
+	ViewedAt  time.Time `gorm:"column:viewed_at;not null"`
+}
+
+// Customer is a customer's storefront profile, linked to their goauthx user
+type Customer struct {
+	UserID    string    `gorm:"primaryKey;column:user_id;size:255"`
+	FirstName string    `gorm:"column:first_name;size:100"`
+	LastName  string    `gorm:"column:last_name;size:100"`
+	Phone     string    `gorm:"column:phone;size:20"`
+	Birthday  string    `gorm:"column:birthday;size:10"` // YYYY-MM-DD
+	AvatarKey string    `gorm:"column:avatar_key;size:500"`
+	AvatarURL string    `gorm:"column:avatar_url;size:1000"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// Company is a business account whose members buy on its behalf
+type Company struct {
+	ID                        string    `gorm:"primaryKey;column:id;size:255"`
+	Name                      string    `gorm:"column:name;size:255;not null"`
+	ApprovalThresholdAmount   *int64    `gorm:"column:approval_threshold_amount"` // nil when no order needs approval
+	ApprovalThresholdCurrency string    `gorm:"column:approval_threshold_currency;size:3"`
+	NetTermsDays              int       `gorm:"column:net_terms_days;not null;default:0"` // 0 when the company can't pay by invoice
+	CreatedAt                 time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt                 time.Time `gorm:"column:updated_at;not null"`
+}
+
+// CompanyMember links a user to their company with a buyer or approver role
+type CompanyMember struct {
+	UserID    string    `gorm:"primaryKey;column:user_id;size:255"`
+	CompanyID string    `gorm:"column:company_id;size:255;not null;index"`
+	Role      string    `gorm:"column:role;size:20;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// CompanyAddress is an entry in a company's shared address book
+type CompanyAddress struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	CompanyID  string    `gorm:"column:company_id;size:255;not null;index"`
+	Label      string    `gorm:"column:label;size:100"`
+	FirstName  string    `gorm:"column:first_name;size:255;not null"`
+	LastName   string    `gorm:"column:last_name;size:255;not null"`
+	Company    string    `gorm:"column:company;size:255"`
+	Address1   string    `gorm:"column:address1;size:255;not null"`
+	Address2   string    `gorm:"column:address2;size:255"`
+	City       string    `gorm:"column:city;size:255;not null"`
+	State      string    `gorm:"column:state;size:255;not null"`
+	PostalCode string    `gorm:"column:postal_code;size:20;not null"`
+	Country    string    `gorm:"column:country;size:100;not null"`
+	Phone      string    `gorm:"column:phone;size:50"`
+	CreatedBy  string    `gorm:"column:created_by;size:255;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;not null"`
+}
+
+// CompanyOrder records an order placed for a company and its approval
+type CompanyOrder struct {
+	OrderID        string     `gorm:"primaryKey;column:order_id;size:255"`
+	CompanyID      string     `gorm:"column:company_id;size:255;not null"`
+	PlacedBy       string     `gorm:"column:placed_by;size:255;not null"`
+	TotalAmount    int64      `gorm:"column:total_amount;not null"`
+	TotalCurrency  string     `gorm:"column:total_currency;size:3;not null"`
+	ApprovalStatus string     `gorm:"column:approval_status;size:20;not null"`
+	DecidedBy      string     `gorm:"column:decided_by;size:255"`
+	DecidedAt      *time.Time `gorm:"column:decided_at"`
+	Reason         string     `gorm:"column:reason;type:text"`
+	CreatedAt      time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// Quote is a buyer's request for negotiated prices on their cart's items
+type Quote struct {
+	ID             string     `gorm:"primaryKey;column:id;size:255"`
+	UserID         string     `gorm:"column:user_id;size:255;not null;index"`
+	Status         string     `gorm:"column:status;size:20;not null"`
+	SubtotalAmount int64      `gorm:"column:subtotal_amount;not null"`
+	Currency       string     `gorm:"column:currency;size:3;not null"`
+	Note           string     `gorm:"column:note;type:text"`
+	SalesNote      string     `gorm:"column:sales_note;type:text"`
+	ValidUntil     *time.Time `gorm:"column:valid_until"`
+	SentBy         string     `gorm:"column:sent_by;size:255"`
+	SentAt         *time.Time `gorm:"column:sent_at"`
+	OrderID        string     `gorm:"column:order_id;size:255"`
+	AcceptedAt     *time.Time `gorm:"column:accepted_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// QuoteItem is a cart line on a quote with its list and quoted unit prices
+type QuoteItem struct {
+	ID                string  `gorm:"primaryKey;column:id;size:255"`
+	QuoteID           string  `gorm:"column:quote_id;size:255;not null;index"`
+	Position          int     `gorm:"column:position;not null"`
+	ProductID         string  `gorm:"column:product_id;size:255;not null"`
+	VariantID         *string `gorm:"column:variant_id;size:255"`
+	SKU               string  `gorm:"column:sku;size:255;not null"`
+	Name              string  `gorm:"column:name;size:255;not null"`
+	Quantity          int     `gorm:"column:quantity;not null"`
+	ListPriceAmount   int64   `gorm:"column:list_price_amount;not null"`
+	QuotedPriceAmount int64   `gorm:"column:quoted_price_amount;not null"`
+	Currency          string  `gorm:"column:currency;size:3;not null"`
+	Attributes        string  `gorm:"column:attributes;type:jsonb"`
+}
+
+// Invoice is the receivable for an order a company pays on net terms
+type Invoice struct {
+	ID           string     `gorm:"primaryKey;column:id;size:255"`
+	Number       string     `gorm:"column:number;size:50;not null;uniqueIndex"`
+	OrderID      string     `gorm:"column:order_id;size:255;not null;uniqueIndex"`
+	CompanyID    string     `gorm:"column:company_id;size:255;not null;index"`
+	PlacedBy     string     `gorm:"column:placed_by;size:255;not null"`
+	Amount       int64      `gorm:"column:amount;not null"`
+	AmountPaid   int64      `gorm:"column:amount_paid;not null"`
+	Currency     string     `gorm:"column:currency;size:3;not null"`
+	Status       string     `gorm:"column:status;size:20;not null"`
+	NetTermsDays int        `gorm:"column:net_terms_days;not null"`
+	IssuedAt     time.Time  `gorm:"column:issued_at;not null"`
+	DueAt        time.Time  `gorm:"column:due_at;not null"`
+	PaidAt       *time.Time `gorm:"column:paid_at"`
+	VoidedAt     *time.Time `gorm:"column:voided_at"`
+	VoidReason   string     `gorm:"column:void_reason;type:text"`
+	CreatedAt    time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// InvoicePayment is a payment received against an invoice
+type InvoicePayment struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	InvoiceID  string    `gorm:"column:invoice_id;size:255;not null;index"`
+	Amount     int64     `gorm:"column:amount;not null"`
+	Currency   string    `gorm:"column:currency;size:3;not null"`
+	Reference  string    `gorm:"column:reference;size:255"`
+	ReceivedAt time.Time `gorm:"column:received_at;not null"`
+	RecordedBy string    `gorm:"column:recorded_by;size:255;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// CheckoutRule represents a condition a cart must meet to be checked out
+type CheckoutRule struct {
+	ID               string    `gorm:"primaryKey;column:id;size:255"`
+	Name             string    `gorm:"column:name;size:255;not null"`
+	Type             string    `gorm:"column:type;size:30;not null"`
+	ProductID        string    `gorm:"column:product_id;size:255"`
+	MinOrderAmount   *int64    `gorm:"column:min_order_amount"`
+	MinOrderCurrency string    `gorm:"column:min_order_currency;size:3"`
+	Countries        string    `gorm:"column:countries;type:jsonb"` // JSON array of country codes
+	Multiple         int       `gorm:"column:multiple;not null;default:0"`
+	IsActive         bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt        time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt        time.Time `gorm:"column:updated_at;not null"`
+}
+
+// ShippingRestriction represents how a hazardous or regulated product may be shipped
+type ShippingRestriction struct {
+	ProductID string    `gorm:"primaryKey;column:product_id;size:255"`
+	NoAir     bool      `gorm:"column:no_air;not null;default:false"`
+	Carriers  string    `gorm:"column:carriers;type:jsonb"` // JSON array of allowed carriers
+	NoPOBox   bool      `gorm:"column:no_po_box;not null;default:false"`
+	MinAge    int       `gorm:"column:min_age;not null;default:0"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// RentalProduct represents a product in rental mode, rented by the day
+type RentalProduct struct {
+	ProductID  string    `gorm:"primaryKey;column:product_id;size:255"`
+	Units      int       `gorm:"column:units;not null"`
+	SKUUnits   string    `gorm:"column:sku_units;type:jsonb"` // JSON object of units by SKU
+	MinDays    int       `gorm:"column:min_days;not null;default:1"`
+	MaxDays    int       `gorm:"column:max_days;not null;default:0"`
+	BufferDays int       `gorm:"column:buffer_days;not null;default:0"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;not null"`
+}
+
+// RentalBooking represents units of a SKU an order holds for its rental period
+type RentalBooking struct {
+	ID          string    `gorm:"primaryKey;column:id;size:255"`
+	ProductID   string    `gorm:"column:product_id;size:255;not null"`
+	SKU         string    `gorm:"column:sku;size:255;not null;index:idx_rental_bookings_sku_dates"`
+	OrderID     string    `gorm:"column:order_id;size:255;not null;index"`
+	OrderItemID string    `gorm:"column:order_item_id;size:255"`
+	Quantity    int       `gorm:"column:quantity;not null"`
+	StartDate   time.Time `gorm:"column:start_date;type:date;not null;index:idx_rental_bookings_sku_dates"`
+	EndDate     time.Time `gorm:"column:end_date;type:date;not null;index:idx_rental_bookings_sku_dates"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null"`
+}
+
+// AppointmentProduct represents a service product booked into time slots
+type AppointmentProduct struct {
+	ProductID         string    `gorm:"primaryKey;column:product_id;size:255"`
+	CancelWindowHours int       `gorm:"column:cancel_window_hours;not null;default:0"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;not null"`
+}
+
+// AppointmentSlot represents a time slot of an appointment product with a number of seats
+type AppointmentSlot struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	ProductID string    `gorm:"column:product_id;size:255;not null;index:idx_appointment_slots_product_starts"`
+	StartsAt  time.Time `gorm:"column:starts_at;not null;index:idx_appointment_slots_product_starts"`
+	EndsAt    time.Time `gorm:"column:ends_at;not null"`
+	Capacity  int       `gorm:"column:capacity;not null"`
+	Booked    int       `gorm:"column:booked;not null;default:0"`
+	IsActive  bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// AppointmentBooking represents seats an order holds in an appointment slot
+type AppointmentBooking struct {
+	ID          string     `gorm:"primaryKey;column:id;size:255"`
+	SlotID      string     `gorm:"column:slot_id;size:255;not null;index"`
+	ProductID   string     `gorm:"column:product_id;size:255;not null"`
+	OrderID     string     `gorm:"column:order_id;size:255;not null;index"`
+	OrderItemID string     `gorm:"column:order_item_id;size:255"`
+	UserID      string     `gorm:"column:user_id;size:255;not null"`
+	Quantity    int        `gorm:"column:quantity;not null"`
+	StartsAt    time.Time  `gorm:"column:starts_at;not null"`
+	EndsAt      time.Time  `gorm:"column:ends_at;not null"`
+	CancelBy    time.Time  `gorm:"column:cancel_by;not null"`
+	Status      string     `gorm:"column:status;size:20;not null"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null"`
+	CanceledAt  *time.Time `gorm:"column:canceled_at"`
+}
+
+// DonationProduct represents a product the customer sets the price of within limits
+type DonationProduct struct {
+	ProductID string    `gorm:"primaryKey;column:product_id;size:255"`
+	MinAmount int64     `gorm:"column:min_amount;not null"`
+	MaxAmount *int64    `gorm:"column:max_amount"`
+	Currency  string    `gorm:"column:currency;size:3;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// OrderBump represents an add-on offered at checkout at a special price
+type OrderBump struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	ProductID string    `gorm:"column:product_id;size:255;not null"`
+	Headline  string    `gorm:"column:headline;size:255;not null"`
+	Price     int64     `gorm:"column:price;not null"`
+	Currency  string    `gorm:"column:currency;size:3;not null"`
+	Position  int       `gorm:"column:position;not null;default:0"`
+	IsActive  bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// Wishlist represents a customer's wishlist; public ones are shared as gift registries
+type Wishlist struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	UserID     string    `gorm:"column:user_id;size:255;not null;index"`
+	Name       string    `gorm:"column:name;size:255;not null"`
+	IsPublic   bool      `gorm:"column:is_public;not null;default:false"`
+	ShareToken *string   `gorm:"column:share_token;size:64;uniqueIndex"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;not null"`
+}
+
+// WishlistItem represents a product on a wishlist with the units wanted and bought
+type WishlistItem struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	WishlistID string    `gorm:"column:wishlist_id;size:255;not null;index"`
+	ProductID  string    `gorm:"column:product_id;size:255;not null"`
+	VariantID  *string   `gorm:"column:variant_id;size:255"`
+	Quantity   int       `gorm:"column:quantity;not null"`
+	Purchased  int       `gorm:"column:purchased;not null;default:0"`
+	Note       string    `gorm:"column:note;type:text"`
+	AddedAt    time.Time `gorm:"column:added_at;not null"`
+}
+
+// WishlistPurchase represents units of a registry item an order bought
+type WishlistPurchase struct {
+	ID          string     `gorm:"primaryKey;column:id;size:255"`
+	WishlistID  string     `gorm:"column:wishlist_id;size:255;not null"`
+	ItemID      string     `gorm:"column:item_id;size:255;not null;index"`
+	OrderID     string     `gorm:"column:order_id;size:255;not null;index"`
+	OrderItemID string     `gorm:"column:order_item_id;size:255"`
+	BuyerID     string     `gorm:"column:buyer_id;size:255;not null"`
+	Quantity    int        `gorm:"column:quantity;not null"`
+	Status      string     `gorm:"column:status;size:20;not null"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null"`
+	CanceledAt  *time.Time `gorm:"column:canceled_at"`
+}
+
+// SupportTicket represents an issue a customer opened against an order
+type SupportTicket struct {
+	ID        string     `gorm:"primaryKey;column:id;size:255"`
+	OrderID   string     `gorm:"column:order_id;size:255;not null;index"`
+	UserID    string     `gorm:"column:user_id;size:255;not null;index"`
+	Subject   string     `gorm:"column:subject;size:255;not null"`
+	Category  string     `gorm:"column:category;size:30;not null"`
+	Status    string     `gorm:"column:status;size:20;not null;index:idx_support_tickets_status_updated"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;not null;index:idx_support_tickets_status_updated"`
+	ClosedAt  *time.Time `gorm:"column:closed_at"`
+}
+
+// SupportTicketMessage represents a message on a support ticket
+type SupportTicketMessage struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	TicketID  string    `gorm:"column:ticket_id;size:255;not null;index"`
+	AuthorID  string    `gorm:"column:author_id;size:255;not null"`
+	Staff     bool      `gorm:"column:staff;not null;default:false"`
+	Body      string    `gorm:"column:body;type:text;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+// ProductReview represents a customer's review of a product and its moderation
+type ProductReview struct {
+	ID             string     `gorm:"primaryKey;column:id;size:255"`
+	ProductID      string     `gorm:"column:product_id;size:255;not null;uniqueIndex:idx_product_reviews_product_user"`
+	UserID         string     `gorm:"column:user_id;size:255;not null;uniqueIndex:idx_product_reviews_product_user;index"`
+	Rating         int        `gorm:"column:rating;not null"`
+	Title          string     `gorm:"column:title;size:255"`
+	Body           string     `gorm:"column:body;type:text;not null"`
+	Status         string     `gorm:"column:status;size:20;not null;index"`
+	Filtered       bool       `gorm:"column:filtered;not null;default:false"`
+	Hidden         bool       `gorm:"column:hidden;not null;default:false"`
+	ModerationNote string     `gorm:"column:moderation_note;type:text"`
+	ModeratedBy    string     `gorm:"column:moderated_by;size:255"`
+	ModeratedAt    *time.Time `gorm:"column:moderated_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;not null"`
+}
+
+// ProductReviewPhoto represents a photo attached to a review, stored in media storage
+type ProductReviewPhoto struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	ReviewID  string    `gorm:"column:review_id;size:255;not null;index"`
+	MediaKey  string    `gorm:"column:media_key;size:500;not null"`
+	URL       string    `gorm:"column:url;size:1000;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+// ProductQuestion represents a customer's question about a product
+type ProductQuestion struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	ProductID string    `gorm:"column:product_id;size:255;not null;index"`
+	UserID    string    `gorm:"column:user_id;size:255;not null"`
+	Body      string    `gorm:"column:body;type:text;not null"`
+	Filtered  bool      `gorm:"column:filtered;not null;default:false"`
+	Hidden    bool      `gorm:"column:hidden;not null;default:false"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// ProductAnswer represents an answer to a product question
+type ProductAnswer struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	QuestionID string    `gorm:"column:question_id;size:255;not null;index"`
+	UserID     string    `gorm:"column:user_id;size:255;not null"`
+	Staff      bool      `gorm:"column:staff;not null;default:false"`
+	Body       string    `gorm:"column:body;type:text;not null"`
+	Filtered   bool      `gorm:"column:filtered;not null;default:false"`
+	Hidden     bool      `gorm:"column:hidden;not null;default:false"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// ContentReport represents a user reporting a review, question or answer as abusive
+type ContentReport struct {
+	ID          string     `gorm:"primaryKey;column:id;size:255"`
+	ContentType string     `gorm:"column:content_type;size:20;not null;uniqueIndex:idx_content_reports_content_user"`
+	ContentID   string     `gorm:"column:content_id;size:255;not null;uniqueIndex:idx_content_reports_content_user"`
+	UserID      string     `gorm:"column:user_id;size:255;not null;uniqueIndex:idx_content_reports_content_user"`
+	Reason      string     `gorm:"column:reason;size:20;not null"`
+	Details     string     `gorm:"column:details;type:text"`
+	Status      string     `gorm:"column:status;size:20;not null;index"`
+	ResolvedBy  string     `gorm:"column:resolved_by;size:255"`
+	ResolvedAt  *time.Time `gorm:"column:resolved_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null"`
+}
+
+// PricingAnomaly represents a suspicious price change or order held for admin review
+type PricingAnomaly struct {
+	ID         string     `gorm:"primaryKey;column:id;size:255"`
+	Kind       string     `gorm:"column:kind;size:20;not null"`
+	Reason     string     `gorm:"column:reason;size:30;not null"`
+	Status     string     `gorm:"column:status;size:20;not null"`
+	ProductID  string     `gorm:"column:product_id;size:255"`
+	VariantID  string     `gorm:"column:variant_id;size:255"`
+	OrderID    string     `gorm:"column:order_id;size:255"`
+	Currency   string     `gorm:"column:currency;size:3"`
+	OldPrice   *int64     `gorm:"column:old_price_amount"`
+	NewPrice   *int64     `gorm:"column:new_price_amount"`
+	OrderTotal *int64     `gorm:"column:order_total_amount"`
+	ItemValue  *int64     `gorm:"column:item_value_amount"`
+	Message    string     `gorm:"column:message;type:text;not null"`
+	Change     string     `gorm:"column:change;type:jsonb"` // the held product or variant
+	ReviewedBy string     `gorm:"column:reviewed_by;size:255"`
+	ReviewedAt *time.Time `gorm:"column:reviewed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null"`
+}
+
+// CategoryProductCount is the maintained count of active products in a category
+type CategoryProductCount struct {
+	CategoryID   string    `gorm:"primaryKey;column:category_id;size:255"`
+	ProductCount int64     `gorm:"column:product_count;not null;default:0"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;not null"`
+}
+
+// SearchTerm is a word from the catalog's names that misspelled searches are corrected to
+type SearchTerm struct {
+	Term string `gorm:"primaryKey;column:term;size:100"`
+}
+
+// SynonymSet represents a group of search terms that mean the same thing
+type SynonymSet struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	Terms     string    `gorm:"column:terms;type:jsonb;not null"` // JSON array of lower-case terms
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+}
+
+// SearchRule represents the pinned and boosted products for one search query
+type SearchRule struct {
+	ID                string    `gorm:"primaryKey;column:id;size:255"`
+	Query             string    `gorm:"column:query;size:255;not null;uniqueIndex:idx_search_rules_query"`
+	PinnedProductIDs  string    `gorm:"column:pinned_product_ids;type:jsonb"`  // JSON array of product IDs, in order
+	BoostedProductIDs string    `gorm:"column:boosted_product_ids;type:jsonb"` // JSON array of product IDs
+	IsActive          bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt         time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;not null"`
+}
+
+// ProductMedia represents one image, video or 3D model in a product's gallery
+type ProductMedia struct {
+	ProductID string `gorm:"primaryKey;column:product_id;size:255"`
+	Position  int    `gorm:"primaryKey;column:position"`
+	Type      string `gorm:"column:type;size:20;not null"`
+	URL       string `gorm:"column:url;type:text;not null"`
+	PosterURL string `gorm:"column:poster_url;type:text"`
+	Alt       string `gorm:"column:alt;size:500"`
+	MimeType  string `gorm:"column:mime_type;size:100"`
+}
+
+// DeadLetter represents a background job that failed, kept for replay
+type DeadLetter struct {
+	ID         string     `gorm:"primaryKey;column:id;size:255"`
+	Kind       string     `gorm:"column:kind;size:100;not null"`
+	Name       string     `gorm:"column:name;size:255;not null"`
+	Payload    string     `gorm:"column:payload;type:jsonb;not null"`
+	Status     string     `gorm:"column:status;size:50;not null;index"`
+	Attempts   int        `gorm:"column:attempts;not null;default:1"`
+	LastError  string     `gorm:"column:last_error;type:text"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null"`
+	FailedAt   time.Time  `gorm:"column:failed_at;not null"`
+	ReplayedAt *time.Time `gorm:"column:replayed_at"`
+}
+
+// ConfigChange represents one runtime setting changed by a configuration reload
+type ConfigChange struct {
+	ID        string    `gorm:"primaryKey;column:id;size:255"`
+	Setting   string    `gorm:"column:setting;size:100;not null"`
+	OldValue  string    `gorm:"column:old_value;type:text;not null"`
+	NewValue  string    `gorm:"column:new_value;type:text;not null"`
+	Source    string    `gorm:"column:source;size:20;not null"`
+	ActorID   *string   `gorm:"column:actor_id;size:255"`
+	ChangedAt time.Time `gorm:"column:changed_at;not null"`
+}
+
+// QuotaCounter represents the requests an API key or user made in one day or month
+type QuotaCounter struct {
+	Subject     string    `gorm:"primaryKey;column:subject;size:255"`
+	Period      string    `gorm:"primaryKey;column:period;size:10"`
+	PeriodStart time.Time `gorm:"primaryKey;column:period_start"`
+	Tier        string    `gorm:"column:tier;size:50;not null"`
+	Requests    int64     `gorm:"column:requests;not null;default:0"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null"`
+}
+
+// FunnelEvent represents a cart or order reaching a checkout funnel stage. Each subject
+// is counted once per stage.
+type FunnelEvent struct {
+	Stage      string    `gorm:"primaryKey;column:stage;size:30"`
+	Subject    string    `gorm:"primaryKey;column:subject;size:255"`
+	OccurredAt time.Time `gorm:"column:occurred_at;not null;index"`
+}
+
+// FunnelAlert represents a checkout funnel step converting far below its baseline
+type FunnelAlert struct {
+	ID           string    `gorm:"primaryKey;column:id;size:255"`
+	FromStage    string    `gorm:"column:from_stage;size:30;not null"`
+	ToStage      string    `gorm:"column:to_stage;size:30;not null"`
+	Entered      int64     `gorm:"column:entered;not null"`
+	Converted    int64     `gorm:"column:converted;not null"`
+	Rate         float64   `gorm:"column:rate;type:numeric(7,4);not null"`
+	BaselineRate float64   `gorm:"column:baseline_rate;type:numeric(7,4);not null"`
+	WindowStart  time.Time `gorm:"column:window_start;not null"`
+	WindowEnd    time.Time `gorm:"column:window_end;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;index"`
+}
+
+// WebhookEvent represents an inbound webhook stored for processing and replay
+type WebhookEvent struct {
+	ID          string     `gorm:"primaryKey;column:id;size:255"`
+	Provider    string     `gorm:"column:provider;size:100;not null"`
+	ExternalID  string     `gorm:"column:external_id;size:255"`
+	EventType   string     `gorm:"column:event_type;size:255"`
+	Payload     string     `gorm:"column:payload;type:jsonb;not null"`
+	Status      string     `gorm:"column:status;size:50;not null;index"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0"`
+	LastError   string     `gorm:"column:last_error;type:text"`
+	ReceivedAt  time.Time  `gorm:"column:received_at;not null"`
+	ProcessedAt *time.Time `gorm:"column:processed_at"`
+}
+
+// CatalogVersion represents one recorded change to a product or variant
+type CatalogVersion struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	ProductID  string    `gorm:"column:product_id;size:255;not null"`
+	Version    int       `gorm:"column:version;not null"`
+	EntityType string    `gorm:"column:entity_type;size:20;not null"`
+	EntityID   string    `gorm:"column:entity_id;size:255;not null"`
+	Action     string    `gorm:"column:action;size:20;not null"`
+	ChangedBy  string    `gorm:"column:changed_by;size:255"`
+	Changes    string    `gorm:"column:changes;type:jsonb;not null"`
+	Before     string    `gorm:"column:before_state;type:jsonb"`
+	After      string    `gorm:"column:after_state;type:jsonb"`
+	RevertedTo *int      `gorm:"column:reverted_to"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// OrderEvent represents one entry on an order's timeline
+type OrderEvent struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	OrderID    string    `gorm:"column:order_id;size:255;not null"`
+	Type       string    `gorm:"column:type;size:50;not null"`
+	Message    string    `gorm:"column:message;size:500;not null"`
+	FromStatus string    `gorm:"column:from_status;size:50"`
+	Status     string    `gorm:"column:status;size:50"`
+	Amount     *int64    `gorm:"column:amount"`
+	Currency   string    `gorm:"column:currency;size:3"`
+	Details    *string   `gorm:"column:details;type:jsonb"`
+	Internal   bool      `gorm:"column:internal;not null;default:false"`
+	CreatedBy  string    `gorm:"column:created_by;size:255"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// OrderStatusEvent represents one status change staff made to an order
+type OrderStatusEvent struct {
+	ID         string    `gorm:"primaryKey;column:id;size:255"`
+	OrderID    string    `gorm:"column:order_id;size:255;not null;index"`
+	FromStatus string    `gorm:"column:from_status;size:50;not null"`
+	ToStatus   string    `gorm:"column:to_status;size:50;not null"`
+	Reason     string    `gorm:"column:reason;type:text"`
+	ChangedBy  string    `gorm:"column:changed_by;size:255"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+// BusinessCalendar represents the store's working days and order cutoff; there is one row
+type BusinessCalendar struct {
+	ID          string    `gorm:"primaryKey;column:id;size:50"`
+	Timezone    string    `gorm:"column:timezone;size:64;not null"`
+	WorkingDays string    `gorm:"column:working_days;size:20;not null"` // comma-separated weekdays, 0 is Sunday
+	CutoffTime  string    `gorm:"column:cutoff_time;size:5;not null"`   // HH:MM
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null"`
+}
+
+// CalendarHoliday represents a day the business is closed
+type CalendarHoliday struct {
+	Date string `gorm:"primaryKey;column:date;size:10"` // YYYY-MM-DD
+	Name string `gorm:"column:name;size:255;not null"`
+}
+
+// PurchaseLimit represents the most units of a product a customer may buy
+type PurchaseLimit struct {
+	ProductID      string    `gorm:"primaryKey;column:product_id;size:255"`
+	MaxPerOrder    int       `gorm:"column:max_per_order;not null;default:0"`
+	MaxPerCustomer int       `gorm:"column:max_per_customer;not null;default:0"`
+	PeriodDays     int       `gorm:"column:period_days;not null;default:0"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;not null"`
+}
+
+// WaitingRoomTicket represents a customer's place in the queue for a limited drop
+type WaitingRoomTicket struct {
+	ProductID  string     `gorm:"primaryKey;column:product_id;size:255"`
+	UserID     string     `gorm:"primaryKey;column:user_id;size:255"`
+	Token      string     `gorm:"column:token;size:64;not null;uniqueIndex"`
+	Status     string     `gorm:"column:status;size:20;not null"`
+	JoinedAt   time.Time  `gorm:"column:joined_at;not null"`
+	AdmittedAt *time.Time `gorm:"column:admitted_at"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+}
+
+// LoginLockout represents the failed login count and lockout of an email address
+type LoginLockout struct {
+	Email          string     `gorm:"primaryKey;column:email;size:255"`
+	FailedAttempts int        `gorm:"column:failed_attempts;not null;default:0"`
+	Lockouts       int        `gorm:"column:lockouts;not null;default:0"`
+	LockedUntil    *time.Time `gorm:"column:locked_until"`
+	LastFailedAt   time.Time  `gorm:"column:last_failed_at;not null"`
+}
+
+// LoginDevice represents a device a user has signed in from
+type LoginDevice struct {
+	UserID      string    `gorm:"primaryKey;column:user_id;size:255"`
+	Fingerprint string    `gorm:"primaryKey;column:fingerprint;size:64"`
+	UserAgent   string    `gorm:"column:user_agent;size:512"`
+	IPAddress   string    `gorm:"column:ip_address;size:45"`
+	FirstSeenAt time.Time `gorm:"column:first_seen_at;not null"`
+	LastSeenAt  time.Time `gorm:"column:last_seen_at;not null"`
+}
+
+// ConsentEvent represents one consent choice a user made; the latest per purpose applies
+type ConsentEvent struct {
+	ID            string    `gorm:"primaryKey;column:id;size:255"`
+	UserID        string    `gorm:"column:user_id;size:255;not null"`
+	Purpose       string    `gorm:"column:purpose;size:50;not null"`
+	Granted       bool      `gorm:"column:granted;not null"`
+	PolicyVersion string    `gorm:"column:policy_version;size:50;not null"`
+	Source        string    `gorm:"column:source;size:50"`
+	RecordedAt    time.Time `gorm:"column:recorded_at;not null"`
+}
+
+// ProductTag represents one tag on a product
+type ProductTag struct {
+	ProductID string `gorm:"primaryKey;column:product_id;size:255"`
+	Tag       string `gorm:"primaryKey;column:tag;size:100"`
+}
+
+// Collection represents a curated product collection, either hand-picked or rule-based
+type Collection struct {
+	ID          string    `gorm:"primaryKey;column:id;size:255"`
+	Slug        string    `gorm:"column:slug;size:255;not null;uniqueIndex"`
+	Title       string    `gorm:"column:title;size:255;not null"`
+	Description string    `gorm:"column:description;type:text"`
+	Type        string    `gorm:"column:type;size:20;not null"`
+	Rule        string    `gorm:"column:rule;type:text"`
+	Status      string    `gorm:"column:status;size:20;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null"`
+}
+
+// CollectionProduct represents a product placed in a manual collection
+type CollectionProduct struct {
+	CollectionID string `gorm:"primaryKey;column:collection_id;size:255"`
+	ProductID    string `gorm:"primaryKey;column:product_id;size:255"`
+	Position     int    `gorm:"column:position;not null"`
+}
+
+// InventoryLevel represents a SKU's current stock, from gocommerce's inventory_levels table
+type InventoryLevel struct {
+	ID                string `gorm:"primaryKey;column:id;size:255"`
+	SKU               string `gorm:"column:sku;size:100;not null"`
+	QuantityOnHand    int    `gorm:"column:quantity_on_hand;not null"`
+	QuantityReserved  int    `gorm:"column:quantity_reserved;not null"`
+	QuantityAvailable int    `gorm:"column:quantity_available;->"` // generated column
+	IsActive          bool   `gorm:"column:is_active;not null"`
+}
+
+// InventoryActivity represents one stock movement in gocommerce's inventory_activities log
+type InventoryActivity struct {
+	ID             string    `gorm:"primaryKey;column:id;size:255"`
+	SKU            string    `gorm:"column:sku;size:100;not null"`
+	ActivityType   string    `gorm:"column:activity_type;not null"`
+	Quantity       int       `gorm:"column:quantity;not null"`
+	QuantityBefore int       `gorm:"column:quantity_before;not null"`
+	QuantityAfter  int       `gorm:"column:quantity_after;not null"`
+	ReferenceID    string    `gorm:"column:reference_id;size:255"`
+	Reason         string    `gorm:"column:reason;type:text"`
+	CreatedAt      time.Time `gorm:"column:created_at;not null"`
+}
+
+// InventorySnapshot represents a SKU's stock level recorded by the daily snapshot
+type InventorySnapshot struct {
+	SKU          string    `gorm:"primaryKey;column:sku;size:100"`
+	SnapshotDate string    `gorm:"primaryKey;column:snapshot_date;size:10"` // YYYY-MM-DD
+	OnHand       int       `gorm:"column:on_hand;not null"`
+	Reserved     int       `gorm:"column:reserved;not null"`
+	Available    int       `gorm:"column:available;not null"`
+	TakenAt      time.Time `gorm:"column:taken_at;not null"`
+}
+
 // Helper functions to convert between domain and database models
 
 // MoneyToInt64 converts money.Money to int64 cents
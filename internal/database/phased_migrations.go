@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/devchuckcamp/gocommerce/migrations"
+)
+
+// SchemaChange is a breaking schema change split into two migrations so old and new code
+// can share the database during a rolling deploy:
+//
+//   - Expand only adds: the new shape appears beside the old one and both are kept in
+//     step. Ship it with the code that starts using the new shape.
+//   - Contract removes the old shape. Add it to localMigrations in a later release, once
+//     no deployed code uses the old shape; the migration linter refuses to drop a column
+//     a model still maps, so it cannot ship early.
+type SchemaChange struct {
+	Expand   migrations.Migration
+	Contract migrations.Migration
+}
+
+// RenameColumn renames table.from to table.to in two phases. Expand adds to with the
+// given definition, copies the data across and installs a trigger that keeps both columns
+// in step while old and new code write to the table; contract drops the trigger and from.
+// The definition must be nullable or carry a default, as it is added to a live table.
+func RenameColumn(expandVersion, contractVersion, table, from, to, definition string) SchemaChange {
+	function := fmt.Sprintf("sync_%s_%s_to_%s", table, from, to)
+	createSync := fmt.Sprintf(`
+		CREATE OR REPLACE FUNCTION %[1]s() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				NEW.%[3]s := COALESCE(NEW.%[3]s, NEW.%[2]s);
+				NEW.%[2]s := COALESCE(NEW.%[2]s, NEW.%[3]s);
+			ELSIF NEW.%[2]s IS DISTINCT FROM OLD.%[2]s THEN
+				NEW.%[3]s := NEW.%[2]s;
+			ELSIF NEW.%[3]s IS DISTINCT FROM OLD.%[3]s THEN
+				NEW.%[2]s := NEW.%[3]s;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS %[1]s ON %[4]s;
+		CREATE TRIGGER %[1]s BEFORE INSERT OR UPDATE ON %[4]s
+			FOR EACH ROW EXECUTE FUNCTION %[1]s();
+	`, function, from, to, table)
+	dropSync := fmt.Sprintf(`
+		DROP TRIGGER IF EXISTS %[1]s ON %[2]s;
+		DROP FUNCTION IF EXISTS %[1]s();
+	`, function, table)
+
+	return SchemaChange{
+		Expand: migrations.Migration{
+			Version: expandVersion,
+			Name:    fmt.Sprintf("expand_rename_%s_%s_to_%s", table, from, to),
+			Up: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, fmt.Sprintf(`
+					ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS %[3]s %[4]s;
+					UPDATE %[1]s SET %[3]s = %[2]s WHERE %[3]s IS DISTINCT FROM %[2]s;
+				`, table, from, to, definition)+createSync)
+			},
+			Down: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, dropSync+fmt.Sprintf(`
+					ALTER TABLE %s DROP COLUMN IF EXISTS %s;
+				`, table, to))
+			},
+		},
+		Contract: migrations.Migration{
+			Version: contractVersion,
+			Name:    fmt.Sprintf("contract_rename_%s_%s_to_%s", table, from, to),
+			Up: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, dropSync+fmt.Sprintf(`
+					ALTER TABLE %s DROP COLUMN IF EXISTS %s;
+				`, table, from))
+			},
+			Down: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, fmt.Sprintf(`
+					ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS %[2]s %[4]s;
+					UPDATE %[1]s SET %[2]s = %[3]s WHERE %[2]s IS DISTINCT FROM %[3]s;
+				`, table, from, to, definition)+createSync)
+			},
+		},
+	}
+}
+
+// DropColumn removes table.column in two phases. Expand drops its NOT NULL constraint so
+// code that no longer writes it can still insert; contract drops the column once no model
+// maps it. definition restores the column, nullable, if the contract is rolled back.
+func DropColumn(expandVersion, contractVersion, table, column, definition string) SchemaChange {
+	return SchemaChange{
+		Expand: migrations.Migration{
+			Version: expandVersion,
+			Name:    fmt.Sprintf("expand_drop_%s_%s", table, column),
+			Up: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;`, table, column))
+			},
+			Down: func(ctx context.Context, exec migrations.Executor) error {
+				// Restoring NOT NULL would scan the table under lock; leave the column nullable
+				return nil
+			},
+		},
+		Contract: migrations.Migration{
+			Version: contractVersion,
+			Name:    fmt.Sprintf("contract_drop_%s_%s", table, column),
+			Up: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s;`, table, column))
+			},
+			Down: func(ctx context.Context, exec migrations.Executor) error {
+				return exec.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s;`, table, column, definition))
+			},
+		},
+	}
+}
+
+// CreateIndexConcurrently builds an index without blocking writes to the table. The
+// statement runs outside the migration's transaction, which CONCURRENTLY requires, and a
+// build left invalid by an earlier failed attempt is dropped and started again.
+func CreateIndexConcurrently(version, index, table, columns string) migrations.Migration {
+	return createIndexConcurrently(version, index, "CREATE INDEX", fmt.Sprintf("%s (%s)", table, columns))
+}
+
+// CreateUniqueIndexConcurrently is CreateIndexConcurrently for a unique index. A build
+// that fails on duplicate values is left invalid and is retried on the next run, once the
+// duplicates have been resolved.
+func CreateUniqueIndexConcurrently(version, index, table, columns string) migrations.Migration {
+	return createIndexConcurrently(version, index, "CREATE UNIQUE INDEX", fmt.Sprintf("%s (%s)", table, columns))
+}
+
+// CreateTrigramIndexConcurrently is CreateIndexConcurrently for a pg_trgm GIN index on
+// lower(column), which serves case-insensitive LIKE and ILIKE matches anywhere in the
+// value. The pg_trgm extension must already be enabled.
+func CreateTrigramIndexConcurrently(version, index, table, column string) migrations.Migration {
+	return createIndexConcurrently(version, index, "CREATE INDEX", fmt.Sprintf("%s USING gin (lower(%s) gin_trgm_ops)", table, column))
+}
+
+// createIndexConcurrently builds index with create on target, a table and its indexed
+// columns or expressions
+func createIndexConcurrently(version, index, create, target string) migrations.Migration {
+	return migrations.Migration{
+		Version: version,
+		Name:    "create_index_" + index,
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			invalid, err := exec.Query(ctx, `
+				SELECT 1 FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid
+				WHERE c.relname = $1 AND NOT i.indisvalid
+			`, index)
+			if err != nil {
+				return err
+			}
+			if len(invalid) > 0 {
+				if err := exec.Exec(ctx, fmt.Sprintf(`DROP INDEX CONCURRENTLY IF EXISTS %s`, index)); err != nil {
+					return err
+				}
+			}
+			return exec.Exec(ctx, fmt.Sprintf(`%s CONCURRENTLY IF NOT EXISTS %s ON %s`, create, index, target))
+		},
+		Down: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, fmt.Sprintf(`DROP INDEX CONCURRENTLY IF EXISTS %s`, index))
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// maxLoggedQueries caps the statements kept per QueryLog; later ones are counted only
+const maxLoggedQueries = 200
+
+// RecordedQuery is one SQL statement run while a QueryLog was in the context
+type RecordedQuery struct {
+	SQL      string
+	Rows     int64
+	Duration time.Duration
+	Error    string
+}
+
+// QueryLog collects the statements run on behalf of one request
+type QueryLog struct {
+	mu       sync.Mutex
+	queries  []RecordedQuery
+	count    int
+	duration time.Duration
+}
+
+// Count returns the number of statements run
+func (l *QueryLog) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Duration returns the total time spent in the database
+func (l *QueryLog) Duration() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.duration
+}
+
+// Queries returns the recorded statements in the order they ran
+func (l *QueryLog) Queries() []RecordedQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]RecordedQuery(nil), l.queries...)
+}
+
+func (l *QueryLog) add(query RecordedQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	l.duration += query.Duration
+	if len(l.queries) < maxLoggedQueries {
+		l.queries = append(l.queries, query)
+	}
+}
+
+type queryLogKey struct{}
+
+// WithQueryLog returns a context whose statements are recorded to log once RecordQueries is on
+func WithQueryLog(ctx context.Context, log *QueryLog) context.Context {
+	return context.WithValue(ctx, queryLogKey{}, log)
+}
+
+// QueryLogFrom returns the context's QueryLog, if any
+func QueryLogFrom(ctx context.Context) (*QueryLog, bool) {
+	log, ok := ctx.Value(queryLogKey{}).(*QueryLog)
+	return log, ok
+}
+
+// RecordQueries records every statement run with a context from WithQueryLog. GORM
+// reports each statement to its logger, so the recorder wraps the configured one.
+func (db *DB) RecordQueries() {
+	db.Logger = &queryRecorder{Interface: db.Logger}
+}
+
+// queryRecorder is a GORM logger that copies statements into the context's QueryLog
+type queryRecorder struct {
+	logger.Interface
+}
+
+// LogMode implements logger.Interface, keeping the recorder in place
+func (r *queryRecorder) LogMode(level logger.LogLevel) logger.Interface {
+	return &queryRecorder{Interface: r.Interface.LogMode(level)}
+}
+
+// Trace implements logger.Interface
+func (r *queryRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if log, ok := QueryLogFrom(ctx); ok {
+		sql, rows := fc()
+		query := RecordedQuery{SQL: sql, Rows: rows, Duration: time.Since(begin)}
+		if err != nil {
+			query.Error = err.Error()
+		}
+		log.add(query)
+	}
+	r.Interface.Trace(ctx, begin, fc, err)
+}
+
+// ParamsFilter passes through to the wrapped logger, which GORM would otherwise not see
+func (r *queryRecorder) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if filter, ok := r.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
@@ -0,0 +1,455 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"gorm.io/gorm/clause"
+)
+
+// seedBatchSize is how many rows a single INSERT carries while seeding
+const seedBatchSize = 1000
+
+// SeedProfile sets which data a seeding run generates and how much of it
+type SeedProfile struct {
+	Name       string
+	Categories int
+	Brands     int
+	Products   int
+	Customers  int
+	Orders     int
+	// SalePercent is the share of products given a sale price
+	SalePercent int
+	// Samples also loads the hand-written sample catalog from SeedCommerce
+	Samples bool
+	// RandSeed makes runs repeatable: the same profile and seed generate the same rows,
+	// so a second run inserts nothing new
+	RandSeed int64
+}
+
+// SeedProfiles are the profiles selectable by name
+var SeedProfiles = map[string]SeedProfile{
+	"demo": {
+		Name:        "demo",
+		Categories:  12,
+		Brands:      20,
+		Products:    500,
+		Customers:   50,
+		Orders:      300,
+		SalePercent: 15,
+		Samples:     true,
+		RandSeed:    1,
+	},
+	"large-catalog": {
+		Name:        "large-catalog",
+		Categories:  200,
+		Brands:      1000,
+		Products:    100000,
+		SalePercent: 10,
+		RandSeed:    1,
+	},
+	"load-test": {
+		Name:        "load-test",
+		Categories:  60,
+		Brands:      250,
+		Products:    10000,
+		Customers:   5000,
+		Orders:      100000,
+		SalePercent: 10,
+		RandSeed:    1,
+	},
+}
+
+// LookupSeedProfile returns the named profile
+func LookupSeedProfile(name string) (SeedProfile, error) {
+	profile, ok := SeedProfiles[name]
+	if !ok {
+		return SeedProfile{}, fmt.Errorf("unknown seed profile %q (available: %s)", name, strings.Join(SeedProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// SeedProfileNames lists the profile names in order
+func SeedProfileNames() []string {
+	names := make([]string, 0, len(SeedProfiles))
+	for name := range SeedProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SeedWithProfile generates the profile's categories, brands, products, sale prices and
+// orders. Rows get stable IDs derived from the profile, and rows that already exist are
+// left alone, so a run can be repeated or resumed after an interruption.
+func (db *DB) SeedWithProfile(ctx context.Context, profile SeedProfile) error {
+	started := time.Now()
+	log.Printf("Seeding profile %q: %d categories, %d brands, %d products, %d orders",
+		profile.Name, profile.Categories, profile.Brands, profile.Products, profile.Orders)
+
+	if profile.Samples {
+		if err := db.SeedCommerce(ctx); err != nil {
+			return err
+		}
+	}
+
+	gen := &seedGenerator{
+		profile: profile,
+		rng:     rand.New(rand.NewSource(profile.RandSeed)),
+		now:     time.Now().UTC().Truncate(time.Second),
+	}
+
+	categories := gen.categories()
+	if err := insertSeedRows(ctx, db, "categories", categories); err != nil {
+		return err
+	}
+
+	brands := gen.brands()
+	if err := insertSeedRows(ctx, db, "brands", brands); err != nil {
+		return err
+	}
+
+	products := gen.products(categories, brands)
+	if err := insertSeedRows(ctx, db, "products", products); err != nil {
+		return err
+	}
+
+	if err := insertSeedRows(ctx, db, "product prices", gen.salePrices(products)); err != nil {
+		return err
+	}
+
+	// Orders are generated batch by batch so a large load-test run does not hold them all
+	if profile.Orders > 0 && len(products) > 0 {
+		popularity := rand.NewZipf(gen.rng, 1.2, 1, uint64(len(products)-1))
+		for start := 0; start < profile.Orders; start += seedBatchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			end := min(start+seedBatchSize, profile.Orders)
+			batch := make([]Order, 0, end-start)
+			for i := start; i < end; i++ {
+				batch = append(batch, gen.order(i, products, popularity))
+			}
+			if err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&batch).Error; err != nil {
+				return fmt.Errorf("failed to seed orders: %w", err)
+			}
+			if end%(seedBatchSize*10) == 0 || end == profile.Orders {
+				log.Printf("  → Orders: %d/%d", end, profile.Orders)
+			}
+		}
+	}
+
+	log.Printf("✓ Seed profile %q completed in %s", profile.Name, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// insertSeedRows inserts rows in batches, skipping rows whose ID already exists
+func insertSeedRows[T any](ctx context.Context, db *DB, label string, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, seedBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to seed %s: %w", label, err)
+	}
+	log.Printf("  → %s: %d", strings.ToUpper(label[:1])+label[1:], len(rows))
+	return nil
+}
+
+// seedDepartments are the top-level categories, with the product nouns and price range
+// (in cents) generated for each
+var seedDepartments = []struct {
+	name     string
+	nouns    []string
+	minPrice int64
+	maxPrice int64
+}{
+	{"Electronics", []string{"Headphones", "Speaker", "Monitor", "Keyboard", "Webcam", "Charger"}, 1999, 89999},
+	{"Computers", []string{"Laptop", "Desktop", "Tablet", "Router", "SSD", "Docking Station"}, 4999, 249999},
+	{"Clothing", []string{"T-Shirt", "Hoodie", "Jacket", "Jeans", "Sweater", "Dress"}, 1499, 19999},
+	{"Shoes", []string{"Sneakers", "Boots", "Sandals", "Loafers", "Running Shoes"}, 2999, 24999},
+	{"Home & Kitchen", []string{"Blender", "Knife Set", "Cookware Set", "Coffee Maker", "Lamp", "Rug"}, 999, 49999},
+	{"Sports & Outdoors", []string{"Yoga Mat", "Tent", "Backpack", "Water Bottle", "Bike Helmet"}, 999, 59999},
+	{"Beauty", []string{"Moisturizer", "Serum", "Shampoo", "Lip Balm", "Perfume"}, 499, 14999},
+	{"Toys & Games", []string{"Board Game", "Puzzle", "Building Set", "Plush Toy", "Card Game"}, 799, 9999},
+	{"Books", []string{"Novel", "Cookbook", "Biography", "Travel Guide", "Notebook"}, 599, 4999},
+	{"Garden", []string{"Planter", "Hose", "Pruning Shears", "Bird Feeder", "Seed Kit"}, 799, 29999},
+}
+
+var (
+	seedAdjectives = []string{"Classic", "Premium", "Essential", "Compact", "Ultra", "Eco", "Pro", "Everyday", "Deluxe", "Lightweight", "Vintage", "Smart"}
+	seedColors     = []string{"Black", "White", "Navy", "Gray", "Red", "Green", "Blue", "Sand", "Olive", "Charcoal"}
+	seedBrandWords = []string{"Acme", "Northwind", "Summit", "Bluebird", "Ironwood", "Lumen", "Harbor", "Crescent", "Vertex", "Willow", "Atlas", "Pioneer", "Cobalt", "Maple", "Orbit"}
+	seedBrandKinds = []string{"Labs", "Goods", "Supply", "Works", "Co", "Outfitters", "Studio", "Trading"}
+	seedFirstNames = []string{"Ava", "Liam", "Mia", "Noah", "Sofia", "Ethan", "Zoe", "Lucas", "Chloe", "Mateo", "Priya", "Kenji", "Amara", "Omar", "Elena"}
+	seedLastNames  = []string{"Smith", "Garcia", "Chen", "Johnson", "Patel", "Kim", "Nguyen", "Brown", "Lopez", "Okafor", "Rossi", "Müller", "Silva", "Cohen", "Tanaka"}
+	seedStreets    = []string{"Main St", "Oak Ave", "Maple Dr", "Cedar Ln", "Park Blvd", "Elm St", "Lakeview Rd", "Hillcrest Way"}
+	seedCities     = []struct{ city, state, postal string }{
+		{"Seattle", "WA", "98101"}, {"Austin", "TX", "73301"}, {"Denver", "CO", "80202"},
+		{"Chicago", "IL", "60601"}, {"Boston", "MA", "02108"}, {"Atlanta", "GA", "30303"},
+		{"Portland", "OR", "97201"}, {"Miami", "FL", "33101"}, {"Phoenix", "AZ", "85001"},
+	}
+)
+
+// seedGenerator builds rows from a seeded random source
+type seedGenerator struct {
+	profile SeedProfile
+	rng     *rand.Rand
+	now     time.Time
+}
+
+func (g *seedGenerator) id(kind string, n int) string {
+	return fmt.Sprintf("seed-%s-%s-%06d", g.profile.Name, kind, n)
+}
+
+// categories returns the departments first, then subcategories spread across them
+func (g *seedGenerator) categories() []Category {
+	count := g.profile.Categories
+	departments := min(count, len(seedDepartments))
+	categories := make([]Category, 0, count)
+
+	for i := 0; i < departments; i++ {
+		name := seedDepartments[i].name
+		categories = append(categories, Category{
+			ID:          g.id("cat", i+1),
+			Name:        name,
+			Slug:        fmt.Sprintf("%s-%s", g.profile.Name, slugify(name)),
+			Description: fmt.Sprintf("Shop %s", strings.ToLower(name)),
+			Active:      true,
+			CreatedAt:   g.now,
+			UpdatedAt:   g.now,
+		})
+	}
+	for i := departments; i < count; i++ {
+		parent := categories[i%departments]
+		noun := seedDepartments[i%departments].nouns[(i/departments)%len(seedDepartments[i%departments].nouns)]
+		name := fmt.Sprintf("%s %s", seedAdjectives[(i/departments)%len(seedAdjectives)], noun)
+		parentID := parent.ID
+		categories = append(categories, Category{
+			ID:          g.id("cat", i+1),
+			Name:        name,
+			Slug:        fmt.Sprintf("%s-%s-%d", g.profile.Name, slugify(name), i+1),
+			Description: fmt.Sprintf("%s in %s", name, parent.Name),
+			ParentID:    &parentID,
+			Active:      true,
+			CreatedAt:   g.now,
+			UpdatedAt:   g.now,
+		})
+	}
+	return categories
+}
+
+func (g *seedGenerator) brands() []Brand {
+	brands := make([]Brand, 0, g.profile.Brands)
+	for i := 0; i < g.profile.Brands; i++ {
+		name := fmt.Sprintf("%s %s", seedBrandWords[i%len(seedBrandWords)], seedBrandKinds[(i/len(seedBrandWords))%len(seedBrandKinds)])
+		if i >= len(seedBrandWords)*len(seedBrandKinds) {
+			name = fmt.Sprintf("%s %d", name, i+1)
+		}
+		// Brand names are unique across the table, so they carry the profile
+		name = fmt.Sprintf("%s (%s)", name, g.profile.Name)
+		brands = append(brands, Brand{
+			ID:          g.id("brand", i+1),
+			Name:        name,
+			Slug:        slugify(name),
+			Description: fmt.Sprintf("Products by %s", name),
+			Active:      true,
+			CreatedAt:   g.now,
+			UpdatedAt:   g.now,
+		})
+	}
+	return brands
+}
+
+// products spreads products across leaf categories and brands, priced from the department's
+// range. A few are left as drafts or archived, as in a live catalog.
+func (g *seedGenerator) products(categories []Category, brands []Brand) []Product {
+	if len(categories) == 0 || len(brands) == 0 {
+		return nil
+	}
+	departments := min(len(categories), len(seedDepartments))
+
+	products := make([]Product, 0, g.profile.Products)
+	for i := 0; i < g.profile.Products; i++ {
+		category := categories[g.rng.Intn(len(categories))]
+		dept := seedDepartments[g.departmentIndex(category, categories, departments)]
+		brand := brands[g.rng.Intn(len(brands))]
+		noun := dept.nouns[g.rng.Intn(len(dept.nouns))]
+		adjective := seedAdjectives[g.rng.Intn(len(seedAdjectives))]
+		color := seedColors[g.rng.Intn(len(seedColors))]
+
+		// Prices end in 99 cents, skewed toward the low end of the range
+		span := dept.maxPrice - dept.minPrice
+		price := dept.minPrice + int64(float64(span)*g.rng.Float64()*g.rng.Float64())
+		price = price/100*100 + 99
+
+		status := "active"
+		switch roll := g.rng.Intn(100); {
+		case roll < 3:
+			status = "draft"
+		case roll < 5:
+			status = "archived"
+		}
+
+		// Listed before the 90 days of generated orders, so no order predates its product
+		created := g.now.AddDate(0, 0, -90).Add(-time.Duration(g.rng.Intn(365*24)) * time.Hour)
+		name := fmt.Sprintf("%s %s %s", adjective, color, noun)
+		products = append(products, Product{
+			ID:          g.id("prod", i+1),
+			SKU:         fmt.Sprintf("%s-%07d", strings.ToUpper(strings.ReplaceAll(g.profile.Name, "-", "")), i+1),
+			Name:        name,
+			Description: fmt.Sprintf("The %s %s from %s, in %s.", strings.ToLower(adjective), strings.ToLower(noun), brand.Name, strings.ToLower(color)),
+			BasePrice:   price,
+			Currency:    "USD",
+			Status:      status,
+			BrandID:     brand.ID,
+			CategoryID:  category.ID,
+			Images:      MarshalJSON([]string{fmt.Sprintf("https://picsum.photos/seed/%s/800/800", g.id("prod", i+1))}),
+			Metadata:    MarshalJSON(map[string]string{"color": strings.ToLower(color)}),
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		})
+	}
+	return products
+}
+
+// departmentIndex finds the department a category belongs to
+func (g *seedGenerator) departmentIndex(category Category, categories []Category, departments int) int {
+	for i := 0; i < departments; i++ {
+		if category.ID == categories[i].ID || (category.ParentID != nil && *category.ParentID == categories[i].ID) {
+			return i
+		}
+	}
+	return 0
+}
+
+// salePrices puts a share of the active products on sale for the next month
+func (g *seedGenerator) salePrices(products []Product) []ProductPrice {
+	validTo := g.now.AddDate(0, 1, 0)
+	prices := make([]ProductPrice, 0, len(products)*g.profile.SalePercent/100)
+	for i, product := range products {
+		if product.Status != "active" || g.rng.Intn(100) >= g.profile.SalePercent {
+			continue
+		}
+		discount := 10 + 5*g.rng.Intn(5) // 10% to 30% off
+		prices = append(prices, ProductPrice{
+			ID:            g.id("price", i+1),
+			ProductID:     product.ID,
+			PriceAmount:   product.BasePrice * int64(100-discount) / 100,
+			PriceCurrency: product.Currency,
+			ValidFrom:     &g.now,
+			ValidTo:       &validTo,
+			Priority:      10,
+			PriceType:     "sale",
+			IsActive:      true,
+			CreatedAt:     g.now,
+			UpdatedAt:     g.now,
+		})
+	}
+	return prices
+}
+
+// order builds the nth order. Customers are synthetic user IDs, products are picked by a
+// Zipf distribution so a few best sellers dominate, and orders are spread over 90 days.
+func (g *seedGenerator) order(n int, products []Product, popularity *rand.Zipf) Order {
+	customers := max(g.profile.Customers, 1)
+	customer := g.rng.Intn(customers)
+
+	lines := 1 + g.rng.Intn(4)
+	items := make([]orders.OrderItem, 0, lines)
+	var subtotal int64
+	for l := 0; l < lines; l++ {
+		product := products[popularity.Uint64()]
+		quantity := 1 + g.rng.Intn(3)
+		unit := money.Money{Amount: product.BasePrice, Currency: product.Currency}
+		total := money.Money{Amount: product.BasePrice * int64(quantity), Currency: product.Currency}
+		items = append(items, orders.OrderItem{
+			ID:        fmt.Sprintf("%s-%d", g.id("order", n+1), l+1),
+			ProductID: product.ID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+			UnitPrice: unit,
+			Quantity:  quantity,
+			Total:     total,
+		})
+		subtotal += total.Amount
+	}
+
+	var shippingTotal int64 = 999
+	if subtotal >= 5000 {
+		shippingTotal = 0
+	}
+	taxTotal := subtotal * 875 / 10000
+
+	status := orders.OrderStatusDelivered
+	switch roll := g.rng.Intn(100); {
+	case roll < 5:
+		status = orders.OrderStatusPending
+	case roll < 15:
+		status = orders.OrderStatusPaid
+	case roll < 25:
+		status = orders.OrderStatusShipped
+	case roll < 30:
+		status = orders.OrderStatusCanceled
+	case roll < 33:
+		status = orders.OrderStatusRefunded
+	}
+
+	city := seedCities[customer%len(seedCities)]
+	address := orders.Address{
+		FirstName:    seedFirstNames[customer%len(seedFirstNames)],
+		LastName:     seedLastNames[(customer/len(seedFirstNames))%len(seedLastNames)],
+		AddressLine1: fmt.Sprintf("%d %s", 100+customer%900, seedStreets[customer%len(seedStreets)]),
+		City:         city.city,
+		State:        city.state,
+		PostalCode:   city.postal,
+		Country:      "US",
+	}
+
+	created := g.now.Add(-time.Duration(g.rng.Int63n(int64(90 * 24 * time.Hour))))
+	order := Order{
+		ID:              g.id("order", n+1),
+		OrderNumber:     fmt.Sprintf("SEED-%s-%07d", strings.ToUpper(strings.ReplaceAll(g.profile.Name, "-", "")), n+1),
+		UserID:          fmt.Sprintf("seed-customer-%06d", customer+1),
+		Status:          string(status),
+		Items:           MarshalJSON(items),
+		ShippingAddress: MarshalJSON(address),
+		BillingAddress:  MarshalJSON(address),
+		PaymentMethodID: "pm_card_visa",
+		Subtotal:        subtotal,
+		TaxTotal:        taxTotal,
+		ShippingTotal:   shippingTotal,
+		Total:           subtotal + taxTotal + shippingTotal,
+		Currency:        "USD",
+		CreatedAt:       created,
+		UpdatedAt:       created,
+	}
+	if status == orders.OrderStatusCanceled {
+		order.CancelledAt = &created
+		order.CancelReason = "Customer changed their mind"
+	}
+	return order
+}
+
+// slugify lowercases a name and joins its words with dashes
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,237 @@
+// Package fieldcrypt encrypts individual database columns with envelope encryption. Each
+// value gets its own random data key, which is stored wrapped by a named master key next
+// to the ciphertext. Rotating master keys only rewraps data keys; the data itself is never
+// re-encrypted.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks an encrypted value: enc:v1:<key id>:<wrapped data key>:<ciphertext>
+const prefix = "enc:v1:"
+
+var (
+	ErrNoKeys     = errors.New("value is encrypted but no field encryption keys are configured")
+	ErrUnknownKey = errors.New("value is encrypted with a key that is not configured")
+	ErrMalformed  = errors.New("encrypted value is malformed")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Key is a named 256-bit master key
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Cipher encrypts values with the primary key and decrypts values written with any of its
+// keys. A nil Cipher stores values as plaintext and only fails to read encrypted ones.
+type Cipher struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// New creates a Cipher. The first key encrypts new values; the others are kept to read
+// values written before a rotation.
+func New(keys ...Key) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one field encryption key is required")
+	}
+
+	c := &Cipher{primary: keys[0].ID, keys: make(map[string]cipher.AEAD, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("invalid field encryption key id %q", key.ID)
+		}
+		if _, ok := c.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate field encryption key id %q", key.ID)
+		}
+		if len(key.Secret) != 32 {
+			return nil, fmt.Errorf("field encryption key %q must be 32 bytes", key.ID)
+		}
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[key.ID] = aead
+	}
+	return c, nil
+}
+
+// ParseKeys parses keys written as id:base64secret, separated by commas
+func ParseKeys(spec []string) ([]Key, error) {
+	keys := make([]Key, 0, len(spec))
+	for _, entry := range spec {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("field encryption key %q must be id:base64secret", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("field encryption key %q is not valid base64: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// IsEncrypted reports whether a value was written by a Cipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt seals a value with a fresh data key wrapped by the primary key. Empty values
+// are left empty so optional columns stay blank.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(dataAEAD, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(c.keys[c.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+	return format(c.primary, wrapped, sealed), nil
+}
+
+// Decrypt opens an encrypted value. Values that aren't encrypted, such as rows written
+// before encryption was turned on, are returned as they are.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrNoKeys
+	}
+
+	keyID, wrapped, sealed, err := split(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := c.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, sealed)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a value should be rewritten: it is plaintext, or its data
+// key is wrapped by a key other than the primary
+func (c *Cipher) NeedsRotation(value string) bool {
+	if c == nil || value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _, err := split(value)
+	return err == nil && keyID != c.primary
+}
+
+// Rotate encrypts a plaintext value, or rewraps an encrypted value's data key with the
+// primary key without touching its ciphertext
+func (c *Cipher) Rotate(value string) (string, error) {
+	if !c.NeedsRotation(value) {
+		return value, nil
+	}
+	if !IsEncrypted(value) {
+		return c.Encrypt(value)
+	}
+
+	keyID, wrapped, sealed, err := split(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := c.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	rewrapped, err := seal(c.keys[c.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+	return format(c.primary, rewrapped, sealed), nil
+}
+
+func (c *Cipher) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	keyAEAD, ok := c.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	dataKey, err := open(keyAEAD, wrapped)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return dataKey, nil
+}
+
+func format(keyID string, wrapped, sealed []byte) string {
+	return prefix + keyID + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(sealed)
+}
+
+func split(value string) (keyID string, wrapped, sealed []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformed
+	}
+	if wrapped, err = encoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	if sealed, err = encoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data with a random nonce prepended to the ciphertext
+func seal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"expvar"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+)
+
+var publishRuntimeVars sync.Once
+
+// NewDiagnosticsRouter serves net/http/pprof profiles under /debug/pprof and expvar
+// variables at /debug/vars to admins only. It is meant for its own listener, bound to a
+// private address, so profiles can be captured from production during an incident
+// without exposing them on the public port. Importing net/http/pprof also registers its
+// handlers on http.DefaultServeMux, which the API never serves.
+func NewDiagnosticsRouter(authMiddleware *middleware.AuthMiddleware) *gin.Engine {
+	publishRuntimeVars.Do(func() {
+		started := time.Now()
+		expvar.Publish("goroutines", expvar.Func(func() interface{} {
+			return runtime.NumGoroutine()
+		}))
+		expvar.Publish("uptime_seconds", expvar.Func(func() interface{} {
+			return int64(time.Since(started).Seconds())
+		}))
+	})
+
+	router := gin.New()
+	router.Use(middleware.Logger())
+	router.Use(middleware.Recovery())
+
+	debug := router.Group("/debug")
+	debug.Use(authMiddleware.Authenticate())
+	debug.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+	{
+		debug.GET("/vars", gin.WrapH(expvar.Handler()))
+
+		// GET /debug/pprof/profile?seconds=30 and /debug/pprof/trace?seconds=5 stream for
+		// the requested duration; the named profiles (heap, goroutine, block, mutex,
+		// allocs, threadcreate) are served by the index
+		debug.GET("/pprof/", gin.WrapF(pprof.Index))
+		debug.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+		debug.GET("/pprof/:name", gin.WrapF(pprof.Index))
+	}
+
+	return router
+}
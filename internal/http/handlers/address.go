@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// AddressHandler handles address validation endpoints
+type AddressHandler struct {
+	addressService *services.AddressService
+}
+
+// NewAddressHandler creates a new AddressHandler
+func NewAddressHandler(addressService *services.AddressService) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+	}
+}
+
+// ValidateAddress validates an address and returns suggested corrections
+// POST /checkout/address/validate
+func (h *AddressHandler) ValidateAddress(c *gin.Context) {
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	result, err := h.addressService.Validate(c.Request.Context(), req.ToOrderAddress())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// respondAddressError maps address validation errors to a structured 422 response
+func respondAddressError(c *gin.Context, field string, err error) {
+	validationErr, ok := err.(*services.AddressValidationError)
+	if !ok {
+		response.FromError(c, err)
+		return
+	}
+
+	response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "undeliverable_address", validationErr.Error(), gin.H{
+		"field":     field,
+		"issues":    validationErr.Issues,
+		"suggested": validationErr.Suggested,
+	})
+}
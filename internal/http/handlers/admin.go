@@ -46,7 +46,7 @@ func (h *AdminHandler) CreateRole(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -74,7 +74,7 @@ func (h *AdminHandler) GetRole(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, gin.H{"role": role})
+	response.SuccessWithETag(c, gin.H{"role": role})
 }
 
 // UpdateRole updates an existing role
@@ -88,7 +88,7 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -97,6 +97,9 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 		response.NotFound(c, "Role not found")
 		return
 	}
+	if !response.IfMatch(c, gin.H{"role": role}) {
+		return
+	}
 
 	if req.Name != "" {
 		role.Name = req.Name
@@ -110,7 +113,12 @@ func (h *AdminHandler) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, gin.H{"role": role})
+	// Reload so the ETag matches the stored role
+	if role, err = h.authStore.GetRoleByID(c.Request.Context(), roleID); err != nil {
+		response.NotFound(c, "Role not found")
+		return
+	}
+	response.SuccessWithETag(c, gin.H{"role": role})
 }
 
 // DeleteRole deletes a role
@@ -151,7 +159,7 @@ func (h *AdminHandler) CreatePermission(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -181,7 +189,7 @@ func (h *AdminHandler) GetPermission(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, gin.H{"permission": permission})
+	response.SuccessWithETag(c, gin.H{"permission": permission})
 }
 
 // UpdatePermission updates an existing permission
@@ -197,7 +205,7 @@ func (h *AdminHandler) UpdatePermission(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -206,6 +214,9 @@ func (h *AdminHandler) UpdatePermission(c *gin.Context) {
 		response.NotFound(c, "Permission not found")
 		return
 	}
+	if !response.IfMatch(c, gin.H{"permission": permission}) {
+		return
+	}
 
 	if req.Name != "" {
 		permission.Name = req.Name
@@ -225,7 +236,12 @@ func (h *AdminHandler) UpdatePermission(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, gin.H{"permission": permission})
+	// Reload so the ETag matches the stored permission
+	if permission, err = h.authStore.GetPermissionByID(c.Request.Context(), permissionID); err != nil {
+		response.NotFound(c, "Permission not found")
+		return
+	}
+	response.SuccessWithETag(c, gin.H{"permission": permission})
 }
 
 // DeletePermission deletes a permission
@@ -268,7 +284,7 @@ func (h *AdminHandler) AssignRoleToUser(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -344,7 +360,7 @@ func (h *AdminHandler) GrantPermissionToRole(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
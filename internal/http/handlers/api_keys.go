@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// APIKeyHandler handles admin management of integration API keys
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKeyRequest represents the request to issue an API key
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=fulfillment:read fulfillment:write"`
+}
+
+// CreateAPIKeyResponse includes the key's secret, which is only ever returned here
+type CreateAPIKeyResponse struct {
+	*services.APIKey
+	Secret string `json:"secret"`
+}
+
+// ListAPIKeys lists issued API keys
+// GET /admin/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// CreateAPIKey issues a new scoped API key
+// POST /admin/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	adminID, _ := middleware.GetUserID(c)
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	scopes := make([]services.APIKeyScope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = services.APIKeyScope(scope)
+	}
+
+	key, secret, err := h.apiKeyService.CreateKey(c.Request.Context(), req.Name, scopes, adminID)
+	if err != nil {
+		if err == services.ErrInvalidScopes {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, CreateAPIKeyResponse{APIKey: key, Secret: secret})
+}
+
+// RevokeAPIKey permanently disables an API key
+// DELETE /admin/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	key, err := h.apiKeyService.RevokeKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrAPIKeyNotFound {
+			response.NotFound(c, "API key not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, key)
+}
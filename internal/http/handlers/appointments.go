@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// AppointmentHandler handles appointment product, slot and booking endpoints
+type AppointmentHandler struct {
+	appointmentService *services.AppointmentService
+	orderService       *services.OrderService
+}
+
+// NewAppointmentHandler creates a new AppointmentHandler
+func NewAppointmentHandler(appointmentService *services.AppointmentService, orderService *services.OrderService) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+		orderService:       orderService,
+	}
+}
+
+// AppointmentProductRequest represents the request to make a product bookable by appointment
+type AppointmentProductRequest struct {
+	CancelWindowHours int `json:"cancel_window_hours" binding:"min=0,max=720"`
+}
+
+// AppointmentSlotRequest represents the request to create or update an appointment slot
+type AppointmentSlotRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Capacity int       `json:"capacity" binding:"required,gt=0"`
+	IsActive *bool     `json:"is_active"` // defaults to true
+}
+
+// AppointmentSlotResponse is an appointment slot as shown to shoppers
+type AppointmentSlotResponse struct {
+	ID        string    `json:"id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Remaining int       `json:"remaining"`
+}
+
+// ListAppointmentSlots lists the bookable slots of an appointment product
+// GET /catalog/products/:id/appointment-slots?from=2024-01-01&days=14
+func (h *AppointmentHandler) ListAppointmentSlots(c *gin.Context) {
+	from, to, ok := slotWindow(c)
+	if !ok {
+		return
+	}
+
+	slots, err := h.appointmentService.AvailableSlots(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	result := make([]AppointmentSlotResponse, len(slots))
+	for i, slot := range slots {
+		result[i] = AppointmentSlotResponse{
+			ID:        slot.ID,
+			StartsAt:  slot.StartsAt,
+			EndsAt:    slot.EndsAt,
+			Remaining: slot.Remaining(),
+		}
+	}
+
+	response.Success(c, result)
+}
+
+// GetAppointmentProduct retrieves a product's appointment settings
+// GET /admin/catalog/products/:id/appointment
+func (h *AppointmentHandler) GetAppointmentProduct(c *gin.Context) {
+	product, err := h.appointmentService.GetAppointmentProduct(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Success(c, product)
+}
+
+// SetAppointmentProduct makes a product bookable by appointment or replaces its settings
+// PUT /admin/catalog/products/:id/appointment
+func (h *AppointmentHandler) SetAppointmentProduct(c *gin.Context) {
+	var req AppointmentProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	product := &services.AppointmentProduct{
+		ProductID:         c.Param("id"),
+		CancelWindowHours: req.CancelWindowHours,
+	}
+	if err := h.appointmentService.SetAppointmentProduct(c.Request.Context(), product); err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Success(c, product)
+}
+
+// DeleteAppointmentProduct stops a product being bookable by appointment
+// DELETE /admin/catalog/products/:id/appointment
+func (h *AppointmentHandler) DeleteAppointmentProduct(c *gin.Context) {
+	if err := h.appointmentService.RemoveAppointmentProduct(c.Request.Context(), c.Param("id")); err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListSlots lists all of an appointment product's slots, with the seats booked
+// GET /admin/catalog/products/:id/appointment-slots?from=2024-01-01&days=14
+func (h *AppointmentHandler) ListSlots(c *gin.Context) {
+	from, to, ok := slotWindow(c)
+	if !ok {
+		return
+	}
+
+	slots, err := h.appointmentService.ListSlots(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Success(c, slots)
+}
+
+// CreateSlot adds a time slot to an appointment product
+// POST /admin/catalog/products/:id/appointment-slots
+func (h *AppointmentHandler) CreateSlot(c *gin.Context) {
+	var req AppointmentSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	slot := &services.AppointmentSlot{
+		ProductID: c.Param("id"),
+		StartsAt:  req.StartsAt.UTC(),
+		EndsAt:    req.EndsAt.UTC(),
+		Capacity:  req.Capacity,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+	if err := h.appointmentService.CreateSlot(c.Request.Context(), slot); err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Created(c, slot)
+}
+
+// GetSlot retrieves an appointment slot with its booked seats
+// GET /admin/appointment-slots/:id
+func (h *AppointmentHandler) GetSlot(c *gin.Context) {
+	slot, err := h.appointmentService.GetSlot(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, slot)
+}
+
+// UpdateSlot changes an appointment slot's times, capacity or whether it is open for booking
+// PUT /admin/appointment-slots/:id
+func (h *AppointmentHandler) UpdateSlot(c *gin.Context) {
+	var req AppointmentSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.appointmentService.GetSlot(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	slot, err := h.appointmentService.UpdateSlot(c.Request.Context(), current.ID, services.AppointmentSlot{
+		StartsAt: req.StartsAt.UTC(),
+		EndsAt:   req.EndsAt.UTC(),
+		Capacity: req.Capacity,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	})
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, slot)
+}
+
+// ListSlotBookings lists the bookings of an appointment slot
+// GET /admin/appointment-slots/:id/bookings
+func (h *AppointmentHandler) ListSlotBookings(c *gin.Context) {
+	bookings, err := h.appointmentService.SlotBookings(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Success(c, bookings)
+}
+
+// GetOrderAppointments lists the appointments an order booked. Customers may see their own
+// orders' appointments; admin, manager and customer experience roles any order's.
+// GET /orders/:id/appointments
+// GET /admin/orders/:id/appointments
+func (h *AppointmentHandler) GetOrderAppointments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, orders.ErrOrderNotFound) {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+	if order.UserID != userID && !hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience)) {
+		response.Forbidden(c, "You don't have permission to view this order's appointments")
+		return
+	}
+
+	bookings, err := h.appointmentService.OrderBookings(c.Request.Context(), order.ID)
+	if err != nil {
+		respondAppointmentError(c, err)
+		return
+	}
+
+	response.Success(c, bookings)
+}
+
+// slotWindow reads the from (YYYY-MM-DD, default today) and days query parameters of slot
+// listings, responding with a 400 when either is invalid
+func slotWindow(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsed, err := time.Parse("2006-01-02", fromParam)
+		if err != nil {
+			response.BadRequest(c, "from must be a date in YYYY-MM-DD format")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	days := services.DefaultAppointmentWindowDays
+	if daysParam := c.Query("days"); daysParam != "" {
+		var params struct {
+			Days int `form:"days" binding:"min=1,max=60"`
+		}
+		if err := c.ShouldBindQuery(&params); err != nil {
+			response.BadRequest(c, "days must be between 1 and 60")
+			return time.Time{}, time.Time{}, false
+		}
+		days = params.Days
+	}
+	return from, from.AddDate(0, 0, days), true
+}
+
+// isAppointmentError reports whether err is about an appointment item's slot, for
+// endpoints that also return other errors
+func isAppointmentError(err error) bool {
+	return errors.Is(err, services.ErrAppointmentSlotRequired) ||
+		errors.Is(err, services.ErrAppointmentSlotUnavailable) ||
+		errors.Is(err, services.ErrAppointmentSlotFull) ||
+		errors.Is(err, services.ErrAppointmentInCart) ||
+		errors.Is(err, services.ErrAppointmentCancelWindowPassed)
+}
+
+// respondAppointmentError maps appointment errors to HTTP responses
+func respondAppointmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAppointmentSlotRequired),
+		errors.Is(err, services.ErrInvalidAppointmentSlot),
+		errors.Is(err, services.ErrInvalidAppointmentProduct):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrAppointmentSlotUnavailable),
+		errors.Is(err, services.ErrAppointmentSlotFull),
+		errors.Is(err, services.ErrAppointmentInCart),
+		errors.Is(err, services.ErrAppointmentCancelWindowPassed):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrAppointmentProductNotFound):
+		response.NotFound(c, "Product is not bookable by appointment")
+	case errors.Is(err, services.ErrAppointmentSlotNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// defaultReportDays is how many days a channel report covers when no range is given
+const defaultReportDays = 30
+
+// AttributionHandler handles the order channel attribution report
+type AttributionHandler struct {
+	attributionService *services.OrderAttributionService
+}
+
+// NewAttributionHandler creates a new AttributionHandler
+func NewAttributionHandler(attributionService *services.OrderAttributionService) *AttributionHandler {
+	return &AttributionHandler{
+		attributionService: attributionService,
+	}
+}
+
+// GetChannelReport reports revenue per order channel and UTM campaign, by default for the
+// last 30 days
+// GET /admin/reports/channels?from=2026-10-01&to=2026-10-31
+func (h *AttributionHandler) GetChannelReport(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -(defaultReportDays - 1))
+	for param, day := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				response.BadRequest(c, param+" must be in YYYY-MM-DD format")
+				return
+			}
+			*day = parsed
+		}
+	}
+
+	report, err := h.attributionService.Report(c.Request.Context(), from, to)
+	if err != nil {
+		if err == services.ErrInvalidReportRange {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
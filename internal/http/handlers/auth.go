@@ -1,15 +1,23 @@
 package handlers
 
 import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/devchuckcamp/goauthx"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	authService *goauthx.Service
+	authService     *goauthx.Service
+	securityService *services.LoginSecurityService
+	guestSessions   *services.GuestSessionService
 }
 
 // NewAuthHandler creates a new AuthHandler
@@ -19,12 +27,26 @@ func NewAuthHandler(authService *goauthx.Service) *AuthHandler {
 	}
 }
 
+// WithLoginSecurity locks accounts after repeated failed logins and alerts customers who
+// sign in from a new device
+func (h *AuthHandler) WithLoginSecurity(securityService *services.LoginSecurityService) *AuthHandler {
+	h.securityService = securityService
+	return h
+}
+
+// WithGuestSessions hands the cart, orders and recently viewed products of a guest session,
+// sent in the X-Guest-Session header, to the account that registers or signs in
+func (h *AuthHandler) WithGuestSessions(guestSessions *services.GuestSessionService) *AuthHandler {
+	h.guestSessions = guestSessions
+	return h
+}
+
 // Register handles user registration
 // POST /auth/register
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req goauthx.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -34,10 +56,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			response.Conflict(c, "Email already exists")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
+	h.claimGuestSession(c, authResp.User.ID)
+
 	response.Created(c, gin.H{
 		"user":          authResp.User,
 		"access_token":  authResp.AccessToken,
@@ -51,13 +75,27 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req goauthx.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
-	authResp, err := h.authService.Login(c.Request.Context(), req)
+	ctx := c.Request.Context()
+	if h.securityService != nil {
+		if err := h.securityService.CheckLocked(ctx, req.Email); err != nil {
+			respondLoginSecurityError(c, err)
+			return
+		}
+	}
+
+	authResp, err := h.authService.Login(ctx, req)
 	if err != nil {
 		if err == goauthx.ErrInvalidCredentials || err == goauthx.ErrUserNotFound {
+			if h.securityService != nil {
+				if err := h.securityService.RecordFailure(ctx, req.Email); err != nil {
+					respondLoginSecurityError(c, err)
+					return
+				}
+			}
 			response.Unauthorized(c, "Invalid credentials")
 			return
 		}
@@ -65,10 +103,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			response.Forbidden(c, "Account is inactive")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
+	// The customer is signed in either way; a failure here only costs the device record
+	if h.securityService != nil {
+		if err := h.securityService.RecordLogin(ctx, authResp.User.ID, authResp.User.Email, c.Request.UserAgent(), c.ClientIP()); err != nil {
+			log.Printf("Failed to record login for user %s: %v", authResp.User.ID, err)
+		}
+	}
+
+	h.claimGuestSession(c, authResp.User.ID)
+
 	response.Success(c, gin.H{
 		"user":          authResp.User,
 		"access_token":  authResp.AccessToken,
@@ -105,7 +152,7 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req goauthx.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
@@ -115,7 +162,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 			response.Unauthorized(c, "Invalid refresh token")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -136,7 +183,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 
 	if err := h.authService.Logout(c.Request.Context(), userID); err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -156,7 +203,7 @@ func (h *AuthHandler) GoogleOAuthURL(c *gin.Context) {
 		State: state,
 	})
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -189,7 +236,7 @@ func (h *AuthHandler) GoogleOAuthCallback(c *gin.Context) {
 		State: state,
 	})
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -200,3 +247,31 @@ func (h *AuthHandler) GoogleOAuthCallback(c *gin.Context) {
 		"expires_at":    authResp.ExpiresAt,
 	})
 }
+
+// claimGuestSession hands the guest session of the request, if any, to the account. The
+// customer is signed in either way, so a failure is only logged.
+func (h *AuthHandler) claimGuestSession(c *gin.Context, userID string) {
+	token := c.GetHeader(services.GuestSessionHeader)
+	if h.guestSessions == nil || token == "" {
+		return
+	}
+	sessionID, err := h.guestSessions.Verify(token)
+	if err != nil {
+		return
+	}
+	if _, err := h.guestSessions.Claim(c.Request.Context(), sessionID, userID); err != nil {
+		log.Printf("Failed to claim guest session %s for user %s: %v", sessionID, userID, err)
+	}
+}
+
+// respondLoginSecurityError maps account lockout errors to HTTP responses
+func respondLoginSecurityError(c *gin.Context, err error) {
+	if lockedErr, ok := err.(*services.AccountLockedError); ok {
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(lockedErr.Until).Seconds())+1))
+		response.ErrorWithDetails(c, http.StatusTooManyRequests, "account_locked", "Too many failed logins; try again later", gin.H{
+			"locked_until": lockedErr.Until,
+		})
+		return
+	}
+	response.FromError(c, err)
+}
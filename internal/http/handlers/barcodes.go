@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// BarcodeHandler handles variant barcode endpoints
+type BarcodeHandler struct {
+	barcodeService *services.BarcodeService
+}
+
+// NewBarcodeHandler creates a new BarcodeHandler
+func NewBarcodeHandler(barcodeService *services.BarcodeService) *BarcodeHandler {
+	return &BarcodeHandler{
+		barcodeService: barcodeService,
+	}
+}
+
+// BarcodeRequest represents the request to assign a barcode to a variant
+type BarcodeRequest struct {
+	Barcode string `json:"barcode" binding:"required"`
+}
+
+// VariantBarcodeResponse is a variant's barcode as stored, 14 digits or "" for none
+type VariantBarcodeResponse struct {
+	VariantID string `json:"variant_id"`
+	Barcode   string `json:"barcode"`
+}
+
+// LookupBarcode finds the variant a scanned GTIN/EAN/UPC barcode belongs to
+// GET /catalog/variants/barcode/:code
+func (h *BarcodeHandler) LookupBarcode(c *gin.Context) {
+	scanned, err := h.barcodeService.Lookup(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		switch err {
+		case services.ErrInvalidBarcode:
+			response.BadRequest(c, err.Error())
+		case services.ErrBarcodeNotFound:
+			response.NotFound(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, scanned)
+}
+
+// GetVariantBarcode returns a variant's barcode
+// GET /admin/catalog/variants/:id/barcode
+func (h *BarcodeHandler) GetVariantBarcode(c *gin.Context) {
+	variantID := c.Param("id")
+	barcode, err := h.barcodeService.GetBarcode(c.Request.Context(), variantID)
+	if err != nil {
+		if err == services.ErrVariantNotFound {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, VariantBarcodeResponse{VariantID: variantID, Barcode: barcode})
+}
+
+// SetVariantBarcode assigns a barcode to a variant
+// PUT /admin/catalog/variants/:id/barcode
+func (h *BarcodeHandler) SetVariantBarcode(c *gin.Context) {
+	var req BarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	variantID := c.Param("id")
+	barcode, err := h.barcodeService.SetBarcode(c.Request.Context(), variantID, req.Barcode)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidBarcode:
+			response.BadRequest(c, err.Error())
+		case services.ErrVariantNotFound:
+			response.NotFound(c, err.Error())
+		case services.ErrBarcodeTaken:
+			response.Conflict(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, VariantBarcodeResponse{VariantID: variantID, Barcode: barcode})
+}
+
+// DeleteVariantBarcode removes a variant's barcode
+// DELETE /admin/catalog/variants/:id/barcode
+func (h *BarcodeHandler) DeleteVariantBarcode(c *gin.Context) {
+	if err := h.barcodeService.RemoveBarcode(c.Request.Context(), c.Param("id")); err != nil {
+		if err == services.ErrVariantNotFound {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+// BotTrafficHandler reports on automated traffic turned away by the bot guard
+type BotTrafficHandler struct {
+	botGuard *middleware.BotGuard
+}
+
+// NewBotTrafficHandler creates a new BotTrafficHandler; botGuard is nil when bot detection is disabled
+func NewBotTrafficHandler(botGuard *middleware.BotGuard) *BotTrafficHandler {
+	return &BotTrafficHandler{
+		botGuard: botGuard,
+	}
+}
+
+// BotTrafficResponse reports whether bot detection is on and what it has blocked
+type BotTrafficResponse struct {
+	Enabled bool                 `json:"enabled"`
+	Stats   *middleware.BotStats `json:"stats,omitempty"`
+}
+
+// GetBotTraffic returns counts of blocked and throttled requests since startup
+// GET /admin/bot-traffic
+func (h *BotTrafficHandler) GetBotTraffic(c *gin.Context) {
+	if h.botGuard == nil {
+		response.Success(c, BotTrafficResponse{Enabled: false})
+		return
+	}
+
+	stats := h.botGuard.Stats()
+	response.Success(c, BotTrafficResponse{Enabled: true, Stats: &stats})
+}
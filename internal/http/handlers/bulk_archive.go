@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// BulkArchiveHandler handles bulk archive endpoints for products and variants
+type BulkArchiveHandler struct {
+	bulkArchiveService *services.BulkArchiveService
+}
+
+// NewBulkArchiveHandler creates a new BulkArchiveHandler
+func NewBulkArchiveHandler(bulkArchiveService *services.BulkArchiveService) *BulkArchiveHandler {
+	return &BulkArchiveHandler{
+		bulkArchiveService: bulkArchiveService,
+	}
+}
+
+// BulkArchiveRequest lists the IDs to archive, or a filter selecting them
+type BulkArchiveRequest struct {
+	IDs    []string                   `json:"ids"`
+	Filter services.BulkArchiveFilter `json:"filter"`
+}
+
+// ArchiveProducts starts archiving products in the background
+// POST /admin/catalog/products/archive
+func (h *BulkArchiveHandler) ArchiveProducts(c *gin.Context) {
+	h.archive(c, h.bulkArchiveService.ArchiveProducts)
+}
+
+// ArchiveVariants starts archiving variants in the background
+// POST /admin/catalog/variants/archive
+func (h *BulkArchiveHandler) ArchiveVariants(c *gin.Context) {
+	h.archive(c, h.bulkArchiveService.ArchiveVariants)
+}
+
+// ListBulkJobs lists recent bulk archive jobs with their progress, newest first
+// GET /admin/catalog/bulk-jobs
+func (h *BulkArchiveHandler) ListBulkJobs(c *gin.Context) {
+	response.Success(c, h.bulkArchiveService.ListJobs(c.Request.Context()))
+}
+
+// GetBulkJob retrieves a bulk archive job's progress
+// GET /admin/catalog/bulk-jobs/:id
+func (h *BulkArchiveHandler) GetBulkJob(c *gin.Context) {
+	job, err := h.bulkArchiveService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondBulkArchiveError(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+func (h *BulkArchiveHandler) archive(c *gin.Context, start func(ctx context.Context, adminID string, ids []string, filter services.BulkArchiveFilter) (*services.BulkJob, error)) {
+	var req BulkArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(c)
+	job, err := start(c.Request.Context(), adminID, req.IDs, req.Filter)
+	if err != nil {
+		respondBulkArchiveError(c, err)
+		return
+	}
+
+	response.Accepted(c, job)
+}
+
+func respondBulkArchiveError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidBulkArchive):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrBulkJobNotFound):
+		response.NotFound(c, "Bulk job not found")
+	case errors.Is(err, jobs.ErrQueueFull):
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "queue_full", "Job queue is full, try again shortly")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// maxWarmProducts caps how many products one warm-up request may load
+const maxWarmProducts = 1000
+
+// CacheHandler lets admins inspect, bust and pre-warm the catalog caches
+type CacheHandler struct {
+	catalogService *services.CatalogService
+	warmer         *services.CacheWarmer
+	warmProducts   int
+	responseCache  *middleware.ResponseCache
+}
+
+// cacheStatsResponse adds the public response cache to the catalog cache statistics
+type cacheStatsResponse struct {
+	services.CatalogCacheStats
+	Responses *middleware.ResponseCacheStats `json:"responses"`
+}
+
+// NewCacheHandler creates a new CacheHandler.
+// warmProducts is the number of products a warm-up loads unless the request says otherwise.
+func NewCacheHandler(catalogService *services.CatalogService, warmer *services.CacheWarmer, warmProducts int) *CacheHandler {
+	return &CacheHandler{
+		catalogService: catalogService,
+		warmer:         warmer,
+		warmProducts:   warmProducts,
+	}
+}
+
+// WithResponseCache reports the public response cache along with the catalog caches.
+// Its responses are purged by the routes, not the handler.
+func (h *CacheHandler) WithResponseCache(responseCache *middleware.ResponseCache) *CacheHandler {
+	h.responseCache = responseCache
+	return h
+}
+
+// GetCacheStats reports cache sizes and hit counts; a cache that is off is null
+// GET /admin/cache
+func (h *CacheHandler) GetCacheStats(c *gin.Context) {
+	stats := cacheStatsResponse{CatalogCacheStats: h.catalogService.CacheStats()}
+	if h.responseCache != nil {
+		responses := h.responseCache.Stats()
+		stats.Responses = &responses
+	}
+	response.Success(c, stats)
+}
+
+// FlushCaches drops every cached product, category, brand and response
+// DELETE /admin/cache
+func (h *CacheHandler) FlushCaches(c *gin.Context) {
+	h.catalogService.FlushCaches()
+	response.NoContent(c)
+}
+
+// WarmCaches loads the category and brand lists and the best-selling products
+// POST /admin/cache/warm?products=100
+func (h *CacheHandler) WarmCaches(c *gin.Context) {
+	products := h.warmProducts
+	if raw := c.Query("products"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > maxWarmProducts {
+			response.BadRequest(c, "products must be between 0 and "+strconv.Itoa(maxWarmProducts))
+			return
+		}
+		products = n
+	}
+
+	result, err := h.warmer.Warm(c.Request.Context(), products)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// InvalidateProduct drops one product from the cache after it was edited
+// DELETE /admin/cache/products/:id
+func (h *CacheHandler) InvalidateProduct(c *gin.Context) {
+	h.catalogService.InvalidateProducts(c.Param("id"))
+	response.NoContent(c)
+}
+
+// FlushProducts drops every cached product
+// DELETE /admin/cache/products
+func (h *CacheHandler) FlushProducts(c *gin.Context) {
+	h.catalogService.FlushProductCache()
+	response.NoContent(c)
+}
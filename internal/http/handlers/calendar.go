@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CalendarHandler handles the business calendar endpoints
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+// NewCalendarHandler creates a new CalendarHandler
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// UpdateCalendarRequest represents the working week and order cutoff of the business calendar
+type UpdateCalendarRequest struct {
+	Timezone    string         `json:"timezone" binding:"required"`
+	WorkingDays []time.Weekday `json:"working_days" binding:"required,min=1,dive,min=0,max=6"` // 0 is Sunday
+	CutoffTime  string         `json:"cutoff_time" binding:"required"`                         // HH:MM
+}
+
+// AddHolidayRequest represents a day the business is closed
+type AddHolidayRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required"`
+}
+
+// GetCalendar retrieves the business calendar with its holidays
+// GET /admin/calendar
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	calendar, err := h.calendarService.Calendar(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, calendar)
+}
+
+// UpdateCalendar replaces the timezone, working days and order cutoff time
+// PUT /admin/calendar
+func (h *CalendarHandler) UpdateCalendar(c *gin.Context) {
+	var req UpdateCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.calendarService.Calendar(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	calendar, err := h.calendarService.UpdateCalendar(c.Request.Context(), req.Timezone, req.WorkingDays, req.CutoffTime)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, calendar)
+}
+
+// AddHoliday closes the business on a date
+// POST /admin/calendar/holidays
+func (h *CalendarHandler) AddHoliday(c *gin.Context) {
+	var req AddHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	holiday, err := h.calendarService.AddHoliday(c.Request.Context(), req.Date, req.Name)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	response.Created(c, holiday)
+}
+
+// DeleteHoliday reopens the business on a date
+// DELETE /admin/calendar/holidays/:date
+func (h *CalendarHandler) DeleteHoliday(c *gin.Context) {
+	if err := h.calendarService.RemoveHoliday(c.Request.Context(), c.Param("date")); err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondCalendarError maps business calendar errors to HTTP responses
+func respondCalendarError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidCalendar, services.ErrInvalidHoliday:
+		response.BadRequest(c, err.Error())
+	case services.ErrHolidayNotFound:
+		response.NotFound(c, "Holiday not found")
+	default:
+		response.FromError(c, err)
+	}
+}
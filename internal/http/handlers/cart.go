@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
@@ -12,6 +15,11 @@ import (
 // CartHandler handles cart endpoints
 type CartHandler struct {
 	cartService *services.CartService
+	waitingRoom *services.WaitingRoomService
+	metadata    *services.CartMetadataService
+	rules       *services.CheckoutRuleService
+	funnel      *services.FunnelService
+	orderBumps  *services.OrderBumpService
 }
 
 // NewCartHandler creates a new CartHandler
@@ -21,31 +29,122 @@ func NewCartHandler(cartService *services.CartService) *CartHandler {
 	}
 }
 
+// WithWaitingRoom requires an admitted waiting room token, sent in the X-Waiting-Room-Token
+// header, to add a limited-drop product to the cart
+func (h *CartHandler) WithWaitingRoom(waitingRoom *services.WaitingRoomService) *CartHandler {
+	h.waitingRoom = waitingRoom
+	return h
+}
+
+// WithMetadata lets clients attach metadata to the cart and its items
+func (h *CartHandler) WithMetadata(metadata *services.CartMetadataService) *CartHandler {
+	h.metadata = metadata
+	return h
+}
+
+// WithCheckoutRules lets clients check the cart against checkout rules before checking out
+func (h *CartHandler) WithCheckoutRules(rules *services.CheckoutRuleService) *CartHandler {
+	h.rules = rules
+	return h
+}
+
+// WithFunnel counts carts checked before checking out as reaching checkout preview
+func (h *CartHandler) WithFunnel(funnel *services.FunnelService) *CartHandler {
+	h.funnel = funnel
+	return h
+}
+
+// WithOrderBumps offers order bumps for the cart when it is checked before checking out
+func (h *CartHandler) WithOrderBumps(orderBumps *services.OrderBumpService) *CartHandler {
+	h.orderBumps = orderBumps
+	return h
+}
+
+// CartValidationResponse lists the checkout rules a cart fails and the order bumps offered
+// with it
+type CartValidationResponse struct {
+	Valid      bool                             `json:"valid"`
+	Violations []services.CheckoutRuleViolation `json:"violations"`
+	OrderBumps []services.OrderBumpOffer        `json:"order_bumps"`
+}
+
 // GetCart retrieves the current user's cart
 // GET /cart
 func (h *CartHandler) GetCart(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
+	if _, exists := middleware.GetUserID(c); !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
 
 	// Try to get existing cart or create new one
-	cart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	cart, err := shopperCart(c, h.cartService)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
 	response.Success(c, cart)
 }
 
+// ValidateCart checks the cart against the checkout rules and lists the order bumps the
+// customer can accept when placing the order. Shipping country restrictions are only
+// checked when a country is given.
+// GET /cart/validate?country=US
+func (h *CartHandler) ValidateCart(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	currentCart, err := shopperCart(c, h.cartService)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	violations := []services.CheckoutRuleViolation{}
+	if h.rules != nil {
+		if violations, err = h.rules.Evaluate(c.Request.Context(), currentCart, c.Query("country")); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	offers := []services.OrderBumpOffer{}
+	if h.orderBumps != nil {
+		if offers, err = h.orderBumps.Offers(c.Request.Context(), currentCart); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if h.funnel != nil && len(currentCart.Items) > 0 {
+		h.funnel.Record(c.Request.Context(), services.FunnelStageCheckoutPreview, currentCart.ID)
+	}
+
+	response.Success(c, CartValidationResponse{
+		Valid:      len(violations) == 0,
+		Violations: violations,
+		OrderBumps: offers,
+	})
+}
+
 // AddItemRequest represents the request to add an item to cart
 type AddItemRequest struct {
 	ProductID  string            `json:"product_id" binding:"required"`
 	VariantID  *string           `json:"variant_id"`
 	Quantity   int               `json:"quantity" binding:"required,gt=0"`
 	Attributes map[string]string `json:"attributes"`
+	Metadata   services.Metadata `json:"metadata"`
+	// Rental period of a product in rental mode, as YYYY-MM-DD dates, both included
+	RentalStart string `json:"rental_start"`
+	RentalEnd   string `json:"rental_end"`
+	// Slot booked for an appointment product; each unit is a seat in it
+	AppointmentSlotID string `json:"appointment_slot_id"`
+	// Amount in cents to pay for each unit of a donation product
+	DonationAmount *int64 `json:"donation_amount"`
+	// Item of a public wishlist the units are bought for, as a gift to its owner
+	WishlistItemID string `json:"wishlist_item_id"`
 }
 
 // AddItem adds an item to the cart
@@ -59,14 +158,34 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 
 	var req AddItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
+	if h.waitingRoom != nil {
+		token := c.GetHeader(services.WaitingRoomTokenHeader)
+		if err := h.waitingRoom.Authorize(c.Request.Context(), req.ProductID, userID, token); err != nil {
+			respondWaitingRoomError(c, err)
+			return
+		}
+	}
+
+	// Validate metadata up front so invalid metadata doesn't leave the item added without it
+	if len(req.Metadata) > 0 {
+		if h.metadata == nil {
+			response.BadRequest(c, "Metadata is not available")
+			return
+		}
+		if err := h.metadata.Validate(req.Metadata); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
 	// Get or create cart
-	currentCart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	currentCart, err := shopperCart(c, h.cartService)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -77,6 +196,31 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 		Quantity:   req.Quantity,
 		Attributes: req.Attributes,
 	}
+	if req.RentalStart != "" || req.RentalEnd != "" {
+		if addReq.Attributes == nil {
+			addReq.Attributes = make(map[string]string)
+		}
+		addReq.Attributes[services.RentalStartAttribute] = req.RentalStart
+		addReq.Attributes[services.RentalEndAttribute] = req.RentalEnd
+	}
+	if req.AppointmentSlotID != "" {
+		if addReq.Attributes == nil {
+			addReq.Attributes = make(map[string]string)
+		}
+		addReq.Attributes[services.AppointmentSlotAttribute] = req.AppointmentSlotID
+	}
+	if req.DonationAmount != nil {
+		if addReq.Attributes == nil {
+			addReq.Attributes = make(map[string]string)
+		}
+		addReq.Attributes[services.DonationAmountAttribute] = strconv.FormatInt(*req.DonationAmount, 10)
+	}
+	if req.WishlistItemID != "" {
+		if addReq.Attributes == nil {
+			addReq.Attributes = make(map[string]string)
+		}
+		addReq.Attributes[services.WishlistItemAttribute] = req.WishlistItemID
+	}
 
 	updatedCart, err := h.cartService.AddItem(c.Request.Context(), currentCart.ID, addReq)
 	if err != nil {
@@ -84,13 +228,67 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 			response.BadRequest(c, "Product is out of stock")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		if limitErr, ok := err.(*services.PurchaseLimitError); ok {
+			respondPurchaseLimitError(c, limitErr)
+			return
+		}
+		if isRentalError(err) {
+			respondRentalError(c, err)
+			return
+		}
+		if isAppointmentError(err) {
+			respondAppointmentError(c, err)
+			return
+		}
+		if isDonationError(err) {
+			respondDonationError(c, err)
+			return
+		}
+		if isRegistryError(err) {
+			respondWishlistError(c, err)
+			return
+		}
+		response.FromError(c, err)
 		return
 	}
 
+	if len(req.Metadata) > 0 {
+		if item := findAddedItem(updatedCart, req.ProductID, req.VariantID); item != nil {
+			if err := h.metadata.SetItemMetadata(c.Request.Context(), updatedCart, item.ID, req.Metadata); err != nil {
+				response.FromError(c, err)
+				return
+			}
+		}
+	}
+
 	response.Success(c, updatedCart)
 }
 
+// shopperCart gets or creates the cart of the signed-in user, or of the guest session for
+// guests
+func shopperCart(c *gin.Context, cartService *services.CartService) (*cart.Cart, error) {
+	if sessionID, ok := middleware.GetGuestSessionID(c); ok {
+		return cartService.GetOrCreateCart(c.Request.Context(), "", sessionID)
+	}
+	userID, _ := middleware.GetUserID(c)
+	return cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+}
+
+// findAddedItem finds the line an added product went to; new lines are appended, so the
+// last match is the one
+func findAddedItem(c *cart.Cart, productID string, variantID *string) *cart.CartItem {
+	for i := len(c.Items) - 1; i >= 0; i-- {
+		item := &c.Items[i]
+		if item.ProductID != productID || (item.VariantID == nil) != (variantID == nil) {
+			continue
+		}
+		if variantID == nil || *item.VariantID == *variantID {
+			return item
+		}
+	}
+	return nil
+}
+
 // UpdateItemQuantityRequest represents the request to update item quantity
 type UpdateItemQuantityRequest struct {
 	Quantity int `json:"quantity" binding:"required,gte=0"`
@@ -99,8 +297,7 @@ type UpdateItemQuantityRequest struct {
 // UpdateItemQuantity updates the quantity of an item in the cart
 // PATCH /cart/items/:id
 func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
+	if _, exists := middleware.GetUserID(c); !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
@@ -113,14 +310,14 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 
 	var req UpdateItemQuantityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
 	// Get cart
-	currentCart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	currentCart, err := shopperCart(c, h.cartService)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -131,7 +328,23 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 			response.NotFound(c, "Item not found in cart")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		if limitErr, ok := err.(*services.PurchaseLimitError); ok {
+			respondPurchaseLimitError(c, limitErr)
+			return
+		}
+		if isRentalError(err) {
+			respondRentalError(c, err)
+			return
+		}
+		if isAppointmentError(err) {
+			respondAppointmentError(c, err)
+			return
+		}
+		if isRegistryError(err) {
+			respondWishlistError(c, err)
+			return
+		}
+		response.FromError(c, err)
 		return
 	}
 
@@ -141,8 +354,7 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 // RemoveItem removes an item from the cart
 // DELETE /cart/items/:id
 func (h *CartHandler) RemoveItem(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
+	if _, exists := middleware.GetUserID(c); !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
@@ -154,9 +366,9 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 	}
 
 	// Get cart
-	currentCart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	currentCart, err := shopperCart(c, h.cartService)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -167,7 +379,7 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 			response.NotFound(c, "Item not found in cart")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -177,25 +389,124 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 // ClearCart clears all items from the cart
 // DELETE /cart
 func (h *CartHandler) ClearCart(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
+	if _, exists := middleware.GetUserID(c); !exists {
 		response.Unauthorized(c, "User not authenticated")
 		return
 	}
 
 	// Get cart
-	currentCart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	currentCart, err := shopperCart(c, h.cartService)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
 	// Clear cart
 	updatedCart, err := h.cartService.Clear(c.Request.Context(), currentCart.ID)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
 	response.Success(c, updatedCart)
 }
+
+// MetadataRequest represents the request to replace cart or cart item metadata
+type MetadataRequest struct {
+	Metadata services.Metadata `json:"metadata"` // empty clears it
+}
+
+// GetCartMetadata retrieves the metadata of the current user's cart and its items
+// GET /cart/metadata
+func (h *CartHandler) GetCartMetadata(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	currentCart, err := shopperCart(c, h.cartService)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	h.respondCartMetadata(c, currentCart.ID)
+}
+
+// SetCartMetadata replaces the metadata of the current user's cart
+// PUT /cart/metadata
+func (h *CartHandler) SetCartMetadata(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req MetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	currentCart, err := shopperCart(c, h.cartService)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	if err := h.metadata.SetCartMetadata(c.Request.Context(), currentCart.ID, req.Metadata); err != nil {
+		respondMetadataError(c, err)
+		return
+	}
+
+	h.respondCartMetadata(c, currentCart.ID)
+}
+
+// SetItemMetadata replaces the metadata of an item in the current user's cart
+// PUT /cart/items/:id/metadata
+func (h *CartHandler) SetItemMetadata(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req MetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	currentCart, err := shopperCart(c, h.cartService)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	if err := h.metadata.SetItemMetadata(c.Request.Context(), currentCart, c.Param("id"), req.Metadata); err != nil {
+		respondMetadataError(c, err)
+		return
+	}
+
+	h.respondCartMetadata(c, currentCart.ID)
+}
+
+// respondCartMetadata responds with the cart's current metadata
+func (h *CartHandler) respondCartMetadata(c *gin.Context, cartID string) {
+	metadata, err := h.metadata.GetCartMetadata(c.Request.Context(), cartID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, metadata)
+}
+
+func respondMetadataError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMetadata):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, cart.ErrItemNotFound):
+		response.NotFound(c, "Item not found in cart")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
 	"github.com/devchuckcamp/gocommerce-api/internal/services"
 	"github.com/devchuckcamp/gocommerce/catalog"
@@ -10,7 +14,8 @@ import (
 
 // CatalogHandler handles catalog endpoints
 type CatalogHandler struct {
-	catalogService *services.CatalogService
+	catalogService       *services.CatalogService
+	categoryCountService *services.CategoryCountService
 }
 
 // NewCatalogHandler creates a new CatalogHandler
@@ -20,8 +25,14 @@ func NewCatalogHandler(catalogService *services.CatalogService) *CatalogHandler
 	}
 }
 
-// ListProducts lists all products with pagination and search
-// GET /products?page=1&page_size=20&keyword=laptop
+// WithCategoryCounts serves the category tree with maintained product counts
+func (h *CatalogHandler) WithCategoryCounts(categoryCountService *services.CategoryCountService) *CatalogHandler {
+	h.categoryCountService = categoryCountService
+	return h
+}
+
+// ListProducts lists all products with pagination and search, newest first unless sorted
+// GET /products?page=1&page_size=20&keyword=laptop&sort=-price,name&fields=id,name,base_price&currency=EUR
 func (h *CatalogHandler) ListProducts(c *gin.Context) {
 	// Get pagination parameters
 	params := response.GetPaginationParams(c)
@@ -29,34 +40,73 @@ func (h *CatalogHandler) ListProducts(c *gin.Context) {
 	// Get search keyword
 	keyword := c.Query("keyword")
 
+	// Optional sparse fieldset, e.g. ?fields=id,name,base_price
+	fields, err := response.ParseFields(c, services.ProductFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// Optional sort, e.g. ?sort=-price,name
+	sort, err := services.ParseSort(c.Query("sort"), services.ProductSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
 	active := catalog.ProductStatus("active")
 	filter := catalog.ProductFilter{
 		Status: &active,
+		SortBy: sort.String(),
 		Limit:  params.CalculateLimit(),
 		Offset: params.CalculateOffset(),
 	}
 
 	// Get products (with search if keyword provided)
-	products, err := h.catalogService.SearchProducts(c.Request.Context(), keyword, filter)
+	products, err := h.catalogService.SearchProductFields(c.Request.Context(), keyword, filter, fields)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
+		return
+	}
+
+	// A search that found nothing is retried with its spelling corrected
+	var search *response.SearchMeta
+	if keyword != "" && len(products) == 0 && params.Page == 1 {
+		corrected, correctedProducts, err := h.catalogService.CorrectedSearch(c.Request.Context(), keyword, filter, fields)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		if corrected != "" {
+			products = correctedProducts
+			search = &response.SearchMeta{Query: keyword, CorrectedQuery: corrected}
+		}
+	}
+	products, ok := h.inCurrency(c, products)
+	if !ok {
 		return
 	}
 
 	// Get total count
 	total, err := h.catalogService.CountProducts(c.Request.Context(), filter)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
 	// Build pagination metadata
 	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
-	response.SuccessWithPagination(c, products, meta)
+	meta.Search = search
+	middleware.AddSurrogateKeys(c, productSurrogateKeys(products)...)
+	if keyword != "" {
+		// Searches change with the merchandising rules as well as the products
+		middleware.AddSurrogateKeys(c, "search")
+	}
+	response.SuccessWithPagination(c, response.SelectFields(products, fields, services.ProductFields), meta)
 }
 
 // GetProduct retrieves a single product by ID
-// GET /products/:id
+// GET /products/:id?currency=EUR
 func (h *CatalogHandler) GetProduct(c *gin.Context) {
 	productID := c.Param("id")
 	if productID == "" {
@@ -69,12 +119,16 @@ func (h *CatalogHandler) GetProduct(c *gin.Context) {
 		response.NotFound(c, "Product not found")
 		return
 	}
+	products, ok := h.inCurrency(c, []*services.ProductResponse{product})
+	if !ok {
+		return
+	}
 
-	response.Success(c, product)
+	response.Success(c, products[0])
 }
 
 // GetProductsByCategory retrieves products by category with pagination
-// GET /products/category/:id?page=1&page_size=20
+// GET /products/category/:id?page=1&page_size=20&sort=name&fields=id,name,base_price&currency=EUR
 func (h *CatalogHandler) GetProductsByCategory(c *gin.Context) {
 	categoryID := c.Param("id")
 	if categoryID == "" {
@@ -82,6 +136,20 @@ func (h *CatalogHandler) GetProductsByCategory(c *gin.Context) {
 		return
 	}
 
+	// Optional sparse fieldset, e.g. ?fields=id,name,base_price
+	fields, err := response.ParseFields(c, services.ProductFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// Optional sort, e.g. ?sort=-price,name
+	sort, err := services.ParseSort(c.Query("sort"), services.ProductSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
 	// Get pagination parameters
 	params := response.GetPaginationParams(c)
 
@@ -89,27 +157,68 @@ func (h *CatalogHandler) GetProductsByCategory(c *gin.Context) {
 	filter := catalog.ProductFilter{
 		Status:      &active,
 		CategoryIDs: []string{categoryID},
+		SortBy:      sort.String(),
 		Limit:       params.CalculateLimit(),
 		Offset:      params.CalculateOffset(),
 	}
 
 	// Get products
-	products, err := h.catalogService.GetProductsByCategory(c.Request.Context(), categoryID, filter)
+	products, err := h.catalogService.GetProductsByCategoryFields(c.Request.Context(), categoryID, filter, fields)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
+		return
+	}
+	products, ok := h.inCurrency(c, products)
+	if !ok {
 		return
 	}
 
 	// Get total count for this category
 	total, err := h.catalogService.CountProducts(c.Request.Context(), filter)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
 	// Build pagination metadata
 	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
-	response.SuccessWithPagination(c, products, meta)
+	middleware.AddSurrogateKeys(c, productSurrogateKeys(products)...)
+	response.SuccessWithPagination(c, response.SelectFields(products, fields, services.ProductFields), meta)
+}
+
+// productSurrogateKeys tags a listing with its products, so changing one purges the listing
+func productSurrogateKeys(products []*services.ProductResponse) []string {
+	keys := make([]string, 0, len(products))
+	for _, product := range products {
+		if product.ID != "" {
+			keys = append(keys, "product:"+product.ID)
+		}
+	}
+	return keys
+}
+
+// inCurrency converts product prices into the ?currency= display currency when one is
+// given, responding 400 if it can't be converted into
+func (h *CatalogHandler) inCurrency(c *gin.Context, products []*services.ProductResponse) ([]*services.ProductResponse, bool) {
+	currency := strings.ToUpper(c.Query("currency"))
+	if currency == "" {
+		return products, true
+	}
+	if len(currency) != 3 {
+		response.BadRequest(c, "currency must be a three-letter currency code")
+		return nil, false
+	}
+
+	converted, err := h.catalogService.InCurrency(products, currency)
+	if err != nil {
+		if errors.Is(err, services.ErrExchangeRateNotFound) {
+			response.BadRequest(c, err.Error())
+			return nil, false
+		}
+		response.FromError(c, err)
+		return nil, false
+	}
+	return converted, true
 }
 
 // ListCategories lists all categories with pagination
@@ -121,7 +230,7 @@ func (h *CatalogHandler) ListCategories(c *gin.Context) {
 	// Get all categories (gocommerce doesn't have pagination for categories yet)
 	categories, err := h.catalogService.GetCategories(c.Request.Context())
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -142,6 +251,23 @@ func (h *CatalogHandler) ListCategories(c *gin.Context) {
 	response.SuccessWithPagination(c, paginatedCategories, meta)
 }
 
+// GetCategoryTree returns the categories as a tree with their active product counts
+// GET /categories/tree
+func (h *CatalogHandler) GetCategoryTree(c *gin.Context) {
+	if h.categoryCountService == nil {
+		response.NotFound(c, "Category tree is not available")
+		return
+	}
+
+	tree, err := h.categoryCountService.Tree(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, tree)
+}
+
 // ListBrands lists all brands with pagination
 // GET /brands?page=1&page_size=20
 func (h *CatalogHandler) ListBrands(c *gin.Context) {
@@ -151,7 +277,7 @@ func (h *CatalogHandler) ListBrands(c *gin.Context) {
 	// Get all brands (gocommerce doesn't have pagination for brands yet)
 	brands, err := h.catalogService.GetBrands(c.Request.Context())
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CatalogHistoryHandler handles product change history endpoints
+type CatalogHistoryHandler struct {
+	historyService *services.CatalogHistoryService
+}
+
+// NewCatalogHistoryHandler creates a new CatalogHistoryHandler
+func NewCatalogHistoryHandler(historyService *services.CatalogHistoryService) *CatalogHistoryHandler {
+	return &CatalogHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// ListProductHistory lists the recorded changes to a product and its variants, newest first
+// GET /admin/catalog/products/:id/history?page=1&page_size=20
+func (h *CatalogHistoryHandler) ListProductHistory(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	productID := c.Param("id")
+
+	versions, err := h.historyService.ListHistory(c.Request.Context(), productID, params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.historyService.CountHistory(c.Request.Context(), productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, versions, meta)
+}
+
+// GetProductVersion retrieves one recorded change to a product
+// GET /admin/catalog/products/:id/history/:version
+func (h *CatalogHistoryHandler) GetProductVersion(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+
+	recorded, err := h.historyService.GetVersion(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		respondCatalogHistoryError(c, err)
+		return
+	}
+
+	response.Success(c, recorded)
+}
+
+// RevertProduct restores a product and its variants to how they were right after a version
+// POST /admin/catalog/products/:id/history/:version/revert
+func (h *CatalogHistoryHandler) RevertProduct(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	reverted, err := h.historyService.Revert(c.Request.Context(), c.Param("id"), version, userID)
+	if err != nil {
+		respondCatalogHistoryError(c, err)
+		return
+	}
+
+	response.Success(c, reverted)
+}
+
+// versionParam parses the :version path parameter, responding 400 if it isn't a version number
+func versionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.BadRequest(c, "version must be a positive number")
+		return 0, false
+	}
+	return version, true
+}
+
+// respondCatalogHistoryError maps catalog history errors to HTTP responses
+func respondCatalogHistoryError(c *gin.Context, err error) {
+	var held *services.PriceChangeHeldError
+	if errors.As(err, &held) {
+		// The suspicious price is saved once an admin approves the anomaly
+		response.Accepted(c, held.Anomaly)
+		return
+	}
+	switch err {
+	case services.ErrCatalogVersionNotFound:
+		response.NotFound(c, "Version not found")
+	case services.ErrNothingToRevert:
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
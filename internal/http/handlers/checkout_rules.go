@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// CheckoutRuleHandler handles checkout rule endpoints
+type CheckoutRuleHandler struct {
+	ruleService *services.CheckoutRuleService
+}
+
+// NewCheckoutRuleHandler creates a new CheckoutRuleHandler
+func NewCheckoutRuleHandler(ruleService *services.CheckoutRuleService) *CheckoutRuleHandler {
+	return &CheckoutRuleHandler{
+		ruleService: ruleService,
+	}
+}
+
+// CheckoutRuleRequest represents the request to create or update a checkout rule
+type CheckoutRuleRequest struct {
+	Name           string   `json:"name" binding:"required,max=255"`
+	Type           string   `json:"type" binding:"required,oneof=min_order_value restricted_countries quantity_multiple"`
+	ProductID      string   `json:"product_id"`       // restricted_countries and quantity_multiple
+	MinOrderAmount *int64   `json:"min_order_amount"` // min_order_value, in cents
+	Currency       string   `json:"currency" binding:"omitempty,len=3"`
+	Countries      []string `json:"countries"` // restricted_countries
+	Multiple       int      `json:"multiple"`  // quantity_multiple
+	IsActive       *bool    `json:"is_active"` // defaults to true
+}
+
+// toCheckoutRule applies the request onto a checkout rule
+func (r *CheckoutRuleRequest) toCheckoutRule(rule *services.CheckoutRule) {
+	rule.Name = r.Name
+	rule.Type = services.CheckoutRuleType(r.Type)
+	rule.ProductID = r.ProductID
+	rule.MinOrderValue = nil
+	if r.MinOrderAmount != nil {
+		rule.MinOrderValue = &money.Money{Amount: *r.MinOrderAmount, Currency: r.Currency}
+	}
+	rule.Countries = r.Countries
+	rule.Multiple = r.Multiple
+	rule.IsActive = r.IsActive == nil || *r.IsActive
+}
+
+// ListCheckoutRules lists every checkout rule
+// GET /admin/checkout-rules
+func (h *CheckoutRuleHandler) ListCheckoutRules(c *gin.Context) {
+	rules, err := h.ruleService.ListRules(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// GetCheckoutRule retrieves a checkout rule
+// GET /admin/checkout-rules/:id
+func (h *CheckoutRuleHandler) GetCheckoutRule(c *gin.Context) {
+	rule, err := h.ruleService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, rule)
+}
+
+// CreateCheckoutRule creates a checkout rule
+// POST /admin/checkout-rules
+func (h *CheckoutRuleHandler) CreateCheckoutRule(c *gin.Context) {
+	var req CheckoutRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	rule := &services.CheckoutRule{}
+	req.toCheckoutRule(rule)
+	if err := h.ruleService.CreateRule(c.Request.Context(), rule); err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+
+	response.Created(c, rule)
+}
+
+// UpdateCheckoutRule replaces a checkout rule
+// PUT /admin/checkout-rules/:id
+func (h *CheckoutRuleHandler) UpdateCheckoutRule(c *gin.Context) {
+	var req CheckoutRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.ruleService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	rule := &services.CheckoutRule{ID: c.Param("id")}
+	req.toCheckoutRule(rule)
+	if err := h.ruleService.UpdateRule(c.Request.Context(), rule); err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored rule
+	if rule, err = h.ruleService.GetRule(c.Request.Context(), rule.ID); err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, rule)
+}
+
+// DeleteCheckoutRule deletes a checkout rule
+// DELETE /admin/checkout-rules/:id
+func (h *CheckoutRuleHandler) DeleteCheckoutRule(c *gin.Context) {
+	if err := h.ruleService.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		respondCheckoutRuleAdminError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+func respondCheckoutRuleAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCheckoutRuleNotFound):
+		response.NotFound(c, "Checkout rule not found")
+	case errors.Is(err, services.ErrInvalidCheckoutRule):
+		response.BadRequest(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
+
+// respondCheckoutRuleError maps failed checkout rules to a structured 422 response listing
+// every violation
+func respondCheckoutRuleError(c *gin.Context, ruleErr *services.CheckoutRuleError) {
+	response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "checkout_rules_failed", ruleErr.Error(), gin.H{
+		"violations": ruleErr.Violations,
+	})
+}
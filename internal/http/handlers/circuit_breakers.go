@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+// CircuitBreakerHandler reports on the circuit breakers in front of external providers
+type CircuitBreakerHandler struct {
+	breakers []*breaker.Breaker
+}
+
+// NewCircuitBreakerHandler creates a new CircuitBreakerHandler
+func NewCircuitBreakerHandler(breakers []*breaker.Breaker) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{
+		breakers: breakers,
+	}
+}
+
+// ListCircuitBreakers returns each provider's breaker state and call counts since startup
+// GET /admin/circuit-breakers
+func (h *CircuitBreakerHandler) ListCircuitBreakers(c *gin.Context) {
+	statuses := make([]breaker.Status, len(h.breakers))
+	for i, b := range h.breakers {
+		statuses[i] = b.Status()
+	}
+	response.Success(c, statuses)
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CollectionHandler handles product tag and collection endpoints
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+// NewCollectionHandler creates a new CollectionHandler
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CollectionRequest represents the request to create or update a collection
+type CollectionRequest struct {
+	Slug        string   `json:"slug" binding:"required,max=255"`
+	Title       string   `json:"title" binding:"required,max=255"`
+	Description string   `json:"description"`
+	Type        string   `json:"type" binding:"required,oneof=manual rule"`
+	Rule        string   `json:"rule"`
+	ProductIDs  []string `json:"product_ids"`
+	Status      string   `json:"status" binding:"omitempty,oneof=draft published"`
+}
+
+// ProductTagsRequest represents the request to replace a product's tags
+type ProductTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+// toCollection applies the request onto a collection
+func (r *CollectionRequest) toCollection(collection *services.Collection) {
+	collection.Slug = r.Slug
+	collection.Title = r.Title
+	collection.Description = r.Description
+	collection.Type = services.CollectionType(r.Type)
+	collection.Rule = r.Rule
+	collection.ProductIDs = r.ProductIDs
+	collection.Status = services.CollectionStatus(r.Status)
+}
+
+// GetPublishedCollection retrieves a published collection by slug
+// GET /catalog/collections/:slug
+func (h *CollectionHandler) GetPublishedCollection(c *gin.Context) {
+	collection, err := h.collectionService.GetPublishedCollection(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, collection)
+}
+
+// GetCollectionProducts lists the products in a published collection with pagination
+// GET /catalog/collections/:slug/products?page=1&page_size=20&fields=id,name,sale_price
+func (h *CollectionHandler) GetCollectionProducts(c *gin.Context) {
+	// Optional sparse fieldset, e.g. ?fields=id,name,base_price
+	fields, err := response.ParseFields(c, services.ProductFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.GetPublishedCollection(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	params := response.GetPaginationParams(c)
+	products, err := h.collectionService.ListProducts(c.Request.Context(), collection, params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.collectionService.CountProducts(c.Request.Context(), collection)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	middleware.AddSurrogateKeys(c, productSurrogateKeys(products)...)
+	response.SuccessWithPagination(c, response.SelectFields(products, fields, services.ProductFields), meta)
+}
+
+// ListCollections lists collections, including drafts
+// GET /admin/collections?status=draft&sort=-updated_at&page=1&page_size=20
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.CollectionSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	filter := services.CollectionFilter{
+		Status: services.CollectionStatus(c.Query("status")),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	}
+
+	collections, err := h.collectionService.ListCollections(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.collectionService.CountCollections(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, collections, meta)
+}
+
+// GetCollection retrieves a collection by ID
+// GET /admin/collections/:id
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	collection, err := h.collectionService.GetCollection(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, collection)
+}
+
+// CreateCollection creates a collection
+// POST /admin/collections
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	collection := &services.Collection{}
+	req.toCollection(collection)
+
+	if err := h.collectionService.SaveCollection(c.Request.Context(), collection); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	response.Created(c, collection)
+}
+
+// UpdateCollection replaces a collection
+// PUT /admin/collections/:id
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	if !response.IfMatch(c, collection) {
+		return
+	}
+
+	req.toCollection(collection)
+	if err := h.collectionService.SaveCollection(c.Request.Context(), collection); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored collection
+	if collection, err = h.collectionService.GetCollection(c.Request.Context(), collection.ID); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, collection)
+}
+
+// DeleteCollection deletes a collection
+// DELETE /admin/collections/:id
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), c.Param("id")); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// GetProductTags retrieves a product's tags
+// GET /admin/catalog/products/:id/tags
+func (h *CollectionHandler) GetProductTags(c *gin.Context) {
+	tags, err := h.collectionService.GetProductTags(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"product_id": c.Param("id"), "tags": tags})
+}
+
+// SetProductTags replaces a product's tags
+// PUT /admin/catalog/products/:id/tags
+func (h *CollectionHandler) SetProductTags(c *gin.Context) {
+	var req ProductTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	tags, err := h.collectionService.SetProductTags(c.Request.Context(), c.Param("id"), req.Tags)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"product_id": c.Param("id"), "tags": tags})
+}
+
+// respondCollectionError maps collection errors to HTTP responses
+func respondCollectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCollectionNotFound):
+		response.NotFound(c, "Collection not found")
+	case errors.Is(err, services.ErrInvalidCollection), errors.Is(err, services.ErrInvalidTag):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrCollectionSlugTaken):
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,422 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// CompanyHandler handles company accounts: admin management, and the member endpoints for
+// the shared address book, order history and order approvals
+type CompanyHandler struct {
+	companyService *services.CompanyService
+}
+
+// NewCompanyHandler creates a new CompanyHandler
+func NewCompanyHandler(companyService *services.CompanyService) *CompanyHandler {
+	return &CompanyHandler{
+		companyService: companyService,
+	}
+}
+
+// CompanyRequest represents the request to create or update a company
+type CompanyRequest struct {
+	Name              string `json:"name" binding:"required,max=255"`
+	ApprovalThreshold *int64 `json:"approval_threshold" binding:"omitempty,gte=0"`   // in cents; omit when no order needs approval
+	Currency          string `json:"currency" binding:"omitempty,len=3"`             // currency of the approval threshold
+	NetTermsDays      int    `json:"net_terms_days" binding:"omitempty,oneof=30 60"` // lets members pay by invoice; omit for none
+}
+
+// CompanyMemberRequest represents the request to add a member or change their role
+type CompanyMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=buyer approver"`
+}
+
+// CompanyAddressRequest represents the request to add or update an address book entry
+type CompanyAddressRequest struct {
+	Label   string         `json:"label" binding:"max=100"`
+	Address AddressRequest `json:"address" binding:"required"`
+}
+
+// RejectCompanyOrderRequest represents the request to reject an order awaiting approval
+type RejectCompanyOrderRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// toInput converts the request to company fields
+func (r *CompanyRequest) toInput() services.CompanyInput {
+	input := services.CompanyInput{Name: r.Name, NetTermsDays: r.NetTermsDays}
+	if r.ApprovalThreshold != nil {
+		input.ApprovalThreshold = &money.Money{Amount: *r.ApprovalThreshold, Currency: r.Currency}
+	}
+	return input
+}
+
+// ListCompanies lists companies
+// GET /admin/companies?page=1&page_size=20
+func (h *CompanyHandler) ListCompanies(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	companies, err := h.companyService.ListCompanies(c.Request.Context(), params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, companies)
+}
+
+// GetCompany retrieves a company with its members
+// GET /admin/companies/:id
+func (h *CompanyHandler) GetCompany(c *gin.Context) {
+	company, err := h.companyService.GetCompany(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	members, err := h.companyService.ListMembers(c.Request.Context(), company.ID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	c.Header("ETag", response.ETag(company))
+	response.Success(c, gin.H{"company": company, "members": members})
+}
+
+// CreateCompany creates a company
+// POST /admin/companies
+func (h *CompanyHandler) CreateCompany(c *gin.Context) {
+	var req CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	company, err := h.companyService.CreateCompany(c.Request.Context(), req.toInput())
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Created(c, company)
+}
+
+// UpdateCompany replaces a company's name and approval threshold
+// PUT /admin/companies/:id
+func (h *CompanyHandler) UpdateCompany(c *gin.Context) {
+	var req CompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.companyService.GetCompany(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	company, err := h.companyService.UpdateCompany(c.Request.Context(), c.Param("id"), req.toInput())
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored company
+	if company, err = h.companyService.GetCompany(c.Request.Context(), company.ID); err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, company)
+}
+
+// SetCompanyMember adds a user to a company or changes their role
+// PUT /admin/companies/:id/members/:userId
+func (h *CompanyHandler) SetCompanyMember(c *gin.Context) {
+	var req CompanyMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	member, err := h.companyService.SetMember(c.Request.Context(), c.Param("id"), c.Param("userId"), services.CompanyRole(req.Role))
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, member)
+}
+
+// RemoveCompanyMember removes a user from a company
+// DELETE /admin/companies/:id/members/:userId
+func (h *CompanyHandler) RemoveCompanyMember(c *gin.Context) {
+	if err := h.companyService.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("userId")); err != nil {
+		if err == services.ErrNotCompanyMember {
+			response.NotFound(c, "Member not found")
+			return
+		}
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// GetMyCompany returns the current user's company and their role in it
+// GET /company
+func (h *CompanyHandler) GetMyCompany(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	membership, err := h.companyService.Membership(c.Request.Context(), userID)
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, membership)
+}
+
+// ListMyCompanyMembers lists the members of the current user's company
+// GET /company/members
+func (h *CompanyHandler) ListMyCompanyMembers(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	membership, err := h.companyService.Membership(c.Request.Context(), userID)
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	members, err := h.companyService.ListMembers(c.Request.Context(), membership.Company.ID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, members)
+}
+
+// ListCompanyAddresses lists the shared address book of the current user's company
+// GET /company/addresses
+func (h *CompanyHandler) ListCompanyAddresses(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	addresses, err := h.companyService.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, addresses)
+}
+
+// CreateCompanyAddress adds an address to the shared address book
+// POST /company/addresses
+func (h *CompanyHandler) CreateCompanyAddress(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CompanyAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	address, err := h.companyService.AddAddress(c.Request.Context(), userID, req.Label, req.Address.ToOrderAddress())
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Created(c, address)
+}
+
+// UpdateCompanyAddress replaces an address in the shared address book
+// PUT /company/addresses/:id
+func (h *CompanyHandler) UpdateCompanyAddress(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CompanyAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	address, err := h.companyService.UpdateAddress(c.Request.Context(), userID, c.Param("id"), req.Label, req.Address.ToOrderAddress())
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, address)
+}
+
+// DeleteCompanyAddress removes an address from the shared address book
+// DELETE /company/addresses/:id
+func (h *CompanyHandler) DeleteCompanyAddress(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.companyService.DeleteAddress(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListCompanyOrders lists the order history of the current user's company
+// GET /company/orders?approval_status=pending&page=1&page_size=20
+func (h *CompanyHandler) ListCompanyOrders(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	params := response.GetPaginationParams(c)
+	filter := services.CompanyOrderFilter{
+		ApprovalStatus: services.ApprovalStatus(c.Query("approval_status")),
+		Limit:          params.CalculateLimit(),
+		Offset:         params.CalculateOffset(),
+	}
+
+	records, err := h.companyService.ListOrders(c.Request.Context(), userID, filter)
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, records)
+}
+
+// GetCompanyOrder retrieves an order from the company's history, placed by any member
+// GET /company/orders/:id
+func (h *CompanyHandler) GetCompanyOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	detail, err := h.companyService.GetOrder(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// ApproveCompanyOrder approves an order awaiting approval and charges it
+// POST /company/orders/:id/approve
+func (h *CompanyHandler) ApproveCompanyOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	record, err := h.companyService.Approve(c.Request.Context(), userID, c.Param("id"))
+	switch {
+	case err == nil:
+	case record == nil:
+		respondCompanyError(c, err)
+		return
+	case err == services.ErrTenderDeclined:
+		// The approval stands; the buyer pays the order with another payment method
+		response.ErrorWithDetails(c, http.StatusPaymentRequired, "payment_failed", "Order approved but payment was declined; the buyer can retry with another payment method", gin.H{
+			"order_id":  record.OrderID,
+			"retry_url": "/api/v1/orders/" + record.OrderID + "/retry-payment",
+		})
+		return
+	case err == services.ErrPaymentRetriesExhausted:
+		response.ErrorWithCode(c, http.StatusPaymentRequired, "payment_retries_exhausted", "Order approved but payment was declined and the order has been canceled")
+		return
+	default:
+		respondPaymentError(c, err)
+		return
+	}
+
+	response.Success(c, record)
+}
+
+// RejectCompanyOrder rejects an order awaiting approval and cancels it
+// POST /company/orders/:id/reject
+func (h *CompanyHandler) RejectCompanyOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req RejectCompanyOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	record, err := h.companyService.Reject(c.Request.Context(), userID, c.Param("id"), req.Reason)
+	if err != nil {
+		respondCompanyError(c, err)
+		return
+	}
+
+	response.Success(c, record)
+}
+
+// respondCompanyError maps company errors to HTTP responses
+func respondCompanyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCompanyNotFound):
+		response.NotFound(c, "Company not found")
+	case errors.Is(err, services.ErrCompanyAddressNotFound):
+		response.NotFound(c, "Address not found")
+	case errors.Is(err, services.ErrCompanyOrderNotFound):
+		response.NotFound(c, "Order not found")
+	case errors.Is(err, services.ErrNotCompanyMember), errors.Is(err, services.ErrNotCompanyApprover),
+		errors.Is(err, services.ErrSelfApproval):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, services.ErrInvalidCompany), errors.Is(err, services.ErrInvalidCompanyRole),
+		errors.Is(err, services.ErrInvalidCompanyAddress):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrAlreadyCompanyMember), errors.Is(err, services.ErrApprovalNotPending):
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ConsentHandler handles marketing and analytics consent
+type ConsentHandler struct {
+	consentService *services.ConsentService
+}
+
+// NewConsentHandler creates a new ConsentHandler
+func NewConsentHandler(consentService *services.ConsentService) *ConsentHandler {
+	return &ConsentHandler{
+		consentService: consentService,
+	}
+}
+
+// ConsentChoiceRequest is a choice for one purpose
+type ConsentChoiceRequest struct {
+	Purpose string `json:"purpose" binding:"required,oneof=marketing analytics"`
+	Granted *bool  `json:"granted" binding:"required"`
+}
+
+// RecordConsentRequest represents the request to record consent choices
+type RecordConsentRequest struct {
+	Consents      []ConsentChoiceRequest `json:"consents" binding:"required,min=1,dive"`
+	PolicyVersion string                 `json:"policy_version" binding:"omitempty,max=50"`
+	Source        string                 `json:"source" binding:"omitempty,max=50"`
+}
+
+// ConsentPreferencesResponse is the current user's consent choices
+type ConsentPreferencesResponse struct {
+	PolicyVersion string                       `json:"policy_version,omitempty"`
+	Preferences   []services.ConsentPreference `json:"preferences"`
+}
+
+// GetConsents returns the current user's choice for each purpose
+// GET /consents
+func (h *ConsentHandler) GetConsents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	preferences, err := h.consentService.Preferences(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, ConsentPreferencesResponse{
+		PolicyVersion: h.consentService.PolicyVersion(),
+		Preferences:   preferences,
+	})
+}
+
+// RecordConsents records the current user's consent choices, e.g. from the cookie banner
+// POST /consents
+func (h *ConsentHandler) RecordConsents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req RecordConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	choices := make([]services.ConsentChoice, len(req.Consents))
+	for i, consent := range req.Consents {
+		choices[i] = services.ConsentChoice{
+			Purpose: services.ConsentPurpose(consent.Purpose),
+			Granted: *consent.Granted,
+		}
+	}
+
+	events, err := h.consentService.Record(c.Request.Context(), userID, choices, req.PolicyVersion, req.Source)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidConsentPurpose, services.ErrConsentPolicyRequired:
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Created(c, events)
+}
+
+// ListUserConsents lists every consent choice a user has made, newest first
+// GET /admin/users/:id/consents
+func (h *ConsentHandler) ListUserConsents(c *gin.Context) {
+	events, err := h.consentService.History(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, events)
+}
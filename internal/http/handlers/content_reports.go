@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ContentReportHandler handles abuse reports on reviews, questions and answers and their
+// moderation queue
+type ContentReportHandler struct {
+	reportService *services.ContentReportService
+}
+
+// NewContentReportHandler creates a new ContentReportHandler
+func NewContentReportHandler(reportService *services.ContentReportService) *ContentReportHandler {
+	return &ContentReportHandler{
+		reportService: reportService,
+	}
+}
+
+// ReportContentRequest represents the request to report content as abusive
+type ReportContentRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam offensive off_topic other"`
+	Details string `json:"details" binding:"max=1000"`
+}
+
+// ResolveReportsRequest represents the request to resolve the reports on a piece of content
+type ResolveReportsRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss remove"`
+}
+
+// ReportReview reports an approved review
+// POST /reviews/:id/report
+func (h *ContentReportHandler) ReportReview(c *gin.Context) {
+	h.report(c, services.ReportContentReview, c.Param("id"))
+}
+
+// ReportQuestion reports a product question
+// POST /questions/:id/report
+func (h *ContentReportHandler) ReportQuestion(c *gin.Context) {
+	h.report(c, services.ReportContentQuestion, c.Param("id"))
+}
+
+// ReportAnswer reports an answer to a product question
+// POST /answers/:id/report
+func (h *ContentReportHandler) ReportAnswer(c *gin.Context) {
+	h.report(c, services.ReportContentAnswer, c.Param("id"))
+}
+
+// ListReportedContent lists the content with open reports, most reported first
+// GET /admin/content-reports
+func (h *ContentReportHandler) ListReportedContent(c *gin.Context) {
+	queue, err := h.reportService.Queue(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, queue)
+}
+
+// ResolveReports dismisses a piece of content's open reports, showing it again, or removes
+// the content, keeping it hidden
+// POST /admin/content-reports/:type/:id/resolve
+func (h *ContentReportHandler) ResolveReports(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req ResolveReportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	content, err := h.reportService.Resolve(c.Request.Context(), services.ReportContentType(c.Param("type")), c.Param("id"), userID, services.ReportAction(req.Action))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, content)
+}
+
+func (h *ContentReportHandler) report(c *gin.Context, contentType services.ReportContentType, contentID string) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req ReportContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	report, err := h.reportService.Report(c.Request.Context(), userID, contentType, contentID, services.ReportReason(req.Reason), req.Details)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, report)
+}
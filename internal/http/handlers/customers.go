@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// multipartOverhead is the room left for multipart headers around an uploaded file
+const multipartOverhead = 64 << 10
+
+// CustomerHandler handles the signed-in customer's profile
+type CustomerHandler struct {
+	customerService *services.CustomerService
+}
+
+// NewCustomerHandler creates a new CustomerHandler
+func NewCustomerHandler(customerService *services.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+// UpdateProfileRequest represents the request to update profile fields; fields left out
+// are kept and empty strings clear them
+type UpdateProfileRequest struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Phone     *string `json:"phone"`
+	Birthday  *string `json:"birthday"` // YYYY-MM-DD
+}
+
+// GetProfile returns the current user's profile
+// GET /me
+func (h *CustomerHandler) GetProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	profile, err := h.customerService.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// UpdateProfile updates the current user's name, phone and birthday
+// PATCH /me
+func (h *CustomerHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	profile, err := h.customerService.UpdateProfile(c.Request.Context(), userID, services.ProfileUpdate{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Phone:     req.Phone,
+		Birthday:  req.Birthday,
+	})
+	if err != nil {
+		if err == services.ErrInvalidProfile {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// UploadAvatar replaces the current user's avatar with the image in the avatar form field
+// PUT /me/avatar
+func (h *CustomerHandler) UploadAvatar(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.customerService.MaxAvatarSize()+multipartOverhead)
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		response.BadRequest(c, "avatar must be uploaded as a multipart form file within the size limit")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.customerService.SetAvatar(c.Request.Context(), userID, file)
+	if err != nil {
+		if err == services.ErrInvalidAvatar {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// DeleteAvatar removes the current user's avatar
+// DELETE /me/avatar
+func (h *CustomerHandler) DeleteAvatar(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	profile, err := h.customerService.RemoveAvatar(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, profile)
+}
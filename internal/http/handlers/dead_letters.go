@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DeadLetterHandler handles the admin dead-letter queue of failed background jobs
+type DeadLetterHandler struct {
+	deadLetterService *services.DeadLetterService
+}
+
+// NewDeadLetterHandler creates a new DeadLetterHandler
+func NewDeadLetterHandler(deadLetterService *services.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// ListDeadLetters lists failed jobs, newest failure first
+// GET /admin/dead-letters?kind=webhook&status=dead&page=1&page_size=20
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	filter := services.DeadLetterFilter{
+		Kind:   c.Query("kind"),
+		Status: services.DeadLetterStatus(c.Query("status")),
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	}
+
+	letters, err := h.deadLetterService.ListDeadLetters(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.deadLetterService.CountDeadLetters(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, letters, meta)
+}
+
+// GetDeadLetter retrieves a failed job with its error and payload
+// GET /admin/dead-letters/:id
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	letter, err := h.deadLetterService.GetDeadLetter(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDeadLetterError(c, err)
+		return
+	}
+
+	response.Success(c, letter)
+}
+
+// ReplayDeadLetter queues a failed job to run again
+// POST /admin/dead-letters/:id/replay
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	letter, err := h.deadLetterService.Replay(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDeadLetterError(c, err)
+		return
+	}
+
+	response.Accepted(c, letter)
+}
+
+func respondDeadLetterError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrDeadLetterNotFound):
+		response.NotFound(c, "Dead letter not found")
+	case errors.Is(err, services.ErrDeadLetterKindUnknown),
+		errors.Is(err, services.ErrDeadLetterReplayed),
+		errors.Is(err, services.ErrDeadLetterReplaying):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, jobs.ErrQueueFull):
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "queue_full", "Job queue is full, try again shortly")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DeliveryHandler handles delivery slot endpoints
+type DeliveryHandler struct {
+	deliveryService *services.DeliveryService
+}
+
+// NewDeliveryHandler creates a new DeliveryHandler
+func NewDeliveryHandler(deliveryService *services.DeliveryService) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveryService: deliveryService,
+	}
+}
+
+// DeliverySlotResponse is a delivery slot as shown to shoppers
+type DeliverySlotResponse struct {
+	ID        string    `json:"id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Remaining int       `json:"remaining"`
+}
+
+// ListDeliverySlots lists bookable delivery slots for a postal code
+// GET /checkout/delivery-slots?postal_code=94107&from=2024-01-01&days=7
+func (h *DeliveryHandler) ListDeliverySlots(c *gin.Context) {
+	postalCode := c.Query("postal_code")
+	if postalCode == "" {
+		response.BadRequest(c, "postal_code is required")
+		return
+	}
+
+	from := time.Now()
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsed, err := time.Parse("2006-01-02", fromParam)
+		if err != nil {
+			response.BadRequest(c, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		if parsed.After(from) {
+			from = parsed
+		}
+	}
+
+	days := services.DefaultDeliveryWindowDays
+	if daysParam := c.Query("days"); daysParam != "" {
+		var params struct {
+			Days int `form:"days" binding:"min=1,max=60"`
+		}
+		if err := c.ShouldBindQuery(&params); err != nil {
+			response.BadRequest(c, "days must be between 1 and 60")
+			return
+		}
+		days = params.Days
+	}
+
+	slots, err := h.deliveryService.AvailableSlots(c.Request.Context(), postalCode, from, from.AddDate(0, 0, days))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	result := make([]DeliverySlotResponse, len(slots))
+	for i, slot := range slots {
+		result[i] = DeliverySlotResponse{
+			ID:        slot.ID,
+			StartsAt:  slot.StartsAt,
+			EndsAt:    slot.EndsAt,
+			Remaining: slot.Remaining(),
+		}
+	}
+
+	response.Success(c, result)
+}
+
+// CreateDeliverySlotRequest represents the request to create a delivery slot
+type CreateDeliverySlotRequest struct {
+	Region   string    `json:"region" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Capacity int       `json:"capacity" binding:"required,gt=0"`
+}
+
+// CreateDeliverySlot creates a delivery slot for a postcode region
+// POST /admin/delivery-slots
+func (h *DeliveryHandler) CreateDeliverySlot(c *gin.Context) {
+	var req CreateDeliverySlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	slot := &services.DeliverySlot{
+		Region:   req.Region,
+		StartsAt: req.StartsAt.UTC(),
+		EndsAt:   req.EndsAt.UTC(),
+		Capacity: req.Capacity,
+		IsActive: true,
+	}
+
+	if err := h.deliveryService.CreateSlot(c.Request.Context(), slot); err != nil {
+		if err == services.ErrInvalidDeliverySlot {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "Failed to create delivery slot")
+		return
+	}
+
+	response.Created(c, slot)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DisputeHandler handles the admin chargeback queue
+type DisputeHandler struct {
+	disputeService *services.DisputeService
+}
+
+// NewDisputeHandler creates a new DisputeHandler
+func NewDisputeHandler(disputeService *services.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+// ListDisputes lists disputes, soonest evidence deadline first
+// GET /admin/disputes?status=needs_response&evidence_status=not_submitted&page=1&page_size=20
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	filter := services.DisputeFilter{
+		Status:         services.DisputeStatus(c.Query("status")),
+		EvidenceStatus: services.EvidenceStatus(c.Query("evidence_status")),
+		Limit:          params.CalculateLimit(),
+		Offset:         params.CalculateOffset(),
+	}
+
+	disputes, err := h.disputeService.ListDisputes(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.disputeService.CountDisputes(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, disputes, meta)
+}
+
+// GetDispute retrieves a dispute by ID
+// GET /admin/disputes/:id
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	dispute, err := h.disputeService.GetDispute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrDisputeNotFound {
+			response.NotFound(c, "Dispute not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, dispute)
+}
+
+// SubmitEvidenceRequest represents the request to record evidence submission
+type SubmitEvidenceRequest struct {
+	Notes string `json:"notes" binding:"required"`
+}
+
+// SubmitEvidence records that evidence was submitted to the gateway for a dispute
+// POST /admin/disputes/:id/evidence
+func (h *DisputeHandler) SubmitEvidence(c *gin.Context) {
+	var req SubmitEvidenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	dispute, err := h.disputeService.SubmitEvidence(c.Request.Context(), c.Param("id"), req.Notes)
+	if err != nil {
+		switch err {
+		case services.ErrDisputeNotFound:
+			response.NotFound(c, "Dispute not found")
+		case services.ErrDisputeClosed:
+			response.Conflict(c, "Dispute is already closed")
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, dispute)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DonationHandler handles donation product settings endpoints
+type DonationHandler struct {
+	donationService *services.DonationService
+}
+
+// NewDonationHandler creates a new DonationHandler
+func NewDonationHandler(donationService *services.DonationService) *DonationHandler {
+	return &DonationHandler{
+		donationService: donationService,
+	}
+}
+
+// DonationRequest represents the request to make a product take donations
+type DonationRequest struct {
+	MinAmount int64  `json:"min_amount" binding:"required,gt=0"` // in cents
+	MaxAmount *int64 `json:"max_amount"`                         // in cents; omit for no maximum
+}
+
+// GetDonation retrieves the amounts a donation product takes
+// GET /catalog/products/:id/donation
+// GET /admin/catalog/products/:id/donation
+func (h *DonationHandler) GetDonation(c *gin.Context) {
+	donation, err := h.donationService.GetDonation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDonationError(c, err)
+		return
+	}
+
+	response.Success(c, donation)
+}
+
+// SetDonation makes a product take donations or replaces its limits
+// PUT /admin/catalog/products/:id/donation
+func (h *DonationHandler) SetDonation(c *gin.Context) {
+	var req DonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	donation := &services.DonationProduct{
+		ProductID: c.Param("id"),
+		MinAmount: money.Money{Amount: req.MinAmount},
+	}
+	if req.MaxAmount != nil {
+		donation.MaxAmount = &money.Money{Amount: *req.MaxAmount}
+	}
+	if err := h.donationService.SetDonation(c.Request.Context(), donation); err != nil {
+		respondDonationError(c, err)
+		return
+	}
+
+	response.Success(c, donation)
+}
+
+// DeleteDonation stops a product taking donations
+// DELETE /admin/catalog/products/:id/donation
+func (h *DonationHandler) DeleteDonation(c *gin.Context) {
+	if err := h.donationService.RemoveDonation(c.Request.Context(), c.Param("id")); err != nil {
+		respondDonationError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// isDonationError reports whether err is about a donation item's amount, for endpoints
+// that also return other errors
+func isDonationError(err error) bool {
+	return errors.Is(err, services.ErrDonationAmountRequired) ||
+		errors.Is(err, services.ErrInvalidDonationAmount) ||
+		errors.Is(err, services.ErrDonationInCart) ||
+		errors.Is(err, services.ErrDonationClosed)
+}
+
+// respondDonationError maps donation errors to HTTP responses
+func respondDonationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrDonationAmountRequired),
+		errors.Is(err, services.ErrInvalidDonationAmount),
+		errors.Is(err, services.ErrInvalidDonation):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrDonationInCart),
+		errors.Is(err, services.ErrDonationClosed):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrDonationNotFound):
+		response.NotFound(c, "Product does not take donations")
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/inventory"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RegisterErrors maps the domain errors handlers pass to response.FromError to an HTTP
+// status and a stable error code clients can branch on. Error codes are part of the API:
+// add new ones freely but don't rename existing ones. Errors not listed here are sent as a
+// 500 internal_server_error without their text.
+func RegisterErrors() {
+	// Orders
+	response.RegisterError(orders.ErrEmptyCart, http.StatusBadRequest, "empty_cart")
+	response.RegisterError(orders.ErrInvalidAddress, http.StatusBadRequest, "invalid_address")
+	response.RegisterError(orders.ErrInvalidStatus, http.StatusConflict, "invalid_status_transition")
+	response.RegisterError(orders.ErrOrderNotFound, http.StatusNotFound, "order_not_found")
+	response.RegisterError(orders.ErrPaymentFailed, http.StatusPaymentRequired, "payment_failed")
+
+	// Carts
+	response.RegisterError(cart.ErrCartNotFound, http.StatusNotFound, "cart_not_found")
+	response.RegisterError(cart.ErrInvalidQuantity, http.StatusBadRequest, "invalid_quantity")
+	response.RegisterError(cart.ErrItemNotFound, http.StatusNotFound, "cart_item_not_found")
+	response.RegisterError(cart.ErrOutOfStock, http.StatusBadRequest, "out_of_stock")
+
+	// Inventory
+	response.RegisterError(inventory.ErrInsufficientStock, http.StatusConflict, "insufficient_stock")
+	response.RegisterError(inventory.ErrInvalidSKU, http.StatusBadRequest, "invalid_sku")
+
+	// Money
+	response.RegisterError(money.ErrCurrencyMismatch, http.StatusBadRequest, "currency_mismatch")
+	response.RegisterError(money.ErrInvalidCurrency, http.StatusBadRequest, "invalid_currency")
+	response.RegisterError(money.ErrNegativeAmount, http.StatusBadRequest, "negative_amount")
+
+	// Scheduled tasks
+	response.RegisterError(jobs.ErrQueueFull, http.StatusServiceUnavailable, "queue_full")
+	response.RegisterError(jobs.ErrTaskNotFound, http.StatusNotFound, "task_not_found")
+	response.RegisterError(jobs.ErrTaskRunning, http.StatusConflict, "task_running")
+
+	// API services
+	response.RegisterError(services.ErrAPIKeyNotFound, http.StatusNotFound, "api_key_not_found")
+	response.RegisterError(services.ErrAlreadyCompanyMember, http.StatusConflict, "already_company_member")
+	response.RegisterError(services.ErrAlreadyReported, http.StatusConflict, "already_reported")
+	response.RegisterError(services.ErrAnswerNotFound, http.StatusNotFound, "answer_not_found")
+	response.RegisterError(services.ErrAppointmentCancelWindowPassed, http.StatusConflict, "appointment_cancel_window_passed")
+	response.RegisterError(services.ErrAppointmentInCart, http.StatusConflict, "appointment_in_cart")
+	response.RegisterError(services.ErrAppointmentProductNotFound, http.StatusNotFound, "appointment_product_not_found")
+	response.RegisterError(services.ErrAppointmentSlotFull, http.StatusConflict, "appointment_slot_full")
+	response.RegisterError(services.ErrAppointmentSlotNotFound, http.StatusNotFound, "appointment_slot_not_found")
+	response.RegisterError(services.ErrAppointmentSlotRequired, http.StatusBadRequest, "appointment_slot_required")
+	response.RegisterError(services.ErrAppointmentSlotUnavailable, http.StatusConflict, "appointment_slot_unavailable")
+	response.RegisterError(services.ErrApprovalNotPending, http.StatusConflict, "approval_not_pending")
+	response.RegisterError(services.ErrBarcodeNotFound, http.StatusNotFound, "barcode_not_found")
+	response.RegisterError(services.ErrBarcodeTaken, http.StatusConflict, "barcode_taken")
+	response.RegisterError(services.ErrBulkJobNotFound, http.StatusNotFound, "bulk_job_not_found")
+	response.RegisterError(services.ErrCalendarNotFound, http.StatusNotFound, "calendar_not_found")
+	response.RegisterError(services.ErrCatalogVersionNotFound, http.StatusNotFound, "catalog_version_not_found")
+	response.RegisterError(services.ErrCheckoutRuleNotFound, http.StatusNotFound, "checkout_rule_not_found")
+	response.RegisterError(services.ErrCollectionNotFound, http.StatusNotFound, "collection_not_found")
+	response.RegisterError(services.ErrCollectionSlugTaken, http.StatusConflict, "collection_slug_taken")
+	response.RegisterError(services.ErrCompanyAddressNotFound, http.StatusNotFound, "company_address_not_found")
+	response.RegisterError(services.ErrCompanyNotFound, http.StatusNotFound, "company_not_found")
+	response.RegisterError(services.ErrCompanyOrderNotFound, http.StatusNotFound, "company_order_not_found")
+	response.RegisterError(services.ErrConsentPolicyRequired, http.StatusBadRequest, "consent_policy_required")
+	response.RegisterError(services.ErrDeadLetterKindUnknown, http.StatusConflict, "dead_letter_kind_unknown")
+	response.RegisterError(services.ErrDeadLetterNotFound, http.StatusNotFound, "dead_letter_not_found")
+	response.RegisterError(services.ErrDeadLetterReplayed, http.StatusConflict, "dead_letter_replayed")
+	response.RegisterError(services.ErrDeadLetterReplaying, http.StatusConflict, "dead_letter_replaying")
+	response.RegisterError(services.ErrDeliverySlotFull, http.StatusConflict, "delivery_slot_full")
+	response.RegisterError(services.ErrDeliverySlotNotFound, http.StatusNotFound, "delivery_slot_not_found")
+	response.RegisterError(services.ErrDeliverySlotUnavailable, http.StatusBadRequest, "delivery_slot_unavailable")
+	response.RegisterError(services.ErrDisputeClosed, http.StatusConflict, "dispute_closed")
+	response.RegisterError(services.ErrDisputeNotFound, http.StatusNotFound, "dispute_not_found")
+	response.RegisterError(services.ErrDonationAmountRequired, http.StatusBadRequest, "donation_amount_required")
+	response.RegisterError(services.ErrDonationClosed, http.StatusConflict, "donation_closed")
+	response.RegisterError(services.ErrDonationInCart, http.StatusConflict, "donation_in_cart")
+	response.RegisterError(services.ErrDonationNotFound, http.StatusNotFound, "donation_not_found")
+	response.RegisterError(services.ErrDuplicateVariantOptions, http.StatusConflict, "duplicate_variant_options")
+	response.RegisterError(services.ErrEmptyQuoteRequest, http.StatusBadRequest, "empty_quote_request")
+	response.RegisterError(services.ErrExchangeRateNotFound, http.StatusBadRequest, "exchange_rate_not_found")
+	response.RegisterError(services.ErrFunnelAlertsDisabled, http.StatusServiceUnavailable, "funnel_alerts_disabled")
+	response.RegisterError(services.ErrHolidayNotFound, http.StatusNotFound, "holiday_not_found")
+	response.RegisterError(services.ErrInsufficientStoreCredit, http.StatusConflict, "insufficient_store_credit")
+	response.RegisterError(services.ErrInvalidAPIKey, http.StatusUnauthorized, "invalid_api_key")
+	response.RegisterError(services.ErrInvalidAppointmentProduct, http.StatusBadRequest, "invalid_appointment_product")
+	response.RegisterError(services.ErrInvalidAppointmentSlot, http.StatusBadRequest, "invalid_appointment_slot")
+	response.RegisterError(services.ErrInvalidAttribution, http.StatusBadRequest, "invalid_attribution")
+	response.RegisterError(services.ErrInvalidAvatar, http.StatusBadRequest, "invalid_avatar")
+	response.RegisterError(services.ErrInvalidBarcode, http.StatusBadRequest, "invalid_barcode")
+	response.RegisterError(services.ErrInvalidBulkArchive, http.StatusBadRequest, "invalid_bulk_archive")
+	response.RegisterError(services.ErrInvalidCalendar, http.StatusBadRequest, "invalid_calendar")
+	response.RegisterError(services.ErrInvalidCalendarRange, http.StatusBadRequest, "invalid_calendar_range")
+	response.RegisterError(services.ErrInvalidCancelReason, http.StatusBadRequest, "invalid_cancel_reason")
+	response.RegisterError(services.ErrInvalidCheckoutRule, http.StatusBadRequest, "invalid_checkout_rule")
+	response.RegisterError(services.ErrInvalidCollection, http.StatusBadRequest, "invalid_collection")
+	response.RegisterError(services.ErrInvalidCompany, http.StatusBadRequest, "invalid_company")
+	response.RegisterError(services.ErrInvalidCompanyAddress, http.StatusBadRequest, "invalid_company_address")
+	response.RegisterError(services.ErrInvalidCompanyRole, http.StatusBadRequest, "invalid_company_role")
+	response.RegisterError(services.ErrInvalidConsentPurpose, http.StatusBadRequest, "invalid_consent_purpose")
+	response.RegisterError(services.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor")
+	response.RegisterError(services.ErrInvalidDeliverySlot, http.StatusBadRequest, "invalid_delivery_slot")
+	response.RegisterError(services.ErrInvalidDisputeEvent, http.StatusBadRequest, "invalid_dispute_event")
+	response.RegisterError(services.ErrInvalidDonation, http.StatusBadRequest, "invalid_donation")
+	response.RegisterError(services.ErrInvalidDonationAmount, http.StatusBadRequest, "invalid_donation_amount")
+	response.RegisterError(services.ErrInvalidExportLink, http.StatusForbidden, "invalid_export_link")
+	response.RegisterError(services.ErrInvalidExportRange, http.StatusBadRequest, "invalid_export_range")
+	response.RegisterError(services.ErrInvalidFunnelWindow, http.StatusBadRequest, "invalid_funnel_window")
+	response.RegisterError(services.ErrInvalidHoliday, http.StatusBadRequest, "invalid_holiday")
+	response.RegisterError(services.ErrInvalidInvoicePayment, http.StatusBadRequest, "invalid_invoice_payment")
+	response.RegisterError(services.ErrInvalidMetadata, http.StatusBadRequest, "invalid_metadata")
+	response.RegisterError(services.ErrInvalidOrderBump, http.StatusBadRequest, "invalid_order_bump")
+	response.RegisterError(services.ErrInvalidOrderNote, http.StatusBadRequest, "invalid_order_note")
+	response.RegisterError(services.ErrInvalidPOSSale, http.StatusBadRequest, "invalid_pos_sale")
+	response.RegisterError(services.ErrInvalidPage, http.StatusBadRequest, "invalid_page")
+	response.RegisterError(services.ErrInvalidPlacement, http.StatusBadRequest, "invalid_placement")
+	response.RegisterError(services.ErrInvalidProductMedia, http.StatusBadRequest, "invalid_product_media")
+	response.RegisterError(services.ErrInvalidProfile, http.StatusBadRequest, "invalid_profile")
+	response.RegisterError(services.ErrInvalidPurchaseLimit, http.StatusBadRequest, "invalid_purchase_limit")
+	response.RegisterError(services.ErrInvalidQuestion, http.StatusBadRequest, "invalid_question")
+	response.RegisterError(services.ErrInvalidQuotaPeriod, http.StatusBadRequest, "invalid_quota_period")
+	response.RegisterError(services.ErrInvalidQuoteNote, http.StatusBadRequest, "invalid_quote_note")
+	response.RegisterError(services.ErrInvalidQuoteOffer, http.StatusBadRequest, "invalid_quote_offer")
+	response.RegisterError(services.ErrInvalidRefund, http.StatusBadRequest, "invalid_refund")
+	response.RegisterError(services.ErrInvalidRental, http.StatusBadRequest, "invalid_rental")
+	response.RegisterError(services.ErrInvalidRentalPeriod, http.StatusBadRequest, "invalid_rental_period")
+	response.RegisterError(services.ErrInvalidReport, http.StatusBadRequest, "invalid_report")
+	response.RegisterError(services.ErrInvalidReportRange, http.StatusBadRequest, "invalid_report_range")
+	response.RegisterError(services.ErrInvalidReview, http.StatusBadRequest, "invalid_review")
+	response.RegisterError(services.ErrInvalidReviewPhoto, http.StatusBadRequest, "invalid_review_photo")
+	response.RegisterError(services.ErrInvalidRuntimeConfig, http.StatusUnprocessableEntity, "invalid_config")
+	response.RegisterError(services.ErrInvalidSchedule, http.StatusBadRequest, "invalid_schedule")
+	response.RegisterError(services.ErrInvalidScopes, http.StatusBadRequest, "invalid_scopes")
+	response.RegisterError(services.ErrInvalidSearchRule, http.StatusBadRequest, "invalid_search_rule")
+	response.RegisterError(services.ErrInvalidShipment, http.StatusBadRequest, "invalid_shipment")
+	response.RegisterError(services.ErrInvalidShippingMethod, http.StatusBadRequest, "invalid_shipping_method")
+	response.RegisterError(services.ErrInvalidShippingRestriction, http.StatusBadRequest, "invalid_shipping_restriction")
+	response.RegisterError(services.ErrInvalidShippingZone, http.StatusBadRequest, "invalid_shipping_zone")
+	response.RegisterError(services.ErrInvalidStockHistoryRange, http.StatusBadRequest, "invalid_stock_history_range")
+	response.RegisterError(services.ErrInvalidStoreCredit, http.StatusBadRequest, "invalid_store_credit")
+	response.RegisterError(services.ErrInvalidSynonymSet, http.StatusBadRequest, "invalid_synonym_set")
+	response.RegisterError(services.ErrInvalidTag, http.StatusBadRequest, "invalid_tag")
+	response.RegisterError(services.ErrInvalidTender, http.StatusBadRequest, "invalid_tender")
+	response.RegisterError(services.ErrInvalidTicket, http.StatusBadRequest, "invalid_ticket")
+	response.RegisterError(services.ErrInvalidUnitMeasure, http.StatusBadRequest, "invalid_unit_measure")
+	response.RegisterError(services.ErrInvalidVariant, http.StatusBadRequest, "invalid_variant")
+	response.RegisterError(services.ErrInvalidVariantAttributes, http.StatusBadRequest, "invalid_variant_attributes")
+	response.RegisterError(services.ErrInvalidWebhookPayload, http.StatusBadRequest, "invalid_webhook_payload")
+	response.RegisterError(services.ErrInvalidWebhookSignature, http.StatusUnauthorized, "invalid_webhook_signature")
+	response.RegisterError(services.ErrInvalidWishlist, http.StatusBadRequest, "invalid_wishlist")
+	response.RegisterError(services.ErrInvalidWishlistItem, http.StatusBadRequest, "invalid_wishlist_item")
+	response.RegisterError(services.ErrInvoiceNotFound, http.StatusNotFound, "invoice_not_found")
+	response.RegisterError(services.ErrInvoiceNotOpen, http.StatusConflict, "invoice_not_open")
+	response.RegisterError(services.ErrInvoiceOverpaid, http.StatusConflict, "invoice_overpaid")
+	response.RegisterError(services.ErrLoginAlertsDisabled, http.StatusServiceUnavailable, "login_alerts_disabled")
+	response.RegisterError(services.ErrLoginDeviceNotFound, http.StatusNotFound, "login_device_not_found")
+	response.RegisterError(services.ErrLoginLockoutNotFound, http.StatusNotFound, "login_lockout_not_found")
+	response.RegisterError(services.ErrNetTermsUnavailable, http.StatusForbidden, "net_terms_unavailable")
+	response.RegisterError(services.ErrNoBalanceDue, http.StatusConflict, "no_balance_due")
+	response.RegisterError(services.ErrNoConfirmationEmail, http.StatusConflict, "no_confirmation_email")
+	response.RegisterError(services.ErrNoOpenReports, http.StatusNotFound, "no_open_reports")
+	response.RegisterError(services.ErrNoStockHistory, http.StatusNotFound, "no_stock_history")
+	response.RegisterError(services.ErrNotADrop, http.StatusNotFound, "not_a_drop")
+	response.RegisterError(services.ErrNotCompanyApprover, http.StatusForbidden, "not_company_approver")
+	response.RegisterError(services.ErrNotCompanyMember, http.StatusForbidden, "not_company_member")
+	response.RegisterError(services.ErrNothingToRevert, http.StatusConflict, "nothing_to_revert")
+	response.RegisterError(services.ErrOrderBumpNotFound, http.StatusNotFound, "order_bump_not_found")
+	response.RegisterError(services.ErrOrderBumpUnavailable, http.StatusConflict, "order_bump_unavailable")
+	response.RegisterError(services.ErrOrderConfirmationsDisabled, http.StatusServiceUnavailable, "confirmations_disabled")
+	response.RegisterError(services.ErrOrderExportInProgress, http.StatusConflict, "order_export_in_progress")
+	response.RegisterError(services.ErrOrderExportNotFound, http.StatusNotFound, "order_export_not_found")
+	response.RegisterError(services.ErrOrderExportNotReady, http.StatusConflict, "order_export_not_ready")
+	response.RegisterError(services.ErrOrderNotCancelable, http.StatusConflict, "order_not_cancelable")
+	response.RegisterError(services.ErrOrderNotFulfillable, http.StatusConflict, "order_not_fulfillable")
+	response.RegisterError(services.ErrOrderNotRefundable, http.StatusConflict, "order_not_refundable")
+	response.RegisterError(services.ErrOrderOnHold, http.StatusConflict, "order_on_hold")
+	response.RegisterError(services.ErrPOSItemNotFound, http.StatusBadRequest, "pos_item_not_found")
+	response.RegisterError(services.ErrPageNotFound, http.StatusNotFound, "page_not_found")
+	response.RegisterError(services.ErrPageSlugTaken, http.StatusConflict, "page_slug_taken")
+	response.RegisterError(services.ErrPaymentNotPending, http.StatusConflict, "payment_not_pending")
+	response.RegisterError(services.ErrPaymentRetriesExhausted, http.StatusPaymentRequired, "payment_retries_exhausted")
+	response.RegisterError(services.ErrPaymentRetryNotFound, http.StatusNotFound, "payment_retry_not_found")
+	response.RegisterError(services.ErrPlacementNotFound, http.StatusNotFound, "placement_not_found")
+	response.RegisterError(services.ErrPricingAnomalyNotFound, http.StatusNotFound, "pricing_anomaly_not_found")
+	response.RegisterError(services.ErrPricingAnomalyReviewed, http.StatusConflict, "pricing_anomaly_reviewed")
+	response.RegisterError(services.ErrProductNotFound, http.StatusNotFound, "product_not_found")
+	response.RegisterError(services.ErrPurchaseLimitNotFound, http.StatusNotFound, "purchase_limit_not_found")
+	response.RegisterError(services.ErrQuestionNotFound, http.StatusNotFound, "question_not_found")
+	response.RegisterError(services.ErrQuotaExceeded, http.StatusTooManyRequests, "quota_exceeded")
+	response.RegisterError(services.ErrQuoteExpired, http.StatusConflict, "quote_expired")
+	response.RegisterError(services.ErrQuoteNotFound, http.StatusNotFound, "quote_not_found")
+	response.RegisterError(services.ErrQuoteNotOpen, http.StatusConflict, "quote_not_open")
+	response.RegisterError(services.ErrQuoteNotSent, http.StatusConflict, "quote_not_sent")
+	response.RegisterError(services.ErrRefundExceedsCapture, http.StatusConflict, "refund_exceeds_capture")
+	response.RegisterError(services.ErrRefundExceedsPaid, http.StatusConflict, "refund_exceeds_paid")
+	response.RegisterError(services.ErrRefundItemNotFound, http.StatusBadRequest, "refund_item_not_found")
+	response.RegisterError(services.ErrRefundQuantityExceeded, http.StatusBadRequest, "refund_quantity_exceeded")
+	response.RegisterError(services.ErrRegistryItemInCart, http.StatusConflict, "registry_item_in_cart")
+	response.RegisterError(services.ErrRegistryItemPurchased, http.StatusConflict, "registry_item_purchased")
+	response.RegisterError(services.ErrRegistryItemUnavailable, http.StatusConflict, "registry_item_unavailable")
+	response.RegisterError(services.ErrRentalInCart, http.StatusConflict, "rental_in_cart")
+	response.RegisterError(services.ErrRentalNotFound, http.StatusNotFound, "rental_not_found")
+	response.RegisterError(services.ErrRentalPeriodRequired, http.StatusBadRequest, "rental_period_required")
+	response.RegisterError(services.ErrRentalSKUNotFound, http.StatusNotFound, "rental_sku_not_found")
+	response.RegisterError(services.ErrReviewExists, http.StatusConflict, "review_exists")
+	response.RegisterError(services.ErrReviewNotFound, http.StatusNotFound, "review_not_found")
+	response.RegisterError(services.ErrReviewPhotoLimit, http.StatusConflict, "review_photo_limit")
+	response.RegisterError(services.ErrReviewPhotoNotFound, http.StatusNotFound, "review_photo_not_found")
+	response.RegisterError(services.ErrSearchRuleNotFound, http.StatusNotFound, "search_rule_not_found")
+	response.RegisterError(services.ErrSelfApproval, http.StatusForbidden, "self_approval")
+	response.RegisterError(services.ErrShippingMethodNotFound, http.StatusNotFound, "shipping_method_not_found")
+	response.RegisterError(services.ErrShippingMethodUnavailable, http.StatusBadRequest, "shipping_method_unavailable")
+	response.RegisterError(services.ErrShippingRestrictionNotFound, http.StatusNotFound, "shipping_restriction_not_found")
+	response.RegisterError(services.ErrShippingZoneNotFound, http.StatusNotFound, "shipping_zone_not_found")
+	response.RegisterError(services.ErrSuggestQueryRequired, http.StatusBadRequest, "suggest_query_required")
+	response.RegisterError(services.ErrSynonymSetNotFound, http.StatusNotFound, "synonym_set_not_found")
+	response.RegisterError(services.ErrTenderDeclined, http.StatusPaymentRequired, "payment_failed")
+	response.RegisterError(services.ErrTenderNotFound, http.StatusNotFound, "tender_not_found")
+	response.RegisterError(services.ErrTenderTotalMismatch, http.StatusBadRequest, "tender_total_mismatch")
+	response.RegisterError(services.ErrTicketClosed, http.StatusConflict, "ticket_closed")
+	response.RegisterError(services.ErrTicketNotFound, http.StatusNotFound, "ticket_not_found")
+	response.RegisterError(services.ErrTooManyTenders, http.StatusBadRequest, "too_many_tenders")
+	response.RegisterError(services.ErrUnknownQuotaTier, http.StatusBadRequest, "unknown_quota_tier")
+	response.RegisterError(services.ErrUnsupportedExportFormat, http.StatusBadRequest, "unsupported_export_format")
+	response.RegisterError(services.ErrVariantAttributesMismatch, http.StatusBadRequest, "variant_attributes_mismatch")
+	response.RegisterError(services.ErrVariantNotFound, http.StatusNotFound, "variant_not_found")
+	response.RegisterError(services.ErrVariantSKUTaken, http.StatusConflict, "variant_sku_taken")
+	response.RegisterError(services.ErrViewedProductNotFound, http.StatusNotFound, "viewed_product_not_found")
+	response.RegisterError(services.ErrWaitingRoomExpired, http.StatusForbidden, "waiting_room_expired")
+	response.RegisterError(services.ErrWaitingRoomNotAdmitted, http.StatusForbidden, "waiting_room_not_admitted")
+	response.RegisterError(services.ErrWaitingRoomRequired, http.StatusForbidden, "waiting_room_required")
+	response.RegisterError(services.ErrWaitingRoomTicketNotFound, http.StatusNotFound, "waiting_room_ticket_not_found")
+	response.RegisterError(services.ErrWebhookEventNotFound, http.StatusNotFound, "webhook_event_not_found")
+	response.RegisterError(services.ErrWebhookPayloadPurged, http.StatusConflict, "webhook_payload_purged")
+	response.RegisterError(services.ErrWebhookProviderUnknown, http.StatusNotFound, "webhook_provider_unknown")
+	response.RegisterError(services.ErrWishlistItemNotFound, http.StatusNotFound, "wishlist_item_not_found")
+	response.RegisterError(services.ErrWishlistNotFound, http.StatusNotFound, "wishlist_not_found")
+}
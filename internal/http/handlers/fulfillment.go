@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// FulfillmentHandler handles the 3PL integration endpoints, authenticated by API key
+type FulfillmentHandler struct {
+	fulfillmentService *services.FulfillmentService
+}
+
+// NewFulfillmentHandler creates a new FulfillmentHandler
+func NewFulfillmentHandler(fulfillmentService *services.FulfillmentService) *FulfillmentHandler {
+	return &FulfillmentHandler{
+		fulfillmentService: fulfillmentService,
+	}
+}
+
+// OpenOrdersResponse is one page of the open order feed
+type OpenOrdersResponse struct {
+	Orders     []*OrderV2 `json:"orders"`
+	NextCursor string     `json:"next_cursor"`
+	HasMore    bool       `json:"has_more"`
+}
+
+// ListOpenOrders returns paid and processing orders changed since the cursor
+// GET /fulfillment/orders?cursor=<next_cursor>&limit=50
+func (h *FulfillmentHandler) ListOpenOrders(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.fulfillmentService.OpenOrders(c.Request.Context(), c.Query("cursor"), limit)
+	if err != nil {
+		if err == services.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	result := OpenOrdersResponse{
+		Orders:     make([]*OrderV2, len(page.Orders)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	for i, order := range page.Orders {
+		result.Orders[i] = toOrderV2(&OrderResponse{Order: order})
+	}
+
+	response.Success(c, result)
+}
+
+// ConfirmShipmentRequest represents a shipment confirmation from a warehouse
+type ConfirmShipmentRequest struct {
+	Carrier        string     `json:"carrier" binding:"required"`
+	TrackingNumber string     `json:"tracking_number" binding:"required"`
+	TrackingURL    string     `json:"tracking_url" binding:"omitempty,url"`
+	ShippedAt      *time.Time `json:"shipped_at"`
+}
+
+// ConfirmShipment records a shipment with its tracking number and marks the order shipped
+// POST /fulfillment/orders/:id/shipments
+func (h *FulfillmentHandler) ConfirmShipment(c *gin.Context) {
+	keyID, _ := middleware.GetAPIKeyID(c)
+
+	var req ConfirmShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	shipment, err := h.fulfillmentService.ConfirmShipment(c.Request.Context(), c.Param("id"), services.ShipmentRequest{
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		TrackingURL:    req.TrackingURL,
+		ShippedAt:      req.ShippedAt,
+		APIKeyID:       keyID,
+	})
+	if err != nil {
+		switch err {
+		case orders.ErrOrderNotFound:
+			response.NotFound(c, "Order not found")
+		case services.ErrInvalidShipment:
+			response.BadRequest(c, err.Error())
+		case services.ErrOrderNotFulfillable:
+			response.Conflict(c, "Order is not awaiting fulfillment")
+		case services.ErrOrderOnHold:
+			response.Conflict(c, "Order is on hold for pricing review")
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Created(c, shipment)
+}
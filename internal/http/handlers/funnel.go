@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// FunnelHandler handles the checkout funnel report and its drop-off alerts
+type FunnelHandler struct {
+	funnelService *services.FunnelService
+}
+
+// NewFunnelHandler creates a new FunnelHandler
+func NewFunnelHandler(funnelService *services.FunnelService) *FunnelHandler {
+	return &FunnelHandler{
+		funnelService: funnelService,
+	}
+}
+
+// GetFunnel reports checkout funnel counts and conversion over a window, compared with the
+// baseline before it
+// GET /admin/reports/funnel?window=24h
+func (h *FunnelHandler) GetFunnel(c *gin.Context) {
+	var window time.Duration
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			response.BadRequest(c, "window must be a duration such as 1h or 24h")
+			return
+		}
+		window = parsed
+	}
+
+	snapshot, err := h.funnelService.Snapshot(c.Request.Context(), window)
+	if err != nil {
+		if err == services.ErrInvalidFunnelWindow {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, snapshot)
+}
+
+// ListFunnelAlerts lists checkout funnel drop-off alerts, newest first
+// GET /admin/reports/funnel/alerts
+func (h *FunnelHandler) ListFunnelAlerts(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	alerts, total, err := h.funnelService.ListAlerts(c.Request.Context(), params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, alerts, response.NewPaginationMeta(params.Page, params.PageSize, total))
+}
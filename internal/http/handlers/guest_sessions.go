@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// GuestSessionHandler issues guest session tokens for headless storefronts
+type GuestSessionHandler struct {
+	guestSessions *services.GuestSessionService
+}
+
+// NewGuestSessionHandler creates a new GuestSessionHandler
+func NewGuestSessionHandler(guestSessions *services.GuestSessionService) *GuestSessionHandler {
+	return &GuestSessionHandler{
+		guestSessions: guestSessions,
+	}
+}
+
+// CreateGuestSession issues a guest session token. A valid token sent in the
+// X-Guest-Session header is renewed for the same session; otherwise a new session starts.
+// POST /guest-sessions
+func (h *GuestSessionHandler) CreateGuestSession(c *gin.Context) {
+	sessionID := ""
+	if token := c.GetHeader(services.GuestSessionHeader); token != "" {
+		if id, err := h.guestSessions.Verify(token); err == nil {
+			sessionID = id
+		}
+	}
+
+	response.Created(c, h.guestSessions.Issue(sessionID))
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RegisterHALSerializers registers the HAL collection names and self links for the
+// resources the API returns, used when a client sends Accept: application/hal+json
+func RegisterHALSerializers() {
+	response.RegisterHALSerializer("products", func(p *services.ProductResponse) string {
+		return "/api/v1/catalog/products/" + p.ID
+	})
+	response.RegisterHALSerializer("categories", func(c *catalog.Category) string {
+		return "/api/v1/catalog/products/category/" + c.ID
+	})
+	response.RegisterHALSerializer("brands", func(b *catalog.Brand) string {
+		return ""
+	})
+
+	response.RegisterHALSerializer("orders", func(o *orders.Order) string {
+		return "/api/v1/orders/" + o.ID
+	})
+	response.RegisterHALSerializer("orders", func(o *OrderResponse) string {
+		return "/api/v1/orders/" + o.ID
+	})
+	response.RegisterHALSerializer("orders", func(o *OrderV2) string {
+		return "/api/v2/orders/" + o.ID
+	})
+	response.RegisterHALSerializer("refunds", func(r *services.OrderRefund) string {
+		return ""
+	})
+	response.RegisterHALSerializer("disputes", func(d *services.Dispute) string {
+		return "/api/v1/admin/disputes/" + d.ID
+	})
+
+	response.RegisterHALSerializer("store_credit_balances", func(b *services.StoreCreditBalance) string {
+		return ""
+	})
+	response.RegisterHALSerializer("store_credit_transactions", func(t *services.StoreCreditTransaction) string {
+		return ""
+	})
+
+	response.RegisterHALSerializer("shipments", func(s *services.Shipment) string {
+		return ""
+	})
+	response.RegisterHALSerializer("api_keys", func(k *services.APIKey) string {
+		return ""
+	})
+
+	response.RegisterHALSerializer("pages", func(p *services.Page) string {
+		if p.Status == services.PageStatusPublished {
+			return "/api/v1/content/pages/" + p.Slug
+		}
+		return "/api/v1/admin/pages/" + p.ID
+	})
+	response.RegisterHALSerializer("placements", func(p *services.Placement) string {
+		return "/api/v1/admin/placements/" + p.ID
+	})
+}
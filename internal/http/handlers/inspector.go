@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+// InspectorHandler serves the requests recorded by the development request inspector
+type InspectorHandler struct {
+	inspector *middleware.RequestInspector
+}
+
+// NewInspectorHandler creates a new InspectorHandler
+func NewInspectorHandler(inspector *middleware.RequestInspector) *InspectorHandler {
+	return &InspectorHandler{
+		inspector: inspector,
+	}
+}
+
+// ListRequests returns the recently served requests, newest first, with their timing and
+// SQL counts. min_sql limits the list to requests that ran at least that many statements.
+// GET /debug/requests?min_sql=10
+func (h *InspectorHandler) ListRequests(c *gin.Context) {
+	minSQL, _ := strconv.Atoi(c.Query("min_sql"))
+	requests := h.inspector.Requests()
+	summaries := make([]middleware.InspectedRequest, 0, len(requests))
+	for _, recorded := range requests {
+		if recorded.SQLCount >= minSQL {
+			summaries = append(summaries, recorded.Summary())
+		}
+	}
+	response.Success(c, summaries)
+}
+
+// GetRequest returns a recorded request with its headers, bodies and SQL statements
+// GET /debug/requests/:id
+func (h *InspectorHandler) GetRequest(c *gin.Context) {
+	recorded, ok := h.inspector.Request(c.Param("id"))
+	if !ok {
+		response.NotFound(c, "Request not found")
+		return
+	}
+	response.Success(c, recorded)
+}
+
+// ClearRequests forgets the recorded requests
+// DELETE /debug/requests
+func (h *InspectorHandler) ClearRequests(c *gin.Context) {
+	h.inspector.Clear()
+	response.NoContent(c)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// defaultStockHistoryDays is how far back stock history goes when no from date is given
+const defaultStockHistoryDays = 30
+
+// InventoryHandler handles inventory history endpoints
+type InventoryHandler struct {
+	historyService *services.InventoryHistoryService
+}
+
+// NewInventoryHandler creates a new InventoryHandler
+func NewInventoryHandler(historyService *services.InventoryHistoryService) *InventoryHandler {
+	return &InventoryHandler{
+		historyService: historyService,
+	}
+}
+
+// GetStockHistory returns a SKU's daily snapshots and stock movements, and optionally its
+// stock at a point in time. Dates cover whole days; at=YYYY-MM-DD is the closing stock.
+// GET /admin/inventory/:sku/history?from=2026-01-01&to=2026-01-31&at=2026-01-15
+func (h *InventoryHandler) GetStockHistory(c *gin.Context) {
+	sku := c.Param("sku")
+
+	to := time.Now()
+	if toParam := c.Query("to"); toParam != "" {
+		parsed, err := parseHistoryTime(toParam)
+		if err != nil {
+			response.BadRequest(c, "to must be a date in YYYY-MM-DD format or an RFC 3339 time")
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -defaultStockHistoryDays)
+	if fromParam := c.Query("from"); fromParam != "" {
+		parsed, err := time.Parse("2006-01-02", fromParam)
+		if err != nil {
+			response.BadRequest(c, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		from = parsed
+	}
+
+	history, err := h.historyService.History(c.Request.Context(), sku, from, to)
+	if err != nil {
+		if err == services.ErrInvalidStockHistoryRange {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	if atParam := c.Query("at"); atParam != "" {
+		at, err := parseHistoryTime(atParam)
+		if err != nil {
+			response.BadRequest(c, "at must be a date in YYYY-MM-DD format or an RFC 3339 time")
+			return
+		}
+		stockAt, err := h.historyService.StockAt(c.Request.Context(), sku, at)
+		if err != nil {
+			if err == services.ErrNoStockHistory {
+				response.NotFound(c, err.Error())
+				return
+			}
+			response.FromError(c, err)
+			return
+		}
+		history.StockAt = stockAt
+	}
+
+	response.Success(c, history)
+}
+
+// parseHistoryTime parses an RFC 3339 time, or a date meaning the end of that day
+func parseHistoryTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// InvoiceHandler handles invoices of orders paid on company net terms: the company's view
+// and the admin accounts receivable endpoints
+type InvoiceHandler struct {
+	invoiceService *services.InvoiceService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler
+func NewInvoiceHandler(invoiceService *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// RecordInvoicePaymentRequest represents a payment received against an invoice
+type RecordInvoicePaymentRequest struct {
+	Amount     int64      `json:"amount" binding:"required,gt=0"` // in cents, in the invoice currency
+	Reference  string     `json:"reference" binding:"max=255"`
+	ReceivedAt *time.Time `json:"received_at"` // defaults to now
+}
+
+// ListCompanyInvoices lists the invoices of the current user's company, newest first unless
+// sorted
+// GET /company/invoices?status=open&sort=due_at&page=1&page_size=20
+func (h *InvoiceHandler) ListCompanyInvoices(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.InvoiceSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	invoices, err := h.invoiceService.ListCompanyInvoices(c.Request.Context(), userID, services.InvoiceFilter{
+		Status: services.InvoiceStatus(c.Query("status")),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	})
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	response.Success(c, invoices)
+}
+
+// ListInvoices lists invoices, newest first unless sorted
+// GET /admin/invoices?status=open&company_id=uuid&sort=-amount&page=1&page_size=20
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.InvoiceSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	invoices, err := h.invoiceService.ListInvoices(c.Request.Context(), services.InvoiceFilter{
+		CompanyID: c.Query("company_id"),
+		Status:    services.InvoiceStatus(c.Query("status")),
+		Sort:      sort,
+		Limit:     params.CalculateLimit(),
+		Offset:    params.CalculateOffset(),
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, invoices)
+}
+
+// GetOverdueInvoices reports open invoices past their due date, most overdue first
+// GET /admin/invoices/overdue
+func (h *InvoiceHandler) GetOverdueInvoices(c *gin.Context) {
+	report, err := h.invoiceService.OverdueReport(c.Request.Context(), time.Now())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// GetInvoice retrieves an invoice with its payments
+// GET /admin/invoices/:id
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	invoice, err := h.invoiceService.GetInvoice(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	response.Success(c, invoice)
+}
+
+// RecordInvoicePayment records a payment received against an open invoice; settling the
+// invoice marks its order paid
+// POST /admin/invoices/:id/payments
+func (h *InvoiceHandler) RecordInvoicePayment(c *gin.Context) {
+	adminID, _ := middleware.GetUserID(c)
+
+	var req RecordInvoicePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	invoice, err := h.invoiceService.RecordPayment(c.Request.Context(), adminID, c.Param("id"), services.InvoicePaymentInput{
+		Amount:     req.Amount,
+		Reference:  req.Reference,
+		ReceivedAt: req.ReceivedAt,
+	})
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	response.Success(c, invoice)
+}
+
+// respondInvoiceError maps invoice errors to HTTP responses
+func respondInvoiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvoiceNotFound):
+		response.NotFound(c, "Invoice not found")
+	case errors.Is(err, services.ErrNetTermsUnavailable), errors.Is(err, services.ErrNotCompanyMember):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, services.ErrInvalidInvoicePayment):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrInvoiceNotOpen), errors.Is(err, services.ErrInvoiceOverpaid):
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
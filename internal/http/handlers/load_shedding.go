@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+// LoadSheddingHandler reports on low-priority traffic shed under load
+type LoadSheddingHandler struct {
+	loadShedder *middleware.LoadShedder
+}
+
+// NewLoadSheddingHandler creates a new LoadSheddingHandler; loadShedder is nil when load shedding is disabled
+func NewLoadSheddingHandler(loadShedder *middleware.LoadShedder) *LoadSheddingHandler {
+	return &LoadSheddingHandler{
+		loadShedder: loadShedder,
+	}
+}
+
+// LoadSheddingResponse reports whether load shedding is on, what it has shed and the current load
+type LoadSheddingResponse struct {
+	Enabled bool                  `json:"enabled"`
+	Stats   *middleware.LoadStats `json:"stats,omitempty"`
+}
+
+// GetLoadShedding returns counts of shed requests since startup
+// GET /admin/load-shedding
+func (h *LoadSheddingHandler) GetLoadShedding(c *gin.Context) {
+	if h.loadShedder == nil {
+		response.Success(c, LoadSheddingResponse{Enabled: false})
+		return
+	}
+
+	stats := h.loadShedder.Stats()
+	response.Success(c, LoadSheddingResponse{Enabled: true, Stats: &stats})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/devchuckcamp/goauthx"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// LoginSecurityHandler handles the admin account lockout endpoints
+type LoginSecurityHandler struct {
+	securityService *services.LoginSecurityService
+	authService     *goauthx.Service
+}
+
+// NewLoginSecurityHandler creates a new LoginSecurityHandler
+func NewLoginSecurityHandler(securityService *services.LoginSecurityService, authService *goauthx.Service) *LoginSecurityHandler {
+	return &LoginSecurityHandler{
+		securityService: securityService,
+		authService:     authService,
+	}
+}
+
+// GetUserLockout retrieves a user's failed login count and lockout
+// GET /admin/users/:id/lockout
+func (h *LoginSecurityHandler) GetUserLockout(c *gin.Context) {
+	user, err := h.authService.GetUserByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.NotFound(c, "User not found")
+		return
+	}
+
+	lockout, err := h.securityService.Lockout(c.Request.Context(), user.Email)
+	if err == services.ErrLoginLockoutNotFound {
+		lockout, err = &services.LoginLockout{Email: user.Email}, nil
+	}
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, lockout)
+}
+
+// UnlockUser lifts a user's lockout and resets their failed login count
+// POST /admin/users/:id/unlock
+func (h *LoginSecurityHandler) UnlockUser(c *gin.Context) {
+	user, err := h.authService.GetUserByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.NotFound(c, "User not found")
+		return
+	}
+
+	if err := h.securityService.Unlock(c.Request.Context(), user.Email); err != nil {
+		if err == services.ErrLoginLockoutNotFound {
+			response.NotFound(c, "Account is not locked")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
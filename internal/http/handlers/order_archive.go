@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderArchiveHandler previews order archival for admins
+type OrderArchiveHandler struct {
+	archiveService *services.OrderArchiveService
+}
+
+// NewOrderArchiveHandler creates a new OrderArchiveHandler
+func NewOrderArchiveHandler(archiveService *services.OrderArchiveService) *OrderArchiveHandler {
+	return &OrderArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// GetOrderArchiveReport reports how many delivered orders would be archived if the
+// order-archive task ran now, without moving any
+// GET /admin/order-archive
+func (h *OrderArchiveHandler) GetOrderArchiveReport(c *gin.Context) {
+	report, err := h.archiveService.Run(c.Request.Context(), true)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// OrderBumpHandler handles order bump endpoints
+type OrderBumpHandler struct {
+	bumpService *services.OrderBumpService
+}
+
+// NewOrderBumpHandler creates a new OrderBumpHandler
+func NewOrderBumpHandler(bumpService *services.OrderBumpService) *OrderBumpHandler {
+	return &OrderBumpHandler{
+		bumpService: bumpService,
+	}
+}
+
+// OrderBumpRequest represents the request to create or update an order bump
+type OrderBumpRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Headline  string `json:"headline" binding:"required,max=255"`
+	Price     int64  `json:"price" binding:"required,gt=0"` // in cents, in the product's currency
+	Position  int    `json:"position" binding:"gte=0"`
+	IsActive  *bool  `json:"is_active"` // defaults to true
+}
+
+// toOrderBump applies the request onto an order bump
+func (r *OrderBumpRequest) toOrderBump(bump *services.OrderBump) {
+	bump.ProductID = r.ProductID
+	bump.Headline = r.Headline
+	bump.Price = money.Money{Amount: r.Price}
+	bump.Position = r.Position
+	bump.IsActive = r.IsActive == nil || *r.IsActive
+}
+
+// ListOrderBumps lists every order bump in the order they are offered
+// GET /admin/order-bumps
+func (h *OrderBumpHandler) ListOrderBumps(c *gin.Context) {
+	bumps, err := h.bumpService.ListBumps(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, bumps)
+}
+
+// GetOrderBump retrieves an order bump
+// GET /admin/order-bumps/:id
+func (h *OrderBumpHandler) GetOrderBump(c *gin.Context) {
+	bump, err := h.bumpService.GetBump(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, bump)
+}
+
+// CreateOrderBump creates an order bump
+// POST /admin/order-bumps
+func (h *OrderBumpHandler) CreateOrderBump(c *gin.Context) {
+	var req OrderBumpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	bump := &services.OrderBump{}
+	req.toOrderBump(bump)
+	if err := h.bumpService.CreateBump(c.Request.Context(), bump); err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+
+	response.Created(c, bump)
+}
+
+// UpdateOrderBump replaces an order bump
+// PUT /admin/order-bumps/:id
+func (h *OrderBumpHandler) UpdateOrderBump(c *gin.Context) {
+	var req OrderBumpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.bumpService.GetBump(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	bump := &services.OrderBump{ID: current.ID}
+	req.toOrderBump(bump)
+	if err := h.bumpService.UpdateBump(c.Request.Context(), bump); err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored bump
+	if bump, err = h.bumpService.GetBump(c.Request.Context(), bump.ID); err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, bump)
+}
+
+// DeleteOrderBump deletes an order bump
+// DELETE /admin/order-bumps/:id
+func (h *OrderBumpHandler) DeleteOrderBump(c *gin.Context) {
+	if err := h.bumpService.DeleteBump(c.Request.Context(), c.Param("id")); err != nil {
+		respondOrderBumpError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondOrderBumpError maps order bump errors to HTTP responses
+func respondOrderBumpError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidOrderBump):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrOrderBumpUnavailable):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrOrderBumpNotFound):
+		response.NotFound(c, "Order bump not found")
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	default:
+		response.FromError(c, err)
+	}
+}
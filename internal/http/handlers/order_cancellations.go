@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderCancellationHandler handles order cancellations by customers and staff
+type OrderCancellationHandler struct {
+	orderService        *services.OrderService
+	cancellationService *services.OrderCancellationService
+}
+
+// NewOrderCancellationHandler creates a new OrderCancellationHandler
+func NewOrderCancellationHandler(orderService *services.OrderService, cancellationService *services.OrderCancellationService) *OrderCancellationHandler {
+	return &OrderCancellationHandler{
+		orderService:        orderService,
+		cancellationService: cancellationService,
+	}
+}
+
+// CancelOrderRequest represents the request to cancel an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CancelOrder cancels an order, refunding what was paid for it and releasing its stock.
+// Customers may cancel their own orders until they are being processed; admin, manager and
+// customer experience roles any order until it has shipped.
+// POST /orders/:id/cancel
+// POST /admin/orders/:id/cancel
+func (h *OrderCancellationHandler) CancelOrder(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondOrderCancellationError(c, err)
+		return
+	}
+	staff := hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience))
+	if order.UserID != userID && !staff {
+		response.Forbidden(c, "You don't have permission to cancel this order")
+		return
+	}
+
+	cancellation, err := h.cancellationService.Cancel(c.Request.Context(), order.ID, services.CancelOrderRequest{
+		Reason:     req.Reason,
+		CanceledBy: userID,
+		Staff:      staff,
+	})
+	if err != nil {
+		respondOrderCancellationError(c, err)
+		return
+	}
+
+	response.Success(c, cancellation)
+}
+
+// respondOrderCancellationError maps order cancellation errors to HTTP responses
+func respondOrderCancellationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCancelReason):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, orders.ErrOrderNotFound):
+		response.NotFound(c, "Order not found")
+	case errors.Is(err, services.ErrOrderNotCancelable), errors.Is(err, services.ErrRefundExceedsPaid):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrAppointmentCancelWindowPassed):
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
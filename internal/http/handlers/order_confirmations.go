@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderConfirmationHandler handles resending order confirmation emails
+type OrderConfirmationHandler struct {
+	orderService        *services.OrderService
+	confirmationService *services.OrderConfirmationService
+}
+
+// NewOrderConfirmationHandler creates a new OrderConfirmationHandler
+func NewOrderConfirmationHandler(orderService *services.OrderService, confirmationService *services.OrderConfirmationService) *OrderConfirmationHandler {
+	return &OrderConfirmationHandler{
+		orderService:        orderService,
+		confirmationService: confirmationService,
+	}
+}
+
+// ResendConfirmation emails an order's confirmation to its customer again. Customers may
+// resend their own orders' confirmations; admin, manager and customer experience roles any
+// order's. Resends are rate limited per order.
+// POST /orders/:id/resend-confirmation
+// POST /admin/orders/:id/resend-confirmation
+func (h *OrderConfirmationHandler) ResendConfirmation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, orders.ErrOrderNotFound) {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+	if order.UserID != userID && !hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience)) {
+		response.Forbidden(c, "You don't have permission to resend this order's confirmation")
+		return
+	}
+
+	if err := h.confirmationService.Resend(c.Request.Context(), order); err != nil {
+		respondOrderConfirmationError(c, err)
+		return
+	}
+
+	response.Accepted(c, gin.H{"order_id": order.ID})
+}
+
+// respondOrderConfirmationError maps order confirmation errors to HTTP responses
+func respondOrderConfirmationError(c *gin.Context, err error) {
+	var limited *services.ConfirmationResendLimitedError
+	switch {
+	case errors.As(err, &limited):
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(limited.RetryAt).Seconds())+1))
+		response.ErrorWithDetails(c, http.StatusTooManyRequests, "resend_limited", "Order confirmation was resent too recently; try again later", gin.H{
+			"retry_at": limited.RetryAt,
+		})
+	case errors.Is(err, services.ErrNoConfirmationEmail):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrOrderConfirmationsDisabled):
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "confirmations_disabled", err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// OrderEventHandler handles the admin view of order timelines
+type OrderEventHandler struct {
+	eventService *services.OrderEventService
+	orderService *services.OrderService
+}
+
+// NewOrderEventHandler creates a new OrderEventHandler
+func NewOrderEventHandler(eventService *services.OrderEventService, orderService *services.OrderService) *OrderEventHandler {
+	return &OrderEventHandler{
+		eventService: eventService,
+		orderService: orderService,
+	}
+}
+
+// AddOrderNoteRequest represents a staff note on an order's timeline
+type AddOrderNoteRequest struct {
+	Note     string `json:"note" binding:"required"`
+	Internal bool   `json:"internal"` // hide the note from the customer
+}
+
+// GetTimeline returns an order's full timeline, including internal notes
+// GET /admin/orders/:id/timeline
+func (h *OrderEventHandler) GetTimeline(c *gin.Context) {
+	if !h.orderExists(c) {
+		return
+	}
+
+	timeline, err := h.eventService.Timeline(c.Request.Context(), c.Param("id"), true)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, timeline)
+}
+
+// AddNote adds a note to an order's timeline
+// POST /admin/orders/:id/notes
+func (h *OrderEventHandler) AddNote(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req AddOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	if !h.orderExists(c) {
+		return
+	}
+
+	event, err := h.eventService.AddNote(c.Request.Context(), c.Param("id"), req.Note, req.Internal, userID)
+	if err != nil {
+		if err == services.ErrInvalidOrderNote {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, event)
+}
+
+// orderExists looks up the :id order, responding 404 if there is none
+func (h *OrderEventHandler) orderExists(c *gin.Context) bool {
+	if _, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id")); err != nil {
+		if err == orders.ErrOrderNotFound {
+			response.NotFound(c, "Order not found")
+			return false
+		}
+		response.FromError(c, err)
+		return false
+	}
+	return true
+}
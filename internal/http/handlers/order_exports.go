@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// defaultExportDays is how far back an order export reaches without a from date
+const defaultExportDays = 365
+
+// OrderExportHandler handles customer exports of their order history
+type OrderExportHandler struct {
+	exportService *services.OrderExportService
+}
+
+// NewOrderExportHandler creates a new OrderExportHandler
+func NewOrderExportHandler(exportService *services.OrderExportService) *OrderExportHandler {
+	return &OrderExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportOrders downloads the customer's orders and line items as CSV, by default for the
+// last year. Larger histories are exported in the background: the response is 202 with
+// the export, whose status carries the download link once ready.
+// GET /me/orders/export?from=2026-01-01&to=2026-10-31
+func (h *OrderExportHandler) ExportOrders(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -(defaultExportDays - 1))
+	for param, day := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				response.BadRequest(c, param+" must be in YYYY-MM-DD format")
+				return
+			}
+			*day = parsed
+		}
+	}
+
+	data, export, err := h.exportService.Export(c.Request.Context(), userID, from, to, c.Query("format"))
+	if err != nil {
+		respondOrderExportError(c, err)
+		return
+	}
+	if export != nil {
+		response.Accepted(c, export)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+services.ExportFilename(from, to)+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// GetOrderExport returns the status of one of the customer's background exports
+// GET /me/orders/exports/:id
+func (h *OrderExportHandler) GetOrderExport(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondOrderExportError(c, err)
+		return
+	}
+
+	response.Success(c, export)
+}
+
+// DownloadOrderExport serves a completed export's file; the signed link stands in for
+// authentication so it can be opened straight from a browser or an email
+// GET /order-exports/:id/download?expires=...&signature=...
+func (h *OrderExportHandler) DownloadOrderExport(c *gin.Context) {
+	path, filename, err := h.exportService.Open(c.Request.Context(), c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		respondOrderExportError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.FileAttachment(path, filename)
+}
+
+// respondOrderExportError maps order export errors to HTTP responses
+func respondOrderExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidExportRange), errors.Is(err, services.ErrUnsupportedExportFormat):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrOrderExportNotFound):
+		response.NotFound(c, "Order export not found")
+	case errors.Is(err, services.ErrOrderExportInProgress), errors.Is(err, services.ErrOrderExportNotReady):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrInvalidExportLink):
+		response.Forbidden(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
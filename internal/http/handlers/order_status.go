@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderStatusHandler handles staff moving orders through the fulfillment workflow
+type OrderStatusHandler struct {
+	statusService *services.OrderStatusService
+}
+
+// NewOrderStatusHandler creates a new OrderStatusHandler
+func NewOrderStatusHandler(statusService *services.OrderStatusService) *OrderStatusHandler {
+	return &OrderStatusHandler{
+		statusService: statusService,
+	}
+}
+
+// ChangeOrderStatusRequest represents the request to change an order's status
+type ChangeOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending paid processing shipped delivered canceled refunded"`
+	Reason string `json:"reason"` // required when canceling
+}
+
+// ChangeOrderStatus moves an order to a new status, enforcing the order workflow
+// PATCH /admin/orders/:id/status
+func (h *OrderStatusHandler) ChangeOrderStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req ChangeOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	order, err := h.statusService.ChangeStatus(c.Request.Context(), c.Param("id"), services.OrderStatusChange{
+		Status:    orders.OrderStatus(req.Status),
+		Reason:    req.Reason,
+		ChangedBy: userID,
+	})
+	if err != nil {
+		respondOrderStatusError(c, err)
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// GetStatusHistory returns the status changes staff made to an order, oldest first
+// GET /admin/orders/:id/status-history
+func (h *OrderStatusHandler) GetStatusHistory(c *gin.Context) {
+	history, err := h.statusService.History(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondOrderStatusError(c, err)
+		return
+	}
+
+	response.Success(c, history)
+}
+
+// respondOrderStatusError maps order status errors to HTTP responses. Changes the workflow
+// doesn't allow get a structured 409 with the statuses the order can move to.
+func respondOrderStatusError(c *gin.Context, err error) {
+	var transitionErr *services.OrderStatusTransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		response.ErrorWithDetails(c, http.StatusConflict, "invalid_status_transition", transitionErr.Error(), gin.H{
+			"from":    transitionErr.From,
+			"to":      transitionErr.To,
+			"allowed": transitionErr.Allowed,
+		})
+	case errors.Is(err, services.ErrOrderOnHold):
+		response.Conflict(c, "Order is on hold for pricing review")
+	case errors.Is(err, orders.ErrInvalidStatus):
+		response.Conflict(c, "Order status changed meanwhile; reload the order and try again")
+	default:
+		respondOrderCancellationError(c, err)
+	}
+}
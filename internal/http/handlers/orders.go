@@ -1,19 +1,46 @@
 package handlers
 
 import (
+	"log"
+	"net/http"
+	"time"
+
 	"github.com/devchuckcamp/goauthx"
 	"github.com/gin-gonic/gin"
 
 	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
 	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
 	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/shipping"
 )
 
 // OrderHandler handles order endpoints
 type OrderHandler struct {
-	orderService *services.OrderService
-	cartService  *services.CartService
+	orderService    *services.OrderService
+	cartService     *services.CartService
+	deliveryService *services.DeliveryService
+	addressService  *services.AddressService
+	shippingService *services.ShippingZoneService
+	paymentService  *services.PaymentService
+	retryService    *services.PaymentRetryService
+	disputeService  *services.DisputeService
+	refundService   *services.RefundService
+	storeCredit     *services.StoreCreditService
+	autoApplyCredit bool
+	fulfillment     *services.FulfillmentService
+	events          *services.OrderEventService
+	metadata        *services.CartMetadataService
+	attribution     *services.OrderAttributionService
+	companies       *services.CompanyService
+	quotes          *services.QuoteService
+	invoices        *services.InvoiceService
+	restrictions    *services.ShippingRestrictionService
+	funnel          *services.FunnelService
+	orderBumps      *services.OrderBumpService
+	tickets         *services.SupportTicketService
 }
 
 // NewOrderHandler creates a new OrderHandler
@@ -24,6 +51,141 @@ func NewOrderHandler(orderService *services.OrderService, cartService *services.
 	}
 }
 
+// WithDeliveryService enables delivery slot selection at checkout
+func (h *OrderHandler) WithDeliveryService(deliveryService *services.DeliveryService) *OrderHandler {
+	h.deliveryService = deliveryService
+	return h
+}
+
+// WithAddressService enables address normalization and deliverability checks at checkout
+func (h *OrderHandler) WithAddressService(addressService *services.AddressService) *OrderHandler {
+	h.addressService = addressService
+	return h
+}
+
+// WithShippingService restricts checkout to shipping methods offered in the destination's zone
+func (h *OrderHandler) WithShippingService(shippingService *services.ShippingZoneService) *OrderHandler {
+	h.shippingService = shippingService
+	return h
+}
+
+// WithPaymentService enables paying an order with multiple tenders
+func (h *OrderHandler) WithPaymentService(paymentService *services.PaymentService) *OrderHandler {
+	h.paymentService = paymentService
+	return h
+}
+
+// WithPaymentRetryService keeps orders with declined charges open for payment retries
+func (h *OrderHandler) WithPaymentRetryService(retryService *services.PaymentRetryService) *OrderHandler {
+	h.retryService = retryService
+	return h
+}
+
+// WithDisputeService shows chargebacks raised against an order on its detail
+func (h *OrderHandler) WithDisputeService(disputeService *services.DisputeService) *OrderHandler {
+	h.disputeService = disputeService
+	return h
+}
+
+// WithRefundService shows refunded and net totals on order detail
+func (h *OrderHandler) WithRefundService(refundService *services.RefundService) *OrderHandler {
+	h.refundService = refundService
+	return h
+}
+
+// WithStoreCreditService lets customers pay with store credit at checkout.
+// When autoApply is set, available credit is applied unless the customer opts out.
+func (h *OrderHandler) WithStoreCreditService(storeCredit *services.StoreCreditService, autoApply bool) *OrderHandler {
+	h.storeCredit = storeCredit
+	h.autoApplyCredit = autoApply
+	return h
+}
+
+// WithFulfillmentService attaches the fulfillment service so orders show their shipments
+func (h *OrderHandler) WithFulfillmentService(fulfillment *services.FulfillmentService) *OrderHandler {
+	h.fulfillment = fulfillment
+	return h
+}
+
+// WithEventService shows the order's timeline on its detail
+func (h *OrderHandler) WithEventService(events *services.OrderEventService) *OrderHandler {
+	h.events = events
+	return h
+}
+
+// WithCartMetadataService shows the client metadata the order was placed with on its detail
+func (h *OrderHandler) WithCartMetadataService(metadata *services.CartMetadataService) *OrderHandler {
+	h.metadata = metadata
+	return h
+}
+
+// WithAttributionService records the channel and UTM parameters orders are placed with
+func (h *OrderHandler) WithAttributionService(attribution *services.OrderAttributionService) *OrderHandler {
+	h.attribution = attribution
+	return h
+}
+
+// WithCompanyService holds company buyers' orders above the approval threshold for sign-off
+func (h *OrderHandler) WithCompanyService(companies *services.CompanyService) *OrderHandler {
+	h.companies = companies
+	return h
+}
+
+// WithQuoteService lets buyers check out with a sent quote at its negotiated prices
+func (h *OrderHandler) WithQuoteService(quotes *services.QuoteService) *OrderHandler {
+	h.quotes = quotes
+	return h
+}
+
+// WithInvoiceService lets company buyers with net terms pay by invoice
+func (h *OrderHandler) WithInvoiceService(invoices *services.InvoiceService) *OrderHandler {
+	h.invoices = invoices
+	return h
+}
+
+// WithShippingRestrictions rejects orders that break a product's shipping restrictions
+func (h *OrderHandler) WithShippingRestrictions(restrictions *services.ShippingRestrictionService) *OrderHandler {
+	h.restrictions = restrictions
+	return h
+}
+
+// WithFunnel counts orders placed at checkout as reaching order created in the checkout funnel
+func (h *OrderHandler) WithFunnel(funnel *services.FunnelService) *OrderHandler {
+	h.funnel = funnel
+	return h
+}
+
+// WithOrderBumps lets customers accept the order bumps offered at checkout when placing
+// the order
+func (h *OrderHandler) WithOrderBumps(orderBumps *services.OrderBumpService) *OrderHandler {
+	h.orderBumps = orderBumps
+	return h
+}
+
+// WithSupportTickets shows staff the support tickets opened against an order when they
+// view it
+func (h *OrderHandler) WithSupportTickets(tickets *services.SupportTicketService) *OrderHandler {
+	h.tickets = tickets
+	return h
+}
+
+// OrderResponse wraps orders.Order with checkout selections stored alongside it
+type OrderResponse struct {
+	*orders.Order
+	DeliverySlot *services.DeliverySlot `json:"DeliverySlot,omitempty"`
+	Disputed     bool                   `json:"Disputed"` // true while a chargeback is open
+	Disputes     []*services.Dispute    `json:"Disputes,omitempty"`
+	Refunds      *services.RefundTotals `json:"Refunds,omitempty"`
+	Shipments    []*services.Shipment   `json:"Shipments,omitempty"`
+	Timeline     []*services.OrderEvent `json:"Timeline,omitempty"`
+	Metadata     *services.CartMetadata `json:"Metadata,omitempty"` // items keyed by order item ID
+	Approval     *services.CompanyOrder `json:"Approval,omitempty"` // company orders only
+	Invoice      *services.Invoice      `json:"Invoice,omitempty"`  // orders paid by invoice only
+
+	// Support tickets opened against the order, shown to staff only
+	Tickets []*services.SupportTicket `json:"Tickets,omitempty"`
+}
+
 // CreateOrderRequest represents the request to create an order
 type CreateOrderRequest struct {
 	ShippingAddress  AddressRequest  `json:"shipping_address" binding:"required"`
@@ -31,7 +193,23 @@ type CreateOrderRequest struct {
 	PaymentMethodID  string          `json:"payment_method_id"`
 	PromotionCodes   []string        `json:"promotion_codes"`
 	ShippingMethodID string          `json:"shipping_method_id"`
+	DeliverySlotID   string          `json:"delivery_slot_id"`
+	Payments         []TenderRequest `json:"payments" binding:"omitempty,max=5,dive"`
+	ApplyStoreCredit *bool           `json:"apply_store_credit"` // defaults to the store's auto-apply setting for single payment checkouts
 	Notes            string          `json:"notes"`
+	Channel          string          `json:"channel" binding:"omitempty,oneof=web mobile_app marketplace"` // defaults to web
+	UTM              services.UTM    `json:"utm"`
+	QuoteID          string          `json:"quote_id"`       // check out the quote's items at its prices instead of the cart
+	PayByInvoice     bool            `json:"pay_by_invoice"` // company net terms; takes no payment method
+	DateOfBirth      string          `json:"date_of_birth"`  // YYYY-MM-DD; required for age-restricted products
+	OrderBumpIDs     []string        `json:"order_bump_ids" binding:"omitempty,max=3"`
+}
+
+// TenderRequest represents one tender when splitting payment across several
+type TenderRequest struct {
+	Type            string `json:"type" binding:"required,oneof=card gift_card"`
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"` // in cents, in the order currency
 }
 
 // AddressRequest represents an address
@@ -48,6 +226,22 @@ type AddressRequest struct {
 	PhoneNumber string `json:"phone_number"`
 }
 
+// ToOrderAddress converts the request address to the domain address
+func (a AddressRequest) ToOrderAddress() orders.Address {
+	return orders.Address{
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		Company:      a.Company,
+		AddressLine1: a.Address1,
+		AddressLine2: a.Address2,
+		City:         a.City,
+		State:        a.State,
+		PostalCode:   a.PostalCode,
+		Country:      a.Country,
+		Phone:        a.PhoneNumber,
+	}
+}
+
 // CreateOrder creates a new order from the user's cart
 // POST /orders
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
@@ -59,14 +253,13 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 	var req CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request body")
+		response.InvalidRequest(c, err)
 		return
 	}
 
-	// Get user's cart
-	cart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
-	if err != nil {
-		response.InternalServerError(c, err.Error())
+	// Get user's cart, or the quote's items at the negotiated prices
+	cart, ok := h.checkoutCart(c, userID, req)
+	if !ok {
 		return
 	}
 
@@ -76,34 +269,147 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	// Convert addresses
-	shippingAddr := orders.Address{
-		FirstName:    req.ShippingAddress.FirstName,
-		LastName:     req.ShippingAddress.LastName,
-		Company:      req.ShippingAddress.Company,
-		AddressLine1: req.ShippingAddress.Address1,
-		AddressLine2: req.ShippingAddress.Address2,
-		City:         req.ShippingAddress.City,
-		State:        req.ShippingAddress.State,
-		PostalCode:   req.ShippingAddress.PostalCode,
-		Country:      req.ShippingAddress.Country,
-		Phone:        req.ShippingAddress.PhoneNumber,
+	// Accepted order bumps join the order at their special prices, so they are only bought
+	// with it; the stored cart is left as it is
+	if len(req.OrderBumpIDs) > 0 {
+		if h.orderBumps == nil || req.QuoteID != "" {
+			response.BadRequest(c, "Order bumps are not available for this checkout")
+			return
+		}
+		withBumps, err := h.orderBumps.Apply(c.Request.Context(), cart, req.OrderBumpIDs)
+		if err != nil {
+			respondOrderBumpError(c, err)
+			return
+		}
+		cart = withBumps
 	}
 
+	// Convert addresses
+	shippingAddr := req.ShippingAddress.ToOrderAddress()
+
 	billingAddr := shippingAddr
 	if req.BillingAddress != nil {
-		billingAddr = orders.Address{
-			FirstName:    req.BillingAddress.FirstName,
-			LastName:     req.BillingAddress.LastName,
-			Company:      req.BillingAddress.Company,
-			AddressLine1: req.BillingAddress.Address1,
-			AddressLine2: req.BillingAddress.Address2,
-			City:         req.BillingAddress.City,
-			State:        req.BillingAddress.State,
-			PostalCode:   req.BillingAddress.PostalCode,
-			Country:      req.BillingAddress.Country,
-			Phone:        req.BillingAddress.PhoneNumber,
+		billingAddr = req.BillingAddress.ToOrderAddress()
+	}
+
+	// Normalize addresses and reject undeliverable ones before pricing the order
+	if h.addressService != nil {
+		var ok bool
+		if shippingAddr, ok = h.normalizeAddress(c, "shipping_address", shippingAddr); !ok {
+			return
+		}
+		if billingAddr, ok = h.normalizeAddress(c, "billing_address", billingAddr); !ok {
+			return
+		}
+	}
+
+	// Only allow shipping methods offered in the destination's shipping zone
+	if h.shippingService != nil && req.ShippingMethodID != "" {
+		_, err := h.shippingService.GetRate(c.Request.Context(), shipping.RateRequest{
+			DestinationAddress: toShippingAddress(shippingAddr),
+			ShippingMethodID:   req.ShippingMethodID,
+		})
+		if err != nil {
+			if err == services.ErrShippingMethodUnavailable {
+				response.BadRequest(c, "Shipping method not available for this address")
+				return
+			}
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	// Hazardous and regulated products limit the shipping method, address and buyer
+	if h.restrictions != nil {
+		err := h.restrictions.CheckOrder(c.Request.Context(), cart, services.ShippingOrder{
+			Address:          shippingAddr,
+			ShippingMethodID: req.ShippingMethodID,
+			DateOfBirth:      req.DateOfBirth,
+		})
+		if err != nil {
+			if restrictionErr, ok := err.(*services.ShippingRestrictionError); ok {
+				respondShippingRestrictionError(c, restrictionErr)
+				return
+			}
+			if err == services.ErrShippingMethodUnavailable {
+				response.BadRequest(c, "Shipping method not available for this address")
+				return
+			}
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if h.attribution != nil {
+		if err := h.attribution.Validate(services.OrderChannel(req.Channel), req.UTM); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	if len(req.Payments) > 0 && h.paymentService == nil {
+		response.BadRequest(c, "Split payments are not available")
+		return
+	}
+
+	// Orders held for approval are charged to a single payment method once approved
+	if len(req.Payments) > 0 && h.companies != nil {
+		usesApproval, err := h.companies.UsesApproval(c.Request.Context(), userID)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		if usesApproval {
+			response.BadRequest(c, "Company buyers pay with a single payment method")
+			return
+		}
+	}
+
+	// Orders paid by invoice are settled through accounts receivable, so take no other payment
+	if req.PayByInvoice {
+		if h.invoices == nil {
+			response.BadRequest(c, "Paying by invoice is not available")
+			return
+		}
+		if req.PaymentMethodID != "" || len(req.Payments) > 0 || (req.ApplyStoreCredit != nil && *req.ApplyStoreCredit) {
+			response.BadRequest(c, "Orders paid by invoice take no other payment")
+			return
+		}
+		if _, err := h.invoices.NetTerms(c.Request.Context(), userID); err != nil {
+			respondInvoiceError(c, err)
+			return
+		}
+	}
+
+	applyCredit := h.autoApplyCredit && len(req.Payments) == 0 && !req.PayByInvoice
+	if req.ApplyStoreCredit != nil {
+		applyCredit = *req.ApplyStoreCredit
+	}
+	// Guests have no store credit
+	if applyCredit && (h.storeCredit == nil || h.paymentService == nil || services.IsGuestUserID(userID)) {
+		if req.ApplyStoreCredit != nil {
+			response.BadRequest(c, "Store credit is not available")
+			return
 		}
+		applyCredit = false
+	}
+
+	// Validate the delivery slot up front so an unbookable slot doesn't leave an order behind
+	if req.DeliverySlotID != "" {
+		if h.deliveryService == nil {
+			response.BadRequest(c, "Delivery slot selection is not available")
+			return
+		}
+		if _, err := h.deliveryService.ValidateSlot(c.Request.Context(), req.DeliverySlotID, shippingAddr.PostalCode); err != nil {
+			respondDeliverySlotError(c, err)
+			return
+		}
+	}
+
+	// Split payments are charged per tender below, not as a single charge for the order
+	paymentMethodID := req.PaymentMethodID
+	if len(req.Payments) > 0 {
+		paymentMethodID = ""
 	}
 
 	// Create order using gocommerce domain service
@@ -112,7 +418,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		UserID:           userID,
 		ShippingAddress:  shippingAddr,
 		BillingAddress:   billingAddr,
-		PaymentMethodID:  req.PaymentMethodID,
+		PaymentMethodID:  paymentMethodID,
 		PromotionCodes:   req.PromotionCodes,
 		ShippingMethodID: req.ShippingMethodID,
 		Notes:            req.Notes,
@@ -130,14 +436,161 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 			response.BadRequest(c, "Invalid address")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		if limitErr, ok := err.(*services.PurchaseLimitError); ok {
+			respondPurchaseLimitError(c, limitErr)
+			return
+		}
+		if ruleErr, ok := err.(*services.CheckoutRuleError); ok {
+			respondCheckoutRuleError(c, ruleErr)
+			return
+		}
+		if totalsErr, ok := err.(*services.OrderTotalsError); ok {
+			respondOrderTotalsError(c, totalsErr)
+			return
+		}
+		if isRentalError(err) {
+			respondRentalError(c, err)
+			return
+		}
+		if isAppointmentError(err) {
+			respondAppointmentError(c, err)
+			return
+		}
+		if isDonationError(err) {
+			respondDonationError(c, err)
+			return
+		}
+		if isRegistryError(err) {
+			respondWishlistError(c, err)
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	// Accepting fails when the quote was accepted or declined meanwhile; drop the order then
+	if req.QuoteID != "" {
+		if _, err := h.quotes.AcceptQuote(c.Request.Context(), req.QuoteID, order.ID); err != nil {
+			if cancelErr := h.abandonOrder(c, order.ID, "quote no longer open"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			respondQuoteError(c, err)
+			return
+		}
+	}
+
+	// Attribution only feeds reports, so failing to record it doesn't fail checkout
+	if h.attribution != nil {
+		if _, err := h.attribution.Record(c.Request.Context(), order.ID, services.OrderChannel(req.Channel), req.UTM); err != nil {
+			log.Printf("Failed to record attribution for order %s: %v", order.ID, err)
+		}
+	}
+	if h.funnel != nil {
+		h.funnel.Record(c.Request.Context(), services.FunnelStageOrderCreated, order.ID)
+	}
+
+	result := &OrderResponse{Order: order}
+	if h.metadata != nil {
+		if result.Metadata, err = h.orderMetadata(c, order.ID); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if req.DeliverySlotID != "" {
+		slot, err := h.deliveryService.ReserveSlot(c.Request.Context(), req.DeliverySlotID, order.ID, shippingAddr.PostalCode)
+		if err != nil {
+			// The slot filled up between validation and booking; don't keep an order without its slot
+			if cancelErr := h.abandonOrder(c, order.ID, "delivery slot unavailable"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			respondDeliverySlotError(c, err)
+			return
+		}
+		result.DeliverySlot = slot
+	}
+
+	if req.PayByInvoice {
+		if result.Invoice, err = h.invoices.OpenInvoice(c.Request.Context(), userID, order); err != nil {
+			if cancelErr := h.abandonOrder(c, order.ID, "invoice not opened"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			respondInvoiceError(c, err)
+			return
+		}
+	}
+
+	if h.companies != nil {
+		if result.Approval, err = h.companies.RecordOrder(c.Request.Context(), order); err != nil {
+			if cancelErr := h.abandonOrder(c, order.ID, "company order not recorded"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			response.FromError(c, err)
+			return
+		}
+		// Nothing is charged, store credit included, until an approver signs the order off
+		if result.Approval != nil && result.Approval.ApprovalStatus == services.ApprovalPending {
+			response.Created(c, presentOrder(c, result))
+			return
+		}
+	}
+
+	// The order is paid once accounts receivable records payment of the invoice
+	if result.Invoice != nil {
+		response.Created(c, presentOrder(c, result))
 		return
 	}
 
-	response.Created(c, order)
+	tenders := toTenderRequests(order, req.Payments)
+	if applyCredit {
+		if tenders, err = h.withStoreCredit(c, order, tenders, req.PaymentMethodID); err != nil {
+			if cancelErr := h.abandonOrder(c, order.ID, "store credit unavailable"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	// With a gateway, a single payment method is charged as one card tender for the total
+	if len(tenders) == 0 && req.PaymentMethodID != "" && h.paymentService != nil && h.paymentService.HasGateway() {
+		tenders = []services.TenderRequest{{
+			Type:            services.TenderTypeCard,
+			PaymentMethodID: req.PaymentMethodID,
+			Amount:          order.Total,
+		}}
+	}
+
+	if len(tenders) > 0 {
+		paid, err := h.chargeTenders(c, result.Order, tenders)
+		if err == services.ErrTenderDeclined && h.retryService != nil {
+			// Keep the order in payment_pending so the customer can retry with another payment method
+			retry, retryErr := h.retryService.RecordFailure(c.Request.Context(), order.ID, "payment declined at checkout")
+			h.respondPaymentDeclined(c, order.ID, retry, retryErr)
+			return
+		}
+		if err != nil {
+			if cancelErr := h.abandonOrder(c, order.ID, "payment failed"); cancelErr != nil {
+				response.FromError(c, cancelErr)
+				return
+			}
+			respondPaymentError(c, err)
+			return
+		}
+		result.Order = paid
+	}
+
+	response.Created(c, presentOrder(c, result))
 }
 
-// ListOrders lists the current user's orders with pagination
+// ListOrders lists the current user's orders with pagination, optionally by status and
+// the dates they were placed
+// GET /orders?status=shipped&from=2026-01-01&to=2026-10-31&page=1&page_size=20
 // GET /orders?page=1&page_size=20
 func (h *OrderHandler) ListOrders(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
@@ -149,27 +602,60 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 	// Get pagination parameters
 	params := response.GetPaginationParams(c)
 
-	filter := orders.OrderFilter{
-		Limit:  params.CalculateLimit(),
-		Offset: params.CalculateOffset(),
+	filter, ok := orderListFilter(c)
+	if !ok {
+		return
 	}
+	filter.Limit = params.CalculateLimit()
+	filter.Offset = params.CalculateOffset()
 
 	ordersList, err := h.orderService.GetUserOrders(c.Request.Context(), userID, filter)
 	if err != nil {
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
-	// Apply pagination with total count
-	// Note: gocommerce doesn't provide count, so we estimate from results
-	total := int64(len(ordersList))
-	if len(ordersList) == params.CalculateLimit() {
-		// If we got a full page, there might be more
-		total = int64(params.Page * params.PageSize) // Estimate
+	total, err := h.orderService.CountUserOrders(c.Request.Context(), userID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
 	}
 
 	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
-	response.SuccessWithPagination(c, ordersList, meta)
+	response.SuccessWithPagination(c, presentOrders(c, ordersList), meta)
+}
+
+// orderListFilter reads the status and the from and to dates of an order listing. Orders
+// placed any time on the to date are included. It responds with 400 and returns false for
+// an unknown status or a malformed date.
+func orderListFilter(c *gin.Context) (orders.OrderFilter, bool) {
+	var filter orders.OrderFilter
+	if value := c.Query("status"); value != "" {
+		status := orders.OrderStatus(value)
+		switch status {
+		case orders.OrderStatusPending, orders.OrderStatusPaid, orders.OrderStatusProcessing,
+			orders.OrderStatusShipped, orders.OrderStatusDelivered, orders.OrderStatusCanceled,
+			orders.OrderStatusRefunded:
+			filter.Status = &status
+		default:
+			response.BadRequest(c, "status must be one of pending, paid, processing, shipped, delivered, canceled or refunded")
+			return filter, false
+		}
+	}
+	for param, day := range map[string]**time.Time{"from": &filter.DateFrom, "to": &filter.DateTo} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+			if err != nil {
+				response.BadRequest(c, param+" must be in YYYY-MM-DD format")
+				return filter, false
+			}
+			if param == "to" {
+				parsed = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			*day = &parsed
+		}
+	}
+	return filter, true
 }
 
 // GetOrder retrieves a specific order by ID
@@ -193,7 +679,7 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 			response.NotFound(c, "Order not found")
 			return
 		}
-		response.InternalServerError(c, err.Error())
+		response.FromError(c, err)
 		return
 	}
 
@@ -206,7 +692,388 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		}
 	}
 
-	response.Success(c, order)
+	result := &OrderResponse{Order: order}
+	if h.deliveryService != nil {
+		slot, err := h.deliveryService.GetOrderSlot(c.Request.Context(), order.ID)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		result.DeliverySlot = slot
+	}
+
+	if h.disputeService != nil {
+		disputes, err := h.disputeService.GetOrderDisputes(c.Request.Context(), order.ID)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		result.Disputes = disputes
+		for _, dispute := range disputes {
+			if dispute.IsOpen() {
+				result.Disputed = true
+			}
+		}
+	}
+
+	if h.refundService != nil {
+		totals, err := h.refundService.Totals(c.Request.Context(), order)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		if totals.Refunded.IsPositive() {
+			result.Refunds = totals
+		}
+	}
+
+	if h.fulfillment != nil {
+		shipments, err := h.fulfillment.GetShipments(c.Request.Context(), order.ID)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		result.Shipments = shipments
+	}
+
+	if h.events != nil {
+		staff := hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience))
+		timeline, err := h.events.Timeline(c.Request.Context(), order.ID, staff)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		result.Timeline = timeline
+	}
+
+	if h.metadata != nil {
+		if result.Metadata, err = h.orderMetadata(c, order.ID); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if h.companies != nil {
+		if result.Approval, err = h.companies.GetApproval(c.Request.Context(), order.ID); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if h.invoices != nil {
+		if result.Invoice, err = h.invoices.GetOrderInvoice(c.Request.Context(), order.ID); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	if h.tickets != nil && isSupportStaff(c) {
+		if result.Tickets, err = h.tickets.OrderTickets(c.Request.Context(), order.ID); err != nil {
+			response.FromError(c, err)
+			return
+		}
+	}
+
+	response.Success(c, presentOrder(c, result))
+}
+
+// orderMetadata loads the metadata an order was placed with, or nil when it has none
+func (h *OrderHandler) orderMetadata(c *gin.Context, orderID string) (*services.CartMetadata, error) {
+	metadata, err := h.metadata.GetOrderMetadata(c.Request.Context(), orderID)
+	if err != nil || metadata.IsEmpty() {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// GetOrderPayments returns the order's payment tenders reconciled against the order total
+// GET /orders/:id/payments
+func (h *OrderHandler) GetOrderPayments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == orders.ErrOrderNotFound {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	if order.UserID != userID && !hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience)) {
+		response.Forbidden(c, "You don't have permission to view this order")
+		return
+	}
+
+	summary, err := h.paymentService.Summary(c.Request.Context(), order)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, summary)
+}
+
+// RetryPaymentRequest represents the request to retry a failed payment
+type RetryPaymentRequest struct {
+	Type            string `json:"type" binding:"omitempty,oneof=card gift_card"`
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+}
+
+// RetryPaymentResponse is the result of a payment retry
+type RetryPaymentResponse struct {
+	Order   *orders.Order           `json:"order"`
+	Payment *services.PaymentTender `json:"payment"`
+}
+
+// RetryPayment charges the balance of an order awaiting payment to a new payment method
+// POST /orders/:id/retry-payment
+func (h *OrderHandler) RetryPayment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if h.retryService == nil {
+		response.BadRequest(c, "Payment retries are not available")
+		return
+	}
+
+	var req RetryPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == orders.ErrOrderNotFound {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	if order.UserID != userID {
+		response.Forbidden(c, "You don't have permission to pay for this order")
+		return
+	}
+
+	tenderType := services.TenderTypeCard
+	if req.Type != "" {
+		tenderType = services.TenderType(req.Type)
+	}
+
+	tender, retry, err := h.retryService.RetryPayment(c.Request.Context(), order, services.TenderRequest{
+		Type:            tenderType,
+		PaymentMethodID: req.PaymentMethodID,
+	})
+	if err != nil {
+		switch err {
+		case services.ErrTenderDeclined, services.ErrPaymentRetriesExhausted:
+			h.respondPaymentDeclined(c, order.ID, retry, err)
+		case services.ErrPaymentNotPending, services.ErrNoBalanceDue:
+			response.Conflict(c, "Order is not awaiting payment")
+		default:
+			respondPaymentError(c, err)
+		}
+		return
+	}
+
+	order, err = h.orderService.GetOrder(c.Request.Context(), order.ID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, RetryPaymentResponse{Order: order, Payment: tender})
+}
+
+// respondPaymentDeclined reports a declined charge along with how the customer can retry.
+// err is the result of recording the failure; ErrPaymentRetriesExhausted means the order was canceled.
+func (h *OrderHandler) respondPaymentDeclined(c *gin.Context, orderID string, retry *services.PaymentRetry, err error) {
+	if err == services.ErrPaymentRetriesExhausted {
+		response.ErrorWithCode(c, http.StatusPaymentRequired, "payment_retries_exhausted", "Payment was declined and the order has been canceled")
+		return
+	}
+	if err != nil && err != services.ErrTenderDeclined {
+		response.FromError(c, err)
+		return
+	}
+
+	response.ErrorWithDetails(c, http.StatusPaymentRequired, "payment_failed", "Payment was declined; retry with another payment method", gin.H{
+		"order_id":           orderID,
+		"retry_url":          "/api/v1/orders/" + orderID + "/retry-payment",
+		"attempts_remaining": h.retryService.AttemptsRemaining(retry),
+		"retry_before":       retry.ExpiresAt,
+	})
+}
+
+// chargeTenders charges each tender and marks the order paid once the tenders cover its total
+func (h *OrderHandler) chargeTenders(c *gin.Context, order *orders.Order, requests []services.TenderRequest) (*orders.Order, error) {
+	if _, err := h.paymentService.ChargeTenders(c.Request.Context(), order, requests); err != nil {
+		return nil, err
+	}
+
+	summary, err := h.paymentService.Summary(c.Request.Context(), order)
+	if err != nil {
+		return nil, err
+	}
+	if !summary.IsFullyCaptured() {
+		return order, nil
+	}
+	return h.orderService.UpdateStatus(c.Request.Context(), order.ID, orders.OrderStatusPaid)
+}
+
+// withStoreCredit puts the customer's available store credit ahead of their other tenders.
+// Credit is only applied when the customer's payment covers the rest of the total: split
+// tenders must add up to the remainder, and a single payment method is charged for it.
+func (h *OrderHandler) withStoreCredit(c *gin.Context, order *orders.Order, tenders []services.TenderRequest, paymentMethodID string) ([]services.TenderRequest, error) {
+	credit, err := h.storeCredit.Available(c.Request.Context(), order.UserID, order.Total)
+	if err != nil {
+		return nil, err
+	}
+	if !credit.IsPositive() {
+		return tenders, nil
+	}
+
+	remaining, err := order.Total.Subtract(credit)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := []services.TenderRequest{{
+		Type:            services.TenderTypeStoreCredit,
+		PaymentMethodID: order.UserID,
+		Amount:          credit,
+	}}
+	switch {
+	case len(tenders) > 0:
+		return append(requests, tenders...), nil
+	case !remaining.IsPositive():
+		return requests, nil
+	case paymentMethodID != "":
+		return append(requests, services.TenderRequest{
+			Type:            services.TenderTypeCard,
+			PaymentMethodID: paymentMethodID,
+			Amount:          remaining,
+		}), nil
+	default:
+		// Nothing to pay the remainder with; leave the order to be paid as before
+		return tenders, nil
+	}
+}
+
+// toTenderRequests converts the requested tenders to amounts in the order currency
+func toTenderRequests(order *orders.Order, tenders []TenderRequest) []services.TenderRequest {
+	requests := make([]services.TenderRequest, len(tenders))
+	for i, tender := range tenders {
+		requests[i] = services.TenderRequest{
+			Type:            services.TenderType(tender.Type),
+			PaymentMethodID: tender.PaymentMethodID,
+			Amount:          money.Money{Amount: tender.Amount, Currency: order.Total.Currency},
+		}
+	}
+	return requests
+}
+
+// abandonOrder cancels an order that could not complete checkout, frees its delivery slot
+// and voids its invoice
+func (h *OrderHandler) abandonOrder(c *gin.Context, orderID, reason string) error {
+	if h.deliveryService != nil {
+		if err := h.deliveryService.ReleaseSlot(c.Request.Context(), orderID); err != nil {
+			return err
+		}
+	}
+	if h.invoices != nil {
+		if err := h.invoices.VoidOrderInvoice(c.Request.Context(), orderID, reason); err != nil {
+			return err
+		}
+	}
+	_, err := h.orderService.CancelOrder(c.Request.Context(), orderID, reason)
+	return err
+}
+
+// checkoutCart returns the cart to check out and writes an error response when there is none:
+// the shopper's cart, or with a quote ID the quote's items at its negotiated prices
+func (h *OrderHandler) checkoutCart(c *gin.Context, userID string, req CreateOrderRequest) (*cart.Cart, bool) {
+	if req.QuoteID == "" {
+		shopper, err := shopperCart(c, h.cartService)
+		if err != nil {
+			response.FromError(c, err)
+			return nil, false
+		}
+		return shopper, true
+	}
+
+	if h.quotes == nil {
+		response.BadRequest(c, "Quotes are not available")
+		return nil, false
+	}
+	// The negotiated prices already are the discount
+	if len(req.PromotionCodes) > 0 {
+		response.BadRequest(c, "Promotion codes can't be combined with a quote")
+		return nil, false
+	}
+	quoted, err := h.quotes.QuoteCart(c.Request.Context(), userID, req.QuoteID)
+	if err != nil {
+		respondQuoteError(c, err)
+		return nil, false
+	}
+	return quoted, true
+}
+
+// normalizeAddress validates an address and writes a structured 422 response if it is undeliverable
+func (h *OrderHandler) normalizeAddress(c *gin.Context, field string, address orders.Address) (orders.Address, bool) {
+	normalized, err := h.addressService.Normalize(c.Request.Context(), address)
+	if err != nil {
+		respondAddressError(c, field, err)
+		return address, false
+	}
+	return normalized, true
+}
+
+// toShippingAddress converts an order address to a shipping destination
+func toShippingAddress(address orders.Address) shipping.Address {
+	return shipping.Address{
+		Country:    address.Country,
+		State:      address.State,
+		City:       address.City,
+		PostalCode: address.PostalCode,
+	}
+}
+
+// respondDeliverySlotError maps delivery slot errors to HTTP responses
+func respondDeliverySlotError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrDeliverySlotNotFound:
+		response.NotFound(c, "Delivery slot not found")
+	case services.ErrDeliverySlotFull:
+		response.Conflict(c, "Delivery slot is fully booked")
+	case services.ErrDeliverySlotUnavailable:
+		response.BadRequest(c, "Delivery slot is not available for this address")
+	default:
+		response.FromError(c, err)
+	}
+}
+
+// respondPaymentError maps split payment errors to HTTP responses
+func respondPaymentError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidTender, services.ErrTooManyTenders, services.ErrTenderTotalMismatch:
+		response.BadRequest(c, err.Error())
+	case services.ErrTenderDeclined:
+		response.ErrorWithCode(c, http.StatusPaymentRequired, "payment_failed", "A payment tender was declined; no payment was taken")
+	default:
+		response.FromError(c, err)
+	}
 }
 
 // hasAnyRole checks if the user has any of the specified roles
@@ -225,3 +1092,11 @@ func hasAnyRole(c *gin.Context, roles ...string) bool {
 	}
 	return false
 }
+
+// respondOrderTotalsError maps an order whose totals don't reconcile to a structured 422
+// response listing every mismatch
+func respondOrderTotalsError(c *gin.Context, totalsErr *services.OrderTotalsError) {
+	response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "order_totals_mismatch", totalsErr.Error(), gin.H{
+		"mismatches": totalsErr.Mismatches,
+	})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// MoneyV2 is a money amount in the v2 shape
+type MoneyV2 struct {
+	Amount   int64  `json:"amount"` // in cents
+	Currency string `json:"currency"`
+}
+
+// OrderItemV2 is an order line with its own pricing breakdown
+type OrderItemV2 struct {
+	ID         string            `json:"id"`
+	ProductID  string            `json:"product_id"`
+	VariantID  *string           `json:"variant_id,omitempty"`
+	SKU        string            `json:"sku"`
+	Name       string            `json:"name"`
+	Quantity   int               `json:"quantity"`
+	UnitPrice  MoneyV2           `json:"unit_price"`
+	Discount   MoneyV2           `json:"discount"`
+	Tax        MoneyV2           `json:"tax"`
+	Total      MoneyV2           `json:"total"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Metadata   services.Metadata `json:"metadata,omitempty"`
+}
+
+// AddressV2 is an order address in the v2 shape, matching AddressRequest
+type AddressV2 struct {
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Company     string `json:"company,omitempty"`
+	Address1    string `json:"address1"`
+	Address2    string `json:"address2,omitempty"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	PostalCode  string `json:"postal_code"`
+	Country     string `json:"country"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+}
+
+// OrderTotalsV2 groups the order-level amounts
+type OrderTotalsV2 struct {
+	Subtotal MoneyV2  `json:"subtotal"`
+	Discount MoneyV2  `json:"discount"`
+	Tax      MoneyV2  `json:"tax"`
+	Shipping MoneyV2  `json:"shipping"`
+	Total    MoneyV2  `json:"total"`
+	Refunded *MoneyV2 `json:"refunded,omitempty"`
+	Net      *MoneyV2 `json:"net,omitempty"` // total less refunds
+}
+
+// OrderV2 is the v2 order response. Unlike v1 it uses snake_case throughout, nests amounts
+// under totals and leaves out the payment method and request metadata.
+type OrderV2 struct {
+	ID              string                 `json:"id"`
+	OrderNumber     string                 `json:"order_number"`
+	UserID          string                 `json:"user_id"`
+	Status          orders.OrderStatus     `json:"status"`
+	Items           []OrderItemV2          `json:"items"`
+	ShippingAddress AddressV2              `json:"shipping_address"`
+	BillingAddress  AddressV2              `json:"billing_address"`
+	Totals          OrderTotalsV2          `json:"totals"`
+	Notes           string                 `json:"notes,omitempty"`
+	Metadata        services.Metadata      `json:"metadata,omitempty"`
+	DeliverySlot    *services.DeliverySlot `json:"delivery_slot,omitempty"`
+	Disputed        bool                   `json:"disputed"`
+	Disputes        []*services.Dispute    `json:"disputes,omitempty"`
+	Shipments       []*services.Shipment   `json:"shipments,omitempty"`
+	Timeline        []*services.OrderEvent `json:"timeline,omitempty"`
+	Approval        *services.CompanyOrder `json:"approval,omitempty"`
+	Invoice         *services.Invoice      `json:"invoice,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	CompletedAt     *time.Time             `json:"completed_at,omitempty"`
+	CanceledAt      *time.Time             `json:"canceled_at,omitempty"`
+}
+
+// presentOrder adapts an order response to the negotiated API version
+func presentOrder(c *gin.Context, result *OrderResponse) interface{} {
+	if middleware.GetAPIVersion(c) < middleware.APIVersion2 {
+		return result
+	}
+	return toOrderV2(result)
+}
+
+// presentOrders adapts a list of orders to the negotiated API version
+func presentOrders(c *gin.Context, list []*orders.Order) interface{} {
+	if middleware.GetAPIVersion(c) < middleware.APIVersion2 {
+		return list
+	}
+	adapted := make([]*OrderV2, len(list))
+	for i, order := range list {
+		adapted[i] = toOrderV2(&OrderResponse{Order: order})
+	}
+	return adapted
+}
+
+func toOrderV2(result *OrderResponse) *OrderV2 {
+	order := result.Order
+	v2 := &OrderV2{
+		ID:              order.ID,
+		OrderNumber:     order.OrderNumber,
+		UserID:          order.UserID,
+		Status:          order.Status,
+		Items:           make([]OrderItemV2, len(order.Items)),
+		ShippingAddress: toAddressV2(order.ShippingAddress),
+		BillingAddress:  toAddressV2(order.BillingAddress),
+		Totals: OrderTotalsV2{
+			Subtotal: toMoneyV2(order.Subtotal),
+			Discount: toMoneyV2(order.DiscountTotal),
+			Tax:      toMoneyV2(order.TaxTotal),
+			Shipping: toMoneyV2(order.ShippingTotal),
+			Total:    toMoneyV2(order.Total),
+		},
+		Notes:        order.Notes,
+		DeliverySlot: result.DeliverySlot,
+		Disputed:     result.Disputed,
+		Disputes:     result.Disputes,
+		Shipments:    result.Shipments,
+		Timeline:     result.Timeline,
+		Approval:     result.Approval,
+		Invoice:      result.Invoice,
+		CreatedAt:    order.CreatedAt,
+		UpdatedAt:    order.UpdatedAt,
+		CompletedAt:  order.CompletedAt,
+		CanceledAt:   order.CanceledAt,
+	}
+
+	for i, item := range order.Items {
+		v2.Items[i] = OrderItemV2{
+			ID:         item.ID,
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			SKU:        item.SKU,
+			Name:       item.Name,
+			Quantity:   item.Quantity,
+			UnitPrice:  toMoneyV2(item.UnitPrice),
+			Discount:   toMoneyV2(item.DiscountAmount),
+			Tax:        toMoneyV2(item.TaxAmount),
+			Total:      toMoneyV2(item.Total),
+			Attributes: item.Attributes,
+		}
+	}
+	if result.Metadata != nil {
+		v2.Metadata = result.Metadata.Metadata
+		for i := range v2.Items {
+			v2.Items[i].Metadata = result.Metadata.Items[v2.Items[i].ID]
+		}
+	}
+
+	if result.Refunds != nil {
+		refunded, net := toMoneyV2(result.Refunds.Refunded), toMoneyV2(result.Refunds.Net)
+		v2.Totals.Refunded = &refunded
+		v2.Totals.Net = &net
+	}
+	return v2
+}
+
+func toMoneyV2(m money.Money) MoneyV2 {
+	return MoneyV2{Amount: m.Amount, Currency: m.Currency}
+}
+
+func toAddressV2(a orders.Address) AddressV2 {
+	return AddressV2{
+		FirstName:   a.FirstName,
+		LastName:    a.LastName,
+		Company:     a.Company,
+		Address1:    a.AddressLine1,
+		Address2:    a.AddressLine2,
+		City:        a.City,
+		State:       a.State,
+		PostalCode:  a.PostalCode,
+		Country:     a.Country,
+		PhoneNumber: a.Phone,
+	}
+}
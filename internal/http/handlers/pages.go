@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PageHandler handles content page endpoints
+type PageHandler struct {
+	pageService *services.PageService
+}
+
+// NewPageHandler creates a new PageHandler
+func NewPageHandler(pageService *services.PageService) *PageHandler {
+	return &PageHandler{
+		pageService: pageService,
+	}
+}
+
+// PageRequest represents the request to create or update a content page
+type PageRequest struct {
+	Slug            string `json:"slug" binding:"required,max=255"`
+	Title           string `json:"title" binding:"required,max=255"`
+	Body            string `json:"body"`
+	Format          string `json:"format" binding:"omitempty,oneof=markdown html"`
+	MetaDescription string `json:"meta_description" binding:"max=500"`
+	Status          string `json:"status" binding:"omitempty,oneof=draft published"`
+}
+
+// toPage applies the request onto a page
+func (r *PageRequest) toPage(page *services.Page) {
+	page.Slug = r.Slug
+	page.Title = r.Title
+	page.Body = r.Body
+	page.Format = services.PageFormat(r.Format)
+	page.MetaDescription = r.MetaDescription
+	page.Status = services.PageStatus(r.Status)
+}
+
+// GetPublishedPage retrieves a published page by slug
+// GET /content/pages/:slug
+func (h *PageHandler) GetPublishedPage(c *gin.Context) {
+	page, err := h.pageService.GetPublishedPage(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if err == services.ErrPageNotFound {
+			response.NotFound(c, "Page not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, page)
+}
+
+// ListPages lists content pages, including drafts
+// GET /admin/pages?status=draft&sort=-updated_at&page=1&page_size=20
+func (h *PageHandler) ListPages(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.PageSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	filter := services.PageFilter{
+		Status: services.PageStatus(c.Query("status")),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	}
+
+	pages, err := h.pageService.ListPages(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.pageService.CountPages(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, pages, meta)
+}
+
+// GetPage retrieves a content page by ID
+// GET /admin/pages/:id
+func (h *PageHandler) GetPage(c *gin.Context) {
+	page, err := h.pageService.GetPage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrPageNotFound {
+			response.NotFound(c, "Page not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, page)
+}
+
+// CreatePage creates a content page
+// POST /admin/pages
+func (h *PageHandler) CreatePage(c *gin.Context) {
+	var req PageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	page := &services.Page{}
+	req.toPage(page)
+
+	if err := h.pageService.SavePage(c.Request.Context(), page); err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	response.Created(c, page)
+}
+
+// UpdatePage replaces a content page
+// PUT /admin/pages/:id
+func (h *PageHandler) UpdatePage(c *gin.Context) {
+	var req PageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	page, err := h.pageService.GetPage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+	if !response.IfMatch(c, page) {
+		return
+	}
+
+	req.toPage(page)
+	if err := h.pageService.SavePage(c.Request.Context(), page); err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored page
+	if page, err = h.pageService.GetPage(c.Request.Context(), page.ID); err != nil {
+		respondPageError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, page)
+}
+
+// DeletePage deletes a content page
+// DELETE /admin/pages/:id
+func (h *PageHandler) DeletePage(c *gin.Context) {
+	if err := h.pageService.DeletePage(c.Request.Context(), c.Param("id")); err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondPageError maps page errors to HTTP responses
+func respondPageError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrPageNotFound:
+		response.NotFound(c, "Page not found")
+	case services.ErrInvalidPage:
+		response.BadRequest(c, err.Error())
+	case services.ErrPageSlugTaken:
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PlacementHandler handles banner and promo tile placement endpoints
+type PlacementHandler struct {
+	placementService *services.PlacementService
+}
+
+// NewPlacementHandler creates a new PlacementHandler
+func NewPlacementHandler(placementService *services.PlacementService) *PlacementHandler {
+	return &PlacementHandler{
+		placementService: placementService,
+	}
+}
+
+// PlacementRequest represents the request to create or update a placement
+type PlacementRequest struct {
+	Slot      string     `json:"slot" binding:"required,max=100"`
+	Title     string     `json:"title" binding:"required,max=255"`
+	Subtitle  string     `json:"subtitle" binding:"max=500"`
+	ImageURL  string     `json:"image_url" binding:"required,max=1000"`
+	ImageAlt  string     `json:"image_alt" binding:"max=255"`
+	TargetURL string     `json:"target_url" binding:"required,max=1000"`
+	Position  int        `json:"position"`
+	IsActive  *bool      `json:"is_active"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+}
+
+// toPlacement applies the request onto a placement
+func (r *PlacementRequest) toPlacement(placement *services.Placement) {
+	placement.Slot = r.Slot
+	placement.Title = r.Title
+	placement.Subtitle = r.Subtitle
+	placement.ImageURL = r.ImageURL
+	placement.ImageAlt = r.ImageAlt
+	placement.TargetURL = r.TargetURL
+	placement.Position = r.Position
+	placement.IsActive = r.IsActive == nil || *r.IsActive
+	placement.StartsAt = r.StartsAt
+	placement.EndsAt = r.EndsAt
+}
+
+// GetSlotPlacements lists the placements currently showing in a slot
+// GET /content/placements/:slot
+func (h *PlacementHandler) GetSlotPlacements(c *gin.Context) {
+	placements, err := h.placementService.GetLivePlacements(c.Request.Context(), c.Param("slot"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, placements)
+}
+
+// ListPlacements lists placements, including scheduled and inactive ones
+// GET /admin/placements?slot=home_hero&sort=-starts_at&page=1&page_size=20
+func (h *PlacementHandler) ListPlacements(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.PlacementSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	filter := services.PlacementFilter{
+		Slot:   c.Query("slot"),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	}
+
+	placements, err := h.placementService.ListPlacements(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.placementService.CountPlacements(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, placements, meta)
+}
+
+// GetPlacement retrieves a placement by ID
+// GET /admin/placements/:id
+func (h *PlacementHandler) GetPlacement(c *gin.Context) {
+	placement, err := h.placementService.GetPlacement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, placement)
+}
+
+// CreatePlacement creates a placement
+// POST /admin/placements
+func (h *PlacementHandler) CreatePlacement(c *gin.Context) {
+	var req PlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	placement := &services.Placement{}
+	req.toPlacement(placement)
+
+	if err := h.placementService.SavePlacement(c.Request.Context(), placement); err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+
+	response.Created(c, placement)
+}
+
+// UpdatePlacement replaces a placement
+// PUT /admin/placements/:id
+func (h *PlacementHandler) UpdatePlacement(c *gin.Context) {
+	var req PlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	placement, err := h.placementService.GetPlacement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+	if !response.IfMatch(c, placement) {
+		return
+	}
+
+	req.toPlacement(placement)
+	if err := h.placementService.SavePlacement(c.Request.Context(), placement); err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored placement
+	if placement, err = h.placementService.GetPlacement(c.Request.Context(), placement.ID); err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, placement)
+}
+
+// DeletePlacement deletes a placement
+// DELETE /admin/placements/:id
+func (h *PlacementHandler) DeletePlacement(c *gin.Context) {
+	if err := h.placementService.DeletePlacement(c.Request.Context(), c.Param("id")); err != nil {
+		respondPlacementError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondPlacementError maps placement errors to HTTP responses
+func respondPlacementError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrPlacementNotFound:
+		response.NotFound(c, "Placement not found")
+	case services.ErrInvalidPlacement, services.ErrInvalidSchedule:
+		response.BadRequest(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// POSHandler handles the in-store POS endpoints, authenticated by API key
+type POSHandler struct {
+	posService *services.POSService
+}
+
+// NewPOSHandler creates a new POSHandler
+func NewPOSHandler(posService *services.POSService) *POSHandler {
+	return &POSHandler{
+		posService: posService,
+	}
+}
+
+// POSSaleRequest represents an in-store sale rung up at a register
+type POSSaleRequest struct {
+	RegisterID     string               `json:"register_id" binding:"required,max=100"`
+	TerminalID     string               `json:"terminal_id" binding:"max=100"`
+	CustomerID     string               `json:"customer_id"`
+	StoreAddress   AddressRequest       `json:"store_address" binding:"required"`
+	Items          []POSSaleItemRequest `json:"items" binding:"required,min=1,dive"`
+	Payments       []POSPaymentRequest  `json:"payments" binding:"required,min=1,max=5,dive"`
+	PromotionCodes []string             `json:"promotion_codes"`
+	Notes          string               `json:"notes"`
+}
+
+// POSSaleItemRequest represents one line of an in-store sale
+type POSSaleItemRequest struct {
+	SKU      string `json:"sku" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// POSPaymentRequest represents a payment the register already captured
+type POSPaymentRequest struct {
+	Type      string `json:"type" binding:"required,oneof=card cash"`
+	Reference string `json:"reference"`                      // the terminal's transaction reference; required for cards
+	Amount    int64  `json:"amount" binding:"required,gt=0"` // in cents, in the order currency
+}
+
+// POSSaleResponse is a recorded in-store sale, with the order in the v2 shape
+type POSSaleResponse struct {
+	Sale    *services.POSSale         `json:"sale"`
+	Order   *OrderV2                  `json:"order"`
+	Tenders []*services.PaymentTender `json:"tenders"`
+}
+
+// CreateSale records an in-store sale and the payments captured for it
+// POST /pos/sales
+func (h *POSHandler) CreateSale(c *gin.Context) {
+	keyID, _ := middleware.GetAPIKeyID(c)
+
+	var req POSSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	sale := services.POSSaleRequest{
+		RegisterID:     req.RegisterID,
+		TerminalID:     req.TerminalID,
+		CustomerID:     req.CustomerID,
+		StoreAddress:   req.StoreAddress.ToOrderAddress(),
+		Items:          make([]services.POSSaleItem, len(req.Items)),
+		Payments:       make([]services.POSPayment, len(req.Payments)),
+		PromotionCodes: req.PromotionCodes,
+		Notes:          req.Notes,
+		APIKeyID:       keyID,
+	}
+	for i, item := range req.Items {
+		sale.Items[i] = services.POSSaleItem{SKU: item.SKU, Quantity: item.Quantity}
+	}
+	for i, payment := range req.Payments {
+		sale.Payments[i] = services.POSPayment{
+			Type:      services.TenderType(payment.Type),
+			Reference: payment.Reference,
+			Amount:    payment.Amount,
+		}
+	}
+
+	result, err := h.posService.CreateSale(c.Request.Context(), sale)
+	if err != nil {
+		var mismatch *services.POSTotalMismatchError
+		var limitErr *services.PurchaseLimitError
+		var ruleErr *services.CheckoutRuleError
+		var totalsErr *services.OrderTotalsError
+		switch {
+		case errors.As(err, &mismatch):
+			response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "payment_total_mismatch", err.Error(), gin.H{
+				"order_total": mismatch.OrderTotal,
+			})
+		case errors.As(err, &limitErr):
+			respondPurchaseLimitError(c, limitErr)
+		case errors.As(err, &ruleErr):
+			respondCheckoutRuleError(c, ruleErr)
+		case errors.As(err, &totalsErr):
+			respondOrderTotalsError(c, totalsErr)
+		case errors.Is(err, services.ErrPOSItemNotFound), err == services.ErrInvalidPOSSale, err == orders.ErrInvalidAddress:
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Created(c, POSSaleResponse{
+		Sale:    result.Sale,
+		Order:   toOrderV2(&OrderResponse{Order: result.Order}),
+		Tenders: result.Tenders,
+	})
+}
+
+// GetRegisterSummary returns a register's end-of-day sales summary
+// GET /pos/registers/:id/summary?date=2026-01-15
+func (h *POSHandler) GetRegisterSummary(c *gin.Context) {
+	day := time.Now()
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", dateParam, time.Local)
+		if err != nil {
+			response.BadRequest(c, "date must be in YYYY-MM-DD format")
+			return
+		}
+		day = parsed
+	}
+
+	summary, err := h.posService.RegisterSummary(c.Request.Context(), c.Param("id"), day)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, summary)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// PricingAnomalyHandler handles the review of suspicious price changes and orders
+type PricingAnomalyHandler struct {
+	anomalyService *services.PricingAnomalyService
+}
+
+// NewPricingAnomalyHandler creates a new PricingAnomalyHandler
+func NewPricingAnomalyHandler(anomalyService *services.PricingAnomalyService) *PricingAnomalyHandler {
+	return &PricingAnomalyHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+// ListPricingAnomalies lists pricing anomalies, newest first
+// GET /admin/pricing-anomalies?status=pending&page=1&page_size=20
+func (h *PricingAnomalyHandler) ListPricingAnomalies(c *gin.Context) {
+	status := services.PricingAnomalyStatus(c.Query("status"))
+	switch status {
+	case "", services.AnomalyPending, services.AnomalyApproved, services.AnomalyRejected:
+	default:
+		response.BadRequest(c, "status must be pending, approved or rejected")
+		return
+	}
+	params := response.GetPaginationParams(c)
+
+	anomalies, err := h.anomalyService.ListAnomalies(c.Request.Context(), status, params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, anomalies)
+}
+
+// GetPricingAnomaly retrieves a pricing anomaly
+// GET /admin/pricing-anomalies/:id
+func (h *PricingAnomalyHandler) GetPricingAnomaly(c *gin.Context) {
+	anomaly, err := h.anomalyService.GetAnomaly(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondPricingAnomalyError(c, err)
+		return
+	}
+
+	response.Success(c, anomaly)
+}
+
+// ApprovePricingAnomaly applies a held price change or releases a held order
+// POST /admin/pricing-anomalies/:id/approve
+func (h *PricingAnomalyHandler) ApprovePricingAnomaly(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	anomaly, err := h.anomalyService.Approve(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondPricingAnomalyError(c, err)
+		return
+	}
+
+	response.Success(c, anomaly)
+}
+
+// RejectPricingAnomaly discards a held price change or cancels a held order
+// POST /admin/pricing-anomalies/:id/reject
+func (h *PricingAnomalyHandler) RejectPricingAnomaly(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	anomaly, err := h.anomalyService.Reject(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondPricingAnomalyError(c, err)
+		return
+	}
+
+	response.Success(c, anomaly)
+}
+
+// respondPricingAnomalyError maps pricing anomaly errors to HTTP responses
+func respondPricingAnomalyError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrPricingAnomalyNotFound:
+		response.NotFound(c, "Pricing anomaly not found")
+	case orders.ErrOrderNotFound:
+		response.NotFound(c, "Order not found")
+	case services.ErrPricingAnomalyReviewed:
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ProductMediaHandler handles product gallery endpoints
+type ProductMediaHandler struct {
+	productMediaService *services.ProductMediaService
+}
+
+// NewProductMediaHandler creates a new ProductMediaHandler
+func NewProductMediaHandler(productMediaService *services.ProductMediaService) *ProductMediaHandler {
+	return &ProductMediaHandler{
+		productMediaService: productMediaService,
+	}
+}
+
+// ProductMediaRequest represents the request to replace a product's media, in gallery order
+type ProductMediaRequest struct {
+	Media []*services.ProductMedia `json:"media" binding:"required"`
+}
+
+// GetProductMedia retrieves a product's media in gallery order
+// GET /admin/catalog/products/:id/media
+func (h *ProductMediaHandler) GetProductMedia(c *gin.Context) {
+	media, err := h.productMediaService.GetMedia(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"product_id": c.Param("id"), "media": media})
+}
+
+// SetProductMedia replaces a product's media; positions follow the order given
+// PUT /admin/catalog/products/:id/media
+func (h *ProductMediaHandler) SetProductMedia(c *gin.Context) {
+	var req ProductMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	media, err := h.productMediaService.SetMedia(c.Request.Context(), c.Param("id"), req.Media)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidProductMedia) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"product_id": c.Param("id"), "media": media})
+}
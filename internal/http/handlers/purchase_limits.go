@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PurchaseLimitHandler handles product purchase limit endpoints
+type PurchaseLimitHandler struct {
+	limitService *services.PurchaseLimitService
+}
+
+// NewPurchaseLimitHandler creates a new PurchaseLimitHandler
+func NewPurchaseLimitHandler(limitService *services.PurchaseLimitService) *PurchaseLimitHandler {
+	return &PurchaseLimitHandler{
+		limitService: limitService,
+	}
+}
+
+// PurchaseLimitRequest represents the request to set a product's purchase limit
+type PurchaseLimitRequest struct {
+	MaxPerOrder    int `json:"max_per_order" binding:"min=0"`
+	MaxPerCustomer int `json:"max_per_customer" binding:"min=0"`
+	PeriodDays     int `json:"period_days" binding:"min=0"` // window for max_per_customer; 0 counts every past order
+}
+
+// ListPurchaseLimits lists every product purchase limit
+// GET /admin/purchase-limits
+func (h *PurchaseLimitHandler) ListPurchaseLimits(c *gin.Context) {
+	limits, err := h.limitService.ListLimits(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, limits)
+}
+
+// GetPurchaseLimit retrieves a product's purchase limit
+// GET /admin/catalog/products/:id/purchase-limit
+func (h *PurchaseLimitHandler) GetPurchaseLimit(c *gin.Context) {
+	limit, err := h.limitService.GetLimit(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrPurchaseLimitNotFound {
+			response.NotFound(c, "Purchase limit not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, limit)
+}
+
+// SetPurchaseLimit creates or replaces a product's purchase limit
+// PUT /admin/catalog/products/:id/purchase-limit
+func (h *PurchaseLimitHandler) SetPurchaseLimit(c *gin.Context) {
+	var req PurchaseLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	limit := &services.PurchaseLimit{
+		ProductID:      c.Param("id"),
+		MaxPerOrder:    req.MaxPerOrder,
+		MaxPerCustomer: req.MaxPerCustomer,
+		PeriodDays:     req.PeriodDays,
+	}
+	if err := h.limitService.SetLimit(c.Request.Context(), limit); err != nil {
+		if err == services.ErrInvalidPurchaseLimit {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, limit)
+}
+
+// DeletePurchaseLimit removes a product's purchase limit
+// DELETE /admin/catalog/products/:id/purchase-limit
+func (h *PurchaseLimitHandler) DeletePurchaseLimit(c *gin.Context) {
+	if err := h.limitService.RemoveLimit(c.Request.Context(), c.Param("id")); err != nil {
+		if err == services.ErrPurchaseLimitNotFound {
+			response.NotFound(c, "Purchase limit not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondPurchaseLimitError maps a purchase limit violation to a structured 422 response
+func respondPurchaseLimitError(c *gin.Context, limitErr *services.PurchaseLimitError) {
+	scope := "order"
+	if limitErr.PerCustomer {
+		scope = "customer"
+	}
+
+	response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "purchase_limit_exceeded", limitErr.Error(), gin.H{
+		"product_id":  limitErr.ProductID,
+		"limit":       limitErr.Limit,
+		"remaining":   limitErr.Remaining,
+		"scope":       scope,
+		"period_days": limitErr.PeriodDays,
+	})
+}
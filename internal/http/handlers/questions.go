@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"github.com/devchuckcamp/goauthx"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// QuestionHandler handles product questions and answers
+type QuestionHandler struct {
+	questionService *services.QuestionService
+}
+
+// NewQuestionHandler creates a new QuestionHandler
+func NewQuestionHandler(questionService *services.QuestionService) *QuestionHandler {
+	return &QuestionHandler{
+		questionService: questionService,
+	}
+}
+
+// AskQuestionRequest represents the request to ask a question about a product
+type AskQuestionRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Body      string `json:"body" binding:"required,max=2000"`
+}
+
+// AnswerQuestionRequest represents the request to answer a product question
+type AnswerQuestionRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// ListProductQuestions lists a product's questions with their answers, newest first
+// GET /catalog/products/:id/questions
+func (h *QuestionHandler) ListProductQuestions(c *gin.Context) {
+	questions, err := h.questionService.ProductQuestions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, questions)
+}
+
+// AskQuestion asks a question about a product as the current user
+// POST /questions
+func (h *QuestionHandler) AskQuestion(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req AskQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	question, err := h.questionService.AskQuestion(c.Request.Context(), userID, req.ProductID, req.Body)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, question)
+}
+
+// GetQuestion retrieves a question with its answers
+// GET /questions/:id
+func (h *QuestionHandler) GetQuestion(c *gin.Context) {
+	question, err := h.questionService.GetQuestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, question)
+}
+
+// AnswerQuestion answers a question as the current user. Answers from admins, managers and
+// customer experience staff are marked as the store's.
+// POST /questions/:id/answers
+func (h *QuestionHandler) AnswerQuestion(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req AnswerQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	staff := hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience))
+	question, err := h.questionService.Answer(c.Request.Context(), c.Param("id"), userID, staff, req.Body)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, question)
+}
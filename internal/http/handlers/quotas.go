@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// QuotaHandler handles admin endpoints for request quotas and their usage
+type QuotaHandler struct {
+	quotaService *services.QuotaService
+}
+
+// NewQuotaHandler creates a new QuotaHandler
+func NewQuotaHandler(quotaService *services.QuotaService) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+	}
+}
+
+// QuotaTierRequest represents the request to put an API key on a quota tier
+type QuotaTierRequest struct {
+	Tier string `json:"tier"` // "" or api_key for the default API key tier
+}
+
+// ListQuotaTiers lists the daily and monthly limits of each tier
+// GET /admin/quotas
+func (h *QuotaHandler) ListQuotaTiers(c *gin.Context) {
+	response.Success(c, h.quotaService.Tiers())
+}
+
+// ListQuotaUsage lists the request counts of the current day or month, most requests first
+// GET /admin/quotas/usage
+func (h *QuotaHandler) ListQuotaUsage(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	period := services.QuotaPeriod(c.DefaultQuery("period", string(services.QuotaPeriodDay)))
+
+	counters, total, err := h.quotaService.ListUsage(c.Request.Context(), period, params.CalculateLimit(), params.CalculateOffset())
+	if err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, counters, response.NewPaginationMeta(params.Page, params.PageSize, total))
+}
+
+// GetAPIKeyQuota returns an API key's usage in the current day and month
+// GET /admin/quotas/api-keys/:id
+func (h *QuotaHandler) GetAPIKeyQuota(c *gin.Context) {
+	status, err := h.quotaService.APIKeyUsage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// SetAPIKeyQuotaTier puts an API key on a quota tier
+// PUT /admin/quotas/api-keys/:id/tier
+func (h *QuotaHandler) SetAPIKeyQuotaTier(c *gin.Context) {
+	var req QuotaTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	key, err := h.quotaService.SetAPIKeyTier(c.Request.Context(), c.Param("id"), req.Tier)
+	if err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.Success(c, key)
+}
+
+// ResetAPIKeyQuota clears an API key's usage
+// DELETE /admin/quotas/api-keys/:id
+func (h *QuotaHandler) ResetAPIKeyQuota(c *gin.Context) {
+	if err := h.quotaService.Reset(c.Request.Context(), services.APIKeyQuotaSubject(c.Param("id"))); err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// GetUserQuota returns a user's usage in the current day and month
+// GET /admin/quotas/users/:id
+func (h *QuotaHandler) GetUserQuota(c *gin.Context) {
+	status, err := h.quotaService.UserUsage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// ResetUserQuota clears a user's usage
+// DELETE /admin/quotas/users/:id
+func (h *QuotaHandler) ResetUserQuota(c *gin.Context) {
+	if err := h.quotaService.Reset(c.Request.Context(), services.UserQuotaSubject(c.Param("id"))); err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondQuotaError maps quota errors to HTTP responses
+func respondQuotaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidQuotaPeriod), errors.Is(err, services.ErrUnknownQuotaTier):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		response.NotFound(c, "API key not found")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// QuoteHandler handles quote requests from buyers and their pricing by sales admins.
+// Buyers accept a quote by checking out with its quote_id on POST /orders.
+type QuoteHandler struct {
+	quoteService *services.QuoteService
+	cartService  *services.CartService
+}
+
+// NewQuoteHandler creates a new QuoteHandler
+func NewQuoteHandler(quoteService *services.QuoteService, cartService *services.CartService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService: quoteService,
+		cartService:  cartService,
+	}
+}
+
+// RequestQuoteRequest represents the request to submit the cart for a quote
+type RequestQuoteRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+// SendQuoteRequest represents the request to price a quote and send it to the buyer
+type SendQuoteRequest struct {
+	Prices     map[string]int64 `json:"prices"` // unit prices in cents keyed by quote item ID; unlisted items keep their price
+	Note       string           `json:"note" binding:"max=2000"`
+	ValidUntil *time.Time       `json:"valid_until"` // defaults to QUOTE_VALIDITY from now
+}
+
+// RequestQuote submits the user's cart for negotiated pricing; the cart is left as is
+// POST /quotes
+func (h *QuoteHandler) RequestQuote(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req RequestQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	cart, err := h.cartService.GetOrCreateCart(c.Request.Context(), userID, "")
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	quote, err := h.quoteService.RequestQuote(c.Request.Context(), userID, cart, req.Note)
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	response.Created(c, quote)
+}
+
+// ListQuotes lists the user's quotes, newest first unless sorted
+// GET /quotes?status=sent&sort=valid_until&page=1&page_size=20
+func (h *QuoteHandler) ListQuotes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.QuoteSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	quotes, err := h.quoteService.ListQuotes(c.Request.Context(), services.QuoteFilter{
+		UserID: userID,
+		Status: services.QuoteStatus(c.Query("status")),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, quotes)
+}
+
+// GetQuote retrieves one of the user's quotes
+// GET /quotes/:id
+func (h *QuoteHandler) GetQuote(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	quote, err := h.quoteService.GetUserQuote(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	response.Success(c, quote)
+}
+
+// DeclineQuote closes one of the user's open quotes without placing an order
+// POST /quotes/:id/decline
+func (h *QuoteHandler) DeclineQuote(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	quote, err := h.quoteService.DeclineQuote(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	response.Success(c, quote)
+}
+
+// AdminListQuotes lists all quotes, newest first unless sorted
+// GET /admin/quotes?status=requested&sort=-subtotal&page=1&page_size=20
+func (h *QuoteHandler) AdminListQuotes(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+	sort, err := services.ParseSort(c.Query("sort"), services.QuoteSortFields)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	quotes, err := h.quoteService.ListQuotes(c.Request.Context(), services.QuoteFilter{
+		Status: services.QuoteStatus(c.Query("status")),
+		Sort:   sort,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, quotes)
+}
+
+// AdminGetQuote retrieves any quote
+// GET /admin/quotes/:id
+func (h *QuoteHandler) AdminGetQuote(c *gin.Context) {
+	quote, err := h.quoteService.GetQuote(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	response.Success(c, quote)
+}
+
+// SendQuote prices a quote and sends it to the buyer; a sent quote can be revised until
+// the buyer answers
+// POST /admin/quotes/:id/send
+func (h *QuoteHandler) SendQuote(c *gin.Context) {
+	adminID, _ := middleware.GetUserID(c)
+
+	var req SendQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	quote, err := h.quoteService.SendQuote(c.Request.Context(), adminID, c.Param("id"), services.QuoteOffer{
+		Prices:     req.Prices,
+		Note:       req.Note,
+		ValidUntil: req.ValidUntil,
+	})
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	response.Success(c, quote)
+}
+
+// respondQuoteError maps quote errors to HTTP responses
+func respondQuoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrQuoteNotFound):
+		response.NotFound(c, "Quote not found")
+	case errors.Is(err, services.ErrEmptyQuoteRequest), errors.Is(err, services.ErrInvalidQuoteNote),
+		errors.Is(err, services.ErrInvalidQuoteOffer):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrQuoteNotOpen), errors.Is(err, services.ErrQuoteNotSent),
+		errors.Is(err, services.ErrQuoteExpired):
+		response.Conflict(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RecentlyViewedHandler handles the products a shopper recently viewed
+type RecentlyViewedHandler struct {
+	recentlyViewed *services.RecentlyViewedService
+}
+
+// NewRecentlyViewedHandler creates a new RecentlyViewedHandler
+func NewRecentlyViewedHandler(recentlyViewed *services.RecentlyViewedService) *RecentlyViewedHandler {
+	return &RecentlyViewedHandler{
+		recentlyViewed: recentlyViewed,
+	}
+}
+
+// RecordViewRequest represents the request to record a product view
+type RecordViewRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+}
+
+// ListRecentlyViewed returns the products the current user or guest viewed, most recent first
+// GET /recently-viewed
+func (h *RecentlyViewedHandler) ListRecentlyViewed(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	viewed, err := h.recentlyViewed.List(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, viewed)
+}
+
+// RecordView records that the current user or guest viewed a product
+// POST /recently-viewed
+func (h *RecentlyViewedHandler) RecordView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req RecordViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	if err := h.recentlyViewed.RecordView(c.Request.Context(), userID, req.ProductID); err != nil {
+		if err == services.ErrViewedProductNotFound {
+			response.NotFound(c, "Product not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/payments"
+)
+
+// RefundHandler handles admin order refunds
+type RefundHandler struct {
+	refundService *services.RefundService
+	orderService  *services.OrderService
+}
+
+// NewRefundHandler creates a new RefundHandler
+func NewRefundHandler(refundService *services.RefundService, orderService *services.OrderService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+		orderService:  orderService,
+	}
+}
+
+// CreateRefundRequest represents the request to refund an order.
+// Send amount for a partial refund, lines for a per-line refund, or neither for a full refund.
+type CreateRefundRequest struct {
+	Amount *int64              `json:"amount" binding:"omitempty,gt=0"` // in cents, in the order currency
+	Lines  []RefundLineRequest `json:"lines" binding:"omitempty,dive"`
+	Reason string              `json:"reason" binding:"omitempty,oneof=duplicate fraudulent requested_by_customer defective_product other"`
+	Note   string              `json:"note"`
+}
+
+// RefundLineRequest selects a quantity of an order item to refund
+type RefundLineRequest struct {
+	ItemID   string `json:"order_item_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// CreateRefund refunds an order in full, by amount or by line
+// POST /admin/orders/:id/refunds
+func (h *RefundHandler) CreateRefund(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == orders.ErrOrderNotFound {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	refundReq := services.CreateRefundRequest{
+		Reason:    payments.RefundReason(req.Reason),
+		Note:      req.Note,
+		CreatedBy: userID,
+	}
+	if req.Amount != nil {
+		refundReq.Amount = &money.Money{Amount: *req.Amount, Currency: order.Total.Currency}
+	}
+	for _, line := range req.Lines {
+		refundReq.Lines = append(refundReq.Lines, services.RefundLineRequest{OrderItemID: line.ItemID, Quantity: line.Quantity})
+	}
+
+	refund, err := h.refundService.CreateRefund(c.Request.Context(), order.ID, refundReq)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidRefund, services.ErrRefundItemNotFound, services.ErrRefundQuantityExceeded, services.ErrRefundExceedsPaid:
+			response.BadRequest(c, err.Error())
+		case services.ErrOrderNotRefundable:
+			response.Conflict(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Created(c, refund)
+}
+
+// ListRefunds lists the refunds issued for an order
+// GET /admin/orders/:id/refunds
+func (h *RefundHandler) ListRefunds(c *gin.Context) {
+	refunds, err := h.refundService.ListRefunds(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, refunds)
+}
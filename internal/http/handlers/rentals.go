@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RentalHandler handles rental product settings and availability calendar endpoints
+type RentalHandler struct {
+	rentalService *services.RentalService
+}
+
+// NewRentalHandler creates a new RentalHandler
+func NewRentalHandler(rentalService *services.RentalService) *RentalHandler {
+	return &RentalHandler{
+		rentalService: rentalService,
+	}
+}
+
+// RentalRequest represents the request to put a product in rental mode
+type RentalRequest struct {
+	Units      int            `json:"units" binding:"required,min=1"`
+	SKUUnits   map[string]int `json:"sku_units"` // units by SKU, for SKUs stocked differently
+	MinDays    int            `json:"min_days" binding:"min=0"`
+	MaxDays    int            `json:"max_days" binding:"min=0"` // 0 for no maximum
+	BufferDays int            `json:"buffer_days" binding:"min=0,max=30"`
+}
+
+// GetRentalCalendar lists the units of a SKU free to rent on each day of a date range
+// GET /catalog/products/:id/rental-calendar?sku=&from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *RentalHandler) GetRentalCalendar(c *gin.Context) {
+	calendar, err := h.rentalService.Calendar(c.Request.Context(), c.Param("id"), c.Query("sku"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondRentalError(c, err)
+		return
+	}
+
+	response.Success(c, calendar)
+}
+
+// GetRental retrieves a product's rental settings
+// GET /admin/catalog/products/:id/rental
+func (h *RentalHandler) GetRental(c *gin.Context) {
+	rental, err := h.rentalService.GetRental(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondRentalError(c, err)
+		return
+	}
+
+	response.Success(c, rental)
+}
+
+// SetRental puts a product in rental mode or replaces its rental settings
+// PUT /admin/catalog/products/:id/rental
+func (h *RentalHandler) SetRental(c *gin.Context) {
+	var req RentalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	rental := &services.RentalProduct{
+		ProductID:  c.Param("id"),
+		Units:      req.Units,
+		SKUUnits:   req.SKUUnits,
+		MinDays:    req.MinDays,
+		MaxDays:    req.MaxDays,
+		BufferDays: req.BufferDays,
+	}
+	if err := h.rentalService.SetRental(c.Request.Context(), rental); err != nil {
+		respondRentalError(c, err)
+		return
+	}
+
+	response.Success(c, rental)
+}
+
+// DeleteRental takes a product out of rental mode
+// DELETE /admin/catalog/products/:id/rental
+func (h *RentalHandler) DeleteRental(c *gin.Context) {
+	if err := h.rentalService.RemoveRental(c.Request.Context(), c.Param("id")); err != nil {
+		respondRentalError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// isRentalError reports whether err is about a rental product or its rental period, for
+// endpoints that also return other errors
+func isRentalError(err error) bool {
+	var conflictErr *services.RentalConflictError
+	return errors.As(err, &conflictErr) ||
+		errors.Is(err, services.ErrRentalPeriodRequired) ||
+		errors.Is(err, services.ErrInvalidRentalPeriod) ||
+		errors.Is(err, services.ErrRentalInCart)
+}
+
+// respondRentalError maps rental errors to HTTP responses. Dates no longer free get a
+// structured 409 with the units available.
+func respondRentalError(c *gin.Context, err error) {
+	var conflictErr *services.RentalConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		response.ErrorWithDetails(c, http.StatusConflict, "rental_unavailable", conflictErr.Error(), gin.H{
+			"conflict": conflictErr,
+		})
+	case errors.Is(err, services.ErrRentalPeriodRequired),
+		errors.Is(err, services.ErrInvalidRentalPeriod),
+		errors.Is(err, services.ErrInvalidCalendarRange),
+		errors.Is(err, services.ErrInvalidRental):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrRentalInCart):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrRentalNotFound):
+		response.NotFound(c, "Product is not offered for rent")
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	case errors.Is(err, services.ErrRentalSKUNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RetentionHandler previews the data retention rules for admins
+type RetentionHandler struct {
+	retentionService *services.RetentionService
+}
+
+// NewRetentionHandler creates a new RetentionHandler
+func NewRetentionHandler(retentionService *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// GetRetentionReport reports what the retention rules would change if they ran now,
+// without changing anything
+// GET /admin/retention
+func (h *RetentionHandler) GetRetentionReport(c *gin.Context) {
+	report, err := h.retentionService.Run(c.Request.Context(), true)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
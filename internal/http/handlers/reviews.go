@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ReviewHandler handles product reviews and their moderation
+type ReviewHandler struct {
+	reviewService *services.ReviewService
+}
+
+// NewReviewHandler creates a new ReviewHandler
+func NewReviewHandler(reviewService *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// WriteReviewRequest represents the request to review a product
+type WriteReviewRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Rating    int    `json:"rating" binding:"required,min=1,max=5"`
+	Title     string `json:"title" binding:"max=255"`
+	Body      string `json:"body" binding:"required,max=5000"`
+}
+
+// ModerateReviewRequest represents the request to approve or reject a review
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending approved rejected"`
+	Note   string `json:"note" binding:"max=1000"` // shown to the author, e.g. why it was rejected
+}
+
+// BulkModerateReviewsRequest represents the request to moderate several reviews at once
+type BulkModerateReviewsRequest struct {
+	ReviewIDs []string `json:"review_ids" binding:"required,min=1,max=100"`
+	Status    string   `json:"status" binding:"required,oneof=pending approved rejected"`
+	Note      string   `json:"note" binding:"max=1000"`
+}
+
+// ListProductReviews lists a product's approved reviews with their average rating
+// GET /catalog/products/:id/reviews
+func (h *ReviewHandler) ListProductReviews(c *gin.Context) {
+	reviews, err := h.reviewService.ProductReviews(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, reviews)
+}
+
+// WriteReview reviews a product as the current user; the review is shown once approved
+// POST /reviews
+func (h *ReviewHandler) WriteReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WriteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	review, err := h.reviewService.WriteReview(c.Request.Context(), userID, services.WriteReviewRequest{
+		ProductID: req.ProductID,
+		Rating:    req.Rating,
+		Title:     req.Title,
+		Body:      req.Body,
+	})
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, review)
+}
+
+// ListMyReviews lists the current user's reviews in any status
+// GET /reviews
+func (h *ReviewHandler) ListMyReviews(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	reviews, err := h.reviewService.UserReviews(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, reviews)
+}
+
+// GetReview retrieves a review. Authors see their reviews in any status; others only
+// approved ones.
+// GET /reviews/:id
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	review, err := h.reviewService.GetReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if review.UserID != userID && review.Status != services.ReviewStatusApproved {
+		response.NotFound(c, "Review not found")
+		return
+	}
+
+	response.Success(c, review)
+}
+
+// AddReviewPhoto attaches the image in the photo form field to one of the current user's
+// reviews
+// POST /reviews/:id/photos
+func (h *ReviewHandler) AddReviewPhoto(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.reviewService.MaxPhotoSize()+multipartOverhead)
+	header, err := c.FormFile("photo")
+	if err != nil {
+		response.BadRequest(c, "photo must be uploaded as a multipart form file within the size limit")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	defer file.Close()
+
+	review, err := h.reviewService.AddPhoto(c.Request.Context(), userID, c.Param("id"), file)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, review)
+}
+
+// RemoveReviewPhoto removes a photo from one of the current user's reviews
+// DELETE /reviews/:id/photos/:photoId
+func (h *ReviewHandler) RemoveReviewPhoto(c *gin.Context) {
+	h.removePhoto(c, false)
+}
+
+// ModeratorRemoveReviewPhoto removes a photo from any review
+// DELETE /admin/reviews/:id/photos/:photoId
+func (h *ReviewHandler) ModeratorRemoveReviewPhoto(c *gin.Context) {
+	h.removePhoto(c, true)
+}
+
+// ListReviews lists the moderation queue, optionally by status
+// GET /admin/reviews?status=pending
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	reviews, err := h.reviewService.ListReviews(c.Request.Context(), services.ReviewStatus(c.Query("status")))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, reviews)
+}
+
+// GetReviewForModeration retrieves a review in any status
+// GET /admin/reviews/:id
+func (h *ReviewHandler) GetReviewForModeration(c *gin.Context) {
+	review, err := h.reviewService.GetReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, review)
+}
+
+// ModerateReview approves or rejects a review, or sends it back to pending
+// PATCH /admin/reviews/:id/status
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	review, err := h.reviewService.Moderate(c.Request.Context(), c.Param("id"), userID, services.ReviewStatus(req.Status), req.Note)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, review)
+}
+
+// BulkModerateReviews moves several reviews to the same status
+// POST /admin/reviews/bulk-status
+func (h *ReviewHandler) BulkModerateReviews(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req BulkModerateReviewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	result, err := h.reviewService.ModerateBulk(c.Request.Context(), req.ReviewIDs, userID, services.ReviewStatus(req.Status), req.Note)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+func (h *ReviewHandler) removePhoto(c *gin.Context, moderator bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	review, err := h.reviewService.RemovePhoto(c.Request.Context(), userID, moderator, c.Param("id"), c.Param("photoId"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, review)
+}
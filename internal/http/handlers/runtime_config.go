@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RuntimeConfigHandler handles the admin view and reload of runtime settings
+type RuntimeConfigHandler struct {
+	runtimeConfigService *services.RuntimeConfigService
+}
+
+// NewRuntimeConfigHandler creates a new RuntimeConfigHandler
+func NewRuntimeConfigHandler(runtimeConfigService *services.RuntimeConfigService) *RuntimeConfigHandler {
+	return &RuntimeConfigHandler{
+		runtimeConfigService: runtimeConfigService,
+	}
+}
+
+// GetRuntimeConfig returns the runtime settings in effect
+// GET /admin/config
+func (h *RuntimeConfigHandler) GetRuntimeConfig(c *gin.Context) {
+	response.Success(c, gin.H{
+		"settings":    h.runtimeConfigService.Current().Values(),
+		"reloaded_at": h.runtimeConfigService.ReloadedAt(),
+	})
+}
+
+// ReloadConfig reads the configuration again and applies the runtime settings that changed
+// POST /admin/config/reload
+func (h *RuntimeConfigHandler) ReloadConfig(c *gin.Context) {
+	var actorID *string
+	if adminID, ok := middleware.GetUserID(c); ok {
+		actorID = &adminID
+	}
+
+	changes, err := h.runtimeConfigService.Reload(c.Request.Context(), services.ConfigChangeSourceAdmin, actorID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRuntimeConfig) {
+			response.ErrorWithCode(c, http.StatusUnprocessableEntity, "invalid_config", err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"changes":  changes,
+		"settings": h.runtimeConfigService.Current().Values(),
+	})
+}
+
+// ListConfigChanges lists the audited runtime setting changes, newest first
+// GET /admin/config/changes?setting=TAX_RATE&page=1&page_size=20
+func (h *RuntimeConfigHandler) ListConfigChanges(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	filter := services.ConfigChangeFilter{
+		Setting: c.Query("setting"),
+		Limit:   params.CalculateLimit(),
+		Offset:  params.CalculateOffset(),
+	}
+
+	changes, err := h.runtimeConfigService.ListChanges(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.runtimeConfigService.CountChanges(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, changes, meta)
+}
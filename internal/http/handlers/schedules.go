@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+)
+
+// ScheduleHandler exposes the recurring task scheduler to admins
+type ScheduleHandler struct {
+	scheduler *jobs.Scheduler
+}
+
+// NewScheduleHandler creates a new ScheduleHandler
+func NewScheduleHandler(scheduler *jobs.Scheduler) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduler: scheduler,
+	}
+}
+
+// ListSchedules lists scheduled tasks with their next and last runs
+// GET /admin/schedules
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	response.Success(c, h.scheduler.Tasks())
+}
+
+// RunSchedule queues a scheduled task to run now
+// POST /admin/schedules/:name/run
+func (h *ScheduleHandler) RunSchedule(c *gin.Context) {
+	status, err := h.scheduler.Trigger(c.Param("name"))
+	if err != nil {
+		switch err {
+		case jobs.ErrTaskNotFound:
+			response.NotFound(c, "Scheduled task not found")
+		case jobs.ErrTaskRunning:
+			response.Conflict(c, "Task is already running")
+		case jobs.ErrQueueFull:
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, "queue_full", "Job queue is full, try again shortly")
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Accepted(c, status)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SearchRuleHandler handles search synonym and merchandising rule endpoints
+type SearchRuleHandler struct {
+	searchRuleService *services.SearchRuleService
+}
+
+// NewSearchRuleHandler creates a new SearchRuleHandler
+func NewSearchRuleHandler(searchRuleService *services.SearchRuleService) *SearchRuleHandler {
+	return &SearchRuleHandler{
+		searchRuleService: searchRuleService,
+	}
+}
+
+// SynonymSetRequest represents the request to create or update a synonym set
+type SynonymSetRequest struct {
+	Terms []string `json:"terms" binding:"required,min=2,max=20,dive,max=100"`
+}
+
+// SearchRuleRequest represents the request to create or update a search rule
+type SearchRuleRequest struct {
+	Query             string   `json:"query" binding:"required,max=255"`
+	PinnedProductIDs  []string `json:"pinned_product_ids"`  // listed first, in this order
+	BoostedProductIDs []string `json:"boosted_product_ids"` // listed before the other matches
+	IsActive          *bool    `json:"is_active"`           // defaults to true
+}
+
+// toSearchRule applies the request onto a search rule
+func (r *SearchRuleRequest) toSearchRule(rule *services.SearchRule) {
+	rule.Query = r.Query
+	rule.PinnedProductIDs = r.PinnedProductIDs
+	rule.BoostedProductIDs = r.BoostedProductIDs
+	rule.IsActive = r.IsActive == nil || *r.IsActive
+}
+
+// ListSynonymSets lists every synonym set
+// GET /admin/search/synonyms
+func (h *SearchRuleHandler) ListSynonymSets(c *gin.Context) {
+	sets, err := h.searchRuleService.ListSynonymSets(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, sets)
+}
+
+// GetSynonymSet retrieves a synonym set
+// GET /admin/search/synonyms/:id
+func (h *SearchRuleHandler) GetSynonymSet(c *gin.Context) {
+	set, err := h.searchRuleService.GetSynonymSet(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, set)
+}
+
+// CreateSynonymSet creates a synonym set
+// POST /admin/search/synonyms
+func (h *SearchRuleHandler) CreateSynonymSet(c *gin.Context) {
+	var req SynonymSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	set := &services.SynonymSet{Terms: req.Terms}
+	if err := h.searchRuleService.SaveSynonymSet(c.Request.Context(), set); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.Created(c, set)
+}
+
+// UpdateSynonymSet replaces a synonym set's terms
+// PUT /admin/search/synonyms/:id
+func (h *SearchRuleHandler) UpdateSynonymSet(c *gin.Context) {
+	var req SynonymSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.searchRuleService.GetSynonymSet(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	set := &services.SynonymSet{ID: c.Param("id"), Terms: req.Terms}
+	if err := h.searchRuleService.SaveSynonymSet(c.Request.Context(), set); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored set
+	if set, err = h.searchRuleService.GetSynonymSet(c.Request.Context(), set.ID); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, set)
+}
+
+// DeleteSynonymSet deletes a synonym set
+// DELETE /admin/search/synonyms/:id
+func (h *SearchRuleHandler) DeleteSynonymSet(c *gin.Context) {
+	if err := h.searchRuleService.DeleteSynonymSet(c.Request.Context(), c.Param("id")); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListSearchRules lists every search rule
+// GET /admin/search/rules
+func (h *SearchRuleHandler) ListSearchRules(c *gin.Context) {
+	rules, err := h.searchRuleService.ListRules(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// GetSearchRule retrieves a search rule
+// GET /admin/search/rules/:id
+func (h *SearchRuleHandler) GetSearchRule(c *gin.Context) {
+	rule, err := h.searchRuleService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, rule)
+}
+
+// CreateSearchRule creates a search rule
+// POST /admin/search/rules
+func (h *SearchRuleHandler) CreateSearchRule(c *gin.Context) {
+	var req SearchRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	rule := &services.SearchRule{}
+	req.toSearchRule(rule)
+	if err := h.searchRuleService.SaveRule(c.Request.Context(), rule); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.Created(c, rule)
+}
+
+// UpdateSearchRule replaces a search rule
+// PUT /admin/search/rules/:id
+func (h *SearchRuleHandler) UpdateSearchRule(c *gin.Context) {
+	var req SearchRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.searchRuleService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	rule := &services.SearchRule{ID: c.Param("id")}
+	req.toSearchRule(rule)
+	if err := h.searchRuleService.SaveRule(c.Request.Context(), rule); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored rule
+	if rule, err = h.searchRuleService.GetRule(c.Request.Context(), rule.ID); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, rule)
+}
+
+// DeleteSearchRule deletes a search rule
+// DELETE /admin/search/rules/:id
+func (h *SearchRuleHandler) DeleteSearchRule(c *gin.Context) {
+	if err := h.searchRuleService.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		respondSearchRuleError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondSearchRuleError maps synonym set and search rule errors to HTTP responses
+func respondSearchRuleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSynonymSetNotFound):
+		response.NotFound(c, "Synonym set not found")
+	case errors.Is(err, services.ErrSearchRuleNotFound):
+		response.NotFound(c, "Search rule not found")
+	case errors.Is(err, services.ErrInvalidSynonymSet), errors.Is(err, services.ErrInvalidSearchRule):
+		response.BadRequest(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SuggestionHandler handles storefront search type-ahead
+type SuggestionHandler struct {
+	suggestionService *services.SuggestionService
+}
+
+// NewSuggestionHandler creates a new SuggestionHandler
+func NewSuggestionHandler(suggestionService *services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+// Suggest returns product name, category and brand suggestions for a partly typed query
+// GET /catalog/search/suggest?q=lap&limit=10
+func (h *SuggestionHandler) Suggest(c *gin.Context) {
+	limit := services.DefaultSuggestLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			response.BadRequest(c, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.suggestionService.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrSuggestQueryRequired) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, suggestions)
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/shipping"
+)
+
+// ShippingHandler handles shipping zone and rate endpoints
+type ShippingHandler struct {
+	shippingService *services.ShippingZoneService
+	calendarService *services.CalendarService
+	restrictions    *services.ShippingRestrictionService
+	cartService     *services.CartService
+}
+
+// NewShippingHandler creates a new ShippingHandler
+func NewShippingHandler(shippingService *services.ShippingZoneService) *ShippingHandler {
+	return &ShippingHandler{
+		shippingService: shippingService,
+	}
+}
+
+// WithCalendarService adds delivery dates to shipping rates, counted in working days of
+// the business calendar
+func (h *ShippingHandler) WithCalendarService(calendarService *services.CalendarService) *ShippingHandler {
+	h.calendarService = calendarService
+	return h
+}
+
+// WithRestrictions leaves out shipping options that a product in the shopper's cart may
+// not ship with
+func (h *ShippingHandler) WithRestrictions(restrictions *services.ShippingRestrictionService, cartService *services.CartService) *ShippingHandler {
+	h.restrictions = restrictions
+	h.cartService = cartService
+	return h
+}
+
+// ShippingRateResponse is a shipping option with the dates an order placed now ships and arrives
+type ShippingRateResponse struct {
+	*shipping.ShippingRate
+	EstimatedDelivery *services.DeliveryEstimate `json:"EstimatedDelivery,omitempty"`
+}
+
+// ShippingZoneRequest represents the request to create or update a shipping zone
+type ShippingZoneRequest struct {
+	Name               string                    `json:"name" binding:"required"`
+	Country            string                    `json:"country" binding:"required,len=2"`
+	State              string                    `json:"state"`
+	PostalCodePatterns []string                  `json:"postal_code_patterns"`
+	Priority           int                       `json:"priority"`
+	IsActive           *bool                     `json:"is_active"`
+	Rates              []ShippingZoneRateRequest `json:"rates" binding:"required,min=1,dive"`
+}
+
+// ShippingZoneRateRequest represents a shipping method offered in a zone
+type ShippingZoneRateRequest struct {
+	MethodID         string `json:"method_id" binding:"required"`
+	MethodName       string `json:"method_name" binding:"required"`
+	Carrier          string `json:"carrier"`
+	ServiceLevel     string `json:"service_level"`
+	Air              bool   `json:"air"`                         // shipped by air
+	CostAmount       int64  `json:"cost_amount" binding:"min=0"` // in cents
+	Currency         string `json:"currency" binding:"required,len=3"`
+	EstimatedDaysMin int    `json:"estimated_days_min" binding:"min=0"`
+	EstimatedDaysMax int    `json:"estimated_days_max" binding:"min=0"`
+}
+
+// toZone applies the request onto a shipping zone
+func (r *ShippingZoneRequest) toZone(zone *services.ShippingZone) error {
+	zone.Name = r.Name
+	zone.Country = r.Country
+	zone.State = r.State
+	zone.PostalCodePatterns = r.PostalCodePatterns
+	zone.Priority = r.Priority
+	zone.IsActive = r.IsActive == nil || *r.IsActive
+
+	zone.Rates = make([]services.ShippingZoneRate, len(r.Rates))
+	for i, rate := range r.Rates {
+		cost, err := money.New(rate.CostAmount, rate.Currency)
+		if err != nil {
+			return err
+		}
+		zone.Rates[i] = services.ShippingZoneRate{
+			MethodID:         rate.MethodID,
+			MethodName:       rate.MethodName,
+			Carrier:          rate.Carrier,
+			ServiceLevel:     rate.ServiceLevel,
+			Air:              rate.Air,
+			Cost:             cost,
+			EstimatedDaysMin: rate.EstimatedDaysMin,
+			EstimatedDaysMax: rate.EstimatedDaysMax,
+		}
+	}
+	return nil
+}
+
+// ListShippingRates lists the shipping options available for a destination
+// GET /checkout/shipping-rates?country=US&state=CA&postal_code=94107
+func (h *ShippingHandler) ListShippingRates(c *gin.Context) {
+	country := c.Query("country")
+	if country == "" {
+		response.BadRequest(c, "country is required")
+		return
+	}
+
+	destination := shipping.Address{
+		Country:    country,
+		State:      c.Query("state"),
+		PostalCode: c.Query("postal_code"),
+	}
+
+	var rates []*shipping.ShippingRate
+	var err error
+	if h.restrictions != nil {
+		// Only offer methods that every product in the cart may ship with
+		shopper, cartErr := shopperCart(c, h.cartService)
+		if cartErr != nil {
+			response.FromError(c, cartErr)
+			return
+		}
+		ctx := c.Request.Context()
+		if !shopper.IsEmpty() {
+			ctx = services.WithShippingSubtotal(ctx, shopper.Subtotal())
+		}
+		rates, err = h.restrictions.AllowedRates(ctx, shopper, destination)
+	} else {
+		rates, err = h.shippingService.GetAvailableRates(c.Request.Context(), shipping.RateRequest{
+			DestinationAddress: destination,
+		})
+	}
+	if err != nil {
+		if err == services.ErrShippingZoneNotFound {
+			// No zone covers the destination, so there is nothing to offer
+			response.Success(c, []*shipping.ShippingRate{})
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	if h.calendarService == nil {
+		response.Success(c, rates)
+		return
+	}
+
+	calendar, err := h.calendarService.Calendar(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	now := time.Now()
+	estimated := make([]ShippingRateResponse, len(rates))
+	for i, rate := range rates {
+		estimated[i] = ShippingRateResponse{ShippingRate: rate}
+		// Rates without a transit time have nothing to count from
+		if rate.EstimatedDaysMax > 0 {
+			estimated[i].EstimatedDelivery = calendar.EstimateDelivery(now, rate.EstimatedDaysMin, rate.EstimatedDaysMax)
+		}
+	}
+	response.Success(c, estimated)
+}
+
+// ListShippingMethods quotes the shipping methods for a destination without a cart, e.g.
+// for a shipping estimate on product pages. Table rates and free shipping are priced
+// against the optional subtotal, in cents of currency.
+// GET /shipping/methods?country=US&state=CA&postal_code=94107&subtotal=4999&currency=USD
+func (h *ShippingHandler) ListShippingMethods(c *gin.Context) {
+	country := c.Query("country")
+	if country == "" {
+		response.BadRequest(c, "country is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if value := c.Query("subtotal"); value != "" {
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount < 0 {
+			response.BadRequest(c, "subtotal must be a non-negative amount in cents")
+			return
+		}
+		subtotal, err := money.New(amount, strings.ToUpper(c.Query("currency")))
+		if err != nil {
+			response.BadRequest(c, "currency is required with subtotal")
+			return
+		}
+		ctx = services.WithShippingSubtotal(ctx, subtotal)
+	}
+
+	rates, err := h.shippingService.GetAvailableRates(ctx, shipping.RateRequest{
+		DestinationAddress: shipping.Address{
+			Country:    country,
+			State:      c.Query("state"),
+			PostalCode: c.Query("postal_code"),
+		},
+	})
+	if err != nil {
+		if err == services.ErrShippingZoneNotFound {
+			response.Success(c, []*shipping.ShippingRate{})
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, rates)
+}
+
+// ListShippingZones lists all shipping zones
+// GET /admin/shipping-zones
+func (h *ShippingHandler) ListShippingZones(c *gin.Context) {
+	zones, err := h.shippingService.ListZones(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, zones)
+}
+
+// GetShippingZone retrieves a shipping zone by ID
+// GET /admin/shipping-zones/:id
+func (h *ShippingHandler) GetShippingZone(c *gin.Context) {
+	zone, err := h.shippingService.GetZone(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrShippingZoneNotFound {
+			response.NotFound(c, "Shipping zone not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, zone)
+}
+
+// CreateShippingZone creates a shipping zone with its rates
+// POST /admin/shipping-zones
+func (h *ShippingHandler) CreateShippingZone(c *gin.Context) {
+	var req ShippingZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	zone := &services.ShippingZone{}
+	if err := req.toZone(zone); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.shippingService.SaveZone(c.Request.Context(), zone); err != nil {
+		if err == services.ErrInvalidShippingZone {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "Failed to create shipping zone")
+		return
+	}
+
+	response.Created(c, zone)
+}
+
+// UpdateShippingZone replaces a shipping zone and its rates
+// PUT /admin/shipping-zones/:id
+func (h *ShippingHandler) UpdateShippingZone(c *gin.Context) {
+	var req ShippingZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	zone, err := h.shippingService.GetZone(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrShippingZoneNotFound {
+			response.NotFound(c, "Shipping zone not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+	if !response.IfMatch(c, zone) {
+		return
+	}
+
+	if err := req.toZone(zone); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.shippingService.SaveZone(c.Request.Context(), zone); err != nil {
+		if err == services.ErrInvalidShippingZone {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "Failed to update shipping zone")
+		return
+	}
+
+	// Reload so the ETag matches the stored zone
+	if zone, err = h.shippingService.GetZone(c.Request.Context(), zone.ID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, zone)
+}
+
+// DeleteShippingZone deletes a shipping zone
+// DELETE /admin/shipping-zones/:id
+func (h *ShippingHandler) DeleteShippingZone(c *gin.Context) {
+	if err := h.shippingService.DeleteZone(c.Request.Context(), c.Param("id")); err != nil {
+		if err == services.ErrShippingZoneNotFound {
+			response.NotFound(c, "Shipping zone not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
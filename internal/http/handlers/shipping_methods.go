@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// ShippingMethodHandler handles admin endpoints for shipping methods and their rates
+type ShippingMethodHandler struct {
+	methodService *services.ShippingMethodService
+}
+
+// NewShippingMethodHandler creates a new ShippingMethodHandler
+func NewShippingMethodHandler(methodService *services.ShippingMethodService) *ShippingMethodHandler {
+	return &ShippingMethodHandler{
+		methodService: methodService,
+	}
+}
+
+// ShippingMethodRequest represents the request to create or update a shipping method.
+// Amounts are in cents of currency.
+type ShippingMethodRequest struct {
+	ID               string                    `json:"id"` // required on create; taken from the path on update
+	Name             string                    `json:"name" binding:"required"`
+	Description      string                    `json:"description"`
+	Carrier          string                    `json:"carrier"`
+	ServiceLevel     string                    `json:"service_level"`
+	Air              bool                      `json:"air"`
+	Countries        []string                  `json:"countries"`
+	Currency         string                    `json:"currency" binding:"required,len=3"`
+	FlatRate         int64                     `json:"flat_rate" binding:"min=0"`
+	RateTiers        []ShippingRateTierRequest `json:"rate_tiers" binding:"dive"`
+	FreeShippingMin  *int64                    `json:"free_shipping_min"`
+	EstimatedDaysMin int                       `json:"estimated_days_min" binding:"min=0"`
+	EstimatedDaysMax int                       `json:"estimated_days_max" binding:"min=0"`
+	SortOrder        int                       `json:"sort_order"`
+	IsActive         *bool                     `json:"is_active"`
+}
+
+// ShippingRateTierRequest represents a table rate: the cost for subtotals of at least
+// min_subtotal
+type ShippingRateTierRequest struct {
+	MinSubtotal int64 `json:"min_subtotal" binding:"min=0"`
+	Cost        int64 `json:"cost" binding:"min=0"`
+}
+
+// toMethod applies the request onto a shipping method
+func (r *ShippingMethodRequest) toMethod(method *services.ShippingMethod) {
+	method.Name = r.Name
+	method.Description = r.Description
+	method.Carrier = r.Carrier
+	method.ServiceLevel = r.ServiceLevel
+	method.Air = r.Air
+	method.Countries = r.Countries
+	method.FlatRate = money.Money{Amount: r.FlatRate, Currency: r.Currency}
+	method.EstimatedDaysMin = r.EstimatedDaysMin
+	method.EstimatedDaysMax = r.EstimatedDaysMax
+	method.SortOrder = r.SortOrder
+	method.IsActive = r.IsActive == nil || *r.IsActive
+
+	method.RateTiers = make([]services.ShippingRateTier, len(r.RateTiers))
+	for i, tier := range r.RateTiers {
+		method.RateTiers[i] = services.ShippingRateTier{
+			MinSubtotal: money.Money{Amount: tier.MinSubtotal, Currency: r.Currency},
+			Cost:        money.Money{Amount: tier.Cost, Currency: r.Currency},
+		}
+	}
+
+	method.FreeShippingMin = nil
+	if r.FreeShippingMin != nil {
+		method.FreeShippingMin = &money.Money{Amount: *r.FreeShippingMin, Currency: r.Currency}
+	}
+}
+
+// ListShippingMethods lists all shipping methods, active or not
+// GET /admin/shipping-methods
+func (h *ShippingMethodHandler) ListShippingMethods(c *gin.Context) {
+	methods, err := h.methodService.ListMethods(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, methods)
+}
+
+// GetShippingMethod retrieves a shipping method by ID
+// GET /admin/shipping-methods/:id
+func (h *ShippingMethodHandler) GetShippingMethod(c *gin.Context) {
+	method, err := h.methodService.GetMethod(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondShippingMethodError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, method)
+}
+
+// CreateShippingMethod creates a shipping method
+// POST /admin/shipping-methods
+func (h *ShippingMethodHandler) CreateShippingMethod(c *gin.Context) {
+	var req ShippingMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	req.ID = strings.ToLower(strings.TrimSpace(req.ID))
+	if _, err := h.methodService.GetMethod(c.Request.Context(), req.ID); err == nil {
+		response.Conflict(c, "A shipping method with this id already exists")
+		return
+	} else if !errors.Is(err, services.ErrShippingMethodNotFound) {
+		response.FromError(c, err)
+		return
+	}
+
+	method := &services.ShippingMethod{ID: req.ID}
+	req.toMethod(method)
+	if err := h.methodService.SaveMethod(c.Request.Context(), method); err != nil {
+		respondShippingMethodError(c, err)
+		return
+	}
+
+	response.Created(c, method)
+}
+
+// UpdateShippingMethod replaces a shipping method's settings and rates
+// PUT /admin/shipping-methods/:id
+func (h *ShippingMethodHandler) UpdateShippingMethod(c *gin.Context) {
+	var req ShippingMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	method, err := h.methodService.GetMethod(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondShippingMethodError(c, err)
+		return
+	}
+	if !response.IfMatch(c, method) {
+		return
+	}
+
+	req.toMethod(method)
+	if err := h.methodService.SaveMethod(c.Request.Context(), method); err != nil {
+		respondShippingMethodError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored method
+	if method, err = h.methodService.GetMethod(c.Request.Context(), method.ID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, method)
+}
+
+// DeleteShippingMethod deletes a shipping method
+// DELETE /admin/shipping-methods/:id
+func (h *ShippingMethodHandler) DeleteShippingMethod(c *gin.Context) {
+	if err := h.methodService.DeleteMethod(c.Request.Context(), c.Param("id")); err != nil {
+		respondShippingMethodError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondShippingMethodError maps shipping method errors to HTTP responses
+func respondShippingMethodError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidShippingMethod):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrShippingMethodNotFound):
+		response.NotFound(c, "Shipping method not found")
+	default:
+		response.FromError(c, err)
+	}
+}
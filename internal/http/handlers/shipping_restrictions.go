@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ShippingRestrictionHandler handles product shipping restriction endpoints
+type ShippingRestrictionHandler struct {
+	restrictionService *services.ShippingRestrictionService
+}
+
+// NewShippingRestrictionHandler creates a new ShippingRestrictionHandler
+func NewShippingRestrictionHandler(restrictionService *services.ShippingRestrictionService) *ShippingRestrictionHandler {
+	return &ShippingRestrictionHandler{
+		restrictionService: restrictionService,
+	}
+}
+
+// ShippingRestrictionRequest represents the request to set a product's shipping restriction
+type ShippingRestrictionRequest struct {
+	NoAir    bool     `json:"no_air"`
+	Carriers []string `json:"carriers"` // only these carriers; empty allows any
+	NoPOBox  bool     `json:"no_po_box"`
+	MinAge   int      `json:"min_age" binding:"min=0,max=130"`
+}
+
+// GetShippingRestriction retrieves a product's shipping restriction
+// GET /admin/catalog/products/:id/shipping-restriction
+func (h *ShippingRestrictionHandler) GetShippingRestriction(c *gin.Context) {
+	restriction, err := h.restrictionService.GetRestriction(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrShippingRestrictionNotFound {
+			response.NotFound(c, "Shipping restriction not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, restriction)
+}
+
+// SetShippingRestriction creates or replaces a product's shipping restriction
+// PUT /admin/catalog/products/:id/shipping-restriction
+func (h *ShippingRestrictionHandler) SetShippingRestriction(c *gin.Context) {
+	var req ShippingRestrictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	restriction := &services.ShippingRestriction{
+		ProductID: c.Param("id"),
+		NoAir:     req.NoAir,
+		Carriers:  req.Carriers,
+		NoPOBox:   req.NoPOBox,
+		MinAge:    req.MinAge,
+	}
+	if err := h.restrictionService.SetRestriction(c.Request.Context(), restriction); err != nil {
+		if err == services.ErrInvalidShippingRestriction {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, restriction)
+}
+
+// DeleteShippingRestriction removes a product's shipping restriction
+// DELETE /admin/catalog/products/:id/shipping-restriction
+func (h *ShippingRestrictionHandler) DeleteShippingRestriction(c *gin.Context) {
+	if err := h.restrictionService.RemoveRestriction(c.Request.Context(), c.Param("id")); err != nil {
+		if err == services.ErrShippingRestrictionNotFound {
+			response.NotFound(c, "Shipping restriction not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondShippingRestrictionError maps broken shipping restrictions to a structured 422
+// response listing every violation
+func respondShippingRestrictionError(c *gin.Context, restrictionErr *services.ShippingRestrictionError) {
+	response.ErrorWithDetails(c, http.StatusUnprocessableEntity, "shipping_restricted", restrictionErr.Error(), gin.H{
+		"violations": restrictionErr.Violations,
+	})
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// StoreCreditHandler handles store credit wallets
+type StoreCreditHandler struct {
+	storeCreditService *services.StoreCreditService
+}
+
+// NewStoreCreditHandler creates a new StoreCreditHandler
+func NewStoreCreditHandler(storeCreditService *services.StoreCreditService) *StoreCreditHandler {
+	return &StoreCreditHandler{
+		storeCreditService: storeCreditService,
+	}
+}
+
+// GetStoreCredit returns the current user's store credit balances
+// GET /store-credit
+func (h *StoreCreditHandler) GetStoreCredit(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	h.respondBalances(c, userID)
+}
+
+// ListStoreCreditTransactions lists the current user's store credit ledger, newest first
+// GET /store-credit/transactions?page=1&page_size=20
+func (h *StoreCreditHandler) ListStoreCreditTransactions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	h.respondTransactions(c, userID)
+}
+
+// GetUserStoreCredit returns a customer's store credit balances
+// GET /admin/users/:id/store-credit
+func (h *StoreCreditHandler) GetUserStoreCredit(c *gin.Context) {
+	h.respondBalances(c, c.Param("id"))
+}
+
+// ListUserStoreCreditTransactions lists a customer's store credit ledger, newest first
+// GET /admin/users/:id/store-credit/transactions?page=1&page_size=20
+func (h *StoreCreditHandler) ListUserStoreCreditTransactions(c *gin.Context) {
+	h.respondTransactions(c, c.Param("id"))
+}
+
+// GrantStoreCreditRequest represents the request to issue store credit to a customer
+type GrantStoreCreditRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"` // in cents
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	Reason   string `json:"reason" binding:"omitempty,oneof=goodwill refund_to_credit promotional other"`
+	Note     string `json:"note"`
+	OrderID  string `json:"order_id"`
+}
+
+// GrantStoreCredit issues store credit to a customer
+// POST /admin/users/:id/store-credit
+func (h *StoreCreditHandler) GrantStoreCredit(c *gin.Context) {
+	adminID, _ := middleware.GetUserID(c)
+
+	var req GrantStoreCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	txn, err := h.storeCreditService.Grant(c.Request.Context(), services.GrantStoreCreditRequest{
+		UserID:    c.Param("id"),
+		Amount:    money.Money{Amount: req.Amount, Currency: currency},
+		Reason:    services.StoreCreditGrantReason(req.Reason),
+		Note:      req.Note,
+		OrderID:   req.OrderID,
+		CreatedBy: adminID,
+	})
+	if err != nil {
+		if err == services.ErrInvalidStoreCredit {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Created(c, txn)
+}
+
+func (h *StoreCreditHandler) respondBalances(c *gin.Context, userID string) {
+	balances, err := h.storeCreditService.GetBalances(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, balances)
+}
+
+func (h *StoreCreditHandler) respondTransactions(c *gin.Context, userID string) {
+	params := response.GetPaginationParams(c)
+
+	filter := services.StoreCreditFilter{
+		UserID: userID,
+		Limit:  params.CalculateLimit(),
+		Offset: params.CalculateOffset(),
+	}
+
+	txns, err := h.storeCreditService.ListTransactions(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.storeCreditService.CountTransactions(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, txns, meta)
+}
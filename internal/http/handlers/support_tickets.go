@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SupportTicketHandler handles support tickets customers open against their orders
+type SupportTicketHandler struct {
+	ticketService *services.SupportTicketService
+	orderService  *services.OrderService
+}
+
+// NewSupportTicketHandler creates a new SupportTicketHandler
+func NewSupportTicketHandler(ticketService *services.SupportTicketService, orderService *services.OrderService) *SupportTicketHandler {
+	return &SupportTicketHandler{
+		ticketService: ticketService,
+		orderService:  orderService,
+	}
+}
+
+// OpenTicketRequest represents the request to open a support ticket against an order
+type OpenTicketRequest struct {
+	Subject  string `json:"subject" binding:"required,max=255"`
+	Category string `json:"category"` // damaged, missing_item, wrong_item, delivery, billing or other (default)
+	Message  string `json:"message" binding:"required,max=5000"`
+}
+
+// TicketReplyRequest represents the request to reply on a support ticket
+type TicketReplyRequest struct {
+	Message string `json:"message" binding:"required,max=5000"`
+}
+
+// TicketStatusRequest represents the request to change a support ticket's status
+type TicketStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=open pending resolved closed"`
+}
+
+// OpenTicket opens a support ticket against one of the current user's orders
+// POST /orders/:id/tickets
+func (h *SupportTicketHandler) OpenTicket(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req OpenTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	ticket, err := h.ticketService.OpenTicket(c.Request.Context(), userID, c.Param("id"), services.OpenTicketRequest{
+		Subject:  req.Subject,
+		Category: services.TicketCategory(req.Category),
+		Message:  req.Message,
+	})
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+
+	response.Created(c, ticket)
+}
+
+// GetOrderTickets lists the support tickets opened against an order with their messages
+// GET /orders/:id/tickets
+// GET /admin/orders/:id/tickets
+func (h *SupportTicketHandler) GetOrderTickets(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+	if order.UserID != userID && !isSupportStaff(c) {
+		response.Forbidden(c, "You don't have permission to view this order's tickets")
+		return
+	}
+
+	tickets, err := h.ticketService.OrderTickets(c.Request.Context(), order.ID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, tickets)
+}
+
+// ListMyTickets lists the current user's support tickets, most recently updated first
+// GET /tickets
+func (h *SupportTicketHandler) ListMyTickets(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	tickets, err := h.ticketService.UserTickets(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, tickets)
+}
+
+// GetTicket retrieves a support ticket with its messages
+// GET /tickets/:id
+// GET /admin/tickets/:id
+func (h *SupportTicketHandler) GetTicket(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	ticket, err := h.ticketService.GetTicket(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+	if ticket.UserID != userID && !isSupportStaff(c) {
+		response.NotFound(c, "Support ticket not found")
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+// ReplyToTicket adds the customer's reply to one of their tickets, putting it back in
+// support's queue
+// POST /tickets/:id/messages
+func (h *SupportTicketHandler) ReplyToTicket(c *gin.Context) {
+	h.reply(c, false)
+}
+
+// StaffReplyToTicket adds support's reply to a ticket, leaving it waiting on the customer
+// POST /admin/tickets/:id/messages
+func (h *SupportTicketHandler) StaffReplyToTicket(c *gin.Context) {
+	h.reply(c, true)
+}
+
+// ListTickets lists the support queue, optionally by status
+// GET /admin/tickets?status=open
+func (h *SupportTicketHandler) ListTickets(c *gin.Context) {
+	tickets, err := h.ticketService.ListTickets(c.Request.Context(), services.TicketStatus(c.Query("status")))
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+
+	response.Success(c, tickets)
+}
+
+// SetTicketStatus resolves, closes or reopens a support ticket
+// PATCH /admin/tickets/:id/status
+func (h *SupportTicketHandler) SetTicketStatus(c *gin.Context) {
+	var req TicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	ticket, err := h.ticketService.SetStatus(c.Request.Context(), c.Param("id"), services.TicketStatus(req.Status))
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+func (h *SupportTicketHandler) reply(c *gin.Context, staff bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req TicketReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	if !staff {
+		ticket, err := h.ticketService.GetTicket(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			respondSupportTicketError(c, err)
+			return
+		}
+		if ticket.UserID != userID {
+			response.NotFound(c, "Support ticket not found")
+			return
+		}
+	}
+
+	ticket, err := h.ticketService.Reply(c.Request.Context(), c.Param("id"), userID, staff, req.Message)
+	if err != nil {
+		respondSupportTicketError(c, err)
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+// isSupportStaff reports whether the current user may see every customer's tickets
+func isSupportStaff(c *gin.Context) bool {
+	return hasAnyRole(c, string(goauthx.RoleAdmin), string(goauthx.RoleManager), string(goauthx.RoleCustomerExperience))
+}
+
+// respondSupportTicketError maps support ticket errors to HTTP responses
+func respondSupportTicketError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTicket):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrTicketClosed):
+		response.Conflict(c, "Support ticket is closed")
+	case errors.Is(err, services.ErrTicketNotFound):
+		response.NotFound(c, "Support ticket not found")
+	case errors.Is(err, orders.ErrOrderNotFound):
+		response.NotFound(c, "Order not found")
+	default:
+		response.FromError(c, err)
+	}
+}
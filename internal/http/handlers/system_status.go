@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SystemStatusHandler serves the admin system status used by the ops dashboard
+type SystemStatusHandler struct {
+	systemStatusService *services.SystemStatusService
+}
+
+// NewSystemStatusHandler creates a new SystemStatusHandler
+func NewSystemStatusHandler(systemStatusService *services.SystemStatusService) *SystemStatusHandler {
+	return &SystemStatusHandler{
+		systemStatusService: systemStatusService,
+	}
+}
+
+// GetSystemStatus summarizes job queues, failed jobs, the webhook backlog, cache hit
+// rates, database latency and the last migration applied
+// GET /admin/system/status
+func (h *SystemStatusHandler) GetSystemStatus(c *gin.Context) {
+	response.Success(c, h.systemStatusService.Status(c.Request.Context()))
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// UnitPriceHandler handles variant unit and unit price endpoints
+type UnitPriceHandler struct {
+	unitPriceService *services.UnitPriceService
+}
+
+// NewUnitPriceHandler creates a new UnitPriceHandler
+func NewUnitPriceHandler(unitPriceService *services.UnitPriceService) *UnitPriceHandler {
+	return &UnitPriceHandler{
+		unitPriceService: unitPriceService,
+	}
+}
+
+// VariantUnitRequest represents the request to set a variant's contents for unit pricing
+type VariantUnitRequest struct {
+	Quantity float64 `json:"unit_quantity" binding:"required,gt=0"`
+	Measure  string  `json:"unit_measure" binding:"required"` // g, kg, ml, cl, l, cm, m or m2
+}
+
+// ListProductVariants lists a product's variants with their sale and unit prices
+// GET /catalog/products/:id/variants
+func (h *UnitPriceHandler) ListProductVariants(c *gin.Context) {
+	variants, err := h.unitPriceService.ProductVariants(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, variants)
+}
+
+// GetVariantUnit returns a variant's unit
+// GET /admin/catalog/variants/:id/unit
+func (h *UnitPriceHandler) GetVariantUnit(c *gin.Context) {
+	unit, err := h.unitPriceService.GetUnit(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondVariantUnitError(c, err)
+		return
+	}
+
+	response.Success(c, unit)
+}
+
+// SetVariantUnit sets a variant's unit
+// PUT /admin/catalog/variants/:id/unit
+func (h *UnitPriceHandler) SetVariantUnit(c *gin.Context) {
+	var req VariantUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	unit := &services.VariantUnit{VariantID: c.Param("id"), Quantity: req.Quantity, Measure: req.Measure}
+	if err := h.unitPriceService.SetUnit(c.Request.Context(), unit); err != nil {
+		respondVariantUnitError(c, err)
+		return
+	}
+
+	response.Success(c, unit)
+}
+
+// DeleteVariantUnit clears a variant's unit
+// DELETE /admin/catalog/variants/:id/unit
+func (h *UnitPriceHandler) DeleteVariantUnit(c *gin.Context) {
+	if err := h.unitPriceService.RemoveUnit(c.Request.Context(), c.Param("id")); err != nil {
+		respondVariantUnitError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+func respondVariantUnitError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidUnitMeasure:
+		response.BadRequest(c, err.Error())
+	case services.ErrVariantNotFound:
+		response.NotFound(c, err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// VariantHandler handles variant option and variant management endpoints
+type VariantHandler struct {
+	variantService *services.VariantService
+}
+
+// NewVariantHandler creates a new VariantHandler
+func NewVariantHandler(variantService *services.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// VariantRequest represents the request to create or replace a variant
+type VariantRequest struct {
+	SKU         string            `json:"sku" binding:"required"`
+	Name        string            `json:"name" binding:"required"`
+	Price       int64             `json:"price" binding:"required,gt=0"` // in cents, in the product currency
+	Attributes  map[string]string `json:"attributes"`                    // e.g. {"size": "M", "color": "Blue"}
+	Images      []string          `json:"images"`
+	IsAvailable *bool             `json:"is_available"`
+}
+
+func (r VariantRequest) input() services.VariantInput {
+	return services.VariantInput{
+		SKU:         r.SKU,
+		Name:        r.Name,
+		Price:       r.Price,
+		Attributes:  r.Attributes,
+		Images:      r.Images,
+		IsAvailable: r.IsAvailable,
+	}
+}
+
+// ListVariantOptions lists the attributes a product's variants vary by, with their values
+// GET /catalog/products/:id/variant-options
+func (h *VariantHandler) ListVariantOptions(c *gin.Context) {
+	options, err := h.variantService.Options(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, options)
+}
+
+// GetVariant returns a variant
+// GET /admin/catalog/variants/:id
+func (h *VariantHandler) GetVariant(c *gin.Context) {
+	variant, err := h.variantService.GetVariant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondVariantError(c, err)
+		return
+	}
+
+	response.SuccessWithETag(c, variant)
+}
+
+// CreateVariant adds a variant to a product
+// POST /admin/catalog/products/:id/variants
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	var req VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	variant, err := h.variantService.CreateVariant(c.Request.Context(), c.Param("id"), req.input())
+	if err != nil {
+		respondVariantError(c, err)
+		return
+	}
+
+	response.Created(c, variant)
+}
+
+// UpdateVariant replaces a variant
+// PUT /admin/catalog/variants/:id
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	var req VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	current, err := h.variantService.GetVariant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondVariantError(c, err)
+		return
+	}
+	if !response.IfMatch(c, current) {
+		return
+	}
+
+	variant, err := h.variantService.UpdateVariant(c.Request.Context(), current.ID, req.input())
+	if err != nil {
+		respondVariantError(c, err)
+		return
+	}
+
+	// Reload so the ETag matches the stored variant
+	if variant, err = h.variantService.GetVariant(c.Request.Context(), variant.ID); err != nil {
+		respondVariantError(c, err)
+		return
+	}
+	response.SuccessWithETag(c, variant)
+}
+
+// DeleteVariant removes a variant
+// DELETE /admin/catalog/variants/:id
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	if err := h.variantService.DeleteVariant(c.Request.Context(), c.Param("id")); err != nil {
+		respondVariantError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// respondVariantError maps variant errors to HTTP responses
+func respondVariantError(c *gin.Context, err error) {
+	var held *services.PriceChangeHeldError
+	if errors.As(err, &held) {
+		// The suspicious price is saved once an admin approves the anomaly
+		response.Accepted(c, held.Anomaly)
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidVariant), errors.Is(err, services.ErrInvalidVariantAttributes), errors.Is(err, services.ErrVariantAttributesMismatch):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrVariantSKUTaken), errors.Is(err, services.ErrDuplicateVariantOptions):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	case errors.Is(err, services.ErrVariantNotFound):
+		response.NotFound(c, "Variant not found")
+	default:
+		response.FromError(c, err)
+	}
+}
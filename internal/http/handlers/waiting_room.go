@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WaitingRoomHandler handles the limited-drop waiting room endpoints
+type WaitingRoomHandler struct {
+	waitingRoomService *services.WaitingRoomService
+}
+
+// NewWaitingRoomHandler creates a new WaitingRoomHandler
+func NewWaitingRoomHandler(waitingRoomService *services.WaitingRoomService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{
+		waitingRoomService: waitingRoomService,
+	}
+}
+
+// JoinWaitingRoom puts the current user in a drop product's queue
+// POST /waiting-room/:id
+func (h *WaitingRoomHandler) JoinWaitingRoom(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	ticket, err := h.waitingRoomService.Join(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondWaitingRoomError(c, err)
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+// GetWaitingRoomStatus retrieves the current user's place in a drop product's queue
+// GET /waiting-room/:id
+func (h *WaitingRoomHandler) GetWaitingRoomStatus(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	ticket, err := h.waitingRoomService.Status(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondWaitingRoomError(c, err)
+		return
+	}
+
+	response.Success(c, ticket)
+}
+
+// respondWaitingRoomError maps waiting room errors to HTTP responses
+func respondWaitingRoomError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrNotADrop:
+		response.NotFound(c, "Product does not have a waiting room")
+	case services.ErrWaitingRoomTicketNotFound:
+		response.NotFound(c, "You have not joined the waiting room for this product")
+	case services.ErrWaitingRoomRequired:
+		response.ErrorWithCode(c, http.StatusForbidden, "waiting_room_required", err.Error())
+	case services.ErrWaitingRoomNotAdmitted:
+		response.ErrorWithCode(c, http.StatusForbidden, "waiting_room_not_admitted", err.Error())
+	case services.ErrWaitingRoomExpired:
+		response.ErrorWithCode(c, http.StatusForbidden, "waiting_room_expired", err.Error())
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// maxWebhookBodySize caps inbound webhook payloads
+const maxWebhookBodySize = 1 << 20
+
+// WebhookEventHandler handles the generic inbound webhook receiver and its admin event log
+type WebhookEventHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookEventHandler creates a new WebhookEventHandler
+func NewWebhookEventHandler(webhookService *services.WebhookService) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ReceiveWebhook verifies and stores a webhook for a registered provider; it is processed asynchronously
+// POST /webhooks/:provider
+func (h *WebhookEventHandler) ReceiveWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	event, duplicate, err := h.webhookService.Receive(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		switch err {
+		case services.ErrWebhookProviderUnknown:
+			response.NotFound(c, "Unknown webhook provider")
+		case services.ErrInvalidWebhookSignature:
+			response.Unauthorized(c, "Invalid webhook signature")
+		case services.ErrInvalidWebhookPayload:
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Accepted(c, gin.H{"received": true, "event_id": event.ID, "duplicate": duplicate})
+}
+
+// ListWebhookEvents lists received webhook events, newest first
+// GET /admin/webhooks/events?provider=payments&status=failed&page=1&page_size=20
+func (h *WebhookEventHandler) ListWebhookEvents(c *gin.Context) {
+	params := response.GetPaginationParams(c)
+
+	filter := services.WebhookEventFilter{
+		Provider: c.Query("provider"),
+		Status:   services.WebhookEventStatus(c.Query("status")),
+		Limit:    params.CalculateLimit(),
+		Offset:   params.CalculateOffset(),
+	}
+
+	events, err := h.webhookService.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	total, err := h.webhookService.CountEvents(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	meta := response.NewPaginationMeta(params.Page, params.PageSize, total)
+	response.SuccessWithPagination(c, events, meta)
+}
+
+// GetWebhookEvent retrieves a webhook event with its stored payload
+// GET /admin/webhooks/events/:id
+func (h *WebhookEventHandler) GetWebhookEvent(c *gin.Context) {
+	event, err := h.webhookService.GetEvent(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrWebhookEventNotFound {
+			response.NotFound(c, "Webhook event not found")
+			return
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, event)
+}
+
+// ReplayWebhookEvent queues a stored webhook event to be processed again
+// POST /admin/webhooks/events/:id/replay
+func (h *WebhookEventHandler) ReplayWebhookEvent(c *gin.Context) {
+	event, err := h.webhookService.Replay(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch err {
+		case services.ErrWebhookEventNotFound:
+			response.NotFound(c, "Webhook event not found")
+		case services.ErrWebhookProviderUnknown:
+			response.Conflict(c, "Webhook provider is no longer registered")
+		case services.ErrWebhookPayloadPurged:
+			response.Conflict(c, "Webhook payload was purged by the retention policy")
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Accepted(c, event)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the raw request body
+const WebhookSignatureHeader = services.PaymentWebhookSignatureHeader
+
+// WebhookHandler handles inbound payment gateway webhooks
+type WebhookHandler struct {
+	disputeService *services.DisputeService
+	secret         []byte
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+// Requests are rejected unless signed with the shared secret.
+func NewWebhookHandler(disputeService *services.DisputeService, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		disputeService: disputeService,
+		secret:         []byte(secret),
+	}
+}
+
+// DisputeWebhookPayload is a dispute event sent by the payment gateway
+type DisputeWebhookPayload = services.DisputeWebhookPayload
+
+// HandleDisputeWebhook records a dispute created or updated at the gateway
+// POST /webhooks/payments/disputes
+func (h *WebhookHandler) HandleDisputeWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	if !services.ValidHMACSignature(h.secret, body, c.GetHeader(WebhookSignatureHeader)) {
+		response.Unauthorized(c, "Invalid webhook signature")
+		return
+	}
+
+	var payload DisputeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	dispute, err := h.disputeService.HandleEvent(c.Request.Context(), payload.DisputeEvent())
+	if err != nil {
+		switch err {
+		case services.ErrInvalidDisputeEvent:
+			response.BadRequest(c, err.Error())
+		case services.ErrDisputeOrderUnknown:
+			// Acknowledge so the gateway stops redelivering an event we can never match
+			response.Success(c, gin.H{"received": true, "matched": false})
+		default:
+			response.FromError(c, err)
+		}
+		return
+	}
+
+	response.Success(c, gin.H{"received": true, "matched": true, "dispute_id": dispute.ID})
+}
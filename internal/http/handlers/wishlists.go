@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WishlistHandler handles wishlist and gift registry endpoints
+type WishlistHandler struct {
+	wishlistService *services.WishlistService
+}
+
+// NewWishlistHandler creates a new WishlistHandler
+func NewWishlistHandler(wishlistService *services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{
+		wishlistService: wishlistService,
+	}
+}
+
+// WishlistRequest represents the request to create or update a wishlist
+type WishlistRequest struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	IsPublic bool   `json:"is_public"` // public wishlists are shared as gift registries
+}
+
+// WishlistItemRequest represents the request to add a product to a wishlist
+type WishlistItemRequest struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	VariantID *string `json:"variant_id"`
+	Quantity  int     `json:"quantity" binding:"required,gt=0"`
+	Note      string  `json:"note" binding:"max=500"`
+}
+
+// UpdateWishlistItemRequest represents the request to change a wishlist item
+type UpdateWishlistItemRequest struct {
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+	Note     string `json:"note" binding:"max=500"`
+}
+
+// ListWishlists lists the current user's wishlists
+// GET /wishlists
+func (h *WishlistHandler) ListWishlists(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	wishlists, err := h.wishlistService.ListWishlists(c.Request.Context(), userID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, wishlists)
+}
+
+// GetWishlist retrieves one of the current user's wishlists with the units bought of each item
+// GET /wishlists/:id
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	wishlist, err := h.wishlistService.GetWishlist(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Success(c, wishlist)
+}
+
+// CreateWishlist creates a wishlist for the current user
+// POST /wishlists
+func (h *WishlistHandler) CreateWishlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	wishlist, err := h.wishlistService.CreateWishlist(c.Request.Context(), userID, services.WishlistRequest{
+		Name:     req.Name,
+		IsPublic: req.IsPublic,
+	})
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Created(c, wishlist)
+}
+
+// UpdateWishlist renames a wishlist or makes it public or private
+// PUT /wishlists/:id
+func (h *WishlistHandler) UpdateWishlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	wishlist, err := h.wishlistService.UpdateWishlist(c.Request.Context(), userID, c.Param("id"), services.WishlistRequest{
+		Name:     req.Name,
+		IsPublic: req.IsPublic,
+	})
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Success(c, wishlist)
+}
+
+// DeleteWishlist deletes a wishlist
+// DELETE /wishlists/:id
+func (h *WishlistHandler) DeleteWishlist(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.wishlistService.DeleteWishlist(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// AddWishlistItem adds a product to a wishlist
+// POST /wishlists/:id/items
+func (h *WishlistHandler) AddWishlistItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	wishlist, err := h.wishlistService.AddItem(c.Request.Context(), userID, c.Param("id"), services.WishlistItemRequest{
+		ProductID: req.ProductID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+		Note:      req.Note,
+	})
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Success(c, wishlist)
+}
+
+// UpdateWishlistItem changes the quantity wanted and note of a wishlist item
+// PATCH /wishlists/:id/items/:itemId
+func (h *WishlistHandler) UpdateWishlistItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req UpdateWishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidRequest(c, err)
+		return
+	}
+
+	wishlist, err := h.wishlistService.UpdateItem(c.Request.Context(), userID, c.Param("id"), c.Param("itemId"), req.Quantity, req.Note)
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Success(c, wishlist)
+}
+
+// RemoveWishlistItem removes an item from a wishlist
+// DELETE /wishlists/:id/items/:itemId
+func (h *WishlistHandler) RemoveWishlistItem(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.wishlistService.RemoveItem(c.Request.Context(), userID, c.Param("id"), c.Param("itemId")); err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// GetRegistry retrieves a public wishlist by its share token, with the units of each item
+// still wanted; buy an item for the owner by adding it to the cart with its wishlist_item_id
+// GET /registries/:token
+func (h *WishlistHandler) GetRegistry(c *gin.Context) {
+	registry, err := h.wishlistService.GetRegistry(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+
+	response.Success(c, registry)
+}
+
+// isRegistryError reports whether err is about buying an item from a registry
+func isRegistryError(err error) bool {
+	return errors.Is(err, services.ErrRegistryItemUnavailable) ||
+		errors.Is(err, services.ErrRegistryItemPurchased) ||
+		errors.Is(err, services.ErrRegistryItemInCart)
+}
+
+// respondWishlistError maps wishlist and registry errors to HTTP responses
+func respondWishlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWishlist),
+		errors.Is(err, services.ErrInvalidWishlistItem):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, services.ErrRegistryItemUnavailable),
+		errors.Is(err, services.ErrRegistryItemPurchased),
+		errors.Is(err, services.ErrRegistryItemInCart):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, services.ErrWishlistNotFound):
+		response.NotFound(c, "Wishlist not found")
+	case errors.Is(err, services.ErrWishlistItemNotFound):
+		response.NotFound(c, "Wishlist item not found")
+	case errors.Is(err, services.ErrProductNotFound):
+		response.NotFound(c, "Product not found")
+	default:
+		response.FromError(c, err)
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+const (
+	// APIKeyHeader carries the secret of an integration API key
+	APIKeyHeader = "X-API-Key"
+	// APIKeyIDKey is the context key for the authenticated API key ID
+	APIKeyIDKey = "api_key_id"
+)
+
+// APIKeyMiddleware authenticates external integrations with scoped API keys
+type APIKeyMiddleware struct {
+	apiKeyService *services.APIKeyService
+	quotas        *services.QuotaService
+}
+
+// NewAPIKeyMiddleware creates a new APIKeyMiddleware
+func NewAPIKeyMiddleware(apiKeyService *services.APIKeyService) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// WithQuotas counts requests against the key's daily and monthly quotas
+func (m *APIKeyMiddleware) WithQuotas(quotas *services.QuotaService) *APIKeyMiddleware {
+	m.quotas = quotas
+	return m
+}
+
+// RequireScope validates the X-API-Key header and checks the key grants the scope
+func (m *APIKeyMiddleware) RequireScope(scope services.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			response.Unauthorized(c, "X-API-Key header required")
+			c.Abort()
+			return
+		}
+
+		key, err := m.apiKeyService.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			if err == services.ErrInvalidAPIKey {
+				response.Unauthorized(c, "Invalid or revoked API key")
+			} else {
+				response.FromError(c, err)
+			}
+			c.Abort()
+			return
+		}
+
+		if !key.HasScope(scope) {
+			response.Forbidden(c, "API key lacks the "+string(scope)+" scope")
+			c.Abort()
+			return
+		}
+
+		if m.quotas != nil {
+			status, err := m.quotas.ConsumeAPIKey(c.Request.Context(), key)
+			if !enforceQuota(c, status, err) {
+				return
+			}
+		}
+
+		c.Set(APIKeyIDKey, key.ID)
+		c.Next()
+	}
+}
+
+// GetAPIKeyID extracts the authenticated API key ID from the Gin context
+func GetAPIKeyID(c *gin.Context) (string, bool) {
+	keyID, exists := c.Get(APIKeyIDKey)
+	if !exists {
+		return "", false
+	}
+	id, ok := keyID.(string)
+	return id, ok
+}
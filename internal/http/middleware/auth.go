@@ -5,6 +5,7 @@ import (
 
 	"github.com/devchuckcamp/goauthx"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,7 +20,9 @@ const (
 
 // AuthMiddleware wraps goauthx authentication for Gin
 type AuthMiddleware struct {
-	authService *goauthx.Service
+	authService   *goauthx.Service
+	guestSessions *services.GuestSessionService
+	quotas        *services.QuotaService
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware
@@ -29,6 +32,13 @@ func NewAuthMiddleware(authService *goauthx.Service) *AuthMiddleware {
 	}
 }
 
+// WithQuotas counts signed-in users' requests against the daily and monthly quotas of
+// their tier; guests are not counted
+func (m *AuthMiddleware) WithQuotas(quotas *services.QuotaService) *AuthMiddleware {
+	m.quotas = quotas
+	return m
+}
+
 // Authenticate validates JWT tokens and sets user context
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -63,6 +73,13 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Set(UserEmailKey, claims.Email)
 		c.Set(UserRolesKey, claims.Roles)
 
+		if m.quotas != nil {
+			status, err := m.quotas.ConsumeUser(c.Request.Context(), claims.UserID, claims.Roles)
+			if !enforceQuota(c, status, err) {
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
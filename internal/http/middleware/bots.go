@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/gin-gonic/gin"
+)
+
+// BotVerdict is what the bot guard decided to do with a request
+type BotVerdict string
+
+const (
+	BotVerdictAllow    BotVerdict = "allow"
+	BotVerdictThrottle BotVerdict = "throttle"
+	BotVerdictBlock    BotVerdict = "block"
+)
+
+// suspectedBotPatterns mark clients that look automated but are not denylisted; they are throttled
+var suspectedBotPatterns = []string{
+	"bot", "crawl", "spider", "scrape", "curl", "wget", "python", "go-http-client", "java/", "okhttp", "headless", "phantomjs",
+}
+
+// IPReputationProvider scores client IPs against an external reputation service
+type IPReputationProvider interface {
+	Check(ctx context.Context, ip string) (BotVerdict, error)
+}
+
+// BotPolicy configures how the bot guard treats automated traffic.
+// User-agent patterns are matched case-insensitively as substrings.
+type BotPolicy struct {
+	// AllowUserAgents are known search engine crawlers that are never throttled or blocked.
+	// User agents can be spoofed; verify crawlers by reverse DNS upstream if that matters.
+	AllowUserAgents []string
+	// BlockUserAgents are scrapers that are rejected outright
+	BlockUserAgents []string
+	// BlockEmptyUserAgent rejects requests without a User-Agent instead of throttling them
+	BlockEmptyUserAgent bool
+	// ThrottlePerMinute is how many requests a suspected bot IP may make per minute
+	ThrottlePerMinute int
+}
+
+// BotStats counts the requests the bot guard has turned away
+type BotStats struct {
+	Blocked   int64            `json:"blocked"`
+	Throttled int64            `json:"throttled"`
+	ByReason  map[string]int64 `json:"by_reason"`
+	Since     time.Time        `json:"since"`
+}
+
+// BotGuard throttles or blocks scrapers based on user-agent rules and an optional IP reputation provider
+type BotGuard struct {
+	policy          BotPolicy
+	reputation      IPReputationProvider
+	reputationRetry retry.Policy
+
+	mu      sync.Mutex
+	windows map[string]*throttleWindow
+	stats   BotStats
+}
+
+type throttleWindow struct {
+	start time.Time
+	count int
+}
+
+// NewBotGuard creates a new BotGuard
+func NewBotGuard(policy BotPolicy) *BotGuard {
+	policy.AllowUserAgents = lowerAll(policy.AllowUserAgents)
+	policy.BlockUserAgents = lowerAll(policy.BlockUserAgents)
+
+	return &BotGuard{
+		policy:  policy,
+		windows: make(map[string]*throttleWindow),
+		stats:   BotStats{ByReason: make(map[string]int64), Since: time.Now()},
+	}
+}
+
+// SetThrottle changes how many requests a suspected bot IP may make per minute; windows
+// already open are held to the new limit
+func (g *BotGuard) SetThrottle(perMinute int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policy.ThrottlePerMinute = perMinute
+}
+
+// WithReputationProvider consults an IP reputation service for requests not already decided by user agent
+func (g *BotGuard) WithReputationProvider(provider IPReputationProvider) *BotGuard {
+	g.reputation = provider
+	return g
+}
+
+// WithReputationRetry retries failed reputation lookups. Lookups run inline with catalog
+// requests, so keep the budget short; when it runs out the request is let through.
+func (g *BotGuard) WithReputationRetry(policy retry.Policy) *BotGuard {
+	g.reputationRetry = policy
+	return g
+}
+
+// Protect rejects denylisted bots with 403 and rate limits suspected bots with 429
+func (g *BotGuard) Protect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verdict, reason := g.classify(c.Request.UserAgent())
+
+		// Reputation lookups fail open: an unavailable provider must not take the catalog down
+		if verdict != BotVerdictBlock && reason != "allowlisted" && g.reputation != nil {
+			ipVerdict, err := retry.Call(c.Request.Context(), g.reputationRetry, func(ctx context.Context) (BotVerdict, error) {
+				return g.reputation.Check(ctx, c.ClientIP())
+			})
+			if err == nil && ipVerdict != BotVerdictAllow {
+				if ipVerdict == BotVerdictBlock || verdict == BotVerdictAllow {
+					verdict, reason = ipVerdict, "ip_reputation"
+				}
+			}
+		}
+
+		switch verdict {
+		case BotVerdictBlock:
+			g.record(BotVerdictBlock, reason)
+			response.ErrorWithCode(c, http.StatusForbidden, "bot_blocked", "Automated traffic is not allowed")
+			c.Abort()
+			return
+		case BotVerdictThrottle:
+			if retryAfter, ok := g.allow(c.ClientIP()); !ok {
+				g.record(BotVerdictThrottle, reason)
+				c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				response.ErrorWithCode(c, http.StatusTooManyRequests, "rate_limited", "Too many requests")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Stats returns a snapshot of the requests turned away so far
+func (g *BotGuard) Stats() BotStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	snapshot := g.stats
+	snapshot.ByReason = make(map[string]int64, len(g.stats.ByReason))
+	for reason, count := range g.stats.ByReason {
+		snapshot.ByReason[reason] = count
+	}
+	return snapshot
+}
+
+// classify decides what to do with a request from its user agent alone
+func (g *BotGuard) classify(userAgent string) (BotVerdict, string) {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		if g.policy.BlockEmptyUserAgent {
+			return BotVerdictBlock, "empty_user_agent"
+		}
+		return BotVerdictThrottle, "empty_user_agent"
+	}
+
+	for _, pattern := range g.policy.AllowUserAgents {
+		if strings.Contains(ua, pattern) {
+			return BotVerdictAllow, "allowlisted"
+		}
+	}
+	for _, pattern := range g.policy.BlockUserAgents {
+		if strings.Contains(ua, pattern) {
+			return BotVerdictBlock, "user_agent:" + pattern
+		}
+	}
+	for _, pattern := range suspectedBotPatterns {
+		if strings.Contains(ua, pattern) {
+			return BotVerdictThrottle, "suspected_bot"
+		}
+	}
+	return BotVerdictAllow, ""
+}
+
+// allow counts a request against the IP's one-minute window and reports whether it is within the limit
+func (g *BotGuard) allow(ip string) (time.Duration, bool) {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.policy.ThrottlePerMinute <= 0 {
+		return 0, true
+	}
+
+	window, ok := g.windows[ip]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		if !ok && len(g.windows) >= 10000 {
+			g.evictExpired(now)
+		}
+		window = &throttleWindow{start: now}
+		g.windows[ip] = window
+	}
+
+	window.count++
+	if window.count > g.policy.ThrottlePerMinute {
+		return time.Minute - now.Sub(window.start), false
+	}
+	return 0, true
+}
+
+// evictExpired drops finished windows so the table stays bounded; callers hold g.mu
+func (g *BotGuard) evictExpired(now time.Time) {
+	for ip, window := range g.windows {
+		if now.Sub(window.start) >= time.Minute {
+			delete(g.windows, ip)
+		}
+	}
+}
+
+func (g *BotGuard) record(verdict BotVerdict, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if verdict == BotVerdictBlock {
+		g.stats.Blocked++
+	} else {
+		g.stats.Throttled++
+	}
+	g.stats.ByReason[reason]++
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			lowered = append(lowered, value)
+		}
+	}
+	return lowered
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// GuestSessionIDKey is the context key for the guest session ID of a guest request
+const GuestSessionIDKey = "guest_session_id"
+
+// WithGuestSessions lets AuthenticateOrGuest accept guest session tokens
+func (m *AuthMiddleware) WithGuestSessions(guestSessions *services.GuestSessionService) *AuthMiddleware {
+	m.guestSessions = guestSessions
+	return m
+}
+
+// AuthenticateOrGuest validates a JWT like Authenticate when an Authorization header is
+// sent, and otherwise accepts a guest session token in the X-Guest-Session header. Guests
+// act under services.GuestUserID, so handlers read the user ID as for signed-in users.
+func (m *AuthMiddleware) AuthenticateOrGuest() gin.HandlerFunc {
+	authenticate := m.Authenticate()
+	return func(c *gin.Context) {
+		token := c.GetHeader(services.GuestSessionHeader)
+		if c.GetHeader("Authorization") != "" || token == "" || m.guestSessions == nil {
+			authenticate(c)
+			return
+		}
+
+		sessionID, err := m.guestSessions.Verify(token)
+		if err != nil {
+			response.Unauthorized(c, "Invalid or expired guest session")
+			c.Abort()
+			return
+		}
+
+		c.Set(UserIDKey, services.GuestUserID(sessionID))
+		c.Set(UserRolesKey, []string{})
+		c.Set(GuestSessionIDKey, sessionID)
+
+		c.Next()
+	}
+}
+
+// GetGuestSessionID extracts the guest session ID of a guest request from the Gin context
+func GetGuestSessionID(c *gin.Context) (string, bool) {
+	sessionID, exists := c.Get(GuestSessionIDKey)
+	if !exists {
+		return "", false
+	}
+	id, ok := sessionID.(string)
+	return id, ok
+}
@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+const (
+	// inspectorBodyLimit caps the request and response body kept per request
+	inspectorBodyLimit = 4 << 10
+	// inspectorPathPrefix is where the inspector serves itself; those requests aren't recorded
+	inspectorPathPrefix = "/debug/"
+)
+
+// inspectorRedactedHeaders are replaced with a placeholder before a request is kept
+var inspectorRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-API-Key", "X-Webhook-Signature"}
+
+// sqlLiteral matches quoted strings and numbers, so statements that differ only in
+// their arguments group together
+var sqlLiteral = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+
+// InspectedQuery is one SQL statement run while serving a request
+type InspectedQuery struct {
+	SQL      string `json:"sql"`
+	Rows     int64  `json:"rows"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RepeatedQuery is a statement shape run more than once for one request, the usual sign
+// of an N+1 query
+type RepeatedQuery struct {
+	SQL   string `json:"sql"`
+	Count int    `json:"count"`
+}
+
+// InspectedRequest is a request recorded by the RequestInspector
+type InspectedRequest struct {
+	ID              string              `json:"id"`
+	Method          string              `json:"method"`
+	Path            string              `json:"path"`
+	Route           string              `json:"route"`
+	Query           string              `json:"query,omitempty"`
+	Status          int                 `json:"status"`
+	StartedAt       time.Time           `json:"started_at"`
+	Duration        string              `json:"duration"`
+	SQLCount        int                 `json:"sql_count"`
+	SQLDuration     string              `json:"sql_duration"`
+	RequestHeaders  map[string][]string `json:"request_headers,omitempty"`
+	RequestBody     string              `json:"request_body,omitempty"`
+	ResponseHeaders map[string][]string `json:"response_headers,omitempty"`
+	ResponseBody    string              `json:"response_body,omitempty"`
+	Queries         []InspectedQuery    `json:"queries,omitempty"`
+	RepeatedQueries []RepeatedQuery     `json:"repeated_queries,omitempty"`
+}
+
+// Summary returns the request without its headers, bodies and statements
+func (r InspectedRequest) Summary() InspectedRequest {
+	r.RequestHeaders, r.RequestBody = nil, ""
+	r.ResponseHeaders, r.ResponseBody = nil, ""
+	r.Queries = nil
+	return r
+}
+
+// RequestInspector keeps the last requests served, with their timing and the SQL they
+// ran, for developers to look through at /debug/requests. It holds request and response
+// bodies in memory, so it is only for local development.
+type RequestInspector struct {
+	capacity int
+	seq      atomic.Int64
+
+	mu       sync.Mutex
+	requests []InspectedRequest // ring buffer; next is the oldest once full
+	next     int
+}
+
+// NewRequestInspector creates an inspector keeping the last capacity requests
+func NewRequestInspector(capacity int) *RequestInspector {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RequestInspector{
+		capacity: capacity,
+		requests: make([]InspectedRequest, 0, capacity),
+	}
+}
+
+// Record records every request it sees. Apply it ahead of the other middleware so the
+// timing covers them; statements are counted when the database has RecordQueries on.
+func (i *RequestInspector) Record() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, inspectorPathPrefix) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		queries := &database.QueryLog{}
+		c.Request = c.Request.WithContext(database.WithQueryLog(c.Request.Context(), queries))
+
+		var requestBody []byte
+		if c.Request.Body != nil && !strings.Contains(c.Request.URL.Path, "/auth/") {
+			// Auth requests carry passwords and tokens; keep their bodies out of the inspector
+			requestBody, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+
+		writer := &inspectorWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		recorded := InspectedRequest{
+			ID:              strconv.FormatInt(i.seq.Add(1), 10),
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Route:           c.FullPath(),
+			Query:           c.Request.URL.RawQuery,
+			Status:          c.Writer.Status(),
+			StartedAt:       start,
+			Duration:        time.Since(start).String(),
+			SQLCount:        queries.Count(),
+			SQLDuration:     queries.Duration().String(),
+			RequestHeaders:  redactHeaders(c.Request.Header),
+			RequestBody:     truncateBody(requestBody),
+			ResponseHeaders: redactHeaders(c.Writer.Header()),
+			ResponseBody:    truncateBody(writer.body.Bytes()),
+		}
+		recorded.Queries, recorded.RepeatedQueries = inspectQueries(queries.Queries())
+		i.add(recorded)
+	}
+}
+
+// Requests returns the recorded requests, newest first
+func (i *RequestInspector) Requests() []InspectedRequest {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	result := make([]InspectedRequest, 0, len(i.requests))
+	for n := 1; n <= len(i.requests); n++ {
+		result = append(result, i.requests[(i.next-n+len(i.requests))%len(i.requests)])
+	}
+	return result
+}
+
+// Request returns a recorded request by ID
+func (i *RequestInspector) Request(id string) (InspectedRequest, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, recorded := range i.requests {
+		if recorded.ID == id {
+			return recorded, true
+		}
+	}
+	return InspectedRequest{}, false
+}
+
+// Clear forgets the recorded requests
+func (i *RequestInspector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.requests = i.requests[:0]
+	i.next = 0
+}
+
+func (i *RequestInspector) add(recorded InspectedRequest) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.requests) < i.capacity {
+		i.requests = append(i.requests, recorded)
+		i.next = len(i.requests) % i.capacity
+		return
+	}
+	i.requests[i.next] = recorded
+	i.next = (i.next + 1) % i.capacity
+}
+
+// inspectorWriter copies the response body as it is written
+type inspectorWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *inspectorWriter) Write(b []byte) (int, error) {
+	if room := inspectorBodyLimit + 1 - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *inspectorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// inspectQueries presents the statements and groups the ones run more than once
+func inspectQueries(recorded []database.RecordedQuery) ([]InspectedQuery, []RepeatedQuery) {
+	queries := make([]InspectedQuery, len(recorded))
+	counts := make(map[string]int)
+	for n, query := range recorded {
+		queries[n] = InspectedQuery{
+			SQL:      query.SQL,
+			Rows:     query.Rows,
+			Duration: query.Duration.String(),
+			Error:    query.Error,
+		}
+		counts[sqlLiteral.ReplaceAllString(query.SQL, "?")]++
+	}
+
+	var repeated []RepeatedQuery
+	for sql, count := range counts {
+		if count > 1 {
+			repeated = append(repeated, RepeatedQuery{SQL: sql, Count: count})
+		}
+	}
+	sort.Slice(repeated, func(a, b int) bool {
+		if repeated[a].Count != repeated[b].Count {
+			return repeated[a].Count > repeated[b].Count
+		}
+		return repeated[a].SQL < repeated[b].SQL
+	})
+	return queries, repeated
+}
+
+func redactHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(map[string][]string, len(header))
+	for name, values := range header {
+		redacted[name] = append([]string(nil), values...)
+	}
+	for _, name := range inspectorRedactedHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			redacted[http.CanonicalHeaderKey(name)] = []string{"[redacted]"}
+		}
+	}
+	return redacted
+}
+
+func truncateBody(body []byte) string {
+	if len(body) > inspectorBodyLimit {
+		return string(body[:inspectorBodyLimit]) + "...(truncated)"
+	}
+	return string(body)
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// latencyWindow is how far back request latency is averaged
+	latencyWindow = 10
+	// minLatencySamples keeps a handful of slow requests from triggering shedding
+	minLatencySamples = 20
+	// shedRetryAfter is the Retry-After sent with shed requests, in seconds
+	shedRetryAfter = "2"
+
+	shedKey = "load_shed"
+)
+
+// LoadPolicy configures when low-priority traffic is shed
+type LoadPolicy struct {
+	// MaxInFlight sheds low-priority requests while this many requests of any priority are
+	// being served; 0 disables the check
+	MaxInFlight int
+	// MaxLatency sheds low-priority requests once the average latency over the last ten
+	// seconds passes it. The share shed grows with the overshoot, reaching all of them at
+	// twice MaxLatency. 0 disables the check.
+	MaxLatency time.Duration
+}
+
+// LoadStats counts the requests shed and reports the current load
+type LoadStats struct {
+	Shed           int64            `json:"shed"`
+	ByReason       map[string]int64 `json:"by_reason"`
+	ByRoute        map[string]int64 `json:"by_route"`
+	InFlight       int64            `json:"in_flight"`
+	AvgLatency     string           `json:"avg_latency"`
+	LatencySamples int64            `json:"latency_samples"`
+	MaxInFlight    int              `json:"max_in_flight"`
+	MaxLatency     string           `json:"max_latency"`
+	Since          time.Time        `json:"since"`
+}
+
+// LoadShedder rejects low-priority requests early with 503 when the server is overloaded,
+// so the capacity that is left goes to checkout, payments and webhooks. Track measures
+// every request; Shed is applied only to the routes that may be turned away.
+type LoadShedder struct {
+	policy   LoadPolicy
+	inFlight atomic.Int64
+
+	mu      sync.Mutex
+	buckets [latencyWindow]latencyBucket
+	stats   LoadStats
+}
+
+// latencyBucket sums the latency of requests that finished within one second
+type latencyBucket struct {
+	second int64
+	total  time.Duration
+	count  int64
+}
+
+// NewLoadShedder creates a new LoadShedder
+func NewLoadShedder(policy LoadPolicy) *LoadShedder {
+	return &LoadShedder{
+		policy: policy,
+		stats: LoadStats{
+			ByReason: make(map[string]int64),
+			ByRoute:  make(map[string]int64),
+			Since:    time.Now(),
+		},
+	}
+}
+
+// SetPolicy changes the shedding thresholds
+func (s *LoadShedder) SetPolicy(policy LoadPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+}
+
+// Track counts in-flight requests and records their latency. Apply it to every route.
+func (s *LoadShedder) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
+		c.Next()
+
+		// Shed requests return at once; counting them would hide the overload
+		if !c.GetBool(shedKey) {
+			s.observe(time.Now(), time.Since(started))
+		}
+	}
+}
+
+// Shed turns requests away with 503 while the server is overloaded
+func (s *LoadShedder) Shed() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if reason, shed := s.overloaded(); shed {
+			s.record(reason, c.FullPath())
+			c.Set(shedKey, true)
+			c.Header("Retry-After", shedRetryAfter)
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, "overloaded", "The server is busy, please retry shortly")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Stats returns a snapshot of shed traffic and current load
+func (s *LoadShedder) Stats() LoadStats {
+	avg, samples := s.averageLatency(time.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.stats
+	snapshot.ByReason = make(map[string]int64, len(s.stats.ByReason))
+	for reason, count := range s.stats.ByReason {
+		snapshot.ByReason[reason] = count
+	}
+	snapshot.ByRoute = make(map[string]int64, len(s.stats.ByRoute))
+	for route, count := range s.stats.ByRoute {
+		snapshot.ByRoute[route] = count
+	}
+	snapshot.InFlight = s.inFlight.Load()
+	snapshot.AvgLatency = avg.Round(time.Millisecond).String()
+	snapshot.LatencySamples = samples
+	snapshot.MaxInFlight = s.policy.MaxInFlight
+	snapshot.MaxLatency = s.policy.MaxLatency.String()
+	return snapshot
+}
+
+// overloaded decides whether to shed the current request
+func (s *LoadShedder) overloaded() (string, bool) {
+	s.mu.Lock()
+	policy := s.policy
+	s.mu.Unlock()
+
+	// The request being decided is already counted by Track
+	if policy.MaxInFlight > 0 && s.inFlight.Load() > int64(policy.MaxInFlight) {
+		return "in_flight", true
+	}
+
+	if policy.MaxLatency > 0 {
+		avg, samples := s.averageLatency(time.Now())
+		if samples >= minLatencySamples && avg > policy.MaxLatency {
+			overshoot := float64(avg-policy.MaxLatency) / float64(policy.MaxLatency)
+			if overshoot >= 1 || rand.Float64() < overshoot {
+				return "latency", true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s *LoadShedder) observe(finished time.Time, latency time.Duration) {
+	second := finished.Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket := &s.buckets[second%latencyWindow]
+	if bucket.second != second {
+		*bucket = latencyBucket{second: second}
+	}
+	bucket.total += latency
+	bucket.count++
+}
+
+// averageLatency averages the requests that finished within the window
+func (s *LoadShedder) averageLatency(now time.Time) (time.Duration, int64) {
+	oldest := now.Unix() - latencyWindow + 1
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total time.Duration
+	var count int64
+	for _, bucket := range s.buckets {
+		if bucket.second >= oldest {
+			total += bucket.total
+			count += bucket.count
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(count), count
+}
+
+func (s *LoadShedder) record(reason, route string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Shed++
+	s.stats.ByReason[reason]++
+	s.stats.ByRoute[route]++
+}
@@ -1,31 +1,178 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
 	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Logger is a middleware that logs HTTP requests
+// loggedBodyLimit caps the request and response body logged with a failed request
+const loggedBodyLimit = 2 << 10
+
+// LoggerOptions tunes request logging
+type LoggerOptions struct {
+	// SampleRate is the share of successful requests to SampledPaths that are logged; 1
+	// logs them all. Failed requests are always logged in full.
+	SampleRate float64
+	// SampledPaths are path prefixes, after /api/vN, of high-volume routes such as the
+	// catalog whose successful requests are sampled
+	SampledPaths []string
+}
+
+// Logger is a middleware that logs every HTTP request
 func Logger() gin.HandlerFunc {
+	return LoggerWithOptions(LoggerOptions{SampleRate: 1})
+}
+
+// LoggerWithOptions logs HTTP requests, sampling successful requests to high-volume
+// routes. Requests that fail with 4xx or 5xx are logged with their query, headers,
+// request body and response body so they can be investigated. Credentials, emails and
+// postal addresses are redacted from everything logged.
+func LoggerWithOptions(opts LoggerOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		method := c.Request.Method
 
+		// Keep the start of the bodies in case the request fails
+		var requestBody *limitedBuffer
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			requestBody = &limitedBuffer{limit: loggedBodyLimit}
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(c.Request.Body, requestBody), c.Request.Body}
+		}
+		writer := &loggerWriter{ResponseWriter: c.Writer, body: limitedBuffer{limit: loggedBodyLimit}}
+		c.Writer = writer
+
 		c.Next()
 
 		latency := time.Since(start)
 		statusCode := c.Writer.Status()
 		clientIP := c.ClientIP()
 
-		log.Printf("[%s] %s %s - %d (%v)",
+		if statusCode < http.StatusBadRequest {
+			if !sampled(path, opts) {
+				return
+			}
+			log.Printf("[%s] %s %s - %d (%v)",
+				method,
+				RedactPII(path),
+				clientIP,
+				statusCode,
+				latency,
+			)
+			return
+		}
+
+		var details []string
+		if query := redactQuery(c.Request.URL.RawQuery); query != "" {
+			details = append(details, "query="+query)
+		}
+		details = append(details, "headers="+formatHeaders(c.Request.Header))
+		if requestBody != nil && requestBody.Len() > 0 {
+			details = append(details, "request="+RedactPII(requestBody.String()))
+		}
+		if writer.body.Len() > 0 {
+			details = append(details, "response="+RedactPII(writer.body.String()))
+		}
+		if len(c.Errors) > 0 {
+			details = append(details, "errors="+RedactPII(c.Errors.String()))
+		}
+
+		log.Printf("[%s] %s %s - %d (%v) %s",
 			method,
-			path,
+			RedactPII(path),
 			clientIP,
 			statusCode,
 			latency,
+			strings.Join(details, " "),
 		)
 	}
 }
+
+// sampled reports whether a successful request is logged: always for routes that aren't
+// sampled, otherwise for SampleRate of them
+func sampled(path string, opts LoggerOptions) bool {
+	if opts.SampleRate >= 1 {
+		return true
+	}
+	route := path
+	if rest, ok := strings.CutPrefix(path, "/api/v"); ok {
+		if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+			route = rest[slash:]
+		}
+	}
+	for _, prefix := range opts.SampledPaths {
+		if route == prefix || strings.HasPrefix(route, prefix+"/") {
+			return rand.Float64() < opts.SampleRate
+		}
+	}
+	return true
+}
+
+// formatHeaders lists request headers sorted by name, with credentials redacted
+func formatHeaders(header http.Header) string {
+	redacted := redactHeaders(header)
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ":" + RedactPII(strings.Join(redacted[name], ","))
+	}
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	room := b.limit - b.Buffer.Len()
+	if len(p) > room {
+		b.truncated = true
+	}
+	if room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.Buffer.String() + "...(truncated)"
+	}
+	return b.Buffer.String()
+}
+
+// loggerWriter keeps the start of error responses for the log
+type loggerWriter struct {
+	gin.ResponseWriter
+	body limitedBuffer
+}
+
+func (w *loggerWriter) Write(b []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *loggerWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// defaultMaintenanceMessage is sent when MAINTENANCE_MESSAGE is empty
+const defaultMaintenanceMessage = "The store is down for maintenance, please try again later"
+
+// maintenanceExempt are the API paths, after /api/vN, that stay open during maintenance:
+// admins signing in and working, and providers delivering webhooks
+var maintenanceExempt = []string{"/admin", "/auth/login", "/auth/refresh", "/webhooks"}
+
+// Maintenance turns API requests away with 503 while maintenance mode is on. It reads the
+// runtime settings on every request, so a configuration reload turns it on or off at once.
+func Maintenance(runtimeConfig *services.RuntimeConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings := runtimeConfig.Current()
+		if !settings.MaintenanceMode || maintenanceAllowed(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		message := settings.MaintenanceMessage
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "maintenance", message)
+		c.Abort()
+	}
+}
+
+// maintenanceAllowed reports whether a path is served during maintenance; only API routes
+// are closed, so health checks and media keep working
+func maintenanceAllowed(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/api/v")
+	if !ok {
+		return true
+	}
+	if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+		rest = rest[slash:]
+	}
+	for _, prefix := range maintenanceExempt {
+		if rest == prefix || strings.HasPrefix(rest, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+const (
+	// QuotaLimitHeader is the request limit of the quota window closest to running out
+	QuotaLimitHeader = "X-Quota-Limit"
+	// QuotaRemainingHeader is how many requests that window has left
+	QuotaRemainingHeader = "X-Quota-Remaining"
+	// QuotaResetHeader is when that window resets, in Unix seconds
+	QuotaResetHeader = "X-Quota-Reset"
+)
+
+// enforceQuota sets the quota headers from a request's quota status and responds 429 once
+// a quota is used up. It reports whether the request may go on. Quotas fail open: when
+// usage can't be counted the request is let through.
+func enforceQuota(c *gin.Context, status *services.QuotaStatus, err error) bool {
+	if err != nil && !errors.Is(err, services.ErrQuotaExceeded) {
+		log.Printf("Failed to count request against quota: %v", err)
+		return true
+	}
+	if status == nil {
+		return true
+	}
+
+	usage := status.Binding()
+	if usage == nil {
+		return true
+	}
+	c.Header(QuotaLimitHeader, strconv.FormatInt(usage.Limit, 10))
+	c.Header(QuotaRemainingHeader, strconv.FormatInt(usage.Remaining, 10))
+	c.Header(QuotaResetHeader, strconv.FormatInt(usage.ResetsAt.Unix(), 10))
+	if err == nil {
+		return true
+	}
+
+	// With nothing left, the binding window is the last one to reset
+	c.Header("Retry-After", strconv.Itoa(int(time.Until(usage.ResetsAt).Seconds())+1))
+	response.ErrorWithCode(c, http.StatusTooManyRequests, "quota_exceeded", "Request quota for the "+status.Tier+" tier is used up until "+usage.ResetsAt.Format(time.RFC3339))
+	c.Abort()
+	return false
+}
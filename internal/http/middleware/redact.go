@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces personal data and credentials in logs
+const redactedValue = "[redacted]"
+
+var (
+	// emailPattern matches email addresses anywhere in logged text
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+
+	// sensitiveJSONField matches JSON fields holding credentials, contact details or postal
+	// addresses, with a string value or a flat object value such as a shipping address
+	sensitiveJSONField = regexp.MustCompile(`"([A-Za-z0-9_]+)"\s*:\s*("(?:[^"\\]|\\.)*"|\{[^{}]*\})`)
+
+	// sensitiveFieldName matches the names of fields and query parameters that are redacted
+	sensitiveFieldName = regexp.MustCompile(`(?i)password|passcode|secret|token|authorization|email|phone|address|street|line1|line2|city|postal|zip|card|cvc|cvv`)
+)
+
+// isSensitiveField reports whether a field or parameter is redacted. References such as
+// address_id are kept, so logs still show which record a request was about.
+func isSensitiveField(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, "_id") || strings.HasSuffix(lower, "_ids") || lower == "id" {
+		return false
+	}
+	return sensitiveFieldName.MatchString(lower)
+}
+
+// RedactPII removes credentials, emails and postal addresses from text bound for the logs:
+// sensitive JSON fields lose their values and email addresses are replaced wherever they
+// appear
+func RedactPII(text string) string {
+	if text == "" {
+		return text
+	}
+	text = sensitiveJSONField.ReplaceAllStringFunc(text, func(field string) string {
+		name := sensitiveJSONField.FindStringSubmatch(field)[1]
+		if !isSensitiveField(name) {
+			return field
+		}
+		return `"` + name + `":"` + redactedValue + `"`
+	})
+	return emailPattern.ReplaceAllString(text, redactedValue)
+}
+
+// redactQuery redacts sensitive query parameters and any emails in the rest
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return RedactPII(rawQuery)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(values))
+	for _, name := range names {
+		for _, value := range values[name] {
+			if isSensitiveField(name) {
+				value = redactedValue
+			}
+			parts = append(parts, name+"="+RedactPII(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
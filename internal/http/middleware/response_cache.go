@@ -0,0 +1,312 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+const (
+	// SurrogateKeyAll tags every cached response, so purging it empties the cache
+	SurrogateKeyAll = "catalog"
+	// SurrogateKeyProducts tags every response listing or showing products
+	SurrogateKeyProducts = "products"
+
+	surrogateKeysKey = "surrogate_keys"
+)
+
+// SurrogatePurger purges responses by surrogate key from a CDN in front of the API
+type SurrogatePurger interface {
+	Purge(ctx context.Context, keys []string) error
+}
+
+// SurrogateKeyFunc returns the surrogate keys of a request, such as product:123
+type SurrogateKeyFunc func(c *gin.Context) []string
+
+// SurrogateKeys returns the same keys for every request
+func SurrogateKeys(keys ...string) SurrogateKeyFunc {
+	return func(c *gin.Context) []string { return keys }
+}
+
+// SurrogateKeyParam returns prefix:<param>, e.g. product:123 for /products/:id, along with
+// any fixed keys
+func SurrogateKeyParam(prefix, param string, keys ...string) SurrogateKeyFunc {
+	return func(c *gin.Context) []string {
+		return append([]string{prefix + ":" + c.Param(param)}, keys...)
+	}
+}
+
+// AddSurrogateKeys tags the response with keys the route doesn't know of, such as the
+// products in a listing. Call it before writing the response.
+func AddSurrogateKeys(c *gin.Context, keys ...string) {
+	c.Set(surrogateKeysKey, append(c.GetStringSlice(surrogateKeysKey), keys...))
+}
+
+// ResponseCacheStats reports the response cache's size and hit counts
+type ResponseCacheStats struct {
+	Entries int       `json:"entries"`
+	Hits    int64     `json:"hits"`
+	Misses  int64     `json:"misses"`
+	Purged  int64     `json:"purged"`
+	TTL     string    `json:"ttl"`
+	Since   time.Time `json:"since"`
+}
+
+// cachedResponse is a stored 200 response and the surrogate keys it was tagged with
+type cachedResponse struct {
+	contentType string
+	body        []byte
+	keys        []string
+	expires     time.Time
+}
+
+// ResponseCache keeps whole responses of public GET routes in memory and tags them with
+// surrogate keys, which are also sent to a CDN in the Surrogate-Key header. Cache is
+// applied per route with the route's keys; PurgeOnSuccess is applied to the admin routes
+// that change what those responses show.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	purger     SurrogatePurger
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	index   map[string]map[string]struct{} // surrogate key -> entries tagged with it
+	stats   ResponseCacheStats
+}
+
+// NewResponseCache creates a ResponseCache holding up to maxEntries responses for ttl
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cachedResponse),
+		index:      make(map[string]map[string]struct{}),
+		stats:      ResponseCacheStats{TTL: ttl.String(), Since: time.Now()},
+	}
+}
+
+// WithPurger also purges the keys from a CDN whenever they are purged here
+func (rc *ResponseCache) WithPurger(purger SurrogatePurger) *ResponseCache {
+	rc.purger = purger
+	return rc
+}
+
+// Cache serves the route from the cache, storing 200 responses tagged with keys, the keys
+// added by the handler and SurrogateKeyAll. Responses vary by path, query string and
+// whether HAL was asked for.
+func (rc *ResponseCache) Cache(keys SurrogateKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		cacheKey := responseCacheKey(c)
+		if cached := rc.get(cacheKey); cached != nil {
+			rc.writeHeaders(c, "HIT", cached.keys)
+			c.Data(http.StatusOK, cached.contentType, cached.body)
+			c.Abort()
+			return
+		}
+
+		writer := &cacheWriter{ResponseWriter: c.Writer}
+		writer.beforeWrite = func() {
+			if writer.Status() == http.StatusOK {
+				writer.keys = surrogateKeys(keys(c), c.GetStringSlice(surrogateKeysKey))
+				rc.writeHeaders(c, "MISS", writer.keys)
+			}
+		}
+		c.Writer = writer
+
+		c.Next()
+
+		if writer.Status() == http.StatusOK && writer.keys != nil {
+			rc.set(cacheKey, &cachedResponse{
+				contentType: writer.Header().Get("Content-Type"),
+				body:        writer.body,
+				keys:        writer.keys,
+				expires:     time.Now().Add(rc.ttl),
+			})
+		}
+	}
+}
+
+// PurgeOnSuccess purges keys once a POST, PUT, PATCH or DELETE succeeds, for admin routes
+// that change cached responses. A CDN purge that fails is logged; the change itself stands.
+func (rc *ResponseCache) PurgeOnSuccess(keys SurrogateKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return
+		}
+
+		if status := c.Writer.Status(); status >= 200 && status < 300 {
+			purged := keys(c)
+			if err := rc.Purge(c.Request.Context(), purged...); err != nil {
+				log.Printf("Failed to purge %v from the CDN: %v", purged, err)
+			}
+		}
+	}
+}
+
+// Purge drops the responses tagged with any of keys, then purges the keys from the CDN
+func (rc *ResponseCache) Purge(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	rc.mu.Lock()
+	for _, key := range keys {
+		for cacheKey := range rc.index[key] {
+			rc.remove(cacheKey)
+			rc.stats.Purged++
+		}
+	}
+	rc.mu.Unlock()
+
+	if rc.purger == nil {
+		return nil
+	}
+	return rc.purger.Purge(ctx, keys)
+}
+
+// Stats reports the cache's size and hit counts
+func (rc *ResponseCache) Stats() ResponseCacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	stats := rc.stats
+	stats.Entries = len(rc.entries)
+	return stats
+}
+
+func (rc *ResponseCache) get(cacheKey string) *cachedResponse {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	cached, ok := rc.entries[cacheKey]
+	if !ok || time.Now().After(cached.expires) {
+		rc.stats.Misses++
+		return nil
+	}
+	rc.stats.Hits++
+	return cached
+}
+
+// set stores a response. A full cache first drops expired responses; if it is still
+// full, the response is not stored.
+func (rc *ResponseCache) set(cacheKey string, cached *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.remove(cacheKey)
+	if len(rc.entries) >= rc.maxEntries {
+		now := time.Now()
+		for key, entry := range rc.entries {
+			if now.After(entry.expires) {
+				rc.remove(key)
+			}
+		}
+		if len(rc.entries) >= rc.maxEntries {
+			return
+		}
+	}
+
+	rc.entries[cacheKey] = cached
+	for _, key := range cached.keys {
+		if rc.index[key] == nil {
+			rc.index[key] = make(map[string]struct{})
+		}
+		rc.index[key][cacheKey] = struct{}{}
+	}
+}
+
+// remove drops an entry and its index references; callers hold the lock
+func (rc *ResponseCache) remove(cacheKey string) {
+	cached, ok := rc.entries[cacheKey]
+	if !ok {
+		return
+	}
+	delete(rc.entries, cacheKey)
+	for _, key := range cached.keys {
+		delete(rc.index[key], cacheKey)
+		if len(rc.index[key]) == 0 {
+			delete(rc.index, key)
+		}
+	}
+}
+
+// writeHeaders tags the response for the CDN, which keeps it as long as the API does
+func (rc *ResponseCache) writeHeaders(c *gin.Context, status string, keys []string) {
+	c.Header("X-Cache", status)
+	c.Header("Surrogate-Key", strings.Join(keys, " "))
+	c.Header("Surrogate-Control", "max-age="+strconv.Itoa(int(rc.ttl.Seconds())))
+}
+
+// responseCacheKey identifies a response by path, sorted query string and HAL
+func responseCacheKey(c *gin.Context) string {
+	key := c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+	if response.WantsHAL(c) {
+		key += "#hal"
+	}
+	return key
+}
+
+// surrogateKeys merges route and handler keys with SurrogateKeyAll, sorted and deduplicated
+func surrogateKeys(sets ...[]string) []string {
+	seen := map[string]bool{SurrogateKeyAll: true}
+	keys := []string{SurrogateKeyAll}
+	for _, set := range sets {
+		for _, key := range set {
+			if key != "" && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cacheWriter copies the response body as it is written, and calls beforeWrite once
+// before anything is sent so headers can still be set
+type cacheWriter struct {
+	gin.ResponseWriter
+	body        []byte
+	keys        []string
+	beforeWrite func()
+	started     bool
+}
+
+func (w *cacheWriter) start() {
+	if !w.started {
+		w.started = true
+		w.beforeWrite()
+	}
+}
+
+func (w *cacheWriter) WriteHeaderNow() {
+	w.start()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	w.start()
+	w.body = append(w.body, b...)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	w.start()
+	w.body = append(w.body, s...)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+const (
+	// APIVersionKey is the context key for the negotiated API version
+	APIVersionKey = "api_version"
+	// APIVersionHeader lets clients request a response shape other than the one implied by the path
+	APIVersionHeader = "API-Version"
+
+	// APIVersion1 is the original response shape
+	APIVersion1 = 1
+	// APIVersion2 normalizes order responses to snake_case with itemized line pricing
+	APIVersion2 = 2
+	// LatestAPIVersion is the newest version a client can negotiate
+	LatestAPIVersion = APIVersion2
+)
+
+// APIVersion negotiates the response version for a route group. The version in the path is
+// the default; an API-Version request header (e.g. "2" or "v2") overrides it so existing v1
+// integrations can opt into a new shape one endpoint at a time. The negotiated version is
+// echoed back in the API-Version response header.
+func APIVersion(pathVersion int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := pathVersion
+		if requested := c.GetHeader(APIVersionHeader); requested != "" {
+			parsed, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(requested)), "v"))
+			if err != nil || parsed < APIVersion1 || parsed > LatestAPIVersion {
+				response.ErrorWithCode(c, http.StatusBadRequest, "unsupported_api_version",
+					"Unsupported API version; supported versions are 1 to "+strconv.Itoa(LatestAPIVersion))
+				c.Abort()
+				return
+			}
+			version = parsed
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, strconv.Itoa(version))
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the negotiated API version, defaulting to v1 outside a versioned group
+func GetAPIVersion(c *gin.Context) int {
+	if version, ok := c.Get(APIVersionKey); ok {
+		if v, ok := version.(int); ok {
+			return v
+		}
+	}
+	return APIVersion1
+}
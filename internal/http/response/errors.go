@@ -0,0 +1,179 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one request field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`   // JSON path of the field, e.g. shipping_address.city
+	Rule    string `json:"rule"`    // validation rule that failed, e.g. required or email
+	Message string `json:"message"` // human-readable description of the failure
+}
+
+// errorMapping is the HTTP status and code a registered domain error is sent with
+type errorMapping struct {
+	target error
+	status int
+	code   string
+}
+
+var (
+	errorMu       sync.RWMutex
+	errorMappings []errorMapping
+)
+
+func init() {
+	// Name fields in validation details as clients send them rather than by Go field name
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// RegisterError maps a domain error, and every error wrapping it, to an HTTP status and a
+// stable error code. Mappings are checked in the order they were registered; registering
+// the same error again replaces its mapping.
+func RegisterError(target error, status int, code string) {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+
+	for i, mapping := range errorMappings {
+		if mapping.target == target {
+			errorMappings[i] = errorMapping{target: target, status: status, code: code}
+			return
+		}
+	}
+	errorMappings = append(errorMappings, errorMapping{target: target, status: status, code: code})
+}
+
+// FromError sends err as the status and code it was registered with, using the error's own
+// text as the message. Unregistered errors are logged and sent as a 500 with a generic
+// message, so internal details never reach the client.
+func FromError(c *gin.Context, err error) {
+	if mapping, ok := lookupError(err); ok {
+		ErrorWithCode(c, mapping.status, mapping.code, err.Error())
+		return
+	}
+
+	_ = c.Error(err)
+	log.Printf("ERROR: %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	InternalServerError(c, "An unexpected error occurred")
+}
+
+// InvalidRequest sends a 400 for a request body or query that failed to bind. Validation
+// failures list each offending field in details.fields under the validation_failed code.
+func InvalidRequest(c *gin.Context, err error) {
+	fields := validationDetails(err)
+	if len(fields) == 0 {
+		BadRequest(c, "Invalid request body")
+		return
+	}
+
+	ErrorWithDetails(c, http.StatusBadRequest, "validation_failed", "Invalid request body", gin.H{
+		"fields": fields,
+	})
+}
+
+// lookupError finds the first registered mapping err matches
+func lookupError(err error) (errorMapping, bool) {
+	errorMu.RLock()
+	defer errorMu.RUnlock()
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.target) {
+			return mapping, true
+		}
+	}
+	return errorMapping{}, false
+}
+
+// validationDetails lists the fields a binding error complains about, if any
+func validationDetails(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			field := fieldPath(fieldErr.Namespace())
+			fields = append(fields, FieldError{
+				Field:   field,
+				Rule:    fieldErr.Tag(),
+				Message: field + " " + ruleMessage(fieldErr),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type),
+		}}
+	}
+	return nil
+}
+
+// fieldPath drops the request struct's name from a validator namespace, leaving the path a
+// client sends, e.g. CreateOrderRequest.shipping_address.city becomes shipping_address.city
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// ruleMessage describes a failed validation rule
+func ruleMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "len":
+		return "must have a length of " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
+
+// requestFieldName names a struct field by its json tag, falling back to its form tag for
+// query parameters
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
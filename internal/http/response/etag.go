@@ -0,0 +1,51 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns a strong entity tag for a resource: a hash of its JSON representation, so
+// it changes whenever any field does
+func ETag(resource interface{}) string {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// SuccessWithETag sends a successful response with the resource's ETag, which clients send
+// back in If-Match to update it
+func SuccessWithETag(c *gin.Context, data interface{}) {
+	c.Header("ETag", ETag(data))
+	Success(c, data)
+}
+
+// IfMatch checks a write's If-Match header against the resource as currently stored, so
+// an admin UI can't overwrite a change it hasn't seen. It responds 412 with the current
+// ETag and returns false when no tag matches; "*" matches any resource. Requests without
+// the header are let through.
+func IfMatch(c *gin.Context, current interface{}) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+
+	etag := ETag(current)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	c.Header("ETag", etag)
+	ErrorWithCode(c, http.StatusPreconditionFailed, "precondition_failed", "The resource has changed since it was read; reload it and retry")
+	return false
+}
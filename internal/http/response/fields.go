@@ -0,0 +1,82 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sparse is a resource trimmed to the fields a client selected with ?fields=
+type Sparse struct {
+	Resource interface{}
+	Keys     []string // JSON keys to keep
+}
+
+// MarshalJSON renders only the selected keys of the resource
+func (s Sparse) MarshalJSON() ([]byte, error) {
+	body, err := json.Marshal(s.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return body, nil
+	}
+
+	trimmed := make(map[string]json.RawMessage, len(s.Keys))
+	for _, key := range s.Keys {
+		if value, ok := object[key]; ok {
+			trimmed[key] = value
+		}
+	}
+	return json.Marshal(trimmed)
+}
+
+// ParseFields reads the comma separated ?fields= query parameter. allowed maps each field
+// name a client may request to the resource's JSON key. It returns the selected names, or
+// nil when the parameter is absent so callers return every field.
+func ParseFields(c *gin.Context, allowed map[string]string) ([]string, error) {
+	raw := strings.TrimSpace(c.Query("fields"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" || seen[field] {
+			continue
+		}
+		if _, ok := allowed[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// SelectFields trims every element of a slice to the selected fields. With no fields
+// selected the data is returned unchanged.
+func SelectFields(data interface{}, fields []string, allowed map[string]string) interface{} {
+	value := reflect.ValueOf(data)
+	if len(fields) == 0 || value.Kind() != reflect.Slice {
+		return data
+	}
+
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = allowed[field]
+	}
+
+	items := make([]Sparse, value.Len())
+	for i := range items {
+		items[i] = Sparse{Resource: value.Index(i).Interface(), Keys: keys}
+	}
+	return items
+}
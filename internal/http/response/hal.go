@@ -0,0 +1,209 @@
+package response
+
+import (
+	"encoding/json"
+	"mime"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HALMediaType is the media type clients send in Accept to receive HAL responses
+const HALMediaType = "application/hal+json"
+
+// HALLink is a HAL link object
+type HALLink struct {
+	Href string `json:"href"`
+}
+
+// halSerializer describes how one resource type is rendered as HAL
+type halSerializer struct {
+	collection string
+	self       func(resource interface{}) string
+}
+
+var (
+	halMu          sync.RWMutex
+	halSerializers = make(map[reflect.Type]halSerializer)
+)
+
+// RegisterHALSerializer registers how resources of type T are rendered as HAL. collection is
+// the _embedded key used for lists of T and self returns the resource's path, or "" when the
+// resource has no URL of its own. Unregistered types are embedded as "items" without links.
+func RegisterHALSerializer[T any](collection string, self func(T) string) {
+	halMu.Lock()
+	defer halMu.Unlock()
+
+	halSerializers[reflect.TypeOf((*T)(nil)).Elem()] = halSerializer{
+		collection: collection,
+		self: func(resource interface{}) string {
+			return self(resource.(T))
+		},
+	}
+}
+
+// WantsHAL reports whether the client asked for HAL in its Accept header
+func WantsHAL(c *gin.Context) bool {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == HALMediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// halResource renders a single resource with its self link
+func halResource(c *gin.Context, data interface{}) (interface{}, error) {
+	if data == nil {
+		return gin.H{"_links": gin.H{"self": HALLink{Href: c.Request.URL.RequestURI()}}}, nil
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Slice {
+		collection, items, err := halItems(value)
+		if err != nil {
+			return nil, err
+		}
+		return gin.H{
+			"_links":    gin.H{"self": HALLink{Href: c.Request.URL.RequestURI()}},
+			"_embedded": gin.H{collection: items},
+		}, nil
+	}
+
+	resource, err := halEmbed(data)
+	if err != nil {
+		return nil, err
+	}
+	if object, ok := resource.(map[string]interface{}); ok {
+		if _, linked := object["_links"]; !linked {
+			object["_links"] = gin.H{"self": HALLink{Href: c.Request.URL.RequestURI()}}
+		}
+	}
+	return resource, nil
+}
+
+// halCollection renders a page of resources with first/prev/next/last links
+func halCollection(c *gin.Context, data interface{}, meta PaginationMeta) (interface{}, error) {
+	collection, items := "items", []interface{}{}
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice {
+		var err error
+		if collection, items, err = halItems(value); err != nil {
+			return nil, err
+		}
+	}
+
+	links := gin.H{
+		"self":  HALLink{Href: pageURL(c, meta.Page)},
+		"first": HALLink{Href: pageURL(c, 1)},
+	}
+	if meta.TotalPages > 0 {
+		links["last"] = HALLink{Href: pageURL(c, meta.TotalPages)}
+	}
+	if meta.HasPrev {
+		links["prev"] = HALLink{Href: pageURL(c, meta.Page-1)}
+	}
+	if meta.HasNext {
+		links["next"] = HALLink{Href: pageURL(c, meta.Page+1)}
+	}
+
+	body := gin.H{
+		"_links":      links,
+		"_embedded":   gin.H{collection: items},
+		"page":        meta.Page,
+		"page_size":   meta.PageSize,
+		"total_items": meta.TotalItems,
+		"total_pages": meta.TotalPages,
+	}
+	if meta.Search != nil {
+		body["search"] = meta.Search
+	}
+	return body, nil
+}
+
+// halItems embeds each element of a slice, naming the collection after the element serializer
+func halItems(value reflect.Value) (string, []interface{}, error) {
+	elemType := value.Type().Elem()
+	if elemType == reflect.TypeOf(Sparse{}) && value.Len() > 0 {
+		elemType = reflect.TypeOf(value.Index(0).Interface().(Sparse).Resource)
+	}
+
+	collection := "items"
+	halMu.RLock()
+	if serializer, ok := halSerializers[elemType]; ok && serializer.collection != "" {
+		collection = serializer.collection
+	}
+	halMu.RUnlock()
+
+	items := make([]interface{}, value.Len())
+	for i := range items {
+		item, err := halEmbed(value.Index(i).Interface())
+		if err != nil {
+			return "", nil, err
+		}
+		items[i] = item
+	}
+	return collection, items, nil
+}
+
+// halEmbed converts a resource to a JSON object carrying its self link, when it has one
+func halEmbed(resource interface{}) (interface{}, error) {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		// Not an object (e.g. a string or number); embed it as is
+		return resource, nil
+	}
+
+	// Sparse resources keep the self link of the resource they were trimmed from
+	if sparse, isSparse := resource.(Sparse); isSparse {
+		resource = sparse.Resource
+	}
+
+	halMu.RLock()
+	serializer, ok := halSerializers[reflect.TypeOf(resource)]
+	halMu.RUnlock()
+	if ok {
+		if href := serializer.self(resource); href != "" {
+			object["_links"] = gin.H{"self": HALLink{Href: href}}
+		}
+	}
+	return object, nil
+}
+
+// pageURL returns the current request URL pointing at another page
+func pageURL(c *gin.Context, page int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// writeHAL sends body as HAL, falling back to an error response if it cannot be rendered
+func writeHAL(c *gin.Context, status int, body interface{}, err error) {
+	c.Header("Vary", "Accept")
+	if err != nil {
+		InternalServerError(c, err.Error())
+		return
+	}
+	c.Header("Content-Type", HALMediaType)
+	c.JSON(status, body)
+}
+
+// respondHAL renders data as a HAL resource when the client asked for it
+func respondHAL(c *gin.Context, status int, data interface{}) bool {
+	if !WantsHAL(c) {
+		return false
+	}
+	body, err := halResource(c, data)
+	writeHAL(c, status, body, err)
+	return true
+}
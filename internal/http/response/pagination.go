@@ -16,6 +16,15 @@ type PaginationMeta struct {
 	TotalPages int   `json:"total_pages"`
 	HasNext    bool  `json:"has_next"`
 	HasPrev    bool  `json:"has_prev"`
+
+	// Search is set on search results whose query was changed before searching
+	Search *SearchMeta `json:"search,omitempty"`
+}
+
+// SearchMeta describes how a search query was changed before searching
+type SearchMeta struct {
+	Query          string `json:"query"`
+	CorrectedQuery string `json:"corrected_query,omitempty"`
 }
 
 // GetPaginationParams extracts and validates pagination parameters from query string
@@ -65,6 +74,12 @@ func NewPaginationMeta(page, pageSize int, totalItems int64) PaginationMeta {
 
 // SuccessWithPagination sends a successful response with pagination metadata
 func SuccessWithPagination(c *gin.Context, data interface{}, meta PaginationMeta) {
+	if WantsHAL(c) {
+		body, err := halCollection(c, data, meta)
+		writeHAL(c, 200, body, err)
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"data": data,
 		"meta": meta,
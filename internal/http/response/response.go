@@ -23,12 +23,16 @@ type Meta struct {
 
 // Error represents an error response
 type Error struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // Success sends a successful response
 func Success(c *gin.Context, data interface{}) {
+	if respondHAL(c, http.StatusOK, data) {
+		return
+	}
 	c.JSON(http.StatusOK, Response{
 		Data: data,
 	})
@@ -44,11 +48,21 @@ func SuccessWithMeta(c *gin.Context, data interface{}, meta *Meta) {
 
 // Created sends a created response (201)
 func Created(c *gin.Context, data interface{}) {
+	if respondHAL(c, http.StatusCreated, data) {
+		return
+	}
 	c.JSON(http.StatusCreated, Response{
 		Data: data,
 	})
 }
 
+// Accepted sends an accepted response (202) for work that completes asynchronously
+func Accepted(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusAccepted, Response{
+		Data: data,
+	})
+}
+
 // NoContent sends a no content response (204)
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
@@ -123,3 +137,14 @@ func ErrorWithCode(c *gin.Context, status int, code string, message string) {
 		},
 	})
 }
+
+// ErrorWithDetails sends a custom error response with structured details
+func ErrorWithDetails(c *gin.Context, status int, code string, message string, details interface{}) {
+	c.JSON(status, Response{
+		Error: &Error{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/devchuckcamp/goauthx"
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
 	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
 	"github.com/devchuckcamp/gocommerce-api/internal/services"
 )
 
@@ -19,32 +21,237 @@ func NewServer(
 	authService *goauthx.Service,
 	authStore goauthx.Store,
 	authSeeder *goauthx.Seeder,
+	loginSecurityService *services.LoginSecurityService,
 	catalogService *services.CatalogService,
+	categoryCountService *services.CategoryCountService,
+	suggestionService *services.SuggestionService,
+	searchRuleService *services.SearchRuleService,
+	productMediaService *services.ProductMediaService,
+	catalogHistoryService *services.CatalogHistoryService,
+	bulkArchiveService *services.BulkArchiveService,
+	collectionService *services.CollectionService,
+	barcodeService *services.BarcodeService,
+	unitPriceService *services.UnitPriceService,
+	variantService *services.VariantService,
 	cartService *services.CartService,
+	cartMetadataService *services.CartMetadataService,
+	guestSessionService *services.GuestSessionService,
+	recentlyViewedService *services.RecentlyViewedService,
+	customerService *services.CustomerService,
+	reviewService *services.ReviewService,
+	questionService *services.QuestionService,
+	contentReportService *services.ContentReportService,
+	companyService *services.CompanyService,
+	quoteService *services.QuoteService,
+	invoiceService *services.InvoiceService,
+	purchaseLimitService *services.PurchaseLimitService,
+	checkoutRuleService *services.CheckoutRuleService,
+	orderBumpService *services.OrderBumpService,
+	pricingAnomalyService *services.PricingAnomalyService,
+	shippingRestrictionService *services.ShippingRestrictionService,
+	rentalService *services.RentalService,
+	appointmentService *services.AppointmentService,
+	donationService *services.DonationService,
+	wishlistService *services.WishlistService,
+	waitingRoomService *services.WaitingRoomService,
 	orderService *services.OrderService,
+	supportTicketService *services.SupportTicketService,
+	orderEventService *services.OrderEventService,
+	orderAttributionService *services.OrderAttributionService,
+	orderExportService *services.OrderExportService,
+	orderConfirmationService *services.OrderConfirmationService,
+	orderCancellationService *services.OrderCancellationService,
+	orderStatusService *services.OrderStatusService,
+	deliveryService *services.DeliveryService,
+	addressService *services.AddressService,
+	shippingService *services.ShippingZoneService,
+	shippingMethodService *services.ShippingMethodService,
+	calendarService *services.CalendarService,
+	paymentService *services.PaymentService,
+	paymentRetryService *services.PaymentRetryService,
+	disputeService *services.DisputeService,
+	refundService *services.RefundService,
+	storeCreditService *services.StoreCreditService,
+	consentService *services.ConsentService,
+	pageService *services.PageService,
+	placementService *services.PlacementService,
+	apiKeyService *services.APIKeyService,
+	quotaService *services.QuotaService,
+	funnelService *services.FunnelService,
+	fulfillmentService *services.FulfillmentService,
+	posService *services.POSService,
+	webhookService *services.WebhookService,
+	deadLetterService *services.DeadLetterService,
+	systemStatusService *services.SystemStatusService,
+	runtimeConfigService *services.RuntimeConfigService,
+	retentionService *services.RetentionService,
+	orderArchiveService *services.OrderArchiveService,
+	inventoryHistoryService *services.InventoryHistoryService,
+	scheduler *jobs.Scheduler,
+	cacheWarmer *services.CacheWarmer,
+	botGuard *middleware.BotGuard,
+	captchaGuard *middleware.CaptchaGuard,
+	loadShedder *middleware.LoadShedder,
+	responseCache *middleware.ResponseCache,
+	circuitBreakers []*breaker.Breaker,
+	inspector *middleware.RequestInspector,
+	logOptions middleware.LoggerOptions,
+	mode string,
+	webhookSecret string,
+	storeCreditAutoApply bool,
+	cacheWarmProducts int,
+	mediaDir string,
 ) *Server {
 	// Set Gin mode
-	gin.SetMode(gin.ReleaseMode)
+	gin.SetMode(mode)
 
 	router := gin.New()
 
-	// Apply global middleware
-	router.Use(middleware.Logger())
+	// Apply global middleware; the inspector goes first so its timing covers the rest
+	if inspector != nil {
+		router.Use(inspector.Record())
+	}
+	router.Use(middleware.LoggerWithOptions(logOptions))
 	router.Use(middleware.Recovery())
 	router.Use(middleware.CORS())
+	if loadShedder != nil {
+		router.Use(loadShedder.Track())
+	}
+	router.Use(middleware.Maintenance(runtimeConfigService))
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	catalogHandler := handlers.NewCatalogHandler(catalogService)
-	cartHandler := handlers.NewCartHandler(cartService)
-	orderHandler := handlers.NewOrderHandler(orderService, cartService)
+	authHandler := handlers.NewAuthHandler(authService).
+		WithLoginSecurity(loginSecurityService).
+		WithGuestSessions(guestSessionService)
+	guestSessionHandler := handlers.NewGuestSessionHandler(guestSessionService)
+	recentlyViewedHandler := handlers.NewRecentlyViewedHandler(recentlyViewedService)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	questionHandler := handlers.NewQuestionHandler(questionService)
+	contentReportHandler := handlers.NewContentReportHandler(contentReportService)
+	companyHandler := handlers.NewCompanyHandler(companyService)
+	quoteHandler := handlers.NewQuoteHandler(quoteService, cartService)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
+	loginSecurityHandler := handlers.NewLoginSecurityHandler(loginSecurityService, authService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService).
+		WithCategoryCounts(categoryCountService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	suggestionHandler := handlers.NewSuggestionHandler(suggestionService)
+	searchRuleHandler := handlers.NewSearchRuleHandler(searchRuleService)
+	productMediaHandler := handlers.NewProductMediaHandler(productMediaService)
+	barcodeHandler := handlers.NewBarcodeHandler(barcodeService)
+	unitPriceHandler := handlers.NewUnitPriceHandler(unitPriceService)
+	variantHandler := handlers.NewVariantHandler(variantService)
+	cartHandler := handlers.NewCartHandler(cartService).
+		WithWaitingRoom(waitingRoomService).
+		WithMetadata(cartMetadataService).
+		WithCheckoutRules(checkoutRuleService).
+		WithOrderBumps(orderBumpService).
+		WithFunnel(funnelService)
+	purchaseLimitHandler := handlers.NewPurchaseLimitHandler(purchaseLimitService)
+	checkoutRuleHandler := handlers.NewCheckoutRuleHandler(checkoutRuleService)
+	orderBumpHandler := handlers.NewOrderBumpHandler(orderBumpService)
+	pricingAnomalyHandler := handlers.NewPricingAnomalyHandler(pricingAnomalyService)
+	var waitingRoomHandler *handlers.WaitingRoomHandler
+	if waitingRoomService != nil {
+		waitingRoomHandler = handlers.NewWaitingRoomHandler(waitingRoomService)
+	}
+	orderHandler := handlers.NewOrderHandler(orderService, cartService).
+		WithDeliveryService(deliveryService).
+		WithAddressService(addressService).
+		WithShippingService(shippingService).
+		WithPaymentService(paymentService).
+		WithPaymentRetryService(paymentRetryService).
+		WithDisputeService(disputeService).
+		WithRefundService(refundService).
+		WithStoreCreditService(storeCreditService, storeCreditAutoApply).
+		WithFulfillmentService(fulfillmentService).
+		WithEventService(orderEventService).
+		WithCartMetadataService(cartMetadataService).
+		WithAttributionService(orderAttributionService).
+		WithCompanyService(companyService).
+		WithQuoteService(quoteService).
+		WithInvoiceService(invoiceService).
+		WithShippingRestrictions(shippingRestrictionService).
+		WithOrderBumps(orderBumpService).
+		WithFunnel(funnelService).
+		WithSupportTickets(supportTicketService)
 	adminHandler := handlers.NewAdminHandler(authService, authStore, authSeeder)
+	deliveryHandler := handlers.NewDeliveryHandler(deliveryService)
+	addressHandler := handlers.NewAddressHandler(addressService)
+	shippingHandler := handlers.NewShippingHandler(shippingService).
+		WithCalendarService(calendarService).
+		WithRestrictions(shippingRestrictionService, cartService)
+	shippingRestrictionHandler := handlers.NewShippingRestrictionHandler(shippingRestrictionService)
+	rentalHandler := handlers.NewRentalHandler(rentalService)
+	appointmentHandler := handlers.NewAppointmentHandler(appointmentService, orderService)
+	donationHandler := handlers.NewDonationHandler(donationService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
+	supportTicketHandler := handlers.NewSupportTicketHandler(supportTicketService, orderService)
+	shippingMethodHandler := handlers.NewShippingMethodHandler(shippingMethodService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	disputeHandler := handlers.NewDisputeHandler(disputeService)
+	refundHandler := handlers.NewRefundHandler(refundService, orderService)
+	orderEventHandler := handlers.NewOrderEventHandler(orderEventService, orderService)
+	orderExportHandler := handlers.NewOrderExportHandler(orderExportService)
+	orderConfirmationHandler := handlers.NewOrderConfirmationHandler(orderService, orderConfirmationService)
+	orderCancellationHandler := handlers.NewOrderCancellationHandler(orderService, orderCancellationService)
+	orderStatusHandler := handlers.NewOrderStatusHandler(orderStatusService)
+	storeCreditHandler := handlers.NewStoreCreditHandler(storeCreditService)
+	consentHandler := handlers.NewConsentHandler(consentService)
+	pageHandler := handlers.NewPageHandler(pageService)
+	placementHandler := handlers.NewPlacementHandler(placementService)
+	botTrafficHandler := handlers.NewBotTrafficHandler(botGuard)
+	loadSheddingHandler := handlers.NewLoadSheddingHandler(loadShedder)
+	circuitBreakerHandler := handlers.NewCircuitBreakerHandler(circuitBreakers)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	quotaHandler := handlers.NewQuotaHandler(quotaService)
+	fulfillmentHandler := handlers.NewFulfillmentHandler(fulfillmentService)
+	posHandler := handlers.NewPOSHandler(posService)
+	attributionHandler := handlers.NewAttributionHandler(orderAttributionService)
+	funnelHandler := handlers.NewFunnelHandler(funnelService)
+	webhookHandler := handlers.NewWebhookHandler(disputeService, webhookSecret)
+	webhookEventHandler := handlers.NewWebhookEventHandler(webhookService)
+	deadLetterHandler := handlers.NewDeadLetterHandler(deadLetterService)
+	systemStatusHandler := handlers.NewSystemStatusHandler(systemStatusService)
+	runtimeConfigHandler := handlers.NewRuntimeConfigHandler(runtimeConfigService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduler)
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	orderArchiveHandler := handlers.NewOrderArchiveHandler(orderArchiveService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryHistoryService)
+	cacheHandler := handlers.NewCacheHandler(catalogService, cacheWarmer, cacheWarmProducts).
+		WithResponseCache(responseCache)
+	catalogHistoryHandler := handlers.NewCatalogHistoryHandler(catalogHistoryService)
+	bulkArchiveHandler := handlers.NewBulkArchiveHandler(bulkArchiveService)
+
+	// Hypermedia links for clients that ask for application/hal+json
+	handlers.RegisterHALSerializers()
+
+	// Stable error codes for the domain errors handlers return
+	handlers.RegisterErrors()
 
 	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(authService).
+		WithGuestSessions(guestSessionService).
+		WithQuotas(quotaService)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(apiKeyService).WithQuotas(quotaService)
 
 	// Register routes
-	setupRoutes(router, authHandler, catalogHandler, cartHandler, orderHandler, adminHandler, authMiddleware)
+	setupRoutes(router, authHandler, loginSecurityHandler, guestSessionHandler, recentlyViewedHandler, customerHandler, reviewHandler, questionHandler, contentReportHandler, companyHandler, quoteHandler, invoiceHandler, catalogHandler, suggestionHandler, searchRuleHandler, productMediaHandler, collectionHandler, barcodeHandler, unitPriceHandler, variantHandler, cartHandler, purchaseLimitHandler, checkoutRuleHandler, orderBumpHandler, pricingAnomalyHandler, waitingRoomHandler, orderHandler, adminHandler, deliveryHandler, addressHandler, shippingHandler, shippingMethodHandler, shippingRestrictionHandler, rentalHandler, appointmentHandler, donationHandler, wishlistHandler, supportTicketHandler, calendarHandler, disputeHandler, refundHandler, orderEventHandler, orderExportHandler, orderConfirmationHandler, orderCancellationHandler, orderStatusHandler, storeCreditHandler, consentHandler, pageHandler, placementHandler, botTrafficHandler, loadSheddingHandler, circuitBreakerHandler, apiKeyHandler, quotaHandler, fulfillmentHandler, posHandler, attributionHandler, funnelHandler, webhookHandler, webhookEventHandler, deadLetterHandler, systemStatusHandler, runtimeConfigHandler, scheduleHandler, retentionHandler, orderArchiveHandler, inventoryHandler, cacheHandler, catalogHistoryHandler, bulkArchiveHandler, authMiddleware, apiKeyMiddleware, botGuard, captchaGuard, loadShedder, responseCache)
+
+	// Uploaded media such as avatars, stored by services.LocalMediaStorage
+	router.Static(services.LocalMediaPath, mediaDir)
+
+	// Development request inspector; never wired in release mode
+	if inspector != nil {
+		inspectorHandler := handlers.NewInspectorHandler(inspector)
+		debug := router.Group("/debug")
+		{
+			debug.GET("/requests", inspectorHandler.ListRequests)
+			debug.GET("/requests/:id", inspectorHandler.GetRequest)
+			debug.DELETE("/requests", inspectorHandler.ClearRequests)
+		}
+	}
 
 	return &Server{
 		router: router,
@@ -55,11 +262,81 @@ func NewServer(
 func setupRoutes(
 	router *gin.Engine,
 	authHandler *handlers.AuthHandler,
+	loginSecurityHandler *handlers.LoginSecurityHandler,
+	guestSessionHandler *handlers.GuestSessionHandler,
+	recentlyViewedHandler *handlers.RecentlyViewedHandler,
+	customerHandler *handlers.CustomerHandler,
+	reviewHandler *handlers.ReviewHandler,
+	questionHandler *handlers.QuestionHandler,
+	contentReportHandler *handlers.ContentReportHandler,
+	companyHandler *handlers.CompanyHandler,
+	quoteHandler *handlers.QuoteHandler,
+	invoiceHandler *handlers.InvoiceHandler,
 	catalogHandler *handlers.CatalogHandler,
+	suggestionHandler *handlers.SuggestionHandler,
+	searchRuleHandler *handlers.SearchRuleHandler,
+	productMediaHandler *handlers.ProductMediaHandler,
+	collectionHandler *handlers.CollectionHandler,
+	barcodeHandler *handlers.BarcodeHandler,
+	unitPriceHandler *handlers.UnitPriceHandler,
+	variantHandler *handlers.VariantHandler,
 	cartHandler *handlers.CartHandler,
+	purchaseLimitHandler *handlers.PurchaseLimitHandler,
+	checkoutRuleHandler *handlers.CheckoutRuleHandler,
+	orderBumpHandler *handlers.OrderBumpHandler,
+	pricingAnomalyHandler *handlers.PricingAnomalyHandler,
+	waitingRoomHandler *handlers.WaitingRoomHandler,
 	orderHandler *handlers.OrderHandler,
 	adminHandler *handlers.AdminHandler,
+	deliveryHandler *handlers.DeliveryHandler,
+	addressHandler *handlers.AddressHandler,
+	shippingHandler *handlers.ShippingHandler,
+	shippingMethodHandler *handlers.ShippingMethodHandler,
+	shippingRestrictionHandler *handlers.ShippingRestrictionHandler,
+	rentalHandler *handlers.RentalHandler,
+	appointmentHandler *handlers.AppointmentHandler,
+	donationHandler *handlers.DonationHandler,
+	wishlistHandler *handlers.WishlistHandler,
+	supportTicketHandler *handlers.SupportTicketHandler,
+	calendarHandler *handlers.CalendarHandler,
+	disputeHandler *handlers.DisputeHandler,
+	refundHandler *handlers.RefundHandler,
+	orderEventHandler *handlers.OrderEventHandler,
+	orderExportHandler *handlers.OrderExportHandler,
+	orderConfirmationHandler *handlers.OrderConfirmationHandler,
+	orderCancellationHandler *handlers.OrderCancellationHandler,
+	orderStatusHandler *handlers.OrderStatusHandler,
+	storeCreditHandler *handlers.StoreCreditHandler,
+	consentHandler *handlers.ConsentHandler,
+	pageHandler *handlers.PageHandler,
+	placementHandler *handlers.PlacementHandler,
+	botTrafficHandler *handlers.BotTrafficHandler,
+	loadSheddingHandler *handlers.LoadSheddingHandler,
+	circuitBreakerHandler *handlers.CircuitBreakerHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	quotaHandler *handlers.QuotaHandler,
+	fulfillmentHandler *handlers.FulfillmentHandler,
+	posHandler *handlers.POSHandler,
+	attributionHandler *handlers.AttributionHandler,
+	funnelHandler *handlers.FunnelHandler,
+	webhookHandler *handlers.WebhookHandler,
+	webhookEventHandler *handlers.WebhookEventHandler,
+	deadLetterHandler *handlers.DeadLetterHandler,
+	systemStatusHandler *handlers.SystemStatusHandler,
+	runtimeConfigHandler *handlers.RuntimeConfigHandler,
+	scheduleHandler *handlers.ScheduleHandler,
+	retentionHandler *handlers.RetentionHandler,
+	orderArchiveHandler *handlers.OrderArchiveHandler,
+	inventoryHandler *handlers.InventoryHandler,
+	cacheHandler *handlers.CacheHandler,
+	catalogHistoryHandler *handlers.CatalogHistoryHandler,
+	bulkArchiveHandler *handlers.BulkArchiveHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	apiKeyMiddleware *middleware.APIKeyMiddleware,
+	botGuard *middleware.BotGuard,
+	captchaGuard *middleware.CaptchaGuard,
+	loadShedder *middleware.LoadShedder,
+	responseCache *middleware.ResponseCache,
 ) {
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -68,11 +345,12 @@ func setupRoutes(
 
 	// API v1 group
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.APIVersion(middleware.APIVersion1))
 
 	// Auth routes (public)
 	auth := v1.Group("/auth")
 	{
-		auth.POST("/register", authHandler.Register)
+		auth.POST("/register", captchaGuard.Require(middleware.CaptchaRouteRegister), authHandler.Register)
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/refresh", authHandler.RefreshToken)
 
@@ -89,34 +367,259 @@ func setupRoutes(
 		}
 	}
 
-	// Catalog routes (public)
+	// Customer profile routes (protected); profile fields are kept apart from goauthx credentials
+	me := v1.Group("/me")
+	me.Use(authMiddleware.Authenticate())
+	{
+		me.GET("", customerHandler.GetProfile)
+		me.PATCH("", customerHandler.UpdateProfile)
+		me.PUT("/avatar", customerHandler.UploadAvatar)
+		me.DELETE("/avatar", customerHandler.DeleteAvatar)
+
+		// Order history exports; large ones run in the background and are fetched by link
+		me.GET("/orders/export", orderExportHandler.ExportOrders)
+		me.GET("/orders/exports/:id", orderExportHandler.GetOrderExport)
+	}
+
+	// Signed download links of order exports (public; the signature authorizes the download)
+	v1.GET("/order-exports/:id/download", orderExportHandler.DownloadOrderExport)
+
+	// Company account routes (protected); members share the address book and order history
+	company := v1.Group("/company")
+	company.Use(authMiddleware.Authenticate())
+	{
+		company.GET("", companyHandler.GetMyCompany)
+		company.GET("/members", companyHandler.ListMyCompanyMembers)
+		company.GET("/addresses", companyHandler.ListCompanyAddresses)
+		company.POST("/addresses", companyHandler.CreateCompanyAddress)
+		company.PUT("/addresses/:id", companyHandler.UpdateCompanyAddress)
+		company.DELETE("/addresses/:id", companyHandler.DeleteCompanyAddress)
+		company.GET("/orders", companyHandler.ListCompanyOrders)
+		company.GET("/orders/:id", companyHandler.GetCompanyOrder)
+		company.POST("/orders/:id/approve", companyHandler.ApproveCompanyOrder)
+		company.POST("/orders/:id/reject", companyHandler.RejectCompanyOrder)
+		company.GET("/invoices", invoiceHandler.ListCompanyInvoices)
+	}
+
+	// Quote routes (protected); accept a sent quote with its quote_id on POST /orders
+	quotes := v1.Group("/quotes")
+	quotes.Use(authMiddleware.Authenticate())
+	{
+		quotes.POST("", quoteHandler.RequestQuote)
+		quotes.GET("", quoteHandler.ListQuotes)
+		quotes.GET("/:id", quoteHandler.GetQuote)
+		quotes.POST("/:id/decline", quoteHandler.DeclineQuote)
+	}
+
+	// Guest session routes (public); the token lets guests use the cart, checkout and
+	// recently viewed products until they register or sign in
+	v1.POST("/guest-sessions", guestSessionHandler.CreateGuestSession)
+
+	// Recently viewed products (signed-in users or guests)
+	recentlyViewed := v1.Group("/recently-viewed")
+	recentlyViewed.Use(authMiddleware.AuthenticateOrGuest())
+	{
+		recentlyViewed.GET("", recentlyViewedHandler.ListRecentlyViewed)
+		recentlyViewed.POST("", recentlyViewedHandler.RecordView)
+	}
+
+	// Public catalog responses are cached and tagged with surrogate keys for the CDN when a
+	// response cache is configured; admin changes purge the keys they affect
+	cached := func(keys middleware.SurrogateKeyFunc) gin.HandlerFunc {
+		if responseCache == nil {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return responseCache.Cache(keys)
+	}
+	purges := func(keys middleware.SurrogateKeyFunc) gin.HandlerFunc {
+		if responseCache == nil {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return responseCache.PurgeOnSuccess(keys)
+	}
+	products := middleware.SurrogateKeyProducts
+
+	// Catalog routes (public); browsing is the first traffic shed under load
 	catalog := v1.Group("/catalog")
+	if loadShedder != nil {
+		catalog.Use(loadShedder.Shed())
+	}
+	if botGuard != nil {
+		catalog.Use(botGuard.Protect())
+	}
+	{
+		catalog.GET("/products", cached(middleware.SurrogateKeys(products)), catalogHandler.ListProducts)
+		catalog.GET("/search/suggest", suggestionHandler.Suggest)
+		catalog.GET("/products/:id", cached(middleware.SurrogateKeyParam("product", "id", products)), catalogHandler.GetProduct)
+		catalog.GET("/products/:id/variants", cached(middleware.SurrogateKeyParam("product", "id", products)), unitPriceHandler.ListProductVariants)
+		catalog.GET("/products/:id/variant-options", cached(middleware.SurrogateKeyParam("product", "id", products)), variantHandler.ListVariantOptions)
+		catalog.GET("/products/category/:id", cached(middleware.SurrogateKeyParam("category", "id", products)), catalogHandler.GetProductsByCategory)
+		catalog.GET("/categories", cached(middleware.SurrogateKeys("categories")), catalogHandler.ListCategories)
+		catalog.GET("/categories/tree", cached(middleware.SurrogateKeys("categories")), catalogHandler.GetCategoryTree)
+		catalog.GET("/brands", cached(middleware.SurrogateKeys("brands")), catalogHandler.ListBrands)
+		catalog.GET("/collections/:slug", cached(middleware.SurrogateKeyParam("collection", "slug", "collections")), collectionHandler.GetPublishedCollection)
+		catalog.GET("/collections/:slug/products", cached(middleware.SurrogateKeyParam("collection", "slug", "collections", products)), collectionHandler.GetCollectionProducts)
+		catalog.GET("/variants/barcode/:code", barcodeHandler.LookupBarcode)
+		catalog.GET("/products/:id/rental-calendar", rentalHandler.GetRentalCalendar)
+		catalog.GET("/products/:id/appointment-slots", appointmentHandler.ListAppointmentSlots)
+		catalog.GET("/products/:id/donation", donationHandler.GetDonation)
+		catalog.GET("/products/:id/reviews", reviewHandler.ListProductReviews)
+		catalog.GET("/products/:id/questions", questionHandler.ListProductQuestions)
+	}
+
+	// Content page routes (public)
+	content := v1.Group("/content")
+	if loadShedder != nil {
+		content.Use(loadShedder.Shed())
+	}
+	{
+		content.GET("/pages/:slug", pageHandler.GetPublishedPage)
+		content.GET("/placements/:slot", placementHandler.GetSlotPlacements)
+	}
+
+	// Wishlist routes (protected); public wishlists are shared as gift registries
+	wishlists := v1.Group("/wishlists")
+	wishlists.Use(authMiddleware.Authenticate())
 	{
-		catalog.GET("/products", catalogHandler.ListProducts)
-		catalog.GET("/products/:id", catalogHandler.GetProduct)
-		catalog.GET("/products/category/:id", catalogHandler.GetProductsByCategory)
-		catalog.GET("/categories", catalogHandler.ListCategories)
-		catalog.GET("/brands", catalogHandler.ListBrands)
+		wishlists.GET("", wishlistHandler.ListWishlists)
+		wishlists.POST("", wishlistHandler.CreateWishlist)
+		wishlists.GET("/:id", wishlistHandler.GetWishlist)
+		wishlists.PUT("/:id", wishlistHandler.UpdateWishlist)
+		wishlists.DELETE("/:id", wishlistHandler.DeleteWishlist)
+		wishlists.POST("/:id/items", wishlistHandler.AddWishlistItem)
+		wishlists.PATCH("/:id/items/:itemId", wishlistHandler.UpdateWishlistItem)
+		wishlists.DELETE("/:id/items/:itemId", wishlistHandler.RemoveWishlistItem)
 	}
 
-	// Cart routes (protected)
+	// Gift registries (public; the share token grants access). Guests buy an item for the
+	// owner by adding it to their cart with its wishlist_item_id.
+	v1.GET("/registries/:token", wishlistHandler.GetRegistry)
+
+	// Cart routes (signed-in users or guests)
 	cart := v1.Group("/cart")
-	cart.Use(authMiddleware.Authenticate())
+	cart.Use(authMiddleware.AuthenticateOrGuest())
 	{
 		cart.GET("", cartHandler.GetCart)
+		cart.GET("/validate", cartHandler.ValidateCart)
 		cart.POST("/items", cartHandler.AddItem)
 		cart.PATCH("/items/:id", cartHandler.UpdateItemQuantity)
 		cart.DELETE("/items/:id", cartHandler.RemoveItem)
+		cart.PUT("/items/:id/metadata", cartHandler.SetItemMetadata)
+		cart.GET("/metadata", cartHandler.GetCartMetadata)
+		cart.PUT("/metadata", cartHandler.SetCartMetadata)
 		cart.DELETE("", cartHandler.ClearCart)
 	}
 
-	// Order routes (protected)
+	// Waiting room routes for limited drops (protected); only registered when the waiting room is enabled
+	if waitingRoomHandler != nil {
+		waitingRoom := v1.Group("/waiting-room")
+		waitingRoom.Use(authMiddleware.Authenticate())
+		{
+			waitingRoom.POST("/:id", waitingRoomHandler.JoinWaitingRoom)
+			waitingRoom.GET("/:id", waitingRoomHandler.GetWaitingRoomStatus)
+		}
+	}
+
+	// Order routes (signed-in users or guests)
 	orders := v1.Group("/orders")
-	orders.Use(authMiddleware.Authenticate())
+	orders.Use(authMiddleware.AuthenticateOrGuest())
 	{
-		orders.POST("", orderHandler.CreateOrder)
+		MountCreateOrder(orders, captchaGuard, orderHandler.CreateOrder)
 		orders.GET("", orderHandler.ListOrders)
 		orders.GET("/:id", orderHandler.GetOrder)
+		orders.GET("/:id/payments", orderHandler.GetOrderPayments)
+		orders.POST("/:id/retry-payment", orderHandler.RetryPayment)
+		orders.POST("/:id/resend-confirmation", orderConfirmationHandler.ResendConfirmation)
+		orders.POST("/:id/cancel", orderCancellationHandler.CancelOrder)
+		orders.GET("/:id/appointments", appointmentHandler.GetOrderAppointments)
+		orders.GET("/:id/tickets", supportTicketHandler.GetOrderTickets)
+		orders.POST("/:id/tickets", supportTicketHandler.OpenTicket)
+	}
+
+	// Review routes (protected); reviews are shown on the product once a moderator approves them
+	reviews := v1.Group("/reviews")
+	reviews.Use(authMiddleware.Authenticate())
+	{
+		reviews.POST("", reviewHandler.WriteReview)
+		reviews.GET("", reviewHandler.ListMyReviews)
+		reviews.GET("/:id", reviewHandler.GetReview)
+		reviews.POST("/:id/photos", reviewHandler.AddReviewPhoto)
+		reviews.DELETE("/:id/photos/:photoId", reviewHandler.RemoveReviewPhoto)
+		reviews.POST("/:id/report", contentReportHandler.ReportReview)
+	}
+
+	// Product Q&A routes (protected); questions and answers are shown as soon as they are
+	// posted and hidden once reported often enough
+	questions := v1.Group("/questions")
+	questions.Use(authMiddleware.Authenticate())
+	{
+		questions.POST("", questionHandler.AskQuestion)
+		questions.GET("/:id", questionHandler.GetQuestion)
+		questions.POST("/:id/answers", questionHandler.AnswerQuestion)
+		questions.POST("/:id/report", contentReportHandler.ReportQuestion)
+	}
+	answers := v1.Group("/answers")
+	answers.Use(authMiddleware.Authenticate())
+	{
+		answers.POST("/:id/report", contentReportHandler.ReportAnswer)
+	}
+
+	// Support ticket routes (signed-in users or guests); tickets are opened against an order
+	tickets := v1.Group("/tickets")
+	tickets.Use(authMiddleware.AuthenticateOrGuest())
+	{
+		tickets.GET("", supportTicketHandler.ListMyTickets)
+		tickets.GET("/:id", supportTicketHandler.GetTicket)
+		tickets.POST("/:id/messages", supportTicketHandler.ReplyToTicket)
+	}
+
+	// Store credit routes (protected)
+	storeCredit := v1.Group("/store-credit")
+	storeCredit.Use(authMiddleware.Authenticate())
+	{
+		storeCredit.GET("", storeCreditHandler.GetStoreCredit)
+		storeCredit.GET("/transactions", storeCreditHandler.ListStoreCreditTransactions)
+	}
+
+	// Marketing and analytics consent (protected)
+	consents := v1.Group("/consents")
+	consents.Use(authMiddleware.Authenticate())
+	{
+		consents.GET("", consentHandler.GetConsents)
+		consents.POST("", consentHandler.RecordConsents)
+	}
+
+	// Shipping estimates for a destination before checkout (public)
+	v1.GET("/shipping/methods", shippingHandler.ListShippingMethods)
+
+	// Checkout routes (signed-in users or guests)
+	checkout := v1.Group("/checkout")
+	checkout.Use(authMiddleware.AuthenticateOrGuest())
+	{
+		checkout.GET("/delivery-slots", deliveryHandler.ListDeliverySlots)
+		checkout.POST("/address/validate", addressHandler.ValidateAddress)
+		checkout.GET("/shipping-rates", shippingHandler.ListShippingRates)
+	}
+
+	// Webhook routes (public - verified by signature)
+	webhooks := v1.Group("/webhooks")
+	{
+		webhooks.POST("/payments/disputes", webhookHandler.HandleDisputeWebhook)
+		webhooks.POST("/:provider", webhookEventHandler.ReceiveWebhook)
+	}
+
+	// Fulfillment routes for 3PL warehouses (authenticated by scoped API key)
+	fulfillment := v1.Group("/fulfillment")
+	{
+		fulfillment.GET("/orders", apiKeyMiddleware.RequireScope(services.APIKeyScopeFulfillmentRead), fulfillmentHandler.ListOpenOrders)
+		fulfillment.POST("/orders/:id/shipments", apiKeyMiddleware.RequireScope(services.APIKeyScopeFulfillmentWrite), fulfillmentHandler.ConfirmShipment)
+	}
+
+	// POS routes for in-store registers (authenticated by scoped API key)
+	pos := v1.Group("/pos")
+	{
+		pos.POST("/sales", apiKeyMiddleware.RequireScope(services.APIKeyScopePOSWrite), posHandler.CreateSale)
+		pos.GET("/registers/:id/summary", apiKeyMiddleware.RequireScope(services.APIKeyScopePOSRead), posHandler.GetRegisterSummary)
 	}
 
 	// Admin routes (protected - requires admin, manager, or customer_experience role)
@@ -155,8 +658,412 @@ func setupRoutes(
 			users.GET("/:id/roles", adminHandler.GetUserRoles)
 			users.POST("/:id/roles", adminHandler.AssignRoleToUser)
 			users.DELETE("/:id/roles/:roleId", adminHandler.RemoveRoleFromUser)
+
+			// Failed login lockouts
+			users.GET("/:id/lockout", loginSecurityHandler.GetUserLockout)
+			users.POST("/:id/unlock", loginSecurityHandler.UnlockUser)
+
+			// Store credit wallets
+			users.GET("/:id/store-credit", storeCreditHandler.GetUserStoreCredit)
+			users.POST("/:id/store-credit", storeCreditHandler.GrantStoreCredit)
+			users.GET("/:id/store-credit/transactions", storeCreditHandler.ListUserStoreCreditTransactions)
+
+			// Consent history
+			users.GET("/:id/consents", consentHandler.ListUserConsents)
+		}
+
+		// Delivery slot capacity management
+		admin.POST("/delivery-slots", deliveryHandler.CreateDeliverySlot)
+
+		// Appointment slots and their bookings
+		admin.GET("/appointment-slots/:id", appointmentHandler.GetSlot)
+		admin.PUT("/appointment-slots/:id", appointmentHandler.UpdateSlot)
+		admin.GET("/appointment-slots/:id/bookings", appointmentHandler.ListSlotBookings)
+
+		// Shipping zones and rates
+		shippingZones := admin.Group("/shipping-zones")
+		{
+			shippingZones.GET("", shippingHandler.ListShippingZones)
+			shippingZones.POST("", shippingHandler.CreateShippingZone)
+			shippingZones.GET("/:id", shippingHandler.GetShippingZone)
+			shippingZones.PUT("/:id", shippingHandler.UpdateShippingZone)
+			shippingZones.DELETE("/:id", shippingHandler.DeleteShippingZone)
+		}
+
+		// Flat and table-rate shipping methods for destinations no zone covers
+		shippingMethods := admin.Group("/shipping-methods")
+		{
+			shippingMethods.GET("", shippingMethodHandler.ListShippingMethods)
+			shippingMethods.POST("", shippingMethodHandler.CreateShippingMethod)
+			shippingMethods.GET("/:id", shippingMethodHandler.GetShippingMethod)
+			shippingMethods.PUT("/:id", shippingMethodHandler.UpdateShippingMethod)
+			shippingMethods.DELETE("/:id", shippingMethodHandler.DeleteShippingMethod)
+		}
+
+		// Business calendar: working days, order cutoff and holidays
+		calendar := admin.Group("/calendar")
+		{
+			calendar.GET("", calendarHandler.GetCalendar)
+			calendar.PUT("", calendarHandler.UpdateCalendar)
+			calendar.POST("/holidays", calendarHandler.AddHoliday)
+			calendar.DELETE("/holidays/:date", calendarHandler.DeleteHoliday)
+		}
+
+		// Content pages
+		pages := admin.Group("/pages")
+		{
+			pages.GET("", pageHandler.ListPages)
+			pages.POST("", pageHandler.CreatePage)
+			pages.GET("/:id", pageHandler.GetPage)
+			pages.PUT("/:id", pageHandler.UpdatePage)
+			pages.DELETE("/:id", pageHandler.DeletePage)
+		}
+
+		// Curated product collections
+		collections := admin.Group("/collections")
+		collections.Use(purges(middleware.SurrogateKeys("collections")))
+		{
+			collections.GET("", collectionHandler.ListCollections)
+			collections.POST("", collectionHandler.CreateCollection)
+			collections.GET("/:id", collectionHandler.GetCollection)
+			collections.PUT("/:id", collectionHandler.UpdateCollection)
+			collections.DELETE("/:id", collectionHandler.DeleteCollection)
+		}
+
+		// Company accounts and their members
+		companies := admin.Group("/companies")
+		{
+			companies.GET("", companyHandler.ListCompanies)
+			companies.POST("", companyHandler.CreateCompany)
+			companies.GET("/:id", companyHandler.GetCompany)
+			companies.PUT("/:id", companyHandler.UpdateCompany)
+			companies.PUT("/:id/members/:userId", companyHandler.SetCompanyMember)
+			companies.DELETE("/:id/members/:userId", companyHandler.RemoveCompanyMember)
+		}
+
+		// Quote requests priced by sales
+		adminQuotes := admin.Group("/quotes")
+		{
+			adminQuotes.GET("", quoteHandler.AdminListQuotes)
+			adminQuotes.GET("/:id", quoteHandler.AdminGetQuote)
+			adminQuotes.POST("/:id/send", quoteHandler.SendQuote)
+		}
+
+		// Accounts receivable for orders paid on company net terms
+		invoices := admin.Group("/invoices")
+		{
+			invoices.GET("", invoiceHandler.ListInvoices)
+			invoices.GET("/overdue", invoiceHandler.GetOverdueInvoices)
+			invoices.GET("/:id", invoiceHandler.GetInvoice)
+			invoices.POST("/:id/payments", invoiceHandler.RecordInvoicePayment)
+		}
+
+		// Search synonyms and pinned/boosted products per query
+		search := admin.Group("/search")
+		search.Use(purges(middleware.SurrogateKeys("search")))
+		{
+			search.GET("/synonyms", searchRuleHandler.ListSynonymSets)
+			search.POST("/synonyms", searchRuleHandler.CreateSynonymSet)
+			search.GET("/synonyms/:id", searchRuleHandler.GetSynonymSet)
+			search.PUT("/synonyms/:id", searchRuleHandler.UpdateSynonymSet)
+			search.DELETE("/synonyms/:id", searchRuleHandler.DeleteSynonymSet)
+			search.GET("/rules", searchRuleHandler.ListSearchRules)
+			search.POST("/rules", searchRuleHandler.CreateSearchRule)
+			search.GET("/rules/:id", searchRuleHandler.GetSearchRule)
+			search.PUT("/rules/:id", searchRuleHandler.UpdateSearchRule)
+			search.DELETE("/rules/:id", searchRuleHandler.DeleteSearchRule)
+		}
+
+		// Banner and promo tile placements
+		placements := admin.Group("/placements")
+		{
+			placements.GET("", placementHandler.ListPlacements)
+			placements.POST("", placementHandler.CreatePlacement)
+			placements.GET("/:id", placementHandler.GetPlacement)
+			placements.PUT("/:id", placementHandler.UpdatePlacement)
+			placements.DELETE("/:id", placementHandler.DeletePlacement)
+		}
+
+		// Integration API keys (admin only)
+		apiKeys := admin.Group("/api-keys")
+		apiKeys.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+			apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+		}
+
+		// Daily and monthly request quotas of API keys and user roles (admin only)
+		quotas := admin.Group("/quotas")
+		quotas.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			quotas.GET("", quotaHandler.ListQuotaTiers)
+			quotas.GET("/usage", quotaHandler.ListQuotaUsage)
+			quotas.GET("/api-keys/:id", quotaHandler.GetAPIKeyQuota)
+			quotas.PUT("/api-keys/:id/tier", quotaHandler.SetAPIKeyQuotaTier)
+			quotas.DELETE("/api-keys/:id", quotaHandler.ResetAPIKeyQuota)
+			quotas.GET("/users/:id", quotaHandler.GetUserQuota)
+			quotas.DELETE("/users/:id", quotaHandler.ResetUserQuota)
+		}
+
+		// Inbound webhook event log and replay (admin only)
+		webhookEvents := admin.Group("/webhooks/events")
+		webhookEvents.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			webhookEvents.GET("", webhookEventHandler.ListWebhookEvents)
+			webhookEvents.GET("/:id", webhookEventHandler.GetWebhookEvent)
+			webhookEvents.POST("/:id/replay", webhookEventHandler.ReplayWebhookEvent)
+		}
+
+		// Failed background jobs and their replay (admin only)
+		deadLetters := admin.Group("/dead-letters")
+		deadLetters.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			deadLetters.GET("", deadLetterHandler.ListDeadLetters)
+			deadLetters.GET("/:id", deadLetterHandler.GetDeadLetter)
+			deadLetters.POST("/:id/replay", deadLetterHandler.ReplayDeadLetter)
+		}
+
+		// Recurring task schedules and manual runs (admin only)
+		schedules := admin.Group("/schedules")
+		schedules.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			schedules.GET("", scheduleHandler.ListSchedules)
+			schedules.POST("/:name/run", scheduleHandler.RunSchedule)
+		}
+
+		// Queue depths, failures, cache hit rates and database health for the ops dashboard (admin only)
+		system := admin.Group("/system")
+		system.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			system.GET("/status", systemStatusHandler.GetSystemStatus)
+		}
+
+		// Runtime settings: view, reload without a restart and the audit log of changes (admin only)
+		runtimeConfig := admin.Group("/config")
+		runtimeConfig.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			runtimeConfig.GET("", runtimeConfigHandler.GetRuntimeConfig)
+			runtimeConfig.POST("/reload", runtimeConfigHandler.ReloadConfig)
+			runtimeConfig.GET("/changes", runtimeConfigHandler.ListConfigChanges)
+		}
+
+		// Data retention dry run; the rules themselves run as the data-retention task (admin only)
+		retention := admin.Group("/retention")
+		retention.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			retention.GET("", retentionHandler.GetRetentionReport)
+		}
+
+		// Order archival dry run; orders are archived by the order-archive task (admin only)
+		orderArchive := admin.Group("/order-archive")
+		orderArchive.Use(authMiddleware.RequireRole(string(goauthx.RoleAdmin)))
+		{
+			orderArchive.GET("", orderArchiveHandler.GetOrderArchiveReport)
+		}
+
+		// Daily stock snapshots and stock movements
+		inventory := admin.Group("/inventory")
+		{
+			inventory.GET("/:sku/history", inventoryHandler.GetStockHistory)
+		}
+
+		// End-of-day register summaries for in-store sales
+		admin.GET("/pos/registers/:id/summary", posHandler.GetRegisterSummary)
+
+		// Revenue per order channel and UTM campaign
+		admin.GET("/reports/channels", attributionHandler.GetChannelReport)
+
+		// Checkout funnel conversion against its baseline, and drop-off alerts
+		admin.GET("/reports/funnel", funnelHandler.GetFunnel)
+		admin.GET("/reports/funnel/alerts", funnelHandler.ListFunnelAlerts)
+
+		// Catalog caches
+		cache := admin.Group("/cache")
+		{
+			cache.GET("", cacheHandler.GetCacheStats)
+			cache.DELETE("", purges(middleware.SurrogateKeys(middleware.SurrogateKeyAll)), cacheHandler.FlushCaches)
+			cache.POST("/warm", cacheHandler.WarmCaches)
+			cache.DELETE("/products", purges(middleware.SurrogateKeys(products)), cacheHandler.FlushProducts)
+			cache.DELETE("/products/:id", purges(middleware.SurrogateKeyParam("product", "id")), cacheHandler.InvalidateProduct)
+		}
+
+		// Bulk archives run in batches on the job runner; the job reports their progress.
+		// Archived products are purged from the response cache as each batch lands.
+		admin.POST("/catalog/products/archive", bulkArchiveHandler.ArchiveProducts)
+		admin.POST("/catalog/variants/archive", bulkArchiveHandler.ArchiveVariants)
+		admin.GET("/catalog/bulk-jobs", bulkArchiveHandler.ListBulkJobs)
+		admin.GET("/catalog/bulk-jobs/:id", bulkArchiveHandler.GetBulkJob)
+
+		// Product and variant change history, with revert for accidental edits
+		catalogProducts := admin.Group("/catalog/products")
+		catalogProducts.Use(purges(middleware.SurrogateKeyParam("product", "id")))
+		{
+			catalogProducts.GET("/:id/history", catalogHistoryHandler.ListProductHistory)
+			catalogProducts.GET("/:id/history/:version", catalogHistoryHandler.GetProductVersion)
+			catalogProducts.POST("/:id/history/:version/revert", catalogHistoryHandler.RevertProduct)
+
+			// Purchase limits for limited drops
+			catalogProducts.GET("/:id/purchase-limit", purchaseLimitHandler.GetPurchaseLimit)
+			catalogProducts.PUT("/:id/purchase-limit", purchaseLimitHandler.SetPurchaseLimit)
+			catalogProducts.DELETE("/:id/purchase-limit", purchaseLimitHandler.DeletePurchaseLimit)
+
+			// Hazmat and regulated product shipping restrictions
+			catalogProducts.GET("/:id/shipping-restriction", shippingRestrictionHandler.GetShippingRestriction)
+			catalogProducts.PUT("/:id/shipping-restriction", shippingRestrictionHandler.SetShippingRestriction)
+			catalogProducts.DELETE("/:id/shipping-restriction", shippingRestrictionHandler.DeleteShippingRestriction)
+
+			// Rental mode: units to rent by the day, rental length and turnaround buffer
+			catalogProducts.GET("/:id/rental", rentalHandler.GetRental)
+			catalogProducts.PUT("/:id/rental", rentalHandler.SetRental)
+			catalogProducts.DELETE("/:id/rental", rentalHandler.DeleteRental)
+
+			// Appointment products: bookable time slots with a number of seats each
+			catalogProducts.GET("/:id/appointment", appointmentHandler.GetAppointmentProduct)
+			catalogProducts.PUT("/:id/appointment", appointmentHandler.SetAppointmentProduct)
+			catalogProducts.DELETE("/:id/appointment", appointmentHandler.DeleteAppointmentProduct)
+			catalogProducts.GET("/:id/appointment-slots", appointmentHandler.ListSlots)
+			catalogProducts.POST("/:id/appointment-slots", appointmentHandler.CreateSlot)
+
+			// Donation and pay-what-you-want products: the amounts customers may choose
+			catalogProducts.GET("/:id/donation", donationHandler.GetDonation)
+			catalogProducts.PUT("/:id/donation", donationHandler.SetDonation)
+			catalogProducts.DELETE("/:id/donation", donationHandler.DeleteDonation)
+
+			// Tags used by rule-based collections
+			catalogProducts.GET("/:id/tags", collectionHandler.GetProductTags)
+			catalogProducts.PUT("/:id/tags", purges(middleware.SurrogateKeys("collections")), collectionHandler.SetProductTags)
+
+			// Ordered galleries of images, videos and 3D models
+			catalogProducts.GET("/:id/media", productMediaHandler.GetProductMedia)
+			catalogProducts.PUT("/:id/media", productMediaHandler.SetProductMedia)
+
+			// Variants (SKUs by size, color and other attributes)
+			catalogProducts.POST("/:id/variants", purges(middleware.SurrogateKeys(products)), variantHandler.CreateVariant)
+		}
+
+		// Variants of products, each with its own SKU, price and attributes
+		catalogVariants := admin.Group("/catalog/variants")
+		{
+			catalogVariants.GET("/:id", variantHandler.GetVariant)
+			catalogVariants.PUT("/:id", purges(middleware.SurrogateKeys(products)), variantHandler.UpdateVariant)
+			catalogVariants.DELETE("/:id", purges(middleware.SurrogateKeys(products)), variantHandler.DeleteVariant)
+
+			// Variant GTIN/EAN barcodes for POS and warehouse scanners
+			catalogVariants.GET("/:id/barcode", barcodeHandler.GetVariantBarcode)
+			catalogVariants.PUT("/:id/barcode", barcodeHandler.SetVariantBarcode)
+			catalogVariants.DELETE("/:id/barcode", barcodeHandler.DeleteVariantBarcode)
+
+			// Variant contents for unit prices (price per kg, litre, metre or square metre)
+			catalogVariants.GET("/:id/unit", unitPriceHandler.GetVariantUnit)
+			catalogVariants.PUT("/:id/unit", purges(middleware.SurrogateKeys(products)), unitPriceHandler.SetVariantUnit)
+			catalogVariants.DELETE("/:id/unit", purges(middleware.SurrogateKeys(products)), unitPriceHandler.DeleteVariantUnit)
+		}
+		admin.GET("/purchase-limits", purchaseLimitHandler.ListPurchaseLimits)
+
+		// Checkout rules: minimum order value, restricted shipping countries and quantity multiples
+		checkoutRules := admin.Group("/checkout-rules")
+		{
+			checkoutRules.GET("", checkoutRuleHandler.ListCheckoutRules)
+			checkoutRules.POST("", checkoutRuleHandler.CreateCheckoutRule)
+			checkoutRules.GET("/:id", checkoutRuleHandler.GetCheckoutRule)
+			checkoutRules.PUT("/:id", checkoutRuleHandler.UpdateCheckoutRule)
+			checkoutRules.DELETE("/:id", checkoutRuleHandler.DeleteCheckoutRule)
+		}
+
+		// Order bumps: add-ons offered at checkout at a special price
+		orderBumps := admin.Group("/order-bumps")
+		{
+			orderBumps.GET("", orderBumpHandler.ListOrderBumps)
+			orderBumps.POST("", orderBumpHandler.CreateOrderBump)
+			orderBumps.GET("/:id", orderBumpHandler.GetOrderBump)
+			orderBumps.PUT("/:id", orderBumpHandler.UpdateOrderBump)
+			orderBumps.DELETE("/:id", orderBumpHandler.DeleteOrderBump)
+		}
+
+		// Suspicious price changes and orders held for review
+		pricingAnomalies := admin.Group("/pricing-anomalies")
+		{
+			pricingAnomalies.GET("", pricingAnomalyHandler.ListPricingAnomalies)
+			pricingAnomalies.GET("/:id", pricingAnomalyHandler.GetPricingAnomaly)
+			pricingAnomalies.POST("/:id/approve", purges(middleware.SurrogateKeys(products)), pricingAnomalyHandler.ApprovePricingAnomaly)
+			pricingAnomalies.POST("/:id/reject", pricingAnomalyHandler.RejectPricingAnomaly)
+		}
+
+		// Bot traffic turned away from the catalog
+		admin.GET("/bot-traffic", botTrafficHandler.GetBotTraffic)
+		admin.GET("/load-shedding", loadSheddingHandler.GetLoadShedding)
+		admin.GET("/circuit-breakers", circuitBreakerHandler.ListCircuitBreakers)
+
+		// Order refunds, cancellations, status changes, timeline and confirmation emails
+		adminOrders := admin.Group("/orders")
+		{
+			adminOrders.GET("/:id/refunds", refundHandler.ListRefunds)
+			adminOrders.POST("/:id/refunds", refundHandler.CreateRefund)
+			adminOrders.GET("/:id/timeline", orderEventHandler.GetTimeline)
+			adminOrders.POST("/:id/notes", orderEventHandler.AddNote)
+			adminOrders.POST("/:id/resend-confirmation", orderConfirmationHandler.ResendConfirmation)
+			adminOrders.POST("/:id/cancel", orderCancellationHandler.CancelOrder)
+			adminOrders.PATCH("/:id/status", orderStatusHandler.ChangeOrderStatus)
+			adminOrders.GET("/:id/status-history", orderStatusHandler.GetStatusHistory)
+			adminOrders.GET("/:id/appointments", appointmentHandler.GetOrderAppointments)
+			adminOrders.GET("/:id/tickets", supportTicketHandler.GetOrderTickets)
+		}
+
+		// Support ticket queue
+		supportTickets := admin.Group("/tickets")
+		{
+			supportTickets.GET("", supportTicketHandler.ListTickets)
+			supportTickets.GET("/:id", supportTicketHandler.GetTicket)
+			supportTickets.POST("/:id/messages", supportTicketHandler.StaffReplyToTicket)
+			supportTickets.PATCH("/:id/status", supportTicketHandler.SetTicketStatus)
+		}
+
+		// Review moderation queue
+		adminReviews := admin.Group("/reviews")
+		{
+			adminReviews.GET("", reviewHandler.ListReviews)
+			adminReviews.POST("/bulk-status", reviewHandler.BulkModerateReviews)
+			adminReviews.GET("/:id", reviewHandler.GetReviewForModeration)
+			adminReviews.PATCH("/:id/status", reviewHandler.ModerateReview)
+			adminReviews.DELETE("/:id/photos/:photoId", reviewHandler.ModeratorRemoveReviewPhoto)
+		}
+
+		// Reported reviews, questions and answers
+		contentReports := admin.Group("/content-reports")
+		{
+			contentReports.GET("", contentReportHandler.ListReportedContent)
+			contentReports.POST("/:type/:id/resolve", contentReportHandler.ResolveReports)
+		}
+
+		// Chargeback dispute queue
+		disputes := admin.Group("/disputes")
+		{
+			disputes.GET("", disputeHandler.ListDisputes)
+			disputes.GET("/:id", disputeHandler.GetDispute)
+			disputes.POST("/:id/evidence", disputeHandler.SubmitEvidence)
 		}
 	}
+
+	// API v2 group. Endpoints are versioned individually: only those whose response shape
+	// changed are mounted here, and everything else stays current under /api/v1.
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion(middleware.APIVersion2))
+
+	// Order routes (protected) - normalized order and line item shape
+	v2Orders := v2.Group("/orders")
+	v2Orders.Use(authMiddleware.Authenticate())
+	{
+		MountCreateOrder(v2Orders, captchaGuard, orderHandler.CreateOrder)
+		v2Orders.GET("", orderHandler.ListOrders)
+		v2Orders.GET("/:id", orderHandler.GetOrder)
+	}
+}
+
+// MountCreateOrder registers the route placing orders on an API version's orders group.
+// Every version mounts checkout through it, so none can skip the CAPTCHA challenge.
+func MountCreateOrder(orders *gin.RouterGroup, captchaGuard *middleware.CaptchaGuard, createOrder gin.HandlerFunc) {
+	orders.POST("", captchaGuard.Require(middleware.CaptchaRouteCheckout), createOrder)
 }
 
 // Router returns the Gin router instance
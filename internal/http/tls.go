@@ -0,0 +1,188 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSOptions configures TLS on a listener. Certificates come from CertFile and KeyFile,
+// or from Let's Encrypt for AutocertDomains; without either the listener serves plain HTTP.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+
+	AutocertDomains  []string
+	AutocertCacheDir string // where issued certificates and the account key are kept
+	AutocertEmail    string // contact for expiry notices from Let's Encrypt
+
+	MinVersion string // 1.2 or 1.3
+	HTTP2      bool   // offer HTTP/2 through ALPN
+
+	// ClientCAFile requires clients to present a certificate signed by one of its CAs
+	// (mutual TLS); empty accepts any client
+	ClientCAFile string
+}
+
+// Enabled reports whether the options turn TLS on
+func (o TLSOptions) Enabled() bool {
+	return o.CertFile != "" || len(o.AutocertDomains) > 0
+}
+
+// NewTLSConfig builds the TLS configuration for a listener, or returns nil when TLS isn't
+// enabled. Certificate files are read again when they change on disk, so renewed
+// certificates are served without a restart. With autocert, the manager is returned so
+// its ACME challenge handler can be served on port 80.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, *autocert.Manager, error) {
+	if !opts.Enabled() {
+		return nil, nil, nil
+	}
+
+	minVersion := uint16(tls.VersionTLS12)
+	switch opts.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		minVersion = tls.VersionTLS13
+	default:
+		return nil, nil, fmt.Errorf("unsupported TLS minimum version: %s", opts.MinVersion)
+	}
+
+	var cfg *tls.Config
+	var manager *autocert.Manager
+	if len(opts.AutocertDomains) > 0 {
+		manager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.AutocertDomains...),
+			Cache:      autocert.DirCache(opts.AutocertCacheDir),
+			Email:      opts.AutocertEmail,
+		}
+		// Includes acme-tls/1 so certificates can also be issued over the TLS port
+		cfg = manager.TLSConfig()
+	} else {
+		certs := &certReloader{certFile: opts.CertFile, keyFile: opts.KeyFile}
+		if _, err := certs.load(); err != nil {
+			return nil, nil, err
+		}
+		cfg = &tls.Config{
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return certs.load()
+			},
+			NextProtos: []string{"h2", "http/1.1"},
+		}
+	}
+	cfg.MinVersion = minVersion
+
+	if !opts.HTTP2 {
+		protos := make([]string, 0, len(cfg.NextProtos))
+		for _, proto := range cfg.NextProtos {
+			if proto != "h2" {
+				protos = append(protos, proto)
+			}
+		}
+		cfg.NextProtos = protos
+	}
+
+	if opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in client CA file %s", opts.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, manager, nil
+}
+
+// ConfigureServer applies TLS to srv. Without HTTP/2, the server is kept from upgrading
+// connections even when a client asks for it.
+func ConfigureServer(srv *http.Server, tlsConfig *tls.Config, http2 bool) {
+	srv.TLSConfig = tlsConfig
+	if tlsConfig != nil && !http2 {
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+}
+
+// RedirectToHTTPS serves plain HTTP by redirecting to the same URL over HTTPS on
+// httpsPort. With autocert it answers ACME HTTP-01 challenges first.
+func RedirectToHTTPS(manager *autocert.Manager, httpsPort string) http.Handler {
+	redirect := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Use HTTPS", http.StatusBadRequest)
+			return
+		}
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+	if manager != nil {
+		return manager.HTTPHandler(redirect)
+	}
+	return redirect
+}
+
+// certReloaderCheckInterval is how often the certificate file is checked for changes
+const certReloaderCheckInterval = 30 * time.Second
+
+// certReloader serves a certificate from files, loading it again after they change
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// load returns the certificate, reading the files again when the certificate file was
+// modified since they were last read
+func (r *certReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cert != nil && time.Since(r.checkedAt) < certReloaderCheckInterval {
+		return r.cert, nil
+	}
+	r.checkedAt = time.Now()
+
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		if r.cert != nil {
+			// Keep serving the loaded certificate while the file is being replaced
+			return r.cert, nil
+		}
+		return nil, fmt.Errorf("failed to read TLS certificate: %w", err)
+	}
+	if r.cert != nil && info.ModTime().Equal(r.modTime) {
+		return r.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			// A half-written certificate and key don't match yet; try again later
+			return r.cert, nil
+		}
+		return nil, errors.Join(errors.New("failed to load TLS certificate and key"), err)
+	}
+	r.cert = &cert
+	r.modTime = info.ModTime()
+	return r.cert, nil
+}
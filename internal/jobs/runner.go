@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrQueueFull is returned by Enqueue when the runner cannot accept more work
+var ErrQueueFull = errors.New("job queue is full")
+
+// Job is a unit of background work
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+	// Kind and Payload describe the job well enough to run it again. Jobs with a Kind that
+	// fail are handed to the runner's dead-letter handler; jobs without one are only logged.
+	Kind    string
+	Payload []byte
+}
+
+// DeadLetterFunc receives a job that failed or panicked, with the error it ended with
+type DeadLetterFunc func(job Job, err error)
+
+// Runner executes jobs asynchronously on a fixed pool of workers. Jobs live in memory
+// only; callers that must survive a restart persist their own state and re-enqueue on boot.
+type Runner struct {
+	queue   chan Job
+	workers int
+	wg      sync.WaitGroup
+
+	deadLetter DeadLetterFunc
+
+	running   atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+}
+
+// RunnerStats reports the runner's queue depth and job outcomes since startup
+type RunnerStats struct {
+	Workers   int   `json:"workers"`
+	Queued    int   `json:"queued"`
+	Capacity  int   `json:"capacity"`
+	Running   int64 `json:"running"`
+	Succeeded int64 `json:"succeeded"`
+	Failed    int64 `json:"failed"`
+}
+
+// NewRunner creates a runner with the given number of workers and queue capacity
+func NewRunner(workers, queueSize int) *Runner {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &Runner{
+		queue:   make(chan Job, queueSize),
+		workers: workers,
+	}
+}
+
+// WithDeadLetter sets the handler for failed jobs that have a Kind. It is called on the
+// worker that ran the job, so it should be quick.
+func (r *Runner) WithDeadLetter(fn DeadLetterFunc) *Runner {
+	r.deadLetter = fn
+	return r
+}
+
+// Start launches the workers. They stop once ctx is canceled, finishing the job in hand first.
+func (r *Runner) Start(ctx context.Context) {
+	for i := 0; i < r.workers; i++ {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-r.queue:
+					r.run(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue schedules a job without blocking
+func (r *Runner) Enqueue(job Job) error {
+	select {
+	case r.queue <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Stats reports how many jobs are waiting and running, and how many have finished
+func (r *Runner) Stats() RunnerStats {
+	return RunnerStats{
+		Workers:   r.workers,
+		Queued:    len(r.queue),
+		Capacity:  cap(r.queue),
+		Running:   r.running.Load(),
+		Succeeded: r.succeeded.Load(),
+		Failed:    r.failed.Load(),
+	}
+}
+
+// Wait blocks until all workers have exited
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) run(ctx context.Context, job Job) {
+	r.running.Add(1)
+	defer r.running.Add(-1)
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("Job %s panicked: %v", job.Name, rec)
+			r.failed.Add(1)
+			r.deadLettered(job, fmt.Errorf("panic: %v", rec))
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed: %v", job.Name, err)
+		r.failed.Add(1)
+		r.deadLettered(job, err)
+		return
+	}
+	r.succeeded.Add(1)
+}
+
+func (r *Runner) deadLettered(job Job, err error) {
+	if r.deadLetter == nil || job.Kind == "" {
+		return
+	}
+	r.deadLetter(job, err)
+}
@@ -0,0 +1,270 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+)
+
+// scheduleLockHold is the least time a scheduled run keeps its lock, so replicas whose
+// clocks trail this one by a few seconds find the slot taken instead of running it again
+const scheduleLockHold = 30 * time.Second
+
+var (
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	ErrTaskRunning  = errors.New("scheduled task is already running")
+	ErrTaskExists   = errors.New("scheduled task is already registered")
+)
+
+// Task trigger sources
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// TaskStatus describes a scheduled task and its most recent run
+type TaskStatus struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Schedule     string     `json:"schedule"`
+	NextRunAt    time.Time  `json:"next_run_at"`
+	Running      bool       `json:"running"`
+	Runs         int        `json:"runs"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastTrigger  string     `json:"last_trigger,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	// WorkingDaysOnly tasks skip scheduled runs on days the business calendar marks closed
+	WorkingDaysOnly bool `json:"working_days_only,omitempty"`
+}
+
+// WorkingDays reports whether a time falls on a working day of the business calendar
+type WorkingDays interface {
+	IsWorkingDay(ctx context.Context, t time.Time) (bool, error)
+}
+
+type scheduledTask struct {
+	schedule Schedule
+	run      func(ctx context.Context) error
+	status   TaskStatus
+}
+
+// Scheduler runs recurring tasks on cron schedules. Due tasks are handed to the job
+// runner; a task whose previous run has not finished is skipped rather than stacked.
+type Scheduler struct {
+	runner   *Runner
+	locker   lock.Manager
+	calendar WorkingDays
+	mu       sync.Mutex
+	tasks    map[string]*scheduledTask
+}
+
+// NewScheduler creates a scheduler that executes tasks on the given runner
+func NewScheduler(runner *Runner) *Scheduler {
+	return &Scheduler{
+		runner: runner,
+		tasks:  make(map[string]*scheduledTask),
+	}
+}
+
+// WithLocker makes every replica's scheduler share task locks, so each scheduled run
+// executes on one replica only and a manual run never overlaps a run elsewhere
+func (s *Scheduler) WithLocker(locker lock.Manager) *Scheduler {
+	s.locker = locker
+	return s
+}
+
+// WithCalendar sets the business calendar consulted by tasks registered with
+// RegisterWorkingDays
+func (s *Scheduler) WithCalendar(calendar WorkingDays) *Scheduler {
+	s.calendar = calendar
+	return s
+}
+
+// Register adds a recurring task. The spec is parsed with ParseSchedule.
+func (s *Scheduler) Register(name, description, spec string, run func(ctx context.Context) error) error {
+	return s.register(name, description, spec, false, run)
+}
+
+// RegisterWorkingDays adds a recurring task whose scheduled runs are skipped on weekends
+// and holidays of the business calendar. Manual runs are never skipped.
+func (s *Scheduler) RegisterWorkingDays(name, description, spec string, run func(ctx context.Context) error) error {
+	return s.register(name, description, spec, true, run)
+}
+
+func (s *Scheduler) register(name, description, spec string, workingDaysOnly bool, run func(ctx context.Context) error) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.tasks[name]; exists {
+		return ErrTaskExists
+	}
+	s.tasks[name] = &scheduledTask{
+		schedule: schedule,
+		run:      run,
+		status: TaskStatus{
+			Name:            name,
+			Description:     description,
+			Schedule:        spec,
+			NextRunAt:       schedule.Next(time.Now()),
+			WorkingDaysOnly: workingDaysOnly,
+		},
+	}
+	return nil
+}
+
+// Start runs due tasks until ctx is canceled
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(s.nextDue()))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case now := <-timer.C:
+				s.runDue(now)
+			}
+		}
+	}()
+}
+
+// Tasks returns the status of every registered task, ordered by name
+func (s *Scheduler) Tasks() []TaskStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]TaskStatus, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		list = append(list, task.status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Trigger queues a task to run now, outside its schedule
+func (s *Scheduler) Trigger(name string) (TaskStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[name]
+	if !ok {
+		return TaskStatus{}, ErrTaskNotFound
+	}
+	if err := s.dispatch(task, TriggerManual); err != nil {
+		return task.status, err
+	}
+	return task.status, nil
+}
+
+func (s *Scheduler) nextDue() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := time.Now().Add(time.Minute)
+	for _, task := range s.tasks {
+		if task.status.NextRunAt.Before(next) {
+			next = task.status.NextRunAt
+		}
+	}
+	return next
+}
+
+func (s *Scheduler) runDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, task := range s.tasks {
+		if task.status.NextRunAt.After(now) {
+			continue
+		}
+		task.status.NextRunAt = task.schedule.Next(now)
+		if err := s.dispatch(task, TriggerSchedule); err != nil {
+			log.Printf("Skipped scheduled task %s: %v", name, err)
+		}
+	}
+}
+
+// dispatch hands a task to the runner; the caller holds s.mu
+func (s *Scheduler) dispatch(task *scheduledTask, trigger string) error {
+	if task.status.Running {
+		return ErrTaskRunning
+	}
+
+	err := s.runner.Enqueue(Job{
+		Name: "schedule:" + task.status.Name,
+		Run: func(ctx context.Context) error {
+			return s.execute(ctx, task, trigger)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	task.status.Running = true
+	return nil
+}
+
+// execute runs a task on a runner worker and records the outcome
+func (s *Scheduler) execute(ctx context.Context, task *scheduledTask, trigger string) error {
+	started := time.Now()
+
+	if trigger == TriggerSchedule && task.status.WorkingDaysOnly && s.calendar != nil {
+		working, err := s.calendar.IsWorkingDay(ctx, started)
+		if err != nil || !working {
+			s.mu.Lock()
+			task.status.Running = false
+			s.mu.Unlock()
+			if err != nil {
+				return err
+			}
+			log.Printf("Skipped scheduled task %s: not a working day", task.status.Name)
+			return nil
+		}
+	}
+
+	if s.locker != nil {
+		held, err := s.locker.TryAcquire(ctx, "schedule:"+task.status.Name)
+		if err != nil {
+			s.mu.Lock()
+			task.status.Running = false
+			s.mu.Unlock()
+			if err == lock.ErrNotAcquired {
+				log.Printf("Skipped scheduled task %s: running on another replica", task.status.Name)
+				return nil
+			}
+			return err
+		}
+
+		hold := time.Duration(0)
+		if trigger == TriggerSchedule {
+			hold = scheduleLockHold
+		}
+		defer func() {
+			time.AfterFunc(time.Until(started.Add(hold)), func() {
+				if err := held.Release(context.Background()); err != nil {
+					log.Printf("Failed to release lock for scheduled task %s: %v", task.status.Name, err)
+				}
+			})
+		}()
+	}
+
+	runErr := task.run(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.status.Running = false
+	task.status.Runs++
+	task.status.LastRunAt = &started
+	task.status.LastDuration = time.Since(started).Round(time.Millisecond).String()
+	task.status.LastTrigger = trigger
+	task.status.LastError = ""
+	if runErr != nil {
+		task.status.LastError = runErr.Error()
+	}
+	return runErr
+}
@@ -0,0 +1,61 @@
+package lock
+
+import (
+	"context"
+	"sync"
+)
+
+// LocalManager grants locks within this process. Use it for single-replica deployments,
+// databases without advisory locks, and tests.
+type LocalManager struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// NewLocalManager creates a new LocalManager
+func NewLocalManager() *LocalManager {
+	return &LocalManager{locks: make(map[string]chan struct{})}
+}
+
+// TryAcquire takes the lock if it is free
+func (m *LocalManager) TryAcquire(ctx context.Context, name string) (Lock, error) {
+	select {
+	case m.slot(name) <- struct{}{}:
+		return &localLock{slot: m.slot(name)}, nil
+	default:
+		return nil, ErrNotAcquired
+	}
+}
+
+// Acquire waits for the lock
+func (m *LocalManager) Acquire(ctx context.Context, name string) (Lock, error) {
+	slot := m.slot(name)
+	select {
+	case slot <- struct{}{}:
+		return &localLock{slot: slot}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// slot returns the single-capacity channel that is full while the lock is held
+func (m *LocalManager) slot(name string) chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	slot, ok := m.locks[name]
+	if !ok {
+		slot = make(chan struct{}, 1)
+		m.locks[name] = slot
+	}
+	return slot
+}
+
+type localLock struct {
+	once sync.Once
+	slot chan struct{}
+}
+
+func (l *localLock) Release(ctx context.Context) error {
+	l.once.Do(func() { <-l.slot })
+	return nil
+}
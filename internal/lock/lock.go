@@ -0,0 +1,32 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+)
+
+// ErrNotAcquired is returned by TryAcquire when another holder has the lock
+var ErrNotAcquired = errors.New("lock is held elsewhere")
+
+// Manager grants named locks. Implementations backed by shared storage exclude holders
+// in every API replica; the local implementation only excludes goroutines in this process.
+type Manager interface {
+	// TryAcquire takes the lock if it is free and returns ErrNotAcquired otherwise
+	TryAcquire(ctx context.Context, name string) (Lock, error)
+	// Acquire waits for the lock until it is free or ctx is done
+	Acquire(ctx context.Context, name string) (Lock, error)
+}
+
+// Lock is a held lock
+type Lock interface {
+	// Release gives up the lock. Releasing twice is a no-op.
+	Release(ctx context.Context) error
+}
+
+// key maps a lock name onto the 64-bit key space used by Postgres advisory locks
+func key(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
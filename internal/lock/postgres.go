@@ -0,0 +1,73 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+)
+
+// PostgresManager grants locks with Postgres session-level advisory locks, so a lock
+// excludes holders in every replica that shares the database. Each held lock pins one
+// pooled connection until it is released; if the process dies the lock goes with it.
+type PostgresManager struct {
+	db *sql.DB
+}
+
+// NewPostgresManager creates a new PostgresManager
+func NewPostgresManager(db *sql.DB) *PostgresManager {
+	return &PostgresManager{db: db}
+}
+
+// TryAcquire takes the lock with pg_try_advisory_lock
+func (m *PostgresManager) TryAcquire(ctx context.Context, name string) (Lock, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key(name)).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, ErrNotAcquired
+	}
+	return &postgresLock{conn: conn, key: key(name)}, nil
+}
+
+// Acquire waits for the lock with pg_advisory_lock; canceling ctx abandons the wait
+func (m *PostgresManager) Acquire(ctx context.Context, name string) (Lock, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key(name)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &postgresLock{conn: conn, key: key(name)}, nil
+}
+
+type postgresLock struct {
+	once sync.Once
+	conn *sql.Conn
+	key  int64
+}
+
+func (l *postgresLock) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		_, err = l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+		// Closing returns the connection to the pool; should the unlock have failed,
+		// discard it instead so the session, and the lock with it, ends
+		if err != nil {
+			l.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		l.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,138 @@
+// Package moneymath is checked arithmetic on money.Money for totals, taxes and prorations.
+// Every operation fails rather than wrap around on int64 overflow or mix currencies, and
+// rate multiplication is done in exact decimal with an explicit rounding mode.
+package moneymath
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// ErrOverflow is returned when a result does not fit in an int64 number of minor units
+var ErrOverflow = errors.New("money amount overflows")
+
+// Rounding is how a fractional number of minor units becomes a whole one
+type Rounding string
+
+const (
+	// Truncate drops the fraction, rounding toward zero
+	Truncate Rounding = "truncate"
+	// HalfUp rounds to the nearest minor unit, halves away from zero
+	HalfUp Rounding = "half_up"
+	// HalfEven rounds to the nearest minor unit, halves to the even one (banker's rounding),
+	// so rounding many halves does not drift the total upward
+	HalfEven Rounding = "half_even"
+)
+
+// ParseRounding parses truncate, half_up or half_even; an empty string is Truncate
+func ParseRounding(s string) (Rounding, error) {
+	switch rounding := Rounding(strings.ToLower(strings.TrimSpace(s))); rounding {
+	case "":
+		return Truncate, nil
+	case Truncate, HalfUp, HalfEven:
+		return rounding, nil
+	default:
+		return "", fmt.Errorf("rounding %q must be truncate, half_up or half_even", s)
+	}
+}
+
+// AssertCurrency returns money.ErrCurrencyMismatch, naming both, when m is not in currency
+func AssertCurrency(m money.Money, currency string) error {
+	if m.Currency != currency {
+		return fmt.Errorf("%w: %s and %s", money.ErrCurrencyMismatch, m.Currency, currency)
+	}
+	return nil
+}
+
+// Add returns a + b
+func Add(a, b money.Money) (money.Money, error) {
+	if err := AssertCurrency(b, a.Currency); err != nil {
+		return money.Money{}, err
+	}
+	sum := a.Amount + b.Amount
+	if (b.Amount > 0 && sum < a.Amount) || (b.Amount < 0 && sum > a.Amount) {
+		return money.Money{}, ErrOverflow
+	}
+	return money.Money{Amount: sum, Currency: a.Currency}, nil
+}
+
+// Sub returns a - b
+func Sub(a, b money.Money) (money.Money, error) {
+	if b.Amount == math.MinInt64 {
+		return money.Money{}, ErrOverflow
+	}
+	return Add(a, money.Money{Amount: -b.Amount, Currency: b.Currency})
+}
+
+// MulInt returns m * n, e.g. a unit price times a quantity
+func MulInt(m money.Money, n int64) (money.Money, error) {
+	if m.Amount == 0 || n == 0 {
+		return money.Money{Currency: m.Currency}, nil
+	}
+	product := m.Amount * n
+	if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return money.Money{}, ErrOverflow
+	}
+	return money.Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Sum totals amounts that must all be in currency. No amounts sum to zero.
+func Sum(currency string, amounts ...money.Money) (money.Money, error) {
+	total := money.Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		if total, err = Add(total, amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// MulRate returns m * rate rounded to a whole minor unit, e.g. the tax on an amount. The
+// rate is taken as the decimal it prints as, so 0.0875 is exactly 875/10000 rather than
+// the nearest float64.
+func MulRate(m money.Money, rate float64, rounding Rounding) (money.Money, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return money.Money{}, fmt.Errorf("rate %v is not a number", rate)
+	}
+	exact, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return money.Money{}, fmt.Errorf("rate %v is not a number", rate)
+	}
+	exact.Mul(exact, new(big.Rat).SetInt64(m.Amount))
+	return fromRat(exact, m.Currency, rounding)
+}
+
+// Prorate returns the share part/whole of m, truncated, e.g. the refund for 2 of 3 units
+// of a line. The intermediate product cannot overflow.
+func Prorate(m money.Money, part, whole int64) (money.Money, error) {
+	if whole == 0 {
+		return money.Money{}, errors.New("cannot prorate over a whole of zero")
+	}
+	share := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(part)), big.NewInt(whole))
+	return fromRat(share, m.Currency, Truncate)
+}
+
+// fromRat rounds an exact number of minor units to a whole one
+func fromRat(r *big.Rat, currency string, rounding Rounding) (money.Money, error) {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && rounding != Truncate {
+		// Compare twice the remainder with the denominator to find which side of half it is on
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		cmp := twice.Cmp(r.Denom())
+		if cmp > 0 || (cmp == 0 && (rounding == HalfUp || quo.Bit(0) == 1)) {
+			quo.Add(quo, big.NewInt(int64(rem.Sign())))
+		}
+	}
+	if !quo.IsInt64() {
+		return money.Money{}, ErrOverflow
+	}
+	return money.Money{Amount: quo.Int64(), Currency: currency}, nil
+}
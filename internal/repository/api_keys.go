@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// APIKeyRepository implements services.APIKeyRepository using GORM
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// FindByID finds an API key by ID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id string) (*services.APIKey, error) {
+	var dbKey database.APIKey
+	if err := r.db.WithContext(ctx).First(&dbKey, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbKey), nil
+}
+
+// FindByHash finds an API key by the hash of its secret
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*services.APIKey, error) {
+	var dbKey database.APIKey
+	if err := r.db.WithContext(ctx).First(&dbKey, "key_hash = ?", keyHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbKey), nil
+}
+
+// List lists all API keys, newest first
+func (r *APIKeyRepository) List(ctx context.Context) ([]*services.APIKey, error) {
+	var dbKeys []database.APIKey
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&dbKeys).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]*services.APIKey, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		keys[i] = r.toDomain(&dbKey)
+	}
+	return keys, nil
+}
+
+// Save saves an API key
+func (r *APIKeyRepository) Save(ctx context.Context, key *services.APIKey) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(key)).Error
+}
+
+// Helper methods
+
+func (r *APIKeyRepository) toDomain(dbKey *database.APIKey) *services.APIKey {
+	var scopes []services.APIKeyScope
+	for _, scope := range strings.Split(dbKey.Scopes, ",") {
+		if scope != "" {
+			scopes = append(scopes, services.APIKeyScope(scope))
+		}
+	}
+
+	return &services.APIKey{
+		ID:         dbKey.ID,
+		Name:       dbKey.Name,
+		Prefix:     dbKey.Prefix,
+		KeyHash:    dbKey.KeyHash,
+		Scopes:     scopes,
+		QuotaTier:  dbKey.QuotaTier,
+		CreatedBy:  dbKey.CreatedBy,
+		LastUsedAt: dbKey.LastUsedAt,
+		RevokedAt:  dbKey.RevokedAt,
+		CreatedAt:  dbKey.CreatedAt,
+	}
+}
+
+func (r *APIKeyRepository) toDatabase(key *services.APIKey) *database.APIKey {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &database.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     strings.Join(scopes, ","),
+		QuotaTier:  key.QuotaTier,
+		CreatedBy:  key.CreatedBy,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
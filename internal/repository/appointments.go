@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// AppointmentRepository implements services.AppointmentRepository using GORM
+type AppointmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepository creates a new AppointmentRepository
+func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
+	return &AppointmentRepository{db: db}
+}
+
+// FindProduct finds a product's appointment settings
+func (r *AppointmentRepository) FindProduct(ctx context.Context, productID string) (*services.AppointmentProduct, error) {
+	var dbProduct database.AppointmentProduct
+	if err := r.db.WithContext(ctx).First(&dbProduct, "product_id = ?", productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrAppointmentProductNotFound
+		}
+		return nil, err
+	}
+	return &services.AppointmentProduct{
+		ProductID:         dbProduct.ProductID,
+		CancelWindowHours: dbProduct.CancelWindowHours,
+		UpdatedAt:         dbProduct.UpdatedAt,
+	}, nil
+}
+
+// SaveProduct creates or replaces a product's appointment settings
+func (r *AppointmentRepository) SaveProduct(ctx context.Context, product *services.AppointmentProduct) error {
+	return r.db.WithContext(ctx).Save(&database.AppointmentProduct{
+		ProductID:         product.ProductID,
+		CancelWindowHours: product.CancelWindowHours,
+		UpdatedAt:         product.UpdatedAt,
+	}).Error
+}
+
+// DeleteProduct deletes a product's appointment settings
+func (r *AppointmentRepository) DeleteProduct(ctx context.Context, productID string) error {
+	result := r.db.WithContext(ctx).Delete(&database.AppointmentProduct{}, "product_id = ?", productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrAppointmentProductNotFound
+	}
+	return nil
+}
+
+// FindSlot finds an appointment slot by ID
+func (r *AppointmentRepository) FindSlot(ctx context.Context, id string) (*services.AppointmentSlot, error) {
+	var dbSlot database.AppointmentSlot
+	if err := r.db.WithContext(ctx).First(&dbSlot, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrAppointmentSlotNotFound
+		}
+		return nil, err
+	}
+	return r.slotToDomain(&dbSlot), nil
+}
+
+// FindSlots finds a product's slots starting within the given range
+func (r *AppointmentRepository) FindSlots(ctx context.Context, productID string, from, to time.Time) ([]*services.AppointmentSlot, error) {
+	var dbSlots []database.AppointmentSlot
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND starts_at >= ? AND starts_at < ?", productID, from, to).
+		Order("starts_at ASC").
+		Find(&dbSlots).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make([]*services.AppointmentSlot, len(dbSlots))
+	for i := range dbSlots {
+		slots[i] = r.slotToDomain(&dbSlots[i])
+	}
+	return slots, nil
+}
+
+// SaveSlot creates a slot, or updates its times, capacity and status. The seats booked are
+// only ever changed by Book and CancelOrderBookings.
+func (r *AppointmentRepository) SaveSlot(ctx context.Context, slot *services.AppointmentSlot) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"starts_at":  slot.StartsAt,
+			"ends_at":    slot.EndsAt,
+			"capacity":   slot.Capacity,
+			"is_active":  slot.IsActive,
+			"updated_at": now,
+		}),
+	}).Create(&database.AppointmentSlot{
+		ID:        slot.ID,
+		ProductID: slot.ProductID,
+		StartsAt:  slot.StartsAt,
+		EndsAt:    slot.EndsAt,
+		Capacity:  slot.Capacity,
+		Booked:    slot.Booked,
+		IsActive:  slot.IsActive,
+		CreatedAt: slot.CreatedAt,
+		UpdatedAt: now,
+	}).Error
+}
+
+// Book takes the booking's seats in its slot and records it in a single transaction.
+// The conditional update guards against overbooking when orders race for the last seats.
+func (r *AppointmentRepository) Book(ctx context.Context, booking *services.AppointmentBooking) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&database.AppointmentSlot{}).
+			Where("id = ? AND is_active = ? AND booked + ? <= capacity", booking.SlotID, true, booking.Quantity).
+			Updates(map[string]interface{}{
+				"booked":     gorm.Expr("booked + ?", booking.Quantity),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrAppointmentSlotFull
+		}
+
+		return tx.Create(&database.AppointmentBooking{
+			ID:          booking.ID,
+			SlotID:      booking.SlotID,
+			ProductID:   booking.ProductID,
+			OrderID:     booking.OrderID,
+			OrderItemID: booking.OrderItemID,
+			UserID:      booking.UserID,
+			Quantity:    booking.Quantity,
+			StartsAt:    booking.StartsAt,
+			EndsAt:      booking.EndsAt,
+			CancelBy:    booking.CancelBy,
+			Status:      booking.Status,
+			CreatedAt:   booking.CreatedAt,
+		}).Error
+	})
+}
+
+// FindOrderBookings finds the appointments an order booked
+func (r *AppointmentRepository) FindOrderBookings(ctx context.Context, orderID string) ([]*services.AppointmentBooking, error) {
+	return r.findBookings(ctx, "order_id = ?", orderID)
+}
+
+// FindSlotBookings finds the bookings of a slot
+func (r *AppointmentRepository) FindSlotBookings(ctx context.Context, slotID string) ([]*services.AppointmentBooking, error) {
+	return r.findBookings(ctx, "slot_id = ?", slotID)
+}
+
+// CancelOrderBookings frees the seats of an order's booked appointments and marks them
+// canceled in a single transaction. Each booking is canceled before its seats are freed,
+// and only when it was still booked, so two releases of the same order running at once
+// free its seats once.
+func (r *AppointmentRepository) CancelOrderBookings(ctx context.Context, orderID string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbBookings []database.AppointmentBooking
+		if err := tx.Where("order_id = ? AND status = ?", orderID, services.AppointmentBooked).
+			Find(&dbBookings).Error; err != nil {
+			return err
+		}
+
+		for _, dbBooking := range dbBookings {
+			result := tx.Model(&database.AppointmentBooking{}).
+				Where("id = ? AND status = ?", dbBooking.ID, services.AppointmentBooked).
+				Updates(map[string]interface{}{
+					"status":      services.AppointmentCanceled,
+					"canceled_at": at,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != 1 {
+				// Another release canceled it first and freed its seats
+				continue
+			}
+			if err := tx.Model(&database.AppointmentSlot{}).
+				Where("id = ?", dbBooking.SlotID).
+				Updates(map[string]interface{}{
+					"booked":     gorm.Expr("GREATEST(booked - ?, 0)", dbBooking.Quantity),
+					"updated_at": at,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Helper methods
+
+func (r *AppointmentRepository) findBookings(ctx context.Context, query string, arg string) ([]*services.AppointmentBooking, error) {
+	var dbBookings []database.AppointmentBooking
+	if err := r.db.WithContext(ctx).
+		Where(query, arg).
+		Order("starts_at ASC, created_at ASC").
+		Find(&dbBookings).Error; err != nil {
+		return nil, err
+	}
+
+	bookings := make([]*services.AppointmentBooking, len(dbBookings))
+	for i, dbBooking := range dbBookings {
+		bookings[i] = &services.AppointmentBooking{
+			ID:          dbBooking.ID,
+			SlotID:      dbBooking.SlotID,
+			ProductID:   dbBooking.ProductID,
+			OrderID:     dbBooking.OrderID,
+			OrderItemID: dbBooking.OrderItemID,
+			UserID:      dbBooking.UserID,
+			Quantity:    dbBooking.Quantity,
+			StartsAt:    dbBooking.StartsAt,
+			EndsAt:      dbBooking.EndsAt,
+			CancelBy:    dbBooking.CancelBy,
+			Status:      dbBooking.Status,
+			CreatedAt:   dbBooking.CreatedAt,
+			CanceledAt:  dbBooking.CanceledAt,
+		}
+	}
+	return bookings, nil
+}
+
+func (r *AppointmentRepository) slotToDomain(dbSlot *database.AppointmentSlot) *services.AppointmentSlot {
+	return &services.AppointmentSlot{
+		ID:        dbSlot.ID,
+		ProductID: dbSlot.ProductID,
+		StartsAt:  dbSlot.StartsAt,
+		EndsAt:    dbSlot.EndsAt,
+		Capacity:  dbSlot.Capacity,
+		Booked:    dbSlot.Booked,
+		IsActive:  dbSlot.IsActive,
+		CreatedAt: dbSlot.CreatedAt,
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// BarcodeRepository implements services.BarcodeRepository using GORM
+type BarcodeRepository struct {
+	db *gorm.DB
+}
+
+// NewBarcodeRepository creates a new BarcodeRepository
+func NewBarcodeRepository(db *gorm.DB) *BarcodeRepository {
+	return &BarcodeRepository{db: db}
+}
+
+// FindVariantID finds the ID of the variant with the barcode
+func (r *BarcodeRepository) FindVariantID(ctx context.Context, barcode string) (string, error) {
+	var dbVariant database.Variant
+	err := r.db.WithContext(ctx).Select("id").First(&dbVariant, "barcode = ?", barcode).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", services.ErrBarcodeNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return dbVariant.ID, nil
+}
+
+// FindBarcode finds a variant's barcode
+func (r *BarcodeRepository) FindBarcode(ctx context.Context, variantID string) (string, error) {
+	var dbVariant database.Variant
+	err := r.db.WithContext(ctx).Select("barcode").First(&dbVariant, "id = ?", variantID).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", services.ErrVariantNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if dbVariant.Barcode == nil {
+		return "", nil
+	}
+	return *dbVariant.Barcode, nil
+}
+
+// SetBarcode sets or clears a variant's barcode
+func (r *BarcodeRepository) SetBarcode(ctx context.Context, variantID, barcode string) error {
+	var value *string
+	if barcode != "" {
+		value = &barcode
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.Variant{}).
+		Where("id = ?", variantID).
+		Updates(map[string]interface{}{"barcode": value, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrVariantNotFound
+	}
+	return nil
+}
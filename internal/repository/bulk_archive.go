@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// BulkArchiveRepository implements services.BulkArchiveRepository using GORM
+type BulkArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewBulkArchiveRepository creates a new BulkArchiveRepository
+func NewBulkArchiveRepository(db *gorm.DB) *BulkArchiveRepository {
+	return &BulkArchiveRepository{db: db}
+}
+
+// FindProductIDs finds the IDs of the products matching the filter, ordered by ID
+func (r *BulkArchiveRepository) FindProductIDs(ctx context.Context, filter services.BulkArchiveFilter) ([]string, error) {
+	var ids []string
+	err := r.productFilter(r.db.WithContext(ctx).Model(&database.Product{}), filter).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindVariantIDs finds the IDs of the variants matching the filter, ordered by ID
+func (r *BulkArchiveRepository) FindVariantIDs(ctx context.Context, filter services.BulkArchiveFilter) ([]string, error) {
+	query := r.db.WithContext(ctx).Model(&database.Variant{})
+	if filter.ProductID != "" {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.CategoryID != "" || filter.BrandID != "" {
+		products := r.productFilter(r.db.Model(&database.Product{}).Select("id"), filter)
+		query = query.Where("product_id IN (?)", products)
+	}
+
+	var ids []string
+	err := query.Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Helper methods
+
+func (r *BulkArchiveRepository) productFilter(query *gorm.DB, filter services.BulkArchiveFilter) *gorm.DB {
+	if filter.CategoryID != "" {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.BrandID != "" {
+		query = query.Where("brand_id = ?", filter.BrandID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
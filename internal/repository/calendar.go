@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// businessCalendarID is the key of the single calendar row
+const businessCalendarID = "default"
+
+// CalendarRepository implements services.CalendarRepository using GORM
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarRepository creates a new CalendarRepository
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// Get finds the calendar settings
+func (r *CalendarRepository) Get(ctx context.Context) (*services.BusinessCalendar, error) {
+	var dbCalendar database.BusinessCalendar
+	if err := r.db.WithContext(ctx).First(&dbCalendar, "id = ?", businessCalendarID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCalendarNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbCalendar), nil
+}
+
+// Save creates or replaces the calendar settings
+func (r *CalendarRepository) Save(ctx context.Context, calendar *services.BusinessCalendar) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(calendar)).Error
+}
+
+// ListHolidays lists holidays by date
+func (r *CalendarRepository) ListHolidays(ctx context.Context) ([]services.Holiday, error) {
+	var dbHolidays []database.CalendarHoliday
+	if err := r.db.WithContext(ctx).Order("date ASC").Find(&dbHolidays).Error; err != nil {
+		return nil, err
+	}
+
+	holidays := make([]services.Holiday, len(dbHolidays))
+	for i, dbHoliday := range dbHolidays {
+		holidays[i] = services.Holiday{Date: dbHoliday.Date, Name: dbHoliday.Name}
+	}
+	return holidays, nil
+}
+
+// SaveHoliday creates a holiday or renames the one on the same date
+func (r *CalendarRepository) SaveHoliday(ctx context.Context, holiday services.Holiday) error {
+	return r.db.WithContext(ctx).Save(&database.CalendarHoliday{Date: holiday.Date, Name: holiday.Name}).Error
+}
+
+// DeleteHoliday deletes the holiday on a date
+func (r *CalendarRepository) DeleteHoliday(ctx context.Context, date string) error {
+	result := r.db.WithContext(ctx).Delete(&database.CalendarHoliday{}, "date = ?", date)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrHolidayNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *CalendarRepository) toDomain(dbCalendar *database.BusinessCalendar) *services.BusinessCalendar {
+	calendar := &services.BusinessCalendar{
+		Timezone:   dbCalendar.Timezone,
+		CutoffTime: dbCalendar.CutoffTime,
+		UpdatedAt:  dbCalendar.UpdatedAt,
+	}
+	for _, field := range strings.Split(dbCalendar.WorkingDays, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+			calendar.WorkingDays = append(calendar.WorkingDays, time.Weekday(day))
+		}
+	}
+	return calendar
+}
+
+func (r *CalendarRepository) toDatabase(calendar *services.BusinessCalendar) *database.BusinessCalendar {
+	days := make([]string, len(calendar.WorkingDays))
+	for i, day := range calendar.WorkingDays {
+		days[i] = strconv.Itoa(int(day))
+	}
+	return &database.BusinessCalendar{
+		ID:          businessCalendarID,
+		Timezone:    calendar.Timezone,
+		WorkingDays: strings.Join(days, ","),
+		CutoffTime:  calendar.CutoffTime,
+		UpdatedAt:   calendar.UpdatedAt,
+	}
+}
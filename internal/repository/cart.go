@@ -9,13 +9,15 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
 	"github.com/devchuckcamp/gocommerce/cart"
 	"github.com/devchuckcamp/gocommerce/money"
 )
 
 // CartRepository implements cart.Repository using GORM
 type CartRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	retry retry.Policy
 }
 
 // NewCartRepository creates a new CartRepository
@@ -23,6 +25,12 @@ func NewCartRepository(db *gorm.DB) *CartRepository {
 	return &CartRepository{db: db}
 }
 
+// WithRetry runs writes that fail with a serialization failure or deadlock again
+func (r *CartRepository) WithRetry(policy retry.Policy) *CartRepository {
+	r.retry = writeRetryPolicy(policy)
+	return r
+}
+
 // FindByID finds a cart by ID
 func (r *CartRepository) FindByID(ctx context.Context, id string) (*cart.Cart, error) {
 	var dbCart database.Cart
@@ -69,9 +77,9 @@ func (r *CartRepository) Save(ctx context.Context, c *cart.Cart) error {
 	}
 
 	// Sync cart header + items in a single transaction.
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return retryTransaction(ctx, r.db, r.retry, func(tx *gorm.DB) error {
 		dbCart := r.toDatabase(c)
-		if err := tx.Save(dbCart).Error; err != nil {
+		if err := tx.Omit("metadata").Save(dbCart).Error; err != nil {
 			return err
 		}
 
@@ -91,7 +99,7 @@ func (r *CartRepository) Save(ctx context.Context, c *cart.Cart) error {
 		for _, item := range c.Items {
 			desired[item.ID] = struct{}{}
 			dbItem := r.toDatabaseItem(c.ID, item)
-			if err := tx.Save(dbItem).Error; err != nil {
+			if err := tx.Omit("metadata").Save(dbItem).Error; err != nil {
 				return err
 			}
 		}
@@ -115,7 +123,25 @@ func (r *CartRepository) Save(ctx context.Context, c *cart.Cart) error {
 
 // Delete deletes a cart
 func (r *CartRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&database.Cart{}, "id = ?", id).Error
+	return retryWrite(ctx, r.retry, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Delete(&database.Cart{}, "id = ?", id).Error
+	})
+}
+
+// DeleteExpired deletes carts that expired before the given time, with their items
+func (r *CartRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := retryTransaction(ctx, r.db, r.retry, func(tx *gorm.DB) error {
+		expired := tx.Model(&database.Cart{}).Select("id").Where("expires_at < ?", before)
+		if err := tx.Where("cart_id IN (?)", expired).Delete(&database.CartItem{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("expires_at < ?", before).Delete(&database.Cart{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
 }
 
 // Helper methods
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// CartMetadataRepository implements services.CartMetadataRepository using GORM. Metadata
+// lives in its own columns on carts, cart_items and orders, which the cart and order
+// repositories leave untouched when they save.
+type CartMetadataRepository struct {
+	db *gorm.DB
+}
+
+// NewCartMetadataRepository creates a new CartMetadataRepository
+func NewCartMetadataRepository(db *gorm.DB) *CartMetadataRepository {
+	return &CartMetadataRepository{db: db}
+}
+
+// FindCartMetadata finds the metadata of a cart and its items
+func (r *CartMetadataRepository) FindCartMetadata(ctx context.Context, cartID string) (*services.CartMetadata, error) {
+	var dbCart database.Cart
+	err := r.db.WithContext(ctx).Select("id", "metadata").First(&dbCart, "id = ?", cartID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, cart.ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dbItems []database.CartItem
+	if err := r.db.WithContext(ctx).Select("id", "metadata").
+		Where("cart_id = ? AND metadata IS NOT NULL", cartID).
+		Find(&dbItems).Error; err != nil {
+		return nil, err
+	}
+
+	result := &services.CartMetadata{Items: make(map[string]services.Metadata, len(dbItems))}
+	if result.Metadata, err = unmarshalMetadata(dbCart.Metadata); err != nil {
+		return nil, err
+	}
+	for _, dbItem := range dbItems {
+		metadata, err := unmarshalMetadata(dbItem.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if len(metadata) > 0 {
+			result.Items[dbItem.ID] = metadata
+		}
+	}
+	return result, nil
+}
+
+// SetCartMetadata replaces or clears a cart's metadata
+func (r *CartMetadataRepository) SetCartMetadata(ctx context.Context, cartID string, metadata services.Metadata) error {
+	result := r.db.WithContext(ctx).Model(&database.Cart{}).
+		Where("id = ?", cartID).
+		Update("metadata", marshalMetadata(metadata))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return cart.ErrCartNotFound
+	}
+	return nil
+}
+
+// SetItemMetadata replaces or clears a cart item's metadata
+func (r *CartMetadataRepository) SetItemMetadata(ctx context.Context, cartID, itemID string, metadata services.Metadata) error {
+	result := r.db.WithContext(ctx).Model(&database.CartItem{}).
+		Where("id = ? AND cart_id = ?", itemID, cartID).
+		Update("metadata", marshalMetadata(metadata))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return cart.ErrItemNotFound
+	}
+	return nil
+}
+
+// FindOrderMetadata finds the metadata an order was placed with, reading through to the
+// archive for archived orders
+func (r *CartMetadataRepository) FindOrderMetadata(ctx context.Context, orderID string) (*services.CartMetadata, error) {
+	var dbOrder database.Order
+	err := r.db.WithContext(ctx).Select("id", "metadata", "item_metadata").First(&dbOrder, "id = ?", orderID).Error
+	if err == gorm.ErrRecordNotFound {
+		var dbArchived database.ArchivedOrder
+		err = r.db.WithContext(ctx).Select("id", "metadata", "item_metadata").First(&dbArchived, "id = ?", orderID).Error
+		dbOrder = dbArchived.Order
+	}
+	if err == gorm.ErrRecordNotFound {
+		return nil, orders.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &services.CartMetadata{Items: make(map[string]services.Metadata)}
+	if result.Metadata, err = unmarshalMetadata(dbOrder.Metadata); err != nil {
+		return nil, err
+	}
+	if dbOrder.ItemMetadata != nil {
+		if err := database.UnmarshalJSON(*dbOrder.ItemMetadata, &result.Items); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// SaveOrderMetadata stores the metadata an order was placed with
+func (r *CartMetadataRepository) SaveOrderMetadata(ctx context.Context, orderID string, metadata *services.CartMetadata) error {
+	var items *string
+	if len(metadata.Items) > 0 {
+		encoded := database.MarshalJSON(metadata.Items)
+		items = &encoded
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.Order{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{"metadata": marshalMetadata(metadata.Metadata), "item_metadata": items})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return orders.ErrOrderNotFound
+	}
+	return nil
+}
+
+// marshalMetadata encodes metadata for a JSONB column, or NULL when there is none
+func marshalMetadata(metadata services.Metadata) *string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	encoded := database.MarshalJSON(metadata)
+	return &encoded
+}
+
+func unmarshalMetadata(value *string) (services.Metadata, error) {
+	metadata := services.Metadata{}
+	if value == nil {
+		return metadata, nil
+	}
+	if err := database.UnmarshalJSON(*value, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
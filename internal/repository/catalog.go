@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
 	"github.com/devchuckcamp/gocommerce/catalog"
 )
 
@@ -86,6 +88,69 @@ func (r *ProductRepository) Search(ctx context.Context, searchQuery string, filt
 	return r.toDomainList(dbProducts), nil
 }
 
+// SearchFields searches for products, selecting only the columns behind the requested fields
+func (r *ProductRepository) SearchFields(ctx context.Context, searchQuery string, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error) {
+	query := r.db.WithContext(ctx).Where("name ILIKE ? OR description ILIKE ?",
+		"%"+searchQuery+"%", "%"+searchQuery+"%")
+	query = r.applyFilter(query, filter)
+	query = r.applyFields(query, fields)
+
+	var dbProducts []database.Product
+	if err := query.Find(&dbProducts).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(dbProducts), nil
+}
+
+// SearchQuery searches for products matching any of the query's keywords, boosted products
+// first, selecting only the columns behind the requested fields
+func (r *ProductRepository) SearchQuery(ctx context.Context, searchQuery services.ProductQuery, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error) {
+	match := r.db
+	for i, keyword := range searchQuery.Keywords {
+		if i == 0 {
+			match = match.Where("name ILIKE ? OR description ILIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+		} else {
+			match = match.Or("name ILIKE ? OR description ILIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+		}
+	}
+
+	query := r.db.WithContext(ctx).Where(match)
+	if len(searchQuery.ExcludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", searchQuery.ExcludeIDs)
+	}
+	if len(searchQuery.BoostIDs) > 0 {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN id IN (?) THEN 0 ELSE 1 END",
+			Vars:               []interface{}{searchQuery.BoostIDs},
+			WithoutParentheses: true,
+		}})
+	}
+	query = r.applyFilter(query, filter)
+	query = r.applyFields(query, fields)
+
+	var dbProducts []database.Product
+	if err := query.Find(&dbProducts).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(dbProducts), nil
+}
+
+// FindByCategoryFields finds products by category, selecting only the columns behind the requested fields
+func (r *ProductRepository) FindByCategoryFields(ctx context.Context, categoryID string, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error) {
+	query := r.db.WithContext(ctx).Where("category_id = ?", categoryID)
+	query = r.applyFilter(query, filter)
+	query = r.applyFields(query, fields)
+
+	var dbProducts []database.Product
+	if err := query.Find(&dbProducts).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(dbProducts), nil
+}
+
 // Save saves a product
 func (r *ProductRepository) Save(ctx context.Context, product *catalog.Product) error {
 	dbProduct := r.toDatabase(product)
@@ -112,10 +177,21 @@ func (r *ProductRepository) CountProducts(ctx context.Context, filter catalog.Pr
 
 // Helper methods
 
+// productSortColumns maps services.ProductSortFields to their columns
+var productSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "base_price_amount",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
 func (r *ProductRepository) applyFilter(query *gorm.DB, filter catalog.ProductFilter) *gorm.DB {
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
+	// SortBy carries a sort the handler already parsed, e.g. -price,name; newest first by default
+	sort, _ := services.ParseSort(filter.SortBy, services.ProductSortFields)
+	query = applySort(query, sort, productSortColumns, "created_at DESC, id ASC")
 	if filter.Limit > 0 {
 		query = query.Limit(filter.Limit)
 	}
@@ -125,6 +201,37 @@ func (r *ProductRepository) applyFilter(query *gorm.DB, filter catalog.ProductFi
 	return query
 }
 
+// productFieldColumns maps the selectable product fields to the columns that back them.
+// sale_price is resolved from pricing and only needs the product ID; media falls back to
+// the images.
+var productFieldColumns = map[string][]string{
+	"sku":              {"sku"},
+	"name":             {"name"},
+	"description":      {"description"},
+	"brand_id":         {"brand_id"},
+	"category_id":      {"category_id"},
+	"base_price":       {"base_price_amount", "base_price_currency"},
+	"status":           {"status"},
+	"images":           {"images"},
+	"image_renditions": {"images"},
+	"media":            {"images"},
+	"attributes":       {"attributes"},
+	"created_at":       {"created_at"},
+	"updated_at":       {"updated_at"},
+}
+
+// applyFields restricts the query to the selected fields' columns; the ID is always loaded
+func (r *ProductRepository) applyFields(query *gorm.DB, fields []string) *gorm.DB {
+	if len(fields) == 0 {
+		return query
+	}
+	columns := []string{"id"}
+	for _, field := range fields {
+		columns = append(columns, productFieldColumns[field]...)
+	}
+	return query.Select(columns)
+}
+
 func (r *ProductRepository) toDomain(dbProduct *database.Product) *catalog.Product {
 	var attributes map[string]string
 	database.UnmarshalJSON(dbProduct.Metadata, &attributes)
@@ -222,10 +329,11 @@ func (r *VariantRepository) FindByProductID(ctx context.Context, productID strin
 	return r.toDomainList(dbVariants), nil
 }
 
-// Save saves a variant
+// Save saves a variant. catalog.Variant has no barcode or unit, so those columns are left
+// as they are; BarcodeRepository and UnitPriceRepository manage them.
 func (r *VariantRepository) Save(ctx context.Context, variant *catalog.Variant) error {
 	dbVariant := r.toDatabase(variant)
-	return r.db.WithContext(ctx).Save(dbVariant).Error
+	return r.db.WithContext(ctx).Omit("barcode", "unit_quantity", "unit_measure").Save(dbVariant).Error
 }
 
 // Delete deletes a variant
@@ -252,7 +360,7 @@ func (r *VariantRepository) toDomain(dbVariant *database.Variant) *catalog.Varia
 		Price:       database.Int64ToMoney(dbVariant.Price, dbVariant.Currency),
 		Attributes:  attributes,
 		Images:      images,
-		IsAvailable: true,
+		IsAvailable: !dbVariant.Archived,
 		CreatedAt:   dbVariant.CreatedAt,
 		UpdatedAt:   dbVariant.UpdatedAt,
 	}
@@ -281,6 +389,7 @@ func (r *VariantRepository) toDatabase(variant *catalog.Variant) *database.Varia
 		Currency:   variant.Price.Currency,
 		Attributes: database.MarshalJSON(variant.Attributes),
 		ImageURL:   imageURL,
+		Archived:   !variant.IsAvailable,
 		CreatedAt:  variant.CreatedAt,
 		UpdatedAt:  variant.UpdatedAt,
 	}
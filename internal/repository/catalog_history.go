@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CatalogVersionRepository implements services.CatalogVersionRepository using GORM
+type CatalogVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogVersionRepository creates a new CatalogVersionRepository
+func NewCatalogVersionRepository(db *gorm.DB) *CatalogVersionRepository {
+	return &CatalogVersionRepository{db: db}
+}
+
+// Append numbers the version after the product's latest and stores it. A transaction-level
+// advisory lock on the product serializes concurrent appends so numbers are never reused.
+func (r *CatalogVersionRepository) Append(ctx context.Context, version *services.CatalogVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "catalog_versions:"+version.ProductID).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&database.CatalogVersion{}).
+			Where("product_id = ?", version.ProductID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+
+		dbVersion, err := r.toDatabase(version)
+		if err != nil {
+			return err
+		}
+		return tx.Create(dbVersion).Error
+	})
+}
+
+// ListByProduct lists a product's versions, newest first
+func (r *CatalogVersionRepository) ListByProduct(ctx context.Context, productID string, limit, offset int) ([]*services.CatalogVersion, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("version DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var dbVersions []database.CatalogVersion
+	if err := query.Find(&dbVersions).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbVersions)
+}
+
+// CountByProduct counts a product's versions
+func (r *CatalogVersionRepository) CountByProduct(ctx context.Context, productID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.CatalogVersion{}).Where("product_id = ?", productID).Count(&count).Error
+	return count, err
+}
+
+// FindByVersion finds one version of a product
+func (r *CatalogVersionRepository) FindByVersion(ctx context.Context, productID string, version int) (*services.CatalogVersion, error) {
+	var dbVersion database.CatalogVersion
+	if err := r.db.WithContext(ctx).First(&dbVersion, "product_id = ? AND version = ?", productID, version).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCatalogVersionNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbVersion)
+}
+
+// ListAfter lists a product's versions newer than version, oldest first
+func (r *CatalogVersionRepository) ListAfter(ctx context.Context, productID string, version int) ([]*services.CatalogVersion, error) {
+	var dbVersions []database.CatalogVersion
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND version > ?", productID, version).
+		Order("version ASC").
+		Find(&dbVersions).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbVersions)
+}
+
+// Helper methods
+
+func (r *CatalogVersionRepository) toDomain(dbVersion *database.CatalogVersion) (*services.CatalogVersion, error) {
+	version := &services.CatalogVersion{
+		ID:         dbVersion.ID,
+		ProductID:  dbVersion.ProductID,
+		Version:    dbVersion.Version,
+		EntityType: services.CatalogEntityType(dbVersion.EntityType),
+		EntityID:   dbVersion.EntityID,
+		Action:     services.CatalogChangeAction(dbVersion.Action),
+		ChangedBy:  dbVersion.ChangedBy,
+		Before:     jsonOrNull([]byte(dbVersion.Before)),
+		After:      jsonOrNull([]byte(dbVersion.After)),
+		CreatedAt:  dbVersion.CreatedAt,
+	}
+	if dbVersion.RevertedTo != nil {
+		version.RevertedTo = *dbVersion.RevertedTo
+	}
+	if err := json.Unmarshal([]byte(dbVersion.Changes), &version.Changes); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (r *CatalogVersionRepository) toDomainList(dbVersions []database.CatalogVersion) ([]*services.CatalogVersion, error) {
+	versions := make([]*services.CatalogVersion, len(dbVersions))
+	for i := range dbVersions {
+		version, err := r.toDomain(&dbVersions[i])
+		if err != nil {
+			return nil, err
+		}
+		versions[i] = version
+	}
+	return versions, nil
+}
+
+func (r *CatalogVersionRepository) toDatabase(version *services.CatalogVersion) (*database.CatalogVersion, error) {
+	changes, err := json.Marshal(version.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	dbVersion := &database.CatalogVersion{
+		ID:         version.ID,
+		ProductID:  version.ProductID,
+		Version:    version.Version,
+		EntityType: string(version.EntityType),
+		EntityID:   version.EntityID,
+		Action:     string(version.Action),
+		ChangedBy:  version.ChangedBy,
+		Changes:    string(changes),
+		Before:     string(jsonOrNull(version.Before)),
+		After:      string(jsonOrNull(version.After)),
+		CreatedAt:  version.CreatedAt,
+	}
+	if version.RevertedTo > 0 {
+		revertedTo := version.RevertedTo
+		dbVersion.RevertedTo = &revertedTo
+	}
+	return dbVersion, nil
+}
+
+// jsonOrNull returns the JSON null literal for an empty document
+func jsonOrNull(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+// CategoryCountRepository implements services.CategoryCountRepository using GORM
+type CategoryCountRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryCountRepository creates a new CategoryCountRepository
+func NewCategoryCountRepository(db *gorm.DB) *CategoryCountRepository {
+	return &CategoryCountRepository{db: db}
+}
+
+// All returns every category's counter, by category ID
+func (r *CategoryCountRepository) All(ctx context.Context) (map[string]int64, error) {
+	var dbCounts []database.CategoryProductCount
+	if err := r.db.WithContext(ctx).Find(&dbCounts).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(dbCounts))
+	for _, dbCount := range dbCounts {
+		counts[dbCount.CategoryID] = dbCount.ProductCount
+	}
+	return counts, nil
+}
+
+// Increment adds delta to a category's counter in one statement, so concurrent saves do
+// not overwrite each other. A missing counter starts from zero.
+func (r *CategoryCountRepository) Increment(ctx context.Context, categoryID string, delta int64) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "category_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"product_count": gorm.Expr("category_product_counts.product_count + ?", delta),
+			"updated_at":    now,
+		}),
+	}).Create(&database.CategoryProductCount{
+		CategoryID:   categoryID,
+		ProductCount: max(delta, 0),
+		UpdatedAt:    now,
+	}).Error
+}
+
+// Recount sets every counter from the active products in the products table, returning
+// how many counters were wrong
+func (r *CategoryCountRepository) Recount(ctx context.Context) (int64, error) {
+	var fixed int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			CategoryID string
+			Count      int64
+		}
+		if err := tx.Model(&database.Product{}).
+			Select("category_id, COUNT(*) AS count").
+			Where("status = ? AND category_id <> ''", string(catalog.ProductStatusActive)).
+			Group("category_id").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		actual := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			actual[row.CategoryID] = row.Count
+		}
+
+		var dbCounts []database.CategoryProductCount
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&dbCounts).Error; err != nil {
+			return err
+		}
+		stored := make(map[string]int64, len(dbCounts))
+		for _, dbCount := range dbCounts {
+			stored[dbCount.CategoryID] = dbCount.ProductCount
+		}
+
+		now := time.Now()
+		for categoryID, count := range actual {
+			if current, ok := stored[categoryID]; ok && current == count {
+				continue
+			}
+			if err := tx.Save(&database.CategoryProductCount{CategoryID: categoryID, ProductCount: count, UpdatedAt: now}).Error; err != nil {
+				return err
+			}
+			fixed++
+		}
+		for categoryID, current := range stored {
+			if _, ok := actual[categoryID]; ok || current == 0 {
+				continue
+			}
+			if err := tx.Save(&database.CategoryProductCount{CategoryID: categoryID, ProductCount: 0, UpdatedAt: now}).Error; err != nil {
+				return err
+			}
+			fixed++
+		}
+		return nil
+	})
+	return fixed, err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CheckoutRuleRepository implements services.CheckoutRuleRepository using GORM
+type CheckoutRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckoutRuleRepository creates a new CheckoutRuleRepository
+func NewCheckoutRuleRepository(db *gorm.DB) *CheckoutRuleRepository {
+	return &CheckoutRuleRepository{db: db}
+}
+
+// FindByID finds a checkout rule by ID
+func (r *CheckoutRuleRepository) FindByID(ctx context.Context, id string) (*services.CheckoutRule, error) {
+	var dbRule database.CheckoutRule
+	if err := r.db.WithContext(ctx).First(&dbRule, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCheckoutRuleNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbRule)
+}
+
+// FindAll finds all checkout rules
+func (r *CheckoutRuleRepository) FindAll(ctx context.Context) ([]*services.CheckoutRule, error) {
+	var dbRules []database.CheckoutRule
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&dbRules).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbRules)
+}
+
+// FindActive finds the checkout rules in force
+func (r *CheckoutRuleRepository) FindActive(ctx context.Context) ([]*services.CheckoutRule, error) {
+	var dbRules []database.CheckoutRule
+	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("created_at ASC").Find(&dbRules).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbRules)
+}
+
+// Save creates or replaces a checkout rule
+func (r *CheckoutRuleRepository) Save(ctx context.Context, rule *services.CheckoutRule) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(rule)).Error
+}
+
+// Delete deletes a checkout rule
+func (r *CheckoutRuleRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.CheckoutRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrCheckoutRuleNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *CheckoutRuleRepository) toDomain(dbRule *database.CheckoutRule) (*services.CheckoutRule, error) {
+	var countries []string
+	if err := database.UnmarshalJSON(dbRule.Countries, &countries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkout rule countries: %w", err)
+	}
+
+	rule := &services.CheckoutRule{
+		ID:        dbRule.ID,
+		Name:      dbRule.Name,
+		Type:      services.CheckoutRuleType(dbRule.Type),
+		ProductID: dbRule.ProductID,
+		Countries: countries,
+		Multiple:  dbRule.Multiple,
+		IsActive:  dbRule.IsActive,
+		CreatedAt: dbRule.CreatedAt,
+		UpdatedAt: dbRule.UpdatedAt,
+	}
+	if dbRule.MinOrderAmount != nil {
+		minimum := database.Int64ToMoney(*dbRule.MinOrderAmount, dbRule.MinOrderCurrency)
+		rule.MinOrderValue = &minimum
+	}
+	return rule, nil
+}
+
+func (r *CheckoutRuleRepository) toDomainList(dbRules []database.CheckoutRule) ([]*services.CheckoutRule, error) {
+	rules := make([]*services.CheckoutRule, len(dbRules))
+	for i := range dbRules {
+		rule, err := r.toDomain(&dbRules[i])
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
+
+func (r *CheckoutRuleRepository) toDatabase(rule *services.CheckoutRule) *database.CheckoutRule {
+	dbRule := &database.CheckoutRule{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		Type:      string(rule.Type),
+		ProductID: rule.ProductID,
+		Multiple:  rule.Multiple,
+		IsActive:  rule.IsActive,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	if len(rule.Countries) > 0 {
+		dbRule.Countries = database.MarshalJSON(rule.Countries)
+	}
+	if rule.MinOrderValue != nil {
+		amount := database.MoneyToInt64(*rule.MinOrderValue)
+		dbRule.MinOrderAmount = &amount
+		dbRule.MinOrderCurrency = rule.MinOrderValue.Currency
+	}
+	return dbRule
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+// CollectionRepository implements services.CollectionRepository using GORM
+type CollectionRepository struct {
+	db       *gorm.DB
+	products *ProductRepository
+}
+
+// NewCollectionRepository creates a new CollectionRepository
+func NewCollectionRepository(db *gorm.DB) *CollectionRepository {
+	return &CollectionRepository{db: db, products: NewProductRepository(db)}
+}
+
+// FindByID finds a collection by ID
+func (r *CollectionRepository) FindByID(ctx context.Context, id string) (*services.Collection, error) {
+	return r.find(ctx, "id = ?", id)
+}
+
+// FindBySlug finds a collection by slug
+func (r *CollectionRepository) FindBySlug(ctx context.Context, slug string) (*services.Collection, error) {
+	return r.find(ctx, "slug = ?", slug)
+}
+
+// collectionSortColumns maps services.CollectionSortFields to their columns
+var collectionSortColumns = map[string]string{
+	"slug":       "slug",
+	"title":      "title",
+	"type":       "type",
+	"status":     "status",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// List lists collections matching the filter in the filter's order, then by slug. Product
+// lists are not loaded.
+func (r *CollectionRepository) List(ctx context.Context, filter services.CollectionFilter) ([]*services.Collection, error) {
+	query := applySort(r.applyFilter(r.db.WithContext(ctx), filter), filter.Sort, collectionSortColumns, "slug ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbCollections []database.Collection
+	if err := query.Find(&dbCollections).Error; err != nil {
+		return nil, err
+	}
+
+	collections := make([]*services.Collection, len(dbCollections))
+	for i, dbCollection := range dbCollections {
+		collections[i] = r.toDomain(&dbCollection)
+	}
+	return collections, nil
+}
+
+// Count counts collections matching the filter
+func (r *CollectionRepository) Count(ctx context.Context, filter services.CollectionFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.WithContext(ctx).Model(&database.Collection{}), filter).Count(&count).Error
+	return count, err
+}
+
+// Save saves a collection and replaces its product list
+func (r *CollectionRepository) Save(ctx context.Context, collection *services.Collection) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(r.toDatabase(collection)).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&database.CollectionProduct{}, "collection_id = ?", collection.ID).Error; err != nil {
+			return err
+		}
+		if len(collection.ProductIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]database.CollectionProduct, len(collection.ProductIDs))
+		for i, productID := range collection.ProductIDs {
+			rows[i] = database.CollectionProduct{CollectionID: collection.ID, ProductID: productID, Position: i}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Delete deletes a collection; its product list goes with it
+func (r *CollectionRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.Collection{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrCollectionNotFound
+	}
+	return nil
+}
+
+// FindProducts finds the active products in a collection
+func (r *CollectionRepository) FindProducts(ctx context.Context, collection *services.Collection, conditions []services.CollectionCondition, limit, offset int) ([]*catalog.Product, error) {
+	query := r.productQuery(ctx, collection, conditions)
+	if collection.Type == services.CollectionManual {
+		query = query.Order("collection_products.position ASC")
+	} else {
+		query = query.Order("products.name ASC")
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var dbProducts []database.Product
+	if err := query.Find(&dbProducts).Error; err != nil {
+		return nil, err
+	}
+	return r.products.toDomainList(dbProducts), nil
+}
+
+// CountProducts counts the active products in a collection
+func (r *CollectionRepository) CountProducts(ctx context.Context, collection *services.Collection, conditions []services.CollectionCondition) (int64, error) {
+	var count int64
+	err := r.productQuery(ctx, collection, conditions).Count(&count).Error
+	return count, err
+}
+
+// FindTags finds a product's tags, sorted
+func (r *CollectionRepository) FindTags(ctx context.Context, productID string) ([]string, error) {
+	tags := make([]string, 0)
+	err := r.db.WithContext(ctx).Model(&database.ProductTag{}).
+		Where("product_id = ?", productID).
+		Order("tag ASC").
+		Pluck("tag", &tags).Error
+	return tags, err
+}
+
+// SetTags replaces a product's tags
+func (r *CollectionRepository) SetTags(ctx context.Context, productID string, tags []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&database.ProductTag{}, "product_id = ?", productID).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		rows := make([]database.ProductTag, len(tags))
+		for i, tag := range tags {
+			rows[i] = database.ProductTag{ProductID: productID, Tag: tag}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Helper methods
+
+func (r *CollectionRepository) find(ctx context.Context, query string, arg string) (*services.Collection, error) {
+	var dbCollection database.Collection
+	if err := r.db.WithContext(ctx).First(&dbCollection, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCollectionNotFound
+		}
+		return nil, err
+	}
+
+	collection := r.toDomain(&dbCollection)
+	if collection.Type == services.CollectionManual {
+		err := r.db.WithContext(ctx).Model(&database.CollectionProduct{}).
+			Where("collection_id = ?", collection.ID).
+			Order("position ASC").
+			Pluck("product_id", &collection.ProductIDs).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return collection, nil
+}
+
+func (r *CollectionRepository) applyFilter(query *gorm.DB, filter services.CollectionFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
+
+// productQuery selects the collection's active products. Manual collections join their
+// product list; rule collections translate each condition to SQL. Operators were checked
+// when the rule was parsed, so they are safe to place in the query.
+func (r *CollectionRepository) productQuery(ctx context.Context, collection *services.Collection, conditions []services.CollectionCondition) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&database.Product{}).
+		Where("products.status = ?", string(catalog.ProductStatusActive))
+
+	if collection.Type == services.CollectionManual {
+		return query.Joins("JOIN collection_products ON collection_products.product_id = products.id").
+			Where("collection_products.collection_id = ?", collection.ID)
+	}
+
+	for _, condition := range conditions {
+		operator := condition.Operator
+		if operator == "!=" {
+			operator = "<>"
+		}
+		switch condition.Field {
+		case "price":
+			query = query.Where("products.base_price_amount "+operator+" ?", condition.Cents)
+		case "category":
+			query = query.Where("products.category_id "+operator+" ?", condition.Value)
+		case "brand":
+			query = query.Where("products.brand_id "+operator+" ?", condition.Value)
+		case "tag":
+			exists := "EXISTS (SELECT 1 FROM product_tags WHERE product_tags.product_id = products.id AND product_tags.tag = ?)"
+			if condition.Operator == "!=" {
+				exists = "NOT " + exists
+			}
+			query = query.Where(exists, condition.Value)
+		}
+	}
+	return query
+}
+
+func (r *CollectionRepository) toDomain(dbCollection *database.Collection) *services.Collection {
+	return &services.Collection{
+		ID:          dbCollection.ID,
+		Slug:        dbCollection.Slug,
+		Title:       dbCollection.Title,
+		Description: dbCollection.Description,
+		Type:        services.CollectionType(dbCollection.Type),
+		Rule:        dbCollection.Rule,
+		Status:      services.CollectionStatus(dbCollection.Status),
+		CreatedAt:   dbCollection.CreatedAt,
+		UpdatedAt:   dbCollection.UpdatedAt,
+	}
+}
+
+func (r *CollectionRepository) toDatabase(collection *services.Collection) *database.Collection {
+	return &database.Collection{
+		ID:          collection.ID,
+		Slug:        collection.Slug,
+		Title:       collection.Title,
+		Description: collection.Description,
+		Type:        string(collection.Type),
+		Rule:        collection.Rule,
+		Status:      string(collection.Status),
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// CompanyRepository implements services.CompanyRepository using GORM
+type CompanyRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyRepository creates a new CompanyRepository
+func NewCompanyRepository(db *gorm.DB) *CompanyRepository {
+	return &CompanyRepository{db: db}
+}
+
+// FindByID finds a company by ID
+func (r *CompanyRepository) FindByID(ctx context.Context, id string) (*services.Company, error) {
+	var dbCompany database.Company
+	if err := r.db.WithContext(ctx).First(&dbCompany, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCompanyNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbCompany), nil
+}
+
+// List lists companies ordered by name
+func (r *CompanyRepository) List(ctx context.Context, limit, offset int) ([]*services.Company, error) {
+	query := r.db.WithContext(ctx).Order("name ASC, id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var dbCompanies []database.Company
+	if err := query.Find(&dbCompanies).Error; err != nil {
+		return nil, err
+	}
+
+	companies := make([]*services.Company, len(dbCompanies))
+	for i := range dbCompanies {
+		companies[i] = r.toDomain(&dbCompanies[i])
+	}
+	return companies, nil
+}
+
+// Save saves a company
+func (r *CompanyRepository) Save(ctx context.Context, company *services.Company) error {
+	dbCompany := &database.Company{
+		ID:           company.ID,
+		Name:         company.Name,
+		NetTermsDays: company.NetTermsDays,
+		CreatedAt:    company.CreatedAt,
+		UpdatedAt:    company.UpdatedAt,
+	}
+	if company.ApprovalThreshold != nil {
+		amount := company.ApprovalThreshold.Amount
+		dbCompany.ApprovalThresholdAmount = &amount
+		dbCompany.ApprovalThresholdCurrency = company.ApprovalThreshold.Currency
+	}
+	return r.db.WithContext(ctx).Save(dbCompany).Error
+}
+
+// FindMember finds a user's company membership, or nil when they have none
+func (r *CompanyRepository) FindMember(ctx context.Context, userID string) (*services.CompanyMember, error) {
+	var dbMember database.CompanyMember
+	err := r.db.WithContext(ctx).First(&dbMember, "user_id = ?", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCompanyMember(&dbMember), nil
+}
+
+// ListMembers lists a company's members, approvers first
+func (r *CompanyRepository) ListMembers(ctx context.Context, companyID string) ([]*services.CompanyMember, error) {
+	var dbMembers []database.CompanyMember
+	err := r.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("role ASC, created_at ASC").
+		Find(&dbMembers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*services.CompanyMember, len(dbMembers))
+	for i := range dbMembers {
+		members[i] = toCompanyMember(&dbMembers[i])
+	}
+	return members, nil
+}
+
+// SaveMember saves a company membership
+func (r *CompanyRepository) SaveMember(ctx context.Context, member *services.CompanyMember) error {
+	return r.db.WithContext(ctx).Save(&database.CompanyMember{
+		UserID:    member.UserID,
+		CompanyID: member.CompanyID,
+		Role:      string(member.Role),
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}).Error
+}
+
+// DeleteMember deletes a user's company membership
+func (r *CompanyRepository) DeleteMember(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Delete(&database.CompanyMember{}, "user_id = ?", userID).Error
+}
+
+// FindAddress finds an address book entry by ID
+func (r *CompanyRepository) FindAddress(ctx context.Context, id string) (*services.CompanyAddress, error) {
+	var dbAddress database.CompanyAddress
+	if err := r.db.WithContext(ctx).First(&dbAddress, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrCompanyAddressNotFound
+		}
+		return nil, err
+	}
+	return toCompanyAddress(&dbAddress), nil
+}
+
+// ListAddresses lists a company's address book ordered by label
+func (r *CompanyRepository) ListAddresses(ctx context.Context, companyID string) ([]*services.CompanyAddress, error) {
+	var dbAddresses []database.CompanyAddress
+	err := r.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("label ASC, created_at ASC").
+		Find(&dbAddresses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*services.CompanyAddress, len(dbAddresses))
+	for i := range dbAddresses {
+		addresses[i] = toCompanyAddress(&dbAddresses[i])
+	}
+	return addresses, nil
+}
+
+// SaveAddress saves an address book entry
+func (r *CompanyRepository) SaveAddress(ctx context.Context, address *services.CompanyAddress) error {
+	return r.db.WithContext(ctx).Save(&database.CompanyAddress{
+		ID:         address.ID,
+		CompanyID:  address.CompanyID,
+		Label:      address.Label,
+		FirstName:  address.Address.FirstName,
+		LastName:   address.Address.LastName,
+		Company:    address.Address.Company,
+		Address1:   address.Address.AddressLine1,
+		Address2:   address.Address.AddressLine2,
+		City:       address.Address.City,
+		State:      address.Address.State,
+		PostalCode: address.Address.PostalCode,
+		Country:    address.Address.Country,
+		Phone:      address.Address.Phone,
+		CreatedBy:  address.CreatedBy,
+		CreatedAt:  address.CreatedAt,
+		UpdatedAt:  address.UpdatedAt,
+	}).Error
+}
+
+// DeleteAddress deletes an address book entry
+func (r *CompanyRepository) DeleteAddress(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.CompanyAddress{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrCompanyAddressNotFound
+	}
+	return nil
+}
+
+// FindOrder finds the company record of an order, or nil when it wasn't placed for a company
+func (r *CompanyRepository) FindOrder(ctx context.Context, orderID string) (*services.CompanyOrder, error) {
+	var dbOrder database.CompanyOrder
+	err := r.db.WithContext(ctx).First(&dbOrder, "order_id = ?", orderID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCompanyOrder(&dbOrder), nil
+}
+
+// ListOrders lists a company's orders, newest first
+func (r *CompanyRepository) ListOrders(ctx context.Context, companyID string, filter services.CompanyOrderFilter) ([]*services.CompanyOrder, error) {
+	query := r.db.WithContext(ctx).Where("company_id = ?", companyID)
+	if filter.ApprovalStatus != "" {
+		query = query.Where("approval_status = ?", string(filter.ApprovalStatus))
+	}
+	query = query.Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbOrders []database.CompanyOrder
+	if err := query.Find(&dbOrders).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]*services.CompanyOrder, len(dbOrders))
+	for i := range dbOrders {
+		records[i] = toCompanyOrder(&dbOrders[i])
+	}
+	return records, nil
+}
+
+// SaveOrder saves the company record of an order
+func (r *CompanyRepository) SaveOrder(ctx context.Context, record *services.CompanyOrder) error {
+	return r.db.WithContext(ctx).Save(&database.CompanyOrder{
+		OrderID:        record.OrderID,
+		CompanyID:      record.CompanyID,
+		PlacedBy:       record.PlacedBy,
+		TotalAmount:    record.Total.Amount,
+		TotalCurrency:  record.Total.Currency,
+		ApprovalStatus: string(record.ApprovalStatus),
+		DecidedBy:      record.DecidedBy,
+		DecidedAt:      record.DecidedAt,
+		Reason:         record.Reason,
+		CreatedAt:      record.CreatedAt,
+		UpdatedAt:      record.UpdatedAt,
+	}).Error
+}
+
+// Helper methods
+
+func (r *CompanyRepository) toDomain(dbCompany *database.Company) *services.Company {
+	company := &services.Company{
+		ID:           dbCompany.ID,
+		Name:         dbCompany.Name,
+		NetTermsDays: dbCompany.NetTermsDays,
+		CreatedAt:    dbCompany.CreatedAt,
+		UpdatedAt:    dbCompany.UpdatedAt,
+	}
+	if dbCompany.ApprovalThresholdAmount != nil {
+		company.ApprovalThreshold = &money.Money{
+			Amount:   *dbCompany.ApprovalThresholdAmount,
+			Currency: dbCompany.ApprovalThresholdCurrency,
+		}
+	}
+	return company
+}
+
+func toCompanyMember(dbMember *database.CompanyMember) *services.CompanyMember {
+	return &services.CompanyMember{
+		CompanyID: dbMember.CompanyID,
+		UserID:    dbMember.UserID,
+		Role:      services.CompanyRole(dbMember.Role),
+		CreatedAt: dbMember.CreatedAt,
+		UpdatedAt: dbMember.UpdatedAt,
+	}
+}
+
+func toCompanyAddress(dbAddress *database.CompanyAddress) *services.CompanyAddress {
+	return &services.CompanyAddress{
+		ID:        dbAddress.ID,
+		CompanyID: dbAddress.CompanyID,
+		Label:     dbAddress.Label,
+		Address: orders.Address{
+			FirstName:    dbAddress.FirstName,
+			LastName:     dbAddress.LastName,
+			Company:      dbAddress.Company,
+			AddressLine1: dbAddress.Address1,
+			AddressLine2: dbAddress.Address2,
+			City:         dbAddress.City,
+			State:        dbAddress.State,
+			PostalCode:   dbAddress.PostalCode,
+			Country:      dbAddress.Country,
+			Phone:        dbAddress.Phone,
+		},
+		CreatedBy: dbAddress.CreatedBy,
+		CreatedAt: dbAddress.CreatedAt,
+		UpdatedAt: dbAddress.UpdatedAt,
+	}
+}
+
+func toCompanyOrder(dbOrder *database.CompanyOrder) *services.CompanyOrder {
+	return &services.CompanyOrder{
+		OrderID:        dbOrder.OrderID,
+		CompanyID:      dbOrder.CompanyID,
+		PlacedBy:       dbOrder.PlacedBy,
+		Total:          money.Money{Amount: dbOrder.TotalAmount, Currency: dbOrder.TotalCurrency},
+		ApprovalStatus: services.ApprovalStatus(dbOrder.ApprovalStatus),
+		DecidedBy:      dbOrder.DecidedBy,
+		DecidedAt:      dbOrder.DecidedAt,
+		Reason:         dbOrder.Reason,
+		CreatedAt:      dbOrder.CreatedAt,
+		UpdatedAt:      dbOrder.UpdatedAt,
+	}
+}
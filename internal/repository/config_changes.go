@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ConfigChangeRepository implements services.ConfigChangeRepository using GORM
+type ConfigChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewConfigChangeRepository creates a new ConfigChangeRepository
+func NewConfigChangeRepository(db *gorm.DB) *ConfigChangeRepository {
+	return &ConfigChangeRepository{db: db}
+}
+
+// CreateBatch stores the changes of one reload in a single transaction
+func (r *ConfigChangeRepository) CreateBatch(ctx context.Context, changes []*services.ConfigChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	dbChanges := make([]*database.ConfigChange, len(changes))
+	for i, change := range changes {
+		dbChanges[i] = r.toDatabase(change)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&dbChanges).Error
+	})
+}
+
+// List lists configuration changes matching the filter, newest first
+func (r *ConfigChangeRepository) List(ctx context.Context, filter services.ConfigChangeFilter) ([]*services.ConfigChange, error) {
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&database.ConfigChange{}), filter).
+		Order("changed_at DESC, setting ASC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbChanges []database.ConfigChange
+	if err := query.Find(&dbChanges).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make([]*services.ConfigChange, len(dbChanges))
+	for i, dbChange := range dbChanges {
+		changes[i] = r.toDomain(&dbChange)
+	}
+	return changes, nil
+}
+
+// Count counts configuration changes matching the filter
+func (r *ConfigChangeRepository) Count(ctx context.Context, filter services.ConfigChangeFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.WithContext(ctx).Model(&database.ConfigChange{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Helper methods
+
+func (r *ConfigChangeRepository) applyFilter(query *gorm.DB, filter services.ConfigChangeFilter) *gorm.DB {
+	if filter.Setting != "" {
+		query = query.Where("setting = ?", filter.Setting)
+	}
+	return query
+}
+
+func (r *ConfigChangeRepository) toDomain(dbChange *database.ConfigChange) *services.ConfigChange {
+	return &services.ConfigChange{
+		ID:        dbChange.ID,
+		Setting:   dbChange.Setting,
+		OldValue:  dbChange.OldValue,
+		NewValue:  dbChange.NewValue,
+		Source:    services.ConfigChangeSource(dbChange.Source),
+		ActorID:   dbChange.ActorID,
+		ChangedAt: dbChange.ChangedAt,
+	}
+}
+
+func (r *ConfigChangeRepository) toDatabase(change *services.ConfigChange) *database.ConfigChange {
+	return &database.ConfigChange{
+		ID:        change.ID,
+		Setting:   change.Setting,
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Source:    string(change.Source),
+		ActorID:   change.ActorID,
+		ChangedAt: change.ChangedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ConsentRepository implements services.ConsentRepository using GORM
+type ConsentRepository struct {
+	db *gorm.DB
+}
+
+// NewConsentRepository creates a new ConsentRepository
+func NewConsentRepository(db *gorm.DB) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+// Append stores consent events together
+func (r *ConsentRepository) Append(ctx context.Context, events []*services.ConsentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	dbEvents := make([]database.ConsentEvent, len(events))
+	for i, event := range events {
+		dbEvents[i] = r.toDatabase(event)
+	}
+	return r.db.WithContext(ctx).Create(&dbEvents).Error
+}
+
+// FindLatest finds a user's most recent event for each purpose
+func (r *ConsentRepository) FindLatest(ctx context.Context, userID string) ([]*services.ConsentEvent, error) {
+	events, err := r.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[services.ConsentPurpose]bool)
+	var latest []*services.ConsentEvent
+	for _, event := range events {
+		if !seen[event.Purpose] {
+			seen[event.Purpose] = true
+			latest = append(latest, event)
+		}
+	}
+	return latest, nil
+}
+
+// ListByUser lists a user's consent events, newest first
+func (r *ConsentRepository) ListByUser(ctx context.Context, userID string) ([]*services.ConsentEvent, error) {
+	var dbEvents []database.ConsentEvent
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("recorded_at DESC, id DESC").
+		Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*services.ConsentEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = r.toDomain(&dbEvent)
+	}
+	return events, nil
+}
+
+// Helper methods
+
+func (r *ConsentRepository) toDomain(dbEvent *database.ConsentEvent) *services.ConsentEvent {
+	return &services.ConsentEvent{
+		ID:            dbEvent.ID,
+		UserID:        dbEvent.UserID,
+		Purpose:       services.ConsentPurpose(dbEvent.Purpose),
+		Granted:       dbEvent.Granted,
+		PolicyVersion: dbEvent.PolicyVersion,
+		Source:        dbEvent.Source,
+		RecordedAt:    dbEvent.RecordedAt,
+	}
+}
+
+func (r *ConsentRepository) toDatabase(event *services.ConsentEvent) database.ConsentEvent {
+	return database.ConsentEvent{
+		ID:            event.ID,
+		UserID:        event.UserID,
+		Purpose:       string(event.Purpose),
+		Granted:       event.Granted,
+		PolicyVersion: event.PolicyVersion,
+		Source:        event.Source,
+		RecordedAt:    event.RecordedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ContentReportRepository implements services.ContentReportRepository using GORM
+type ContentReportRepository struct {
+	db *gorm.DB
+}
+
+// NewContentReportRepository creates a new ContentReportRepository
+func NewContentReportRepository(db *gorm.DB) *ContentReportRepository {
+	return &ContentReportRepository{db: db}
+}
+
+// Add records a report. The unique index on content and user turns a second report from
+// the same user into ErrAlreadyReported.
+func (r *ContentReportRepository) Add(ctx context.Context, report *services.ContentReport) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&database.ContentReport{
+		ID:          report.ID,
+		ContentType: string(report.ContentType),
+		ContentID:   report.ContentID,
+		UserID:      report.UserID,
+		Reason:      string(report.Reason),
+		Details:     report.Details,
+		Status:      string(report.Status),
+		CreatedAt:   report.CreatedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrAlreadyReported
+	}
+	return nil
+}
+
+// CountOpen counts the open reports on a piece of content
+func (r *ContentReportRepository) CountOpen(ctx context.Context, contentType services.ReportContentType, contentID string) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.ContentReport{}).
+		Where("content_type = ? AND content_id = ? AND status = ?", string(contentType), contentID, string(services.ReportStatusOpen)).
+		Count(&count).Error
+	return int(count), err
+}
+
+// FindOpen finds every open report, oldest first
+func (r *ContentReportRepository) FindOpen(ctx context.Context) ([]*services.ContentReport, error) {
+	var dbReports []database.ContentReport
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", string(services.ReportStatusOpen)).
+		Order("created_at ASC").
+		Find(&dbReports).Error; err != nil {
+		return nil, err
+	}
+
+	reports := make([]*services.ContentReport, len(dbReports))
+	for i := range dbReports {
+		reports[i] = r.toDomain(&dbReports[i])
+	}
+	return reports, nil
+}
+
+// Resolve closes the open reports on a piece of content
+func (r *ContentReportRepository) Resolve(ctx context.Context, contentType services.ReportContentType, contentID string, status services.ReportStatus, resolvedBy string, resolvedAt time.Time) (int, error) {
+	result := r.db.WithContext(ctx).Model(&database.ContentReport{}).
+		Where("content_type = ? AND content_id = ? AND status = ?", string(contentType), contentID, string(services.ReportStatusOpen)).
+		Updates(map[string]interface{}{
+			"status":      string(status),
+			"resolved_by": resolvedBy,
+			"resolved_at": resolvedAt,
+		})
+	return int(result.RowsAffected), result.Error
+}
+
+// Helper methods
+
+func (r *ContentReportRepository) toDomain(dbReport *database.ContentReport) *services.ContentReport {
+	return &services.ContentReport{
+		ID:          dbReport.ID,
+		ContentType: services.ReportContentType(dbReport.ContentType),
+		ContentID:   dbReport.ContentID,
+		UserID:      dbReport.UserID,
+		Reason:      services.ReportReason(dbReport.Reason),
+		Details:     dbReport.Details,
+		Status:      services.ReportStatus(dbReport.Status),
+		ResolvedBy:  dbReport.ResolvedBy,
+		ResolvedAt:  dbReport.ResolvedAt,
+		CreatedAt:   dbReport.CreatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// CustomerRepository implements services.CustomerRepository using GORM
+type CustomerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository creates a new CustomerRepository
+func NewCustomerRepository(db *gorm.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// FindByUserID finds a customer's profile, or nil when they have none
+func (r *CustomerRepository) FindByUserID(ctx context.Context, userID string) (*services.CustomerProfile, error) {
+	var dbCustomer database.Customer
+	err := r.db.WithContext(ctx).First(&dbCustomer, "user_id = ?", userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &services.CustomerProfile{
+		UserID:    dbCustomer.UserID,
+		FirstName: dbCustomer.FirstName,
+		LastName:  dbCustomer.LastName,
+		Phone:     dbCustomer.Phone,
+		Birthday:  dbCustomer.Birthday,
+		AvatarKey: dbCustomer.AvatarKey,
+		AvatarURL: dbCustomer.AvatarURL,
+		CreatedAt: dbCustomer.CreatedAt,
+		UpdatedAt: dbCustomer.UpdatedAt,
+	}, nil
+}
+
+// Save saves a customer's profile
+func (r *CustomerRepository) Save(ctx context.Context, profile *services.CustomerProfile) error {
+	return r.db.WithContext(ctx).Save(&database.Customer{
+		UserID:    profile.UserID,
+		FirstName: profile.FirstName,
+		LastName:  profile.LastName,
+		Phone:     profile.Phone,
+		Birthday:  profile.Birthday,
+		AvatarKey: profile.AvatarKey,
+		AvatarURL: profile.AvatarURL,
+		CreatedAt: profile.CreatedAt,
+		UpdatedAt: profile.UpdatedAt,
+	}).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DeadLetterRepository implements services.DeadLetterRepository using GORM
+type DeadLetterRepository struct {
+	db *gorm.DB
+}
+
+// NewDeadLetterRepository creates a new DeadLetterRepository
+func NewDeadLetterRepository(db *gorm.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+// FindByID finds a dead letter by ID
+func (r *DeadLetterRepository) FindByID(ctx context.Context, id string) (*services.DeadLetter, error) {
+	var dbLetter database.DeadLetter
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dbLetter).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbLetter), nil
+}
+
+// List lists dead letters matching the filter, newest failure first
+func (r *DeadLetterRepository) List(ctx context.Context, filter services.DeadLetterFilter) ([]*services.DeadLetter, error) {
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&database.DeadLetter{}), filter).
+		Order("failed_at DESC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbLetters []database.DeadLetter
+	if err := query.Find(&dbLetters).Error; err != nil {
+		return nil, err
+	}
+
+	letters := make([]*services.DeadLetter, len(dbLetters))
+	for i, dbLetter := range dbLetters {
+		letters[i] = r.toDomain(&dbLetter)
+	}
+	return letters, nil
+}
+
+// Count counts dead letters matching the filter
+func (r *DeadLetterRepository) Count(ctx context.Context, filter services.DeadLetterFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.WithContext(ctx).Model(&database.DeadLetter{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Save saves a dead letter
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *services.DeadLetter) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(letter)).Error
+}
+
+// Helper methods
+
+func (r *DeadLetterRepository) applyFilter(query *gorm.DB, filter services.DeadLetterFilter) *gorm.DB {
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	return query
+}
+
+func (r *DeadLetterRepository) toDomain(dbLetter *database.DeadLetter) *services.DeadLetter {
+	return &services.DeadLetter{
+		ID:         dbLetter.ID,
+		Kind:       dbLetter.Kind,
+		Name:       dbLetter.Name,
+		Payload:    []byte(dbLetter.Payload),
+		Status:     services.DeadLetterStatus(dbLetter.Status),
+		Attempts:   dbLetter.Attempts,
+		LastError:  dbLetter.LastError,
+		CreatedAt:  dbLetter.CreatedAt,
+		FailedAt:   dbLetter.FailedAt,
+		ReplayedAt: dbLetter.ReplayedAt,
+	}
+}
+
+func (r *DeadLetterRepository) toDatabase(letter *services.DeadLetter) *database.DeadLetter {
+	return &database.DeadLetter{
+		ID:         letter.ID,
+		Kind:       letter.Kind,
+		Name:       letter.Name,
+		Payload:    string(letter.Payload),
+		Status:     string(letter.Status),
+		Attempts:   letter.Attempts,
+		LastError:  letter.LastError,
+		CreatedAt:  letter.CreatedAt,
+		FailedAt:   letter.FailedAt,
+		ReplayedAt: letter.ReplayedAt,
+	}
+}
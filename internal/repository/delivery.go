@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DeliverySlotRepository implements services.DeliverySlotRepository using GORM
+type DeliverySlotRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliverySlotRepository creates a new DeliverySlotRepository
+func NewDeliverySlotRepository(db *gorm.DB) *DeliverySlotRepository {
+	return &DeliverySlotRepository{db: db}
+}
+
+// FindByID finds a delivery slot by ID
+func (r *DeliverySlotRepository) FindByID(ctx context.Context, id string) (*services.DeliverySlot, error) {
+	var dbSlot database.DeliverySlot
+	if err := r.db.WithContext(ctx).First(&dbSlot, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrDeliverySlotNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbSlot), nil
+}
+
+// FindActiveBetween finds active slots starting within the given range
+func (r *DeliverySlotRepository) FindActiveBetween(ctx context.Context, from, to time.Time) ([]*services.DeliverySlot, error) {
+	var dbSlots []database.DeliverySlot
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ? AND starts_at >= ? AND starts_at < ?", true, from, to).
+		Order("starts_at ASC").
+		Find(&dbSlots).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make([]*services.DeliverySlot, len(dbSlots))
+	for i, dbSlot := range dbSlots {
+		slots[i] = r.toDomain(&dbSlot)
+	}
+	return slots, nil
+}
+
+// FindByOrderID finds the slot booked for an order
+func (r *DeliverySlotRepository) FindByOrderID(ctx context.Context, orderID string) (*services.DeliverySlot, error) {
+	var dbSlot database.DeliverySlot
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN order_delivery_slots ON order_delivery_slots.slot_id = delivery_slots.id").
+		Where("order_delivery_slots.order_id = ?", orderID).
+		First(&dbSlot).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrDeliverySlotNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbSlot), nil
+}
+
+// Save saves a delivery slot
+func (r *DeliverySlotRepository) Save(ctx context.Context, slot *services.DeliverySlot) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(slot)).Error
+}
+
+// Reserve books one unit of capacity for the order in a single transaction.
+// The booking row is inserted first so a repeated reservation for the same order
+// takes no capacity, and the conditional update guards against overbooking when
+// orders race for the last unit.
+func (r *DeliverySlotRepository) Reserve(ctx context.Context, slotID, orderID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		booking := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&database.OrderDeliverySlot{
+			OrderID:    orderID,
+			SlotID:     slotID,
+			ReservedAt: time.Now(),
+		})
+		if booking.Error != nil {
+			return booking.Error
+		}
+		if booking.RowsAffected == 0 {
+			return nil
+		}
+
+		result := tx.Model(&database.DeliverySlot{}).
+			Where("id = ? AND is_active = ? AND reserved < capacity", slotID, true).
+			Updates(map[string]interface{}{
+				"reserved":   gorm.Expr("reserved + 1"),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrDeliverySlotFull
+		}
+		return nil
+	})
+}
+
+// Release frees the capacity held by an order
+func (r *DeliverySlotRepository) Release(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var booking database.OrderDeliverySlot
+		if err := tx.First(&booking, "order_id = ?", orderID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&database.DeliverySlot{}).
+			Where("id = ? AND reserved > 0", booking.SlotID).
+			Update("reserved", gorm.Expr("reserved - 1")).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&database.OrderDeliverySlot{}, "order_id = ?", orderID).Error
+	})
+}
+
+// Helper methods
+
+func (r *DeliverySlotRepository) toDomain(dbSlot *database.DeliverySlot) *services.DeliverySlot {
+	return &services.DeliverySlot{
+		ID:       dbSlot.ID,
+		Region:   dbSlot.Region,
+		StartsAt: dbSlot.StartsAt,
+		EndsAt:   dbSlot.EndsAt,
+		Capacity: dbSlot.Capacity,
+		Reserved: dbSlot.Reserved,
+		IsActive: dbSlot.IsActive,
+	}
+}
+
+func (r *DeliverySlotRepository) toDatabase(slot *services.DeliverySlot) *database.DeliverySlot {
+	now := time.Now()
+	return &database.DeliverySlot{
+		ID:        slot.ID,
+		Region:    services.NormalizePostalCode(slot.Region),
+		StartsAt:  slot.StartsAt,
+		EndsAt:    slot.EndsAt,
+		Capacity:  slot.Capacity,
+		Reserved:  slot.Reserved,
+		IsActive:  slot.IsActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
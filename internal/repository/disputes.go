@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DisputeRepository implements services.DisputeRepository using GORM
+type DisputeRepository struct {
+	db     *gorm.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewDisputeRepository creates a new DisputeRepository
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// WithCipher encrypts evidence notes at rest
+func (r *DisputeRepository) WithCipher(cipher *fieldcrypt.Cipher) *DisputeRepository {
+	r.cipher = cipher
+	return r
+}
+
+// FindByID finds a dispute by ID
+func (r *DisputeRepository) FindByID(ctx context.Context, id string) (*services.Dispute, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByGatewayID finds a dispute by the gateway's dispute ID
+func (r *DisputeRepository) FindByGatewayID(ctx context.Context, gatewayDisputeID string) (*services.Dispute, error) {
+	return r.findOne(ctx, "gateway_dispute_id = ?", gatewayDisputeID)
+}
+
+// FindByOrderID finds all disputes raised against an order
+func (r *DisputeRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.Dispute, error) {
+	var dbDisputes []database.Dispute
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbDisputes).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbDisputes)
+}
+
+// List lists disputes matching the filter, soonest evidence deadline first
+func (r *DisputeRepository) List(ctx context.Context, filter services.DisputeFilter) ([]*services.Dispute, error) {
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&database.Dispute{}), filter).
+		Order("evidence_due_by ASC NULLS LAST, created_at ASC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbDisputes []database.Dispute
+	if err := query.Find(&dbDisputes).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbDisputes)
+}
+
+// Count counts disputes matching the filter
+func (r *DisputeRepository) Count(ctx context.Context, filter services.DisputeFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.WithContext(ctx).Model(&database.Dispute{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Save saves a dispute
+func (r *DisputeRepository) Save(ctx context.Context, dispute *services.Dispute) error {
+	dbDispute, err := r.toDatabase(dispute)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Save(dbDispute).Error
+}
+
+// Helper methods
+
+func (r *DisputeRepository) findOne(ctx context.Context, query string, args ...interface{}) (*services.Dispute, error) {
+	var dbDispute database.Dispute
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&dbDispute).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbDispute)
+}
+
+func (r *DisputeRepository) applyFilter(query *gorm.DB, filter services.DisputeFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.EvidenceStatus != "" {
+		query = query.Where("evidence_status = ?", string(filter.EvidenceStatus))
+	}
+	return query
+}
+
+func (r *DisputeRepository) toDomainList(dbDisputes []database.Dispute) ([]*services.Dispute, error) {
+	disputes := make([]*services.Dispute, len(dbDisputes))
+	for i, dbDispute := range dbDisputes {
+		dispute, err := r.toDomain(&dbDispute)
+		if err != nil {
+			return nil, err
+		}
+		disputes[i] = dispute
+	}
+	return disputes, nil
+}
+
+func (r *DisputeRepository) toDomain(dbDispute *database.Dispute) (*services.Dispute, error) {
+	evidenceNotes, err := r.cipher.Decrypt(dbDispute.EvidenceNotes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt evidence notes: %w", err)
+	}
+
+	return &services.Dispute{
+		ID:                  dbDispute.ID,
+		OrderID:             dbDispute.OrderID,
+		TenderID:            dbDispute.TenderID,
+		GatewayDisputeID:    dbDispute.GatewayDisputeID,
+		GatewayReference:    dbDispute.GatewayReference,
+		Amount:              database.Int64ToMoney(dbDispute.Amount, dbDispute.Currency),
+		Reason:              dbDispute.Reason,
+		Status:              services.DisputeStatus(dbDispute.Status),
+		EvidenceStatus:      services.EvidenceStatus(dbDispute.EvidenceStatus),
+		EvidenceNotes:       evidenceNotes,
+		EvidenceDueBy:       dbDispute.EvidenceDueBy,
+		EvidenceSubmittedAt: dbDispute.EvidenceSubmittedAt,
+		CreatedAt:           dbDispute.CreatedAt,
+		UpdatedAt:           dbDispute.UpdatedAt,
+	}, nil
+}
+
+func (r *DisputeRepository) toDatabase(dispute *services.Dispute) (*database.Dispute, error) {
+	evidenceNotes, err := r.cipher.Encrypt(dispute.EvidenceNotes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt evidence notes: %w", err)
+	}
+
+	return &database.Dispute{
+		ID:                  dispute.ID,
+		OrderID:             dispute.OrderID,
+		TenderID:            dispute.TenderID,
+		GatewayDisputeID:    dispute.GatewayDisputeID,
+		GatewayReference:    dispute.GatewayReference,
+		Amount:              database.MoneyToInt64(dispute.Amount),
+		Currency:            dispute.Amount.Currency,
+		Reason:              dispute.Reason,
+		Status:              string(dispute.Status),
+		EvidenceStatus:      string(dispute.EvidenceStatus),
+		EvidenceNotes:       evidenceNotes,
+		EvidenceDueBy:       dispute.EvidenceDueBy,
+		EvidenceSubmittedAt: dispute.EvidenceSubmittedAt,
+		CreatedAt:           dispute.CreatedAt,
+		UpdatedAt:           dispute.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// DonationRepository implements services.DonationRepository using GORM
+type DonationRepository struct {
+	db *gorm.DB
+}
+
+// NewDonationRepository creates a new DonationRepository
+func NewDonationRepository(db *gorm.DB) *DonationRepository {
+	return &DonationRepository{db: db}
+}
+
+// FindProduct finds a product's donation settings
+func (r *DonationRepository) FindProduct(ctx context.Context, productID string) (*services.DonationProduct, error) {
+	var dbDonation database.DonationProduct
+	if err := r.db.WithContext(ctx).First(&dbDonation, "product_id = ?", productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrDonationNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbDonation), nil
+}
+
+// SaveProduct creates or replaces a product's donation settings
+func (r *DonationRepository) SaveProduct(ctx context.Context, donation *services.DonationProduct) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(donation)).Error
+}
+
+// DeleteProduct deletes a product's donation settings
+func (r *DonationRepository) DeleteProduct(ctx context.Context, productID string) error {
+	result := r.db.WithContext(ctx).Delete(&database.DonationProduct{}, "product_id = ?", productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrDonationNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *DonationRepository) toDomain(dbDonation *database.DonationProduct) *services.DonationProduct {
+	donation := &services.DonationProduct{
+		ProductID: dbDonation.ProductID,
+		MinAmount: money.Money{Amount: dbDonation.MinAmount, Currency: dbDonation.Currency},
+		UpdatedAt: dbDonation.UpdatedAt,
+	}
+	if dbDonation.MaxAmount != nil {
+		donation.MaxAmount = &money.Money{Amount: *dbDonation.MaxAmount, Currency: dbDonation.Currency}
+	}
+	return donation
+}
+
+func (r *DonationRepository) toDatabase(donation *services.DonationProduct) *database.DonationProduct {
+	dbDonation := &database.DonationProduct{
+		ProductID: donation.ProductID,
+		MinAmount: donation.MinAmount.Amount,
+		Currency:  donation.MinAmount.Currency,
+		UpdatedAt: donation.UpdatedAt,
+	}
+	if donation.MaxAmount != nil {
+		maxAmount := donation.MaxAmount.Amount
+		dbDonation.MaxAmount = &maxAmount
+	}
+	return dbDonation
+}
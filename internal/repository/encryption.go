@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+)
+
+// encryptedColumns lists the columns holding customer PII. JSON columns keep their
+// encrypted value as a JSON string so the column type doesn't change.
+var encryptedColumns = []struct {
+	table, column string
+	json          bool
+}{
+	{table: "orders", column: "shipping_address", json: true},
+	{table: "orders", column: "billing_address", json: true},
+	{table: "orders", column: "notes"},
+	{table: "archived_orders", column: "shipping_address", json: true},
+	{table: "archived_orders", column: "billing_address", json: true},
+	{table: "archived_orders", column: "notes"},
+	{table: "disputes", column: "evidence_notes"},
+	{table: "order_refunds", column: "note"},
+	{table: "store_credit_transactions", column: "note"},
+}
+
+// FieldEncryptionRepository re-encrypts PII columns after field encryption is turned on
+// or a new primary key is added
+type FieldEncryptionRepository struct {
+	db     *gorm.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewFieldEncryptionRepository creates a new FieldEncryptionRepository
+func NewFieldEncryptionRepository(db *gorm.DB, cipher *fieldcrypt.Cipher) *FieldEncryptionRepository {
+	return &FieldEncryptionRepository{db: db, cipher: cipher}
+}
+
+// RotateFields encrypts plaintext values and rewraps values encrypted with an older key,
+// a batch of rows at a time. Rows are updated column by column so updated_at is kept.
+func (r *FieldEncryptionRepository) RotateFields(ctx context.Context, batchSize int) (int64, error) {
+	var rotated int64
+	for _, col := range encryptedColumns {
+		after := ""
+		for {
+			var rows []struct {
+				ID    string
+				Value sql.NullString
+			}
+			err := r.db.WithContext(ctx).Table(col.table).
+				Select("id, "+col.column+" AS value").
+				Where("id > ?", after).
+				Order("id ASC").
+				Limit(batchSize).
+				Scan(&rows).Error
+			if err != nil {
+				return rotated, fmt.Errorf("%s.%s: %w", col.table, col.column, err)
+			}
+
+			for _, row := range rows {
+				value, changed, err := r.rotate(row.Value.String, col.json)
+				if err != nil {
+					return rotated, fmt.Errorf("%s.%s of %s: %w", col.table, col.column, row.ID, err)
+				}
+				if !changed {
+					continue
+				}
+				if err := r.db.WithContext(ctx).Table(col.table).Where("id = ?", row.ID).UpdateColumn(col.column, value).Error; err != nil {
+					return rotated, err
+				}
+				rotated++
+			}
+
+			if len(rows) < batchSize {
+				break
+			}
+			after = rows[len(rows)-1].ID
+		}
+	}
+	return rotated, nil
+}
+
+func (r *FieldEncryptionRepository) rotate(value string, isJSON bool) (string, bool, error) {
+	if !isJSON {
+		if !r.cipher.NeedsRotation(value) {
+			return value, false, nil
+		}
+		rotated, err := r.cipher.Rotate(value)
+		return rotated, err == nil, err
+	}
+
+	inner, encrypted := encryptedJSON(value)
+	if !encrypted {
+		inner = value
+	}
+	if !r.cipher.NeedsRotation(inner) {
+		return value, false, nil
+	}
+	rotated, err := r.cipher.Rotate(inner)
+	if err != nil {
+		return "", false, err
+	}
+	return database.MarshalJSON(rotated), true, nil
+}
+
+// sealJSON marshals v for a JSON column, storing it as an encrypted JSON string when a
+// cipher is set
+func sealJSON(c *fieldcrypt.Cipher, v interface{}) (string, error) {
+	data := database.MarshalJSON(v)
+	if c == nil {
+		return data, nil
+	}
+	sealed, err := c.Encrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return database.MarshalJSON(sealed), nil
+}
+
+// openJSON unmarshals a JSON column into target, decrypting it first if it was sealed
+func openJSON(c *fieldcrypt.Cipher, data string, target interface{}) error {
+	if inner, ok := encryptedJSON(data); ok {
+		plaintext, err := c.Decrypt(inner)
+		if err != nil {
+			return err
+		}
+		data = plaintext
+	}
+	return database.UnmarshalJSON(data, target)
+}
+
+// encryptedJSON returns the encrypted value held by a JSON string
+func encryptedJSON(data string) (string, bool) {
+	if !strings.HasPrefix(data, `"`) {
+		return "", false
+	}
+	var inner string
+	if err := json.Unmarshal([]byte(data), &inner); err != nil || !fieldcrypt.IsEncrypted(inner) {
+		return "", false
+	}
+	return inner, true
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// FulfillmentRepository implements services.FulfillmentRepository using GORM
+type FulfillmentRepository struct {
+	db     *gorm.DB
+	orders *OrderRepository
+}
+
+// NewFulfillmentRepository creates a new FulfillmentRepository
+func NewFulfillmentRepository(db *gorm.DB) *FulfillmentRepository {
+	return &FulfillmentRepository{db: db, orders: NewOrderRepository(db)}
+}
+
+// WithCipher reads orders encrypted at rest
+func (r *FulfillmentRepository) WithCipher(cipher *fieldcrypt.Cipher) *FulfillmentRepository {
+	r.orders.WithCipher(cipher)
+	return r
+}
+
+// FindOpenOrders finds paid and processing orders changed after the cursor, in (updated_at, id) order.
+// In-store sales are handed over at the register and are left out.
+func (r *FulfillmentRepository) FindOpenOrders(ctx context.Context, after *services.FulfillmentCursor, limit int) ([]*orders.Order, error) {
+	query := r.db.WithContext(ctx).
+		Where("status IN ?", []string{string(orders.OrderStatusPaid), string(orders.OrderStatusProcessing)}).
+		Where("NOT EXISTS (SELECT 1 FROM pos_sales WHERE pos_sales.order_id = orders.id)")
+	if after != nil {
+		query = query.Where("(updated_at > ? OR (updated_at = ? AND id > ?))", after.UpdatedAt, after.UpdatedAt, after.OrderID)
+	}
+
+	var dbOrders []database.Order
+	if err := query.Order("updated_at ASC, id ASC").Limit(limit).Find(&dbOrders).Error; err != nil {
+		return nil, err
+	}
+
+	return r.orders.toDomainList(dbOrders)
+}
+
+// FindShipments finds the shipments for an order, oldest first
+func (r *FulfillmentRepository) FindShipments(ctx context.Context, orderID string) ([]*services.Shipment, error) {
+	var dbShipments []database.OrderShipment
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("shipped_at ASC").Find(&dbShipments).Error; err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*services.Shipment, len(dbShipments))
+	for i, dbShipment := range dbShipments {
+		shipments[i] = &services.Shipment{
+			ID:             dbShipment.ID,
+			OrderID:        dbShipment.OrderID,
+			Carrier:        dbShipment.Carrier,
+			TrackingNumber: dbShipment.TrackingNumber,
+			TrackingURL:    dbShipment.TrackingURL,
+			ShippedAt:      dbShipment.ShippedAt,
+			APIKeyID:       dbShipment.APIKeyID,
+			CreatedAt:      dbShipment.CreatedAt,
+		}
+	}
+	return shipments, nil
+}
+
+// SaveShipment saves a shipment
+func (r *FulfillmentRepository) SaveShipment(ctx context.Context, shipment *services.Shipment) error {
+	return r.db.WithContext(ctx).Save(&database.OrderShipment{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		TrackingURL:    shipment.TrackingURL,
+		ShippedAt:      shipment.ShippedAt,
+		APIKeyID:       shipment.APIKeyID,
+		CreatedAt:      shipment.CreatedAt,
+	}).Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// FunnelRepository implements services.FunnelRepository using GORM
+type FunnelRepository struct {
+	db *gorm.DB
+}
+
+// NewFunnelRepository creates a new FunnelRepository
+func NewFunnelRepository(db *gorm.DB) *FunnelRepository {
+	return &FunnelRepository{db: db}
+}
+
+// Record stores that subject reached stage, reporting false when it already had
+func (r *FunnelRepository) Record(ctx context.Context, stage services.FunnelStage, subject string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&database.FunnelEvent{
+		Stage:      string(stage),
+		Subject:    subject,
+		OccurredAt: at,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists reports whether subject reached stage
+func (r *FunnelRepository) Exists(ctx context.Context, stage services.FunnelStage, subject string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.FunnelEvent{}).
+		Where("stage = ? AND subject = ?", string(stage), subject).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountByStage counts the subjects reaching each stage between from and to
+func (r *FunnelRepository) CountByStage(ctx context.Context, from, to time.Time) (map[services.FunnelStage]int64, error) {
+	var rows []struct {
+		Stage string
+		Count int64
+	}
+	if err := r.db.WithContext(ctx).Model(&database.FunnelEvent{}).
+		Select("stage, COUNT(*) AS count").
+		Where("occurred_at >= ? AND occurred_at < ?", from, to).
+		Group("stage").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[services.FunnelStage]int64, len(services.FunnelStages))
+	for _, stage := range services.FunnelStages {
+		counts[stage] = 0
+	}
+	for _, row := range rows {
+		counts[services.FunnelStage(row.Stage)] = row.Count
+	}
+	return counts, nil
+}
+
+// DeleteBefore deletes events that occurred before before
+func (r *FunnelRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("occurred_at < ?", before).Delete(&database.FunnelEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// SaveAlert stores a funnel alert
+func (r *FunnelRepository) SaveAlert(ctx context.Context, alert *services.FunnelAlert) error {
+	return r.db.WithContext(ctx).Create(&database.FunnelAlert{
+		ID:           alert.ID,
+		FromStage:    string(alert.From),
+		ToStage:      string(alert.To),
+		Entered:      alert.Entered,
+		Converted:    alert.Converted,
+		Rate:         alert.Rate,
+		BaselineRate: alert.BaselineRate,
+		WindowStart:  alert.WindowStart,
+		WindowEnd:    alert.WindowEnd,
+		CreatedAt:    alert.CreatedAt,
+	}).Error
+}
+
+// ListAlerts lists alerts raised at or after since, newest first; a limit of 0 lists all
+func (r *FunnelRepository) ListAlerts(ctx context.Context, since time.Time, limit, offset int) ([]*services.FunnelAlert, error) {
+	query := r.db.WithContext(ctx).Where("created_at >= ?", since).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	var dbAlerts []database.FunnelAlert
+	if err := query.Find(&dbAlerts).Error; err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*services.FunnelAlert, len(dbAlerts))
+	for i, dbAlert := range dbAlerts {
+		alerts[i] = &services.FunnelAlert{
+			ID:           dbAlert.ID,
+			From:         services.FunnelStage(dbAlert.FromStage),
+			To:           services.FunnelStage(dbAlert.ToStage),
+			Entered:      dbAlert.Entered,
+			Converted:    dbAlert.Converted,
+			Rate:         dbAlert.Rate,
+			BaselineRate: dbAlert.BaselineRate,
+			WindowStart:  dbAlert.WindowStart,
+			WindowEnd:    dbAlert.WindowEnd,
+			CreatedAt:    dbAlert.CreatedAt,
+		}
+	}
+	return alerts, nil
+}
+
+// CountAlerts counts alerts raised at or after since
+func (r *FunnelRepository) CountAlerts(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.FunnelAlert{}).Where("created_at >= ?", since).Count(&count).Error
+	return count, err
+}
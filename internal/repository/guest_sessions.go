@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+// GuestSessionRepository implements services.GuestSessionRepository using GORM
+type GuestSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewGuestSessionRepository creates a new GuestSessionRepository
+func NewGuestSessionRepository(db *gorm.DB) *GuestSessionRepository {
+	return &GuestSessionRepository{db: db}
+}
+
+// ReassignOrders moves every order of one user ID to another
+func (r *GuestSessionRepository) ReassignOrders(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&database.Order{}).
+		Where("user_id = ?", fromUserID).
+		Update("user_id", toUserID)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// InventoryHistoryRepository implements services.InventoryHistoryRepository using GORM.
+// Movements go to gocommerce's inventory_activities log, with quantity_before and
+// quantity_after holding on-hand stock.
+type InventoryHistoryRepository struct {
+	db    *gorm.DB
+	retry retry.Policy
+}
+
+// NewInventoryHistoryRepository creates a new InventoryHistoryRepository
+func NewInventoryHistoryRepository(db *gorm.DB) *InventoryHistoryRepository {
+	return &InventoryHistoryRepository{db: db}
+}
+
+// WithRetry runs writes that fail with a serialization failure or deadlock again
+func (r *InventoryHistoryRepository) WithRetry(policy retry.Policy) *InventoryHistoryRepository {
+	r.retry = writeRetryPolicy(policy)
+	return r
+}
+
+// AppendMovement records a stock movement
+func (r *InventoryHistoryRepository) AppendMovement(ctx context.Context, movement *services.StockMovement) error {
+	return retryWrite(ctx, r.retry, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Create(r.toDatabase(movement)).Error
+	})
+}
+
+// OutstandingReservations sums a reference's reservations less its releases and commits, per SKU
+func (r *InventoryHistoryRepository) OutstandingReservations(ctx context.Context, referenceID string) (map[string]int, error) {
+	var rows []struct {
+		SKU      string
+		Quantity int
+	}
+	err := r.db.WithContext(ctx).Model(&database.InventoryActivity{}).
+		Select(`sku, SUM(CASE WHEN activity_type = ? THEN quantity ELSE -quantity END) AS quantity`, string(services.StockMovementReservation)).
+		Where("reference_id = ? AND activity_type IN ?", referenceID, []string{
+			string(services.StockMovementReservation),
+			string(services.StockMovementRelease),
+			string(services.StockMovementCommit),
+		}).
+		Group("sku").
+		Having(`SUM(CASE WHEN activity_type = ? THEN quantity ELSE -quantity END) > 0`, string(services.StockMovementReservation)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding := make(map[string]int, len(rows))
+	for _, row := range rows {
+		outstanding[row.SKU] = row.Quantity
+	}
+	return outstanding, nil
+}
+
+// ListMovements lists a SKU's movements within [from, to], oldest first
+func (r *InventoryHistoryRepository) ListMovements(ctx context.Context, sku string, from, to time.Time) ([]*services.StockMovement, error) {
+	var dbActivities []database.InventoryActivity
+	err := r.db.WithContext(ctx).
+		Where("sku = ? AND created_at >= ? AND created_at <= ?", sku, from, to).
+		Order("created_at ASC").
+		Find(&dbActivities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	movements := make([]*services.StockMovement, len(dbActivities))
+	for i, dbActivity := range dbActivities {
+		movements[i] = r.toDomain(&dbActivity)
+	}
+	return movements, nil
+}
+
+// ListSnapshots lists a SKU's snapshots dated within [fromDate, toDate], oldest first
+func (r *InventoryHistoryRepository) ListSnapshots(ctx context.Context, sku string, fromDate, toDate string) ([]*services.InventorySnapshot, error) {
+	var dbSnapshots []database.InventorySnapshot
+	err := r.db.WithContext(ctx).
+		Where("sku = ? AND snapshot_date >= ? AND snapshot_date <= ?", sku, fromDate, toDate).
+		Order("snapshot_date ASC").
+		Find(&dbSnapshots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*services.InventorySnapshot, len(dbSnapshots))
+	for i, dbSnapshot := range dbSnapshots {
+		snapshots[i] = r.snapshotToDomain(&dbSnapshot)
+	}
+	return snapshots, nil
+}
+
+// LatestSnapshot finds the SKU's most recent snapshot taken at or before at
+func (r *InventoryHistoryRepository) LatestSnapshot(ctx context.Context, sku string, at time.Time) (*services.InventorySnapshot, error) {
+	var dbSnapshot database.InventorySnapshot
+	err := r.db.WithContext(ctx).
+		Where("sku = ? AND taken_at <= ?", sku, at).
+		Order("taken_at DESC").
+		First(&dbSnapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.snapshotToDomain(&dbSnapshot), nil
+}
+
+// LatestMovement finds the SKU's most recent movement made at or before at
+func (r *InventoryHistoryRepository) LatestMovement(ctx context.Context, sku string, at time.Time) (*services.StockMovement, error) {
+	var dbActivity database.InventoryActivity
+	err := r.db.WithContext(ctx).
+		Where("sku = ? AND created_at <= ?", sku, at).
+		Order("created_at DESC").
+		First(&dbActivity).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomain(&dbActivity), nil
+}
+
+// TakeSnapshots copies every active SKU's stock level into the day's snapshot
+func (r *InventoryHistoryRepository) TakeSnapshots(ctx context.Context, date string, takenAt time.Time) (int64, error) {
+	var snapshots int64
+	err := retryWrite(ctx, r.retry, func(ctx context.Context) error {
+		result := r.db.WithContext(ctx).Exec(`
+			INSERT INTO inventory_snapshots (sku, snapshot_date, on_hand, reserved, available, taken_at)
+			SELECT sku, ?, quantity_on_hand, quantity_reserved, quantity_available, ?
+			FROM inventory_levels
+			WHERE is_active = true
+			ON CONFLICT (sku, snapshot_date) DO UPDATE SET
+				on_hand = EXCLUDED.on_hand,
+				reserved = EXCLUDED.reserved,
+				available = EXCLUDED.available,
+				taken_at = EXCLUDED.taken_at
+		`, date, takenAt)
+		snapshots = result.RowsAffected
+		return result.Error
+	})
+	return snapshots, err
+}
+
+// Helper methods
+
+func (r *InventoryHistoryRepository) toDomain(dbActivity *database.InventoryActivity) *services.StockMovement {
+	return &services.StockMovement{
+		ID:           dbActivity.ID,
+		SKU:          dbActivity.SKU,
+		Type:         services.StockMovementType(dbActivity.ActivityType),
+		Quantity:     dbActivity.Quantity,
+		OnHandBefore: dbActivity.QuantityBefore,
+		OnHandAfter:  dbActivity.QuantityAfter,
+		ReferenceID:  dbActivity.ReferenceID,
+		Reason:       dbActivity.Reason,
+		CreatedAt:    dbActivity.CreatedAt,
+	}
+}
+
+func (r *InventoryHistoryRepository) toDatabase(movement *services.StockMovement) *database.InventoryActivity {
+	return &database.InventoryActivity{
+		ID:             movement.ID,
+		SKU:            movement.SKU,
+		ActivityType:   string(movement.Type),
+		Quantity:       movement.Quantity,
+		QuantityBefore: movement.OnHandBefore,
+		QuantityAfter:  movement.OnHandAfter,
+		ReferenceID:    movement.ReferenceID,
+		Reason:         movement.Reason,
+		CreatedAt:      movement.CreatedAt,
+	}
+}
+
+func (r *InventoryHistoryRepository) snapshotToDomain(dbSnapshot *database.InventorySnapshot) *services.InventorySnapshot {
+	return &services.InventorySnapshot{
+		SKU:       dbSnapshot.SKU,
+		Date:      dbSnapshot.SnapshotDate,
+		OnHand:    dbSnapshot.OnHand,
+		Reserved:  dbSnapshot.Reserved,
+		Available: dbSnapshot.Available,
+		TakenAt:   dbSnapshot.TakenAt,
+	}
+}
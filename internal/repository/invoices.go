@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// InvoiceRepository implements services.InvoiceRepository using GORM
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new InvoiceRepository
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// FindByID finds an invoice by ID
+func (r *InvoiceRepository) FindByID(ctx context.Context, id string) (*services.Invoice, error) {
+	var dbInvoice database.Invoice
+	if err := r.db.WithContext(ctx).First(&dbInvoice, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbInvoice), nil
+}
+
+// FindByOrderID finds an order's invoice, or nil when the order isn't paid by invoice
+func (r *InvoiceRepository) FindByOrderID(ctx context.Context, orderID string) (*services.Invoice, error) {
+	var dbInvoice database.Invoice
+	err := r.db.WithContext(ctx).First(&dbInvoice, "order_id = ?", orderID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomain(&dbInvoice), nil
+}
+
+// invoiceSortColumns maps services.InvoiceSortFields to their columns
+var invoiceSortColumns = map[string]string{
+	"number":     "number",
+	"amount":     "amount",
+	"status":     "status",
+	"issued_at":  "issued_at",
+	"due_at":     "due_at",
+	"created_at": "created_at",
+}
+
+// List lists invoices matching the filter in the filter's order, then newest first
+func (r *InvoiceRepository) List(ctx context.Context, filter services.InvoiceFilter) ([]*services.Invoice, error) {
+	query := r.db.WithContext(ctx)
+	if filter.CompanyID != "" {
+		query = query.Where("company_id = ?", filter.CompanyID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	query = applySort(query, filter.Sort, invoiceSortColumns, "created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbInvoices []database.Invoice
+	if err := query.Find(&dbInvoices).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbInvoices), nil
+}
+
+// ListOverdue lists open invoices due before asOf, oldest due date first
+func (r *InvoiceRepository) ListOverdue(ctx context.Context, asOf time.Time) ([]*services.Invoice, error) {
+	var dbInvoices []database.Invoice
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND due_at < ?", string(services.InvoiceStatusOpen), asOf).
+		Order("due_at ASC").
+		Find(&dbInvoices).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbInvoices), nil
+}
+
+// ListPayments lists the payments recorded against an invoice, in the order received
+func (r *InvoiceRepository) ListPayments(ctx context.Context, invoiceID string) ([]services.InvoicePayment, error) {
+	var dbPayments []database.InvoicePayment
+	err := r.db.WithContext(ctx).
+		Where("invoice_id = ?", invoiceID).
+		Order("received_at ASC, created_at ASC").
+		Find(&dbPayments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]services.InvoicePayment, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		payments[i] = services.InvoicePayment{
+			ID:         dbPayment.ID,
+			InvoiceID:  dbPayment.InvoiceID,
+			Amount:     money.Money{Amount: dbPayment.Amount, Currency: dbPayment.Currency},
+			Reference:  dbPayment.Reference,
+			ReceivedAt: dbPayment.ReceivedAt,
+			RecordedBy: dbPayment.RecordedBy,
+			CreatedAt:  dbPayment.CreatedAt,
+		}
+	}
+	return payments, nil
+}
+
+// Save saves an invoice
+func (r *InvoiceRepository) Save(ctx context.Context, invoice *services.Invoice) error {
+	return r.db.WithContext(ctx).Save(&database.Invoice{
+		ID:           invoice.ID,
+		Number:       invoice.Number,
+		OrderID:      invoice.OrderID,
+		CompanyID:    invoice.CompanyID,
+		PlacedBy:     invoice.PlacedBy,
+		Amount:       invoice.Amount.Amount,
+		AmountPaid:   invoice.AmountPaid.Amount,
+		Currency:     invoice.Amount.Currency,
+		Status:       string(invoice.Status),
+		NetTermsDays: invoice.NetTermsDays,
+		IssuedAt:     invoice.IssuedAt,
+		DueAt:        invoice.DueAt,
+		PaidAt:       invoice.PaidAt,
+		VoidedAt:     invoice.VoidedAt,
+		VoidReason:   invoice.VoidReason,
+		CreatedAt:    invoice.CreatedAt,
+		UpdatedAt:    invoice.UpdatedAt,
+	}).Error
+}
+
+// AddPayment records a payment and adds it to the invoice's amount paid in one
+// transaction. The update only applies while the invoice is open and the payment fits
+// the balance, so concurrent payments can't overpay it.
+func (r *InvoiceRepository) AddPayment(ctx context.Context, payment *services.InvoicePayment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		amount := payment.Amount.Amount
+		result := tx.Model(&database.Invoice{}).
+			Where("id = ? AND status = ? AND amount_paid + ? <= amount", payment.InvoiceID, string(services.InvoiceStatusOpen), amount).
+			Updates(map[string]interface{}{
+				"amount_paid": gorm.Expr("amount_paid + ?", amount),
+				"status":      gorm.Expr("CASE WHEN amount_paid + ? = amount THEN ? ELSE status END", amount, string(services.InvoiceStatusPaid)),
+				"paid_at":     gorm.Expr("CASE WHEN amount_paid + ? = amount THEN ? ELSE paid_at END", amount, payment.CreatedAt),
+				"updated_at":  payment.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var dbInvoice database.Invoice
+			if err := tx.First(&dbInvoice, "id = ?", payment.InvoiceID).Error; err != nil {
+				return err
+			}
+			if dbInvoice.Status != string(services.InvoiceStatusOpen) {
+				return services.ErrInvoiceNotOpen
+			}
+			return services.ErrInvoiceOverpaid
+		}
+
+		return tx.Create(&database.InvoicePayment{
+			ID:         payment.ID,
+			InvoiceID:  payment.InvoiceID,
+			Amount:     amount,
+			Currency:   payment.Amount.Currency,
+			Reference:  payment.Reference,
+			ReceivedAt: payment.ReceivedAt,
+			RecordedBy: payment.RecordedBy,
+			CreatedAt:  payment.CreatedAt,
+		}).Error
+	})
+}
+
+// Helper methods
+
+func (r *InvoiceRepository) toDomain(dbInvoice *database.Invoice) *services.Invoice {
+	return &services.Invoice{
+		ID:           dbInvoice.ID,
+		Number:       dbInvoice.Number,
+		OrderID:      dbInvoice.OrderID,
+		CompanyID:    dbInvoice.CompanyID,
+		PlacedBy:     dbInvoice.PlacedBy,
+		Amount:       money.Money{Amount: dbInvoice.Amount, Currency: dbInvoice.Currency},
+		AmountPaid:   money.Money{Amount: dbInvoice.AmountPaid, Currency: dbInvoice.Currency},
+		Status:       services.InvoiceStatus(dbInvoice.Status),
+		NetTermsDays: dbInvoice.NetTermsDays,
+		IssuedAt:     dbInvoice.IssuedAt,
+		DueAt:        dbInvoice.DueAt,
+		PaidAt:       dbInvoice.PaidAt,
+		VoidedAt:     dbInvoice.VoidedAt,
+		VoidReason:   dbInvoice.VoidReason,
+		CreatedAt:    dbInvoice.CreatedAt,
+		UpdatedAt:    dbInvoice.UpdatedAt,
+	}
+}
+
+func (r *InvoiceRepository) toDomainList(dbInvoices []database.Invoice) []*services.Invoice {
+	invoices := make([]*services.Invoice, len(dbInvoices))
+	for i := range dbInvoices {
+		invoices[i] = r.toDomain(&dbInvoices[i])
+	}
+	return invoices
+}
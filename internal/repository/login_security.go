@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// maxUserAgentLength is the size of the login_devices.user_agent column
+const maxUserAgentLength = 512
+
+// LoginSecurityRepository implements services.LoginSecurityRepository using GORM
+type LoginSecurityRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginSecurityRepository creates a new LoginSecurityRepository
+func NewLoginSecurityRepository(db *gorm.DB) *LoginSecurityRepository {
+	return &LoginSecurityRepository{db: db}
+}
+
+// FindLockout finds the failed login state of an email address
+func (r *LoginSecurityRepository) FindLockout(ctx context.Context, email string) (*services.LoginLockout, error) {
+	var dbLockout database.LoginLockout
+	if err := r.db.WithContext(ctx).First(&dbLockout, "email = ?", email).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrLoginLockoutNotFound
+		}
+		return nil, err
+	}
+	return &services.LoginLockout{
+		Email:          dbLockout.Email,
+		FailedAttempts: dbLockout.FailedAttempts,
+		Lockouts:       dbLockout.Lockouts,
+		LockedUntil:    dbLockout.LockedUntil,
+		LastFailedAt:   dbLockout.LastFailedAt,
+	}, nil
+}
+
+// SaveLockout creates or replaces the failed login state of an email address
+func (r *LoginSecurityRepository) SaveLockout(ctx context.Context, lockout *services.LoginLockout) error {
+	return r.db.WithContext(ctx).Save(&database.LoginLockout{
+		Email:          lockout.Email,
+		FailedAttempts: lockout.FailedAttempts,
+		Lockouts:       lockout.Lockouts,
+		LockedUntil:    lockout.LockedUntil,
+		LastFailedAt:   lockout.LastFailedAt,
+	}).Error
+}
+
+// DeleteLockout deletes the failed login state of an email address
+func (r *LoginSecurityRepository) DeleteLockout(ctx context.Context, email string) error {
+	result := r.db.WithContext(ctx).Delete(&database.LoginLockout{}, "email = ?", email)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrLoginLockoutNotFound
+	}
+	return nil
+}
+
+// FindDevice finds a device a user has signed in from
+func (r *LoginSecurityRepository) FindDevice(ctx context.Context, userID, fingerprint string) (*services.LoginDevice, error) {
+	var dbDevice database.LoginDevice
+	if err := r.db.WithContext(ctx).First(&dbDevice, "user_id = ? AND fingerprint = ?", userID, fingerprint).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrLoginDeviceNotFound
+		}
+		return nil, err
+	}
+	return &services.LoginDevice{
+		UserID:      dbDevice.UserID,
+		Fingerprint: dbDevice.Fingerprint,
+		UserAgent:   dbDevice.UserAgent,
+		IPAddress:   dbDevice.IPAddress,
+		FirstSeenAt: dbDevice.FirstSeenAt,
+		LastSeenAt:  dbDevice.LastSeenAt,
+	}, nil
+}
+
+// CountDevices counts the devices a user has signed in from
+func (r *LoginSecurityRepository) CountDevices(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.LoginDevice{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// SaveDevice creates or updates a device a user has signed in from
+func (r *LoginSecurityRepository) SaveDevice(ctx context.Context, device *services.LoginDevice) error {
+	userAgent := device.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return r.db.WithContext(ctx).Save(&database.LoginDevice{
+		UserID:      device.UserID,
+		Fingerprint: device.Fingerprint,
+		UserAgent:   userAgent,
+		IPAddress:   device.IPAddress,
+		FirstSeenAt: device.FirstSeenAt,
+		LastSeenAt:  device.LastSeenAt,
+	}).Error
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// OrderArchiveRepository implements services.OrderArchiveRepository using GORM. Orders are
+// moved with one DELETE ... RETURNING statement per batch, so an order is never in both
+// tables or in neither.
+type OrderArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderArchiveRepository creates a new OrderArchiveRepository
+func NewOrderArchiveRepository(db *gorm.DB) *OrderArchiveRepository {
+	return &OrderArchiveRepository{db: db}
+}
+
+// ArchiveDelivered moves up to limit delivered orders not changed since before into
+// archived_orders, least recently changed first. Orders locked by a write in progress are
+// left for the next batch.
+func (r *OrderArchiveRepository) ArchiveDelivered(ctx context.Context, before time.Time, limit int) (int64, error) {
+	columns, err := orderColumns(r.db)
+	if err != nil {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Exec(fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM orders WHERE id IN (
+				SELECT id FROM orders
+				WHERE status = ? AND updated_at < ?
+				ORDER BY updated_at ASC
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING %s
+		)
+		INSERT INTO archived_orders (%s, archived_at)
+		SELECT %s, ? FROM moved`, columns, columns, columns),
+		string(orders.OrderStatusDelivered), before, limit, time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// CountArchivable counts the delivered orders not changed since before
+func (r *OrderArchiveRepository) CountArchivable(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.Order{}).
+		Where("status = ? AND updated_at < ?", string(orders.OrderStatusDelivered), before).
+		Count(&count).Error
+	return count, err
+}
+
+// CountArchived counts the orders in the archive
+func (r *OrderArchiveRepository) CountArchived(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.ArchivedOrder{}).Count(&count).Error
+	return count, err
+}
+
+// Helper methods
+
+// orderColumns lists the orders columns the Order model maps, which are the ones archived
+func orderColumns(db *gorm.DB) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&database.Order{}); err != nil {
+		return "", fmt.Errorf("failed to parse order model: %w", err)
+	}
+	return strings.Join(stmt.Schema.DBNames, ", "), nil
+}
+
+// restoreArchivedOrder moves an order back from the archive to the orders table; it does
+// nothing for orders that aren't archived
+func restoreArchivedOrder(tx *gorm.DB, id string) error {
+	columns, err := orderColumns(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(`
+		WITH restored AS (
+			DELETE FROM archived_orders WHERE id = ?
+			RETURNING %s
+		)
+		INSERT INTO orders (%s)
+		SELECT %s FROM restored`, columns, columns, columns), id).Error
+}
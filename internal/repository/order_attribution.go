@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// OrderAttributionRepository implements services.OrderAttributionRepository using GORM
+type OrderAttributionRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderAttributionRepository creates a new OrderAttributionRepository
+func NewOrderAttributionRepository(db *gorm.DB) *OrderAttributionRepository {
+	return &OrderAttributionRepository{db: db}
+}
+
+// Save saves an order's attribution
+func (r *OrderAttributionRepository) Save(ctx context.Context, attribution *services.OrderAttribution) error {
+	return r.db.WithContext(ctx).Save(&database.OrderAttribution{
+		OrderID:     attribution.OrderID,
+		Channel:     string(attribution.Channel),
+		UTMSource:   attribution.UTM.Source,
+		UTMMedium:   attribution.UTM.Medium,
+		UTMCampaign: attribution.UTM.Campaign,
+		UTMTerm:     attribution.UTM.Term,
+		UTMContent:  attribution.UTM.Content,
+		CreatedAt:   attribution.CreatedAt,
+	}).Error
+}
+
+// FindByOrderID finds an order's attribution, or nil when it has none
+func (r *OrderAttributionRepository) FindByOrderID(ctx context.Context, orderID string) (*services.OrderAttribution, error) {
+	var dbAttribution database.OrderAttribution
+	err := r.db.WithContext(ctx).First(&dbAttribution, "order_id = ?", orderID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &services.OrderAttribution{
+		OrderID:   dbAttribution.OrderID,
+		Channel:   services.OrderChannel(dbAttribution.Channel),
+		UTM:       toUTM(&dbAttribution),
+		CreatedAt: dbAttribution.CreatedAt,
+	}, nil
+}
+
+// ListAttributedOrders reads the attribution and totals of orders attributed within [from, to)
+func (r *OrderAttributionRepository) ListAttributedOrders(ctx context.Context, from, to time.Time) ([]*services.AttributedOrder, error) {
+	var rows []struct {
+		database.OrderAttribution
+		Status   string
+		Total    int64
+		Currency string
+	}
+	err := r.db.WithContext(ctx).
+		Model(&database.OrderAttribution{}).
+		Select("order_attributions.*, orders.status, orders.total, orders.currency").
+		Joins("JOIN orders ON orders.id = order_attributions.order_id").
+		Where("order_attributions.created_at >= ? AND order_attributions.created_at < ?", from, to).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	attributed := make([]*services.AttributedOrder, len(rows))
+	for i, row := range rows {
+		attributed[i] = &services.AttributedOrder{
+			Channel: services.OrderChannel(row.Channel),
+			UTM:     toUTM(&row.OrderAttribution),
+			Status:  orders.OrderStatus(row.Status),
+			Total:   database.Int64ToMoney(row.Total, row.Currency),
+		}
+	}
+	return attributed, nil
+}
+
+func toUTM(dbAttribution *database.OrderAttribution) services.UTM {
+	return services.UTM{
+		Source:   dbAttribution.UTMSource,
+		Medium:   dbAttribution.UTMMedium,
+		Campaign: dbAttribution.UTMCampaign,
+		Term:     dbAttribution.UTMTerm,
+		Content:  dbAttribution.UTMContent,
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderBumpRepository implements services.OrderBumpRepository using GORM
+type OrderBumpRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderBumpRepository creates a new OrderBumpRepository
+func NewOrderBumpRepository(db *gorm.DB) *OrderBumpRepository {
+	return &OrderBumpRepository{db: db}
+}
+
+// FindByID finds an order bump by ID
+func (r *OrderBumpRepository) FindByID(ctx context.Context, id string) (*services.OrderBump, error) {
+	var dbBump database.OrderBump
+	if err := r.db.WithContext(ctx).First(&dbBump, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrOrderBumpNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbBump), nil
+}
+
+// FindAll finds all order bumps, in the order they are offered
+func (r *OrderBumpRepository) FindAll(ctx context.Context) ([]*services.OrderBump, error) {
+	var dbBumps []database.OrderBump
+	if err := r.db.WithContext(ctx).Order("position ASC, created_at ASC").Find(&dbBumps).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbBumps), nil
+}
+
+// FindActive finds the order bumps on offer, in the order they are offered
+func (r *OrderBumpRepository) FindActive(ctx context.Context) ([]*services.OrderBump, error) {
+	var dbBumps []database.OrderBump
+	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Order("position ASC, created_at ASC").Find(&dbBumps).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbBumps), nil
+}
+
+// Save creates or replaces an order bump
+func (r *OrderBumpRepository) Save(ctx context.Context, bump *services.OrderBump) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(bump)).Error
+}
+
+// Delete deletes an order bump
+func (r *OrderBumpRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.OrderBump{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrOrderBumpNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *OrderBumpRepository) toDomain(dbBump *database.OrderBump) *services.OrderBump {
+	return &services.OrderBump{
+		ID:        dbBump.ID,
+		ProductID: dbBump.ProductID,
+		Headline:  dbBump.Headline,
+		Price:     database.Int64ToMoney(dbBump.Price, dbBump.Currency),
+		Position:  dbBump.Position,
+		IsActive:  dbBump.IsActive,
+		CreatedAt: dbBump.CreatedAt,
+		UpdatedAt: dbBump.UpdatedAt,
+	}
+}
+
+func (r *OrderBumpRepository) toDomainList(dbBumps []database.OrderBump) []*services.OrderBump {
+	bumps := make([]*services.OrderBump, len(dbBumps))
+	for i := range dbBumps {
+		bumps[i] = r.toDomain(&dbBumps[i])
+	}
+	return bumps
+}
+
+func (r *OrderBumpRepository) toDatabase(bump *services.OrderBump) *database.OrderBump {
+	return &database.OrderBump{
+		ID:        bump.ID,
+		ProductID: bump.ProductID,
+		Headline:  bump.Headline,
+		Price:     database.MoneyToInt64(bump.Price),
+		Currency:  bump.Price.Currency,
+		Position:  bump.Position,
+		IsActive:  bump.IsActive,
+		CreatedAt: bump.CreatedAt,
+		UpdatedAt: bump.UpdatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderEventRepository implements services.OrderEventRepository using GORM
+type OrderEventRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderEventRepository creates a new OrderEventRepository
+func NewOrderEventRepository(db *gorm.DB) *OrderEventRepository {
+	return &OrderEventRepository{db: db}
+}
+
+// Append stores an order event
+func (r *OrderEventRepository) Append(ctx context.Context, event *services.OrderEvent) error {
+	dbEvent, err := r.toDatabase(event)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(dbEvent).Error
+}
+
+// ListByOrder lists an order's events, oldest first
+func (r *OrderEventRepository) ListByOrder(ctx context.Context, orderID string) ([]*services.OrderEvent, error) {
+	var dbEvents []database.OrderEvent
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*services.OrderEvent, len(dbEvents))
+	for i := range dbEvents {
+		event, err := r.toDomain(&dbEvents[i])
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+// Helper methods
+
+func (r *OrderEventRepository) toDomain(dbEvent *database.OrderEvent) (*services.OrderEvent, error) {
+	event := &services.OrderEvent{
+		ID:         dbEvent.ID,
+		OrderID:    dbEvent.OrderID,
+		Type:       services.OrderEventType(dbEvent.Type),
+		Message:    dbEvent.Message,
+		FromStatus: orders.OrderStatus(dbEvent.FromStatus),
+		Status:     orders.OrderStatus(dbEvent.Status),
+		Internal:   dbEvent.Internal,
+		CreatedBy:  dbEvent.CreatedBy,
+		CreatedAt:  dbEvent.CreatedAt,
+	}
+	if dbEvent.Amount != nil {
+		amount := money.Money{Amount: *dbEvent.Amount, Currency: dbEvent.Currency}
+		event.Amount = &amount
+	}
+	if dbEvent.Details != nil {
+		if err := json.Unmarshal([]byte(*dbEvent.Details), &event.Details); err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
+
+func (r *OrderEventRepository) toDatabase(event *services.OrderEvent) (*database.OrderEvent, error) {
+	dbEvent := &database.OrderEvent{
+		ID:         event.ID,
+		OrderID:    event.OrderID,
+		Type:       string(event.Type),
+		Message:    event.Message,
+		FromStatus: string(event.FromStatus),
+		Status:     string(event.Status),
+		Internal:   event.Internal,
+		CreatedBy:  event.CreatedBy,
+		CreatedAt:  event.CreatedAt,
+	}
+	if event.Amount != nil {
+		dbEvent.Amount = &event.Amount.Amount
+		dbEvent.Currency = event.Amount.Currency
+	}
+	if len(event.Details) > 0 {
+		details, err := json.Marshal(event.Details)
+		if err != nil {
+			return nil, err
+		}
+		encoded := string(details)
+		dbEvent.Details = &encoded
+	}
+	return dbEvent, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// OrderStatusEventRepository implements services.OrderStatusEventRepository using GORM
+type OrderStatusEventRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderStatusEventRepository creates a new OrderStatusEventRepository
+func NewOrderStatusEventRepository(db *gorm.DB) *OrderStatusEventRepository {
+	return &OrderStatusEventRepository{db: db}
+}
+
+// Add stores a status change
+func (r *OrderStatusEventRepository) Add(ctx context.Context, event *services.OrderStatusEvent) error {
+	return r.db.WithContext(ctx).Create(&database.OrderStatusEvent{
+		ID:         event.ID,
+		OrderID:    event.OrderID,
+		FromStatus: string(event.FromStatus),
+		ToStatus:   string(event.ToStatus),
+		Reason:     event.Reason,
+		ChangedBy:  event.ChangedBy,
+		CreatedAt:  event.CreatedAt,
+	}).Error
+}
+
+// ListByOrder lists an order's status changes, oldest first
+func (r *OrderStatusEventRepository) ListByOrder(ctx context.Context, orderID string) ([]*services.OrderStatusEvent, error) {
+	var dbEvents []database.OrderStatusEvent
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*services.OrderStatusEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = &services.OrderStatusEvent{
+			ID:         dbEvent.ID,
+			OrderID:    dbEvent.OrderID,
+			FromStatus: orders.OrderStatus(dbEvent.FromStatus),
+			ToStatus:   orders.OrderStatus(dbEvent.ToStatus),
+			Reason:     dbEvent.Reason,
+			ChangedBy:  dbEvent.ChangedBy,
+			CreatedAt:  dbEvent.CreatedAt,
+		}
+	}
+	return events, nil
+}
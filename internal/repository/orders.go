@@ -3,16 +3,22 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
 	"github.com/devchuckcamp/gocommerce/orders"
 )
 
 // OrderRepository implements orders.Repository using GORM
 type OrderRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cipher *fieldcrypt.Cipher
+	retry  retry.Policy
 }
 
 // NewOrderRepository creates a new OrderRepository
@@ -20,12 +26,25 @@ func NewOrderRepository(db *gorm.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-// FindByID finds an order by ID
+// WithCipher encrypts addresses and notes at rest
+func (r *OrderRepository) WithCipher(cipher *fieldcrypt.Cipher) *OrderRepository {
+	r.cipher = cipher
+	return r
+}
+
+// WithRetry runs writes that fail with a serialization failure or deadlock again
+func (r *OrderRepository) WithRetry(policy retry.Policy) *OrderRepository {
+	r.retry = writeRetryPolicy(policy)
+	return r
+}
+
+// FindByID finds an order by ID, reading through to the archive for orders archived
+// after delivery
 func (r *OrderRepository) FindByID(ctx context.Context, id string) (*orders.Order, error) {
 	var dbOrder database.Order
 	if err := r.db.WithContext(ctx).First(&dbOrder, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, orders.ErrOrderNotFound
+			return r.findArchived(ctx, "id = ?", id)
 		}
 		return nil, err
 	}
@@ -33,12 +52,12 @@ func (r *OrderRepository) FindByID(ctx context.Context, id string) (*orders.Orde
 	return r.toDomain(&dbOrder)
 }
 
-// FindByOrderNumber finds an order by order number
+// FindByOrderNumber finds an order by order number, reading through to the archive
 func (r *OrderRepository) FindByOrderNumber(ctx context.Context, orderNumber string) (*orders.Order, error) {
 	var dbOrder database.Order
 	if err := r.db.WithContext(ctx).First(&dbOrder, "order_number = ?", orderNumber).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, orders.ErrOrderNotFound
+			return r.findArchived(ctx, "order_number = ?", orderNumber)
 		}
 		return nil, err
 	}
@@ -46,10 +65,13 @@ func (r *OrderRepository) FindByOrderNumber(ctx context.Context, orderNumber str
 	return r.toDomain(&dbOrder)
 }
 
-// FindByUserID finds orders by user ID
+// FindByUserID finds orders by user ID, including the ones archived after delivery
 func (r *OrderRepository) FindByUserID(ctx context.Context, userID string, filter orders.OrderFilter) ([]*orders.Order, error) {
-	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
-	query = r.applyFilter(query, filter)
+	query, err := r.userOrders(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	query = r.applyPage(query, filter)
 
 	var dbOrders []database.Order
 	if err := query.Find(&dbOrders).Error; err != nil {
@@ -59,29 +81,120 @@ func (r *OrderRepository) FindByUserID(ctx context.Context, userID string, filte
 	return r.toDomainList(dbOrders)
 }
 
-// Save saves an order
+// CountByUserID counts a user's orders matching the filter, ignoring its limit and offset.
+// Archived orders are counted too.
+func (r *OrderRepository) CountByUserID(ctx context.Context, userID string, filter orders.OrderFilter) (int64, error) {
+	query, err := r.userOrders(ctx, userID, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Save saves an order. An archived order that changes again, e.g. when it is refunded,
+// is moved back to the orders table first.
 func (r *OrderRepository) Save(ctx context.Context, order *orders.Order) error {
-	dbOrder := r.toDatabase(order)
-	return r.db.WithContext(ctx).Save(dbOrder).Error
+	dbOrder, err := r.toDatabase(order)
+	if err != nil {
+		return err
+	}
+	return retryWrite(ctx, r.retry, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := restoreArchivedOrder(tx, order.ID); err != nil {
+				return err
+			}
+			return tx.Omit("metadata", "item_metadata").Save(dbOrder).Error
+		})
+	})
 }
 
-// Delete deletes an order
+// Delete deletes an order, whether or not it is archived
 func (r *OrderRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&database.Order{}, "id = ?", id).Error
+	return retryWrite(ctx, r.retry, func(ctx context.Context) error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&database.ArchivedOrder{}, "id = ?", id).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&database.Order{}, "id = ?", id).Error
+		})
+	})
 }
 
-// Helper methods
+// TopSellingProductIDs ranks products by units ordered since the given time, best first.
+// Items are stored as JSON, so they are tallied here rather than in SQL.
+func (r *OrderRepository) TopSellingProductIDs(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	units := make(map[string]int)
 
-func (r *OrderRepository) applyFilter(query *gorm.DB, filter orders.OrderFilter) *gorm.DB {
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
+	var batch []database.Order
+	err := r.db.WithContext(ctx).
+		Select("id", "items").
+		Where("created_at >= ? AND status NOT IN ?", since, []string{string(orders.OrderStatusCanceled), string(orders.OrderStatusRefunded)}).
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, dbOrder := range batch {
+				var items []orders.OrderItem
+				if err := database.UnmarshalJSON(dbOrder.Items, &items); err != nil {
+					return fmt.Errorf("failed to unmarshal items of order %s: %w", dbOrder.ID, err)
+				}
+				for _, item := range items {
+					units[item.ProductID] += item.Quantity
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
 	}
-	if filter.DateFrom != nil {
-		query = query.Where("created_at >= ?", *filter.DateFrom)
+
+	ids := make([]string, 0, len(units))
+	for id := range units {
+		ids = append(ids, id)
 	}
-	if filter.DateTo != nil {
-		query = query.Where("created_at <= ?", *filter.DateTo)
+	sort.Slice(ids, func(i, j int) bool {
+		if units[ids[i]] != units[ids[j]] {
+			return units[ids[i]] > units[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// Helper methods
+
+// findArchived finds an order in the archive
+func (r *OrderRepository) findArchived(ctx context.Context, query string, args ...interface{}) (*orders.Order, error) {
+	var dbArchived database.ArchivedOrder
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&dbArchived).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, orders.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbArchived.Order)
+}
+
+// userOrders selects a user's orders matching the filter's conditions from both the orders
+// table and the archive. An order is only ever in one of them, so UNION ALL lists each once.
+func (r *OrderRepository) userOrders(ctx context.Context, userID string, filter orders.OrderFilter) (*gorm.DB, error) {
+	columns, err := orderColumns(r.db)
+	if err != nil {
+		return nil, err
 	}
+	live := r.applyConditions(r.db.Table("orders").Select(columns).Where("user_id = ?", userID), filter)
+	archived := r.applyConditions(r.db.Table("archived_orders").Select(columns).Where("user_id = ?", userID), filter)
+	return r.db.WithContext(ctx).Table("(? UNION ALL ?) AS orders", live, archived), nil
+}
+
+// applyPage applies the filter's limit and offset, newest first
+func (r *OrderRepository) applyPage(query *gorm.DB, filter orders.OrderFilter) *gorm.DB {
 	if filter.Limit > 0 {
 		query = query.Limit(filter.Limit)
 	}
@@ -92,6 +205,21 @@ func (r *OrderRepository) applyFilter(query *gorm.DB, filter orders.OrderFilter)
 	return query
 }
 
+// applyConditions applies the filter's status and date range, shared by listing and counting
+// on both the orders table and the archive
+func (r *OrderRepository) applyConditions(query *gorm.DB, filter orders.OrderFilter) *gorm.DB {
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("created_at <= ?", *filter.DateTo)
+	}
+	return query
+}
+
 func (r *OrderRepository) toDomain(dbOrder *database.Order) (*orders.Order, error) {
 	var items []orders.OrderItem
 	if err := database.UnmarshalJSON(dbOrder.Items, &items); err != nil {
@@ -99,15 +227,20 @@ func (r *OrderRepository) toDomain(dbOrder *database.Order) (*orders.Order, erro
 	}
 
 	var shippingAddress orders.Address
-	if err := database.UnmarshalJSON(dbOrder.ShippingAddress, &shippingAddress); err != nil {
+	if err := openJSON(r.cipher, dbOrder.ShippingAddress, &shippingAddress); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal shipping address: %w", err)
 	}
 
 	var billingAddress orders.Address
-	if err := database.UnmarshalJSON(dbOrder.BillingAddress, &billingAddress); err != nil {
+	if err := openJSON(r.cipher, dbOrder.BillingAddress, &billingAddress); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal billing address: %w", err)
 	}
 
+	notes, err := r.cipher.Decrypt(dbOrder.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt order notes: %w", err)
+	}
+
 	return &orders.Order{
 		ID:              dbOrder.ID,
 		OrderNumber:     dbOrder.OrderNumber,
@@ -122,7 +255,7 @@ func (r *OrderRepository) toDomain(dbOrder *database.Order) (*orders.Order, erro
 		TaxTotal:        database.Int64ToMoney(dbOrder.TaxTotal, dbOrder.Currency),
 		ShippingTotal:   database.Int64ToMoney(dbOrder.ShippingTotal, dbOrder.Currency),
 		Total:           database.Int64ToMoney(dbOrder.Total, dbOrder.Currency),
-		Notes:           dbOrder.Notes,
+		Notes:           notes,
 		IPAddress:       dbOrder.IPAddress,
 		UserAgent:       dbOrder.UserAgent,
 		CanceledAt:      dbOrder.CancelledAt,
@@ -143,15 +276,28 @@ func (r *OrderRepository) toDomainList(dbOrders []database.Order) ([]*orders.Ord
 	return ordersList, nil
 }
 
-func (r *OrderRepository) toDatabase(order *orders.Order) *database.Order {
+func (r *OrderRepository) toDatabase(order *orders.Order) (*database.Order, error) {
+	shippingAddress, err := sealJSON(r.cipher, order.ShippingAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt shipping address: %w", err)
+	}
+	billingAddress, err := sealJSON(r.cipher, order.BillingAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt billing address: %w", err)
+	}
+	notes, err := r.cipher.Encrypt(order.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt order notes: %w", err)
+	}
+
 	return &database.Order{
 		ID:              order.ID,
 		OrderNumber:     order.OrderNumber,
 		UserID:          order.UserID,
 		Status:          string(order.Status),
 		Items:           database.MarshalJSON(order.Items),
-		ShippingAddress: database.MarshalJSON(order.ShippingAddress),
-		BillingAddress:  database.MarshalJSON(order.BillingAddress),
+		ShippingAddress: shippingAddress,
+		BillingAddress:  billingAddress,
 		PaymentMethodID: order.PaymentMethodID,
 		Subtotal:        database.MoneyToInt64(order.Subtotal),
 		DiscountTotal:   database.MoneyToInt64(order.DiscountTotal),
@@ -159,11 +305,11 @@ func (r *OrderRepository) toDatabase(order *orders.Order) *database.Order {
 		ShippingTotal:   database.MoneyToInt64(order.ShippingTotal),
 		Total:           database.MoneyToInt64(order.Total),
 		Currency:        order.Total.Currency,
-		Notes:           order.Notes,
+		Notes:           notes,
 		IPAddress:       order.IPAddress,
 		UserAgent:       order.UserAgent,
 		CancelledAt:     order.CanceledAt,
 		CreatedAt:       order.CreatedAt,
 		UpdatedAt:       order.UpdatedAt,
-	}
+	}, nil
 }
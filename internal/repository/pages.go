@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PageRepository implements services.PageRepository using GORM
+type PageRepository struct {
+	db *gorm.DB
+}
+
+// NewPageRepository creates a new PageRepository
+func NewPageRepository(db *gorm.DB) *PageRepository {
+	return &PageRepository{db: db}
+}
+
+// FindByID finds a page by ID
+func (r *PageRepository) FindByID(ctx context.Context, id string) (*services.Page, error) {
+	var dbPage database.ContentPage
+	if err := r.db.WithContext(ctx).First(&dbPage, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPageNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbPage), nil
+}
+
+// FindBySlug finds a page by slug
+func (r *PageRepository) FindBySlug(ctx context.Context, slug string) (*services.Page, error) {
+	var dbPage database.ContentPage
+	if err := r.db.WithContext(ctx).First(&dbPage, "slug = ?", slug).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPageNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbPage), nil
+}
+
+// pageSortColumns maps services.PageSortFields to their columns
+var pageSortColumns = map[string]string{
+	"slug":         "slug",
+	"title":        "title",
+	"status":       "status",
+	"published_at": "published_at",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+// List lists pages matching the filter in the filter's order, then by slug
+func (r *PageRepository) List(ctx context.Context, filter services.PageFilter) ([]*services.Page, error) {
+	query := applySort(r.applyFilter(r.db.WithContext(ctx), filter), filter.Sort, pageSortColumns, "slug ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbPages []database.ContentPage
+	if err := query.Find(&dbPages).Error; err != nil {
+		return nil, err
+	}
+
+	pages := make([]*services.Page, len(dbPages))
+	for i, dbPage := range dbPages {
+		pages[i] = r.toDomain(&dbPage)
+	}
+	return pages, nil
+}
+
+// Count counts pages matching the filter
+func (r *PageRepository) Count(ctx context.Context, filter services.PageFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.WithContext(ctx).Model(&database.ContentPage{}), filter).Count(&count).Error
+	return count, err
+}
+
+// Save saves a page
+func (r *PageRepository) Save(ctx context.Context, page *services.Page) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(page)).Error
+}
+
+// Delete deletes a page
+func (r *PageRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.ContentPage{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrPageNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *PageRepository) applyFilter(query *gorm.DB, filter services.PageFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
+
+func (r *PageRepository) toDomain(dbPage *database.ContentPage) *services.Page {
+	return &services.Page{
+		ID:              dbPage.ID,
+		Slug:            dbPage.Slug,
+		Title:           dbPage.Title,
+		Body:            dbPage.Body,
+		Format:          services.PageFormat(dbPage.Format),
+		MetaDescription: dbPage.MetaDescription,
+		Status:          services.PageStatus(dbPage.Status),
+		PublishedAt:     dbPage.PublishedAt,
+		CreatedAt:       dbPage.CreatedAt,
+		UpdatedAt:       dbPage.UpdatedAt,
+	}
+}
+
+func (r *PageRepository) toDatabase(page *services.Page) *database.ContentPage {
+	return &database.ContentPage{
+		ID:              page.ID,
+		Slug:            page.Slug,
+		Title:           page.Title,
+		Body:            page.Body,
+		Format:          string(page.Format),
+		MetaDescription: page.MetaDescription,
+		Status:          string(page.Status),
+		PublishedAt:     page.PublishedAt,
+		CreatedAt:       page.CreatedAt,
+		UpdatedAt:       page.UpdatedAt,
+	}
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PaymentRepository implements services.PaymentRepository using GORM
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new PaymentRepository
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// FindByID finds a payment tender by ID
+func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*services.PaymentTender, error) {
+	var dbPayment database.OrderPayment
+	if err := r.db.WithContext(ctx).First(&dbPayment, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrTenderNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbPayment), nil
+}
+
+// FindByOrderID finds all payment tenders for an order
+func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.PaymentTender, error) {
+	var dbPayments []database.OrderPayment
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbPayments).Error; err != nil {
+		return nil, err
+	}
+
+	tenders := make([]*services.PaymentTender, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		tenders[i] = r.toDomain(&dbPayment)
+	}
+	return tenders, nil
+}
+
+// FindByGatewayReference finds the payment tender charged by a gateway payment intent
+func (r *PaymentRepository) FindByGatewayReference(ctx context.Context, reference string) (*services.PaymentTender, error) {
+	var dbPayment database.OrderPayment
+	if err := r.db.WithContext(ctx).First(&dbPayment, "gateway_reference = ?", reference).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrTenderNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbPayment), nil
+}
+
+// Save saves a payment tender
+func (r *PaymentRepository) Save(ctx context.Context, tender *services.PaymentTender) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(tender)).Error
+}
+
+// Helper methods
+
+func (r *PaymentRepository) toDomain(dbPayment *database.OrderPayment) *services.PaymentTender {
+	return &services.PaymentTender{
+		ID:               dbPayment.ID,
+		OrderID:          dbPayment.OrderID,
+		Type:             services.TenderType(dbPayment.TenderType),
+		PaymentMethodID:  dbPayment.PaymentMethodID,
+		Amount:           database.Int64ToMoney(dbPayment.Amount, dbPayment.Currency),
+		CapturedAmount:   database.Int64ToMoney(dbPayment.CapturedAmount, dbPayment.Currency),
+		RefundedAmount:   database.Int64ToMoney(dbPayment.RefundedAmount, dbPayment.Currency),
+		Status:           services.TenderStatus(dbPayment.Status),
+		GatewayReference: dbPayment.GatewayReference,
+		FailureReason:    dbPayment.FailureReason,
+		CreatedAt:        dbPayment.CreatedAt,
+		UpdatedAt:        dbPayment.UpdatedAt,
+	}
+}
+
+func (r *PaymentRepository) toDatabase(tender *services.PaymentTender) *database.OrderPayment {
+	return &database.OrderPayment{
+		ID:               tender.ID,
+		OrderID:          tender.OrderID,
+		TenderType:       string(tender.Type),
+		PaymentMethodID:  tender.PaymentMethodID,
+		Amount:           database.MoneyToInt64(tender.Amount),
+		CapturedAmount:   database.MoneyToInt64(tender.CapturedAmount),
+		RefundedAmount:   database.MoneyToInt64(tender.RefundedAmount),
+		Currency:         tender.Amount.Currency,
+		Status:           string(tender.Status),
+		GatewayReference: tender.GatewayReference,
+		FailureReason:    tender.FailureReason,
+		CreatedAt:        tender.CreatedAt,
+		UpdatedAt:        tender.UpdatedAt,
+	}
+}
+
+// PaymentRetryRepository implements services.PaymentRetryRepository using GORM
+type PaymentRetryRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRetryRepository creates a new PaymentRetryRepository
+func NewPaymentRetryRepository(db *gorm.DB) *PaymentRetryRepository {
+	return &PaymentRetryRepository{db: db}
+}
+
+// FindByOrderID finds the retry state for an order
+func (r *PaymentRetryRepository) FindByOrderID(ctx context.Context, orderID string) (*services.PaymentRetry, error) {
+	var dbRetry database.OrderPaymentRetry
+	if err := r.db.WithContext(ctx).First(&dbRetry, "order_id = ?", orderID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPaymentRetryNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbRetry), nil
+}
+
+// FindExpired finds pending retries whose window ended before the given time
+func (r *PaymentRetryRepository) FindExpired(ctx context.Context, before time.Time) ([]*services.PaymentRetry, error) {
+	var dbRetries []database.OrderPaymentRetry
+	if err := r.db.WithContext(ctx).
+		Where("state = ? AND expires_at < ?", string(services.PaymentRetryStatePending), before).
+		Find(&dbRetries).Error; err != nil {
+		return nil, err
+	}
+
+	retries := make([]*services.PaymentRetry, len(dbRetries))
+	for i, dbRetry := range dbRetries {
+		retries[i] = r.toDomain(&dbRetry)
+	}
+	return retries, nil
+}
+
+// Save saves the retry state for an order
+func (r *PaymentRetryRepository) Save(ctx context.Context, retry *services.PaymentRetry) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(retry)).Error
+}
+
+func (r *PaymentRetryRepository) toDomain(dbRetry *database.OrderPaymentRetry) *services.PaymentRetry {
+	return &services.PaymentRetry{
+		OrderID:           dbRetry.OrderID,
+		State:             services.PaymentRetryState(dbRetry.State),
+		Failures:          dbRetry.Failures,
+		LastFailureReason: dbRetry.LastFailureReason,
+		FirstFailedAt:     dbRetry.FirstFailedAt,
+		LastFailedAt:      dbRetry.LastFailedAt,
+		ExpiresAt:         dbRetry.ExpiresAt,
+		UpdatedAt:         dbRetry.UpdatedAt,
+	}
+}
+
+func (r *PaymentRetryRepository) toDatabase(retry *services.PaymentRetry) *database.OrderPaymentRetry {
+	return &database.OrderPaymentRetry{
+		OrderID:           retry.OrderID,
+		State:             string(retry.State),
+		Failures:          retry.Failures,
+		LastFailureReason: retry.LastFailureReason,
+		FirstFailedAt:     retry.FirstFailedAt,
+		LastFailedAt:      retry.LastFailedAt,
+		ExpiresAt:         retry.ExpiresAt,
+		UpdatedAt:         retry.UpdatedAt,
+	}
+}
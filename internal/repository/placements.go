@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PlacementRepository implements services.PlacementRepository using GORM
+type PlacementRepository struct {
+	db *gorm.DB
+}
+
+// NewPlacementRepository creates a new PlacementRepository
+func NewPlacementRepository(db *gorm.DB) *PlacementRepository {
+	return &PlacementRepository{db: db}
+}
+
+// FindByID finds a placement by ID
+func (r *PlacementRepository) FindByID(ctx context.Context, id string) (*services.Placement, error) {
+	var dbPlacement database.Placement
+	if err := r.db.WithContext(ctx).First(&dbPlacement, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPlacementNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&dbPlacement), nil
+}
+
+// FindBySlot finds all placements in a slot, ordered by position
+func (r *PlacementRepository) FindBySlot(ctx context.Context, slot string) ([]*services.Placement, error) {
+	return r.List(ctx, services.PlacementFilter{Slot: slot})
+}
+
+// placementSortColumns maps services.PlacementSortFields to their columns
+var placementSortColumns = map[string]string{
+	"slot":       "slot",
+	"position":   "position",
+	"title":      "title",
+	"starts_at":  "starts_at",
+	"ends_at":    "ends_at",
+	"created_at": "created_at",
+}
+
+// List lists placements matching the filter in the filter's order, then by slot and
+// position
+func (r *PlacementRepository) List(ctx context.Context, filter services.PlacementFilter) ([]*services.Placement, error) {
+	query := applySort(r.applyFilter(r.db.WithContext(ctx), filter), filter.Sort, placementSortColumns, "slot ASC, position ASC, created_at ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbPlacements []database.Placement
+	if err := query.Find(&dbPlacements).Error; err != nil {
+		return nil, err
+	}
+
+	placements := make([]*services.Placement, len(dbPlacements))
+	for i, dbPlacement := range dbPlacements {
+		placements[i] = r.toDomain(&dbPlacement)
+	}
+	return placements, nil
+}
+
+// Count counts placements matching the filter
+func (r *PlacementRepository) Count(ctx context.Context, filter services.PlacementFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.WithContext(ctx).Model(&database.Placement{}), filter).Count(&count).Error
+	return count, err
+}
+
+// Save saves a placement
+func (r *PlacementRepository) Save(ctx context.Context, placement *services.Placement) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(placement)).Error
+}
+
+// Delete deletes a placement
+func (r *PlacementRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.Placement{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrPlacementNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *PlacementRepository) applyFilter(query *gorm.DB, filter services.PlacementFilter) *gorm.DB {
+	if filter.Slot != "" {
+		query = query.Where("slot = ?", filter.Slot)
+	}
+	return query
+}
+
+func (r *PlacementRepository) toDomain(dbPlacement *database.Placement) *services.Placement {
+	return &services.Placement{
+		ID:        dbPlacement.ID,
+		Slot:      dbPlacement.Slot,
+		Title:     dbPlacement.Title,
+		Subtitle:  dbPlacement.Subtitle,
+		ImageURL:  dbPlacement.ImageURL,
+		ImageAlt:  dbPlacement.ImageAlt,
+		TargetURL: dbPlacement.TargetURL,
+		Position:  dbPlacement.Position,
+		IsActive:  dbPlacement.IsActive,
+		StartsAt:  dbPlacement.StartsAt,
+		EndsAt:    dbPlacement.EndsAt,
+		CreatedAt: dbPlacement.CreatedAt,
+		UpdatedAt: dbPlacement.UpdatedAt,
+	}
+}
+
+func (r *PlacementRepository) toDatabase(placement *services.Placement) *database.Placement {
+	return &database.Placement{
+		ID:        placement.ID,
+		Slot:      placement.Slot,
+		Title:     placement.Title,
+		Subtitle:  placement.Subtitle,
+		ImageURL:  placement.ImageURL,
+		ImageAlt:  placement.ImageAlt,
+		TargetURL: placement.TargetURL,
+		Position:  placement.Position,
+		IsActive:  placement.IsActive,
+		StartsAt:  placement.StartsAt,
+		EndsAt:    placement.EndsAt,
+		CreatedAt: placement.CreatedAt,
+		UpdatedAt: placement.UpdatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// POSRepository implements services.POSRepository using GORM
+type POSRepository struct {
+	db       *gorm.DB
+	payments *PaymentRepository
+}
+
+// NewPOSRepository creates a new POSRepository
+func NewPOSRepository(db *gorm.DB) *POSRepository {
+	return &POSRepository{db: db, payments: NewPaymentRepository(db)}
+}
+
+// Save saves an in-store sale
+func (r *POSRepository) Save(ctx context.Context, sale *services.POSSale) error {
+	return r.db.WithContext(ctx).Save(&database.POSSale{
+		OrderID:    sale.OrderID,
+		RegisterID: sale.RegisterID,
+		TerminalID: sale.TerminalID,
+		APIKeyID:   sale.APIKeyID,
+		CreatedAt:  sale.CreatedAt,
+	}).Error
+}
+
+// ListSales lists a register's sales made within [from, to), oldest first
+func (r *POSRepository) ListSales(ctx context.Context, registerID string, from, to time.Time) ([]*services.POSSale, error) {
+	var dbSales []database.POSSale
+	err := r.db.WithContext(ctx).
+		Where("register_id = ? AND created_at >= ? AND created_at < ?", registerID, from, to).
+		Order("created_at ASC").
+		Find(&dbSales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make([]*services.POSSale, len(dbSales))
+	for i, dbSale := range dbSales {
+		sales[i] = &services.POSSale{
+			OrderID:    dbSale.OrderID,
+			RegisterID: dbSale.RegisterID,
+			TerminalID: dbSale.TerminalID,
+			APIKeyID:   dbSale.APIKeyID,
+			CreatedAt:  dbSale.CreatedAt,
+		}
+	}
+	return sales, nil
+}
+
+// ListSaleTotals reads the totals of the given orders, without decrypting their addresses
+func (r *POSRepository) ListSaleTotals(ctx context.Context, orderIDs []string) ([]*services.POSSaleTotals, error) {
+	var dbOrders []database.Order
+	err := r.db.WithContext(ctx).
+		Select("id", "status", "total", "discount_total", "tax_total", "currency").
+		Where("id IN ?", orderIDs).
+		Find(&dbOrders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make([]*services.POSSaleTotals, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		totals[i] = &services.POSSaleTotals{
+			OrderID:       dbOrder.ID,
+			Status:        orders.OrderStatus(dbOrder.Status),
+			Total:         database.Int64ToMoney(dbOrder.Total, dbOrder.Currency),
+			DiscountTotal: database.Int64ToMoney(dbOrder.DiscountTotal, dbOrder.Currency),
+			TaxTotal:      database.Int64ToMoney(dbOrder.TaxTotal, dbOrder.Currency),
+		}
+	}
+	return totals, nil
+}
+
+// ListSaleTenders reads the payment tenders of the given orders
+func (r *POSRepository) ListSaleTenders(ctx context.Context, orderIDs []string) ([]*services.PaymentTender, error) {
+	var dbPayments []database.OrderPayment
+	if err := r.db.WithContext(ctx).Where("order_id IN ?", orderIDs).Find(&dbPayments).Error; err != nil {
+		return nil, err
+	}
+
+	tenders := make([]*services.PaymentTender, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		tenders[i] = r.payments.toDomain(&dbPayment)
+	}
+	return tenders, nil
+}
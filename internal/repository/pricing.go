@@ -108,6 +108,57 @@ func (r *ProductPriceRepository) FindEffectivePrices(ctx context.Context, produc
 	return result, nil
 }
 
+// FindEffectiveVariantPrices finds the effective prices of several of a product's variants
+// at a given time in one query, keyed by variant ID
+func (r *ProductPriceRepository) FindEffectiveVariantPrices(ctx context.Context, productID string, variantIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error) {
+	result := make(map[string]*pricing.ProductPrice)
+	if len(variantIDs) == 0 {
+		return result, nil
+	}
+
+	var dbPrices []database.ProductPrice
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND variant_id IN ? AND is_active = ?", productID, variantIDs, true).
+		Where("(valid_from IS NULL OR valid_from <= ?)", at).
+		Where("(valid_to IS NULL OR valid_to >= ?)", at).
+		Order("priority DESC").
+		Find(&dbPrices).Error; err != nil {
+		return nil, err
+	}
+
+	// Group by variant_id, keeping highest priority (first due to ORDER BY)
+	for _, dbPrice := range dbPrices {
+		if dbPrice.VariantID == nil {
+			continue
+		}
+		if _, exists := result[*dbPrice.VariantID]; !exists {
+			result[*dbPrice.VariantID] = r.toDomain(&dbPrice)
+		}
+	}
+	return result, nil
+}
+
+// DeactivateEnded deactivates active prices whose validity window ended before the given time
+func (r *ProductPriceRepository) DeactivateEnded(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&database.ProductPrice{}).
+		Where("is_active = ? AND valid_to IS NOT NULL AND valid_to < ?", true, before).
+		Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
+// FindProductsWithPriceChanges finds products with a price whose window opened or closed
+// between from and to. Inactive rows are included, since ended prices get deactivated.
+func (r *ProductPriceRepository) FindProductsWithPriceChanges(ctx context.Context, from, to time.Time) ([]string, error) {
+	var productIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&database.ProductPrice{}).
+		Distinct("product_id").
+		Where("(valid_from > ? AND valid_from <= ?) OR (valid_to >= ? AND valid_to < ?)", from, to, from, to).
+		Pluck("product_id", &productIDs).Error
+	return productIDs, err
+}
+
 // Save saves a product price
 func (r *ProductPriceRepository) Save(ctx context.Context, price *pricing.ProductPrice) error {
 	dbPrice := r.toDatabase(price)
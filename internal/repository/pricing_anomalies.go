@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PricingAnomalyRepository implements services.PricingAnomalyRepository using GORM
+type PricingAnomalyRepository struct {
+	db *gorm.DB
+}
+
+// NewPricingAnomalyRepository creates a new PricingAnomalyRepository
+func NewPricingAnomalyRepository(db *gorm.DB) *PricingAnomalyRepository {
+	return &PricingAnomalyRepository{db: db}
+}
+
+// FindByID finds a pricing anomaly by ID
+func (r *PricingAnomalyRepository) FindByID(ctx context.Context, id string) (*services.PricingAnomaly, error) {
+	var dbAnomaly database.PricingAnomaly
+	if err := r.db.WithContext(ctx).First(&dbAnomaly, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPricingAnomalyNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbAnomaly), nil
+}
+
+// List lists pricing anomalies, newest first, optionally only those in one status
+func (r *PricingAnomalyRepository) List(ctx context.Context, status services.PricingAnomalyStatus, limit, offset int) ([]*services.PricingAnomaly, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var dbAnomalies []database.PricingAnomaly
+	if err := query.Find(&dbAnomalies).Error; err != nil {
+		return nil, err
+	}
+	anomalies := make([]*services.PricingAnomaly, len(dbAnomalies))
+	for i := range dbAnomalies {
+		anomalies[i] = r.toDomain(&dbAnomalies[i])
+	}
+	return anomalies, nil
+}
+
+// HasPendingForOrder reports whether an order has an anomaly awaiting review
+func (r *PricingAnomalyRepository) HasPendingForOrder(ctx context.Context, orderID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.PricingAnomaly{}).
+		Where("order_id = ? AND status = ?", orderID, string(services.AnomalyPending)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Save creates or updates a pricing anomaly
+func (r *PricingAnomalyRepository) Save(ctx context.Context, anomaly *services.PricingAnomaly) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(anomaly)).Error
+}
+
+// Helper methods
+
+func (r *PricingAnomalyRepository) toDomain(dbAnomaly *database.PricingAnomaly) *services.PricingAnomaly {
+	amount := func(minor *int64) *money.Money {
+		if minor == nil {
+			return nil
+		}
+		m := database.Int64ToMoney(*minor, dbAnomaly.Currency)
+		return &m
+	}
+
+	anomaly := &services.PricingAnomaly{
+		ID:         dbAnomaly.ID,
+		Kind:       services.PricingAnomalyKind(dbAnomaly.Kind),
+		Reason:     services.PricingAnomalyReason(dbAnomaly.Reason),
+		Status:     services.PricingAnomalyStatus(dbAnomaly.Status),
+		ProductID:  dbAnomaly.ProductID,
+		VariantID:  dbAnomaly.VariantID,
+		OrderID:    dbAnomaly.OrderID,
+		OldPrice:   amount(dbAnomaly.OldPrice),
+		NewPrice:   amount(dbAnomaly.NewPrice),
+		OrderTotal: amount(dbAnomaly.OrderTotal),
+		ItemValue:  amount(dbAnomaly.ItemValue),
+		Message:    dbAnomaly.Message,
+		ReviewedBy: dbAnomaly.ReviewedBy,
+		ReviewedAt: dbAnomaly.ReviewedAt,
+		CreatedAt:  dbAnomaly.CreatedAt,
+	}
+	if dbAnomaly.Change != "" && dbAnomaly.Change != "null" {
+		anomaly.Change = []byte(dbAnomaly.Change)
+	}
+	return anomaly
+}
+
+func (r *PricingAnomalyRepository) toDatabase(anomaly *services.PricingAnomaly) *database.PricingAnomaly {
+	dbAnomaly := &database.PricingAnomaly{
+		ID:         anomaly.ID,
+		Kind:       string(anomaly.Kind),
+		Reason:     string(anomaly.Reason),
+		Status:     string(anomaly.Status),
+		ProductID:  anomaly.ProductID,
+		VariantID:  anomaly.VariantID,
+		OrderID:    anomaly.OrderID,
+		Message:    anomaly.Message,
+		Change:     string(jsonOrNull(anomaly.Change)),
+		ReviewedBy: anomaly.ReviewedBy,
+		ReviewedAt: anomaly.ReviewedAt,
+		CreatedAt:  anomaly.CreatedAt,
+	}
+
+	// One currency column: the new price's or the order's. An old price in another
+	// currency isn't comparable, so it isn't kept.
+	for _, m := range []*money.Money{anomaly.NewPrice, anomaly.OrderTotal, anomaly.ItemValue} {
+		if m != nil {
+			dbAnomaly.Currency = m.Currency
+			break
+		}
+	}
+	amount := func(m *money.Money) *int64 {
+		if m == nil || m.Currency != dbAnomaly.Currency {
+			return nil
+		}
+		minor := database.MoneyToInt64(*m)
+		return &minor
+	}
+	dbAnomaly.OldPrice = amount(anomaly.OldPrice)
+	dbAnomaly.NewPrice = amount(anomaly.NewPrice)
+	dbAnomaly.OrderTotal = amount(anomaly.OrderTotal)
+	dbAnomaly.ItemValue = amount(anomaly.ItemValue)
+	return dbAnomaly
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ProductMediaRepository implements services.ProductMediaRepository using GORM
+type ProductMediaRepository struct {
+	db *gorm.DB
+}
+
+// NewProductMediaRepository creates a new ProductMediaRepository
+func NewProductMediaRepository(db *gorm.DB) *ProductMediaRepository {
+	return &ProductMediaRepository{db: db}
+}
+
+// FindByProduct finds a product's media in gallery order
+func (r *ProductMediaRepository) FindByProduct(ctx context.Context, productID string) ([]*services.ProductMedia, error) {
+	var rows []database.ProductMedia
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("position ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	media := make([]*services.ProductMedia, len(rows))
+	for i := range rows {
+		media[i] = r.toDomain(&rows[i])
+	}
+	return media, nil
+}
+
+// FindByProducts finds the media of several products in gallery order, by product ID
+func (r *ProductMediaRepository) FindByProducts(ctx context.Context, productIDs []string) (map[string][]*services.ProductMedia, error) {
+	media := make(map[string][]*services.ProductMedia)
+	if len(productIDs) == 0 {
+		return media, nil
+	}
+
+	var rows []database.ProductMedia
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Order("product_id, position ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		media[rows[i].ProductID] = append(media[rows[i].ProductID], r.toDomain(&rows[i]))
+	}
+	return media, nil
+}
+
+// Replace replaces a product's media
+func (r *ProductMediaRepository) Replace(ctx context.Context, productID string, media []*services.ProductMedia) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&database.ProductMedia{}, "product_id = ?", productID).Error; err != nil {
+			return err
+		}
+		if len(media) == 0 {
+			return nil
+		}
+
+		rows := make([]database.ProductMedia, len(media))
+		for i, item := range media {
+			rows[i] = database.ProductMedia{
+				ProductID: productID,
+				Position:  item.Position,
+				Type:      string(item.Type),
+				URL:       item.URL,
+				PosterURL: item.PosterURL,
+				Alt:       item.Alt,
+				MimeType:  item.MimeType,
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Helper methods
+
+func (r *ProductMediaRepository) toDomain(row *database.ProductMedia) *services.ProductMedia {
+	return &services.ProductMedia{
+		Type:      services.MediaType(row.Type),
+		URL:       row.URL,
+		PosterURL: row.PosterURL,
+		Alt:       row.Alt,
+		MimeType:  row.MimeType,
+		Position:  row.Position,
+	}
+}
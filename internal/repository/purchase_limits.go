@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// PurchaseLimitRepository implements services.PurchaseLimitRepository using GORM
+type PurchaseLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewPurchaseLimitRepository creates a new PurchaseLimitRepository
+func NewPurchaseLimitRepository(db *gorm.DB) *PurchaseLimitRepository {
+	return &PurchaseLimitRepository{db: db}
+}
+
+// FindByProductID finds a product's purchase limit
+func (r *PurchaseLimitRepository) FindByProductID(ctx context.Context, productID string) (*services.PurchaseLimit, error) {
+	var dbLimit database.PurchaseLimit
+	if err := r.db.WithContext(ctx).First(&dbLimit, "product_id = ?", productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrPurchaseLimitNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbLimit), nil
+}
+
+// FindByProductIDs finds the purchase limits of the given products; products without one are skipped
+func (r *PurchaseLimitRepository) FindByProductIDs(ctx context.Context, productIDs []string) ([]*services.PurchaseLimit, error) {
+	var dbLimits []database.PurchaseLimit
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&dbLimits).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbLimits), nil
+}
+
+// FindAll finds all purchase limits
+func (r *PurchaseLimitRepository) FindAll(ctx context.Context) ([]*services.PurchaseLimit, error) {
+	var dbLimits []database.PurchaseLimit
+	if err := r.db.WithContext(ctx).Order("product_id ASC").Find(&dbLimits).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbLimits), nil
+}
+
+// Save creates or replaces a product's purchase limit
+func (r *PurchaseLimitRepository) Save(ctx context.Context, limit *services.PurchaseLimit) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(limit)).Error
+}
+
+// Delete deletes a product's purchase limit
+func (r *PurchaseLimitRepository) Delete(ctx context.Context, productID string) error {
+	result := r.db.WithContext(ctx).Delete(&database.PurchaseLimit{}, "product_id = ?", productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrPurchaseLimitNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *PurchaseLimitRepository) toDomain(dbLimit *database.PurchaseLimit) *services.PurchaseLimit {
+	return &services.PurchaseLimit{
+		ProductID:      dbLimit.ProductID,
+		MaxPerOrder:    dbLimit.MaxPerOrder,
+		MaxPerCustomer: dbLimit.MaxPerCustomer,
+		PeriodDays:     dbLimit.PeriodDays,
+		UpdatedAt:      dbLimit.UpdatedAt,
+	}
+}
+
+func (r *PurchaseLimitRepository) toDomainList(dbLimits []database.PurchaseLimit) []*services.PurchaseLimit {
+	limits := make([]*services.PurchaseLimit, len(dbLimits))
+	for i := range dbLimits {
+		limits[i] = r.toDomain(&dbLimits[i])
+	}
+	return limits
+}
+
+func (r *PurchaseLimitRepository) toDatabase(limit *services.PurchaseLimit) *database.PurchaseLimit {
+	return &database.PurchaseLimit{
+		ProductID:      limit.ProductID,
+		MaxPerOrder:    limit.MaxPerOrder,
+		MaxPerCustomer: limit.MaxPerCustomer,
+		PeriodDays:     limit.PeriodDays,
+		UpdatedAt:      limit.UpdatedAt,
+	}
+}
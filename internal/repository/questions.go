@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// QuestionRepository implements services.QuestionRepository using GORM
+type QuestionRepository struct {
+	db *gorm.DB
+}
+
+// NewQuestionRepository creates a new QuestionRepository
+func NewQuestionRepository(db *gorm.DB) *QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// FindByID finds a question by ID with its answers
+func (r *QuestionRepository) FindByID(ctx context.Context, id string) (*services.ProductQuestion, error) {
+	var dbQuestion database.ProductQuestion
+	if err := r.db.WithContext(ctx).First(&dbQuestion, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrQuestionNotFound
+		}
+		return nil, err
+	}
+
+	question := r.toDomain(&dbQuestion)
+	if err := r.loadAnswers(ctx, []*services.ProductQuestion{question}); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// FindByProduct finds a product's questions with their answers, newest first
+func (r *QuestionRepository) FindByProduct(ctx context.Context, productID string) ([]*services.ProductQuestion, error) {
+	var dbQuestions []database.ProductQuestion
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at DESC").
+		Find(&dbQuestions).Error; err != nil {
+		return nil, err
+	}
+
+	questions := make([]*services.ProductQuestion, len(dbQuestions))
+	for i := range dbQuestions {
+		questions[i] = r.toDomain(&dbQuestions[i])
+	}
+	if err := r.loadAnswers(ctx, questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// FindAnswerByID finds an answer by ID
+func (r *QuestionRepository) FindAnswerByID(ctx context.Context, id string) (*services.ProductAnswer, error) {
+	var dbAnswer database.ProductAnswer
+	if err := r.db.WithContext(ctx).First(&dbAnswer, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrAnswerNotFound
+		}
+		return nil, err
+	}
+	answer := answerToDomain(&dbAnswer)
+	return &answer, nil
+}
+
+// Save creates or replaces a question
+func (r *QuestionRepository) Save(ctx context.Context, question *services.ProductQuestion) error {
+	return r.db.WithContext(ctx).Save(&database.ProductQuestion{
+		ID:        question.ID,
+		ProductID: question.ProductID,
+		UserID:    question.UserID,
+		Body:      question.Body,
+		Filtered:  question.Filtered,
+		Hidden:    question.Hidden,
+		CreatedAt: question.CreatedAt,
+		UpdatedAt: question.UpdatedAt,
+	}).Error
+}
+
+// AddAnswer records an answer to a question
+func (r *QuestionRepository) AddAnswer(ctx context.Context, answer *services.ProductAnswer) error {
+	return r.db.WithContext(ctx).Create(&database.ProductAnswer{
+		ID:         answer.ID,
+		QuestionID: answer.QuestionID,
+		UserID:     answer.UserID,
+		Staff:      answer.Staff,
+		Body:       answer.Body,
+		Filtered:   answer.Filtered,
+		Hidden:     answer.Hidden,
+		CreatedAt:  answer.CreatedAt,
+	}).Error
+}
+
+// SetQuestionHidden hides a question from the product or shows it again
+func (r *QuestionRepository) SetQuestionHidden(ctx context.Context, id string, hidden bool) error {
+	return r.db.WithContext(ctx).Model(&database.ProductQuestion{}).
+		Where("id = ?", id).
+		Update("hidden", hidden).Error
+}
+
+// SetAnswerHidden hides an answer from its question or shows it again
+func (r *QuestionRepository) SetAnswerHidden(ctx context.Context, id string, hidden bool) error {
+	return r.db.WithContext(ctx).Model(&database.ProductAnswer{}).
+		Where("id = ?", id).
+		Update("hidden", hidden).Error
+}
+
+// Helper methods
+
+// loadAnswers fills in the questions' answers, oldest first, in one query
+func (r *QuestionRepository) loadAnswers(ctx context.Context, questions []*services.ProductQuestion) error {
+	if len(questions) == 0 {
+		return nil
+	}
+	ids := make([]string, len(questions))
+	byID := make(map[string]*services.ProductQuestion, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+		byID[question.ID] = question
+	}
+
+	var dbAnswers []database.ProductAnswer
+	if err := r.db.WithContext(ctx).
+		Where("question_id IN ?", ids).
+		Order("created_at ASC").
+		Find(&dbAnswers).Error; err != nil {
+		return err
+	}
+	for i := range dbAnswers {
+		question := byID[dbAnswers[i].QuestionID]
+		question.Answers = append(question.Answers, answerToDomain(&dbAnswers[i]))
+	}
+	return nil
+}
+
+func (r *QuestionRepository) toDomain(dbQuestion *database.ProductQuestion) *services.ProductQuestion {
+	return &services.ProductQuestion{
+		ID:        dbQuestion.ID,
+		ProductID: dbQuestion.ProductID,
+		UserID:    dbQuestion.UserID,
+		Body:      dbQuestion.Body,
+		Filtered:  dbQuestion.Filtered,
+		Hidden:    dbQuestion.Hidden,
+		Answers:   []services.ProductAnswer{},
+		CreatedAt: dbQuestion.CreatedAt,
+		UpdatedAt: dbQuestion.UpdatedAt,
+	}
+}
+
+func answerToDomain(dbAnswer *database.ProductAnswer) services.ProductAnswer {
+	return services.ProductAnswer{
+		ID:         dbAnswer.ID,
+		QuestionID: dbAnswer.QuestionID,
+		UserID:     dbAnswer.UserID,
+		Staff:      dbAnswer.Staff,
+		Body:       dbAnswer.Body,
+		Filtered:   dbAnswer.Filtered,
+		Hidden:     dbAnswer.Hidden,
+		CreatedAt:  dbAnswer.CreatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// QuotaRepository implements services.QuotaRepository using GORM
+type QuotaRepository struct {
+	db *gorm.DB
+}
+
+// NewQuotaRepository creates a new QuotaRepository
+func NewQuotaRepository(db *gorm.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+// Increment adds one to a subject's counter for a window and returns the new count. The
+// add is a single upsert, so concurrent requests don't overwrite each other.
+func (r *QuotaRepository) Increment(ctx context.Context, subject, tier string, period services.QuotaPeriod, start time.Time) (int64, error) {
+	now := time.Now()
+	var requests int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject"}, {Name: "period"}, {Name: "period_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"requests":   gorm.Expr("quota_counters.requests + 1"),
+				"tier":       tier,
+				"updated_at": now,
+			}),
+		}).Create(&database.QuotaCounter{
+			Subject:     subject,
+			Period:      string(period),
+			PeriodStart: start,
+			Tier:        tier,
+			Requests:    1,
+			UpdatedAt:   now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&database.QuotaCounter{}).
+			Where("subject = ? AND period = ? AND period_start = ?", subject, string(period), start).
+			Pluck("requests", &requests).Error
+	})
+	return requests, err
+}
+
+// FindBySubject returns a subject's counters for windows starting at or after since
+func (r *QuotaRepository) FindBySubject(ctx context.Context, subject string, since time.Time) ([]*services.QuotaCounter, error) {
+	var dbCounters []database.QuotaCounter
+	if err := r.db.WithContext(ctx).
+		Where("subject = ? AND period_start >= ?", subject, since).
+		Order("period_start ASC").
+		Find(&dbCounters).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbCounters), nil
+}
+
+// ListByPeriod lists the counters of one window, most requests first
+func (r *QuotaRepository) ListByPeriod(ctx context.Context, period services.QuotaPeriod, start time.Time, limit, offset int) ([]*services.QuotaCounter, error) {
+	var dbCounters []database.QuotaCounter
+	if err := r.db.WithContext(ctx).
+		Where("period = ? AND period_start = ?", string(period), start).
+		Order("requests DESC, subject ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&dbCounters).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomainList(dbCounters), nil
+}
+
+// CountByPeriod counts the counters of one window
+func (r *QuotaRepository) CountByPeriod(ctx context.Context, period services.QuotaPeriod, start time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.QuotaCounter{}).
+		Where("period = ? AND period_start = ?", string(period), start).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteBySubject deletes a subject's counters
+func (r *QuotaRepository) DeleteBySubject(ctx context.Context, subject string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("subject = ?", subject).Delete(&database.QuotaCounter{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteBefore deletes counters for windows starting before before
+func (r *QuotaRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("period_start < ?", before).Delete(&database.QuotaCounter{})
+	return result.RowsAffected, result.Error
+}
+
+// Helper methods
+
+func (r *QuotaRepository) toDomainList(dbCounters []database.QuotaCounter) []*services.QuotaCounter {
+	counters := make([]*services.QuotaCounter, len(dbCounters))
+	for i, dbCounter := range dbCounters {
+		counters[i] = &services.QuotaCounter{
+			Subject:     dbCounter.Subject,
+			Tier:        dbCounter.Tier,
+			Period:      services.QuotaPeriod(dbCounter.Period),
+			PeriodStart: dbCounter.PeriodStart,
+			Requests:    dbCounter.Requests,
+			UpdatedAt:   dbCounter.UpdatedAt,
+		}
+	}
+	return counters
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// QuoteRepository implements services.QuoteRepository using GORM
+type QuoteRepository struct {
+	db *gorm.DB
+}
+
+// NewQuoteRepository creates a new QuoteRepository
+func NewQuoteRepository(db *gorm.DB) *QuoteRepository {
+	return &QuoteRepository{db: db}
+}
+
+// FindByID finds a quote by ID with its items
+func (r *QuoteRepository) FindByID(ctx context.Context, id string) (*services.Quote, error) {
+	var dbQuote database.Quote
+	if err := r.db.WithContext(ctx).First(&dbQuote, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrQuoteNotFound
+		}
+		return nil, err
+	}
+
+	quotes, err := r.toDomain(ctx, []database.Quote{dbQuote})
+	if err != nil {
+		return nil, err
+	}
+	return quotes[0], nil
+}
+
+// quoteSortColumns maps services.QuoteSortFields to their columns
+var quoteSortColumns = map[string]string{
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+	"status":      "status",
+	"subtotal":    "subtotal_amount",
+	"valid_until": "valid_until",
+}
+
+// List lists quotes matching the filter in the filter's order, then newest first
+func (r *QuoteRepository) List(ctx context.Context, filter services.QuoteFilter) ([]*services.Quote, error) {
+	query := r.db.WithContext(ctx)
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	query = applySort(query, filter.Sort, quoteSortColumns, "created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbQuotes []database.Quote
+	if err := query.Find(&dbQuotes).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(ctx, dbQuotes)
+}
+
+// Save saves a quote and its items in a single transaction. Items are never removed
+// from a quote, so saving them is enough.
+func (r *QuoteRepository) Save(ctx context.Context, quote *services.Quote) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(r.toDatabase(quote)).Error; err != nil {
+			return err
+		}
+		for i, item := range quote.Items {
+			if err := tx.Save(toDatabaseQuoteItem(quote.ID, i, item)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MarkAccepted records the order a quote became, only while the quote is still sent
+func (r *QuoteRepository) MarkAccepted(ctx context.Context, id, orderID string, acceptedAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&database.Quote{}).
+		Where("id = ? AND status = ?", id, string(services.QuoteStatusSent)).
+		Updates(map[string]interface{}{
+			"status":      string(services.QuoteStatusAccepted),
+			"order_id":    orderID,
+			"accepted_at": acceptedAt,
+			"updated_at":  acceptedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrQuoteNotOpen
+	}
+	return nil
+}
+
+// Helper methods
+
+// toDomain converts quotes, loading the items of all of them in one query
+func (r *QuoteRepository) toDomain(ctx context.Context, dbQuotes []database.Quote) ([]*services.Quote, error) {
+	quotes := make([]*services.Quote, len(dbQuotes))
+	if len(dbQuotes) == 0 {
+		return quotes, nil
+	}
+
+	byID := make(map[string]*services.Quote, len(dbQuotes))
+	ids := make([]string, len(dbQuotes))
+	for i := range dbQuotes {
+		dbQuote := &dbQuotes[i]
+		quotes[i] = &services.Quote{
+			ID:         dbQuote.ID,
+			UserID:     dbQuote.UserID,
+			Status:     services.QuoteStatus(dbQuote.Status),
+			Subtotal:   money.Money{Amount: dbQuote.SubtotalAmount, Currency: dbQuote.Currency},
+			Note:       dbQuote.Note,
+			SalesNote:  dbQuote.SalesNote,
+			ValidUntil: dbQuote.ValidUntil,
+			SentBy:     dbQuote.SentBy,
+			SentAt:     dbQuote.SentAt,
+			OrderID:    dbQuote.OrderID,
+			AcceptedAt: dbQuote.AcceptedAt,
+			CreatedAt:  dbQuote.CreatedAt,
+			UpdatedAt:  dbQuote.UpdatedAt,
+		}
+		byID[dbQuote.ID] = quotes[i]
+		ids[i] = dbQuote.ID
+	}
+
+	var dbItems []database.QuoteItem
+	err := r.db.WithContext(ctx).
+		Where("quote_id IN ?", ids).
+		Order("quote_id ASC, position ASC").
+		Find(&dbItems).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range dbItems {
+		item, err := toQuoteItem(&dbItems[i])
+		if err != nil {
+			return nil, err
+		}
+		quote := byID[dbItems[i].QuoteID]
+		quote.Items = append(quote.Items, *item)
+	}
+	return quotes, nil
+}
+
+func (r *QuoteRepository) toDatabase(quote *services.Quote) *database.Quote {
+	return &database.Quote{
+		ID:             quote.ID,
+		UserID:         quote.UserID,
+		Status:         string(quote.Status),
+		SubtotalAmount: quote.Subtotal.Amount,
+		Currency:       quote.Subtotal.Currency,
+		Note:           quote.Note,
+		SalesNote:      quote.SalesNote,
+		ValidUntil:     quote.ValidUntil,
+		SentBy:         quote.SentBy,
+		SentAt:         quote.SentAt,
+		OrderID:        quote.OrderID,
+		AcceptedAt:     quote.AcceptedAt,
+		CreatedAt:      quote.CreatedAt,
+		UpdatedAt:      quote.UpdatedAt,
+	}
+}
+
+func toQuoteItem(dbItem *database.QuoteItem) (*services.QuoteItem, error) {
+	var attrs map[string]string
+	if err := database.UnmarshalJSON(dbItem.Attributes, &attrs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quote item attributes: %w", err)
+	}
+	return &services.QuoteItem{
+		ID:          dbItem.ID,
+		ProductID:   dbItem.ProductID,
+		VariantID:   dbItem.VariantID,
+		SKU:         dbItem.SKU,
+		Name:        dbItem.Name,
+		Quantity:    dbItem.Quantity,
+		ListPrice:   money.Money{Amount: dbItem.ListPriceAmount, Currency: dbItem.Currency},
+		QuotedPrice: money.Money{Amount: dbItem.QuotedPriceAmount, Currency: dbItem.Currency},
+		Attributes:  attrs,
+	}, nil
+}
+
+func toDatabaseQuoteItem(quoteID string, position int, item services.QuoteItem) *database.QuoteItem {
+	attrs := "{}"
+	if item.Attributes != nil {
+		attrs = database.MarshalJSON(item.Attributes)
+	}
+	return &database.QuoteItem{
+		ID:                item.ID,
+		QuoteID:           quoteID,
+		Position:          position,
+		ProductID:         item.ProductID,
+		VariantID:         item.VariantID,
+		SKU:               item.SKU,
+		Name:              item.Name,
+		Quantity:          item.Quantity,
+		ListPriceAmount:   item.ListPrice.Amount,
+		QuotedPriceAmount: item.QuotedPrice.Amount,
+		Currency:          item.ListPrice.Currency,
+		Attributes:        attrs,
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RecentlyViewedRepository implements services.RecentlyViewedRepository using GORM
+type RecentlyViewedRepository struct {
+	db *gorm.DB
+}
+
+// NewRecentlyViewedRepository creates a new RecentlyViewedRepository
+func NewRecentlyViewedRepository(db *gorm.DB) *RecentlyViewedRepository {
+	return &RecentlyViewedRepository{db: db}
+}
+
+// Record stores a view and trims the user's views to the most recent keep
+func (r *RecentlyViewedRepository) Record(ctx context.Context, userID string, view services.ProductView, keep int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+		}).Create(&database.RecentlyViewedProduct{
+			UserID:    userID,
+			ProductID: view.ProductID,
+			ViewedAt:  view.ViewedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return r.trim(tx, userID, keep)
+	})
+}
+
+// List returns a user's views, most recent first
+func (r *RecentlyViewedRepository) List(ctx context.Context, userID string, limit int) ([]services.ProductView, error) {
+	var dbViews []database.RecentlyViewedProduct
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("viewed_at DESC").
+		Limit(limit).
+		Find(&dbViews).Error; err != nil {
+		return nil, err
+	}
+
+	views := make([]services.ProductView, 0, len(dbViews))
+	for _, dbView := range dbViews {
+		views = append(views, services.ProductView{ProductID: dbView.ProductID, ViewedAt: dbView.ViewedAt})
+	}
+	return views, nil
+}
+
+// Move gives one user's views to another, keeping the later view of a product both viewed
+func (r *RecentlyViewedRepository) Move(ctx context.Context, fromUserID, toUserID string, keep int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO recently_viewed_products (user_id, product_id, viewed_at)
+			SELECT ?, product_id, viewed_at
+			FROM recently_viewed_products
+			WHERE user_id = ?
+			ON CONFLICT (user_id, product_id) DO UPDATE SET
+				viewed_at = GREATEST(recently_viewed_products.viewed_at, EXCLUDED.viewed_at)
+		`, toUserID, fromUserID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", fromUserID).Delete(&database.RecentlyViewedProduct{}).Error; err != nil {
+			return err
+		}
+		return r.trim(tx, toUserID, keep)
+	})
+}
+
+// trim deletes all but a user's most recent keep views
+func (r *RecentlyViewedRepository) trim(tx *gorm.DB, userID string, keep int) error {
+	return tx.Exec(`
+		DELETE FROM recently_viewed_products
+		WHERE user_id = ? AND product_id NOT IN (
+			SELECT product_id FROM recently_viewed_products
+			WHERE user_id = ?
+			ORDER BY viewed_at DESC
+			LIMIT ?
+		)
+	`, userID, userID, keep).Error
+}
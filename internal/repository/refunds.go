@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/payments"
+)
+
+// RefundRepository implements services.RefundRepository using GORM
+type RefundRepository struct {
+	db     *gorm.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewRefundRepository creates a new RefundRepository
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// WithCipher encrypts refund notes at rest
+func (r *RefundRepository) WithCipher(cipher *fieldcrypt.Cipher) *RefundRepository {
+	r.cipher = cipher
+	return r
+}
+
+// FindByOrderID finds all refunds for an order, oldest first
+func (r *RefundRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.OrderRefund, error) {
+	var dbRefunds []database.OrderRefund
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbRefunds).Error; err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*services.OrderRefund, len(dbRefunds))
+	for i, dbRefund := range dbRefunds {
+		refund, err := r.toDomain(&dbRefund)
+		if err != nil {
+			return nil, err
+		}
+		refunds[i] = refund
+	}
+	return refunds, nil
+}
+
+// Save saves a refund
+func (r *RefundRepository) Save(ctx context.Context, refund *services.OrderRefund) error {
+	dbRefund, err := r.toDatabase(refund)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Save(dbRefund).Error
+}
+
+// Helper methods
+
+func (r *RefundRepository) toDomain(dbRefund *database.OrderRefund) (*services.OrderRefund, error) {
+	note, err := r.cipher.Decrypt(dbRefund.Note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt refund note: %w", err)
+	}
+
+	refund := &services.OrderRefund{
+		ID:        dbRefund.ID,
+		OrderID:   dbRefund.OrderID,
+		Amount:    database.Int64ToMoney(dbRefund.Amount, dbRefund.Currency),
+		Reason:    payments.RefundReason(dbRefund.Reason),
+		Note:      note,
+		CreatedBy: dbRefund.CreatedBy,
+		CreatedAt: dbRefund.CreatedAt,
+	}
+
+	if err := database.UnmarshalJSON(dbRefund.Lines, &refund.Lines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refund lines: %w", err)
+	}
+	if err := database.UnmarshalJSON(dbRefund.Allocations, &refund.Allocations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal refund allocations: %w", err)
+	}
+	return refund, nil
+}
+
+func (r *RefundRepository) toDatabase(refund *services.OrderRefund) (*database.OrderRefund, error) {
+	note, err := r.cipher.Encrypt(refund.Note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt refund note: %w", err)
+	}
+
+	return &database.OrderRefund{
+		ID:          refund.ID,
+		OrderID:     refund.OrderID,
+		Amount:      database.MoneyToInt64(refund.Amount),
+		Currency:    refund.Amount.Currency,
+		Reason:      string(refund.Reason),
+		Note:        note,
+		Lines:       database.MarshalJSON(refund.Lines),
+		Allocations: database.MarshalJSON(refund.Allocations),
+		CreatedBy:   refund.CreatedBy,
+		CreatedAt:   refund.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RentalRepository implements services.RentalRepository using GORM
+type RentalRepository struct {
+	db *gorm.DB
+}
+
+// NewRentalRepository creates a new RentalRepository
+func NewRentalRepository(db *gorm.DB) *RentalRepository {
+	return &RentalRepository{db: db}
+}
+
+// FindProduct finds a product's rental settings
+func (r *RentalRepository) FindProduct(ctx context.Context, productID string) (*services.RentalProduct, error) {
+	var dbRental database.RentalProduct
+	if err := r.db.WithContext(ctx).First(&dbRental, "product_id = ?", productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrRentalNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbRental)
+}
+
+// SaveProduct creates or replaces a product's rental settings
+func (r *RentalRepository) SaveProduct(ctx context.Context, rental *services.RentalProduct) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(rental)).Error
+}
+
+// DeleteProduct deletes a product's rental settings
+func (r *RentalRepository) DeleteProduct(ctx context.Context, productID string) error {
+	result := r.db.WithContext(ctx).Delete(&database.RentalProduct{}, "product_id = ?", productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrRentalNotFound
+	}
+	return nil
+}
+
+// FindBookings finds the bookings of a SKU running on any day from from to to
+func (r *RentalRepository) FindBookings(ctx context.Context, sku string, from, to time.Time) ([]*services.RentalBooking, error) {
+	var dbBookings []database.RentalBooking
+	if err := r.db.WithContext(ctx).
+		Where("sku = ? AND start_date <= ? AND end_date >= ?", sku, to, from).
+		Order("start_date ASC").
+		Find(&dbBookings).Error; err != nil {
+		return nil, err
+	}
+
+	bookings := make([]*services.RentalBooking, len(dbBookings))
+	for i := range dbBookings {
+		bookings[i] = r.bookingToDomain(&dbBookings[i])
+	}
+	return bookings, nil
+}
+
+// SaveBookings creates an order's bookings in one transaction
+func (r *RentalRepository) SaveBookings(ctx context.Context, bookings []*services.RentalBooking) error {
+	dbBookings := make([]database.RentalBooking, len(bookings))
+	for i, booking := range bookings {
+		dbBookings[i] = database.RentalBooking{
+			ID:          booking.ID,
+			ProductID:   booking.ProductID,
+			SKU:         booking.SKU,
+			OrderID:     booking.OrderID,
+			OrderItemID: booking.OrderItemID,
+			Quantity:    booking.Quantity,
+			StartDate:   booking.Start,
+			EndDate:     booking.End,
+			CreatedAt:   booking.CreatedAt,
+		}
+	}
+	return r.db.WithContext(ctx).Create(&dbBookings).Error
+}
+
+// DeleteOrderBookings deletes the bookings of an order
+func (r *RentalRepository) DeleteOrderBookings(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).Delete(&database.RentalBooking{}, "order_id = ?", orderID).Error
+}
+
+// Helper methods
+
+func (r *RentalRepository) toDomain(dbRental *database.RentalProduct) (*services.RentalProduct, error) {
+	var skuUnits map[string]int
+	if err := database.UnmarshalJSON(dbRental.SKUUnits, &skuUnits); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rental sku units: %w", err)
+	}
+
+	return &services.RentalProduct{
+		ProductID:  dbRental.ProductID,
+		Units:      dbRental.Units,
+		SKUUnits:   skuUnits,
+		MinDays:    dbRental.MinDays,
+		MaxDays:    dbRental.MaxDays,
+		BufferDays: dbRental.BufferDays,
+		UpdatedAt:  dbRental.UpdatedAt,
+	}, nil
+}
+
+func (r *RentalRepository) toDatabase(rental *services.RentalProduct) *database.RentalProduct {
+	dbRental := &database.RentalProduct{
+		ProductID:  rental.ProductID,
+		Units:      rental.Units,
+		MinDays:    rental.MinDays,
+		MaxDays:    rental.MaxDays,
+		BufferDays: rental.BufferDays,
+		UpdatedAt:  rental.UpdatedAt,
+	}
+	if len(rental.SKUUnits) > 0 {
+		dbRental.SKUUnits = database.MarshalJSON(rental.SKUUnits)
+	}
+	return dbRental
+}
+
+// bookingToDomain reads booking dates back as UTC midnights, the form rental periods use
+func (r *RentalRepository) bookingToDomain(dbBooking *database.RentalBooking) *services.RentalBooking {
+	return &services.RentalBooking{
+		ID:          dbBooking.ID,
+		ProductID:   dbBooking.ProductID,
+		SKU:         dbBooking.SKU,
+		OrderID:     dbBooking.OrderID,
+		OrderItemID: dbBooking.OrderItemID,
+		Quantity:    dbBooking.Quantity,
+		Start:       dateOnly(dbBooking.StartDate),
+		End:         dateOnly(dbBooking.EndDate),
+		CreatedAt:   dbBooking.CreatedAt,
+	}
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
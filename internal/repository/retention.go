@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// RetentionRepository implements services.RetentionRepository using GORM. Columns are
+// cleared with UpdateColumn so updated_at still shows the last real change.
+type RetentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a new RetentionRepository
+func NewRetentionRepository(db *gorm.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// AnonymizeGuestCarts clears the session ID of guest carts not changed since before
+func (r *RetentionRepository) AnonymizeGuestCarts(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&database.Cart{}).
+		Where("(user_id IS NULL OR user_id = '') AND session_id <> '' AND updated_at < ?", before)
+	return r.apply(query, dryRun, "session_id", "")
+}
+
+// PurgeWebhookPayloads replaces the payload of settled webhooks received before before
+// with JSON null. The rows stay so redeliveries are still recognized.
+func (r *RetentionRepository) PurgeWebhookPayloads(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&database.WebhookEvent{}).
+		Where("status <> ? AND received_at < ? AND payload <> 'null'", string(services.WebhookEventStatusPending), before)
+	return r.apply(query, dryRun, "payload", "null")
+}
+
+// RedactIPAddresses clears the IP address of orders placed, archived or not, and login
+// devices last seen, before before
+func (r *RetentionRepository) RedactIPAddresses(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		orders, err := r.apply(tx.Model(&database.Order{}).Where("ip_address <> '' AND created_at < ?", before), dryRun, "ip_address", "")
+		if err != nil {
+			return err
+		}
+		archived, err := r.apply(tx.Model(&database.ArchivedOrder{}).Where("ip_address <> '' AND created_at < ?", before), dryRun, "ip_address", "")
+		if err != nil {
+			return err
+		}
+		devices, err := r.apply(tx.Model(&database.LoginDevice{}).Where("ip_address <> '' AND last_seen_at < ?", before), dryRun, "ip_address", "")
+		if err != nil {
+			return err
+		}
+		affected = orders + archived + devices
+		return nil
+	})
+	return affected, err
+}
+
+// Helper methods
+
+func (r *RetentionRepository) apply(query *gorm.DB, dryRun bool, column string, value interface{}) (int64, error) {
+	if dryRun {
+		var count int64
+		err := query.Count(&count).Error
+		return count, err
+	}
+	result := query.UpdateColumn(column, value)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ReviewRepository implements services.ReviewRepository using GORM
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new ReviewRepository
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// FindByID finds a review by ID with its photos
+func (r *ReviewRepository) FindByID(ctx context.Context, id string) (*services.Review, error) {
+	var dbReview database.ProductReview
+	if err := r.db.WithContext(ctx).First(&dbReview, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	review := r.toDomain(&dbReview)
+	if err := r.loadPhotos(ctx, []*services.Review{review}); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// FindByUserAndProduct finds a user's review of a product, or nil when there is none
+func (r *ReviewRepository) FindByUserAndProduct(ctx context.Context, userID, productID string) (*services.Review, error) {
+	var dbReview database.ProductReview
+	err := r.db.WithContext(ctx).First(&dbReview, "user_id = ? AND product_id = ?", userID, productID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomain(&dbReview), nil
+}
+
+// FindByProduct finds a product's reviews in a status with their photos, newest first
+func (r *ReviewRepository) FindByProduct(ctx context.Context, productID string, status services.ReviewStatus) ([]*services.Review, error) {
+	return r.find(ctx, r.db.WithContext(ctx).
+		Where("product_id = ? AND status = ?", productID, string(status)).
+		Order("created_at DESC"))
+}
+
+// FindByUserID finds a user's reviews with their photos, newest first
+func (r *ReviewRepository) FindByUserID(ctx context.Context, userID string) ([]*services.Review, error) {
+	return r.find(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC"))
+}
+
+// FindByStatus finds the reviews in a status, or all reviews when status is empty, with
+// their photos, oldest first
+func (r *ReviewRepository) FindByStatus(ctx context.Context, status services.ReviewStatus) ([]*services.Review, error) {
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	return r.find(ctx, query)
+}
+
+// Save creates or replaces a review
+func (r *ReviewRepository) Save(ctx context.Context, review *services.Review) error {
+	return r.db.WithContext(ctx).Save(&database.ProductReview{
+		ID:             review.ID,
+		ProductID:      review.ProductID,
+		UserID:         review.UserID,
+		Rating:         review.Rating,
+		Title:          review.Title,
+		Body:           review.Body,
+		Status:         string(review.Status),
+		Filtered:       review.Filtered,
+		Hidden:         review.Hidden,
+		ModerationNote: review.ModerationNote,
+		ModeratedBy:    review.ModeratedBy,
+		ModeratedAt:    review.ModeratedAt,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}).Error
+}
+
+// AddPhoto records a photo on a review
+func (r *ReviewRepository) AddPhoto(ctx context.Context, photo *services.ReviewPhoto) error {
+	return r.db.WithContext(ctx).Create(&database.ProductReviewPhoto{
+		ID:        photo.ID,
+		ReviewID:  photo.ReviewID,
+		MediaKey:  photo.Key,
+		URL:       photo.URL,
+		CreatedAt: photo.CreatedAt,
+	}).Error
+}
+
+// DeletePhoto deletes a photo
+func (r *ReviewRepository) DeletePhoto(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&database.ProductReviewPhoto{}, "id = ?", id).Error
+}
+
+// SetHidden hides a review from the product or shows it again
+func (r *ReviewRepository) SetHidden(ctx context.Context, id string, hidden bool) error {
+	return r.db.WithContext(ctx).Model(&database.ProductReview{}).
+		Where("id = ?", id).
+		Update("hidden", hidden).Error
+}
+
+// Helper methods
+
+func (r *ReviewRepository) find(ctx context.Context, query *gorm.DB) ([]*services.Review, error) {
+	var dbReviews []database.ProductReview
+	if err := query.Find(&dbReviews).Error; err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*services.Review, len(dbReviews))
+	for i := range dbReviews {
+		reviews[i] = r.toDomain(&dbReviews[i])
+	}
+	if err := r.loadPhotos(ctx, reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// loadPhotos fills in the reviews' photos, oldest first, in one query
+func (r *ReviewRepository) loadPhotos(ctx context.Context, reviews []*services.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	ids := make([]string, len(reviews))
+	byID := make(map[string]*services.Review, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.ID
+		byID[review.ID] = review
+	}
+
+	var dbPhotos []database.ProductReviewPhoto
+	if err := r.db.WithContext(ctx).
+		Where("review_id IN ?", ids).
+		Order("created_at ASC").
+		Find(&dbPhotos).Error; err != nil {
+		return err
+	}
+	for _, dbPhoto := range dbPhotos {
+		review := byID[dbPhoto.ReviewID]
+		review.Photos = append(review.Photos, services.ReviewPhoto{
+			ID:        dbPhoto.ID,
+			ReviewID:  dbPhoto.ReviewID,
+			URL:       dbPhoto.URL,
+			Key:       dbPhoto.MediaKey,
+			CreatedAt: dbPhoto.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (r *ReviewRepository) toDomain(dbReview *database.ProductReview) *services.Review {
+	return &services.Review{
+		ID:             dbReview.ID,
+		ProductID:      dbReview.ProductID,
+		UserID:         dbReview.UserID,
+		Rating:         dbReview.Rating,
+		Title:          dbReview.Title,
+		Body:           dbReview.Body,
+		Status:         services.ReviewStatus(dbReview.Status),
+		Filtered:       dbReview.Filtered,
+		Hidden:         dbReview.Hidden,
+		ModerationNote: dbReview.ModerationNote,
+		ModeratedBy:    dbReview.ModeratedBy,
+		ModeratedAt:    dbReview.ModeratedAt,
+		Photos:         []services.ReviewPhoto{},
+		CreatedAt:      dbReview.CreatedAt,
+		UpdatedAt:      dbReview.UpdatedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SearchRuleRepository implements services.SearchRuleRepository using GORM
+type SearchRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRuleRepository creates a new SearchRuleRepository
+func NewSearchRuleRepository(db *gorm.DB) *SearchRuleRepository {
+	return &SearchRuleRepository{db: db}
+}
+
+// FindSynonymSet finds a synonym set by ID
+func (r *SearchRuleRepository) FindSynonymSet(ctx context.Context, id string) (*services.SynonymSet, error) {
+	var dbSet database.SynonymSet
+	if err := r.db.WithContext(ctx).First(&dbSet, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrSynonymSetNotFound
+		}
+		return nil, err
+	}
+	return r.toDomainSet(&dbSet)
+}
+
+// FindSynonymSets finds every synonym set, oldest first
+func (r *SearchRuleRepository) FindSynonymSets(ctx context.Context) ([]*services.SynonymSet, error) {
+	var dbSets []database.SynonymSet
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&dbSets).Error; err != nil {
+		return nil, err
+	}
+
+	sets := make([]*services.SynonymSet, len(dbSets))
+	for i := range dbSets {
+		set, err := r.toDomainSet(&dbSets[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	return sets, nil
+}
+
+// SaveSynonymSet creates or replaces a synonym set
+func (r *SearchRuleRepository) SaveSynonymSet(ctx context.Context, set *services.SynonymSet) error {
+	return r.db.WithContext(ctx).Save(&database.SynonymSet{
+		ID:        set.ID,
+		Terms:     database.MarshalJSON(set.Terms),
+		CreatedAt: set.CreatedAt,
+		UpdatedAt: set.UpdatedAt,
+	}).Error
+}
+
+// DeleteSynonymSet deletes a synonym set
+func (r *SearchRuleRepository) DeleteSynonymSet(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.SynonymSet{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrSynonymSetNotFound
+	}
+	return nil
+}
+
+// FindRule finds a search rule by ID
+func (r *SearchRuleRepository) FindRule(ctx context.Context, id string) (*services.SearchRule, error) {
+	var dbRule database.SearchRule
+	if err := r.db.WithContext(ctx).First(&dbRule, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrSearchRuleNotFound
+		}
+		return nil, err
+	}
+	return r.toDomainRule(&dbRule)
+}
+
+// FindRules finds every search rule, by query
+func (r *SearchRuleRepository) FindRules(ctx context.Context) ([]*services.SearchRule, error) {
+	var dbRules []database.SearchRule
+	if err := r.db.WithContext(ctx).Order("query ASC").Find(&dbRules).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*services.SearchRule, len(dbRules))
+	for i := range dbRules {
+		rule, err := r.toDomainRule(&dbRules[i])
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+	return rules, nil
+}
+
+// SaveRule creates or replaces a search rule
+func (r *SearchRuleRepository) SaveRule(ctx context.Context, rule *services.SearchRule) error {
+	return r.db.WithContext(ctx).Save(&database.SearchRule{
+		ID:                rule.ID,
+		Query:             rule.Query,
+		PinnedProductIDs:  database.MarshalJSON(rule.PinnedProductIDs),
+		BoostedProductIDs: database.MarshalJSON(rule.BoostedProductIDs),
+		IsActive:          rule.IsActive,
+		CreatedAt:         rule.CreatedAt,
+		UpdatedAt:         rule.UpdatedAt,
+	}).Error
+}
+
+// DeleteRule deletes a search rule
+func (r *SearchRuleRepository) DeleteRule(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.SearchRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrSearchRuleNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *SearchRuleRepository) toDomainSet(dbSet *database.SynonymSet) (*services.SynonymSet, error) {
+	var terms []string
+	if err := database.UnmarshalJSON(dbSet.Terms, &terms); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal synonym set terms: %w", err)
+	}
+	return &services.SynonymSet{
+		ID:        dbSet.ID,
+		Terms:     terms,
+		CreatedAt: dbSet.CreatedAt,
+		UpdatedAt: dbSet.UpdatedAt,
+	}, nil
+}
+
+func (r *SearchRuleRepository) toDomainRule(dbRule *database.SearchRule) (*services.SearchRule, error) {
+	pinned := []string{}
+	if err := database.UnmarshalJSON(dbRule.PinnedProductIDs, &pinned); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search rule pinned products: %w", err)
+	}
+	boosted := []string{}
+	if err := database.UnmarshalJSON(dbRule.BoostedProductIDs, &boosted); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search rule boosted products: %w", err)
+	}
+	return &services.SearchRule{
+		ID:                dbRule.ID,
+		Query:             dbRule.Query,
+		PinnedProductIDs:  pinned,
+		BoostedProductIDs: boosted,
+		IsActive:          dbRule.IsActive,
+		CreatedAt:         dbRule.CreatedAt,
+		UpdatedAt:         dbRule.UpdatedAt,
+	}, nil
+}
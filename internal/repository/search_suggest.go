@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+// SuggestionRepository implements services.SuggestionRepository using GORM. Matching is on
+// lower(name), which the trigram indexes of migrations 939-941 cover.
+type SuggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionRepository creates a new SuggestionRepository
+func NewSuggestionRepository(db *gorm.DB) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// likeEscaper escapes LIKE wildcards so a typed % or _ matches itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Suggest returns up to limit active records of a type whose name, or a word in it,
+// starts with prefix, exact and leading matches first
+func (r *SuggestionRepository) Suggest(ctx context.Context, suggestionType services.SuggestionType, prefix string, limit int) ([]*services.Suggestion, error) {
+	var query *gorm.DB
+	switch suggestionType {
+	case services.SuggestProduct:
+		query = r.db.WithContext(ctx).Model(&database.Product{}).
+			Select("id, name, '' AS slug").
+			Where("status = ?", string(catalog.ProductStatusActive))
+	case services.SuggestCategory:
+		query = r.db.WithContext(ctx).Model(&database.Category{}).
+			Select("id, name, slug").
+			Where("is_active = ?", true)
+	case services.SuggestBrand:
+		query = r.db.WithContext(ctx).Model(&database.Brand{}).
+			Select("id, name, slug").
+			Where("is_active = ?", true)
+	default:
+		return nil, fmt.Errorf("unknown suggestion type %q", suggestionType)
+	}
+
+	escaped := likeEscaper.Replace(prefix)
+	var rows []struct {
+		ID   string
+		Name string
+		Slug string
+	}
+	if err := query.
+		Where("lower(name) LIKE ? OR lower(name) LIKE ?", escaped+"%", "% "+escaped+"%").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN lower(name) = ? THEN 0 WHEN lower(name) LIKE ? THEN 1 ELSE 2 END, length(name), name",
+			Vars:               []interface{}{prefix, escaped + "%"},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*services.Suggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = &services.Suggestion{Type: suggestionType, ID: row.ID, Text: row.Name, Slug: row.Slug}
+	}
+	return suggestions, nil
+}
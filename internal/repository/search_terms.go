@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+// SearchTermRepository implements services.SearchTermRepository using GORM and pg_trgm
+type SearchTermRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchTermRepository creates a new SearchTermRepository
+func NewSearchTermRepository(db *gorm.DB) *SearchTermRepository {
+	return &SearchTermRepository{db: db}
+}
+
+// searchTermsQuery selects the distinct words of at least 3 letters or digits in active
+// product, category and brand names
+const searchTermsQuery = `
+	SELECT DISTINCT word FROM (
+		SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') AS word FROM products WHERE status = 'active'
+		UNION ALL
+		SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') FROM categories WHERE is_active
+		UNION ALL
+		SELECT regexp_split_to_table(lower(name), '[^[:alnum:]]+') FROM brands WHERE is_active
+	) words
+	WHERE length(word) BETWEEN 3 AND 100`
+
+// Closest returns the known term most similar to word by trigram similarity, using the
+// pg_trgm % operator and its similarity threshold (0.3 by default)
+func (r *SearchTermRepository) Closest(ctx context.Context, word string) (string, error) {
+	var terms []string
+	if err := r.db.WithContext(ctx).
+		Model(&database.SearchTerm{}).
+		Where("term % ?", word).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "similarity(term, ?) DESC, length(term)",
+			Vars:               []interface{}{word},
+			WithoutParentheses: true,
+		}}).
+		Limit(1).
+		Pluck("term", &terms).Error; err != nil {
+		return "", err
+	}
+	if len(terms) == 0 {
+		return "", nil
+	}
+	return terms[0], nil
+}
+
+// Rebuild replaces the terms with the words of the current names in one transaction
+func (r *SearchTermRepository) Rebuild(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM search_terms`).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(`INSERT INTO search_terms (term) ` + searchTermsQuery)
+		count = result.RowsAffected
+		return result.Error
+	})
+	return count, err
+}
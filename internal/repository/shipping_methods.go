@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ShippingMethodRepository implements services.ShippingMethodRepository using GORM
+type ShippingMethodRepository struct {
+	db *gorm.DB
+}
+
+// NewShippingMethodRepository creates a new ShippingMethodRepository
+func NewShippingMethodRepository(db *gorm.DB) *ShippingMethodRepository {
+	return &ShippingMethodRepository{db: db}
+}
+
+// shippingRateTierRow is a rate tier as stored in the rate_tiers JSON column, in cents of
+// the method's currency
+type shippingRateTierRow struct {
+	MinSubtotal int64 `json:"min_subtotal"`
+	Cost        int64 `json:"cost"`
+}
+
+// FindByID finds a shipping method by ID
+func (r *ShippingMethodRepository) FindByID(ctx context.Context, id string) (*services.ShippingMethod, error) {
+	var dbMethod database.ShippingMethod
+	if err := r.db.WithContext(ctx).First(&dbMethod, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrShippingMethodNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbMethod)
+}
+
+// FindAll finds all shipping methods in sort order
+func (r *ShippingMethodRepository) FindAll(ctx context.Context) ([]*services.ShippingMethod, error) {
+	return r.find(r.db.WithContext(ctx))
+}
+
+// FindActive finds the active shipping methods in sort order
+func (r *ShippingMethodRepository) FindActive(ctx context.Context) ([]*services.ShippingMethod, error) {
+	return r.find(r.db.WithContext(ctx).Where("is_active = ?", true))
+}
+
+// Save creates or replaces a shipping method
+func (r *ShippingMethodRepository) Save(ctx context.Context, method *services.ShippingMethod) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(method)).Error
+}
+
+// Delete deletes a shipping method
+func (r *ShippingMethodRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.ShippingMethod{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrShippingMethodNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *ShippingMethodRepository) find(query *gorm.DB) ([]*services.ShippingMethod, error) {
+	var dbMethods []database.ShippingMethod
+	if err := query.Order("sort_order ASC, name ASC").Find(&dbMethods).Error; err != nil {
+		return nil, err
+	}
+
+	methods := make([]*services.ShippingMethod, len(dbMethods))
+	for i := range dbMethods {
+		method, err := r.toDomain(&dbMethods[i])
+		if err != nil {
+			return nil, err
+		}
+		methods[i] = method
+	}
+	return methods, nil
+}
+
+func (r *ShippingMethodRepository) toDomain(dbMethod *database.ShippingMethod) (*services.ShippingMethod, error) {
+	var countries []string
+	if err := database.UnmarshalJSON(dbMethod.Countries, &countries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping method countries: %w", err)
+	}
+	var rows []shippingRateTierRow
+	if err := database.UnmarshalJSON(dbMethod.RateTiers, &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping rate tiers: %w", err)
+	}
+
+	method := &services.ShippingMethod{
+		ID:               dbMethod.ID,
+		Name:             dbMethod.Name,
+		Description:      dbMethod.Description,
+		Carrier:          dbMethod.Carrier,
+		ServiceLevel:     dbMethod.ServiceLevel,
+		Air:              dbMethod.Air,
+		Countries:        countries,
+		FlatRate:         database.Int64ToMoney(dbMethod.FlatRate, dbMethod.Currency),
+		EstimatedDaysMin: dbMethod.EstimatedDaysMin,
+		EstimatedDaysMax: dbMethod.EstimatedDaysMax,
+		SortOrder:        dbMethod.SortOrder,
+		IsActive:         dbMethod.IsActive,
+		CreatedAt:        dbMethod.CreatedAt,
+		UpdatedAt:        dbMethod.UpdatedAt,
+	}
+	for _, row := range rows {
+		method.RateTiers = append(method.RateTiers, services.ShippingRateTier{
+			MinSubtotal: database.Int64ToMoney(row.MinSubtotal, dbMethod.Currency),
+			Cost:        database.Int64ToMoney(row.Cost, dbMethod.Currency),
+		})
+	}
+	if dbMethod.FreeShippingMin != nil {
+		minimum := database.Int64ToMoney(*dbMethod.FreeShippingMin, dbMethod.Currency)
+		method.FreeShippingMin = &minimum
+	}
+	return method, nil
+}
+
+func (r *ShippingMethodRepository) toDatabase(method *services.ShippingMethod) *database.ShippingMethod {
+	rows := make([]shippingRateTierRow, len(method.RateTiers))
+	for i, tier := range method.RateTiers {
+		rows[i] = shippingRateTierRow{MinSubtotal: tier.MinSubtotal.Amount, Cost: tier.Cost.Amount}
+	}
+
+	dbMethod := &database.ShippingMethod{
+		ID:               method.ID,
+		Name:             method.Name,
+		Description:      method.Description,
+		Carrier:          method.Carrier,
+		ServiceLevel:     method.ServiceLevel,
+		Air:              method.Air,
+		Countries:        database.MarshalJSON(method.Countries),
+		Currency:         method.FlatRate.Currency,
+		FlatRate:         database.MoneyToInt64(method.FlatRate),
+		RateTiers:        database.MarshalJSON(rows),
+		EstimatedDaysMin: method.EstimatedDaysMin,
+		EstimatedDaysMax: method.EstimatedDaysMax,
+		SortOrder:        method.SortOrder,
+		IsActive:         method.IsActive,
+		CreatedAt:        method.CreatedAt,
+		UpdatedAt:        method.UpdatedAt,
+	}
+	if method.FreeShippingMin != nil {
+		minimum := database.MoneyToInt64(*method.FreeShippingMin)
+		dbMethod.FreeShippingMin = &minimum
+	}
+	return dbMethod
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ShippingRestrictionRepository implements services.ShippingRestrictionRepository using GORM
+type ShippingRestrictionRepository struct {
+	db *gorm.DB
+}
+
+// NewShippingRestrictionRepository creates a new ShippingRestrictionRepository
+func NewShippingRestrictionRepository(db *gorm.DB) *ShippingRestrictionRepository {
+	return &ShippingRestrictionRepository{db: db}
+}
+
+// FindByProductID finds a product's shipping restriction
+func (r *ShippingRestrictionRepository) FindByProductID(ctx context.Context, productID string) (*services.ShippingRestriction, error) {
+	var dbRestriction database.ShippingRestriction
+	if err := r.db.WithContext(ctx).First(&dbRestriction, "product_id = ?", productID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrShippingRestrictionNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbRestriction)
+}
+
+// FindByProductIDs finds the shipping restrictions of the given products; products without one are skipped
+func (r *ShippingRestrictionRepository) FindByProductIDs(ctx context.Context, productIDs []string) ([]*services.ShippingRestriction, error) {
+	var dbRestrictions []database.ShippingRestriction
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Order("product_id ASC").Find(&dbRestrictions).Error; err != nil {
+		return nil, err
+	}
+
+	restrictions := make([]*services.ShippingRestriction, len(dbRestrictions))
+	for i := range dbRestrictions {
+		restriction, err := r.toDomain(&dbRestrictions[i])
+		if err != nil {
+			return nil, err
+		}
+		restrictions[i] = restriction
+	}
+	return restrictions, nil
+}
+
+// Save creates or replaces a product's shipping restriction
+func (r *ShippingRestrictionRepository) Save(ctx context.Context, restriction *services.ShippingRestriction) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(restriction)).Error
+}
+
+// Delete deletes a product's shipping restriction
+func (r *ShippingRestrictionRepository) Delete(ctx context.Context, productID string) error {
+	result := r.db.WithContext(ctx).Delete(&database.ShippingRestriction{}, "product_id = ?", productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrShippingRestrictionNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+func (r *ShippingRestrictionRepository) toDomain(dbRestriction *database.ShippingRestriction) (*services.ShippingRestriction, error) {
+	var carriers []string
+	if err := database.UnmarshalJSON(dbRestriction.Carriers, &carriers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping restriction carriers: %w", err)
+	}
+
+	return &services.ShippingRestriction{
+		ProductID: dbRestriction.ProductID,
+		NoAir:     dbRestriction.NoAir,
+		Carriers:  carriers,
+		NoPOBox:   dbRestriction.NoPOBox,
+		MinAge:    dbRestriction.MinAge,
+		UpdatedAt: dbRestriction.UpdatedAt,
+	}, nil
+}
+
+func (r *ShippingRestrictionRepository) toDatabase(restriction *services.ShippingRestriction) *database.ShippingRestriction {
+	dbRestriction := &database.ShippingRestriction{
+		ProductID: restriction.ProductID,
+		NoAir:     restriction.NoAir,
+		NoPOBox:   restriction.NoPOBox,
+		MinAge:    restriction.MinAge,
+		UpdatedAt: restriction.UpdatedAt,
+	}
+	if len(restriction.Carriers) > 0 {
+		dbRestriction.Carriers = database.MarshalJSON(restriction.Carriers)
+	}
+	return dbRestriction
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// ShippingZoneRepository implements services.ShippingZoneRepository using GORM
+type ShippingZoneRepository struct {
+	db *gorm.DB
+}
+
+// NewShippingZoneRepository creates a new ShippingZoneRepository
+func NewShippingZoneRepository(db *gorm.DB) *ShippingZoneRepository {
+	return &ShippingZoneRepository{db: db}
+}
+
+// FindByID finds a shipping zone by ID with its rates
+func (r *ShippingZoneRepository) FindByID(ctx context.Context, id string) (*services.ShippingZone, error) {
+	var dbZone database.ShippingZone
+	if err := r.db.WithContext(ctx).First(&dbZone, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrShippingZoneNotFound
+		}
+		return nil, err
+	}
+
+	zones, err := r.withRates(ctx, []database.ShippingZone{dbZone})
+	if err != nil {
+		return nil, err
+	}
+	return zones[0], nil
+}
+
+// FindAll finds all shipping zones
+func (r *ShippingZoneRepository) FindAll(ctx context.Context) ([]*services.ShippingZone, error) {
+	var dbZones []database.ShippingZone
+	if err := r.db.WithContext(ctx).Order("country ASC, name ASC").Find(&dbZones).Error; err != nil {
+		return nil, err
+	}
+	return r.withRates(ctx, dbZones)
+}
+
+// FindActive finds all active shipping zones
+func (r *ShippingZoneRepository) FindActive(ctx context.Context) ([]*services.ShippingZone, error) {
+	var dbZones []database.ShippingZone
+	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&dbZones).Error; err != nil {
+		return nil, err
+	}
+	return r.withRates(ctx, dbZones)
+}
+
+// Save saves a shipping zone and replaces its rates in a single transaction
+func (r *ShippingZoneRepository) Save(ctx context.Context, zone *services.ShippingZone) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(r.toDatabase(zone)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("zone_id = ?", zone.ID).Delete(&database.ShippingZoneRate{}).Error; err != nil {
+			return err
+		}
+
+		for _, rate := range zone.Rates {
+			if err := tx.Create(r.toDatabaseRate(zone.ID, rate)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete deletes a shipping zone (rates cascade)
+func (r *ShippingZoneRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.ShippingZone{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrShippingZoneNotFound
+	}
+	return nil
+}
+
+// Helper methods
+
+// withRates loads rates for the zones with a single query
+func (r *ShippingZoneRepository) withRates(ctx context.Context, dbZones []database.ShippingZone) ([]*services.ShippingZone, error) {
+	zones := make([]*services.ShippingZone, len(dbZones))
+	if len(dbZones) == 0 {
+		return zones, nil
+	}
+
+	zoneIDs := make([]string, len(dbZones))
+	for i, dbZone := range dbZones {
+		zoneIDs[i] = dbZone.ID
+	}
+
+	var dbRates []database.ShippingZoneRate
+	if err := r.db.WithContext(ctx).
+		Where("zone_id IN ?", zoneIDs).
+		Order("cost_amount ASC").
+		Find(&dbRates).Error; err != nil {
+		return nil, err
+	}
+
+	ratesByZone := make(map[string][]services.ShippingZoneRate)
+	for _, dbRate := range dbRates {
+		ratesByZone[dbRate.ZoneID] = append(ratesByZone[dbRate.ZoneID], r.toDomainRate(&dbRate))
+	}
+
+	for i, dbZone := range dbZones {
+		zone, err := r.toDomain(&dbZone)
+		if err != nil {
+			return nil, err
+		}
+		zone.Rates = ratesByZone[dbZone.ID]
+		zones[i] = zone
+	}
+	return zones, nil
+}
+
+func (r *ShippingZoneRepository) toDomain(dbZone *database.ShippingZone) (*services.ShippingZone, error) {
+	var patterns []string
+	if err := database.UnmarshalJSON(dbZone.PostalCodePatterns, &patterns); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal postal code patterns: %w", err)
+	}
+
+	return &services.ShippingZone{
+		ID:                 dbZone.ID,
+		Name:               dbZone.Name,
+		Country:            dbZone.Country,
+		State:              dbZone.State,
+		PostalCodePatterns: patterns,
+		Priority:           dbZone.Priority,
+		IsActive:           dbZone.IsActive,
+		CreatedAt:          dbZone.CreatedAt,
+		UpdatedAt:          dbZone.UpdatedAt,
+	}, nil
+}
+
+func (r *ShippingZoneRepository) toDatabase(zone *services.ShippingZone) *database.ShippingZone {
+	return &database.ShippingZone{
+		ID:                 zone.ID,
+		Name:               zone.Name,
+		Country:            zone.Country,
+		State:              zone.State,
+		PostalCodePatterns: database.MarshalJSON(zone.PostalCodePatterns),
+		Priority:           zone.Priority,
+		IsActive:           zone.IsActive,
+		CreatedAt:          zone.CreatedAt,
+		UpdatedAt:          zone.UpdatedAt,
+	}
+}
+
+func (r *ShippingZoneRepository) toDomainRate(dbRate *database.ShippingZoneRate) services.ShippingZoneRate {
+	return services.ShippingZoneRate{
+		ID:               dbRate.ID,
+		MethodID:         dbRate.MethodID,
+		MethodName:       dbRate.MethodName,
+		Carrier:          dbRate.Carrier,
+		ServiceLevel:     dbRate.ServiceLevel,
+		Air:              dbRate.Air,
+		Cost:             database.Int64ToMoney(dbRate.CostAmount, dbRate.CostCurrency),
+		EstimatedDaysMin: dbRate.EstimatedDaysMin,
+		EstimatedDaysMax: dbRate.EstimatedDaysMax,
+	}
+}
+
+func (r *ShippingZoneRepository) toDatabaseRate(zoneID string, rate services.ShippingZoneRate) *database.ShippingZoneRate {
+	return &database.ShippingZoneRate{
+		ID:               rate.ID,
+		ZoneID:           zoneID,
+		MethodID:         rate.MethodID,
+		MethodName:       rate.MethodName,
+		Carrier:          rate.Carrier,
+		ServiceLevel:     rate.ServiceLevel,
+		Air:              rate.Air,
+		CostAmount:       database.MoneyToInt64(rate.Cost),
+		CostCurrency:     rate.Cost.Currency,
+		EstimatedDaysMin: rate.EstimatedDaysMin,
+		EstimatedDaysMax: rate.EstimatedDaysMax,
+	}
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// applySort orders a query by the requested sort, then by fallback, which is the listing's
+// default order and breaks ties. Fields go through columns, the listing's own map of sort
+// fields to columns, and fields it doesn't have are skipped, so request input never
+// reaches the ORDER BY clause.
+func applySort(query *gorm.DB, sort services.Sort, columns map[string]string, fallback string) *gorm.DB {
+	for _, field := range sort {
+		column, ok := columns[field.Field]
+		if !ok {
+			continue
+		}
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: field.Desc})
+	}
+	return query.Order(fallback)
+}
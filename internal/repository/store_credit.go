@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+// StoreCreditRepository implements services.StoreCreditRepository using GORM
+type StoreCreditRepository struct {
+	db     *gorm.DB
+	cipher *fieldcrypt.Cipher
+}
+
+// NewStoreCreditRepository creates a new StoreCreditRepository
+func NewStoreCreditRepository(db *gorm.DB) *StoreCreditRepository {
+	return &StoreCreditRepository{db: db}
+}
+
+// WithCipher encrypts ledger notes at rest
+func (r *StoreCreditRepository) WithCipher(cipher *fieldcrypt.Cipher) *StoreCreditRepository {
+	r.cipher = cipher
+	return r
+}
+
+// FindBalances finds a customer's balances in every currency
+func (r *StoreCreditRepository) FindBalances(ctx context.Context, userID string) ([]*services.StoreCreditBalance, error) {
+	var dbBalances []database.StoreCreditBalance
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("currency ASC").
+		Find(&dbBalances).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make([]*services.StoreCreditBalance, len(dbBalances))
+	for i, dbBalance := range dbBalances {
+		balances[i] = r.balanceToDomain(&dbBalance)
+	}
+	return balances, nil
+}
+
+// FindBalance finds a customer's balance in one currency, or a zero balance if they have none
+func (r *StoreCreditRepository) FindBalance(ctx context.Context, userID, currency string) (*services.StoreCreditBalance, error) {
+	var dbBalance database.StoreCreditBalance
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND currency = ?", userID, currency).
+		First(&dbBalance).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &services.StoreCreditBalance{UserID: userID, Balance: money.Zero(currency)}, nil
+		}
+		return nil, err
+	}
+
+	return r.balanceToDomain(&dbBalance), nil
+}
+
+// Apply adjusts the balance and appends the ledger entry in a single transaction.
+// The conditional update keeps concurrent redemptions from overdrawing the wallet.
+func (r *StoreCreditRepository) Apply(ctx context.Context, txn *services.StoreCreditTransaction) error {
+	currency := txn.Amount.Currency
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&database.StoreCreditBalance{
+			UserID:    txn.UserID,
+			Currency:  currency,
+			UpdatedAt: time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&database.StoreCreditBalance{}).
+			Where("user_id = ? AND currency = ? AND balance + ? >= 0", txn.UserID, currency, txn.Amount.Amount).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", txn.Amount.Amount),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrInsufficientStoreCredit
+		}
+
+		var dbBalance database.StoreCreditBalance
+		if err := tx.Where("user_id = ? AND currency = ?", txn.UserID, currency).First(&dbBalance).Error; err != nil {
+			return err
+		}
+		txn.BalanceAfter = database.Int64ToMoney(dbBalance.Balance, currency)
+
+		dbTxn, err := r.transactionToDatabase(txn)
+		if err != nil {
+			return err
+		}
+		return tx.Create(dbTxn).Error
+	})
+}
+
+// ListTransactions lists a customer's ledger entries, newest first
+func (r *StoreCreditRepository) ListTransactions(ctx context.Context, filter services.StoreCreditFilter) ([]*services.StoreCreditTransaction, error) {
+	query := r.db.WithContext(ctx).
+		Where("user_id = ?", filter.UserID).
+		Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbTxns []database.StoreCreditTransaction
+	if err := query.Find(&dbTxns).Error; err != nil {
+		return nil, err
+	}
+
+	txns := make([]*services.StoreCreditTransaction, len(dbTxns))
+	for i, dbTxn := range dbTxns {
+		txn, err := r.transactionToDomain(&dbTxn)
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = txn
+	}
+	return txns, nil
+}
+
+// CountTransactions counts a customer's ledger entries
+func (r *StoreCreditRepository) CountTransactions(ctx context.Context, filter services.StoreCreditFilter) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&database.StoreCreditTransaction{}).
+		Where("user_id = ?", filter.UserID).
+		Count(&count).Error
+	return count, err
+}
+
+// Helper methods
+
+func (r *StoreCreditRepository) balanceToDomain(dbBalance *database.StoreCreditBalance) *services.StoreCreditBalance {
+	return &services.StoreCreditBalance{
+		UserID:    dbBalance.UserID,
+		Balance:   database.Int64ToMoney(dbBalance.Balance, dbBalance.Currency),
+		UpdatedAt: dbBalance.UpdatedAt,
+	}
+}
+
+func (r *StoreCreditRepository) transactionToDomain(dbTxn *database.StoreCreditTransaction) (*services.StoreCreditTransaction, error) {
+	note, err := r.cipher.Decrypt(dbTxn.Note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt store credit note: %w", err)
+	}
+
+	return &services.StoreCreditTransaction{
+		ID:           dbTxn.ID,
+		UserID:       dbTxn.UserID,
+		Type:         services.StoreCreditTransactionType(dbTxn.Type),
+		Amount:       database.Int64ToMoney(dbTxn.Amount, dbTxn.Currency),
+		BalanceAfter: database.Int64ToMoney(dbTxn.BalanceAfter, dbTxn.Currency),
+		Reason:       services.StoreCreditGrantReason(dbTxn.Reason),
+		Note:         note,
+		OrderID:      dbTxn.OrderID,
+		CreatedBy:    dbTxn.CreatedBy,
+		CreatedAt:    dbTxn.CreatedAt,
+	}, nil
+}
+
+func (r *StoreCreditRepository) transactionToDatabase(txn *services.StoreCreditTransaction) (*database.StoreCreditTransaction, error) {
+	note, err := r.cipher.Encrypt(txn.Note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt store credit note: %w", err)
+	}
+
+	return &database.StoreCreditTransaction{
+		ID:           txn.ID,
+		UserID:       txn.UserID,
+		Type:         string(txn.Type),
+		Amount:       database.MoneyToInt64(txn.Amount),
+		Currency:     txn.Amount.Currency,
+		BalanceAfter: database.MoneyToInt64(txn.BalanceAfter),
+		Reason:       string(txn.Reason),
+		Note:         note,
+		OrderID:      txn.OrderID,
+		CreatedBy:    txn.CreatedBy,
+		CreatedAt:    txn.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SupportTicketRepository implements services.SupportTicketRepository using GORM
+type SupportTicketRepository struct {
+	db *gorm.DB
+}
+
+// NewSupportTicketRepository creates a new SupportTicketRepository
+func NewSupportTicketRepository(db *gorm.DB) *SupportTicketRepository {
+	return &SupportTicketRepository{db: db}
+}
+
+// FindByID finds a ticket by ID with its messages
+func (r *SupportTicketRepository) FindByID(ctx context.Context, id string) (*services.SupportTicket, error) {
+	var dbTicket database.SupportTicket
+	if err := r.db.WithContext(ctx).First(&dbTicket, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrTicketNotFound
+		}
+		return nil, err
+	}
+
+	ticket := r.toDomain(&dbTicket)
+	if err := r.loadMessages(ctx, []*services.SupportTicket{ticket}); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// FindByOrderID finds an order's tickets with their messages, oldest first
+func (r *SupportTicketRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.SupportTicket, error) {
+	tickets, err := r.find(ctx, r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at ASC"))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadMessages(ctx, tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// FindByUserID finds a user's tickets, most recently updated first
+func (r *SupportTicketRepository) FindByUserID(ctx context.Context, userID string) ([]*services.SupportTicket, error) {
+	return r.find(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID).Order("updated_at DESC"))
+}
+
+// FindByStatus finds the tickets in a status, or all tickets when status is empty, least
+// recently updated first
+func (r *SupportTicketRepository) FindByStatus(ctx context.Context, status services.TicketStatus) ([]*services.SupportTicket, error) {
+	query := r.db.WithContext(ctx).Order("updated_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	return r.find(ctx, query)
+}
+
+// Save creates or replaces a ticket
+func (r *SupportTicketRepository) Save(ctx context.Context, ticket *services.SupportTicket) error {
+	return r.db.WithContext(ctx).Save(&database.SupportTicket{
+		ID:        ticket.ID,
+		OrderID:   ticket.OrderID,
+		UserID:    ticket.UserID,
+		Subject:   ticket.Subject,
+		Category:  string(ticket.Category),
+		Status:    string(ticket.Status),
+		CreatedAt: ticket.CreatedAt,
+		UpdatedAt: ticket.UpdatedAt,
+		ClosedAt:  ticket.ClosedAt,
+	}).Error
+}
+
+// AddMessage records a message on a ticket
+func (r *SupportTicketRepository) AddMessage(ctx context.Context, message *services.SupportTicketMessage) error {
+	return r.db.WithContext(ctx).Create(&database.SupportTicketMessage{
+		ID:        message.ID,
+		TicketID:  message.TicketID,
+		AuthorID:  message.AuthorID,
+		Staff:     message.Staff,
+		Body:      message.Body,
+		CreatedAt: message.CreatedAt,
+	}).Error
+}
+
+// Helper methods
+
+func (r *SupportTicketRepository) find(ctx context.Context, query *gorm.DB) ([]*services.SupportTicket, error) {
+	var dbTickets []database.SupportTicket
+	if err := query.Find(&dbTickets).Error; err != nil {
+		return nil, err
+	}
+
+	tickets := make([]*services.SupportTicket, len(dbTickets))
+	for i := range dbTickets {
+		tickets[i] = r.toDomain(&dbTickets[i])
+	}
+	return tickets, nil
+}
+
+// loadMessages fills in the tickets' messages, oldest first, in one query
+func (r *SupportTicketRepository) loadMessages(ctx context.Context, tickets []*services.SupportTicket) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	ids := make([]string, len(tickets))
+	byID := make(map[string]*services.SupportTicket, len(tickets))
+	for i, ticket := range tickets {
+		ids[i] = ticket.ID
+		byID[ticket.ID] = ticket
+	}
+
+	var dbMessages []database.SupportTicketMessage
+	if err := r.db.WithContext(ctx).
+		Where("ticket_id IN ?", ids).
+		Order("created_at ASC").
+		Find(&dbMessages).Error; err != nil {
+		return err
+	}
+	for _, dbMessage := range dbMessages {
+		ticket := byID[dbMessage.TicketID]
+		ticket.Messages = append(ticket.Messages, services.SupportTicketMessage{
+			ID:        dbMessage.ID,
+			TicketID:  dbMessage.TicketID,
+			AuthorID:  dbMessage.AuthorID,
+			Staff:     dbMessage.Staff,
+			Body:      dbMessage.Body,
+			CreatedAt: dbMessage.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (r *SupportTicketRepository) toDomain(dbTicket *database.SupportTicket) *services.SupportTicket {
+	return &services.SupportTicket{
+		ID:        dbTicket.ID,
+		OrderID:   dbTicket.OrderID,
+		UserID:    dbTicket.UserID,
+		Subject:   dbTicket.Subject,
+		Category:  services.TicketCategory(dbTicket.Category),
+		Status:    services.TicketStatus(dbTicket.Status),
+		Messages:  []services.SupportTicketMessage{},
+		CreatedAt: dbTicket.CreatedAt,
+		UpdatedAt: dbTicket.UpdatedAt,
+		ClosedAt:  dbTicket.ClosedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/migrations"
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// SystemStatusRepository implements services.SystemStatusRepository using GORM and the
+// statement latency tracked by the database
+type SystemStatusRepository struct {
+	db      *gorm.DB
+	latency *database.QueryLatency
+}
+
+// NewSystemStatusRepository creates a new SystemStatusRepository
+func NewSystemStatusRepository(db *gorm.DB, latency *database.QueryLatency) *SystemStatusRepository {
+	return &SystemStatusRepository{db: db, latency: latency}
+}
+
+// LastMigration finds the migration applied most recently
+func (r *SystemStatusRepository) LastMigration(ctx context.Context) (*services.AppliedMigration, error) {
+	var rows []struct {
+		Version   string
+		Name      string
+		AppliedAt time.Time
+	}
+	err := r.db.WithContext(ctx).Table(migrations.TableName).
+		Select("version, name, applied_at").
+		Order("applied_at DESC, version DESC").
+		Limit(1).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &services.AppliedMigration{Version: rows[0].Version, Name: rows[0].Name, AppliedAt: rows[0].AppliedAt}, nil
+}
+
+// Latency returns percentiles of the most recent statements in milliseconds
+func (r *SystemStatusRepository) Latency() services.DBLatency {
+	p := r.latency.Percentiles()
+	return services.DBLatency{
+		Samples: p.Samples,
+		P50:     milliseconds(p.P50),
+		P95:     milliseconds(p.P95),
+		P99:     milliseconds(p.P99),
+		Max:     milliseconds(p.Max),
+	}
+}
+
+// Connections returns the connection pool's counters
+func (r *SystemStatusRepository) Connections() services.DBConnections {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return services.DBConnections{}
+	}
+	stats := sqlDB.Stats()
+	return services.DBConnections{
+		Open:      stats.OpenConnections,
+		InUse:     stats.InUse,
+		Idle:      stats.Idle,
+		MaxOpen:   stats.MaxOpenConnections,
+		WaitCount: stats.WaitCount,
+	}
+}
+
+// Helper methods
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// UnitPriceRepository implements services.UnitPriceRepository using GORM
+type UnitPriceRepository struct {
+	db *gorm.DB
+}
+
+// NewUnitPriceRepository creates a new UnitPriceRepository
+func NewUnitPriceRepository(db *gorm.DB) *UnitPriceRepository {
+	return &UnitPriceRepository{db: db}
+}
+
+// FindUnit finds a variant's unit
+func (r *UnitPriceRepository) FindUnit(ctx context.Context, variantID string) (*services.VariantUnit, error) {
+	var dbVariant database.Variant
+	err := r.db.WithContext(ctx).Select("id", "unit_quantity", "unit_measure").First(&dbVariant, "id = ?", variantID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, services.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomain(&dbVariant), nil
+}
+
+// FindUnitsByProduct finds the units of a product's variants that have one
+func (r *UnitPriceRepository) FindUnitsByProduct(ctx context.Context, productID string) (map[string]*services.VariantUnit, error) {
+	var dbVariants []database.Variant
+	if err := r.db.WithContext(ctx).Select("id", "unit_quantity", "unit_measure").
+		Where("product_id = ? AND unit_quantity IS NOT NULL", productID).
+		Find(&dbVariants).Error; err != nil {
+		return nil, err
+	}
+
+	units := make(map[string]*services.VariantUnit, len(dbVariants))
+	for i := range dbVariants {
+		units[dbVariants[i].ID] = r.toDomain(&dbVariants[i])
+	}
+	return units, nil
+}
+
+// SetUnit sets or clears a variant's unit
+func (r *UnitPriceRepository) SetUnit(ctx context.Context, unit *services.VariantUnit) error {
+	var quantity *float64
+	measure := ""
+	if unit.Quantity > 0 {
+		quantity = &unit.Quantity
+		measure = unit.Measure
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.Variant{}).
+		Where("id = ?", unit.VariantID).
+		Updates(map[string]interface{}{"unit_quantity": quantity, "unit_measure": measure, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrVariantNotFound
+	}
+	return nil
+}
+
+func (r *UnitPriceRepository) toDomain(dbVariant *database.Variant) *services.VariantUnit {
+	unit := &services.VariantUnit{VariantID: dbVariant.ID}
+	if dbVariant.UnitQuantity != nil {
+		unit.Quantity = *dbVariant.UnitQuantity
+		unit.Measure = dbVariant.UnitMeasure
+	}
+	return unit
+}
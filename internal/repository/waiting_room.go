@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WaitingRoomRepository implements services.WaitingRoomRepository using GORM
+type WaitingRoomRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitingRoomRepository creates a new WaitingRoomRepository
+func NewWaitingRoomRepository(db *gorm.DB) *WaitingRoomRepository {
+	return &WaitingRoomRepository{db: db}
+}
+
+// FindByUser finds a customer's ticket for a product
+func (r *WaitingRoomRepository) FindByUser(ctx context.Context, productID, userID string) (*services.WaitingRoomTicket, error) {
+	var dbTicket database.WaitingRoomTicket
+	if err := r.db.WithContext(ctx).First(&dbTicket, "product_id = ? AND user_id = ?", productID, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrWaitingRoomTicketNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbTicket), nil
+}
+
+// FindByToken finds the ticket holding a token
+func (r *WaitingRoomRepository) FindByToken(ctx context.Context, token string) (*services.WaitingRoomTicket, error) {
+	var dbTicket database.WaitingRoomTicket
+	if err := r.db.WithContext(ctx).First(&dbTicket, "token = ?", token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrWaitingRoomTicketNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbTicket), nil
+}
+
+// Save creates or replaces a customer's ticket for a product
+func (r *WaitingRoomRepository) Save(ctx context.Context, ticket *services.WaitingRoomTicket) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(ticket)).Error
+}
+
+// CountAhead counts waiting tickets for the same product that joined before this one
+func (r *WaitingRoomRepository) CountAhead(ctx context.Context, ticket *services.WaitingRoomTicket) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&database.WaitingRoomTicket{}).
+		Where("product_id = ? AND status = ? AND joined_at < ?", ticket.ProductID, string(services.WaitingRoomWaiting), ticket.JoinedAt).
+		Count(&count).Error
+	return count, err
+}
+
+// AdmitNext admits the product's n longest-waiting tickets until expiresAt. The scheduler
+// runs it on one replica at a time, so the select and update need no row locks.
+func (r *WaitingRoomRepository) AdmitNext(ctx context.Context, productID string, n int, admittedAt, expiresAt time.Time) (int64, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).Model(&database.WaitingRoomTicket{}).
+		Where("product_id = ? AND status = ?", productID, string(services.WaitingRoomWaiting)).
+		Order("joined_at ASC").
+		Limit(n).
+		Pluck("user_id", &userIDs).Error
+	if err != nil || len(userIDs) == 0 {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.WaitingRoomTicket{}).
+		Where("product_id = ? AND user_id IN ? AND status = ?", productID, userIDs, string(services.WaitingRoomWaiting)).
+		Updates(map[string]interface{}{
+			"status":      string(services.WaitingRoomAdmitted),
+			"admitted_at": admittedAt,
+			"expires_at":  expiresAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ExpireAdmitted marks admitted tickets past their expiry as expired
+func (r *WaitingRoomRepository) ExpireAdmitted(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&database.WaitingRoomTicket{}).
+		Where("status = ? AND expires_at <= ?", string(services.WaitingRoomAdmitted), now).
+		Update("status", string(services.WaitingRoomExpired))
+	return result.RowsAffected, result.Error
+}
+
+// Helper methods
+
+func (r *WaitingRoomRepository) toDomain(dbTicket *database.WaitingRoomTicket) *services.WaitingRoomTicket {
+	return &services.WaitingRoomTicket{
+		Token:      dbTicket.Token,
+		ProductID:  dbTicket.ProductID,
+		UserID:     dbTicket.UserID,
+		Status:     services.WaitingRoomStatus(dbTicket.Status),
+		JoinedAt:   dbTicket.JoinedAt,
+		AdmittedAt: dbTicket.AdmittedAt,
+		ExpiresAt:  dbTicket.ExpiresAt,
+	}
+}
+
+func (r *WaitingRoomRepository) toDatabase(ticket *services.WaitingRoomTicket) *database.WaitingRoomTicket {
+	return &database.WaitingRoomTicket{
+		ProductID:  ticket.ProductID,
+		UserID:     ticket.UserID,
+		Token:      ticket.Token,
+		Status:     string(ticket.Status),
+		JoinedAt:   ticket.JoinedAt,
+		AdmittedAt: ticket.AdmittedAt,
+		ExpiresAt:  ticket.ExpiresAt,
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WebhookEventRepository implements services.WebhookEventRepository using GORM
+type WebhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEventRepository creates a new WebhookEventRepository
+func NewWebhookEventRepository(db *gorm.DB) *WebhookEventRepository {
+	return &WebhookEventRepository{db: db}
+}
+
+// FindByID finds a webhook event by ID
+func (r *WebhookEventRepository) FindByID(ctx context.Context, id string) (*services.WebhookEvent, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByExternalID finds a webhook event by the provider's own event ID
+func (r *WebhookEventRepository) FindByExternalID(ctx context.Context, provider, externalID string) (*services.WebhookEvent, error) {
+	return r.findOne(ctx, "provider = ? AND external_id = ?", provider, externalID)
+}
+
+// List lists webhook events matching the filter, newest first
+func (r *WebhookEventRepository) List(ctx context.Context, filter services.WebhookEventFilter) ([]*services.WebhookEvent, error) {
+	query := r.applyFilter(r.db.WithContext(ctx).Model(&database.WebhookEvent{}), filter).
+		Order("received_at DESC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var dbEvents []database.WebhookEvent
+	if err := query.Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]*services.WebhookEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = r.toDomain(&dbEvent)
+	}
+	return events, nil
+}
+
+// Count counts webhook events matching the filter
+func (r *WebhookEventRepository) Count(ctx context.Context, filter services.WebhookEventFilter) (int64, error) {
+	var count int64
+	if err := r.applyFilter(r.db.WithContext(ctx).Model(&database.WebhookEvent{}), filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Save saves a webhook event
+func (r *WebhookEventRepository) Save(ctx context.Context, event *services.WebhookEvent) error {
+	return r.db.WithContext(ctx).Save(r.toDatabase(event)).Error
+}
+
+// Helper methods
+
+func (r *WebhookEventRepository) findOne(ctx context.Context, query string, args ...interface{}) (*services.WebhookEvent, error) {
+	var dbEvent database.WebhookEvent
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&dbEvent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrWebhookEventNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(&dbEvent), nil
+}
+
+func (r *WebhookEventRepository) applyFilter(query *gorm.DB, filter services.WebhookEventFilter) *gorm.DB {
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	return query
+}
+
+func (r *WebhookEventRepository) toDomain(dbEvent *database.WebhookEvent) *services.WebhookEvent {
+	return &services.WebhookEvent{
+		ID:          dbEvent.ID,
+		Provider:    dbEvent.Provider,
+		ExternalID:  dbEvent.ExternalID,
+		EventType:   dbEvent.EventType,
+		Payload:     []byte(dbEvent.Payload),
+		Status:      services.WebhookEventStatus(dbEvent.Status),
+		Attempts:    dbEvent.Attempts,
+		LastError:   dbEvent.LastError,
+		ReceivedAt:  dbEvent.ReceivedAt,
+		ProcessedAt: dbEvent.ProcessedAt,
+	}
+}
+
+func (r *WebhookEventRepository) toDatabase(event *services.WebhookEvent) *database.WebhookEvent {
+	return &database.WebhookEvent{
+		ID:          event.ID,
+		Provider:    event.Provider,
+		ExternalID:  event.ExternalID,
+		EventType:   event.EventType,
+		Payload:     string(event.Payload),
+		Status:      string(event.Status),
+		Attempts:    event.Attempts,
+		LastError:   event.LastError,
+		ReceivedAt:  event.ReceivedAt,
+		ProcessedAt: event.ProcessedAt,
+	}
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// WishlistRepository implements services.WishlistRepository using GORM
+type WishlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWishlistRepository creates a new WishlistRepository
+func NewWishlistRepository(db *gorm.DB) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+// FindByID finds a wishlist by ID with its items
+func (r *WishlistRepository) FindByID(ctx context.Context, id string) (*services.Wishlist, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByUserID finds a user's wishlists with their items, newest first
+func (r *WishlistRepository) FindByUserID(ctx context.Context, userID string) ([]*services.Wishlist, error) {
+	var dbWishlists []database.Wishlist
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&dbWishlists).Error; err != nil {
+		return nil, err
+	}
+
+	wishlists := make([]*services.Wishlist, len(dbWishlists))
+	for i := range dbWishlists {
+		wishlist, err := r.withItems(ctx, &dbWishlists[i])
+		if err != nil {
+			return nil, err
+		}
+		wishlists[i] = wishlist
+	}
+	return wishlists, nil
+}
+
+// FindByShareToken finds the wishlist shared by the token with its items
+func (r *WishlistRepository) FindByShareToken(ctx context.Context, token string) (*services.Wishlist, error) {
+	return r.findOne(ctx, "share_token = ?", token)
+}
+
+// Save creates a wishlist or updates its name, visibility and share token
+func (r *WishlistRepository) Save(ctx context.Context, wishlist *services.Wishlist) error {
+	var shareToken *string
+	if wishlist.ShareToken != "" {
+		shareToken = &wishlist.ShareToken
+	}
+	return r.db.WithContext(ctx).Save(&database.Wishlist{
+		ID:         wishlist.ID,
+		UserID:     wishlist.UserID,
+		Name:       wishlist.Name,
+		IsPublic:   wishlist.IsPublic,
+		ShareToken: shareToken,
+		CreatedAt:  wishlist.CreatedAt,
+		UpdatedAt:  wishlist.UpdatedAt,
+	}).Error
+}
+
+// Delete deletes a wishlist and its items in a single transaction. Purchases made from it
+// are kept for the orders that made them.
+func (r *WishlistRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&database.Wishlist{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrWishlistNotFound
+		}
+		return tx.Delete(&database.WishlistItem{}, "wishlist_id = ?", id).Error
+	})
+}
+
+// FindItem finds a wishlist item by ID
+func (r *WishlistRepository) FindItem(ctx context.Context, id string) (*services.WishlistItem, error) {
+	var dbItem database.WishlistItem
+	if err := r.db.WithContext(ctx).First(&dbItem, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrWishlistItemNotFound
+		}
+		return nil, err
+	}
+	item := r.itemToDomain(&dbItem)
+	return &item, nil
+}
+
+// SaveItem creates an item, or updates its quantity and note. The units purchased are
+// only ever changed by Purchase and CancelOrderPurchases.
+func (r *WishlistRepository) SaveItem(ctx context.Context, item *services.WishlistItem) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity": item.Quantity,
+			"note":     item.Note,
+		}),
+	}).Create(&database.WishlistItem{
+		ID:         item.ID,
+		WishlistID: item.WishlistID,
+		ProductID:  item.ProductID,
+		VariantID:  item.VariantID,
+		Quantity:   item.Quantity,
+		Purchased:  item.Purchased,
+		Note:       item.Note,
+		AddedAt:    item.AddedAt,
+	}).Error
+}
+
+// DeleteItem deletes a wishlist item
+func (r *WishlistRepository) DeleteItem(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&database.WishlistItem{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return services.ErrWishlistItemNotFound
+	}
+	return nil
+}
+
+// Purchase marks the purchase's units of its item bought and records it in a single
+// transaction. The conditional update guards against two orders buying the same gift.
+func (r *WishlistRepository) Purchase(ctx context.Context, purchase *services.WishlistPurchase) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&database.WishlistItem{}).
+			Where("id = ? AND purchased + ? <= quantity", purchase.ItemID, purchase.Quantity).
+			Update("purchased", gorm.Expr("purchased + ?", purchase.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrRegistryItemPurchased
+		}
+
+		return tx.Create(&database.WishlistPurchase{
+			ID:          purchase.ID,
+			WishlistID:  purchase.WishlistID,
+			ItemID:      purchase.ItemID,
+			OrderID:     purchase.OrderID,
+			OrderItemID: purchase.OrderItemID,
+			BuyerID:     purchase.BuyerID,
+			Quantity:    purchase.Quantity,
+			Status:      purchase.Status,
+			CreatedAt:   purchase.CreatedAt,
+		}).Error
+	})
+}
+
+// CancelOrderPurchases gives the units an order bought back to their items and marks its
+// purchases canceled in a single transaction
+func (r *WishlistRepository) CancelOrderPurchases(ctx context.Context, orderID string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbPurchases []database.WishlistPurchase
+		if err := tx.Where("order_id = ? AND status = ?", orderID, services.WishlistPurchaseBought).
+			Find(&dbPurchases).Error; err != nil {
+			return err
+		}
+
+		for _, dbPurchase := range dbPurchases {
+			if err := tx.Model(&database.WishlistItem{}).
+				Where("id = ?", dbPurchase.ItemID).
+				Update("purchased", gorm.Expr("GREATEST(purchased - ?, 0)", dbPurchase.Quantity)).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&database.WishlistPurchase{}).
+				Where("id = ?", dbPurchase.ID).
+				Updates(map[string]interface{}{
+					"status":      services.WishlistPurchaseCanceled,
+					"canceled_at": at,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Helper methods
+
+func (r *WishlistRepository) findOne(ctx context.Context, query string, arg string) (*services.Wishlist, error) {
+	var dbWishlist database.Wishlist
+	if err := r.db.WithContext(ctx).First(&dbWishlist, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, services.ErrWishlistNotFound
+		}
+		return nil, err
+	}
+	return r.withItems(ctx, &dbWishlist)
+}
+
+func (r *WishlistRepository) withItems(ctx context.Context, dbWishlist *database.Wishlist) (*services.Wishlist, error) {
+	var dbItems []database.WishlistItem
+	if err := r.db.WithContext(ctx).
+		Where("wishlist_id = ?", dbWishlist.ID).
+		Order("added_at ASC").
+		Find(&dbItems).Error; err != nil {
+		return nil, err
+	}
+
+	wishlist := &services.Wishlist{
+		ID:        dbWishlist.ID,
+		UserID:    dbWishlist.UserID,
+		Name:      dbWishlist.Name,
+		IsPublic:  dbWishlist.IsPublic,
+		Items:     make([]services.WishlistItem, len(dbItems)),
+		CreatedAt: dbWishlist.CreatedAt,
+		UpdatedAt: dbWishlist.UpdatedAt,
+	}
+	if dbWishlist.ShareToken != nil {
+		wishlist.ShareToken = *dbWishlist.ShareToken
+	}
+	for i := range dbItems {
+		wishlist.Items[i] = r.itemToDomain(&dbItems[i])
+	}
+	return wishlist, nil
+}
+
+func (r *WishlistRepository) itemToDomain(dbItem *database.WishlistItem) services.WishlistItem {
+	return services.WishlistItem{
+		ID:         dbItem.ID,
+		WishlistID: dbItem.WishlistID,
+		ProductID:  dbItem.ProductID,
+		VariantID:  dbItem.VariantID,
+		Quantity:   dbItem.Quantity,
+		Purchased:  dbItem.Purchased,
+		Note:       dbItem.Note,
+		AddedAt:    dbItem.AddedAt,
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+)
+
+// IsRetryableWriteError reports whether a write failed only because it lost a race with a
+// concurrent transaction, so running it again can succeed: a serialization failure or a
+// deadlock. Drivers are matched by the methods on their errors rather than imported.
+func IsRetryableWriteError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// PostgreSQL (pgx): 40001 serialization_failure, 40P01 deadlock_detected
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+		return false
+	}
+
+	// SQL Server: 1205 chosen as deadlock victim
+	var mssqlErr interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.SQLErrorNumber() == 1205
+	}
+
+	// MySQL reports deadlocks as "Error 1213 (40001): Deadlock found ..."
+	message := err.Error()
+	return strings.Contains(message, "Error 1213") || strings.Contains(message, "(40001)")
+}
+
+// writeRetryPolicy returns policy retrying only IsRetryableWriteError failures
+func writeRetryPolicy(policy retry.Policy) retry.Policy {
+	policy.Retryable = IsRetryableWriteError
+	return policy
+}
+
+// retryWrite runs a write, running it again from the start when it fails with a
+// serialization failure or deadlock. A zero policy runs it once.
+func retryWrite(ctx context.Context, policy retry.Policy, write func(ctx context.Context) error) error {
+	return retry.Do(ctx, policy, write)
+}
+
+// retryTransaction runs fn in a transaction, running the whole transaction again when it
+// fails with a serialization failure or deadlock, so no partial attempt is kept
+func retryTransaction(ctx context.Context, db *gorm.DB, policy retry.Policy, fn func(tx *gorm.DB) error) error {
+	return retryWrite(ctx, policy, func(ctx context.Context) error {
+		return db.WithContext(ctx).Transaction(fn)
+	})
+}
@@ -0,0 +1,108 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ErrBudgetExhausted is returned, wrapping the last attempt's error, when the time budget
+// or the caller's deadline leaves no room for another attempt
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// Policy configures retries of a call to an external service
+type Policy struct {
+	// MaxAttempts is the total number of attempts including the first; 1 or less never retries
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles for each later retry
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+	// Budget caps the time spent on all attempts and waits together. The caller's own
+	// deadline applies as well; whichever is sooner wins. 0 leaves only the caller's.
+	Budget time.Duration
+	// Retryable decides which errors are worth another attempt; nil retries every error
+	Retryable func(err error) bool
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable, runs out of
+// attempts or runs out of budget. Waits between attempts use exponential backoff with
+// jitter, so callers that failed together do not retry together. A retry is skipped
+// rather than started when the wait alone would overrun the deadline.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	parent := ctx
+	if policy.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Budget)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if parent.Err() != nil {
+			return err // the caller gave up; nothing to report beyond what it already knows
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", ErrBudgetExhausted, err)
+		}
+
+		delay := policy.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return fmt.Errorf("%w: %w", ErrBudgetExhausted, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if parent.Err() != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ErrBudgetExhausted, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// Call is Do for functions that return a value
+func Call[T any](ctx context.Context, policy Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := Do(ctx, policy, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (p Policy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return true
+	}
+	return p.Retryable(err)
+}
+
+// backoff returns the wait after the given attempt: half the exponential delay plus a
+// random share of the other half
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
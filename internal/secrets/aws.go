@@ -0,0 +1,175 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials are the access keys requests to AWS are signed with
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // for temporary credentials
+}
+
+// AWSProvider reads secrets from AWS Secrets Manager. Paths are secret names or ARNs, as in
+// awssm://prod/gocommerce/db#password.
+type AWSProvider struct {
+	region      string
+	endpoint    string
+	credentials AWSCredentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// NewAWSProvider creates an AWSProvider for the region's Secrets Manager endpoint
+func NewAWSProvider(region string, credentials AWSCredentials, timeout time.Duration) *AWSProvider {
+	return &AWSProvider{
+		region:      region,
+		endpoint:    "https://secretsmanager." + region + ".amazonaws.com",
+		credentials: credentials,
+		client:      &http.Client{Timeout: timeout},
+		now:         time.Now,
+	}
+}
+
+// WithEndpoint sends requests to endpoint instead of the regional one, such as a VPC
+// endpoint or a local emulator
+func (p *AWSProvider) WithEndpoint(endpoint string) *AWSProvider {
+	p.endpoint = strings.TrimRight(endpoint, "/")
+	return p
+}
+
+// Fetch returns the current version of the secret: its string, or its binary value
+// decoded
+func (p *AWSProvider) Fetch(ctx context.Context, path string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, body)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(raw, &awsErr)
+		if strings.HasSuffix(awsErr.Type, "ResourceNotFoundException") {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("secrets manager returned %d: %s %s", resp.StatusCode, awsErr.Type, awsErr.Message)
+	}
+
+	var payload struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary *string `json:"SecretBinary"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode secrets manager response: %w", err)
+	}
+	switch {
+	case payload.SecretString != nil:
+		return *payload.SecretString, nil
+	case payload.SecretBinary != nil:
+		decoded, err := base64.StdEncoding.DecodeString(*payload.SecretBinary)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode secret binary: %w", err)
+		}
+		return string(decoded), nil
+	}
+	return "", ErrSecretNotFound
+}
+
+// sign adds a Signature Version 4 Authorization header to the request
+func (p *AWSProvider) sign(req *http.Request, body []byte) {
+	const service = "secretsmanager"
+
+	now := p.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.credentials.SessionToken)
+	}
+
+	host := req.URL.Host
+	if u, err := url.Parse(p.endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	if p.credentials.SessionToken != "" {
+		headers["x-amz-security-token"] = p.credentials.SessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + p.region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+p.credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
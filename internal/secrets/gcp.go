@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GCPProvider reads secrets from GCP Secret Manager. Paths are <project>/<secret> for the
+// latest version or <project>/<secret>/<version>, as in gcpsm://shop-prod/jwt-secret.
+// Requests are authorized with a fixed access token when one is given, otherwise with the
+// service account token from the instance metadata server.
+type GCPProvider struct {
+	endpoint    string
+	metadataURL string
+	staticToken string
+	client      *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPProvider creates a GCPProvider; accessToken may be empty to use the metadata server
+func NewGCPProvider(accessToken string, timeout time.Duration) *GCPProvider {
+	return &GCPProvider{
+		endpoint:    "https://secretmanager.googleapis.com",
+		metadataURL: "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token",
+		staticToken: accessToken,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+// WithEndpoints sends requests to other Secret Manager and metadata server URLs, such as
+// a private endpoint or a local emulator
+func (p *GCPProvider) WithEndpoints(endpoint, metadataURL string) *GCPProvider {
+	p.endpoint = strings.TrimRight(endpoint, "/")
+	p.metadataURL = metadataURL
+	return p
+}
+
+// Fetch returns the payload of the secret version
+func (p *GCPProvider) Fetch(ctx context.Context, path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", fmt.Errorf("%w: gcp path %q must be <project>/<secret>[/<version>]", ErrInvalidRef, path)
+	}
+	version := "latest"
+	if len(parts) == 3 {
+		version = parts[2]
+	}
+
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/%s:access", p.endpoint, parts[0], parts[1], version)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSecretNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("secret manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode secret manager response: %w", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(payload.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret payload: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// accessToken returns the fixed token, or a metadata server token cached until shortly
+// before it expires
+func (p *GCPProvider) accessToken(ctx context.Context) (string, error) {
+	if p.staticToken != "" {
+		return p.staticToken, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get a token from the metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode metadata server token: %w", err)
+	}
+	p.token = token.AccessToken
+	p.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
@@ -0,0 +1,230 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoProvider     = errors.New("no secrets provider is configured for the reference")
+	ErrInvalidRef     = errors.New("invalid secret reference")
+	ErrFieldNotFound  = errors.New("secret has no such field")
+	ErrSecretNotFound = errors.New("secret not found")
+)
+
+// Schemes of the supported secret stores
+const (
+	SchemeVault = "vault" // vault://<mount>/<path>#<field>, a HashiCorp Vault KV v2 secret
+	SchemeAWS   = "awssm" // awssm://<secret id or ARN>[#<field>], an AWS Secrets Manager secret
+	SchemeGCP   = "gcpsm" // gcpsm://<project>/<secret>[/<version>][#<field>], a GCP Secret Manager secret
+)
+
+// Reference points at a secret in a store. Field picks one key of a secret stored as a
+// JSON object; without it the whole secret is the value.
+type Reference struct {
+	Scheme string
+	Path   string
+	Field  string
+}
+
+// ParseReference parses a value such as "vault://secret/api#jwt_secret". ok is false for
+// values that aren't references, which are used as they are.
+func ParseReference(value string) (ref Reference, ok bool, err error) {
+	scheme, rest, found := strings.Cut(value, "://")
+	if !found {
+		return Reference{}, false, nil
+	}
+	switch scheme {
+	case SchemeVault, SchemeAWS, SchemeGCP:
+	default:
+		return Reference{}, false, nil
+	}
+
+	path, field, _ := strings.Cut(rest, "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return Reference{}, true, fmt.Errorf("%w: %s has no path", ErrInvalidRef, value)
+	}
+	if scheme == SchemeVault && field == "" {
+		return Reference{}, true, fmt.Errorf("%w: %s needs a #field; Vault secrets are key/value maps", ErrInvalidRef, value)
+	}
+	return Reference{Scheme: scheme, Path: path, Field: field}, true, nil
+}
+
+// String returns the reference as written
+func (r Reference) String() string {
+	if r.Field == "" {
+		return r.Scheme + "://" + r.Path
+	}
+	return r.Scheme + "://" + r.Path + "#" + r.Field
+}
+
+// secretKey identifies the stored secret, shared by references to different fields of it
+func (r Reference) secretKey() string {
+	return r.Scheme + "://" + r.Path
+}
+
+// Provider fetches secrets from one store
+type Provider interface {
+	// Fetch returns the secret at path as stored: a plain string, or a JSON object for
+	// secrets holding several values
+	Fetch(ctx context.Context, path string) (string, error)
+}
+
+// Resolver resolves secret references through the configured providers. Fetched secrets
+// are cached for the TTL; when a store can't be reached, the last value fetched keeps
+// being used. Refresh fetches every secret again and reports those that were rotated.
+type Resolver struct {
+	providers map[string]Provider
+	ttl       time.Duration
+
+	mu       sync.Mutex
+	cache    map[string]cachedSecret // by stored secret
+	resolved map[string]string       // last value of each reference resolved, by reference
+}
+
+// cachedSecret is a fetched secret and when it was fetched
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewResolver creates a Resolver caching secrets for ttl; 0 caches them until Refresh
+func NewResolver(ttl time.Duration) *Resolver {
+	return &Resolver{
+		providers: make(map[string]Provider),
+		ttl:       ttl,
+		cache:     make(map[string]cachedSecret),
+		resolved:  make(map[string]string),
+	}
+}
+
+// WithProvider resolves references with scheme through provider
+func (r *Resolver) WithProvider(scheme string, provider Provider) *Resolver {
+	r.providers[scheme] = provider
+	return r
+}
+
+// Resolve returns the secret a reference points at, or value itself when it isn't one
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, ok, err := ParseReference(value)
+	if err != nil || !ok {
+		return value, err
+	}
+
+	r.mu.Lock()
+	cached, found := r.cache[ref.secretKey()]
+	r.mu.Unlock()
+
+	if !found || (r.ttl > 0 && time.Since(cached.fetchedAt) >= r.ttl) {
+		fetched, err := r.fetch(ctx, ref)
+		switch {
+		case err == nil:
+			cached = fetched
+		case found:
+			// Keep serving the last value while the store is unreachable
+			log.Printf("Failed to refresh secret %s, using the cached value: %v", ref.secretKey(), err)
+		default:
+			return "", err
+		}
+	}
+
+	secret, err := extractField(cached.value, ref)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	r.resolved[ref.String()] = secret
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// Refresh fetches every cached secret again and returns the references resolved before
+// whose value has changed since, sorted. Secrets that can't be fetched keep their cached
+// value; their errors are returned together after the rest are refreshed.
+func (r *Resolver) Refresh(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	refs := make([]Reference, 0, len(r.resolved))
+	for raw := range r.resolved {
+		ref, _, _ := ParseReference(raw)
+		refs = append(refs, ref)
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	fetched := make(map[string]bool)
+	for _, ref := range refs {
+		if fetched[ref.secretKey()] {
+			continue
+		}
+		fetched[ref.secretKey()] = true
+		if _, err := r.fetch(ctx, ref); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	rotated := []string{}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ref := range refs {
+		secret, err := extractField(r.cache[ref.secretKey()].value, ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if r.resolved[ref.String()] != secret {
+			r.resolved[ref.String()] = secret
+			rotated = append(rotated, ref.String())
+		}
+	}
+	sort.Strings(rotated)
+	return rotated, errors.Join(errs...)
+}
+
+// fetch reads a secret from its store and caches it
+func (r *Resolver) fetch(ctx context.Context, ref Reference) (cachedSecret, error) {
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return cachedSecret{}, fmt.Errorf("%w: %s", ErrNoProvider, ref.secretKey())
+	}
+	value, err := provider.Fetch(ctx, ref.Path)
+	if err != nil {
+		return cachedSecret{}, fmt.Errorf("failed to fetch secret %s: %w", ref.secretKey(), err)
+	}
+
+	secret := cachedSecret{value: value, fetchedAt: time.Now()}
+	r.mu.Lock()
+	r.cache[ref.secretKey()] = secret
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// extractField picks the reference's field out of a secret stored as a JSON object
+func extractField(value string, ref Reference) (string, error) {
+	if ref.Field == "" {
+		return value, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("%w: %s is not a JSON object", ErrFieldNotFound, ref.secretKey())
+	}
+	field, ok := fields[ref.Field]
+	if !ok || field == nil {
+		return "", fmt.Errorf("%w: %s", ErrFieldNotFound, ref)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(field)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
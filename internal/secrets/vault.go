@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault KV v2 engine. Paths are
+// <mount>/<path>, as in vault://secret/gocommerce/api#jwt_secret.
+type VaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// NewVaultProvider creates a VaultProvider for the Vault server at addr
+func NewVaultProvider(addr, token, namespace string, timeout time.Duration) *VaultProvider {
+	return &VaultProvider{
+		addr:      strings.TrimRight(addr, "/"),
+		token:     token,
+		namespace: namespace,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Fetch returns the latest version of the secret's key/value map as a JSON object
+func (p *VaultProvider) Fetch(ctx context.Context, path string) (string, error) {
+	mount, name, ok := strings.Cut(path, "/")
+	if !ok {
+		return "", fmt.Errorf("%w: vault path %q needs a mount and a secret name", ErrInvalidRef, path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+mount+"/data/"+name, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSecretNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if len(payload.Data.Data) == 0 || string(payload.Data.Data) == "null" {
+		// The latest version was deleted
+		return "", ErrSecretNotFound
+	}
+	return string(payload.Data.Data), nil
+}
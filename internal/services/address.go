@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+)
+
+// AddressIssue describes a single problem found with an address field
+type AddressIssue struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AddressValidationResult is the outcome of validating an address
+type AddressValidationResult struct {
+	Deliverable bool           `json:"deliverable"`
+	Normalized  orders.Address `json:"normalized"`
+	Corrected   bool           `json:"corrected"` // true when Normalized differs from the input
+	Issues      []AddressIssue `json:"issues,omitempty"`
+}
+
+// AddressValidationError is returned when an address is rejected as undeliverable
+type AddressValidationError struct {
+	Issues    []AddressIssue
+	Suggested orders.Address
+}
+
+func (e *AddressValidationError) Error() string {
+	if len(e.Issues) == 0 {
+		return "address is undeliverable"
+	}
+	return fmt.Sprintf("address is undeliverable: %s", e.Issues[0].Message)
+}
+
+// AddressValidator validates and normalizes postal addresses.
+// Implementations may call out to an external provider; the built-in
+// RuleBasedAddressValidator works offline.
+type AddressValidator interface {
+	Validate(ctx context.Context, address orders.Address) (*AddressValidationResult, error)
+}
+
+// AddressService validates addresses for checkout and saved addresses
+type AddressService struct {
+	validator AddressValidator
+	retry     retry.Policy
+}
+
+// NewAddressService creates a new AddressService
+func NewAddressService(validator AddressValidator) *AddressService {
+	return &AddressService{validator: validator}
+}
+
+// WithRetry retries validator calls that fail, for validators backed by an external provider
+func (s *AddressService) WithRetry(policy retry.Policy) *AddressService {
+	s.retry = policy
+	return s
+}
+
+// Validate returns validation details without rejecting the address
+func (s *AddressService) Validate(ctx context.Context, address orders.Address) (*AddressValidationResult, error) {
+	return s.validate(ctx, address)
+}
+
+// Normalize returns the normalized address, or an *AddressValidationError if it is undeliverable
+func (s *AddressService) Normalize(ctx context.Context, address orders.Address) (orders.Address, error) {
+	result, err := s.validate(ctx, address)
+	if err != nil {
+		return address, err
+	}
+	if !result.Deliverable {
+		return address, &AddressValidationError{Issues: result.Issues, Suggested: result.Normalized}
+	}
+	return result.Normalized, nil
+}
+
+func (s *AddressService) validate(ctx context.Context, address orders.Address) (*AddressValidationResult, error) {
+	return retry.Call(ctx, s.retry, func(ctx context.Context) (*AddressValidationResult, error) {
+		return s.validator.Validate(ctx, address)
+	})
+}
+
+// postalCodePatterns holds postal code formats for countries we ship to most often
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] \d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} [A-Z]{2}$`),
+	"PH": regexp.MustCompile(`^\d{4}$`),
+}
+
+// usStates lists valid US state and territory codes
+var usStates = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true, "DE": true,
+	"FL": true, "GA": true, "HI": true, "ID": true, "IL": true, "IN": true, "IA": true, "KS": true,
+	"KY": true, "LA": true, "ME": true, "MD": true, "MA": true, "MI": true, "MN": true, "MS": true,
+	"MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true, "NM": true, "NY": true,
+	"NC": true, "ND": true, "OH": true, "OK": true, "OR": true, "PA": true, "RI": true, "SC": true,
+	"SD": true, "TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true, "WV": true,
+	"WI": true, "WY": true, "DC": true, "PR": true, "GU": true, "VI": true, "AS": true, "MP": true,
+}
+
+// countryAliases maps common country spellings to ISO 3166-1 alpha-2 codes
+var countryAliases = map[string]string{
+	"USA":            "US",
+	"UNITED STATES":  "US",
+	"CANADA":         "CA",
+	"UK":             "GB",
+	"UNITED KINGDOM": "GB",
+	"GREAT BRITAIN":  "GB",
+	"AUSTRALIA":      "AU",
+	"GERMANY":        "DE",
+	"FRANCE":         "FR",
+	"NETHERLANDS":    "NL",
+	"PHILIPPINES":    "PH",
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// RuleBasedAddressValidator normalizes addresses and checks them against
+// country-specific formatting rules without calling an external service
+type RuleBasedAddressValidator struct{}
+
+// NewRuleBasedAddressValidator creates a new RuleBasedAddressValidator
+func NewRuleBasedAddressValidator() *RuleBasedAddressValidator {
+	return &RuleBasedAddressValidator{}
+}
+
+// Validate normalizes the address and reports any issues that make it undeliverable
+func (v *RuleBasedAddressValidator) Validate(ctx context.Context, address orders.Address) (*AddressValidationResult, error) {
+	normalized := orders.Address{
+		FirstName:    collapseSpaces(address.FirstName),
+		LastName:     collapseSpaces(address.LastName),
+		Company:      collapseSpaces(address.Company),
+		AddressLine1: collapseSpaces(address.AddressLine1),
+		AddressLine2: collapseSpaces(address.AddressLine2),
+		City:         collapseSpaces(address.City),
+		State:        strings.ToUpper(collapseSpaces(address.State)),
+		PostalCode:   strings.ToUpper(collapseSpaces(address.PostalCode)),
+		Country:      normalizeCountry(address.Country),
+		Phone:        collapseSpaces(address.Phone),
+	}
+	normalized.PostalCode = formatPostalCode(normalized.Country, normalized.PostalCode)
+
+	var issues []AddressIssue
+	if normalized.AddressLine1 == "" {
+		issues = append(issues, AddressIssue{Field: "address1", Code: "missing", Message: "street address is required"})
+	}
+	if normalized.City == "" {
+		issues = append(issues, AddressIssue{Field: "city", Code: "missing", Message: "city is required"})
+	}
+	if len(normalized.Country) != 2 {
+		issues = append(issues, AddressIssue{Field: "country", Code: "invalid", Message: "country must be an ISO 3166-1 alpha-2 code"})
+	}
+	if pattern, ok := postalCodePatterns[normalized.Country]; ok && !pattern.MatchString(normalized.PostalCode) {
+		issues = append(issues, AddressIssue{Field: "postal_code", Code: "invalid_format", Message: fmt.Sprintf("postal code is not valid for %s", normalized.Country)})
+	}
+	if normalized.Country == "US" && !usStates[normalized.State] {
+		issues = append(issues, AddressIssue{Field: "state", Code: "invalid", Message: "state must be a two-letter US state code"})
+	}
+
+	return &AddressValidationResult{
+		Deliverable: len(issues) == 0,
+		Normalized:  normalized,
+		Corrected:   normalized != address,
+		Issues:      issues,
+	}, nil
+}
+
+func collapseSpaces(s string) string {
+	return whitespace.ReplaceAllString(strings.TrimSpace(s), " ")
+}
+
+func normalizeCountry(country string) string {
+	c := strings.ToUpper(collapseSpaces(country))
+	if alias, ok := countryAliases[c]; ok {
+		return alias
+	}
+	return c
+}
+
+// formatPostalCode inserts the conventional space for countries that use one
+func formatPostalCode(country, postalCode string) string {
+	compact := strings.ReplaceAll(postalCode, " ", "")
+	switch country {
+	case "CA":
+		if len(compact) == 6 {
+			return compact[:3] + " " + compact[3:]
+		}
+	case "GB":
+		if len(compact) >= 5 && len(compact) <= 7 {
+			return compact[:len(compact)-3] + " " + compact[len(compact)-3:]
+		}
+	case "NL":
+		if len(compact) == 6 {
+			return compact[:4] + " " + compact[4:]
+		}
+	}
+	return postalCode
+}
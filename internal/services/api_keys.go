@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("api key is invalid or has been revoked")
+	ErrInvalidScopes  = errors.New("api key requires a name and at least one known scope")
+)
+
+// apiKeySecretPrefix marks API key secrets so they are recognizable in logs and secret scanners
+const apiKeySecretPrefix = "gck_"
+
+// APIKeyScope limits what an API key may do
+type APIKeyScope string
+
+const (
+	APIKeyScopeFulfillmentRead  APIKeyScope = "fulfillment:read"
+	APIKeyScopeFulfillmentWrite APIKeyScope = "fulfillment:write"
+	APIKeyScopePOSRead          APIKeyScope = "pos:read"
+	APIKeyScopePOSWrite         APIKeyScope = "pos:write"
+)
+
+// IsValid reports whether the scope is a known API key scope
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeFulfillmentRead, APIKeyScopeFulfillmentWrite, APIKeyScopePOSRead, APIKeyScopePOSWrite:
+		return true
+	}
+	return false
+}
+
+// APIKey is a credential issued to an external system such as a 3PL warehouse or a POS.
+// Only a hash of the secret is stored; the secret itself is shown once, at creation.
+type APIKey struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"` // first characters of the secret, to tell keys apart
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	QuotaTier  string        `json:"quota_tier,omitempty"` // "" is the api_key tier
+	CreatedBy  string        `json:"created_by"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// HasScope reports whether the key grants the scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRepository defines persistence for API keys
+type APIKeyRepository interface {
+	FindByID(ctx context.Context, id string) (*APIKey, error)
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	Save(ctx context.Context, key *APIKey) error
+}
+
+// APIKeyService issues, verifies and revokes scoped API keys
+type APIKeyService struct {
+	repo APIKeyRepository
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(repo APIKeyRepository) *APIKeyService {
+	return &APIKeyService{repo: repo}
+}
+
+// CreateKey issues a new key and returns it with its secret, which cannot be retrieved later
+func (s *APIKeyService) CreateKey(ctx context.Context, name string, scopes []APIKeyScope, createdBy string) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(scopes) == 0 {
+		return nil, "", ErrInvalidScopes
+	}
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, "", ErrInvalidScopes
+		}
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := apiKeySecretPrefix + hex.EncodeToString(raw)
+
+	key := &APIKey{
+		ID:        utils.GenerateID(),
+		Name:      name,
+		Prefix:    secret[:len(apiKeySecretPrefix)+8],
+		KeyHash:   hashAPIKey(secret),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// Authenticate returns the active key matching a secret
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (*APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeySecretPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByHash(ctx, hashAPIKey(secret))
+	if err == ErrAPIKeyNotFound {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Record usage at most once a minute to keep polling integrations from writing on every request
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > time.Minute {
+		key.LastUsedAt = &now
+		_ = s.repo.Save(ctx, key)
+	}
+	return key, nil
+}
+
+// GetKey returns a key
+func (s *APIKeyService) GetKey(ctx context.Context, id string) (*APIKey, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListKeys lists all issued keys, including revoked ones
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]*APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// RevokeKey permanently disables a key
+func (s *APIKeyService) RevokeKey(ctx context.Context, id string) (*APIKey, error) {
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := s.repo.Save(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// SetQuotaTier puts a key on a quota tier, such as one for partner integrations; "" puts
+// it back on the api_key tier
+func (s *APIKeyService) SetQuotaTier(ctx context.Context, id, tier string) (*APIKey, error) {
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	key.QuotaTier = tier
+	if err := s.repo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
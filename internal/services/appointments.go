@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrAppointmentProductNotFound    = errors.New("product is not bookable by appointment")
+	ErrInvalidAppointmentProduct     = errors.New("cancellation window must be between 0 and 720 hours")
+	ErrAppointmentSlotNotFound       = errors.New("appointment slot not found")
+	ErrInvalidAppointmentSlot        = errors.New("appointment slot needs a start in the future, an end after it and a capacity of at least 1 and no less than the seats booked")
+	ErrAppointmentSlotRequired       = errors.New("appointment products need an appointment_slot_id")
+	ErrAppointmentSlotUnavailable    = errors.New("appointment slot is not open for booking")
+	ErrAppointmentSlotFull           = errors.New("appointment slot does not have enough seats left")
+	ErrAppointmentInCart             = errors.New("product is already in the cart for another slot; remove it first to change it")
+	ErrAppointmentCancelWindowPassed = errors.New("appointments can no longer be canceled this close to their start")
+)
+
+// Cart and order item attributes recording the appointment booked: the slot and when it
+// starts and ends, in RFC 3339
+const (
+	AppointmentSlotAttribute   = "appointment_slot_id"
+	AppointmentStartsAttribute = "appointment_starts_at"
+	AppointmentEndsAttribute   = "appointment_ends_at"
+)
+
+// Appointment booking statuses
+const (
+	AppointmentBooked   = "booked"
+	AppointmentCanceled = "canceled"
+)
+
+// DefaultAppointmentWindowDays is how far ahead slots are listed when no range is given
+const DefaultAppointmentWindowDays = 14
+
+const maxCancelWindowHours = 720
+
+// AppointmentProduct makes a product a service booked into time slots, such as a class or
+// a repair appointment. Each unit in the cart is a seat in the slot chosen.
+type AppointmentProduct struct {
+	ProductID         string    `json:"product_id"`
+	CancelWindowHours int       `json:"cancel_window_hours"` // customers can't cancel within this many hours of the start
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// AppointmentSlot is a time slot of an appointment product with a number of seats
+type AppointmentSlot struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int       `json:"capacity"`
+	Booked    int       `json:"booked"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Remaining returns the seats of the slot still free
+func (s *AppointmentSlot) Remaining() int {
+	return max(s.Capacity-s.Booked, 0)
+}
+
+// AppointmentBooking is seats an order holds in an appointment slot
+type AppointmentBooking struct {
+	ID          string     `json:"id"`
+	SlotID      string     `json:"slot_id"`
+	ProductID   string     `json:"product_id"`
+	OrderID     string     `json:"order_id"`
+	OrderItemID string     `json:"order_item_id"`
+	UserID      string     `json:"user_id"`
+	Quantity    int        `json:"quantity"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CancelBy    time.Time  `json:"cancel_by"` // last moment the customer may cancel
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CanceledAt  *time.Time `json:"canceled_at,omitempty"`
+}
+
+// AppointmentRepository defines persistence for appointment products, slots and bookings
+type AppointmentRepository interface {
+	FindProduct(ctx context.Context, productID string) (*AppointmentProduct, error)
+	SaveProduct(ctx context.Context, product *AppointmentProduct) error
+	DeleteProduct(ctx context.Context, productID string) error
+	FindSlot(ctx context.Context, id string) (*AppointmentSlot, error)
+	// FindSlots returns a product's slots starting from from to to, in start order
+	FindSlots(ctx context.Context, productID string, from, to time.Time) ([]*AppointmentSlot, error)
+	// SaveSlot creates a slot or updates its times, capacity and status, leaving the seats
+	// booked as they are
+	SaveSlot(ctx context.Context, slot *AppointmentSlot) error
+	// Book atomically takes the booking's seats in its slot and records it; it returns
+	// ErrAppointmentSlotFull when too few are left
+	Book(ctx context.Context, booking *AppointmentBooking) error
+	FindOrderBookings(ctx context.Context, orderID string) ([]*AppointmentBooking, error)
+	FindSlotBookings(ctx context.Context, slotID string) ([]*AppointmentBooking, error)
+	// CancelOrderBookings frees the seats of an order's booked appointments and marks them
+	// canceled
+	CancelOrderBookings(ctx context.Context, orderID string, at time.Time) error
+}
+
+// AppointmentService manages service products booked into time slots: their slots, the
+// slot chosen for each cart item and the bookings orders make
+type AppointmentService struct {
+	repo     AppointmentRepository
+	products catalog.ProductRepository
+}
+
+// NewAppointmentService creates a new AppointmentService
+func NewAppointmentService(repo AppointmentRepository, products catalog.ProductRepository) *AppointmentService {
+	return &AppointmentService{repo: repo, products: products}
+}
+
+// GetAppointmentProduct returns a product's appointment settings
+func (s *AppointmentService) GetAppointmentProduct(ctx context.Context, productID string) (*AppointmentProduct, error) {
+	return s.repo.FindProduct(ctx, productID)
+}
+
+// SetAppointmentProduct validates and saves a product's appointment settings, making it
+// bookable by appointment
+func (s *AppointmentService) SetAppointmentProduct(ctx context.Context, product *AppointmentProduct) error {
+	if _, err := s.products.FindByID(ctx, product.ProductID); err != nil {
+		return ErrProductNotFound
+	}
+	if product.CancelWindowHours < 0 || product.CancelWindowHours > maxCancelWindowHours {
+		return ErrInvalidAppointmentProduct
+	}
+	product.UpdatedAt = time.Now()
+	return s.repo.SaveProduct(ctx, product)
+}
+
+// RemoveAppointmentProduct stops a product being bookable by appointment. Its slots and
+// bookings are kept.
+func (s *AppointmentService) RemoveAppointmentProduct(ctx context.Context, productID string) error {
+	return s.repo.DeleteProduct(ctx, productID)
+}
+
+// CreateSlot adds a time slot to an appointment product
+func (s *AppointmentService) CreateSlot(ctx context.Context, slot *AppointmentSlot) error {
+	if _, err := s.repo.FindProduct(ctx, slot.ProductID); err != nil {
+		return err
+	}
+	if !slot.StartsAt.After(time.Now()) || !slot.EndsAt.After(slot.StartsAt) || slot.Capacity < 1 {
+		return ErrInvalidAppointmentSlot
+	}
+	slot.ID = utils.GenerateID()
+	slot.Booked = 0
+	slot.CreatedAt = time.Now()
+	return s.repo.SaveSlot(ctx, slot)
+}
+
+// GetSlot returns an appointment slot with its booked seats
+func (s *AppointmentService) GetSlot(ctx context.Context, id string) (*AppointmentSlot, error) {
+	return s.repo.FindSlot(ctx, id)
+}
+
+// UpdateSlot changes a slot's times, capacity and whether it is open for booking. The
+// capacity may not go below the seats already booked.
+func (s *AppointmentService) UpdateSlot(ctx context.Context, id string, update AppointmentSlot) (*AppointmentSlot, error) {
+	slot, err := s.repo.FindSlot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !update.EndsAt.After(update.StartsAt) || update.Capacity < 1 || update.Capacity < slot.Booked {
+		return nil, ErrInvalidAppointmentSlot
+	}
+	if !update.StartsAt.Equal(slot.StartsAt) && !update.StartsAt.After(time.Now()) {
+		return nil, ErrInvalidAppointmentSlot
+	}
+
+	slot.StartsAt = update.StartsAt
+	slot.EndsAt = update.EndsAt
+	slot.Capacity = update.Capacity
+	slot.IsActive = update.IsActive
+	if err := s.repo.SaveSlot(ctx, slot); err != nil {
+		return nil, err
+	}
+	return s.repo.FindSlot(ctx, id)
+}
+
+// ListSlots returns all of a product's slots starting within the range, for staff
+func (s *AppointmentService) ListSlots(ctx context.Context, productID string, from, to time.Time) ([]*AppointmentSlot, error) {
+	return s.repo.FindSlots(ctx, productID, from, to)
+}
+
+// AvailableSlots returns a product's open slots starting within the range that still have
+// seats free
+func (s *AppointmentService) AvailableSlots(ctx context.Context, productID string, from, to time.Time) ([]*AppointmentSlot, error) {
+	if _, err := s.repo.FindProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+	slots, err := s.repo.FindSlots(ctx, productID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*AppointmentSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.IsActive && slot.Remaining() > 0 {
+			available = append(available, slot)
+		}
+	}
+	return available, nil
+}
+
+// SlotBookings returns the bookings of a slot, for staff
+func (s *AppointmentService) SlotBookings(ctx context.Context, slotID string) ([]*AppointmentBooking, error) {
+	if _, err := s.repo.FindSlot(ctx, slotID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindSlotBookings(ctx, slotID)
+}
+
+// OrderBookings returns the appointments an order booked
+func (s *AppointmentService) OrderBookings(ctx context.Context, orderID string) ([]*AppointmentBooking, error) {
+	return s.repo.FindOrderBookings(ctx, orderID)
+}
+
+// PrepareItem checks an item about to be added to the cart. Items of appointment products
+// need an open slot of the product with a seat free for each unit, and may not already be
+// in the cart; the slot and its times are recorded in the item's attributes. Items of
+// other products have any appointment attributes removed.
+func (s *AppointmentService) PrepareItem(ctx context.Context, c *cart.Cart, req *cart.AddItemRequest) error {
+	if _, err := s.repo.FindProduct(ctx, req.ProductID); errors.Is(err, ErrAppointmentProductNotFound) {
+		clearAppointmentAttributes(req.Attributes)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	slotID := req.Attributes[AppointmentSlotAttribute]
+	if slotID == "" {
+		return ErrAppointmentSlotRequired
+	}
+	if cartLine(c, req.ProductID, req.VariantID) != nil {
+		return ErrAppointmentInCart
+	}
+	slot, err := s.openSlot(ctx, req.ProductID, slotID, req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	if req.Attributes == nil {
+		req.Attributes = make(map[string]string)
+	}
+	req.Attributes[AppointmentStartsAttribute] = slot.StartsAt.UTC().Format(time.RFC3339)
+	req.Attributes[AppointmentEndsAttribute] = slot.EndsAt.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// CheckItem checks that an appointment item's slot is still open with a seat free for
+// each unit at the quantity given. Items without a slot pass.
+func (s *AppointmentService) CheckItem(ctx context.Context, item *cart.CartItem, quantity int) error {
+	slotID := item.Attributes[AppointmentSlotAttribute]
+	if slotID == "" {
+		return nil
+	}
+	_, err := s.openSlot(ctx, item.ProductID, slotID, quantity)
+	return err
+}
+
+// CheckCart checks every appointment item in the cart before an order is placed
+func (s *AppointmentService) CheckCart(ctx context.Context, c *cart.Cart) error {
+	for i := range c.Items {
+		if err := s.CheckItem(ctx, &c.Items[i], c.Items[i].Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BookOrder books the seats of a placed order's appointment items. When a slot has filled
+// up in the meantime, the seats already booked for the order are freed again and
+// ErrAppointmentSlotFull returned.
+func (s *AppointmentService) BookOrder(ctx context.Context, order *orders.Order) error {
+	now := time.Now()
+	for _, item := range order.Items {
+		slotID := item.Attributes[AppointmentSlotAttribute]
+		if slotID == "" {
+			continue
+		}
+		product, err := s.repo.FindProduct(ctx, item.ProductID)
+		if errors.Is(err, ErrAppointmentProductNotFound) {
+			continue
+		}
+		if err == nil {
+			var slot *AppointmentSlot
+			if slot, err = s.openSlot(ctx, item.ProductID, slotID, item.Quantity); err == nil {
+				err = s.repo.Book(ctx, &AppointmentBooking{
+					ID:          utils.GenerateID(),
+					SlotID:      slot.ID,
+					ProductID:   item.ProductID,
+					OrderID:     order.ID,
+					OrderItemID: item.ID,
+					UserID:      order.UserID,
+					Quantity:    item.Quantity,
+					StartsAt:    slot.StartsAt,
+					EndsAt:      slot.EndsAt,
+					CancelBy:    slot.StartsAt.Add(-time.Duration(product.CancelWindowHours) * time.Hour),
+					Status:      AppointmentBooked,
+					CreatedAt:   now,
+				})
+			}
+		}
+		if err != nil {
+			if releaseErr := s.repo.CancelOrderBookings(ctx, order.ID, now); releaseErr != nil {
+				return fmt.Errorf("%w (and freeing the order's other seats failed: %v)", err, releaseErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckCancel returns ErrAppointmentCancelWindowPassed when a customer may no longer
+// cancel the order because one of its appointments is within its cancellation window
+func (s *AppointmentService) CheckCancel(ctx context.Context, orderID string, at time.Time) error {
+	bookings, err := s.repo.FindOrderBookings(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, booking := range bookings {
+		if booking.Status == AppointmentBooked && at.After(booking.CancelBy) {
+			return fmt.Errorf("%w: the appointment at %s could be canceled until %s", ErrAppointmentCancelWindowPassed,
+				booking.StartsAt.UTC().Format(time.RFC3339), booking.CancelBy.UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// ReleaseOrder frees the seats an order booked, when it is canceled
+func (s *AppointmentService) ReleaseOrder(ctx context.Context, orderID string) error {
+	return s.repo.CancelOrderBookings(ctx, orderID, time.Now())
+}
+
+// openSlot returns a slot of the product that is open for booking and has quantity seats
+// free
+func (s *AppointmentService) openSlot(ctx context.Context, productID, slotID string, quantity int) (*AppointmentSlot, error) {
+	slot, err := s.repo.FindSlot(ctx, slotID)
+	if errors.Is(err, ErrAppointmentSlotNotFound) {
+		return nil, ErrAppointmentSlotUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if slot.ProductID != productID || !slot.IsActive || !slot.StartsAt.After(time.Now()) {
+		return nil, ErrAppointmentSlotUnavailable
+	}
+	if slot.Remaining() < quantity {
+		return nil, fmt.Errorf("%w: %d of %d seats requested are free", ErrAppointmentSlotFull, slot.Remaining(), quantity)
+	}
+	return slot, nil
+}
+
+func clearAppointmentAttributes(attributes map[string]string) {
+	delete(attributes, AppointmentSlotAttribute)
+	delete(attributes, AppointmentStartsAttribute)
+	delete(attributes, AppointmentEndsAttribute)
+}
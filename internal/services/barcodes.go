@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+var (
+	ErrInvalidBarcode  = errors.New("barcode must be an 8, 12, 13 or 14 digit GTIN with a valid check digit")
+	ErrBarcodeTaken    = errors.New("barcode is already assigned to another variant")
+	ErrBarcodeNotFound = errors.New("no variant has this barcode")
+	ErrVariantNotFound = errors.New("variant not found")
+)
+
+// gtinLength is the length barcodes are stored at; shorter GTINs are padded with leading
+// zeros so a UPC-A scanned as 12 digits and its 13 digit EAN form find the same variant
+const gtinLength = 14
+
+// ScannedVariant is the variant a barcode belongs to, with its product for display at the
+// till or on a warehouse handset
+type ScannedVariant struct {
+	Barcode   string           `json:"barcode"`
+	Variant   *catalog.Variant `json:"variant"`
+	UnitPrice *UnitPrice       `json:"unit_price,omitempty"`
+	Product   *ProductResponse `json:"product"`
+}
+
+// BarcodeRepository defines persistence for variant barcodes
+type BarcodeRepository interface {
+	// FindVariantID returns the ID of the variant with the barcode, or ErrBarcodeNotFound
+	FindVariantID(ctx context.Context, barcode string) (string, error)
+	// FindBarcode returns a variant's barcode, or "" when it has none
+	FindBarcode(ctx context.Context, variantID string) (string, error)
+	// SetBarcode sets a variant's barcode, or clears it when barcode is "", returning
+	// ErrVariantNotFound when there is no such variant
+	SetBarcode(ctx context.Context, variantID, barcode string) error
+}
+
+// BarcodeService assigns GTIN/EAN barcodes to variants and looks variants up by scan
+type BarcodeService struct {
+	repo           BarcodeRepository
+	variants       catalog.VariantRepository
+	catalogService *CatalogService
+	unitPrices     *UnitPriceService
+}
+
+// NewBarcodeService creates a new BarcodeService
+func NewBarcodeService(repo BarcodeRepository, variants catalog.VariantRepository, catalogService *CatalogService) *BarcodeService {
+	return &BarcodeService{
+		repo:           repo,
+		variants:       variants,
+		catalogService: catalogService,
+	}
+}
+
+// WithUnitPrices shows the unit price of scanned variants that have a unit, for shelf
+// labels and the till display
+func (s *BarcodeService) WithUnitPrices(unitPrices *UnitPriceService) *BarcodeService {
+	s.unitPrices = unitPrices
+	return s
+}
+
+// NormalizeBarcode validates a GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN) or GTIN-14 and
+// returns it padded to 14 digits
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrInvalidBarcode
+	}
+
+	// Weights alternate 3 and 1 leftwards from the digit before the check digit
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		digit := code[i]
+		if digit < '0' || digit > '9' {
+			return "", ErrInvalidBarcode
+		}
+		if i == len(code)-1 {
+			continue
+		}
+		weight := 1
+		if (len(code)-1-i)%2 == 1 {
+			weight = 3
+		}
+		sum += int(digit-'0') * weight
+	}
+	if int(code[len(code)-1]-'0') != (10-sum%10)%10 {
+		return "", ErrInvalidBarcode
+	}
+
+	return strings.Repeat("0", gtinLength-len(code)) + code, nil
+}
+
+// Lookup finds the variant a scanned barcode belongs to
+func (s *BarcodeService) Lookup(ctx context.Context, code string) (*ScannedVariant, error) {
+	barcode, err := NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	variantID, err := s.repo.FindVariantID(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := s.variants.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.catalogService.GetProduct(ctx, variant.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	scanned := &ScannedVariant{Barcode: barcode, Variant: variant, Product: product}
+	if s.unitPrices != nil {
+		if scanned.UnitPrice, err = s.unitPrices.VariantUnitPrice(ctx, variant); err != nil {
+			return nil, err
+		}
+	}
+	return scanned, nil
+}
+
+// GetBarcode returns a variant's barcode, or "" when it has none
+func (s *BarcodeService) GetBarcode(ctx context.Context, variantID string) (string, error) {
+	return s.repo.FindBarcode(ctx, variantID)
+}
+
+// SetBarcode assigns a barcode to a variant and returns it as stored. A barcode already
+// assigned to another variant is refused; the unique index on variants.barcode backs
+// this check up against concurrent assignments.
+func (s *BarcodeService) SetBarcode(ctx context.Context, variantID, code string) (string, error) {
+	barcode, err := NormalizeBarcode(code)
+	if err != nil {
+		return "", err
+	}
+
+	ownerID, err := s.repo.FindVariantID(ctx, barcode)
+	switch {
+	case err == nil && ownerID != variantID:
+		return "", ErrBarcodeTaken
+	case err != nil && err != ErrBarcodeNotFound:
+		return "", err
+	}
+
+	if err := s.repo.SetBarcode(ctx, variantID, barcode); err != nil {
+		return "", err
+	}
+	return barcode, nil
+}
+
+// RemoveBarcode clears a variant's barcode
+func (s *BarcodeService) RemoveBarcode(ctx context.Context, variantID string) error {
+	return s.repo.SetBarcode(ctx, variantID, "")
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidBulkArchive = errors.New("invalid bulk archive")
+	ErrBulkJobNotFound    = errors.New("bulk job not found")
+)
+
+// ProductStatusArchived is the status of a product taken out of the catalog without
+// deleting it; archived products are no longer listed or sold
+const ProductStatusArchived catalog.ProductStatus = "archived"
+
+const (
+	// maxBulkArchiveIDs bounds how many IDs one request may list; larger archives use a filter
+	maxBulkArchiveIDs = 10000
+	// maxBulkJobErrors bounds how many item errors a job keeps
+	maxBulkJobErrors = 20
+	// maxBulkJobs bounds how many finished jobs are kept for their progress to be read
+	maxBulkJobs = 100
+)
+
+// Bulk job actions
+const (
+	BulkArchiveProducts = "archive_products"
+	BulkArchiveVariants = "archive_variants"
+)
+
+// BulkJobStatus is where a bulk job is in its run
+type BulkJobStatus string
+
+const (
+	BulkJobQueued    BulkJobStatus = "queued"
+	BulkJobRunning   BulkJobStatus = "running"
+	BulkJobCompleted BulkJobStatus = "completed"
+	BulkJobFailed    BulkJobStatus = "failed"
+)
+
+// BulkArchiveFilter selects what to archive when no IDs are given. Products match on
+// category, brand and status; variants on their product, or their product's category and
+// brand. Every set field must match.
+type BulkArchiveFilter struct {
+	ProductID  string `json:"product_id,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	BrandID    string `json:"brand_id,omitempty"`
+	Status     string `json:"status,omitempty"`
+}
+
+// BulkJob reports the progress of a bulk archive. Items already archived are counted as
+// processed but not as archived.
+type BulkJob struct {
+	ID         string             `json:"id"`
+	Action     string             `json:"action"`
+	Status     BulkJobStatus      `json:"status"`
+	Filter     *BulkArchiveFilter `json:"filter,omitempty"`
+	Total      int                `json:"total"`
+	Processed  int                `json:"processed"`
+	Archived   int                `json:"archived"`
+	Failed     int                `json:"failed"`
+	Errors     []string           `json:"errors,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	CreatedBy  string             `json:"created_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+// BulkArchiveRepository finds the catalog entities a bulk archive filter selects
+type BulkArchiveRepository interface {
+	// FindProductIDs returns the IDs of the products matching the filter, ordered by ID
+	FindProductIDs(ctx context.Context, filter BulkArchiveFilter) ([]string, error)
+	// FindVariantIDs returns the IDs of the variants matching the filter, ordered by ID
+	FindVariantIDs(ctx context.Context, filter BulkArchiveFilter) ([]string, error)
+}
+
+// BulkArchiveService archives many products or variants at once. Each request becomes a
+// job on the job runner that archives in batches through the catalog writers, so history,
+// category counts and price guards see every change, and reports its progress until it
+// is read. Jobs live in memory; one interrupted by a restart is started again by the admin.
+type BulkArchiveService struct {
+	repo      BulkArchiveRepository
+	products  catalog.ProductRepository
+	variants  catalog.VariantRepository
+	runner    *jobs.Runner
+	batchSize int
+	onChanged func(productIDs ...string)
+
+	mu   sync.Mutex
+	jobs map[string]*BulkJob
+}
+
+// NewBulkArchiveService creates a new BulkArchiveService writing through the given
+// product and variant repositories
+func NewBulkArchiveService(repo BulkArchiveRepository, products catalog.ProductRepository, variants catalog.VariantRepository, runner *jobs.Runner) *BulkArchiveService {
+	return &BulkArchiveService{
+		repo:      repo,
+		products:  products,
+		variants:  variants,
+		runner:    runner,
+		batchSize: 100,
+		jobs:      make(map[string]*BulkJob),
+	}
+}
+
+// WithBatchSize sets how many items are archived between progress updates
+func (s *BulkArchiveService) WithBatchSize(size int) *BulkArchiveService {
+	if size > 0 {
+		s.batchSize = size
+	}
+	return s
+}
+
+// WithChangeHook calls fn with the products changed by each batch, e.g. to drop them from
+// caches
+func (s *BulkArchiveService) WithChangeHook(fn func(productIDs ...string)) *BulkArchiveService {
+	s.onChanged = fn
+	return s
+}
+
+// ArchiveProducts starts a job archiving the listed products, or those matching filter
+// when ids is empty
+func (s *BulkArchiveService) ArchiveProducts(ctx context.Context, adminID string, ids []string, filter BulkArchiveFilter) (*BulkJob, error) {
+	if filter.ProductID != "" {
+		return nil, fmt.Errorf("%w: products are filtered by category_id, brand_id and status", ErrInvalidBulkArchive)
+	}
+	return s.start(adminID, BulkArchiveProducts, ids, filter, s.repo.FindProductIDs, s.archiveProduct)
+}
+
+// ArchiveVariants starts a job archiving the listed variants, or those matching filter
+// when ids is empty
+func (s *BulkArchiveService) ArchiveVariants(ctx context.Context, adminID string, ids []string, filter BulkArchiveFilter) (*BulkJob, error) {
+	if filter.Status != "" {
+		return nil, fmt.Errorf("%w: variants are filtered by product_id, category_id and brand_id", ErrInvalidBulkArchive)
+	}
+	return s.start(adminID, BulkArchiveVariants, ids, filter, s.repo.FindVariantIDs, s.archiveVariant)
+}
+
+// GetJob returns a copy of a job's progress
+func (s *BulkArchiveService) GetJob(ctx context.Context, id string) (*BulkJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrBulkJobNotFound
+	}
+	return copyBulkJob(job), nil
+}
+
+// ListJobs returns copies of the kept jobs, newest first
+func (s *BulkArchiveService) ListJobs(ctx context.Context) []*BulkJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*BulkJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, copyBulkJob(job))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// archiveFunc archives one item, returning the product it belongs to and whether it changed
+type archiveFunc func(ctx context.Context, id string) (productID string, changed bool, err error)
+
+func (s *BulkArchiveService) start(adminID, action string, ids []string, filter BulkArchiveFilter, find func(context.Context, BulkArchiveFilter) ([]string, error), archive archiveFunc) (*BulkJob, error) {
+	ids = uniqueStrings(ids)
+	switch {
+	case len(ids) > 0 && filter != (BulkArchiveFilter{}):
+		return nil, fmt.Errorf("%w: give ids or a filter, not both", ErrInvalidBulkArchive)
+	case len(ids) == 0 && filter == (BulkArchiveFilter{}):
+		return nil, fmt.Errorf("%w: give ids or a filter", ErrInvalidBulkArchive)
+	case len(ids) > maxBulkArchiveIDs:
+		return nil, fmt.Errorf("%w: at most %d ids; use a filter for more", ErrInvalidBulkArchive, maxBulkArchiveIDs)
+	}
+
+	job := &BulkJob{
+		ID:        utils.GenerateID(),
+		Action:    action,
+		Status:    BulkJobQueued,
+		Total:     len(ids),
+		CreatedBy: adminID,
+		CreatedAt: time.Now(),
+	}
+	if len(ids) == 0 {
+		job.Filter = &filter
+	}
+
+	s.mu.Lock()
+	s.prune()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	err := s.runner.Enqueue(jobs.Job{
+		Name: "bulk:" + action + ":" + job.ID,
+		Run: func(ctx context.Context) error {
+			ctx = WithCatalogActor(ctx, adminID)
+			if len(ids) == 0 {
+				found, err := find(ctx, filter)
+				if err != nil {
+					s.finish(job, err)
+					return err
+				}
+				ids = found
+			}
+			err := s.run(ctx, job, ids, archive)
+			s.finish(job, err)
+			return err
+		},
+	})
+	if err != nil {
+		s.mu.Lock()
+		delete(s.jobs, job.ID)
+		s.mu.Unlock()
+		return nil, err
+	}
+	return copyBulkJob(job), nil
+}
+
+// run archives ids in batches, updating the job's progress after each one
+func (s *BulkArchiveService) run(ctx context.Context, job *BulkJob, ids []string, archive archiveFunc) error {
+	now := time.Now()
+	s.mu.Lock()
+	job.Status = BulkJobRunning
+	job.StartedAt = &now
+	job.Total = len(ids)
+	s.mu.Unlock()
+
+	for start := 0; start < len(ids); start += s.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + s.batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var changed []string
+		archived, failed := 0, 0
+		var errs []string
+		for _, id := range ids[start:end] {
+			productID, ok, err := archive(ctx, id)
+			switch {
+			case err != nil:
+				failed++
+				errs = append(errs, fmt.Sprintf("%s: %v", id, err))
+			case ok:
+				archived++
+				changed = append(changed, productID)
+			}
+		}
+
+		if len(changed) > 0 && s.onChanged != nil {
+			s.onChanged(uniqueStrings(changed)...)
+		}
+
+		s.mu.Lock()
+		job.Processed += end - start
+		job.Archived += archived
+		job.Failed += failed
+		for _, e := range errs {
+			if len(job.Errors) < maxBulkJobErrors {
+				job.Errors = append(job.Errors, e)
+			}
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *BulkArchiveService) archiveProduct(ctx context.Context, id string) (string, bool, error) {
+	product, err := s.products.FindByID(ctx, id)
+	if err != nil {
+		return "", false, err
+	}
+	if product.Status == ProductStatusArchived {
+		return product.ID, false, nil
+	}
+	product.Status = ProductStatusArchived
+	product.UpdatedAt = time.Now()
+	if err := s.products.Save(ctx, product); err != nil {
+		return "", false, err
+	}
+	return product.ID, true, nil
+}
+
+func (s *BulkArchiveService) archiveVariant(ctx context.Context, id string) (string, bool, error) {
+	variant, err := s.variants.FindByID(ctx, id)
+	if err != nil {
+		return "", false, err
+	}
+	if !variant.IsAvailable {
+		return variant.ProductID, false, nil
+	}
+	variant.IsAvailable = false
+	variant.UpdatedAt = time.Now()
+	if err := s.variants.Save(ctx, variant); err != nil {
+		return "", false, err
+	}
+	return variant.ProductID, true, nil
+}
+
+// finish records how a job ended
+func (s *BulkArchiveService) finish(job *BulkJob, err error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.FinishedAt = &now
+	job.Status = BulkJobCompleted
+	if err != nil {
+		job.Status = BulkJobFailed
+		job.Error = err.Error()
+	}
+}
+
+// prune drops the oldest finished jobs once too many are kept; callers hold the lock
+func (s *BulkArchiveService) prune() {
+	for len(s.jobs) >= maxBulkJobs {
+		var oldest *BulkJob
+		for _, job := range s.jobs {
+			if job.FinishedAt != nil && (oldest == nil || job.CreatedAt.Before(oldest.CreatedAt)) {
+				oldest = job
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(s.jobs, oldest.ID)
+	}
+}
+
+func copyBulkJob(job *BulkJob) *BulkJob {
+	copied := *job
+	copied.Errors = append([]string(nil), job.Errors...)
+	return &copied
+}
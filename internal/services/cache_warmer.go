@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"golang.org/x/sync/errgroup"
+)
+
+// topSellerWindow is how far back orders are counted when picking products to warm
+const topSellerWindow = 30 * 24 * time.Hour
+
+// warmConcurrency bounds the product loads a warm-up runs at once, so warming does
+// not compete with live traffic for every database connection
+const warmConcurrency = 8
+
+// TopSellerRepository ranks products by recent sales
+type TopSellerRepository interface {
+	TopSellingProductIDs(ctx context.Context, since time.Time, limit int) ([]string, error)
+}
+
+// CacheWarmResult summarizes a warm-up
+type CacheWarmResult struct {
+	Products   int    `json:"products"`
+	Failed     int    `json:"failed"`
+	Categories int    `json:"categories"`
+	Brands     int    `json:"brands"`
+	Duration   string `json:"duration"`
+}
+
+// CacheWarmer loads the category and brand lists and the best-selling products into the
+// catalog caches, so the first requests after a deploy or flush do not all miss
+type CacheWarmer struct {
+	catalog    *CatalogService
+	topSellers TopSellerRepository
+}
+
+// NewCacheWarmer creates a new CacheWarmer
+func NewCacheWarmer(catalogService *CatalogService, topSellers TopSellerRepository) *CacheWarmer {
+	return &CacheWarmer{
+		catalog:    catalogService,
+		topSellers: topSellers,
+	}
+}
+
+// Warm loads the lists and up to topN products: best sellers of the last 30 days first,
+// then other active products. A product that fails to load is counted and skipped.
+func (w *CacheWarmer) Warm(ctx context.Context, topN int) (*CacheWarmResult, error) {
+	started := time.Now()
+	result := &CacheWarmResult{}
+
+	categories, err := w.catalog.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Categories = len(categories)
+
+	brands, err := w.catalog.GetBrands(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Brands = len(brands)
+
+	ids, err := w.productIDs(ctx, topN)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed atomic.Int32
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(warmConcurrency)
+	for _, id := range ids {
+		group.Go(func() error {
+			if _, err := w.catalog.GetProduct(groupCtx, id); err != nil {
+				failed.Add(1)
+				log.Printf("Cache warm-up skipped product %s: %v", id, err)
+			}
+			return nil
+		})
+	}
+	group.Wait()
+
+	result.Failed = int(failed.Load())
+	result.Products = len(ids) - result.Failed
+	result.Duration = time.Since(started).Round(time.Millisecond).String()
+	return result, nil
+}
+
+// productIDs picks the products to warm, topping best sellers up with other active products
+func (w *CacheWarmer) productIDs(ctx context.Context, topN int) ([]string, error) {
+	if topN <= 0 {
+		return nil, nil
+	}
+
+	var ids []string
+	if w.topSellers != nil {
+		top, err := w.topSellers.TopSellingProductIDs(ctx, time.Now().Add(-topSellerWindow), topN)
+		if err != nil {
+			return nil, err
+		}
+		ids = top
+	}
+	if len(ids) >= topN {
+		return ids, nil
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	status := catalog.ProductStatusActive
+	active, err := w.catalog.productRepo.Search(ctx, "", catalog.ProductFilter{
+		Status: &status,
+		Limit:  topN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range active {
+		if len(ids) >= topN {
+			break
+		}
+		if !seen[product.ID] {
+			ids = append(ids, product.ID)
+			seen[product.ID] = true
+		}
+	}
+	return ids, nil
+}
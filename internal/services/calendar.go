@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCalendarNotFound = errors.New("business calendar not found")
+	ErrHolidayNotFound  = errors.New("holiday not found")
+	ErrInvalidCalendar  = errors.New("business calendar requires a valid timezone, at least one working day and a cutoff time as HH:MM")
+	ErrInvalidHoliday   = errors.New("holiday requires a date as YYYY-MM-DD and a name")
+)
+
+const (
+	calendarDateLayout = "2006-01-02"
+	cutoffTimeLayout   = "15:04"
+)
+
+// Holiday is a day the business is closed, in the calendar's timezone
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// DeliveryEstimate is when an order placed now leaves the warehouse and the range of
+// dates it should arrive, counting carrier transit in working days
+type DeliveryEstimate struct {
+	ShipsOn          string `json:"ships_on"`
+	EarliestDelivery string `json:"earliest_delivery"`
+	LatestDelivery   string `json:"latest_delivery"`
+}
+
+// BusinessCalendar describes when the business works: the days orders are packed and
+// shipped, the holidays it is closed, and the time of day after which an order waits
+// for the next working day
+type BusinessCalendar struct {
+	Timezone    string         `json:"timezone"`
+	WorkingDays []time.Weekday `json:"working_days"` // 0 is Sunday
+	CutoffTime  string         `json:"cutoff_time"`  // HH:MM in the calendar's timezone
+	Holidays    []Holiday      `json:"holidays"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// DefaultBusinessCalendar is used until an admin saves one: Monday to Friday in UTC,
+// shipping same day for orders placed before 14:00
+func DefaultBusinessCalendar() *BusinessCalendar {
+	return &BusinessCalendar{
+		Timezone:    "UTC",
+		WorkingDays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		CutoffTime:  "14:00",
+		Holidays:    []Holiday{},
+	}
+}
+
+// Validate checks the calendar's timezone, working days and cutoff time
+func (c *BusinessCalendar) Validate() error {
+	if _, err := time.LoadLocation(c.Timezone); err != nil || c.Timezone == "" {
+		return ErrInvalidCalendar
+	}
+	if len(c.WorkingDays) == 0 {
+		return ErrInvalidCalendar
+	}
+	for _, day := range c.WorkingDays {
+		if day < time.Sunday || day > time.Saturday {
+			return ErrInvalidCalendar
+		}
+	}
+	if _, err := time.Parse(cutoffTimeLayout, c.CutoffTime); err != nil {
+		return ErrInvalidCalendar
+	}
+	return nil
+}
+
+// Location returns the calendar's timezone, or UTC if it can't be loaded
+func (c *BusinessCalendar) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsHoliday reports whether the business is closed for a holiday on t's date
+func (c *BusinessCalendar) IsHoliday(t time.Time) bool {
+	date := t.In(c.Location()).Format(calendarDateLayout)
+	for _, holiday := range c.Holidays {
+		if holiday.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkingDay reports whether t falls on a working day that isn't a holiday
+func (c *BusinessCalendar) IsWorkingDay(t time.Time) bool {
+	weekday := t.In(c.Location()).Weekday()
+	for _, day := range c.WorkingDays {
+		if day == weekday {
+			return !c.IsHoliday(t)
+		}
+	}
+	return false
+}
+
+// DispatchDate returns the working day an order placed at t ships: the same day when it
+// is a working day and the order beat the cutoff, otherwise the next working day
+func (c *BusinessCalendar) DispatchDate(t time.Time) time.Time {
+	loc := c.Location()
+	local := t.In(loc)
+	day := startOfDay(local)
+
+	cutoff, err := time.Parse(cutoffTimeLayout, c.CutoffTime)
+	if err == nil && !local.Before(day.Add(time.Duration(cutoff.Hour())*time.Hour+time.Duration(cutoff.Minute())*time.Minute)) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return c.nextWorkingDay(day)
+}
+
+// AddWorkingDays returns the date n working days after day
+func (c *BusinessCalendar) AddWorkingDays(day time.Time, n int) time.Time {
+	day = startOfDay(day.In(c.Location()))
+	for ; n > 0; n-- {
+		day = c.nextWorkingDay(day.AddDate(0, 0, 1))
+	}
+	return day
+}
+
+// EstimateDelivery returns when an order placed at orderedAt ships and arrives, given
+// the carrier's transit time in working days
+func (c *BusinessCalendar) EstimateDelivery(orderedAt time.Time, minDays, maxDays int) *DeliveryEstimate {
+	if maxDays < minDays {
+		maxDays = minDays
+	}
+	shipsOn := c.DispatchDate(orderedAt)
+	return &DeliveryEstimate{
+		ShipsOn:          shipsOn.Format(calendarDateLayout),
+		EarliestDelivery: c.AddWorkingDays(shipsOn, minDays).Format(calendarDateLayout),
+		LatestDelivery:   c.AddWorkingDays(shipsOn, maxDays).Format(calendarDateLayout),
+	}
+}
+
+// nextWorkingDay returns day if it is a working day, otherwise the next one
+func (c *BusinessCalendar) nextWorkingDay(day time.Time) time.Time {
+	if len(c.WorkingDays) == 0 {
+		return day
+	}
+	// Holidays are single days, so a year ahead always reaches a working day
+	for i := 0; i < 366 && !c.IsWorkingDay(day); i++ {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// CalendarRepository defines persistence for the business calendar and its holidays
+type CalendarRepository interface {
+	// Get returns the calendar settings without holidays, or ErrCalendarNotFound
+	Get(ctx context.Context) (*BusinessCalendar, error)
+	Save(ctx context.Context, calendar *BusinessCalendar) error
+	// ListHolidays lists holidays by date
+	ListHolidays(ctx context.Context) ([]Holiday, error)
+	SaveHoliday(ctx context.Context, holiday Holiday) error
+	DeleteHoliday(ctx context.Context, date string) error
+}
+
+// CalendarService manages the business calendar used for delivery estimates and
+// working-day schedules
+type CalendarService struct {
+	repo CalendarRepository
+}
+
+// NewCalendarService creates a new CalendarService
+func NewCalendarService(repo CalendarRepository) *CalendarService {
+	return &CalendarService{repo: repo}
+}
+
+// Calendar returns the saved calendar with its holidays, or the default calendar if
+// none has been saved
+func (s *CalendarService) Calendar(ctx context.Context) (*BusinessCalendar, error) {
+	calendar, err := s.repo.Get(ctx)
+	if err == ErrCalendarNotFound {
+		calendar, err = DefaultBusinessCalendar(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.repo.ListHolidays(ctx)
+	if err != nil {
+		return nil, err
+	}
+	calendar.Holidays = holidays
+	return calendar, nil
+}
+
+// UpdateCalendar validates and saves the timezone, working days and cutoff time
+func (s *CalendarService) UpdateCalendar(ctx context.Context, timezone string, workingDays []time.Weekday, cutoffTime string) (*BusinessCalendar, error) {
+	days := make([]time.Weekday, 0, len(workingDays))
+	seen := make(map[time.Weekday]bool, len(workingDays))
+	for _, day := range workingDays {
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	calendar := &BusinessCalendar{
+		Timezone:    strings.TrimSpace(timezone),
+		WorkingDays: days,
+		CutoffTime:  strings.TrimSpace(cutoffTime),
+		UpdatedAt:   time.Now(),
+	}
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, calendar); err != nil {
+		return nil, err
+	}
+	return s.Calendar(ctx)
+}
+
+// AddHoliday closes the business on a date, replacing the name of an existing holiday
+func (s *CalendarService) AddHoliday(ctx context.Context, date, name string) (*Holiday, error) {
+	holiday := Holiday{Date: strings.TrimSpace(date), Name: strings.TrimSpace(name)}
+	if _, err := time.Parse(calendarDateLayout, holiday.Date); err != nil || holiday.Name == "" {
+		return nil, ErrInvalidHoliday
+	}
+	if err := s.repo.SaveHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// RemoveHoliday reopens the business on a date
+func (s *CalendarService) RemoveHoliday(ctx context.Context, date string) error {
+	return s.repo.DeleteHoliday(ctx, date)
+}
+
+// IsWorkingDay reports whether t falls on a working day. It lets the scheduler run
+// working-day tasks without depending on this package.
+func (s *CalendarService) IsWorkingDay(ctx context.Context, t time.Time) (bool, error) {
+	calendar, err := s.Calendar(ctx)
+	if err != nil {
+		return false, err
+	}
+	return calendar.IsWorkingDay(t), nil
+}
+
+// EstimateDelivery returns when an order placed at orderedAt ships and arrives, given
+// the carrier's transit time in working days
+func (s *CalendarService) EstimateDelivery(ctx context.Context, orderedAt time.Time, minDays, maxDays int) (*DeliveryEstimate, error) {
+	calendar, err := s.Calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.EstimateDelivery(orderedAt, minDays, maxDays), nil
+}
@@ -1,15 +1,26 @@
 package services
 
 import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
 	"github.com/devchuckcamp/gocommerce-api/internal/utils"
 	"github.com/devchuckcamp/gocommerce/cart"
 	"github.com/devchuckcamp/gocommerce/catalog"
 	"github.com/devchuckcamp/gocommerce/inventory"
+	"github.com/devchuckcamp/gocommerce/money"
 )
 
 // CartService holds the gocommerce cart service
 type CartService struct {
 	*cart.CartService
+	repo         cart.Repository
+	limits       *PurchaseLimitService
+	funnel       *FunnelService
+	rentals      *RentalService
+	appointments *AppointmentService
+	donations    *DonationService
+	wishlists    *WishlistService
 }
 
 // NewCartService creates a new CartService using gocommerce domain service
@@ -29,6 +40,7 @@ func NewCartService(
 
 	return &CartService{
 		CartService: svc,
+		repo:        cartRepo,
 	}
 }
 
@@ -37,3 +49,153 @@ func (s *CartService) WithPriceResolver(resolver cart.PriceResolver) *CartServic
 	s.CartService.WithPriceResolver(resolver)
 	return s
 }
+
+// WithPurchaseLimits rejects cart changes that take a product over its purchase limit
+func (s *CartService) WithPurchaseLimits(limits *PurchaseLimitService) *CartService {
+	s.limits = limits
+	return s
+}
+
+// WithFunnel counts carts getting their first item as entering the checkout funnel
+func (s *CartService) WithFunnel(funnel *FunnelService) *CartService {
+	s.funnel = funnel
+	return s
+}
+
+// WithRentals takes the rental period of items of rental products from their attributes,
+// checks the dates are free and prices the item for the days rented
+func (s *CartService) WithRentals(rentals *RentalService) *CartService {
+	s.rentals = rentals
+	return s
+}
+
+// WithAppointments takes the slot booked for items of appointment products from their
+// attributes and checks it is open with enough seats free
+func (s *CartService) WithAppointments(appointments *AppointmentService) *CartService {
+	s.appointments = appointments
+	return s
+}
+
+// WithDonations takes the amount chosen for items of donation products from their
+// attributes, checks it is within the product's limits and prices the item at it
+func (s *CartService) WithDonations(donations *DonationService) *CartService {
+	s.donations = donations
+	return s
+}
+
+// WithWishlists takes the registry item bought for from the attributes of items bought
+// from a public wishlist and checks the units are still wanted
+func (s *CartService) WithWishlists(wishlists *WishlistService) *CartService {
+	s.wishlists = wishlists
+	return s
+}
+
+// AddItem adds an item to the cart, checking the product's purchase limit and, for rental,
+// appointment and donation products, the rental period, appointment slot or amount first.
+// Items bought from a registry are checked against the units it still wants.
+func (s *CartService) AddItem(ctx context.Context, cartID string, req cart.AddItemRequest) (*cart.Cart, error) {
+	if (s.limits == nil && s.funnel == nil && s.rentals == nil && s.appointments == nil && s.donations == nil && s.wishlists == nil) || req.Quantity <= 0 {
+		return s.CartService.AddItem(ctx, cartID, req)
+	}
+
+	current, err := s.CartService.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if s.limits != nil {
+		quantity := CartQuantities(current)[req.ProductID] + req.Quantity
+		if err := s.limits.Check(ctx, current.UserID, map[string]int{req.ProductID: quantity}); err != nil {
+			return nil, err
+		}
+	}
+	var period *RentalPeriod
+	if s.rentals != nil {
+		if period, err = s.rentals.PrepareItem(ctx, current, &req); err != nil {
+			return nil, err
+		}
+	}
+	if s.appointments != nil {
+		if err := s.appointments.PrepareItem(ctx, current, &req); err != nil {
+			return nil, err
+		}
+	}
+	if s.wishlists != nil {
+		if err := s.wishlists.PrepareItem(ctx, current, &req); err != nil {
+			return nil, err
+		}
+	}
+	var donation *money.Money
+	if s.donations != nil {
+		if donation, err = s.donations.PrepareItem(ctx, current, &req); err != nil {
+			return nil, err
+		}
+	}
+	wasEmpty := len(current.Items) == 0
+
+	updated, err := s.CartService.AddItem(ctx, cartID, req)
+	if err == nil && period != nil {
+		err = s.priceItem(ctx, updated, req, func(price money.Money) (money.Money, error) {
+			return moneymath.MulInt(price, int64(period.Days()))
+		})
+	}
+	if err == nil && donation != nil {
+		err = s.priceItem(ctx, updated, req, func(money.Money) (money.Money, error) {
+			return *donation, nil
+		})
+	}
+	if err == nil && s.funnel != nil && wasEmpty {
+		s.funnel.Record(ctx, FunnelStageCartCreated, cartID)
+	}
+	return updated, err
+}
+
+// priceItem reprices the item just added, such as a rental at its daily price for the
+// days rented or a donation at the amount chosen
+func (s *CartService) priceItem(ctx context.Context, c *cart.Cart, req cart.AddItemRequest, reprice func(money.Money) (money.Money, error)) error {
+	item := cartLine(c, req.ProductID, req.VariantID)
+	if item == nil {
+		return nil
+	}
+	price, err := reprice(item.Price)
+	if err != nil {
+		return err
+	}
+	item.Price = price
+	return s.repo.Save(ctx, c)
+}
+
+// UpdateItemQuantity changes an item's quantity, checking the product's purchase limit
+// and, for rental, appointment and registry items, that enough units, seats or wanted units
+// are left when it goes up
+func (s *CartService) UpdateItemQuantity(ctx context.Context, cartID, itemID string, quantity int) (*cart.Cart, error) {
+	if s.limits != nil || s.rentals != nil || s.appointments != nil || s.wishlists != nil {
+		current, err := s.CartService.GetCart(ctx, cartID)
+		if err != nil {
+			return nil, err
+		}
+		if item := current.FindItem(itemID); item != nil && quantity > item.Quantity {
+			if s.limits != nil {
+				total := CartQuantities(current)[item.ProductID] - item.Quantity + quantity
+				if err := s.limits.Check(ctx, current.UserID, map[string]int{item.ProductID: total}); err != nil {
+					return nil, err
+				}
+			}
+			if s.rentals != nil {
+				if err := s.rentals.CheckItem(ctx, item, quantity); err != nil {
+					return nil, err
+				}
+			}
+			if s.appointments != nil {
+				if err := s.appointments.CheckItem(ctx, item, quantity); err != nil {
+					return nil, err
+				}
+			}
+			if s.wishlists != nil {
+				if err := s.wishlists.CheckItem(ctx, item, quantity); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return s.CartService.UpdateItemQuantity(ctx, cartID, itemID, quantity)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// Metadata is custom data a client attaches to a cart or cart item, such as a campaign ID
+// or personalization text, and which is carried through to the order
+type Metadata map[string]string
+
+// MetadataPolicy limits which metadata keys clients may set and how much they may store
+type MetadataPolicy struct {
+	AllowedKeys    []string // empty accepts no metadata
+	MaxKeys        int      // per cart or item
+	MaxValueLength int      // bytes per value
+}
+
+// Validate checks metadata against the policy. Empty metadata is always valid.
+func (p MetadataPolicy) Validate(metadata Metadata) error {
+	if len(metadata) > p.MaxKeys {
+		return fmt.Errorf("%w: at most %d keys are allowed", ErrInvalidMetadata, p.MaxKeys)
+	}
+	for key, value := range metadata {
+		if !p.allows(key) {
+			return fmt.Errorf("%w: key %q is not allowed", ErrInvalidMetadata, key)
+		}
+		if len(value) > p.MaxValueLength {
+			return fmt.Errorf("%w: value of %q is longer than %d bytes", ErrInvalidMetadata, key, p.MaxValueLength)
+		}
+	}
+	return nil
+}
+
+func (p MetadataPolicy) allows(key string) bool {
+	for _, allowed := range p.AllowedKeys {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CartMetadata is the metadata of a cart and its items. On an order, items are keyed by
+// order item ID.
+type CartMetadata struct {
+	Metadata Metadata            `json:"metadata"`
+	Items    map[string]Metadata `json:"items"`
+}
+
+// IsEmpty reports whether neither the cart nor any item has metadata
+func (m *CartMetadata) IsEmpty() bool {
+	return len(m.Metadata) == 0 && len(m.Items) == 0
+}
+
+// CartMetadataRepository defines persistence for cart and order metadata
+type CartMetadataRepository interface {
+	// FindCartMetadata returns a cart's metadata and that of its items that have any
+	FindCartMetadata(ctx context.Context, cartID string) (*CartMetadata, error)
+	// SetCartMetadata replaces a cart's metadata; empty metadata clears it
+	SetCartMetadata(ctx context.Context, cartID string, metadata Metadata) error
+	// SetItemMetadata replaces a cart item's metadata; empty metadata clears it
+	SetItemMetadata(ctx context.Context, cartID, itemID string, metadata Metadata) error
+	FindOrderMetadata(ctx context.Context, orderID string) (*CartMetadata, error)
+	SaveOrderMetadata(ctx context.Context, orderID string, metadata *CartMetadata) error
+}
+
+// CartMetadataService stores validated client metadata on carts and cart items and copies
+// it to the order placed from the cart
+type CartMetadataService struct {
+	repo   CartMetadataRepository
+	policy MetadataPolicy
+}
+
+// NewCartMetadataService creates a new CartMetadataService
+func NewCartMetadataService(repo CartMetadataRepository, policy MetadataPolicy) *CartMetadataService {
+	return &CartMetadataService{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+// Validate checks metadata against the policy without storing it
+func (s *CartMetadataService) Validate(metadata Metadata) error {
+	return s.policy.Validate(metadata)
+}
+
+// GetCartMetadata returns the metadata of a cart and its items
+func (s *CartMetadataService) GetCartMetadata(ctx context.Context, cartID string) (*CartMetadata, error) {
+	return s.repo.FindCartMetadata(ctx, cartID)
+}
+
+// SetCartMetadata replaces a cart's metadata
+func (s *CartMetadataService) SetCartMetadata(ctx context.Context, cartID string, metadata Metadata) error {
+	if err := s.policy.Validate(metadata); err != nil {
+		return err
+	}
+	return s.repo.SetCartMetadata(ctx, cartID, metadata)
+}
+
+// SetItemMetadata replaces the metadata of an item in the cart
+func (s *CartMetadataService) SetItemMetadata(ctx context.Context, c *cart.Cart, itemID string, metadata Metadata) error {
+	if c.FindItem(itemID) == nil {
+		return cart.ErrItemNotFound
+	}
+	if err := s.policy.Validate(metadata); err != nil {
+		return err
+	}
+	return s.repo.SetItemMetadata(ctx, c.ID, itemID, metadata)
+}
+
+// GetOrderMetadata returns the metadata an order was placed with
+func (s *CartMetadataService) GetOrderMetadata(ctx context.Context, orderID string) (*CartMetadata, error) {
+	return s.repo.FindOrderMetadata(ctx, orderID)
+}
+
+// CopyToOrder records a cart's metadata on the order placed from it. Order items are
+// created in cart item order, so item metadata moves to the order item at the same position.
+func (s *CartMetadataService) CopyToOrder(ctx context.Context, c *cart.Cart, order *orders.Order) error {
+	source, err := s.repo.FindCartMetadata(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if source.IsEmpty() {
+		return nil
+	}
+
+	copied := &CartMetadata{Metadata: source.Metadata, Items: make(map[string]Metadata)}
+	for i, item := range c.Items {
+		if metadata, ok := source.Items[item.ID]; ok && i < len(order.Items) {
+			copied.Items[order.Items[i].ID] = metadata
+		}
+	}
+	return s.repo.SaveOrderMetadata(ctx, order.ID, copied)
+}
+
+// copyToOrder copies metadata for OrderService; the order is already placed, so a failure
+// is logged rather than failing checkout
+func (s *CartMetadataService) copyToOrder(ctx context.Context, c *cart.Cart, order *orders.Order) {
+	if err := s.CopyToOrder(ctx, c, order); err != nil {
+		log.Printf("Failed to copy cart %s metadata to order %s: %v", c.ID, order.ID, err)
+	}
+}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/devchuckcamp/gocommerce/catalog"
@@ -15,10 +19,69 @@ type SalePriceResolver interface {
 	FindEffectivePrices(ctx context.Context, productIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error)
 }
 
+// VariantSalePriceResolver is implemented by sale price resolvers that can resolve the
+// effective prices of several of a product's variants in one lookup, keyed by variant ID
+type VariantSalePriceResolver interface {
+	FindEffectiveVariantPrices(ctx context.Context, productID string, variantIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error)
+}
+
 // ProductResponse wraps catalog.Product with sale price information
 type ProductResponse struct {
 	*catalog.Product
 	SalePrice *money.Money `json:"SalePrice,omitempty"`
+	// ImageRenditions holds resized URLs for each of the product's Images, by preset
+	ImageRenditions []ImageRenditions `json:"ImageRenditions,omitempty"`
+	// Media is the product's gallery of images, videos and 3D models, or its Images as
+	// image media when no gallery has been set
+	Media []*ProductMedia `json:"Media,omitempty"`
+}
+
+// ProductSortFields are the fields product listings can be sorted by with ?sort=
+var ProductSortFields = []string{"name", "price", "created_at", "updated_at"}
+
+// ProductFields maps the product fields a client can select with ?fields= to their JSON keys
+var ProductFields = map[string]string{
+	"id":               "ID",
+	"sku":              "SKU",
+	"name":             "Name",
+	"description":      "Description",
+	"brand_id":         "BrandID",
+	"category_id":      "CategoryID",
+	"base_price":       "BasePrice",
+	"sale_price":       "SalePrice",
+	"status":           "Status",
+	"images":           "Images",
+	"image_renditions": "ImageRenditions",
+	"media":            "Media",
+	"attributes":       "Attributes",
+	"created_at":       "CreatedAt",
+	"updated_at":       "UpdatedAt",
+}
+
+// PriceScheduleRepository finds products whose prices start or end within a time range
+type PriceScheduleRepository interface {
+	FindProductsWithPriceChanges(ctx context.Context, from, to time.Time) ([]string, error)
+}
+
+// ProductFieldRepository is implemented by product repositories that can load only the
+// columns behind a set of ProductFields instead of the whole row
+type ProductFieldRepository interface {
+	SearchFields(ctx context.Context, query string, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error)
+	FindByCategoryFields(ctx context.Context, categoryID string, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error)
+}
+
+// ProductQuery is a keyword search expanded by the search rules
+type ProductQuery struct {
+	Keywords   []string // a product matches when its name or description contains any of them
+	BoostIDs   []string // matching products listed before the other matches
+	ExcludeIDs []string // products left out, such as pinned products listed separately
+}
+
+// ProductQueryRepository is implemented by product repositories that can run a
+// ProductQuery as one query, so expanded and boosted searches page correctly. No fields
+// selects every field.
+type ProductQueryRepository interface {
+	SearchQuery(ctx context.Context, query ProductQuery, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error)
 }
 
 // CatalogService provides additional catalog operations
@@ -28,6 +91,23 @@ type CatalogService struct {
 	categoryRepo      catalog.CategoryRepository
 	brandRepo         catalog.BrandRepository
 	salePriceResolver SalePriceResolver
+	productCache      *ProductCache
+	priceSchedule     PriceScheduleRepository
+	categoryCache     *listCache[*catalog.Category]
+	brandCache        *listCache[*catalog.Brand]
+	currency          *CurrencyService
+	spelling          *SpellingService
+	spellingOff       atomic.Bool
+	searchRules       *SearchRuleService
+	images            *ImageService
+	media             *ProductMediaService
+}
+
+// CatalogCacheStats reports each catalog cache; a nil entry means that cache is off
+type CatalogCacheStats struct {
+	Products   *CacheStats `json:"products"`
+	Categories *CacheStats `json:"categories"`
+	Brands     *CacheStats `json:"brands"`
 }
 
 // NewCatalogService creates a new CatalogService
@@ -45,20 +125,178 @@ func NewCatalogService(
 	}
 }
 
+// WithSpelling corrects the spelling of keyword searches that find nothing
+func (s *CatalogService) WithSpelling(spelling *SpellingService) *CatalogService {
+	s.spelling = spelling
+	return s
+}
+
+// SetSpellCorrection turns correcting searches with WithSpelling's service on or off
+func (s *CatalogService) SetSpellCorrection(on bool) {
+	s.spellingOff.Store(!on)
+}
+
+// WithSearchRules applies synonyms, pinned and boosted products to keyword searches
+func (s *CatalogService) WithSearchRules(rules *SearchRuleService) *CatalogService {
+	s.searchRules = rules
+	return s
+}
+
+// WithImages adds resized CDN renditions of each product image to product responses
+func (s *CatalogService) WithImages(images *ImageService) *CatalogService {
+	s.images = images
+	return s
+}
+
+// WithMedia adds each product's gallery of images, videos and 3D models to product responses
+func (s *CatalogService) WithMedia(media *ProductMediaService) *CatalogService {
+	s.media = media
+	return s
+}
+
 // WithSalePriceResolver attaches the sale price resolver for sale price resolution
 func (s *CatalogService) WithSalePriceResolver(resolver SalePriceResolver) *CatalogService {
 	s.salePriceResolver = resolver
 	return s
 }
 
+// WithProductCache serves product detail through a read-through cache. Pass the price
+// schedule so cached products are dropped when one of their prices starts or ends.
+func (s *CatalogService) WithProductCache(cache *ProductCache, priceSchedule PriceScheduleRepository) *CatalogService {
+	s.productCache = cache
+	s.priceSchedule = priceSchedule
+	return s
+}
+
+// WithListCache caches the full category and brand lists, which every storefront page
+// loads to build its navigation
+func (s *CatalogService) WithListCache(ttl time.Duration) *CatalogService {
+	s.categoryCache = newListCache[*catalog.Category](ttl)
+	s.brandCache = newListCache[*catalog.Brand](ttl)
+	return s
+}
+
+// WithCurrencyService lets shoppers see product prices in another currency with
+// InCurrency, rounded by the currency's price policy
+func (s *CatalogService) WithCurrencyService(currency *CurrencyService) *CatalogService {
+	s.currency = currency
+	return s
+}
+
+// InCurrency returns copies of the products with their base and sale prices converted
+// into a display currency. Without a currency service only the products' own currency
+// can be asked for.
+func (s *CatalogService) InCurrency(products []*ProductResponse, currency string) ([]*ProductResponse, error) {
+	converted := make([]*ProductResponse, len(products))
+	for i, product := range products {
+		if product.Product == nil {
+			converted[i] = product
+			continue
+		}
+		copied := *product.Product
+		response := &ProductResponse{Product: &copied, ImageRenditions: product.ImageRenditions, Media: product.Media}
+		var err error
+		if response.BasePrice, err = s.convertPrice(product.BasePrice, currency); err != nil {
+			return nil, err
+		}
+		if product.SalePrice != nil {
+			salePrice, err := s.convertPrice(*product.SalePrice, currency)
+			if err != nil {
+				return nil, err
+			}
+			response.SalePrice = &salePrice
+		}
+		converted[i] = response
+	}
+	return converted, nil
+}
+
+func (s *CatalogService) convertPrice(price money.Money, currency string) (money.Money, error) {
+	// Products loaded with a sparse fieldset may have no price at all
+	if price.Currency == "" {
+		return price, nil
+	}
+	if s.currency == nil {
+		if !strings.EqualFold(price.Currency, currency) {
+			return money.Money{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, price.Currency, currency)
+		}
+		return price, nil
+	}
+	return s.currency.ConvertPrice(price, currency)
+}
+
 // GetProduct retrieves a product by ID with sale price
 func (s *CatalogService) GetProduct(ctx context.Context, id string) (*ProductResponse, error) {
+	if s.productCache != nil {
+		return s.productCache.Get(ctx, id, func(ctx context.Context) (*ProductResponse, error) {
+			return s.loadProduct(ctx, id)
+		})
+	}
+	return s.loadProduct(ctx, id)
+}
+
+// InvalidateProducts drops products from the product cache after they change
+func (s *CatalogService) InvalidateProducts(ids ...string) {
+	if s.productCache != nil {
+		s.productCache.Invalidate(ids...)
+	}
+}
+
+// FlushProductCache drops every cached product
+func (s *CatalogService) FlushProductCache() {
+	if s.productCache != nil {
+		s.productCache.Flush()
+	}
+}
+
+// FlushCaches drops every cached product, category and brand
+func (s *CatalogService) FlushCaches() {
+	s.FlushProductCache()
+	if s.categoryCache != nil {
+		s.categoryCache.flush()
+		s.brandCache.flush()
+	}
+}
+
+// CacheStats reports the size and hit rate of each catalog cache
+func (s *CatalogService) CacheStats() CatalogCacheStats {
+	var stats CatalogCacheStats
+	if s.productCache != nil {
+		products := s.productCache.Stats()
+		stats.Products = &products
+	}
+	if s.categoryCache != nil {
+		categories, brands := s.categoryCache.stats(), s.brandCache.stats()
+		stats.Categories = &categories
+		stats.Brands = &brands
+	}
+	return stats
+}
+
+// InvalidateScheduledPrices drops cached products whose price started or ended between from
+// and to, since a scheduled sale changes the price without anyone editing the product
+func (s *CatalogService) InvalidateScheduledPrices(ctx context.Context, from, to time.Time) (int, error) {
+	if s.productCache == nil || s.priceSchedule == nil {
+		return 0, nil
+	}
+	ids, err := s.priceSchedule.FindProductsWithPriceChanges(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		s.productCache.Invalidate(ids...)
+	}
+	return len(ids), nil
+}
+
+func (s *CatalogService) loadProduct(ctx context.Context, id string) (*ProductResponse, error) {
 	product, err := s.productRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ProductResponse{Product: product}
+	response := s.newResponse(product)
+	s.attachMedia(ctx, []*ProductResponse{response})
 
 	// Fetch sale price if resolver is available
 	if s.salePriceResolver != nil {
@@ -82,31 +320,182 @@ func (s *CatalogService) ListProducts(ctx context.Context, filter catalog.Produc
 
 // SearchProducts searches products by keyword with sale prices
 func (s *CatalogService) SearchProducts(ctx context.Context, keyword string, filter catalog.ProductFilter) ([]*ProductResponse, error) {
-	products, err := s.productRepo.Search(ctx, keyword, filter)
+	return s.SearchProductFields(ctx, keyword, filter, nil)
+}
+
+// SearchProductFields searches products by keyword, loading only the selected ProductFields
+// when the repository supports it. No fields selects every field. With search rules, the
+// keyword's synonyms are searched too and its pinned and boosted products listed first.
+func (s *CatalogService) SearchProductFields(ctx context.Context, keyword string, filter catalog.ProductFilter, fields []string) ([]*ProductResponse, error) {
+	if s.searchRules != nil && strings.TrimSpace(keyword) != "" {
+		plan, err := s.searchRules.Plan(ctx, keyword)
+		if err != nil {
+			log.Printf("Failed to load search rules for %q: %v", keyword, err)
+		} else if plan.merchandised() {
+			return s.merchandisedSearch(ctx, plan, filter, fields)
+		}
+	}
+
+	var products []*catalog.Product
+	var err error
+	if repo, ok := s.productRepo.(ProductFieldRepository); ok && len(fields) > 0 {
+		products, err = repo.SearchFields(ctx, keyword, filter, fields)
+	} else {
+		products, err = s.productRepo.Search(ctx, keyword, filter)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return s.enrichWithSalePrices(ctx, products)
+	return s.enrichSelected(ctx, products, fields)
+}
+
+// merchandisedSearch pages through the plan's pinned products followed by the products
+// matching any of its keywords, boosted ones first. A pinned product that cannot be loaded
+// or does not pass the filter's status is skipped.
+func (s *CatalogService) merchandisedSearch(ctx context.Context, plan *SearchPlan, filter catalog.ProductFilter, fields []string) ([]*ProductResponse, error) {
+	pinned := make([]*catalog.Product, 0, len(plan.Pinned))
+	pinnedIDs := make([]string, 0, len(plan.Pinned))
+	for _, id := range plan.Pinned {
+		product, err := s.productRepo.FindByID(ctx, id)
+		if err != nil {
+			log.Printf("Failed to load pinned product %s: %v", id, err)
+			continue
+		}
+		if filter.Status != nil && product.Status != *filter.Status {
+			continue
+		}
+		pinned = append(pinned, product)
+		pinnedIDs = append(pinnedIDs, id)
+	}
+
+	page := []*catalog.Product{}
+	if filter.Offset < len(pinned) {
+		end := len(pinned)
+		if filter.Limit > 0 && filter.Offset+filter.Limit < end {
+			end = filter.Offset + filter.Limit
+		}
+		page = append(page, pinned[filter.Offset:end]...)
+	}
+
+	matchFilter := filter
+	matchFilter.Offset = max(filter.Offset-len(pinned), 0)
+	if filter.Limit > 0 {
+		matchFilter.Limit = filter.Limit - len(page)
+	}
+	if filter.Limit == 0 || matchFilter.Limit > 0 {
+		query := ProductQuery{Keywords: plan.Keywords, BoostIDs: plan.Boosted, ExcludeIDs: pinnedIDs}
+		matches, err := s.queryProducts(ctx, query, matchFilter, fields)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, matches...)
+	}
+
+	return s.enrichSelected(ctx, page, fields)
+}
+
+// queryProducts runs a ProductQuery in the repository when it supports it. Otherwise each
+// keyword is searched on its own and the results are merged, boosted and paged in memory.
+func (s *CatalogService) queryProducts(ctx context.Context, query ProductQuery, filter catalog.ProductFilter, fields []string) ([]*catalog.Product, error) {
+	if repo, ok := s.productRepo.(ProductQueryRepository); ok {
+		return repo.SearchQuery(ctx, query, filter, fields)
+	}
+
+	unpaged := catalog.ProductFilter{Status: filter.Status}
+	seen := make(map[string]bool)
+	for _, id := range query.ExcludeIDs {
+		seen[id] = true
+	}
+	var boosted, others []*catalog.Product
+	for _, keyword := range query.Keywords {
+		products, err := s.productRepo.Search(ctx, keyword, unpaged)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			if seen[product.ID] {
+				continue
+			}
+			seen[product.ID] = true
+			if containsField(query.BoostIDs, product.ID) {
+				boosted = append(boosted, product)
+			} else {
+				others = append(others, product)
+			}
+		}
+	}
+
+	matches := append(boosted, others...)
+	if filter.Offset >= len(matches) {
+		return []*catalog.Product{}, nil
+	}
+	matches = matches[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matches) {
+		matches = matches[:filter.Limit]
+	}
+	return matches, nil
+}
+
+// CorrectedSearch runs a keyword search that found nothing again with its spelling
+// corrected, returning the corrected keyword and its results. The keyword is "" when
+// spelling correction is off, nothing needed correcting or the correction found nothing
+// either. A failed correction is logged and treated as no correction.
+func (s *CatalogService) CorrectedSearch(ctx context.Context, keyword string, filter catalog.ProductFilter, fields []string) (string, []*ProductResponse, error) {
+	if s.spelling == nil || s.spellingOff.Load() {
+		return "", nil, nil
+	}
+	corrected, ok, err := s.spelling.Correct(ctx, keyword)
+	if err != nil {
+		log.Printf("Failed to correct search %q: %v", keyword, err)
+		return "", nil, nil
+	}
+	if !ok {
+		return "", nil, nil
+	}
+
+	products, err := s.SearchProductFields(ctx, corrected, filter, fields)
+	if err != nil || len(products) == 0 {
+		return "", nil, err
+	}
+	return corrected, products, nil
 }
 
 // GetProductsByCategory retrieves products in a category with sale prices
 func (s *CatalogService) GetProductsByCategory(ctx context.Context, categoryID string, filter catalog.ProductFilter) ([]*ProductResponse, error) {
-	products, err := s.productRepo.FindByCategory(ctx, categoryID, filter)
+	return s.GetProductsByCategoryFields(ctx, categoryID, filter, nil)
+}
+
+// GetProductsByCategoryFields retrieves products in a category, loading only the selected
+// ProductFields when the repository supports it. No fields selects every field.
+func (s *CatalogService) GetProductsByCategoryFields(ctx context.Context, categoryID string, filter catalog.ProductFilter, fields []string) ([]*ProductResponse, error) {
+	var products []*catalog.Product
+	var err error
+	if repo, ok := s.productRepo.(ProductFieldRepository); ok && len(fields) > 0 {
+		products, err = repo.FindByCategoryFields(ctx, categoryID, filter, fields)
+	} else {
+		products, err = s.productRepo.FindByCategory(ctx, categoryID, filter)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return s.enrichWithSalePrices(ctx, products)
+	return s.enrichSelected(ctx, products, fields)
 }
 
 // GetCategories retrieves all categories
 func (s *CatalogService) GetCategories(ctx context.Context) ([]*catalog.Category, error) {
+	if s.categoryCache != nil {
+		return s.categoryCache.get(ctx, s.categoryRepo.FindAll)
+	}
 	return s.categoryRepo.FindAll(ctx)
 }
 
 // GetBrands retrieves all brands
 func (s *CatalogService) GetBrands(ctx context.Context) ([]*catalog.Brand, error) {
+	if s.brandCache != nil {
+		return s.brandCache.get(ctx, s.brandRepo.FindAll)
+	}
 	return s.brandRepo.FindAll(ctx)
 }
 
@@ -120,15 +509,73 @@ func (s *CatalogService) CountProducts(ctx context.Context, filter catalog.Produ
 	return 0, nil
 }
 
-// enrichWithSalePrices batch-fetches sale prices for products and returns ProductResponses
+// enrichSelected resolves sale prices and media only when the client selected them
+func (s *CatalogService) enrichSelected(ctx context.Context, products []*catalog.Product, fields []string) ([]*ProductResponse, error) {
+	if len(fields) > 0 && !containsField(fields, "sale_price") {
+		responses := make([]*ProductResponse, len(products))
+		for i, product := range products {
+			responses[i] = s.newResponse(product)
+		}
+		if containsField(fields, "media") {
+			s.attachMedia(ctx, responses)
+		}
+		return responses, nil
+	}
+	return s.enrichWithSalePrices(ctx, products)
+}
+
+// newResponse wraps a product for a response, with its image renditions when enabled
+func (s *CatalogService) newResponse(product *catalog.Product) *ProductResponse {
+	response := &ProductResponse{Product: product}
+	if s.images != nil {
+		response.ImageRenditions = s.images.Renditions(product.Images)
+	}
+	return response
+}
+
+// attachMedia loads the products' galleries in one lookup. Products without a gallery, or
+// all of them when the lookup fails, list their images as image media.
+func (s *CatalogService) attachMedia(ctx context.Context, responses []*ProductResponse) {
+	if s.media == nil || len(responses) == 0 {
+		return
+	}
+	productIDs := make([]string, len(responses))
+	for i, response := range responses {
+		productIDs[i] = response.ID
+	}
+	media, err := s.media.FindMedia(ctx, productIDs)
+	if err != nil {
+		log.Printf("Failed to load media for %d products: %v", len(productIDs), err)
+	}
+	for _, response := range responses {
+		if gallery, ok := media[response.ID]; ok {
+			response.Media = gallery
+		} else {
+			response.Media = ImageMedia(response.Images)
+		}
+	}
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// enrichWithSalePrices batch-fetches sale prices and media for products and returns
+// ProductResponses
 func (s *CatalogService) enrichWithSalePrices(ctx context.Context, products []*catalog.Product) ([]*ProductResponse, error) {
 	responses := make([]*ProductResponse, len(products))
+	for i, product := range products {
+		responses[i] = s.newResponse(product)
+	}
+	s.attachMedia(ctx, responses)
 
 	// If no resolver, return products without sale prices
 	if s.salePriceResolver == nil {
-		for i, product := range products {
-			responses[i] = &ProductResponse{Product: product}
-		}
 		return responses, nil
 	}
 
@@ -138,23 +585,19 @@ func (s *CatalogService) enrichWithSalePrices(ctx context.Context, products []*c
 		productIDs[i] = product.ID
 	}
 
-	// Batch fetch sale prices
+	// Batch fetch sale prices in one lookup for the whole page
 	salePrices, err := s.salePriceResolver.FindEffectivePrices(ctx, productIDs, time.Now())
 	if err != nil {
 		// On error, return products without sale prices
-		for i, product := range products {
-			responses[i] = &ProductResponse{Product: product}
-		}
+		log.Printf("Failed to resolve sale prices for %d products: %v", len(productIDs), err)
 		return responses, nil
 	}
 
-	// Map products to responses with sale prices
+	// Add sale prices to the responses
 	for i, product := range products {
-		response := &ProductResponse{Product: product}
 		if salePrice, exists := salePrices[product.ID]; exists {
-			response.SalePrice = &salePrice.Price
+			responses[i].SalePrice = &salePrice.Price
 		}
-		responses[i] = response
 	}
 
 	return responses, nil
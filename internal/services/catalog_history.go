@@ -0,0 +1,484 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrCatalogVersionNotFound = errors.New("catalog version not found")
+	ErrNothingToRevert        = errors.New("the product has not changed since this version")
+)
+
+// CatalogEntityType is the kind of catalog record a version describes
+type CatalogEntityType string
+
+const (
+	CatalogEntityProduct CatalogEntityType = "product"
+	CatalogEntityVariant CatalogEntityType = "variant"
+)
+
+// CatalogChangeAction is what happened to the record in a version
+type CatalogChangeAction string
+
+const (
+	CatalogChangeCreated  CatalogChangeAction = "created"
+	CatalogChangeUpdated  CatalogChangeAction = "updated"
+	CatalogChangeDeleted  CatalogChangeAction = "deleted"
+	CatalogChangeReverted CatalogChangeAction = "reverted"
+)
+
+// FieldChange is one field's value before and after a change
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// CatalogVersion records one change to a product or one of its variants. Versions are
+// numbered per product, so a product's history covers its variants too. Before and After
+// are snapshots of the record; Before is null when it was created and After when deleted.
+type CatalogVersion struct {
+	ID         string                 `json:"id"`
+	ProductID  string                 `json:"product_id"`
+	Version    int                    `json:"version"`
+	EntityType CatalogEntityType      `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Action     CatalogChangeAction    `json:"action"`
+	ChangedBy  string                 `json:"changed_by,omitempty"`
+	Changes    map[string]FieldChange `json:"changes"`
+	Before     json.RawMessage        `json:"before"`
+	After      json.RawMessage        `json:"after"`
+	RevertedTo int                    `json:"reverted_to,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// ProductSnapshot is the versioned state of a product
+type ProductSnapshot struct {
+	ID          string            `json:"id"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	BrandID     string            `json:"brand_id"`
+	CategoryID  string            `json:"category_id"`
+	BasePrice   int64             `json:"base_price"`
+	Currency    string            `json:"currency"`
+	Status      string            `json:"status"`
+	Images      []string          `json:"images"`
+	Attributes  map[string]string `json:"attributes"`
+}
+
+// VariantSnapshot is the versioned state of a variant
+type VariantSnapshot struct {
+	ID          string            `json:"id"`
+	ProductID   string            `json:"product_id"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Price       int64             `json:"price"`
+	Currency    string            `json:"currency"`
+	Attributes  map[string]string `json:"attributes"`
+	Images      []string          `json:"images"`
+	IsAvailable bool              `json:"is_available"`
+}
+
+// CatalogVersionRepository defines persistence for catalog versions
+type CatalogVersionRepository interface {
+	// Append stores the version under the product's next version number, which it sets
+	Append(ctx context.Context, version *CatalogVersion) error
+	// ListByProduct lists a product's versions, newest first
+	ListByProduct(ctx context.Context, productID string, limit, offset int) ([]*CatalogVersion, error)
+	CountByProduct(ctx context.Context, productID string) (int64, error)
+	FindByVersion(ctx context.Context, productID string, version int) (*CatalogVersion, error)
+	// ListAfter lists a product's versions newer than version, oldest first
+	ListAfter(ctx context.Context, productID string, version int) ([]*CatalogVersion, error)
+}
+
+type catalogActorKey struct{}
+
+// WithCatalogActor attributes catalog changes made with ctx to a user
+func WithCatalogActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, catalogActorKey{}, userID)
+}
+
+func catalogActor(ctx context.Context) string {
+	actor, _ := ctx.Value(catalogActorKey{}).(string)
+	return actor
+}
+
+type catalogRevertKey struct{}
+
+// CatalogHistoryService records every product and variant change as a version and can
+// put a product back the way it was at an earlier version
+type CatalogHistoryService struct {
+	repo       CatalogVersionRepository
+	products   catalog.ProductRepository
+	variants   catalog.VariantRepository
+	onReverted func(productIDs ...string)
+}
+
+// NewCatalogHistoryService creates a CatalogHistoryService. products and variants are the
+// underlying repositories; write through Products and Variants so changes are recorded.
+func NewCatalogHistoryService(repo CatalogVersionRepository, products catalog.ProductRepository, variants catalog.VariantRepository) *CatalogHistoryService {
+	return &CatalogHistoryService{repo: repo, products: products, variants: variants}
+}
+
+// WithRevertHook calls fn with the product after a revert, e.g. to drop it from caches
+func (s *CatalogHistoryService) WithRevertHook(fn func(productIDs ...string)) *CatalogHistoryService {
+	s.onReverted = fn
+	return s
+}
+
+// Products returns the product repository with every save and delete recorded
+func (s *CatalogHistoryService) Products() catalog.ProductRepository {
+	return &versionedProductRepository{ProductRepository: s.products, history: s}
+}
+
+// Variants returns the variant repository with every save and delete recorded
+func (s *CatalogHistoryService) Variants() catalog.VariantRepository {
+	return &versionedVariantRepository{VariantRepository: s.variants, history: s}
+}
+
+// ListHistory lists a product's versions, newest first
+func (s *CatalogHistoryService) ListHistory(ctx context.Context, productID string, limit, offset int) ([]*CatalogVersion, error) {
+	return s.repo.ListByProduct(ctx, productID, limit, offset)
+}
+
+// CountHistory counts a product's versions
+func (s *CatalogHistoryService) CountHistory(ctx context.Context, productID string) (int64, error) {
+	return s.repo.CountByProduct(ctx, productID)
+}
+
+// GetVersion returns one version of a product
+func (s *CatalogHistoryService) GetVersion(ctx context.Context, productID string, version int) (*CatalogVersion, error) {
+	return s.repo.FindByVersion(ctx, productID, version)
+}
+
+// Revert puts the product and its variants back the way they were right after version,
+// undoing every later change: records changed since are restored, records created since
+// are deleted and records deleted since are recreated. Each restored record is recorded
+// as a new "reverted" version, so a revert can itself be reverted.
+func (s *CatalogHistoryService) Revert(ctx context.Context, productID string, version int, changedBy string) ([]*CatalogVersion, error) {
+	if _, err := s.repo.FindByVersion(ctx, productID, version); err != nil {
+		return nil, err
+	}
+	later, err := s.repo.ListAfter(ctx, productID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// The state to restore is each record's Before in the first change after version.
+	// Variants are restored before the product is deleted, and after it is recreated.
+	type target struct {
+		entityType CatalogEntityType
+		entityID   string
+		state      json.RawMessage
+	}
+	var targets []target
+	seen := make(map[string]bool)
+	for _, v := range later {
+		key := string(v.EntityType) + ":" + v.EntityID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		t := target{entityType: v.EntityType, entityID: v.EntityID, state: v.Before}
+		if v.EntityType == CatalogEntityProduct {
+			targets = append([]target{t}, targets...)
+		} else {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, ErrNothingToRevert
+	}
+	if product := targets[0]; product.entityType == CatalogEntityProduct && isNullSnapshot(product.state) {
+		targets = append(targets[1:], product)
+	}
+
+	ctx = context.WithValue(WithCatalogActor(ctx, changedBy), catalogRevertKey{}, version)
+	var reverted []*CatalogVersion
+	for _, t := range targets {
+		var recorded *CatalogVersion
+		var err error
+		if t.entityType == CatalogEntityProduct {
+			recorded, err = s.restoreProduct(ctx, t.entityID, t.state)
+		} else {
+			recorded, err = s.restoreVariant(ctx, t.entityID, t.state)
+		}
+		if err != nil {
+			return reverted, err
+		}
+		if recorded != nil {
+			reverted = append(reverted, recorded)
+		}
+	}
+
+	if s.onReverted != nil {
+		s.onReverted(productID)
+	}
+	return reverted, nil
+}
+
+func (s *CatalogHistoryService) restoreProduct(ctx context.Context, id string, state json.RawMessage) (*CatalogVersion, error) {
+	repo := &versionedProductRepository{ProductRepository: s.products, history: s}
+	if isNullSnapshot(state) {
+		if _, err := s.products.FindByID(ctx, id); err != nil {
+			return nil, nil
+		}
+		return repo.delete(ctx, id)
+	}
+
+	var snapshot ProductSnapshot
+	if err := json.Unmarshal(state, &snapshot); err != nil {
+		return nil, err
+	}
+	product := &catalog.Product{CreatedAt: time.Now()}
+	if existing, err := s.products.FindByID(ctx, id); err == nil {
+		product.CreatedAt = existing.CreatedAt
+	}
+	snapshot.applyTo(product)
+	product.UpdatedAt = time.Now()
+	return repo.save(ctx, product)
+}
+
+func (s *CatalogHistoryService) restoreVariant(ctx context.Context, id string, state json.RawMessage) (*CatalogVersion, error) {
+	repo := &versionedVariantRepository{VariantRepository: s.variants, history: s}
+	if isNullSnapshot(state) {
+		if _, err := s.variants.FindByID(ctx, id); err != nil {
+			return nil, nil
+		}
+		return repo.delete(ctx, id)
+	}
+
+	var snapshot VariantSnapshot
+	if err := json.Unmarshal(state, &snapshot); err != nil {
+		return nil, err
+	}
+	variant := &catalog.Variant{CreatedAt: time.Now()}
+	if existing, err := s.variants.FindByID(ctx, id); err == nil {
+		variant.CreatedAt = existing.CreatedAt
+	}
+	snapshot.applyTo(variant)
+	variant.UpdatedAt = time.Now()
+	return repo.save(ctx, variant)
+}
+
+// record appends a version for a change from before to after, either of which may be nil.
+// Saves that change nothing are not recorded.
+func (s *CatalogHistoryService) record(ctx context.Context, productID string, entityType CatalogEntityType, entityID string, before, after interface{}) (*CatalogVersion, error) {
+	beforeJSON, beforeFields, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, afterFields, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffFields(beforeFields, afterFields)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	action := CatalogChangeUpdated
+	switch {
+	case beforeFields == nil:
+		action = CatalogChangeCreated
+	case afterFields == nil:
+		action = CatalogChangeDeleted
+	}
+	version := &CatalogVersion{
+		ID:         utils.GenerateID(),
+		ProductID:  productID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ChangedBy:  catalogActor(ctx),
+		Changes:    changes,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  time.Now(),
+	}
+	if revertedTo, ok := ctx.Value(catalogRevertKey{}).(int); ok {
+		version.Action = CatalogChangeReverted
+		version.RevertedTo = revertedTo
+	}
+
+	if err := s.repo.Append(ctx, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// snapshotFields encodes a snapshot, returning null and no fields for a nil one
+func snapshotFields(snapshot interface{}) (json.RawMessage, map[string]interface{}, error) {
+	if snapshot == nil || reflect.ValueOf(snapshot).IsNil() {
+		return json.RawMessage("null"), nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+	return data, fields, nil
+}
+
+func diffFields(before, after map[string]interface{}) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for field, from := range before {
+		if to := after[field]; !reflect.DeepEqual(from, to) {
+			changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = FieldChange{From: nil, To: to}
+		}
+	}
+	return changes
+}
+
+func isNullSnapshot(state json.RawMessage) bool {
+	return len(state) == 0 || string(state) == "null"
+}
+
+func newProductSnapshot(p *catalog.Product) *ProductSnapshot {
+	if p == nil {
+		return nil
+	}
+	return &ProductSnapshot{
+		ID:          p.ID,
+		SKU:         p.SKU,
+		Name:        p.Name,
+		Description: p.Description,
+		BrandID:     p.BrandID,
+		CategoryID:  p.CategoryID,
+		BasePrice:   p.BasePrice.Amount,
+		Currency:    p.BasePrice.Currency,
+		Status:      string(p.Status),
+		Images:      p.Images,
+		Attributes:  p.Attributes,
+	}
+}
+
+func (s *ProductSnapshot) applyTo(p *catalog.Product) {
+	p.ID = s.ID
+	p.SKU = s.SKU
+	p.Name = s.Name
+	p.Description = s.Description
+	p.BrandID = s.BrandID
+	p.CategoryID = s.CategoryID
+	p.BasePrice = money.Money{Amount: s.BasePrice, Currency: s.Currency}
+	p.Status = catalog.ProductStatus(s.Status)
+	p.Images = s.Images
+	p.Attributes = s.Attributes
+}
+
+func newVariantSnapshot(v *catalog.Variant) *VariantSnapshot {
+	if v == nil {
+		return nil
+	}
+	return &VariantSnapshot{
+		ID:          v.ID,
+		ProductID:   v.ProductID,
+		SKU:         v.SKU,
+		Name:        v.Name,
+		Price:       v.Price.Amount,
+		Currency:    v.Price.Currency,
+		Attributes:  v.Attributes,
+		Images:      v.Images,
+		IsAvailable: v.IsAvailable,
+	}
+}
+
+func (s *VariantSnapshot) applyTo(v *catalog.Variant) {
+	v.ID = s.ID
+	v.ProductID = s.ProductID
+	v.SKU = s.SKU
+	v.Name = s.Name
+	v.Price = money.Money{Amount: s.Price, Currency: s.Currency}
+	v.Attributes = s.Attributes
+	v.Images = s.Images
+	v.IsAvailable = s.IsAvailable
+}
+
+// versionedProductRepository records a version for every product save and delete
+type versionedProductRepository struct {
+	catalog.ProductRepository
+	history *CatalogHistoryService
+}
+
+func (r *versionedProductRepository) Save(ctx context.Context, product *catalog.Product) error {
+	_, err := r.save(ctx, product)
+	return err
+}
+
+func (r *versionedProductRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.delete(ctx, id)
+	return err
+}
+
+func (r *versionedProductRepository) save(ctx context.Context, product *catalog.Product) (*CatalogVersion, error) {
+	before, _ := r.ProductRepository.FindByID(ctx, product.ID)
+	if err := r.ProductRepository.Save(ctx, product); err != nil {
+		return nil, err
+	}
+	return r.history.record(ctx, product.ID, CatalogEntityProduct, product.ID, newProductSnapshot(before), newProductSnapshot(product))
+}
+
+func (r *versionedProductRepository) delete(ctx context.Context, id string) (*CatalogVersion, error) {
+	before, err := r.ProductRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ProductRepository.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return r.history.record(ctx, id, CatalogEntityProduct, id, newProductSnapshot(before), (*ProductSnapshot)(nil))
+}
+
+// versionedVariantRepository records a version for every variant save and delete, in the
+// history of the variant's product
+type versionedVariantRepository struct {
+	catalog.VariantRepository
+	history *CatalogHistoryService
+}
+
+func (r *versionedVariantRepository) Save(ctx context.Context, variant *catalog.Variant) error {
+	_, err := r.save(ctx, variant)
+	return err
+}
+
+func (r *versionedVariantRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.delete(ctx, id)
+	return err
+}
+
+func (r *versionedVariantRepository) save(ctx context.Context, variant *catalog.Variant) (*CatalogVersion, error) {
+	before, _ := r.VariantRepository.FindByID(ctx, variant.ID)
+	if err := r.VariantRepository.Save(ctx, variant); err != nil {
+		return nil, err
+	}
+	return r.history.record(ctx, variant.ProductID, CatalogEntityVariant, variant.ID, newVariantSnapshot(before), newVariantSnapshot(variant))
+}
+
+func (r *versionedVariantRepository) delete(ctx context.Context, id string) (*CatalogVersion, error) {
+	before, err := r.VariantRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.VariantRepository.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return r.history.record(ctx, before.ProductID, CatalogEntityVariant, id, newVariantSnapshot(before), (*VariantSnapshot)(nil))
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+// CategoryNode is a category in the category tree with its active product counts
+type CategoryNode struct {
+	*catalog.Category
+	// ProductCount counts the active products directly in the category
+	ProductCount int64 `json:"product_count"`
+	// TotalProductCount adds the active products of every subcategory below it
+	TotalProductCount int64           `json:"total_product_count"`
+	Children          []*CategoryNode `json:"children"`
+}
+
+// CategoryCountRepository keeps a counter of active products per category, so the
+// category tree is shown without counting products per category on every request
+type CategoryCountRepository interface {
+	// All returns every category's counter, by category ID
+	All(ctx context.Context) (map[string]int64, error)
+	// Increment adds delta to a category's counter, creating it when missing
+	Increment(ctx context.Context, categoryID string, delta int64) error
+	// Recount sets every counter from the products table, returning how many counters
+	// were wrong
+	Recount(ctx context.Context) (int64, error)
+}
+
+// CategoryCountService maintains per-category product counters as products are saved and
+// deleted, and serves the category tree with them. Writes that bypass Products, such as
+// seeding or direct SQL, are corrected by Repair.
+type CategoryCountService struct {
+	repo       CategoryCountRepository
+	categories catalog.CategoryRepository
+}
+
+// NewCategoryCountService creates a new CategoryCountService
+func NewCategoryCountService(repo CategoryCountRepository, categories catalog.CategoryRepository) *CategoryCountService {
+	return &CategoryCountService{
+		repo:       repo,
+		categories: categories,
+	}
+}
+
+// Products returns the product repository with category counters kept up to date on
+// every save and delete
+func (s *CategoryCountService) Products(products catalog.ProductRepository) catalog.ProductRepository {
+	return &countedProductRepository{ProductRepository: products, counts: s}
+}
+
+// Tree returns the categories as a tree, roots and children ordered by display order and
+// name, each with its product counts
+func (s *CategoryCountService) Tree(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := s.categories.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, ProductCount: counts[category.ID], Children: []*CategoryNode{}}
+	}
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		// A category whose parent is missing is shown as a root rather than dropped
+		if parent, ok := parentNode(nodes, category); ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	sortCategoryNodes(roots)
+	for _, root := range roots {
+		sumCategoryCounts(root)
+	}
+	return roots, nil
+}
+
+// Repair recounts every category's products, fixing counters that drifted, and returns
+// how many were wrong
+func (s *CategoryCountService) Repair(ctx context.Context) (int64, error) {
+	return s.repo.Recount(ctx)
+}
+
+// adjust moves a product's count from the category it counted towards to the one it
+// counts towards now, either "" when it does not count. A failed update is logged, not returned: the product
+// is already written, and the next Repair fixes the counter.
+func (s *CategoryCountService) adjust(ctx context.Context, oldCategory, newCategory string) {
+	if oldCategory == newCategory {
+		return
+	}
+	if oldCategory != "" {
+		if err := s.repo.Increment(ctx, oldCategory, -1); err != nil {
+			log.Printf("Failed to update product count of category %s: %v", oldCategory, err)
+		}
+	}
+	if newCategory != "" {
+		if err := s.repo.Increment(ctx, newCategory, 1); err != nil {
+			log.Printf("Failed to update product count of category %s: %v", newCategory, err)
+		}
+	}
+}
+
+// countedCategory returns the category a product counts towards, or "" when it does not
+// count
+func countedCategory(product *catalog.Product) string {
+	if product == nil || product.Status != catalog.ProductStatusActive {
+		return ""
+	}
+	return product.CategoryID
+}
+
+func parentNode(nodes map[string]*CategoryNode, category *catalog.Category) (*CategoryNode, bool) {
+	if category.ParentID == nil || *category.ParentID == category.ID {
+		return nil, false
+	}
+	parent, ok := nodes[*category.ParentID]
+	return parent, ok
+}
+
+func sortCategoryNodes(nodes []*CategoryNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].DisplayOrder != nodes[j].DisplayOrder {
+			return nodes[i].DisplayOrder < nodes[j].DisplayOrder
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortCategoryNodes(node.Children)
+	}
+}
+
+// sumCategoryCounts sets the total product counts of node and the categories below it
+func sumCategoryCounts(node *CategoryNode) int64 {
+	node.TotalProductCount = node.ProductCount
+	for _, child := range node.Children {
+		node.TotalProductCount += sumCategoryCounts(child)
+	}
+	return node.TotalProductCount
+}
+
+// countedProductRepository is a catalog.ProductRepository that keeps category counters
+// up to date
+type countedProductRepository struct {
+	catalog.ProductRepository
+	counts *CategoryCountService
+}
+
+func (r *countedProductRepository) Save(ctx context.Context, product *catalog.Product) error {
+	// Read before saving: the stored product may be the one being changed
+	before := r.countedCategory(ctx, product.ID)
+	if err := r.ProductRepository.Save(ctx, product); err != nil {
+		return err
+	}
+	r.counts.adjust(ctx, before, countedCategory(product))
+	return nil
+}
+
+func (r *countedProductRepository) Delete(ctx context.Context, id string) error {
+	before := r.countedCategory(ctx, id)
+	if err := r.ProductRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.counts.adjust(ctx, before, "")
+	return nil
+}
+
+// countedCategory returns the category the stored product counts towards, "" for a new
+// product
+func (r *countedProductRepository) countedCategory(ctx context.Context, id string) string {
+	product, err := r.ProductRepository.FindByID(ctx, id)
+	if err != nil {
+		return ""
+	}
+	return countedCategory(product)
+}
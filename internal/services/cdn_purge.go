@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+)
+
+// CDNPurgeClient purges cached API responses from a CDN by surrogate key. It POSTs the
+// keys to a purge endpoint both in a Surrogate-Key header, as Fastly's purge API takes
+// them, and as a JSON body for purge hooks that adapt other CDNs.
+type CDNPurgeClient struct {
+	purgeURL   string
+	token      string
+	httpClient *http.Client
+	retry      retry.Policy
+}
+
+// NewCDNPurgeClient creates a CDNPurgeClient for purgeURL. The token, when set, is sent as
+// a bearer token and in a Fastly-Key header.
+func NewCDNPurgeClient(purgeURL, token string, timeout time.Duration) *CDNPurgeClient {
+	return &CDNPurgeClient{
+		purgeURL:   purgeURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// WithRetry retries purges that fail
+func (c *CDNPurgeClient) WithRetry(policy retry.Policy) *CDNPurgeClient {
+	c.retry = policy
+	return c
+}
+
+// Purge asks the CDN to drop every response tagged with any of keys
+func (c *CDNPurgeClient) Purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		return err
+	}
+	return retry.Do(ctx, c.retry, func(ctx context.Context) error {
+		return c.purge(ctx, keys, body)
+	})
+}
+
+func (c *CDNPurgeClient) purge(ctx context.Context, keys []string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.purgeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Fastly-Key", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cdn purge failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("cdn purge failed: endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
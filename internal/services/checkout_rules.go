@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrCheckoutRuleNotFound = errors.New("checkout rule not found")
+	ErrInvalidCheckoutRule  = errors.New("invalid checkout rule")
+)
+
+// CheckoutRuleType is the kind of condition a checkout rule enforces
+type CheckoutRuleType string
+
+const (
+	// CheckoutRuleMinOrderValue rejects carts whose subtotal is below a minimum
+	CheckoutRuleMinOrderValue CheckoutRuleType = "min_order_value"
+	// CheckoutRuleRestrictedCountries rejects shipping a product to the listed countries
+	CheckoutRuleRestrictedCountries CheckoutRuleType = "restricted_countries"
+	// CheckoutRuleQuantityMultiple only sells a product in multiples of a pack size
+	CheckoutRuleQuantityMultiple CheckoutRuleType = "quantity_multiple"
+)
+
+// CheckoutRule is a condition a cart must meet before it can be checked out
+type CheckoutRule struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Type          CheckoutRuleType `json:"type"`
+	ProductID     string           `json:"product_id,omitempty"`      // restricted_countries and quantity_multiple
+	MinOrderValue *money.Money     `json:"min_order_value,omitempty"` // min_order_value
+	Countries     []string         `json:"countries,omitempty"`       // restricted_countries; ISO 3166-1 alpha-2
+	Multiple      int              `json:"multiple,omitempty"`        // quantity_multiple
+	IsActive      bool             `json:"is_active"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// CheckoutRuleViolation explains why a cart fails one checkout rule
+type CheckoutRuleViolation struct {
+	RuleID    string           `json:"rule_id"`
+	Type      CheckoutRuleType `json:"type"`
+	Message   string           `json:"message"`
+	ProductID string           `json:"product_id,omitempty"`
+	Minimum   *money.Money     `json:"minimum,omitempty"`  // min_order_value, in the cart currency
+	Subtotal  *money.Money     `json:"subtotal,omitempty"` // min_order_value
+	Country   string           `json:"country,omitempty"`  // restricted_countries
+	Multiple  int              `json:"multiple,omitempty"` // quantity_multiple
+	Quantity  int              `json:"quantity,omitempty"` // quantity_multiple
+}
+
+// CheckoutRuleError reports every checkout rule a cart fails
+type CheckoutRuleError struct {
+	Violations []CheckoutRuleViolation
+}
+
+func (e *CheckoutRuleError) Error() string {
+	if len(e.Violations) == 1 {
+		return e.Violations[0].Message
+	}
+	return fmt.Sprintf("cart fails %d checkout rules", len(e.Violations))
+}
+
+// CheckoutRuleRepository defines persistence for checkout rules
+type CheckoutRuleRepository interface {
+	FindByID(ctx context.Context, id string) (*CheckoutRule, error)
+	FindAll(ctx context.Context) ([]*CheckoutRule, error)
+	FindActive(ctx context.Context) ([]*CheckoutRule, error)
+	Save(ctx context.Context, rule *CheckoutRule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// CheckoutRuleService evaluates configurable checkout rules against carts
+type CheckoutRuleService struct {
+	repo     CheckoutRuleRepository
+	currency *CurrencyService
+}
+
+// NewCheckoutRuleService creates a new CheckoutRuleService
+func NewCheckoutRuleService(repo CheckoutRuleRepository) *CheckoutRuleService {
+	return &CheckoutRuleService{repo: repo}
+}
+
+// WithCurrencyService converts minimum order values into the cart currency. Without one,
+// a minimum only applies to carts in its own currency.
+func (s *CheckoutRuleService) WithCurrencyService(currency *CurrencyService) *CheckoutRuleService {
+	s.currency = currency
+	return s
+}
+
+// Evaluate returns every active rule the cart fails. Country rules are only evaluated
+// once the shipping country is known; pass an empty country before then.
+func (s *CheckoutRuleService) Evaluate(ctx context.Context, c *cart.Cart, country string) ([]CheckoutRuleViolation, error) {
+	violations := make([]CheckoutRuleViolation, 0)
+	if c == nil || len(c.Items) == 0 {
+		return violations, nil
+	}
+
+	rules, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := CartQuantities(c)
+	country = strings.ToUpper(strings.TrimSpace(country))
+	for _, rule := range rules {
+		switch rule.Type {
+		case CheckoutRuleMinOrderValue:
+			violation, err := s.checkMinOrderValue(rule, c)
+			if err != nil {
+				return nil, err
+			}
+			if violation != nil {
+				violations = append(violations, *violation)
+			}
+		case CheckoutRuleRestrictedCountries:
+			if country == "" || quantities[rule.ProductID] == 0 || !containsCountry(rule.Countries, country) {
+				continue
+			}
+			violations = append(violations, CheckoutRuleViolation{
+				RuleID:    rule.ID,
+				Type:      rule.Type,
+				Message:   fmt.Sprintf("product %s cannot be shipped to %s", rule.ProductID, country),
+				ProductID: rule.ProductID,
+				Country:   country,
+			})
+		case CheckoutRuleQuantityMultiple:
+			quantity := quantities[rule.ProductID]
+			if quantity == 0 || quantity%rule.Multiple == 0 {
+				continue
+			}
+			violations = append(violations, CheckoutRuleViolation{
+				RuleID:    rule.ID,
+				Type:      rule.Type,
+				Message:   fmt.Sprintf("product %s is sold in multiples of %d", rule.ProductID, rule.Multiple),
+				ProductID: rule.ProductID,
+				Multiple:  rule.Multiple,
+				Quantity:  quantity,
+			})
+		}
+	}
+	return violations, nil
+}
+
+// Check returns a *CheckoutRuleError listing every rule the cart fails, or nil when it
+// passes them all
+func (s *CheckoutRuleService) Check(ctx context.Context, c *cart.Cart, country string) error {
+	violations, err := s.Evaluate(ctx, c, country)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &CheckoutRuleError{Violations: violations}
+	}
+	return nil
+}
+
+// ListRules returns every checkout rule
+func (s *CheckoutRuleService) ListRules(ctx context.Context) ([]*CheckoutRule, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetRule returns a checkout rule
+func (s *CheckoutRuleService) GetRule(ctx context.Context, id string) (*CheckoutRule, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// CreateRule validates and saves a new checkout rule
+func (s *CheckoutRuleService) CreateRule(ctx context.Context, rule *CheckoutRule) error {
+	if err := validateCheckoutRule(rule); err != nil {
+		return err
+	}
+	now := time.Now()
+	rule.ID = utils.GenerateID()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	return s.repo.Save(ctx, rule)
+}
+
+// UpdateRule validates and saves changes to an existing checkout rule
+func (s *CheckoutRuleService) UpdateRule(ctx context.Context, rule *CheckoutRule) error {
+	existing, err := s.repo.FindByID(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateCheckoutRule(rule); err != nil {
+		return err
+	}
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, rule)
+}
+
+// DeleteRule removes a checkout rule
+func (s *CheckoutRuleService) DeleteRule(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// checkMinOrderValue compares the cart subtotal with the rule's minimum, converted into
+// the cart currency
+func (s *CheckoutRuleService) checkMinOrderValue(rule *CheckoutRule, c *cart.Cart) (*CheckoutRuleViolation, error) {
+	subtotal := c.Subtotal()
+	minimum := *rule.MinOrderValue
+	if s.currency != nil {
+		converted, err := s.currency.Convert(minimum, subtotal.Currency)
+		if err != nil {
+			return nil, err
+		}
+		minimum = converted
+	} else if !strings.EqualFold(minimum.Currency, subtotal.Currency) {
+		return nil, nil
+	}
+
+	if subtotal.Amount >= minimum.Amount {
+		return nil, nil
+	}
+	return &CheckoutRuleViolation{
+		RuleID:   rule.ID,
+		Type:     rule.Type,
+		Message:  fmt.Sprintf("minimum order value is %s", minimum.String()),
+		Minimum:  &minimum,
+		Subtotal: &subtotal,
+	}, nil
+}
+
+// validateCheckoutRule checks that a rule has the fields its type needs, and normalizes
+// its country codes
+func validateCheckoutRule(rule *CheckoutRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCheckoutRule)
+	}
+
+	switch rule.Type {
+	case CheckoutRuleMinOrderValue:
+		if rule.MinOrderValue == nil || !rule.MinOrderValue.IsPositive() || len(rule.MinOrderValue.Currency) != 3 {
+			return fmt.Errorf("%w: min_order_value needs a positive amount and a currency", ErrInvalidCheckoutRule)
+		}
+		rule.MinOrderValue.Currency = strings.ToUpper(rule.MinOrderValue.Currency)
+	case CheckoutRuleRestrictedCountries:
+		if rule.ProductID == "" || len(rule.Countries) == 0 {
+			return fmt.Errorf("%w: restricted_countries needs a product_id and countries", ErrInvalidCheckoutRule)
+		}
+		for i, country := range rule.Countries {
+			country = strings.ToUpper(strings.TrimSpace(country))
+			if len(country) != 2 {
+				return fmt.Errorf("%w: country %q must be a two-letter code", ErrInvalidCheckoutRule, rule.Countries[i])
+			}
+			rule.Countries[i] = country
+		}
+	case CheckoutRuleQuantityMultiple:
+		if rule.ProductID == "" || rule.Multiple < 2 {
+			return fmt.Errorf("%w: quantity_multiple needs a product_id and a multiple of at least 2", ErrInvalidCheckoutRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidCheckoutRule, rule.Type)
+	}
+	return nil
+}
+
+func containsCountry(countries []string, country string) bool {
+	for _, c := range countries {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
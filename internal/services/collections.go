@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrCollectionSlugTaken = errors.New("a collection with this slug already exists")
+	ErrInvalidCollection   = errors.New("collection requires a lowercase URL slug, a title and a manual or rule type")
+	ErrInvalidTag          = errors.New("tags must be lowercase words separated by hyphens")
+)
+
+// CollectionType is how a collection's products are chosen
+type CollectionType string
+
+const (
+	CollectionManual CollectionType = "manual" // merchandisers pick and order the products
+	CollectionRule   CollectionType = "rule"   // products matching the rule, kept up to date automatically
+)
+
+// CollectionStatus controls whether a collection is served publicly
+type CollectionStatus string
+
+const (
+	CollectionStatusDraft     CollectionStatus = "draft"
+	CollectionStatusPublished CollectionStatus = "published"
+)
+
+// Collection is a curated set of products behind a merchandised landing page
+type Collection struct {
+	ID          string           `json:"id"`
+	Slug        string           `json:"slug"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Type        CollectionType   `json:"type"`
+	Rule        string           `json:"rule,omitempty"`        // rule collections only, e.g. "price < 50 and category = cat-1"
+	ProductIDs  []string         `json:"product_ids,omitempty"` // manual collections only, in display order
+	Status      CollectionStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CollectionSortFields are the fields the admin collection list can be sorted by
+var CollectionSortFields = []string{"slug", "title", "type", "status", "created_at", "updated_at"}
+
+// CollectionFilter filters the admin collection list
+type CollectionFilter struct {
+	Status CollectionStatus
+	Sort   Sort
+	Limit  int
+	Offset int
+}
+
+// CollectionCondition is one comparison in a collection rule. Price values are in cents.
+type CollectionCondition struct {
+	Field    string
+	Operator string
+	Value    string
+	Cents    int64
+}
+
+// collectionRuleOperators lists the operators each rule field accepts
+var collectionRuleOperators = map[string][]string{
+	"price":    {"<", "<=", ">", ">=", "="},
+	"category": {"=", "!="},
+	"brand":    {"=", "!="},
+	"tag":      {"=", "!="},
+}
+
+var (
+	collectionRuleAnd          = regexp.MustCompile(`(?i)\s+and\s+`)
+	collectionConditionPattern = regexp.MustCompile(`^([a-z]+)\s*(<=|>=|!=|<|>|=)\s*(\S+)$`)
+)
+
+// tagPattern allows lowercase words separated by single hyphens, e.g. "back-to-school"
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ParseCollectionRule parses a rule such as "price < 49.99 and category = cat-1". Every
+// condition must hold. Prices are in the store currency's major unit; category and brand
+// compare IDs, and tag matches products carrying the tag.
+func ParseCollectionRule(rule string) ([]CollectionCondition, error) {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return nil, errors.New("rule is empty")
+	}
+
+	var conditions []CollectionCondition
+	for _, part := range collectionRuleAnd.Split(rule, -1) {
+		match := collectionConditionPattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			return nil, fmt.Errorf("cannot parse rule condition %q", part)
+		}
+		condition := CollectionCondition{Field: match[1], Operator: match[2], Value: match[3]}
+
+		operators, ok := collectionRuleOperators[condition.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule field %q; use price, category, brand or tag", condition.Field)
+		}
+		if !containsField(operators, condition.Operator) {
+			return nil, fmt.Errorf("%s does not support %s", condition.Field, condition.Operator)
+		}
+		if condition.Field == "price" {
+			amount, err := strconv.ParseFloat(condition.Value, 64)
+			if err != nil || amount < 0 {
+				return nil, fmt.Errorf("price %q is not an amount", condition.Value)
+			}
+			condition.Cents = int64(math.Round(amount * 100))
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// Matches reports whether a product with the given tags meets the condition
+func (c CollectionCondition) Matches(product *catalog.Product, tags []string) bool {
+	switch c.Field {
+	case "price":
+		price := product.BasePrice.Amount
+		switch c.Operator {
+		case "<":
+			return price < c.Cents
+		case "<=":
+			return price <= c.Cents
+		case ">":
+			return price > c.Cents
+		case ">=":
+			return price >= c.Cents
+		default:
+			return price == c.Cents
+		}
+	case "category":
+		return (product.CategoryID == c.Value) == (c.Operator == "=")
+	case "brand":
+		return (product.BrandID == c.Value) == (c.Operator == "=")
+	case "tag":
+		return containsField(tags, c.Value) == (c.Operator == "=")
+	}
+	return false
+}
+
+// CollectionRepository defines persistence for collections and product tags
+type CollectionRepository interface {
+	FindByID(ctx context.Context, id string) (*Collection, error)
+	FindBySlug(ctx context.Context, slug string) (*Collection, error)
+	List(ctx context.Context, filter CollectionFilter) ([]*Collection, error)
+	Count(ctx context.Context, filter CollectionFilter) (int64, error)
+	// Save stores the collection and, for manual collections, replaces its product list
+	Save(ctx context.Context, collection *Collection) error
+	Delete(ctx context.Context, id string) error
+	// FindProducts returns the active products in a collection: in merchandised order for
+	// manual collections, by name for rule collections
+	FindProducts(ctx context.Context, collection *Collection, conditions []CollectionCondition, limit, offset int) ([]*catalog.Product, error)
+	CountProducts(ctx context.Context, collection *Collection, conditions []CollectionCondition) (int64, error)
+	FindTags(ctx context.Context, productID string) ([]string, error)
+	SetTags(ctx context.Context, productID string, tags []string) error
+}
+
+// CollectionService manages product tags and the collections built from them
+type CollectionService struct {
+	repo    CollectionRepository
+	catalog *CatalogService
+}
+
+// NewCollectionService creates a new CollectionService. Products are returned with their
+// sale prices, resolved through the catalog service.
+func NewCollectionService(repo CollectionRepository, catalogService *CatalogService) *CollectionService {
+	return &CollectionService{repo: repo, catalog: catalogService}
+}
+
+// GetPublishedCollection returns a published collection by slug; drafts are reported as not found
+func (s *CollectionService) GetPublishedCollection(ctx context.Context, slug string) (*Collection, error) {
+	collection, err := s.repo.FindBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+	if collection.Status != CollectionStatusPublished {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+// ListProducts returns a page of the collection's products with sale prices
+func (s *CollectionService) ListProducts(ctx context.Context, collection *Collection, limit, offset int) ([]*ProductResponse, error) {
+	conditions, err := s.conditions(collection)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.repo.FindProducts(ctx, collection, conditions, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.catalog.enrichWithSalePrices(ctx, products)
+}
+
+// CountProducts counts the collection's products
+func (s *CollectionService) CountProducts(ctx context.Context, collection *Collection) (int64, error) {
+	conditions, err := s.conditions(collection)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.CountProducts(ctx, collection, conditions)
+}
+
+// ListCollections returns collections matching the filter, ordered by slug
+func (s *CollectionService) ListCollections(ctx context.Context, filter CollectionFilter) ([]*Collection, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountCollections counts collections matching the filter
+func (s *CollectionService) CountCollections(ctx context.Context, filter CollectionFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetCollection returns a collection by ID, including drafts
+func (s *CollectionService) GetCollection(ctx context.Context, id string) (*Collection, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SaveCollection validates and saves a collection. Slugs must be unique; rule collections
+// need a rule that parses, and manual collections keep their product order.
+func (s *CollectionService) SaveCollection(ctx context.Context, collection *Collection) error {
+	collection.Slug = strings.ToLower(strings.TrimSpace(collection.Slug))
+	collection.Title = strings.TrimSpace(collection.Title)
+	collection.Rule = strings.TrimSpace(collection.Rule)
+	if collection.Status == "" {
+		collection.Status = CollectionStatusDraft
+	}
+
+	if !pageSlugPattern.MatchString(collection.Slug) || collection.Title == "" ||
+		(collection.Type != CollectionManual && collection.Type != CollectionRule) ||
+		(collection.Status != CollectionStatusDraft && collection.Status != CollectionStatusPublished) {
+		return ErrInvalidCollection
+	}
+	if collection.Type == CollectionRule {
+		if _, err := ParseCollectionRule(collection.Rule); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCollection, err)
+		}
+		collection.ProductIDs = nil
+	} else {
+		collection.Rule = ""
+		collection.ProductIDs = uniqueStrings(collection.ProductIDs)
+	}
+
+	existing, err := s.repo.FindBySlug(ctx, collection.Slug)
+	if err == nil && existing.ID != collection.ID {
+		return ErrCollectionSlugTaken
+	}
+	if err != nil && err != ErrCollectionNotFound {
+		return err
+	}
+
+	now := time.Now()
+	if collection.ID == "" {
+		collection.ID = utils.GenerateID()
+		collection.CreatedAt = now
+	}
+	collection.UpdatedAt = now
+
+	return s.repo.Save(ctx, collection)
+}
+
+// DeleteCollection deletes a collection
+func (s *CollectionService) DeleteCollection(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// GetProductTags returns a product's tags, sorted
+func (s *CollectionService) GetProductTags(ctx context.Context, productID string) ([]string, error) {
+	return s.repo.FindTags(ctx, productID)
+}
+
+// SetProductTags replaces a product's tags. Tags are lowercased and deduplicated.
+func (s *CollectionService) SetProductTags(ctx context.Context, productID string, tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, ErrInvalidTag
+		}
+		normalized = append(normalized, tag)
+	}
+	normalized = uniqueStrings(normalized)
+
+	if err := s.repo.SetTags(ctx, productID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// conditions parses a rule collection's rule; manual collections have none
+func (s *CollectionService) conditions(collection *Collection) ([]CollectionCondition, error) {
+	if collection.Type != CollectionRule {
+		return nil, nil
+	}
+	return ParseCollectionRule(collection.Rule)
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence of each
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,649 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrCompanyNotFound        = errors.New("company not found")
+	ErrInvalidCompany         = errors.New("company requires a name of at most 255 characters, a non-negative approval threshold in a 3-letter currency, and net terms of 0, 30 or 60 days")
+	ErrInvalidCompanyRole     = errors.New("company role must be buyer or approver")
+	ErrAlreadyCompanyMember   = errors.New("user already belongs to another company")
+	ErrNotCompanyMember       = errors.New("user does not belong to a company")
+	ErrNotCompanyApprover     = errors.New("only company approvers can approve or reject orders")
+	ErrCompanyAddressNotFound = errors.New("company address not found")
+	ErrInvalidCompanyAddress  = errors.New("address label must be at most 100 characters")
+	ErrCompanyOrderNotFound   = errors.New("company order not found")
+	ErrApprovalNotPending     = errors.New("order is not awaiting approval")
+	ErrSelfApproval           = errors.New("approvers cannot approve or reject their own orders")
+)
+
+const (
+	maxCompanyNameLength         = 255
+	maxCompanyAddressLabelLength = 100
+)
+
+// CompanyRole is what a member can do for their company
+type CompanyRole string
+
+const (
+	CompanyRoleBuyer    CompanyRole = "buyer"    // places orders; those above the threshold need approval
+	CompanyRoleApprover CompanyRole = "approver" // places orders without approval and signs off buyers' orders
+)
+
+// IsValid reports whether the role is supported
+func (r CompanyRole) IsValid() bool {
+	return r == CompanyRoleBuyer || r == CompanyRoleApprover
+}
+
+// ApprovalStatus tracks a company order through approval
+type ApprovalStatus string
+
+const (
+	ApprovalNotRequired ApprovalStatus = "not_required"
+	ApprovalPending     ApprovalStatus = "pending"
+	ApprovalApproved    ApprovalStatus = "approved"
+	ApprovalRejected    ApprovalStatus = "rejected"
+)
+
+// Company is a business account whose members buy on its behalf
+type Company struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ApprovalThreshold is the order total buyers' orders need approval above; nil when
+	// no order needs approval
+	ApprovalThreshold *money.Money `json:"approval_threshold,omitempty"`
+	// NetTermsDays is how many days after an order members may pay its invoice; 0 when the
+	// company isn't approved to pay by invoice
+	NetTermsDays int       `json:"net_terms_days"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CompanyInput holds the company fields set by admins
+type CompanyInput struct {
+	Name              string
+	ApprovalThreshold *money.Money
+	NetTermsDays      int // 0, 30 or 60
+}
+
+// CompanyMember links a user to the company they buy for. A user belongs to at most one company.
+type CompanyMember struct {
+	CompanyID string      `json:"company_id"`
+	UserID    string      `json:"user_id"`
+	Role      CompanyRole `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// CompanyMembership is a member's view of their company
+type CompanyMembership struct {
+	Company *Company    `json:"company"`
+	Role    CompanyRole `json:"role"`
+}
+
+// CompanyAddress is an entry in a company's shared address book
+type CompanyAddress struct {
+	ID        string         `json:"id"`
+	CompanyID string         `json:"company_id"`
+	Label     string         `json:"label"`
+	Address   orders.Address `json:"address"`
+	CreatedBy string         `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// CompanyOrder records an order placed for a company and its approval
+type CompanyOrder struct {
+	OrderID        string         `json:"order_id"`
+	CompanyID      string         `json:"company_id"`
+	PlacedBy       string         `json:"placed_by"`
+	Total          money.Money    `json:"total"`
+	ApprovalStatus ApprovalStatus `json:"approval_status"`
+	DecidedBy      string         `json:"decided_by,omitempty"`
+	DecidedAt      *time.Time     `json:"decided_at,omitempty"`
+	Reason         string         `json:"reason,omitempty"` // why the order was rejected
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// CompanyOrderDetail is a company order with the order itself
+type CompanyOrderDetail struct {
+	*CompanyOrder
+	Order *orders.Order `json:"order"`
+}
+
+// CompanyOrderFilter filters a company's order history
+type CompanyOrderFilter struct {
+	ApprovalStatus ApprovalStatus
+	Limit          int
+	Offset         int
+}
+
+// CompanyRepository defines persistence for companies, their members, address books and orders
+type CompanyRepository interface {
+	FindByID(ctx context.Context, id string) (*Company, error)
+	List(ctx context.Context, limit, offset int) ([]*Company, error)
+	Save(ctx context.Context, company *Company) error
+
+	// FindMember returns nil when the user belongs to no company
+	FindMember(ctx context.Context, userID string) (*CompanyMember, error)
+	ListMembers(ctx context.Context, companyID string) ([]*CompanyMember, error)
+	SaveMember(ctx context.Context, member *CompanyMember) error
+	DeleteMember(ctx context.Context, userID string) error
+
+	FindAddress(ctx context.Context, id string) (*CompanyAddress, error)
+	ListAddresses(ctx context.Context, companyID string) ([]*CompanyAddress, error)
+	SaveAddress(ctx context.Context, address *CompanyAddress) error
+	DeleteAddress(ctx context.Context, id string) error
+
+	// FindOrder returns nil when the order wasn't placed for a company
+	FindOrder(ctx context.Context, orderID string) (*CompanyOrder, error)
+	// ListOrders lists a company's orders, newest first
+	ListOrders(ctx context.Context, companyID string, filter CompanyOrderFilter) ([]*CompanyOrder, error)
+	SaveOrder(ctx context.Context, order *CompanyOrder) error
+}
+
+// CompanyService manages company accounts: members and their roles, the shared address book,
+// the shared order history, and approval of buyers' orders above the company's threshold.
+// Orders awaiting approval are created but not charged; approving charges the payment method
+// they were placed with, and rejecting cancels them.
+type CompanyService struct {
+	repo            CompanyRepository
+	orderService    orders.Service
+	paymentService  *PaymentService
+	retryService    *PaymentRetryService
+	deliveryService *DeliveryService
+	currency        *CurrencyService
+	invoices        *InvoiceService
+}
+
+// NewCompanyService creates a new CompanyService
+func NewCompanyService(repo CompanyRepository, orderService orders.Service) *CompanyService {
+	return &CompanyService{
+		repo:         repo,
+		orderService: orderService,
+	}
+}
+
+// WithPaymentService charges approved orders; declined charges are left to payment retries
+// when a retry service is given
+func (s *CompanyService) WithPaymentService(paymentService *PaymentService, retryService *PaymentRetryService) *CompanyService {
+	s.paymentService = paymentService
+	s.retryService = retryService
+	return s
+}
+
+// WithDeliveryService releases booked delivery slots when an order is rejected
+func (s *CompanyService) WithDeliveryService(deliveryService *DeliveryService) *CompanyService {
+	s.deliveryService = deliveryService
+	return s
+}
+
+// WithInvoiceService voids the invoices of rejected orders paid by invoice
+func (s *CompanyService) WithInvoiceService(invoices *InvoiceService) *CompanyService {
+	s.invoices = invoices
+	return s
+}
+
+// WithCurrencyService compares orders in other currencies against the approval threshold.
+// Without it, orders in a currency other than the threshold's always need approval.
+func (s *CompanyService) WithCurrencyService(currency *CurrencyService) *CompanyService {
+	s.currency = currency
+	return s
+}
+
+// CreateCompany creates a company
+func (s *CompanyService) CreateCompany(ctx context.Context, input CompanyInput) (*Company, error) {
+	now := time.Now()
+	company := &Company{ID: utils.GenerateID(), CreatedAt: now}
+	if err := applyCompanyInput(company, input); err != nil {
+		return nil, err
+	}
+	company.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, company); err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// UpdateCompany replaces a company's name and approval threshold
+func (s *CompanyService) UpdateCompany(ctx context.Context, id string, input CompanyInput) (*Company, error) {
+	company, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCompanyInput(company, input); err != nil {
+		return nil, err
+	}
+	company.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, company); err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// GetCompany returns a company
+func (s *CompanyService) GetCompany(ctx context.Context, id string) (*Company, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListCompanies lists companies by name
+func (s *CompanyService) ListCompanies(ctx context.Context, limit, offset int) ([]*Company, error) {
+	return s.repo.List(ctx, limit, offset)
+}
+
+// SetMember adds a user to a company or changes their role
+func (s *CompanyService) SetMember(ctx context.Context, companyID, userID string, role CompanyRole) (*CompanyMember, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidCompanyRole
+	}
+	if _, err := s.repo.FindByID(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.FindMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if member == nil {
+		member = &CompanyMember{CompanyID: companyID, UserID: userID, CreatedAt: now}
+	} else if member.CompanyID != companyID {
+		return nil, ErrAlreadyCompanyMember
+	}
+	member.Role = role
+	member.UpdatedAt = now
+
+	if err := s.repo.SaveMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a user from a company. Orders they placed stay in the company's history.
+func (s *CompanyService) RemoveMember(ctx context.Context, companyID, userID string) error {
+	member, err := s.repo.FindMember(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.CompanyID != companyID {
+		return ErrNotCompanyMember
+	}
+	return s.repo.DeleteMember(ctx, userID)
+}
+
+// ListMembers lists a company's members
+func (s *CompanyService) ListMembers(ctx context.Context, companyID string) ([]*CompanyMember, error) {
+	return s.repo.ListMembers(ctx, companyID)
+}
+
+// Membership returns the company a user belongs to and their role in it
+func (s *CompanyService) Membership(ctx context.Context, userID string) (*CompanyMembership, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	company, err := s.repo.FindByID(ctx, member.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	return &CompanyMembership{Company: company, Role: member.Role}, nil
+}
+
+// ListAddresses lists the address book of the user's company
+func (s *CompanyService) ListAddresses(ctx context.Context, userID string) ([]*CompanyAddress, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListAddresses(ctx, member.CompanyID)
+}
+
+// AddAddress adds an address to the user's company address book
+func (s *CompanyService) AddAddress(ctx context.Context, userID, label string, address orders.Address) (*CompanyAddress, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	label, err = normalizeCompanyAddressLabel(label)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &CompanyAddress{
+		ID:        utils.GenerateID(),
+		CompanyID: member.CompanyID,
+		Label:     label,
+		Address:   address,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.SaveAddress(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateAddress replaces an address in the user's company address book
+func (s *CompanyService) UpdateAddress(ctx context.Context, userID, addressID, label string, address orders.Address) (*CompanyAddress, error) {
+	entry, err := s.companyAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Label, err = normalizeCompanyAddressLabel(label); err != nil {
+		return nil, err
+	}
+	entry.Address = address
+	entry.UpdatedAt = time.Now()
+
+	if err := s.repo.SaveAddress(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteAddress removes an address from the user's company address book
+func (s *CompanyService) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	if _, err := s.companyAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+	return s.repo.DeleteAddress(ctx, addressID)
+}
+
+// UsesApproval reports whether a user's orders may be held for approval: they are a buyer
+// for a company with an approval threshold
+func (s *CompanyService) UsesApproval(ctx context.Context, userID string) (bool, error) {
+	member, err := s.repo.FindMember(ctx, userID)
+	if err != nil || member == nil || member.Role != CompanyRoleBuyer {
+		return false, err
+	}
+	company, err := s.repo.FindByID(ctx, member.CompanyID)
+	if err != nil {
+		return false, err
+	}
+	return company.ApprovalThreshold != nil, nil
+}
+
+// RecordOrder adds a newly placed order to its buyer's company history. Buyers' orders above
+// the approval threshold are recorded as pending and must not be charged until approved.
+// It returns nil for users who belong to no company.
+func (s *CompanyService) RecordOrder(ctx context.Context, order *orders.Order) (*CompanyOrder, error) {
+	member, err := s.repo.FindMember(ctx, order.UserID)
+	if err != nil || member == nil {
+		return nil, err
+	}
+	company, err := s.repo.FindByID(ctx, member.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	record := &CompanyOrder{
+		OrderID:        order.ID,
+		CompanyID:      company.ID,
+		PlacedBy:       order.UserID,
+		Total:          order.Total,
+		ApprovalStatus: ApprovalNotRequired,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if member.Role == CompanyRoleBuyer && s.exceedsThreshold(company, order.Total) {
+		record.ApprovalStatus = ApprovalPending
+	}
+
+	if err := s.repo.SaveOrder(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetApproval returns the company record of an order, or nil when it wasn't placed for a company
+func (s *CompanyService) GetApproval(ctx context.Context, orderID string) (*CompanyOrder, error) {
+	return s.repo.FindOrder(ctx, orderID)
+}
+
+// ListOrders lists the order history of the user's company
+func (s *CompanyService) ListOrders(ctx context.Context, userID string, filter CompanyOrderFilter) ([]*CompanyOrder, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListOrders(ctx, member.CompanyID, filter)
+}
+
+// GetOrder returns an order from the history of the user's company
+func (s *CompanyService) GetOrder(ctx context.Context, userID, orderID string) (*CompanyOrderDetail, error) {
+	record, _, err := s.companyOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &CompanyOrderDetail{CompanyOrder: record, Order: order}, nil
+}
+
+// Approve signs off an order awaiting approval and charges it to the payment method it
+// was placed with. The approval stands when the charge is declined: the error is returned,
+// and with payment retries enabled the buyer can retry with another payment method.
+func (s *CompanyService) Approve(ctx context.Context, approverID, orderID string) (*CompanyOrder, error) {
+	record, err := s.decide(ctx, approverID, orderID, ApprovalApproved, "")
+	if err != nil {
+		return nil, err
+	}
+	return record, s.chargeApproved(ctx, orderID)
+}
+
+// Reject turns down an order awaiting approval and cancels it
+func (s *CompanyService) Reject(ctx context.Context, approverID, orderID, reason string) (*CompanyOrder, error) {
+	record, err := s.decide(ctx, approverID, orderID, ApprovalRejected, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.deliveryService != nil {
+		if err := s.deliveryService.ReleaseSlot(ctx, orderID); err != nil {
+			return nil, err
+		}
+	}
+	cancelReason := "rejected by company approver"
+	if record.Reason != "" {
+		cancelReason += ": " + record.Reason
+	}
+	if _, err := s.orderService.CancelOrder(ctx, orderID, cancelReason); err != nil {
+		return nil, err
+	}
+	if s.invoices != nil {
+		if err := s.invoices.VoidOrderInvoice(ctx, orderID, cancelReason); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// decide records an approver's decision on an order awaiting approval
+func (s *CompanyService) decide(ctx context.Context, approverID, orderID string, status ApprovalStatus, reason string) (*CompanyOrder, error) {
+	record, member, err := s.companyOrder(ctx, approverID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != CompanyRoleApprover {
+		return nil, ErrNotCompanyApprover
+	}
+	if record.ApprovalStatus != ApprovalPending {
+		return nil, ErrApprovalNotPending
+	}
+	if record.PlacedBy == approverID {
+		return nil, ErrSelfApproval
+	}
+
+	now := time.Now()
+	record.ApprovalStatus = status
+	record.DecidedBy = approverID
+	record.DecidedAt = &now
+	record.Reason = reason
+	record.UpdatedAt = now
+
+	if err := s.repo.SaveOrder(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// chargeApproved charges an approved order's balance to the payment method it was placed
+// with. Without a gateway or payment method the order stays pending, as it would have at
+// checkout.
+func (s *CompanyService) chargeApproved(ctx context.Context, orderID string) error {
+	if s.paymentService == nil || !s.paymentService.HasGateway() {
+		return nil
+	}
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.PaymentMethodID == "" {
+		return nil
+	}
+
+	_, err = s.paymentService.ChargeBalance(ctx, order, TenderRequest{
+		Type:            TenderTypeCard,
+		PaymentMethodID: order.PaymentMethodID,
+	})
+	if err == ErrTenderDeclined && s.retryService != nil {
+		// Keep the order in payment_pending so the buyer can retry with another payment method
+		if _, retryErr := s.retryService.RecordFailure(ctx, orderID, "payment declined after approval"); retryErr != nil {
+			return retryErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	summary, err := s.paymentService.Summary(ctx, order)
+	if err != nil {
+		return err
+	}
+	if summary.IsFullyCaptured() {
+		_, err = s.orderService.UpdateStatus(ctx, orderID, orders.OrderStatusPaid)
+	}
+	return err
+}
+
+// exceedsThreshold reports whether an order total is above the company's approval threshold
+func (s *CompanyService) exceedsThreshold(company *Company, total money.Money) bool {
+	if company.ApprovalThreshold == nil {
+		return false
+	}
+	threshold := *company.ApprovalThreshold
+	if !strings.EqualFold(threshold.Currency, total.Currency) {
+		if s.currency == nil {
+			return true
+		}
+		converted, err := s.currency.Convert(threshold, total.Currency)
+		if err != nil {
+			// Hold the order rather than let an unconvertible total skip approval
+			log.Printf("Failed to convert approval threshold for company %s: %v", company.ID, err)
+			return true
+		}
+		threshold = converted
+	}
+	return total.Amount > threshold.Amount
+}
+
+// member returns the user's company membership
+func (s *CompanyService) member(ctx context.Context, userID string) (*CompanyMember, error) {
+	member, err := s.repo.FindMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrNotCompanyMember
+	}
+	return member, nil
+}
+
+// companyAddress returns an address from the user's company address book
+func (s *CompanyService) companyAddress(ctx context.Context, userID, addressID string) (*CompanyAddress, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.FindAddress(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.CompanyID != member.CompanyID {
+		return nil, ErrCompanyAddressNotFound
+	}
+	return entry, nil
+}
+
+// companyOrder returns an order from the history of the user's company, along with the
+// user's membership
+func (s *CompanyService) companyOrder(ctx context.Context, userID, orderID string) (*CompanyOrder, *CompanyMember, error) {
+	member, err := s.member(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := s.repo.FindOrder(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record == nil || record.CompanyID != member.CompanyID {
+		return nil, nil, ErrCompanyOrderNotFound
+	}
+	return record, member, nil
+}
+
+// applyCompanyInput validates company fields and sets them on the company
+func applyCompanyInput(company *Company, input CompanyInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxCompanyNameLength {
+		return ErrInvalidCompany
+	}
+
+	var threshold *money.Money
+	if input.ApprovalThreshold != nil {
+		currency := strings.ToUpper(strings.TrimSpace(input.ApprovalThreshold.Currency))
+		if input.ApprovalThreshold.Amount < 0 || len(currency) != 3 {
+			return ErrInvalidCompany
+		}
+		threshold = &money.Money{Amount: input.ApprovalThreshold.Amount, Currency: currency}
+	}
+
+	switch input.NetTermsDays {
+	case 0, 30, 60:
+	default:
+		return ErrInvalidCompany
+	}
+
+	company.Name = name
+	company.ApprovalThreshold = threshold
+	company.NetTermsDays = input.NetTermsDays
+	return nil
+}
+
+// normalizeCompanyAddressLabel trims an address label and checks its length
+func normalizeCompanyAddressLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if utf8.RuneCountInString(label) > maxCompanyAddressLabelLength {
+		return "", ErrInvalidCompanyAddress
+	}
+	return label, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidConsentPurpose = errors.New("consent purpose must be marketing or analytics")
+	ErrConsentPolicyRequired = errors.New("policy version is required")
+)
+
+// ConsentPurpose is what a user agrees to their data being used for
+type ConsentPurpose string
+
+const (
+	ConsentMarketing ConsentPurpose = "marketing"
+	ConsentAnalytics ConsentPurpose = "analytics"
+)
+
+// consentPurposes lists every purpose, in the order preferences are shown
+var consentPurposes = []ConsentPurpose{ConsentMarketing, ConsentAnalytics}
+
+// ConsentEvent is one consent choice, kept as a record of when and under which policy
+// version it was made. Events are never changed; a new choice is a new event.
+type ConsentEvent struct {
+	ID            string         `json:"id"`
+	UserID        string         `json:"user_id"`
+	Purpose       ConsentPurpose `json:"purpose"`
+	Granted       bool           `json:"granted"`
+	PolicyVersion string         `json:"policy_version"`
+	Source        string         `json:"source,omitempty"` // where the choice was made, e.g. cookie_banner or account
+	RecordedAt    time.Time      `json:"recorded_at"`
+}
+
+// ConsentChoice is a choice to record for one purpose
+type ConsentChoice struct {
+	Purpose ConsentPurpose
+	Granted bool
+}
+
+// ConsentPreference is a user's current choice for one purpose. Purposes without a
+// recorded choice are not granted.
+type ConsentPreference struct {
+	Purpose       ConsentPurpose `json:"purpose"`
+	Granted       bool           `json:"granted"`
+	PolicyVersion string         `json:"policy_version,omitempty"`
+	RecordedAt    *time.Time     `json:"recorded_at,omitempty"`
+	Outdated      bool           `json:"outdated"` // chosen under an older policy version; ask again
+}
+
+// ConsentRepository defines persistence for consent events
+type ConsentRepository interface {
+	Append(ctx context.Context, events []*ConsentEvent) error
+	// FindLatest returns the user's most recent event for each purpose
+	FindLatest(ctx context.Context, userID string) ([]*ConsentEvent, error)
+	// ListByUser lists a user's events, newest first
+	ListByUser(ctx context.Context, userID string) ([]*ConsentEvent, error)
+}
+
+// ConsentService records consent choices and answers whether a user may be contacted
+// or tracked for a purpose
+type ConsentService struct {
+	repo          ConsentRepository
+	policyVersion string
+}
+
+// NewConsentService creates a new ConsentService. policyVersion is the privacy policy
+// currently shown to users; choices made under another version are marked outdated.
+func NewConsentService(repo ConsentRepository, policyVersion string) *ConsentService {
+	return &ConsentService{repo: repo, policyVersion: policyVersion}
+}
+
+// PolicyVersion returns the current privacy policy version
+func (s *ConsentService) PolicyVersion() string {
+	return s.policyVersion
+}
+
+// Record stores a user's choices, under the current policy version unless another is given
+func (s *ConsentService) Record(ctx context.Context, userID string, choices []ConsentChoice, policyVersion, source string) ([]*ConsentEvent, error) {
+	if policyVersion == "" {
+		policyVersion = s.policyVersion
+	}
+	if policyVersion == "" {
+		return nil, ErrConsentPolicyRequired
+	}
+
+	now := time.Now()
+	events := make([]*ConsentEvent, 0, len(choices))
+	for _, choice := range choices {
+		if !validConsentPurpose(choice.Purpose) {
+			return nil, ErrInvalidConsentPurpose
+		}
+		events = append(events, &ConsentEvent{
+			ID:            utils.GenerateID(),
+			UserID:        userID,
+			Purpose:       choice.Purpose,
+			Granted:       choice.Granted,
+			PolicyVersion: policyVersion,
+			Source:        source,
+			RecordedAt:    now,
+		})
+	}
+
+	if err := s.repo.Append(ctx, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Preferences returns the user's current choice for every purpose
+func (s *ConsentService) Preferences(ctx context.Context, userID string) ([]ConsentPreference, error) {
+	latest, err := s.repo.FindLatest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byPurpose := make(map[ConsentPurpose]*ConsentEvent, len(latest))
+	for _, event := range latest {
+		byPurpose[event.Purpose] = event
+	}
+
+	preferences := make([]ConsentPreference, 0, len(consentPurposes))
+	for _, purpose := range consentPurposes {
+		preference := ConsentPreference{Purpose: purpose}
+		if event, ok := byPurpose[purpose]; ok {
+			recordedAt := event.RecordedAt
+			preference.Granted = event.Granted
+			preference.PolicyVersion = event.PolicyVersion
+			preference.RecordedAt = &recordedAt
+			preference.Outdated = s.policyVersion != "" && event.PolicyVersion != s.policyVersion
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+// History lists every choice a user has made, newest first
+func (s *ConsentService) History(ctx context.Context, userID string) ([]*ConsentEvent, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Allows reports whether the user's latest choice grants the purpose. Anything that sends
+// marketing messages must check this first; users who never chose are not contacted.
+func (s *ConsentService) Allows(ctx context.Context, userID string, purpose ConsentPurpose) (bool, error) {
+	latest, err := s.repo.FindLatest(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, event := range latest {
+		if event.Purpose == purpose {
+			return event.Granted, nil
+		}
+	}
+	return false, nil
+}
+
+func validConsentPurpose(purpose ConsentPurpose) bool {
+	for _, p := range consentPurposes {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidReport   = errors.New("invalid report")
+	ErrAlreadyReported = errors.New("you have already reported this content")
+	ErrNoOpenReports   = errors.New("content has no open reports")
+)
+
+// ReportContentType is the kind of content a report is about
+type ReportContentType string
+
+const (
+	ReportContentReview   ReportContentType = "review"
+	ReportContentQuestion ReportContentType = "question"
+	ReportContentAnswer   ReportContentType = "answer"
+)
+
+// ReportReason is why a user reported content
+type ReportReason string
+
+const (
+	ReportReasonSpam      ReportReason = "spam"
+	ReportReasonOffensive ReportReason = "offensive"
+	ReportReasonOffTopic  ReportReason = "off_topic"
+	ReportReasonOther     ReportReason = "other"
+)
+
+// IsValid reports whether the reason is a known report reason
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonOffensive, ReportReasonOffTopic, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus is where a report is in moderation
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"      // waiting on a moderator
+	ReportStatusDismissed ReportStatus = "dismissed" // the content was fine and is shown again
+	ReportStatusUpheld    ReportStatus = "upheld"    // the content was removed
+)
+
+// ReportAction is what a moderator decides about reported content
+type ReportAction string
+
+const (
+	ReportActionDismiss ReportAction = "dismiss" // show the content again and close its reports
+	ReportActionRemove  ReportAction = "remove"  // keep the content hidden and close its reports
+)
+
+// ContentReport is one user's report of a review, question or answer
+type ContentReport struct {
+	ID          string            `json:"id"`
+	ContentType ReportContentType `json:"content_type"`
+	ContentID   string            `json:"content_id"`
+	UserID      string            `json:"user_id"`
+	Reason      ReportReason      `json:"reason"`
+	Details     string            `json:"details,omitempty"`
+	Status      ReportStatus      `json:"status"`
+	ResolvedBy  string            `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time        `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// ReportedContent is a piece of content in the moderation queue with its open reports
+type ReportedContent struct {
+	ContentType ReportContentType `json:"content_type"`
+	ContentID   string            `json:"content_id"`
+	Excerpt     string            `json:"excerpt"`
+	Hidden      bool              `json:"hidden"`
+	ReportCount int               `json:"report_count"`
+	Reports     []*ContentReport  `json:"reports"`
+}
+
+// ContentReportRepository defines persistence for content reports
+type ContentReportRepository interface {
+	// Add records a report, returning ErrAlreadyReported when the user already reported the
+	// content
+	Add(ctx context.Context, report *ContentReport) error
+	CountOpen(ctx context.Context, contentType ReportContentType, contentID string) (int, error)
+	// FindOpen returns every open report, oldest first
+	FindOpen(ctx context.Context) ([]*ContentReport, error)
+	// Resolve closes the content's open reports with a status and returns how many it closed
+	Resolve(ctx context.Context, contentType ReportContentType, contentID string, status ReportStatus, resolvedBy string, resolvedAt time.Time) (int, error)
+}
+
+// ContentReportService lets users report abusive reviews, questions and answers. Content
+// is hidden once hideAfter users have reported it, until a moderator dismisses the reports.
+type ContentReportService struct {
+	repo      ContentReportRepository
+	reviews   ReviewRepository
+	questions QuestionRepository
+	hideAfter int
+}
+
+// NewContentReportService creates a new ContentReportService hiding content after hideAfter
+// open reports; 0 never hides content automatically
+func NewContentReportService(repo ContentReportRepository, reviews ReviewRepository, questions QuestionRepository, hideAfter int) *ContentReportService {
+	return &ContentReportService{repo: repo, reviews: reviews, questions: questions, hideAfter: hideAfter}
+}
+
+// reportedContent is what reporting and moderation need to know about a piece of content
+type reportedContent struct {
+	ownerID string
+	text    string
+	visible bool // other users can see it
+	hidden  bool
+}
+
+// Report records a user's report of content they can see. Users can't report their own
+// content, and each user reports a piece of content once.
+func (s *ContentReportService) Report(ctx context.Context, userID string, contentType ReportContentType, contentID string, reason ReportReason, details string) (*ContentReport, error) {
+	if !reason.IsValid() {
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidReport, reason)
+	}
+	details = strings.TrimSpace(details)
+	if utf8.RuneCountInString(details) > 1000 {
+		return nil, fmt.Errorf("%w: details must be at most 1000 characters", ErrInvalidReport)
+	}
+	content, err := s.content(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if !content.visible {
+		return nil, notFoundError(contentType)
+	}
+	if content.ownerID == userID {
+		return nil, fmt.Errorf("%w: you can't report your own content", ErrInvalidReport)
+	}
+
+	report := &ContentReport{
+		ID:          utils.GenerateID(),
+		ContentType: contentType,
+		ContentID:   contentID,
+		UserID:      userID,
+		Reason:      reason,
+		Details:     details,
+		Status:      ReportStatusOpen,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.Add(ctx, report); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountOpen(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if !content.hidden && s.hideAfter > 0 && count >= s.hideAfter {
+		if err := s.setHidden(ctx, contentType, contentID, true); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Queue returns the content with open reports, most reported first
+func (s *ContentReportService) Queue(ctx context.Context) ([]*ReportedContent, error) {
+	reports, err := s.repo.FindOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]*ReportedContent, 0)
+	byContent := make(map[string]*ReportedContent)
+	for _, report := range reports {
+		key := string(report.ContentType) + ":" + report.ContentID
+		entry, ok := byContent[key]
+		if !ok {
+			entry = &ReportedContent{ContentType: report.ContentType, ContentID: report.ContentID, Reports: []*ContentReport{}}
+			content, err := s.content(ctx, report.ContentType, report.ContentID)
+			switch {
+			case err == nil:
+				entry.Excerpt = contentExcerpt(content.text)
+				entry.Hidden = content.hidden
+			case !errors.Is(err, notFoundError(report.ContentType)):
+				return nil, err
+			}
+			byContent[key] = entry
+			queue = append(queue, entry)
+		}
+		entry.Reports = append(entry.Reports, report)
+		entry.ReportCount++
+	}
+
+	// Ties keep the oldest report first
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].ReportCount > queue[j].ReportCount })
+	return queue, nil
+}
+
+// Resolve closes the content's open reports. Dismissing shows the content again; removing
+// keeps it hidden.
+func (s *ContentReportService) Resolve(ctx context.Context, contentType ReportContentType, contentID, moderatorID string, action ReportAction) (*ReportedContent, error) {
+	var hidden bool
+	var status ReportStatus
+	switch action {
+	case ReportActionDismiss:
+		hidden, status = false, ReportStatusDismissed
+	case ReportActionRemove:
+		hidden, status = true, ReportStatusUpheld
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidReport, action)
+	}
+	content, err := s.content(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountOpen(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrNoOpenReports
+	}
+	if content.hidden != hidden {
+		if err := s.setHidden(ctx, contentType, contentID, hidden); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.repo.Resolve(ctx, contentType, contentID, status, moderatorID, time.Now()); err != nil {
+		return nil, err
+	}
+	return &ReportedContent{
+		ContentType: contentType,
+		ContentID:   contentID,
+		Excerpt:     contentExcerpt(content.text),
+		Hidden:      hidden,
+		Reports:     []*ContentReport{},
+	}, nil
+}
+
+// content loads what reporting needs to know about a review, question or answer
+func (s *ContentReportService) content(ctx context.Context, contentType ReportContentType, contentID string) (*reportedContent, error) {
+	switch contentType {
+	case ReportContentReview:
+		review, err := s.reviews.FindByID(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		text := review.Body
+		if review.Title != "" {
+			text = review.Title + ": " + review.Body
+		}
+		return &reportedContent{
+			ownerID: review.UserID,
+			text:    text,
+			visible: review.Status == ReviewStatusApproved,
+			hidden:  review.Hidden,
+		}, nil
+	case ReportContentQuestion:
+		question, err := s.questions.FindByID(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		return &reportedContent{ownerID: question.UserID, text: question.Body, visible: true, hidden: question.Hidden}, nil
+	case ReportContentAnswer:
+		answer, err := s.questions.FindAnswerByID(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		return &reportedContent{ownerID: answer.UserID, text: answer.Body, visible: true, hidden: answer.Hidden}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown content type %q", ErrInvalidReport, contentType)
+}
+
+func (s *ContentReportService) setHidden(ctx context.Context, contentType ReportContentType, contentID string, hidden bool) error {
+	switch contentType {
+	case ReportContentReview:
+		return s.reviews.SetHidden(ctx, contentID, hidden)
+	case ReportContentQuestion:
+		return s.questions.SetQuestionHidden(ctx, contentID, hidden)
+	default:
+		return s.questions.SetAnswerHidden(ctx, contentID, hidden)
+	}
+}
+
+// notFoundError returns the not found error for a content type
+func notFoundError(contentType ReportContentType) error {
+	switch contentType {
+	case ReportContentQuestion:
+		return ErrQuestionNotFound
+	case ReportContentAnswer:
+		return ErrAnswerNotFound
+	}
+	return ErrReviewNotFound
+}
+
+// contentExcerpt shortens content text for the moderation queue
+func contentExcerpt(text string) string {
+	const maxExcerpt = 200
+	if utf8.RuneCountInString(text) <= maxExcerpt {
+		return text
+	}
+	return string([]rune(text)[:maxExcerpt]) + "…"
+}
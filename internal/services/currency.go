@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+var ErrExchangeRateNotFound = errors.New("no exchange rate between these currencies")
+
+// CurrencyService converts amounts between currencies using exchange rates quoted against
+// a base currency
+type CurrencyService struct {
+	base   string
+	rates  map[string]float64 // units of each currency per one unit of base
+	policy *PricePolicy
+}
+
+// NewCurrencyService creates a new CurrencyService. Rates give how much of each currency
+// one unit of base buys, e.g. EUR: 0.92 with a USD base.
+func NewCurrencyService(base string, rates map[string]float64) *CurrencyService {
+	normalized := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		normalized[strings.ToUpper(currency)] = rate
+	}
+	return &CurrencyService{base: strings.ToUpper(base), rates: normalized}
+}
+
+// WithPricePolicy rounds prices converted with ConvertPrice by the policy of their new
+// currency. Convert stays exact, for thresholds such as minimum order values.
+func (s *CurrencyService) WithPricePolicy(policy *PricePolicy) *CurrencyService {
+	s.policy = policy
+	return s
+}
+
+// ParseExchangeRates parses CODE:rate entries such as EUR:0.92 into rates for NewCurrencyService
+func ParseExchangeRates(entries []string) (map[string]float64, error) {
+	rates := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		currency, value, ok := strings.Cut(entry, ":")
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if !ok || len(currency) != 3 {
+			return nil, fmt.Errorf("exchange rate %q must be CODE:rate", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("exchange rate for %s must be a positive number", currency)
+		}
+		if _, dup := rates[currency]; dup {
+			return nil, fmt.Errorf("exchange rate for %s is given twice", currency)
+		}
+		rates[currency] = rate
+	}
+	return rates, nil
+}
+
+// Convert converts an amount into another currency, rounding to the nearest minor unit.
+// Amounts already in that currency are returned unchanged.
+func (s *CurrencyService) Convert(amount money.Money, currency string) (money.Money, error) {
+	currency = strings.ToUpper(currency)
+	if strings.EqualFold(amount.Currency, currency) {
+		return money.Money{Amount: amount.Amount, Currency: currency}, nil
+	}
+
+	from, ok := s.rate(amount.Currency)
+	if !ok {
+		return money.Money{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, amount.Currency, currency)
+	}
+	to, ok := s.rate(currency)
+	if !ok {
+		return money.Money{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, amount.Currency, currency)
+	}
+	return money.Money{Amount: int64(math.Round(float64(amount.Amount) / from * to)), Currency: currency}, nil
+}
+
+// ConvertPrice converts a price shown to shoppers into another currency and rounds it by
+// that currency's price policy. Prices already in that currency are returned unchanged.
+func (s *CurrencyService) ConvertPrice(price money.Money, currency string) (money.Money, error) {
+	if strings.EqualFold(price.Currency, currency) {
+		return s.Convert(price, currency)
+	}
+	converted, err := s.Convert(price, currency)
+	if err != nil || s.policy == nil {
+		return converted, err
+	}
+	return s.policy.Apply(converted), nil
+}
+
+// rate returns the units of a currency one unit of base buys
+func (s *CurrencyService) rate(currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == s.base {
+		return 1, true
+	}
+	rate, ok := s.rates[currency]
+	return rate, ok
+}
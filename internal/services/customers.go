@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidProfile = errors.New("names must be at most 100 characters, phone a valid phone number and birthday a past date in YYYY-MM-DD format")
+	ErrInvalidAvatar  = errors.New("avatar must be a JPEG, PNG, GIF or WebP image within the size limit")
+)
+
+const maxProfileNameLength = 100
+
+// phonePattern accepts international numbers after spaces, dashes, dots and parentheses
+// are removed: an optional + and 7 to 15 digits
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// avatarExtensions maps the image types accepted as avatars to their file extensions
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// CustomerProfile is the storefront profile of a customer, kept apart from their goauthx
+// credentials and linked by user ID
+type CustomerProfile struct {
+	UserID    string    `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Phone     string    `json:"phone"`
+	Birthday  string    `json:"birthday,omitempty"` // YYYY-MM-DD
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	AvatarKey string    `json:"-"` // media storage key of the avatar
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProfileUpdate changes the fields that are set; an empty string clears a field
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	Phone     *string
+	Birthday  *string // YYYY-MM-DD
+}
+
+// CustomerRepository defines persistence for customer profiles
+type CustomerRepository interface {
+	// FindByUserID returns nil when the customer has no profile yet
+	FindByUserID(ctx context.Context, userID string) (*CustomerProfile, error)
+	Save(ctx context.Context, profile *CustomerProfile) error
+}
+
+// CustomerService manages customer profiles and avatars
+type CustomerService struct {
+	repo          CustomerRepository
+	media         MediaStorage
+	maxAvatarSize int64
+}
+
+// NewCustomerService creates a new CustomerService storing avatars of up to maxAvatarSize
+// bytes in media
+func NewCustomerService(repo CustomerRepository, media MediaStorage, maxAvatarSize int64) *CustomerService {
+	return &CustomerService{
+		repo:          repo,
+		media:         media,
+		maxAvatarSize: maxAvatarSize,
+	}
+}
+
+// MaxAvatarSize returns the largest avatar accepted, in bytes
+func (s *CustomerService) MaxAvatarSize() int64 {
+	return s.maxAvatarSize
+}
+
+// GetProfile returns a customer's profile, empty when they haven't filled it in
+func (s *CustomerService) GetProfile(ctx context.Context, userID string) (*CustomerProfile, error) {
+	profile, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &CustomerProfile{UserID: userID}
+	}
+	return profile, nil
+}
+
+// UpdateProfile validates and applies a profile update
+func (s *CustomerService) UpdateProfile(ctx context.Context, userID string, update ProfileUpdate) (*CustomerProfile, error) {
+	profile, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.FirstName != nil {
+		if profile.FirstName, err = normalizeProfileName(*update.FirstName); err != nil {
+			return nil, err
+		}
+	}
+	if update.LastName != nil {
+		if profile.LastName, err = normalizeProfileName(*update.LastName); err != nil {
+			return nil, err
+		}
+	}
+	if update.Phone != nil {
+		if profile.Phone, err = normalizePhone(*update.Phone); err != nil {
+			return nil, err
+		}
+	}
+	if update.Birthday != nil {
+		if profile.Birthday, err = normalizeBirthday(*update.Birthday, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.save(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// SetAvatar stores a new avatar image and replaces the previous one. The image type is
+// detected from the content, not taken from the client.
+func (s *CustomerService) SetAvatar(ctx context.Context, userID string, image io.Reader) (*CustomerProfile, error) {
+	data, err := io.ReadAll(io.LimitReader(image, s.maxAvatarSize+1))
+	if err != nil {
+		return nil, err
+	}
+	extension, ok := avatarExtensions[http.DetectContentType(data)]
+	if !ok || len(data) == 0 || int64(len(data)) > s.maxAvatarSize {
+		return nil, ErrInvalidAvatar
+	}
+
+	profile, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new key per upload so caches and CDNs never serve the previous avatar
+	key := fmt.Sprintf("avatars/%s/%s%s", userID, utils.GenerateID(), extension)
+	url, err := s.media.Put(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	previousKey := profile.AvatarKey
+	profile.AvatarKey = key
+	profile.AvatarURL = url
+	if err := s.save(ctx, profile); err != nil {
+		s.deleteAvatar(ctx, key)
+		return nil, err
+	}
+	s.deleteAvatar(ctx, previousKey)
+	return profile, nil
+}
+
+// RemoveAvatar removes a customer's avatar
+func (s *CustomerService) RemoveAvatar(ctx context.Context, userID string) (*CustomerProfile, error) {
+	profile, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.AvatarKey == "" {
+		return profile, nil
+	}
+
+	previousKey := profile.AvatarKey
+	profile.AvatarKey = ""
+	profile.AvatarURL = ""
+	if err := s.save(ctx, profile); err != nil {
+		return nil, err
+	}
+	s.deleteAvatar(ctx, previousKey)
+	return profile, nil
+}
+
+// save stamps and saves a profile
+func (s *CustomerService) save(ctx context.Context, profile *CustomerProfile) error {
+	now := time.Now()
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = now
+	}
+	profile.UpdatedAt = now
+	return s.repo.Save(ctx, profile)
+}
+
+// deleteAvatar removes a replaced avatar file. The profile no longer points at it, so a
+// failure only leaves an orphaned file behind.
+func (s *CustomerService) deleteAvatar(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.media.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete avatar %s: %v", key, err)
+	}
+}
+
+// normalizeProfileName trims a name and checks its length
+func normalizeProfileName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxProfileNameLength {
+		return "", ErrInvalidProfile
+	}
+	return name, nil
+}
+
+// normalizePhone removes spaces, dashes, dots and parentheses from a phone number
+func normalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return "", ErrInvalidProfile
+	}
+	return phone, nil
+}
+
+// normalizeBirthday checks a birthday is a real date in the past, within 130 years
+func normalizeBirthday(birthday string, now time.Time) (string, error) {
+	birthday = strings.TrimSpace(birthday)
+	if birthday == "" {
+		return "", nil
+	}
+	date, err := time.Parse("2006-01-02", birthday)
+	if err != nil || !date.Before(now) || date.Before(now.AddDate(-130, 0, 0)) {
+		return "", ErrInvalidProfile
+	}
+	return date.Format("2006-01-02"), nil
+}
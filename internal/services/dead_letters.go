@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrDeadLetterNotFound       = errors.New("dead letter not found")
+	ErrDeadLetterKindUnknown    = errors.New("no replayer is registered for the dead letter's kind")
+	ErrDeadLetterReplayed       = errors.New("dead letter was already replayed")
+	ErrDeadLetterReplaying      = errors.New("dead letter is already being replayed")
+	ErrDeadLetterReplayerExists = errors.New("dead letter replayer is already registered")
+)
+
+// DeadLetterStatus tracks a failed job from failure to successful replay
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusDead      DeadLetterStatus = "dead"
+	DeadLetterStatusReplaying DeadLetterStatus = "replaying"
+	DeadLetterStatusReplayed  DeadLetterStatus = "replayed"
+)
+
+// DeadLetter is a background job that failed, kept with its payload so it can be replayed
+// once whatever made it fail is fixed
+type DeadLetter struct {
+	ID         string           `json:"id"`
+	Kind       string           `json:"kind"`
+	Name       string           `json:"name"`
+	Payload    json.RawMessage  `json:"payload"`
+	Status     DeadLetterStatus `json:"status"`
+	Attempts   int              `json:"attempts"` // failed runs, counting the original
+	LastError  string           `json:"last_error"`
+	CreatedAt  time.Time        `json:"created_at"`
+	FailedAt   time.Time        `json:"failed_at"`
+	ReplayedAt *time.Time       `json:"replayed_at,omitempty"`
+}
+
+// DeadLetterFilter filters the admin dead-letter listing
+type DeadLetterFilter struct {
+	Kind   string
+	Status DeadLetterStatus
+	Limit  int
+	Offset int
+}
+
+// DeadLetterRepository defines persistence for dead letters
+type DeadLetterRepository interface {
+	FindByID(ctx context.Context, id string) (*DeadLetter, error)
+	List(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error)
+	Count(ctx context.Context, filter DeadLetterFilter) (int64, error)
+	Save(ctx context.Context, letter *DeadLetter) error
+}
+
+// Replayer runs a dead-lettered job again from its payload
+type Replayer func(ctx context.Context, payload []byte) error
+
+// DeadLetterService stores jobs that failed on the job runner and replays them on request.
+// Each job kind that can be replayed registers a Replayer; a replay that fails again keeps
+// the dead letter with its attempt count raised.
+type DeadLetterService struct {
+	repo      DeadLetterRepository
+	runner    *jobs.Runner
+	mu        sync.Mutex
+	replayers map[string]Replayer
+	replaying map[string]struct{} // dead letters handed to the runner and not yet finished
+}
+
+// NewDeadLetterService creates a new DeadLetterService
+func NewDeadLetterService(repo DeadLetterRepository, runner *jobs.Runner) *DeadLetterService {
+	return &DeadLetterService{
+		repo:      repo,
+		runner:    runner,
+		replayers: make(map[string]Replayer),
+		replaying: make(map[string]struct{}),
+	}
+}
+
+// RegisterReplayer adds the replayer for a job kind
+func (s *DeadLetterService) RegisterReplayer(kind string, replayer Replayer) error {
+	kind = strings.TrimSpace(kind)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.replayers[kind]; exists {
+		return ErrDeadLetterReplayerExists
+	}
+	s.replayers[kind] = replayer
+	return nil
+}
+
+// Record stores a failed job. It is the job runner's dead-letter handler, so it runs on a
+// worker after the job's context may have been canceled.
+func (s *DeadLetterService) Record(job jobs.Job, err error) {
+	now := time.Now()
+	letter := &DeadLetter{
+		ID:        utils.GenerateID(),
+		Kind:      job.Kind,
+		Name:      job.Name,
+		Payload:   deadLetterPayload(job.Payload),
+		Status:    DeadLetterStatusDead,
+		Attempts:  1,
+		LastError: err.Error(),
+		CreatedAt: now,
+		FailedAt:  now,
+	}
+	if saveErr := s.repo.Save(context.Background(), letter); saveErr != nil {
+		log.Printf("Failed to dead-letter job %s: %v", job.Name, saveErr)
+	}
+}
+
+// ListDeadLetters lists dead letters, newest failure first
+func (s *DeadLetterService) ListDeadLetters(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountDeadLetters counts dead letters matching the filter
+func (s *DeadLetterService) CountDeadLetters(ctx context.Context, filter DeadLetterFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetDeadLetter returns a dead letter by ID
+func (s *DeadLetterService) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Replay queues a dead letter to run again with its kind's replayer. A dead letter left
+// replaying by a restart can be replayed again; one already replayed cannot.
+func (s *DeadLetterService) Replay(ctx context.Context, id string) (*DeadLetter, error) {
+	letter, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if letter.Status == DeadLetterStatusReplayed {
+		return nil, ErrDeadLetterReplayed
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replayer, ok := s.replayers[letter.Kind]
+	if !ok {
+		return nil, ErrDeadLetterKindUnknown
+	}
+	if _, ok := s.replaying[letter.ID]; ok {
+		return nil, ErrDeadLetterReplaying
+	}
+
+	previous := letter.Status
+	letter.Status = DeadLetterStatusReplaying
+	if err := s.repo.Save(ctx, letter); err != nil {
+		return nil, err
+	}
+
+	err = s.runner.Enqueue(jobs.Job{
+		Name: "replay:" + letter.Name,
+		Run: func(ctx context.Context) error {
+			defer func() {
+				s.mu.Lock()
+				delete(s.replaying, id)
+				s.mu.Unlock()
+			}()
+			return s.settle(id, replayer(ctx, letter.Payload))
+		},
+	})
+	if err != nil {
+		letter.Status = previous
+		if saveErr := s.repo.Save(ctx, letter); saveErr != nil {
+			log.Printf("Failed to restore dead letter %s: %v", id, saveErr)
+		}
+		return nil, err
+	}
+	s.replaying[letter.ID] = struct{}{}
+	return letter, nil
+}
+
+// settle records the outcome of a replay and returns the replay's error
+func (s *DeadLetterService) settle(id string, replayErr error) error {
+	ctx := context.Background()
+	letter, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if replayErr != nil {
+		letter.Status = DeadLetterStatusDead
+		letter.Attempts++
+		letter.LastError = replayErr.Error()
+		letter.FailedAt = now
+	} else {
+		letter.Status = DeadLetterStatusReplayed
+		letter.ReplayedAt = &now
+	}
+	if err := s.repo.Save(ctx, letter); err != nil {
+		return err
+	}
+	return replayErr
+}
+
+// deadLetterPayload keeps JSON payloads as they are and stores anything else as a JSON string
+func deadLetterPayload(payload []byte) json.RawMessage {
+	if len(payload) == 0 {
+		return json.RawMessage("null")
+	}
+	if json.Valid(payload) {
+		return json.RawMessage(payload)
+	}
+	encoded, _ := json.Marshal(string(payload))
+	return encoded
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrDeliverySlotNotFound    = errors.New("delivery slot not found")
+	ErrDeliverySlotFull        = errors.New("delivery slot is fully booked")
+	ErrDeliverySlotUnavailable = errors.New("delivery slot is not available for this address")
+	ErrInvalidDeliverySlot     = errors.New("delivery slot requires a region, positive capacity and a valid time range")
+)
+
+// DefaultDeliveryWindowDays is how far ahead slot availability is listed when no range is given
+const DefaultDeliveryWindowDays = 14
+
+// DeliverySlot is a delivery window with a fixed number of bookable orders
+type DeliverySlot struct {
+	ID       string    `json:"id"`
+	Region   string    `json:"region"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Capacity int       `json:"capacity"`
+	Reserved int       `json:"reserved"`
+	IsActive bool      `json:"is_active"`
+}
+
+// Remaining returns the number of orders that can still book this slot
+func (s *DeliverySlot) Remaining() int {
+	if s.Reserved >= s.Capacity {
+		return 0
+	}
+	return s.Capacity - s.Reserved
+}
+
+// ServesPostalCode reports whether the slot's region covers the given postal code
+func (s *DeliverySlot) ServesPostalCode(postalCode string) bool {
+	return strings.HasPrefix(NormalizePostalCode(postalCode), NormalizePostalCode(s.Region))
+}
+
+// DeliverySlotRepository defines persistence for delivery slots and order selections
+type DeliverySlotRepository interface {
+	FindByID(ctx context.Context, id string) (*DeliverySlot, error)
+	FindActiveBetween(ctx context.Context, from, to time.Time) ([]*DeliverySlot, error)
+	FindByOrderID(ctx context.Context, orderID string) (*DeliverySlot, error)
+	Save(ctx context.Context, slot *DeliverySlot) error
+	// Reserve atomically books one unit of slot capacity for the order
+	Reserve(ctx context.Context, slotID, orderID string) error
+	// Release frees the capacity held by the order, if any
+	Release(ctx context.Context, orderID string) error
+}
+
+// DeliveryService manages delivery slot availability and booking
+type DeliveryService struct {
+	repo DeliverySlotRepository
+}
+
+// NewDeliveryService creates a new DeliveryService
+func NewDeliveryService(repo DeliverySlotRepository) *DeliveryService {
+	return &DeliveryService{repo: repo}
+}
+
+// AvailableSlots lists bookable slots serving the postal code within the given range
+func (s *DeliveryService) AvailableSlots(ctx context.Context, postalCode string, from, to time.Time) ([]*DeliverySlot, error) {
+	slots, err := s.repo.FindActiveBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*DeliverySlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Remaining() > 0 && slot.ServesPostalCode(postalCode) {
+			available = append(available, slot)
+		}
+	}
+	return available, nil
+}
+
+// ValidateSlot checks that a slot can be booked for the postal code without reserving it
+func (s *DeliveryService) ValidateSlot(ctx context.Context, slotID, postalCode string) (*DeliverySlot, error) {
+	slot, err := s.repo.FindByID(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slot.IsActive || slot.StartsAt.Before(time.Now()) || !slot.ServesPostalCode(postalCode) {
+		return nil, ErrDeliverySlotUnavailable
+	}
+	if slot.Remaining() == 0 {
+		return nil, ErrDeliverySlotFull
+	}
+	return slot, nil
+}
+
+// ReserveSlot books the slot for an order after validating it against the postal code
+func (s *DeliveryService) ReserveSlot(ctx context.Context, slotID, orderID, postalCode string) (*DeliverySlot, error) {
+	if _, err := s.ValidateSlot(ctx, slotID, postalCode); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Reserve(ctx, slotID, orderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, slotID)
+}
+
+// ReleaseSlot frees the slot booked by an order
+func (s *DeliveryService) ReleaseSlot(ctx context.Context, orderID string) error {
+	return s.repo.Release(ctx, orderID)
+}
+
+// GetOrderSlot returns the slot booked for an order, or nil if none was selected
+func (s *DeliveryService) GetOrderSlot(ctx context.Context, orderID string) (*DeliverySlot, error) {
+	slot, err := s.repo.FindByOrderID(ctx, orderID)
+	if err == ErrDeliverySlotNotFound {
+		return nil, nil
+	}
+	return slot, err
+}
+
+// CreateSlot creates a new delivery slot
+func (s *DeliveryService) CreateSlot(ctx context.Context, slot *DeliverySlot) error {
+	if slot.Region == "" || slot.Capacity <= 0 || !slot.EndsAt.After(slot.StartsAt) {
+		return ErrInvalidDeliverySlot
+	}
+	if slot.ID == "" {
+		slot.ID = utils.GenerateID()
+	}
+	return s.repo.Save(ctx, slot)
+}
+
+// NormalizePostalCode uppercases a postal code and strips spaces and dashes
+func NormalizePostalCode(postalCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(postalCode)))
+}
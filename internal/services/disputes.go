@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrDisputeNotFound     = errors.New("dispute not found")
+	ErrDisputeClosed       = errors.New("dispute is closed")
+	ErrDisputeOrderUnknown = errors.New("dispute does not match a known payment or order")
+	ErrInvalidDisputeEvent = errors.New("dispute event requires a dispute ID, a status and a payment reference or order ID")
+)
+
+// DisputeStatus mirrors the gateway's dispute lifecycle
+type DisputeStatus string
+
+const (
+	DisputeStatusNeedsResponse DisputeStatus = "needs_response"
+	DisputeStatusUnderReview   DisputeStatus = "under_review"
+	DisputeStatusWon           DisputeStatus = "won"
+	DisputeStatusLost          DisputeStatus = "lost"
+)
+
+// IsValid reports whether the status is a known dispute status
+func (s DisputeStatus) IsValid() bool {
+	switch s {
+	case DisputeStatusNeedsResponse, DisputeStatusUnderReview, DisputeStatusWon, DisputeStatusLost:
+		return true
+	}
+	return false
+}
+
+// EvidenceStatus tracks whether we have responded to a dispute
+type EvidenceStatus string
+
+const (
+	EvidenceStatusNotSubmitted EvidenceStatus = "not_submitted"
+	EvidenceStatusSubmitted    EvidenceStatus = "submitted"
+)
+
+// Dispute is a chargeback raised by the customer's bank against an order payment
+type Dispute struct {
+	ID                  string         `json:"id"`
+	OrderID             string         `json:"order_id"`
+	TenderID            string         `json:"tender_id,omitempty"`
+	GatewayDisputeID    string         `json:"gateway_dispute_id"`
+	GatewayReference    string         `json:"gateway_reference,omitempty"`
+	Amount              money.Money    `json:"amount"`
+	Reason              string         `json:"reason"`
+	Status              DisputeStatus  `json:"status"`
+	EvidenceStatus      EvidenceStatus `json:"evidence_status"`
+	EvidenceNotes       string         `json:"evidence_notes,omitempty"`
+	EvidenceDueBy       *time.Time     `json:"evidence_due_by,omitempty"`
+	EvidenceSubmittedAt *time.Time     `json:"evidence_submitted_at,omitempty"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// IsOpen reports whether the dispute is still awaiting a decision
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeStatusNeedsResponse || d.Status == DisputeStatusUnderReview
+}
+
+// DisputeEvent is a dispute notification received from the payment gateway
+type DisputeEvent struct {
+	GatewayDisputeID string
+	GatewayReference string // payment intent the dispute was raised against
+	OrderID          string // optional; used when the payment reference is unknown
+	Amount           money.Money
+	Reason           string
+	Status           DisputeStatus
+	EvidenceDueBy    *time.Time
+}
+
+// DisputeFilter filters the admin dispute queue
+type DisputeFilter struct {
+	Status         DisputeStatus
+	EvidenceStatus EvidenceStatus
+	Limit          int
+	Offset         int
+}
+
+// DisputeRepository defines persistence for disputes
+type DisputeRepository interface {
+	FindByID(ctx context.Context, id string) (*Dispute, error)
+	FindByGatewayID(ctx context.Context, gatewayDisputeID string) (*Dispute, error)
+	FindByOrderID(ctx context.Context, orderID string) ([]*Dispute, error)
+	List(ctx context.Context, filter DisputeFilter) ([]*Dispute, error)
+	Count(ctx context.Context, filter DisputeFilter) (int64, error)
+	Save(ctx context.Context, dispute *Dispute) error
+}
+
+// DisputeService records gateway disputes against orders and tracks evidence submission
+type DisputeService struct {
+	repo        DisputeRepository
+	paymentRepo PaymentRepository
+}
+
+// NewDisputeService creates a new DisputeService
+func NewDisputeService(repo DisputeRepository, paymentRepo PaymentRepository) *DisputeService {
+	return &DisputeService{
+		repo:        repo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// HandleEvent creates or updates the dispute described by a gateway event.
+// Events are matched on the gateway dispute ID, so redelivered events are safe to apply again.
+func (s *DisputeService) HandleEvent(ctx context.Context, event DisputeEvent) (*Dispute, error) {
+	if event.GatewayDisputeID == "" || !event.Status.IsValid() || (event.GatewayReference == "" && event.OrderID == "") {
+		return nil, ErrInvalidDisputeEvent
+	}
+
+	now := time.Now()
+	dispute, err := s.repo.FindByGatewayID(ctx, event.GatewayDisputeID)
+	if err == ErrDisputeNotFound {
+		dispute = &Dispute{
+			ID:               utils.GenerateID(),
+			GatewayDisputeID: event.GatewayDisputeID,
+			GatewayReference: event.GatewayReference,
+			OrderID:          event.OrderID,
+			EvidenceStatus:   EvidenceStatusNotSubmitted,
+			CreatedAt:        now,
+		}
+		if err := s.linkPayment(ctx, dispute); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	dispute.Status = event.Status
+	if !event.Amount.IsZero() {
+		dispute.Amount = event.Amount
+	}
+	if event.Reason != "" {
+		dispute.Reason = event.Reason
+	}
+	if event.EvidenceDueBy != nil {
+		dispute.EvidenceDueBy = event.EvidenceDueBy
+	}
+	dispute.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// ListDisputes returns the admin dispute queue, oldest evidence deadline first
+func (s *DisputeService) ListDisputes(ctx context.Context, filter DisputeFilter) ([]*Dispute, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountDisputes counts disputes matching the filter
+func (s *DisputeService) CountDisputes(ctx context.Context, filter DisputeFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetDispute returns a dispute by ID
+func (s *DisputeService) GetDispute(ctx context.Context, id string) (*Dispute, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetOrderDisputes returns the disputes raised against an order
+func (s *DisputeService) GetOrderDisputes(ctx context.Context, orderID string) ([]*Dispute, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// SubmitEvidence records that evidence has been submitted to the gateway for an open dispute
+func (s *DisputeService) SubmitEvidence(ctx context.Context, id, notes string) (*Dispute, error) {
+	dispute, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.IsOpen() {
+		return nil, ErrDisputeClosed
+	}
+
+	now := time.Now()
+	dispute.EvidenceStatus = EvidenceStatusSubmitted
+	dispute.EvidenceNotes = notes
+	dispute.EvidenceSubmittedAt = &now
+	if dispute.Status == DisputeStatusNeedsResponse {
+		dispute.Status = DisputeStatusUnderReview
+	}
+	dispute.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// linkPayment links a new dispute to the tender and order it was raised against
+func (s *DisputeService) linkPayment(ctx context.Context, dispute *Dispute) error {
+	if dispute.GatewayReference != "" {
+		tender, err := s.paymentRepo.FindByGatewayReference(ctx, dispute.GatewayReference)
+		if err == nil {
+			dispute.TenderID = tender.ID
+			dispute.OrderID = tender.OrderID
+			dispute.Amount = tender.CapturedAmount
+			return nil
+		}
+		if err != ErrTenderNotFound {
+			return err
+		}
+	}
+
+	if dispute.OrderID == "" {
+		return ErrDisputeOrderUnknown
+	}
+	return nil
+}
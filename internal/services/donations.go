@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/pricing"
+)
+
+var (
+	ErrDonationNotFound       = errors.New("product does not take donations")
+	ErrInvalidDonation        = errors.New("minimum amount must be at least 1 and no more than the maximum")
+	ErrDonationAmountRequired = errors.New("donation products need a donation_amount")
+	ErrInvalidDonationAmount  = errors.New("donation amount is outside the product's minimum and maximum")
+	ErrDonationInCart         = errors.New("product is already in the cart with another amount; remove it first to change it")
+	ErrDonationClosed         = errors.New("product no longer takes donations; remove it from the cart")
+)
+
+// DonationAmountAttribute is the cart and order item attribute recording the amount the
+// customer chose to pay for each unit of a donation product, in cents
+const DonationAmountAttribute = "donation_amount"
+
+// DonationProduct makes a product one the customer sets the price of, such as a charity
+// add-on at checkout, within a minimum and an optional maximum
+type DonationProduct struct {
+	ProductID string       `json:"product_id"`
+	MinAmount money.Money  `json:"min_amount"`
+	MaxAmount *money.Money `json:"max_amount,omitempty"` // nil for no maximum
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Allows reports whether the product takes a donation of amount
+func (d *DonationProduct) Allows(amount money.Money) bool {
+	if amount.Currency != d.MinAmount.Currency || amount.Amount < d.MinAmount.Amount {
+		return false
+	}
+	return d.MaxAmount == nil || amount.Amount <= d.MaxAmount.Amount
+}
+
+// DonationRepository defines persistence for donation product settings
+type DonationRepository interface {
+	FindProduct(ctx context.Context, productID string) (*DonationProduct, error)
+	SaveProduct(ctx context.Context, donation *DonationProduct) error
+	DeleteProduct(ctx context.Context, productID string) error
+}
+
+// DonationService manages pay-what-you-want products: their limits, the amount chosen for
+// each cart item and the checks made whenever a cart is priced
+type DonationService struct {
+	repo     DonationRepository
+	products catalog.ProductRepository
+}
+
+// NewDonationService creates a new DonationService
+func NewDonationService(repo DonationRepository, products catalog.ProductRepository) *DonationService {
+	return &DonationService{repo: repo, products: products}
+}
+
+// GetDonation returns a product's donation settings
+func (s *DonationService) GetDonation(ctx context.Context, productID string) (*DonationProduct, error) {
+	return s.repo.FindProduct(ctx, productID)
+}
+
+// SetDonation validates and saves a product's donation settings. The amounts are in the
+// currency of the product's price.
+func (s *DonationService) SetDonation(ctx context.Context, donation *DonationProduct) error {
+	product, err := s.products.FindByID(ctx, donation.ProductID)
+	if err != nil {
+		return ErrProductNotFound
+	}
+	if donation.MinAmount.Amount < 1 || (donation.MaxAmount != nil && donation.MaxAmount.Amount < donation.MinAmount.Amount) {
+		return ErrInvalidDonation
+	}
+
+	donation.MinAmount.Currency = product.BasePrice.Currency
+	if donation.MaxAmount != nil {
+		donation.MaxAmount.Currency = product.BasePrice.Currency
+	}
+	donation.UpdatedAt = time.Now()
+	return s.repo.SaveProduct(ctx, donation)
+}
+
+// RemoveDonation stops a product taking donations. Carts holding it are refused at
+// checkout until it is removed.
+func (s *DonationService) RemoveDonation(ctx context.Context, productID string) error {
+	return s.repo.DeleteProduct(ctx, productID)
+}
+
+// PrepareItem checks an item about to be added to the cart and returns the price of each
+// unit. Items of donation products need an amount within the product's limits, recorded
+// in the item's attributes, and may not already be in the cart. Items of other products
+// have any donation amount removed, and a nil price returned.
+func (s *DonationService) PrepareItem(ctx context.Context, c *cart.Cart, req *cart.AddItemRequest) (*money.Money, error) {
+	donation, err := s.repo.FindProduct(ctx, req.ProductID)
+	if errors.Is(err, ErrDonationNotFound) {
+		delete(req.Attributes, DonationAmountAttribute)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := donationAmount(donation, req.Attributes[DonationAmountAttribute])
+	if err != nil {
+		return nil, err
+	}
+	if cartLine(c, req.ProductID, req.VariantID) != nil {
+		return nil, ErrDonationInCart
+	}
+	return &amount, nil
+}
+
+// CheckItems checks the donation items being priced: each must be of a product still
+// taking donations, priced at an amount within its limits
+func (s *DonationService) CheckItems(ctx context.Context, items []pricing.LineItem) error {
+	for _, item := range items {
+		donation, err := s.repo.FindProduct(ctx, item.ProductID)
+		if errors.Is(err, ErrDonationNotFound) {
+			if item.Attributes[DonationAmountAttribute] != "" {
+				return fmt.Errorf("%w: %s", ErrDonationClosed, item.Name)
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		amount, err := donationAmount(donation, item.Attributes[DonationAmountAttribute])
+		if err != nil {
+			return err
+		}
+		if item.UnitPrice != amount {
+			return fmt.Errorf("%w: %s is priced at %s, not the %s chosen", ErrInvalidDonationAmount, item.Name, item.UnitPrice.String(), amount.String())
+		}
+	}
+	return nil
+}
+
+// donationAmount parses a donation amount in cents and checks it against the product's
+// limits
+func donationAmount(donation *DonationProduct, value string) (money.Money, error) {
+	if value == "" {
+		return money.Money{}, ErrDonationAmountRequired
+	}
+	cents, err := strconv.ParseInt(value, 10, 64)
+	amount := money.Money{Amount: cents, Currency: donation.MinAmount.Currency}
+	if err != nil || !donation.Allows(amount) {
+		limit := "no maximum"
+		if donation.MaxAmount != nil {
+			limit = "at most " + donation.MaxAmount.String()
+		}
+		return money.Money{}, fmt.Errorf("%w: amounts are at least %s with %s", ErrInvalidDonationAmount, donation.MinAmount.String(), limit)
+	}
+	return amount, nil
+}
+
+// isDonation reports whether an item is a donation, whose amount promotions don't discount
+func isDonation(attributes map[string]string) bool {
+	return attributes[DonationAmountAttribute] != ""
+}
+
+// promotionExclusionsKey is the context key of the products promotions skip
+type promotionExclusionsKey struct{}
+
+// withPromotionExclusions records on ctx products no promotion may discount while pricing
+func withPromotionExclusions(ctx context.Context, productIDs []string) context.Context {
+	return context.WithValue(ctx, promotionExclusionsKey{}, productIDs)
+}
+
+// excludingPromotions serves promotions that also exclude the products recorded on the
+// pricing context, leaving the stored promotions as they are
+type excludingPromotions struct {
+	pricing.PromotionRepository
+}
+
+// FindByCode finds a promotion by code, excluding the products recorded on ctx
+func (r excludingPromotions) FindByCode(ctx context.Context, code string) (*pricing.Promotion, error) {
+	promotion, err := r.PromotionRepository.FindByCode(ctx, code)
+	excluded, _ := ctx.Value(promotionExclusionsKey{}).([]string)
+	if err != nil || len(excluded) == 0 {
+		return promotion, err
+	}
+
+	scoped := *promotion
+	scoped.ExcludedProductIDs = append(append([]string(nil), promotion.ExcludedProductIDs...), excluded...)
+	return &scoped, nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidCursor       = errors.New("cursor is malformed")
+	ErrOrderNotFulfillable = errors.New("order is not awaiting fulfillment")
+	ErrInvalidShipment     = errors.New("shipment requires a carrier and tracking number")
+)
+
+const (
+	defaultFulfillmentPageSize = 50
+	maxFulfillmentPageSize     = 200
+)
+
+// FulfillmentCursor marks a position in the open order feed. Orders are returned in
+// (updated_at, id) order, so a warehouse polling with the last cursor it saw receives every
+// order that became ready or changed since, without paging offsets shifting under it.
+type FulfillmentCursor struct {
+	UpdatedAt time.Time
+	OrderID   string
+}
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c FulfillmentCursor) Encode() string {
+	raw := strconv.FormatInt(c.UpdatedAt.UnixNano(), 10) + ":" + c.OrderID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeFulfillmentCursor parses a cursor produced by Encode
+func DecodeFulfillmentCursor(cursor string) (*FulfillmentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, orderID, ok := strings.Cut(string(raw), ":")
+	if !ok || orderID == "" {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &FulfillmentCursor{UpdatedAt: time.Unix(0, n).UTC(), OrderID: orderID}, nil
+}
+
+// OpenOrderPage is one page of the open order feed
+type OpenOrderPage struct {
+	Orders     []*orders.Order
+	NextCursor string // pass back as ?cursor= to continue; unchanged when there is nothing new
+	HasMore    bool
+}
+
+// Shipment is a shipment confirmation posted back by a fulfillment partner
+type Shipment struct {
+	ID             string    `json:"id"`
+	OrderID        string    `json:"order_id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	TrackingURL    string    `json:"tracking_url,omitempty"`
+	ShippedAt      time.Time `json:"shipped_at"`
+	APIKeyID       string    `json:"api_key_id,omitempty"` // the integration that confirmed it
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ShipmentRequest confirms that an order left the warehouse
+type ShipmentRequest struct {
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    string
+	ShippedAt      *time.Time // defaults to now
+	APIKeyID       string
+}
+
+// FulfillmentRepository defines persistence for the 3PL order feed and shipments
+type FulfillmentRepository interface {
+	FindOpenOrders(ctx context.Context, after *FulfillmentCursor, limit int) ([]*orders.Order, error)
+	FindShipments(ctx context.Context, orderID string) ([]*Shipment, error)
+	SaveShipment(ctx context.Context, shipment *Shipment) error
+}
+
+// FulfillmentService exposes paid orders to external warehouses and records their shipments
+type FulfillmentService struct {
+	repo         FulfillmentRepository
+	orderService orders.Service
+	events       *OrderEventService
+	pricing      *PricingAnomalyService
+}
+
+// NewFulfillmentService creates a new FulfillmentService
+func NewFulfillmentService(repo FulfillmentRepository, orderService orders.Service) *FulfillmentService {
+	return &FulfillmentService{
+		repo:         repo,
+		orderService: orderService,
+	}
+}
+
+// OpenOrders returns paid and processing orders after the cursor, oldest change first
+func (s *FulfillmentService) OpenOrders(ctx context.Context, cursor string, limit int) (*OpenOrderPage, error) {
+	if limit <= 0 {
+		limit = defaultFulfillmentPageSize
+	}
+	if limit > maxFulfillmentPageSize {
+		limit = maxFulfillmentPageSize
+	}
+
+	var after *FulfillmentCursor
+	if cursor != "" {
+		var err error
+		if after, err = DecodeFulfillmentCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fetch one extra to learn whether another page follows
+	list, err := s.repo.FindOpenOrders(ctx, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &OpenOrderPage{Orders: list, NextCursor: cursor}
+	if len(list) > limit {
+		page.Orders = list[:limit]
+		page.HasMore = true
+	}
+	if n := len(page.Orders); n > 0 {
+		last := page.Orders[n-1]
+		page.NextCursor = FulfillmentCursor{UpdatedAt: last.UpdatedAt, OrderID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// ConfirmShipment records a shipment and moves the order to shipped. Posting the same
+// tracking number again returns the existing shipment, so warehouses can safely retry;
+// further parcels for an order that already shipped are recorded without a status change.
+func (s *FulfillmentService) ConfirmShipment(ctx context.Context, orderID string, req ShipmentRequest) (*Shipment, error) {
+	req.Carrier = strings.TrimSpace(req.Carrier)
+	req.TrackingNumber = strings.TrimSpace(req.TrackingNumber)
+	if req.Carrier == "" || req.TrackingNumber == "" {
+		return nil, ErrInvalidShipment
+	}
+
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindShipments(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, shipment := range existing {
+		if strings.EqualFold(shipment.TrackingNumber, req.TrackingNumber) {
+			return shipment, nil
+		}
+	}
+
+	switch order.Status {
+	case orders.OrderStatusPaid, orders.OrderStatusProcessing, orders.OrderStatusShipped:
+	default:
+		return nil, ErrOrderNotFulfillable
+	}
+	if s.pricing != nil {
+		held, err := s.pricing.OrderOnHold(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			return nil, ErrOrderOnHold
+		}
+	}
+
+	now := time.Now()
+	shipment := &Shipment{
+		ID:             utils.GenerateID(),
+		OrderID:        order.ID,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		TrackingURL:    strings.TrimSpace(req.TrackingURL),
+		ShippedAt:      now,
+		APIKeyID:       req.APIKeyID,
+		CreatedAt:      now,
+	}
+	if req.ShippedAt != nil {
+		shipment.ShippedAt = *req.ShippedAt
+	}
+	if err := s.repo.SaveShipment(ctx, shipment); err != nil {
+		return nil, err
+	}
+	if s.events != nil {
+		s.events.RecordShipment(ctx, shipment)
+	}
+
+	// Orders go paid -> processing -> shipped
+	if order.Status == orders.OrderStatusPaid {
+		if order, err = s.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusProcessing); err != nil {
+			return nil, err
+		}
+	}
+	if order.Status == orders.OrderStatusProcessing {
+		if _, err := s.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusShipped); err != nil {
+			return nil, err
+		}
+	}
+	return shipment, nil
+}
+
+// WithEvents records each new shipment on the order's timeline
+func (s *FulfillmentService) WithEvents(events *OrderEventService) *FulfillmentService {
+	s.events = events
+	return s
+}
+
+// WithPricingAnomalies refuses shipments for orders held for pricing review
+func (s *FulfillmentService) WithPricingAnomalies(anomalies *PricingAnomalyService) *FulfillmentService {
+	s.pricing = anomalies
+	return s
+}
+
+// GetShipments returns the shipments recorded for an order, oldest first
+func (s *FulfillmentService) GetShipments(ctx context.Context, orderID string) ([]*Shipment, error) {
+	return s.repo.FindShipments(ctx, orderID)
+}
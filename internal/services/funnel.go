@@ -0,0 +1,389 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrFunnelAlertsDisabled = errors.New("funnel alerts are disabled")
+	ErrInvalidFunnelWindow  = errors.New("window must be between 1 minute and 30 days")
+)
+
+// FunnelAlertJobKind is the job kind of funnel alerts, whose failures are dead-lettered
+const FunnelAlertJobKind = "funnel-alert"
+
+// FunnelStage is a step of the checkout funnel a cart or order reaches
+type FunnelStage string
+
+const (
+	FunnelStageCartCreated     FunnelStage = "cart_created"     // a cart got its first item
+	FunnelStageCheckoutPreview FunnelStage = "checkout_preview" // the cart was checked before checking out
+	FunnelStageOrderCreated    FunnelStage = "order_created"    // a storefront order was placed
+	FunnelStagePaid            FunnelStage = "paid"             // that order was paid
+)
+
+// FunnelStages lists the funnel stages in order
+var FunnelStages = []FunnelStage{FunnelStageCartCreated, FunnelStageCheckoutPreview, FunnelStageOrderCreated, FunnelStagePaid}
+
+// funnelVars publishes funnel counts since startup and the latest conversion rates to
+// expvar, served at /debug/vars on the diagnostics listener
+var funnelVars = expvar.NewMap("checkout_funnel")
+
+// FunnelPolicy sets how drop-off is measured and when it raises an alert
+type FunnelPolicy struct {
+	Window    time.Duration // recent period whose conversion is checked
+	Baseline  time.Duration // period before the window that conversion is compared with
+	MaxDrop   float64       // fraction a step's conversion may fall below its baseline, e.g. 0.5
+	MinSample int64         // carts or orders entering a step, in the window and baseline, before it is checked
+}
+
+// FunnelStep is the conversion from one funnel stage to the next
+type FunnelStep struct {
+	From         FunnelStage `json:"from"`
+	To           FunnelStage `json:"to"`
+	Entered      int64       `json:"entered"`
+	Converted    int64       `json:"converted"`
+	Rate         float64     `json:"rate"`
+	BaselineRate float64     `json:"baseline_rate"`
+	Deviation    float64     `json:"deviation"` // relative change from the baseline, e.g. -0.4
+}
+
+// FunnelSnapshot is the funnel over a window, compared with the baseline before it
+type FunnelSnapshot struct {
+	WindowStart    time.Time             `json:"window_start"`
+	WindowEnd      time.Time             `json:"window_end"`
+	BaselineStart  time.Time             `json:"baseline_start"`
+	Counts         map[FunnelStage]int64 `json:"counts"`
+	BaselineCounts map[FunnelStage]int64 `json:"baseline_counts"`
+	Steps          []FunnelStep          `json:"steps"`
+}
+
+// FunnelAlert records a funnel step converting far below its baseline
+type FunnelAlert struct {
+	ID           string      `json:"id"`
+	From         FunnelStage `json:"from"`
+	To           FunnelStage `json:"to"`
+	Entered      int64       `json:"entered"`
+	Converted    int64       `json:"converted"`
+	Rate         float64     `json:"rate"`
+	BaselineRate float64     `json:"baseline_rate"`
+	WindowStart  time.Time   `json:"window_start"`
+	WindowEnd    time.Time   `json:"window_end"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// FunnelRepository defines persistence for funnel events and alerts
+type FunnelRepository interface {
+	// Record stores that subject reached stage, reporting false when it already had
+	Record(ctx context.Context, stage FunnelStage, subject string, at time.Time) (bool, error)
+	Exists(ctx context.Context, stage FunnelStage, subject string) (bool, error)
+	CountByStage(ctx context.Context, from, to time.Time) (map[FunnelStage]int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	SaveAlert(ctx context.Context, alert *FunnelAlert) error
+	ListAlerts(ctx context.Context, since time.Time, limit, offset int) ([]*FunnelAlert, error)
+	CountAlerts(ctx context.Context, since time.Time) (int64, error)
+}
+
+// FunnelAlertNotifier tells operators about funnel drop-off
+type FunnelAlertNotifier interface {
+	NotifyFunnelAlert(ctx context.Context, alert *FunnelAlert) error
+}
+
+// FunnelService counts carts and orders through the checkout funnel and raises alerts
+// when a step converts far below its baseline
+type FunnelService struct {
+	repo     FunnelRepository
+	policy   FunnelPolicy
+	notifier FunnelAlertNotifier
+	runner   *jobs.Runner
+}
+
+// NewFunnelService creates a new FunnelService
+func NewFunnelService(repo FunnelRepository, policy FunnelPolicy) *FunnelService {
+	if policy.Window <= 0 {
+		policy.Window = time.Hour
+	}
+	if policy.Baseline < policy.Window {
+		policy.Baseline = policy.Window
+	}
+	return &FunnelService{repo: repo, policy: policy}
+}
+
+// WithAlerts sends funnel alerts through notifier. Alerts are queued on runner so a slow
+// endpoint doesn't hold up the check; without a runner they are sent inline.
+func (s *FunnelService) WithAlerts(notifier FunnelAlertNotifier, runner *jobs.Runner) *FunnelService {
+	s.notifier = notifier
+	s.runner = runner
+	return s
+}
+
+// Record counts subject, a cart or order ID, as reaching stage. Counting never fails the
+// request being counted, so errors are only logged.
+func (s *FunnelService) Record(ctx context.Context, stage FunnelStage, subject string) {
+	recorded, err := s.repo.Record(ctx, stage, subject, time.Now())
+	if err != nil {
+		log.Printf("Failed to record %s for %s in the checkout funnel: %v", stage, subject, err)
+		return
+	}
+	if recorded {
+		funnelVars.Add(string(stage), 1)
+	}
+}
+
+// Advance counts subject as reaching stage only when it already reached from, so orders
+// that skipped the storefront checkout, such as POS sales, stay out of the funnel
+func (s *FunnelService) Advance(ctx context.Context, from, stage FunnelStage, subject string) {
+	entered, err := s.repo.Exists(ctx, from, subject)
+	if err != nil {
+		log.Printf("Failed to look up %s in the checkout funnel: %v", subject, err)
+		return
+	}
+	if entered {
+		s.Record(ctx, stage, subject)
+	}
+}
+
+// Snapshot returns the funnel over the last window, 0 for the policy's window, with each
+// step's conversion compared with the baseline period before it
+func (s *FunnelService) Snapshot(ctx context.Context, window time.Duration) (*FunnelSnapshot, error) {
+	if window == 0 {
+		window = s.policy.Window
+	}
+	if window < time.Minute || window > 30*24*time.Hour {
+		return nil, ErrInvalidFunnelWindow
+	}
+
+	now := time.Now()
+	snapshot := &FunnelSnapshot{
+		WindowStart:   now.Add(-window),
+		WindowEnd:     now,
+		BaselineStart: now.Add(-window - s.policy.Baseline),
+	}
+	var err error
+	if snapshot.Counts, err = s.repo.CountByStage(ctx, snapshot.WindowStart, snapshot.WindowEnd); err != nil {
+		return nil, err
+	}
+	if snapshot.BaselineCounts, err = s.repo.CountByStage(ctx, snapshot.BaselineStart, snapshot.WindowStart); err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(FunnelStages); i++ {
+		from, to := FunnelStages[i-1], FunnelStages[i]
+		step := FunnelStep{
+			From:         from,
+			To:           to,
+			Entered:      snapshot.Counts[from],
+			Converted:    snapshot.Counts[to],
+			Rate:         conversionRate(snapshot.Counts[from], snapshot.Counts[to]),
+			BaselineRate: conversionRate(snapshot.BaselineCounts[from], snapshot.BaselineCounts[to]),
+		}
+		if step.BaselineRate > 0 {
+			step.Deviation = (step.Rate - step.BaselineRate) / step.BaselineRate
+		}
+		snapshot.Steps = append(snapshot.Steps, step)
+	}
+	return snapshot, nil
+}
+
+// Check compares the last window's conversion with the baseline and raises an alert for
+// each step that fell more than the policy allows. A step is alerted once per window.
+// The rates are also published to expvar.
+func (s *FunnelService) Check(ctx context.Context) ([]*FunnelAlert, error) {
+	snapshot, err := s.Snapshot(ctx, s.policy.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.repo.ListAlerts(ctx, snapshot.WindowStart, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	alerted := make(map[FunnelStage]bool, len(recent))
+	for _, alert := range recent {
+		alerted[alert.From] = true
+	}
+
+	var raised []*FunnelAlert
+	for _, step := range snapshot.Steps {
+		rate := new(expvar.Float)
+		rate.Set(step.Rate)
+		funnelVars.Set(string(step.From)+"_to_"+string(step.To)+"_rate", rate)
+
+		if alerted[step.From] || !s.droppedOff(step, snapshot.BaselineCounts[step.From]) {
+			continue
+		}
+		alert := &FunnelAlert{
+			ID:           utils.GenerateID(),
+			From:         step.From,
+			To:           step.To,
+			Entered:      step.Entered,
+			Converted:    step.Converted,
+			Rate:         step.Rate,
+			BaselineRate: step.BaselineRate,
+			WindowStart:  snapshot.WindowStart,
+			WindowEnd:    snapshot.WindowEnd,
+			CreatedAt:    snapshot.WindowEnd,
+		}
+		if err := s.repo.SaveAlert(ctx, alert); err != nil {
+			return raised, err
+		}
+		funnelVars.Add("alerts", 1)
+		s.alert(alert)
+		raised = append(raised, alert)
+	}
+	return raised, nil
+}
+
+// droppedOff reports whether a step converted far enough below its baseline to alert,
+// once both periods have enough carts or orders entering it to judge
+func (s *FunnelService) droppedOff(step FunnelStep, baselineEntered int64) bool {
+	if step.Entered < s.policy.MinSample || baselineEntered < s.policy.MinSample || step.BaselineRate == 0 {
+		return false
+	}
+	return step.Rate < step.BaselineRate*(1-s.policy.MaxDrop)
+}
+
+// ListAlerts lists funnel alerts, newest first
+func (s *FunnelService) ListAlerts(ctx context.Context, limit, offset int) ([]*FunnelAlert, int64, error) {
+	alerts, err := s.repo.ListAlerts(ctx, time.Time{}, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountAlerts(ctx, time.Time{})
+	if err != nil {
+		return nil, 0, err
+	}
+	return alerts, total, nil
+}
+
+// Prune deletes funnel events older than the window and baseline, returning how many
+// there were
+func (s *FunnelService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-s.policy.Window-s.policy.Baseline))
+}
+
+func (s *FunnelService) alert(alert *FunnelAlert) {
+	if s.notifier == nil {
+		return
+	}
+	send := func(ctx context.Context) error {
+		return s.notifier.NotifyFunnelAlert(ctx, alert)
+	}
+	if s.runner == nil {
+		if err := send(context.Background()); err != nil {
+			log.Printf("Failed to send funnel alert %s: %v", alert.ID, err)
+		}
+		return
+	}
+	payload, _ := json.Marshal(alert)
+	if err := s.runner.Enqueue(jobs.Job{Name: "funnel-alert:" + alert.ID, Kind: FunnelAlertJobKind, Payload: payload, Run: send}); err != nil {
+		log.Printf("Failed to queue funnel alert %s: %v", alert.ID, err)
+	}
+}
+
+// ReplayAlert sends again the alert of a dead-lettered funnel alert job
+func (s *FunnelService) ReplayAlert(ctx context.Context, payload []byte) error {
+	if s.notifier == nil {
+		return ErrFunnelAlertsDisabled
+	}
+	var alert FunnelAlert
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		return err
+	}
+	return s.notifier.NotifyFunnelAlert(ctx, &alert)
+}
+
+func conversionRate(entered, converted int64) float64 {
+	if entered == 0 {
+		return 0
+	}
+	return float64(converted) / float64(entered)
+}
+
+// LogFunnelAlertNotifier writes funnel alerts to the log, for development or until an
+// alert webhook is configured
+type LogFunnelAlertNotifier struct{}
+
+// NotifyFunnelAlert logs the alert
+func (LogFunnelAlertNotifier) NotifyFunnelAlert(ctx context.Context, alert *FunnelAlert) error {
+	log.Printf("Checkout funnel drop-off: %s to %s converted %.1f%% of %d, against %.1f%% before",
+		alert.From, alert.To, alert.Rate*100, alert.Entered, alert.BaselineRate*100)
+	return nil
+}
+
+// WebhookFunnelAlertNotifier POSTs funnel alerts as JSON to a webhook, such as an incident
+// tool or chat integration. With a secret, the body's hex HMAC-SHA256 is sent in an
+// X-Signature header as sha256=<hex>.
+type WebhookFunnelAlertNotifier struct {
+	url        string
+	secret     []byte
+	httpClient *http.Client
+	retry      retry.Policy
+}
+
+// NewWebhookFunnelAlertNotifier creates a WebhookFunnelAlertNotifier
+func NewWebhookFunnelAlertNotifier(url, secret string, timeout time.Duration) *WebhookFunnelAlertNotifier {
+	return &WebhookFunnelAlertNotifier{
+		url:        url,
+		secret:     []byte(secret),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// WithRetry retries deliveries that fail
+func (n *WebhookFunnelAlertNotifier) WithRetry(policy retry.Policy) *WebhookFunnelAlertNotifier {
+	n.retry = policy
+	return n
+}
+
+// NotifyFunnelAlert sends the alert as a checkout_funnel.drop_off event
+func (n *WebhookFunnelAlertNotifier) NotifyFunnelAlert(ctx context.Context, alert *FunnelAlert) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":  "checkout_funnel.drop_off",
+		"alert": alert,
+	})
+	if err != nil {
+		return err
+	}
+	return retry.Do(ctx, n.retry, func(ctx context.Context) error {
+		return n.post(ctx, body)
+	})
+}
+
+func (n *WebhookFunnelAlertNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("funnel alert webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("funnel alert webhook failed: endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
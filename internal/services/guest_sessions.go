@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+	"github.com/devchuckcamp/gocommerce/cart"
+)
+
+var ErrInvalidGuestSession = errors.New("guest session token is invalid or has expired")
+
+const (
+	// GuestSessionHeader carries a guest session token on storefront requests
+	GuestSessionHeader = "X-Guest-Session"
+
+	// guestSessionTokenPrefix marks guest session tokens so they are recognizable in logs
+	guestSessionTokenPrefix = "gs_"
+	// guestUserIDPrefix marks the user IDs guests act under
+	guestUserIDPrefix = "guest:"
+)
+
+// GuestUserID is the user ID a guest session acts under. A guest's orders and recently
+// viewed products are kept under it until the guest signs in; the guest cart is the
+// cart of the session.
+func GuestUserID(sessionID string) string {
+	return guestUserIDPrefix + sessionID
+}
+
+// IsGuestUserID reports whether a user ID belongs to a guest session
+func IsGuestUserID(userID string) bool {
+	return strings.HasPrefix(userID, guestUserIDPrefix)
+}
+
+// GuestSession is an issued guest session
+type GuestSession struct {
+	ID        string    `json:"session_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GuestClaim is what a guest session brought to the account that claimed it
+type GuestClaim struct {
+	CartMerged    bool  `json:"cart_merged"`
+	OrdersClaimed int64 `json:"orders_claimed"`
+}
+
+// GuestSessionRepository defines persistence for handing guest data to an account
+type GuestSessionRepository interface {
+	// ReassignOrders moves every order of one user ID to another and returns how many moved
+	ReassignOrders(ctx context.Context, fromUserID, toUserID string) (int64, error)
+}
+
+// GuestSessionService issues signed guest session tokens and hands a guest's cart,
+// orders and recently viewed products to the account they register or sign in to.
+// Tokens are stateless: they carry the session ID and expiry, signed with HMAC-SHA256.
+type GuestSessionService struct {
+	repo           GuestSessionRepository
+	cartService    *CartService
+	carts          cart.Repository
+	recentlyViewed *RecentlyViewedService
+	secret         []byte
+	ttl            time.Duration
+}
+
+// NewGuestSessionService creates a new GuestSessionService
+func NewGuestSessionService(repo GuestSessionRepository, cartService *CartService, carts cart.Repository, secret []byte, ttl time.Duration) *GuestSessionService {
+	return &GuestSessionService{
+		repo:        repo,
+		cartService: cartService,
+		carts:       carts,
+		secret:      secret,
+		ttl:         ttl,
+	}
+}
+
+// WithRecentlyViewed moves a guest's recently viewed products to their account on claim
+func (s *GuestSessionService) WithRecentlyViewed(recentlyViewed *RecentlyViewedService) *GuestSessionService {
+	s.recentlyViewed = recentlyViewed
+	return s
+}
+
+// Issue issues a token for a new guest session, or renews an existing session when its
+// ID is given
+func (s *GuestSessionService) Issue(sessionID string) *GuestSession {
+	if sessionID == "" {
+		sessionID = utils.GenerateID()
+	}
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return &GuestSession{
+		ID:        sessionID,
+		Token:     guestSessionTokenPrefix + sessionID + "." + expiry + "." + s.sign(sessionID, expiry),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// Verify checks a guest session token and returns its session ID
+func (s *GuestSessionService) Verify(token string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(token, guestSessionTokenPrefix), ".")
+	if !strings.HasPrefix(token, guestSessionTokenPrefix) || len(parts) != 3 || parts[0] == "" {
+		return "", ErrInvalidGuestSession
+	}
+	sessionID, expiry, signature := parts[0], parts[1], parts[2]
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(sessionID, expiry))) {
+		return "", ErrInvalidGuestSession
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !time.Now().Before(time.Unix(expiresAt, 0)) {
+		return "", ErrInvalidGuestSession
+	}
+	return sessionID, nil
+}
+
+// sign signs a session ID and expiry
+func (s *GuestSessionService) sign(sessionID, expiry string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("gs1." + sessionID + "." + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Claim hands a guest session to an account: the guest cart is merged into the
+// account's cart, and the guest's orders and recently viewed products move to it
+func (s *GuestSessionService) Claim(ctx context.Context, sessionID, userID string) (*GuestClaim, error) {
+	guestID := GuestUserID(sessionID)
+	claim := &GuestClaim{}
+
+	guestCart, err := s.carts.FindBySessionID(ctx, sessionID)
+	if err != nil && err != cart.ErrCartNotFound {
+		return nil, err
+	}
+	if err == nil && len(guestCart.Items) > 0 {
+		userCart, err := s.cartService.GetOrCreateCart(ctx, userID, "")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.cartService.MergeCarts(ctx, guestCart.ID, userCart.ID); err != nil {
+			return nil, err
+		}
+		claim.CartMerged = true
+	}
+
+	if claim.OrdersClaimed, err = s.repo.ReassignOrders(ctx, guestID, userID); err != nil {
+		return nil, err
+	}
+
+	if s.recentlyViewed != nil {
+		if err := s.recentlyViewed.Move(ctx, guestID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return claim, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/inventory"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+// inventoryLockTimeout bounds how long a stock change waits for another replica
+const inventoryLockTimeout = 5 * time.Second
+
+// LockingInventoryService serializes stock changes per SKU across API replicas, so two
+// checkouts cannot both pass the availability check for the last unit and oversell
+type LockingInventoryService struct {
+	inventory.Service
+	locker lock.Manager
+}
+
+// LockInventory wraps an inventory service with per-SKU locks. It returns nil when inv
+// is nil, for deployments that do not track stock.
+func LockInventory(inv inventory.Service, locker lock.Manager) inventory.Service {
+	if inv == nil {
+		return nil
+	}
+	return &LockingInventoryService{Service: inv, locker: locker}
+}
+
+// Reserve holds stock for a reference while the SKU is locked
+func (s *LockingInventoryService) Reserve(ctx context.Context, sku string, quantity int, referenceID string) error {
+	return s.withLock(ctx, "inventory:"+sku, func() error {
+		return s.Service.Reserve(ctx, sku, quantity, referenceID)
+	})
+}
+
+// Release returns reserved stock while the SKU is locked
+func (s *LockingInventoryService) Release(ctx context.Context, sku string, quantity int, referenceID string) error {
+	return s.withLock(ctx, "inventory:"+sku, func() error {
+		return s.Service.Release(ctx, sku, quantity, referenceID)
+	})
+}
+
+// AdjustStock changes on-hand stock while the SKU is locked
+func (s *LockingInventoryService) AdjustStock(ctx context.Context, sku string, quantity int, reason string) error {
+	return s.withLock(ctx, "inventory:"+sku, func() error {
+		return s.Service.AdjustStock(ctx, sku, quantity, reason)
+	})
+}
+
+// Commit converts a reference's reservations into sales, once across replicas
+func (s *LockingInventoryService) Commit(ctx context.Context, referenceID string) error {
+	return s.withLock(ctx, "inventory-reference:"+referenceID, func() error {
+		return s.Service.Commit(ctx, referenceID)
+	})
+}
+
+func (s *LockingInventoryService) withLock(ctx context.Context, name string, fn func() error) error {
+	lockCtx, cancel := context.WithTimeout(ctx, inventoryLockTimeout)
+	defer cancel()
+
+	held, err := s.locker.Acquire(lockCtx, name)
+	if err != nil {
+		return err
+	}
+	defer held.Release(context.Background())
+
+	return fn()
+}
+
+// RecordingInventoryService writes every stock movement to the inventory history. Wrap it
+// in LockInventory so the on-hand quantities recorded around a change are consistent.
+type RecordingInventoryService struct {
+	inventory.Service
+	history InventoryHistoryRepository
+}
+
+// RecordInventory wraps an inventory service so its stock movements are recorded. It
+// returns nil when inv is nil, for deployments that do not track stock.
+func RecordInventory(inv inventory.Service, history InventoryHistoryRepository) inventory.Service {
+	if inv == nil {
+		return nil
+	}
+	return &RecordingInventoryService{Service: inv, history: history}
+}
+
+// Reserve holds stock for a reference and records the reservation
+func (s *RecordingInventoryService) Reserve(ctx context.Context, sku string, quantity int, referenceID string) error {
+	before := s.onHand(ctx, sku)
+	if err := s.Service.Reserve(ctx, sku, quantity, referenceID); err != nil {
+		return err
+	}
+	s.record(ctx, sku, StockMovementReservation, quantity, before, referenceID, "")
+	return nil
+}
+
+// Release returns reserved stock and records the release
+func (s *RecordingInventoryService) Release(ctx context.Context, sku string, quantity int, referenceID string) error {
+	before := s.onHand(ctx, sku)
+	if err := s.Service.Release(ctx, sku, quantity, referenceID); err != nil {
+		return err
+	}
+	s.record(ctx, sku, StockMovementRelease, quantity, before, referenceID, "")
+	return nil
+}
+
+// AdjustStock changes on-hand stock and records the adjustment with its reason
+func (s *RecordingInventoryService) AdjustStock(ctx context.Context, sku string, quantity int, reason string) error {
+	before := s.onHand(ctx, sku)
+	if err := s.Service.AdjustStock(ctx, sku, quantity, reason); err != nil {
+		return err
+	}
+	s.record(ctx, sku, StockMovementAdjustment, quantity, before, "", reason)
+	return nil
+}
+
+// Commit converts a reference's reservations into sales and records one movement per SKU.
+// The SKUs come from the reference's recorded reservations that are still outstanding.
+func (s *RecordingInventoryService) Commit(ctx context.Context, referenceID string) error {
+	outstanding, err := s.history.OutstandingReservations(ctx, referenceID)
+	if err != nil {
+		log.Printf("Stock movements for %s not recorded: %v", referenceID, err)
+	}
+	before := make(map[string]int, len(outstanding))
+	for sku := range outstanding {
+		before[sku] = s.onHand(ctx, sku)
+	}
+
+	if err := s.Service.Commit(ctx, referenceID); err != nil {
+		return err
+	}
+	for sku, quantity := range outstanding {
+		s.record(ctx, sku, StockMovementCommit, quantity, before[sku], referenceID, "")
+	}
+	return nil
+}
+
+// onHand returns the units physically in stock: available plus reserved
+func (s *RecordingInventoryService) onHand(ctx context.Context, sku string) int {
+	available, _ := s.Service.GetAvailableStock(ctx, sku)
+	reserved, _ := s.Service.GetReservedStock(ctx, sku)
+	return available + reserved
+}
+
+// record appends a movement once the stock change has been made. A failure is logged
+// rather than returned, since the change itself cannot be undone.
+func (s *RecordingInventoryService) record(ctx context.Context, sku string, movementType StockMovementType, quantity, before int, referenceID, reason string) {
+	movement := &StockMovement{
+		ID:           utils.GenerateID(),
+		SKU:          sku,
+		Type:         movementType,
+		Quantity:     quantity,
+		OnHandBefore: before,
+		OnHandAfter:  s.onHand(ctx, sku),
+		ReferenceID:  referenceID,
+		Reason:       reason,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.history.AppendMovement(ctx, movement); err != nil {
+		log.Printf("Stock movement %s for %s not recorded: %v", movementType, sku, err)
+	}
+}
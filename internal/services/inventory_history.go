@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoStockHistory           = errors.New("no stock history recorded for this SKU at that time")
+	ErrInvalidStockHistoryRange = errors.New("history range must end after it starts and span at most 366 days")
+)
+
+// maxStockHistoryRange bounds how much history one request loads
+const maxStockHistoryRange = 366 * 24 * time.Hour
+
+// snapshotDateLayout is the format of InventorySnapshot.Date
+const snapshotDateLayout = "2006-01-02"
+
+// StockMovementType is the kind of stock change. The values match gocommerce's
+// inventory_activity_type so movements share the inventory_activities log.
+type StockMovementType string
+
+const (
+	StockMovementReservation StockMovementType = "reservation"
+	StockMovementRelease     StockMovementType = "reservation_release"
+	StockMovementCommit      StockMovementType = "reservation_commit"
+	StockMovementAdjustment  StockMovementType = "adjustment"
+)
+
+// StockMovement is one change to a SKU's stock. Quantity is the units reserved, released
+// or committed, or the signed change for an adjustment; the on-hand quantities show its
+// effect on the units physically in stock.
+type StockMovement struct {
+	ID           string            `json:"id"`
+	SKU          string            `json:"sku"`
+	Type         StockMovementType `json:"type"`
+	Quantity     int               `json:"quantity"`
+	OnHandBefore int               `json:"on_hand_before"`
+	OnHandAfter  int               `json:"on_hand_after"`
+	ReferenceID  string            `json:"reference_id,omitempty"`
+	Reason       string            `json:"reason,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// InventorySnapshot is a SKU's stock level as recorded by the daily snapshot.
+// UnexplainedChange is the change in on-hand stock since the previous snapshot that no
+// recorded movement accounts for, such as shrinkage or an unrecorded count correction.
+type InventorySnapshot struct {
+	SKU               string    `json:"sku"`
+	Date              string    `json:"date"` // YYYY-MM-DD
+	OnHand            int       `json:"on_hand"`
+	Reserved          int       `json:"reserved"`
+	Available         int       `json:"available"`
+	TakenAt           time.Time `json:"taken_at"`
+	UnexplainedChange *int      `json:"unexplained_change,omitempty"`
+}
+
+// StockAtDate is a SKU's on-hand stock at a point in time, taken from whichever of the
+// latest snapshot or the latest movement before that time is more recent
+type StockAtDate struct {
+	At         time.Time `json:"at"`
+	OnHand     int       `json:"on_hand"`
+	Source     string    `json:"source"` // snapshot or movement
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// StockHistory is a SKU's snapshots and movements over a period, oldest first
+type StockHistory struct {
+	SKU       string               `json:"sku"`
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	StockAt   *StockAtDate         `json:"stock_at,omitempty"`
+	Snapshots []*InventorySnapshot `json:"snapshots"`
+	Movements []*StockMovement     `json:"movements"`
+}
+
+// InventoryHistoryRepository defines persistence for stock movements and snapshots
+type InventoryHistoryRepository interface {
+	AppendMovement(ctx context.Context, movement *StockMovement) error
+	// OutstandingReservations returns the units per SKU a reference has reserved and not
+	// yet released or committed, according to the recorded movements
+	OutstandingReservations(ctx context.Context, referenceID string) (map[string]int, error)
+	// ListMovements lists a SKU's movements made within [from, to], oldest first
+	ListMovements(ctx context.Context, sku string, from, to time.Time) ([]*StockMovement, error)
+	// ListSnapshots lists a SKU's snapshots dated within [fromDate, toDate], oldest first
+	ListSnapshots(ctx context.Context, sku string, fromDate, toDate string) ([]*InventorySnapshot, error)
+	// LatestSnapshot and LatestMovement return the most recent record at or before at,
+	// or nil when there is none
+	LatestSnapshot(ctx context.Context, sku string, at time.Time) (*InventorySnapshot, error)
+	LatestMovement(ctx context.Context, sku string, at time.Time) (*StockMovement, error)
+	// TakeSnapshots records every active SKU's current stock level under date, replacing
+	// any snapshot already taken that day, and returns how many SKUs were recorded
+	TakeSnapshots(ctx context.Context, date string, takenAt time.Time) (int64, error)
+}
+
+// InventoryHistoryService reports stock history for shrinkage analysis and stock-at-date
+// questions
+type InventoryHistoryService struct {
+	repo InventoryHistoryRepository
+}
+
+// NewInventoryHistoryService creates a new InventoryHistoryService
+func NewInventoryHistoryService(repo InventoryHistoryRepository) *InventoryHistoryService {
+	return &InventoryHistoryService{repo: repo}
+}
+
+// TakeSnapshots records today's stock level for every active SKU
+func (s *InventoryHistoryService) TakeSnapshots(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return s.repo.TakeSnapshots(ctx, now.Format(snapshotDateLayout), now)
+}
+
+// History returns a SKU's snapshots and movements between from and to, with the change
+// each snapshot shows that the movements since the previous one do not explain
+func (s *InventoryHistoryService) History(ctx context.Context, sku string, from, to time.Time) (*StockHistory, error) {
+	if !to.After(from) || to.Sub(from) > maxStockHistoryRange {
+		return nil, ErrInvalidStockHistoryRange
+	}
+
+	snapshots, err := s.repo.ListSnapshots(ctx, sku, from.Format(snapshotDateLayout), to.Format(snapshotDateLayout))
+	if err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.ListMovements(ctx, sku, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(snapshots); i++ {
+		previous, current := snapshots[i-1], snapshots[i]
+		recorded := 0
+		for _, movement := range movements {
+			if movement.CreatedAt.After(previous.TakenAt) && !movement.CreatedAt.After(current.TakenAt) {
+				recorded += movement.OnHandAfter - movement.OnHandBefore
+			}
+		}
+		unexplained := current.OnHand - previous.OnHand - recorded
+		current.UnexplainedChange = &unexplained
+	}
+
+	return &StockHistory{SKU: sku, From: from, To: to, Snapshots: snapshots, Movements: movements}, nil
+}
+
+// StockAt returns a SKU's on-hand stock at a point in time
+func (s *InventoryHistoryService) StockAt(ctx context.Context, sku string, at time.Time) (*StockAtDate, error) {
+	snapshot, err := s.repo.LatestSnapshot(ctx, sku, at)
+	if err != nil {
+		return nil, err
+	}
+	movement, err := s.repo.LatestMovement(ctx, sku, at)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case movement != nil && (snapshot == nil || movement.CreatedAt.After(snapshot.TakenAt)):
+		return &StockAtDate{At: at, OnHand: movement.OnHandAfter, Source: "movement", RecordedAt: movement.CreatedAt}, nil
+	case snapshot != nil:
+		return &StockAtDate{At: at, OnHand: snapshot.OnHand, Source: "snapshot", RecordedAt: snapshot.TakenAt}, nil
+	}
+	return nil, ErrNoStockHistory
+}
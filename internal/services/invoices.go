@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvoiceNotFound       = errors.New("invoice not found")
+	ErrNetTermsUnavailable   = errors.New("paying by invoice requires a company account with net terms")
+	ErrInvoiceNotOpen        = errors.New("invoice is not open")
+	ErrInvalidInvoicePayment = errors.New("invoice payment must be positive, in the invoice currency, with a reference of at most 255 characters")
+	ErrInvoiceOverpaid       = errors.New("invoice payment exceeds the balance due")
+)
+
+const maxInvoicePaymentReferenceLength = 255
+
+// InvoiceStatus tracks an invoice from issue to settlement
+type InvoiceStatus string
+
+const (
+	InvoiceStatusOpen InvoiceStatus = "open"
+	InvoiceStatusPaid InvoiceStatus = "paid"
+	InvoiceStatusVoid InvoiceStatus = "void" // the order was rejected or never completed
+)
+
+// Invoice is the receivable for an order a company pays on net terms
+type Invoice struct {
+	ID           string           `json:"id"`
+	Number       string           `json:"number"`
+	OrderID      string           `json:"order_id"`
+	CompanyID    string           `json:"company_id"`
+	PlacedBy     string           `json:"placed_by"`
+	Amount       money.Money      `json:"amount"`
+	AmountPaid   money.Money      `json:"amount_paid"`
+	Status       InvoiceStatus    `json:"status"`
+	NetTermsDays int              `json:"net_terms_days"`
+	IssuedAt     time.Time        `json:"issued_at"`
+	DueAt        time.Time        `json:"due_at"`
+	PaidAt       *time.Time       `json:"paid_at,omitempty"`
+	VoidedAt     *time.Time       `json:"voided_at,omitempty"`
+	VoidReason   string           `json:"void_reason,omitempty"`
+	Payments     []InvoicePayment `json:"payments,omitempty"` // on invoice detail only
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// Balance returns what is still owed on the invoice
+func (i *Invoice) Balance() money.Money {
+	return money.Money{Amount: i.Amount.Amount - i.AmountPaid.Amount, Currency: i.Amount.Currency}
+}
+
+// InvoicePayment is a payment received against an invoice, such as a bank transfer or check
+type InvoicePayment struct {
+	ID         string      `json:"id"`
+	InvoiceID  string      `json:"invoice_id"`
+	Amount     money.Money `json:"amount"`
+	Reference  string      `json:"reference,omitempty"` // e.g. the transfer or check number
+	ReceivedAt time.Time   `json:"received_at"`
+	RecordedBy string      `json:"recorded_by"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// InvoicePaymentInput is a payment an admin records against an invoice
+type InvoicePaymentInput struct {
+	Amount     int64 // in cents, in the invoice currency
+	Reference  string
+	ReceivedAt *time.Time // defaults to now
+}
+
+// InvoiceSortFields are the fields invoice lists can be sorted by
+var InvoiceSortFields = []string{"number", "amount", "status", "issued_at", "due_at", "created_at"}
+
+// InvoiceFilter filters invoices
+type InvoiceFilter struct {
+	CompanyID string
+	Status    InvoiceStatus
+	Sort      Sort
+	Limit     int
+	Offset    int
+}
+
+// OverdueInvoice is an open invoice past its due date
+type OverdueInvoice struct {
+	*Invoice
+	Balance     money.Money `json:"balance"`
+	DaysOverdue int         `json:"days_overdue"`
+}
+
+// OverdueReport lists overdue invoices, most overdue first, with the balance overdue per currency
+type OverdueReport struct {
+	AsOf     time.Time         `json:"as_of"`
+	Invoices []*OverdueInvoice `json:"invoices"`
+	Totals   []money.Money     `json:"totals"`
+}
+
+// InvoiceRepository defines persistence for invoices and the payments recorded against them
+type InvoiceRepository interface {
+	FindByID(ctx context.Context, id string) (*Invoice, error)
+	// FindByOrderID returns nil when the order isn't paid by invoice
+	FindByOrderID(ctx context.Context, orderID string) (*Invoice, error)
+	// List lists invoices, newest first
+	List(ctx context.Context, filter InvoiceFilter) ([]*Invoice, error)
+	// ListOverdue lists open invoices due before asOf, oldest due date first
+	ListOverdue(ctx context.Context, asOf time.Time) ([]*Invoice, error)
+	ListPayments(ctx context.Context, invoiceID string) ([]InvoicePayment, error)
+	Save(ctx context.Context, invoice *Invoice) error
+	// AddPayment records a payment and adds it to the invoice's amount paid, settling the
+	// invoice when nothing is left owing. It returns ErrInvoiceOverpaid when the payment is
+	// more than the balance, and ErrInvoiceNotOpen when the invoice is no longer open.
+	AddPayment(ctx context.Context, payment *InvoicePayment) error
+}
+
+// InvoiceService handles net-terms payment for company accounts: orders paid by invoice
+// are created with an open invoice due after the company's net terms, admins record the
+// payments received against it, and the order is marked paid once the invoice is settled.
+type InvoiceService struct {
+	repo         InvoiceRepository
+	companies    *CompanyService
+	orderService orders.Service
+}
+
+// NewInvoiceService creates a new InvoiceService
+func NewInvoiceService(repo InvoiceRepository, companies *CompanyService, orderService orders.Service) *InvoiceService {
+	return &InvoiceService{
+		repo:         repo,
+		companies:    companies,
+		orderService: orderService,
+	}
+}
+
+// NetTerms returns the net terms, in days, of the company a user buys for. It fails with
+// ErrNetTermsUnavailable when the user belongs to no company or their company has none.
+func (s *InvoiceService) NetTerms(ctx context.Context, userID string) (int, error) {
+	membership, err := s.netTermsMembership(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return membership.Company.NetTermsDays, nil
+}
+
+// netTermsMembership returns the membership of a user whose company has net terms
+func (s *InvoiceService) netTermsMembership(ctx context.Context, userID string) (*CompanyMembership, error) {
+	membership, err := s.companies.Membership(ctx, userID)
+	if errors.Is(err, ErrNotCompanyMember) {
+		return nil, ErrNetTermsUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if membership.Company.NetTermsDays == 0 {
+		return nil, ErrNetTermsUnavailable
+	}
+	return membership, nil
+}
+
+// OpenInvoice issues the invoice for an order placed on the buyer's company net terms
+func (s *InvoiceService) OpenInvoice(ctx context.Context, userID string, order *orders.Order) (*Invoice, error) {
+	membership, err := s.netTermsMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	terms := membership.Company.NetTermsDays
+	invoice := &Invoice{
+		ID:           utils.GenerateID(),
+		Number:       "INV-" + strings.TrimPrefix(order.OrderNumber, "ORD-"),
+		OrderID:      order.ID,
+		CompanyID:    membership.Company.ID,
+		PlacedBy:     userID,
+		Amount:       order.Total,
+		AmountPaid:   money.Money{Currency: order.Total.Currency},
+		Status:       InvoiceStatusOpen,
+		NetTermsDays: terms,
+		IssuedAt:     now,
+		DueAt:        now.AddDate(0, 0, terms),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.Save(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetInvoice returns an invoice with its payments
+func (s *InvoiceService) GetInvoice(ctx context.Context, id string) (*Invoice, error) {
+	invoice, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Payments, err = s.repo.ListPayments(ctx, id); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetOrderInvoice returns an order's invoice, or nil when the order isn't paid by invoice
+func (s *InvoiceService) GetOrderInvoice(ctx context.Context, orderID string) (*Invoice, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// ListInvoices lists invoices, newest first
+func (s *InvoiceService) ListInvoices(ctx context.Context, filter InvoiceFilter) ([]*Invoice, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// ListCompanyInvoices lists the invoices of the user's company, newest first
+func (s *InvoiceService) ListCompanyInvoices(ctx context.Context, userID string, filter InvoiceFilter) ([]*Invoice, error) {
+	membership, err := s.companies.Membership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.CompanyID = membership.Company.ID
+	return s.repo.List(ctx, filter)
+}
+
+// RecordPayment records a payment received against an open invoice. Once the invoice is
+// settled, its order is marked paid.
+func (s *InvoiceService) RecordPayment(ctx context.Context, adminID, invoiceID string, input InvoicePaymentInput) (*Invoice, error) {
+	invoice, err := s.repo.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != InvoiceStatusOpen {
+		return nil, ErrInvoiceNotOpen
+	}
+
+	reference := strings.TrimSpace(input.Reference)
+	if input.Amount <= 0 || utf8.RuneCountInString(reference) > maxInvoicePaymentReferenceLength {
+		return nil, ErrInvalidInvoicePayment
+	}
+	if input.Amount > invoice.Balance().Amount {
+		return nil, ErrInvoiceOverpaid
+	}
+
+	now := time.Now()
+	receivedAt := now
+	if input.ReceivedAt != nil {
+		receivedAt = *input.ReceivedAt
+	}
+	payment := &InvoicePayment{
+		ID:         utils.GenerateID(),
+		InvoiceID:  invoice.ID,
+		Amount:     money.Money{Amount: input.Amount, Currency: invoice.Amount.Currency},
+		Reference:  reference,
+		ReceivedAt: receivedAt,
+		RecordedBy: adminID,
+		CreatedAt:  now,
+	}
+	if err := s.repo.AddPayment(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	invoice, err = s.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	// The payment is recorded either way; a failure here leaves the order for AR to update
+	if invoice.Status == InvoiceStatusPaid {
+		if _, err := s.orderService.UpdateStatus(ctx, invoice.OrderID, orders.OrderStatusPaid); err != nil {
+			log.Printf("Failed to mark order %s paid after settling invoice %s: %v", invoice.OrderID, invoice.Number, err)
+		}
+	}
+	return invoice, nil
+}
+
+// VoidOrderInvoice voids the open invoice of an order that was rejected or never completed
+// checkout. Orders not paid by invoice are left alone.
+func (s *InvoiceService) VoidOrderInvoice(ctx context.Context, orderID, reason string) error {
+	invoice, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil || invoice == nil || invoice.Status != InvoiceStatusOpen {
+		return err
+	}
+
+	now := time.Now()
+	invoice.Status = InvoiceStatusVoid
+	invoice.VoidedAt = &now
+	invoice.VoidReason = reason
+	invoice.UpdatedAt = now
+	return s.repo.Save(ctx, invoice)
+}
+
+// OverdueReport lists open invoices past their due date as of a time
+func (s *InvoiceService) OverdueReport(ctx context.Context, asOf time.Time) (*OverdueReport, error) {
+	invoices, err := s.repo.ListOverdue(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OverdueReport{AsOf: asOf, Invoices: make([]*OverdueInvoice, len(invoices)), Totals: []money.Money{}}
+	totals := make(map[string]int64)
+	for i, invoice := range invoices {
+		balance := invoice.Balance()
+		report.Invoices[i] = &OverdueInvoice{
+			Invoice:     invoice,
+			Balance:     balance,
+			DaysOverdue: int(asOf.Sub(invoice.DueAt).Hours() / 24),
+		}
+		totals[balance.Currency] += balance.Amount
+	}
+	for currency, amount := range totals {
+		report.Totals = append(report.Totals, money.Money{Amount: amount, Currency: currency})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].Currency < report.Totals[j].Currency })
+	return report, nil
+}
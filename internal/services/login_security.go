@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+)
+
+var (
+	ErrLoginLockoutNotFound = errors.New("account is not locked")
+	ErrLoginDeviceNotFound  = errors.New("login device not found")
+	ErrLoginAlertsDisabled  = errors.New("new device alerts are disabled")
+)
+
+// LoginAlertJobKind is the job kind of new-device alerts, whose failures are dead-lettered
+// and replayed with ReplayAlert
+const LoginAlertJobKind = "login-alert"
+
+// AccountLockedError reports a login refused because of too many failed attempts
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account is locked after too many failed logins until %s", e.Until.UTC().Format(time.RFC3339))
+}
+
+// LoginSecurityPolicy configures progressive lockout after failed logins
+type LoginSecurityPolicy struct {
+	MaxFailures int           // failed logins in a row that lock the account
+	LockoutBase time.Duration // length of the first lockout; each later one doubles
+	LockoutMax  time.Duration // longest lockout
+}
+
+// LoginLockout tracks failed logins for an email address. Lockouts counts how many times
+// the account has been locked since its last successful login, so each lock lasts longer.
+type LoginLockout struct {
+	Email          string     `json:"email"`
+	FailedAttempts int        `json:"failed_attempts"`
+	Lockouts       int        `json:"lockouts"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	LastFailedAt   time.Time  `json:"last_failed_at"`
+}
+
+// LoginDevice is a browser or app a customer has signed in from, identified by a
+// fingerprint of its user agent and IP address
+type LoginDevice struct {
+	UserID      string
+	Fingerprint string
+	UserAgent   string
+	IPAddress   string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// NewDeviceLogin describes a sign-in from a device the customer hasn't used before
+type NewDeviceLogin struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	At        time.Time `json:"at"`
+}
+
+// LoginAlertNotifier tells customers about sign-ins from new devices
+type LoginAlertNotifier interface {
+	NotifyNewDevice(ctx context.Context, login NewDeviceLogin) error
+}
+
+// LoginSecurityRepository defines persistence for failed login counters and known devices
+type LoginSecurityRepository interface {
+	FindLockout(ctx context.Context, email string) (*LoginLockout, error)
+	SaveLockout(ctx context.Context, lockout *LoginLockout) error
+	DeleteLockout(ctx context.Context, email string) error
+	FindDevice(ctx context.Context, userID, fingerprint string) (*LoginDevice, error)
+	CountDevices(ctx context.Context, userID string) (int64, error)
+	SaveDevice(ctx context.Context, device *LoginDevice) error
+}
+
+// LoginSecurityService locks accounts after repeated failed logins and alerts customers
+// when they sign in from a new device
+type LoginSecurityService struct {
+	repo     LoginSecurityRepository
+	policy   LoginSecurityPolicy
+	notifier LoginAlertNotifier
+	runner   *jobs.Runner
+}
+
+// NewLoginSecurityService creates a new LoginSecurityService
+func NewLoginSecurityService(repo LoginSecurityRepository, policy LoginSecurityPolicy) *LoginSecurityService {
+	if policy.MaxFailures < 1 {
+		policy.MaxFailures = 1
+	}
+	if policy.LockoutMax < policy.LockoutBase {
+		policy.LockoutMax = policy.LockoutBase
+	}
+	return &LoginSecurityService{repo: repo, policy: policy}
+}
+
+// WithAlerts sends new-device alerts through notifier. Alerts are queued on runner so a
+// slow mail server doesn't hold up sign-in; without a runner they are sent inline.
+func (s *LoginSecurityService) WithAlerts(notifier LoginAlertNotifier, runner *jobs.Runner) *LoginSecurityService {
+	s.notifier = notifier
+	s.runner = runner
+	return s
+}
+
+// CheckLocked returns an *AccountLockedError while the email's account is locked
+func (s *LoginSecurityService) CheckLocked(ctx context.Context, email string) error {
+	lockout, err := s.repo.FindLockout(ctx, normalizeLoginEmail(email))
+	if err == ErrLoginLockoutNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lockout.LockedUntil != nil && time.Now().Before(*lockout.LockedUntil) {
+		return &AccountLockedError{Until: *lockout.LockedUntil}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login. When it reaches MaxFailures in a row the account
+// is locked and an *AccountLockedError is returned.
+func (s *LoginSecurityService) RecordFailure(ctx context.Context, email string) error {
+	email = normalizeLoginEmail(email)
+	lockout, err := s.repo.FindLockout(ctx, email)
+	if err == ErrLoginLockoutNotFound {
+		lockout, err = &LoginLockout{Email: email}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	lockout.FailedAttempts++
+	lockout.LastFailedAt = now
+
+	var locked error
+	if lockout.FailedAttempts >= s.policy.MaxFailures {
+		lockout.Lockouts++
+		lockout.FailedAttempts = 0
+		until := now.Add(s.lockoutDuration(lockout.Lockouts))
+		lockout.LockedUntil = &until
+		locked = &AccountLockedError{Until: until}
+	}
+
+	if err := s.repo.SaveLockout(ctx, lockout); err != nil {
+		return err
+	}
+	return locked
+}
+
+// RecordLogin clears the failed login count after a successful sign-in and remembers the
+// device. A customer signing in from a new device is alerted, unless it is their first.
+func (s *LoginSecurityService) RecordLogin(ctx context.Context, userID, email, userAgent, ipAddress string) error {
+	if err := s.repo.DeleteLockout(ctx, normalizeLoginEmail(email)); err != nil && err != ErrLoginLockoutNotFound {
+		return err
+	}
+
+	now := time.Now()
+	fingerprint := DeviceFingerprint(userAgent, ipAddress)
+	device, err := s.repo.FindDevice(ctx, userID, fingerprint)
+	if err == nil {
+		device.LastSeenAt = now
+		return s.repo.SaveDevice(ctx, device)
+	}
+	if err != ErrLoginDeviceNotFound {
+		return err
+	}
+
+	known, err := s.repo.CountDevices(ctx, userID)
+	if err != nil {
+		return err
+	}
+	device = &LoginDevice{
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UserAgent:   userAgent,
+		IPAddress:   ipAddress,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+	if err := s.repo.SaveDevice(ctx, device); err != nil {
+		return err
+	}
+
+	if known > 0 {
+		s.alert(NewDeviceLogin{UserID: userID, Email: email, UserAgent: userAgent, IPAddress: ipAddress, At: now})
+	}
+	return nil
+}
+
+// Lockout returns the failed login state of an email address
+func (s *LoginSecurityService) Lockout(ctx context.Context, email string) (*LoginLockout, error) {
+	return s.repo.FindLockout(ctx, normalizeLoginEmail(email))
+}
+
+// Unlock lifts a lockout and resets the failed login count
+func (s *LoginSecurityService) Unlock(ctx context.Context, email string) error {
+	return s.repo.DeleteLockout(ctx, normalizeLoginEmail(email))
+}
+
+// lockoutDuration doubles the base lockout for each lock since the last successful login
+func (s *LoginSecurityService) lockoutDuration(lockouts int) time.Duration {
+	duration := s.policy.LockoutBase
+	for i := 1; i < lockouts && duration < s.policy.LockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > s.policy.LockoutMax {
+		duration = s.policy.LockoutMax
+	}
+	return duration
+}
+
+func (s *LoginSecurityService) alert(login NewDeviceLogin) {
+	if s.notifier == nil {
+		return
+	}
+	send := func(ctx context.Context) error {
+		return s.notifier.NotifyNewDevice(ctx, login)
+	}
+	if s.runner == nil {
+		if err := send(context.Background()); err != nil {
+			log.Printf("Failed to send new device alert to user %s: %v", login.UserID, err)
+		}
+		return
+	}
+	payload, _ := json.Marshal(login)
+	if err := s.runner.Enqueue(jobs.Job{Name: "login-alert:" + login.UserID, Kind: LoginAlertJobKind, Payload: payload, Run: send}); err != nil {
+		log.Printf("Failed to queue new device alert for user %s: %v", login.UserID, err)
+	}
+}
+
+// ReplayAlert sends again the alert of a dead-lettered new-device alert job
+func (s *LoginSecurityService) ReplayAlert(ctx context.Context, payload []byte) error {
+	if s.notifier == nil {
+		return ErrLoginAlertsDisabled
+	}
+	var login NewDeviceLogin
+	if err := json.Unmarshal(payload, &login); err != nil {
+		return err
+	}
+	return s.notifier.NotifyNewDevice(ctx, login)
+}
+
+// DeviceFingerprint identifies a device by its user agent and IP address
+func DeviceFingerprint(userAgent, ipAddress string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent)) + "|" + strings.TrimSpace(ipAddress)))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// LogLoginAlertNotifier writes new-device alerts to the log, for development or until a
+// mail server is configured
+type LogLoginAlertNotifier struct{}
+
+// NotifyNewDevice logs the alert
+func (LogLoginAlertNotifier) NotifyNewDevice(ctx context.Context, login NewDeviceLogin) error {
+	log.Printf("New device sign-in for %s from %s (%s)", login.Email, login.IPAddress, login.UserAgent)
+	return nil
+}
+
+// SMTPLoginAlertNotifier emails new-device alerts through an SMTP server
+type SMTPLoginAlertNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPLoginAlertNotifier creates an SMTPLoginAlertNotifier. Without a username the
+// server is used without authentication.
+func NewSMTPLoginAlertNotifier(host string, port int, username, password, from string) *SMTPLoginAlertNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPLoginAlertNotifier{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from}
+}
+
+// NotifyNewDevice emails the customer about the sign-in
+func (n *SMTPLoginAlertNotifier) NotifyNewDevice(ctx context.Context, login NewDeviceLogin) error {
+	body := fmt.Sprintf("We noticed a new sign-in to your account.\r\n\r\n"+
+		"Time: %s\r\nIP address: %s\r\nDevice: %s\r\n\r\n"+
+		"If this was you, there's nothing to do. If not, change your password right away.\r\n",
+		login.At.UTC().Format(time.RFC1123), login.IPAddress, login.UserAgent)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: New sign-in to your account\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s", n.from, login.Email, body)
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{login.Email}, []byte(message))
+}
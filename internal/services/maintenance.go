@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiredCartRepository deletes carts whose expiry has passed
+type ExpiredCartRepository interface {
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EndedPriceRepository deactivates date-bounded prices whose window has closed
+type EndedPriceRepository interface {
+	DeactivateEnded(ctx context.Context, before time.Time) (int64, error)
+}
+
+// FieldRekeyRepository encrypts plaintext PII and rewraps values encrypted with an old key
+type FieldRekeyRepository interface {
+	RotateFields(ctx context.Context, batchSize int) (int64, error)
+}
+
+// fieldRekeyBatch is how many rows of a table are read at a time when re-encrypting
+const fieldRekeyBatch = 500
+
+// MaintenanceService holds the housekeeping run by scheduled tasks
+type MaintenanceService struct {
+	cartRepo  ExpiredCartRepository
+	priceRepo EndedPriceRepository
+	rekeyRepo FieldRekeyRepository
+}
+
+// NewMaintenanceService creates a new MaintenanceService
+func NewMaintenanceService(cartRepo ExpiredCartRepository, priceRepo EndedPriceRepository) *MaintenanceService {
+	return &MaintenanceService{
+		cartRepo:  cartRepo,
+		priceRepo: priceRepo,
+	}
+}
+
+// WithFieldRekey enables re-encrypting PII columns when encryption keys are configured
+func (s *MaintenanceService) WithFieldRekey(repo FieldRekeyRepository) *MaintenanceService {
+	s.rekeyRepo = repo
+	return s
+}
+
+// PurgeExpiredCarts deletes carts, with their items, that expired before now
+func (s *MaintenanceService) PurgeExpiredCarts(ctx context.Context) (int64, error) {
+	return s.cartRepo.DeleteExpired(ctx, time.Now())
+}
+
+// RetireEndedPrices deactivates sale and promotional prices whose window has ended.
+// Prices take effect and lapse by date when they are resolved, so this only keeps
+// ended rows out of the active set and off the storefront's price lookups.
+func (s *MaintenanceService) RetireEndedPrices(ctx context.Context) (int64, error) {
+	return s.priceRepo.DeactivateEnded(ctx, time.Now())
+}
+
+// RekeyFields brings every PII column under the primary encryption key: rows written
+// before encryption was enabled are encrypted, and rows under a retired key are rewrapped
+func (s *MaintenanceService) RekeyFields(ctx context.Context) (int64, error) {
+	if s.rekeyRepo == nil {
+		return 0, nil
+	}
+	return s.rekeyRepo.RotateFields(ctx, fieldRekeyBatch)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalMediaPath is the path the API serves LocalMediaStorage files under
+const LocalMediaPath = "/media"
+
+// MediaStorage stores uploaded files such as avatars and returns their public URLs
+type MediaStorage interface {
+	// Put stores a file under key, replacing any file already there, and returns its URL
+	Put(ctx context.Context, key string, data io.Reader) (string, error)
+	// Delete removes the file under key; a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalMediaStorage stores media files in a local directory. The API serves the directory
+// under LocalMediaPath, so it only suits a single replica unless the directory is shared.
+type LocalMediaStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalMediaStorage creates a LocalMediaStorage writing to dir. File URLs are baseURL
+// followed by the key, e.g. /media or a CDN in front of it.
+func NewLocalMediaStorage(dir, baseURL string) *LocalMediaStorage {
+	return &LocalMediaStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Dir returns the directory files are written to
+func (s *LocalMediaStorage) Dir() string {
+	return s.dir
+}
+
+// Put writes the file to a temporary name first so readers never see a partial file
+func (s *LocalMediaStorage) Put(ctx context.Context, key string, data io.Reader) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete removes a stored file
+func (s *LocalMediaStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path resolves a key inside the media directory, refusing keys that would escape it
+func (s *LocalMediaStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return "", errors.New("invalid media key: " + key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
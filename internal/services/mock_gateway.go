@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+// Test payment methods understood by MockPaymentGateway. Any other payment method
+// follows the gateway's mode.
+const (
+	MockPaymentMethodApprove      = "pm_card_visa"
+	MockPaymentMethodDecline      = "pm_card_declined"
+	MockPaymentMethodAsync        = "pm_card_processing"          // succeeds later, by webhook
+	MockPaymentMethodAsyncDecline = "pm_card_processing_declined" // fails later, by webhook
+	MockPaymentMethodError        = "pm_card_error"               // the gateway call itself fails
+)
+
+// MockGatewayMode decides the outcome of charges that don't use a test payment method
+type MockGatewayMode string
+
+const (
+	MockGatewayApprove MockGatewayMode = "approve"
+	MockGatewayDecline MockGatewayMode = "decline"
+	MockGatewayAsync   MockGatewayMode = "async"
+)
+
+// IsValid reports whether m is a known mode
+func (m MockGatewayMode) IsValid() bool {
+	switch m {
+	case MockGatewayApprove, MockGatewayDecline, MockGatewayAsync:
+		return true
+	}
+	return false
+}
+
+var (
+	ErrMockGatewayError  = errors.New("mock gateway: simulated gateway error")
+	ErrMockIntentUnknown = errors.New("mock gateway: payment intent not found")
+	ErrMockRefundUnknown = errors.New("mock gateway: refund not found")
+	ErrMockNotCapturable = errors.New("mock gateway: payment intent cannot be captured")
+	ErrMockNotCancelable = errors.New("mock gateway: payment intent cannot be canceled")
+	ErrMockNotRefundable = errors.New("mock gateway: payment intent cannot be refunded")
+)
+
+// MockGatewayOptions configures how MockPaymentGateway behaves
+type MockGatewayOptions struct {
+	Mode         MockGatewayMode
+	FailureRate  float64       // share of approvable charges declined anyway, 0-1
+	ErrorRate    float64       // share of calls that fail with ErrMockGatewayError, 0-1
+	Latency      time.Duration // each call takes between half and one and a half times this
+	WebhookDelay time.Duration // how long async charges stay processing before their webhook
+}
+
+// MockPaymentGateway is an in-memory payments.Gateway for local development. Charges are
+// approved, declined or left processing according to the payment method and options, and
+// processing charges are settled later by a signed payment_intent webhook, so checkout can
+// be exercised end to end without a real gateway account.
+type MockPaymentGateway struct {
+	opts MockGatewayOptions
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	intents map[string]*payments.PaymentIntent
+	refunds map[string]*payments.Refund
+
+	webhooks *WebhookService
+	provider string
+	secret   []byte
+}
+
+// NewMockPaymentGateway creates a mock gateway; an unknown mode approves
+func NewMockPaymentGateway(opts MockGatewayOptions) *MockPaymentGateway {
+	if !opts.Mode.IsValid() {
+		opts.Mode = MockGatewayApprove
+	}
+	return &MockPaymentGateway{
+		opts:    opts,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		intents: make(map[string]*payments.PaymentIntent),
+		refunds: make(map[string]*payments.Refund),
+	}
+}
+
+// WithWebhooks delivers the outcome of processing charges to the webhook provider, signed
+// with its secret. Without it, processing charges stay processing.
+func (g *MockPaymentGateway) WithWebhooks(webhooks *WebhookService, provider, secret string) *MockPaymentGateway {
+	g.webhooks = webhooks
+	g.provider = provider
+	g.secret = []byte(secret)
+	return g
+}
+
+// CreateIntent implements payments.Gateway
+func (g *MockPaymentGateway) CreateIntent(ctx context.Context, req payments.IntentRequest) (*payments.PaymentIntent, error) {
+	if err := g.call(ctx); err != nil {
+		return nil, err
+	}
+	if req.PaymentMethodID == MockPaymentMethodError {
+		return nil, ErrMockGatewayError
+	}
+
+	now := time.Now()
+	intent := &payments.PaymentIntent{
+		ID:              "pi_mock_" + utils.GenerateID(),
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		PaymentMethodID: req.PaymentMethodID,
+		OrderID:         req.OrderID,
+		Description:     req.Description,
+		Metadata:        req.Metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	var settle payments.IntentStatus
+	switch {
+	case req.PaymentMethodID == "":
+		// Nothing to charge yet, as with a hosted checkout the customer hasn't completed
+		intent.Status = payments.IntentStatusPending
+	case g.declines(req.PaymentMethodID):
+		intent.Status = payments.IntentStatusFailed
+	case req.PaymentMethodID == MockPaymentMethodAsyncDecline:
+		intent.Status = payments.IntentStatusProcessing
+		settle = payments.IntentStatusFailed
+	case req.PaymentMethodID == MockPaymentMethodAsync ||
+		(req.PaymentMethodID != MockPaymentMethodApprove && g.opts.Mode == MockGatewayAsync):
+		intent.Status = payments.IntentStatusProcessing
+		settle = payments.IntentStatusSucceeded
+	case req.CaptureMethod == payments.CaptureMethodManual:
+		intent.Status = payments.IntentStatusRequiresAction
+	default:
+		intent.Status = payments.IntentStatusSucceeded
+		intent.CapturedAmount = req.Amount
+	}
+
+	g.mu.Lock()
+	g.intents[intent.ID] = intent
+	result := *intent
+	g.mu.Unlock()
+
+	if settle != "" {
+		time.AfterFunc(g.opts.WebhookDelay, func() { g.settle(intent.ID, settle) })
+	}
+	return &result, nil
+}
+
+// GetIntent implements payments.Gateway
+func (g *MockPaymentGateway) GetIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	if err := g.call(ctx); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrMockIntentUnknown
+	}
+	result := *intent
+	return &result, nil
+}
+
+// CaptureIntent implements payments.Gateway
+func (g *MockPaymentGateway) CaptureIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	return g.update(ctx, intentID, func(intent *payments.PaymentIntent) error {
+		if intent.Status != payments.IntentStatusRequiresAction {
+			return ErrMockNotCapturable
+		}
+		intent.Status = payments.IntentStatusSucceeded
+		intent.CapturedAmount = intent.Amount
+		return nil
+	})
+}
+
+// CancelIntent implements payments.Gateway
+func (g *MockPaymentGateway) CancelIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	return g.update(ctx, intentID, func(intent *payments.PaymentIntent) error {
+		if !intent.IsCancelable() {
+			return ErrMockNotCancelable
+		}
+		intent.Status = payments.IntentStatusCanceled
+		return nil
+	})
+}
+
+// CreateRefund implements payments.Gateway; refunds succeed immediately
+func (g *MockPaymentGateway) CreateRefund(ctx context.Context, req payments.RefundRequest) (*payments.Refund, error) {
+	if err := g.call(ctx); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[req.PaymentIntentID]
+	if !ok {
+		return nil, ErrMockIntentUnknown
+	}
+	if !intent.IsRefundable() || req.Amount.Currency != intent.CapturedAmount.Currency ||
+		req.Amount.Amount > intent.CapturedAmount.Amount-g.refundedLocked(intent.ID) {
+		return nil, ErrMockNotRefundable
+	}
+
+	now := time.Now()
+	refund := &payments.Refund{
+		ID:              "re_mock_" + utils.GenerateID(),
+		PaymentIntentID: intent.ID,
+		Amount:          req.Amount,
+		Currency:        req.Amount.Currency,
+		Status:          payments.RefundStatusSucceeded,
+		Reason:          req.Reason,
+		Metadata:        req.Metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	g.refunds[refund.ID] = refund
+	result := *refund
+	return &result, nil
+}
+
+// GetRefund implements payments.Gateway
+func (g *MockPaymentGateway) GetRefund(ctx context.Context, refundID string) (*payments.Refund, error) {
+	if err := g.call(ctx); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	refund, ok := g.refunds[refundID]
+	if !ok {
+		return nil, ErrMockRefundUnknown
+	}
+	result := *refund
+	return &result, nil
+}
+
+// call simulates the round trip to the gateway: it waits out the configured latency and
+// fails with ErrMockGatewayError at the configured rate
+func (g *MockPaymentGateway) call(ctx context.Context) error {
+	g.mu.Lock()
+	var delay time.Duration
+	if g.opts.Latency > 0 {
+		delay = g.opts.Latency/2 + time.Duration(g.rand.Int63n(int64(g.opts.Latency)+1))
+	}
+	fail := g.opts.ErrorRate > 0 && g.rand.Float64() < g.opts.ErrorRate
+	g.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if fail {
+		return ErrMockGatewayError
+	}
+	return nil
+}
+
+// declines reports whether a charge with paymentMethodID is declined up front
+func (g *MockPaymentGateway) declines(paymentMethodID string) bool {
+	switch paymentMethodID {
+	case MockPaymentMethodDecline:
+		return true
+	case MockPaymentMethodApprove, MockPaymentMethodAsync, MockPaymentMethodAsyncDecline:
+		return false
+	}
+	if g.opts.Mode == MockGatewayDecline {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.opts.FailureRate > 0 && g.rand.Float64() < g.opts.FailureRate
+}
+
+// update applies change to a stored intent
+func (g *MockPaymentGateway) update(ctx context.Context, intentID string, change func(*payments.PaymentIntent) error) (*payments.PaymentIntent, error) {
+	if err := g.call(ctx); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrMockIntentUnknown
+	}
+	if err := change(intent); err != nil {
+		return nil, err
+	}
+	intent.UpdatedAt = time.Now()
+	result := *intent
+	return &result, nil
+}
+
+// refundedLocked totals the refunds issued against an intent; g.mu must be held
+func (g *MockPaymentGateway) refundedLocked(intentID string) int64 {
+	var total int64
+	for _, refund := range g.refunds {
+		if refund.PaymentIntentID == intentID {
+			total += refund.Amount.Amount
+		}
+	}
+	return total
+}
+
+// settle moves a processing intent to its final status and reports it by webhook
+func (g *MockPaymentGateway) settle(intentID string, status payments.IntentStatus) {
+	g.mu.Lock()
+	intent, ok := g.intents[intentID]
+	if !ok || intent.Status != payments.IntentStatusProcessing {
+		g.mu.Unlock()
+		return
+	}
+	intent.Status = status
+	intent.UpdatedAt = time.Now()
+	if status == payments.IntentStatusSucceeded {
+		intent.CapturedAmount = intent.Amount
+	}
+	settled := *intent
+	g.mu.Unlock()
+
+	if err := g.emit(&settled); err != nil {
+		log.Printf("Mock payment gateway failed to deliver webhook for %s: %v", intentID, err)
+	}
+}
+
+// emit sends a signed payment_intent event for intent to the webhook receiver
+func (g *MockPaymentGateway) emit(intent *payments.PaymentIntent) error {
+	if g.webhooks == nil {
+		return nil
+	}
+
+	payload := PaymentIntentWebhookPayload{ID: "evt_mock_" + utils.GenerateID(), Type: PaymentEventIntentSucceeded}
+	if intent.Status != payments.IntentStatusSucceeded {
+		payload.Type = PaymentEventIntentFailed
+		payload.Data.FailureReason = "card_declined"
+	}
+	payload.Data.PaymentIntentID = intent.ID
+	payload.Data.OrderID = intent.OrderID
+	payload.Data.TenderID = intent.Metadata["tender_id"]
+	payload.Data.Amount = intent.Amount.Amount
+	payload.Data.Currency = intent.Amount.Currency
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(body)
+	header := http.Header{}
+	header.Set(PaymentWebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	_, _, err = g.webhooks.Receive(context.Background(), g.provider, header, body)
+	return err
+}
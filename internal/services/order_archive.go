@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// OrderArchiveRepository moves delivered orders out of the orders table into the archive.
+// Archived orders are still found by ID and order number.
+type OrderArchiveRepository interface {
+	// ArchiveDelivered moves up to limit delivered orders not changed since before to the
+	// archive, least recently changed first, and returns how many moved
+	ArchiveDelivered(ctx context.Context, before time.Time, limit int) (int64, error)
+	// CountArchivable counts the delivered orders not changed since before
+	CountArchivable(ctx context.Context, before time.Time) (int64, error)
+	// CountArchived counts the orders in the archive
+	CountArchived(ctx context.Context) (int64, error)
+}
+
+// OrderArchivePolicy sets when delivered orders leave the orders table
+type OrderArchivePolicy struct {
+	AfterMonths int // delivered orders not changed for this many months are archived; 0 never archives
+	BatchSize   int // orders moved per statement
+}
+
+// OrderArchiveReport is the outcome of one archive run
+type OrderArchiveReport struct {
+	DryRun      bool       `json:"dry_run"`
+	RanAt       time.Time  `json:"ran_at"`
+	AfterMonths int        `json:"after_months"`
+	Before      *time.Time `json:"before,omitempty"` // left out when archiving is off
+	Orders      int64      `json:"orders"`           // moved, or that would move in a dry run
+	Archived    int64      `json:"archived"`         // orders in the archive after the run
+}
+
+// OrderArchiveService keeps the orders table small by moving delivered orders to the
+// archive once they are old enough
+type OrderArchiveService struct {
+	repo   OrderArchiveRepository
+	policy OrderArchivePolicy
+}
+
+// NewOrderArchiveService creates a new OrderArchiveService
+func NewOrderArchiveService(repo OrderArchiveRepository, policy OrderArchivePolicy) *OrderArchiveService {
+	if policy.BatchSize < 1 {
+		policy.BatchSize = 500
+	}
+	return &OrderArchiveService{repo: repo, policy: policy}
+}
+
+// Run archives every delivered order past the policy's age, a batch at a time. With dryRun
+// nothing is moved and the report shows how many orders a real run would move.
+func (s *OrderArchiveService) Run(ctx context.Context, dryRun bool) (*OrderArchiveReport, error) {
+	now := time.Now()
+	report := &OrderArchiveReport{DryRun: dryRun, RanAt: now, AfterMonths: s.policy.AfterMonths}
+
+	if s.policy.AfterMonths > 0 {
+		before := now.AddDate(0, -s.policy.AfterMonths, 0)
+		report.Before = &before
+
+		if dryRun {
+			count, err := s.repo.CountArchivable(ctx, before)
+			if err != nil {
+				return report, err
+			}
+			report.Orders = count
+		} else {
+			for {
+				moved, err := s.repo.ArchiveDelivered(ctx, before, s.policy.BatchSize)
+				report.Orders += moved
+				if err != nil {
+					return report, err
+				}
+				if moved < int64(s.policy.BatchSize) {
+					break
+				}
+				if err := ctx.Err(); err != nil {
+					return report, err
+				}
+			}
+		}
+	}
+
+	archived, err := s.repo.CountArchived(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Archived = archived
+	return report, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+var (
+	ErrInvalidAttribution = errors.New("channel must be web, mobile_app, pos or marketplace and UTM values at most 255 characters")
+	ErrInvalidReportRange = errors.New("report range must not end before it starts and must span at most 366 days")
+)
+
+const (
+	maxUTMLength   = 255 // longest UTM value stored
+	maxReportRange = 366 // days
+)
+
+// OrderChannel is where an order was placed
+type OrderChannel string
+
+const (
+	ChannelWeb         OrderChannel = "web"
+	ChannelMobileApp   OrderChannel = "mobile_app"
+	ChannelPOS         OrderChannel = "pos"
+	ChannelMarketplace OrderChannel = "marketplace"
+)
+
+// IsValid reports whether the channel is a known one
+func (c OrderChannel) IsValid() bool {
+	switch c {
+	case ChannelWeb, ChannelMobileApp, ChannelPOS, ChannelMarketplace:
+		return true
+	}
+	return false
+}
+
+// UTM holds the campaign parameters of the visit an order came from
+type UTM struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// OrderAttribution records the channel an order came through and its UTM parameters
+type OrderAttribution struct {
+	OrderID   string       `json:"order_id"`
+	Channel   OrderChannel `json:"channel"`
+	UTM       UTM          `json:"utm"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// AttributedOrder is the part of an attributed order the channel report counts
+type AttributedOrder struct {
+	Channel OrderChannel
+	UTM     UTM
+	Status  orders.OrderStatus
+	Total   money.Money
+}
+
+// ChannelRevenue is the revenue of one channel in one currency
+type ChannelRevenue struct {
+	Channel OrderChannel `json:"channel"`
+	Orders  int          `json:"orders"`
+	Revenue money.Money  `json:"revenue"`
+}
+
+// CampaignRevenue is the revenue of one UTM source, medium and campaign in one currency
+type CampaignRevenue struct {
+	Source   string      `json:"source"`
+	Medium   string      `json:"medium"`
+	Campaign string      `json:"campaign"`
+	Orders   int         `json:"orders"`
+	Revenue  money.Money `json:"revenue"`
+}
+
+// ChannelReport is revenue per channel and per UTM campaign for orders placed within
+// [From, To]. Canceled orders are left out; amounts in different currencies get
+// separate rows.
+type ChannelReport struct {
+	From      string             `json:"from"` // YYYY-MM-DD
+	To        string             `json:"to"`   // YYYY-MM-DD, inclusive
+	Channels  []*ChannelRevenue  `json:"channels"`
+	Campaigns []*CampaignRevenue `json:"campaigns"` // only orders with UTM parameters
+}
+
+// OrderAttributionRepository defines persistence for order attribution
+type OrderAttributionRepository interface {
+	Save(ctx context.Context, attribution *OrderAttribution) error
+	// FindByOrderID returns nil when the order has no attribution
+	FindByOrderID(ctx context.Context, orderID string) (*OrderAttribution, error)
+	// ListAttributedOrders returns the orders attributed within [from, to)
+	ListAttributedOrders(ctx context.Context, from, to time.Time) ([]*AttributedOrder, error)
+}
+
+// OrderAttributionService records where orders come from and reports revenue per channel
+type OrderAttributionService struct {
+	repo OrderAttributionRepository
+}
+
+// NewOrderAttributionService creates a new OrderAttributionService
+func NewOrderAttributionService(repo OrderAttributionRepository) *OrderAttributionService {
+	return &OrderAttributionService{
+		repo: repo,
+	}
+}
+
+// Validate checks a channel and UTM parameters without recording them. An empty
+// channel is web.
+func (s *OrderAttributionService) Validate(channel OrderChannel, utm UTM) error {
+	if channel != "" && !channel.IsValid() {
+		return ErrInvalidAttribution
+	}
+	for _, value := range []string{utm.Source, utm.Medium, utm.Campaign, utm.Term, utm.Content} {
+		if len(strings.TrimSpace(value)) > maxUTMLength {
+			return ErrInvalidAttribution
+		}
+	}
+	return nil
+}
+
+// Record records the channel and UTM parameters of a new order. An empty channel is web.
+func (s *OrderAttributionService) Record(ctx context.Context, orderID string, channel OrderChannel, utm UTM) (*OrderAttribution, error) {
+	if err := s.Validate(channel, utm); err != nil {
+		return nil, err
+	}
+	if channel == "" {
+		channel = ChannelWeb
+	}
+
+	attribution := &OrderAttribution{
+		OrderID: orderID,
+		Channel: channel,
+		UTM: UTM{
+			Source:   strings.TrimSpace(utm.Source),
+			Medium:   strings.TrimSpace(utm.Medium),
+			Campaign: strings.TrimSpace(utm.Campaign),
+			Term:     strings.TrimSpace(utm.Term),
+			Content:  strings.TrimSpace(utm.Content),
+		},
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, attribution); err != nil {
+		return nil, err
+	}
+	return attribution, nil
+}
+
+// GetOrderAttribution returns an order's attribution, or nil when it has none
+func (s *OrderAttributionService) GetOrderAttribution(ctx context.Context, orderID string) (*OrderAttribution, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// Report returns revenue per channel and per UTM campaign for orders placed from the
+// start of from through the end of to
+func (s *OrderAttributionService) Report(ctx context.Context, from, to time.Time) (*ChannelReport, error) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1)
+	if !end.After(start) || end.After(start.AddDate(0, 0, maxReportRange)) {
+		return nil, ErrInvalidReportRange
+	}
+	attributed, err := s.repo.ListAttributedOrders(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChannelReport{
+		From:      start.Format(posDateLayout),
+		To:        to.Format(posDateLayout),
+		Channels:  []*ChannelRevenue{},
+		Campaigns: []*CampaignRevenue{},
+	}
+	channels := make(map[string]*ChannelRevenue)
+	campaigns := make(map[string]*CampaignRevenue)
+	for _, order := range attributed {
+		if order.Status == orders.OrderStatusCanceled {
+			continue
+		}
+
+		key := string(order.Channel) + "|" + order.Total.Currency
+		row, ok := channels[key]
+		if !ok {
+			row = &ChannelRevenue{Channel: order.Channel, Revenue: money.Zero(order.Total.Currency)}
+			channels[key] = row
+			report.Channels = append(report.Channels, row)
+		}
+		row.Orders++
+		if row.Revenue, err = row.Revenue.Add(order.Total); err != nil {
+			return nil, err
+		}
+
+		if order.UTM.Source == "" && order.UTM.Medium == "" && order.UTM.Campaign == "" {
+			continue
+		}
+		key = strings.Join([]string{order.UTM.Source, order.UTM.Medium, order.UTM.Campaign, order.Total.Currency}, "|")
+		campaign, ok := campaigns[key]
+		if !ok {
+			campaign = &CampaignRevenue{
+				Source:   order.UTM.Source,
+				Medium:   order.UTM.Medium,
+				Campaign: order.UTM.Campaign,
+				Revenue:  money.Zero(order.Total.Currency),
+			}
+			campaigns[key] = campaign
+			report.Campaigns = append(report.Campaigns, campaign)
+		}
+		campaign.Orders++
+		if campaign.Revenue, err = campaign.Revenue.Add(order.Total); err != nil {
+			return nil, err
+		}
+	}
+
+	// Highest revenue first
+	sort.SliceStable(report.Channels, func(i, j int) bool {
+		return report.Channels[i].Revenue.Amount > report.Channels[j].Revenue.Amount
+	})
+	sort.SliceStable(report.Campaigns, func(i, j int) bool {
+		return report.Campaigns[i].Revenue.Amount > report.Campaigns[j].Revenue.Amount
+	})
+	return report, nil
+}
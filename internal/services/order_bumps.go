@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrOrderBumpNotFound    = errors.New("order bump not found")
+	ErrInvalidOrderBump     = errors.New("invalid order bump")
+	ErrOrderBumpUnavailable = errors.New("order bump is not offered for this cart")
+)
+
+// OrderBumpAttribute is the order item attribute recording the order bump an item was
+// added by at checkout
+const OrderBumpAttribute = "order_bump_id"
+
+// maxOrderBumpOffers is how many order bumps are offered at checkout at most
+const maxOrderBumpOffers = 3
+
+// OrderBump is a small add-on offered at checkout at a special price. Customers accept it
+// when placing the order, which adds one unit of the product to the order.
+type OrderBump struct {
+	ID        string      `json:"id"`
+	ProductID string      `json:"product_id"`
+	Headline  string      `json:"headline"`
+	Price     money.Money `json:"price"`    // in the currency of the product's price
+	Position  int         `json:"position"` // lower positions are offered first
+	IsActive  bool        `json:"is_active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// OrderBumpOffer is an order bump as shown at checkout, with the product's regular price
+type OrderBumpOffer struct {
+	ID           string      `json:"id"`
+	ProductID    string      `json:"product_id"`
+	Name         string      `json:"name"`
+	Headline     string      `json:"headline"`
+	Price        money.Money `json:"price"`
+	RegularPrice money.Money `json:"regular_price"`
+}
+
+// OrderBumpRepository defines persistence for order bumps
+type OrderBumpRepository interface {
+	FindByID(ctx context.Context, id string) (*OrderBump, error)
+	FindAll(ctx context.Context) ([]*OrderBump, error)
+	FindActive(ctx context.Context) ([]*OrderBump, error)
+	Save(ctx context.Context, bump *OrderBump) error
+	Delete(ctx context.Context, id string) error
+}
+
+// OrderBumpService manages the add-ons offered at checkout and adds the accepted ones to
+// the order being placed
+type OrderBumpService struct {
+	repo     OrderBumpRepository
+	products catalog.ProductRepository
+}
+
+// NewOrderBumpService creates a new OrderBumpService
+func NewOrderBumpService(repo OrderBumpRepository, products catalog.ProductRepository) *OrderBumpService {
+	return &OrderBumpService{repo: repo, products: products}
+}
+
+// ListBumps returns every order bump, in the order they are offered
+func (s *OrderBumpService) ListBumps(ctx context.Context) ([]*OrderBump, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetBump returns an order bump
+func (s *OrderBumpService) GetBump(ctx context.Context, id string) (*OrderBump, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// CreateBump validates and saves a new order bump
+func (s *OrderBumpService) CreateBump(ctx context.Context, bump *OrderBump) error {
+	if err := s.validate(ctx, bump); err != nil {
+		return err
+	}
+	now := time.Now()
+	bump.ID = utils.GenerateID()
+	bump.CreatedAt = now
+	bump.UpdatedAt = now
+	return s.repo.Save(ctx, bump)
+}
+
+// UpdateBump validates and saves changes to an existing order bump
+func (s *OrderBumpService) UpdateBump(ctx context.Context, bump *OrderBump) error {
+	existing, err := s.repo.FindByID(ctx, bump.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, bump); err != nil {
+		return err
+	}
+	bump.CreatedAt = existing.CreatedAt
+	bump.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, bump)
+}
+
+// DeleteBump removes an order bump
+func (s *OrderBumpService) DeleteBump(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Offers returns the order bumps to show at checkout for a cart: active bumps of products
+// still for sale that aren't in the cart already, priced in the cart's currency
+func (s *OrderBumpService) Offers(ctx context.Context, c *cart.Cart) ([]OrderBumpOffer, error) {
+	offers := make([]OrderBumpOffer, 0)
+	if c == nil || len(c.Items) == 0 {
+		return offers, nil
+	}
+
+	bumps, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, bump := range bumps {
+		if offer, _ := s.offer(ctx, c, bump); offer != nil {
+			offers = append(offers, *offer)
+		}
+		if len(offers) == maxOrderBumpOffers {
+			break
+		}
+	}
+	return offers, nil
+}
+
+// Apply returns a copy of the cart with one unit of each accepted order bump added at its
+// special price, for placing the order. The stored cart is left as it is, so the bumps
+// only become part of the order if it is placed. A bump the cart isn't offered fails
+// with ErrOrderBumpUnavailable.
+func (s *OrderBumpService) Apply(ctx context.Context, c *cart.Cart, bumpIDs []string) (*cart.Cart, error) {
+	if len(bumpIDs) == 0 {
+		return c, nil
+	}
+
+	withBumps := *c
+	withBumps.Items = append([]cart.CartItem(nil), c.Items...)
+	accepted := make(map[string]bool, len(bumpIDs))
+	for _, id := range bumpIDs {
+		if accepted[id] {
+			return nil, fmt.Errorf("%w: %s is accepted twice", ErrInvalidOrderBump, id)
+		}
+		accepted[id] = true
+
+		bump, err := s.repo.FindByID(ctx, id)
+		if errors.Is(err, ErrOrderBumpNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrOrderBumpUnavailable, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !bump.IsActive {
+			return nil, fmt.Errorf("%w: %s", ErrOrderBumpUnavailable, id)
+		}
+		offer, product := s.offer(ctx, &withBumps, bump)
+		if offer == nil {
+			return nil, fmt.Errorf("%w: %s", ErrOrderBumpUnavailable, id)
+		}
+		withBumps.Items = append(withBumps.Items, cart.CartItem{
+			ID:         utils.GenerateID(),
+			ProductID:  product.ID,
+			SKU:        product.SKU,
+			Name:       product.Name,
+			Price:      bump.Price,
+			Quantity:   1,
+			Attributes: map[string]string{OrderBumpAttribute: bump.ID},
+			AddedAt:    time.Now(),
+		})
+	}
+	return &withBumps, nil
+}
+
+// offer returns the bump as offered to the cart with its product, or nil when the cart
+// isn't offered it. Bumps of products that can't be found or are no longer for sale
+// aren't offered.
+func (s *OrderBumpService) offer(ctx context.Context, c *cart.Cart, bump *OrderBump) (*OrderBumpOffer, *catalog.Product) {
+	if CartQuantities(c)[bump.ProductID] > 0 || bump.Price.Currency != c.Subtotal().Currency {
+		return nil, nil
+	}
+	product, err := s.products.FindByID(ctx, bump.ProductID)
+	if err != nil || !product.IsActive() {
+		return nil, nil
+	}
+	return &OrderBumpOffer{
+		ID:           bump.ID,
+		ProductID:    bump.ProductID,
+		Name:         product.Name,
+		Headline:     bump.Headline,
+		Price:        bump.Price,
+		RegularPrice: product.BasePrice,
+	}, product
+}
+
+// validate checks an order bump's fields and sets its price in the product's currency
+func (s *OrderBumpService) validate(ctx context.Context, bump *OrderBump) error {
+	bump.Headline = strings.TrimSpace(bump.Headline)
+	if bump.Headline == "" || len(bump.Headline) > 255 {
+		return fmt.Errorf("%w: headline is required and at most 255 characters", ErrInvalidOrderBump)
+	}
+	if !bump.Price.IsPositive() {
+		return fmt.Errorf("%w: price must be positive", ErrInvalidOrderBump)
+	}
+	product, err := s.products.FindByID(ctx, bump.ProductID)
+	if err != nil {
+		return ErrProductNotFound
+	}
+	bump.Price.Currency = product.BasePrice.Currency
+	return nil
+}
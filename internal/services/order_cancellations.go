@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/payments"
+)
+
+var (
+	ErrOrderNotCancelable  = errors.New("order can no longer be canceled")
+	ErrInvalidCancelReason = errors.New("reason must be between 1 and 500 characters")
+)
+
+const maxCancelReasonLength = 500
+
+// CancelOrderRequest describes a cancellation. Staff may also cancel orders that are being
+// processed; customers only before then.
+type CancelOrderRequest struct {
+	Reason     string
+	CanceledBy string
+	Staff      bool
+}
+
+// OrderCancellation is a canceled order with the refund of what was paid for it, if any
+type OrderCancellation struct {
+	Order  *orders.Order `json:"order"`
+	Refund *OrderRefund  `json:"refund,omitempty"`
+}
+
+// OrderCancellationService cancels orders and rolls back what checkout did: money taken is
+// refunded to the order's payment tenders, reserved stock is released, and the delivery
+// slot and any invoice are freed
+type OrderCancellationService struct {
+	orderService    *OrderService
+	refundService   *RefundService
+	deliveryService *DeliveryService
+	invoices        *InvoiceService
+	appointments    *AppointmentService
+}
+
+// NewOrderCancellationService creates a new OrderCancellationService
+func NewOrderCancellationService(orderService *OrderService, refundService *RefundService) *OrderCancellationService {
+	return &OrderCancellationService{orderService: orderService, refundService: refundService}
+}
+
+// WithDeliveryService frees the delivery slot booked by a canceled order
+func (s *OrderCancellationService) WithDeliveryService(deliveryService *DeliveryService) *OrderCancellationService {
+	s.deliveryService = deliveryService
+	return s
+}
+
+// WithInvoiceService voids the open invoice of a canceled order paid by invoice
+func (s *OrderCancellationService) WithInvoiceService(invoices *InvoiceService) *OrderCancellationService {
+	s.invoices = invoices
+	return s
+}
+
+// WithAppointments keeps customers from canceling orders with an appointment inside its
+// cancellation window; staff still can
+func (s *OrderCancellationService) WithAppointments(appointments *AppointmentService) *OrderCancellationService {
+	s.appointments = appointments
+	return s
+}
+
+// Cancel cancels an order with a reason. Paid orders are refunded in full first, so a
+// failed refund leaves the order as it was, and canceling it again refunds what is left.
+func (s *OrderCancellationService) Cancel(ctx context.Context, orderID string, req CancelOrderRequest) (*OrderCancellation, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" || len(req.Reason) > maxCancelReasonLength {
+		return nil, ErrInvalidCancelReason
+	}
+
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !canCancel(order.Status, req.Staff) {
+		return nil, ErrOrderNotCancelable
+	}
+	if s.appointments != nil && !req.Staff {
+		if err := s.appointments.CheckCancel(ctx, order.ID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	cancellation := &OrderCancellation{}
+	if s.refundService != nil {
+		reason := payments.RefundReasonRequestedByCustomer
+		if req.Staff {
+			reason = payments.RefundReasonOther
+		}
+		if cancellation.Refund, err = s.refundService.RefundCancellation(ctx, order, reason, "Order canceled: "+req.Reason, req.CanceledBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.deliveryService != nil {
+		if err := s.deliveryService.ReleaseSlot(ctx, order.ID); err != nil {
+			return nil, err
+		}
+	}
+	if s.invoices != nil {
+		if err := s.invoices.VoidOrderInvoice(ctx, order.ID, req.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	// The order service releases the items' reserved stock and records the reason on the
+	// order and its timeline
+	ctx = WithStatusChangedBy(ctx, req.CanceledBy)
+	if cancellation.Order, err = s.orderService.CancelOrder(ctx, order.ID, req.Reason); err != nil {
+		return nil, err
+	}
+	return cancellation, nil
+}
+
+// canCancel reports whether an order in this status may be canceled. Orders being
+// processed may only be canceled by staff, who can still stop them in the warehouse.
+func canCancel(status orders.OrderStatus, staff bool) bool {
+	switch status {
+	case orders.OrderStatusPending, orders.OrderStatusPaid:
+		return true
+	case orders.OrderStatusProcessing:
+		return staff
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+)
+
+var (
+	ErrOrderConfirmationsDisabled = errors.New("order confirmation emails are disabled")
+	ErrNoConfirmationEmail        = errors.New("order has no email address to send a confirmation to")
+)
+
+// OrderConfirmationJobKind is the job kind of order confirmation emails, whose failures are
+// dead-lettered and replayed with ReplayConfirmation
+const OrderConfirmationJobKind = "order-confirmation"
+
+// ConfirmationResendLimitedError reports a resend refused because the order's confirmation
+// was sent too recently or too often
+type ConfirmationResendLimitedError struct {
+	RetryAt time.Time
+}
+
+func (e *ConfirmationResendLimitedError) Error() string {
+	return fmt.Sprintf("order confirmation was resent too recently; try again after %s", e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+// ConfirmationResendPolicy limits how often an order's confirmation may be resent
+type ConfirmationResendPolicy struct {
+	Cooldown  time.Duration // least time between two resends of an order's confirmation
+	MaxResend int           // resends of an order's confirmation allowed per Window
+	Window    time.Duration
+}
+
+// OrderConfirmation is an order confirmation email to send
+type OrderConfirmation struct {
+	OrderID string    `json:"order_id"`
+	UserID  string    `json:"user_id"`
+	Resend  bool      `json:"resend"`
+	At      time.Time `json:"at"`
+}
+
+// OrderConfirmationNotifier emails customers the confirmation of an order they placed
+type OrderConfirmationNotifier interface {
+	NotifyOrderConfirmation(ctx context.Context, email string, order *orders.Order, resend bool) error
+}
+
+// CustomerEmailLookup returns the email address of a signed-in customer
+type CustomerEmailLookup func(ctx context.Context, userID string) (string, error)
+
+// OrderConfirmationService emails customers their order confirmation when an order is
+// placed, and again on request within the resend policy
+type OrderConfirmationService struct {
+	orderRepo orders.Repository
+	emails    CustomerEmailLookup
+	notifier  OrderConfirmationNotifier
+	runner    *jobs.Runner
+	policy    ConfirmationResendPolicy
+
+	mu      sync.Mutex
+	resends map[string]*confirmationResends
+}
+
+// confirmationResends tracks the resends of one order's confirmation in the current window
+type confirmationResends struct {
+	windowStart time.Time
+	count       int
+	last        time.Time
+}
+
+// NewOrderConfirmationService creates a new OrderConfirmationService. Without a notifier
+// no confirmations are sent.
+func NewOrderConfirmationService(orderRepo orders.Repository, emails CustomerEmailLookup, notifier OrderConfirmationNotifier, policy ConfirmationResendPolicy) *OrderConfirmationService {
+	if policy.MaxResend < 1 {
+		policy.MaxResend = 1
+	}
+	if policy.Window < policy.Cooldown {
+		policy.Window = policy.Cooldown
+	}
+	return &OrderConfirmationService{
+		orderRepo: orderRepo,
+		emails:    emails,
+		notifier:  notifier,
+		policy:    policy,
+		resends:   make(map[string]*confirmationResends),
+	}
+}
+
+// WithRunner queues confirmations on runner so a slow mail server doesn't hold up checkout;
+// without a runner they are sent inline
+func (s *OrderConfirmationService) WithRunner(runner *jobs.Runner) *OrderConfirmationService {
+	s.runner = runner
+	return s
+}
+
+// Send emails the confirmation of a newly placed order. Guest orders, which have no email
+// address on file, are skipped.
+func (s *OrderConfirmationService) Send(ctx context.Context, order *orders.Order) {
+	if s.notifier == nil || IsGuestUserID(order.UserID) {
+		return
+	}
+	s.enqueue(OrderConfirmation{OrderID: order.ID, UserID: order.UserID, At: time.Now()})
+}
+
+// Resend emails an order's confirmation again. It returns a *ConfirmationResendLimitedError
+// when the order's confirmation was resent less than the cooldown ago, or MaxResend times
+// already in the window.
+func (s *OrderConfirmationService) Resend(ctx context.Context, order *orders.Order) error {
+	if s.notifier == nil {
+		return ErrOrderConfirmationsDisabled
+	}
+	if IsGuestUserID(order.UserID) {
+		return ErrNoConfirmationEmail
+	}
+	if err := s.allowResend(order.ID, time.Now()); err != nil {
+		return err
+	}
+	s.enqueue(OrderConfirmation{OrderID: order.ID, UserID: order.UserID, Resend: true, At: time.Now()})
+	return nil
+}
+
+// ReplayConfirmation sends again the confirmation of a dead-lettered confirmation job
+func (s *OrderConfirmationService) ReplayConfirmation(ctx context.Context, payload []byte) error {
+	if s.notifier == nil {
+		return ErrOrderConfirmationsDisabled
+	}
+	var confirmation OrderConfirmation
+	if err := json.Unmarshal(payload, &confirmation); err != nil {
+		return err
+	}
+	return s.send(ctx, confirmation)
+}
+
+// allowResend counts a resend of the order's confirmation against the policy
+func (s *OrderConfirmationService) allowResend(orderID string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, resends := range s.resends {
+		if now.Sub(resends.windowStart) >= s.policy.Window {
+			delete(s.resends, id)
+		}
+	}
+
+	resends, ok := s.resends[orderID]
+	if !ok {
+		s.resends[orderID] = &confirmationResends{windowStart: now, count: 1, last: now}
+		return nil
+	}
+	if retryAt := resends.last.Add(s.policy.Cooldown); now.Before(retryAt) {
+		return &ConfirmationResendLimitedError{RetryAt: retryAt}
+	}
+	if resends.count >= s.policy.MaxResend {
+		return &ConfirmationResendLimitedError{RetryAt: resends.windowStart.Add(s.policy.Window)}
+	}
+	resends.count++
+	resends.last = now
+	return nil
+}
+
+func (s *OrderConfirmationService) enqueue(confirmation OrderConfirmation) {
+	send := func(ctx context.Context) error {
+		return s.send(ctx, confirmation)
+	}
+	if s.runner == nil {
+		if err := send(context.Background()); err != nil {
+			log.Printf("Failed to send confirmation of order %s: %v", confirmation.OrderID, err)
+		}
+		return
+	}
+	payload, _ := json.Marshal(confirmation)
+	if err := s.runner.Enqueue(jobs.Job{Name: "order-confirmation:" + confirmation.OrderID, Kind: OrderConfirmationJobKind, Payload: payload, Run: send}); err != nil {
+		log.Printf("Failed to queue confirmation of order %s: %v", confirmation.OrderID, err)
+	}
+}
+
+// send looks up the order and its customer's email address and emails the confirmation
+func (s *OrderConfirmationService) send(ctx context.Context, confirmation OrderConfirmation) error {
+	order, err := s.orderRepo.FindByID(ctx, confirmation.OrderID)
+	if err != nil {
+		return err
+	}
+	email, err := s.emails(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	if email == "" {
+		return ErrNoConfirmationEmail
+	}
+	return s.notifier.NotifyOrderConfirmation(ctx, email, order, confirmation.Resend)
+}
+
+// LogOrderConfirmationNotifier writes order confirmations to the log, for development or
+// until a mail server is configured
+type LogOrderConfirmationNotifier struct{}
+
+// NotifyOrderConfirmation logs the confirmation
+func (LogOrderConfirmationNotifier) NotifyOrderConfirmation(ctx context.Context, email string, order *orders.Order, resend bool) error {
+	log.Printf("Order confirmation for %s to %s: %d items, total %s %s (resend: %t)",
+		order.OrderNumber, email, len(order.Items), exportAmount(order.Total), order.Total.Currency, resend)
+	return nil
+}
+
+// SMTPOrderConfirmationNotifier emails order confirmations through an SMTP server
+type SMTPOrderConfirmationNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPOrderConfirmationNotifier creates an SMTPOrderConfirmationNotifier. Without a
+// username the server is used without authentication.
+func NewSMTPOrderConfirmationNotifier(host string, port int, username, password, from string) *SMTPOrderConfirmationNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPOrderConfirmationNotifier{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from}
+}
+
+// NotifyOrderConfirmation emails the customer a receipt of the order
+func (n *SMTPOrderConfirmationNotifier) NotifyOrderConfirmation(ctx context.Context, email string, order *orders.Order, resend bool) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Thank you for your order.\r\n\r\nOrder: %s\r\nPlaced: %s\r\n\r\n",
+		order.OrderNumber, order.CreatedAt.UTC().Format(time.RFC1123))
+	for _, item := range order.Items {
+		fmt.Fprintf(&body, "%d x %s (%s)  %s %s\r\n", item.Quantity, item.Name, item.SKU, exportAmount(item.Total), item.Total.Currency)
+	}
+	currency := order.Total.Currency
+	fmt.Fprintf(&body, "\r\nSubtotal: %s %s\r\nDiscounts: -%s %s\r\nShipping: %s %s\r\nTax: %s %s\r\nTotal: %s %s\r\n",
+		exportAmount(order.Subtotal), currency, exportAmount(order.DiscountTotal), currency,
+		exportAmount(order.ShippingTotal), currency, exportAmount(order.TaxTotal), currency,
+		exportAmount(order.Total), currency)
+
+	subject := "Your order " + order.OrderNumber
+	if resend {
+		subject = "Copy of your order " + order.OrderNumber
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s", n.from, email, subject, body.String())
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{email}, []byte(message))
+}
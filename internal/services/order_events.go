@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var ErrInvalidOrderNote = errors.New("note must be between 1 and 2000 characters")
+
+const maxOrderNoteLength = 2000
+
+// OrderEventType is the kind of entry on an order's timeline
+type OrderEventType string
+
+const (
+	OrderEventStatusChanged   OrderEventType = "status_changed"
+	OrderEventShipmentCreated OrderEventType = "shipment_created"
+	OrderEventRefundIssued    OrderEventType = "refund_issued"
+	OrderEventNote            OrderEventType = "note"
+)
+
+// orderStatusMessages describe each status the way a customer would read it
+var orderStatusMessages = map[orders.OrderStatus]string{
+	orders.OrderStatusPending:    "Order placed",
+	orders.OrderStatusPaid:       "Payment received",
+	orders.OrderStatusProcessing: "Preparing your order",
+	orders.OrderStatusShipped:    "Order shipped",
+	orders.OrderStatusDelivered:  "Order delivered",
+	orders.OrderStatusCanceled:   "Order canceled",
+	orders.OrderStatusRefunded:   "Order refunded",
+}
+
+// OrderEvent is one entry on an order's timeline
+type OrderEvent struct {
+	ID         string             `json:"id"`
+	OrderID    string             `json:"order_id"`
+	Type       OrderEventType     `json:"type"`
+	Message    string             `json:"message"`
+	FromStatus orders.OrderStatus `json:"from_status,omitempty"`
+	Status     orders.OrderStatus `json:"status,omitempty"`
+	Amount     *money.Money       `json:"amount,omitempty"`
+	Details    map[string]string  `json:"details,omitempty"`
+	Internal   bool               `json:"internal,omitempty"` // staff-only notes, hidden from the customer
+	CreatedBy  string             `json:"created_by,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// OrderEventRepository defines persistence for order timeline events
+type OrderEventRepository interface {
+	Append(ctx context.Context, event *OrderEvent) error
+	// ListByOrder lists an order's events, oldest first
+	ListByOrder(ctx context.Context, orderID string) ([]*OrderEvent, error)
+}
+
+// OrderEventService records what happens to an order so customers can follow its progress
+type OrderEventService struct {
+	repo OrderEventRepository
+}
+
+// NewOrderEventService creates a new OrderEventService
+func NewOrderEventService(repo OrderEventRepository) *OrderEventService {
+	return &OrderEventService{repo: repo}
+}
+
+// Timeline returns an order's events, oldest first. Customers don't see internal notes or
+// who made a change.
+func (s *OrderEventService) Timeline(ctx context.Context, orderID string, staff bool) ([]*OrderEvent, error) {
+	events, err := s.repo.ListByOrder(ctx, orderID)
+	if err != nil || staff {
+		return events, err
+	}
+
+	visible := make([]*OrderEvent, 0, len(events))
+	for _, event := range events {
+		if event.Internal {
+			continue
+		}
+		customerView := *event
+		customerView.CreatedBy = ""
+		visible = append(visible, &customerView)
+	}
+	return visible, nil
+}
+
+// AddNote adds a staff note to the timeline; internal notes are shown to staff only
+func (s *OrderEventService) AddNote(ctx context.Context, orderID, body string, internal bool, createdBy string) (*OrderEvent, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len(body) > maxOrderNoteLength {
+		return nil, ErrInvalidOrderNote
+	}
+
+	event := newOrderEvent(orderID, OrderEventNote, body)
+	event.Internal = internal
+	event.CreatedBy = createdBy
+	if err := s.repo.Append(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// statusChangedByKey is the context key of the user changing an order's status
+type statusChangedByKey struct{}
+
+// WithStatusChangedBy records on ctx the user changing an order's status, for the timeline
+func WithStatusChangedBy(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, statusChangedByKey{}, userID)
+}
+
+// RecordStatusChange records an order moving from one status to another. A reason, such
+// as why an order was canceled, is kept in the event details, and the user making the
+// change when one is recorded on ctx.
+func (s *OrderEventService) RecordStatusChange(ctx context.Context, orderID string, from, to orders.OrderStatus, reason string) {
+	message, ok := orderStatusMessages[to]
+	if !ok {
+		message = "Order " + string(to)
+	}
+	event := newOrderEvent(orderID, OrderEventStatusChanged, message)
+	event.FromStatus = from
+	event.Status = to
+	event.CreatedBy, _ = ctx.Value(statusChangedByKey{}).(string)
+	if reason != "" {
+		event.Details = map[string]string{"reason": reason}
+	}
+	s.append(ctx, event)
+}
+
+// RecordShipment records a shipment leaving the warehouse
+func (s *OrderEventService) RecordShipment(ctx context.Context, shipment *Shipment) {
+	event := newOrderEvent(shipment.OrderID, OrderEventShipmentCreated, "Shipped with "+shipment.Carrier)
+	event.Details = map[string]string{
+		"shipment_id":     shipment.ID,
+		"carrier":         shipment.Carrier,
+		"tracking_number": shipment.TrackingNumber,
+	}
+	if shipment.TrackingURL != "" {
+		event.Details["tracking_url"] = shipment.TrackingURL
+	}
+	event.CreatedAt = shipment.ShippedAt
+	s.append(ctx, event)
+}
+
+// RecordRefund records money going back to the customer
+func (s *OrderEventService) RecordRefund(ctx context.Context, refund *OrderRefund) {
+	amount := refund.Amount
+	event := newOrderEvent(refund.OrderID, OrderEventRefundIssued, "Refund issued")
+	event.Amount = &amount
+	event.Details = map[string]string{"refund_id": refund.ID}
+	event.CreatedBy = refund.CreatedBy
+	s.append(ctx, event)
+}
+
+// append stores an event; a timeline entry that fails to save is logged rather than
+// failing the change it describes, which has already happened
+func (s *OrderEventService) append(ctx context.Context, event *OrderEvent) {
+	if err := s.repo.Append(ctx, event); err != nil {
+		log.Printf("Order %s timeline event %s not recorded: %v", event.OrderID, event.Type, err)
+	}
+}
+
+func newOrderEvent(orderID string, eventType OrderEventType, message string) *OrderEvent {
+	return &OrderEvent{
+		ID:        utils.GenerateID(),
+		OrderID:   orderID,
+		Type:      eventType,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+}
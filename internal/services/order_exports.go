@@ -0,0 +1,481 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidExportRange      = errors.New("invalid export range")
+	ErrUnsupportedExportFormat = errors.New("unsupported export format: only csv is available")
+	ErrOrderExportNotFound     = errors.New("order export not found")
+	ErrOrderExportInProgress   = errors.New("an order export is already in progress")
+	ErrOrderExportNotReady     = errors.New("order export not ready")
+	ErrInvalidExportLink       = errors.New("invalid or expired export link")
+)
+
+const (
+	// OrderExportFormatCSV is the only export format; spreadsheets open it directly
+	OrderExportFormatCSV = "csv"
+	// maxOrderExports bounds how many finished exports are kept for their links to be read
+	maxOrderExports = 500
+)
+
+// orderExportHeader is the CSV header; each row is one line item with its order's columns
+// repeated, and orders without items get one row with the item columns empty
+var orderExportHeader = []string{
+	"order_number", "order_date", "status", "currency",
+	"order_subtotal", "order_discount", "order_tax", "order_shipping", "order_total",
+	"sku", "product_id", "variant_id", "item_name", "quantity",
+	"unit_price", "item_discount", "item_tax", "item_total",
+}
+
+// OrderExport reports an export generated in the background and, once completed, the
+// signed link its file is downloaded from until it expires
+type OrderExport struct {
+	ID          string        `json:"id"`
+	Status      BulkJobStatus `json:"status"`
+	Format      string        `json:"format"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Orders      int           `json:"orders"`
+	Rows        int           `json:"rows"`
+	Error       string        `json:"error,omitempty"`
+	DownloadURL string        `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time    `json:"expires_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty"`
+
+	userID string
+	path   string
+}
+
+// OrderExportService exports a customer's orders and line items over a date range as CSV.
+// Histories up to the sync limit are returned straight away; larger ones are written to a
+// file by a job on the job runner and downloaded through an HMAC-signed link that expires
+// with the file. Exports live in memory; files left by a restart are removed when pruned.
+type OrderExportService struct {
+	orders    orders.Repository
+	runner    *jobs.Runner
+	dir       string
+	key       []byte
+	linkPath  string
+	syncLimit int
+	ttl       time.Duration
+	batchSize int
+
+	mu      sync.Mutex
+	exports map[string]*OrderExport
+}
+
+// NewOrderExportService creates a new OrderExportService writing export files to dir and
+// signing their links with key
+func NewOrderExportService(orderRepo orders.Repository, runner *jobs.Runner, dir string, key []byte) *OrderExportService {
+	return &OrderExportService{
+		orders:    orderRepo,
+		runner:    runner,
+		dir:       dir,
+		key:       key,
+		linkPath:  "/api/v1/order-exports",
+		syncLimit: 500,
+		ttl:       24 * time.Hour,
+		batchSize: 200,
+		exports:   make(map[string]*OrderExport),
+	}
+}
+
+// WithSyncLimit sets the most orders exported within the request; more start a job
+func (s *OrderExportService) WithSyncLimit(limit int) *OrderExportService {
+	if limit > 0 {
+		s.syncLimit = limit
+	}
+	return s
+}
+
+// WithTTL sets how long a finished export's file and download link are kept
+func (s *OrderExportService) WithTTL(ttl time.Duration) *OrderExportService {
+	if ttl > 0 {
+		s.ttl = ttl
+	}
+	return s
+}
+
+// WithBatchSize sets how many orders a job reads at a time
+func (s *OrderExportService) WithBatchSize(size int) *OrderExportService {
+	if size > 0 {
+		s.batchSize = size
+	}
+	return s
+}
+
+// Export exports the user's orders placed between from and to, both days inclusive. It
+// returns the CSV when there are at most the sync limit orders, and otherwise the export
+// started in the background.
+func (s *OrderExportService) Export(ctx context.Context, userID string, from, to time.Time, format string) ([]byte, *OrderExport, error) {
+	if format != "" && format != OrderExportFormatCSV {
+		return nil, nil, ErrUnsupportedExportFormat
+	}
+	if to.Before(from) {
+		return nil, nil, fmt.Errorf("%w: from is after to", ErrInvalidExportRange)
+	}
+	filter := exportFilter(from, to)
+
+	// Read one order past the limit to learn whether the history is too large to wait for
+	filter.Limit = s.syncLimit + 1
+	list, err := s.orders.FindByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(list) <= s.syncLimit {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(orderExportHeader); err != nil {
+			return nil, nil, err
+		}
+		if _, err := writeOrderRows(w, list); err != nil {
+			return nil, nil, err
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, nil, err
+		}
+		return buf.Bytes(), nil, nil
+	}
+
+	export, err := s.start(userID, from, to)
+	return nil, export, err
+}
+
+// GetExport returns a copy of one of the user's exports, with its download link once
+// it has completed
+func (s *OrderExportService) GetExport(ctx context.Context, userID, id string) (*OrderExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	export, ok := s.exports[id]
+	if !ok || export.userID != userID {
+		return nil, ErrOrderExportNotFound
+	}
+	return s.present(export), nil
+}
+
+// Open checks a download link's expiry and signature and returns the export's file and
+// the name it is downloaded as
+func (s *OrderExportService) Open(ctx context.Context, id, expires, signature string) (path, filename string, err error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix || !hmac.Equal([]byte(signature), []byte(s.sign(id, unix))) {
+		return "", "", ErrInvalidExportLink
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	export, ok := s.exports[id]
+	if !ok {
+		return "", "", ErrOrderExportNotFound
+	}
+	if export.Status != BulkJobCompleted {
+		return "", "", ErrOrderExportNotReady
+	}
+	return export.path, exportFilename(export.From, export.To), nil
+}
+
+// Prune drops expired exports with their files, and files no export refers to, such as
+// those left by a restart, once they are older than the TTL
+func (s *OrderExportService) Prune(ctx context.Context) (int, error) {
+	now := time.Now()
+	pruned := 0
+
+	s.mu.Lock()
+	kept := make(map[string]bool, len(s.exports))
+	for id, export := range s.exports {
+		if export.ExpiresAt != nil && now.After(*export.ExpiresAt) {
+			s.remove(export)
+			delete(s.exports, id)
+			pruned++
+			continue
+		}
+		kept[export.path] = true
+	}
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return pruned, nil
+		}
+		return pruned, err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(s.dir, entry.Name())
+		if entry.IsDir() || kept[path] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < s.ttl {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func (s *OrderExportService) start(userID string, from, to time.Time) (*OrderExport, error) {
+	s.mu.Lock()
+	for _, existing := range s.exports {
+		if existing.userID == userID && existing.FinishedAt == nil {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrOrderExportInProgress, existing.ID)
+		}
+	}
+	export := &OrderExport{
+		ID:        utils.GenerateID(),
+		Status:    BulkJobQueued,
+		Format:    OrderExportFormatCSV,
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		CreatedAt: time.Now(),
+		userID:    userID,
+	}
+	export.path = filepath.Join(s.dir, export.ID+".csv")
+	s.prune()
+	s.exports[export.ID] = export
+	s.mu.Unlock()
+
+	err := s.runner.Enqueue(jobs.Job{
+		Name: "order-export:" + export.ID,
+		Run: func(ctx context.Context) error {
+			err := s.run(ctx, export, exportFilter(from, to))
+			s.finish(export, err)
+			return err
+		},
+	})
+	if err != nil {
+		s.mu.Lock()
+		delete(s.exports, export.ID)
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.present(export), nil
+}
+
+// run writes the export's file in batches, renaming it into place once complete so a
+// download never reads a partial file
+func (s *OrderExportService) run(ctx context.Context, export *OrderExport, filter orders.OrderFilter) error {
+	s.mu.Lock()
+	export.Status = BulkJobRunning
+	s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(s.dir, export.ID+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := s.write(ctx, file, export, filter); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), export.path)
+}
+
+func (s *OrderExportService) write(ctx context.Context, out io.Writer, export *OrderExport, filter orders.OrderFilter) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(orderExportHeader); err != nil {
+		return err
+	}
+
+	filter.Limit = s.batchSize
+	for filter.Offset = 0; ; filter.Offset += s.batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		list, err := s.orders.FindByUserID(ctx, export.userID, filter)
+		if err != nil {
+			return err
+		}
+		rows, err := writeOrderRows(w, list)
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		export.Orders += len(list)
+		export.Rows += rows
+		s.mu.Unlock()
+
+		if len(list) < s.batchSize {
+			break
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// finish records how an export ended; a completed export's file and link expire after the TTL
+func (s *OrderExportService) finish(export *OrderExport, err error) {
+	now := time.Now()
+	expires := now.Add(s.ttl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	export.FinishedAt = &now
+	export.ExpiresAt = &expires
+	export.Status = BulkJobCompleted
+	if err != nil {
+		export.Status = BulkJobFailed
+		export.Error = err.Error()
+	}
+}
+
+// present copies an export, adding its signed download link once completed; callers hold
+// the lock
+func (s *OrderExportService) present(export *OrderExport) *OrderExport {
+	copied := *export
+	if export.Status == BulkJobCompleted && export.ExpiresAt != nil {
+		expires := export.ExpiresAt.Unix()
+		copied.DownloadURL = fmt.Sprintf("%s/%s/download?expires=%d&signature=%s", s.linkPath, export.ID, expires, s.sign(export.ID, expires))
+	}
+	return &copied
+}
+
+// sign returns the hex HMAC-SHA256 of an export's ID and link expiry
+func (s *OrderExportService) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// prune drops the oldest finished exports once too many are kept; callers hold the lock
+func (s *OrderExportService) prune() {
+	for len(s.exports) >= maxOrderExports {
+		var oldest *OrderExport
+		for _, export := range s.exports {
+			if export.FinishedAt != nil && (oldest == nil || export.CreatedAt.Before(oldest.CreatedAt)) {
+				oldest = export
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		s.remove(oldest)
+		delete(s.exports, oldest.ID)
+	}
+}
+
+// remove deletes an export's file, if it was written
+func (s *OrderExportService) remove(export *OrderExport) {
+	if err := os.Remove(export.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Failed to remove order export %s: %v", export.ID, err)
+	}
+}
+
+// exportFilter selects orders placed from the start of from to the end of to
+func exportFilter(from, to time.Time) orders.OrderFilter {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return orders.OrderFilter{DateFrom: &start, DateTo: &end}
+}
+
+// ExportFilename is the name an export of the given range is downloaded as
+func ExportFilename(from, to time.Time) string {
+	return exportFilename(from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+func exportFilename(from, to string) string {
+	return "orders-" + from + "-to-" + to + ".csv"
+}
+
+// writeOrderRows writes the rows of each order, returning how many were written
+func writeOrderRows(w *csv.Writer, list []*orders.Order) (int, error) {
+	rows := 0
+	for _, order := range list {
+		columns := []string{
+			exportCell(order.OrderNumber),
+			order.CreatedAt.Format(time.RFC3339),
+			string(order.Status),
+			order.Total.Currency,
+			exportAmount(order.Subtotal),
+			exportAmount(order.DiscountTotal),
+			exportAmount(order.TaxTotal),
+			exportAmount(order.ShippingTotal),
+			exportAmount(order.Total),
+		}
+		if len(order.Items) == 0 {
+			if err := w.Write(append(columns, make([]string, 9)...)); err != nil {
+				return rows, err
+			}
+			rows++
+			continue
+		}
+		for _, item := range order.Items {
+			variantID := ""
+			if item.VariantID != nil {
+				variantID = *item.VariantID
+			}
+			row := append(append([]string(nil), columns...),
+				exportCell(item.SKU),
+				item.ProductID,
+				variantID,
+				exportCell(item.Name),
+				strconv.Itoa(item.Quantity),
+				exportAmount(item.UnitPrice),
+				exportAmount(item.DiscountAmount),
+				exportAmount(item.TaxAmount),
+				exportAmount(item.Total),
+			)
+			if err := w.Write(row); err != nil {
+				return rows, err
+			}
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+// exportAmount formats minor units as a decimal amount, e.g. 1999 as 19.99
+func exportAmount(m money.Money) string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// exportCell stops spreadsheets from evaluating text that starts like a formula
+func exportCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
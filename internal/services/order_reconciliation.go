@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
+)
+
+// ReconciliationMode is what happens to an order whose totals don't add up when it is saved
+type ReconciliationMode string
+
+const (
+	// ReconcileReject refuses to save the order
+	ReconcileReject ReconciliationMode = "reject"
+	// ReconcileFlag saves the order, logs the mismatch and notes it on the order's timeline
+	ReconcileFlag ReconciliationMode = "flag"
+	// ReconcileOff saves orders without checking their totals
+	ReconcileOff ReconciliationMode = "off"
+)
+
+// ParseReconciliationMode parses reject, flag or off; an empty string is reject
+func ParseReconciliationMode(s string) (ReconciliationMode, error) {
+	switch mode := ReconciliationMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ReconcileReject, nil
+	case ReconcileReject, ReconcileFlag, ReconcileOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("order totals check %q must be reject, flag or off", s)
+	}
+}
+
+// OrderTotalsMismatch is one stored total that differs from the total recomputed from its
+// components. Field is subtotal, discount_total, total, currency, or item_total with ItemID.
+type OrderTotalsMismatch struct {
+	Field    string       `json:"field"`
+	ItemID   string       `json:"item_id,omitempty"`
+	Expected *money.Money `json:"expected,omitempty"`
+	Actual   *money.Money `json:"actual,omitempty"`
+	Message  string       `json:"message"`
+}
+
+// OrderTotalsError reports every total of an order that doesn't reconcile
+type OrderTotalsError struct {
+	OrderID    string
+	Mismatches []OrderTotalsMismatch
+}
+
+func (e *OrderTotalsError) Error() string {
+	if len(e.Mismatches) == 1 {
+		return "order totals do not reconcile: " + e.Mismatches[0].Message
+	}
+	return fmt.Sprintf("order totals do not reconcile: %d mismatches", len(e.Mismatches))
+}
+
+// ReconcileOrderTotals recomputes an order's totals from its components and returns every
+// stored total that differs: each item's total from its unit price, quantity, discount and
+// tax, the subtotal and discount total from the items, and the order total from subtotal,
+// discount, tax and shipping. Every amount must be in the order's currency, and none may
+// be negative. Tax itself comes from the tax provider, so it is taken as given.
+func ReconcileOrderTotals(order *orders.Order) []OrderTotalsMismatch {
+	var mismatches []OrderTotalsMismatch
+	currency := order.Total.Currency
+	mismatch := func(field, itemID string, expected, actual money.Money) {
+		mismatches = append(mismatches, OrderTotalsMismatch{
+			Field:    field,
+			ItemID:   itemID,
+			Expected: &expected,
+			Actual:   &actual,
+			Message:  fmt.Sprintf("%s is %s, components add up to %s", strings.TrimSpace(field+" "+itemID), actual.String(), expected.String()),
+		})
+	}
+	// invalid records an amount that can't be reconciled at all, returning false
+	invalid := func(field, itemID string, err error) bool {
+		mismatches = append(mismatches, OrderTotalsMismatch{
+			Field:   field,
+			ItemID:  itemID,
+			Message: fmt.Sprintf("%s: %v", strings.TrimSpace(field+" "+itemID), err),
+		})
+		return false
+	}
+	// valid checks an amount is in the order currency and not negative; zero amounts may
+	// leave their currency unset
+	valid := func(field, itemID string, m money.Money) bool {
+		if m.Amount == 0 && m.Currency == "" {
+			return true
+		}
+		if err := moneymath.AssertCurrency(m, currency); err != nil {
+			return invalid("currency", itemID, fmt.Errorf("%s %w", field, err))
+		}
+		if m.IsNegative() {
+			return invalid(field, itemID, money.ErrNegativeAmount)
+		}
+		return true
+	}
+	inCurrency := func(m money.Money) money.Money {
+		m.Currency = currency
+		return m
+	}
+
+	subtotal := money.Zero(currency)
+	discounts := money.Zero(currency)
+	for _, item := range order.Items {
+		if !valid("unit_price", item.ID, item.UnitPrice) || !valid("discount_amount", item.ID, item.DiscountAmount) ||
+			!valid("tax_amount", item.ID, item.TaxAmount) || !valid("item_total", item.ID, item.Total) {
+			continue
+		}
+		itemSubtotal, err := moneymath.MulInt(inCurrency(item.UnitPrice), int64(item.Quantity))
+		if err != nil {
+			invalid("item_total", item.ID, err)
+			continue
+		}
+		expected, err := moneymath.Sub(itemSubtotal, inCurrency(item.DiscountAmount))
+		if err == nil {
+			expected, err = moneymath.Add(expected, inCurrency(item.TaxAmount))
+		}
+		if err != nil {
+			invalid("item_total", item.ID, err)
+			continue
+		}
+		if expected.Amount != item.Total.Amount {
+			mismatch("item_total", item.ID, expected, inCurrency(item.Total))
+		}
+		if subtotal, err = moneymath.Add(subtotal, itemSubtotal); err != nil {
+			invalid("subtotal", "", err)
+		}
+		if discounts, err = moneymath.Add(discounts, inCurrency(item.DiscountAmount)); err != nil {
+			invalid("discount_total", "", err)
+		}
+	}
+
+	totals := []struct {
+		field  string
+		amount money.Money
+	}{
+		{"subtotal", order.Subtotal},
+		{"discount_total", order.DiscountTotal},
+		{"tax_total", order.TaxTotal},
+		{"shipping_total", order.ShippingTotal},
+		{"total", order.Total},
+	}
+	for _, total := range totals {
+		if !valid(total.field, "", total.amount) {
+			return mismatches
+		}
+	}
+	if subtotal.Amount != order.Subtotal.Amount {
+		mismatch("subtotal", "", subtotal, inCurrency(order.Subtotal))
+	}
+	if discounts.Amount != order.DiscountTotal.Amount {
+		mismatch("discount_total", "", discounts, inCurrency(order.DiscountTotal))
+	}
+
+	total, err := moneymath.Sub(inCurrency(order.Subtotal), inCurrency(order.DiscountTotal))
+	if err == nil {
+		total, err = moneymath.Sum(currency, total, inCurrency(order.TaxTotal), inCurrency(order.ShippingTotal))
+	}
+	if err != nil {
+		invalid("total", "", err)
+	} else if total.Amount != order.Total.Amount {
+		mismatch("total", "", total, order.Total)
+	}
+	return mismatches
+}
+
+// ReconcilingOrderRepository is an OrderRepository that reconciles an order's totals
+// before saving it, so corrupted totals are never stored unnoticed
+type ReconcilingOrderRepository struct {
+	OrderRepository
+	mode   ReconciliationMode
+	events *OrderEventService
+}
+
+// NewReconcilingOrderRepository wraps an order repository with a totals check in the given mode
+func NewReconcilingOrderRepository(repo OrderRepository, mode ReconciliationMode) *ReconcilingOrderRepository {
+	return &ReconcilingOrderRepository{OrderRepository: repo, mode: mode}
+}
+
+// WithEvents notes flagged orders on their timeline for staff to review
+func (r *ReconcilingOrderRepository) WithEvents(events *OrderEventService) *ReconcilingOrderRepository {
+	r.events = events
+	return r
+}
+
+// Save reconciles the order's totals and saves it. In reject mode a new order that doesn't
+// reconcile fails with an *OrderTotalsError. Orders already stored are flagged instead, so
+// totals stored before the check can't block their status changes. A flagged order is
+// saved and the mismatch logged, and when it is placed an internal note is added to its
+// timeline.
+func (r *ReconcilingOrderRepository) Save(ctx context.Context, order *orders.Order) error {
+	if r.mode == ReconcileOff {
+		return r.OrderRepository.Save(ctx, order)
+	}
+	mismatches := ReconcileOrderTotals(order)
+	if len(mismatches) == 0 {
+		return r.OrderRepository.Save(ctx, order)
+	}
+
+	totalsErr := &OrderTotalsError{OrderID: order.ID, Mismatches: mismatches}
+	if r.mode == ReconcileReject {
+		_, err := r.OrderRepository.FindByID(ctx, order.ID)
+		if errors.Is(err, orders.ErrOrderNotFound) {
+			log.Printf("Rejected order %s: %v", order.ID, totalsErr)
+			return totalsErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.OrderRepository.Save(ctx, order); err != nil {
+		return err
+	}
+	log.Printf("Flagged order %s: %v", order.ID, totalsErr)
+	if r.events != nil && order.Status == orders.OrderStatusPending {
+		messages := make([]string, len(mismatches))
+		for i, mismatch := range mismatches {
+			messages[i] = mismatch.Message
+		}
+		note := "Order totals do not reconcile: " + strings.Join(messages, "; ")
+		if len(note) > maxOrderNoteLength {
+			note = totalsErr.Error()
+		}
+		if _, err := r.events.AddNote(ctx, order.ID, note, true, "system"); err != nil {
+			log.Printf("Failed to note totals mismatch on order %s: %v", order.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+// orderStatusWorkflow lists the statuses staff may move an order to from each status.
+// Payment moves orders from pending to paid and refunds to refunded, so staff set neither
+// by hand, but staff can start processing a pending order paid outside checkout, such as
+// by bank transfer or cash on delivery. Canceling goes through OrderCancellationService to
+// refund and release the order.
+var orderStatusWorkflow = map[orders.OrderStatus][]orders.OrderStatus{
+	orders.OrderStatusPending:    {orders.OrderStatusProcessing, orders.OrderStatusCanceled},
+	orders.OrderStatusPaid:       {orders.OrderStatusProcessing, orders.OrderStatusCanceled},
+	orders.OrderStatusProcessing: {orders.OrderStatusShipped, orders.OrderStatusCanceled},
+	orders.OrderStatusShipped:    {orders.OrderStatusDelivered},
+}
+
+// OrderStatusTransitionError reports a status change the workflow doesn't allow, with the
+// statuses the order can move to instead
+type OrderStatusTransitionError struct {
+	From    orders.OrderStatus   `json:"from"`
+	To      orders.OrderStatus   `json:"to"`
+	Allowed []orders.OrderStatus `json:"allowed"`
+}
+
+func (e *OrderStatusTransitionError) Error() string {
+	return fmt.Sprintf("order can't move from %s to %s", e.From, e.To)
+}
+
+// OrderStatusChange is a status change made by staff. Cancellations need a reason.
+type OrderStatusChange struct {
+	Status    orders.OrderStatus
+	Reason    string
+	ChangedBy string
+}
+
+// OrderStatusEvent is one entry in an order's status history: a change staff made through
+// the workflow
+type OrderStatusEvent struct {
+	ID         string             `json:"id"`
+	OrderID    string             `json:"order_id"`
+	FromStatus orders.OrderStatus `json:"from_status"`
+	ToStatus   orders.OrderStatus `json:"to_status"`
+	Reason     string             `json:"reason,omitempty"`
+	ChangedBy  string             `json:"changed_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// OrderStatusEventRepository defines persistence for order status history
+type OrderStatusEventRepository interface {
+	Add(ctx context.Context, event *OrderStatusEvent) error
+	// ListByOrder lists an order's status changes, oldest first
+	ListByOrder(ctx context.Context, orderID string) ([]*OrderStatusEvent, error)
+}
+
+// OrderStatusService moves orders through the fulfillment workflow by hand, keeping each
+// change in the order's status history and on its timeline with who made it
+type OrderStatusService struct {
+	orderService  *OrderService
+	cancellations *OrderCancellationService
+	history       OrderStatusEventRepository
+}
+
+// NewOrderStatusService creates a new OrderStatusService
+func NewOrderStatusService(orderService *OrderService, cancellations *OrderCancellationService, history OrderStatusEventRepository) *OrderStatusService {
+	return &OrderStatusService{orderService: orderService, cancellations: cancellations, history: history}
+}
+
+// AllowedStatuses returns the statuses staff may move an order in status to
+func AllowedStatuses(status orders.OrderStatus) []orders.OrderStatus {
+	allowed := orderStatusWorkflow[status]
+	if allowed == nil {
+		return []orders.OrderStatus{}
+	}
+	return allowed
+}
+
+// ChangeStatus moves an order to a new status. It returns an *OrderStatusTransitionError
+// when the workflow doesn't allow the change. Canceling refunds what was paid and releases
+// the order's stock and bookings.
+func (s *OrderStatusService) ChangeStatus(ctx context.Context, orderID string, change OrderStatusChange) (*orders.Order, error) {
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !canChangeStatus(order.Status, change.Status) {
+		return nil, &OrderStatusTransitionError{From: order.Status, To: change.Status, Allowed: AllowedStatuses(order.Status)}
+	}
+
+	from := order.Status
+	ctx = WithStatusChangedBy(ctx, change.ChangedBy)
+	switch {
+	case change.Status == orders.OrderStatusCanceled:
+		cancellation, cancelErr := s.cancellations.Cancel(ctx, order.ID, CancelOrderRequest{
+			Reason:     change.Reason,
+			CanceledBy: change.ChangedBy,
+			Staff:      true,
+		})
+		if cancelErr != nil {
+			return nil, cancelErr
+		}
+		order = cancellation.Order
+	case from == orders.OrderStatusPending:
+		order, err = s.orderService.ProcessUnpaid(ctx, order.ID)
+	default:
+		order, err = s.orderService.UpdateStatus(ctx, order.ID, change.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event := &OrderStatusEvent{
+		ID:         utils.GenerateID(),
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Reason:     strings.TrimSpace(change.Reason),
+		ChangedBy:  change.ChangedBy,
+		CreatedAt:  time.Now(),
+	}
+	// The change stands either way; a failed write only loses the history entry
+	if err := s.history.Add(ctx, event); err != nil {
+		log.Printf("Order %s status change to %s not recorded in its history: %v", order.ID, order.Status, err)
+	}
+	return order, nil
+}
+
+// History returns the status changes staff made to an order, oldest first
+func (s *OrderStatusService) History(ctx context.Context, orderID string) ([]*OrderStatusEvent, error) {
+	if _, err := s.orderService.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.history.ListByOrder(ctx, orderID)
+}
+
+func canChangeStatus(from, to orders.OrderStatus) bool {
+	for _, allowed := range orderStatusWorkflow[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
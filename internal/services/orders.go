@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/devchuckcamp/gocommerce-api/internal/utils"
 	"github.com/devchuckcamp/gocommerce/inventory"
 	"github.com/devchuckcamp/gocommerce/orders"
@@ -8,14 +12,33 @@ import (
 	"github.com/devchuckcamp/gocommerce/pricing"
 )
 
+// OrderRepository is an orders.Repository that can also count a user's orders, so order
+// listings can report their true total
+type OrderRepository interface {
+	orders.Repository
+	// CountByUserID counts a user's orders matching the filter, ignoring its limit and offset
+	CountByUserID(ctx context.Context, userID string, filter orders.OrderFilter) (int64, error)
+}
+
 // OrderService holds the gocommerce order service
 type OrderService struct {
 	orders.Service
+	repo         OrderRepository
+	events       *OrderEventService
+	limits       *PurchaseLimitService
+	rules        *CheckoutRuleService
+	metadata     *CartMetadataService
+	pricing      *PricingAnomalyService
+	funnel       *FunnelService
+	confirm      *OrderConfirmationService
+	rentals      *RentalService
+	appointments *AppointmentService
+	wishlists    *WishlistService
 }
 
 // NewOrderService creates a new OrderService using gocommerce domain service
 func NewOrderService(
-	orderRepo orders.Repository,
+	orderRepo OrderRepository,
 	pricingService pricing.Service,
 	inventoryService inventory.Service, // can be nil if not using inventory
 	paymentGateway payments.Gateway, // can be nil for now
@@ -31,5 +54,275 @@ func NewOrderService(
 
 	return &OrderService{
 		Service: svc,
+		repo:    orderRepo,
+	}
+}
+
+// WithEvents records order placement and every status change on the order's timeline
+func (s *OrderService) WithEvents(events *OrderEventService) *OrderService {
+	s.events = events
+	return s
+}
+
+// WithPurchaseLimits rejects orders that take a product over its purchase limit
+func (s *OrderService) WithPurchaseLimits(limits *PurchaseLimitService) *OrderService {
+	s.limits = limits
+	return s
+}
+
+// WithCheckoutRules rejects orders whose cart fails a checkout rule, such as a minimum
+// order value or a country the shipping address can't receive a product in
+func (s *OrderService) WithCheckoutRules(rules *CheckoutRuleService) *OrderService {
+	s.rules = rules
+	return s
+}
+
+// WithCartMetadata carries the cart's client metadata through to the order
+func (s *OrderService) WithCartMetadata(metadata *CartMetadataService) *OrderService {
+	s.metadata = metadata
+	return s
+}
+
+// WithPricingAnomalies holds orders paying far less than their items are worth for review,
+// and keeps held orders from moving on to processing, shipped or delivered
+func (s *OrderService) WithPricingAnomalies(anomalies *PricingAnomalyService) *OrderService {
+	s.pricing = anomalies
+	return s
+}
+
+// WithFunnel counts storefront orders as paid in the checkout funnel once their payment
+// is received
+func (s *OrderService) WithFunnel(funnel *FunnelService) *OrderService {
+	s.funnel = funnel
+	return s
+}
+
+// WithConfirmations emails customers the confirmation of each order they place
+func (s *OrderService) WithConfirmations(confirm *OrderConfirmationService) *OrderService {
+	s.confirm = confirm
+	return s
+}
+
+// WithRentals books the rental periods of rental items for the orders placed, and frees
+// them when an order is canceled
+func (s *OrderService) WithRentals(rentals *RentalService) *OrderService {
+	s.rentals = rentals
+	return s
+}
+
+// WithAppointments books the slots of appointment items for the orders placed, and frees
+// the seats when an order is canceled
+func (s *OrderService) WithAppointments(appointments *AppointmentService) *OrderService {
+	s.appointments = appointments
+	return s
+}
+
+// WithWishlists marks the registry items of the orders placed bought, and gives them back
+// to the registry when an order is canceled
+func (s *OrderService) WithWishlists(wishlists *WishlistService) *OrderService {
+	s.wishlists = wishlists
+	return s
+}
+
+// CreateFromCart creates an order and records it as placed. Purchase limits are checked
+// again here, since they may have changed, or other orders been placed, since the items
+// went into the cart. Checkout rules are evaluated against the shipping country. Rental
+// periods and appointment slots are booked, and registry items marked bought, once the
+// order exists; an order losing its dates, seats or gifts to another checkout is canceled
+// and the conflict returned.
+func (s *OrderService) CreateFromCart(ctx context.Context, req orders.CreateOrderRequest) (*orders.Order, error) {
+	if s.limits != nil {
+		if err := s.limits.CheckCart(ctx, req.UserID, req.Cart); err != nil {
+			return nil, err
+		}
+	}
+	if s.rules != nil {
+		if err := s.rules.Check(ctx, req.Cart, req.ShippingAddress.Country); err != nil {
+			return nil, err
+		}
+	}
+	if s.rentals != nil {
+		if err := s.rentals.CheckCart(ctx, req.Cart); err != nil {
+			return nil, err
+		}
+	}
+	if s.appointments != nil {
+		if err := s.appointments.CheckCart(ctx, req.Cart); err != nil {
+			return nil, err
+		}
+	}
+	if s.wishlists != nil {
+		if err := s.wishlists.CheckCart(ctx, req.Cart); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err := s.Service.CreateFromCart(ctx, req)
+	if err == nil {
+		if bookErr := s.book(ctx, order); bookErr != nil {
+			return nil, bookErr
+		}
+	}
+	if err == nil && s.events != nil {
+		s.events.RecordStatusChange(ctx, order.ID, "", order.Status, "")
+	}
+	if err == nil && s.metadata != nil {
+		s.metadata.copyToOrder(ctx, req.Cart, order)
+	}
+	if err == nil && s.pricing != nil {
+		// The order stands either way; a failed check only loses the hold
+		if _, checkErr := s.pricing.CheckOrder(ctx, order); checkErr != nil {
+			log.Printf("Failed to check order %s for pricing anomalies: %v", order.ID, checkErr)
+		}
+	}
+	if err == nil && s.confirm != nil {
+		s.confirm.Send(ctx, order)
+	}
+	return order, err
+}
+
+// UpdateStatus moves an order to a new status and records the transition
+func (s *OrderService) UpdateStatus(ctx context.Context, orderID string, status orders.OrderStatus) (*orders.Order, error) {
+	if err := s.checkHold(ctx, orderID, status); err != nil {
+		return nil, err
+	}
+	if s.events == nil && s.funnel == nil {
+		return s.Service.UpdateStatus(ctx, orderID, status)
+	}
+
+	before, err := s.Service.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	from := before.Status
+	order, err := s.Service.UpdateStatus(ctx, orderID, status)
+	if err == nil && order.Status != from && s.events != nil {
+		s.events.RecordStatusChange(ctx, order.ID, from, order.Status, "")
+	}
+	if err == nil && order.Status != from && order.Status == orders.OrderStatusPaid && s.funnel != nil {
+		s.funnel.Advance(ctx, FunnelStageOrderCreated, FunnelStagePaid, order.ID)
+	}
+	return order, err
+}
+
+// ProcessUnpaid moves a pending order straight to processing, for orders staff accept
+// before payment is captured. gocommerce only lets payment move a pending order on, so the
+// order is saved directly.
+func (s *OrderService) ProcessUnpaid(ctx context.Context, orderID string) (*orders.Order, error) {
+	if err := s.checkHold(ctx, orderID, orders.OrderStatusProcessing); err != nil {
+		return nil, err
+	}
+	order, err := s.repo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != orders.OrderStatusPending {
+		return nil, orders.ErrInvalidStatus
+	}
+
+	order.Status = orders.OrderStatusProcessing
+	order.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, order); err != nil {
+		return nil, err
+	}
+	if s.events != nil {
+		s.events.RecordStatusChange(ctx, order.ID, orders.OrderStatusPending, order.Status, "")
+	}
+	return order, nil
+}
+
+// CancelOrder cancels an order, records the cancellation with its reason and frees the
+// rental periods and appointment seats it booked and the registry items it bought
+func (s *OrderService) CancelOrder(ctx context.Context, orderID string, reason string) (*orders.Order, error) {
+	if s.events == nil && s.rentals == nil && s.appointments == nil && s.wishlists == nil {
+		return s.Service.CancelOrder(ctx, orderID, reason)
+	}
+
+	before, err := s.Service.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	from := before.Status
+	order, err := s.Service.CancelOrder(ctx, orderID, reason)
+	if err == nil && s.events != nil {
+		s.events.RecordStatusChange(ctx, order.ID, from, order.Status, reason)
+	}
+	if err == nil && s.rentals != nil {
+		// The order stays canceled either way; a failed release leaves the dates blocked
+		if releaseErr := s.rentals.ReleaseOrder(ctx, order.ID); releaseErr != nil {
+			log.Printf("Failed to release rental bookings of order %s: %v", order.ID, releaseErr)
+		}
+	}
+	if err == nil && s.appointments != nil {
+		if releaseErr := s.appointments.ReleaseOrder(ctx, order.ID); releaseErr != nil {
+			log.Printf("Failed to release appointment bookings of order %s: %v", order.ID, releaseErr)
+		}
+	}
+	if err == nil && s.wishlists != nil {
+		if releaseErr := s.wishlists.ReleaseOrder(ctx, order.ID); releaseErr != nil {
+			log.Printf("Failed to release registry purchases of order %s: %v", order.ID, releaseErr)
+		}
+	}
+	return order, err
+}
+
+// CountUserOrders counts a user's orders matching the filter, ignoring its limit and
+// offset, for the total of a paginated order listing
+func (s *OrderService) CountUserOrders(ctx context.Context, userID string, filter orders.OrderFilter) (int64, error) {
+	return s.repo.CountByUserID(ctx, userID, filter)
+}
+
+// book books the rental periods and appointment slots of a new order and marks its
+// registry items bought. When any of them can't be, the order is canceled, freeing
+// whatever was booked for it.
+func (s *OrderService) book(ctx context.Context, order *orders.Order) error {
+	var err error
+	if s.rentals != nil {
+		err = s.rentals.BookOrder(ctx, order)
+	}
+	if err == nil && s.appointments != nil {
+		err = s.appointments.BookOrder(ctx, order)
+	}
+	if err == nil && s.wishlists != nil {
+		err = s.wishlists.PurchaseOrder(ctx, order)
+	}
+	if err == nil {
+		return nil
+	}
+
+	if _, cancelErr := s.Service.CancelOrder(ctx, order.ID, "booking no longer available"); cancelErr != nil {
+		log.Printf("Failed to cancel order %s after its booking failed: %v", order.ID, cancelErr)
+	}
+	if s.rentals != nil {
+		if releaseErr := s.rentals.ReleaseOrder(ctx, order.ID); releaseErr != nil {
+			log.Printf("Failed to release rental bookings of order %s: %v", order.ID, releaseErr)
+		}
+	}
+	if s.appointments != nil {
+		if releaseErr := s.appointments.ReleaseOrder(ctx, order.ID); releaseErr != nil {
+			log.Printf("Failed to release appointment bookings of order %s: %v", order.ID, releaseErr)
+		}
+	}
+	return err
+}
+
+// checkHold returns ErrOrderOnHold when an order held for pricing review would move on
+// towards fulfillment. It may still be paid or canceled.
+func (s *OrderService) checkHold(ctx context.Context, orderID string, status orders.OrderStatus) error {
+	if s.pricing == nil {
+		return nil
+	}
+	switch status {
+	case orders.OrderStatusProcessing, orders.OrderStatusShipped, orders.OrderStatusDelivered:
+	default:
+		return nil
+	}
+	held, err := s.pricing.OrderOnHold(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if held {
+		return ErrOrderOnHold
 	}
+	return nil
 }
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrPageNotFound  = errors.New("page not found")
+	ErrPageSlugTaken = errors.New("a page with this slug already exists")
+	ErrInvalidPage   = errors.New("page requires a lowercase URL slug, a title and a markdown or html body")
+)
+
+// pageSlugPattern allows lowercase words separated by single hyphens, e.g. "shipping-policy"
+var pageSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// PageFormat is the markup a page body is written in; clients render it
+type PageFormat string
+
+const (
+	PageFormatMarkdown PageFormat = "markdown"
+	PageFormatHTML     PageFormat = "html"
+)
+
+// PageStatus controls whether a page is served publicly
+type PageStatus string
+
+const (
+	PageStatusDraft     PageStatus = "draft"
+	PageStatusPublished PageStatus = "published"
+)
+
+// Page is a content page such as About, FAQ or a store policy
+type Page struct {
+	ID              string     `json:"id"`
+	Slug            string     `json:"slug"`
+	Title           string     `json:"title"`
+	Body            string     `json:"body"`
+	Format          PageFormat `json:"format"`
+	MetaDescription string     `json:"meta_description,omitempty"`
+	Status          PageStatus `json:"status"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// PageSortFields are the fields the admin page list can be sorted by
+var PageSortFields = []string{"slug", "title", "status", "published_at", "created_at", "updated_at"}
+
+// PageFilter filters the admin page list
+type PageFilter struct {
+	Status PageStatus
+	Sort   Sort
+	Limit  int
+	Offset int
+}
+
+// PageRepository defines persistence for content pages
+type PageRepository interface {
+	FindByID(ctx context.Context, id string) (*Page, error)
+	FindBySlug(ctx context.Context, slug string) (*Page, error)
+	List(ctx context.Context, filter PageFilter) ([]*Page, error)
+	Count(ctx context.Context, filter PageFilter) (int64, error)
+	Save(ctx context.Context, page *Page) error
+	Delete(ctx context.Context, id string) error
+}
+
+// PageService manages content pages served by the storefront
+type PageService struct {
+	repo PageRepository
+}
+
+// NewPageService creates a new PageService
+func NewPageService(repo PageRepository) *PageService {
+	return &PageService{repo: repo}
+}
+
+// GetPublishedPage returns a published page by slug; drafts are reported as not found
+func (s *PageService) GetPublishedPage(ctx context.Context, slug string) (*Page, error) {
+	page, err := s.repo.FindBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+	if page.Status != PageStatusPublished {
+		return nil, ErrPageNotFound
+	}
+	return page, nil
+}
+
+// ListPages returns pages matching the filter, ordered by slug
+func (s *PageService) ListPages(ctx context.Context, filter PageFilter) ([]*Page, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountPages counts pages matching the filter
+func (s *PageService) CountPages(ctx context.Context, filter PageFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetPage returns a page by ID, including drafts
+func (s *PageService) GetPage(ctx context.Context, id string) (*Page, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SavePage validates and saves a page. Slugs must be unique, and a page keeps
+// the time it was first published.
+func (s *PageService) SavePage(ctx context.Context, page *Page) error {
+	page.Slug = strings.ToLower(strings.TrimSpace(page.Slug))
+	page.Title = strings.TrimSpace(page.Title)
+	if page.Format == "" {
+		page.Format = PageFormatMarkdown
+	}
+	if page.Status == "" {
+		page.Status = PageStatusDraft
+	}
+
+	if !pageSlugPattern.MatchString(page.Slug) || page.Title == "" ||
+		(page.Format != PageFormatMarkdown && page.Format != PageFormatHTML) ||
+		(page.Status != PageStatusDraft && page.Status != PageStatusPublished) {
+		return ErrInvalidPage
+	}
+
+	existing, err := s.repo.FindBySlug(ctx, page.Slug)
+	if err == nil && existing.ID != page.ID {
+		return ErrPageSlugTaken
+	}
+	if err != nil && err != ErrPageNotFound {
+		return err
+	}
+
+	now := time.Now()
+	if page.ID == "" {
+		page.ID = utils.GenerateID()
+		page.CreatedAt = now
+	}
+	if page.Status == PageStatusPublished && page.PublishedAt == nil {
+		page.PublishedAt = &now
+	}
+	page.UpdatedAt = now
+
+	return s.repo.Save(ctx, page)
+}
+
+// DeletePage deletes a page
+func (s *PageService) DeletePage(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+var (
+	ErrPaymentRetryNotFound    = errors.New("no failed payment recorded for order")
+	ErrPaymentNotPending       = errors.New("order is not awaiting payment")
+	ErrPaymentRetriesExhausted = errors.New("payment retries exhausted; order has been canceled")
+)
+
+// PaymentRetryState tracks where an order is in the dunning process
+type PaymentRetryState string
+
+const (
+	PaymentRetryStatePending   PaymentRetryState = "payment_pending"
+	PaymentRetryStateRecovered PaymentRetryState = "recovered"
+	PaymentRetryStateCanceled  PaymentRetryState = "canceled"
+)
+
+// PaymentRetryPolicy bounds how long and how often a failed payment can be retried
+type PaymentRetryPolicy struct {
+	MaxAttempts int           // failed charges allowed before the order is canceled
+	Window      time.Duration // time after the first failure before the order is canceled
+}
+
+// PaymentRetry records failed charges for an order awaiting payment
+type PaymentRetry struct {
+	OrderID           string            `json:"order_id"`
+	State             PaymentRetryState `json:"state"`
+	Failures          int               `json:"failures"`
+	LastFailureReason string            `json:"last_failure_reason,omitempty"`
+	FirstFailedAt     time.Time         `json:"first_failed_at"`
+	LastFailedAt      time.Time         `json:"last_failed_at"`
+	ExpiresAt         time.Time         `json:"expires_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// PaymentRetryRepository defines persistence for payment retry state
+type PaymentRetryRepository interface {
+	FindByOrderID(ctx context.Context, orderID string) (*PaymentRetry, error)
+	// FindExpired returns pending retries whose window ended before the given time
+	FindExpired(ctx context.Context, before time.Time) ([]*PaymentRetry, error)
+	Save(ctx context.Context, retry *PaymentRetry) error
+}
+
+// PaymentRetryService keeps orders with failed charges in payment_pending,
+// lets customers retry with a new payment method and cancels orders once
+// the retry policy is exhausted
+type PaymentRetryService struct {
+	repo            PaymentRetryRepository
+	paymentService  *PaymentService
+	orderService    orders.Service
+	deliveryService *DeliveryService
+	policy          PaymentRetryPolicy
+}
+
+// NewPaymentRetryService creates a new PaymentRetryService
+func NewPaymentRetryService(
+	repo PaymentRetryRepository,
+	paymentService *PaymentService,
+	orderService orders.Service,
+	policy PaymentRetryPolicy,
+) *PaymentRetryService {
+	return &PaymentRetryService{
+		repo:           repo,
+		paymentService: paymentService,
+		orderService:   orderService,
+		policy:         policy,
+	}
+}
+
+// WithDeliveryService releases booked delivery slots when an order is canceled for non-payment
+func (s *PaymentRetryService) WithDeliveryService(deliveryService *DeliveryService) *PaymentRetryService {
+	s.deliveryService = deliveryService
+	return s
+}
+
+// AttemptsRemaining returns how many more failed charges are allowed before cancellation
+func (s *PaymentRetryService) AttemptsRemaining(retry *PaymentRetry) int {
+	if retry.Failures >= s.policy.MaxAttempts {
+		return 0
+	}
+	return s.policy.MaxAttempts - retry.Failures
+}
+
+// GetRetry returns the retry state for an order
+func (s *PaymentRetryService) GetRetry(ctx context.Context, orderID string) (*PaymentRetry, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// RecordFailure records a failed charge for an order. The order stays in payment_pending
+// until the policy is exhausted, at which point it is canceled and ErrPaymentRetriesExhausted is returned.
+func (s *PaymentRetryService) RecordFailure(ctx context.Context, orderID, reason string) (*PaymentRetry, error) {
+	now := time.Now()
+
+	retry, err := s.repo.FindByOrderID(ctx, orderID)
+	if err == ErrPaymentRetryNotFound {
+		retry = &PaymentRetry{
+			OrderID:       orderID,
+			State:         PaymentRetryStatePending,
+			FirstFailedAt: now,
+			ExpiresAt:     now.Add(s.policy.Window),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if retry.State != PaymentRetryStatePending {
+		return retry, ErrPaymentNotPending
+	}
+
+	retry.Failures++
+	retry.LastFailureReason = reason
+	retry.LastFailedAt = now
+	retry.UpdatedAt = now
+
+	if retry.Failures >= s.policy.MaxAttempts || now.After(retry.ExpiresAt) {
+		if err := s.cancel(ctx, retry); err != nil {
+			return retry, err
+		}
+		return retry, ErrPaymentRetriesExhausted
+	}
+
+	if err := s.repo.Save(ctx, retry); err != nil {
+		return nil, err
+	}
+	return retry, nil
+}
+
+// RetryPayment charges the order's outstanding balance with a new payment method.
+// A declined charge counts against the retry policy; a captured one marks the order paid.
+func (s *PaymentRetryService) RetryPayment(ctx context.Context, order *orders.Order, req TenderRequest) (*PaymentTender, *PaymentRetry, error) {
+	retry, err := s.repo.FindByOrderID(ctx, order.ID)
+	if err == ErrPaymentRetryNotFound {
+		return nil, nil, ErrPaymentNotPending
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if retry.State != PaymentRetryStatePending || order.Status != orders.OrderStatusPending {
+		return nil, retry, ErrPaymentNotPending
+	}
+
+	if time.Now().After(retry.ExpiresAt) {
+		if err := s.cancel(ctx, retry); err != nil {
+			return nil, retry, err
+		}
+		return nil, retry, ErrPaymentRetriesExhausted
+	}
+
+	tender, err := s.paymentService.ChargeBalance(ctx, order, req)
+	if err == ErrTenderDeclined {
+		retry, err = s.RecordFailure(ctx, order.ID, tender.FailureReason)
+		if err != nil {
+			return tender, retry, err
+		}
+		return tender, retry, ErrTenderDeclined
+	}
+	if err != nil {
+		return nil, retry, err
+	}
+
+	if tender.Status == TenderStatusCaptured {
+		retry.State = PaymentRetryStateRecovered
+		retry.UpdatedAt = time.Now()
+		if err := s.repo.Save(ctx, retry); err != nil {
+			return tender, retry, err
+		}
+		if _, err := s.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusPaid); err != nil {
+			return tender, retry, err
+		}
+	}
+	return tender, retry, nil
+}
+
+// MarkRecovered closes an order's pending retry once it has been paid by other means, such as
+// a capture confirmed by webhook, so the sweep does not cancel a paid order
+func (s *PaymentRetryService) MarkRecovered(ctx context.Context, orderID string) error {
+	retry, err := s.repo.FindByOrderID(ctx, orderID)
+	if err == ErrPaymentRetryNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if retry.State != PaymentRetryStatePending {
+		return nil
+	}
+
+	retry.State = PaymentRetryStateRecovered
+	retry.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, retry)
+}
+
+// CancelExpired cancels orders whose retry window has ended and returns how many were canceled
+func (s *PaymentRetryService) CancelExpired(ctx context.Context) (int, error) {
+	retries, err := s.repo.FindExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	canceled := 0
+	for _, retry := range retries {
+		if err := s.cancel(ctx, retry); err != nil {
+			return canceled, err
+		}
+		canceled++
+	}
+	return canceled, nil
+}
+
+// cancel cancels the order for non-payment and closes the retry
+func (s *PaymentRetryService) cancel(ctx context.Context, retry *PaymentRetry) error {
+	if s.deliveryService != nil {
+		if err := s.deliveryService.ReleaseSlot(ctx, retry.OrderID); err != nil {
+			return err
+		}
+	}
+
+	order, err := s.orderService.GetOrder(ctx, retry.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.IsCancelable() {
+		if _, err := s.orderService.CancelOrder(ctx, retry.OrderID, "payment not received"); err != nil {
+			return err
+		}
+	}
+
+	retry.State = PaymentRetryStateCanceled
+	retry.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, retry)
+}
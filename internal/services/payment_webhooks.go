@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// PaymentWebhookSignatureHeader carries the hex HMAC-SHA256 of the raw request body
+const PaymentWebhookSignatureHeader = "X-Webhook-Signature"
+
+// DisputeWebhookPayload is a dispute event sent by the payment gateway
+type DisputeWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // dispute.created, dispute.updated, dispute.closed
+	Data struct {
+		DisputeID       string     `json:"dispute_id"`
+		PaymentIntentID string     `json:"payment_intent_id"`
+		OrderID         string     `json:"order_id"`
+		Amount          int64      `json:"amount"`
+		Currency        string     `json:"currency"`
+		Reason          string     `json:"reason"`
+		Status          string     `json:"status"`
+		EvidenceDueBy   *time.Time `json:"evidence_due_by"`
+	} `json:"data"`
+}
+
+// DisputeEvent converts the payload to the event applied by DisputeService
+func (p *DisputeWebhookPayload) DisputeEvent() DisputeEvent {
+	event := DisputeEvent{
+		GatewayDisputeID: p.Data.DisputeID,
+		GatewayReference: p.Data.PaymentIntentID,
+		OrderID:          p.Data.OrderID,
+		Reason:           p.Data.Reason,
+		Status:           DisputeStatus(p.Data.Status),
+		EvidenceDueBy:    p.Data.EvidenceDueBy,
+	}
+	if p.Data.Amount > 0 {
+		event.Amount = money.Money{Amount: p.Data.Amount, Currency: strings.ToUpper(p.Data.Currency)}
+	}
+	return event
+}
+
+// Payment intent events applied by PaymentWebhookProvider
+const (
+	PaymentEventIntentSucceeded = "payment_intent.succeeded"
+	PaymentEventIntentFailed    = "payment_intent.payment_failed"
+)
+
+// PaymentIntentWebhookPayload is a payment intent event sent by the payment gateway
+type PaymentIntentWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // payment_intent.succeeded, payment_intent.payment_failed
+	Data struct {
+		PaymentIntentID string `json:"payment_intent_id"`
+		OrderID         string `json:"order_id"`
+		TenderID        string `json:"tender_id"` // echoed from the intent metadata
+		Amount          int64  `json:"amount"`
+		Currency        string `json:"currency"`
+		FailureReason   string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+// PaymentWebhookProvider receives payment gateway events through the generic webhook
+// receiver. Dispute events are applied to the dispute queue, and with WithPayments,
+// confirmed captures mark orders paid. Other types are stored only.
+type PaymentWebhookProvider struct {
+	HMACVerifier
+	disputeService *DisputeService
+	paymentService *PaymentService
+	orderService   orders.Service
+	retryService   *PaymentRetryService
+}
+
+// NewPaymentWebhookProvider creates a provider for events signed with the gateway's shared secret
+func NewPaymentWebhookProvider(secret string, disputeService *DisputeService) *PaymentWebhookProvider {
+	return &PaymentWebhookProvider{
+		HMACVerifier:   HMACVerifier{Header: PaymentWebhookSignatureHeader, Secret: []byte(secret)},
+		disputeService: disputeService,
+	}
+}
+
+// WithPayments applies payment intent events: a capture is recorded against the order's
+// tenders, and the order is marked paid once they cover its total
+func (p *PaymentWebhookProvider) WithPayments(paymentService *PaymentService, orderService orders.Service) *PaymentWebhookProvider {
+	p.paymentService = paymentService
+	p.orderService = orderService
+	return p
+}
+
+// WithPaymentRetryService closes the payment retry of an order paid by webhook, and
+// records failed intents as failed charges
+func (p *PaymentWebhookProvider) WithPaymentRetryService(retryService *PaymentRetryService) *PaymentWebhookProvider {
+	p.retryService = retryService
+	return p
+}
+
+// Identify returns the gateway event ID and type
+func (p *PaymentWebhookProvider) Identify(body []byte) (string, string, error) {
+	var payload DisputeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", "", err
+	}
+	return payload.ID, payload.Type, nil
+}
+
+// Process applies dispute and payment intent events
+func (p *PaymentWebhookProvider) Process(ctx context.Context, event *WebhookEvent) error {
+	switch {
+	case event.EventType == PaymentEventIntentSucceeded || event.EventType == PaymentEventIntentFailed:
+		if p.paymentService == nil {
+			return nil
+		}
+		var payload PaymentIntentWebhookPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		if event.EventType == PaymentEventIntentFailed {
+			return p.applyFailure(ctx, &payload)
+		}
+		return p.applyCapture(ctx, &payload)
+	case !strings.HasPrefix(event.EventType, "dispute."):
+		return nil
+	}
+
+	var payload DisputeWebhookPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	_, err := p.disputeService.HandleEvent(ctx, payload.DisputeEvent())
+	if err == ErrDisputeOrderUnknown {
+		// Nothing to attach the dispute to; retrying will not change that
+		return nil
+	}
+	return err
+}
+
+// applyCapture records a confirmed capture and marks the order paid once it is fully captured
+func (p *PaymentWebhookProvider) applyCapture(ctx context.Context, payload *PaymentIntentWebhookPayload) error {
+	order, err := p.orderService.GetOrder(ctx, payload.Data.OrderID)
+	if err == orders.ErrOrderNotFound {
+		// Nothing to apply the capture to; retrying will not change that
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.paymentService.RecordCapture(ctx, order, PaymentCapture{
+		OrderID:          order.ID,
+		TenderID:         payload.Data.TenderID,
+		GatewayReference: payload.Data.PaymentIntentID,
+		Amount:           money.Money{Amount: payload.Data.Amount, Currency: strings.ToUpper(payload.Data.Currency)},
+	}); err != nil {
+		if err == ErrTenderNotFound || err == ErrInvalidTender {
+			return nil
+		}
+		return err
+	}
+
+	summary, err := p.paymentService.Summary(ctx, order)
+	if err != nil {
+		return err
+	}
+	if !summary.IsFullyCaptured() || order.Status != orders.OrderStatusPending {
+		return nil
+	}
+
+	if _, err := p.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusPaid); err != nil {
+		return err
+	}
+	if p.retryService != nil {
+		return p.retryService.MarkRecovered(ctx, order.ID)
+	}
+	return nil
+}
+
+// applyFailure counts a failed intent against the order's payment retries
+func (p *PaymentWebhookProvider) applyFailure(ctx context.Context, payload *PaymentIntentWebhookPayload) error {
+	if p.retryService == nil || payload.Data.OrderID == "" {
+		return nil
+	}
+
+	// A late failure for an intent the customer has since replaced must not reopen a paid order
+	order, err := p.orderService.GetOrder(ctx, payload.Data.OrderID)
+	if err == orders.ErrOrderNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if order.Status != orders.OrderStatusPending {
+		return nil
+	}
+
+	reason := payload.Data.FailureReason
+	if reason == "" {
+		reason = "payment failed at gateway"
+	}
+	_, err = p.retryService.RecordFailure(ctx, order.ID, reason)
+	if err == ErrPaymentNotPending || err == ErrPaymentRetriesExhausted {
+		return nil
+	}
+	return err
+}
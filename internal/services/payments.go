@@ -0,0 +1,513 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrTenderNotFound       = errors.New("payment tender not found")
+	ErrInvalidTender        = errors.New("each tender requires a type, a payment method and a positive amount in the order currency")
+	ErrTooManyTenders       = errors.New("too many payment tenders for one order")
+	ErrTenderTotalMismatch  = errors.New("payment tenders must add up to the order total")
+	ErrTenderDeclined       = errors.New("payment tender was declined")
+	ErrRefundExceedsCapture = errors.New("refund amount exceeds the captured amount remaining on the tender")
+	ErrNoBalanceDue         = errors.New("order has no balance left to pay")
+)
+
+// MaxTendersPerOrder limits how many tenders a single order can be split across
+const MaxTendersPerOrder = 5
+
+// TenderType identifies how a tender is paid
+type TenderType string
+
+const (
+	TenderTypeCard        TenderType = "card"
+	TenderTypeGiftCard    TenderType = "gift_card"
+	TenderTypeStoreCredit TenderType = "store_credit" // PaymentMethodID is the customer whose wallet is debited
+	TenderTypeCash        TenderType = "cash"         // taken at a POS register; recorded, never charged
+)
+
+// IsValid reports whether the tender type is supported
+func (t TenderType) IsValid() bool {
+	return t == TenderTypeCard || t == TenderTypeGiftCard || t == TenderTypeStoreCredit || t == TenderTypeCash
+}
+
+// TenderStatus represents the state of a payment tender
+type TenderStatus string
+
+const (
+	TenderStatusPending           TenderStatus = "pending"
+	TenderStatusCaptured          TenderStatus = "captured"
+	TenderStatusFailed            TenderStatus = "failed"
+	TenderStatusCanceled          TenderStatus = "canceled" // voided, including captures refunded after another tender failed
+	TenderStatusPartiallyRefunded TenderStatus = "partially_refunded"
+	TenderStatusRefunded          TenderStatus = "refunded"
+)
+
+// PaymentTender is one of the payments an order total is split across
+type PaymentTender struct {
+	ID               string       `json:"id"`
+	OrderID          string       `json:"order_id"`
+	Type             TenderType   `json:"type"`
+	PaymentMethodID  string       `json:"payment_method_id"`
+	Amount           money.Money  `json:"amount"`
+	CapturedAmount   money.Money  `json:"captured_amount"`
+	RefundedAmount   money.Money  `json:"refunded_amount"`
+	Status           TenderStatus `json:"status"`
+	GatewayReference string       `json:"gateway_reference,omitempty"`
+	FailureReason    string       `json:"failure_reason,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// Refundable returns the captured amount that has not been refunded yet
+func (t *PaymentTender) Refundable() money.Money {
+	remaining, err := t.CapturedAmount.Subtract(t.RefundedAmount)
+	if err != nil || remaining.IsNegative() {
+		return money.Zero(t.Amount.Currency)
+	}
+	return remaining
+}
+
+// TenderRequest describes a tender the customer wants to pay with
+type TenderRequest struct {
+	Type            TenderType
+	PaymentMethodID string
+	Amount          money.Money
+}
+
+// PaymentSummary reconciles an order total against its tenders
+type PaymentSummary struct {
+	OrderTotal money.Money      `json:"order_total"`
+	Captured   money.Money      `json:"captured"`
+	Refunded   money.Money      `json:"refunded"`
+	Balance    money.Money      `json:"balance"` // order total still to be captured
+	Tenders    []*PaymentTender `json:"tenders"`
+}
+
+// IsFullyCaptured reports whether the tenders cover the order total
+func (s *PaymentSummary) IsFullyCaptured() bool {
+	return !s.Balance.IsPositive()
+}
+
+// PaymentCapture is a capture the gateway confirmed after checkout, reported by webhook
+type PaymentCapture struct {
+	OrderID          string
+	TenderID         string // the tender_id metadata set when the intent was created, if any
+	GatewayReference string // the payment intent
+	Amount           money.Money
+}
+
+// PaymentRepository defines persistence for order payment tenders
+type PaymentRepository interface {
+	FindByID(ctx context.Context, id string) (*PaymentTender, error)
+	FindByOrderID(ctx context.Context, orderID string) ([]*PaymentTender, error)
+	FindByGatewayReference(ctx context.Context, reference string) (*PaymentTender, error)
+	Save(ctx context.Context, tender *PaymentTender) error
+}
+
+// PaymentService charges, refunds and reconciles split payments
+type PaymentService struct {
+	repo        PaymentRepository
+	gateway     payments.Gateway
+	storeCredit *StoreCreditService
+}
+
+// NewPaymentService creates a new PaymentService.
+// Without a gateway, tenders are recorded as pending for later capture.
+func NewPaymentService(repo PaymentRepository, gateway payments.Gateway) *PaymentService {
+	return &PaymentService{
+		repo:    repo,
+		gateway: gateway,
+	}
+}
+
+// WithStoreCreditService redeems store credit tenders from the customer's wallet
+func (s *PaymentService) WithStoreCreditService(storeCredit *StoreCreditService) *PaymentService {
+	s.storeCredit = storeCredit
+	return s
+}
+
+// HasGateway reports whether card tenders are charged at a payment gateway
+func (s *PaymentService) HasGateway() bool {
+	return s.gateway != nil
+}
+
+// SplitTenders validates the requested tenders against the order total
+func (s *PaymentService) SplitTenders(order *orders.Order, requests []TenderRequest) ([]*PaymentTender, error) {
+	if len(requests) == 0 {
+		return nil, ErrInvalidTender
+	}
+	if len(requests) > MaxTendersPerOrder {
+		return nil, ErrTooManyTenders
+	}
+
+	total := money.Zero(order.Total.Currency)
+	tenders := make([]*PaymentTender, len(requests))
+	for i, req := range requests {
+		tender, err := newTender(order, req)
+		if err != nil {
+			return nil, err
+		}
+		if total, err = total.Add(req.Amount); err != nil {
+			return nil, err
+		}
+		tenders[i] = tender
+	}
+
+	if total != order.Total {
+		return nil, ErrTenderTotalMismatch
+	}
+	return tenders, nil
+}
+
+// ChargeTenders splits the order total across the requested tenders and charges each one.
+// If any tender is declined, tenders already captured are refunded and ErrTenderDeclined is returned.
+func (s *PaymentService) ChargeTenders(ctx context.Context, order *orders.Order, requests []TenderRequest) ([]*PaymentTender, error) {
+	tenders, err := s.SplitTenders(order, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tender := range tenders {
+		if err := s.repo.Save(ctx, tender); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, tender := range tenders {
+		if err := s.charge(ctx, order, tender); err != nil {
+			s.rollback(ctx, tenders[:i])
+			return tenders, err
+		}
+	}
+	return tenders, nil
+}
+
+// RecordCapturedTenders records tenders already captured outside the gateway, such as card
+// and cash payments taken at a POS register. The tenders must add up to the order total;
+// each is saved as captured in full. PaymentMethodID carries the terminal's reference, and
+// no gateway reference is set, so refunds of these tenders are recorded but not charged back.
+func (s *PaymentService) RecordCapturedTenders(ctx context.Context, order *orders.Order, requests []TenderRequest) ([]*PaymentTender, error) {
+	tenders, err := s.SplitTenders(order, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tender := range tenders {
+		tender.Status = TenderStatusCaptured
+		tender.CapturedAmount = tender.Amount
+		if err := s.repo.Save(ctx, tender); err != nil {
+			return nil, err
+		}
+	}
+	return tenders, nil
+}
+
+// ChargeBalance charges the order's outstanding balance to a single tender.
+// The request amount is ignored; the tender is always for the balance due.
+func (s *PaymentService) ChargeBalance(ctx context.Context, order *orders.Order, req TenderRequest) (*PaymentTender, error) {
+	summary, err := s.Summary(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if summary.IsFullyCaptured() {
+		return nil, ErrNoBalanceDue
+	}
+
+	req.Amount = summary.Balance
+	tender, err := newTender(order, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, tender); err != nil {
+		return nil, err
+	}
+	return tender, s.charge(ctx, order, tender)
+}
+
+// RefundTender refunds part or all of the captured amount on a tender
+func (s *PaymentService) RefundTender(ctx context.Context, tenderID string, amount money.Money, reason payments.RefundReason) (*PaymentTender, error) {
+	tender, err := s.repo.FindByID(ctx, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !amount.IsPositive() || amount.Currency != tender.Amount.Currency {
+		return nil, ErrInvalidTender
+	}
+
+	remaining, err := tender.Refundable().Subtract(amount)
+	if err != nil {
+		return nil, err
+	}
+	if remaining.IsNegative() {
+		return nil, ErrRefundExceedsCapture
+	}
+
+	if tender.Type == TenderTypeStoreCredit {
+		if s.storeCredit == nil {
+			return nil, ErrInvalidTender
+		}
+		if _, err := s.storeCredit.Restore(ctx, tender.PaymentMethodID, amount, tender.OrderID); err != nil {
+			return nil, err
+		}
+	} else if s.gateway != nil && tender.GatewayReference != "" {
+		if _, err := s.gateway.CreateRefund(ctx, payments.RefundRequest{
+			PaymentIntentID: tender.GatewayReference,
+			Amount:          amount,
+			Reason:          reason,
+			Metadata:        map[string]string{"tender_id": tender.ID, "order_id": tender.OrderID},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if tender.RefundedAmount, err = tender.RefundedAmount.Add(amount); err != nil {
+		return nil, err
+	}
+	tender.Status = TenderStatusPartiallyRefunded
+	if remaining.IsZero() {
+		tender.Status = TenderStatusRefunded
+	}
+	tender.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, tender); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// RecordCapture applies a capture the gateway confirmed asynchronously. The tender it
+// belongs to is marked captured; a capture for an order that was paid without a tender,
+// such as a single payment method charged by the gateway's hosted checkout, is recorded
+// as a new card tender. Confirmations for a tender already settled change nothing.
+func (s *PaymentService) RecordCapture(ctx context.Context, order *orders.Order, capture PaymentCapture) (*PaymentTender, error) {
+	tender, err := s.findCaptureTender(ctx, capture)
+	if err != nil && err != ErrTenderNotFound {
+		return nil, err
+	}
+	if tender != nil && tender.OrderID != order.ID {
+		return nil, ErrTenderNotFound
+	}
+
+	if tender == nil {
+		paymentMethodID := order.PaymentMethodID
+		if paymentMethodID == "" {
+			paymentMethodID = capture.GatewayReference
+		}
+		if tender, err = newTender(order, TenderRequest{
+			Type:            TenderTypeCard,
+			PaymentMethodID: paymentMethodID,
+			Amount:          capture.Amount,
+		}); err != nil {
+			return nil, err
+		}
+	} else if tender.Status != TenderStatusPending {
+		return tender, nil
+	}
+
+	tender.Status = TenderStatusCaptured
+	tender.CapturedAmount = tender.Amount
+	if capture.Amount.IsPositive() && capture.Amount.Currency == tender.Amount.Currency {
+		tender.CapturedAmount = capture.Amount
+	}
+	if capture.GatewayReference != "" {
+		tender.GatewayReference = capture.GatewayReference
+	}
+	tender.FailureReason = ""
+	tender.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, tender); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// findCaptureTender finds the tender a capture confirms, by payment intent and then by tender ID
+func (s *PaymentService) findCaptureTender(ctx context.Context, capture PaymentCapture) (*PaymentTender, error) {
+	if capture.GatewayReference != "" {
+		tender, err := s.repo.FindByGatewayReference(ctx, capture.GatewayReference)
+		if err != ErrTenderNotFound {
+			return tender, err
+		}
+	}
+	if capture.TenderID != "" {
+		return s.repo.FindByID(ctx, capture.TenderID)
+	}
+	return nil, ErrTenderNotFound
+}
+
+// Summary reconciles the order total against the captured and refunded amounts of its tenders
+func (s *PaymentService) Summary(ctx context.Context, order *orders.Order) (*PaymentSummary, error) {
+	tenders, err := s.repo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := order.Total.Currency
+	summary := &PaymentSummary{
+		OrderTotal: order.Total,
+		Captured:   money.Zero(currency),
+		Refunded:   money.Zero(currency),
+		Tenders:    tenders,
+	}
+
+	for _, tender := range tenders {
+		if tender.Status == TenderStatusCanceled {
+			continue
+		}
+		if summary.Captured, err = summary.Captured.Add(tender.CapturedAmount); err != nil {
+			return nil, err
+		}
+		if summary.Refunded, err = summary.Refunded.Add(tender.RefundedAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	if summary.Balance, err = order.Total.Subtract(summary.Captured); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// newTender validates a tender request against the order and builds a pending tender
+func newTender(order *orders.Order, req TenderRequest) (*PaymentTender, error) {
+	currency := order.Total.Currency
+	if !req.Type.IsValid() || req.PaymentMethodID == "" || !req.Amount.IsPositive() || req.Amount.Currency != currency {
+		return nil, ErrInvalidTender
+	}
+
+	now := time.Now()
+	return &PaymentTender{
+		ID:              utils.GenerateID(),
+		OrderID:         order.ID,
+		Type:            req.Type,
+		PaymentMethodID: req.PaymentMethodID,
+		Amount:          req.Amount,
+		CapturedAmount:  money.Zero(currency),
+		RefundedAmount:  money.Zero(currency),
+		Status:          TenderStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+// charge creates a gateway intent for the tender and records the outcome.
+// Without a gateway, or while its circuit breaker is open, card and gift card tenders
+// stay pending for later capture.
+func (s *PaymentService) charge(ctx context.Context, order *orders.Order, tender *PaymentTender) error {
+	if tender.Type == TenderTypeStoreCredit {
+		return s.redeemStoreCredit(ctx, tender)
+	}
+	if tender.Type == TenderTypeCash {
+		return ErrInvalidTender
+	}
+	if s.gateway == nil {
+		return nil
+	}
+
+	intent, err := s.gateway.CreateIntent(ctx, payments.IntentRequest{
+		Amount:          tender.Amount,
+		Currency:        tender.Amount.Currency,
+		PaymentMethodID: tender.PaymentMethodID,
+		OrderID:         order.ID,
+		Description:     "Order " + order.OrderNumber,
+		Metadata:        map[string]string{"tender_id": tender.ID, "tender_type": string(tender.Type)},
+		CaptureMethod:   payments.CaptureMethodAutomatic,
+	})
+
+	tender.UpdatedAt = time.Now()
+	switch {
+	case errors.Is(err, breaker.ErrOpen):
+		// The gateway is down; the charge was never attempted, so nothing was declined
+	case err != nil:
+		tender.Status = TenderStatusFailed
+		tender.FailureReason = err.Error()
+	case intent.Status == payments.IntentStatusSucceeded:
+		tender.Status = TenderStatusCaptured
+		tender.GatewayReference = intent.ID
+		tender.CapturedAmount = intent.CapturedAmount
+		if tender.CapturedAmount.IsZero() {
+			tender.CapturedAmount = tender.Amount
+		}
+	case intent.Status == payments.IntentStatusFailed || intent.Status == payments.IntentStatusCanceled:
+		tender.Status = TenderStatusFailed
+		tender.GatewayReference = intent.ID
+		tender.FailureReason = string(intent.Status)
+	default:
+		// Still processing at the gateway; capture is confirmed asynchronously
+		tender.GatewayReference = intent.ID
+	}
+
+	if saveErr := s.repo.Save(ctx, tender); saveErr != nil {
+		return saveErr
+	}
+	if tender.Status == TenderStatusFailed {
+		return ErrTenderDeclined
+	}
+	return nil
+}
+
+// redeemStoreCredit debits the tender from the customer's wallet; credit is captured immediately
+func (s *PaymentService) redeemStoreCredit(ctx context.Context, tender *PaymentTender) error {
+	if s.storeCredit == nil {
+		return ErrInvalidTender
+	}
+
+	txn, err := s.storeCredit.Redeem(ctx, tender.PaymentMethodID, tender.Amount, tender.OrderID)
+
+	tender.UpdatedAt = time.Now()
+	switch {
+	case err == ErrInsufficientStoreCredit:
+		tender.Status = TenderStatusFailed
+		tender.FailureReason = err.Error()
+	case err != nil:
+		return err
+	default:
+		tender.Status = TenderStatusCaptured
+		tender.GatewayReference = txn.ID
+		tender.CapturedAmount = tender.Amount
+	}
+
+	if saveErr := s.repo.Save(ctx, tender); saveErr != nil {
+		return saveErr
+	}
+	if tender.Status == TenderStatusFailed {
+		return ErrTenderDeclined
+	}
+	return nil
+}
+
+// rollback voids the tenders charged before another tender was declined:
+// captured tenders are refunded and pending intents are canceled
+func (s *PaymentService) rollback(ctx context.Context, tenders []*PaymentTender) {
+	for _, tender := range tenders {
+		switch tender.Status {
+		case TenderStatusCaptured:
+			refunded, err := s.RefundTender(ctx, tender.ID, tender.Refundable(), payments.RefundReasonOther)
+			if err != nil {
+				continue
+			}
+			tender.RefundedAmount = refunded.RefundedAmount
+		case TenderStatusPending:
+			if tender.GatewayReference != "" {
+				_, _ = s.gateway.CancelIntent(ctx, tender.GatewayReference)
+			}
+		default:
+			continue
+		}
+		tender.Status = TenderStatusCanceled
+		tender.UpdatedAt = time.Now()
+		_ = s.repo.Save(ctx, tender)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrPlacementNotFound = errors.New("placement not found")
+	ErrInvalidPlacement  = errors.New("placement requires a slot, a title, an image and a valid target URL")
+	ErrInvalidSchedule   = errors.New("placement must end after it starts")
+)
+
+// placementSlotPattern allows lowercase slot keys such as "home_hero" or "home-promo-tiles"
+var placementSlotPattern = regexp.MustCompile(`^[a-z0-9]+([_-][a-z0-9]+)*$`)
+
+// Placement is a banner or promo tile shown in a storefront slot such as the homepage hero
+type Placement struct {
+	ID        string     `json:"id"`
+	Slot      string     `json:"slot"`
+	Title     string     `json:"title"`
+	Subtitle  string     `json:"subtitle,omitempty"`
+	ImageURL  string     `json:"image_url"`
+	ImageAlt  string     `json:"image_alt,omitempty"`
+	TargetURL string     `json:"target_url"` // absolute http(s) URL or a storefront path such as /catalog/sale
+	Position  int        `json:"position"`   // lower positions show first
+	IsActive  bool       `json:"is_active"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// IsLive reports whether the placement is active and within its schedule
+func (p *Placement) IsLive(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// PlacementSortFields are the fields the admin placement list can be sorted by
+var PlacementSortFields = []string{"slot", "position", "title", "starts_at", "ends_at", "created_at"}
+
+// PlacementFilter filters the admin placement list
+type PlacementFilter struct {
+	Slot   string
+	Sort   Sort
+	Limit  int
+	Offset int
+}
+
+// PlacementRepository defines persistence for placements
+type PlacementRepository interface {
+	FindByID(ctx context.Context, id string) (*Placement, error)
+	// FindBySlot returns every placement in a slot, ordered by position
+	FindBySlot(ctx context.Context, slot string) ([]*Placement, error)
+	List(ctx context.Context, filter PlacementFilter) ([]*Placement, error)
+	Count(ctx context.Context, filter PlacementFilter) (int64, error)
+	Save(ctx context.Context, placement *Placement) error
+	Delete(ctx context.Context, id string) error
+}
+
+// PlacementService manages scheduled banners and promo tiles
+type PlacementService struct {
+	repo PlacementRepository
+}
+
+// NewPlacementService creates a new PlacementService
+func NewPlacementService(repo PlacementRepository) *PlacementService {
+	return &PlacementService{repo: repo}
+}
+
+// GetLivePlacements returns the placements currently showing in a slot, in display order
+func (s *PlacementService) GetLivePlacements(ctx context.Context, slot string) ([]*Placement, error) {
+	placements, err := s.repo.FindBySlot(ctx, strings.ToLower(slot))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	live := make([]*Placement, 0, len(placements))
+	for _, placement := range placements {
+		if placement.IsLive(now) {
+			live = append(live, placement)
+		}
+	}
+	return live, nil
+}
+
+// ListPlacements returns placements matching the filter, including scheduled and inactive ones
+func (s *PlacementService) ListPlacements(ctx context.Context, filter PlacementFilter) ([]*Placement, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountPlacements counts placements matching the filter
+func (s *PlacementService) CountPlacements(ctx context.Context, filter PlacementFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetPlacement returns a placement by ID
+func (s *PlacementService) GetPlacement(ctx context.Context, id string) (*Placement, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SavePlacement validates and saves a placement
+func (s *PlacementService) SavePlacement(ctx context.Context, placement *Placement) error {
+	placement.Slot = strings.ToLower(strings.TrimSpace(placement.Slot))
+	if !placementSlotPattern.MatchString(placement.Slot) || strings.TrimSpace(placement.Title) == "" ||
+		placement.ImageURL == "" || !isValidTargetURL(placement.TargetURL) {
+		return ErrInvalidPlacement
+	}
+	if placement.StartsAt != nil && placement.EndsAt != nil && !placement.EndsAt.After(*placement.StartsAt) {
+		return ErrInvalidSchedule
+	}
+
+	now := time.Now()
+	if placement.ID == "" {
+		placement.ID = utils.GenerateID()
+		placement.CreatedAt = now
+	}
+	placement.UpdatedAt = now
+
+	return s.repo.Save(ctx, placement)
+}
+
+// DeletePlacement deletes a placement
+func (s *PlacementService) DeletePlacement(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// isValidTargetURL accepts absolute http(s) URLs and site-relative paths
+func isValidTargetURL(target string) bool {
+	if strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") {
+		return true
+	}
+	u, err := url.Parse(target)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidPOSSale  = errors.New("in-store sale requires a register, items with positive quantities, a complete store address and positive card or cash payments, with a terminal reference for cards")
+	ErrPOSItemNotFound = errors.New("no active product or variant has this SKU")
+)
+
+// posDateLayout is the format of RegisterSummary.Date
+const posDateLayout = "2006-01-02"
+
+// POSSaleItem is one line rung up at the register, by product or variant SKU
+type POSSaleItem struct {
+	SKU      string
+	Quantity int
+}
+
+// POSPayment is a payment the register has already captured
+type POSPayment struct {
+	Type      TenderType // card or cash
+	Reference string     // the terminal's transaction reference; defaults to the register for cash
+	Amount    int64      // in cents, in the order currency
+}
+
+// POSSaleRequest is an in-store sale whose payments were already taken at the register.
+// The store address is used for tax and recorded as the order's address.
+type POSSaleRequest struct {
+	RegisterID     string
+	TerminalID     string
+	CustomerID     string // optional; in-store sales are usually anonymous
+	StoreAddress   orders.Address
+	Items          []POSSaleItem
+	Payments       []POSPayment
+	PromotionCodes []string
+	Notes          string
+	APIKeyID       string
+}
+
+// POSSale records which register and terminal an in-store order was rung up on
+type POSSale struct {
+	OrderID    string    `json:"order_id"`
+	RegisterID string    `json:"register_id"`
+	TerminalID string    `json:"terminal_id,omitempty"`
+	APIKeyID   string    `json:"api_key_id,omitempty"` // the POS integration that recorded it
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// POSSaleResult is a completed in-store sale
+type POSSaleResult struct {
+	Sale    *POSSale
+	Order   *orders.Order
+	Tenders []*PaymentTender
+}
+
+// POSTotalMismatchError reports payments that don't add up to the priced order total.
+// The order is canceled; the register should void its payments and ring the sale up again.
+type POSTotalMismatchError struct {
+	OrderTotal money.Money
+}
+
+func (e *POSTotalMismatchError) Error() string {
+	return fmt.Sprintf("payments must add up to the order total of %d %s", e.OrderTotal.Amount, e.OrderTotal.Currency)
+}
+
+func (e *POSTotalMismatchError) Unwrap() error {
+	return ErrTenderTotalMismatch
+}
+
+// POSSaleTotals is the part of an order an end-of-day summary counts
+type POSSaleTotals struct {
+	OrderID       string
+	Status        orders.OrderStatus
+	Total         money.Money
+	DiscountTotal money.Money
+	TaxTotal      money.Money
+}
+
+// RegisterSummary is a register's end-of-day sales. Canceled sales are counted but not
+// included in the amounts; Tenders are the amounts captured per tender type.
+type RegisterSummary struct {
+	RegisterID    string                     `json:"register_id"`
+	Date          string                     `json:"date"` // YYYY-MM-DD
+	Sales         int                        `json:"sales"`
+	CanceledSales int                        `json:"canceled_sales"`
+	GrossSales    money.Money                `json:"gross_sales"`
+	DiscountTotal money.Money                `json:"discount_total"`
+	TaxTotal      money.Money                `json:"tax_total"`
+	Tenders       map[TenderType]money.Money `json:"tenders"`
+	Refunded      money.Money                `json:"refunded"`
+	NetCollected  money.Money                `json:"net_collected"` // captured less refunded
+	FirstSaleAt   *time.Time                 `json:"first_sale_at,omitempty"`
+	LastSaleAt    *time.Time                 `json:"last_sale_at,omitempty"`
+}
+
+// POSRepository defines persistence for in-store sales
+type POSRepository interface {
+	Save(ctx context.Context, sale *POSSale) error
+	// ListSales lists a register's sales made within [from, to), oldest first
+	ListSales(ctx context.Context, registerID string, from, to time.Time) ([]*POSSale, error)
+	// ListSaleTotals returns the totals of the sales' orders
+	ListSaleTotals(ctx context.Context, orderIDs []string) ([]*POSSaleTotals, error)
+	// ListSaleTenders returns the payment tenders of the sales' orders
+	ListSaleTenders(ctx context.Context, orderIDs []string) ([]*PaymentTender, error)
+}
+
+// POSService rings up in-store sales from POS registers and reports their daily totals
+type POSService struct {
+	repo           POSRepository
+	products       catalog.ProductRepository
+	variants       catalog.VariantRepository
+	prices         cart.PriceResolver
+	orderService   *OrderService
+	paymentService *PaymentService
+	attribution    *OrderAttributionService
+}
+
+// NewPOSService creates a new POSService
+func NewPOSService(repo POSRepository, products catalog.ProductRepository, variants catalog.VariantRepository, orderService *OrderService, paymentService *PaymentService) *POSService {
+	return &POSService{
+		repo:           repo,
+		products:       products,
+		variants:       variants,
+		orderService:   orderService,
+		paymentService: paymentService,
+	}
+}
+
+// WithPriceResolver prices items as the online cart does, including sale prices.
+// Without one, items are sold at their catalog price.
+func (s *POSService) WithPriceResolver(prices cart.PriceResolver) *POSService {
+	s.prices = prices
+	return s
+}
+
+// WithAttribution records in-store orders under the pos channel for channel reports
+func (s *POSService) WithAttribution(attribution *OrderAttributionService) *POSService {
+	s.attribution = attribution
+	return s
+}
+
+// CreateSale creates an in-store order from the items rung up and records the payments
+// the register captured. The order is marked paid; it is handed over at the till, so it
+// never appears in the fulfillment feed.
+func (s *POSService) CreateSale(ctx context.Context, req POSSaleRequest) (*POSSaleResult, error) {
+	if err := validatePOSSale(req); err != nil {
+		return nil, err
+	}
+
+	saleCart := &cart.Cart{ID: utils.GenerateID(), UserID: req.CustomerID, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, item := range req.Items {
+		cartItem, err := s.ringUp(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		saleCart.AddItem(*cartItem)
+	}
+
+	order, err := s.orderService.CreateFromCart(ctx, orders.CreateOrderRequest{
+		Cart:            saleCart,
+		UserID:          req.CustomerID,
+		ShippingAddress: req.StoreAddress,
+		BillingAddress:  req.StoreAddress,
+		PromotionCodes:  req.PromotionCodes,
+		Notes:           req.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sale := &POSSale{
+		OrderID:    order.ID,
+		RegisterID: req.RegisterID,
+		TerminalID: req.TerminalID,
+		APIKeyID:   req.APIKeyID,
+		CreatedAt:  order.CreatedAt,
+	}
+	if err := s.repo.Save(ctx, sale); err != nil {
+		return nil, err
+	}
+	if s.attribution != nil {
+		if _, err := s.attribution.Record(ctx, order.ID, ChannelPOS, UTM{}); err != nil {
+			log.Printf("Failed to record attribution for in-store order %s: %v", order.ID, err)
+		}
+	}
+
+	requests := make([]TenderRequest, len(req.Payments))
+	for i, payment := range req.Payments {
+		reference := payment.Reference
+		if reference == "" {
+			reference = req.RegisterID
+		}
+		requests[i] = TenderRequest{
+			Type:            payment.Type,
+			PaymentMethodID: reference,
+			Amount:          money.Money{Amount: payment.Amount, Currency: order.Total.Currency},
+		}
+	}
+	tenders, err := s.paymentService.RecordCapturedTenders(ctx, order, requests)
+	if err != nil {
+		if _, cancelErr := s.orderService.CancelOrder(ctx, order.ID, "In-store payments not recorded: "+err.Error()); cancelErr != nil {
+			log.Printf("Failed to cancel in-store order %s: %v", order.ID, cancelErr)
+		}
+		if err == ErrTenderTotalMismatch {
+			return nil, &POSTotalMismatchError{OrderTotal: order.Total}
+		}
+		return nil, err
+	}
+
+	if order, err = s.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusPaid); err != nil {
+		return nil, err
+	}
+	return &POSSaleResult{Sale: sale, Order: order, Tenders: tenders}, nil
+}
+
+// RegisterSummary returns a register's sales for the day
+func (s *POSService) RegisterSummary(ctx context.Context, registerID string, day time.Time) (*RegisterSummary, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	sales, err := s.repo.ListSales(ctx, registerID, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]string, len(sales))
+	for i, sale := range sales {
+		orderIDs[i] = sale.OrderID
+	}
+	totals, tenders := []*POSSaleTotals{}, []*PaymentTender{}
+	if len(orderIDs) > 0 {
+		if totals, err = s.repo.ListSaleTotals(ctx, orderIDs); err != nil {
+			return nil, err
+		}
+		if tenders, err = s.repo.ListSaleTenders(ctx, orderIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Amounts are in the currency of the register's sales; a day without sales reports zero USD
+	currency := "USD"
+	if len(totals) > 0 {
+		currency = totals[0].Total.Currency
+	}
+	summary := &RegisterSummary{
+		RegisterID:    registerID,
+		Date:          from.Format(posDateLayout),
+		GrossSales:    money.Zero(currency),
+		DiscountTotal: money.Zero(currency),
+		TaxTotal:      money.Zero(currency),
+		Tenders:       make(map[TenderType]money.Money),
+		Refunded:      money.Zero(currency),
+		NetCollected:  money.Zero(currency),
+	}
+	if len(sales) > 0 {
+		summary.FirstSaleAt = &sales[0].CreatedAt
+		summary.LastSaleAt = &sales[len(sales)-1].CreatedAt
+	}
+
+	for _, total := range totals {
+		if total.Status == orders.OrderStatusCanceled {
+			summary.CanceledSales++
+			continue
+		}
+		summary.Sales++
+		if summary.GrossSales, err = summary.GrossSales.Add(total.Total); err != nil {
+			return nil, err
+		}
+		if summary.DiscountTotal, err = summary.DiscountTotal.Add(total.DiscountTotal); err != nil {
+			return nil, err
+		}
+		if summary.TaxTotal, err = summary.TaxTotal.Add(total.TaxTotal); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tender := range tenders {
+		if tender.Status == TenderStatusCanceled || tender.CapturedAmount.IsZero() {
+			continue
+		}
+		captured, ok := summary.Tenders[tender.Type]
+		if !ok {
+			captured = money.Zero(currency)
+		}
+		if summary.Tenders[tender.Type], err = captured.Add(tender.CapturedAmount); err != nil {
+			return nil, err
+		}
+		if summary.Refunded, err = summary.Refunded.Add(tender.RefundedAmount); err != nil {
+			return nil, err
+		}
+		if summary.NetCollected, err = summary.NetCollected.Add(tender.Refundable()); err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// ringUp resolves an item's SKU to a variant or product and prices it
+func (s *POSService) ringUp(ctx context.Context, item POSSaleItem) (*cart.CartItem, error) {
+	var product *catalog.Product
+	variant, err := s.variants.FindBySKU(ctx, item.SKU)
+	if err == nil {
+		product, err = s.products.FindByID(ctx, variant.ProductID)
+	} else {
+		variant = nil
+		product, err = s.products.FindBySKU(ctx, item.SKU)
+	}
+	if err != nil || product.Status != catalog.ProductStatusActive {
+		return nil, fmt.Errorf("%w: %s", ErrPOSItemNotFound, item.SKU)
+	}
+
+	cartItem := &cart.CartItem{
+		ID:        utils.GenerateID(),
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		Name:      product.Name,
+		Price:     product.BasePrice,
+		Quantity:  item.Quantity,
+		AddedAt:   time.Now(),
+	}
+	if variant != nil {
+		cartItem.VariantID = &variant.ID
+		cartItem.SKU = variant.SKU
+		cartItem.Name = variant.Name
+		cartItem.Price = variant.Price
+		cartItem.Attributes = variant.Attributes
+	}
+	if s.prices != nil {
+		price, err := s.prices.GetEffectivePrice(ctx, product, variant, nil)
+		if err != nil {
+			return nil, err
+		}
+		cartItem.Price = price
+	}
+	return cartItem, nil
+}
+
+// validatePOSSale checks a sale has what is needed to price and record it
+func validatePOSSale(req POSSaleRequest) error {
+	if req.RegisterID == "" || len(req.Items) == 0 || !req.StoreAddress.IsComplete() || len(req.Payments) == 0 {
+		return ErrInvalidPOSSale
+	}
+	for _, item := range req.Items {
+		if item.SKU == "" || item.Quantity <= 0 {
+			return ErrInvalidPOSSale
+		}
+	}
+	for _, payment := range req.Payments {
+		if payment.Amount <= 0 {
+			return ErrInvalidPOSSale
+		}
+		if payment.Type != TenderTypeCash && (payment.Type != TenderTypeCard || payment.Reference == "") {
+			return ErrInvalidPOSSale
+		}
+	}
+	return nil
+}
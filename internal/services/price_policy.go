@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/pricing"
+)
+
+// RoundingMode is how a price rounding rule picks the displayed price
+type RoundingMode string
+
+const (
+	// RoundNearest rounds to the nearest multiple of the step, halves up
+	RoundNearest RoundingMode = "nearest"
+	// RoundUp rounds up to a multiple of the step
+	RoundUp RoundingMode = "up"
+	// RoundDown rounds down to a multiple of the step
+	RoundDown RoundingMode = "down"
+	// RoundCharm rounds to the nearest price ending in the given minor units, e.g. 99 for
+	// x.99 or 990 for ¥x,990
+	RoundCharm RoundingMode = "charm"
+)
+
+// PriceRoundingRule rounds prices in one currency. Value is the step in minor units for
+// nearest, up and down, or the ending for charm.
+type PriceRoundingRule struct {
+	Currency string
+	Mode     RoundingMode
+	Value    int64
+}
+
+// PricePolicy applies per-currency rounding rules to prices shoppers see and pay, so
+// converted and price-table prices come out as e.g. CHF 0.05 steps or charm prices
+type PricePolicy struct {
+	rules map[string]PriceRoundingRule
+}
+
+// NewPricePolicy creates a PricePolicy. Currencies without a rule are left as they are.
+func NewPricePolicy(rules []PriceRoundingRule) *PricePolicy {
+	byCurrency := make(map[string]PriceRoundingRule, len(rules))
+	for _, rule := range rules {
+		rule.Currency = strings.ToUpper(rule.Currency)
+		byCurrency[rule.Currency] = rule
+	}
+	return &PricePolicy{rules: byCurrency}
+}
+
+// ParsePriceRoundingRules parses CODE:mode:value entries such as CHF:nearest:5 or
+// USD:charm:99 into rules for NewPricePolicy
+func ParsePriceRoundingRules(entries []string) ([]PriceRoundingRule, error) {
+	rules := make([]PriceRoundingRule, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("price rounding %q must be CODE:mode:value", entry)
+		}
+		currency := strings.ToUpper(strings.TrimSpace(parts[0]))
+		if len(currency) != 3 {
+			return nil, fmt.Errorf("price rounding %q must start with a three-letter currency code", entry)
+		}
+		if seen[currency] {
+			return nil, fmt.Errorf("price rounding for %s is given twice", currency)
+		}
+		seen[currency] = true
+
+		mode := RoundingMode(strings.ToLower(strings.TrimSpace(parts[1])))
+		value, err := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		switch {
+		case mode != RoundNearest && mode != RoundUp && mode != RoundDown && mode != RoundCharm:
+			return nil, fmt.Errorf("price rounding mode for %s must be nearest, up, down or charm", currency)
+		case err != nil || value <= 0:
+			return nil, fmt.Errorf("price rounding value for %s must be a positive whole number of minor units", currency)
+		}
+		rules = append(rules, PriceRoundingRule{Currency: currency, Mode: mode, Value: value})
+	}
+	return rules, nil
+}
+
+// Apply rounds a price by its currency's rule. Zero and negative amounts are left as they
+// are, and rounding never takes a positive price to zero.
+func (p *PricePolicy) Apply(price money.Money) money.Money {
+	rule, ok := p.rules[strings.ToUpper(price.Currency)]
+	if !ok || price.Amount <= 0 {
+		return price
+	}
+
+	amount := price.Amount
+	switch rule.Mode {
+	case RoundNearest:
+		amount = (amount + rule.Value/2) / rule.Value * rule.Value
+	case RoundUp:
+		amount = (amount + rule.Value - 1) / rule.Value * rule.Value
+	case RoundDown:
+		amount = amount / rule.Value * rule.Value
+	case RoundCharm:
+		// Prices ending in 99 repeat every 100 minor units, ending in 990 every 1000
+		block := int64(10)
+		for block <= rule.Value {
+			block *= 10
+		}
+		below := amount - ((amount-rule.Value)%block+block)%block
+		above := below + block
+		amount = above
+		if below > 0 && amount-price.Amount > price.Amount-below {
+			amount = below
+		}
+	}
+	if amount <= 0 {
+		amount = rule.Value
+	}
+	return money.Money{Amount: amount, Currency: price.Currency}
+}
+
+// RoundedPrices wraps a product price repository so effective prices from the price table,
+// such as sale prices, follow the policy wherever they are resolved: catalog display, cart
+// and POS pricing alike
+func (p *PricePolicy) RoundedPrices(repo pricing.ProductPriceRepository) *RoundedPriceRepository {
+	return &RoundedPriceRepository{ProductPriceRepository: repo, policy: p}
+}
+
+// RoundedPriceRepository is a pricing.ProductPriceRepository whose effective prices are
+// rounded by a PricePolicy
+type RoundedPriceRepository struct {
+	pricing.ProductPriceRepository
+	policy *PricePolicy
+}
+
+// FindEffectivePrice returns the rounded effective price for a product or variant
+func (r *RoundedPriceRepository) FindEffectivePrice(ctx context.Context, productID string, variantID *string, at time.Time) (*pricing.ProductPrice, error) {
+	price, err := r.ProductPriceRepository.FindEffectivePrice(ctx, productID, variantID, at)
+	if err != nil || price == nil {
+		return price, err
+	}
+	return r.round(price), nil
+}
+
+// FindEffectivePrices returns the rounded effective prices of several products
+func (r *RoundedPriceRepository) FindEffectivePrices(ctx context.Context, productIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error) {
+	prices, err := r.ProductPriceRepository.FindEffectivePrices(ctx, productIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	rounded := make(map[string]*pricing.ProductPrice, len(prices))
+	for productID, price := range prices {
+		rounded[productID] = r.round(price)
+	}
+	return rounded, nil
+}
+
+// FindEffectiveVariantPrices returns the rounded effective prices of several of a product's
+// variants, in one lookup when the wrapped repository supports it
+func (r *RoundedPriceRepository) FindEffectiveVariantPrices(ctx context.Context, productID string, variantIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error) {
+	rounded := make(map[string]*pricing.ProductPrice, len(variantIDs))
+	if batch, ok := r.ProductPriceRepository.(VariantSalePriceResolver); ok {
+		prices, err := batch.FindEffectiveVariantPrices(ctx, productID, variantIDs, at)
+		if err != nil {
+			return nil, err
+		}
+		for variantID, price := range prices {
+			rounded[variantID] = r.round(price)
+		}
+		return rounded, nil
+	}
+
+	for _, variantID := range variantIDs {
+		variantID := variantID
+		price, err := r.FindEffectivePrice(ctx, productID, &variantID, at)
+		if err != nil {
+			return nil, err
+		}
+		if price != nil {
+			rounded[variantID] = price
+		}
+	}
+	return rounded, nil
+}
+
+// round returns a copy of the price with its amount rounded, leaving the stored record as is
+func (r *RoundedPriceRepository) round(price *pricing.ProductPrice) *pricing.ProductPrice {
+	rounded := *price
+	rounded.Price = r.policy.Apply(price.Price)
+	return &rounded
+}
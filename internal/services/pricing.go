@@ -1,14 +1,23 @@
 package services
 
 import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
 	"github.com/devchuckcamp/gocommerce/pricing"
 	"github.com/devchuckcamp/gocommerce/shipping"
 	"github.com/devchuckcamp/gocommerce/tax"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
 )
 
 // PricingService holds the gocommerce pricing service
 type PricingService struct {
 	pricing.Service
+	promotions pricing.PromotionRepository
+	currency   *CurrencyService
+	donations  *DonationService
 }
 
 // NewPricingService creates a new PricingService using gocommerce domain service
@@ -18,12 +27,156 @@ func NewPricingService(
 	shippingCalc shipping.RateCalculator, // can be nil if not using shipping
 ) *PricingService {
 	svc := pricing.NewPricingService(
-		promotionRepo,
+		excludingPromotions{promotionRepo},
 		taxCalculator,
 		shippingCalc,
 	)
 
 	return &PricingService{
-		Service: svc,
+		Service:    svc,
+		promotions: promotionRepo,
+	}
+}
+
+// WithCurrencyService converts promotion minimum purchases into the cart currency. Without
+// one, a promotion only applies to carts in the currency of its minimum purchase.
+func (s *PricingService) WithCurrencyService(currency *CurrencyService) *PricingService {
+	s.currency = currency
+	return s
+}
+
+// WithDonations checks the amounts of donation items whenever a cart is priced, and keeps
+// promotions from discounting them or counting them towards a minimum purchase
+func (s *PricingService) WithDonations(donations *DonationService) *PricingService {
+	s.donations = donations
+	return s
+}
+
+// PriceCart prices a cart, applying only the promotion codes whose minimum purchase the
+// cart subtotal meets; table-rate shipping is priced against the same subtotal
+func (s *PricingService) PriceCart(ctx context.Context, req pricing.PriceCartRequest) (*pricing.PricingResult, error) {
+	if req.Cart != nil && !req.Cart.IsEmpty() {
+		items := make([]pricing.LineItem, len(req.Cart.Items))
+		for i, item := range req.Cart.Items {
+			items[i] = pricing.LineItem{
+				ID:         item.ID,
+				ProductID:  item.ProductID,
+				VariantID:  item.VariantID,
+				SKU:        item.SKU,
+				Name:       item.Name,
+				UnitPrice:  item.Price,
+				Quantity:   item.Quantity,
+				Attributes: item.Attributes,
+			}
+		}
+		var err error
+		if ctx, req.PromotionCodes, err = s.prepare(ctx, items, req.PromotionCodes); err != nil {
+			return nil, err
+		}
+	}
+	return s.Service.PriceCart(ctx, req)
+}
+
+// PriceLineItems prices line items, applying only the promotion codes whose minimum
+// purchase the items' subtotal meets; table-rate shipping is priced against the same subtotal
+func (s *PricingService) PriceLineItems(ctx context.Context, req pricing.PriceLineItemsRequest) (*pricing.PricingResult, error) {
+	if len(req.Items) > 0 {
+		var err error
+		if ctx, req.PromotionCodes, err = s.prepare(ctx, req.Items, req.PromotionCodes); err != nil {
+			return nil, err
+		}
+	}
+	return s.Service.PriceLineItems(ctx, req)
+}
+
+// prepare checks the donation items about to be priced and returns the promotion codes
+// whose minimum purchase the items other than donations meet, with the pricing context
+// recording the donations promotions skip and the subtotal shipping is priced against
+func (s *PricingService) prepare(ctx context.Context, items []pricing.LineItem, codes []string) (context.Context, []string, error) {
+	if s.donations != nil {
+		if err := s.donations.CheckItems(ctx, items); err != nil {
+			return ctx, nil, err
+		}
+	}
+
+	subtotal := money.Zero(items[0].UnitPrice.Currency)
+	discountable := subtotal
+	var donations []string
+	for _, item := range items {
+		lineTotal, err := moneymath.MulInt(item.UnitPrice, int64(item.Quantity))
+		if err != nil {
+			return ctx, nil, err
+		}
+		if subtotal, err = moneymath.Add(subtotal, lineTotal); err != nil {
+			return ctx, nil, err
+		}
+		if s.donations != nil && isDonation(item.Attributes) {
+			donations = append(donations, item.ProductID)
+		} else if discountable, err = moneymath.Add(discountable, lineTotal); err != nil {
+			return ctx, nil, err
+		}
+	}
+
+	if len(donations) > 0 {
+		ctx = withPromotionExclusions(ctx, donations)
+	}
+	return WithShippingSubtotal(ctx, subtotal), s.eligibleCodes(ctx, discountable, codes), nil
+}
+
+// ValidatePromotion validates a promotion code against a cart total in any currency
+func (s *PricingService) ValidatePromotion(ctx context.Context, code string, cartTotal money.Money) (*pricing.Promotion, error) {
+	promotion, err := s.promotions.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !promotion.IsValid(time.Now()) {
+		return nil, pricing.ErrPromotionInvalid
+	}
+	met, err := s.meetsMinPurchase(promotion, cartTotal)
+	if err != nil {
+		return nil, err
+	}
+	if !met {
+		return nil, pricing.ErrMinPurchaseNotMet
+	}
+	return promotion, nil
+}
+
+// eligibleCodes drops the codes of promotions whose minimum purchase the subtotal doesn't
+// meet. Unknown and expired codes are left for the pricing service to skip.
+func (s *PricingService) eligibleCodes(ctx context.Context, subtotal money.Money, codes []string) []string {
+	eligible := make([]string, 0, len(codes))
+	for _, code := range codes {
+		promotion, err := s.promotions.FindByCode(ctx, code)
+		if err == nil {
+			if met, err := s.meetsMinPurchase(promotion, subtotal); err != nil || !met {
+				continue
+			}
+		}
+		eligible = append(eligible, code)
+	}
+	return eligible
+}
+
+// meetsMinPurchase reports whether an amount reaches a promotion's minimum purchase,
+// converted into the amount's currency
+func (s *PricingService) meetsMinPurchase(promotion *pricing.Promotion, amount money.Money) (bool, error) {
+	if promotion.MinPurchase == nil {
+		return true, nil
+	}
+
+	minimum := *promotion.MinPurchase
+	if s.currency != nil {
+		converted, err := s.currency.Convert(minimum, amount.Currency)
+		if err != nil {
+			return false, err
+		}
+		minimum = converted
+	}
+	below, err := amount.LessThan(minimum)
+	if err != nil {
+		// A minimum in another currency can't be compared without exchange rates
+		return false, ErrExchangeRateNotFound
 	}
+	return !below, nil
 }
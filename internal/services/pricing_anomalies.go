@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrPricingAnomalyNotFound = errors.New("pricing anomaly not found")
+	ErrPricingAnomalyReviewed = errors.New("pricing anomaly has already been reviewed")
+	ErrOrderOnHold            = errors.New("order is on hold for pricing review")
+)
+
+// PricingAnomalyKind is what a pricing anomaly was raised on
+type PricingAnomalyKind string
+
+const (
+	AnomalyProductPrice PricingAnomalyKind = "product_price"
+	AnomalyVariantPrice PricingAnomalyKind = "variant_price"
+	AnomalyOrderTotal   PricingAnomalyKind = "order_total"
+)
+
+// PricingAnomalyReason is why a change or order looks like a pricing mistake
+type PricingAnomalyReason string
+
+const (
+	// AnomalyPriceZero is a product or variant price set to zero
+	AnomalyPriceZero PricingAnomalyReason = "price_zero"
+	// AnomalyPriceDrop is a price cut by more than the allowed share in one change
+	AnomalyPriceDrop PricingAnomalyReason = "price_drop"
+	// AnomalyOrderBelowValue is an order paying far less than its items are worth
+	AnomalyOrderBelowValue PricingAnomalyReason = "order_below_value"
+)
+
+// PricingAnomalyStatus is where a pricing anomaly is in review
+type PricingAnomalyStatus string
+
+const (
+	AnomalyPending  PricingAnomalyStatus = "pending"
+	AnomalyApproved PricingAnomalyStatus = "approved"
+	AnomalyRejected PricingAnomalyStatus = "rejected"
+)
+
+// PricingAnomaly is a suspicious catalog price change or order held for an admin to
+// review. A held price change is not applied until it is approved; a held order can't be
+// fulfilled until it is approved, and is canceled when rejected.
+type PricingAnomaly struct {
+	ID         string               `json:"id"`
+	Kind       PricingAnomalyKind   `json:"kind"`
+	Reason     PricingAnomalyReason `json:"reason"`
+	Status     PricingAnomalyStatus `json:"status"`
+	ProductID  string               `json:"product_id,omitempty"`
+	VariantID  string               `json:"variant_id,omitempty"`
+	OrderID    string               `json:"order_id,omitempty"`
+	OldPrice   *money.Money         `json:"old_price,omitempty"`   // price changes; nil for new records
+	NewPrice   *money.Money         `json:"new_price,omitempty"`   // price changes
+	OrderTotal *money.Money         `json:"order_total,omitempty"` // orders: subtotal less discounts
+	ItemValue  *money.Money         `json:"item_value,omitempty"`  // orders: items at their unit prices
+	Message    string               `json:"message"`
+	Change     json.RawMessage      `json:"change,omitempty"` // the held product or variant
+	ReviewedBy string               `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time           `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+// PriceChangeHeldError is returned when a price change is held for review instead of saved
+type PriceChangeHeldError struct {
+	Anomaly *PricingAnomaly
+}
+
+func (e *PriceChangeHeldError) Error() string {
+	return "price change held for review: " + e.Anomaly.Message
+}
+
+// PricingAnomalyRepository defines persistence for pricing anomalies
+type PricingAnomalyRepository interface {
+	FindByID(ctx context.Context, id string) (*PricingAnomaly, error)
+	// List lists anomalies, newest first, optionally only those in one status
+	List(ctx context.Context, status PricingAnomalyStatus, limit, offset int) ([]*PricingAnomaly, error)
+	// HasPendingForOrder reports whether an order has an anomaly awaiting review
+	HasPendingForOrder(ctx context.Context, orderID string) (bool, error)
+	Save(ctx context.Context, anomaly *PricingAnomaly) error
+}
+
+// PricingAlertNotifier tells admins about pricing anomalies awaiting review
+type PricingAlertNotifier interface {
+	NotifyPricingAnomaly(ctx context.Context, anomaly *PricingAnomaly) error
+}
+
+type approvedPriceChangeKey struct{}
+
+// PricingAnomalyService holds suspicious catalog price changes and orders for review and
+// alerts admins about them
+type PricingAnomalyService struct {
+	repo     PricingAnomalyRepository
+	notifier PricingAlertNotifier
+	maxDrop  float64
+	products catalog.ProductRepository
+	variants catalog.VariantRepository
+	orders   orders.Service
+}
+
+// NewPricingAnomalyService creates a PricingAnomalyService. maxDrop is the largest share a
+// price may be cut by in one change, and an order discounted by, e.g. 0.8 for 80%.
+func NewPricingAnomalyService(repo PricingAnomalyRepository, notifier PricingAlertNotifier, maxDrop float64) *PricingAnomalyService {
+	return &PricingAnomalyService{repo: repo, notifier: notifier, maxDrop: maxDrop}
+}
+
+// WithCatalogWriters applies approved price changes through these repositories, such as
+// the catalog history's, so approved changes are recorded like any other
+func (s *PricingAnomalyService) WithCatalogWriters(products catalog.ProductRepository, variants catalog.VariantRepository) *PricingAnomalyService {
+	s.products = products
+	s.variants = variants
+	return s
+}
+
+// WithOrderService cancels held orders when their anomaly is rejected
+func (s *PricingAnomalyService) WithOrderService(orderService orders.Service) *PricingAnomalyService {
+	s.orders = orderService
+	return s
+}
+
+// GuardProducts wraps a product repository so saves that set a price to zero or cut it by
+// more than the allowed share are held for review with a *PriceChangeHeldError
+func (s *PricingAnomalyService) GuardProducts(repo catalog.ProductRepository) catalog.ProductRepository {
+	return &guardedProductRepository{ProductRepository: repo, anomalies: s}
+}
+
+// GuardVariants wraps a variant repository like GuardProducts
+func (s *PricingAnomalyService) GuardVariants(repo catalog.VariantRepository) catalog.VariantRepository {
+	return &guardedVariantRepository{VariantRepository: repo, anomalies: s}
+}
+
+// CheckPrice reports why a change of price from old to updated looks like a mistake, if
+// it does. old is nil for a new record.
+func (s *PricingAnomalyService) CheckPrice(old *money.Money, updated money.Money) (PricingAnomalyReason, bool) {
+	if updated.Amount <= 0 && (old == nil || old.Amount > 0) {
+		return AnomalyPriceZero, true
+	}
+	if old != nil && old.Currency == updated.Currency && old.Amount > 0 &&
+		float64(updated.Amount) < float64(old.Amount)*(1-s.maxDrop) {
+		return AnomalyPriceDrop, true
+	}
+	return "", false
+}
+
+// CheckOrder holds an order that pays far less than its items are worth, after discounts
+// and before tax and shipping, for review. It returns the anomaly, or nil when the order
+// looks right.
+func (s *PricingAnomalyService) CheckOrder(ctx context.Context, order *orders.Order) (*PricingAnomaly, error) {
+	value := order.Subtotal
+	paid, err := value.Subtract(order.DiscountTotal)
+	if err != nil || value.Amount <= 0 || float64(paid.Amount) >= float64(value.Amount)*(1-s.maxDrop) {
+		return nil, nil
+	}
+
+	anomaly := &PricingAnomaly{
+		Kind:       AnomalyOrderTotal,
+		Reason:     AnomalyOrderBelowValue,
+		OrderID:    order.ID,
+		OrderTotal: &paid,
+		ItemValue:  &value,
+		Message:    fmt.Sprintf("order %s pays %s for items worth %s", order.OrderNumber, paid.String(), value.String()),
+	}
+	if err := s.raise(ctx, anomaly); err != nil {
+		return nil, err
+	}
+	return anomaly, nil
+}
+
+// OrderOnHold reports whether an order is held for pricing review
+func (s *PricingAnomalyService) OrderOnHold(ctx context.Context, orderID string) (bool, error) {
+	return s.repo.HasPendingForOrder(ctx, orderID)
+}
+
+// ListAnomalies lists anomalies, newest first, optionally only those in one status
+func (s *PricingAnomalyService) ListAnomalies(ctx context.Context, status PricingAnomalyStatus, limit, offset int) ([]*PricingAnomaly, error) {
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+// GetAnomaly returns a pricing anomaly
+func (s *PricingAnomalyService) GetAnomaly(ctx context.Context, id string) (*PricingAnomaly, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Approve accepts an anomaly as intended: a held price change is applied and a held order
+// released for fulfillment
+func (s *PricingAnomalyService) Approve(ctx context.Context, id, adminID string) (*PricingAnomaly, error) {
+	anomaly, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(WithCatalogActor(ctx, adminID), approvedPriceChangeKey{}, true)
+	switch anomaly.Kind {
+	case AnomalyProductPrice:
+		var product catalog.Product
+		if err := json.Unmarshal(anomaly.Change, &product); err != nil {
+			return nil, err
+		}
+		if err := s.products.Save(ctx, &product); err != nil {
+			return nil, err
+		}
+	case AnomalyVariantPrice:
+		var variant catalog.Variant
+		if err := json.Unmarshal(anomaly.Change, &variant); err != nil {
+			return nil, err
+		}
+		if err := s.variants.Save(ctx, &variant); err != nil {
+			return nil, err
+		}
+	}
+	return s.review(ctx, anomaly, AnomalyApproved, adminID)
+}
+
+// Reject treats an anomaly as a mistake: a held price change is discarded and a held
+// order canceled
+func (s *PricingAnomalyService) Reject(ctx context.Context, id, adminID string) (*PricingAnomaly, error) {
+	anomaly, err := s.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if anomaly.Kind == AnomalyOrderTotal && s.orders != nil {
+		if _, err := s.orders.CancelOrder(ctx, anomaly.OrderID, "pricing mistake"); err != nil && err != orders.ErrInvalidStatus {
+			return nil, err
+		}
+	}
+	return s.review(ctx, anomaly, AnomalyRejected, adminID)
+}
+
+func (s *PricingAnomalyService) pending(ctx context.Context, id string) (*PricingAnomaly, error) {
+	anomaly, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if anomaly.Status != AnomalyPending {
+		return nil, ErrPricingAnomalyReviewed
+	}
+	return anomaly, nil
+}
+
+func (s *PricingAnomalyService) review(ctx context.Context, anomaly *PricingAnomaly, status PricingAnomalyStatus, adminID string) (*PricingAnomaly, error) {
+	now := time.Now()
+	anomaly.Status = status
+	anomaly.ReviewedBy = adminID
+	anomaly.ReviewedAt = &now
+	if err := s.repo.Save(ctx, anomaly); err != nil {
+		return nil, err
+	}
+	return anomaly, nil
+}
+
+// holdPriceChange records a held price change, returning the error the save fails with
+func (s *PricingAnomalyService) holdPriceChange(ctx context.Context, anomaly *PricingAnomaly, change interface{}) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	anomaly.Change = data
+	if err := s.raise(ctx, anomaly); err != nil {
+		return err
+	}
+	return &PriceChangeHeldError{Anomaly: anomaly}
+}
+
+// raise stores a new anomaly and alerts admins. A failed alert doesn't undo the hold,
+// since the anomaly is listed for review either way.
+func (s *PricingAnomalyService) raise(ctx context.Context, anomaly *PricingAnomaly) error {
+	anomaly.ID = utils.GenerateID()
+	anomaly.Status = AnomalyPending
+	anomaly.CreatedAt = time.Now()
+	if err := s.repo.Save(ctx, anomaly); err != nil {
+		return err
+	}
+	if s.notifier != nil {
+		if err := s.notifier.NotifyPricingAnomaly(ctx, anomaly); err != nil {
+			log.Printf("Failed to send pricing alert for anomaly %s: %v", anomaly.ID, err)
+		}
+	}
+	return nil
+}
+
+func approvedPriceChange(ctx context.Context) bool {
+	approved, _ := ctx.Value(approvedPriceChangeKey{}).(bool)
+	return approved
+}
+
+// priceChangeMessage describes a suspicious price change for admins
+func priceChangeMessage(reason PricingAnomalyReason, what string, old *money.Money, updated money.Money) string {
+	if reason == AnomalyPriceZero {
+		return fmt.Sprintf("%s price set to %s", what, updated.String())
+	}
+	return fmt.Sprintf("%s price cut from %s to %s", what, old.String(), updated.String())
+}
+
+// guardedProductRepository holds product saves with suspicious price changes for review
+type guardedProductRepository struct {
+	catalog.ProductRepository
+	anomalies *PricingAnomalyService
+}
+
+func (r *guardedProductRepository) Save(ctx context.Context, product *catalog.Product) error {
+	if approvedPriceChange(ctx) {
+		return r.ProductRepository.Save(ctx, product)
+	}
+	var old *money.Money
+	if before, err := r.ProductRepository.FindByID(ctx, product.ID); err == nil {
+		old = &before.BasePrice
+	}
+	reason, suspicious := r.anomalies.CheckPrice(old, product.BasePrice)
+	if !suspicious {
+		return r.ProductRepository.Save(ctx, product)
+	}
+
+	updated := product.BasePrice
+	return r.anomalies.holdPriceChange(ctx, &PricingAnomaly{
+		Kind:      AnomalyProductPrice,
+		Reason:    reason,
+		ProductID: product.ID,
+		OldPrice:  old,
+		NewPrice:  &updated,
+		Message:   priceChangeMessage(reason, "product "+product.ID, old, updated),
+	}, product)
+}
+
+// guardedVariantRepository holds variant saves with suspicious price changes for review
+type guardedVariantRepository struct {
+	catalog.VariantRepository
+	anomalies *PricingAnomalyService
+}
+
+func (r *guardedVariantRepository) Save(ctx context.Context, variant *catalog.Variant) error {
+	if approvedPriceChange(ctx) {
+		return r.VariantRepository.Save(ctx, variant)
+	}
+	var old *money.Money
+	if before, err := r.VariantRepository.FindByID(ctx, variant.ID); err == nil {
+		old = &before.Price
+	}
+	reason, suspicious := r.anomalies.CheckPrice(old, variant.Price)
+	if !suspicious {
+		return r.VariantRepository.Save(ctx, variant)
+	}
+
+	updated := variant.Price
+	return r.anomalies.holdPriceChange(ctx, &PricingAnomaly{
+		Kind:      AnomalyVariantPrice,
+		Reason:    reason,
+		ProductID: variant.ProductID,
+		VariantID: variant.ID,
+		OldPrice:  old,
+		NewPrice:  &updated,
+		Message:   priceChangeMessage(reason, "variant "+variant.ID, old, updated),
+	}, variant)
+}
+
+// LogPricingAlertNotifier writes pricing alerts to the log, for development or until a
+// mail server is configured
+type LogPricingAlertNotifier struct{}
+
+// NotifyPricingAnomaly logs the alert
+func (LogPricingAlertNotifier) NotifyPricingAnomaly(ctx context.Context, anomaly *PricingAnomaly) error {
+	log.Printf("Pricing anomaly %s held for review: %s", anomaly.ID, anomaly.Message)
+	return nil
+}
+
+// SMTPPricingAlertNotifier emails pricing alerts to admins through an SMTP server
+type SMTPPricingAlertNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewSMTPPricingAlertNotifier creates an SMTPPricingAlertNotifier sending to the given
+// admin addresses. Without a username the server is used without authentication.
+func NewSMTPPricingAlertNotifier(host string, port int, username, password, from string, to []string) *SMTPPricingAlertNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPPricingAlertNotifier{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from, to: to}
+}
+
+// NotifyPricingAnomaly emails the admins about the anomaly
+func (n *SMTPPricingAlertNotifier) NotifyPricingAnomaly(ctx context.Context, anomaly *PricingAnomaly) error {
+	body := fmt.Sprintf("A possible pricing mistake is on hold for review.\r\n\r\n"+
+		"%s\r\n\r\nAnomaly: %s\r\nReason: %s\r\n\r\n"+
+		"Approve or reject it from the admin API: /api/v1/admin/pricing-anomalies/%s\r\n",
+		anomaly.Message, anomaly.ID, anomaly.Reason, anomaly.ID)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Pricing anomaly held for review\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s", n.from, strings.Join(n.to, ", "), body)
+	return smtp.SendMail(n.addr, n.auth, n.from, n.to, []byte(message))
+}
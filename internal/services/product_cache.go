@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ProductCache is an in-memory read-through cache for product detail. Concurrent misses
+// for the same product share a single load, so a hot product that is cold or was just
+// invalidated reaches the database once instead of once per waiting request.
+type ProductCache struct {
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu         sync.RWMutex
+	entries    map[string]productCacheEntry
+	generation uint64 // bumped on every invalidation; loads that straddle one are not stored
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type productCacheEntry struct {
+	product   *ProductResponse
+	expiresAt time.Time
+}
+
+// CacheStats reports cache effectiveness
+type CacheStats struct {
+	Entries int   `json:"entries"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+// NewProductCache creates a cache whose entries live for ttl
+func NewProductCache(ttl time.Duration) *ProductCache {
+	return &ProductCache{
+		ttl:     ttl,
+		entries: make(map[string]productCacheEntry),
+	}
+}
+
+// Get returns the cached product or loads it. Errors are not cached.
+func (c *ProductCache) Get(ctx context.Context, id string, load func(ctx context.Context) (*ProductResponse, error)) (*ProductResponse, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[id]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		c.hits.Add(1)
+		return entry.product, nil
+	}
+	c.misses.Add(1)
+
+	result, err, _ := c.group.Do(id, func() (interface{}, error) {
+		c.mu.RLock()
+		generation := c.generation
+		c.mu.RUnlock()
+
+		// The load is shared, so one caller going away must not fail the others
+		product, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.entries[id] = productCacheEntry{product: product, expiresAt: time.Now().Add(c.ttl)}
+		}
+		c.mu.Unlock()
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ProductResponse), nil
+}
+
+// Invalidate drops products from the cache
+func (c *ProductCache) Invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for _, id := range ids {
+		delete(c.entries, id)
+		c.group.Forget(id)
+	}
+}
+
+// Flush drops every product from the cache
+func (c *ProductCache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for id := range c.entries {
+		c.group.Forget(id)
+	}
+	c.entries = make(map[string]productCacheEntry)
+}
+
+// Stats returns the entry count and hit/miss counters since startup
+func (c *ProductCache) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return CacheStats{
+		Entries: len(c.entries),
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
+
+// listCache holds one full list, such as every category, for ttl. It shares a single
+// load between concurrent misses the same way ProductCache does.
+type listCache[T any] struct {
+	ttl   time.Duration
+	group singleflight.Group
+
+	mu         sync.RWMutex
+	items      []T
+	expiresAt  time.Time
+	generation uint64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newListCache[T any](ttl time.Duration) *listCache[T] {
+	return &listCache[T]{ttl: ttl}
+}
+
+func (c *listCache[T]) get(ctx context.Context, load func(ctx context.Context) ([]T, error)) ([]T, error) {
+	c.mu.RLock()
+	items, expiresAt := c.items, c.expiresAt
+	c.mu.RUnlock()
+	if items != nil && time.Now().Before(expiresAt) {
+		c.hits.Add(1)
+		return items, nil
+	}
+	c.misses.Add(1)
+
+	result, err, _ := c.group.Do("list", func() (interface{}, error) {
+		c.mu.RLock()
+		generation := c.generation
+		c.mu.RUnlock()
+
+		items, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if items == nil {
+			items = []T{}
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.items = items
+			c.expiresAt = time.Now().Add(c.ttl)
+		}
+		c.mu.Unlock()
+		return items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]T), nil
+}
+
+func (c *listCache[T]) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.items = nil
+	c.group.Forget("list")
+}
+
+func (c *listCache[T]) stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return CacheStats{
+		Entries: len(c.items),
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidProductMedia = errors.New("invalid product media")
+
+// maxProductMedia bounds how many media items a product may have
+const maxProductMedia = 50
+
+// MediaType is the kind of a product media item
+type MediaType string
+
+const (
+	MediaImage   MediaType = "image"
+	MediaVideo   MediaType = "video"
+	MediaModel3D MediaType = "model_3d"
+)
+
+// ProductMedia is an image, video or 3D model shown in a product's gallery
+type ProductMedia struct {
+	Type      MediaType `json:"type"`
+	URL       string    `json:"url"`
+	PosterURL string    `json:"poster_url,omitempty"` // still shown before a video or 3D model loads
+	Alt       string    `json:"alt,omitempty"`
+	MimeType  string    `json:"mime_type,omitempty"` // e.g. video/mp4 or model/gltf-binary
+	Position  int       `json:"position"`            // gallery order, from 0
+}
+
+// ProductMediaRepository defines persistence for product media
+type ProductMediaRepository interface {
+	// FindByProduct returns a product's media in gallery order
+	FindByProduct(ctx context.Context, productID string) ([]*ProductMedia, error)
+	// FindByProducts returns the media of several products in gallery order, by product ID.
+	// Products without media are left out.
+	FindByProducts(ctx context.Context, productIDs []string) (map[string][]*ProductMedia, error)
+	// Replace replaces a product's media
+	Replace(ctx context.Context, productID string, media []*ProductMedia) error
+}
+
+// ProductMediaService manages product galleries of images, videos and 3D models
+type ProductMediaService struct {
+	repo      ProductMediaRepository
+	onChanged func(productIDs ...string)
+}
+
+// NewProductMediaService creates a new ProductMediaService
+func NewProductMediaService(repo ProductMediaRepository) *ProductMediaService {
+	return &ProductMediaService{repo: repo}
+}
+
+// WithChangeHook calls fn with the product after its media is replaced, e.g. to drop it
+// from caches
+func (s *ProductMediaService) WithChangeHook(fn func(productIDs ...string)) *ProductMediaService {
+	s.onChanged = fn
+	return s
+}
+
+// GetMedia returns a product's media in gallery order
+func (s *ProductMediaService) GetMedia(ctx context.Context, productID string) ([]*ProductMedia, error) {
+	return s.repo.FindByProduct(ctx, productID)
+}
+
+// FindMedia returns the media of several products by product ID, for product listings
+func (s *ProductMediaService) FindMedia(ctx context.Context, productIDs []string) (map[string][]*ProductMedia, error) {
+	return s.repo.FindByProducts(ctx, productIDs)
+}
+
+// SetMedia validates and replaces a product's media. Items keep the order given, which
+// sets their positions. An empty list clears the gallery.
+func (s *ProductMediaService) SetMedia(ctx context.Context, productID string, media []*ProductMedia) ([]*ProductMedia, error) {
+	if len(media) > maxProductMedia {
+		return nil, fmt.Errorf("%w: at most %d items", ErrInvalidProductMedia, maxProductMedia)
+	}
+	for i, item := range media {
+		if err := validateProductMedia(item); err != nil {
+			return nil, fmt.Errorf("%w: item %d: %v", ErrInvalidProductMedia, i, err)
+		}
+		item.Position = i
+	}
+
+	if err := s.repo.Replace(ctx, productID, media); err != nil {
+		return nil, err
+	}
+	if s.onChanged != nil {
+		s.onChanged(productID)
+	}
+	return media, nil
+}
+
+// ImageMedia describes a product's plain image URLs as image media, for products whose
+// gallery has not been set
+func ImageMedia(images []string) []*ProductMedia {
+	media := make([]*ProductMedia, len(images))
+	for i, image := range images {
+		media[i] = &ProductMedia{Type: MediaImage, URL: image, Position: i}
+	}
+	return media
+}
+
+// validateProductMedia checks an item's type and URLs. Videos and 3D models may have a
+// poster; images may not.
+func validateProductMedia(item *ProductMedia) error {
+	if item == nil {
+		return errors.New("item is empty")
+	}
+	item.Type = MediaType(strings.ToLower(strings.TrimSpace(string(item.Type))))
+	item.MimeType = strings.ToLower(strings.TrimSpace(item.MimeType))
+	switch item.Type {
+	case MediaImage:
+		if item.PosterURL != "" {
+			return errors.New("images cannot have a poster")
+		}
+	case MediaVideo, MediaModel3D:
+	default:
+		return fmt.Errorf("type must be image, video or model_3d, got %q", item.Type)
+	}
+	if !isValidTargetURL(item.URL) {
+		return errors.New("url must be an http(s) URL or a path starting with /")
+	}
+	if item.PosterURL != "" && !isValidTargetURL(item.PosterURL) {
+		return errors.New("poster_url must be an http(s) URL or a path starting with /")
+	}
+	return nil
+}
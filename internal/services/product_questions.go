@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrQuestionNotFound = errors.New("question not found")
+	ErrAnswerNotFound   = errors.New("answer not found")
+	ErrInvalidQuestion  = errors.New("question and answer text is required and at most 2000 characters")
+)
+
+const maxQuestionLength = 2000
+
+// ProductQuestion is a question a customer asked about a product, with the answers other
+// customers and staff gave. Questions are shown as soon as they are asked, with blocked
+// words masked, and hidden once reported often enough.
+type ProductQuestion struct {
+	ID        string          `json:"id"`
+	ProductID string          `json:"product_id"`
+	UserID    string          `json:"user_id"`
+	Body      string          `json:"body"`
+	Filtered  bool            `json:"filtered"` // blocked words were masked
+	Hidden    bool            `json:"hidden"`
+	Answers   []ProductAnswer `json:"answers"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ProductAnswer is an answer to a product question
+type ProductAnswer struct {
+	ID         string    `json:"id"`
+	QuestionID string    `json:"question_id"`
+	UserID     string    `json:"user_id"`
+	Staff      bool      `json:"staff"` // answered on behalf of the store
+	Body       string    `json:"body"`
+	Filtered   bool      `json:"filtered"`
+	Hidden     bool      `json:"hidden"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// QuestionRepository defines persistence for product questions and their answers
+type QuestionRepository interface {
+	// FindByID returns a question with all its answers, oldest first
+	FindByID(ctx context.Context, id string) (*ProductQuestion, error)
+	// FindByProduct returns a product's questions with all their answers, newest first
+	FindByProduct(ctx context.Context, productID string) ([]*ProductQuestion, error)
+	FindAnswerByID(ctx context.Context, id string) (*ProductAnswer, error)
+	// Save creates a question or updates its body and timestamps
+	Save(ctx context.Context, question *ProductQuestion) error
+	AddAnswer(ctx context.Context, answer *ProductAnswer) error
+	SetQuestionHidden(ctx context.Context, id string, hidden bool) error
+	SetAnswerHidden(ctx context.Context, id string, hidden bool) error
+}
+
+// QuestionService lets customers ask questions about products and customers and staff
+// answer them
+type QuestionService struct {
+	repo     QuestionRepository
+	products catalog.ProductRepository
+	filter   *ProfanityFilter
+}
+
+// NewQuestionService creates a new QuestionService masking blocked words with filter
+func NewQuestionService(repo QuestionRepository, products catalog.ProductRepository, filter *ProfanityFilter) *QuestionService {
+	return &QuestionService{repo: repo, products: products, filter: filter}
+}
+
+// AskQuestion adds a customer's question about a product
+func (s *QuestionService) AskQuestion(ctx context.Context, userID, productID, text string) (*ProductQuestion, error) {
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	body, filtered, err := s.cleanText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	question := &ProductQuestion{
+		ID:        utils.GenerateID(),
+		ProductID: productID,
+		UserID:    userID,
+		Body:      body,
+		Filtered:  filtered,
+		Answers:   []ProductAnswer{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Save(ctx, question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// Answer adds an answer to a visible question. Staff answers are marked as the store's.
+func (s *QuestionService) Answer(ctx context.Context, questionID, userID string, staff bool, text string) (*ProductQuestion, error) {
+	question, err := s.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	body, filtered, err := s.cleanText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	answer := ProductAnswer{
+		ID:         utils.GenerateID(),
+		QuestionID: question.ID,
+		UserID:     userID,
+		Staff:      staff,
+		Body:       body,
+		Filtered:   filtered,
+		CreatedAt:  now,
+	}
+	if err := s.repo.AddAnswer(ctx, &answer); err != nil {
+		return nil, err
+	}
+
+	question.UpdatedAt = now
+	if err := s.repo.Save(ctx, question); err != nil {
+		return nil, err
+	}
+	question.Answers = append(question.Answers, answer)
+	return question, nil
+}
+
+// GetQuestion returns a visible question with its visible answers
+func (s *QuestionService) GetQuestion(ctx context.Context, id string) (*ProductQuestion, error) {
+	question, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if question.Hidden {
+		return nil, ErrQuestionNotFound
+	}
+	question.Answers = visibleAnswers(question.Answers)
+	return question, nil
+}
+
+// ProductQuestions returns a product's visible questions with their visible answers,
+// newest first
+func (s *QuestionService) ProductQuestions(ctx context.Context, productID string) ([]*ProductQuestion, error) {
+	questions, err := s.repo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*ProductQuestion, 0, len(questions))
+	for _, question := range questions {
+		if question.Hidden {
+			continue
+		}
+		question.Answers = visibleAnswers(question.Answers)
+		visible = append(visible, question)
+	}
+	return visible, nil
+}
+
+// cleanText trims question or answer text, checks its length and masks blocked words
+func (s *QuestionService) cleanText(text string) (string, bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxQuestionLength {
+		return "", false, ErrInvalidQuestion
+	}
+	body, filtered := s.filter.Clean(text)
+	return body, filtered, nil
+}
+
+// visibleAnswers drops hidden answers
+func visibleAnswers(answers []ProductAnswer) []ProductAnswer {
+	visible := make([]ProductAnswer, 0, len(answers))
+	for _, answer := range answers {
+		if !answer.Hidden {
+			visible = append(visible, answer)
+		}
+	}
+	return visible
+}
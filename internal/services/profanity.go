@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultBlockedWords are masked in reviews and questions whatever the configuration adds
+var defaultBlockedWords = []string{
+	"asshole", "bastard", "bitch", "bullshit", "cunt", "dick", "fuck", "fucked", "fucker",
+	"fucking", "motherfucker", "piss", "shit", "shitty", "slut", "twat", "wanker", "whore",
+}
+
+// ProfanityFilter masks blocked words in text customers post. Words are matched whole and
+// case-insensitively, so "Scunthorpe" and "classic" pass untouched.
+type ProfanityFilter struct {
+	words map[string]struct{}
+}
+
+// NewProfanityFilter creates a ProfanityFilter blocking the default words and extra
+func NewProfanityFilter(extra []string) *ProfanityFilter {
+	words := make(map[string]struct{}, len(defaultBlockedWords)+len(extra))
+	for _, word := range append(append([]string{}, defaultBlockedWords...), extra...) {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words[word] = struct{}{}
+		}
+	}
+	return &ProfanityFilter{words: words}
+}
+
+// Clean replaces each blocked word with asterisks of the same length and reports whether
+// anything was masked
+func (f *ProfanityFilter) Clean(text string) (string, bool) {
+	var b strings.Builder
+	masked := false
+	word := -1 // byte offset where the current word starts
+	flush := func(end int) {
+		if word < 0 {
+			return
+		}
+		if _, blocked := f.words[strings.ToLower(text[word:end])]; blocked {
+			b.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[word:end])))
+			masked = true
+		} else {
+			b.WriteString(text[word:end])
+		}
+		word = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			if word < 0 {
+				word = i
+			}
+			continue
+		}
+		flush(i)
+		b.WriteRune(r)
+	}
+	flush(len(text))
+	return b.String(), masked
+}
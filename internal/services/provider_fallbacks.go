@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/payments"
+	"github.com/devchuckcamp/gocommerce/shipping"
+	"github.com/devchuckcamp/gocommerce/tax"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+)
+
+// FlatRateShipping quotes the same cost for every destination and method. It is the
+// fallback while the shipping provider is unavailable.
+type FlatRateShipping struct {
+	cost money.Money
+}
+
+// NewFlatRateShipping creates a calculator that always charges cost
+func NewFlatRateShipping(cost money.Money) *FlatRateShipping {
+	return &FlatRateShipping{cost: cost}
+}
+
+// GetRate quotes the flat cost for the requested method
+func (f *FlatRateShipping) GetRate(ctx context.Context, req shipping.RateRequest) (*shipping.ShippingRate, error) {
+	methodID := req.ShippingMethodID
+	if methodID == "" {
+		methodID = "standard"
+	}
+	return &shipping.ShippingRate{
+		MethodID:   methodID,
+		MethodName: "Standard Shipping",
+		Cost:       f.cost,
+	}, nil
+}
+
+// GetAvailableRates offers a single flat-rate method
+func (f *FlatRateShipping) GetAvailableRates(ctx context.Context, req shipping.RateRequest) ([]*shipping.ShippingRate, error) {
+	rate, _ := f.GetRate(ctx, shipping.RateRequest{})
+	return []*shipping.ShippingRate{rate}, nil
+}
+
+// FallbackRateCalculator calls the shipping provider through a circuit breaker and quotes
+// from the fallback while the provider is failing
+type FallbackRateCalculator struct {
+	primary  shipping.RateCalculator
+	fallback shipping.RateCalculator
+	breaker  *breaker.Breaker
+	retry    retry.Policy
+}
+
+// NewFallbackRateCalculator creates a new FallbackRateCalculator
+func NewFallbackRateCalculator(primary, fallback shipping.RateCalculator, b *breaker.Breaker) *FallbackRateCalculator {
+	return &FallbackRateCalculator{
+		primary:  primary,
+		fallback: fallback,
+		breaker:  b,
+	}
+}
+
+// WithRetry retries failed provider calls before the breaker counts a failure.
+// Provider answers the breaker ignores are not retried.
+func (c *FallbackRateCalculator) WithRetry(policy retry.Policy) *FallbackRateCalculator {
+	c.retry = retryProviderFailures(policy, c.breaker)
+	return c
+}
+
+// GetRate implements shipping.RateCalculator
+func (c *FallbackRateCalculator) GetRate(ctx context.Context, req shipping.RateRequest) (*shipping.ShippingRate, error) {
+	rate, err := breaker.Call(ctx, c.breaker, func(ctx context.Context) (*shipping.ShippingRate, error) {
+		return retry.Call(ctx, c.retry, func(ctx context.Context) (*shipping.ShippingRate, error) {
+			return c.primary.GetRate(ctx, req)
+		})
+	})
+	if c.breaker.Failed(err) && c.fallback != nil {
+		log.Printf("Shipping provider unavailable, using fallback rate: %v", err)
+		return c.fallback.GetRate(ctx, req)
+	}
+	return rate, err
+}
+
+// GetAvailableRates implements shipping.RateCalculator
+func (c *FallbackRateCalculator) GetAvailableRates(ctx context.Context, req shipping.RateRequest) ([]*shipping.ShippingRate, error) {
+	rates, err := breaker.Call(ctx, c.breaker, func(ctx context.Context) ([]*shipping.ShippingRate, error) {
+		return retry.Call(ctx, c.retry, func(ctx context.Context) ([]*shipping.ShippingRate, error) {
+			return c.primary.GetAvailableRates(ctx, req)
+		})
+	})
+	if c.breaker.Failed(err) && c.fallback != nil {
+		log.Printf("Shipping provider unavailable, using fallback rates: %v", err)
+		return c.fallback.GetAvailableRates(ctx, req)
+	}
+	return rates, err
+}
+
+// FallbackTaxCalculator calls the tax provider through a circuit breaker and calculates
+// with the fallback while the provider is failing
+type FallbackTaxCalculator struct {
+	primary  tax.Calculator
+	fallback tax.Calculator
+	breaker  *breaker.Breaker
+	retry    retry.Policy
+}
+
+// NewFallbackTaxCalculator creates a new FallbackTaxCalculator
+func NewFallbackTaxCalculator(primary, fallback tax.Calculator, b *breaker.Breaker) *FallbackTaxCalculator {
+	return &FallbackTaxCalculator{
+		primary:  primary,
+		fallback: fallback,
+		breaker:  b,
+	}
+}
+
+// WithRetry retries failed provider calls before the breaker counts a failure
+func (c *FallbackTaxCalculator) WithRetry(policy retry.Policy) *FallbackTaxCalculator {
+	c.retry = retryProviderFailures(policy, c.breaker)
+	return c
+}
+
+// Calculate implements tax.Calculator
+func (c *FallbackTaxCalculator) Calculate(ctx context.Context, req tax.CalculationRequest) (*tax.CalculationResult, error) {
+	result, err := breaker.Call(ctx, c.breaker, func(ctx context.Context) (*tax.CalculationResult, error) {
+		return retry.Call(ctx, c.retry, func(ctx context.Context) (*tax.CalculationResult, error) {
+			return c.primary.Calculate(ctx, req)
+		})
+	})
+	if c.breaker.Failed(err) && c.fallback != nil {
+		log.Printf("Tax provider unavailable, using fallback calculator: %v", err)
+		return c.fallback.Calculate(ctx, req)
+	}
+	return result, err
+}
+
+// GetRatesForAddress implements tax.Calculator
+func (c *FallbackTaxCalculator) GetRatesForAddress(ctx context.Context, address tax.Address) ([]tax.TaxRate, error) {
+	rates, err := breaker.Call(ctx, c.breaker, func(ctx context.Context) ([]tax.TaxRate, error) {
+		return retry.Call(ctx, c.retry, func(ctx context.Context) ([]tax.TaxRate, error) {
+			return c.primary.GetRatesForAddress(ctx, address)
+		})
+	})
+	if c.breaker.Failed(err) && c.fallback != nil {
+		return c.fallback.GetRatesForAddress(ctx, address)
+	}
+	return rates, err
+}
+
+// BreakerGateway calls a payment gateway through a circuit breaker. There is no fallback
+// gateway: while the breaker is open calls fail with breaker.ErrOpen, and PaymentService
+// leaves card tenders pending for later capture instead of declining them.
+type BreakerGateway struct {
+	gateway payments.Gateway
+	breaker *breaker.Breaker
+	retry   retry.Policy
+}
+
+// NewBreakerGateway creates a new BreakerGateway
+func NewBreakerGateway(gateway payments.Gateway, b *breaker.Breaker) *BreakerGateway {
+	return &BreakerGateway{
+		gateway: gateway,
+		breaker: b,
+	}
+}
+
+// WithRetry retries failed lookups and cancellations. Creating intents and refunds and
+// capturing are never retried: the gateway may have acted on a call whose response was
+// lost, and a second attempt could charge or refund twice.
+func (g *BreakerGateway) WithRetry(policy retry.Policy) *BreakerGateway {
+	g.retry = retryProviderFailures(policy, g.breaker)
+	return g
+}
+
+// CreateIntent implements payments.Gateway
+func (g *BreakerGateway) CreateIntent(ctx context.Context, req payments.IntentRequest) (*payments.PaymentIntent, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.PaymentIntent, error) {
+		return g.gateway.CreateIntent(ctx, req)
+	})
+}
+
+// GetIntent implements payments.Gateway
+func (g *BreakerGateway) GetIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.PaymentIntent, error) {
+		return retry.Call(ctx, g.retry, func(ctx context.Context) (*payments.PaymentIntent, error) {
+			return g.gateway.GetIntent(ctx, intentID)
+		})
+	})
+}
+
+// CaptureIntent implements payments.Gateway
+func (g *BreakerGateway) CaptureIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.PaymentIntent, error) {
+		return g.gateway.CaptureIntent(ctx, intentID)
+	})
+}
+
+// CancelIntent implements payments.Gateway
+func (g *BreakerGateway) CancelIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.PaymentIntent, error) {
+		return retry.Call(ctx, g.retry, func(ctx context.Context) (*payments.PaymentIntent, error) {
+			return g.gateway.CancelIntent(ctx, intentID)
+		})
+	})
+}
+
+// CreateRefund implements payments.Gateway
+func (g *BreakerGateway) CreateRefund(ctx context.Context, req payments.RefundRequest) (*payments.Refund, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.Refund, error) {
+		return g.gateway.CreateRefund(ctx, req)
+	})
+}
+
+// GetRefund implements payments.Gateway
+func (g *BreakerGateway) GetRefund(ctx context.Context, refundID string) (*payments.Refund, error) {
+	return breaker.Call(ctx, g.breaker, func(ctx context.Context) (*payments.Refund, error) {
+		return retry.Call(ctx, g.retry, func(ctx context.Context) (*payments.Refund, error) {
+			return g.gateway.GetRefund(ctx, refundID)
+		})
+	})
+}
+
+// retryProviderFailures limits a retry policy to the errors the breaker counts as failures
+func retryProviderFailures(policy retry.Policy, b *breaker.Breaker) retry.Policy {
+	if policy.Retryable == nil {
+		policy.Retryable = b.Failed
+	}
+	return policy
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+var (
+	ErrPurchaseLimitNotFound = errors.New("purchase limit not found")
+	ErrInvalidPurchaseLimit  = errors.New("purchase limit requires a max per order or a max per customer, and no negative values")
+)
+
+// PurchaseLimit caps how many units of a product a customer can buy, to keep limited
+// drops from being bought up by a few buyers
+type PurchaseLimit struct {
+	ProductID      string    `json:"product_id"`
+	MaxPerOrder    int       `json:"max_per_order,omitempty"`    // 0 for no per-order cap
+	MaxPerCustomer int       `json:"max_per_customer,omitempty"` // 0 for no per-customer cap
+	PeriodDays     int       `json:"period_days,omitempty"`      // window for MaxPerCustomer; 0 counts every past order
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PurchaseLimitError reports a quantity over a product's purchase limit
+type PurchaseLimitError struct {
+	ProductID   string
+	Limit       int
+	Remaining   int  // units the customer can still add
+	PerCustomer bool // false for the per-order limit
+	PeriodDays  int
+}
+
+func (e *PurchaseLimitError) Error() string {
+	if !e.PerCustomer {
+		return fmt.Sprintf("limit of %d per order for this product", e.Limit)
+	}
+	if e.PeriodDays > 0 {
+		return fmt.Sprintf("limit of %d per customer every %d days for this product", e.Limit, e.PeriodDays)
+	}
+	return fmt.Sprintf("limit of %d per customer for this product", e.Limit)
+}
+
+// PurchaseLimitRepository defines persistence for purchase limits
+type PurchaseLimitRepository interface {
+	FindByProductID(ctx context.Context, productID string) (*PurchaseLimit, error)
+	FindByProductIDs(ctx context.Context, productIDs []string) ([]*PurchaseLimit, error)
+	FindAll(ctx context.Context) ([]*PurchaseLimit, error)
+	Save(ctx context.Context, limit *PurchaseLimit) error
+	Delete(ctx context.Context, productID string) error
+}
+
+// PurchaseLimitService enforces per-order and per-customer purchase limits
+type PurchaseLimitService struct {
+	repo      PurchaseLimitRepository
+	orderRepo orders.Repository
+}
+
+// NewPurchaseLimitService creates a new PurchaseLimitService. Past orders are read from
+// orderRepo to count what a customer has already bought.
+func NewPurchaseLimitService(repo PurchaseLimitRepository, orderRepo orders.Repository) *PurchaseLimitService {
+	return &PurchaseLimitService{repo: repo, orderRepo: orderRepo}
+}
+
+// Check verifies that a customer may buy the given units per product in one order.
+// Guests have no order history, so only per-order limits apply to them.
+func (s *PurchaseLimitService) Check(ctx context.Context, userID string, quantities map[string]int) error {
+	productIDs := make([]string, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	limits, err := s.repo.FindByProductIDs(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	var history []*orders.Order
+	historyLoaded := false
+	for _, limit := range limits {
+		quantity := quantities[limit.ProductID]
+		if limit.MaxPerOrder > 0 && quantity > limit.MaxPerOrder {
+			return &PurchaseLimitError{ProductID: limit.ProductID, Limit: limit.MaxPerOrder, Remaining: limit.MaxPerOrder}
+		}
+		if limit.MaxPerCustomer == 0 || userID == "" {
+			continue
+		}
+
+		if !historyLoaded {
+			if history, err = s.orderRepo.FindByUserID(ctx, userID, orders.OrderFilter{}); err != nil {
+				return err
+			}
+			historyLoaded = true
+		}
+		remaining := limit.MaxPerCustomer - purchasedUnits(history, limit)
+		if quantity > remaining {
+			if remaining < 0 {
+				remaining = 0
+			}
+			return &PurchaseLimitError{
+				ProductID:   limit.ProductID,
+				Limit:       limit.MaxPerCustomer,
+				Remaining:   remaining,
+				PerCustomer: true,
+				PeriodDays:  limit.PeriodDays,
+			}
+		}
+	}
+	return nil
+}
+
+// CheckCart verifies a cart's quantities against the limits of the products in it
+func (s *PurchaseLimitService) CheckCart(ctx context.Context, userID string, c *cart.Cart) error {
+	return s.Check(ctx, userID, CartQuantities(c))
+}
+
+// GetLimit returns a product's purchase limit
+func (s *PurchaseLimitService) GetLimit(ctx context.Context, productID string) (*PurchaseLimit, error) {
+	return s.repo.FindByProductID(ctx, productID)
+}
+
+// ListLimits returns every purchase limit
+func (s *PurchaseLimitService) ListLimits(ctx context.Context) ([]*PurchaseLimit, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// SetLimit validates and saves a product's purchase limit, replacing any existing one
+func (s *PurchaseLimitService) SetLimit(ctx context.Context, limit *PurchaseLimit) error {
+	if limit.ProductID == "" || limit.MaxPerOrder < 0 || limit.MaxPerCustomer < 0 || limit.PeriodDays < 0 {
+		return ErrInvalidPurchaseLimit
+	}
+	if limit.MaxPerOrder == 0 && limit.MaxPerCustomer == 0 {
+		return ErrInvalidPurchaseLimit
+	}
+	limit.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, limit)
+}
+
+// RemoveLimit removes a product's purchase limit
+func (s *PurchaseLimitService) RemoveLimit(ctx context.Context, productID string) error {
+	return s.repo.Delete(ctx, productID)
+}
+
+// CartQuantities totals a cart's units per product, across all of a product's variants
+func CartQuantities(c *cart.Cart) map[string]int {
+	quantities := make(map[string]int)
+	if c == nil {
+		return quantities
+	}
+	for _, item := range c.Items {
+		quantities[item.ProductID] += item.Quantity
+	}
+	return quantities
+}
+
+// purchasedUnits counts the units of the limited product in orders placed within its
+// period. Canceled and refunded orders don't count.
+func purchasedUnits(history []*orders.Order, limit *PurchaseLimit) int {
+	var since time.Time
+	if limit.PeriodDays > 0 {
+		since = time.Now().AddDate(0, 0, -limit.PeriodDays)
+	}
+
+	units := 0
+	for _, order := range history {
+		if order.Status == orders.OrderStatusCanceled || order.Status == orders.OrderStatusRefunded {
+			continue
+		}
+		if order.CreatedAt.Before(since) {
+			continue
+		}
+		for _, item := range order.Items {
+			if item.ProductID == limit.ProductID {
+				units += item.Quantity
+			}
+		}
+	}
+	return units
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrQuotaExceeded      = errors.New("request quota exceeded")
+	ErrUnknownQuotaTier   = errors.New("quota tier is not configured")
+	ErrInvalidQuotaPeriod = errors.New("period must be day or month")
+)
+
+// QuotaTierAPIKey is the tier of API keys that haven't been put on another tier
+const QuotaTierAPIKey = "api_key"
+
+// QuotaPeriod is the window a quota counts requests over; windows start at midnight UTC
+type QuotaPeriod string
+
+const (
+	QuotaPeriodDay   QuotaPeriod = "day"
+	QuotaPeriodMonth QuotaPeriod = "month"
+)
+
+// start returns the start of the window containing t
+func (p QuotaPeriod) start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == QuotaPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// end returns when the window starting at start resets
+func (p QuotaPeriod) end(start time.Time) time.Time {
+	if p == QuotaPeriodMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// QuotaLimits are the requests a tier may make per day and per month; 0 is unlimited
+type QuotaLimits struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+func (l QuotaLimits) limit(period QuotaPeriod) int64 {
+	if period == QuotaPeriodMonth {
+		return l.Monthly
+	}
+	return l.Daily
+}
+
+// unlimited reports whether the limits allow any number of requests
+func (l QuotaLimits) unlimited() bool {
+	return l.Daily <= 0 && l.Monthly <= 0
+}
+
+// moreGenerous reports whether l allows more requests than other, counting 0 as unlimited
+func (l QuotaLimits) moreGenerous(other QuotaLimits) bool {
+	above := func(a, b int64) bool { return (a <= 0 && b > 0) || (a > 0 && b > 0 && a > b) }
+	if l.Daily != other.Daily {
+		return above(l.Daily, other.Daily)
+	}
+	return above(l.Monthly, other.Monthly)
+}
+
+// ParseQuotaTiers parses tier:daily:monthly entries such as partner:100000:2000000 into
+// limits by tier name. Tiers are user roles, or api_key for API keys.
+func ParseQuotaTiers(entries []string) (map[string]QuotaLimits, error) {
+	tiers := make(map[string]QuotaLimits, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("quota tier %q must be tier:daily:monthly", entry)
+		}
+		tier := strings.ToLower(strings.TrimSpace(parts[0]))
+		daily, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || daily < 0 {
+			return nil, fmt.Errorf("daily quota for %s must be a whole number, 0 for unlimited", tier)
+		}
+		monthly, err := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err != nil || monthly < 0 {
+			return nil, fmt.Errorf("monthly quota for %s must be a whole number, 0 for unlimited", tier)
+		}
+		if _, dup := tiers[tier]; dup {
+			return nil, fmt.Errorf("quota tier %s is given twice", tier)
+		}
+		tiers[tier] = QuotaLimits{Daily: daily, Monthly: monthly}
+	}
+	return tiers, nil
+}
+
+// QuotaCounter is the number of requests a subject made in one window
+type QuotaCounter struct {
+	Subject     string      `json:"subject"` // api_key:<id> or user:<id>
+	Tier        string      `json:"tier"`    // the tier of the subject's latest request
+	Period      QuotaPeriod `json:"period"`
+	PeriodStart time.Time   `json:"period_start"`
+	Requests    int64       `json:"requests"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// QuotaUsage is a subject's use of its quota in the current window
+type QuotaUsage struct {
+	Period    QuotaPeriod `json:"period"`
+	Requests  int64       `json:"requests"`
+	Limit     int64       `json:"limit"` // 0 is unlimited
+	Remaining int64       `json:"remaining"`
+	ResetsAt  time.Time   `json:"resets_at"`
+}
+
+// QuotaStatus is a subject's tier and its usage in the current day and month
+type QuotaStatus struct {
+	Subject string       `json:"subject"`
+	Tier    string       `json:"tier"`
+	Usage   []QuotaUsage `json:"usage"`
+}
+
+// Binding returns the limited window with the fewest requests remaining, the one the
+// quota headers describe, or nil when no window is limited
+func (s *QuotaStatus) Binding() *QuotaUsage {
+	var binding *QuotaUsage
+	for i := range s.Usage {
+		usage := &s.Usage[i]
+		if usage.Limit <= 0 {
+			continue
+		}
+		if binding == nil || usage.Remaining < binding.Remaining ||
+			(usage.Remaining == binding.Remaining && usage.ResetsAt.After(binding.ResetsAt)) {
+			binding = usage
+		}
+	}
+	return binding
+}
+
+// Exceeded reports whether a window has more requests than its limit
+func (s *QuotaStatus) Exceeded() bool {
+	for _, usage := range s.Usage {
+		if usage.Limit > 0 && usage.Requests > usage.Limit {
+			return true
+		}
+	}
+	return false
+}
+
+// QuotaRepository defines persistence for quota counters
+type QuotaRepository interface {
+	// Increment adds one to a subject's counter for the window starting at start, in one
+	// statement so concurrent requests are all counted, and returns the new count
+	Increment(ctx context.Context, subject, tier string, period QuotaPeriod, start time.Time) (int64, error)
+	// FindBySubject returns a subject's counters for the windows starting at or after since
+	FindBySubject(ctx context.Context, subject string, since time.Time) ([]*QuotaCounter, error)
+	// ListByPeriod lists the counters of one window, most requests first
+	ListByPeriod(ctx context.Context, period QuotaPeriod, start time.Time, limit, offset int) ([]*QuotaCounter, error)
+	CountByPeriod(ctx context.Context, period QuotaPeriod, start time.Time) (int64, error)
+	// DeleteBySubject deletes a subject's counters, returning how many there were
+	DeleteBySubject(ctx context.Context, subject string) (int64, error)
+	// DeleteBefore deletes counters for windows starting before before
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// QuotaService counts the requests of API keys and signed-in users against daily and
+// monthly quotas set per tier. Beyond the burst protection of the bot guard, quotas cap
+// how much a partner integration or a tier of users may use the API over time.
+type QuotaService struct {
+	repo    QuotaRepository
+	tiers   map[string]QuotaLimits
+	apiKeys *APIKeyService
+}
+
+// NewQuotaService creates a new QuotaService. tiers holds the limits of user roles and of
+// api_key, the tier of API keys not put on another one.
+func NewQuotaService(repo QuotaRepository, tiers map[string]QuotaLimits) *QuotaService {
+	return &QuotaService{
+		repo:  repo,
+		tiers: tiers,
+	}
+}
+
+// WithAPIKeys lets admins put API keys on a tier and see their usage before their first
+// request
+func (s *QuotaService) WithAPIKeys(apiKeys *APIKeyService) *QuotaService {
+	s.apiKeys = apiKeys
+	return s
+}
+
+// Tiers returns the configured limits by tier
+func (s *QuotaService) Tiers() map[string]QuotaLimits {
+	tiers := make(map[string]QuotaLimits, len(s.tiers))
+	for name, limits := range s.tiers {
+		tiers[name] = limits
+	}
+	return tiers
+}
+
+// HasTier reports whether a tier is configured
+func (s *QuotaService) HasTier(tier string) bool {
+	_, ok := s.tiers[tier]
+	return ok
+}
+
+// APIKeyQuotaSubject is the quota subject of an API key
+func APIKeyQuotaSubject(keyID string) string {
+	return "api_key:" + keyID
+}
+
+// UserQuotaSubject is the quota subject of a signed-in user
+func UserQuotaSubject(userID string) string {
+	return "user:" + userID
+}
+
+// ConsumeAPIKey counts a request by an API key. Keys are on their own tier when it is
+// configured, otherwise on the api_key tier. The status is nil when the key's tier is
+// unlimited; ErrQuotaExceeded is returned with the status once a limit is passed.
+func (s *QuotaService) ConsumeAPIKey(ctx context.Context, key *APIKey) (*QuotaStatus, error) {
+	return s.consume(ctx, APIKeyQuotaSubject(key.ID), s.apiKeyTier(key))
+}
+
+// ConsumeUser counts a request by a signed-in user. Users are on the most generous tier
+// among their roles; users with no role that has a tier are not limited.
+func (s *QuotaService) ConsumeUser(ctx context.Context, userID string, roles []string) (*QuotaStatus, error) {
+	tier := ""
+	for _, role := range roles {
+		role = strings.ToLower(role)
+		limits, ok := s.tiers[role]
+		if !ok {
+			continue
+		}
+		if tier == "" || limits.moreGenerous(s.tiers[tier]) {
+			tier = role
+		}
+	}
+	if tier == "" {
+		return nil, nil
+	}
+	return s.consume(ctx, UserQuotaSubject(userID), tier)
+}
+
+func (s *QuotaService) consume(ctx context.Context, subject, tier string) (*QuotaStatus, error) {
+	limits := s.tiers[tier]
+	if limits.unlimited() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	status := &QuotaStatus{Subject: subject, Tier: tier}
+	for _, period := range []QuotaPeriod{QuotaPeriodDay, QuotaPeriodMonth} {
+		start := period.start(now)
+		requests, err := s.repo.Increment(ctx, subject, tier, period, start)
+		if err != nil {
+			return nil, err
+		}
+		status.Usage = append(status.Usage, newQuotaUsage(period, start, requests, limits.limit(period)))
+	}
+	if status.Exceeded() {
+		return status, ErrQuotaExceeded
+	}
+	return status, nil
+}
+
+// APIKeyUsage returns an API key's usage in the current day and month
+func (s *QuotaService) APIKeyUsage(ctx context.Context, keyID string) (*QuotaStatus, error) {
+	key, err := s.apiKeys.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.usage(ctx, APIKeyQuotaSubject(key.ID), s.apiKeyTier(key))
+}
+
+// UserUsage returns a user's usage in the current day and month, on the tier of their
+// latest request
+func (s *QuotaService) UserUsage(ctx context.Context, userID string) (*QuotaStatus, error) {
+	return s.usage(ctx, UserQuotaSubject(userID), "")
+}
+
+// SetAPIKeyTier puts an API key on a configured tier, or back on the api_key tier with ""
+func (s *QuotaService) SetAPIKeyTier(ctx context.Context, keyID, tier string) (*APIKey, error) {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == QuotaTierAPIKey {
+		tier = ""
+	}
+	if tier != "" && !s.HasTier(tier) {
+		return nil, ErrUnknownQuotaTier
+	}
+	return s.apiKeys.SetQuotaTier(ctx, keyID, tier)
+}
+
+// apiKeyTier is the tier an API key's requests count against
+func (s *QuotaService) apiKeyTier(key *APIKey) string {
+	if key.QuotaTier != "" && s.HasTier(key.QuotaTier) {
+		return key.QuotaTier
+	}
+	return QuotaTierAPIKey
+}
+
+// usage reads a subject's counters for the current day and month. Without a tier, the
+// tier of the subject's latest request is used.
+func (s *QuotaService) usage(ctx context.Context, subject, tier string) (*QuotaStatus, error) {
+	now := time.Now()
+	counters, err := s.repo.FindBySubject(ctx, subject, QuotaPeriodMonth.start(now))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &QuotaStatus{Subject: subject, Tier: tier, Usage: []QuotaUsage{}}
+	requests := make(map[QuotaPeriod]int64)
+	var latest time.Time
+	for _, counter := range counters {
+		if counter.PeriodStart.Equal(counter.Period.start(now)) {
+			requests[counter.Period] = counter.Requests
+		}
+		if tier == "" && counter.UpdatedAt.After(latest) {
+			latest = counter.UpdatedAt
+			status.Tier = counter.Tier
+		}
+	}
+
+	limits := s.tiers[status.Tier]
+	for _, period := range []QuotaPeriod{QuotaPeriodDay, QuotaPeriodMonth} {
+		status.Usage = append(status.Usage, newQuotaUsage(period, period.start(now), requests[period], limits.limit(period)))
+	}
+	return status, nil
+}
+
+// ListUsage lists the counters of the current day or month, most requests first
+func (s *QuotaService) ListUsage(ctx context.Context, period QuotaPeriod, limit, offset int) ([]*QuotaCounter, int64, error) {
+	if period != QuotaPeriodDay && period != QuotaPeriodMonth {
+		return nil, 0, ErrInvalidQuotaPeriod
+	}
+	start := period.start(time.Now())
+	counters, err := s.repo.ListByPeriod(ctx, period, start, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountByPeriod(ctx, period, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	return counters, total, nil
+}
+
+// Reset clears a subject's usage, giving it its full quota again
+func (s *QuotaService) Reset(ctx context.Context, subject string) error {
+	_, err := s.repo.DeleteBySubject(ctx, subject)
+	return err
+}
+
+// Prune deletes counters from before the previous month, returning how many there were.
+// The previous month's are kept so last month's usage can still be listed.
+func (s *QuotaService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, QuotaPeriodMonth.start(time.Now()).AddDate(0, -1, 0))
+}
+
+func newQuotaUsage(period QuotaPeriod, start time.Time, requests, limit int64) QuotaUsage {
+	usage := QuotaUsage{
+		Period:   period,
+		Requests: requests,
+		Limit:    limit,
+		ResetsAt: period.end(start),
+	}
+	if limit > 0 {
+		usage.Remaining = max(limit-requests, 0)
+	}
+	return usage
+}
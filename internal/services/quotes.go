@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrQuoteNotFound     = errors.New("quote not found")
+	ErrEmptyQuoteRequest = errors.New("cart is empty")
+	ErrInvalidQuoteNote  = errors.New("quote note must be at most 2000 characters")
+	ErrInvalidQuoteOffer = errors.New("quoted prices must be non-negative, for items on the quote, and valid until a future time")
+	ErrQuoteNotOpen      = errors.New("quote is no longer open")
+	ErrQuoteNotSent      = errors.New("quote has not been priced yet")
+	ErrQuoteExpired      = errors.New("quote has expired")
+)
+
+const maxQuoteNoteLength = 2000
+
+// QuoteStatus tracks a quote from the buyer's request to their answer
+type QuoteStatus string
+
+const (
+	QuoteStatusRequested QuoteStatus = "requested" // waiting for sales to price it
+	QuoteStatusSent      QuoteStatus = "sent"      // priced and waiting for the buyer
+	QuoteStatusAccepted  QuoteStatus = "accepted"  // turned into an order
+	QuoteStatusDeclined  QuoteStatus = "declined"
+)
+
+// IsOpen reports whether the quote can still be priced, accepted or declined
+func (s QuoteStatus) IsOpen() bool {
+	return s == QuoteStatusRequested || s == QuoteStatusSent
+}
+
+// Quote is a buyer's request for negotiated prices on a cart's items
+type Quote struct {
+	ID         string      `json:"id"`
+	UserID     string      `json:"user_id"`
+	Status     QuoteStatus `json:"status"`
+	Items      []QuoteItem `json:"items"`
+	Subtotal   money.Money `json:"subtotal"`             // at the quoted prices
+	Note       string      `json:"note,omitempty"`       // from the buyer
+	SalesNote  string      `json:"sales_note,omitempty"` // from sales, sent with the prices
+	ValidUntil *time.Time  `json:"valid_until,omitempty"`
+	SentBy     string      `json:"sent_by,omitempty"`
+	SentAt     *time.Time  `json:"sent_at,omitempty"`
+	OrderID    string      `json:"order_id,omitempty"` // set once accepted
+	AcceptedAt *time.Time  `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// IsExpired reports whether a sent quote is past its validity
+func (q *Quote) IsExpired(now time.Time) bool {
+	return q.ValidUntil != nil && now.After(*q.ValidUntil)
+}
+
+// QuoteItem is a cart line on a quote. ListPrice is the cart price when the quote was
+// requested; QuotedPrice is what sales offers and starts out equal to it.
+type QuoteItem struct {
+	ID          string            `json:"id"`
+	ProductID   string            `json:"product_id"`
+	VariantID   *string           `json:"variant_id,omitempty"`
+	SKU         string            `json:"sku"`
+	Name        string            `json:"name"`
+	Quantity    int               `json:"quantity"`
+	ListPrice   money.Money       `json:"list_price"`
+	QuotedPrice money.Money       `json:"quoted_price"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+// QuoteOffer is what sales sends back on a quote
+type QuoteOffer struct {
+	Prices     map[string]int64 // unit prices in cents keyed by quote item ID; unlisted items keep their price
+	Note       string
+	ValidUntil *time.Time // defaults to the service's validity from now
+}
+
+// QuoteSortFields are the fields quote lists can be sorted by
+var QuoteSortFields = []string{"created_at", "updated_at", "status", "subtotal", "valid_until"}
+
+// QuoteFilter filters quotes
+type QuoteFilter struct {
+	UserID string
+	Status QuoteStatus
+	Sort   Sort
+	Limit  int
+	Offset int
+}
+
+// QuoteRepository defines persistence for quotes and their items
+type QuoteRepository interface {
+	FindByID(ctx context.Context, id string) (*Quote, error)
+	// List lists quotes, newest first
+	List(ctx context.Context, filter QuoteFilter) ([]*Quote, error)
+	// Save saves a quote with its items
+	Save(ctx context.Context, quote *Quote) error
+	// MarkAccepted records the order a sent quote became, returning ErrQuoteNotOpen when
+	// the quote is no longer sent so it can't be accepted twice
+	MarkAccepted(ctx context.Context, id, orderID string, acceptedAt time.Time) error
+}
+
+// QuoteService runs the B2B quote flow: a buyer submits their cart as a quote request,
+// sales adjusts the prices and sends it back, and the buyer accepts it by checking out
+// with the quote, which places the order at the quoted prices.
+type QuoteService struct {
+	repo     QuoteRepository
+	validity time.Duration
+}
+
+// NewQuoteService creates a new QuoteService. validity is how long sent quotes stay open
+// when sales doesn't set a date.
+func NewQuoteService(repo QuoteRepository, validity time.Duration) *QuoteService {
+	return &QuoteService{
+		repo:     repo,
+		validity: validity,
+	}
+}
+
+// RequestQuote submits a cart's items for pricing. The cart is left as is.
+func (s *QuoteService) RequestQuote(ctx context.Context, userID string, c *cart.Cart, note string) (*Quote, error) {
+	if len(c.Items) == 0 {
+		return nil, ErrEmptyQuoteRequest
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxQuoteNoteLength {
+		return nil, ErrInvalidQuoteNote
+	}
+
+	now := time.Now()
+	quote := &Quote{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Status:    QuoteStatusRequested,
+		Items:     make([]QuoteItem, len(c.Items)),
+		Note:      note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for i, item := range c.Items {
+		quote.Items[i] = QuoteItem{
+			ID:          utils.GenerateID(),
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			SKU:         item.SKU,
+			Name:        item.Name,
+			Quantity:    item.Quantity,
+			ListPrice:   item.Price,
+			QuotedPrice: item.Price,
+			Attributes:  item.Attributes,
+		}
+	}
+	var err error
+	if quote.Subtotal, err = quoteSubtotal(quote.Items); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Save(ctx, quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// GetQuote returns a quote
+func (s *QuoteService) GetQuote(ctx context.Context, id string) (*Quote, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// GetUserQuote returns one of a user's quotes; other users' quotes are not found
+func (s *QuoteService) GetUserQuote(ctx context.Context, userID, id string) (*Quote, error) {
+	quote, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.UserID != userID {
+		return nil, ErrQuoteNotFound
+	}
+	return quote, nil
+}
+
+// ListQuotes lists quotes, newest first
+func (s *QuoteService) ListQuotes(ctx context.Context, filter QuoteFilter) ([]*Quote, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// SendQuote prices a quote and sends it to the buyer. A sent quote can be sent again with
+// revised prices until the buyer answers.
+func (s *QuoteService) SendQuote(ctx context.Context, adminID, id string, offer QuoteOffer) (*Quote, error) {
+	quote, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !quote.Status.IsOpen() {
+		return nil, ErrQuoteNotOpen
+	}
+
+	note := strings.TrimSpace(offer.Note)
+	if utf8.RuneCountInString(note) > maxQuoteNoteLength {
+		return nil, ErrInvalidQuoteNote
+	}
+	now := time.Now()
+	validUntil := now.Add(s.validity)
+	if offer.ValidUntil != nil {
+		if !offer.ValidUntil.After(now) {
+			return nil, ErrInvalidQuoteOffer
+		}
+		validUntil = *offer.ValidUntil
+	}
+
+	priced := 0
+	for i := range quote.Items {
+		item := &quote.Items[i]
+		amount, ok := offer.Prices[item.ID]
+		if !ok {
+			continue
+		}
+		if amount < 0 {
+			return nil, ErrInvalidQuoteOffer
+		}
+		item.QuotedPrice = money.Money{Amount: amount, Currency: item.ListPrice.Currency}
+		priced++
+	}
+	if priced != len(offer.Prices) {
+		return nil, ErrInvalidQuoteOffer
+	}
+
+	quote.Status = QuoteStatusSent
+	if quote.Subtotal, err = quoteSubtotal(quote.Items); err != nil {
+		return nil, err
+	}
+	quote.SalesNote = note
+	quote.ValidUntil = &validUntil
+	quote.SentBy = adminID
+	quote.SentAt = &now
+	quote.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// DeclineQuote closes one of a user's open quotes without placing an order
+func (s *QuoteService) DeclineQuote(ctx context.Context, userID, id string) (*Quote, error) {
+	quote, err := s.GetUserQuote(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !quote.Status.IsOpen() {
+		return nil, ErrQuoteNotOpen
+	}
+
+	quote.Status = QuoteStatusDeclined
+	quote.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// QuoteCart returns a cart holding a sent quote's items at the quoted prices, for
+// checking out with the quote. The cart isn't stored; it has the quote's ID.
+func (s *QuoteService) QuoteCart(ctx context.Context, userID, id string) (*cart.Cart, error) {
+	quote, err := s.GetUserQuote(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case quote.Status == QuoteStatusRequested:
+		return nil, ErrQuoteNotSent
+	case quote.Status != QuoteStatusSent:
+		return nil, ErrQuoteNotOpen
+	case quote.IsExpired(time.Now()):
+		return nil, ErrQuoteExpired
+	}
+
+	c := &cart.Cart{
+		ID:        quote.ID,
+		UserID:    quote.UserID,
+		Items:     make([]cart.CartItem, len(quote.Items)),
+		CreatedAt: quote.CreatedAt,
+		UpdatedAt: quote.UpdatedAt,
+	}
+	for i, item := range quote.Items {
+		c.Items[i] = cart.CartItem{
+			ID:         item.ID,
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			SKU:        item.SKU,
+			Name:       item.Name,
+			Price:      item.QuotedPrice,
+			Quantity:   item.Quantity,
+			Attributes: item.Attributes,
+			AddedAt:    quote.CreatedAt,
+		}
+	}
+	return c, nil
+}
+
+// AcceptQuote records the order placed from a sent quote. It fails with ErrQuoteNotOpen
+// when the quote was accepted or declined in the meantime, so the order should be dropped.
+func (s *QuoteService) AcceptQuote(ctx context.Context, id, orderID string) (*Quote, error) {
+	if err := s.repo.MarkAccepted(ctx, id, orderID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// quoteSubtotal totals a quote's items at their quoted prices
+func quoteSubtotal(items []QuoteItem) (money.Money, error) {
+	subtotal := money.Zero(items[0].QuotedPrice.Currency)
+	for _, item := range items {
+		lineTotal, err := moneymath.MulInt(item.QuotedPrice, int64(item.Quantity))
+		if err != nil {
+			return money.Money{}, err
+		}
+		if subtotal, err = moneymath.Add(subtotal, lineTotal); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return subtotal, nil
+}
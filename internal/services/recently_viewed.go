@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+var ErrViewedProductNotFound = errors.New("no active product has this ID")
+
+// recentlyViewedLimit is how many products are kept per shopper, most recent first
+const recentlyViewedLimit = 20
+
+// RecentlyViewedProduct is a product a shopper looked at
+type RecentlyViewedProduct struct {
+	Product  *catalog.Product `json:"product"`
+	ViewedAt time.Time        `json:"viewed_at"`
+}
+
+// ProductView is a stored product view
+type ProductView struct {
+	ProductID string
+	ViewedAt  time.Time
+}
+
+// RecentlyViewedRepository defines persistence for recently viewed products, keyed by
+// user ID
+type RecentlyViewedRepository interface {
+	// Record stores a view, replacing an earlier view of the same product, and keeps
+	// only the user's most recent keep views
+	Record(ctx context.Context, userID string, view ProductView, keep int) error
+	// List returns a user's views, most recent first
+	List(ctx context.Context, userID string, limit int) ([]ProductView, error)
+	// Move gives one user's views to another, keeping the later view of a product both
+	// viewed and only the most recent keep views
+	Move(ctx context.Context, fromUserID, toUserID string, keep int) error
+}
+
+// RecentlyViewedService tracks the products shoppers, signed in or guests, looked at
+type RecentlyViewedService struct {
+	repo     RecentlyViewedRepository
+	products catalog.ProductRepository
+}
+
+// NewRecentlyViewedService creates a new RecentlyViewedService
+func NewRecentlyViewedService(repo RecentlyViewedRepository, products catalog.ProductRepository) *RecentlyViewedService {
+	return &RecentlyViewedService{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// RecordView records that a shopper viewed an active product
+func (s *RecentlyViewedService) RecordView(ctx context.Context, userID, productID string) error {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil || product.Status != catalog.ProductStatusActive {
+		return ErrViewedProductNotFound
+	}
+	return s.repo.Record(ctx, userID, ProductView{ProductID: productID, ViewedAt: time.Now()}, recentlyViewedLimit)
+}
+
+// List returns the products a shopper viewed, most recent first. Products that were
+// removed or deactivated since are left out.
+func (s *RecentlyViewedService) List(ctx context.Context, userID string) ([]*RecentlyViewedProduct, error) {
+	views, err := s.repo.List(ctx, userID, recentlyViewedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	viewed := make([]*RecentlyViewedProduct, 0, len(views))
+	for _, view := range views {
+		product, err := s.products.FindByID(ctx, view.ProductID)
+		if err != nil || product.Status != catalog.ProductStatusActive {
+			continue
+		}
+		viewed = append(viewed, &RecentlyViewedProduct{Product: product, ViewedAt: view.ViewedAt})
+	}
+	return viewed, nil
+}
+
+// Move gives a guest's recently viewed products to the account they signed in to
+func (s *RecentlyViewedService) Move(ctx context.Context, fromUserID, toUserID string) error {
+	return s.repo.Move(ctx, fromUserID, toUserID, recentlyViewedLimit)
+}
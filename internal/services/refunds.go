@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrOrderNotRefundable     = errors.New("order has not been paid or has already been refunded")
+	ErrInvalidRefund          = errors.New("refund requires either an amount or order lines, not both")
+	ErrRefundItemNotFound     = errors.New("refund line does not match an order item")
+	ErrRefundQuantityExceeded = errors.New("refund quantity exceeds the quantity not yet refunded")
+	ErrRefundExceedsPaid      = errors.New("refund amount exceeds the amount left to refund on the order")
+)
+
+// RefundLine is an order item (or part of one) being refunded
+type RefundLine struct {
+	OrderItemID string      `json:"order_item_id"`
+	Quantity    int         `json:"quantity"`
+	Amount      money.Money `json:"amount"`
+}
+
+// RefundAllocation is the part of a refund returned to one payment tender
+type RefundAllocation struct {
+	TenderID string      `json:"tender_id"`
+	Amount   money.Money `json:"amount"`
+}
+
+// OrderRefund records money returned to the customer for an order
+type OrderRefund struct {
+	ID          string                `json:"id"`
+	OrderID     string                `json:"order_id"`
+	Amount      money.Money           `json:"amount"`
+	Reason      payments.RefundReason `json:"reason"`
+	Note        string                `json:"note,omitempty"`
+	Lines       []RefundLine          `json:"lines,omitempty"`
+	Allocations []RefundAllocation    `json:"allocations,omitempty"`
+	CreatedBy   string                `json:"created_by"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// RefundLineRequest selects a quantity of an order item to refund
+type RefundLineRequest struct {
+	OrderItemID string
+	Quantity    int
+}
+
+// CreateRefundRequest describes a refund. Set Amount for a partial amount, Lines for
+// per-line refunds, or neither to refund everything left on the order.
+type CreateRefundRequest struct {
+	Amount    *money.Money
+	Lines     []RefundLineRequest
+	Reason    payments.RefundReason
+	Note      string
+	CreatedBy string
+}
+
+// RefundTotals summarizes how much of an order has been refunded
+type RefundTotals struct {
+	Refunded money.Money `json:"refunded"`
+	Net      money.Money `json:"net"` // order total less refunds
+}
+
+// RefundRepository defines persistence for order refunds
+type RefundRepository interface {
+	FindByOrderID(ctx context.Context, orderID string) ([]*OrderRefund, error)
+	Save(ctx context.Context, refund *OrderRefund) error
+}
+
+// RefundService issues full, partial and per-line refunds for orders
+type RefundService struct {
+	repo           RefundRepository
+	paymentService *PaymentService
+	paymentRepo    PaymentRepository
+	orderService   orders.Service
+	events         *OrderEventService
+}
+
+// NewRefundService creates a new RefundService
+func NewRefundService(
+	repo RefundRepository,
+	paymentService *PaymentService,
+	paymentRepo PaymentRepository,
+	orderService orders.Service,
+) *RefundService {
+	return &RefundService{
+		repo:           repo,
+		paymentService: paymentService,
+		paymentRepo:    paymentRepo,
+		orderService:   orderService,
+	}
+}
+
+// WithEvents records each refund on the order's timeline
+func (s *RefundService) WithEvents(events *OrderEventService) *RefundService {
+	s.events = events
+	return s
+}
+
+// ListRefunds returns the refunds issued for an order
+func (s *RefundService) ListRefunds(ctx context.Context, orderID string) ([]*OrderRefund, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// Totals returns how much of the order has been refunded and what remains
+func (s *RefundService) Totals(ctx context.Context, order *orders.Order) (*RefundTotals, error) {
+	refunds, err := s.repo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, err := sumRefunds(order.Total.Currency, refunds)
+	if err != nil {
+		return nil, err
+	}
+	net, err := order.Total.Subtract(refunded)
+	if err != nil {
+		return nil, err
+	}
+	return &RefundTotals{Refunded: refunded, Net: net}, nil
+}
+
+// CreateRefund refunds an order, returning the money to its payment tenders through the
+// gateway, and marks the order refunded once nothing is left to refund
+func (s *RefundService) CreateRefund(ctx context.Context, orderID string, req CreateRefundRequest) (*OrderRefund, error) {
+	if req.Amount != nil && len(req.Lines) > 0 {
+		return nil, ErrInvalidRefund
+	}
+
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isRefundableStatus(order.Status) {
+		return nil, ErrOrderNotRefundable
+	}
+
+	refund, remaining, err := s.refund(ctx, order, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.Amount == remaining && order.CanTransitionTo(orders.OrderStatusRefunded) {
+		if _, err := s.orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusRefunded); err != nil {
+			return nil, err
+		}
+	}
+	return refund, nil
+}
+
+// RefundCancellation refunds everything left to refund on an order that is being canceled,
+// leaving its status for the cancellation to change. It returns nil when no money was
+// taken for the order or all of it has already been refunded.
+func (s *RefundService) RefundCancellation(ctx context.Context, order *orders.Order, reason payments.RefundReason, note, createdBy string) (*OrderRefund, error) {
+	if !isRefundableStatus(order.Status) {
+		return nil, nil
+	}
+	refund, _, err := s.refund(ctx, order, CreateRefundRequest{Reason: reason, Note: note, CreatedBy: createdBy})
+	if errors.Is(err, ErrOrderNotRefundable) {
+		return nil, nil
+	}
+	return refund, err
+}
+
+// refund records a refund of the order and returns it to the order's payment tenders. It
+// also returns what was left to refund before it.
+func (s *RefundService) refund(ctx context.Context, order *orders.Order, req CreateRefundRequest) (*OrderRefund, money.Money, error) {
+	previous, err := s.repo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+	refunded, err := sumRefunds(order.Total.Currency, previous)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+	remaining, err := order.Total.Subtract(refunded)
+	if err != nil {
+		return nil, remaining, err
+	}
+	if !remaining.IsPositive() {
+		return nil, remaining, ErrOrderNotRefundable
+	}
+
+	refund := &OrderRefund{
+		ID:        utils.GenerateID(),
+		OrderID:   order.ID,
+		Reason:    req.Reason,
+		Note:      req.Note,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: time.Now(),
+	}
+	if refund.Reason == "" {
+		refund.Reason = payments.RefundReasonRequestedByCustomer
+	}
+
+	switch {
+	case len(req.Lines) > 0:
+		if refund.Lines, refund.Amount, err = refundLines(order, previous, req.Lines); err != nil {
+			return nil, remaining, err
+		}
+	case req.Amount != nil:
+		if !req.Amount.IsPositive() || req.Amount.Currency != order.Total.Currency {
+			return nil, remaining, ErrInvalidRefund
+		}
+		refund.Amount = *req.Amount
+	default:
+		refund.Amount = remaining
+	}
+
+	if left, err := remaining.Subtract(refund.Amount); err != nil {
+		return nil, remaining, err
+	} else if left.IsNegative() {
+		return nil, remaining, ErrRefundExceedsPaid
+	}
+
+	if refund.Allocations, err = s.refundTenders(ctx, order, refund); err != nil {
+		if len(refund.Allocations) > 0 {
+			// Some tenders were already refunded at the gateway; record what actually went back
+			if amount, sumErr := sumAllocations(order.Total.Currency, refund.Allocations); sumErr == nil {
+				refund.Amount = amount
+			}
+			refund.Lines = nil
+			if s.repo.Save(ctx, refund) == nil && s.events != nil {
+				s.events.RecordRefund(ctx, refund)
+			}
+		}
+		return nil, remaining, err
+	}
+
+	if err := s.repo.Save(ctx, refund); err != nil {
+		return nil, remaining, err
+	}
+	if s.events != nil {
+		s.events.RecordRefund(ctx, refund)
+	}
+	return refund, remaining, nil
+}
+
+// refundTenders returns the refund to the order's captured tenders, most recent first.
+// Orders paid outside split payments have no tenders; their refunds are recorded only.
+func (s *RefundService) refundTenders(ctx context.Context, order *orders.Order, refund *OrderRefund) ([]RefundAllocation, error) {
+	tenders, err := s.paymentRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tenders) == 0 {
+		return nil, nil
+	}
+
+	available := money.Zero(order.Total.Currency)
+	for _, tender := range tenders {
+		if tender.Status == TenderStatusCanceled {
+			continue
+		}
+		if available, err = available.Add(tender.Refundable()); err != nil {
+			return nil, err
+		}
+	}
+	if available.Amount < refund.Amount.Amount {
+		return nil, ErrRefundExceedsPaid
+	}
+
+	var allocations []RefundAllocation
+	outstanding := refund.Amount
+	for i := len(tenders) - 1; i >= 0 && outstanding.IsPositive(); i-- {
+		tender := tenders[i]
+		if tender.Status == TenderStatusCanceled || !tender.Refundable().IsPositive() {
+			continue
+		}
+
+		amount := tender.Refundable()
+		if amount.Amount > outstanding.Amount {
+			amount = outstanding
+		}
+
+		if _, err := s.paymentService.RefundTender(ctx, tender.ID, amount, refund.Reason); err != nil {
+			return allocations, err
+		}
+		allocations = append(allocations, RefundAllocation{TenderID: tender.ID, Amount: amount})
+
+		if outstanding, err = outstanding.Subtract(amount); err != nil {
+			return allocations, err
+		}
+	}
+	return allocations, nil
+}
+
+// refundLines prices the requested lines pro rata from each item's total, including its tax and discount
+func refundLines(order *orders.Order, previous []*OrderRefund, requests []RefundLineRequest) ([]RefundLine, money.Money, error) {
+	refundedQty := make(map[string]int)
+	for _, refund := range previous {
+		for _, line := range refund.Lines {
+			refundedQty[line.OrderItemID] += line.Quantity
+		}
+	}
+
+	items := make(map[string]orders.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		items[item.ID] = item
+	}
+
+	total := money.Zero(order.Total.Currency)
+	lines := make([]RefundLine, len(requests))
+	for i, req := range requests {
+		item, ok := items[req.OrderItemID]
+		if !ok {
+			return nil, total, ErrRefundItemNotFound
+		}
+		if req.Quantity <= 0 || refundedQty[item.ID]+req.Quantity > item.Quantity {
+			return nil, total, ErrRefundQuantityExceeded
+		}
+		refundedQty[item.ID] += req.Quantity
+
+		amount, err := moneymath.Prorate(item.Total, int64(req.Quantity), int64(item.Quantity))
+		if err != nil {
+			return nil, total, err
+		}
+		lines[i] = RefundLine{OrderItemID: item.ID, Quantity: req.Quantity, Amount: amount}
+
+		if total, err = moneymath.Add(total, amount); err != nil {
+			return nil, total, err
+		}
+	}
+	return lines, total, nil
+}
+
+func sumRefunds(currency string, refunds []*OrderRefund) (money.Money, error) {
+	total := money.Zero(currency)
+	for _, refund := range refunds {
+		var err error
+		if total, err = moneymath.Add(total, refund.Amount); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func sumAllocations(currency string, allocations []RefundAllocation) (money.Money, error) {
+	amounts := make([]money.Money, len(allocations))
+	for i, allocation := range allocations {
+		amounts[i] = allocation.Amount
+	}
+	return moneymath.Sum(currency, amounts...)
+}
+
+// isRefundableStatus reports whether money has been taken for an order in this status
+func isRefundableStatus(status orders.OrderStatus) bool {
+	switch status {
+	case orders.OrderStatusPaid, orders.OrderStatusProcessing, orders.OrderStatusShipped, orders.OrderStatusDelivered:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,583 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrRentalNotFound       = errors.New("product is not offered for rent")
+	ErrInvalidRental        = errors.New("rental needs at least one unit, unit counts of the product's SKUs, min days no greater than max days and a buffer of 0 to 30 days")
+	ErrRentalSKUNotFound    = errors.New("sku is not a sku of this product")
+	ErrRentalPeriodRequired = errors.New("rental products need a rental_start and rental_end date")
+	ErrInvalidRentalPeriod  = errors.New("invalid rental period")
+	ErrInvalidCalendarRange = errors.New("calendar range must be YYYY-MM-DD dates from today on, at most 92 days long")
+	ErrRentalInCart         = errors.New("product is already in the cart for other dates; remove it first to change them")
+)
+
+// Cart and order item attributes recording the rental period, as YYYY-MM-DD dates and
+// the number of days rented
+const (
+	RentalStartAttribute = "rental_start"
+	RentalEndAttribute   = "rental_end"
+	RentalDaysAttribute  = "rental_days"
+)
+
+const (
+	rentalDateLayout      = "2006-01-02"
+	maxRentalBufferDays   = 30
+	maxRentalCalendarDays = 92
+	defaultCalendarDays   = 30
+	// rentalLockTimeout bounds how long booking an order waits for another replica
+	rentalLockTimeout = 5 * time.Second
+)
+
+// RentalProduct puts a product in rental mode: it is rented by the day for a period
+// chosen in the cart, with Units of each SKU to go around
+type RentalProduct struct {
+	ProductID  string         `json:"product_id"`
+	Units      int            `json:"units"`               // units of each SKU, unless set in SKUUnits
+	SKUUnits   map[string]int `json:"sku_units,omitempty"` // units by SKU, for SKUs stocked differently
+	MinDays    int            `json:"min_days"`
+	MaxDays    int            `json:"max_days,omitempty"` // 0 for no maximum
+	BufferDays int            `json:"buffer_days"`        // days a unit is unavailable after each rental, for cleaning or shipping back
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// UnitsFor returns the units of a SKU there are to rent
+func (r *RentalProduct) UnitsFor(sku string) int {
+	if units, ok := r.SKUUnits[sku]; ok {
+		return units
+	}
+	return r.Units
+}
+
+// RentalPeriod is the days a rental runs, from Start to End inclusive
+type RentalPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Days returns the number of days rented
+func (p RentalPeriod) Days() int {
+	return int(p.End.Sub(p.Start).Hours()/24) + 1
+}
+
+// ParseRentalPeriod parses a rental period from YYYY-MM-DD dates. The period may not start
+// before today or end before it starts.
+func ParseRentalPeriod(start, end string) (RentalPeriod, error) {
+	if start == "" && end == "" {
+		return RentalPeriod{}, ErrRentalPeriodRequired
+	}
+	from, err := time.Parse(rentalDateLayout, start)
+	if err != nil {
+		return RentalPeriod{}, fmt.Errorf("%w: rental_start must be a YYYY-MM-DD date", ErrInvalidRentalPeriod)
+	}
+	to, err := time.Parse(rentalDateLayout, end)
+	if err != nil {
+		return RentalPeriod{}, fmt.Errorf("%w: rental_end must be a YYYY-MM-DD date", ErrInvalidRentalPeriod)
+	}
+	if from.Before(rentalToday()) {
+		return RentalPeriod{}, fmt.Errorf("%w: rental_start may not be in the past", ErrInvalidRentalPeriod)
+	}
+	if to.Before(from) {
+		return RentalPeriod{}, fmt.Errorf("%w: rental_end may not be before rental_start", ErrInvalidRentalPeriod)
+	}
+	return RentalPeriod{Start: from, End: to}, nil
+}
+
+// RentalBooking holds units of a SKU for an order's rental period
+type RentalBooking struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	SKU         string    `json:"sku"`
+	OrderID     string    `json:"order_id"`
+	OrderItemID string    `json:"order_item_id"`
+	Quantity    int       `json:"quantity"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RentalConflictError reports a rental period with fewer units of the SKU free than asked for
+type RentalConflictError struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+func (e *RentalConflictError) Error() string {
+	return fmt.Sprintf("only %d of the %d units of %s requested are available from %s to %s", e.Available, e.Requested, e.SKU, e.Start, e.End)
+}
+
+// RentalCalendar lists the units of a SKU free to rent on each day of a date range
+type RentalCalendar struct {
+	ProductID  string      `json:"product_id"`
+	SKU        string      `json:"sku"`
+	Units      int         `json:"units"`
+	MinDays    int         `json:"min_days"`
+	MaxDays    int         `json:"max_days,omitempty"`
+	BufferDays int         `json:"buffer_days"`
+	Days       []RentalDay `json:"days"`
+}
+
+// RentalDay is the units of a SKU free to rent on a day
+type RentalDay struct {
+	Date      string `json:"date"`
+	Available int    `json:"available"`
+}
+
+// RentalRepository defines persistence for rental products and their bookings
+type RentalRepository interface {
+	FindProduct(ctx context.Context, productID string) (*RentalProduct, error)
+	SaveProduct(ctx context.Context, rental *RentalProduct) error
+	DeleteProduct(ctx context.Context, productID string) error
+	// FindBookings returns the bookings of a SKU running on any day from from to to
+	FindBookings(ctx context.Context, sku string, from, to time.Time) ([]*RentalBooking, error)
+	SaveBookings(ctx context.Context, bookings []*RentalBooking) error
+	DeleteOrderBookings(ctx context.Context, orderID string) error
+}
+
+// RentalService manages products in rental mode, their availability calendars and the
+// bookings orders make for the rental periods chosen in the cart
+type RentalService struct {
+	repo     RentalRepository
+	products catalog.ProductRepository
+	variants catalog.VariantRepository
+	locker   lock.Manager
+}
+
+// NewRentalService creates a new RentalService
+func NewRentalService(repo RentalRepository, products catalog.ProductRepository, variants catalog.VariantRepository) *RentalService {
+	return &RentalService{repo: repo, products: products, variants: variants}
+}
+
+// WithLocker makes replicas take a per-SKU lock while booking an order, so two checkouts
+// cannot both book the last unit for overlapping dates
+func (s *RentalService) WithLocker(locker lock.Manager) *RentalService {
+	s.locker = locker
+	return s
+}
+
+// GetRental returns a product's rental settings
+func (s *RentalService) GetRental(ctx context.Context, productID string) (*RentalProduct, error) {
+	return s.repo.FindProduct(ctx, productID)
+}
+
+// SetRental validates and saves a product's rental settings, putting it in rental mode
+func (s *RentalService) SetRental(ctx context.Context, rental *RentalProduct) error {
+	skus, err := s.productSKUs(ctx, rental.ProductID)
+	if err != nil {
+		return err
+	}
+	if rental.MinDays < 1 {
+		rental.MinDays = 1
+	}
+	if rental.Units < 1 || rental.MaxDays < 0 || (rental.MaxDays > 0 && rental.MaxDays < rental.MinDays) ||
+		rental.BufferDays < 0 || rental.BufferDays > maxRentalBufferDays {
+		return ErrInvalidRental
+	}
+	for sku, units := range rental.SKUUnits {
+		if !skus[sku] || units < 0 {
+			return ErrInvalidRental
+		}
+	}
+	rental.UpdatedAt = time.Now()
+	return s.repo.SaveProduct(ctx, rental)
+}
+
+// RemoveRental takes a product out of rental mode. Bookings already made are kept.
+func (s *RentalService) RemoveRental(ctx context.Context, productID string) error {
+	return s.repo.DeleteProduct(ctx, productID)
+}
+
+// Calendar returns the units of a SKU of a rental product free to rent on each day from
+// from to to, both YYYY-MM-DD dates. The range defaults to the next 30 days from today; a
+// SKU defaults to the product's own.
+func (s *RentalService) Calendar(ctx context.Context, productID, sku, from, to string) (*RentalCalendar, error) {
+	rental, err := s.repo.FindProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	skus, err := s.productSKUs(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if sku == "" {
+		product, err := s.products.FindByID(ctx, productID)
+		if err != nil {
+			return nil, ErrProductNotFound
+		}
+		sku = product.SKU
+	}
+	if !skus[sku] {
+		return nil, ErrRentalSKUNotFound
+	}
+
+	period, err := calendarRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	booked, err := s.bookedUnits(ctx, rental, sku, period)
+	if err != nil {
+		return nil, err
+	}
+
+	units := rental.UnitsFor(sku)
+	calendar := &RentalCalendar{
+		ProductID:  productID,
+		SKU:        sku,
+		Units:      units,
+		MinDays:    rental.MinDays,
+		MaxDays:    rental.MaxDays,
+		BufferDays: rental.BufferDays,
+		Days:       make([]RentalDay, 0, period.Days()),
+	}
+	for day := period.Start; !day.After(period.End); day = day.AddDate(0, 0, 1) {
+		calendar.Days = append(calendar.Days, RentalDay{
+			Date:      day.Format(rentalDateLayout),
+			Available: max(units-booked[day], 0),
+		})
+	}
+	return calendar, nil
+}
+
+// PrepareItem checks an item about to be added to the cart. Items of rental products need
+// a rental period within the product's minimum and maximum days with enough units free,
+// and may not already be in the cart; their period is normalized in the item's attributes.
+// Items of other products have any rental attributes removed. It returns the period, or
+// nil for items of products not in rental mode.
+func (s *RentalService) PrepareItem(ctx context.Context, c *cart.Cart, req *cart.AddItemRequest) (*RentalPeriod, error) {
+	rental, err := s.repo.FindProduct(ctx, req.ProductID)
+	if errors.Is(err, ErrRentalNotFound) {
+		clearRentalAttributes(req.Attributes)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := ParseRentalPeriod(req.Attributes[RentalStartAttribute], req.Attributes[RentalEndAttribute])
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRentalDays(rental, period); err != nil {
+		return nil, err
+	}
+	if cartLine(c, req.ProductID, req.VariantID) != nil {
+		return nil, ErrRentalInCart
+	}
+
+	sku, err := s.itemSKU(ctx, req.ProductID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(ctx, rental, req.ProductID, sku, period, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if req.Attributes == nil {
+		req.Attributes = make(map[string]string)
+	}
+	setRentalAttributes(req.Attributes, period)
+	return &period, nil
+}
+
+// CheckItem checks that enough units are free for a rental item's period at the quantity
+// given. Items without a rental period pass.
+func (s *RentalService) CheckItem(ctx context.Context, item *cart.CartItem, quantity int) error {
+	period, ok := itemRentalPeriod(item.Attributes)
+	if !ok {
+		return nil
+	}
+	rental, err := s.repo.FindProduct(ctx, item.ProductID)
+	if errors.Is(err, ErrRentalNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.checkAvailable(ctx, rental, item.ProductID, item.SKU, period, quantity)
+}
+
+// CheckCart checks every rental item in the cart before an order is placed: the period
+// must not have started yet and enough units must still be free
+func (s *RentalService) CheckCart(ctx context.Context, c *cart.Cart) error {
+	for i := range c.Items {
+		item := &c.Items[i]
+		if period, ok := itemRentalPeriod(item.Attributes); ok && period.Start.Before(rentalToday()) {
+			return fmt.Errorf("%w: the rental of %s has already started; choose new dates", ErrInvalidRentalPeriod, item.Name)
+		}
+		if err := s.CheckItem(ctx, item, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BookOrder books the rental periods of a placed order's rental items. The SKUs are locked
+// and their availability checked again first, so a conflicting order placed at the same
+// time gets a *RentalConflictError and nothing is booked for it.
+func (s *RentalService) BookOrder(ctx context.Context, order *orders.Order) error {
+	type line struct {
+		rental *RentalProduct
+		item   orders.OrderItem
+		period RentalPeriod
+	}
+	var lines []line
+	skus := make(map[string]bool)
+	for _, item := range order.Items {
+		period, ok := itemRentalPeriod(item.Attributes)
+		if !ok {
+			continue
+		}
+		rental, err := s.repo.FindProduct(ctx, item.ProductID)
+		if errors.Is(err, ErrRentalNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line{rental: rental, item: item, period: period})
+		skus[item.SKU] = true
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	release, err := s.lockSKUs(ctx, skus)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	now := time.Now()
+	bookings := make([]*RentalBooking, len(lines))
+	for i, l := range lines {
+		if err := s.checkAvailable(ctx, l.rental, l.item.ProductID, l.item.SKU, l.period, l.item.Quantity); err != nil {
+			return err
+		}
+		bookings[i] = &RentalBooking{
+			ID:          utils.GenerateID(),
+			ProductID:   l.item.ProductID,
+			SKU:         l.item.SKU,
+			OrderID:     order.ID,
+			OrderItemID: l.item.ID,
+			Quantity:    l.item.Quantity,
+			Start:       l.period.Start,
+			End:         l.period.End,
+			CreatedAt:   now,
+		}
+	}
+	return s.repo.SaveBookings(ctx, bookings)
+}
+
+// ReleaseOrder frees the units an order booked, when it is canceled
+func (s *RentalService) ReleaseOrder(ctx context.Context, orderID string) error {
+	return s.repo.DeleteOrderBookings(ctx, orderID)
+}
+
+// checkAvailable returns a *RentalConflictError when fewer than quantity units of the SKU
+// are free on some day of the period, or of the buffer days after it
+func (s *RentalService) checkAvailable(ctx context.Context, rental *RentalProduct, productID, sku string, period RentalPeriod, quantity int) error {
+	blocked := RentalPeriod{Start: period.Start, End: period.End.AddDate(0, 0, rental.BufferDays)}
+	booked, err := s.bookedUnits(ctx, rental, sku, blocked)
+	if err != nil {
+		return err
+	}
+
+	units := rental.UnitsFor(sku)
+	available := units
+	for day := blocked.Start; !day.After(blocked.End); day = day.AddDate(0, 0, 1) {
+		available = min(available, units-booked[day])
+	}
+	if available < quantity {
+		return &RentalConflictError{
+			ProductID: productID,
+			SKU:       sku,
+			Start:     period.Start.Format(rentalDateLayout),
+			End:       period.End.Format(rentalDateLayout),
+			Requested: quantity,
+			Available: max(available, 0),
+		}
+	}
+	return nil
+}
+
+// bookedUnits counts the units of a SKU booked on each day of the period. A booking holds
+// its units through the product's buffer days after it ends.
+func (s *RentalService) bookedUnits(ctx context.Context, rental *RentalProduct, sku string, period RentalPeriod) (map[time.Time]int, error) {
+	bookings, err := s.repo.FindBookings(ctx, sku, period.Start.AddDate(0, 0, -rental.BufferDays), period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := make(map[time.Time]int)
+	for _, booking := range bookings {
+		end := booking.End.AddDate(0, 0, rental.BufferDays)
+		for day := booking.Start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if !day.Before(period.Start) && !day.After(period.End) {
+				booked[day] += booking.Quantity
+			}
+		}
+	}
+	return booked, nil
+}
+
+// lockSKUs takes the rental lock of each SKU, in order so two orders sharing SKUs cannot
+// deadlock, and returns a func releasing them
+func (s *RentalService) lockSKUs(ctx context.Context, skus map[string]bool) (func(), error) {
+	if s.locker == nil {
+		return func() {}, nil
+	}
+	names := make([]string, 0, len(skus))
+	for sku := range skus {
+		names = append(names, "rental:"+sku)
+	}
+	sort.Strings(names)
+
+	lockCtx, cancel := context.WithTimeout(ctx, rentalLockTimeout)
+	defer cancel()
+
+	held := make([]lock.Lock, 0, len(names))
+	release := func() {
+		for _, l := range held {
+			l.Release(context.Background())
+		}
+	}
+	for _, name := range names {
+		l, err := s.locker.Acquire(lockCtx, name)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		held = append(held, l)
+	}
+	return release, nil
+}
+
+// productSKUs returns the SKUs of a product and its variants
+func (s *RentalService) productSKUs(ctx context.Context, productID string) (map[string]bool, error) {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	variants, err := s.variants.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	skus := map[string]bool{product.SKU: true}
+	for _, variant := range variants {
+		skus[variant.SKU] = true
+	}
+	return skus, nil
+}
+
+// itemSKU returns the SKU of the product or variant an item is for
+func (s *RentalService) itemSKU(ctx context.Context, productID string, variantID *string) (string, error) {
+	if variantID != nil {
+		variant, err := s.variants.FindByID(ctx, *variantID)
+		if err != nil {
+			return "", ErrVariantNotFound
+		}
+		return variant.SKU, nil
+	}
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return "", ErrProductNotFound
+	}
+	return product.SKU, nil
+}
+
+// checkRentalDays checks a period against the product's minimum and maximum days
+func checkRentalDays(rental *RentalProduct, period RentalPeriod) error {
+	days := period.Days()
+	if days < rental.MinDays {
+		return fmt.Errorf("%w: the product is rented for at least %d days", ErrInvalidRentalPeriod, rental.MinDays)
+	}
+	if rental.MaxDays > 0 && days > rental.MaxDays {
+		return fmt.Errorf("%w: the product is rented for at most %d days", ErrInvalidRentalPeriod, rental.MaxDays)
+	}
+	return nil
+}
+
+// calendarRange parses a calendar date range, defaulting to the next 30 days
+func calendarRange(from, to string) (RentalPeriod, error) {
+	today := rentalToday()
+	period := RentalPeriod{Start: today}
+	if from != "" {
+		start, err := time.Parse(rentalDateLayout, from)
+		if err != nil || start.Before(today) {
+			return RentalPeriod{}, ErrInvalidCalendarRange
+		}
+		period.Start = start
+	}
+	period.End = period.Start.AddDate(0, 0, defaultCalendarDays-1)
+	if to != "" {
+		end, err := time.Parse(rentalDateLayout, to)
+		if err != nil {
+			return RentalPeriod{}, ErrInvalidCalendarRange
+		}
+		period.End = end
+	}
+	if period.End.Before(period.Start) || period.Days() > maxRentalCalendarDays {
+		return RentalPeriod{}, ErrInvalidCalendarRange
+	}
+	return period, nil
+}
+
+// itemRentalPeriod reads the rental period recorded in an item's attributes
+func itemRentalPeriod(attributes map[string]string) (RentalPeriod, bool) {
+	start, startErr := time.Parse(rentalDateLayout, attributes[RentalStartAttribute])
+	end, endErr := time.Parse(rentalDateLayout, attributes[RentalEndAttribute])
+	if startErr != nil || endErr != nil || end.Before(start) {
+		return RentalPeriod{}, false
+	}
+	return RentalPeriod{Start: start, End: end}, true
+}
+
+func setRentalAttributes(attributes map[string]string, period RentalPeriod) {
+	attributes[RentalStartAttribute] = period.Start.Format(rentalDateLayout)
+	attributes[RentalEndAttribute] = period.End.Format(rentalDateLayout)
+	attributes[RentalDaysAttribute] = strconv.Itoa(period.Days())
+}
+
+func clearRentalAttributes(attributes map[string]string) {
+	delete(attributes, RentalStartAttribute)
+	delete(attributes, RentalEndAttribute)
+	delete(attributes, RentalDaysAttribute)
+}
+
+// cartLine finds the cart line of a product or variant
+func cartLine(c *cart.Cart, productID string, variantID *string) *cart.CartItem {
+	for i := range c.Items {
+		item := &c.Items[i]
+		if item.ProductID != productID || (item.VariantID == nil) != (variantID == nil) {
+			continue
+		}
+		if variantID == nil || *item.VariantID == *variantID {
+			return item
+		}
+	}
+	return nil
+}
+
+// rentalToday returns today's date in UTC, the time zone rental dates are in
+func rentalToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionRepository applies retention rules. Each method changes the rows older than
+// before, or with dryRun only counts them, and returns how many rows were affected.
+type RetentionRepository interface {
+	AnonymizeGuestCarts(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+	PurgeWebhookPayloads(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+	RedactIPAddresses(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+}
+
+// RetentionPolicy sets how long personal and bulky data is kept; 0 keeps it forever
+type RetentionPolicy struct {
+	GuestCarts      time.Duration // guest carts lose their session link after this long without changes
+	WebhookPayloads time.Duration // processed and failed webhooks lose their payload after this long
+	IPAddresses     time.Duration // order and login device IP addresses are cleared after this long
+}
+
+// RetentionRuleResult is what one retention rule changed, or would change in a dry run
+type RetentionRuleResult struct {
+	Rule        string    `json:"rule"`
+	Description string    `json:"description"`
+	RetainFor   string    `json:"retain_for"`
+	Before      time.Time `json:"before"`
+	Affected    int64     `json:"affected"`
+}
+
+// RetentionReport is the outcome of one retention run
+type RetentionReport struct {
+	DryRun bool                  `json:"dry_run"`
+	RanAt  time.Time             `json:"ran_at"`
+	Rules  []RetentionRuleResult `json:"rules"`
+}
+
+// RetentionService purges and anonymizes data once it is past its retention period
+type RetentionService struct {
+	repo   RetentionRepository
+	policy RetentionPolicy
+}
+
+// NewRetentionService creates a new RetentionService
+func NewRetentionService(repo RetentionRepository, policy RetentionPolicy) *RetentionService {
+	return &RetentionService{repo: repo, policy: policy}
+}
+
+// Run applies every enabled retention rule. With dryRun nothing is changed and the
+// report shows what a real run would affect.
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	now := time.Now()
+	report := &RetentionReport{DryRun: dryRun, RanAt: now, Rules: []RetentionRuleResult{}}
+
+	rules := []struct {
+		name, description string
+		retainFor         time.Duration
+		apply             func(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+	}{
+		{"guest-carts", "Unlink guest carts from their browser session", s.policy.GuestCarts, s.repo.AnonymizeGuestCarts},
+		{"webhook-payloads", "Clear the payload of processed and failed webhooks", s.policy.WebhookPayloads, s.repo.PurgeWebhookPayloads},
+		{"ip-addresses", "Clear IP addresses recorded on orders and login devices", s.policy.IPAddresses, s.repo.RedactIPAddresses},
+	}
+
+	for _, rule := range rules {
+		if rule.retainFor <= 0 {
+			continue
+		}
+		before := now.Add(-rule.retainFor)
+		affected, err := rule.apply(ctx, before, dryRun)
+		if err != nil {
+			return report, err
+		}
+		report.Rules = append(report.Rules, RetentionRuleResult{
+			Rule:        rule.name,
+			Description: rule.description,
+			RetainFor:   rule.retainFor.String(),
+			Before:      before,
+			Affected:    affected,
+		})
+	}
+	return report, nil
+}
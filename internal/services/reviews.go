@@ -0,0 +1,377 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrReviewPhotoNotFound = errors.New("review photo not found")
+	ErrInvalidReview       = errors.New("invalid review")
+	ErrInvalidReviewPhoto  = errors.New("photo must be a JPEG, PNG, GIF or WebP image within the size limit")
+	ErrReviewExists        = errors.New("you have already reviewed this product")
+	ErrReviewPhotoLimit    = errors.New("review has the most photos allowed")
+)
+
+// ReviewStatus is where a review is in moderation
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"  // waiting on a moderator; only the author sees it
+	ReviewStatusApproved ReviewStatus = "approved" // shown on the product
+	ReviewStatusRejected ReviewStatus = "rejected" // kept for the author with the moderator's note
+)
+
+// IsValid reports whether the status is a known review status
+func (s ReviewStatus) IsValid() bool {
+	switch s {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+// Review is a customer's rating and write-up of a product. Reviews are shown on the
+// product once a moderator approves them, until they are hidden after being reported.
+type Review struct {
+	ID             string        `json:"id"`
+	ProductID      string        `json:"product_id"`
+	UserID         string        `json:"user_id"`
+	Rating         int           `json:"rating"` // 1 to 5
+	Title          string        `json:"title"`
+	Body           string        `json:"body"`
+	Status         ReviewStatus  `json:"status"`
+	Filtered       bool          `json:"filtered"` // blocked words were masked in the title or body
+	Hidden         bool          `json:"hidden"`   // taken off the product after reports
+	ModerationNote string        `json:"moderation_note,omitempty"`
+	ModeratedBy    string        `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time    `json:"moderated_at,omitempty"`
+	Photos         []ReviewPhoto `json:"photos"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// ReviewPhoto is a photo attached to a review, kept in media storage
+type ReviewPhoto struct {
+	ID        string    `json:"id"`
+	ReviewID  string    `json:"review_id"`
+	URL       string    `json:"url"`
+	Key       string    `json:"-"` // media storage key
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProductReviews are a product's approved reviews with their average rating
+type ProductReviews struct {
+	ProductID     string    `json:"product_id"`
+	AverageRating float64   `json:"average_rating"` // rounded to one decimal, 0 without reviews
+	ReviewCount   int       `json:"review_count"`
+	Reviews       []*Review `json:"reviews"`
+}
+
+// WriteReviewRequest holds what a customer sends to review a product
+type WriteReviewRequest struct {
+	ProductID string
+	Rating    int
+	Title     string
+	Body      string
+}
+
+// ModerationResult reports which reviews a bulk moderation action changed
+type ModerationResult struct {
+	Status   ReviewStatus `json:"status"`
+	Updated  []string     `json:"updated"`
+	NotFound []string     `json:"not_found"`
+}
+
+// ReviewRepository defines persistence for reviews and their photos
+type ReviewRepository interface {
+	// FindByID returns a review with its photos
+	FindByID(ctx context.Context, id string) (*Review, error)
+	// FindByUserAndProduct returns nil when the user hasn't reviewed the product
+	FindByUserAndProduct(ctx context.Context, userID, productID string) (*Review, error)
+	// FindByProduct returns a product's reviews in a status with their photos, newest first
+	FindByProduct(ctx context.Context, productID string, status ReviewStatus) ([]*Review, error)
+	// FindByUserID returns a user's reviews with their photos, newest first
+	FindByUserID(ctx context.Context, userID string) ([]*Review, error)
+	// FindByStatus returns the reviews in a status, or all reviews when status is empty,
+	// with their photos, oldest first
+	FindByStatus(ctx context.Context, status ReviewStatus) ([]*Review, error)
+	// Save creates a review or updates its fields and status
+	Save(ctx context.Context, review *Review) error
+	AddPhoto(ctx context.Context, photo *ReviewPhoto) error
+	DeletePhoto(ctx context.Context, id string) error
+	SetHidden(ctx context.Context, id string, hidden bool) error
+}
+
+// ReviewService lets customers review products, with photos, and moderators approve or
+// reject the reviews before they are shown. Blocked words are masked as reviews are written.
+type ReviewService struct {
+	repo         ReviewRepository
+	products     catalog.ProductRepository
+	media        MediaStorage
+	filter       *ProfanityFilter
+	maxPhotos    int
+	maxPhotoSize int64
+}
+
+// NewReviewService creates a new ReviewService storing up to maxPhotos photos of up to
+// maxPhotoSize bytes per review in media
+func NewReviewService(repo ReviewRepository, products catalog.ProductRepository, media MediaStorage, filter *ProfanityFilter, maxPhotos int, maxPhotoSize int64) *ReviewService {
+	return &ReviewService{
+		repo:         repo,
+		products:     products,
+		media:        media,
+		filter:       filter,
+		maxPhotos:    maxPhotos,
+		maxPhotoSize: maxPhotoSize,
+	}
+}
+
+// MaxPhotoSize returns the largest photo accepted, in bytes
+func (s *ReviewService) MaxPhotoSize() int64 {
+	return s.maxPhotoSize
+}
+
+// WriteReview adds a customer's review of a product, pending moderation. Each customer
+// reviews a product once.
+func (s *ReviewService) WriteReview(ctx context.Context, userID string, req WriteReviewRequest) (*Review, error) {
+	if _, err := s.products.FindByID(ctx, req.ProductID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidReview)
+	}
+	title := strings.TrimSpace(req.Title)
+	body := strings.TrimSpace(req.Body)
+	if utf8.RuneCountInString(title) > 255 || body == "" || utf8.RuneCountInString(body) > 5000 {
+		return nil, fmt.Errorf("%w: title must be at most 255 characters and body is required and at most 5000", ErrInvalidReview)
+	}
+
+	existing, err := s.repo.FindByUserAndProduct(ctx, userID, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrReviewExists
+	}
+
+	title, titleFiltered := s.filter.Clean(title)
+	body, bodyFiltered := s.filter.Clean(body)
+	now := time.Now()
+	review := &Review{
+		ID:        utils.GenerateID(),
+		ProductID: req.ProductID,
+		UserID:    userID,
+		Rating:    req.Rating,
+		Title:     title,
+		Body:      body,
+		Status:    ReviewStatusPending,
+		Filtered:  titleFiltered || bodyFiltered,
+		Photos:    []ReviewPhoto{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Save(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetReview returns a review with its photos
+func (s *ReviewService) GetReview(ctx context.Context, id string) (*Review, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// UserReviews returns the reviews a user wrote, in any status
+func (s *ReviewService) UserReviews(ctx context.Context, userID string) ([]*Review, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+// ProductReviews returns a product's approved, visible reviews with their average rating
+func (s *ReviewService) ProductReviews(ctx context.Context, productID string) (*ProductReviews, error) {
+	approved, err := s.repo.FindByProduct(ctx, productID, ReviewStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	reviews := make([]*Review, 0, len(approved))
+	for _, review := range approved {
+		if !review.Hidden {
+			reviews = append(reviews, review)
+		}
+	}
+
+	result := &ProductReviews{ProductID: productID, ReviewCount: len(reviews), Reviews: reviews}
+	if len(reviews) > 0 {
+		total := 0
+		for _, review := range reviews {
+			total += review.Rating
+		}
+		result.AverageRating = math.Round(float64(total)/float64(len(reviews))*10) / 10
+	}
+	return result, nil
+}
+
+// ListReviews returns the moderation queue: the reviews in a status, or every review when
+// status is empty
+func (s *ReviewService) ListReviews(ctx context.Context, status ReviewStatus) ([]*Review, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidReview, status)
+	}
+	return s.repo.FindByStatus(ctx, status)
+}
+
+// AddPhoto attaches a photo to the author's review. The image type is detected from the
+// content. A photo added to an approved review sends it back to moderation.
+func (s *ReviewService) AddPhoto(ctx context.Context, userID, reviewID string, image io.Reader) (*Review, error) {
+	review, err := s.authorReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if len(review.Photos) >= s.maxPhotos {
+		return nil, ErrReviewPhotoLimit
+	}
+
+	data, err := io.ReadAll(io.LimitReader(image, s.maxPhotoSize+1))
+	if err != nil {
+		return nil, err
+	}
+	// Reviews take the same image types as avatars
+	extension, ok := avatarExtensions[http.DetectContentType(data)]
+	if !ok || len(data) == 0 || int64(len(data)) > s.maxPhotoSize {
+		return nil, ErrInvalidReviewPhoto
+	}
+
+	photo := ReviewPhoto{
+		ID:        utils.GenerateID(),
+		ReviewID:  review.ID,
+		CreatedAt: time.Now(),
+	}
+	photo.Key = fmt.Sprintf("reviews/%s/%s%s", review.ID, photo.ID, extension)
+	if photo.URL, err = s.media.Put(ctx, photo.Key, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddPhoto(ctx, &photo); err != nil {
+		s.deletePhotoFile(ctx, photo.Key)
+		return nil, err
+	}
+	review.Photos = append(review.Photos, photo)
+
+	if review.Status == ReviewStatusApproved {
+		review.Status = ReviewStatusPending
+		review.UpdatedAt = photo.CreatedAt
+		if err := s.repo.Save(ctx, review); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+// RemovePhoto removes a photo from a review. Authors remove photos from their own reviews;
+// moderators from any review.
+func (s *ReviewService) RemovePhoto(ctx context.Context, userID string, moderator bool, reviewID, photoID string) (*Review, error) {
+	review, err := s.repo.FindByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID && !moderator {
+		return nil, ErrReviewNotFound
+	}
+
+	for i, photo := range review.Photos {
+		if photo.ID != photoID {
+			continue
+		}
+		if err := s.repo.DeletePhoto(ctx, photo.ID); err != nil {
+			return nil, err
+		}
+		s.deletePhotoFile(ctx, photo.Key)
+		review.Photos = append(review.Photos[:i], review.Photos[i+1:]...)
+		return review, nil
+	}
+	return nil, ErrReviewPhotoNotFound
+}
+
+// Moderate approves or rejects a review, or sends it back to pending. The note is kept with
+// the review for its author, typically the reason for a rejection.
+func (s *ReviewService) Moderate(ctx context.Context, reviewID, moderatorID string, status ReviewStatus, note string) (*Review, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidReview, status)
+	}
+	review, err := s.repo.FindByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.ModerationNote = strings.TrimSpace(note)
+	review.ModeratedBy = moderatorID
+	review.ModeratedAt = &now
+	review.UpdatedAt = now
+	if err := s.repo.Save(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// ModerateBulk moves several reviews to the same status. Reviews that don't exist are
+// reported rather than failing the rest.
+func (s *ReviewService) ModerateBulk(ctx context.Context, reviewIDs []string, moderatorID string, status ReviewStatus, note string) (*ModerationResult, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidReview, status)
+	}
+
+	result := &ModerationResult{Status: status, Updated: []string{}, NotFound: []string{}}
+	seen := make(map[string]bool, len(reviewIDs))
+	for _, id := range reviewIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if _, err := s.Moderate(ctx, id, moderatorID, status, note); err != nil {
+			if errors.Is(err, ErrReviewNotFound) {
+				result.NotFound = append(result.NotFound, id)
+				continue
+			}
+			return nil, err
+		}
+		result.Updated = append(result.Updated, id)
+	}
+	return result, nil
+}
+
+// authorReview returns one of the user's own reviews; other users' reviews are reported as
+// not found
+func (s *ReviewService) authorReview(ctx context.Context, userID, reviewID string) (*Review, error) {
+	review, err := s.repo.FindByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// deletePhotoFile removes a photo file no review points at anymore; a failure only leaves
+// an orphaned file behind
+func (s *ReviewService) deletePhotoFile(ctx context.Context, key string) {
+	if err := s.media.Delete(ctx, key); err != nil {
+		log.Printf("Failed to delete review photo %s: %v", key, err)
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInvalidRuntimeConfig = errors.New("invalid runtime configuration")
+)
+
+// ConfigChangeSource is what triggered a configuration reload
+type ConfigChangeSource string
+
+const (
+	ConfigChangeSourceSignal ConfigChangeSource = "signal" // SIGHUP
+	ConfigChangeSourceAdmin  ConfigChangeSource = "admin"  // the admin reload endpoint
+)
+
+// RuntimeSettings are the settings applied without a restart when the configuration is
+// reloaded. Everything else in the configuration is read once at startup.
+type RuntimeSettings struct {
+	MaintenanceMode      bool
+	MaintenanceMessage   string
+	BotThrottlePerMinute int
+	LoadShedMaxInFlight  int
+	LoadShedMaxLatency   time.Duration
+	TaxRate              float64
+	FallbackTaxRate      float64
+	SpellCorrection      bool
+}
+
+// Values returns the settings keyed by the environment variables they are read from
+func (s RuntimeSettings) Values() map[string]string {
+	return map[string]string{
+		"MAINTENANCE_MODE":        strconv.FormatBool(s.MaintenanceMode),
+		"MAINTENANCE_MESSAGE":     s.MaintenanceMessage,
+		"BOT_THROTTLE_PER_MINUTE": strconv.Itoa(s.BotThrottlePerMinute),
+		"LOAD_SHED_MAX_IN_FLIGHT": strconv.Itoa(s.LoadShedMaxInFlight),
+		"LOAD_SHED_MAX_LATENCY":   s.LoadShedMaxLatency.String(),
+		"TAX_RATE":                strconv.FormatFloat(s.TaxRate, 'f', -1, 64),
+		"FALLBACK_TAX_RATE":       strconv.FormatFloat(s.FallbackTaxRate, 'f', -1, 64),
+		"SEARCH_SPELL_CORRECTION": strconv.FormatBool(s.SpellCorrection),
+	}
+}
+
+// ConfigChange records one runtime setting changed by a reload
+type ConfigChange struct {
+	ID        string             `json:"id"`
+	Setting   string             `json:"setting"`
+	OldValue  string             `json:"old_value"`
+	NewValue  string             `json:"new_value"`
+	Source    ConfigChangeSource `json:"source"`
+	ActorID   *string            `json:"actor_id,omitempty"` // the admin who reloaded; nil for SIGHUP
+	ChangedAt time.Time          `json:"changed_at"`
+}
+
+// ConfigChangeFilter filters the configuration change audit log
+type ConfigChangeFilter struct {
+	Setting string
+	Limit   int
+	Offset  int
+}
+
+// ConfigChangeRepository defines persistence for the configuration change audit log
+type ConfigChangeRepository interface {
+	// CreateBatch stores the changes of one reload together
+	CreateBatch(ctx context.Context, changes []*ConfigChange) error
+	List(ctx context.Context, filter ConfigChangeFilter) ([]*ConfigChange, error)
+	Count(ctx context.Context, filter ConfigChangeFilter) (int64, error)
+}
+
+// RuntimeConfigService holds the runtime settings and reloads them on request. A reload
+// reads the configuration again, records each changed setting in the audit log and then
+// hands the new settings to the components registered with OnChange.
+type RuntimeConfigService struct {
+	repo ConfigChangeRepository
+	load func() (RuntimeSettings, error)
+
+	current    atomic.Pointer[RuntimeSettings]
+	reloadedAt atomic.Pointer[time.Time]
+
+	mu    sync.Mutex // one reload at a time
+	hooks []func(RuntimeSettings)
+}
+
+// NewRuntimeConfigService creates a new RuntimeConfigService starting from initial. load
+// reads the configuration again and fails when it is invalid.
+func NewRuntimeConfigService(repo ConfigChangeRepository, initial RuntimeSettings, load func() (RuntimeSettings, error)) *RuntimeConfigService {
+	s := &RuntimeConfigService{repo: repo, load: load}
+	s.current.Store(&initial)
+	return s
+}
+
+// OnChange calls fn with the new settings after every reload that changes one
+func (s *RuntimeConfigService) OnChange(fn func(RuntimeSettings)) *RuntimeConfigService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, fn)
+	return s
+}
+
+// Current returns the settings in effect
+func (s *RuntimeConfigService) Current() RuntimeSettings {
+	return *s.current.Load()
+}
+
+// ReloadedAt returns when a reload last changed the settings, or nil if none has
+func (s *RuntimeConfigService) ReloadedAt() *time.Time {
+	return s.reloadedAt.Load()
+}
+
+// Reload reads the configuration again and applies the runtime settings that changed,
+// returning them. An invalid configuration changes nothing and returns
+// ErrInvalidRuntimeConfig. Changes are audited before they take effect, so a reload that
+// can't be recorded is not applied.
+func (s *RuntimeConfigService) Reload(ctx context.Context, source ConfigChangeSource, actorID *string) ([]*ConfigChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRuntimeConfig, err)
+	}
+
+	now := time.Now()
+	oldValues := s.Current().Values()
+	newValues := next.Values()
+	settings := make([]string, 0, len(newValues))
+	for setting := range newValues {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+
+	changes := []*ConfigChange{}
+	for _, setting := range settings {
+		if oldValues[setting] == newValues[setting] {
+			continue
+		}
+		changes = append(changes, &ConfigChange{
+			ID:        utils.GenerateID(),
+			Setting:   setting,
+			OldValue:  oldValues[setting],
+			NewValue:  newValues[setting],
+			Source:    source,
+			ActorID:   actorID,
+			ChangedAt: now,
+		})
+	}
+	if len(changes) == 0 {
+		log.Printf("Configuration reloaded (%s): no runtime settings changed", source)
+		return changes, nil
+	}
+
+	if err := s.repo.CreateBatch(ctx, changes); err != nil {
+		return nil, fmt.Errorf("failed to record configuration changes: %w", err)
+	}
+	s.current.Store(&next)
+	s.reloadedAt.Store(&now)
+	for _, hook := range s.hooks {
+		hook(next)
+	}
+
+	changed := make([]string, len(changes))
+	for i, change := range changes {
+		changed[i] = fmt.Sprintf("%s %q -> %q", change.Setting, change.OldValue, change.NewValue)
+	}
+	log.Printf("Configuration reloaded (%s): %s", source, strings.Join(changed, ", "))
+	return changes, nil
+}
+
+// ListChanges lists audited configuration changes, newest first
+func (s *RuntimeConfigService) ListChanges(ctx context.Context, filter ConfigChangeFilter) ([]*ConfigChange, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountChanges counts audited configuration changes matching the filter
+func (s *RuntimeConfigService) CountChanges(ctx context.Context, filter ConfigChangeFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
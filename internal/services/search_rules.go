@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrSynonymSetNotFound = errors.New("synonym set not found")
+	ErrInvalidSynonymSet  = errors.New("invalid synonym set")
+	ErrSearchRuleNotFound = errors.New("search rule not found")
+	ErrInvalidSearchRule  = errors.New("invalid search rule")
+)
+
+const (
+	// maxSearchKeywords bounds how many keywords synonyms expand one search into
+	maxSearchKeywords = 10
+	// maxRuleProducts bounds how many products a search rule pins or boosts
+	maxRuleProducts = 20
+)
+
+// SynonymSet is a group of search terms that mean the same thing, such as "tee" and
+// "t-shirt". A search for any of them also finds products matching the others.
+type SynonymSet struct {
+	ID        string    `json:"id"`
+	Terms     []string  `json:"terms"` // lower case, at least two
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SearchRule merchandises the results of one search query: pinned products are listed
+// first, in order, whether or not they match, and boosted products that match are listed
+// before the other matches
+type SearchRule struct {
+	ID                string    `json:"id"`
+	Query             string    `json:"query"` // lower case, single spaced
+	PinnedProductIDs  []string  `json:"pinned_product_ids"`
+	BoostedProductIDs []string  `json:"boosted_product_ids"`
+	IsActive          bool      `json:"is_active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SearchPlan is how the search rules apply to one keyword search
+type SearchPlan struct {
+	Keywords []string // the keyword followed by its synonym expansions
+	Pinned   []string // product IDs listed first, in order
+	Boosted  []string // product IDs listed before the other matches
+}
+
+// merchandised reports whether the plan changes the plain keyword search
+func (p *SearchPlan) merchandised() bool {
+	return len(p.Keywords) > 1 || len(p.Pinned) > 0 || len(p.Boosted) > 0
+}
+
+// SearchRuleRepository defines persistence for synonym sets and search rules
+type SearchRuleRepository interface {
+	FindSynonymSet(ctx context.Context, id string) (*SynonymSet, error)
+	FindSynonymSets(ctx context.Context) ([]*SynonymSet, error)
+	SaveSynonymSet(ctx context.Context, set *SynonymSet) error
+	DeleteSynonymSet(ctx context.Context, id string) error
+	FindRule(ctx context.Context, id string) (*SearchRule, error)
+	FindRules(ctx context.Context) ([]*SearchRule, error)
+	SaveRule(ctx context.Context, rule *SearchRule) error
+	DeleteRule(ctx context.Context, id string) error
+}
+
+// SearchRuleService manages the synonyms and merchandising rules applied to product
+// searches, so merchandisers can tune results without a deploy
+type SearchRuleService struct {
+	repo         SearchRuleRepository
+	products     catalog.ProductRepository
+	synonymCache *listCache[*SynonymSet]
+	ruleCache    *listCache[*SearchRule]
+}
+
+// NewSearchRuleService creates a new SearchRuleService. Products are used to check the
+// products a rule pins or boosts exist.
+func NewSearchRuleService(repo SearchRuleRepository, products catalog.ProductRepository) *SearchRuleService {
+	return &SearchRuleService{repo: repo, products: products}
+}
+
+// WithCache keeps the synonym sets and rules in memory for ttl instead of loading them on
+// every search. Changes made through this service take effect at once; changes made on
+// another instance take up to ttl.
+func (s *SearchRuleService) WithCache(ttl time.Duration) *SearchRuleService {
+	s.synonymCache = newListCache[*SynonymSet](ttl)
+	s.ruleCache = newListCache[*SearchRule](ttl)
+	return s
+}
+
+// Plan returns the keywords, pinned and boosted products for a keyword search. Synonyms
+// replace whole words or phrases of the query; a rule applies when its query equals the
+// whole search.
+func (s *SearchRuleService) Plan(ctx context.Context, keyword string) (*SearchPlan, error) {
+	query := normalizeSearchQuery(keyword)
+	plan := &SearchPlan{Keywords: []string{query}}
+	if query == "" {
+		return plan, nil
+	}
+
+	sets, err := s.synonymSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	padded := " " + query + " "
+	for _, set := range sets {
+		for _, term := range set.Terms {
+			if !strings.Contains(padded, " "+term+" ") {
+				continue
+			}
+			for _, synonym := range set.Terms {
+				if synonym == term || len(plan.Keywords) >= maxSearchKeywords {
+					continue
+				}
+				expanded := strings.TrimSpace(strings.ReplaceAll(padded, " "+term+" ", " "+synonym+" "))
+				if !containsField(plan.Keywords, expanded) {
+					plan.Keywords = append(plan.Keywords, expanded)
+				}
+			}
+		}
+	}
+
+	rules, err := s.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.IsActive && rule.Query == query {
+			plan.Pinned = rule.PinnedProductIDs
+			plan.Boosted = rule.BoostedProductIDs
+			break
+		}
+	}
+	return plan, nil
+}
+
+// ListSynonymSets returns every synonym set
+func (s *SearchRuleService) ListSynonymSets(ctx context.Context) ([]*SynonymSet, error) {
+	return s.repo.FindSynonymSets(ctx)
+}
+
+// GetSynonymSet returns a synonym set
+func (s *SearchRuleService) GetSynonymSet(ctx context.Context, id string) (*SynonymSet, error) {
+	return s.repo.FindSynonymSet(ctx, id)
+}
+
+// SaveSynonymSet validates and saves a synonym set, creating it when it has no ID
+func (s *SearchRuleService) SaveSynonymSet(ctx context.Context, set *SynonymSet) error {
+	if set.ID != "" {
+		existing, err := s.repo.FindSynonymSet(ctx, set.ID)
+		if err != nil {
+			return err
+		}
+		set.CreatedAt = existing.CreatedAt
+	}
+
+	terms := make([]string, 0, len(set.Terms))
+	for _, term := range set.Terms {
+		term = normalizeSearchQuery(term)
+		if term != "" && !containsField(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) < 2 {
+		return fmt.Errorf("%w: at least two different terms are required", ErrInvalidSynonymSet)
+	}
+	set.Terms = terms
+
+	now := time.Now()
+	if set.ID == "" {
+		set.ID = utils.GenerateID()
+		set.CreatedAt = now
+	}
+	set.UpdatedAt = now
+
+	if err := s.repo.SaveSynonymSet(ctx, set); err != nil {
+		return err
+	}
+	s.flushSynonyms()
+	return nil
+}
+
+// DeleteSynonymSet deletes a synonym set
+func (s *SearchRuleService) DeleteSynonymSet(ctx context.Context, id string) error {
+	if err := s.repo.DeleteSynonymSet(ctx, id); err != nil {
+		return err
+	}
+	s.flushSynonyms()
+	return nil
+}
+
+// ListRules returns every search rule
+func (s *SearchRuleService) ListRules(ctx context.Context) ([]*SearchRule, error) {
+	return s.repo.FindRules(ctx)
+}
+
+// GetRule returns a search rule
+func (s *SearchRuleService) GetRule(ctx context.Context, id string) (*SearchRule, error) {
+	return s.repo.FindRule(ctx, id)
+}
+
+// SaveRule validates and saves a search rule, creating it when it has no ID. Each query
+// has at most one rule, and every pinned and boosted product must exist.
+func (s *SearchRuleService) SaveRule(ctx context.Context, rule *SearchRule) error {
+	if rule.ID != "" {
+		existing, err := s.repo.FindRule(ctx, rule.ID)
+		if err != nil {
+			return err
+		}
+		rule.CreatedAt = existing.CreatedAt
+	}
+
+	rule.Query = normalizeSearchQuery(rule.Query)
+	if rule.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidSearchRule)
+	}
+	rule.PinnedProductIDs = uniqueStrings(rule.PinnedProductIDs)
+	rule.BoostedProductIDs = uniqueStrings(rule.BoostedProductIDs)
+	if len(rule.PinnedProductIDs) == 0 && len(rule.BoostedProductIDs) == 0 {
+		return fmt.Errorf("%w: pin or boost at least one product", ErrInvalidSearchRule)
+	}
+	if len(rule.PinnedProductIDs) > maxRuleProducts || len(rule.BoostedProductIDs) > maxRuleProducts {
+		return fmt.Errorf("%w: pin and boost at most %d products each", ErrInvalidSearchRule, maxRuleProducts)
+	}
+	for _, id := range append(append([]string{}, rule.PinnedProductIDs...), rule.BoostedProductIDs...) {
+		if _, err := s.products.FindByID(ctx, id); err != nil {
+			return fmt.Errorf("%w: product %s not found", ErrInvalidSearchRule, id)
+		}
+	}
+
+	rules, err := s.repo.FindRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range rules {
+		if other.ID != rule.ID && other.Query == rule.Query {
+			return fmt.Errorf("%w: a rule for %q already exists", ErrInvalidSearchRule, rule.Query)
+		}
+	}
+
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = utils.GenerateID()
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return err
+	}
+	s.flushRules()
+	return nil
+}
+
+// DeleteRule deletes a search rule
+func (s *SearchRuleService) DeleteRule(ctx context.Context, id string) error {
+	if err := s.repo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	s.flushRules()
+	return nil
+}
+
+func (s *SearchRuleService) synonymSets(ctx context.Context) ([]*SynonymSet, error) {
+	if s.synonymCache != nil {
+		return s.synonymCache.get(ctx, s.repo.FindSynonymSets)
+	}
+	return s.repo.FindSynonymSets(ctx)
+}
+
+func (s *SearchRuleService) rules(ctx context.Context) ([]*SearchRule, error) {
+	if s.ruleCache != nil {
+		return s.ruleCache.get(ctx, s.repo.FindRules)
+	}
+	return s.repo.FindRules(ctx)
+}
+
+func (s *SearchRuleService) flushSynonyms() {
+	if s.synonymCache != nil {
+		s.synonymCache.flush()
+	}
+}
+
+func (s *SearchRuleService) flushRules() {
+	if s.ruleCache != nil {
+		s.ruleCache.flush()
+	}
+}
+
+// normalizeSearchQuery lower-cases a query and collapses its whitespace
+func normalizeSearchQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrSuggestQueryRequired = errors.New("q is required")
+
+const (
+	// minSuggestLength is the shortest query suggestions are looked up for; shorter
+	// queries match too much to be useful
+	minSuggestLength = 2
+	// DefaultSuggestLimit and MaxSuggestLimit bound how many suggestions are returned
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 20
+)
+
+// SuggestionType is what a suggestion points to
+type SuggestionType string
+
+const (
+	SuggestCategory SuggestionType = "category"
+	SuggestBrand    SuggestionType = "brand"
+	SuggestProduct  SuggestionType = "product"
+)
+
+// suggestionTypes lists the suggestion types in the order they rank when they match a
+// query equally well: a category or brand narrows a search more than one product
+var suggestionTypes = []SuggestionType{SuggestCategory, SuggestBrand, SuggestProduct}
+
+// Suggestion is a type-ahead suggestion for a storefront search box
+type Suggestion struct {
+	Type SuggestionType `json:"type"`
+	ID   string         `json:"id"`
+	Text string         `json:"text"`
+	Slug string         `json:"slug,omitempty"`
+}
+
+// SuggestionRepository finds suggestion candidates
+type SuggestionRepository interface {
+	// Suggest returns up to limit active records of a type whose name, or a word in it,
+	// starts with prefix, ignoring case. prefix is lower case.
+	Suggest(ctx context.Context, suggestionType SuggestionType, prefix string, limit int) ([]*Suggestion, error)
+}
+
+// SuggestionService suggests product names, categories and brands as shoppers type
+type SuggestionService struct {
+	repo SuggestionRepository
+}
+
+// NewSuggestionService creates a new SuggestionService
+func NewSuggestionService(repo SuggestionRepository) *SuggestionService {
+	return &SuggestionService{repo: repo}
+}
+
+// Suggest returns up to limit suggestions for a partly typed query, best first: names
+// equal to the query, then names starting with it, then names with a word starting with
+// it. Equal matches rank categories, then brands, then products, shorter names first.
+func (s *SuggestionService) Suggest(ctx context.Context, query string, limit int) ([]*Suggestion, error) {
+	prefix := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if prefix == "" {
+		return nil, ErrSuggestQueryRequired
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit {
+		limit = MaxSuggestLimit
+	}
+	if utf8.RuneCountInString(prefix) < minSuggestLength {
+		return []*Suggestion{}, nil
+	}
+
+	type ranked struct {
+		suggestion *Suggestion
+		match      int
+		typeRank   int
+	}
+	candidates := []ranked{}
+	for typeRank, suggestionType := range suggestionTypes {
+		suggestions, err := s.repo.Suggest(ctx, suggestionType, prefix, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, suggestion := range suggestions {
+			if match := suggestMatch(suggestion.Text, prefix); match >= 0 {
+				candidates = append(candidates, ranked{suggestion: suggestion, match: match, typeRank: typeRank})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.match != b.match {
+			return a.match < b.match
+		}
+		if a.typeRank != b.typeRank {
+			return a.typeRank < b.typeRank
+		}
+		if len(a.suggestion.Text) != len(b.suggestion.Text) {
+			return len(a.suggestion.Text) < len(b.suggestion.Text)
+		}
+		return a.suggestion.Text < b.suggestion.Text
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	suggestions := make([]*Suggestion, len(candidates))
+	for i, candidate := range candidates {
+		suggestions[i] = candidate.suggestion
+	}
+	return suggestions, nil
+}
+
+// suggestMatch ranks how text matches prefix: 0 equal, 1 starts with it, 2 has a word
+// starting with it, -1 no match
+func suggestMatch(text, prefix string) int {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	switch {
+	case text == prefix:
+		return 0
+	case strings.HasPrefix(text, prefix):
+		return 1
+	case strings.Contains(text, " "+prefix):
+		return 2
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+var (
+	ErrShippingMethodNotFound = errors.New("shipping method not found")
+	ErrInvalidShippingMethod  = errors.New("invalid shipping method")
+)
+
+// ShippingMethod is a shipping method offered in a set of countries at a flat rate or, with
+// rate tiers, at a rate looked up by order subtotal (table rate). Methods price shipping to
+// destinations no shipping zone covers; a zone's rates take precedence where one matches.
+type ShippingMethod struct {
+	ID               string             `json:"id"` // code orders select it by, e.g. "standard"
+	Name             string             `json:"name"`
+	Description      string             `json:"description,omitempty"`
+	Carrier          string             `json:"carrier,omitempty"`
+	ServiceLevel     string             `json:"service_level,omitempty"`
+	Air              bool               `json:"air"`                 // shipped by air; ruled out for products restricted to ground
+	Countries        []string           `json:"countries,omitempty"` // ISO codes served; empty serves every country
+	FlatRate         money.Money        `json:"flat_rate"`
+	RateTiers        []ShippingRateTier `json:"rate_tiers,omitempty"`
+	FreeShippingMin  *money.Money       `json:"free_shipping_min,omitempty"`
+	EstimatedDaysMin int                `json:"estimated_days_min"`
+	EstimatedDaysMax int                `json:"estimated_days_max"`
+	SortOrder        int                `json:"sort_order"`
+	IsActive         bool               `json:"is_active"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// ShippingRateTier is the cost of a method for orders whose subtotal is at least MinSubtotal
+type ShippingRateTier struct {
+	MinSubtotal money.Money `json:"min_subtotal"`
+	Cost        money.Money `json:"cost"`
+}
+
+// Serves reports whether the method ships to the country
+func (m *ShippingMethod) Serves(country string) bool {
+	if len(m.Countries) == 0 {
+		return true
+	}
+	country = strings.TrimSpace(country)
+	for _, served := range m.Countries {
+		if strings.EqualFold(served, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cost prices the method for an order subtotal: free from the free shipping minimum, else
+// the tier with the highest minimum the subtotal reaches, else the flat rate. A subtotal in
+// another currency, or none, is priced at the flat rate.
+func (m *ShippingMethod) Cost(subtotal money.Money) money.Money {
+	if subtotal.Currency != m.FlatRate.Currency {
+		return m.FlatRate
+	}
+	if m.FreeShippingMin != nil && subtotal.Amount >= m.FreeShippingMin.Amount {
+		return money.Zero(m.FlatRate.Currency)
+	}
+
+	cost := m.FlatRate
+	reached := int64(-1)
+	for _, tier := range m.RateTiers {
+		if subtotal.Amount >= tier.MinSubtotal.Amount && tier.MinSubtotal.Amount > reached {
+			cost, reached = tier.Cost, tier.MinSubtotal.Amount
+		}
+	}
+	return cost
+}
+
+// validate checks the method's code, name, delivery estimate and that all its amounts are
+// non-negative and in the flat rate's currency
+func (m *ShippingMethod) validate() error {
+	switch {
+	case m.ID == "" || strings.ContainsAny(m.ID, " /"):
+		return fmt.Errorf("%w: id is required and may not contain spaces or slashes", ErrInvalidShippingMethod)
+	case m.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidShippingMethod)
+	case m.FlatRate.Currency == "" || m.FlatRate.IsNegative():
+		return fmt.Errorf("%w: flat rate requires a currency and may not be negative", ErrInvalidShippingMethod)
+	case m.EstimatedDaysMin < 0 || m.EstimatedDaysMax < m.EstimatedDaysMin:
+		return fmt.Errorf("%w: estimated days must be non-negative with min no greater than max", ErrInvalidShippingMethod)
+	}
+
+	amounts := make([]money.Money, 0, 2*len(m.RateTiers)+1)
+	for _, tier := range m.RateTiers {
+		amounts = append(amounts, tier.MinSubtotal, tier.Cost)
+	}
+	if m.FreeShippingMin != nil {
+		amounts = append(amounts, *m.FreeShippingMin)
+	}
+	for _, amount := range amounts {
+		if amount.Currency != m.FlatRate.Currency || amount.IsNegative() {
+			return fmt.Errorf("%w: rate tiers and free shipping minimum must be non-negative %s amounts", ErrInvalidShippingMethod, m.FlatRate.Currency)
+		}
+	}
+	return nil
+}
+
+// zoneRate prices the method for subtotal as a zone rate
+func (m *ShippingMethod) zoneRate(subtotal money.Money) ShippingZoneRate {
+	return ShippingZoneRate{
+		ID:               m.ID,
+		MethodID:         m.ID,
+		MethodName:       m.Name,
+		Carrier:          m.Carrier,
+		ServiceLevel:     m.ServiceLevel,
+		Air:              m.Air,
+		Cost:             m.Cost(subtotal),
+		EstimatedDaysMin: m.EstimatedDaysMin,
+		EstimatedDaysMax: m.EstimatedDaysMax,
+	}
+}
+
+// ShippingMethodRepository defines persistence for shipping methods
+type ShippingMethodRepository interface {
+	FindByID(ctx context.Context, id string) (*ShippingMethod, error)
+	FindAll(ctx context.Context) ([]*ShippingMethod, error)
+	FindActive(ctx context.Context) ([]*ShippingMethod, error)
+	Save(ctx context.Context, method *ShippingMethod) error
+	Delete(ctx context.Context, id string) error
+}
+
+type shippingSubtotalKey struct{}
+
+// WithShippingSubtotal records the order subtotal that table rates and free shipping
+// minimums are priced against; shipping.RateRequest carries no amounts
+func WithShippingSubtotal(ctx context.Context, subtotal money.Money) context.Context {
+	return context.WithValue(ctx, shippingSubtotalKey{}, subtotal)
+}
+
+// shippingSubtotal returns the order subtotal recorded on ctx, if any
+func shippingSubtotal(ctx context.Context) money.Money {
+	subtotal, _ := ctx.Value(shippingSubtotalKey{}).(money.Money)
+	return subtotal
+}
+
+// ShippingMethodService manages shipping methods and quotes them for a destination country
+type ShippingMethodService struct {
+	repo ShippingMethodRepository
+}
+
+// NewShippingMethodService creates a new ShippingMethodService
+func NewShippingMethodService(repo ShippingMethodRepository) *ShippingMethodService {
+	return &ShippingMethodService{repo: repo}
+}
+
+// ListMethods returns all shipping methods
+func (s *ShippingMethodService) ListMethods(ctx context.Context) ([]*ShippingMethod, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetMethod returns a shipping method by ID
+func (s *ShippingMethodService) GetMethod(ctx context.Context, id string) (*ShippingMethod, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SaveMethod validates and saves a shipping method, keeping the creation time of an
+// existing one
+func (s *ShippingMethodService) SaveMethod(ctx context.Context, method *ShippingMethod) error {
+	method.ID = strings.ToLower(strings.TrimSpace(method.ID))
+	for i, country := range method.Countries {
+		method.Countries[i] = strings.ToUpper(strings.TrimSpace(country))
+	}
+	if err := method.validate(); err != nil {
+		return err
+	}
+	sort.Slice(method.RateTiers, func(i, j int) bool {
+		return method.RateTiers[i].MinSubtotal.Amount < method.RateTiers[j].MinSubtotal.Amount
+	})
+
+	now := time.Now()
+	method.CreatedAt = now
+	existing, err := s.repo.FindByID(ctx, method.ID)
+	switch {
+	case err == nil:
+		method.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, ErrShippingMethodNotFound):
+		return err
+	}
+	method.UpdatedAt = now
+	return s.repo.Save(ctx, method)
+}
+
+// DeleteMethod deletes a shipping method
+func (s *ShippingMethodService) DeleteMethod(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// MethodsFor returns the active methods serving the country, in sort order
+func (s *ShippingMethodService) MethodsFor(ctx context.Context, country string) ([]*ShippingMethod, error) {
+	methods, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serving := make([]*ShippingMethod, 0, len(methods))
+	for _, method := range methods {
+		if method.Serves(country) {
+			serving = append(serving, method)
+		}
+	}
+	sort.SliceStable(serving, func(i, j int) bool {
+		if serving[i].SortOrder != serving[j].SortOrder {
+			return serving[i].SortOrder < serving[j].SortOrder
+		}
+		return serving[i].Name < serving[j].Name
+	})
+	return serving, nil
+}
+
+// zoneFor builds a zone offering the methods serving the country, priced for the subtotal
+// recorded on ctx, for a destination no shipping zone covers
+func (s *ShippingMethodService) zoneFor(ctx context.Context, country string) (*ShippingZone, error) {
+	methods, err := s.MethodsFor(ctx, country)
+	if err != nil {
+		return nil, err
+	}
+	if len(methods) == 0 {
+		return nil, ErrShippingZoneNotFound
+	}
+
+	subtotal := shippingSubtotal(ctx)
+	zone := &ShippingZone{
+		ID:       "shipping-methods",
+		Name:     "Shipping methods",
+		Country:  strings.ToUpper(strings.TrimSpace(country)),
+		IsActive: true,
+		Rates:    make([]ShippingZoneRate, len(methods)),
+	}
+	for i, method := range methods {
+		zone.Rates[i] = method.zoneRate(subtotal)
+	}
+	return zone, nil
+}
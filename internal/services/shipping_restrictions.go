@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/shipping"
+)
+
+var (
+	ErrShippingRestrictionNotFound = errors.New("shipping restriction not found")
+	ErrInvalidShippingRestriction  = errors.New("shipping restriction needs at least one restriction, carriers without blanks and an age between 0 and 130")
+)
+
+// Shipping restriction kinds reported in violations
+const (
+	RestrictionNoAir    = "no_air"
+	RestrictionCarriers = "carriers"
+	RestrictionNoPOBox  = "no_po_box"
+	RestrictionMinAge   = "min_age"
+)
+
+// poBoxPattern matches PO box addresses such as "PO Box 12", "P.O. Box 12" and "Post Office Box 12"
+var poBoxPattern = regexp.MustCompile(`(?i)\b(p\.?\s*o\.?\s*box|post\s+office\s+box)\b`)
+
+// ShippingRestriction limits how a hazardous or regulated product can be shipped
+type ShippingRestriction struct {
+	ProductID string    `json:"product_id"`
+	NoAir     bool      `json:"no_air"`             // only ground shipping methods
+	Carriers  []string  `json:"carriers,omitempty"` // only these carriers; empty allows any
+	NoPOBox   bool      `json:"no_po_box"`
+	MinAge    int       `json:"min_age,omitempty"` // buyer's minimum age; 0 for none
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShippingRestrictionViolation explains why an order breaks one product's restriction
+type ShippingRestrictionViolation struct {
+	ProductID   string `json:"product_id"`
+	Restriction string `json:"restriction"`
+	Message     string `json:"message"`
+}
+
+// ShippingRestrictionError reports every shipping restriction an order breaks
+type ShippingRestrictionError struct {
+	Violations []ShippingRestrictionViolation
+}
+
+func (e *ShippingRestrictionError) Error() string {
+	if len(e.Violations) == 1 {
+		return e.Violations[0].Message
+	}
+	return fmt.Sprintf("order breaks %d shipping restrictions", len(e.Violations))
+}
+
+// ShippingRestrictionRepository defines persistence for product shipping restrictions
+type ShippingRestrictionRepository interface {
+	FindByProductID(ctx context.Context, productID string) (*ShippingRestriction, error)
+	FindByProductIDs(ctx context.Context, productIDs []string) ([]*ShippingRestriction, error)
+	Save(ctx context.Context, restriction *ShippingRestriction) error
+	Delete(ctx context.Context, productID string) error
+}
+
+// ShippingOrder is what an order's shipping restrictions are checked against
+type ShippingOrder struct {
+	Address          orders.Address
+	ShippingMethodID string
+	DateOfBirth      string // YYYY-MM-DD; required for age-restricted products
+}
+
+// ShippingRestrictionService filters shipping options and validates orders against the
+// shipping restrictions of the products in the cart
+type ShippingRestrictionService struct {
+	repo     ShippingRestrictionRepository
+	shipping *ShippingZoneService
+}
+
+// NewShippingRestrictionService creates a new ShippingRestrictionService. Shipping methods
+// are looked up in the destination's zone of shippingService.
+func NewShippingRestrictionService(repo ShippingRestrictionRepository, shippingService *ShippingZoneService) *ShippingRestrictionService {
+	return &ShippingRestrictionService{repo: repo, shipping: shippingService}
+}
+
+// AllowedRates returns the shipping options for the destination that every product in the
+// cart may ship with
+func (s *ShippingRestrictionService) AllowedRates(ctx context.Context, c *cart.Cart, address shipping.Address) ([]*shipping.ShippingRate, error) {
+	zone, err := s.shipping.ResolveZone(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	restrictions, err := s.forCart(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]*shipping.ShippingRate, 0, len(zone.Rates))
+	for _, rate := range zone.Rates {
+		if len(rateViolations(restrictions, rate)) == 0 {
+			rates = append(rates, rate.toShippingRate())
+		}
+	}
+	return rates, nil
+}
+
+// CheckOrder returns a *ShippingRestrictionError listing every restriction the order
+// breaks: a shipping method by air or by another carrier, a PO box address, or a buyer
+// under age or without a date of birth
+func (s *ShippingRestrictionService) CheckOrder(ctx context.Context, c *cart.Cart, order ShippingOrder) error {
+	restrictions, err := s.forCart(ctx, c)
+	if err != nil || len(restrictions) == 0 {
+		return err
+	}
+
+	var violations []ShippingRestrictionViolation
+	if order.ShippingMethodID != "" {
+		rate, err := s.findRate(ctx, order.Address, order.ShippingMethodID)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, rateViolations(restrictions, *rate)...)
+	}
+
+	isPOBox := poBoxPattern.MatchString(order.Address.AddressLine1 + " " + order.Address.AddressLine2)
+	age, ageErr := ageOn(order.DateOfBirth, time.Now())
+	for _, restriction := range restrictions {
+		if restriction.NoPOBox && isPOBox {
+			violations = append(violations, ShippingRestrictionViolation{
+				ProductID:   restriction.ProductID,
+				Restriction: RestrictionNoPOBox,
+				Message:     fmt.Sprintf("product %s cannot be shipped to a PO box", restriction.ProductID),
+			})
+		}
+		if restriction.MinAge > 0 && (ageErr != nil || age < restriction.MinAge) {
+			violations = append(violations, ShippingRestrictionViolation{
+				ProductID:   restriction.ProductID,
+				Restriction: RestrictionMinAge,
+				Message:     fmt.Sprintf("product %s requires a date of birth showing the buyer is at least %d", restriction.ProductID, restriction.MinAge),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ShippingRestrictionError{Violations: violations}
+	}
+	return nil
+}
+
+// GetRestriction returns a product's shipping restriction
+func (s *ShippingRestrictionService) GetRestriction(ctx context.Context, productID string) (*ShippingRestriction, error) {
+	return s.repo.FindByProductID(ctx, productID)
+}
+
+// SetRestriction validates and saves a product's shipping restriction, replacing any existing one
+func (s *ShippingRestrictionService) SetRestriction(ctx context.Context, restriction *ShippingRestriction) error {
+	if restriction.ProductID == "" || restriction.MinAge < 0 || restriction.MinAge > 130 {
+		return ErrInvalidShippingRestriction
+	}
+	for i, carrier := range restriction.Carriers {
+		if restriction.Carriers[i] = strings.TrimSpace(carrier); restriction.Carriers[i] == "" {
+			return ErrInvalidShippingRestriction
+		}
+	}
+	if !restriction.NoAir && len(restriction.Carriers) == 0 && !restriction.NoPOBox && restriction.MinAge == 0 {
+		return ErrInvalidShippingRestriction
+	}
+	restriction.UpdatedAt = time.Now()
+	return s.repo.Save(ctx, restriction)
+}
+
+// RemoveRestriction removes a product's shipping restriction
+func (s *ShippingRestrictionService) RemoveRestriction(ctx context.Context, productID string) error {
+	return s.repo.Delete(ctx, productID)
+}
+
+// forCart returns the shipping restrictions of the products in the cart
+func (s *ShippingRestrictionService) forCart(ctx context.Context, c *cart.Cart) ([]*ShippingRestriction, error) {
+	quantities := CartQuantities(c)
+	if len(quantities) == 0 {
+		return nil, nil
+	}
+	productIDs := make([]string, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	return s.repo.FindByProductIDs(ctx, productIDs)
+}
+
+// findRate finds a shipping method in the destination's zone
+func (s *ShippingRestrictionService) findRate(ctx context.Context, address orders.Address, methodID string) (*ShippingZoneRate, error) {
+	zone, err := s.shipping.ResolveZone(ctx, shipping.Address{
+		Country:    address.Country,
+		State:      address.State,
+		City:       address.City,
+		PostalCode: address.PostalCode,
+	})
+	if err != nil {
+		if err == ErrShippingZoneNotFound {
+			return nil, ErrShippingMethodUnavailable
+		}
+		return nil, err
+	}
+	for i := range zone.Rates {
+		if zone.Rates[i].MethodID == methodID {
+			return &zone.Rates[i], nil
+		}
+	}
+	return nil, ErrShippingMethodUnavailable
+}
+
+// rateViolations lists the restrictions that rule out a shipping method
+func rateViolations(restrictions []*ShippingRestriction, rate ShippingZoneRate) []ShippingRestrictionViolation {
+	var violations []ShippingRestrictionViolation
+	for _, restriction := range restrictions {
+		if restriction.NoAir && rate.Air {
+			violations = append(violations, ShippingRestrictionViolation{
+				ProductID:   restriction.ProductID,
+				Restriction: RestrictionNoAir,
+				Message:     fmt.Sprintf("product %s cannot be shipped by air", restriction.ProductID),
+			})
+		}
+		if len(restriction.Carriers) > 0 && !containsFold(restriction.Carriers, rate.Carrier) {
+			violations = append(violations, ShippingRestrictionViolation{
+				ProductID:   restriction.ProductID,
+				Restriction: RestrictionCarriers,
+				Message:     fmt.Sprintf("product %s can only be shipped with %s", restriction.ProductID, strings.Join(restriction.Carriers, ", ")),
+			})
+		}
+	}
+	return violations
+}
+
+// ageOn returns the age in whole years on the given day of someone born on a YYYY-MM-DD date
+func ageOn(dateOfBirth string, now time.Time) (int, error) {
+	born, err := time.Parse("2006-01-02", dateOfBirth)
+	if err != nil {
+		return 0, err
+	}
+	age := now.Year() - born.Year()
+	if now.Month() < born.Month() || (now.Month() == born.Month() && now.Day() < born.Day()) {
+		age--
+	}
+	return age, nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/shipping"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrShippingZoneNotFound      = errors.New("shipping zone not found")
+	ErrInvalidShippingZone       = errors.New("shipping zone requires a name, a country and at least one rate")
+	ErrShippingMethodUnavailable = errors.New("shipping method is not available for this address")
+)
+
+// ShippingZone groups destinations by country, state and postal code patterns.
+// Patterns match exactly or by prefix when they end in "*" (e.g. "941*").
+type ShippingZone struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	Country            string             `json:"country"`
+	State              string             `json:"state,omitempty"`
+	PostalCodePatterns []string           `json:"postal_code_patterns,omitempty"`
+	Priority           int                `json:"priority"`
+	IsActive           bool               `json:"is_active"`
+	Rates              []ShippingZoneRate `json:"rates"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// ShippingZoneRate is a shipping method offered in a zone at a flat cost
+type ShippingZoneRate struct {
+	ID               string      `json:"id"`
+	MethodID         string      `json:"method_id"`
+	MethodName       string      `json:"method_name"`
+	Carrier          string      `json:"carrier,omitempty"`
+	ServiceLevel     string      `json:"service_level,omitempty"`
+	Air              bool        `json:"air"` // shipped by air; ruled out for products restricted to ground
+	Cost             money.Money `json:"cost"`
+	EstimatedDaysMin int         `json:"estimated_days_min"`
+	EstimatedDaysMax int         `json:"estimated_days_max"`
+}
+
+// Matches reports whether the zone covers the destination address
+func (z *ShippingZone) Matches(address shipping.Address) bool {
+	if !strings.EqualFold(z.Country, strings.TrimSpace(address.Country)) {
+		return false
+	}
+	if z.State != "" && !strings.EqualFold(z.State, strings.TrimSpace(address.State)) {
+		return false
+	}
+	if len(z.PostalCodePatterns) == 0 {
+		return true
+	}
+
+	postalCode := NormalizePostalCode(address.PostalCode)
+	for _, pattern := range z.PostalCodePatterns {
+		pattern = NormalizePostalCode(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(postalCode, prefix) {
+				return true
+			}
+		} else if postalCode == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// specificity ranks postal code zones above state zones above country-wide zones
+func (z *ShippingZone) specificity() int {
+	score := 0
+	if len(z.PostalCodePatterns) > 0 {
+		score += 2
+	}
+	if z.State != "" {
+		score++
+	}
+	return score
+}
+
+// ShippingZoneRepository defines persistence for shipping zones and their rates
+type ShippingZoneRepository interface {
+	FindByID(ctx context.Context, id string) (*ShippingZone, error)
+	FindAll(ctx context.Context) ([]*ShippingZone, error)
+	FindActive(ctx context.Context) ([]*ShippingZone, error)
+	Save(ctx context.Context, zone *ShippingZone) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ShippingZoneService manages shipping zones and prices shipping from them.
+// It implements shipping.RateCalculator so it can be plugged into the pricing service.
+type ShippingZoneService struct {
+	repo    ShippingZoneRepository
+	methods *ShippingMethodService
+}
+
+// NewShippingZoneService creates a new ShippingZoneService
+func NewShippingZoneService(repo ShippingZoneRepository) *ShippingZoneService {
+	return &ShippingZoneService{repo: repo}
+}
+
+// WithMethods offers the shipping methods serving a destination's country, at their flat
+// or table rates, where no zone covers it
+func (s *ShippingZoneService) WithMethods(methods *ShippingMethodService) *ShippingZoneService {
+	s.methods = methods
+	return s
+}
+
+// ResolveZone finds the zone that applies to an address: highest priority first,
+// then the most specific match. Without a match, the shipping methods serving the country
+// are offered as a zone of their own.
+func (s *ShippingZoneService) ResolveZone(ctx context.Context, address shipping.Address) (*ShippingZone, error) {
+	zones, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*ShippingZone
+	for _, zone := range zones {
+		if zone.Matches(address) {
+			matches = append(matches, zone)
+		}
+	}
+	if len(matches) == 0 {
+		if s.methods != nil {
+			return s.methods.zoneFor(ctx, address.Country)
+		}
+		return nil, ErrShippingZoneNotFound
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Priority != matches[j].Priority {
+			return matches[i].Priority > matches[j].Priority
+		}
+		return matches[i].specificity() > matches[j].specificity()
+	})
+	return matches[0], nil
+}
+
+// GetAvailableRates returns the shipping options for the destination address
+func (s *ShippingZoneService) GetAvailableRates(ctx context.Context, req shipping.RateRequest) ([]*shipping.ShippingRate, error) {
+	zone, err := s.ResolveZone(ctx, req.DestinationAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]*shipping.ShippingRate, len(zone.Rates))
+	for i, rate := range zone.Rates {
+		rates[i] = rate.toShippingRate()
+	}
+	return rates, nil
+}
+
+// GetRate returns the rate for the requested shipping method at the destination address
+func (s *ShippingZoneService) GetRate(ctx context.Context, req shipping.RateRequest) (*shipping.ShippingRate, error) {
+	rates, err := s.GetAvailableRates(ctx, req)
+	if err != nil {
+		if err == ErrShippingZoneNotFound {
+			return nil, ErrShippingMethodUnavailable
+		}
+		return nil, err
+	}
+
+	for _, rate := range rates {
+		if rate.MethodID == req.ShippingMethodID {
+			return rate, nil
+		}
+	}
+	return nil, ErrShippingMethodUnavailable
+}
+
+// ListZones returns all shipping zones
+func (s *ShippingZoneService) ListZones(ctx context.Context) ([]*ShippingZone, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetZone returns a shipping zone by ID
+func (s *ShippingZoneService) GetZone(ctx context.Context, id string) (*ShippingZone, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SaveZone validates and saves a shipping zone, assigning IDs to new zones and rates
+func (s *ShippingZoneService) SaveZone(ctx context.Context, zone *ShippingZone) error {
+	if zone.Name == "" || zone.Country == "" || len(zone.Rates) == 0 {
+		return ErrInvalidShippingZone
+	}
+
+	now := time.Now()
+	if zone.ID == "" {
+		zone.ID = utils.GenerateID()
+		zone.CreatedAt = now
+	}
+	zone.UpdatedAt = now
+	zone.Country = strings.ToUpper(zone.Country)
+	zone.State = strings.ToUpper(zone.State)
+
+	for i := range zone.Rates {
+		if zone.Rates[i].ID == "" {
+			zone.Rates[i].ID = utils.GenerateID()
+		}
+		if zone.Rates[i].MethodID == "" || zone.Rates[i].Cost.IsNegative() {
+			return ErrInvalidShippingZone
+		}
+	}
+
+	return s.repo.Save(ctx, zone)
+}
+
+// DeleteZone deletes a shipping zone and its rates
+func (s *ShippingZoneService) DeleteZone(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (r ShippingZoneRate) toShippingRate() *shipping.ShippingRate {
+	return &shipping.ShippingRate{
+		MethodID:         r.MethodID,
+		MethodName:       r.MethodName,
+		Cost:             r.Cost,
+		EstimatedDays:    r.EstimatedDaysMax,
+		EstimatedDaysMin: r.EstimatedDaysMin,
+		EstimatedDaysMax: r.EstimatedDaysMax,
+		Carrier:          r.Carrier,
+		ServiceLevel:     r.ServiceLevel,
+	}
+}
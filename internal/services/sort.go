@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSortFields bounds how many keys a ?sort= parameter may have
+const maxSortFields = 3
+
+// SortField is one key of a listing's sort order
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Sort is a listing's sort order, most significant key first. Repositories map each field
+// to a column of their own and order by their default after it, so a nil Sort keeps the
+// default order.
+type Sort []SortField
+
+// String renders the sort the way ParseSort reads it, e.g. -created_at,name
+func (s Sort) String() string {
+	keys := make([]string, len(s))
+	for i, field := range s {
+		keys[i] = field.Field
+		if field.Desc {
+			keys[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// ParseSort reads a comma separated ?sort= parameter such as -created_at,name, where a
+// leading - sorts that field descending. allowed lists the fields the listing can be
+// sorted by. It returns nil when the parameter is empty.
+func ParseSort(raw string, allowed []string) (Sort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	permitted := make(map[string]bool, len(allowed))
+	for _, field := range allowed {
+		permitted[field] = true
+	}
+
+	var sort Sort
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		field := SortField{Field: strings.TrimPrefix(key, "-"), Desc: strings.HasPrefix(key, "-")}
+		if field.Field == "" {
+			continue
+		}
+		if !permitted[field.Field] {
+			return nil, fmt.Errorf("cannot sort by %q; sort by %s", field.Field, strings.Join(allowed, ", "))
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%q is sorted by more than once", field.Field)
+		}
+		seen[field.Field] = true
+		sort = append(sort, field)
+	}
+	if len(sort) > maxSortFields {
+		return nil, fmt.Errorf("sort by at most %d fields", maxSortFields)
+	}
+	return sort, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+// minCorrectedWordLength is the shortest word spelling correction tries to fix; shorter
+// words have too few trigrams to match reliably
+const minCorrectedWordLength = 3
+
+// SearchTermRepository keeps the vocabulary searches are corrected against: the words of
+// active product, category and brand names
+type SearchTermRepository interface {
+	// Closest returns the known term most similar to word, word itself when it is known,
+	// or "" when no term is similar enough
+	Closest(ctx context.Context, word string) (string, error)
+	// Rebuild replaces the vocabulary with the words of the current names, returning how
+	// many terms it holds
+	Rebuild(ctx context.Context) (int64, error)
+}
+
+// SpellingService corrects misspelled search queries against the catalog's vocabulary
+type SpellingService struct {
+	repo SearchTermRepository
+}
+
+// NewSpellingService creates a new SpellingService
+func NewSpellingService(repo SearchTermRepository) *SpellingService {
+	return &SpellingService{repo: repo}
+}
+
+// Correct returns the query with each unknown word replaced by the most similar known
+// term, and whether any word was replaced. The corrected query is lower case.
+func (s *SpellingService) Correct(ctx context.Context, query string) (string, bool, error) {
+	words := strings.Fields(strings.ToLower(query))
+	corrected := false
+	for i, word := range words {
+		if utf8.RuneCountInString(word) < minCorrectedWordLength {
+			continue
+		}
+		closest, err := s.repo.Closest(ctx, word)
+		if err != nil {
+			return "", false, err
+		}
+		if closest != "" && closest != word {
+			words[i] = closest
+			corrected = true
+		}
+	}
+	if !corrected {
+		return "", false, nil
+	}
+	return strings.Join(words, " "), true, nil
+}
+
+// RebuildTerms refreshes the vocabulary from the current catalog
+func (s *SpellingService) RebuildTerms(ctx context.Context) (int64, error) {
+	return s.repo.Rebuild(ctx)
+}
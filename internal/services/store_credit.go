@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrInsufficientStoreCredit = errors.New("insufficient store credit")
+	ErrInvalidStoreCredit      = errors.New("store credit requires a customer and a positive amount")
+)
+
+// StoreCreditTransactionType identifies what moved money in or out of a wallet
+type StoreCreditTransactionType string
+
+const (
+	StoreCreditTypeGrant      StoreCreditTransactionType = "grant"      // issued by an admin
+	StoreCreditTypeRedemption StoreCreditTransactionType = "redemption" // spent at checkout
+	StoreCreditTypeRestore    StoreCreditTransactionType = "restore"    // returned when a store credit payment is refunded
+)
+
+// StoreCreditGrantReason explains why an admin issued credit
+type StoreCreditGrantReason string
+
+const (
+	StoreCreditReasonGoodwill       StoreCreditGrantReason = "goodwill"
+	StoreCreditReasonRefundToCredit StoreCreditGrantReason = "refund_to_credit"
+	StoreCreditReasonPromotional    StoreCreditGrantReason = "promotional"
+	StoreCreditReasonOther          StoreCreditGrantReason = "other"
+)
+
+// IsValid reports whether the reason is a known grant reason
+func (r StoreCreditGrantReason) IsValid() bool {
+	switch r {
+	case StoreCreditReasonGoodwill, StoreCreditReasonRefundToCredit, StoreCreditReasonPromotional, StoreCreditReasonOther:
+		return true
+	}
+	return false
+}
+
+// StoreCreditBalance is a customer's store credit balance in one currency
+type StoreCreditBalance struct {
+	UserID    string      `json:"user_id"`
+	Balance   money.Money `json:"balance"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// StoreCreditTransaction is an entry in a customer's store credit ledger.
+// Amount is positive for credits and negative for redemptions.
+type StoreCreditTransaction struct {
+	ID           string                     `json:"id"`
+	UserID       string                     `json:"user_id"`
+	Type         StoreCreditTransactionType `json:"type"`
+	Amount       money.Money                `json:"amount"`
+	BalanceAfter money.Money                `json:"balance_after"`
+	Reason       StoreCreditGrantReason     `json:"reason,omitempty"`
+	Note         string                     `json:"note,omitempty"`
+	OrderID      string                     `json:"order_id,omitempty"`
+	CreatedBy    string                     `json:"created_by,omitempty"`
+	CreatedAt    time.Time                  `json:"created_at"`
+}
+
+// GrantStoreCreditRequest describes credit issued to a customer by an admin
+type GrantStoreCreditRequest struct {
+	UserID    string
+	Amount    money.Money
+	Reason    StoreCreditGrantReason
+	Note      string
+	OrderID   string // optional; the order being compensated or refunded to credit
+	CreatedBy string
+}
+
+// StoreCreditFilter filters a customer's ledger
+type StoreCreditFilter struct {
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// StoreCreditRepository defines persistence for store credit balances and their ledger
+type StoreCreditRepository interface {
+	FindBalances(ctx context.Context, userID string) ([]*StoreCreditBalance, error)
+	// FindBalance returns a zero balance when the customer has no credit in the currency
+	FindBalance(ctx context.Context, userID, currency string) (*StoreCreditBalance, error)
+	// Apply records the transaction and adjusts the balance atomically, setting BalanceAfter.
+	// It returns ErrInsufficientStoreCredit rather than let the balance go negative.
+	Apply(ctx context.Context, txn *StoreCreditTransaction) error
+	ListTransactions(ctx context.Context, filter StoreCreditFilter) ([]*StoreCreditTransaction, error)
+	CountTransactions(ctx context.Context, filter StoreCreditFilter) (int64, error)
+}
+
+// StoreCreditService manages customer store credit wallets
+type StoreCreditService struct {
+	repo StoreCreditRepository
+}
+
+// NewStoreCreditService creates a new StoreCreditService
+func NewStoreCreditService(repo StoreCreditRepository) *StoreCreditService {
+	return &StoreCreditService{repo: repo}
+}
+
+// GetBalances returns a customer's balance in every currency they hold credit in
+func (s *StoreCreditService) GetBalances(ctx context.Context, userID string) ([]*StoreCreditBalance, error) {
+	return s.repo.FindBalances(ctx, userID)
+}
+
+// Available returns how much credit the customer can put toward an order total
+func (s *StoreCreditService) Available(ctx context.Context, userID string, total money.Money) (money.Money, error) {
+	balance, err := s.repo.FindBalance(ctx, userID, total.Currency)
+	if err != nil {
+		return money.Zero(total.Currency), err
+	}
+	if balance.Balance.Amount > total.Amount {
+		return total, nil
+	}
+	return balance.Balance, nil
+}
+
+// Grant issues store credit to a customer
+func (s *StoreCreditService) Grant(ctx context.Context, req GrantStoreCreditRequest) (*StoreCreditTransaction, error) {
+	if req.UserID == "" || !req.Amount.IsPositive() || req.Amount.Currency == "" {
+		return nil, ErrInvalidStoreCredit
+	}
+	if req.Reason == "" {
+		req.Reason = StoreCreditReasonGoodwill
+	}
+	if !req.Reason.IsValid() {
+		return nil, ErrInvalidStoreCredit
+	}
+
+	txn := newStoreCreditTransaction(req.UserID, StoreCreditTypeGrant, req.Amount, req.OrderID)
+	txn.Reason = req.Reason
+	txn.Note = req.Note
+	txn.CreatedBy = req.CreatedBy
+	if err := s.repo.Apply(ctx, txn); err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// Redeem spends store credit on an order
+func (s *StoreCreditService) Redeem(ctx context.Context, userID string, amount money.Money, orderID string) (*StoreCreditTransaction, error) {
+	if userID == "" || !amount.IsPositive() {
+		return nil, ErrInvalidStoreCredit
+	}
+
+	txn := newStoreCreditTransaction(userID, StoreCreditTypeRedemption, money.Money{Amount: -amount.Amount, Currency: amount.Currency}, orderID)
+	if err := s.repo.Apply(ctx, txn); err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// Restore returns credit spent on an order, e.g. when the store credit payment is refunded
+func (s *StoreCreditService) Restore(ctx context.Context, userID string, amount money.Money, orderID string) (*StoreCreditTransaction, error) {
+	if userID == "" || !amount.IsPositive() {
+		return nil, ErrInvalidStoreCredit
+	}
+
+	txn := newStoreCreditTransaction(userID, StoreCreditTypeRestore, amount, orderID)
+	if err := s.repo.Apply(ctx, txn); err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// ListTransactions returns a customer's ledger, newest first
+func (s *StoreCreditService) ListTransactions(ctx context.Context, filter StoreCreditFilter) ([]*StoreCreditTransaction, error) {
+	return s.repo.ListTransactions(ctx, filter)
+}
+
+// CountTransactions counts ledger entries matching the filter
+func (s *StoreCreditService) CountTransactions(ctx context.Context, filter StoreCreditFilter) (int64, error) {
+	return s.repo.CountTransactions(ctx, filter)
+}
+
+func newStoreCreditTransaction(userID string, txnType StoreCreditTransactionType, amount money.Money, orderID string) *StoreCreditTransaction {
+	return &StoreCreditTransaction{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Type:      txnType,
+		Amount:    amount,
+		OrderID:   orderID,
+		CreatedAt: time.Now(),
+	}
+}
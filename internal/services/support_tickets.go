@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrTicketNotFound = errors.New("support ticket not found")
+	ErrInvalidTicket  = errors.New("invalid support ticket")
+	ErrTicketClosed   = errors.New("support ticket is closed")
+)
+
+// TicketStatus tracks who a support ticket is waiting on
+type TicketStatus string
+
+const (
+	TicketStatusOpen     TicketStatus = "open"     // waiting on support
+	TicketStatusPending  TicketStatus = "pending"  // waiting on the customer
+	TicketStatusResolved TicketStatus = "resolved" // reopened if the customer replies
+	TicketStatusClosed   TicketStatus = "closed"   // no further replies
+)
+
+// IsValid reports whether the status is a known ticket status
+func (s TicketStatus) IsValid() bool {
+	switch s {
+	case TicketStatusOpen, TicketStatusPending, TicketStatusResolved, TicketStatusClosed:
+		return true
+	}
+	return false
+}
+
+// TicketCategory is the kind of issue a customer raises about an order
+type TicketCategory string
+
+const (
+	TicketCategoryDamaged     TicketCategory = "damaged"
+	TicketCategoryMissingItem TicketCategory = "missing_item"
+	TicketCategoryWrongItem   TicketCategory = "wrong_item"
+	TicketCategoryDelivery    TicketCategory = "delivery"
+	TicketCategoryBilling     TicketCategory = "billing"
+	TicketCategoryOther       TicketCategory = "other"
+)
+
+// IsValid reports whether the category is a known ticket category
+func (c TicketCategory) IsValid() bool {
+	switch c {
+	case TicketCategoryDamaged, TicketCategoryMissingItem, TicketCategoryWrongItem,
+		TicketCategoryDelivery, TicketCategoryBilling, TicketCategoryOther:
+		return true
+	}
+	return false
+}
+
+// SupportTicket is an issue a customer opened against one of their orders, with the
+// conversation between them and support
+type SupportTicket struct {
+	ID        string                 `json:"id"`
+	OrderID   string                 `json:"order_id"`
+	UserID    string                 `json:"user_id"`
+	Subject   string                 `json:"subject"`
+	Category  TicketCategory         `json:"category"`
+	Status    TicketStatus           `json:"status"`
+	Messages  []SupportTicketMessage `json:"messages"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	ClosedAt  *time.Time             `json:"closed_at,omitempty"`
+}
+
+// SupportTicketMessage is a message on a support ticket from the customer or support
+type SupportTicketMessage struct {
+	ID        string    `json:"id"`
+	TicketID  string    `json:"ticket_id"`
+	AuthorID  string    `json:"author_id"`
+	Staff     bool      `json:"staff"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpenTicketRequest holds what a customer sends to open a ticket against an order
+type OpenTicketRequest struct {
+	Subject  string
+	Category TicketCategory
+	Message  string
+}
+
+// SupportTicketRepository defines persistence for support tickets and their messages
+type SupportTicketRepository interface {
+	// FindByID returns a ticket with its messages
+	FindByID(ctx context.Context, id string) (*SupportTicket, error)
+	// FindByOrderID returns an order's tickets with their messages, oldest first
+	FindByOrderID(ctx context.Context, orderID string) ([]*SupportTicket, error)
+	// FindByUserID returns a user's tickets without their messages, most recently updated first
+	FindByUserID(ctx context.Context, userID string) ([]*SupportTicket, error)
+	// FindByStatus returns the tickets in a status, or all tickets when status is empty,
+	// without their messages, least recently updated first
+	FindByStatus(ctx context.Context, status TicketStatus) ([]*SupportTicket, error)
+	// Save creates a ticket or updates its status and timestamps
+	Save(ctx context.Context, ticket *SupportTicket) error
+	AddMessage(ctx context.Context, message *SupportTicketMessage) error
+}
+
+// SupportTicketService lets customers open tickets against their orders and support
+// answer them; the tickets are linked to the order so staff see them on it
+type SupportTicketService struct {
+	repo         SupportTicketRepository
+	orderService *OrderService
+}
+
+// NewSupportTicketService creates a new SupportTicketService
+func NewSupportTicketService(repo SupportTicketRepository, orderService *OrderService) *SupportTicketService {
+	return &SupportTicketService{repo: repo, orderService: orderService}
+}
+
+// OpenTicket opens a ticket against one of the user's orders, with the customer's first
+// message. Other users' orders are reported as not found.
+func (s *SupportTicketService) OpenTicket(ctx context.Context, userID, orderID string, req OpenTicketRequest) (*SupportTicket, error) {
+	order, err := s.orderService.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, orders.ErrOrderNotFound
+	}
+
+	subject := strings.TrimSpace(req.Subject)
+	if subject == "" || len(subject) > 255 {
+		return nil, fmt.Errorf("%w: subject is required and at most 255 characters", ErrInvalidTicket)
+	}
+	if req.Category == "" {
+		req.Category = TicketCategoryOther
+	}
+	if !req.Category.IsValid() {
+		return nil, fmt.Errorf("%w: unknown category %q", ErrInvalidTicket, req.Category)
+	}
+	body, err := ticketMessageBody(req.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ticket := &SupportTicket{
+		ID:        utils.GenerateID(),
+		OrderID:   order.ID,
+		UserID:    userID,
+		Subject:   subject,
+		Category:  req.Category,
+		Status:    TicketStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Save(ctx, ticket); err != nil {
+		return nil, err
+	}
+	message := SupportTicketMessage{
+		ID:        utils.GenerateID(),
+		TicketID:  ticket.ID,
+		AuthorID:  userID,
+		Body:      body,
+		CreatedAt: now,
+	}
+	if err := s.repo.AddMessage(ctx, &message); err != nil {
+		return nil, err
+	}
+	ticket.Messages = []SupportTicketMessage{message}
+	return ticket, nil
+}
+
+// GetTicket returns a ticket with its messages
+func (s *SupportTicketService) GetTicket(ctx context.Context, id string) (*SupportTicket, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// OrderTickets returns the tickets opened against an order
+func (s *SupportTicketService) OrderTickets(ctx context.Context, orderID string) ([]*SupportTicket, error) {
+	return s.repo.FindByOrderID(ctx, orderID)
+}
+
+// UserTickets returns the tickets a user opened
+func (s *SupportTicketService) UserTickets(ctx context.Context, userID string) ([]*SupportTicket, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+// ListTickets returns the support queue: the tickets in a status, or every ticket when
+// status is empty
+func (s *SupportTicketService) ListTickets(ctx context.Context, status TicketStatus) ([]*SupportTicket, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTicket, status)
+	}
+	return s.repo.FindByStatus(ctx, status)
+}
+
+// Reply adds a message to a ticket. A reply from support leaves the ticket waiting on the
+// customer; a reply from the customer puts it back in support's queue. Closed tickets
+// take no replies.
+func (s *SupportTicketService) Reply(ctx context.Context, ticketID, authorID string, staff bool, text string) (*SupportTicket, error) {
+	ticket, err := s.repo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == TicketStatusClosed {
+		return nil, ErrTicketClosed
+	}
+	body, err := ticketMessageBody(text)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message := SupportTicketMessage{
+		ID:        utils.GenerateID(),
+		TicketID:  ticket.ID,
+		AuthorID:  authorID,
+		Staff:     staff,
+		Body:      body,
+		CreatedAt: now,
+	}
+	if err := s.repo.AddMessage(ctx, &message); err != nil {
+		return nil, err
+	}
+
+	ticket.Status = TicketStatusOpen
+	if staff {
+		ticket.Status = TicketStatusPending
+	}
+	ticket.UpdatedAt = now
+	if err := s.repo.Save(ctx, ticket); err != nil {
+		return nil, err
+	}
+	ticket.Messages = append(ticket.Messages, message)
+	return ticket, nil
+}
+
+// SetStatus moves a ticket to a status, such as resolving or closing it. Closed tickets
+// stay closed.
+func (s *SupportTicketService) SetStatus(ctx context.Context, ticketID string, status TicketStatus) (*SupportTicket, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTicket, status)
+	}
+	ticket, err := s.repo.FindByID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == status {
+		return ticket, nil
+	}
+	if ticket.Status == TicketStatusClosed {
+		return nil, ErrTicketClosed
+	}
+
+	now := time.Now()
+	ticket.Status = status
+	ticket.UpdatedAt = now
+	if status == TicketStatusClosed {
+		ticket.ClosedAt = &now
+	}
+	if err := s.repo.Save(ctx, ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// ticketMessageBody trims a message and checks its length
+func ticketMessageBody(text string) (string, error) {
+	body := strings.TrimSpace(text)
+	if body == "" || len(body) > 5000 {
+		return "", fmt.Errorf("%w: message is required and at most 5000 characters", ErrInvalidTicket)
+	}
+	return body, nil
+}
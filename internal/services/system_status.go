@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/startup"
+)
+
+// SystemStatus summarizes the API's background work, caches and database for an ops
+// dashboard. A section that couldn't be read is left empty and named in Errors, so one
+// failing source doesn't hide the rest.
+type SystemStatus struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Jobs        JobsStatus        `json:"jobs"`
+	Webhooks    WebhookBacklog    `json:"webhooks"`
+	Caches      []CacheStatus     `json:"caches"`
+	Database    DatabaseStatus    `json:"database"`
+	Startup     *startup.Report   `json:"startup,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// JobsStatus reports the job runner's queue, failures since startup, failed jobs kept for
+// replay and scheduled tasks whose last run failed
+type JobsStatus struct {
+	jobs.RunnerStats
+	DeadLetters  int64    `json:"dead_letters"`
+	FailingTasks []string `json:"failing_tasks"`
+}
+
+// WebhookBacklog counts stored inbound webhooks not yet processed and those that failed
+type WebhookBacklog struct {
+	Pending int64 `json:"pending"`
+	Failed  int64 `json:"failed"`
+}
+
+// CacheStatus reports one cache's hit rate since startup; HitRate is 0 before any lookup
+type CacheStatus struct {
+	Name    string  `json:"name"`
+	Entries int     `json:"entries"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// DatabaseStatus reports recent statement latency, the connection pool and the newest
+// migration applied
+type DatabaseStatus struct {
+	Latency       DBLatency         `json:"latency"`
+	Connections   DBConnections     `json:"connections"`
+	LastMigration *AppliedMigration `json:"last_migration,omitempty"`
+}
+
+// DBLatency holds percentiles of the most recent statements, in milliseconds
+type DBLatency struct {
+	Samples int     `json:"samples"`
+	P50     float64 `json:"p50_ms"`
+	P95     float64 `json:"p95_ms"`
+	P99     float64 `json:"p99_ms"`
+	Max     float64 `json:"max_ms"`
+}
+
+// DBConnections reports the connection pool
+type DBConnections struct {
+	Open      int   `json:"open"`
+	InUse     int   `json:"in_use"`
+	Idle      int   `json:"idle"`
+	MaxOpen   int   `json:"max_open"`
+	WaitCount int64 `json:"wait_count"`
+}
+
+// AppliedMigration is a migration recorded as applied
+type AppliedMigration struct {
+	Version   string    `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// SystemStatusRepository reads the database's own status
+type SystemStatusRepository interface {
+	// LastMigration returns the migration applied most recently, or nil before any
+	LastMigration(ctx context.Context) (*AppliedMigration, error)
+	// Latency returns percentiles of the most recent statements
+	Latency() DBLatency
+	// Connections returns the connection pool's counters
+	Connections() DBConnections
+}
+
+// SystemStatusService gathers the system status from the services that own each part
+type SystemStatusService struct {
+	repo        SystemStatusRepository
+	runner      *jobs.Runner
+	scheduler   *jobs.Scheduler
+	webhooks    *WebhookService
+	deadLetters *DeadLetterService
+	catalog     *CatalogService
+
+	startup *startup.Report
+
+	mu     sync.Mutex
+	caches map[string]func() CacheStats
+}
+
+// NewSystemStatusService creates a new SystemStatusService
+func NewSystemStatusService(repo SystemStatusRepository, runner *jobs.Runner, scheduler *jobs.Scheduler, webhooks *WebhookService, deadLetters *DeadLetterService, catalog *CatalogService) *SystemStatusService {
+	return &SystemStatusService{
+		repo:        repo,
+		runner:      runner,
+		scheduler:   scheduler,
+		webhooks:    webhooks,
+		deadLetters: deadLetters,
+		catalog:     catalog,
+		caches:      make(map[string]func() CacheStats),
+	}
+}
+
+// WithCache reports another cache under name, such as the public response cache, which
+// lives outside the services
+func (s *SystemStatusService) WithCache(name string, stats func() CacheStats) *SystemStatusService {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.caches[name] = stats
+	return s
+}
+
+// WithStartupReport reports the startup checks, so an API started degraded shows why
+func (s *SystemStatusService) WithStartupReport(report *startup.Report) *SystemStatusService {
+	s.startup = report
+	return s
+}
+
+// Status gathers the current system status
+func (s *SystemStatusService) Status(ctx context.Context) *SystemStatus {
+	status := &SystemStatus{
+		GeneratedAt: time.Now(),
+		Jobs: JobsStatus{
+			RunnerStats:  s.runner.Stats(),
+			FailingTasks: []string{},
+		},
+		Caches: s.cacheStatuses(),
+		Database: DatabaseStatus{
+			Latency:     s.repo.Latency(),
+			Connections: s.repo.Connections(),
+		},
+		Startup: s.startup,
+	}
+	fail := func(section string, err error) {
+		if status.Errors == nil {
+			status.Errors = make(map[string]string)
+		}
+		status.Errors[section] = err.Error()
+	}
+
+	for _, task := range s.scheduler.Tasks() {
+		if task.LastError != "" {
+			status.Jobs.FailingTasks = append(status.Jobs.FailingTasks, task.Name)
+		}
+	}
+
+	deadLetters, err := s.deadLetters.CountDeadLetters(ctx, DeadLetterFilter{Status: DeadLetterStatusDead})
+	if err != nil {
+		fail("dead_letters", err)
+	}
+	status.Jobs.DeadLetters = deadLetters
+
+	if status.Webhooks.Pending, err = s.webhooks.CountEvents(ctx, WebhookEventFilter{Status: WebhookEventStatusPending}); err != nil {
+		fail("webhooks", err)
+	} else if status.Webhooks.Failed, err = s.webhooks.CountEvents(ctx, WebhookEventFilter{Status: WebhookEventStatusFailed}); err != nil {
+		fail("webhooks", err)
+	}
+
+	if status.Database.LastMigration, err = s.repo.LastMigration(ctx); err != nil {
+		fail("migrations", err)
+	}
+	return status
+}
+
+// cacheStatuses lists the catalog caches that are on and the added caches, by name
+func (s *SystemStatusService) cacheStatuses() []CacheStatus {
+	catalog := s.catalog.CacheStats()
+	named := map[string]*CacheStats{
+		"products":   catalog.Products,
+		"categories": catalog.Categories,
+		"brands":     catalog.Brands,
+	}
+
+	s.mu.Lock()
+	for name, stats := range s.caches {
+		cache := stats()
+		named[name] = &cache
+	}
+	s.mu.Unlock()
+
+	caches := make([]CacheStatus, 0, len(named))
+	for name, stats := range named {
+		if stats == nil {
+			continue // the cache is off
+		}
+		caches = append(caches, CacheStatus{
+			Name:    name,
+			Entries: stats.Entries,
+			Hits:    stats.Hits,
+			Misses:  stats.Misses,
+			HitRate: hitRate(stats.Hits, stats.Misses),
+		})
+	}
+	sort.Slice(caches, func(i, j int) bool { return caches[i].Name < caches[j].Name })
+	return caches
+}
+
+// hitRate is the share of lookups that hit, rounded to four places
+func hitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return math.Round(float64(hits)/float64(hits+misses)*10000) / 10000
+}
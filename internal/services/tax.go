@@ -2,69 +2,131 @@ package services
 
 import (
 	"context"
+	"sync"
 
 	"github.com/devchuckcamp/gocommerce/money"
 	"github.com/devchuckcamp/gocommerce/tax"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
 )
 
 // SimpleTaxCalculator implements tax.Calculator with a fixed tax rate
 type SimpleTaxCalculator struct {
-	rate float64 // e.g., 0.0875 for 8.75%
+	mu       sync.RWMutex
+	rate     float64 // e.g., 0.0875 for 8.75%
+	rounding moneymath.Rounding
 }
 
-// NewSimpleTaxCalculator creates a new SimpleTaxCalculator
+// NewSimpleTaxCalculator creates a new SimpleTaxCalculator. Fractional cents of tax are
+// truncated unless WithRounding picks another mode.
 func NewSimpleTaxCalculator(rate float64) *SimpleTaxCalculator {
-	return &SimpleTaxCalculator{rate: rate}
+	return &SimpleTaxCalculator{rate: rate, rounding: moneymath.Truncate}
+}
+
+// WithRounding sets how fractional cents of tax are rounded, e.g. moneymath.HalfEven
+func (c *SimpleTaxCalculator) WithRounding(rounding moneymath.Rounding) *SimpleTaxCalculator {
+	c.rounding = rounding
+	return c
+}
+
+// SetRate changes the tax rate for calculations that start afterwards
+func (c *SimpleTaxCalculator) SetRate(rate float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rate = rate
 }
 
-// Calculate calculates tax for the given request
+// Rate returns the current tax rate
+func (c *SimpleTaxCalculator) Rate() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rate
+}
+
+// Calculate calculates tax for the given request. Line items and shipping must share a
+// currency, and totals that would overflow are rejected rather than wrapped.
 func (c *SimpleTaxCalculator) Calculate(ctx context.Context, req tax.CalculationRequest) (*tax.CalculationResult, error) {
+	// One rate for the whole calculation, even if it is changed meanwhile
+	rate := c.Rate()
+
 	// Calculate total from line items
 	currency := "USD"
 	if len(req.LineItems) > 0 {
 		currency = req.LineItems[0].Amount.Currency
+	} else if req.ShippingCost.Currency != "" {
+		currency = req.ShippingCost.Currency
 	}
 
-	var subtotal int64
+	subtotal := money.Zero(currency)
 	lineItemTaxes := make([]tax.LineItemTax, len(req.LineItems))
 
 	for i, item := range req.LineItems {
+		if err := moneymath.AssertCurrency(item.Amount, currency); err != nil {
+			return nil, err
+		}
 		if !item.IsTaxable {
+			// A zero in the order currency, so line totals that add it up stay valid
+			lineItemTaxes[i] = tax.LineItemTax{LineItemID: item.ID, TaxAmount: money.Zero(currency)}
 			continue
 		}
 
-		itemTotal := item.Amount.Amount * int64(item.Quantity)
-		itemTax := int64(float64(itemTotal) * c.rate)
-		subtotal += itemTotal
+		itemTotal, err := moneymath.MulInt(item.Amount, int64(item.Quantity))
+		if err != nil {
+			return nil, err
+		}
+		itemTax, err := moneymath.MulRate(itemTotal, rate, c.rounding)
+		if err != nil {
+			return nil, err
+		}
+		if subtotal, err = moneymath.Add(subtotal, itemTotal); err != nil {
+			return nil, err
+		}
 
 		lineItemTaxes[i] = tax.LineItemTax{
 			LineItemID: item.ID,
-			TaxAmount:  money.Money{Amount: itemTax, Currency: currency},
+			TaxAmount:  itemTax,
 			TaxRates: []tax.AppliedTaxRate{
 				{
 					Name:         "Sales Tax",
-					Rate:         c.rate,
+					Rate:         rate,
 					Jurisdiction: req.Address.State,
 				},
 			},
 		}
 	}
 
-	// Calculate shipping tax
-	shippingTax := int64(float64(req.ShippingCost.Amount) * c.rate)
-	totalTax := int64(float64(subtotal)*c.rate) + shippingTax
+	// Calculate shipping tax; a zero shipping cost may leave its currency unset
+	shippingCost := req.ShippingCost
+	if shippingCost.Amount == 0 {
+		shippingCost.Currency = currency
+	}
+	if err := moneymath.AssertCurrency(shippingCost, currency); err != nil {
+		return nil, err
+	}
+	shippingTax, err := moneymath.MulRate(shippingCost, rate, c.rounding)
+	if err != nil {
+		return nil, err
+	}
+	subtotalTax, err := moneymath.MulRate(subtotal, rate, c.rounding)
+	if err != nil {
+		return nil, err
+	}
+	totalTax, err := moneymath.Add(subtotalTax, shippingTax)
+	if err != nil {
+		return nil, err
+	}
 
 	result := &tax.CalculationResult{
-		TotalTax: money.Money{Amount: totalTax, Currency: currency},
+		TotalTax: totalTax,
 		TaxRates: []tax.AppliedTaxRate{
 			{
 				Name:         "Sales Tax",
-				Rate:         c.rate,
+				Rate:         rate,
 				Jurisdiction: req.Address.State,
 			},
 		},
 		LineItemTaxes: lineItemTaxes,
-		ShippingTax:   money.Money{Amount: shippingTax, Currency: currency},
+		ShippingTax:   shippingTax,
 	}
 
 	return result, nil
@@ -76,7 +138,7 @@ func (c *SimpleTaxCalculator) GetRatesForAddress(ctx context.Context, address ta
 		{
 			ID:       "state-tax",
 			Name:     "Sales Tax",
-			Rate:     c.rate,
+			Rate:     c.Rate(),
 			State:    address.State,
 			TaxType:  "sales",
 			Priority: 1,
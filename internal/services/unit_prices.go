@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/pricing"
+)
+
+var ErrInvalidUnitMeasure = errors.New("unit quantity must be positive and the measure one of g, kg, ml, cl, l, cm, m or m2")
+
+// unitReferences maps each measure a variant's contents can be given in to the reference
+// unit its price is quoted per, and how many reference units one of it makes. EU price
+// indication rules quote unit prices per kilogram, litre, metre or square metre.
+var unitReferences = map[string]struct {
+	per    string
+	factor float64
+}{
+	"g":  {"kg", 0.001},
+	"kg": {"kg", 1},
+	"ml": {"l", 0.001},
+	"cl": {"l", 0.01},
+	"l":  {"l", 1},
+	"cm": {"m", 0.01},
+	"m":  {"m", 1},
+	"m2": {"m2", 1},
+}
+
+// VariantUnit is how much of a measure a variant contains, e.g. 500 g or 1.5 l
+type VariantUnit struct {
+	VariantID string  `json:"variant_id"`
+	Quantity  float64 `json:"unit_quantity"` // 0 when the variant has no unit
+	Measure   string  `json:"unit_measure"`
+}
+
+// UnitPrice is a variant's price per reference unit, e.g. 5.98 USD per kg
+type UnitPrice struct {
+	Price money.Money
+	Per   string
+}
+
+// VariantResponse wraps catalog.Variant with sale and unit price information
+type VariantResponse struct {
+	*catalog.Variant
+	SalePrice    *money.Money `json:"SalePrice,omitempty"`
+	UnitQuantity float64      `json:"UnitQuantity,omitempty"`
+	UnitMeasure  string       `json:"UnitMeasure,omitempty"`
+	UnitPrice    *UnitPrice   `json:"UnitPrice,omitempty"` // from the sale price when on sale
+}
+
+// UnitPriceRepository defines persistence for variant unit quantities
+type UnitPriceRepository interface {
+	// FindUnit returns a variant's unit, with a zero quantity when it has none, or
+	// ErrVariantNotFound when there is no such variant
+	FindUnit(ctx context.Context, variantID string) (*VariantUnit, error)
+	// FindUnitsByProduct returns the units of a product's variants that have one, by variant ID
+	FindUnitsByProduct(ctx context.Context, productID string) (map[string]*VariantUnit, error)
+	// SetUnit sets a variant's unit, or clears it when the quantity is 0, returning
+	// ErrVariantNotFound when there is no such variant
+	SetUnit(ctx context.Context, unit *VariantUnit) error
+}
+
+// UnitPriceService keeps variant unit quantities and computes the unit prices shown next
+// to selling prices
+type UnitPriceService struct {
+	repo              UnitPriceRepository
+	variants          catalog.VariantRepository
+	salePriceResolver SalePriceResolver
+}
+
+// NewUnitPriceService creates a new UnitPriceService
+func NewUnitPriceService(repo UnitPriceRepository, variants catalog.VariantRepository) *UnitPriceService {
+	return &UnitPriceService{
+		repo:     repo,
+		variants: variants,
+	}
+}
+
+// WithSalePriceResolver computes unit prices from variant sale prices while they apply
+func (s *UnitPriceService) WithSalePriceResolver(resolver SalePriceResolver) *UnitPriceService {
+	s.salePriceResolver = resolver
+	return s
+}
+
+// ComputeUnitPrice returns the price per reference unit of quantity of measure selling at
+// price, rounded to the nearest cent
+func ComputeUnitPrice(price money.Money, quantity float64, measure string) (*UnitPrice, error) {
+	reference, ok := unitReferences[measure]
+	if !ok || quantity <= 0 || math.IsInf(quantity, 0) {
+		return nil, ErrInvalidUnitMeasure
+	}
+	return &UnitPrice{
+		Price: money.Money{Amount: int64(math.Round(float64(price.Amount) / (quantity * reference.factor))), Currency: price.Currency},
+		Per:   reference.per,
+	}, nil
+}
+
+// ProductVariants returns a product's variants with their sale and unit prices
+func (s *UnitPriceService) ProductVariants(ctx context.Context, productID string) ([]*VariantResponse, error) {
+	variants, err := s.variants.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.repo.FindUnitsByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	salePrices := s.variantSalePrices(ctx, productID, variants)
+	responses := make([]*VariantResponse, len(variants))
+	for i, variant := range variants {
+		responses[i] = priceVariant(variant, salePrices[variant.ID], units[variant.ID])
+	}
+	return responses, nil
+}
+
+// VariantUnitPrice returns a variant's unit price, or nil when it has no unit
+func (s *UnitPriceService) VariantUnitPrice(ctx context.Context, variant *catalog.Variant) (*UnitPrice, error) {
+	unit, err := s.repo.FindUnit(ctx, variant.ID)
+	if err != nil {
+		return nil, err
+	}
+	return priceVariant(variant, s.variantSalePrice(ctx, variant), unit).UnitPrice, nil
+}
+
+// GetUnit returns a variant's unit
+func (s *UnitPriceService) GetUnit(ctx context.Context, variantID string) (*VariantUnit, error) {
+	return s.repo.FindUnit(ctx, variantID)
+}
+
+// SetUnit validates and sets a variant's unit
+func (s *UnitPriceService) SetUnit(ctx context.Context, unit *VariantUnit) error {
+	unit.Measure = strings.ToLower(strings.TrimSpace(unit.Measure))
+	if _, ok := unitReferences[unit.Measure]; !ok || unit.Quantity <= 0 || math.IsInf(unit.Quantity, 0) {
+		return ErrInvalidUnitMeasure
+	}
+	return s.repo.SetUnit(ctx, unit)
+}
+
+// RemoveUnit clears a variant's unit
+func (s *UnitPriceService) RemoveUnit(ctx context.Context, variantID string) error {
+	return s.repo.SetUnit(ctx, &VariantUnit{VariantID: variantID})
+}
+
+// variantSalePrices resolves the sale prices of a product's variants, by variant ID. A
+// resolver that implements VariantSalePriceResolver does it in one lookup; others are
+// asked variant by variant.
+func (s *UnitPriceService) variantSalePrices(ctx context.Context, productID string, variants []*catalog.Variant) map[string]*pricing.ProductPrice {
+	salePrices := make(map[string]*pricing.ProductPrice, len(variants))
+	if s.salePriceResolver == nil || len(variants) == 0 {
+		return salePrices
+	}
+
+	if batch, ok := s.salePriceResolver.(VariantSalePriceResolver); ok {
+		variantIDs := make([]string, len(variants))
+		for i, variant := range variants {
+			variantIDs[i] = variant.ID
+		}
+		prices, err := batch.FindEffectiveVariantPrices(ctx, productID, variantIDs, time.Now())
+		if err != nil {
+			log.Printf("Failed to resolve sale prices for the variants of product %s: %v", productID, err)
+			return salePrices
+		}
+		return prices
+	}
+
+	for _, variant := range variants {
+		if salePrice := s.variantSalePrice(ctx, variant); salePrice != nil {
+			salePrices[variant.ID] = salePrice
+		}
+	}
+	return salePrices
+}
+
+// variantSalePrice resolves one variant's sale price, or nil when none applies
+func (s *UnitPriceService) variantSalePrice(ctx context.Context, variant *catalog.Variant) *pricing.ProductPrice {
+	if s.salePriceResolver == nil {
+		return nil
+	}
+	variantID := variant.ID
+	salePrice, err := s.salePriceResolver.FindEffectivePrice(ctx, variant.ProductID, &variantID, time.Now())
+	if err != nil {
+		return nil
+	}
+	return salePrice
+}
+
+// priceVariant applies a variant's sale price and computes its unit price from the price
+// it currently sells at
+func priceVariant(variant *catalog.Variant, salePrice *pricing.ProductPrice, unit *VariantUnit) *VariantResponse {
+	response := &VariantResponse{Variant: variant}
+	selling := variant.Price
+	if salePrice != nil {
+		response.SalePrice = &salePrice.Price
+		selling = salePrice.Price
+	}
+
+	if unit != nil && unit.Quantity > 0 {
+		response.UnitQuantity = unit.Quantity
+		response.UnitMeasure = unit.Measure
+		// Units are validated when set, so only a measure dropped since can fail here
+		response.UnitPrice, _ = ComputeUnitPrice(selling, unit.Quantity, unit.Measure)
+	}
+	return response
+}
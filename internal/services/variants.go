@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrProductNotFound           = errors.New("product not found")
+	ErrInvalidVariant            = errors.New("variant needs a SKU, a name and a price above zero")
+	ErrVariantSKUTaken           = errors.New("SKU is already used by another variant")
+	ErrInvalidVariantAttributes  = errors.New("variant attributes need a name and a value")
+	ErrVariantAttributesMismatch = errors.New("variant attributes must have the same names as the product's other variants")
+	ErrDuplicateVariantOptions   = errors.New("another variant of the product has the same attributes")
+)
+
+// VariantInput is the state of a variant to create or update. Price is in cents, in the
+// product's currency.
+type VariantInput struct {
+	SKU         string
+	Name        string
+	Price       int64
+	Attributes  map[string]string
+	Images      []string
+	IsAvailable *bool // nil keeps the current availability; new variants are available
+}
+
+// VariantOption is an attribute customers choose between, such as size or color, with the
+// values the product's variants offer
+type VariantOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// VariantService manages the variants of products: the SKUs customers pick between by
+// size, color and other attributes
+type VariantService struct {
+	variants   catalog.VariantRepository
+	products   catalog.ProductRepository
+	changeHook func(productIDs ...string)
+}
+
+// NewVariantService creates a new VariantService. Pass the catalog history's repositories
+// so variant changes are versioned and guarded against pricing mistakes.
+func NewVariantService(variants catalog.VariantRepository, products catalog.ProductRepository) *VariantService {
+	return &VariantService{
+		variants: variants,
+		products: products,
+	}
+}
+
+// WithChangeHook calls fn with the product whose variants changed, e.g. to drop it from
+// the product cache
+func (s *VariantService) WithChangeHook(fn func(productIDs ...string)) *VariantService {
+	s.changeHook = fn
+	return s
+}
+
+// ListVariants lists a product's variants, oldest first
+func (s *VariantService) ListVariants(ctx context.Context, productID string) ([]*catalog.Variant, error) {
+	variants, err := s.variants.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		if !variants[i].CreatedAt.Equal(variants[j].CreatedAt) {
+			return variants[i].CreatedAt.Before(variants[j].CreatedAt)
+		}
+		return variants[i].ID < variants[j].ID
+	})
+	return variants, nil
+}
+
+// Options lists the attributes a product's available variants vary by, with their values
+// in the order the variants were added, so storefronts can render size and color pickers
+func (s *VariantService) Options(ctx context.Context, productID string) ([]VariantOption, error) {
+	variants, err := s.ListVariants(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []VariantOption
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		if !variant.IsAvailable {
+			continue
+		}
+		for _, name := range sortedKeys(variant.Attributes) {
+			i, ok := index[name]
+			if !ok {
+				i = len(options)
+				index[name] = i
+				options = append(options, VariantOption{Name: name})
+			}
+			value := variant.Attributes[name]
+			if key := name + "\x00" + value; !seen[key] {
+				seen[key] = true
+				options[i].Values = append(options[i].Values, value)
+			}
+		}
+	}
+	if options == nil {
+		options = []VariantOption{}
+	}
+	return options, nil
+}
+
+// GetVariant returns a variant
+func (s *VariantService) GetVariant(ctx context.Context, id string) (*catalog.Variant, error) {
+	variant, err := s.variants.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrVariantNotFound
+	}
+	return variant, nil
+}
+
+// CreateVariant adds a variant to a product
+func (s *VariantService) CreateVariant(ctx context.Context, productID string, input VariantInput) (*catalog.Variant, error) {
+	product, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	now := time.Now()
+	variant := &catalog.Variant{
+		ID:          utils.GenerateID(),
+		ProductID:   product.ID,
+		IsAvailable: true,
+		CreatedAt:   now,
+	}
+	if err := s.apply(ctx, product, variant, input); err != nil {
+		return nil, err
+	}
+	variant.UpdatedAt = now
+
+	if err := s.variants.Save(ctx, variant); err != nil {
+		return nil, err
+	}
+	s.changed(product.ID)
+	return variant, nil
+}
+
+// UpdateVariant replaces a variant's SKU, name, price, attributes and images
+func (s *VariantService) UpdateVariant(ctx context.Context, id string, input VariantInput) (*catalog.Variant, error) {
+	existing, err := s.GetVariant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.products.FindByID(ctx, existing.ProductID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	// Changes are made on a copy so the saved record can be compared with the original
+	updated := *existing
+	if err := s.apply(ctx, product, &updated, input); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+
+	if err := s.variants.Save(ctx, &updated); err != nil {
+		return nil, err
+	}
+	s.changed(product.ID)
+	return &updated, nil
+}
+
+// DeleteVariant removes a variant
+func (s *VariantService) DeleteVariant(ctx context.Context, id string) error {
+	variant, err := s.GetVariant(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.variants.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.changed(variant.ProductID)
+	return nil
+}
+
+// apply validates input against the product's other variants and sets it on variant
+func (s *VariantService) apply(ctx context.Context, product *catalog.Product, variant *catalog.Variant, input VariantInput) error {
+	sku := strings.TrimSpace(input.SKU)
+	name := strings.TrimSpace(input.Name)
+	if sku == "" || name == "" || input.Price <= 0 {
+		return ErrInvalidVariant
+	}
+
+	attributes := make(map[string]string, len(input.Attributes))
+	for key, value := range input.Attributes {
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "" || value == "" {
+			return ErrInvalidVariantAttributes
+		}
+		attributes[key] = value
+	}
+
+	if existing, err := s.variants.FindBySKU(ctx, sku); err == nil && existing.ID != variant.ID {
+		return ErrVariantSKUTaken
+	}
+
+	siblings, err := s.variants.FindByProductID(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	// Every variant needs a value for every option, or pickers would offer combinations
+	// that can't be bought
+	names := strings.Join(sortedKeys(attributes), ",")
+	for _, sibling := range siblings {
+		if sibling.ID == variant.ID {
+			continue
+		}
+		if strings.Join(sortedKeys(sibling.Attributes), ",") != names {
+			return ErrVariantAttributesMismatch
+		}
+		if sameAttributes(sibling.Attributes, attributes) {
+			return ErrDuplicateVariantOptions
+		}
+	}
+
+	variant.SKU = sku
+	variant.Name = name
+	variant.Price = money.Money{Amount: input.Price, Currency: product.BasePrice.Currency}
+	variant.Attributes = attributes
+	variant.Images = input.Images
+	if input.IsAvailable != nil {
+		variant.IsAvailable = *input.IsAvailable
+	}
+	return nil
+}
+
+func (s *VariantService) changed(productID string) {
+	if s.changeHook != nil {
+		s.changeHook(productID)
+	}
+}
+
+// sameAttributes reports whether two variants have the same attribute values, ignoring case
+func sameAttributes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if !strings.EqualFold(b[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the names of attributes in alphabetical order
+func sortedKeys(attributes map[string]string) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotADrop                  = errors.New("product does not have a waiting room")
+	ErrWaitingRoomTicketNotFound = errors.New("waiting room ticket not found")
+	ErrWaitingRoomRequired       = errors.New("this product is a limited drop; join the waiting room to add it to your cart")
+	ErrWaitingRoomNotAdmitted    = errors.New("your place in the waiting room has not been admitted yet")
+	ErrWaitingRoomExpired        = errors.New("your waiting room access has expired; join again")
+)
+
+// WaitingRoomTokenHeader carries an admitted waiting room token on add-to-cart requests
+const WaitingRoomTokenHeader = "X-Waiting-Room-Token"
+
+// WaitingRoomStatus is where a ticket is in the waiting room
+type WaitingRoomStatus string
+
+const (
+	WaitingRoomWaiting  WaitingRoomStatus = "waiting"
+	WaitingRoomAdmitted WaitingRoomStatus = "admitted"
+	WaitingRoomExpired  WaitingRoomStatus = "expired"
+)
+
+// WaitingRoomTicket is a customer's place in the queue for a limited drop. Once admitted,
+// its token lets them add the product to their cart until it expires.
+type WaitingRoomTicket struct {
+	Token      string            `json:"token"`
+	ProductID  string            `json:"product_id"`
+	UserID     string            `json:"-"`
+	Status     WaitingRoomStatus `json:"status"`
+	JoinedAt   time.Time         `json:"joined_at"`
+	AdmittedAt *time.Time        `json:"admitted_at,omitempty"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+
+	// Position and EstimatedWaitSeconds are filled in while the ticket is waiting
+	Position             int64 `json:"position,omitempty"`
+	EstimatedWaitSeconds int64 `json:"estimated_wait_seconds,omitempty"`
+}
+
+// WaitingRoomPolicy lists the drop products and how fast their queues are admitted
+type WaitingRoomPolicy struct {
+	Products      []string      // products that can only be added to a cart with an admitted token
+	AdmitBatch    int           // tickets admitted per product on each run
+	AdmitInterval time.Duration // how often a batch is admitted
+	AccessTTL     time.Duration // how long an admitted token can be used
+}
+
+// WaitingRoomRepository defines persistence for waiting room tickets
+type WaitingRoomRepository interface {
+	FindByUser(ctx context.Context, productID, userID string) (*WaitingRoomTicket, error)
+	FindByToken(ctx context.Context, token string) (*WaitingRoomTicket, error)
+	Save(ctx context.Context, ticket *WaitingRoomTicket) error
+	// CountAhead counts waiting tickets for the same product that joined before this one
+	CountAhead(ctx context.Context, ticket *WaitingRoomTicket) (int64, error)
+	// AdmitNext admits the product's n longest-waiting tickets until expiresAt
+	AdmitNext(ctx context.Context, productID string, n int, admittedAt, expiresAt time.Time) (int64, error)
+	// ExpireAdmitted marks admitted tickets past their expiry as expired
+	ExpireAdmitted(ctx context.Context, now time.Time) (int64, error)
+}
+
+// WaitingRoomService queues customers for limited drops and admits them at a steady rate,
+// so a launch isn't decided by whoever's script adds to cart first
+type WaitingRoomService struct {
+	repo     WaitingRoomRepository
+	policy   WaitingRoomPolicy
+	products map[string]bool
+}
+
+// NewWaitingRoomService creates a new WaitingRoomService
+func NewWaitingRoomService(repo WaitingRoomRepository, policy WaitingRoomPolicy) *WaitingRoomService {
+	if policy.AdmitBatch < 1 {
+		policy.AdmitBatch = 1
+	}
+	products := make(map[string]bool, len(policy.Products))
+	for _, productID := range policy.Products {
+		products[productID] = true
+	}
+	return &WaitingRoomService{repo: repo, policy: policy, products: products}
+}
+
+// IsDrop reports whether adding the product to a cart requires an admitted token
+func (s *WaitingRoomService) IsDrop(productID string) bool {
+	return s.products[productID]
+}
+
+// Join puts the customer in the product's queue. Customers already waiting or admitted
+// keep their ticket; those whose access expired go to the back of the queue.
+func (s *WaitingRoomService) Join(ctx context.Context, productID, userID string) (*WaitingRoomTicket, error) {
+	if !s.IsDrop(productID) {
+		return nil, ErrNotADrop
+	}
+
+	ticket, err := s.repo.FindByUser(ctx, productID, userID)
+	switch {
+	case err == ErrWaitingRoomTicketNotFound:
+		ticket = &WaitingRoomTicket{ProductID: productID, UserID: userID}
+	case err != nil:
+		return nil, err
+	case s.isExpired(ticket, time.Now()):
+	default:
+		return s.withPosition(ctx, ticket)
+	}
+
+	token, err := newWaitingRoomToken()
+	if err != nil {
+		return nil, err
+	}
+	ticket.Token = token
+	ticket.Status = WaitingRoomWaiting
+	ticket.JoinedAt = time.Now()
+	ticket.AdmittedAt = nil
+	ticket.ExpiresAt = nil
+	if err := s.repo.Save(ctx, ticket); err != nil {
+		return nil, err
+	}
+	return s.withPosition(ctx, ticket)
+}
+
+// Status returns the customer's ticket for a product with their place in the queue
+func (s *WaitingRoomService) Status(ctx context.Context, productID, userID string) (*WaitingRoomTicket, error) {
+	if !s.IsDrop(productID) {
+		return nil, ErrNotADrop
+	}
+
+	ticket, err := s.repo.FindByUser(ctx, productID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.isExpired(ticket, time.Now()) {
+		ticket.Status = WaitingRoomExpired
+	}
+	return s.withPosition(ctx, ticket)
+}
+
+// Authorize checks that the customer may add a product to their cart. Products that
+// aren't drops are always allowed; drops need the customer's own admitted, unexpired token.
+func (s *WaitingRoomService) Authorize(ctx context.Context, productID, userID, token string) error {
+	if !s.IsDrop(productID) {
+		return nil
+	}
+	if token == "" {
+		return ErrWaitingRoomRequired
+	}
+
+	ticket, err := s.repo.FindByToken(ctx, token)
+	if err == ErrWaitingRoomTicketNotFound {
+		return ErrWaitingRoomRequired
+	}
+	if err != nil {
+		return err
+	}
+	if ticket.ProductID != productID || ticket.UserID != userID {
+		return ErrWaitingRoomRequired
+	}
+
+	switch {
+	case s.isExpired(ticket, time.Now()):
+		return ErrWaitingRoomExpired
+	case ticket.Status == WaitingRoomWaiting:
+		return ErrWaitingRoomNotAdmitted
+	}
+	return nil
+}
+
+// AdmitNext expires used-up access and admits the next batch of every drop's queue.
+// It runs every AdmitInterval as a scheduled task.
+func (s *WaitingRoomService) AdmitNext(ctx context.Context) (int64, error) {
+	now := time.Now()
+	if _, err := s.repo.ExpireAdmitted(ctx, now); err != nil {
+		return 0, err
+	}
+
+	var admitted int64
+	for _, productID := range s.policy.Products {
+		n, err := s.repo.AdmitNext(ctx, productID, s.policy.AdmitBatch, now, now.Add(s.policy.AccessTTL))
+		if err != nil {
+			return admitted, err
+		}
+		admitted += n
+	}
+	return admitted, nil
+}
+
+// withPosition fills in a waiting ticket's place in the queue and how long until it is
+// admitted at the configured rate
+func (s *WaitingRoomService) withPosition(ctx context.Context, ticket *WaitingRoomTicket) (*WaitingRoomTicket, error) {
+	ticket.Position = 0
+	ticket.EstimatedWaitSeconds = 0
+	if ticket.Status != WaitingRoomWaiting {
+		return ticket, nil
+	}
+
+	ahead, err := s.repo.CountAhead(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	ticket.Position = ahead + 1
+	batches := (ticket.Position + int64(s.policy.AdmitBatch) - 1) / int64(s.policy.AdmitBatch)
+	ticket.EstimatedWaitSeconds = batches * int64(s.policy.AdmitInterval/time.Second)
+	return ticket, nil
+}
+
+// isExpired reports whether an admitted ticket's access has run out
+func (s *WaitingRoomService) isExpired(ticket *WaitingRoomTicket, now time.Time) bool {
+	if ticket.Status == WaitingRoomExpired {
+		return true
+	}
+	return ticket.Status == WaitingRoomAdmitted && ticket.ExpiresAt != nil && !now.Before(*ticket.ExpiresAt)
+}
+
+func newWaitingRoomToken() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return "wr_" + hex.EncodeToString(raw), nil
+}
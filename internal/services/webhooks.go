@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrWebhookProviderUnknown  = errors.New("unknown webhook provider")
+	ErrWebhookProviderExists   = errors.New("webhook provider is already registered")
+	ErrInvalidWebhookSignature = errors.New("webhook signature is invalid")
+	ErrInvalidWebhookPayload   = errors.New("webhook payload could not be parsed")
+	ErrWebhookEventNotFound    = errors.New("webhook event not found")
+	ErrWebhookPayloadPurged    = errors.New("webhook payload was purged by the retention policy")
+)
+
+// WebhookJobKind is the job kind of webhook processing, whose failures are dead-lettered
+// and replayed with ReplayDeadLetter
+const WebhookJobKind = "webhook"
+
+// WebhookEventStatus tracks an inbound webhook through processing
+type WebhookEventStatus string
+
+const (
+	WebhookEventStatusPending   WebhookEventStatus = "pending"
+	WebhookEventStatusProcessed WebhookEventStatus = "processed"
+	WebhookEventStatusFailed    WebhookEventStatus = "failed"
+)
+
+// WebhookEvent is an inbound webhook as it was received, kept so it can be replayed
+type WebhookEvent struct {
+	ID          string             `json:"id"`
+	Provider    string             `json:"provider"`
+	ExternalID  string             `json:"external_id,omitempty"` // the provider's event ID
+	EventType   string             `json:"event_type,omitempty"`
+	Payload     json.RawMessage    `json:"payload"`
+	Status      WebhookEventStatus `json:"status"`
+	Attempts    int                `json:"attempts"`
+	LastError   string             `json:"last_error,omitempty"`
+	ReceivedAt  time.Time          `json:"received_at"`
+	ProcessedAt *time.Time         `json:"processed_at,omitempty"`
+}
+
+// WebhookProvider verifies and applies webhooks from one external service. Providers are
+// registered under a name, which is the :provider segment of the receiver URL.
+type WebhookProvider interface {
+	// Verify checks the request signature against the raw body
+	Verify(header http.Header, body []byte) error
+	// Identify returns the provider's event ID, used to drop redeliveries, and the event type
+	Identify(body []byte) (eventID, eventType string, err error)
+	// Process applies a verified event. It runs on the job runner and may be replayed.
+	Process(ctx context.Context, event *WebhookEvent) error
+}
+
+// WebhookEventFilter filters the admin webhook event log
+type WebhookEventFilter struct {
+	Provider string
+	Status   WebhookEventStatus
+	Limit    int
+	Offset   int
+}
+
+// WebhookEventRepository defines persistence for inbound webhook events
+type WebhookEventRepository interface {
+	FindByID(ctx context.Context, id string) (*WebhookEvent, error)
+	FindByExternalID(ctx context.Context, provider, externalID string) (*WebhookEvent, error)
+	List(ctx context.Context, filter WebhookEventFilter) ([]*WebhookEvent, error)
+	Count(ctx context.Context, filter WebhookEventFilter) (int64, error)
+	Save(ctx context.Context, event *WebhookEvent) error
+}
+
+// WebhookService receives webhooks for registered providers. Each event is verified,
+// stored, acknowledged and then processed asynchronously on the job runner.
+type WebhookService struct {
+	repo      WebhookEventRepository
+	runner    *jobs.Runner
+	locker    lock.Manager
+	mu        sync.RWMutex
+	providers map[string]WebhookProvider
+	queued    map[string]struct{} // events handed to the runner and not yet finished
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(repo WebhookEventRepository, runner *jobs.Runner) *WebhookService {
+	return &WebhookService{
+		repo:      repo,
+		runner:    runner,
+		providers: make(map[string]WebhookProvider),
+		queued:    make(map[string]struct{}),
+	}
+}
+
+// WithLocker makes replicas take a shared lock before processing an event, so an event
+// queued by more than one replica is still applied once
+func (s *WebhookService) WithLocker(locker lock.Manager) *WebhookService {
+	s.locker = locker
+	return s
+}
+
+// RegisterProvider adds a provider under the given name
+func (s *WebhookService) RegisterProvider(name string, provider WebhookProvider) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.providers[name]; exists {
+		return ErrWebhookProviderExists
+	}
+	s.providers[name] = provider
+	return nil
+}
+
+// Providers lists the registered provider names
+func (s *WebhookService) Providers() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Receive verifies and stores an inbound webhook and queues it for processing.
+// A redelivery of an event already stored returns the original with duplicate set.
+func (s *WebhookService) Receive(ctx context.Context, providerName string, header http.Header, body []byte) (event *WebhookEvent, duplicate bool, err error) {
+	providerName = strings.ToLower(providerName)
+	provider, ok := s.provider(providerName)
+	if !ok {
+		return nil, false, ErrWebhookProviderUnknown
+	}
+
+	if err := provider.Verify(header, body); err != nil {
+		return nil, false, ErrInvalidWebhookSignature
+	}
+	if !json.Valid(body) {
+		return nil, false, ErrInvalidWebhookPayload
+	}
+
+	externalID, eventType, err := provider.Identify(body)
+	if err != nil {
+		return nil, false, ErrInvalidWebhookPayload
+	}
+
+	if externalID != "" {
+		existing, err := s.repo.FindByExternalID(ctx, providerName, externalID)
+		if err == nil {
+			return existing, true, nil
+		}
+		if err != ErrWebhookEventNotFound {
+			return nil, false, err
+		}
+	}
+
+	event = &WebhookEvent{
+		ID:         utils.GenerateID(),
+		Provider:   providerName,
+		ExternalID: externalID,
+		EventType:  eventType,
+		Payload:    json.RawMessage(body),
+		Status:     WebhookEventStatusPending,
+		ReceivedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, event); err != nil {
+		return nil, false, err
+	}
+
+	s.enqueue(event.ID)
+	return event, false, nil
+}
+
+// Replay queues a stored event to be processed again, whatever its previous outcome
+func (s *WebhookService) Replay(ctx context.Context, id string) (*WebhookEvent, error) {
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.provider(event.Provider); !ok {
+		return nil, ErrWebhookProviderUnknown
+	}
+	if string(event.Payload) == "null" {
+		return nil, ErrWebhookPayloadPurged
+	}
+
+	event.Status = WebhookEventStatusPending
+	event.LastError = ""
+	if err := s.repo.Save(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.enqueue(event.ID)
+	return event, nil
+}
+
+// RequeuePending queues events that were stored but never processed, such as those
+// received just before a restart or while the job queue was full. Events already
+// queued are not queued twice.
+func (s *WebhookService) RequeuePending(ctx context.Context) (int, error) {
+	pending, err := s.repo.List(ctx, WebhookEventFilter{Status: WebhookEventStatusPending})
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range pending {
+		s.enqueue(event.ID)
+	}
+	return len(pending), nil
+}
+
+// ListEvents lists stored webhook events, newest first
+func (s *WebhookService) ListEvents(ctx context.Context, filter WebhookEventFilter) ([]*WebhookEvent, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountEvents counts stored webhook events matching the filter
+func (s *WebhookService) CountEvents(ctx context.Context, filter WebhookEventFilter) (int64, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// GetEvent returns a stored webhook event by ID
+func (s *WebhookService) GetEvent(ctx context.Context, id string) (*WebhookEvent, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Process applies a stored event with its provider and records the outcome.
+// It is normally run by the job runner; processed events are skipped.
+func (s *WebhookService) Process(ctx context.Context, id string) error {
+	if s.locker != nil {
+		held, err := s.locker.TryAcquire(ctx, "webhook:"+id)
+		if err == lock.ErrNotAcquired {
+			return nil // another replica is processing it
+		}
+		if err != nil {
+			return err
+		}
+		defer held.Release(context.Background())
+	}
+
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if event.Status == WebhookEventStatusProcessed {
+		return nil
+	}
+
+	provider, ok := s.provider(event.Provider)
+	if !ok {
+		return ErrWebhookProviderUnknown
+	}
+
+	event.Attempts++
+	procErr := provider.Process(ctx, event)
+	if procErr != nil {
+		event.Status = WebhookEventStatusFailed
+		event.LastError = procErr.Error()
+	} else {
+		now := time.Now()
+		event.Status = WebhookEventStatusProcessed
+		event.LastError = ""
+		event.ProcessedAt = &now
+	}
+
+	if err := s.repo.Save(ctx, event); err != nil {
+		return err
+	}
+	return procErr
+}
+
+// ReplayDeadLetter processes again the event of a dead-lettered webhook job
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, payload []byte) error {
+	var job webhookJobPayload
+	if err := json.Unmarshal(payload, &job); err != nil || job.EventID == "" {
+		return ErrInvalidWebhookPayload
+	}
+	return s.Process(ctx, job.EventID)
+}
+
+// webhookJobPayload identifies the event a webhook job processes
+type webhookJobPayload struct {
+	EventID string `json:"event_id"`
+}
+
+func (s *WebhookService) provider(name string) (WebhookProvider, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	provider, ok := s.providers[name]
+	return provider, ok
+}
+
+// enqueue hands an event to the job runner unless it is already queued. If the queue
+// is full the event stays pending in the database and is picked up by RequeuePending.
+func (s *WebhookService) enqueue(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.queued[id]; ok {
+		return
+	}
+
+	payload, _ := json.Marshal(webhookJobPayload{EventID: id})
+	err := s.runner.Enqueue(jobs.Job{
+		Name:    "webhook:" + id,
+		Kind:    WebhookJobKind,
+		Payload: payload,
+		Run: func(ctx context.Context) error {
+			defer func() {
+				s.mu.Lock()
+				delete(s.queued, id)
+				s.mu.Unlock()
+			}()
+			return s.Process(ctx, id)
+		},
+	})
+	if err != nil {
+		log.Printf("Webhook event %s left pending: %v", id, err)
+		return
+	}
+	s.queued[id] = struct{}{}
+}
+
+// HMACVerifier verifies webhooks signed with a shared secret: the header carries the hex
+// HMAC-SHA256 of the raw body, with or without a "sha256=" prefix
+type HMACVerifier struct {
+	Header string
+	Secret []byte
+}
+
+// Verify implements the signature check for WebhookProvider
+func (v HMACVerifier) Verify(header http.Header, body []byte) error {
+	if !ValidHMACSignature(v.Secret, body, header.Get(v.Header)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// ValidHMACSignature checks a hex HMAC-SHA256 signature of body, with or without a "sha256=" prefix
+func ValidHMACSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 || signature == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,451 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var (
+	ErrWishlistNotFound        = errors.New("wishlist not found")
+	ErrWishlistItemNotFound    = errors.New("wishlist item not found")
+	ErrInvalidWishlist         = errors.New("invalid wishlist")
+	ErrInvalidWishlistItem     = errors.New("invalid wishlist item")
+	ErrRegistryItemUnavailable = errors.New("registry item is not available")
+	ErrRegistryItemPurchased   = errors.New("registry item has already been bought")
+	ErrRegistryItemInCart      = errors.New("product is already in the cart")
+)
+
+// WishlistItemAttribute is the cart and order item attribute recording the registry item
+// an item is bought for
+const WishlistItemAttribute = "wishlist_item_id"
+
+// Wishlist purchase statuses
+const (
+	WishlistPurchaseBought   = "bought"
+	WishlistPurchaseCanceled = "canceled"
+)
+
+// Wishlist is a customer's list of products they'd like. A public wishlist is a gift
+// registry: anyone with its share token can view it and buy its items for the owner.
+type Wishlist struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	Name       string         `json:"name"`
+	IsPublic   bool           `json:"is_public"`
+	ShareToken string         `json:"share_token,omitempty"` // set the first time the wishlist is made public
+	Items      []WishlistItem `json:"items"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// WishlistItem is a product on a wishlist with the quantity wanted and the quantity
+// already bought from the registry
+type WishlistItem struct {
+	ID         string    `json:"id"`
+	WishlistID string    `json:"wishlist_id"`
+	ProductID  string    `json:"product_id"`
+	VariantID  *string   `json:"variant_id,omitempty"`
+	Quantity   int       `json:"quantity"`
+	Purchased  int       `json:"purchased"`
+	Note       string    `json:"note,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// Remaining returns how many units are still wanted
+func (i WishlistItem) Remaining() int {
+	if i.Purchased >= i.Quantity {
+		return 0
+	}
+	return i.Quantity - i.Purchased
+}
+
+// WishlistPurchase records units of a registry item an order bought
+type WishlistPurchase struct {
+	ID          string     `json:"id"`
+	WishlistID  string     `json:"wishlist_id"`
+	ItemID      string     `json:"item_id"`
+	OrderID     string     `json:"order_id"`
+	OrderItemID string     `json:"order_item_id"`
+	BuyerID     string     `json:"buyer_id"` // the user or guest who placed the order
+	Quantity    int        `json:"quantity"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CanceledAt  *time.Time `json:"canceled_at,omitempty"`
+}
+
+// Registry is a public wishlist as guests see it, without its owner's account
+type Registry struct {
+	Name       string         `json:"name"`
+	ShareToken string         `json:"share_token"`
+	Items      []WishlistItem `json:"items"`
+}
+
+// WishlistRequest holds the fields of a wishlist its owner sets
+type WishlistRequest struct {
+	Name     string
+	IsPublic bool
+}
+
+// WishlistItemRequest holds the fields of a wishlist item its owner sets
+type WishlistItemRequest struct {
+	ProductID string
+	VariantID *string
+	Quantity  int
+	Note      string
+}
+
+// WishlistRepository defines persistence for wishlists, their items and the purchases
+// made from registries
+type WishlistRepository interface {
+	// FindByID returns a wishlist with its items
+	FindByID(ctx context.Context, id string) (*Wishlist, error)
+	// FindByUserID returns a user's wishlists with their items, newest first
+	FindByUserID(ctx context.Context, userID string) ([]*Wishlist, error)
+	// FindByShareToken returns the wishlist shared by the token with its items
+	FindByShareToken(ctx context.Context, token string) (*Wishlist, error)
+	// Save creates a wishlist or updates its name, visibility and share token
+	Save(ctx context.Context, wishlist *Wishlist) error
+	// Delete deletes a wishlist and its items
+	Delete(ctx context.Context, id string) error
+	FindItem(ctx context.Context, id string) (*WishlistItem, error)
+	// SaveItem creates an item or updates its quantity and note, leaving the quantity
+	// purchased as it is
+	SaveItem(ctx context.Context, item *WishlistItem) error
+	DeleteItem(ctx context.Context, id string) error
+	// Purchase atomically marks the purchase's units of its item bought and records it; it
+	// returns ErrRegistryItemPurchased when fewer units are still wanted
+	Purchase(ctx context.Context, purchase *WishlistPurchase) error
+	// CancelOrderPurchases gives the units an order bought back to their items and marks
+	// its purchases canceled
+	CancelOrderPurchases(ctx context.Context, orderID string, at time.Time) error
+}
+
+// WishlistService manages customers' wishlists and, for public ones, the registry guests
+// buy from. Registry items are bought through the cart like any other item; the units
+// ordered are marked bought so two guests can't buy the same gift.
+type WishlistService struct {
+	repo     WishlistRepository
+	products catalog.ProductRepository
+}
+
+// NewWishlistService creates a new WishlistService
+func NewWishlistService(repo WishlistRepository, products catalog.ProductRepository) *WishlistService {
+	return &WishlistService{repo: repo, products: products}
+}
+
+// ListWishlists returns a user's wishlists
+func (s *WishlistService) ListWishlists(ctx context.Context, userID string) ([]*Wishlist, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+// GetWishlist returns one of a user's wishlists. Other users' wishlists are reported as
+// not found.
+func (s *WishlistService) GetWishlist(ctx context.Context, userID, id string) (*Wishlist, error) {
+	wishlist, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.UserID != userID {
+		return nil, ErrWishlistNotFound
+	}
+	return wishlist, nil
+}
+
+// CreateWishlist creates a wishlist for a user
+func (s *WishlistService) CreateWishlist(ctx context.Context, userID string, req WishlistRequest) (*Wishlist, error) {
+	now := time.Now()
+	wishlist := &Wishlist{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Items:     []WishlistItem{},
+		CreatedAt: now,
+	}
+	if err := s.apply(wishlist, req); err != nil {
+		return nil, err
+	}
+	wishlist.UpdatedAt = now
+	if err := s.repo.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+// UpdateWishlist renames a user's wishlist or makes it public or private. Making it public
+// again keeps the share token it had, so links already shared keep working.
+func (s *WishlistService) UpdateWishlist(ctx context.Context, userID, id string, req WishlistRequest) (*Wishlist, error) {
+	wishlist, err := s.GetWishlist(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(wishlist, req); err != nil {
+		return nil, err
+	}
+	wishlist.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
+
+// DeleteWishlist deletes a user's wishlist
+func (s *WishlistService) DeleteWishlist(ctx context.Context, userID, id string) error {
+	if _, err := s.GetWishlist(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// AddItem adds a product to a user's wishlist. A product already on it has the quantity
+// added to the quantity wanted.
+func (s *WishlistService) AddItem(ctx context.Context, userID, wishlistID string, req WishlistItemRequest) (*Wishlist, error) {
+	wishlist, err := s.GetWishlist(ctx, userID, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidWishlistItem)
+	}
+	if _, err := s.products.FindByID(ctx, req.ProductID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	item := wishlistLine(wishlist, req.ProductID, req.VariantID)
+	if item == nil {
+		item = &WishlistItem{
+			ID:         utils.GenerateID(),
+			WishlistID: wishlist.ID,
+			ProductID:  req.ProductID,
+			VariantID:  req.VariantID,
+			AddedAt:    time.Now(),
+		}
+	}
+	item.Quantity += req.Quantity
+	if req.Note != "" {
+		item.Note = strings.TrimSpace(req.Note)
+	}
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, wishlist.ID)
+}
+
+// UpdateItem changes the quantity wanted and note of an item on a user's wishlist. The
+// quantity can't go below the units already bought.
+func (s *WishlistService) UpdateItem(ctx context.Context, userID, wishlistID, itemID string, quantity int, note string) (*Wishlist, error) {
+	item, err := s.ownedItem(ctx, userID, wishlistID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 || quantity < item.Purchased {
+		return nil, fmt.Errorf("%w: quantity must be positive and at least the %d already bought", ErrInvalidWishlistItem, item.Purchased)
+	}
+	item.Quantity = quantity
+	item.Note = strings.TrimSpace(note)
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, wishlistID)
+}
+
+// RemoveItem removes an item from a user's wishlist
+func (s *WishlistService) RemoveItem(ctx context.Context, userID, wishlistID, itemID string) error {
+	if _, err := s.ownedItem(ctx, userID, wishlistID, itemID); err != nil {
+		return err
+	}
+	return s.repo.DeleteItem(ctx, itemID)
+}
+
+// GetRegistry returns the public wishlist shared by the token. Private wishlists are
+// reported as not found.
+func (s *WishlistService) GetRegistry(ctx context.Context, token string) (*Registry, error) {
+	wishlist, err := s.repo.FindByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !wishlist.IsPublic {
+		return nil, ErrWishlistNotFound
+	}
+	return &Registry{Name: wishlist.Name, ShareToken: wishlist.ShareToken, Items: wishlist.Items}, nil
+}
+
+// PrepareItem checks an item about to be added to the cart for a registry. The registry
+// item must be on a public wishlist, be of the same product and variant, and still want
+// the units already in the cart for it plus the ones added. A product can only be in the
+// cart once when bought for a registry, so its units aren't mixed with the shopper's own.
+func (s *WishlistService) PrepareItem(ctx context.Context, c *cart.Cart, req *cart.AddItemRequest) error {
+	line := cartLine(c, req.ProductID, req.VariantID)
+	itemID := req.Attributes[WishlistItemAttribute]
+	if itemID == "" {
+		if line != nil && line.Attributes[WishlistItemAttribute] != "" {
+			return ErrRegistryItemInCart
+		}
+		return nil
+	}
+
+	quantity := req.Quantity
+	if line != nil {
+		if line.Attributes[WishlistItemAttribute] != itemID {
+			return ErrRegistryItemInCart
+		}
+		quantity += line.Quantity
+	}
+	_, err := s.registryItem(ctx, itemID, req.ProductID, req.VariantID, quantity)
+	return err
+}
+
+// CheckItem checks that a registry item still wants the quantity given. Items not bought
+// for a registry pass.
+func (s *WishlistService) CheckItem(ctx context.Context, item *cart.CartItem, quantity int) error {
+	itemID := item.Attributes[WishlistItemAttribute]
+	if itemID == "" {
+		return nil
+	}
+	_, err := s.registryItem(ctx, itemID, item.ProductID, item.VariantID, quantity)
+	return err
+}
+
+// CheckCart checks every registry item in the cart before an order is placed
+func (s *WishlistService) CheckCart(ctx context.Context, c *cart.Cart) error {
+	for i := range c.Items {
+		if err := s.CheckItem(ctx, &c.Items[i], c.Items[i].Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurchaseOrder marks the units of a placed order's registry items bought. When another
+// order bought them in the meantime, the units already marked for the order are given
+// back and ErrRegistryItemPurchased returned.
+func (s *WishlistService) PurchaseOrder(ctx context.Context, order *orders.Order) error {
+	now := time.Now()
+	for _, item := range order.Items {
+		itemID := item.Attributes[WishlistItemAttribute]
+		if itemID == "" {
+			continue
+		}
+		registryItem, err := s.registryItem(ctx, itemID, item.ProductID, item.VariantID, item.Quantity)
+		if err == nil {
+			err = s.repo.Purchase(ctx, &WishlistPurchase{
+				ID:          utils.GenerateID(),
+				WishlistID:  registryItem.WishlistID,
+				ItemID:      registryItem.ID,
+				OrderID:     order.ID,
+				OrderItemID: item.ID,
+				BuyerID:     order.UserID,
+				Quantity:    item.Quantity,
+				Status:      WishlistPurchaseBought,
+				CreatedAt:   now,
+			})
+		}
+		if err != nil {
+			if releaseErr := s.repo.CancelOrderPurchases(ctx, order.ID, now); releaseErr != nil {
+				return fmt.Errorf("%w (and giving back the order's other registry items failed: %v)", err, releaseErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseOrder gives the registry units an order bought back to the registry, when it is
+// canceled
+func (s *WishlistService) ReleaseOrder(ctx context.Context, orderID string) error {
+	return s.repo.CancelOrderPurchases(ctx, orderID, time.Now())
+}
+
+// registryItem returns an item of a public wishlist matching the product and variant that
+// still wants quantity units
+func (s *WishlistService) registryItem(ctx context.Context, itemID, productID string, variantID *string, quantity int) (*WishlistItem, error) {
+	item, err := s.repo.FindItem(ctx, itemID)
+	if errors.Is(err, ErrWishlistItemNotFound) {
+		return nil, ErrRegistryItemUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if item.ProductID != productID || !sameVariant(item.VariantID, variantID) {
+		return nil, fmt.Errorf("%w: the item is for another product", ErrRegistryItemUnavailable)
+	}
+	wishlist, err := s.repo.FindByID(ctx, item.WishlistID)
+	if errors.Is(err, ErrWishlistNotFound) || (err == nil && !wishlist.IsPublic) {
+		return nil, ErrRegistryItemUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	if item.Remaining() < quantity {
+		return nil, fmt.Errorf("%w: %d of %d units requested are still wanted", ErrRegistryItemPurchased, item.Remaining(), quantity)
+	}
+	return item, nil
+}
+
+// ownedItem returns an item of one of the user's wishlists
+func (s *WishlistService) ownedItem(ctx context.Context, userID, wishlistID, itemID string) (*WishlistItem, error) {
+	if _, err := s.GetWishlist(ctx, userID, wishlistID); err != nil {
+		return nil, err
+	}
+	item, err := s.repo.FindItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.WishlistID != wishlistID {
+		return nil, ErrWishlistItemNotFound
+	}
+	return item, nil
+}
+
+// apply validates the fields the owner sets and gives a wishlist made public its share
+// token
+func (s *WishlistService) apply(wishlist *Wishlist, req WishlistRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 255 {
+		return fmt.Errorf("%w: name is required and at most 255 characters", ErrInvalidWishlist)
+	}
+	wishlist.Name = name
+	wishlist.IsPublic = req.IsPublic
+	if wishlist.IsPublic && wishlist.ShareToken == "" {
+		token, err := newShareToken()
+		if err != nil {
+			return err
+		}
+		wishlist.ShareToken = token
+	}
+	return nil
+}
+
+func wishlistLine(wishlist *Wishlist, productID string, variantID *string) *WishlistItem {
+	for i := range wishlist.Items {
+		if wishlist.Items[i].ProductID == productID && sameVariant(wishlist.Items[i].VariantID, variantID) {
+			return &wishlist.Items[i]
+		}
+	}
+	return nil
+}
+
+func sameVariant(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// newShareToken returns an unguessable token for sharing a registry
+func newShareToken() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
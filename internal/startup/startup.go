@@ -0,0 +1,157 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrChecksFailed is returned by Run in ModeFail when a check failed
+var ErrChecksFailed = errors.New("startup checks failed")
+
+// Mode is what a failed check does to startup
+type Mode string
+
+const (
+	ModeFail     Mode = "fail"     // refuse to start
+	ModeDegraded Mode = "degraded" // start anyway and report the failures
+	ModeOff      Mode = "off"      // skip the checks
+)
+
+// Check is one dependency verified before the API serves requests
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string  `json:"name"`
+	OK       bool    `json:"ok"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration_ms"`
+}
+
+// Report is the outcome of the startup checks. Degraded is set when the API started with
+// a check failing.
+type Report struct {
+	Mode      Mode      `json:"mode"`
+	Degraded  bool      `json:"degraded"`
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Failed lists the names of the checks that failed
+func (r *Report) Failed() []string {
+	failed := []string{}
+	for _, result := range r.Checks {
+		if !result.OK {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
+
+// Run runs every check in order and logs each outcome. In ModeFail it returns
+// ErrChecksFailed naming the failed checks; in ModeDegraded the report is marked degraded
+// instead. ModeOff runs nothing.
+func Run(ctx context.Context, mode Mode, checks []Check) (*Report, error) {
+	report := &Report{Mode: mode, CheckedAt: time.Now(), Checks: []Result{}}
+	if mode == ModeOff {
+		log.Println("Startup checks are off")
+		return report, nil
+	}
+
+	for _, check := range checks {
+		started := time.Now()
+		err := check.Run(ctx)
+		result := Result{
+			Name:     check.Name,
+			OK:       err == nil,
+			Duration: float64(time.Since(started).Microseconds()) / 1000,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			log.Printf("✗ Startup check %s failed: %v", check.Name, err)
+		} else {
+			log.Printf("✓ Startup check %s passed", check.Name)
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	failed := report.Failed()
+	if len(failed) == 0 {
+		return report, nil
+	}
+	if mode == ModeFail {
+		return report, fmt.Errorf("%w: %s (set STARTUP_CHECKS=degraded to start anyway)", ErrChecksFailed, strings.Join(failed, ", "))
+	}
+	report.Degraded = true
+	log.Printf("Warning: starting degraded with failed startup checks: %s", strings.Join(failed, ", "))
+	return report, nil
+}
+
+// SecretsCheck fails when any of the named environment variables is unset or empty.
+// lookup reads them; nil uses the process environment.
+func SecretsCheck(names []string, lookup func(string) string) Check {
+	if lookup == nil {
+		lookup = os.Getenv
+	}
+	return Check{
+		Name: "secrets",
+		Run: func(ctx context.Context) error {
+			var missing []string
+			for _, name := range names {
+				if strings.TrimSpace(lookup(name)) == "" {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// DialCheck fails when a TCP connection to addr (host:port) can't be opened within timeout
+func DialCheck(name, addr string, timeout time.Duration) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// URLAddr returns the host:port a URL connects to, using the scheme's default port
+func URLAddr(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port), nil
+	}
+	switch u.Scheme {
+	case "https":
+		return net.JoinHostPort(u.Hostname(), "443"), nil
+	case "http":
+		return net.JoinHostPort(u.Hostname(), "80"), nil
+	default:
+		return "", fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+}
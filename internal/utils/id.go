@@ -1,12 +1,88 @@
 package utils
 
-import "github.com/google/uuid"
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
 
-// GenerateID generates a new UUID v4 ID
+	"github.com/google/uuid"
+)
+
+// IDStrategy is how GenerateID makes new record IDs
+type IDStrategy string
+
+const (
+	// IDUUIDv4 is a random UUID, the default
+	IDUUIDv4 IDStrategy = "uuidv4"
+	// IDUUIDv7 is a UUID led by its creation time in milliseconds, so IDs sort by creation
+	// and new rows land at the end of primary key indexes
+	IDUUIDv7 IDStrategy = "uuidv7"
+	// IDULID is a ULID: creation time and randomness in 26 Crockford base32 characters,
+	// sorting by creation like IDUUIDv7
+	IDULID IDStrategy = "ulid"
+)
+
+var idStrategy atomic.Value // IDStrategy
+
+// ParseIDStrategy parses uuidv4, uuidv7 or ulid; an empty string is IDUUIDv4
+func ParseIDStrategy(s string) (IDStrategy, error) {
+	switch strategy := IDStrategy(strings.ToLower(strings.TrimSpace(s))); strategy {
+	case "":
+		return IDUUIDv4, nil
+	case IDUUIDv4, IDUUIDv7, IDULID:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("ID strategy %q must be uuidv4, uuidv7 or ulid", s)
+	}
+}
+
+// SetIDStrategy sets how GenerateID makes IDs from now on. Set it once at startup; IDs
+// already stored keep their format, and the strategies mix safely in the same columns.
+func SetIDStrategy(strategy IDStrategy) {
+	idStrategy.Store(strategy)
+}
+
+// GenerateID generates a new ID with the configured strategy, a UUID v4 by default
 func GenerateID() string {
+	strategy, _ := idStrategy.Load().(IDStrategy)
+	switch strategy {
+	case IDUUIDv7:
+		if id, err := uuid.NewV7(); err == nil {
+			return id.String()
+		}
+	case IDULID:
+		return NewULID(time.Now())
+	}
 	return uuid.New().String()
 }
 
+// crockford is the Crockford base32 alphabet ULIDs are written in
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID for the given time: 48 bits of Unix milliseconds followed by 80
+// random bits
+func NewULID(t time.Time) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(t.UnixMilli())<<16)
+	if _, err := rand.Read(b[6:]); err != nil {
+		panic(fmt.Sprintf("utils: reading random bytes for ULID: %v", err))
+	}
+
+	// 128 bits in 26 characters of 5 bits each, the first carrying the top 3
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
 // GenerateOrderNumber generates a unique order number
 func GenerateOrderNumber() string {
 	return "ORD-" + uuid.New().String()[:8]
@@ -0,0 +1,175 @@
+//go:build integration
+
+package benchmark_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+)
+
+// benchProfile is the catalog the benchmarks query. It is seeded once per database and
+// left in place: seeding is deterministic, so later runs insert nothing and every run
+// measures the same data.
+var benchProfile = database.SeedProfile{
+	Name:        "bench",
+	Categories:  40,
+	Brands:      100,
+	Products:    20000,
+	Customers:   500,
+	Orders:      5000,
+	SalePercent: 15,
+	RandSeed:    1,
+}
+
+var (
+	seedOnce sync.Once
+	seedErr  error
+	sample   database.Product // a seeded product, for lookups with real keys
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(helpers.IntegrationMain(m))
+}
+
+// seedCatalog seeds benchProfile on first use
+func seedCatalog(b *testing.B) {
+	b.Helper()
+	seedOnce.Do(func() {
+		db := &database.DB{DB: helpers.IntegrationDB(b)}
+		if seedErr = db.SeedWithProfile(context.Background(), benchProfile); seedErr != nil {
+			return
+		}
+		seedErr = db.Where("id LIKE ? AND status = ?", "seed-bench-prod-%", "active").Order("id").First(&sample).Error
+	})
+	if seedErr != nil {
+		b.Fatalf("failed to seed benchmark catalog: %v", seedErr)
+	}
+}
+
+func BenchmarkProductRepository_List(b *testing.B) {
+	seedCatalog(b)
+	repo := repository.NewProductRepository(helpers.IntegrationDB(b))
+	ctx := context.Background()
+	active := catalog.ProductStatusActive
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// One page and the total count, as GET /catalog/products loads them, walking the
+		// first 50 pages the way a shopper paging through the catalog would
+		filter := catalog.ProductFilter{Status: &active, Limit: 20, Offset: (i % 50) * 20}
+		if _, err := repo.Search(ctx, "", filter); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := repo.CountProducts(ctx, filter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProductRepository_FindByCategory(b *testing.B) {
+	seedCatalog(b)
+	repo := repository.NewProductRepository(helpers.IntegrationDB(b))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.FindByCategory(ctx, sample.CategoryID, catalog.ProductFilter{Limit: 20}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProductRepository_Search(b *testing.B) {
+	seedCatalog(b)
+	repo := repository.NewProductRepository(helpers.IntegrationDB(b))
+	ctx := context.Background()
+	words := strings.Fields(sample.Name)
+	term := words[len(words)-1]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Search(ctx, term, catalog.ProductFilter{Limit: 20}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCartService_AddItem(b *testing.B) {
+	seedCatalog(b)
+	db := helpers.TxDB(b)
+	cartService := services.NewCartService(
+		repository.NewCartRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewVariantRepository(db),
+		nil,
+	)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// A fresh cart each time, so the measurement does not grow with the cart
+		userCart, err := cartService.GetOrCreateCart(ctx, fmt.Sprintf("bench-user-%d", i), "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{ProductID: sample.ID, Quantity: 1}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOrderService_CreateFromCart(b *testing.B) {
+	seedCatalog(b)
+	db := helpers.TxDB(b)
+	cartService := services.NewCartService(
+		repository.NewCartRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewVariantRepository(db),
+		nil,
+	)
+	pricingService := services.NewPricingService(
+		repository.NewPromotionRepository(db),
+		services.NewSimpleTaxCalculator(0.0875),
+		nil,
+	)
+	orderService := services.NewOrderService(repository.NewOrderRepository(db), pricingService.Service, nil, nil)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		userID := fmt.Sprintf("bench-user-%d", i)
+		userCart, err := cartService.GetOrCreateCart(ctx, userID, "")
+		if err == nil {
+			userCart, err = cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{ProductID: sample.ID, Quantity: 2})
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		_, err = orderService.CreateFromCart(ctx, orders.CreateOrderRequest{
+			Cart:            userCart,
+			UserID:          userID,
+			ShippingAddress: fixtures.TestShippingAddress,
+			BillingAddress:  fixtures.TestBillingAddress,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
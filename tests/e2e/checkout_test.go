@@ -0,0 +1,273 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/goauthx"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/app"
+	"github.com/devchuckcamp/gocommerce-api/internal/config"
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+)
+
+const webhookSecret = "e2e-webhook-secret"
+
+func TestMain(m *testing.M) {
+	os.Exit(helpers.IntegrationMain(m))
+}
+
+// envelope is the API's response wrapper
+type envelope[T any] struct {
+	Data T `json:"data"`
+}
+
+type moneyJSON struct {
+	Amount   int64
+	Currency string
+}
+
+type orderJSON struct {
+	ID            string
+	Status        string
+	DiscountTotal moneyJSON
+	Total         moneyJSON
+}
+
+// TestCheckoutFlow drives the real router through a whole purchase: a new customer signs
+// up, finds a product, checks out with a promotion code and pays, and the gateway's
+// webhook marks the order paid
+func TestCheckoutFlow(t *testing.T) {
+	api := startAPI(t)
+
+	// Rows written through the API are committed, so every run uses its own keys
+	run := time.Now().UnixNano()
+	productID := fmt.Sprintf("e2e-product-%d", run)
+	promoCode := fmt.Sprintf("E2E%d", run)
+	email := fmt.Sprintf("e2e-%d@example.com", run)
+	createCatalog(t, api.db, productID, promoCode)
+
+	// Register and log in
+	rec := api.http.POST("/api/v1/auth/register", map[string]string{
+		"email":      email,
+		"password":   "Sup3r-secret!",
+		"first_name": "Ada",
+		"last_name":  "Lovelace",
+	})
+	helpers.AssertStatus(t, rec, http.StatusCreated)
+
+	rec = api.http.POST("/api/v1/auth/login", map[string]string{
+		"email":    email,
+		"password": "Sup3r-secret!",
+	})
+	helpers.AssertStatus(t, rec, http.StatusOK)
+	var login envelope[struct {
+		AccessToken string `json:"access_token"`
+	}]
+	helpers.ParseResponse(t, rec, &login)
+	token := login.Data.AccessToken
+	if token == "" {
+		t.Fatalf("login returned no access token: %s", rec.Body.String())
+	}
+
+	// Browse
+	helpers.AssertStatus(t, api.http.GET("/api/v1/catalog/products"), http.StatusOK)
+	helpers.AssertStatus(t, api.http.GET("/api/v1/catalog/products/"+productID), http.StatusOK)
+
+	// Add to cart
+	rec = api.http.RequestWithAuth(http.MethodPost, "/api/v1/cart/items", token, map[string]interface{}{
+		"product_id": productID,
+		"quantity":   2,
+	})
+	if rec.Code != http.StatusOK && rec.Code != http.StatusCreated {
+		t.Fatalf("add to cart: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Create the order with the promotion applied; no payment method, so it waits for the gateway
+	rec = api.http.RequestWithAuth(http.MethodPost, "/api/v1/orders", token, map[string]interface{}{
+		"shipping_address": map[string]string{
+			"first_name":  "Ada",
+			"last_name":   "Lovelace",
+			"address1":    "1 Main St",
+			"city":        "San Francisco",
+			"state":       "CA",
+			"postal_code": "94105",
+			"country":     "US",
+		},
+		"promotion_codes":    []string{promoCode},
+		"apply_store_credit": false,
+	})
+	helpers.AssertStatus(t, rec, http.StatusCreated)
+	var created envelope[orderJSON]
+	helpers.ParseResponse(t, rec, &created)
+	order := created.Data
+	if order.Status != "pending" {
+		t.Fatalf("new order status = %q, want pending", order.Status)
+	}
+	if order.DiscountTotal.Amount == 0 {
+		t.Errorf("promotion %s was not applied: %s", promoCode, rec.Body.String())
+	}
+
+	// The gateway reports the capture
+	event := services.PaymentIntentWebhookPayload{
+		ID:   fmt.Sprintf("evt_%d", run),
+		Type: services.PaymentEventIntentSucceeded,
+	}
+	event.Data.PaymentIntentID = fmt.Sprintf("pi_%d", run)
+	event.Data.OrderID = order.ID
+	event.Data.Amount = order.Total.Amount
+	event.Data.Currency = order.Total.Currency
+	rec = api.webhook(t, event)
+	if rec.Code != http.StatusOK && rec.Code != http.StatusAccepted {
+		t.Fatalf("payment webhook: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Webhooks are applied by the job runner after the response
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		rec = api.http.RequestWithAuth(http.MethodGet, "/api/v1/orders/"+order.ID, token, nil)
+		helpers.AssertStatus(t, rec, http.StatusOK)
+		var fetched envelope[orderJSON]
+		helpers.ParseResponse(t, rec, &fetched)
+		if fetched.Data.Status == "paid" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("order status = %q after the payment webhook, want paid", fetched.Data.Status)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// testAPI is the application under test
+type testAPI struct {
+	http *helpers.HTTPTestContext
+	db   *database.DB
+}
+
+// startAPI wires the application the way cmd/api does, against the integration database
+func startAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	t.Setenv("DB_DRIVER", "postgres")
+	t.Setenv("DB_DSN", helpers.IntegrationDSN(t))
+	t.Setenv("JWT_SECRET", "e2e-jwt-secret-at-least-32-characters")
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", webhookSecret)
+	t.Setenv("SCHEDULER_ENABLED", "false")
+	t.Setenv("CACHE_WARM_ON_START", "false")
+	t.Setenv("LOCK_BACKEND", "local")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	authxConfig := cfg.ToGoAuthXConfig()
+	authStore, err := goauthx.NewStore(authxConfig.Database)
+	if err != nil {
+		t.Fatalf("failed to create auth store: %v", err)
+	}
+	if err := goauthx.NewMigrator(authStore, authxConfig.Database.Driver).Up(context.Background()); err != nil {
+		t.Fatalf("failed to run auth migrations: %v", err)
+	}
+	seeder := goauthx.NewSeeder(authStore)
+	if err := seeder.SeedAll(context.Background()); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+
+	application, err := app.New(cfg, db, authStore, seeder)
+	if err != nil {
+		t.Fatalf("failed to build application: %v", err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	application.Start(ctx)
+	t.Cleanup(func() {
+		stop()
+		application.Wait()
+	})
+
+	httpCtx := helpers.NewHTTPTestContext()
+	httpCtx.Router = application.Server.Router()
+	return &testAPI{http: httpCtx, db: db}
+}
+
+// webhook posts a payment intent event signed with the gateway's secret
+func (a *testAPI) webhook(t *testing.T, payload services.PaymentIntentWebhookPayload) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to encode webhook: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/payments", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(services.PaymentWebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	a.http.Router.ServeHTTP(rec, req)
+	return rec
+}
+
+// createCatalog inserts the product and the promotion the flow buys with
+func createCatalog(t *testing.T, db *database.DB, productID, promoCode string) {
+	t.Helper()
+	now := time.Now().UTC()
+
+	product := database.Product{
+		ID:          productID,
+		SKU:         productID,
+		Name:        "E2E Widget",
+		Description: "Bought by the end-to-end checkout test",
+		BasePrice:   2500,
+		Currency:    "USD",
+		Status:      "active",
+		Images:      "[]",
+		Metadata:    "{}",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := db.WithContext(context.Background()).Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	promotion := database.Promotion{
+		ID:                 "e2e-promo-" + promoCode,
+		Code:               promoCode,
+		Name:               "E2E 10% off",
+		Type:               "percentage",
+		DiscountPercentage: 10,
+		Currency:           "USD",
+		StartDate:          now.Add(-time.Hour),
+		EndDate:            now.Add(time.Hour),
+		Active:             true,
+		ProductIDs:         "[]",
+		CategoryIDs:        "[]",
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := db.WithContext(context.Background()).Create(&promotion).Error; err != nil {
+		t.Fatalf("failed to create promotion: %v", err)
+	}
+}
@@ -20,6 +20,12 @@ func init() {
 type HTTPTestContext struct {
 	Router   *gin.Engine
 	Recorder *httptest.ResponseRecorder
+	// Auth mints the tokens sent by AsUser and AsAdmin; put Auth.Middleware() in front of
+	// protected routes
+	Auth *TokenFactory
+
+	token   string
+	headers map[string]string
 }
 
 // NewHTTPTestContext creates a new HTTP test context
@@ -27,7 +33,39 @@ func NewHTTPTestContext() *HTTPTestContext {
 	return &HTTPTestContext{
 		Router:   gin.New(),
 		Recorder: httptest.NewRecorder(),
+		Auth:     NewTokenFactory(),
+	}
+}
+
+// AsUser returns a context on the same router whose requests are signed in as a customer
+func (ctx *HTTPTestContext) AsUser(t *testing.T, userID string) *HTTPTestContext {
+	t.Helper()
+	return ctx.withToken(ctx.Auth.UserToken(t, userID))
+}
+
+// AsAdmin returns a context on the same router whose requests are signed in as an administrator
+func (ctx *HTTPTestContext) AsAdmin(t *testing.T, userID string) *HTTPTestContext {
+	t.Helper()
+	return ctx.withToken(ctx.Auth.AdminToken(t, userID))
+}
+
+// WithHeader returns a context on the same router whose requests also send the header
+func (ctx *HTTPTestContext) WithHeader(name, value string) *HTTPTestContext {
+	withHeader := *ctx
+	withHeader.Recorder = httptest.NewRecorder()
+	withHeader.headers = make(map[string]string, len(ctx.headers)+1)
+	for k, v := range ctx.headers {
+		withHeader.headers[k] = v
 	}
+	withHeader.headers[name] = value
+	return &withHeader
+}
+
+func (ctx *HTTPTestContext) withToken(token string) *HTTPTestContext {
+	signedIn := *ctx
+	signedIn.Recorder = httptest.NewRecorder()
+	signedIn.token = token
+	return &signedIn
 }
 
 // Request performs an HTTP request and returns the recorder
@@ -42,6 +80,12 @@ func (ctx *HTTPTestContext) Request(method, path string, body interface{}) *http
 
 	req, _ := http.NewRequest(method, path, reqBody)
 	req.Header.Set("Content-Type", "application/json")
+	if ctx.token != "" {
+		req.Header.Set("Authorization", "Bearer "+ctx.token)
+	}
+	for name, value := range ctx.headers {
+		req.Header.Set(name, value)
+	}
 	ctx.Router.ServeHTTP(ctx.Recorder, req)
 
 	return ctx.Recorder
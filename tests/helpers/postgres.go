@@ -0,0 +1,215 @@
+//go:build integration
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+const (
+	// defaultPostgresImage matches the image in docker-compose.yml
+	defaultPostgresImage = "postgres:16-alpine"
+	// postgresStartTimeout covers pulling the image on a cold machine
+	postgresStartTimeout = 2 * time.Minute
+)
+
+// integrationDB is the migrated database shared by the tests in one package
+var (
+	integrationDB  *gorm.DB
+	integrationDSN string
+)
+
+// PostgresContainer is a throwaway Postgres server running in Docker
+type PostgresContainer struct {
+	ID  string
+	DSN string
+}
+
+// StartPostgres starts Postgres in a new container on a random local port and waits until
+// it accepts connections. TEST_POSTGRES_IMAGE overrides the image.
+func StartPostgres(ctx context.Context) (*PostgresContainer, error) {
+	image := os.Getenv("TEST_POSTGRES_IMAGE")
+	if image == "" {
+		image = defaultPostgresImage
+	}
+
+	out, err := docker(ctx, "run", "-d", "--rm",
+		"--label", "gocommerce-api.tests=integration",
+		"-e", "POSTGRES_USER=commerce",
+		"-e", "POSTGRES_PASSWORD=commerce",
+		"-e", "POSTGRES_DB=commerce",
+		"-p", "127.0.0.1::5432",
+		image,
+		// Durability is not needed for a database that lives as long as the test binary
+		"-c", "fsync=off", "-c", "synchronous_commit=off", "-c", "full_page_writes=off",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start postgres container: %w", err)
+	}
+	container := &PostgresContainer{ID: out}
+
+	port, err := docker(ctx, "port", container.ID, "5432/tcp")
+	if err != nil {
+		container.Terminate(context.Background())
+		return nil, fmt.Errorf("failed to read postgres port: %w", err)
+	}
+	// docker port may list an IPv6 binding as well; the first line is enough
+	address := strings.Split(port, "\n")[0]
+	container.DSN = fmt.Sprintf("postgres://commerce:commerce@%s/commerce?sslmode=disable", address)
+
+	if err := waitForPostgres(ctx, container.DSN); err != nil {
+		container.Terminate(context.Background())
+		return nil, err
+	}
+	return container, nil
+}
+
+// Terminate stops and removes the container
+func (c *PostgresContainer) Terminate(ctx context.Context) error {
+	_, err := docker(ctx, "rm", "-f", "-v", c.ID)
+	return err
+}
+
+// IntegrationMain runs a package's integration tests against a migrated Postgres database.
+// Call it from TestMain:
+//
+//	func TestMain(m *testing.M) { os.Exit(helpers.IntegrationMain(m)) }
+//
+// TEST_DATABASE_URL points the tests at an existing database; otherwise a container is
+// started for the package and removed afterwards. Unlike SkipIfNoDatabase, a database
+// that cannot be reached fails the run instead of skipping it.
+func IntegrationMain(m *testing.M) int {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresStartTimeout)
+	defer cancel()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		container, err := StartPostgres(ctx)
+		if err != nil {
+			log.Printf("integration tests need Docker or TEST_DATABASE_URL: %v", err)
+			return 1
+		}
+		defer container.Terminate(context.Background())
+		dsn = container.DSN
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		log.Printf("failed to connect to integration database: %v", err)
+		return 1
+	}
+
+	if err := (&database.DB{DB: db}).RunCommerceMigrations(ctx); err != nil {
+		log.Printf("failed to migrate integration database: %v", err)
+		return 1
+	}
+
+	integrationDB = db
+	integrationDSN = dsn
+	defer func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	return m.Run()
+}
+
+// TxDB returns a transaction on the integration database that is rolled back when the test
+// ends, so tests see only their own rows and leave nothing behind. Repositories that open
+// their own transactions get savepoints inside it.
+func TxDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	if integrationDB == nil {
+		t.Fatal("integration database is not set up; call helpers.IntegrationMain from TestMain")
+	}
+
+	tx := integrationDB.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin test transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+	return tx
+}
+
+// IntegrationDB returns the shared integration database without a transaction, for data
+// that must outlive a single test, such as the dataset benchmarks run against
+func IntegrationDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	if integrationDB == nil {
+		t.Fatal("integration database is not set up; call helpers.IntegrationMain from TestMain")
+	}
+	return integrationDB
+}
+
+// IntegrationDSN returns the connection string of the integration database, for tests that
+// need their own connections, such as a whole application under test. Their writes are
+// committed, so such tests must use unique keys rather than rely on rollback.
+func IntegrationDSN(t *testing.T) string {
+	t.Helper()
+	if integrationDSN == "" {
+		t.Fatal("integration database is not set up; call helpers.IntegrationMain from TestMain")
+	}
+	return integrationDSN
+}
+
+// waitForPostgres retries a query until the server answers. While the image initializes the
+// database Postgres listens only on its socket, so the first answer over TCP comes from the
+// server the tests will use.
+func waitForPostgres(ctx context.Context, dsn string) error {
+	var lastErr error
+	for {
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if err == nil {
+			var one int
+			err = db.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+			if err == nil {
+				return nil
+			}
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postgres did not become ready: %w", lastErr)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// docker runs a docker CLI command and returns its trimmed output
+func docker(ctx context.Context, args ...string) (string, error) {
+	// Only stdout is kept: docker run reports image pulls on stderr
+	out, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		var stderr string
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, stderr)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,84 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+)
+
+func TestAppointmentRepository_ConcurrentReleasesFreeSeatsOnce(t *testing.T) {
+	// Releases race on their own connections, so the rows are committed and removed after
+	db := helpers.IntegrationDB(t)
+	repo := repository.NewAppointmentRepository(db)
+	ctx := context.Background()
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	slot := &services.AppointmentSlot{
+		ID:        utils.GenerateID(),
+		ProductID: "test-appointment-product",
+		StartsAt:  start,
+		EndsAt:    start.Add(time.Hour),
+		Capacity:  3,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	if err := repo.SaveSlot(ctx, slot); err != nil {
+		t.Fatalf("failed to save slot: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Delete(&database.AppointmentBooking{}, "slot_id = ?", slot.ID)
+		db.Delete(&database.AppointmentSlot{}, "id = ?", slot.ID)
+	})
+
+	canceledOrder, otherOrder := utils.GenerateID(), utils.GenerateID()
+	for _, orderID := range []string{canceledOrder, otherOrder} {
+		if err := repo.Book(ctx, &services.AppointmentBooking{
+			ID:        utils.GenerateID(),
+			SlotID:    slot.ID,
+			ProductID: slot.ProductID,
+			OrderID:   orderID,
+			UserID:    "test-user-" + orderID,
+			Quantity:  1,
+			StartsAt:  slot.StartsAt,
+			EndsAt:    slot.EndsAt,
+			CancelBy:  slot.StartsAt,
+			Status:    services.AppointmentBooked,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("failed to book: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.CancelOrderBookings(ctx, canceledOrder, time.Now()); err != nil {
+				t.Errorf("failed to cancel bookings: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	found, err := repo.FindSlot(ctx, slot.ID)
+	if err != nil {
+		t.Fatalf("failed to find slot: %v", err)
+	}
+	if found.Booked != 1 {
+		t.Errorf("expected the other order's seat still booked, got %d seats booked", found.Booked)
+	}
+	bookings, err := repo.FindOrderBookings(ctx, canceledOrder)
+	if err != nil || len(bookings) != 1 || bookings[0].Status != services.AppointmentCanceled {
+		t.Errorf("expected the order's booking canceled, got %v, %v", bookings, err)
+	}
+}
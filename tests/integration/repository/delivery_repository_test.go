@@ -0,0 +1,60 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+)
+
+func TestDeliverySlotRepository_Reserve(t *testing.T) {
+	db := helpers.TxDB(t)
+	repo := repository.NewDeliverySlotRepository(db)
+	ctx := context.Background()
+
+	start := time.Now().Add(24 * time.Hour)
+	slot := &services.DeliverySlot{
+		ID:       "test-slot-001",
+		Region:   "941",
+		StartsAt: start,
+		EndsAt:   start.Add(3 * time.Hour),
+		Capacity: 2,
+		IsActive: true,
+	}
+	if err := repo.Save(ctx, slot); err != nil {
+		t.Fatalf("failed to save slot: %v", err)
+	}
+
+	// Reserving again for the same order must not take a second unit
+	for i := 0; i < 2; i++ {
+		if err := repo.Reserve(ctx, slot.ID, "test-order-001"); err != nil {
+			t.Fatalf("failed to reserve slot: %v", err)
+		}
+	}
+	if err := repo.Reserve(ctx, slot.ID, "test-order-002"); err != nil {
+		t.Fatalf("failed to reserve slot: %v", err)
+	}
+	if err := repo.Reserve(ctx, slot.ID, "test-order-003"); err != services.ErrDeliverySlotFull {
+		t.Fatalf("expected ErrDeliverySlotFull, got %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, slot.ID)
+	if err != nil {
+		t.Fatalf("failed to find slot: %v", err)
+	}
+	if found.Reserved != 2 {
+		t.Errorf("expected 2 reservations, got %d", found.Reserved)
+	}
+
+	var bookings int64
+	db.Model(&database.OrderDeliverySlot{}).Where("slot_id = ?", slot.ID).Count(&bookings)
+	if bookings != 2 {
+		t.Errorf("expected the full slot to leave no booking behind, got %d bookings", bookings)
+	}
+}
@@ -0,0 +1,169 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/pricing"
+)
+
+// Orders and promotions are stored in the example schema's tables, which migration 913
+// aligns with the API models
+
+func TestOrderRepository_SaveAndFind(t *testing.T) {
+	db := helpers.TxDB(t)
+	repo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+
+	usd := func(amount int64) money.Money { return money.Money{Amount: amount, Currency: "USD"} }
+	order := &orders.Order{
+		ID:          "test-order-001",
+		OrderNumber: "ORD-TEST-001",
+		UserID:      "test-user-001",
+		Status:      orders.OrderStatusPending,
+		Items: []orders.OrderItem{{
+			ID:        "test-item-001",
+			ProductID: "test-product-001",
+			SKU:       "TEST-SKU-001",
+			Name:      "Test Product",
+			UnitPrice: usd(2500),
+			Quantity:  2,
+			Total:     usd(5000),
+		}},
+		ShippingAddress: orders.Address{FirstName: "Ada", AddressLine1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+		Subtotal:        usd(5000),
+		DiscountTotal:   usd(500),
+		TaxTotal:        usd(394),
+		ShippingTotal:   usd(0),
+		Total:           usd(4894),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if err := repo.Save(ctx, order); err != nil {
+		t.Fatalf("failed to save order: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("failed to find order: %v", err)
+	}
+	if found.Total != order.Total || found.DiscountTotal != order.DiscountTotal {
+		t.Errorf("expected totals %v and %v, got %v and %v", order.Total, order.DiscountTotal, found.Total, found.DiscountTotal)
+	}
+	if len(found.Items) != 1 || found.Items[0].Quantity != 2 {
+		t.Errorf("expected the order item stored with the order, got %+v", found.Items)
+	}
+
+	byNumber, err := repo.FindByOrderNumber(ctx, order.OrderNumber)
+	if err != nil || byNumber.ID != order.ID {
+		t.Errorf("expected the order found by number, got %v, %v", byNumber, err)
+	}
+}
+
+func TestOrderRepository_ListsArchivedOrders(t *testing.T) {
+	db := helpers.TxDB(t)
+	repo := repository.NewOrderRepository(db)
+	ctx := context.Background()
+
+	usd := func(amount int64) money.Money { return money.Money{Amount: amount, Currency: "USD"} }
+	placed := time.Now().Add(-90 * 24 * time.Hour).Truncate(time.Second)
+	for i, status := range []orders.OrderStatus{orders.OrderStatusDelivered, orders.OrderStatusPaid} {
+		suffix := []string{"001", "002"}[i]
+		order := &orders.Order{
+			ID:              "test-archived-" + suffix,
+			OrderNumber:     "ORD-ARCHIVED-" + suffix,
+			UserID:          "test-user-archived",
+			Status:          status,
+			Items:           []orders.OrderItem{},
+			ShippingAddress: orders.Address{FirstName: "Ada", AddressLine1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+			Subtotal:        usd(1000),
+			Total:           usd(1000),
+			CreatedAt:       placed.Add(time.Duration(i) * time.Hour),
+			UpdatedAt:       placed,
+		}
+		if err := repo.Save(ctx, order); err != nil {
+			t.Fatalf("failed to save order: %v", err)
+		}
+	}
+	if _, err := repository.NewOrderArchiveRepository(db).ArchiveDelivered(ctx, time.Now().Add(time.Hour), 100); err != nil {
+		t.Fatalf("failed to archive orders: %v", err)
+	}
+
+	list, err := repo.FindByUserID(ctx, "test-user-archived", orders.OrderFilter{})
+	if err != nil {
+		t.Fatalf("failed to list orders: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "test-archived-002" || list[1].ID != "test-archived-001" {
+		t.Fatalf("expected the live and archived order newest first, got %d orders", len(list))
+	}
+	page, err := repo.FindByUserID(ctx, "test-user-archived", orders.OrderFilter{Limit: 1, Offset: 1})
+	if err != nil || len(page) != 1 || page[0].ID != "test-archived-001" {
+		t.Errorf("expected the archived order on the second page, got %v, %v", page, err)
+	}
+	delivered := orders.OrderStatusDelivered
+	if count, err := repo.CountByUserID(ctx, "test-user-archived", orders.OrderFilter{}); err != nil || count != 2 {
+		t.Errorf("expected both orders counted, got %d, %v", count, err)
+	}
+	if count, err := repo.CountByUserID(ctx, "test-user-archived", orders.OrderFilter{Status: &delivered}); err != nil || count != 1 {
+		t.Errorf("expected the archived order counted by status, got %d, %v", count, err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	runner := jobs.NewRunner(1, 10)
+	runner.Start(runCtx)
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+	exports := services.NewOrderExportService(repo, runner, t.TempDir(), []byte("export-secret"))
+	data, export, err := exports.Export(ctx, "test-user-archived", placed.AddDate(0, 0, -1), placed.AddDate(0, 0, 1), "")
+	if err != nil || export != nil {
+		t.Fatalf("expected a CSV within the request, got %+v, %v", export, err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(rows) != 3 || rows[2][0] != "ORD-ARCHIVED-001" {
+		t.Errorf("expected the archived order in the export, got %v", rows)
+	}
+}
+
+func TestPromotionRepository_SaveAndFindByCode(t *testing.T) {
+	db := helpers.TxDB(t)
+	repo := repository.NewPromotionRepository(db)
+	ctx := context.Background()
+
+	promotion := &pricing.Promotion{
+		ID:           "test-promo-001",
+		Code:         "TESTPROMO10",
+		Name:         "Ten percent off",
+		DiscountType: pricing.DiscountTypePercentage,
+		Value:        0.10,
+		ValidFrom:    time.Now().Add(-time.Hour),
+		ValidTo:      time.Now().Add(time.Hour),
+		IsActive:     true,
+	}
+	if err := repo.Save(ctx, promotion); err != nil {
+		t.Fatalf("failed to save promotion: %v", err)
+	}
+
+	found, err := repo.FindByCode(ctx, promotion.Code)
+	if err != nil {
+		t.Fatalf("failed to find promotion: %v", err)
+	}
+	if found.DiscountType != pricing.DiscountTypePercentage || found.Value != 0.10 {
+		t.Errorf("expected a 10%% percentage promotion, got %s %v", found.DiscountType, found.Value)
+	}
+}
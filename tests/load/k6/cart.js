@@ -0,0 +1,32 @@
+// Cart add for signed-in customers. Each virtual user signs up once and keeps adding items.
+//
+//   k6 run tests/load/k6/cart.js
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+import { BASE_URL, addToCart, signUp } from './lib.js';
+
+export const options = {
+  vus: 25,
+  duration: '2m',
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:cart_add}': ['p(95)<250'],
+  },
+};
+
+let headers;
+
+export default function () {
+  if (!headers) {
+    headers = signUp('cart');
+  }
+
+  check(addToCart(headers), { 'item added': (added) => added });
+
+  // Empty the cart now and then so it stays the size of a real one
+  if (Math.random() < 0.1) {
+    http.del(`${BASE_URL}/cart`, null, { headers, tags: { name: 'cart_clear' } });
+  }
+
+  sleep(0.5);
+}
@@ -0,0 +1,31 @@
+// Product listing and detail under a steady ramp of anonymous shoppers.
+//
+//   k6 run tests/load/k6/catalog.js
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+import { BASE_URL, randomProductID } from './lib.js';
+
+export const options = {
+  stages: [
+    { duration: '30s', target: 50 },
+    { duration: '2m', target: 50 },
+    { duration: '30s', target: 0 },
+  ],
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:product_list}': ['p(95)<300'],
+    'http_req_duration{name:product_detail}': ['p(95)<150'],
+  },
+};
+
+export default function () {
+  // Most shoppers stay on the first pages
+  const page = 1 + Math.floor(Math.pow(Math.random(), 3) * 50);
+  const list = http.get(`${BASE_URL}/catalog/products?page=${page}&page_size=20`, { tags: { name: 'product_list' } });
+  check(list, { 'list ok': (r) => r.status === 200 });
+
+  const detail = http.get(`${BASE_URL}/catalog/products/${randomProductID()}`, { tags: { name: 'product_detail' } });
+  check(detail, { 'detail ok or not for sale': (r) => r.status === 200 || r.status === 404 });
+
+  sleep(1);
+}
@@ -0,0 +1,37 @@
+// Order creation: each iteration fills a cart with one to three items and checks out.
+//
+//   k6 run tests/load/k6/checkout.js
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+import { BASE_URL, SHIPPING_ADDRESS, addToCart, signUp } from './lib.js';
+
+export const options = {
+  vus: 10,
+  duration: '2m',
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:order_create}': ['p(95)<500'],
+  },
+};
+
+let headers;
+
+export default function () {
+  if (!headers) {
+    headers = signUp('checkout');
+  }
+
+  const items = 1 + Math.floor(Math.random() * 3);
+  for (let i = 0; i < items; i++) {
+    addToCart(headers);
+  }
+
+  const body = JSON.stringify({ shipping_address: SHIPPING_ADDRESS, apply_store_credit: false });
+  const res = http.post(`${BASE_URL}/orders`, body, { headers, tags: { name: 'order_create' } });
+  check(res, { 'order created': (r) => r.status === 201 });
+
+  // Orders do not empty the cart; start the next one from scratch
+  http.del(`${BASE_URL}/cart`, null, { headers, tags: { name: 'cart_clear' } });
+
+  sleep(1);
+}
@@ -0,0 +1,54 @@
+// Shared settings and helpers for the k6 scenarios.
+//
+// The scenarios expect a catalog seeded with the load-test profile:
+//   go run ./cmd/seed -profile load-test
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+export const BASE_URL = (__ENV.BASE_URL || 'http://localhost:8080') + '/api/v1';
+
+// Product IDs follow the seeder's seed-<profile>-prod-<n> pattern
+const PROFILE = __ENV.SEED_PROFILE || 'load-test';
+const PRODUCT_COUNT = parseInt(__ENV.PRODUCT_COUNT || '10000', 10);
+
+export const JSON_HEADERS = { 'Content-Type': 'application/json' };
+
+// randomProductID picks a seeded product; a few are draft or archived and return 404
+export function randomProductID() {
+  const n = 1 + Math.floor(Math.random() * PRODUCT_COUNT);
+  return `seed-${PROFILE}-prod-${String(n).padStart(6, '0')}`;
+}
+
+// signUp registers a throwaway customer and returns auth headers for it
+export function signUp(prefix) {
+  const email = `${prefix}-${Date.now()}-${Math.floor(Math.random() * 1e9)}@loadtest.example.com`;
+  const body = JSON.stringify({ email, password: 'LoadTest-123!', first_name: 'Load', last_name: 'Test' });
+
+  const res = http.post(`${BASE_URL}/auth/register`, body, { headers: JSON_HEADERS, tags: { name: 'register' } });
+  if (!check(res, { 'registered': (r) => r.status === 201 })) {
+    fail(`register failed: ${res.status} ${res.body}`);
+  }
+  return { ...JSON_HEADERS, Authorization: `Bearer ${res.json('data.access_token')}` };
+}
+
+// addToCart adds a random product, retrying a few picks that are not for sale
+export function addToCart(headers) {
+  for (let attempt = 0; attempt < 5; attempt++) {
+    const body = JSON.stringify({ product_id: randomProductID(), quantity: 1 });
+    const res = http.post(`${BASE_URL}/cart/items`, body, { headers, tags: { name: 'cart_add' } });
+    if (res.status === 200) {
+      return true;
+    }
+  }
+  return false;
+}
+
+export const SHIPPING_ADDRESS = {
+  first_name: 'Load',
+  last_name: 'Test',
+  address1: '1 Main St',
+  city: 'San Francisco',
+  state: 'CA',
+  postal_code: '94105',
+  country: 'US',
+};
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockAPIKeyRepository is a mock implementation of services.APIKeyRepository
+type MockAPIKeyRepository struct {
+	Keys map[string]*services.APIKey
+}
+
+// NewMockAPIKeyRepository creates a new mock API key repository
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{
+		Keys: make(map[string]*services.APIKey),
+	}
+}
+
+// FindByID finds an API key by ID
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id string) (*services.APIKey, error) {
+	if key, ok := m.Keys[id]; ok {
+		copied := *key
+		return &copied, nil
+	}
+	return nil, services.ErrAPIKeyNotFound
+}
+
+// FindByHash finds an API key by the hash of its secret
+func (m *MockAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*services.APIKey, error) {
+	for _, key := range m.Keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, services.ErrAPIKeyNotFound
+}
+
+// List lists all API keys
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*services.APIKey, error) {
+	keys := make([]*services.APIKey, 0, len(m.Keys))
+	for _, key := range m.Keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Save saves an API key
+func (m *MockAPIKeyRepository) Save(ctx context.Context, key *services.APIKey) error {
+	copied := *key
+	m.Keys[key.ID] = &copied
+	return nil
+}
@@ -0,0 +1,130 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockAppointmentRepository is a mock implementation of services.AppointmentRepository
+type MockAppointmentRepository struct {
+	Products map[string]*services.AppointmentProduct
+	Slots    map[string]*services.AppointmentSlot
+	Bookings []*services.AppointmentBooking
+}
+
+// NewMockAppointmentRepository creates a new mock appointment repository
+func NewMockAppointmentRepository() *MockAppointmentRepository {
+	return &MockAppointmentRepository{
+		Products: make(map[string]*services.AppointmentProduct),
+		Slots:    make(map[string]*services.AppointmentSlot),
+	}
+}
+
+// FindProduct returns a product's appointment settings
+func (m *MockAppointmentRepository) FindProduct(ctx context.Context, productID string) (*services.AppointmentProduct, error) {
+	if product, ok := m.Products[productID]; ok {
+		return product, nil
+	}
+	return nil, services.ErrAppointmentProductNotFound
+}
+
+// SaveProduct stores a product's appointment settings
+func (m *MockAppointmentRepository) SaveProduct(ctx context.Context, product *services.AppointmentProduct) error {
+	m.Products[product.ProductID] = product
+	return nil
+}
+
+// DeleteProduct removes a product's appointment settings
+func (m *MockAppointmentRepository) DeleteProduct(ctx context.Context, productID string) error {
+	if _, ok := m.Products[productID]; !ok {
+		return services.ErrAppointmentProductNotFound
+	}
+	delete(m.Products, productID)
+	return nil
+}
+
+// FindSlot returns a copy of a slot
+func (m *MockAppointmentRepository) FindSlot(ctx context.Context, id string) (*services.AppointmentSlot, error) {
+	slot, ok := m.Slots[id]
+	if !ok {
+		return nil, services.ErrAppointmentSlotNotFound
+	}
+	found := *slot
+	return &found, nil
+}
+
+// FindSlots returns copies of a product's slots starting within the range
+func (m *MockAppointmentRepository) FindSlots(ctx context.Context, productID string, from, to time.Time) ([]*services.AppointmentSlot, error) {
+	result := make([]*services.AppointmentSlot, 0)
+	for _, slot := range m.Slots {
+		if slot.ProductID == productID && !slot.StartsAt.Before(from) && slot.StartsAt.Before(to) {
+			found := *slot
+			result = append(result, &found)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartsAt.Before(result[j].StartsAt) })
+	return result, nil
+}
+
+// SaveSlot stores a slot, keeping the seats booked of an existing one
+func (m *MockAppointmentRepository) SaveSlot(ctx context.Context, slot *services.AppointmentSlot) error {
+	saved := *slot
+	if existing, ok := m.Slots[slot.ID]; ok {
+		saved.Booked = existing.Booked
+	}
+	m.Slots[slot.ID] = &saved
+	return nil
+}
+
+// Book takes the booking's seats in its slot and stores it
+func (m *MockAppointmentRepository) Book(ctx context.Context, booking *services.AppointmentBooking) error {
+	slot, ok := m.Slots[booking.SlotID]
+	if !ok || !slot.IsActive || slot.Booked+booking.Quantity > slot.Capacity {
+		return services.ErrAppointmentSlotFull
+	}
+	slot.Booked += booking.Quantity
+	m.Bookings = append(m.Bookings, booking)
+	return nil
+}
+
+// FindOrderBookings returns the bookings of an order
+func (m *MockAppointmentRepository) FindOrderBookings(ctx context.Context, orderID string) ([]*services.AppointmentBooking, error) {
+	result := make([]*services.AppointmentBooking, 0)
+	for _, booking := range m.Bookings {
+		if booking.OrderID == orderID {
+			result = append(result, booking)
+		}
+	}
+	return result, nil
+}
+
+// FindSlotBookings returns the bookings of a slot
+func (m *MockAppointmentRepository) FindSlotBookings(ctx context.Context, slotID string) ([]*services.AppointmentBooking, error) {
+	result := make([]*services.AppointmentBooking, 0)
+	for _, booking := range m.Bookings {
+		if booking.SlotID == slotID {
+			result = append(result, booking)
+		}
+	}
+	return result, nil
+}
+
+// CancelOrderBookings frees the seats of an order's booked appointments and marks them
+// canceled
+func (m *MockAppointmentRepository) CancelOrderBookings(ctx context.Context, orderID string, at time.Time) error {
+	for _, booking := range m.Bookings {
+		if booking.OrderID != orderID || booking.Status != services.AppointmentBooked {
+			continue
+		}
+		if slot, ok := m.Slots[booking.SlotID]; ok {
+			slot.Booked = max(slot.Booked-booking.Quantity, 0)
+		}
+		canceledAt := at
+		booking.Status = services.AppointmentCanceled
+		booking.CanceledAt = &canceledAt
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockBarcodeRepository is a mock implementation of services.BarcodeRepository
+type MockBarcodeRepository struct {
+	Barcodes map[string]string // variant ID -> barcode; a variant must be present to be known
+}
+
+// NewMockBarcodeRepository creates a new mock barcode repository for the given variants
+func NewMockBarcodeRepository(variantIDs ...string) *MockBarcodeRepository {
+	repo := &MockBarcodeRepository{Barcodes: make(map[string]string)}
+	for _, id := range variantIDs {
+		repo.Barcodes[id] = ""
+	}
+	return repo
+}
+
+// FindVariantID finds the ID of the variant with the barcode
+func (m *MockBarcodeRepository) FindVariantID(ctx context.Context, barcode string) (string, error) {
+	for variantID, code := range m.Barcodes {
+		if code == barcode {
+			return variantID, nil
+		}
+	}
+	return "", services.ErrBarcodeNotFound
+}
+
+// FindBarcode finds a variant's barcode
+func (m *MockBarcodeRepository) FindBarcode(ctx context.Context, variantID string) (string, error) {
+	barcode, ok := m.Barcodes[variantID]
+	if !ok {
+		return "", services.ErrVariantNotFound
+	}
+	return barcode, nil
+}
+
+// SetBarcode sets or clears a variant's barcode
+func (m *MockBarcodeRepository) SetBarcode(ctx context.Context, variantID, barcode string) error {
+	if _, ok := m.Barcodes[variantID]; !ok {
+		return services.ErrVariantNotFound
+	}
+	m.Barcodes[variantID] = barcode
+	return nil
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockBulkArchiveRepository is a mock implementation of services.BulkArchiveRepository.
+// Every filter matches the configured IDs.
+type MockBulkArchiveRepository struct {
+	ProductIDs []string
+	VariantIDs []string
+	Filters    []services.BulkArchiveFilter
+
+	FindError error
+}
+
+// NewMockBulkArchiveRepository creates a new mock bulk archive repository
+func NewMockBulkArchiveRepository() *MockBulkArchiveRepository {
+	return &MockBulkArchiveRepository{}
+}
+
+// FindProductIDs returns the configured product IDs
+func (m *MockBulkArchiveRepository) FindProductIDs(ctx context.Context, filter services.BulkArchiveFilter) ([]string, error) {
+	m.Filters = append(m.Filters, filter)
+	if m.FindError != nil {
+		return nil, m.FindError
+	}
+	return m.ProductIDs, nil
+}
+
+// FindVariantIDs returns the configured variant IDs
+func (m *MockBulkArchiveRepository) FindVariantIDs(ctx context.Context, filter services.BulkArchiveFilter) ([]string, error) {
+	m.Filters = append(m.Filters, filter)
+	if m.FindError != nil {
+		return nil, m.FindError
+	}
+	return m.VariantIDs, nil
+}
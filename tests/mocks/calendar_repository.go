@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCalendarRepository is a mock implementation of services.CalendarRepository
+type MockCalendarRepository struct {
+	Calendar *services.BusinessCalendar
+	Holidays map[string]services.Holiday
+}
+
+// NewMockCalendarRepository creates a new mock calendar repository with no saved calendar
+func NewMockCalendarRepository() *MockCalendarRepository {
+	return &MockCalendarRepository{
+		Holidays: make(map[string]services.Holiday),
+	}
+}
+
+// Get returns the calendar settings
+func (m *MockCalendarRepository) Get(ctx context.Context) (*services.BusinessCalendar, error) {
+	if m.Calendar == nil {
+		return nil, services.ErrCalendarNotFound
+	}
+	calendar := *m.Calendar
+	return &calendar, nil
+}
+
+// Save replaces the calendar settings
+func (m *MockCalendarRepository) Save(ctx context.Context, calendar *services.BusinessCalendar) error {
+	saved := *calendar
+	saved.Holidays = nil
+	m.Calendar = &saved
+	return nil
+}
+
+// ListHolidays lists holidays by date
+func (m *MockCalendarRepository) ListHolidays(ctx context.Context) ([]services.Holiday, error) {
+	holidays := make([]services.Holiday, 0, len(m.Holidays))
+	for _, holiday := range m.Holidays {
+		holidays = append(holidays, holiday)
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+	return holidays, nil
+}
+
+// SaveHoliday creates or renames a holiday
+func (m *MockCalendarRepository) SaveHoliday(ctx context.Context, holiday services.Holiday) error {
+	m.Holidays[holiday.Date] = holiday
+	return nil
+}
+
+// DeleteHoliday deletes the holiday on a date
+func (m *MockCalendarRepository) DeleteHoliday(ctx context.Context, date string) error {
+	if _, ok := m.Holidays[date]; !ok {
+		return services.ErrHolidayNotFound
+	}
+	delete(m.Holidays, date)
+	return nil
+}
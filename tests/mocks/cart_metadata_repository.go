@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCartMetadataRepository is a mock implementation of services.CartMetadataRepository.
+// Any cart or item ID is accepted; the service checks items against the cart itself.
+type MockCartMetadataRepository struct {
+	Carts  map[string]services.Metadata            // keyed by cart ID
+	Items  map[string]map[string]services.Metadata // keyed by cart ID, then item ID
+	Orders map[string]*services.CartMetadata       // keyed by order ID
+}
+
+// NewMockCartMetadataRepository creates a new mock cart metadata repository
+func NewMockCartMetadataRepository() *MockCartMetadataRepository {
+	return &MockCartMetadataRepository{
+		Carts:  make(map[string]services.Metadata),
+		Items:  make(map[string]map[string]services.Metadata),
+		Orders: make(map[string]*services.CartMetadata),
+	}
+}
+
+// FindCartMetadata returns a cart's metadata and that of its items
+func (m *MockCartMetadataRepository) FindCartMetadata(ctx context.Context, cartID string) (*services.CartMetadata, error) {
+	result := &services.CartMetadata{Metadata: services.Metadata{}, Items: make(map[string]services.Metadata)}
+	for key, value := range m.Carts[cartID] {
+		result.Metadata[key] = value
+	}
+	for itemID, metadata := range m.Items[cartID] {
+		result.Items[itemID] = metadata
+	}
+	return result, nil
+}
+
+// SetCartMetadata replaces or clears a cart's metadata
+func (m *MockCartMetadataRepository) SetCartMetadata(ctx context.Context, cartID string, metadata services.Metadata) error {
+	if len(metadata) == 0 {
+		delete(m.Carts, cartID)
+		return nil
+	}
+	m.Carts[cartID] = metadata
+	return nil
+}
+
+// SetItemMetadata replaces or clears a cart item's metadata
+func (m *MockCartMetadataRepository) SetItemMetadata(ctx context.Context, cartID, itemID string, metadata services.Metadata) error {
+	if len(metadata) == 0 {
+		delete(m.Items[cartID], itemID)
+		return nil
+	}
+	if m.Items[cartID] == nil {
+		m.Items[cartID] = make(map[string]services.Metadata)
+	}
+	m.Items[cartID][itemID] = metadata
+	return nil
+}
+
+// FindOrderMetadata returns the metadata an order was placed with
+func (m *MockCartMetadataRepository) FindOrderMetadata(ctx context.Context, orderID string) (*services.CartMetadata, error) {
+	if metadata, ok := m.Orders[orderID]; ok {
+		return metadata, nil
+	}
+	return &services.CartMetadata{Metadata: services.Metadata{}, Items: make(map[string]services.Metadata)}, nil
+}
+
+// SaveOrderMetadata stores the metadata an order was placed with
+func (m *MockCartMetadataRepository) SaveOrderMetadata(ctx context.Context, orderID string, metadata *services.CartMetadata) error {
+	m.Orders[orderID] = metadata
+	return nil
+}
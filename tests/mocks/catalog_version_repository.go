@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCatalogVersionRepository is a mock implementation of services.CatalogVersionRepository
+type MockCatalogVersionRepository struct {
+	Versions []*services.CatalogVersion // in the order appended
+
+	// Error injection
+	AppendError error
+}
+
+// NewMockCatalogVersionRepository creates a new mock catalog version repository
+func NewMockCatalogVersionRepository() *MockCatalogVersionRepository {
+	return &MockCatalogVersionRepository{}
+}
+
+// Append numbers the version after the product's latest and stores it
+func (m *MockCatalogVersionRepository) Append(ctx context.Context, version *services.CatalogVersion) error {
+	if m.AppendError != nil {
+		return m.AppendError
+	}
+	latest := 0
+	for _, v := range m.Versions {
+		if v.ProductID == version.ProductID && v.Version > latest {
+			latest = v.Version
+		}
+	}
+	version.Version = latest + 1
+	m.Versions = append(m.Versions, version)
+	return nil
+}
+
+// ListByProduct lists a product's versions, newest first
+func (m *MockCatalogVersionRepository) ListByProduct(ctx context.Context, productID string, limit, offset int) ([]*services.CatalogVersion, error) {
+	result := make([]*services.CatalogVersion, 0)
+	for i := len(m.Versions) - 1; i >= 0; i-- {
+		if m.Versions[i].ProductID == productID {
+			result = append(result, m.Versions[i])
+		}
+	}
+	if offset >= len(result) {
+		return []*services.CatalogVersion{}, nil
+	}
+	result = result[offset:]
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// CountByProduct counts a product's versions
+func (m *MockCatalogVersionRepository) CountByProduct(ctx context.Context, productID string) (int64, error) {
+	var count int64
+	for _, v := range m.Versions {
+		if v.ProductID == productID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindByVersion finds one version of a product
+func (m *MockCatalogVersionRepository) FindByVersion(ctx context.Context, productID string, version int) (*services.CatalogVersion, error) {
+	for _, v := range m.Versions {
+		if v.ProductID == productID && v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, services.ErrCatalogVersionNotFound
+}
+
+// ListAfter lists a product's versions newer than version, oldest first
+func (m *MockCatalogVersionRepository) ListAfter(ctx context.Context, productID string, version int) ([]*services.CatalogVersion, error) {
+	result := make([]*services.CatalogVersion, 0)
+	for _, v := range m.Versions {
+		if v.ProductID == productID && v.Version > version {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
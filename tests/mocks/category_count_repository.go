@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+)
+
+// MockCategoryCountRepository is a mock implementation of services.CategoryCountRepository
+// that recounts from a MockProductRepository
+type MockCategoryCountRepository struct {
+	Counts   map[string]int64
+	Products *MockProductRepository
+
+	IncrementError error
+}
+
+// NewMockCategoryCountRepository creates a new mock category count repository
+func NewMockCategoryCountRepository(products *MockProductRepository) *MockCategoryCountRepository {
+	return &MockCategoryCountRepository{
+		Counts:   make(map[string]int64),
+		Products: products,
+	}
+}
+
+// All returns every category's counter
+func (m *MockCategoryCountRepository) All(ctx context.Context) (map[string]int64, error) {
+	counts := make(map[string]int64, len(m.Counts))
+	for categoryID, count := range m.Counts {
+		counts[categoryID] = count
+	}
+	return counts, nil
+}
+
+// Increment adds delta to a category's counter
+func (m *MockCategoryCountRepository) Increment(ctx context.Context, categoryID string, delta int64) error {
+	if m.IncrementError != nil {
+		return m.IncrementError
+	}
+	m.Counts[categoryID] += delta
+	return nil
+}
+
+// Recount sets every counter from the active products
+func (m *MockCategoryCountRepository) Recount(ctx context.Context) (int64, error) {
+	actual := make(map[string]int64)
+	for _, product := range m.Products.Products {
+		if product.Status == catalog.ProductStatusActive && product.CategoryID != "" {
+			actual[product.CategoryID]++
+		}
+	}
+
+	var fixed int64
+	for categoryID, count := range actual {
+		if m.Counts[categoryID] != count {
+			m.Counts[categoryID] = count
+			fixed++
+		}
+	}
+	for categoryID, count := range m.Counts {
+		if _, ok := actual[categoryID]; !ok && count != 0 {
+			m.Counts[categoryID] = 0
+			fixed++
+		}
+	}
+	return fixed, nil
+}
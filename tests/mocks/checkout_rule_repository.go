@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCheckoutRuleRepository is a mock implementation of services.CheckoutRuleRepository
+type MockCheckoutRuleRepository struct {
+	Rules map[string]*services.CheckoutRule
+}
+
+// NewMockCheckoutRuleRepository creates a new mock checkout rule repository
+func NewMockCheckoutRuleRepository() *MockCheckoutRuleRepository {
+	return &MockCheckoutRuleRepository{
+		Rules: make(map[string]*services.CheckoutRule),
+	}
+}
+
+// FindByID returns a checkout rule by ID
+func (m *MockCheckoutRuleRepository) FindByID(ctx context.Context, id string) (*services.CheckoutRule, error) {
+	if rule, ok := m.Rules[id]; ok {
+		return rule, nil
+	}
+	return nil, services.ErrCheckoutRuleNotFound
+}
+
+// FindAll returns all checkout rules, oldest first
+func (m *MockCheckoutRuleRepository) FindAll(ctx context.Context) ([]*services.CheckoutRule, error) {
+	result := make([]*services.CheckoutRule, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindActive returns the active checkout rules, oldest first
+func (m *MockCheckoutRuleRepository) FindActive(ctx context.Context) ([]*services.CheckoutRule, error) {
+	all, _ := m.FindAll(ctx)
+	result := make([]*services.CheckoutRule, 0, len(all))
+	for _, rule := range all {
+		if rule.IsActive {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+// Save stores a checkout rule
+func (m *MockCheckoutRuleRepository) Save(ctx context.Context, rule *services.CheckoutRule) error {
+	m.Rules[rule.ID] = rule
+	return nil
+}
+
+// Delete removes a checkout rule
+func (m *MockCheckoutRuleRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Rules[id]; !ok {
+		return services.ErrCheckoutRuleNotFound
+	}
+	delete(m.Rules, id)
+	return nil
+}
@@ -0,0 +1,139 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCollectionRepository is a mock implementation of services.CollectionRepository.
+// Rule collections are evaluated in memory against Products and Tags.
+type MockCollectionRepository struct {
+	Collections map[string]*services.Collection
+	Products    map[string]*catalog.Product
+	Tags        map[string][]string // product ID -> tags
+}
+
+// NewMockCollectionRepository creates a new mock collection repository
+func NewMockCollectionRepository() *MockCollectionRepository {
+	return &MockCollectionRepository{
+		Collections: make(map[string]*services.Collection),
+		Products:    make(map[string]*catalog.Product),
+		Tags:        make(map[string][]string),
+	}
+}
+
+// FindByID returns a collection by ID
+func (m *MockCollectionRepository) FindByID(ctx context.Context, id string) (*services.Collection, error) {
+	if collection, ok := m.Collections[id]; ok {
+		return collection, nil
+	}
+	return nil, services.ErrCollectionNotFound
+}
+
+// FindBySlug returns a collection by slug
+func (m *MockCollectionRepository) FindBySlug(ctx context.Context, slug string) (*services.Collection, error) {
+	for _, collection := range m.Collections {
+		if collection.Slug == slug {
+			return collection, nil
+		}
+	}
+	return nil, services.ErrCollectionNotFound
+}
+
+// List returns collections matching the filter ordered by slug
+func (m *MockCollectionRepository) List(ctx context.Context, filter services.CollectionFilter) ([]*services.Collection, error) {
+	result := make([]*services.Collection, 0)
+	for _, collection := range m.Collections {
+		if filter.Status == "" || collection.Status == filter.Status {
+			result = append(result, collection)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Slug < result[j].Slug })
+	return result, nil
+}
+
+// Count counts collections matching the filter
+func (m *MockCollectionRepository) Count(ctx context.Context, filter services.CollectionFilter) (int64, error) {
+	collections, _ := m.List(ctx, filter)
+	return int64(len(collections)), nil
+}
+
+// Save stores a collection
+func (m *MockCollectionRepository) Save(ctx context.Context, collection *services.Collection) error {
+	m.Collections[collection.ID] = collection
+	return nil
+}
+
+// Delete removes a collection
+func (m *MockCollectionRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Collections[id]; !ok {
+		return services.ErrCollectionNotFound
+	}
+	delete(m.Collections, id)
+	return nil
+}
+
+// FindProducts returns the active products in a collection
+func (m *MockCollectionRepository) FindProducts(ctx context.Context, collection *services.Collection, conditions []services.CollectionCondition, limit, offset int) ([]*catalog.Product, error) {
+	products := m.matching(collection, conditions)
+	if offset >= len(products) {
+		return []*catalog.Product{}, nil
+	}
+	products = products[offset:]
+	if limit > 0 && limit < len(products) {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+// CountProducts counts the active products in a collection
+func (m *MockCollectionRepository) CountProducts(ctx context.Context, collection *services.Collection, conditions []services.CollectionCondition) (int64, error) {
+	return int64(len(m.matching(collection, conditions))), nil
+}
+
+// FindTags returns a product's tags, sorted
+func (m *MockCollectionRepository) FindTags(ctx context.Context, productID string) ([]string, error) {
+	tags := append([]string{}, m.Tags[productID]...)
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// SetTags replaces a product's tags
+func (m *MockCollectionRepository) SetTags(ctx context.Context, productID string, tags []string) error {
+	m.Tags[productID] = tags
+	return nil
+}
+
+func (m *MockCollectionRepository) matching(collection *services.Collection, conditions []services.CollectionCondition) []*catalog.Product {
+	result := make([]*catalog.Product, 0)
+	if collection.Type == services.CollectionManual {
+		for _, id := range collection.ProductIDs {
+			if product, ok := m.Products[id]; ok && product.Status == catalog.ProductStatusActive {
+				result = append(result, product)
+			}
+		}
+		return result
+	}
+
+	for _, product := range m.Products {
+		if product.Status != catalog.ProductStatusActive {
+			continue
+		}
+		matches := true
+		for _, condition := range conditions {
+			if !condition.Matches(product, m.Tags[product.ID]) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, product)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
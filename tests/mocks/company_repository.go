@@ -0,0 +1,163 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCompanyRepository is a mock implementation of services.CompanyRepository
+type MockCompanyRepository struct {
+	Companies map[string]*services.Company
+	Members   map[string]*services.CompanyMember  // keyed by user ID
+	Addresses map[string]*services.CompanyAddress // keyed by address ID
+	Orders    map[string]*services.CompanyOrder   // keyed by order ID
+}
+
+// NewMockCompanyRepository creates a new mock company repository
+func NewMockCompanyRepository() *MockCompanyRepository {
+	return &MockCompanyRepository{
+		Companies: make(map[string]*services.Company),
+		Members:   make(map[string]*services.CompanyMember),
+		Addresses: make(map[string]*services.CompanyAddress),
+		Orders:    make(map[string]*services.CompanyOrder),
+	}
+}
+
+// FindByID returns a copy of a company
+func (m *MockCompanyRepository) FindByID(ctx context.Context, id string) (*services.Company, error) {
+	company, ok := m.Companies[id]
+	if !ok {
+		return nil, services.ErrCompanyNotFound
+	}
+	stored := *company
+	return &stored, nil
+}
+
+// List returns companies ordered by name, ignoring pagination
+func (m *MockCompanyRepository) List(ctx context.Context, limit, offset int) ([]*services.Company, error) {
+	companies := make([]*services.Company, 0, len(m.Companies))
+	for _, company := range m.Companies {
+		stored := *company
+		companies = append(companies, &stored)
+	}
+	sort.Slice(companies, func(i, j int) bool { return companies[i].Name < companies[j].Name })
+	return companies, nil
+}
+
+// Save stores a copy of a company
+func (m *MockCompanyRepository) Save(ctx context.Context, company *services.Company) error {
+	stored := *company
+	m.Companies[company.ID] = &stored
+	return nil
+}
+
+// FindMember returns a copy of a user's membership, or nil when they have none
+func (m *MockCompanyRepository) FindMember(ctx context.Context, userID string) (*services.CompanyMember, error) {
+	member, ok := m.Members[userID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *member
+	return &stored, nil
+}
+
+// ListMembers returns a company's members ordered by user ID
+func (m *MockCompanyRepository) ListMembers(ctx context.Context, companyID string) ([]*services.CompanyMember, error) {
+	var members []*services.CompanyMember
+	for _, member := range m.Members {
+		if member.CompanyID == companyID {
+			stored := *member
+			members = append(members, &stored)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members, nil
+}
+
+// SaveMember stores a copy of a membership
+func (m *MockCompanyRepository) SaveMember(ctx context.Context, member *services.CompanyMember) error {
+	stored := *member
+	m.Members[member.UserID] = &stored
+	return nil
+}
+
+// DeleteMember removes a user's membership
+func (m *MockCompanyRepository) DeleteMember(ctx context.Context, userID string) error {
+	delete(m.Members, userID)
+	return nil
+}
+
+// FindAddress returns a copy of an address book entry
+func (m *MockCompanyRepository) FindAddress(ctx context.Context, id string) (*services.CompanyAddress, error) {
+	address, ok := m.Addresses[id]
+	if !ok {
+		return nil, services.ErrCompanyAddressNotFound
+	}
+	stored := *address
+	return &stored, nil
+}
+
+// ListAddresses returns a company's address book ordered by label
+func (m *MockCompanyRepository) ListAddresses(ctx context.Context, companyID string) ([]*services.CompanyAddress, error) {
+	var addresses []*services.CompanyAddress
+	for _, address := range m.Addresses {
+		if address.CompanyID == companyID {
+			stored := *address
+			addresses = append(addresses, &stored)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Label < addresses[j].Label })
+	return addresses, nil
+}
+
+// SaveAddress stores a copy of an address book entry
+func (m *MockCompanyRepository) SaveAddress(ctx context.Context, address *services.CompanyAddress) error {
+	stored := *address
+	m.Addresses[address.ID] = &stored
+	return nil
+}
+
+// DeleteAddress removes an address book entry
+func (m *MockCompanyRepository) DeleteAddress(ctx context.Context, id string) error {
+	if _, ok := m.Addresses[id]; !ok {
+		return services.ErrCompanyAddressNotFound
+	}
+	delete(m.Addresses, id)
+	return nil
+}
+
+// FindOrder returns a copy of an order's company record, or nil when it has none
+func (m *MockCompanyRepository) FindOrder(ctx context.Context, orderID string) (*services.CompanyOrder, error) {
+	record, ok := m.Orders[orderID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *record
+	return &stored, nil
+}
+
+// ListOrders returns a company's orders matching the approval status, newest first
+func (m *MockCompanyRepository) ListOrders(ctx context.Context, companyID string, filter services.CompanyOrderFilter) ([]*services.CompanyOrder, error) {
+	var records []*services.CompanyOrder
+	for _, record := range m.Orders {
+		if record.CompanyID != companyID {
+			continue
+		}
+		if filter.ApprovalStatus != "" && record.ApprovalStatus != filter.ApprovalStatus {
+			continue
+		}
+		stored := *record
+		records = append(records, &stored)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	return records, nil
+}
+
+// SaveOrder stores a copy of an order's company record
+func (m *MockCompanyRepository) SaveOrder(ctx context.Context, record *services.CompanyOrder) error {
+	stored := *record
+	m.Orders[record.OrderID] = &stored
+	return nil
+}
@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockConfigChangeRepository is a mock implementation of services.ConfigChangeRepository
+type MockConfigChangeRepository struct {
+	mu      sync.Mutex
+	Changes []*services.ConfigChange
+
+	// Error injection
+	CreateError error
+}
+
+// NewMockConfigChangeRepository creates a new mock configuration change repository
+func NewMockConfigChangeRepository() *MockConfigChangeRepository {
+	return &MockConfigChangeRepository{}
+}
+
+// CreateBatch stores the changes of one reload
+func (m *MockConfigChangeRepository) CreateBatch(ctx context.Context, changes []*services.ConfigChange) error {
+	if m.CreateError != nil {
+		return m.CreateError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range changes {
+		copied := *change
+		m.Changes = append(m.Changes, &copied)
+	}
+	return nil
+}
+
+// List lists changes matching the filter, newest first
+func (m *MockConfigChangeRepository) List(ctx context.Context, filter services.ConfigChangeFilter) ([]*services.ConfigChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*services.ConfigChange, 0)
+	for _, change := range m.Changes {
+		if filter.Setting != "" && change.Setting != filter.Setting {
+			continue
+		}
+		copied := *change
+		result = append(result, &copied)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ChangedAt.After(result[j].ChangedAt)
+	})
+	return result, nil
+}
+
+// Count counts changes matching the filter
+func (m *MockConfigChangeRepository) Count(ctx context.Context, filter services.ConfigChangeFilter) (int64, error) {
+	list, _ := m.List(ctx, filter)
+	return int64(len(list)), nil
+}
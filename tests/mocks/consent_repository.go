@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockConsentRepository is a mock implementation of services.ConsentRepository
+type MockConsentRepository struct {
+	Events map[string][]*services.ConsentEvent // keyed by user ID, oldest first
+}
+
+// NewMockConsentRepository creates a new mock consent repository
+func NewMockConsentRepository() *MockConsentRepository {
+	return &MockConsentRepository{
+		Events: make(map[string][]*services.ConsentEvent),
+	}
+}
+
+// Append stores consent events
+func (m *MockConsentRepository) Append(ctx context.Context, events []*services.ConsentEvent) error {
+	for _, event := range events {
+		m.Events[event.UserID] = append(m.Events[event.UserID], event)
+	}
+	return nil
+}
+
+// FindLatest returns a user's most recent event for each purpose
+func (m *MockConsentRepository) FindLatest(ctx context.Context, userID string) ([]*services.ConsentEvent, error) {
+	events, _ := m.ListByUser(ctx, userID)
+	seen := make(map[services.ConsentPurpose]bool)
+	var latest []*services.ConsentEvent
+	for _, event := range events {
+		if !seen[event.Purpose] {
+			seen[event.Purpose] = true
+			latest = append(latest, event)
+		}
+	}
+	return latest, nil
+}
+
+// ListByUser lists a user's events, newest first
+func (m *MockConsentRepository) ListByUser(ctx context.Context, userID string) ([]*services.ConsentEvent, error) {
+	events := m.Events[userID]
+	newest := make([]*services.ConsentEvent, len(events))
+	for i, event := range events {
+		newest[len(events)-1-i] = event
+	}
+	return newest, nil
+}
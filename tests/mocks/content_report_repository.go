@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockContentReportRepository is a mock implementation of services.ContentReportRepository
+type MockContentReportRepository struct {
+	Reports map[string]*services.ContentReport
+}
+
+// NewMockContentReportRepository creates a new mock content report repository
+func NewMockContentReportRepository() *MockContentReportRepository {
+	return &MockContentReportRepository{
+		Reports: make(map[string]*services.ContentReport),
+	}
+}
+
+// Add records a report, once per user and content
+func (m *MockContentReportRepository) Add(ctx context.Context, report *services.ContentReport) error {
+	for _, existing := range m.Reports {
+		if existing.ContentType == report.ContentType && existing.ContentID == report.ContentID && existing.UserID == report.UserID {
+			return services.ErrAlreadyReported
+		}
+	}
+	recorded := *report
+	m.Reports[report.ID] = &recorded
+	return nil
+}
+
+// CountOpen counts the open reports on a piece of content
+func (m *MockContentReportRepository) CountOpen(ctx context.Context, contentType services.ReportContentType, contentID string) (int, error) {
+	count := 0
+	for _, report := range m.Reports {
+		if m.isOpen(report, contentType, contentID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindOpen returns copies of the open reports, oldest first
+func (m *MockContentReportRepository) FindOpen(ctx context.Context) ([]*services.ContentReport, error) {
+	result := make([]*services.ContentReport, 0)
+	for _, report := range m.Reports {
+		if report.Status == services.ReportStatusOpen {
+			found := *report
+			result = append(result, &found)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// Resolve closes the open reports on a piece of content
+func (m *MockContentReportRepository) Resolve(ctx context.Context, contentType services.ReportContentType, contentID string, status services.ReportStatus, resolvedBy string, resolvedAt time.Time) (int, error) {
+	resolved := 0
+	for _, report := range m.Reports {
+		if m.isOpen(report, contentType, contentID) {
+			at := resolvedAt
+			report.Status = status
+			report.ResolvedBy = resolvedBy
+			report.ResolvedAt = &at
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+func (m *MockContentReportRepository) isOpen(report *services.ContentReport, contentType services.ReportContentType, contentID string) bool {
+	return report.ContentType == contentType && report.ContentID == contentID && report.Status == services.ReportStatusOpen
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockCustomerRepository is a mock implementation of services.CustomerRepository
+type MockCustomerRepository struct {
+	Profiles map[string]*services.CustomerProfile // keyed by user ID
+}
+
+// NewMockCustomerRepository creates a new mock customer repository
+func NewMockCustomerRepository() *MockCustomerRepository {
+	return &MockCustomerRepository{
+		Profiles: make(map[string]*services.CustomerProfile),
+	}
+}
+
+// FindByUserID returns a copy of a customer's profile, or nil when they have none
+func (m *MockCustomerRepository) FindByUserID(ctx context.Context, userID string) (*services.CustomerProfile, error) {
+	profile, ok := m.Profiles[userID]
+	if !ok {
+		return nil, nil
+	}
+	stored := *profile
+	return &stored, nil
+}
+
+// Save stores a copy of a customer's profile
+func (m *MockCustomerRepository) Save(ctx context.Context, profile *services.CustomerProfile) error {
+	stored := *profile
+	m.Profiles[profile.UserID] = &stored
+	return nil
+}
+
+// MockMediaStorage is an in-memory implementation of services.MediaStorage
+type MockMediaStorage struct {
+	Files map[string][]byte // keyed by media key
+}
+
+// NewMockMediaStorage creates a new mock media storage
+func NewMockMediaStorage() *MockMediaStorage {
+	return &MockMediaStorage{
+		Files: make(map[string][]byte),
+	}
+}
+
+// Put stores a file and returns its URL under /media
+func (m *MockMediaStorage) Put(ctx context.Context, key string, data io.Reader) (string, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, data); err != nil {
+		return "", err
+	}
+	m.Files[key] = buf.Bytes()
+	return services.LocalMediaPath + "/" + key, nil
+}
+
+// Delete removes a file
+func (m *MockMediaStorage) Delete(ctx context.Context, key string) error {
+	delete(m.Files, key)
+	return nil
+}
@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockDeadLetterRepository is a mock implementation of services.DeadLetterRepository
+type MockDeadLetterRepository struct {
+	mu      sync.Mutex
+	Letters map[string]*services.DeadLetter
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockDeadLetterRepository creates a new mock dead letter repository
+func NewMockDeadLetterRepository() *MockDeadLetterRepository {
+	return &MockDeadLetterRepository{
+		Letters: make(map[string]*services.DeadLetter),
+	}
+}
+
+// FindByID finds a dead letter by ID
+func (m *MockDeadLetterRepository) FindByID(ctx context.Context, id string) (*services.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	letter, ok := m.Letters[id]
+	if !ok {
+		return nil, services.ErrDeadLetterNotFound
+	}
+	copied := *letter
+	return &copied, nil
+}
+
+// List lists dead letters matching the filter, newest failure first
+func (m *MockDeadLetterRepository) List(ctx context.Context, filter services.DeadLetterFilter) ([]*services.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*services.DeadLetter, 0)
+	for _, letter := range m.Letters {
+		if filter.Kind != "" && letter.Kind != filter.Kind {
+			continue
+		}
+		if filter.Status != "" && letter.Status != filter.Status {
+			continue
+		}
+		copied := *letter
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FailedAt.After(result[j].FailedAt)
+	})
+	return result, nil
+}
+
+// Count counts dead letters matching the filter
+func (m *MockDeadLetterRepository) Count(ctx context.Context, filter services.DeadLetterFilter) (int64, error) {
+	list, _ := m.List(ctx, filter)
+	return int64(len(list)), nil
+}
+
+// Save saves a dead letter
+func (m *MockDeadLetterRepository) Save(ctx context.Context, letter *services.DeadLetter) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *letter
+	m.Letters[letter.ID] = &copied
+	return nil
+}
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockDeliverySlotRepository is a mock implementation of services.DeliverySlotRepository
+type MockDeliverySlotRepository struct {
+	Slots    map[string]*services.DeliverySlot
+	Bookings map[string]string // orderID -> slotID
+
+	// Error injection
+	FindByIDError          error
+	FindActiveBetweenError error
+	SaveError              error
+	ReserveError           error
+}
+
+// NewMockDeliverySlotRepository creates a new mock delivery slot repository
+func NewMockDeliverySlotRepository() *MockDeliverySlotRepository {
+	return &MockDeliverySlotRepository{
+		Slots:    make(map[string]*services.DeliverySlot),
+		Bookings: make(map[string]string),
+	}
+}
+
+// FindByID returns a slot by ID
+func (m *MockDeliverySlotRepository) FindByID(ctx context.Context, id string) (*services.DeliverySlot, error) {
+	if m.FindByIDError != nil {
+		return nil, m.FindByIDError
+	}
+	if slot, ok := m.Slots[id]; ok {
+		return slot, nil
+	}
+	return nil, services.ErrDeliverySlotNotFound
+}
+
+// FindActiveBetween returns active slots starting within the range
+func (m *MockDeliverySlotRepository) FindActiveBetween(ctx context.Context, from, to time.Time) ([]*services.DeliverySlot, error) {
+	if m.FindActiveBetweenError != nil {
+		return nil, m.FindActiveBetweenError
+	}
+	result := make([]*services.DeliverySlot, 0)
+	for _, slot := range m.Slots {
+		if slot.IsActive && !slot.StartsAt.Before(from) && slot.StartsAt.Before(to) {
+			result = append(result, slot)
+		}
+	}
+	return result, nil
+}
+
+// FindByOrderID returns the slot booked for an order
+func (m *MockDeliverySlotRepository) FindByOrderID(ctx context.Context, orderID string) (*services.DeliverySlot, error) {
+	slotID, ok := m.Bookings[orderID]
+	if !ok {
+		return nil, services.ErrDeliverySlotNotFound
+	}
+	return m.FindByID(ctx, slotID)
+}
+
+// Save saves a slot
+func (m *MockDeliverySlotRepository) Save(ctx context.Context, slot *services.DeliverySlot) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Slots[slot.ID] = slot
+	return nil
+}
+
+// Reserve books one unit of slot capacity
+func (m *MockDeliverySlotRepository) Reserve(ctx context.Context, slotID, orderID string) error {
+	if m.ReserveError != nil {
+		return m.ReserveError
+	}
+	if _, booked := m.Bookings[orderID]; booked {
+		return nil
+	}
+	slot, ok := m.Slots[slotID]
+	if !ok || slot.Reserved >= slot.Capacity {
+		return services.ErrDeliverySlotFull
+	}
+	slot.Reserved++
+	m.Bookings[orderID] = slotID
+	return nil
+}
+
+// Release frees the capacity held by an order
+func (m *MockDeliverySlotRepository) Release(ctx context.Context, orderID string) error {
+	if slotID, ok := m.Bookings[orderID]; ok {
+		m.Slots[slotID].Reserved--
+		delete(m.Bookings, orderID)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockDisputeRepository is a mock implementation of services.DisputeRepository
+type MockDisputeRepository struct {
+	Disputes map[string]*services.Dispute
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockDisputeRepository creates a new mock dispute repository
+func NewMockDisputeRepository() *MockDisputeRepository {
+	return &MockDisputeRepository{
+		Disputes: make(map[string]*services.Dispute),
+	}
+}
+
+// FindByID returns a dispute by ID
+func (m *MockDisputeRepository) FindByID(ctx context.Context, id string) (*services.Dispute, error) {
+	if dispute, ok := m.Disputes[id]; ok {
+		return dispute, nil
+	}
+	return nil, services.ErrDisputeNotFound
+}
+
+// FindByGatewayID returns a dispute by gateway dispute ID
+func (m *MockDisputeRepository) FindByGatewayID(ctx context.Context, gatewayDisputeID string) (*services.Dispute, error) {
+	for _, dispute := range m.Disputes {
+		if dispute.GatewayDisputeID == gatewayDisputeID {
+			return dispute, nil
+		}
+	}
+	return nil, services.ErrDisputeNotFound
+}
+
+// FindByOrderID returns the disputes for an order
+func (m *MockDisputeRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.Dispute, error) {
+	result := make([]*services.Dispute, 0)
+	for _, dispute := range m.Disputes {
+		if dispute.OrderID == orderID {
+			result = append(result, dispute)
+		}
+	}
+	return result, nil
+}
+
+// List returns disputes matching the filter
+func (m *MockDisputeRepository) List(ctx context.Context, filter services.DisputeFilter) ([]*services.Dispute, error) {
+	result := make([]*services.Dispute, 0)
+	for _, dispute := range m.Disputes {
+		if filter.Status != "" && dispute.Status != filter.Status {
+			continue
+		}
+		if filter.EvidenceStatus != "" && dispute.EvidenceStatus != filter.EvidenceStatus {
+			continue
+		}
+		result = append(result, dispute)
+	}
+	return result, nil
+}
+
+// Count counts disputes matching the filter
+func (m *MockDisputeRepository) Count(ctx context.Context, filter services.DisputeFilter) (int64, error) {
+	disputes, _ := m.List(ctx, filter)
+	return int64(len(disputes)), nil
+}
+
+// Save stores a dispute
+func (m *MockDisputeRepository) Save(ctx context.Context, dispute *services.Dispute) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Disputes[dispute.ID] = dispute
+	return nil
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockDonationRepository is a mock implementation of services.DonationRepository
+type MockDonationRepository struct {
+	Products map[string]*services.DonationProduct
+}
+
+// NewMockDonationRepository creates a new mock donation repository
+func NewMockDonationRepository() *MockDonationRepository {
+	return &MockDonationRepository{
+		Products: make(map[string]*services.DonationProduct),
+	}
+}
+
+// FindProduct returns a product's donation settings
+func (m *MockDonationRepository) FindProduct(ctx context.Context, productID string) (*services.DonationProduct, error) {
+	if donation, ok := m.Products[productID]; ok {
+		return donation, nil
+	}
+	return nil, services.ErrDonationNotFound
+}
+
+// SaveProduct stores a product's donation settings
+func (m *MockDonationRepository) SaveProduct(ctx context.Context, donation *services.DonationProduct) error {
+	m.Products[donation.ProductID] = donation
+	return nil
+}
+
+// DeleteProduct removes a product's donation settings
+func (m *MockDonationRepository) DeleteProduct(ctx context.Context, productID string) error {
+	if _, ok := m.Products[productID]; !ok {
+		return services.ErrDonationNotFound
+	}
+	delete(m.Products, productID)
+	return nil
+}
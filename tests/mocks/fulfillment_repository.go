@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockFulfillmentRepository is a mock implementation of services.FulfillmentRepository
+// that reads open orders from a MockOrderRepository
+type MockFulfillmentRepository struct {
+	OrderRepo *MockOrderRepository
+	Shipments []*services.Shipment
+
+	// Error injection
+	SaveShipmentError error
+}
+
+// NewMockFulfillmentRepository creates a new mock fulfillment repository
+func NewMockFulfillmentRepository(orderRepo *MockOrderRepository) *MockFulfillmentRepository {
+	return &MockFulfillmentRepository{OrderRepo: orderRepo}
+}
+
+// FindOpenOrders returns paid and processing orders after the cursor in (updated_at, id) order
+func (m *MockFulfillmentRepository) FindOpenOrders(ctx context.Context, after *services.FulfillmentCursor, limit int) ([]*orders.Order, error) {
+	open := make([]*orders.Order, 0)
+	for _, order := range m.OrderRepo.Orders {
+		if order.Status != orders.OrderStatusPaid && order.Status != orders.OrderStatusProcessing {
+			continue
+		}
+		if after != nil && (order.UpdatedAt.Before(after.UpdatedAt) ||
+			(order.UpdatedAt.Equal(after.UpdatedAt) && order.ID <= after.OrderID)) {
+			continue
+		}
+		open = append(open, order)
+	}
+
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].UpdatedAt.Equal(open[j].UpdatedAt) {
+			return open[i].ID < open[j].ID
+		}
+		return open[i].UpdatedAt.Before(open[j].UpdatedAt)
+	})
+	if limit > 0 && len(open) > limit {
+		open = open[:limit]
+	}
+	return open, nil
+}
+
+// FindShipments returns the shipments for an order in the order they were saved
+func (m *MockFulfillmentRepository) FindShipments(ctx context.Context, orderID string) ([]*services.Shipment, error) {
+	result := make([]*services.Shipment, 0)
+	for _, shipment := range m.Shipments {
+		if shipment.OrderID == orderID {
+			result = append(result, shipment)
+		}
+	}
+	return result, nil
+}
+
+// SaveShipment stores a shipment
+func (m *MockFulfillmentRepository) SaveShipment(ctx context.Context, shipment *services.Shipment) error {
+	if m.SaveShipmentError != nil {
+		return m.SaveShipmentError
+	}
+	m.Shipments = append(m.Shipments, shipment)
+	return nil
+}
@@ -0,0 +1,104 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockFunnelRepository is a mock implementation of services.FunnelRepository
+type MockFunnelRepository struct {
+	Events map[services.FunnelStage]map[string]time.Time
+	Alerts []*services.FunnelAlert
+}
+
+// NewMockFunnelRepository creates a new mock funnel repository
+func NewMockFunnelRepository() *MockFunnelRepository {
+	return &MockFunnelRepository{
+		Events: make(map[services.FunnelStage]map[string]time.Time),
+	}
+}
+
+// Record stores that subject reached stage, reporting false when it already had
+func (m *MockFunnelRepository) Record(ctx context.Context, stage services.FunnelStage, subject string, at time.Time) (bool, error) {
+	if m.Events[stage] == nil {
+		m.Events[stage] = make(map[string]time.Time)
+	}
+	if _, ok := m.Events[stage][subject]; ok {
+		return false, nil
+	}
+	m.Events[stage][subject] = at
+	return true, nil
+}
+
+// Exists reports whether subject reached stage
+func (m *MockFunnelRepository) Exists(ctx context.Context, stage services.FunnelStage, subject string) (bool, error) {
+	_, ok := m.Events[stage][subject]
+	return ok, nil
+}
+
+// CountByStage counts the subjects reaching each stage between from and to
+func (m *MockFunnelRepository) CountByStage(ctx context.Context, from, to time.Time) (map[services.FunnelStage]int64, error) {
+	counts := make(map[services.FunnelStage]int64)
+	for _, stage := range services.FunnelStages {
+		counts[stage] = 0
+	}
+	for stage, subjects := range m.Events {
+		for _, at := range subjects {
+			if !at.Before(from) && at.Before(to) {
+				counts[stage]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// DeleteBefore deletes events that occurred before before
+func (m *MockFunnelRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for _, subjects := range m.Events {
+		for subject, at := range subjects {
+			if at.Before(before) {
+				delete(subjects, subject)
+				deleted++
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// SaveAlert stores a funnel alert
+func (m *MockFunnelRepository) SaveAlert(ctx context.Context, alert *services.FunnelAlert) error {
+	m.Alerts = append(m.Alerts, alert)
+	return nil
+}
+
+// ListAlerts lists alerts raised at or after since, newest first; a limit of 0 lists all
+func (m *MockFunnelRepository) ListAlerts(ctx context.Context, since time.Time, limit, offset int) ([]*services.FunnelAlert, error) {
+	var alerts []*services.FunnelAlert
+	for _, alert := range m.Alerts {
+		if !alert.CreatedAt.Before(since) {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].CreatedAt.After(alerts[j].CreatedAt) })
+	if limit <= 0 {
+		return alerts, nil
+	}
+	if offset >= len(alerts) {
+		return []*services.FunnelAlert{}, nil
+	}
+	alerts = alerts[offset:]
+	if limit < len(alerts) {
+		alerts = alerts[:limit]
+	}
+	return alerts, nil
+}
+
+// CountAlerts counts alerts raised at or after since
+func (m *MockFunnelRepository) CountAlerts(ctx context.Context, since time.Time) (int64, error) {
+	alerts, _ := m.ListAlerts(ctx, since, 0, 0)
+	return int64(len(alerts)), nil
+}
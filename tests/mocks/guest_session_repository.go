@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+)
+
+// MockGuestSessionRepository is a mock implementation of services.GuestSessionRepository
+// that moves orders in a MockOrderRepository
+type MockGuestSessionRepository struct {
+	OrderRepo *MockOrderRepository
+}
+
+// NewMockGuestSessionRepository creates a new mock guest session repository
+func NewMockGuestSessionRepository(orderRepo *MockOrderRepository) *MockGuestSessionRepository {
+	return &MockGuestSessionRepository{
+		OrderRepo: orderRepo,
+	}
+}
+
+// ReassignOrders moves every order of one user ID to another
+func (m *MockGuestSessionRepository) ReassignOrders(ctx context.Context, fromUserID, toUserID string) (int64, error) {
+	var moved int64
+	for _, order := range m.OrderRepo.Orders {
+		if order.UserID == fromUserID {
+			order.UserID = toUserID
+			moved++
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,118 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockInventoryHistoryRepository is a mock implementation of services.InventoryHistoryRepository
+type MockInventoryHistoryRepository struct {
+	Movements []*services.StockMovement
+	Snapshots []*services.InventorySnapshot
+	Levels    map[string]services.InventorySnapshot // current stock per SKU, copied by TakeSnapshots
+}
+
+// NewMockInventoryHistoryRepository creates a new mock inventory history repository
+func NewMockInventoryHistoryRepository() *MockInventoryHistoryRepository {
+	return &MockInventoryHistoryRepository{
+		Levels: make(map[string]services.InventorySnapshot),
+	}
+}
+
+// AppendMovement records a stock movement
+func (m *MockInventoryHistoryRepository) AppendMovement(ctx context.Context, movement *services.StockMovement) error {
+	m.Movements = append(m.Movements, movement)
+	return nil
+}
+
+// OutstandingReservations sums a reference's reservations less its releases and commits
+func (m *MockInventoryHistoryRepository) OutstandingReservations(ctx context.Context, referenceID string) (map[string]int, error) {
+	totals := make(map[string]int)
+	for _, movement := range m.Movements {
+		if movement.ReferenceID != referenceID {
+			continue
+		}
+		switch movement.Type {
+		case services.StockMovementReservation:
+			totals[movement.SKU] += movement.Quantity
+		case services.StockMovementRelease, services.StockMovementCommit:
+			totals[movement.SKU] -= movement.Quantity
+		}
+	}
+	for sku, quantity := range totals {
+		if quantity <= 0 {
+			delete(totals, sku)
+		}
+	}
+	return totals, nil
+}
+
+// ListMovements lists a SKU's movements within [from, to], oldest first
+func (m *MockInventoryHistoryRepository) ListMovements(ctx context.Context, sku string, from, to time.Time) ([]*services.StockMovement, error) {
+	result := make([]*services.StockMovement, 0)
+	for _, movement := range m.Movements {
+		if movement.SKU == sku && !movement.CreatedAt.Before(from) && !movement.CreatedAt.After(to) {
+			result = append(result, movement)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// ListSnapshots lists a SKU's snapshots dated within [fromDate, toDate], oldest first
+func (m *MockInventoryHistoryRepository) ListSnapshots(ctx context.Context, sku string, fromDate, toDate string) ([]*services.InventorySnapshot, error) {
+	result := make([]*services.InventorySnapshot, 0)
+	for _, snapshot := range m.Snapshots {
+		if snapshot.SKU == sku && snapshot.Date >= fromDate && snapshot.Date <= toDate {
+			copied := *snapshot
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result, nil
+}
+
+// LatestSnapshot returns the SKU's most recent snapshot taken at or before at
+func (m *MockInventoryHistoryRepository) LatestSnapshot(ctx context.Context, sku string, at time.Time) (*services.InventorySnapshot, error) {
+	var latest *services.InventorySnapshot
+	for _, snapshot := range m.Snapshots {
+		if snapshot.SKU == sku && !snapshot.TakenAt.After(at) && (latest == nil || snapshot.TakenAt.After(latest.TakenAt)) {
+			latest = snapshot
+		}
+	}
+	return latest, nil
+}
+
+// LatestMovement returns the SKU's most recent movement made at or before at
+func (m *MockInventoryHistoryRepository) LatestMovement(ctx context.Context, sku string, at time.Time) (*services.StockMovement, error) {
+	var latest *services.StockMovement
+	for _, movement := range m.Movements {
+		if movement.SKU == sku && !movement.CreatedAt.After(at) && (latest == nil || movement.CreatedAt.After(latest.CreatedAt)) {
+			latest = movement
+		}
+	}
+	return latest, nil
+}
+
+// TakeSnapshots copies every level into the day's snapshot, replacing one already taken
+func (m *MockInventoryHistoryRepository) TakeSnapshots(ctx context.Context, date string, takenAt time.Time) (int64, error) {
+	kept := m.Snapshots[:0]
+	for _, snapshot := range m.Snapshots {
+		if _, ok := m.Levels[snapshot.SKU]; !ok || snapshot.Date != date {
+			kept = append(kept, snapshot)
+		}
+	}
+	m.Snapshots = kept
+
+	for sku, level := range m.Levels {
+		snapshot := level
+		snapshot.SKU = sku
+		snapshot.Date = date
+		snapshot.TakenAt = takenAt
+		m.Snapshots = append(m.Snapshots, &snapshot)
+	}
+	return int64(len(m.Levels)), nil
+}
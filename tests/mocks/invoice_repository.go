@@ -0,0 +1,111 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockInvoiceRepository is a mock implementation of services.InvoiceRepository
+type MockInvoiceRepository struct {
+	Invoices map[string]*services.Invoice // keyed by invoice ID
+	Payments map[string][]services.InvoicePayment
+}
+
+// NewMockInvoiceRepository creates a new mock invoice repository
+func NewMockInvoiceRepository() *MockInvoiceRepository {
+	return &MockInvoiceRepository{
+		Invoices: make(map[string]*services.Invoice),
+		Payments: make(map[string][]services.InvoicePayment),
+	}
+}
+
+// FindByID returns a copy of an invoice
+func (m *MockInvoiceRepository) FindByID(ctx context.Context, id string) (*services.Invoice, error) {
+	invoice, ok := m.Invoices[id]
+	if !ok {
+		return nil, services.ErrInvoiceNotFound
+	}
+	stored := *invoice
+	return &stored, nil
+}
+
+// FindByOrderID returns a copy of an order's invoice, or nil when it has none
+func (m *MockInvoiceRepository) FindByOrderID(ctx context.Context, orderID string) (*services.Invoice, error) {
+	for _, invoice := range m.Invoices {
+		if invoice.OrderID == orderID {
+			stored := *invoice
+			return &stored, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns invoices matching the company and status, newest first, ignoring pagination
+func (m *MockInvoiceRepository) List(ctx context.Context, filter services.InvoiceFilter) ([]*services.Invoice, error) {
+	var invoices []*services.Invoice
+	for _, invoice := range m.Invoices {
+		if filter.CompanyID != "" && invoice.CompanyID != filter.CompanyID {
+			continue
+		}
+		if filter.Status != "" && invoice.Status != filter.Status {
+			continue
+		}
+		stored := *invoice
+		invoices = append(invoices, &stored)
+	}
+	sort.Slice(invoices, func(i, j int) bool { return invoices[i].CreatedAt.After(invoices[j].CreatedAt) })
+	return invoices, nil
+}
+
+// ListOverdue returns open invoices due before asOf, oldest due date first
+func (m *MockInvoiceRepository) ListOverdue(ctx context.Context, asOf time.Time) ([]*services.Invoice, error) {
+	var invoices []*services.Invoice
+	for _, invoice := range m.Invoices {
+		if invoice.Status == services.InvoiceStatusOpen && invoice.DueAt.Before(asOf) {
+			stored := *invoice
+			invoices = append(invoices, &stored)
+		}
+	}
+	sort.Slice(invoices, func(i, j int) bool { return invoices[i].DueAt.Before(invoices[j].DueAt) })
+	return invoices, nil
+}
+
+// ListPayments returns the payments recorded against an invoice
+func (m *MockInvoiceRepository) ListPayments(ctx context.Context, invoiceID string) ([]services.InvoicePayment, error) {
+	return append([]services.InvoicePayment(nil), m.Payments[invoiceID]...), nil
+}
+
+// Save stores a copy of an invoice
+func (m *MockInvoiceRepository) Save(ctx context.Context, invoice *services.Invoice) error {
+	stored := *invoice
+	stored.Payments = nil
+	m.Invoices[invoice.ID] = &stored
+	return nil
+}
+
+// AddPayment records a payment against an open invoice that it fits the balance of
+func (m *MockInvoiceRepository) AddPayment(ctx context.Context, payment *services.InvoicePayment) error {
+	invoice, ok := m.Invoices[payment.InvoiceID]
+	if !ok {
+		return services.ErrInvoiceNotFound
+	}
+	if invoice.Status != services.InvoiceStatusOpen {
+		return services.ErrInvoiceNotOpen
+	}
+	if payment.Amount.Amount > invoice.Balance().Amount {
+		return services.ErrInvoiceOverpaid
+	}
+
+	invoice.AmountPaid.Amount += payment.Amount.Amount
+	invoice.UpdatedAt = payment.CreatedAt
+	if invoice.Balance().Amount == 0 {
+		invoice.Status = services.InvoiceStatusPaid
+		paidAt := payment.CreatedAt
+		invoice.PaidAt = &paidAt
+	}
+	m.Payments[payment.InvoiceID] = append(m.Payments[payment.InvoiceID], *payment)
+	return nil
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockLoginSecurityRepository is a mock implementation of services.LoginSecurityRepository
+type MockLoginSecurityRepository struct {
+	Lockouts map[string]*services.LoginLockout
+	Devices  map[string]*services.LoginDevice // keyed by user ID and fingerprint
+}
+
+// NewMockLoginSecurityRepository creates a new mock login security repository
+func NewMockLoginSecurityRepository() *MockLoginSecurityRepository {
+	return &MockLoginSecurityRepository{
+		Lockouts: make(map[string]*services.LoginLockout),
+		Devices:  make(map[string]*services.LoginDevice),
+	}
+}
+
+// FindLockout returns the failed login state of an email address
+func (m *MockLoginSecurityRepository) FindLockout(ctx context.Context, email string) (*services.LoginLockout, error) {
+	if lockout, ok := m.Lockouts[email]; ok {
+		return lockout, nil
+	}
+	return nil, services.ErrLoginLockoutNotFound
+}
+
+// SaveLockout stores the failed login state of an email address
+func (m *MockLoginSecurityRepository) SaveLockout(ctx context.Context, lockout *services.LoginLockout) error {
+	m.Lockouts[lockout.Email] = lockout
+	return nil
+}
+
+// DeleteLockout removes the failed login state of an email address
+func (m *MockLoginSecurityRepository) DeleteLockout(ctx context.Context, email string) error {
+	if _, ok := m.Lockouts[email]; !ok {
+		return services.ErrLoginLockoutNotFound
+	}
+	delete(m.Lockouts, email)
+	return nil
+}
+
+// FindDevice returns a device a user has signed in from
+func (m *MockLoginSecurityRepository) FindDevice(ctx context.Context, userID, fingerprint string) (*services.LoginDevice, error) {
+	if device, ok := m.Devices[userID+"/"+fingerprint]; ok {
+		return device, nil
+	}
+	return nil, services.ErrLoginDeviceNotFound
+}
+
+// CountDevices counts the devices a user has signed in from
+func (m *MockLoginSecurityRepository) CountDevices(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	for _, device := range m.Devices {
+		if device.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SaveDevice stores a device
+func (m *MockLoginSecurityRepository) SaveDevice(ctx context.Context, device *services.LoginDevice) error {
+	m.Devices[device.UserID+"/"+device.Fingerprint] = device
+	return nil
+}
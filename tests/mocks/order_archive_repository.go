@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+)
+
+// MockOrderArchiveRepository is a mock implementation of services.OrderArchiveRepository.
+// It moves orders from Orders to Archived and records the size of each batch.
+type MockOrderArchiveRepository struct {
+	Orders   map[string]*orders.Order
+	Archived map[string]*orders.Order
+	Batches  []int64
+}
+
+// NewMockOrderArchiveRepository creates a new mock order archive repository
+func NewMockOrderArchiveRepository() *MockOrderArchiveRepository {
+	return &MockOrderArchiveRepository{
+		Orders:   make(map[string]*orders.Order),
+		Archived: make(map[string]*orders.Order),
+	}
+}
+
+// ArchiveDelivered moves up to limit archivable orders, least recently changed first
+func (m *MockOrderArchiveRepository) ArchiveDelivered(ctx context.Context, before time.Time, limit int) (int64, error) {
+	due := m.archivable(before)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, order := range due {
+		delete(m.Orders, order.ID)
+		m.Archived[order.ID] = order
+	}
+	m.Batches = append(m.Batches, int64(len(due)))
+	return int64(len(due)), nil
+}
+
+// CountArchivable counts the archivable orders
+func (m *MockOrderArchiveRepository) CountArchivable(ctx context.Context, before time.Time) (int64, error) {
+	return int64(len(m.archivable(before))), nil
+}
+
+// CountArchived counts the archived orders
+func (m *MockOrderArchiveRepository) CountArchived(ctx context.Context) (int64, error) {
+	return int64(len(m.Archived)), nil
+}
+
+func (m *MockOrderArchiveRepository) archivable(before time.Time) []*orders.Order {
+	due := make([]*orders.Order, 0)
+	for _, order := range m.Orders {
+		if order.Status == orders.OrderStatusDelivered && order.UpdatedAt.Before(before) {
+			due = append(due, order)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].UpdatedAt.Before(due[j].UpdatedAt) })
+	return due
+}
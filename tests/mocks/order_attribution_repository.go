@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockOrderAttributionRepository is a mock implementation of services.OrderAttributionRepository
+// that reads order statuses and totals from a MockOrderRepository
+type MockOrderAttributionRepository struct {
+	OrderRepo    *MockOrderRepository
+	Attributions map[string]*services.OrderAttribution // keyed by order ID
+}
+
+// NewMockOrderAttributionRepository creates a new mock order attribution repository
+func NewMockOrderAttributionRepository(orderRepo *MockOrderRepository) *MockOrderAttributionRepository {
+	return &MockOrderAttributionRepository{
+		OrderRepo:    orderRepo,
+		Attributions: make(map[string]*services.OrderAttribution),
+	}
+}
+
+// Save stores an order's attribution
+func (m *MockOrderAttributionRepository) Save(ctx context.Context, attribution *services.OrderAttribution) error {
+	m.Attributions[attribution.OrderID] = attribution
+	return nil
+}
+
+// FindByOrderID returns an order's attribution, or nil when it has none
+func (m *MockOrderAttributionRepository) FindByOrderID(ctx context.Context, orderID string) (*services.OrderAttribution, error) {
+	return m.Attributions[orderID], nil
+}
+
+// ListAttributedOrders returns the orders attributed within [from, to)
+func (m *MockOrderAttributionRepository) ListAttributedOrders(ctx context.Context, from, to time.Time) ([]*services.AttributedOrder, error) {
+	result := make([]*services.AttributedOrder, 0)
+	for _, attribution := range m.Attributions {
+		order, ok := m.OrderRepo.Orders[attribution.OrderID]
+		if !ok || attribution.CreatedAt.Before(from) || !attribution.CreatedAt.Before(to) {
+			continue
+		}
+		result = append(result, &services.AttributedOrder{
+			Channel: attribution.Channel,
+			UTM:     attribution.UTM,
+			Status:  order.Status,
+			Total:   order.Total,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockOrderBumpRepository is a mock implementation of services.OrderBumpRepository
+type MockOrderBumpRepository struct {
+	Bumps map[string]*services.OrderBump
+}
+
+// NewMockOrderBumpRepository creates a new mock order bump repository
+func NewMockOrderBumpRepository() *MockOrderBumpRepository {
+	return &MockOrderBumpRepository{
+		Bumps: make(map[string]*services.OrderBump),
+	}
+}
+
+// FindByID returns an order bump by ID
+func (m *MockOrderBumpRepository) FindByID(ctx context.Context, id string) (*services.OrderBump, error) {
+	if bump, ok := m.Bumps[id]; ok {
+		return bump, nil
+	}
+	return nil, services.ErrOrderBumpNotFound
+}
+
+// FindAll returns all order bumps by position, then oldest first
+func (m *MockOrderBumpRepository) FindAll(ctx context.Context) ([]*services.OrderBump, error) {
+	result := make([]*services.OrderBump, 0, len(m.Bumps))
+	for _, bump := range m.Bumps {
+		result = append(result, bump)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Position != result[j].Position {
+			return result[i].Position < result[j].Position
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// FindActive returns the active order bumps by position, then oldest first
+func (m *MockOrderBumpRepository) FindActive(ctx context.Context) ([]*services.OrderBump, error) {
+	all, _ := m.FindAll(ctx)
+	result := make([]*services.OrderBump, 0, len(all))
+	for _, bump := range all {
+		if bump.IsActive {
+			result = append(result, bump)
+		}
+	}
+	return result, nil
+}
+
+// Save stores an order bump
+func (m *MockOrderBumpRepository) Save(ctx context.Context, bump *services.OrderBump) error {
+	m.Bumps[bump.ID] = bump
+	return nil
+}
+
+// Delete removes an order bump
+func (m *MockOrderBumpRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Bumps[id]; !ok {
+		return services.ErrOrderBumpNotFound
+	}
+	delete(m.Bumps, id)
+	return nil
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockOrderEventRepository is a mock implementation of services.OrderEventRepository
+type MockOrderEventRepository struct {
+	Events []*services.OrderEvent // in the order appended
+
+	// Error injection
+	AppendError error
+}
+
+// NewMockOrderEventRepository creates a new mock order event repository
+func NewMockOrderEventRepository() *MockOrderEventRepository {
+	return &MockOrderEventRepository{}
+}
+
+// Append stores an order event
+func (m *MockOrderEventRepository) Append(ctx context.Context, event *services.OrderEvent) error {
+	if m.AppendError != nil {
+		return m.AppendError
+	}
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+// ListByOrder lists an order's events, oldest first
+func (m *MockOrderEventRepository) ListByOrder(ctx context.Context, orderID string) ([]*services.OrderEvent, error) {
+	result := make([]*services.OrderEvent, 0)
+	for _, event := range m.Events {
+		if event.OrderID == orderID {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
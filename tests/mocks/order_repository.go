@@ -2,11 +2,12 @@ package mocks
 
 import (
 	"context"
+	"sort"
 
 	"github.com/devchuckcamp/gocommerce/orders"
 )
 
-// MockOrderRepository is a mock implementation of orders.Repository
+// MockOrderRepository is a mock implementation of services.OrderRepository
 type MockOrderRepository struct {
 	Orders map[string]*orders.Order
 
@@ -14,6 +15,7 @@ type MockOrderRepository struct {
 	FindByIDError          error
 	FindByOrderNumberError error
 	FindByUserIDError      error
+	CountByUserIDError     error
 	SaveError              error
 	DeleteError            error
 }
@@ -49,20 +51,59 @@ func (m *MockOrderRepository) FindByOrderNumber(ctx context.Context, orderNumber
 	return nil, orders.ErrOrderNotFound
 }
 
-// FindByUserID returns orders by user ID
+// FindByUserID returns orders by user ID, newest first, applying the filter like the
+// GORM repository
 func (m *MockOrderRepository) FindByUserID(ctx context.Context, userID string, filter orders.OrderFilter) ([]*orders.Order, error) {
 	if m.FindByUserIDError != nil {
 		return nil, m.FindByUserIDError
 	}
 	result := make([]*orders.Order, 0)
 	for _, o := range m.Orders {
-		if o.UserID == userID {
-			result = append(result, o)
+		if o.UserID != userID {
+			continue
 		}
+		if filter.Status != nil && o.Status != *filter.Status {
+			continue
+		}
+		if filter.DateFrom != nil && o.CreatedAt.Before(*filter.DateFrom) {
+			continue
+		}
+		if filter.DateTo != nil && o.CreatedAt.After(*filter.DateTo) {
+			continue
+		}
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if filter.Offset > 0 {
+		if filter.Offset >= len(result) {
+			return []*orders.Order{}, nil
+		}
+		result = result[filter.Offset:]
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
 	}
 	return result, nil
 }
 
+// CountByUserID counts a user's orders matching the filter, ignoring its limit and offset
+func (m *MockOrderRepository) CountByUserID(ctx context.Context, userID string, filter orders.OrderFilter) (int64, error) {
+	if m.CountByUserIDError != nil {
+		return 0, m.CountByUserIDError
+	}
+	filter.Limit, filter.Offset = 0, 0
+	list, err := m.FindByUserID(ctx, userID, filter)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(list)), nil
+}
+
 // Save saves an order
 func (m *MockOrderRepository) Save(ctx context.Context, o *orders.Order) error {
 	if m.SaveError != nil {
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockOrderStatusEventRepository is a mock implementation of services.OrderStatusEventRepository
+type MockOrderStatusEventRepository struct {
+	Events []*services.OrderStatusEvent // in the order added
+
+	// Error injection
+	AddError error
+}
+
+// NewMockOrderStatusEventRepository creates a new mock order status event repository
+func NewMockOrderStatusEventRepository() *MockOrderStatusEventRepository {
+	return &MockOrderStatusEventRepository{}
+}
+
+// Add stores a status change
+func (m *MockOrderStatusEventRepository) Add(ctx context.Context, event *services.OrderStatusEvent) error {
+	if m.AddError != nil {
+		return m.AddError
+	}
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+// ListByOrder lists an order's status changes, oldest first
+func (m *MockOrderStatusEventRepository) ListByOrder(ctx context.Context, orderID string) ([]*services.OrderStatusEvent, error) {
+	result := make([]*services.OrderStatusEvent, 0)
+	for _, event := range m.Events {
+		if event.OrderID == orderID {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPageRepository is a mock implementation of services.PageRepository
+type MockPageRepository struct {
+	Pages map[string]*services.Page
+}
+
+// NewMockPageRepository creates a new mock page repository
+func NewMockPageRepository() *MockPageRepository {
+	return &MockPageRepository{
+		Pages: make(map[string]*services.Page),
+	}
+}
+
+// FindByID returns a page by ID
+func (m *MockPageRepository) FindByID(ctx context.Context, id string) (*services.Page, error) {
+	if page, ok := m.Pages[id]; ok {
+		return page, nil
+	}
+	return nil, services.ErrPageNotFound
+}
+
+// FindBySlug returns a page by slug
+func (m *MockPageRepository) FindBySlug(ctx context.Context, slug string) (*services.Page, error) {
+	for _, page := range m.Pages {
+		if page.Slug == slug {
+			return page, nil
+		}
+	}
+	return nil, services.ErrPageNotFound
+}
+
+// List returns pages matching the filter ordered by slug
+func (m *MockPageRepository) List(ctx context.Context, filter services.PageFilter) ([]*services.Page, error) {
+	result := make([]*services.Page, 0)
+	for _, page := range m.Pages {
+		if filter.Status == "" || page.Status == filter.Status {
+			result = append(result, page)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Slug < result[j].Slug })
+	return result, nil
+}
+
+// Count counts pages matching the filter
+func (m *MockPageRepository) Count(ctx context.Context, filter services.PageFilter) (int64, error) {
+	pages, _ := m.List(ctx, filter)
+	return int64(len(pages)), nil
+}
+
+// Save stores a page
+func (m *MockPageRepository) Save(ctx context.Context, page *services.Page) error {
+	m.Pages[page.ID] = page
+	return nil
+}
+
+// Delete removes a page
+func (m *MockPageRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Pages[id]; !ok {
+		return services.ErrPageNotFound
+	}
+	delete(m.Pages, id)
+	return nil
+}
@@ -0,0 +1,190 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPaymentRepository is a mock implementation of services.PaymentRepository
+type MockPaymentRepository struct {
+	Tenders map[string]*services.PaymentTender
+	saved   []string // tender IDs in the order they were first saved
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockPaymentRepository creates a new mock payment repository
+func NewMockPaymentRepository() *MockPaymentRepository {
+	return &MockPaymentRepository{
+		Tenders: make(map[string]*services.PaymentTender),
+	}
+}
+
+// FindByID returns a tender by ID
+func (m *MockPaymentRepository) FindByID(ctx context.Context, id string) (*services.PaymentTender, error) {
+	if tender, ok := m.Tenders[id]; ok {
+		return tender, nil
+	}
+	return nil, services.ErrTenderNotFound
+}
+
+// FindByOrderID returns all tenders for an order, oldest first
+func (m *MockPaymentRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.PaymentTender, error) {
+	result := make([]*services.PaymentTender, 0)
+	for _, id := range m.saved {
+		if tender := m.Tenders[id]; tender.OrderID == orderID {
+			result = append(result, tender)
+		}
+	}
+	return result, nil
+}
+
+// FindByGatewayReference returns the tender charged by a gateway intent
+func (m *MockPaymentRepository) FindByGatewayReference(ctx context.Context, reference string) (*services.PaymentTender, error) {
+	for _, tender := range m.Tenders {
+		if tender.GatewayReference == reference {
+			return tender, nil
+		}
+	}
+	return nil, services.ErrTenderNotFound
+}
+
+// Save stores a tender
+func (m *MockPaymentRepository) Save(ctx context.Context, tender *services.PaymentTender) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	if _, ok := m.Tenders[tender.ID]; !ok {
+		m.saved = append(m.saved, tender.ID)
+	}
+	m.Tenders[tender.ID] = tender
+	return nil
+}
+
+// MockPaymentGateway is a mock implementation of payments.Gateway
+type MockPaymentGateway struct {
+	Intents map[string]*payments.PaymentIntent
+	Refunds []payments.RefundRequest
+
+	// DeclinedMethods lists payment method IDs whose intents fail
+	DeclinedMethods map[string]bool
+}
+
+// NewMockPaymentGateway creates a new mock payment gateway
+func NewMockPaymentGateway() *MockPaymentGateway {
+	return &MockPaymentGateway{
+		Intents:         make(map[string]*payments.PaymentIntent),
+		DeclinedMethods: make(map[string]bool),
+	}
+}
+
+// CreateIntent captures the full amount unless the payment method is declined
+func (m *MockPaymentGateway) CreateIntent(ctx context.Context, req payments.IntentRequest) (*payments.PaymentIntent, error) {
+	intent := &payments.PaymentIntent{
+		ID:              fmt.Sprintf("pi_%d", len(m.Intents)+1),
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		Status:          payments.IntentStatusSucceeded,
+		PaymentMethodID: req.PaymentMethodID,
+		OrderID:         req.OrderID,
+		CapturedAmount:  req.Amount,
+		CreatedAt:       time.Now(),
+	}
+	if m.DeclinedMethods[req.PaymentMethodID] {
+		intent.Status = payments.IntentStatusFailed
+		intent.CapturedAmount.Amount = 0
+	}
+	m.Intents[intent.ID] = intent
+	return intent, nil
+}
+
+// GetIntent returns an intent by ID
+func (m *MockPaymentGateway) GetIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	if intent, ok := m.Intents[intentID]; ok {
+		return intent, nil
+	}
+	return nil, fmt.Errorf("intent %s not found", intentID)
+}
+
+// CaptureIntent marks an intent as captured
+func (m *MockPaymentGateway) CaptureIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	intent, err := m.GetIntent(ctx, intentID)
+	if err != nil {
+		return nil, err
+	}
+	intent.Status = payments.IntentStatusSucceeded
+	intent.CapturedAmount = intent.Amount
+	return intent, nil
+}
+
+// CancelIntent marks an intent as canceled
+func (m *MockPaymentGateway) CancelIntent(ctx context.Context, intentID string) (*payments.PaymentIntent, error) {
+	intent, err := m.GetIntent(ctx, intentID)
+	if err != nil {
+		return nil, err
+	}
+	intent.Status = payments.IntentStatusCanceled
+	return intent, nil
+}
+
+// CreateRefund records the refund request
+func (m *MockPaymentGateway) CreateRefund(ctx context.Context, req payments.RefundRequest) (*payments.Refund, error) {
+	m.Refunds = append(m.Refunds, req)
+	return &payments.Refund{
+		ID:              fmt.Sprintf("re_%d", len(m.Refunds)),
+		PaymentIntentID: req.PaymentIntentID,
+		Amount:          req.Amount,
+		Currency:        req.Amount.Currency,
+		Status:          payments.RefundStatusSucceeded,
+		Reason:          req.Reason,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// GetRefund is not tracked by the mock
+func (m *MockPaymentGateway) GetRefund(ctx context.Context, refundID string) (*payments.Refund, error) {
+	return nil, fmt.Errorf("refund %s not found", refundID)
+}
+
+// MockPaymentRetryRepository is a mock implementation of services.PaymentRetryRepository
+type MockPaymentRetryRepository struct {
+	Retries map[string]*services.PaymentRetry
+}
+
+// NewMockPaymentRetryRepository creates a new mock payment retry repository
+func NewMockPaymentRetryRepository() *MockPaymentRetryRepository {
+	return &MockPaymentRetryRepository{
+		Retries: make(map[string]*services.PaymentRetry),
+	}
+}
+
+// FindByOrderID returns the retry state for an order
+func (m *MockPaymentRetryRepository) FindByOrderID(ctx context.Context, orderID string) (*services.PaymentRetry, error) {
+	if retry, ok := m.Retries[orderID]; ok {
+		return retry, nil
+	}
+	return nil, services.ErrPaymentRetryNotFound
+}
+
+// FindExpired returns pending retries that expired before the given time
+func (m *MockPaymentRetryRepository) FindExpired(ctx context.Context, before time.Time) ([]*services.PaymentRetry, error) {
+	result := make([]*services.PaymentRetry, 0)
+	for _, retry := range m.Retries {
+		if retry.State == services.PaymentRetryStatePending && retry.ExpiresAt.Before(before) {
+			result = append(result, retry)
+		}
+	}
+	return result, nil
+}
+
+// Save stores the retry state
+func (m *MockPaymentRetryRepository) Save(ctx context.Context, retry *services.PaymentRetry) error {
+	m.Retries[retry.OrderID] = retry
+	return nil
+}
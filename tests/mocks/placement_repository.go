@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPlacementRepository is a mock implementation of services.PlacementRepository
+type MockPlacementRepository struct {
+	Placements map[string]*services.Placement
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockPlacementRepository creates a new mock placement repository
+func NewMockPlacementRepository() *MockPlacementRepository {
+	return &MockPlacementRepository{
+		Placements: make(map[string]*services.Placement),
+	}
+}
+
+// FindByID returns a placement by ID
+func (m *MockPlacementRepository) FindByID(ctx context.Context, id string) (*services.Placement, error) {
+	if placement, ok := m.Placements[id]; ok {
+		return placement, nil
+	}
+	return nil, services.ErrPlacementNotFound
+}
+
+// FindBySlot returns the placements in a slot ordered by position
+func (m *MockPlacementRepository) FindBySlot(ctx context.Context, slot string) ([]*services.Placement, error) {
+	return m.List(ctx, services.PlacementFilter{Slot: slot})
+}
+
+// List returns placements matching the filter ordered by position
+func (m *MockPlacementRepository) List(ctx context.Context, filter services.PlacementFilter) ([]*services.Placement, error) {
+	result := make([]*services.Placement, 0)
+	for _, placement := range m.Placements {
+		if filter.Slot == "" || placement.Slot == filter.Slot {
+			result = append(result, placement)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Position < result[j].Position })
+	return result, nil
+}
+
+// Count counts placements matching the filter
+func (m *MockPlacementRepository) Count(ctx context.Context, filter services.PlacementFilter) (int64, error) {
+	placements, _ := m.List(ctx, filter)
+	return int64(len(placements)), nil
+}
+
+// Save stores a placement
+func (m *MockPlacementRepository) Save(ctx context.Context, placement *services.Placement) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Placements[placement.ID] = placement
+	return nil
+}
+
+// Delete removes a placement
+func (m *MockPlacementRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Placements[id]; !ok {
+		return services.ErrPlacementNotFound
+	}
+	delete(m.Placements, id)
+	return nil
+}
@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPOSRepository is a mock implementation of services.POSRepository that reads
+// order totals and tenders from a MockOrderRepository and MockPaymentRepository
+type MockPOSRepository struct {
+	OrderRepo   *MockOrderRepository
+	PaymentRepo *MockPaymentRepository
+	Sales       []*services.POSSale
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockPOSRepository creates a new mock POS repository
+func NewMockPOSRepository(orderRepo *MockOrderRepository, paymentRepo *MockPaymentRepository) *MockPOSRepository {
+	return &MockPOSRepository{OrderRepo: orderRepo, PaymentRepo: paymentRepo}
+}
+
+// Save stores a sale
+func (m *MockPOSRepository) Save(ctx context.Context, sale *services.POSSale) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Sales = append(m.Sales, sale)
+	return nil
+}
+
+// ListSales lists a register's sales made within [from, to) in the order they were saved
+func (m *MockPOSRepository) ListSales(ctx context.Context, registerID string, from, to time.Time) ([]*services.POSSale, error) {
+	result := make([]*services.POSSale, 0)
+	for _, sale := range m.Sales {
+		if sale.RegisterID == registerID && !sale.CreatedAt.Before(from) && sale.CreatedAt.Before(to) {
+			result = append(result, sale)
+		}
+	}
+	return result, nil
+}
+
+// ListSaleTotals returns the totals of the sales' orders
+func (m *MockPOSRepository) ListSaleTotals(ctx context.Context, orderIDs []string) ([]*services.POSSaleTotals, error) {
+	result := make([]*services.POSSaleTotals, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		if order, ok := m.OrderRepo.Orders[id]; ok {
+			result = append(result, &services.POSSaleTotals{
+				OrderID:       order.ID,
+				Status:        order.Status,
+				Total:         order.Total,
+				DiscountTotal: order.DiscountTotal,
+				TaxTotal:      order.TaxTotal,
+			})
+		}
+	}
+	return result, nil
+}
+
+// ListSaleTenders returns the payment tenders of the sales' orders
+func (m *MockPOSRepository) ListSaleTenders(ctx context.Context, orderIDs []string) ([]*services.PaymentTender, error) {
+	result := make([]*services.PaymentTender, 0)
+	for _, id := range orderIDs {
+		tenders, err := m.PaymentRepo.FindByOrderID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tenders...)
+	}
+	return result, nil
+}
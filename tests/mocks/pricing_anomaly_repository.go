@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPricingAnomalyRepository is a mock implementation of services.PricingAnomalyRepository
+type MockPricingAnomalyRepository struct {
+	Anomalies map[string]*services.PricingAnomaly
+}
+
+// NewMockPricingAnomalyRepository creates a new mock pricing anomaly repository
+func NewMockPricingAnomalyRepository() *MockPricingAnomalyRepository {
+	return &MockPricingAnomalyRepository{
+		Anomalies: make(map[string]*services.PricingAnomaly),
+	}
+}
+
+// FindByID returns a pricing anomaly by ID
+func (m *MockPricingAnomalyRepository) FindByID(ctx context.Context, id string) (*services.PricingAnomaly, error) {
+	if anomaly, ok := m.Anomalies[id]; ok {
+		return anomaly, nil
+	}
+	return nil, services.ErrPricingAnomalyNotFound
+}
+
+// List returns pricing anomalies, newest first, optionally only those in one status
+func (m *MockPricingAnomalyRepository) List(ctx context.Context, status services.PricingAnomalyStatus, limit, offset int) ([]*services.PricingAnomaly, error) {
+	result := make([]*services.PricingAnomaly, 0, len(m.Anomalies))
+	for _, anomaly := range m.Anomalies {
+		if status == "" || anomaly.Status == status {
+			result = append(result, anomaly)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	if offset >= len(result) {
+		return []*services.PricingAnomaly{}, nil
+	}
+	result = result[offset:]
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// HasPendingForOrder reports whether an order has an anomaly awaiting review
+func (m *MockPricingAnomalyRepository) HasPendingForOrder(ctx context.Context, orderID string) (bool, error) {
+	for _, anomaly := range m.Anomalies {
+		if anomaly.OrderID == orderID && anomaly.Status == services.AnomalyPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Save stores a pricing anomaly
+func (m *MockPricingAnomalyRepository) Save(ctx context.Context, anomaly *services.PricingAnomaly) error {
+	m.Anomalies[anomaly.ID] = anomaly
+	return nil
+}
@@ -14,6 +14,11 @@ type MockSalePriceResolver struct {
 
 	FindEffectivePriceError  error
 	FindEffectivePricesError error
+
+	// Lookup counts, so tests can check listings resolve prices in one batch
+	FindEffectivePriceCalls         int
+	FindEffectivePricesCalls        int
+	FindEffectiveVariantPricesCalls int
 }
 
 // NewMockSalePriceResolver creates a new mock sale price resolver
@@ -25,6 +30,7 @@ func NewMockSalePriceResolver() *MockSalePriceResolver {
 
 // FindEffectivePrice returns the effective price for a product
 func (m *MockSalePriceResolver) FindEffectivePrice(ctx context.Context, productID string, variantID *string, at time.Time) (*pricing.ProductPrice, error) {
+	m.FindEffectivePriceCalls++
 	if m.FindEffectivePriceError != nil {
 		return nil, m.FindEffectivePriceError
 	}
@@ -36,6 +42,7 @@ func (m *MockSalePriceResolver) FindEffectivePrice(ctx context.Context, productI
 
 // FindEffectivePrices returns effective prices for multiple products
 func (m *MockSalePriceResolver) FindEffectivePrices(ctx context.Context, productIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error) {
+	m.FindEffectivePricesCalls++
 	if m.FindEffectivePricesError != nil {
 		return nil, m.FindEffectivePricesError
 	}
@@ -48,6 +55,21 @@ func (m *MockSalePriceResolver) FindEffectivePrices(ctx context.Context, product
 	return result, nil
 }
 
+// FindEffectiveVariantPrices returns a product's price for each of its variants asked for
+func (m *MockSalePriceResolver) FindEffectiveVariantPrices(ctx context.Context, productID string, variantIDs []string, at time.Time) (map[string]*pricing.ProductPrice, error) {
+	m.FindEffectiveVariantPricesCalls++
+	if m.FindEffectivePricesError != nil {
+		return nil, m.FindEffectivePricesError
+	}
+	result := make(map[string]*pricing.ProductPrice)
+	if price, ok := m.Prices[productID]; ok {
+		for _, id := range variantIDs {
+			result[id] = price
+		}
+	}
+	return result, nil
+}
+
 // AddPrice adds a price for a product
 func (m *MockSalePriceResolver) AddPrice(productID string, amount int64, currency string) {
 	m.Prices[productID] = &pricing.ProductPrice{
@@ -56,6 +78,62 @@ func (m *MockSalePriceResolver) AddPrice(productID string, amount int64, currenc
 	}
 }
 
+// MockProductPriceRepository is a mock implementation of pricing.ProductPriceRepository
+// holding one price per product
+type MockProductPriceRepository struct {
+	*MockSalePriceResolver
+}
+
+// NewMockProductPriceRepository creates a new mock product price repository
+func NewMockProductPriceRepository() *MockProductPriceRepository {
+	return &MockProductPriceRepository{MockSalePriceResolver: NewMockSalePriceResolver()}
+}
+
+// FindByID returns a price by ID
+func (m *MockProductPriceRepository) FindByID(ctx context.Context, id string) (*pricing.ProductPrice, error) {
+	for _, price := range m.Prices {
+		if price.ID == id {
+			return price, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindActiveForProduct returns a product's price
+func (m *MockProductPriceRepository) FindActiveForProduct(ctx context.Context, productID string) ([]*pricing.ProductPrice, error) {
+	if price, ok := m.Prices[productID]; ok {
+		return []*pricing.ProductPrice{price}, nil
+	}
+	return []*pricing.ProductPrice{}, nil
+}
+
+// FindActiveForVariant returns no prices; the mock only holds product prices
+func (m *MockProductPriceRepository) FindActiveForVariant(ctx context.Context, variantID string) ([]*pricing.ProductPrice, error) {
+	return []*pricing.ProductPrice{}, nil
+}
+
+// Save stores a product's price
+func (m *MockProductPriceRepository) Save(ctx context.Context, price *pricing.ProductPrice) error {
+	m.Prices[price.ProductID] = price
+	return nil
+}
+
+// Delete removes a price by ID
+func (m *MockProductPriceRepository) Delete(ctx context.Context, id string) error {
+	for productID, price := range m.Prices {
+		if price.ID == id {
+			delete(m.Prices, productID)
+		}
+	}
+	return nil
+}
+
+// DeleteByProductID removes a product's price
+func (m *MockProductPriceRepository) DeleteByProductID(ctx context.Context, productID string) error {
+	delete(m.Prices, productID)
+	return nil
+}
+
 // MockPromotionRepository is a mock implementation of pricing.PromotionRepository
 type MockPromotionRepository struct {
 	Promotions []*pricing.Promotion
@@ -72,7 +150,7 @@ func NewMockPromotionRepository() *MockPromotionRepository {
 }
 
 // FindActive returns active promotions
-func (m *MockPromotionRepository) FindActive(ctx context.Context, at time.Time) ([]*pricing.Promotion, error) {
+func (m *MockPromotionRepository) FindActive(ctx context.Context) ([]*pricing.Promotion, error) {
 	if m.FindActiveError != nil {
 		return nil, m.FindActiveError
 	}
@@ -108,6 +186,14 @@ func (m *MockPromotionRepository) Create(ctx context.Context, promotion *pricing
 	return nil
 }
 
+// Save creates or updates a promotion
+func (m *MockPromotionRepository) Save(ctx context.Context, promotion *pricing.Promotion) error {
+	if err := m.Update(ctx, promotion); err == ErrNotFound {
+		return m.Create(ctx, promotion)
+	}
+	return nil
+}
+
 // Update updates a promotion
 func (m *MockPromotionRepository) Update(ctx context.Context, promotion *pricing.Promotion) error {
 	for i, p := range m.Promotions {
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockProductMediaRepository is a mock implementation of services.ProductMediaRepository
+type MockProductMediaRepository struct {
+	Media map[string][]*services.ProductMedia
+
+	FindByProductsError error
+}
+
+// NewMockProductMediaRepository creates a new mock product media repository
+func NewMockProductMediaRepository() *MockProductMediaRepository {
+	return &MockProductMediaRepository{
+		Media: make(map[string][]*services.ProductMedia),
+	}
+}
+
+// FindByProduct returns a product's media
+func (m *MockProductMediaRepository) FindByProduct(ctx context.Context, productID string) ([]*services.ProductMedia, error) {
+	return m.Media[productID], nil
+}
+
+// FindByProducts returns the media of several products by product ID
+func (m *MockProductMediaRepository) FindByProducts(ctx context.Context, productIDs []string) (map[string][]*services.ProductMedia, error) {
+	if m.FindByProductsError != nil {
+		return nil, m.FindByProductsError
+	}
+	result := make(map[string][]*services.ProductMedia)
+	for _, id := range productIDs {
+		if media, ok := m.Media[id]; ok {
+			result[id] = media
+		}
+	}
+	return result, nil
+}
+
+// Replace replaces a product's media
+func (m *MockProductMediaRepository) Replace(ctx context.Context, productID string, media []*services.ProductMedia) error {
+	if len(media) == 0 {
+		delete(m.Media, productID)
+		return nil
+	}
+	m.Media[productID] = media
+	return nil
+}
@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockPurchaseLimitRepository is a mock implementation of services.PurchaseLimitRepository
+type MockPurchaseLimitRepository struct {
+	Limits map[string]*services.PurchaseLimit
+}
+
+// NewMockPurchaseLimitRepository creates a new mock purchase limit repository
+func NewMockPurchaseLimitRepository() *MockPurchaseLimitRepository {
+	return &MockPurchaseLimitRepository{
+		Limits: make(map[string]*services.PurchaseLimit),
+	}
+}
+
+// FindByProductID returns a product's purchase limit
+func (m *MockPurchaseLimitRepository) FindByProductID(ctx context.Context, productID string) (*services.PurchaseLimit, error) {
+	if limit, ok := m.Limits[productID]; ok {
+		return limit, nil
+	}
+	return nil, services.ErrPurchaseLimitNotFound
+}
+
+// FindByProductIDs returns the purchase limits of the given products
+func (m *MockPurchaseLimitRepository) FindByProductIDs(ctx context.Context, productIDs []string) ([]*services.PurchaseLimit, error) {
+	result := make([]*services.PurchaseLimit, 0)
+	for _, productID := range productIDs {
+		if limit, ok := m.Limits[productID]; ok {
+			result = append(result, limit)
+		}
+	}
+	return result, nil
+}
+
+// FindAll returns all purchase limits by product ID
+func (m *MockPurchaseLimitRepository) FindAll(ctx context.Context) ([]*services.PurchaseLimit, error) {
+	result := make([]*services.PurchaseLimit, 0, len(m.Limits))
+	for _, limit := range m.Limits {
+		result = append(result, limit)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ProductID < result[j].ProductID })
+	return result, nil
+}
+
+// Save stores a purchase limit
+func (m *MockPurchaseLimitRepository) Save(ctx context.Context, limit *services.PurchaseLimit) error {
+	m.Limits[limit.ProductID] = limit
+	return nil
+}
+
+// Delete removes a purchase limit
+func (m *MockPurchaseLimitRepository) Delete(ctx context.Context, productID string) error {
+	if _, ok := m.Limits[productID]; !ok {
+		return services.ErrPurchaseLimitNotFound
+	}
+	delete(m.Limits, productID)
+	return nil
+}
@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockQuestionRepository is a mock implementation of services.QuestionRepository
+type MockQuestionRepository struct {
+	Questions map[string]*services.ProductQuestion
+	Answers   map[string]*services.ProductAnswer
+}
+
+// NewMockQuestionRepository creates a new mock question repository
+func NewMockQuestionRepository() *MockQuestionRepository {
+	return &MockQuestionRepository{
+		Questions: make(map[string]*services.ProductQuestion),
+		Answers:   make(map[string]*services.ProductAnswer),
+	}
+}
+
+// FindByID returns a copy of a question with its answers
+func (m *MockQuestionRepository) FindByID(ctx context.Context, id string) (*services.ProductQuestion, error) {
+	question, ok := m.Questions[id]
+	if !ok {
+		return nil, services.ErrQuestionNotFound
+	}
+	return m.withAnswers(question), nil
+}
+
+// FindByProduct returns copies of a product's questions with their answers, newest first
+func (m *MockQuestionRepository) FindByProduct(ctx context.Context, productID string) ([]*services.ProductQuestion, error) {
+	result := make([]*services.ProductQuestion, 0)
+	for _, question := range m.Questions {
+		if question.ProductID == productID {
+			result = append(result, m.withAnswers(question))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindAnswerByID returns a copy of an answer
+func (m *MockQuestionRepository) FindAnswerByID(ctx context.Context, id string) (*services.ProductAnswer, error) {
+	answer, ok := m.Answers[id]
+	if !ok {
+		return nil, services.ErrAnswerNotFound
+	}
+	found := *answer
+	return &found, nil
+}
+
+// Save stores a question without its answers
+func (m *MockQuestionRepository) Save(ctx context.Context, question *services.ProductQuestion) error {
+	saved := *question
+	saved.Answers = nil
+	m.Questions[question.ID] = &saved
+	return nil
+}
+
+// AddAnswer records an answer
+func (m *MockQuestionRepository) AddAnswer(ctx context.Context, answer *services.ProductAnswer) error {
+	recorded := *answer
+	m.Answers[answer.ID] = &recorded
+	return nil
+}
+
+// SetQuestionHidden hides or shows a question
+func (m *MockQuestionRepository) SetQuestionHidden(ctx context.Context, id string, hidden bool) error {
+	if question, ok := m.Questions[id]; ok {
+		question.Hidden = hidden
+	}
+	return nil
+}
+
+// SetAnswerHidden hides or shows an answer
+func (m *MockQuestionRepository) SetAnswerHidden(ctx context.Context, id string, hidden bool) error {
+	if answer, ok := m.Answers[id]; ok {
+		answer.Hidden = hidden
+	}
+	return nil
+}
+
+// withAnswers returns a copy of a question with its answers, oldest first
+func (m *MockQuestionRepository) withAnswers(question *services.ProductQuestion) *services.ProductQuestion {
+	found := *question
+	found.Answers = []services.ProductAnswer{}
+	for _, answer := range m.Answers {
+		if answer.QuestionID == question.ID {
+			found.Answers = append(found.Answers, *answer)
+		}
+	}
+	sort.Slice(found.Answers, func(i, j int) bool { return found.Answers[i].CreatedAt.Before(found.Answers[j].CreatedAt) })
+	return &found
+}
@@ -0,0 +1,128 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockQuotaRepository is a mock implementation of services.QuotaRepository
+type MockQuotaRepository struct {
+	mu       sync.Mutex
+	Counters map[string]*services.QuotaCounter
+
+	IncrementError error
+}
+
+// NewMockQuotaRepository creates a new mock quota repository
+func NewMockQuotaRepository() *MockQuotaRepository {
+	return &MockQuotaRepository{
+		Counters: make(map[string]*services.QuotaCounter),
+	}
+}
+
+func quotaCounterKey(subject string, period services.QuotaPeriod, start time.Time) string {
+	return subject + "|" + string(period) + "|" + start.Format(time.RFC3339)
+}
+
+// Increment adds one to a subject's counter for a window
+func (m *MockQuotaRepository) Increment(ctx context.Context, subject, tier string, period services.QuotaPeriod, start time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.IncrementError != nil {
+		return 0, m.IncrementError
+	}
+
+	key := quotaCounterKey(subject, period, start)
+	counter, ok := m.Counters[key]
+	if !ok {
+		counter = &services.QuotaCounter{Subject: subject, Period: period, PeriodStart: start}
+		m.Counters[key] = counter
+	}
+	counter.Tier = tier
+	counter.Requests++
+	counter.UpdatedAt = time.Now()
+	return counter.Requests, nil
+}
+
+// FindBySubject returns a subject's counters for windows starting at or after since
+func (m *MockQuotaRepository) FindBySubject(ctx context.Context, subject string, since time.Time) ([]*services.QuotaCounter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var counters []*services.QuotaCounter
+	for _, counter := range m.Counters {
+		if counter.Subject == subject && !counter.PeriodStart.Before(since) {
+			copied := *counter
+			counters = append(counters, &copied)
+		}
+	}
+	return counters, nil
+}
+
+// ListByPeriod lists the counters of one window, most requests first
+func (m *MockQuotaRepository) ListByPeriod(ctx context.Context, period services.QuotaPeriod, start time.Time, limit, offset int) ([]*services.QuotaCounter, error) {
+	counters := m.byPeriod(period, start)
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].Requests != counters[j].Requests {
+			return counters[i].Requests > counters[j].Requests
+		}
+		return counters[i].Subject < counters[j].Subject
+	})
+	if offset >= len(counters) {
+		return []*services.QuotaCounter{}, nil
+	}
+	counters = counters[offset:]
+	if limit > 0 && limit < len(counters) {
+		counters = counters[:limit]
+	}
+	return counters, nil
+}
+
+// CountByPeriod counts the counters of one window
+func (m *MockQuotaRepository) CountByPeriod(ctx context.Context, period services.QuotaPeriod, start time.Time) (int64, error) {
+	return int64(len(m.byPeriod(period, start))), nil
+}
+
+// DeleteBySubject deletes a subject's counters
+func (m *MockQuotaRepository) DeleteBySubject(ctx context.Context, subject string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for key, counter := range m.Counters {
+		if counter.Subject == subject {
+			delete(m.Counters, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteBefore deletes counters for windows starting before before
+func (m *MockQuotaRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for key, counter := range m.Counters {
+		if counter.PeriodStart.Before(before) {
+			delete(m.Counters, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockQuotaRepository) byPeriod(period services.QuotaPeriod, start time.Time) []*services.QuotaCounter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var counters []*services.QuotaCounter
+	for _, counter := range m.Counters {
+		if counter.Period == period && counter.PeriodStart.Equal(start) {
+			copied := *counter
+			counters = append(counters, &copied)
+		}
+	}
+	return counters
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockQuoteRepository is a mock implementation of services.QuoteRepository
+type MockQuoteRepository struct {
+	Quotes map[string]*services.Quote
+}
+
+// NewMockQuoteRepository creates a new mock quote repository
+func NewMockQuoteRepository() *MockQuoteRepository {
+	return &MockQuoteRepository{
+		Quotes: make(map[string]*services.Quote),
+	}
+}
+
+// FindByID returns a copy of a quote
+func (m *MockQuoteRepository) FindByID(ctx context.Context, id string) (*services.Quote, error) {
+	quote, ok := m.Quotes[id]
+	if !ok {
+		return nil, services.ErrQuoteNotFound
+	}
+	return copyQuote(quote), nil
+}
+
+// List returns quotes matching the user and status, newest first, ignoring pagination
+func (m *MockQuoteRepository) List(ctx context.Context, filter services.QuoteFilter) ([]*services.Quote, error) {
+	var quotes []*services.Quote
+	for _, quote := range m.Quotes {
+		if filter.UserID != "" && quote.UserID != filter.UserID {
+			continue
+		}
+		if filter.Status != "" && quote.Status != filter.Status {
+			continue
+		}
+		quotes = append(quotes, copyQuote(quote))
+	}
+	sort.Slice(quotes, func(i, j int) bool { return quotes[i].CreatedAt.After(quotes[j].CreatedAt) })
+	return quotes, nil
+}
+
+// Save stores a copy of a quote
+func (m *MockQuoteRepository) Save(ctx context.Context, quote *services.Quote) error {
+	m.Quotes[quote.ID] = copyQuote(quote)
+	return nil
+}
+
+// MarkAccepted accepts a sent quote
+func (m *MockQuoteRepository) MarkAccepted(ctx context.Context, id, orderID string, acceptedAt time.Time) error {
+	quote, ok := m.Quotes[id]
+	if !ok || quote.Status != services.QuoteStatusSent {
+		return services.ErrQuoteNotOpen
+	}
+	quote.Status = services.QuoteStatusAccepted
+	quote.OrderID = orderID
+	quote.AcceptedAt = &acceptedAt
+	quote.UpdatedAt = acceptedAt
+	return nil
+}
+
+func copyQuote(quote *services.Quote) *services.Quote {
+	stored := *quote
+	stored.Items = append([]services.QuoteItem(nil), quote.Items...)
+	return &stored
+}
@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockRecentlyViewedRepository is a mock implementation of services.RecentlyViewedRepository
+type MockRecentlyViewedRepository struct {
+	Views map[string][]services.ProductView // keyed by user ID, most recent first
+}
+
+// NewMockRecentlyViewedRepository creates a new mock recently viewed repository
+func NewMockRecentlyViewedRepository() *MockRecentlyViewedRepository {
+	return &MockRecentlyViewedRepository{
+		Views: make(map[string][]services.ProductView),
+	}
+}
+
+// Record stores a view and keeps only the user's most recent keep views
+func (m *MockRecentlyViewedRepository) Record(ctx context.Context, userID string, view services.ProductView, keep int) error {
+	m.Views[userID] = m.merge(m.Views[userID], []services.ProductView{view}, keep)
+	return nil
+}
+
+// List returns a user's views, most recent first
+func (m *MockRecentlyViewedRepository) List(ctx context.Context, userID string, limit int) ([]services.ProductView, error) {
+	views := m.Views[userID]
+	if len(views) > limit {
+		views = views[:limit]
+	}
+	return append([]services.ProductView(nil), views...), nil
+}
+
+// Move gives one user's views to another
+func (m *MockRecentlyViewedRepository) Move(ctx context.Context, fromUserID, toUserID string, keep int) error {
+	m.Views[toUserID] = m.merge(m.Views[toUserID], m.Views[fromUserID], keep)
+	delete(m.Views, fromUserID)
+	return nil
+}
+
+// merge combines views, keeping the later view of each product and the most recent keep
+func (m *MockRecentlyViewedRepository) merge(views, added []services.ProductView, keep int) []services.ProductView {
+	latest := make(map[string]services.ProductView)
+	for _, view := range append(append([]services.ProductView(nil), views...), added...) {
+		if existing, ok := latest[view.ProductID]; !ok || view.ViewedAt.After(existing.ViewedAt) {
+			latest[view.ProductID] = view
+		}
+	}
+
+	merged := make([]services.ProductView, 0, len(latest))
+	for _, view := range latest {
+		merged = append(merged, view)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ViewedAt.After(merged[j].ViewedAt)
+	})
+	if len(merged) > keep {
+		merged = merged[:keep]
+	}
+	return merged
+}
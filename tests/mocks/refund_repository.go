@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockRefundRepository is a mock implementation of services.RefundRepository
+type MockRefundRepository struct {
+	Refunds []*services.OrderRefund
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockRefundRepository creates a new mock refund repository
+func NewMockRefundRepository() *MockRefundRepository {
+	return &MockRefundRepository{}
+}
+
+// FindByOrderID returns the refunds for an order in the order they were saved
+func (m *MockRefundRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.OrderRefund, error) {
+	result := make([]*services.OrderRefund, 0)
+	for _, refund := range m.Refunds {
+		if refund.OrderID == orderID {
+			result = append(result, refund)
+		}
+	}
+	return result, nil
+}
+
+// Save stores a refund
+func (m *MockRefundRepository) Save(ctx context.Context, refund *services.OrderRefund) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Refunds = append(m.Refunds, refund)
+	return nil
+}
@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockRentalRepository is a mock implementation of services.RentalRepository
+type MockRentalRepository struct {
+	Products map[string]*services.RentalProduct
+	Bookings []*services.RentalBooking
+}
+
+// NewMockRentalRepository creates a new mock rental repository
+func NewMockRentalRepository() *MockRentalRepository {
+	return &MockRentalRepository{
+		Products: make(map[string]*services.RentalProduct),
+	}
+}
+
+// FindProduct returns a product's rental settings
+func (m *MockRentalRepository) FindProduct(ctx context.Context, productID string) (*services.RentalProduct, error) {
+	if rental, ok := m.Products[productID]; ok {
+		return rental, nil
+	}
+	return nil, services.ErrRentalNotFound
+}
+
+// SaveProduct stores a product's rental settings
+func (m *MockRentalRepository) SaveProduct(ctx context.Context, rental *services.RentalProduct) error {
+	m.Products[rental.ProductID] = rental
+	return nil
+}
+
+// DeleteProduct removes a product's rental settings
+func (m *MockRentalRepository) DeleteProduct(ctx context.Context, productID string) error {
+	if _, ok := m.Products[productID]; !ok {
+		return services.ErrRentalNotFound
+	}
+	delete(m.Products, productID)
+	return nil
+}
+
+// FindBookings returns the bookings of a SKU running on any day from from to to
+func (m *MockRentalRepository) FindBookings(ctx context.Context, sku string, from, to time.Time) ([]*services.RentalBooking, error) {
+	result := make([]*services.RentalBooking, 0)
+	for _, booking := range m.Bookings {
+		if booking.SKU == sku && !booking.Start.After(to) && !booking.End.Before(from) {
+			result = append(result, booking)
+		}
+	}
+	return result, nil
+}
+
+// SaveBookings stores bookings
+func (m *MockRentalRepository) SaveBookings(ctx context.Context, bookings []*services.RentalBooking) error {
+	m.Bookings = append(m.Bookings, bookings...)
+	return nil
+}
+
+// DeleteOrderBookings removes the bookings of an order
+func (m *MockRentalRepository) DeleteOrderBookings(ctx context.Context, orderID string) error {
+	kept := m.Bookings[:0]
+	for _, booking := range m.Bookings {
+		if booking.OrderID != orderID {
+			kept = append(kept, booking)
+		}
+	}
+	m.Bookings = kept
+	return nil
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"time"
+)
+
+// MockRetentionRepository is a mock implementation of services.RetentionRepository. It
+// reports Affected[rule] rows for each rule and records the cutoff each rule was run with.
+type MockRetentionRepository struct {
+	Affected map[string]int64
+	Before   map[string]time.Time
+	Applied  map[string]bool // rules run without dryRun
+}
+
+// NewMockRetentionRepository creates a new mock retention repository
+func NewMockRetentionRepository() *MockRetentionRepository {
+	return &MockRetentionRepository{
+		Affected: make(map[string]int64),
+		Before:   make(map[string]time.Time),
+		Applied:  make(map[string]bool),
+	}
+}
+
+// AnonymizeGuestCarts records a guest cart anonymization
+func (m *MockRetentionRepository) AnonymizeGuestCarts(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	return m.record("guest-carts", before, dryRun), nil
+}
+
+// PurgeWebhookPayloads records a webhook payload purge
+func (m *MockRetentionRepository) PurgeWebhookPayloads(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	return m.record("webhook-payloads", before, dryRun), nil
+}
+
+// RedactIPAddresses records an IP address redaction
+func (m *MockRetentionRepository) RedactIPAddresses(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	return m.record("ip-addresses", before, dryRun), nil
+}
+
+func (m *MockRetentionRepository) record(rule string, before time.Time, dryRun bool) int64 {
+	m.Before[rule] = before
+	if !dryRun {
+		m.Applied[rule] = true
+	}
+	return m.Affected[rule]
+}
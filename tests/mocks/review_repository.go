@@ -0,0 +1,114 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockReviewRepository is a mock implementation of services.ReviewRepository
+type MockReviewRepository struct {
+	Reviews map[string]*services.Review
+	Photos  map[string]*services.ReviewPhoto
+}
+
+// NewMockReviewRepository creates a new mock review repository
+func NewMockReviewRepository() *MockReviewRepository {
+	return &MockReviewRepository{
+		Reviews: make(map[string]*services.Review),
+		Photos:  make(map[string]*services.ReviewPhoto),
+	}
+}
+
+// FindByID returns a copy of a review with its photos
+func (m *MockReviewRepository) FindByID(ctx context.Context, id string) (*services.Review, error) {
+	review, ok := m.Reviews[id]
+	if !ok {
+		return nil, services.ErrReviewNotFound
+	}
+	return m.withPhotos(review), nil
+}
+
+// FindByUserAndProduct returns a copy of a user's review of a product, or nil
+func (m *MockReviewRepository) FindByUserAndProduct(ctx context.Context, userID, productID string) (*services.Review, error) {
+	for _, review := range m.Reviews {
+		if review.UserID == userID && review.ProductID == productID {
+			return m.withPhotos(review), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByProduct returns copies of a product's reviews in a status, newest first
+func (m *MockReviewRepository) FindByProduct(ctx context.Context, productID string, status services.ReviewStatus) ([]*services.Review, error) {
+	result := m.filter(func(review *services.Review) bool { return review.ProductID == productID && review.Status == status })
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindByUserID returns copies of a user's reviews, newest first
+func (m *MockReviewRepository) FindByUserID(ctx context.Context, userID string) ([]*services.Review, error) {
+	result := m.filter(func(review *services.Review) bool { return review.UserID == userID })
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindByStatus returns copies of the reviews in a status, or all reviews, oldest first
+func (m *MockReviewRepository) FindByStatus(ctx context.Context, status services.ReviewStatus) ([]*services.Review, error) {
+	result := m.filter(func(review *services.Review) bool { return status == "" || review.Status == status })
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// Save stores a review without its photos
+func (m *MockReviewRepository) Save(ctx context.Context, review *services.Review) error {
+	saved := *review
+	saved.Photos = nil
+	m.Reviews[review.ID] = &saved
+	return nil
+}
+
+// AddPhoto records a photo
+func (m *MockReviewRepository) AddPhoto(ctx context.Context, photo *services.ReviewPhoto) error {
+	recorded := *photo
+	m.Photos[photo.ID] = &recorded
+	return nil
+}
+
+// DeletePhoto removes a photo
+func (m *MockReviewRepository) DeletePhoto(ctx context.Context, id string) error {
+	delete(m.Photos, id)
+	return nil
+}
+
+// SetHidden hides or shows a review
+func (m *MockReviewRepository) SetHidden(ctx context.Context, id string, hidden bool) error {
+	if review, ok := m.Reviews[id]; ok {
+		review.Hidden = hidden
+	}
+	return nil
+}
+
+func (m *MockReviewRepository) filter(match func(*services.Review) bool) []*services.Review {
+	result := make([]*services.Review, 0)
+	for _, review := range m.Reviews {
+		if match(review) {
+			result = append(result, m.withPhotos(review))
+		}
+	}
+	return result
+}
+
+// withPhotos returns a copy of a review with its photos, oldest first
+func (m *MockReviewRepository) withPhotos(review *services.Review) *services.Review {
+	found := *review
+	found.Photos = []services.ReviewPhoto{}
+	for _, photo := range m.Photos {
+		if photo.ReviewID == review.ID {
+			found.Photos = append(found.Photos, *photo)
+		}
+	}
+	sort.Slice(found.Photos, func(i, j int) bool { return found.Photos[i].CreatedAt.Before(found.Photos[j].CreatedAt) })
+	return &found
+}
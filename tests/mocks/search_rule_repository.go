@@ -0,0 +1,95 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockSearchRuleRepository is a mock implementation of services.SearchRuleRepository
+type MockSearchRuleRepository struct {
+	SynonymSets map[string]*services.SynonymSet
+	Rules       map[string]*services.SearchRule
+
+	FindSynonymSetsCalls int
+	FindSynonymSetsError error
+}
+
+// NewMockSearchRuleRepository creates a new mock search rule repository
+func NewMockSearchRuleRepository() *MockSearchRuleRepository {
+	return &MockSearchRuleRepository{
+		SynonymSets: make(map[string]*services.SynonymSet),
+		Rules:       make(map[string]*services.SearchRule),
+	}
+}
+
+// FindSynonymSet returns a synonym set by ID
+func (m *MockSearchRuleRepository) FindSynonymSet(ctx context.Context, id string) (*services.SynonymSet, error) {
+	if set, ok := m.SynonymSets[id]; ok {
+		return set, nil
+	}
+	return nil, services.ErrSynonymSetNotFound
+}
+
+// FindSynonymSets returns every synonym set, oldest first
+func (m *MockSearchRuleRepository) FindSynonymSets(ctx context.Context) ([]*services.SynonymSet, error) {
+	m.FindSynonymSetsCalls++
+	if m.FindSynonymSetsError != nil {
+		return nil, m.FindSynonymSetsError
+	}
+	result := make([]*services.SynonymSet, 0, len(m.SynonymSets))
+	for _, set := range m.SynonymSets {
+		result = append(result, set)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// SaveSynonymSet stores a synonym set
+func (m *MockSearchRuleRepository) SaveSynonymSet(ctx context.Context, set *services.SynonymSet) error {
+	m.SynonymSets[set.ID] = set
+	return nil
+}
+
+// DeleteSynonymSet removes a synonym set
+func (m *MockSearchRuleRepository) DeleteSynonymSet(ctx context.Context, id string) error {
+	if _, ok := m.SynonymSets[id]; !ok {
+		return services.ErrSynonymSetNotFound
+	}
+	delete(m.SynonymSets, id)
+	return nil
+}
+
+// FindRule returns a search rule by ID
+func (m *MockSearchRuleRepository) FindRule(ctx context.Context, id string) (*services.SearchRule, error) {
+	if rule, ok := m.Rules[id]; ok {
+		return rule, nil
+	}
+	return nil, services.ErrSearchRuleNotFound
+}
+
+// FindRules returns every search rule, by query
+func (m *MockSearchRuleRepository) FindRules(ctx context.Context) ([]*services.SearchRule, error) {
+	result := make([]*services.SearchRule, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Query < result[j].Query })
+	return result, nil
+}
+
+// SaveRule stores a search rule
+func (m *MockSearchRuleRepository) SaveRule(ctx context.Context, rule *services.SearchRule) error {
+	m.Rules[rule.ID] = rule
+	return nil
+}
+
+// DeleteRule removes a search rule
+func (m *MockSearchRuleRepository) DeleteRule(ctx context.Context, id string) error {
+	if _, ok := m.Rules[id]; !ok {
+		return services.ErrSearchRuleNotFound
+	}
+	delete(m.Rules, id)
+	return nil
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import "context"
+
+// MockSearchTermRepository is a mock implementation of services.SearchTermRepository.
+// Known terms return themselves and misspellings return their entry in Corrections.
+type MockSearchTermRepository struct {
+	Terms       map[string]bool
+	Corrections map[string]string
+
+	ClosestError error
+}
+
+// NewMockSearchTermRepository creates a new mock search term repository knowing terms
+func NewMockSearchTermRepository(terms ...string) *MockSearchTermRepository {
+	m := &MockSearchTermRepository{
+		Terms:       make(map[string]bool),
+		Corrections: make(map[string]string),
+	}
+	for _, term := range terms {
+		m.Terms[term] = true
+	}
+	return m
+}
+
+// Closest returns word when it is known, else its correction
+func (m *MockSearchTermRepository) Closest(ctx context.Context, word string) (string, error) {
+	if m.ClosestError != nil {
+		return "", m.ClosestError
+	}
+	if m.Terms[word] {
+		return word, nil
+	}
+	return m.Corrections[word], nil
+}
+
+// Rebuild reports the number of known terms
+func (m *MockSearchTermRepository) Rebuild(ctx context.Context) (int64, error) {
+	return int64(len(m.Terms)), nil
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockShippingMethodRepository is a mock implementation of services.ShippingMethodRepository
+type MockShippingMethodRepository struct {
+	Methods map[string]*services.ShippingMethod
+
+	// Error injection
+	FindActiveError error
+}
+
+// NewMockShippingMethodRepository creates a new mock shipping method repository
+func NewMockShippingMethodRepository() *MockShippingMethodRepository {
+	return &MockShippingMethodRepository{
+		Methods: make(map[string]*services.ShippingMethod),
+	}
+}
+
+// FindByID returns a method by ID
+func (m *MockShippingMethodRepository) FindByID(ctx context.Context, id string) (*services.ShippingMethod, error) {
+	if method, ok := m.Methods[id]; ok {
+		return method, nil
+	}
+	return nil, services.ErrShippingMethodNotFound
+}
+
+// FindAll returns all methods by ID
+func (m *MockShippingMethodRepository) FindAll(ctx context.Context) ([]*services.ShippingMethod, error) {
+	result := make([]*services.ShippingMethod, 0, len(m.Methods))
+	for _, method := range m.Methods {
+		result = append(result, method)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// FindActive returns active methods by ID
+func (m *MockShippingMethodRepository) FindActive(ctx context.Context) ([]*services.ShippingMethod, error) {
+	if m.FindActiveError != nil {
+		return nil, m.FindActiveError
+	}
+	all, _ := m.FindAll(ctx)
+	result := make([]*services.ShippingMethod, 0, len(all))
+	for _, method := range all {
+		if method.IsActive {
+			result = append(result, method)
+		}
+	}
+	return result, nil
+}
+
+// Save stores a method
+func (m *MockShippingMethodRepository) Save(ctx context.Context, method *services.ShippingMethod) error {
+	m.Methods[method.ID] = method
+	return nil
+}
+
+// Delete removes a method
+func (m *MockShippingMethodRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Methods[id]; !ok {
+		return services.ErrShippingMethodNotFound
+	}
+	delete(m.Methods, id)
+	return nil
+}
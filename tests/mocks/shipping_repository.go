@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockShippingZoneRepository is a mock implementation of services.ShippingZoneRepository
+type MockShippingZoneRepository struct {
+	Zones map[string]*services.ShippingZone
+
+	// Error injection
+	FindActiveError error
+	SaveError       error
+}
+
+// NewMockShippingZoneRepository creates a new mock shipping zone repository
+func NewMockShippingZoneRepository() *MockShippingZoneRepository {
+	return &MockShippingZoneRepository{
+		Zones: make(map[string]*services.ShippingZone),
+	}
+}
+
+// FindByID returns a zone by ID
+func (m *MockShippingZoneRepository) FindByID(ctx context.Context, id string) (*services.ShippingZone, error) {
+	if zone, ok := m.Zones[id]; ok {
+		return zone, nil
+	}
+	return nil, services.ErrShippingZoneNotFound
+}
+
+// FindAll returns all zones
+func (m *MockShippingZoneRepository) FindAll(ctx context.Context) ([]*services.ShippingZone, error) {
+	result := make([]*services.ShippingZone, 0, len(m.Zones))
+	for _, zone := range m.Zones {
+		result = append(result, zone)
+	}
+	return result, nil
+}
+
+// FindActive returns active zones
+func (m *MockShippingZoneRepository) FindActive(ctx context.Context) ([]*services.ShippingZone, error) {
+	if m.FindActiveError != nil {
+		return nil, m.FindActiveError
+	}
+	result := make([]*services.ShippingZone, 0, len(m.Zones))
+	for _, zone := range m.Zones {
+		if zone.IsActive {
+			result = append(result, zone)
+		}
+	}
+	return result, nil
+}
+
+// Save stores a zone
+func (m *MockShippingZoneRepository) Save(ctx context.Context, zone *services.ShippingZone) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.Zones[zone.ID] = zone
+	return nil
+}
+
+// Delete removes a zone
+func (m *MockShippingZoneRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Zones[id]; !ok {
+		return services.ErrShippingZoneNotFound
+	}
+	delete(m.Zones, id)
+	return nil
+}
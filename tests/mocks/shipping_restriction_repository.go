@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockShippingRestrictionRepository is a mock implementation of services.ShippingRestrictionRepository
+type MockShippingRestrictionRepository struct {
+	Restrictions map[string]*services.ShippingRestriction
+}
+
+// NewMockShippingRestrictionRepository creates a new mock shipping restriction repository
+func NewMockShippingRestrictionRepository() *MockShippingRestrictionRepository {
+	return &MockShippingRestrictionRepository{
+		Restrictions: make(map[string]*services.ShippingRestriction),
+	}
+}
+
+// FindByProductID returns a product's shipping restriction
+func (m *MockShippingRestrictionRepository) FindByProductID(ctx context.Context, productID string) (*services.ShippingRestriction, error) {
+	if restriction, ok := m.Restrictions[productID]; ok {
+		return restriction, nil
+	}
+	return nil, services.ErrShippingRestrictionNotFound
+}
+
+// FindByProductIDs returns the shipping restrictions of the given products by product ID
+func (m *MockShippingRestrictionRepository) FindByProductIDs(ctx context.Context, productIDs []string) ([]*services.ShippingRestriction, error) {
+	result := make([]*services.ShippingRestriction, 0)
+	for _, productID := range productIDs {
+		if restriction, ok := m.Restrictions[productID]; ok {
+			result = append(result, restriction)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ProductID < result[j].ProductID })
+	return result, nil
+}
+
+// Save stores a shipping restriction
+func (m *MockShippingRestrictionRepository) Save(ctx context.Context, restriction *services.ShippingRestriction) error {
+	m.Restrictions[restriction.ProductID] = restriction
+	return nil
+}
+
+// Delete removes a shipping restriction
+func (m *MockShippingRestrictionRepository) Delete(ctx context.Context, productID string) error {
+	if _, ok := m.Restrictions[productID]; !ok {
+		return services.ErrShippingRestrictionNotFound
+	}
+	delete(m.Restrictions, productID)
+	return nil
+}
@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockStoreCreditRepository is a mock implementation of services.StoreCreditRepository
+type MockStoreCreditRepository struct {
+	Balances     map[string]*services.StoreCreditBalance // keyed by user ID and currency
+	Transactions []*services.StoreCreditTransaction
+
+	// Error injection
+	ApplyError error
+}
+
+// NewMockStoreCreditRepository creates a new mock store credit repository
+func NewMockStoreCreditRepository() *MockStoreCreditRepository {
+	return &MockStoreCreditRepository{
+		Balances: make(map[string]*services.StoreCreditBalance),
+	}
+}
+
+// FindBalances returns a customer's balances
+func (m *MockStoreCreditRepository) FindBalances(ctx context.Context, userID string) ([]*services.StoreCreditBalance, error) {
+	result := make([]*services.StoreCreditBalance, 0)
+	for _, balance := range m.Balances {
+		if balance.UserID == userID {
+			result = append(result, balance)
+		}
+	}
+	return result, nil
+}
+
+// FindBalance returns a customer's balance in one currency, or a zero balance
+func (m *MockStoreCreditRepository) FindBalance(ctx context.Context, userID, currency string) (*services.StoreCreditBalance, error) {
+	if balance, ok := m.Balances[userID+":"+currency]; ok {
+		return balance, nil
+	}
+	return &services.StoreCreditBalance{UserID: userID, Balance: money.Zero(currency)}, nil
+}
+
+// Apply adjusts the balance and records the transaction
+func (m *MockStoreCreditRepository) Apply(ctx context.Context, txn *services.StoreCreditTransaction) error {
+	if m.ApplyError != nil {
+		return m.ApplyError
+	}
+
+	balance, _ := m.FindBalance(ctx, txn.UserID, txn.Amount.Currency)
+	if balance.Balance.Amount+txn.Amount.Amount < 0 {
+		return services.ErrInsufficientStoreCredit
+	}
+
+	balance.Balance.Amount += txn.Amount.Amount
+	balance.UpdatedAt = time.Now()
+	m.Balances[txn.UserID+":"+txn.Amount.Currency] = balance
+
+	txn.BalanceAfter = balance.Balance
+	m.Transactions = append(m.Transactions, txn)
+	return nil
+}
+
+// ListTransactions returns a customer's ledger, newest first
+func (m *MockStoreCreditRepository) ListTransactions(ctx context.Context, filter services.StoreCreditFilter) ([]*services.StoreCreditTransaction, error) {
+	result := make([]*services.StoreCreditTransaction, 0)
+	for i := len(m.Transactions) - 1; i >= 0; i-- {
+		if m.Transactions[i].UserID == filter.UserID {
+			result = append(result, m.Transactions[i])
+		}
+	}
+	return result, nil
+}
+
+// CountTransactions counts a customer's ledger entries
+func (m *MockStoreCreditRepository) CountTransactions(ctx context.Context, filter services.StoreCreditFilter) (int64, error) {
+	txns, _ := m.ListTransactions(ctx, filter)
+	return int64(len(txns)), nil
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockSuggestionRepository is a mock implementation of services.SuggestionRepository
+type MockSuggestionRepository struct {
+	Suggestions []*services.Suggestion
+
+	SuggestError error
+}
+
+// NewMockSuggestionRepository creates a new mock suggestion repository
+func NewMockSuggestionRepository(suggestions ...*services.Suggestion) *MockSuggestionRepository {
+	return &MockSuggestionRepository{Suggestions: suggestions}
+}
+
+// Suggest returns up to limit suggestions of a type whose text, or a word in it, starts
+// with prefix
+func (m *MockSuggestionRepository) Suggest(ctx context.Context, suggestionType services.SuggestionType, prefix string, limit int) ([]*services.Suggestion, error) {
+	if m.SuggestError != nil {
+		return nil, m.SuggestError
+	}
+	result := []*services.Suggestion{}
+	for _, suggestion := range m.Suggestions {
+		text := strings.ToLower(suggestion.Text)
+		if suggestion.Type != suggestionType || !(strings.HasPrefix(text, prefix) || strings.Contains(text, " "+prefix)) {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, suggestion)
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockSupportTicketRepository is a mock implementation of services.SupportTicketRepository
+type MockSupportTicketRepository struct {
+	Tickets  map[string]*services.SupportTicket
+	Messages []*services.SupportTicketMessage
+}
+
+// NewMockSupportTicketRepository creates a new mock support ticket repository
+func NewMockSupportTicketRepository() *MockSupportTicketRepository {
+	return &MockSupportTicketRepository{
+		Tickets: make(map[string]*services.SupportTicket),
+	}
+}
+
+// FindByID returns a copy of a ticket with its messages
+func (m *MockSupportTicketRepository) FindByID(ctx context.Context, id string) (*services.SupportTicket, error) {
+	ticket, ok := m.Tickets[id]
+	if !ok {
+		return nil, services.ErrTicketNotFound
+	}
+	return m.withMessages(ticket), nil
+}
+
+// FindByOrderID returns copies of an order's tickets with their messages, oldest first
+func (m *MockSupportTicketRepository) FindByOrderID(ctx context.Context, orderID string) ([]*services.SupportTicket, error) {
+	result := m.filter(func(ticket *services.SupportTicket) bool { return ticket.OrderID == orderID })
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	for i, ticket := range result {
+		result[i] = m.withMessages(ticket)
+	}
+	return result, nil
+}
+
+// FindByUserID returns copies of a user's tickets, most recently updated first
+func (m *MockSupportTicketRepository) FindByUserID(ctx context.Context, userID string) ([]*services.SupportTicket, error) {
+	result := m.filter(func(ticket *services.SupportTicket) bool { return ticket.UserID == userID })
+	sort.Slice(result, func(i, j int) bool { return result[i].UpdatedAt.After(result[j].UpdatedAt) })
+	return result, nil
+}
+
+// FindByStatus returns copies of the tickets in a status, or all tickets, least recently
+// updated first
+func (m *MockSupportTicketRepository) FindByStatus(ctx context.Context, status services.TicketStatus) ([]*services.SupportTicket, error) {
+	result := m.filter(func(ticket *services.SupportTicket) bool { return status == "" || ticket.Status == status })
+	sort.Slice(result, func(i, j int) bool { return result[i].UpdatedAt.Before(result[j].UpdatedAt) })
+	return result, nil
+}
+
+// Save stores a ticket without its messages
+func (m *MockSupportTicketRepository) Save(ctx context.Context, ticket *services.SupportTicket) error {
+	saved := *ticket
+	saved.Messages = nil
+	m.Tickets[ticket.ID] = &saved
+	return nil
+}
+
+// AddMessage records a message
+func (m *MockSupportTicketRepository) AddMessage(ctx context.Context, message *services.SupportTicketMessage) error {
+	recorded := *message
+	m.Messages = append(m.Messages, &recorded)
+	return nil
+}
+
+func (m *MockSupportTicketRepository) filter(match func(*services.SupportTicket) bool) []*services.SupportTicket {
+	result := make([]*services.SupportTicket, 0)
+	for _, ticket := range m.Tickets {
+		if match(ticket) {
+			found := *ticket
+			found.Messages = []services.SupportTicketMessage{}
+			result = append(result, &found)
+		}
+	}
+	return result
+}
+
+func (m *MockSupportTicketRepository) withMessages(ticket *services.SupportTicket) *services.SupportTicket {
+	found := *ticket
+	found.Messages = []services.SupportTicketMessage{}
+	for _, message := range m.Messages {
+		if message.TicketID == ticket.ID {
+			found.Messages = append(found.Messages, *message)
+		}
+	}
+	return &found
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockSystemStatusRepository is a mock implementation of services.SystemStatusRepository
+type MockSystemStatusRepository struct {
+	Migration *services.AppliedMigration
+	DBLatency services.DBLatency
+	Pool      services.DBConnections
+
+	// Error injection
+	MigrationError error
+}
+
+// NewMockSystemStatusRepository creates a new mock system status repository
+func NewMockSystemStatusRepository() *MockSystemStatusRepository {
+	return &MockSystemStatusRepository{}
+}
+
+// LastMigration returns the configured migration
+func (m *MockSystemStatusRepository) LastMigration(ctx context.Context) (*services.AppliedMigration, error) {
+	if m.MigrationError != nil {
+		return nil, m.MigrationError
+	}
+	return m.Migration, nil
+}
+
+// Latency returns the configured latency
+func (m *MockSystemStatusRepository) Latency() services.DBLatency {
+	return m.DBLatency
+}
+
+// Connections returns the configured pool counters
+func (m *MockSystemStatusRepository) Connections() services.DBConnections {
+	return m.Pool
+}
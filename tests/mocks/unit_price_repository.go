@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockUnitPriceRepository is a mock implementation of services.UnitPriceRepository
+type MockUnitPriceRepository struct {
+	Units    map[string]*services.VariantUnit // variant ID -> unit; a variant must be present to be known
+	Products map[string]string                // variant ID -> product ID
+}
+
+// NewMockUnitPriceRepository creates a new mock unit price repository for the given
+// variants of a product
+func NewMockUnitPriceRepository(productID string, variantIDs ...string) *MockUnitPriceRepository {
+	repo := &MockUnitPriceRepository{
+		Units:    make(map[string]*services.VariantUnit),
+		Products: make(map[string]string),
+	}
+	for _, id := range variantIDs {
+		repo.Units[id] = &services.VariantUnit{VariantID: id}
+		repo.Products[id] = productID
+	}
+	return repo
+}
+
+// FindUnit finds a variant's unit
+func (m *MockUnitPriceRepository) FindUnit(ctx context.Context, variantID string) (*services.VariantUnit, error) {
+	unit, ok := m.Units[variantID]
+	if !ok {
+		return nil, services.ErrVariantNotFound
+	}
+	return unit, nil
+}
+
+// FindUnitsByProduct finds the units of a product's variants that have one
+func (m *MockUnitPriceRepository) FindUnitsByProduct(ctx context.Context, productID string) (map[string]*services.VariantUnit, error) {
+	units := make(map[string]*services.VariantUnit)
+	for variantID, unit := range m.Units {
+		if m.Products[variantID] == productID && unit.Quantity > 0 {
+			units[variantID] = unit
+		}
+	}
+	return units, nil
+}
+
+// SetUnit sets or clears a variant's unit
+func (m *MockUnitPriceRepository) SetUnit(ctx context.Context, unit *services.VariantUnit) error {
+	if _, ok := m.Units[unit.VariantID]; !ok {
+		return services.ErrVariantNotFound
+	}
+	m.Units[unit.VariantID] = &services.VariantUnit{VariantID: unit.VariantID, Quantity: unit.Quantity, Measure: unit.Measure}
+	return nil
+}
@@ -0,0 +1,94 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockWaitingRoomRepository is a mock implementation of services.WaitingRoomRepository.
+// Tickets are stored as copies since the service fills in positions on the ones it returns.
+type MockWaitingRoomRepository struct {
+	Tickets map[string]*services.WaitingRoomTicket // keyed by product ID and user ID
+}
+
+// NewMockWaitingRoomRepository creates a new mock waiting room repository
+func NewMockWaitingRoomRepository() *MockWaitingRoomRepository {
+	return &MockWaitingRoomRepository{
+		Tickets: make(map[string]*services.WaitingRoomTicket),
+	}
+}
+
+// FindByUser returns a customer's ticket for a product
+func (m *MockWaitingRoomRepository) FindByUser(ctx context.Context, productID, userID string) (*services.WaitingRoomTicket, error) {
+	if ticket, ok := m.Tickets[productID+"/"+userID]; ok {
+		copied := *ticket
+		return &copied, nil
+	}
+	return nil, services.ErrWaitingRoomTicketNotFound
+}
+
+// FindByToken returns the ticket holding a token
+func (m *MockWaitingRoomRepository) FindByToken(ctx context.Context, token string) (*services.WaitingRoomTicket, error) {
+	for _, ticket := range m.Tickets {
+		if ticket.Token == token {
+			copied := *ticket
+			return &copied, nil
+		}
+	}
+	return nil, services.ErrWaitingRoomTicketNotFound
+}
+
+// Save stores a ticket
+func (m *MockWaitingRoomRepository) Save(ctx context.Context, ticket *services.WaitingRoomTicket) error {
+	copied := *ticket
+	m.Tickets[ticket.ProductID+"/"+ticket.UserID] = &copied
+	return nil
+}
+
+// CountAhead counts waiting tickets for the same product that joined earlier
+func (m *MockWaitingRoomRepository) CountAhead(ctx context.Context, ticket *services.WaitingRoomTicket) (int64, error) {
+	var count int64
+	for _, other := range m.Tickets {
+		if other.ProductID == ticket.ProductID && other.Status == services.WaitingRoomWaiting && other.JoinedAt.Before(ticket.JoinedAt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// AdmitNext admits the product's n longest-waiting tickets
+func (m *MockWaitingRoomRepository) AdmitNext(ctx context.Context, productID string, n int, admittedAt, expiresAt time.Time) (int64, error) {
+	waiting := make([]*services.WaitingRoomTicket, 0)
+	for _, ticket := range m.Tickets {
+		if ticket.ProductID == productID && ticket.Status == services.WaitingRoomWaiting {
+			waiting = append(waiting, ticket)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].JoinedAt.Before(waiting[j].JoinedAt) })
+	if len(waiting) > n {
+		waiting = waiting[:n]
+	}
+
+	for _, ticket := range waiting {
+		admitted, expires := admittedAt, expiresAt
+		ticket.Status = services.WaitingRoomAdmitted
+		ticket.AdmittedAt = &admitted
+		ticket.ExpiresAt = &expires
+	}
+	return int64(len(waiting)), nil
+}
+
+// ExpireAdmitted marks admitted tickets past their expiry as expired
+func (m *MockWaitingRoomRepository) ExpireAdmitted(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	for _, ticket := range m.Tickets {
+		if ticket.Status == services.WaitingRoomAdmitted && ticket.ExpiresAt != nil && !now.Before(*ticket.ExpiresAt) {
+			ticket.Status = services.WaitingRoomExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,89 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockWebhookEventRepository is a mock implementation of services.WebhookEventRepository
+type MockWebhookEventRepository struct {
+	mu     sync.Mutex
+	Events map[string]*services.WebhookEvent
+
+	// Error injection
+	SaveError error
+}
+
+// NewMockWebhookEventRepository creates a new mock webhook event repository
+func NewMockWebhookEventRepository() *MockWebhookEventRepository {
+	return &MockWebhookEventRepository{
+		Events: make(map[string]*services.WebhookEvent),
+	}
+}
+
+// FindByID finds a webhook event by ID
+func (m *MockWebhookEventRepository) FindByID(ctx context.Context, id string) (*services.WebhookEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	event, ok := m.Events[id]
+	if !ok {
+		return nil, services.ErrWebhookEventNotFound
+	}
+	copied := *event
+	return &copied, nil
+}
+
+// FindByExternalID finds a webhook event by the provider's event ID
+func (m *MockWebhookEventRepository) FindByExternalID(ctx context.Context, provider, externalID string) (*services.WebhookEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, event := range m.Events {
+		if event.Provider == provider && event.ExternalID == externalID {
+			copied := *event
+			return &copied, nil
+		}
+	}
+	return nil, services.ErrWebhookEventNotFound
+}
+
+// List lists webhook events matching the filter, newest first
+func (m *MockWebhookEventRepository) List(ctx context.Context, filter services.WebhookEventFilter) ([]*services.WebhookEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*services.WebhookEvent, 0)
+	for _, event := range m.Events {
+		if filter.Provider != "" && event.Provider != filter.Provider {
+			continue
+		}
+		if filter.Status != "" && event.Status != filter.Status {
+			continue
+		}
+		copied := *event
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ReceivedAt.After(result[j].ReceivedAt)
+	})
+	return result, nil
+}
+
+// Count counts webhook events matching the filter
+func (m *MockWebhookEventRepository) Count(ctx context.Context, filter services.WebhookEventFilter) (int64, error) {
+	list, _ := m.List(ctx, filter)
+	return int64(len(list)), nil
+}
+
+// Save saves a webhook event
+func (m *MockWebhookEventRepository) Save(ctx context.Context, event *services.WebhookEvent) error {
+	if m.SaveError != nil {
+		return m.SaveError
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *event
+	m.Events[event.ID] = &copied
+	return nil
+}
@@ -0,0 +1,149 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// MockWishlistRepository is a mock implementation of services.WishlistRepository
+type MockWishlistRepository struct {
+	Wishlists map[string]*services.Wishlist
+	Items     map[string]*services.WishlistItem
+	Purchases []*services.WishlistPurchase
+}
+
+// NewMockWishlistRepository creates a new mock wishlist repository
+func NewMockWishlistRepository() *MockWishlistRepository {
+	return &MockWishlistRepository{
+		Wishlists: make(map[string]*services.Wishlist),
+		Items:     make(map[string]*services.WishlistItem),
+	}
+}
+
+// FindByID returns a copy of a wishlist with its items
+func (m *MockWishlistRepository) FindByID(ctx context.Context, id string) (*services.Wishlist, error) {
+	wishlist, ok := m.Wishlists[id]
+	if !ok {
+		return nil, services.ErrWishlistNotFound
+	}
+	return m.withItems(wishlist), nil
+}
+
+// FindByUserID returns copies of a user's wishlists, newest first
+func (m *MockWishlistRepository) FindByUserID(ctx context.Context, userID string) ([]*services.Wishlist, error) {
+	result := make([]*services.Wishlist, 0)
+	for _, wishlist := range m.Wishlists {
+		if wishlist.UserID == userID {
+			result = append(result, m.withItems(wishlist))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindByShareToken returns a copy of the wishlist shared by the token
+func (m *MockWishlistRepository) FindByShareToken(ctx context.Context, token string) (*services.Wishlist, error) {
+	for _, wishlist := range m.Wishlists {
+		if token != "" && wishlist.ShareToken == token {
+			return m.withItems(wishlist), nil
+		}
+	}
+	return nil, services.ErrWishlistNotFound
+}
+
+// Save stores a wishlist without its items
+func (m *MockWishlistRepository) Save(ctx context.Context, wishlist *services.Wishlist) error {
+	saved := *wishlist
+	saved.Items = nil
+	m.Wishlists[wishlist.ID] = &saved
+	return nil
+}
+
+// Delete removes a wishlist and its items
+func (m *MockWishlistRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := m.Wishlists[id]; !ok {
+		return services.ErrWishlistNotFound
+	}
+	delete(m.Wishlists, id)
+	for itemID, item := range m.Items {
+		if item.WishlistID == id {
+			delete(m.Items, itemID)
+		}
+	}
+	return nil
+}
+
+// FindItem returns a copy of a wishlist item
+func (m *MockWishlistRepository) FindItem(ctx context.Context, id string) (*services.WishlistItem, error) {
+	item, ok := m.Items[id]
+	if !ok {
+		return nil, services.ErrWishlistItemNotFound
+	}
+	found := *item
+	return &found, nil
+}
+
+// SaveItem stores an item, keeping the units purchased of an existing one
+func (m *MockWishlistRepository) SaveItem(ctx context.Context, item *services.WishlistItem) error {
+	saved := *item
+	if existing, ok := m.Items[item.ID]; ok {
+		saved.Purchased = existing.Purchased
+	}
+	m.Items[item.ID] = &saved
+	return nil
+}
+
+// DeleteItem removes a wishlist item
+func (m *MockWishlistRepository) DeleteItem(ctx context.Context, id string) error {
+	if _, ok := m.Items[id]; !ok {
+		return services.ErrWishlistItemNotFound
+	}
+	delete(m.Items, id)
+	return nil
+}
+
+// Purchase marks the units bought unless fewer are still wanted, and records the purchase
+func (m *MockWishlistRepository) Purchase(ctx context.Context, purchase *services.WishlistPurchase) error {
+	item, ok := m.Items[purchase.ItemID]
+	if !ok || item.Purchased+purchase.Quantity > item.Quantity {
+		return services.ErrRegistryItemPurchased
+	}
+	item.Purchased += purchase.Quantity
+	recorded := *purchase
+	m.Purchases = append(m.Purchases, &recorded)
+	return nil
+}
+
+// CancelOrderPurchases gives back the units an order bought and marks its purchases canceled
+func (m *MockWishlistRepository) CancelOrderPurchases(ctx context.Context, orderID string, at time.Time) error {
+	for _, purchase := range m.Purchases {
+		if purchase.OrderID != orderID || purchase.Status != services.WishlistPurchaseBought {
+			continue
+		}
+		if item, ok := m.Items[purchase.ItemID]; ok {
+			item.Purchased -= purchase.Quantity
+			if item.Purchased < 0 {
+				item.Purchased = 0
+			}
+		}
+		canceledAt := at
+		purchase.Status = services.WishlistPurchaseCanceled
+		purchase.CanceledAt = &canceledAt
+	}
+	return nil
+}
+
+func (m *MockWishlistRepository) withItems(wishlist *services.Wishlist) *services.Wishlist {
+	found := *wishlist
+	found.Items = make([]services.WishlistItem, 0)
+	for _, item := range m.Items {
+		if item.WishlistID == wishlist.ID {
+			found.Items = append(found.Items, *item)
+		}
+	}
+	sort.Slice(found.Items, func(i, j int) bool { return found.Items[i].AddedAt.Before(found.Items[j].AddedAt) })
+	return &found
+}
@@ -0,0 +1,31 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+func TestQueryLatency_Percentiles(t *testing.T) {
+	latency := database.NewQueryLatency(100)
+	if p := latency.Percentiles(); p.Samples != 0 || p.P99 != 0 {
+		t.Errorf("expected zero percentiles before any statement, got %+v", p)
+	}
+
+	for i := 1; i <= 100; i++ {
+		latency.Observe(time.Duration(i) * time.Millisecond)
+	}
+	p := latency.Percentiles()
+	if p.Samples != 100 || p.P50 != 51*time.Millisecond || p.P95 != 95*time.Millisecond || p.P99 != 99*time.Millisecond || p.Max != 100*time.Millisecond {
+		t.Errorf("unexpected percentiles %+v", p)
+	}
+
+	// Newer statements push the oldest out of the window
+	for i := 0; i < 100; i++ {
+		latency.Observe(2 * time.Millisecond)
+	}
+	if p := latency.Percentiles(); p.Samples != 100 || p.Max != 2*time.Millisecond {
+		t.Errorf("expected only the last window to count, got %+v", p)
+	}
+}
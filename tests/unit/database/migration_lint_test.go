@@ -0,0 +1,129 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/migrations"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+)
+
+type lintProduct struct {
+	ID    string `gorm:"primaryKey"`
+	Name  string
+	Title string
+}
+
+func (lintProduct) TableName() string { return "products" }
+
+func rowsOf(estimates map[string]int64) database.TableRowsFunc {
+	return func(ctx context.Context, table string) (int64, error) {
+		return estimates[table], nil
+	}
+}
+
+func sqlMigration(version, statements string) migrations.Migration {
+	return migrations.Migration{
+		Version: version,
+		Name:    "test_" + version,
+		Up: func(ctx context.Context, exec migrations.Executor) error {
+			return exec.Exec(ctx, statements)
+		},
+	}
+}
+
+func lint(t *testing.T, rows map[string]int64, pending ...migrations.Migration) []database.LintViolation {
+	t.Helper()
+	linter, err := database.NewMigrationLinter([]interface{}{&lintProduct{}}, rowsOf(rows))
+	if err != nil {
+		t.Fatalf("failed to create linter: %v", err)
+	}
+	err = linter.Lint(context.Background(), pending)
+	if err == nil {
+		return nil
+	}
+	var lintErr *database.MigrationLintError
+	if !errors.As(err, &lintErr) {
+		t.Fatalf("expected a MigrationLintError, got %v", err)
+	}
+	return lintErr.Violations
+}
+
+func assertRules(t *testing.T, violations []database.LintViolation, rules ...string) {
+	t.Helper()
+	if len(violations) != len(rules) {
+		t.Fatalf("expected %d violation(s), got %+v", len(rules), violations)
+	}
+	for i, rule := range rules {
+		if violations[i].Rule != rule {
+			t.Errorf("violation %d: expected rule %s, got %s (%s)", i, rule, violations[i].Rule, violations[i].Reason)
+		}
+	}
+}
+
+func TestMigrationLinter_DropInUse(t *testing.T) {
+	violations := lint(t, nil, sqlMigration("950", `
+		ALTER TABLE products DROP COLUMN name;
+		ALTER TABLE products DROP COLUMN legacy_code, DROP CONSTRAINT products_sku_key;
+		DROP TABLE IF EXISTS products;
+	`))
+	assertRules(t, violations, database.LintDropInUse, database.LintDropInUse)
+	if violations[0].Version != "950" {
+		t.Errorf("expected the violation to name migration 950, got %q", violations[0].Version)
+	}
+}
+
+func TestMigrationLinter_LargeTableLocks(t *testing.T) {
+	pending := sqlMigration("951", `
+		CREATE INDEX idx_products_name ON products(name);
+		CREATE INDEX CONCURRENTLY idx_products_title ON products(title);
+		ALTER TABLE products ALTER COLUMN name TYPE text;
+		ALTER TABLE products ADD COLUMN sku varchar(64) NOT NULL, ADD CONSTRAINT products_sku_key UNIQUE (sku);
+		ALTER TABLE products ADD COLUMN status varchar(20) NOT NULL DEFAULT 'active';
+	`)
+
+	// Locks on an empty or small table are over before checkout notices
+	if violations := lint(t, map[string]int64{"products": 500}, pending); len(violations) != 0 {
+		t.Fatalf("expected a small table to pass, got %+v", violations)
+	}
+
+	violations := lint(t, map[string]int64{"products": 2000000}, pending)
+	assertRules(t, violations, database.LintIndexNotConcurrent, database.LintColumnTypeChange, database.LintAddRequiredColumn)
+}
+
+func TestMigrationLinter_AllowMarker(t *testing.T) {
+	violations := lint(t, map[string]int64{"products": 2000000}, sqlMigration("952", `
+		-- lint:allow index-not-concurrent built during the maintenance window
+		CREATE INDEX idx_products_name ON products(name);
+		-- lint:allow set-not-null
+		ALTER TABLE products ALTER COLUMN title SET NOT NULL;
+		ALTER TABLE products ALTER COLUMN name SET NOT NULL;
+	`))
+	assertRules(t, violations, database.LintSetNotNull)
+}
+
+func TestMigrationLinter_TwoPhaseRename(t *testing.T) {
+	change := database.RenameColumn("953", "954", "products", "title", "headline", "varchar(255)")
+
+	if violations := lint(t, nil, change.Expand); len(violations) != 0 {
+		t.Fatalf("expected the expand phase to pass, got %+v", violations)
+	}
+
+	// The model still maps title, so dropping it would break the code being replaced
+	assertRules(t, lint(t, nil, change.Contract), database.LintDropInUse)
+
+	if violations := lint(t, nil, sqlMigration("955", `ALTER TABLE products RENAME COLUMN title TO headline`)); len(violations) != 1 || violations[0].Rule != database.LintRenameInUse {
+		t.Errorf("expected an in-place rename to be rejected, got %+v", violations)
+	}
+}
+
+func TestCreateIndexConcurrently_PassesOnLargeTable(t *testing.T) {
+	migration := database.CreateIndexConcurrently("956", "idx_products_title", "products", "title")
+	unique := database.CreateUniqueIndexConcurrently("957", "idx_products_name", "products", "name")
+	trigram := database.CreateTrigramIndexConcurrently("958", "idx_products_name_trgm", "products", "name")
+	if violations := lint(t, map[string]int64{"products": 2000000}, migration, unique, trigram); len(violations) != 0 {
+		t.Errorf("expected a concurrent index build to pass, got %+v", violations)
+	}
+}
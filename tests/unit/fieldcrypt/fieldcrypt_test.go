@@ -0,0 +1,136 @@
+package fieldcrypt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/fieldcrypt"
+)
+
+func newKey(id string, fill byte) fieldcrypt.Key {
+	return fieldcrypt.Key{ID: id, Secret: bytes.Repeat([]byte{fill}, 32)}
+}
+
+func newCipher(t *testing.T, keys ...fieldcrypt.Key) *fieldcrypt.Cipher {
+	t.Helper()
+	c, err := fieldcrypt.New(keys...)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	return c
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := newCipher(t, newKey("k1", 1))
+	plaintext := "221B Baker Street, +44 20 7946 0958"
+
+	sealed, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !fieldcrypt.IsEncrypted(sealed) || strings.Contains(sealed, "Baker") {
+		t.Fatalf("Expected an encrypted value, got %q", sealed)
+	}
+
+	again, _ := c.Encrypt(plaintext)
+	if again == sealed {
+		t.Error("Expected each encryption to use a fresh data key and nonce")
+	}
+
+	opened, err := c.Decrypt(sealed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opened != plaintext {
+		t.Errorf("Expected %q, got %q", plaintext, opened)
+	}
+}
+
+func TestCipher_PlaintextPassesThrough(t *testing.T) {
+	c := newCipher(t, newKey("k1", 1))
+
+	if sealed, _ := c.Encrypt(""); sealed != "" {
+		t.Errorf("Expected empty values to stay empty, got %q", sealed)
+	}
+	if opened, err := c.Decrypt("leave at the door"); err != nil || opened != "leave at the door" {
+		t.Errorf("Expected rows written before encryption to read as they are, got %q, %v", opened, err)
+	}
+
+	// Without keys values are stored as plaintext, but encrypted ones can't be read
+	var none *fieldcrypt.Cipher
+	if sealed, _ := none.Encrypt("note"); sealed != "note" {
+		t.Errorf("Expected a nil cipher to store plaintext, got %q", sealed)
+	}
+	sealed, _ := c.Encrypt("note")
+	if _, err := none.Decrypt(sealed); err != fieldcrypt.ErrNoKeys {
+		t.Errorf("Expected ErrNoKeys, got %v", err)
+	}
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	oldCipher := newCipher(t, newKey("k1", 1))
+	sealed, _ := oldCipher.Encrypt("secret note")
+
+	// k2 becomes primary; k1 is kept to read older values
+	c := newCipher(t, newKey("k2", 2), newKey("k1", 1))
+	if !c.NeedsRotation(sealed) || !c.NeedsRotation("plaintext") {
+		t.Fatal("Expected values under a retired key and plaintext to need rotation")
+	}
+
+	rotated, err := c.Rotate(sealed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.NeedsRotation(rotated) {
+		t.Error("Expected the rotated value to be under the primary key")
+	}
+	if sealed[strings.LastIndex(sealed, ":"):] != rotated[strings.LastIndex(rotated, ":"):] {
+		t.Error("Expected rotation to rewrap the data key without re-encrypting the data")
+	}
+
+	// Once k1 is dropped, only the rotated value can be read
+	current := newCipher(t, newKey("k2", 2))
+	if opened, err := current.Decrypt(rotated); err != nil || opened != "secret note" {
+		t.Errorf("Expected the rotated value to decrypt, got %q, %v", opened, err)
+	}
+	if _, err := current.Decrypt(sealed); err != fieldcrypt.ErrUnknownKey {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestCipher_RejectsTampering(t *testing.T) {
+	c := newCipher(t, newKey("k1", 1))
+	sealed, _ := c.Encrypt("secret note")
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "BB"
+	}
+	if _, err := c.Decrypt(tampered); err != fieldcrypt.ErrMalformed {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+	if _, err := c.Decrypt("enc:v1:k1:not-enough-parts"); err != fieldcrypt.ErrMalformed {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := fieldcrypt.ParseKeys([]string{"2026-10:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != "2026-10" || len(keys[0].Secret) != 32 {
+		t.Fatalf("Unexpected keys: %+v", keys)
+	}
+
+	if _, err := fieldcrypt.ParseKeys([]string{"no-secret"}); err == nil {
+		t.Error("Expected an error for a key without a secret")
+	}
+	if _, err := fieldcrypt.New(fieldcrypt.Key{ID: "short", Secret: []byte("too short")}); err == nil {
+		t.Error("Expected an error for a key that isn't 32 bytes")
+	}
+	if _, err := fieldcrypt.New(newKey("k1", 1), newKey("k1", 2)); err == nil {
+		t.Error("Expected an error for duplicate key IDs")
+	}
+}
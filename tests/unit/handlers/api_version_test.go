@@ -0,0 +1,136 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// setupVersionedRouter mounts the order routes under /api/v1 and /api/v2 with version
+// negotiation, as server.go does, with the pending order fixture stored
+func setupVersionedRouter(t *testing.T) *helpers.HTTPTestContext {
+	t.Helper()
+	orderRepo := mocks.NewMockOrderRepository()
+	order := fixtures.OrderPending()
+	orderRepo.Orders[order.ID] = order
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), mocks.NewMockProductRepository(), mocks.NewMockVariantRepository(), nil)
+	orderHandler := handlers.NewOrderHandler(orderService, cartService)
+
+	ctx := helpers.NewHTTPTestContext()
+	auth := ctx.Auth.Middleware()
+	for path, version := range map[string]int{"/api/v1": middleware.APIVersion1, "/api/v2": middleware.APIVersion2} {
+		orders := ctx.Router.Group(path, middleware.APIVersion(version)).Group("/orders", auth.Authenticate())
+		orders.GET("", orderHandler.ListOrders)
+		orders.GET("/:id", orderHandler.GetOrder)
+	}
+	return ctx.AsUser(t, order.UserID)
+}
+
+// versionedOrder holds fields of both order shapes; only one shape's fields are set
+type versionedOrder struct {
+	// v1
+	OrderNumber string
+	Total       struct{ Amount int64 }
+
+	// v2
+	V2Number string `json:"order_number"`
+	Items    []struct {
+		UnitPrice handlers.MoneyV2 `json:"unit_price"`
+	} `json:"items"`
+	ShippingAddress struct {
+		Address1 string `json:"address1"`
+	} `json:"shipping_address"`
+	Totals struct {
+		Total    handlers.MoneyV2 `json:"total"`
+		Shipping handlers.MoneyV2 `json:"shipping"`
+	} `json:"totals"`
+}
+
+func TestAPIVersion_PathDefaultAndHeaderOverride(t *testing.T) {
+	owner := setupVersionedRouter(t)
+	path := "/orders/" + fixtures.OrderPending().ID
+
+	tests := []struct {
+		name     string
+		client   *helpers.HTTPTestContext
+		path     string
+		expected string
+	}{
+		{"v1 path", owner, "/api/v1" + path, "1"},
+		{"v2 path", owner, "/api/v2" + path, "2"},
+		{"v1 path opting into v2", owner.WithHeader(middleware.APIVersionHeader, "v2"), "/api/v1" + path, "2"},
+		{"v2 path asking for v1", owner.WithHeader(middleware.APIVersionHeader, "1"), "/api/v2" + path, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := tt.client.GET(tt.path)
+			helpers.AssertStatus(t, rec, http.StatusOK)
+			if got := rec.Header().Get(middleware.APIVersionHeader); got != tt.expected {
+				t.Errorf("expected API-Version %s echoed, got %q", tt.expected, got)
+			}
+
+			var body struct {
+				Data versionedOrder `json:"data"`
+			}
+			helpers.ParseResponse(t, rec, &body)
+			if tt.expected == "1" && (body.Data.OrderNumber == "" || body.Data.Total.Amount != 109749 || body.Data.Totals.Total.Amount != 0) {
+				t.Errorf("expected the v1 order shape, got %+v", body.Data)
+			}
+			if tt.expected == "2" && (body.Data.V2Number == "" || body.Data.Totals.Total.Amount != 109749) {
+				t.Errorf("expected the v2 order shape, got %+v", body.Data)
+			}
+		})
+	}
+}
+
+func TestAPIVersion_Unsupported(t *testing.T) {
+	owner := setupVersionedRouter(t)
+
+	for _, version := range []string{"3", "0", "latest"} {
+		rec := owner.WithHeader(middleware.APIVersionHeader, version).GET("/api/v1/orders")
+		helpers.AssertStatus(t, rec, http.StatusBadRequest)
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		helpers.ParseResponse(t, rec, &body)
+		if body.Error.Code != "unsupported_api_version" {
+			t.Errorf("API-Version %q: expected unsupported_api_version, got %q", version, body.Error.Code)
+		}
+	}
+}
+
+func TestOrderV2_ResponseShape(t *testing.T) {
+	owner := setupVersionedRouter(t)
+
+	var single struct {
+		Data versionedOrder `json:"data"`
+	}
+	helpers.ParseResponse(t, owner.GET("/api/v2/orders/"+fixtures.OrderPending().ID), &single)
+	order := single.Data
+	if order.V2Number != "ORD-2024-00001" || order.ShippingAddress.Address1 != fixtures.TestShippingAddress.AddressLine1 {
+		t.Errorf("expected snake_case order fields and addresses, got %+v", order)
+	}
+	if order.Totals.Total != (handlers.MoneyV2{Amount: 109749, Currency: "USD"}) || order.Totals.Shipping.Amount != 1000 {
+		t.Errorf("expected amounts nested under totals, got %+v", order.Totals)
+	}
+	if len(order.Items) != 1 || order.Items[0].UnitPrice != (handlers.MoneyV2{Amount: 99999, Currency: "USD"}) {
+		t.Errorf("expected itemized line pricing, got %+v", order.Items)
+	}
+
+	var list struct {
+		Data []versionedOrder `json:"data"`
+	}
+	helpers.ParseResponse(t, owner.GET("/api/v2/orders"), &list)
+	if len(list.Data) != 1 || list.Data[0].Totals.Total.Amount != 109749 || list.Data[0].OrderNumber != "" {
+		t.Errorf("expected the listing in the v2 shape, got %+v", list.Data)
+	}
+}
@@ -97,6 +97,52 @@ func TestCatalogHandler_ListProducts(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "list products with sparse fieldset",
+			queryParams: "?fields=id,name,base_price",
+			setupMock: func(repo *mocks.MockProductRepository) {
+				repo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response struct {
+					Data []map[string]interface{} `json:"data"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+					t.Fatalf("failed to parse response: %v", err)
+				}
+				if len(response.Data) != 1 {
+					t.Fatalf("expected 1 product, got %d", len(response.Data))
+				}
+				product := response.Data[0]
+				if len(product) != 3 || product["ID"] == nil || product["Name"] == nil || product["BasePrice"] == nil {
+					t.Errorf("expected only ID, Name and BasePrice, got %v", product)
+				}
+			},
+		},
+		{
+			name:           "list products with unknown field",
+			queryParams:    "?fields=id,cost_price",
+			setupMock:      func(repo *mocks.MockProductRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse:  func(t *testing.T, rec *httptest.ResponseRecorder) {},
+		},
+		{
+			name:        "list products sorted",
+			queryParams: "?sort=-price,name",
+			setupMock: func(repo *mocks.MockProductRepository) {
+				repo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse:  func(t *testing.T, rec *httptest.ResponseRecorder) {},
+		},
+		{
+			name:           "list products sorted by unknown field",
+			queryParams:    "?sort=cost_price",
+			setupMock:      func(repo *mocks.MockProductRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse:  func(t *testing.T, rec *httptest.ResponseRecorder) {},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,39 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	httpserver "github.com/devchuckcamp/gocommerce-api/internal/http"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+)
+
+func TestDiagnosticsRouter_AdminOnly(t *testing.T) {
+	ctx := helpers.NewHTTPTestContext()
+	ctx.Router = httpserver.NewDiagnosticsRouter(ctx.Auth.Middleware())
+
+	for _, path := range []string{"/debug/vars", "/debug/pprof/", "/debug/pprof/heap"} {
+		helpers.AssertStatus(t, ctx.GET(path), http.StatusUnauthorized)
+		helpers.AssertStatus(t, ctx.AsUser(t, fixtures.TestUserID).GET(path), http.StatusForbidden)
+	}
+}
+
+func TestDiagnosticsRouter_ServesProfilesAndVars(t *testing.T) {
+	ctx := helpers.NewHTTPTestContext()
+	ctx.Router = httpserver.NewDiagnosticsRouter(ctx.Auth.Middleware())
+	admin := ctx.AsAdmin(t, "admin-1")
+
+	rec := admin.GET("/debug/vars")
+	helpers.AssertStatus(t, rec, http.StatusOK)
+	for _, name := range []string{`"memstats"`, `"goroutines"`, `"uptime_seconds"`} {
+		if !strings.Contains(rec.Body.String(), name) {
+			t.Errorf("expected /debug/vars to publish %s", name)
+		}
+	}
+
+	helpers.AssertStatus(t, admin.GET("/debug/pprof/"), http.StatusOK)
+	helpers.AssertStatus(t, admin.GET("/debug/pprof/goroutine?debug=1"), http.StatusOK)
+	helpers.AssertStatus(t, admin.GET("/debug/pprof/not-a-profile"), http.StatusNotFound)
+}
@@ -0,0 +1,145 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+)
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details struct {
+			Fields []response.FieldError `json:"fields"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+type validatedAddress struct {
+	City    string `json:"city" binding:"required"`
+	Country string `json:"country" binding:"required,len=2"`
+}
+
+type validatedRequest struct {
+	Email    string           `json:"email" binding:"required,email"`
+	Quantity int              `json:"quantity" binding:"gt=0"`
+	Status   string           `json:"status" binding:"omitempty,oneof=open closed"`
+	Address  validatedAddress `json:"address"`
+}
+
+func serveError(t *testing.T, err error) (int, errorBody) {
+	t.Helper()
+	handlers.RegisterErrors()
+
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) { response.FromError(c, err) })
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var body errorBody
+	if jsonErr := json.Unmarshal(rec.Body.Bytes(), &body); jsonErr != nil {
+		t.Fatalf("failed to parse response: %v", jsonErr)
+	}
+	return rec.Code, body
+}
+
+func TestErrorResponse_MapsDomainErrors(t *testing.T) {
+	code, body := serveError(t, fmt.Errorf("loading order ord-1: %w", orders.ErrOrderNotFound))
+	if code != http.StatusNotFound || body.Error.Code != "order_not_found" {
+		t.Errorf("expected 404 order_not_found for a wrapped domain error, got %d %q", code, body.Error.Code)
+	}
+	if body.Error.Message != "loading order ord-1: order not found" {
+		t.Errorf("expected the error's own message, got %q", body.Error.Message)
+	}
+
+	code, body = serveError(t, errors.New("pq: relation \"orders\" does not exist"))
+	if code != http.StatusInternalServerError || body.Error.Code != "internal_server_error" {
+		t.Errorf("expected 500 internal_server_error for an unknown error, got %d %q", code, body.Error.Code)
+	}
+	if strings.Contains(body.Error.Message, "pq:") {
+		t.Errorf("expected the internal error text kept from the client, got %q", body.Error.Message)
+	}
+}
+
+func TestErrorResponse_ValidationDetails(t *testing.T) {
+	router := gin.New()
+	router.POST("/", func(c *gin.Context) {
+		var req validatedRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.InvalidRequest(c, err)
+			return
+		}
+		response.Success(c, req)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		fields map[string]string
+	}{
+		{
+			name: "field rules",
+			body: `{"email":"not-an-email","quantity":0,"status":"lost","address":{"country":"USA"}}`,
+			code: "validation_failed",
+			fields: map[string]string{
+				"email":           "email",
+				"quantity":        "gt",
+				"status":          "oneof",
+				"address.city":    "required",
+				"address.country": "len",
+			},
+		},
+		{
+			name:   "wrong type",
+			body:   `{"email":"a@example.com","quantity":"two"}`,
+			code:   "validation_failed",
+			fields: map[string]string{"quantity": "type"},
+		},
+		{
+			name: "malformed JSON",
+			body: `{"email":`,
+			code: "bad_request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d. Body: %s", rec.Code, rec.Body.String())
+			}
+			var body errorBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if body.Error.Code != tt.code {
+				t.Errorf("expected code %q, got %q", tt.code, body.Error.Code)
+			}
+
+			got := make(map[string]string)
+			for _, field := range body.Error.Details.Fields {
+				got[field.Field] = field.Rule
+				if !strings.HasPrefix(field.Message, field.Field+" ") {
+					t.Errorf("expected message to name the field, got %q", field.Message)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.fields) && !(len(got) == 0 && len(tt.fields) == 0) {
+				t.Errorf("expected fields %v, got %v", tt.fields, got)
+			}
+		})
+	}
+}
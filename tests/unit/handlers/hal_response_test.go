@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type halLinks map[string]struct {
+	Href string `json:"href"`
+}
+
+type halBody struct {
+	Links    halLinks                            `json:"_links"`
+	Embedded map[string][]map[string]interface{} `json:"_embedded"`
+	Data     interface{}                         `json:"data"`
+}
+
+func setupHALTestRouter() http.Handler {
+	handlers.RegisterHALSerializers()
+
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+
+	categoryRepo := mocks.NewMockCategoryRepository()
+	categoryRepo.Categories[fixtures.CategoryElectronics.ID] = fixtures.CategoryElectronics
+	categoryRepo.Categories[fixtures.CategoryClothing.ID] = fixtures.CategoryClothing
+	categoryRepo.Categories[fixtures.CategoryBooks.ID] = fixtures.CategoryBooks
+
+	catalogService := services.NewCatalogService(productRepo, mocks.NewMockVariantRepository(), categoryRepo, mocks.NewMockBrandRepository())
+	return setupCatalogTestRouter(handlers.NewCatalogHandler(catalogService))
+}
+
+func TestHALResponse_ListCategories(t *testing.T) {
+	router := setupHALTestRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/catalog/categories?page=2&page_size=1", nil)
+	req.Header.Set("Accept", "application/hal+json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != response.HALMediaType+"; charset=utf-8" && contentType != response.HALMediaType {
+		t.Errorf("expected HAL content type, got %q", contentType)
+	}
+
+	var body halBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	expected := map[string]string{
+		"self":  "/catalog/categories?page=2&page_size=1",
+		"first": "/catalog/categories?page=1&page_size=1",
+		"prev":  "/catalog/categories?page=1&page_size=1",
+		"next":  "/catalog/categories?page=3&page_size=1",
+		"last":  "/catalog/categories?page=3&page_size=1",
+	}
+	for rel, href := range expected {
+		if body.Links[rel].Href != href {
+			t.Errorf("expected %s link %q, got %q", rel, href, body.Links[rel].Href)
+		}
+	}
+
+	categories := body.Embedded["categories"]
+	if len(categories) != 1 {
+		t.Fatalf("expected 1 embedded category, got %d", len(categories))
+	}
+	links, ok := categories[0]["_links"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected embedded category to carry _links")
+	}
+	self := links["self"].(map[string]interface{})["href"]
+	if self != "/api/v1/catalog/products/category/"+categories[0]["ID"].(string) {
+		t.Errorf("unexpected self link %v", self)
+	}
+}
+
+func TestHALResponse_DefaultsToJSON(t *testing.T) {
+	router := setupHALTestRouter()
+
+	req, _ := http.NewRequest(http.MethodGet, "/catalog/products/"+fixtures.ProductLaptop.ID, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body halBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if body.Data == nil || body.Links != nil {
+		t.Errorf("expected the standard data envelope without HAL links, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/response"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type versionedPage struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// setupIfMatchRouter serves one page the way the admin handlers do: GET returns its ETag
+// and PUT checks If-Match before renaming it
+func setupIfMatchRouter(page *versionedPage) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/pages/:id", func(c *gin.Context) {
+		response.SuccessWithETag(c, page)
+	})
+	router.PUT("/pages/:id", func(c *gin.Context) {
+		if !response.IfMatch(c, page) {
+			return
+		}
+		page.Title = c.Query("title")
+		response.SuccessWithETag(c, page)
+	})
+	return router
+}
+
+func putPage(router http.Handler, title, ifMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPut, "/pages/1?title="+title, nil)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIfMatch_PreventsLostUpdates(t *testing.T) {
+	page := &versionedPage{ID: "1", Title: "About"}
+	router := setupIfMatchRouter(page)
+
+	req, _ := http.NewRequest(http.MethodGet, "/pages/1", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	etag := rec.Header().Get("ETag")
+	if etag == "" || etag != response.ETag(page) {
+		t.Fatalf("expected GET to return the page's ETag, got %q", etag)
+	}
+
+	// The first editor saves with the ETag they read
+	rec = putPage(router, "About us", etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+	newETag := rec.Header().Get("ETag")
+	if newETag == "" || newETag == etag {
+		t.Fatalf("expected a new ETag after the update, got %q", newETag)
+	}
+
+	// The second editor still holds the old ETag and must not overwrite the change
+	rec = putPage(router, "Company", etag)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "precondition_failed") {
+		t.Errorf("expected precondition_failed code, got %s", rec.Body.String())
+	}
+	if rec.Header().Get("ETag") != newETag {
+		t.Errorf("expected the 412 to carry the current ETag %q, got %q", newETag, rec.Header().Get("ETag"))
+	}
+	if page.Title != "About us" {
+		t.Errorf("expected the stale update to be rejected, title is %q", page.Title)
+	}
+}
+
+func TestIfMatch_OptionalAndWildcard(t *testing.T) {
+	page := &versionedPage{ID: "1", Title: "About"}
+	router := setupIfMatchRouter(page)
+
+	tests := []struct {
+		name    string
+		ifMatch string
+	}{
+		{"no header", ""},
+		{"wildcard", "*"},
+		{"one of several tags", `"stale", ` + response.ETag(page)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := putPage(router, page.Title, tt.ifMatch)
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalendarHandler_UpdateCalendarIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calendarService := services.NewCalendarService(mocks.NewMockCalendarRepository())
+	h := handlers.NewCalendarHandler(calendarService)
+	router := gin.New()
+	router.GET("/admin/calendar", h.GetCalendar)
+	router.PUT("/admin/calendar", h.UpdateCalendar)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/calendar", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected GET to return the calendar's ETag")
+	}
+
+	// Another admin closes the business on a holiday after the calendar was read
+	if _, err := calendarService.AddHoliday(context.Background(), "2026-12-25", "Christmas Day"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := `{"timezone":"UTC","working_days":[1,2,3,4,5,6],"cutoff_time":"15:00"}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/calendar", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", etag)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412 for a stale ETag, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/admin/calendar", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with the current ETag, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("expected the updated calendar's ETag")
+	}
+}
@@ -0,0 +1,143 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/helpers"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// setupAuthenticatedRouter mounts cart and order routes behind the real AuthMiddleware,
+// as server.go does
+func setupAuthenticatedRouter() (*helpers.HTTPTestContext, *mocks.MockOrderRepository) {
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil)
+
+	orderRepo := mocks.NewMockOrderRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+
+	ctx := helpers.NewHTTPTestContext()
+	auth := ctx.Auth.Middleware()
+	cartHandler := handlers.NewCartHandler(cartService)
+	orderHandler := handlers.NewOrderHandler(orderService, cartService)
+
+	cart := ctx.Router.Group("/cart", auth.Authenticate())
+	cart.GET("", cartHandler.GetCart)
+	cart.POST("/items", cartHandler.AddItem)
+
+	orders := ctx.Router.Group("/orders", auth.Authenticate())
+	orders.GET("", orderHandler.ListOrders)
+	orders.GET("/:id", orderHandler.GetOrder)
+
+	return ctx, orderRepo
+}
+
+func TestCartHandler_RequiresAuthentication(t *testing.T) {
+	ctx, _ := setupAuthenticatedRouter()
+
+	helpers.AssertStatus(t, ctx.GET("/cart"), http.StatusUnauthorized)
+	helpers.AssertStatus(t, ctx.RequestWithAuth(http.MethodGet, "/cart", "not-a-jwt", nil), http.StatusUnauthorized)
+	helpers.AssertStatus(t, ctx.AsUser(t, fixtures.TestUserID).GET("/cart"), http.StatusOK)
+}
+
+func TestCartHandler_CartsArePerUser(t *testing.T) {
+	ctx, _ := setupAuthenticatedRouter()
+	owner := ctx.AsUser(t, fixtures.TestUserID)
+
+	rec := owner.POST("/cart/items", map[string]interface{}{
+		"product_id": fixtures.ProductLaptop.ID,
+		"quantity":   1,
+	})
+	helpers.AssertStatus(t, rec, http.StatusOK)
+
+	var cart struct {
+		Data struct {
+			UserID string
+			Items  []struct{ ProductID string }
+		} `json:"data"`
+	}
+	helpers.ParseResponse(t, owner.GET("/cart"), &cart)
+	if cart.Data.UserID != fixtures.TestUserID || len(cart.Data.Items) != 1 {
+		t.Errorf("expected the owner's cart with 1 item, got user %q with %d items", cart.Data.UserID, len(cart.Data.Items))
+	}
+
+	helpers.ParseResponse(t, ctx.AsUser(t, "user-002").GET("/cart"), &cart)
+	if len(cart.Data.Items) != 0 {
+		t.Errorf("expected another user's cart to be empty, got %d items", len(cart.Data.Items))
+	}
+}
+
+func TestOrderHandler_GetOrder_Access(t *testing.T) {
+	ctx, orderRepo := setupAuthenticatedRouter()
+	order := fixtures.OrderPending()
+	orderRepo.Orders[order.ID] = order
+
+	tests := []struct {
+		name           string
+		client         *helpers.HTTPTestContext
+		expectedStatus int
+	}{
+		{"anonymous", ctx, http.StatusUnauthorized},
+		{"owner", ctx.AsUser(t, order.UserID), http.StatusOK},
+		{"another customer", ctx.AsUser(t, "user-002"), http.StatusForbidden},
+		{"admin", ctx.AsAdmin(t, "admin-001"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helpers.AssertStatus(t, tt.client.GET("/orders/"+order.ID), tt.expectedStatus)
+		})
+	}
+}
+
+func TestOrderHandler_ListOrders_Total(t *testing.T) {
+	ctx, orderRepo := setupAuthenticatedRouter()
+	for i := 0; i < 25; i++ {
+		order := fixtures.OrderPending()
+		order.ID = fmt.Sprintf("order-%03d", i)
+		order.CreatedAt = time.Now().Add(-time.Duration(i) * time.Hour)
+		if i%5 == 0 {
+			order.Status = orders.OrderStatusCanceled
+		}
+		orderRepo.Orders[order.ID] = order
+	}
+	owner := ctx.AsUser(t, "user-001")
+
+	var list struct {
+		Data []struct{ ID string } `json:"data"`
+		Meta struct {
+			TotalItems int64 `json:"total_items"`
+			TotalPages int   `json:"total_pages"`
+			HasNext    bool  `json:"has_next"`
+		} `json:"meta"`
+	}
+	helpers.ParseResponse(t, owner.GET("/orders?page=1&page_size=10"), &list)
+	if len(list.Data) != 10 || list.Meta.TotalItems != 25 || list.Meta.TotalPages != 3 || !list.Meta.HasNext {
+		t.Errorf("expected 10 of 25 orders over 3 pages, got %d with %+v", len(list.Data), list.Meta)
+	}
+	helpers.ParseResponse(t, owner.GET("/orders?page=3&page_size=10"), &list)
+	if len(list.Data) != 5 || list.Meta.TotalItems != 25 || list.Meta.HasNext {
+		t.Errorf("expected the last 5 of 25 orders, got %d with %+v", len(list.Data), list.Meta)
+	}
+
+	helpers.ParseResponse(t, owner.GET("/orders?status=canceled&page_size=2"), &list)
+	if len(list.Data) != 2 || list.Meta.TotalItems != 5 {
+		t.Errorf("expected 2 of 5 canceled orders, got %d with %+v", len(list.Data), list.Meta)
+	}
+	helpers.ParseResponse(t, ctx.AsUser(t, "user-002").GET("/orders"), &list)
+	if list.Meta.TotalItems != 0 {
+		t.Errorf("expected no orders for another customer, got %+v", list.Meta)
+	}
+
+	helpers.AssertStatus(t, owner.GET("/orders?status=lost"), http.StatusBadRequest)
+	helpers.AssertStatus(t, owner.GET("/orders?from=yesterday"), http.StatusBadRequest)
+}
@@ -0,0 +1,220 @@
+package handlers_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	httpserver "github.com/devchuckcamp/gocommerce-api/internal/http"
+)
+
+// testCA signs the server and client certificates of a test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFiles writes PEM blocks to files in dir
+func writeFiles(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serveTLS serves a handler reporting the protocol with tlsConfig and returns its URL
+func serveTLS(t *testing.T, tlsConfig *tls.Config, http2 bool) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})}
+	httpserver.ConfigureServer(srv, tlsConfig, http2)
+	go func() { _ = srv.ServeTLS(listener, "", "") }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+// tlsClient trusts the CA and presents the client certificate, if any
+func tlsClient(ca *testCA, clientCert *tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	cfg := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		cfg.Certificates = []tls.Certificate{*clientCert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, ForceAttemptHTTP2: true}, Timeout: 5 * time.Second}
+}
+
+func TestNewTLSConfig_DisabledWithoutCertificates(t *testing.T) {
+	cfg, manager, err := httpserver.NewTLSConfig(httpserver.TLSOptions{HTTP2: true})
+	if err != nil || cfg != nil || manager != nil {
+		t.Errorf("Expected plain HTTP without certificates, got %v %v %v", cfg, manager, err)
+	}
+}
+
+func TestNewTLSConfig_ServesHTTP2(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "api", x509.ExtKeyUsageServerAuth)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"cert.pem": certPEM, "key.pem": keyPEM})
+
+	for _, http2 := range []bool{true, false} {
+		cfg, _, err := httpserver.NewTLSConfig(httpserver.TLSOptions{
+			CertFile:   filepath.Join(dir, "cert.pem"),
+			KeyFile:    filepath.Join(dir, "key.pem"),
+			MinVersion: "1.3",
+			HTTP2:      http2,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.MinVersion != tls.VersionTLS13 {
+			t.Errorf("Expected TLS 1.3 minimum, got %x", cfg.MinVersion)
+		}
+
+		resp, err := tlsClient(ca, nil).Get(serveTLS(t, cfg, http2))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if want := map[bool]int{true: 2, false: 1}[http2]; resp.ProtoMajor != want {
+			t.Errorf("With HTTP2=%t expected HTTP/%d, got %s", http2, want, resp.Proto)
+		}
+	}
+}
+
+func TestNewTLSConfig_RequiresClientCertificates(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "diagnostics", x509.ExtKeyUsageServerAuth)
+	clientCertPEM, clientKeyPEM := ca.issue(t, "operator", x509.ExtKeyUsageClientAuth)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"cert.pem": certPEM, "key.pem": keyPEM, "ca.pem": ca.pem})
+
+	cfg, _, err := httpserver.NewTLSConfig(httpserver.TLSOptions{
+		CertFile:     filepath.Join(dir, "cert.pem"),
+		KeyFile:      filepath.Join(dir, "key.pem"),
+		HTTP2:        true,
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	url := serveTLS(t, cfg, true)
+
+	if resp, err := tlsClient(ca, nil).Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("Expected a client without a certificate to be refused")
+	}
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tlsClient(ca, &clientCert).Get(url)
+	if err != nil {
+		t.Fatalf("Expected a client with a certificate signed by the CA to be served, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewTLSConfig_InvalidCertificate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string][]byte{"cert.pem": []byte("not a certificate"), "key.pem": []byte("not a key")})
+
+	_, _, err := httpserver.NewTLSConfig(httpserver.TLSOptions{
+		CertFile: filepath.Join(dir, "cert.pem"),
+		KeyFile:  filepath.Join(dir, "key.pem"),
+	})
+	if err == nil {
+		t.Error("Expected an unreadable certificate to fail")
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	handler := httpserver.RedirectToHTTPS(nil, "8443")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://shop.example.com/api/v1/products?page=2", nil))
+	if w.Code != http.StatusPermanentRedirect {
+		t.Fatalf("Expected 308, got %d", w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "https://shop.example.com:8443/api/v1/products?page=2" {
+		t.Errorf("Unexpected redirect %s", location)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "http://shop.example.com/api/v1/orders", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected writes over plain HTTP to be refused rather than redirected, got %d", w.Code)
+	}
+}
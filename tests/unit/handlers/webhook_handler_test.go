@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/handlers"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+	"github.com/devchuckcamp/gocommerce/money"
+)
+
+const testWebhookSecret = "whsec_test"
+
+func signWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler_HandleDisputeWebhook(t *testing.T) {
+	const disputeCreated = `{"id":"evt_1","type":"dispute.created","data":{"dispute_id":"dp_1","payment_intent_id":"pi_1","reason":"fraudulent","status":"needs_response"}}`
+	const unknownPayment = `{"id":"evt_2","type":"dispute.created","data":{"dispute_id":"dp_2","payment_intent_id":"pi_unknown","status":"needs_response"}}`
+
+	tests := []struct {
+		name           string
+		body           string
+		signature      string
+		expectedStatus int
+		expectDispute  bool
+	}{
+		{
+			name:           "valid signature links dispute to order",
+			body:           disputeCreated,
+			signature:      signWebhook(testWebhookSecret, disputeCreated),
+			expectedStatus: http.StatusOK,
+			expectDispute:  true,
+		},
+		{
+			name:           "missing signature is rejected",
+			body:           disputeCreated,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "signature from another secret is rejected",
+			body:           disputeCreated,
+			signature:      signWebhook("whsec_other", disputeCreated),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown payment is acknowledged without a dispute",
+			body:           unknownPayment,
+			signature:      signWebhook(testWebhookSecret, unknownPayment),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentRepo := mocks.NewMockPaymentRepository()
+			paymentRepo.Tenders["tender-1"] = &services.PaymentTender{
+				ID:               "tender-1",
+				OrderID:          "order-1",
+				GatewayReference: "pi_1",
+				CapturedAmount:   money.Money{Amount: 5000, Currency: "USD"},
+			}
+			disputeRepo := mocks.NewMockDisputeRepository()
+			handler := handlers.NewWebhookHandler(services.NewDisputeService(disputeRepo, paymentRepo), testWebhookSecret)
+
+			router := gin.New()
+			router.POST("/webhooks/payments/disputes", handler.HandleDisputeWebhook)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/payments/disputes", bytes.NewBufferString(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(handlers.WebhookSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			dispute, err := disputeRepo.FindByGatewayID(req.Context(), "dp_1")
+			if tt.expectDispute {
+				if err != nil {
+					t.Fatalf("expected dispute to be recorded: %v", err)
+				}
+				if dispute.OrderID != "order-1" || dispute.Amount.Amount != 5000 || dispute.EvidenceStatus != services.EvidenceStatusNotSubmitted {
+					t.Errorf("unexpected dispute: %+v", dispute)
+				}
+			} else if len(disputeRepo.Disputes) != 0 {
+				t.Errorf("expected no disputes, got %d", len(disputeRepo.Disputes))
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/lock"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 1, 17, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 17, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 17, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 1, 18, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * *", time.Date(2024, 1, 17, 13, 30, 0, 0, time.UTC)},
+		{"0 0 * * 1,5", time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 0", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)}, // day-of-month OR day-of-week
+		{"0 0 */1 * 1", time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)}, // a full-range day-of-month leaves it to day-of-week
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 17, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2024, 1, 17, 10, 9, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := jobs.ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule: %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "@every 1ms", "@sometimes"} {
+		if _, err := jobs.ParseSchedule(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := jobs.NewRunner(2, 10)
+	runner.Start(ctx)
+	scheduler := jobs.NewScheduler(runner)
+
+	release := make(chan struct{})
+	done := make(chan struct{}, 1)
+	err := scheduler.Register("cart-expiry", "Delete expired carts", "0 3 * * *", func(ctx context.Context) error {
+		<-release
+		done <- struct{}{}
+		return errors.New("database unavailable")
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := scheduler.Register("cart-expiry", "", "@daily", nil); err != jobs.ErrTaskExists {
+		t.Errorf("expected ErrTaskExists, got %v", err)
+	}
+
+	status, err := scheduler.Trigger("cart-expiry")
+	if err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if !status.Running {
+		t.Error("expected task to be running after trigger")
+	}
+	if _, err := scheduler.Trigger("cart-expiry"); err != jobs.ErrTaskRunning {
+		t.Errorf("expected ErrTaskRunning while the first run is in progress, got %v", err)
+	}
+	if _, err := scheduler.Trigger("missing"); err != jobs.ErrTaskNotFound {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	close(release)
+	<-done
+
+	// The run is recorded just after the task returns
+	deadline := time.Now().Add(time.Second)
+	for {
+		tasks := scheduler.Tasks()
+		if len(tasks) != 1 {
+			t.Fatalf("expected 1 task, got %d", len(tasks))
+		}
+		got := tasks[0]
+		if !got.Running {
+			if got.Runs != 1 || got.LastRunAt == nil || got.LastTrigger != jobs.TriggerManual || got.LastError != "database unavailable" {
+				t.Errorf("unexpected status after run: %+v", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("task still running after it returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduler_SkipsTaskLockedElsewhere(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := jobs.NewRunner(1, 10)
+	runner.Start(ctx)
+	locker := lock.NewLocalManager()
+	scheduler := jobs.NewScheduler(runner).WithLocker(locker)
+
+	ran := make(chan struct{}, 1)
+	if err := scheduler.Register("sale-prices", "", "*/15 * * * *", func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// Another replica holds the task's lock
+	held, err := locker.TryAcquire(ctx, "schedule:sale-prices")
+	if err != nil {
+		t.Fatalf("TryAcquire: %v", err)
+	}
+	if _, err := locker.TryAcquire(ctx, "schedule:sale-prices"); err != lock.ErrNotAcquired {
+		t.Fatalf("expected ErrNotAcquired, got %v", err)
+	}
+
+	if _, err := scheduler.Trigger("sale-prices"); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	waitIdle(t, scheduler)
+	if got := scheduler.Tasks()[0]; got.Runs != 0 {
+		t.Errorf("expected the run to be skipped, got %d runs", got.Runs)
+	}
+
+	// Once released, a manual run goes ahead and frees the lock when done
+	held.Release(ctx)
+	if _, err := scheduler.Trigger("sale-prices"); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	<-ran
+	waitIdle(t, scheduler)
+	if got := scheduler.Tasks()[0]; got.Runs != 1 {
+		t.Errorf("expected 1 run, got %d", got.Runs)
+	}
+
+	lockCtx, cancelLock := context.WithTimeout(ctx, time.Second)
+	defer cancelLock()
+	if _, err := locker.Acquire(lockCtx, "schedule:sale-prices"); err != nil {
+		t.Errorf("expected lock to be released after a manual run, got %v", err)
+	}
+}
+
+// waitIdle waits until no task is running
+func waitIdle(t *testing.T, scheduler *jobs.Scheduler) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		running := false
+		for _, task := range scheduler.Tasks() {
+			running = running || task.Running
+		}
+		if !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("tasks still running")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// stubReputation returns a fixed verdict for every IP
+type stubReputation struct {
+	verdict middleware.BotVerdict
+	err     error
+}
+
+func (s stubReputation) Check(ctx context.Context, ip string) (middleware.BotVerdict, error) {
+	return s.verdict, s.err
+}
+
+func setupBotGuardRouter(guard *middleware.BotGuard) *gin.Engine {
+	router := gin.New()
+	router.Use(guard.Protect())
+	router.GET("/catalog/products", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func newTestBotGuard() *middleware.BotGuard {
+	return middleware.NewBotGuard(middleware.BotPolicy{
+		AllowUserAgents:   []string{"Googlebot"},
+		BlockUserAgents:   []string{"SemrushBot"},
+		ThrottlePerMinute: 2,
+	})
+}
+
+func request(router *gin.Engine, userAgent string) int {
+	req := httptest.NewRequest(http.MethodGet, "/catalog/products", nil)
+	req.Header.Set("User-Agent", userAgent)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestBotGuard_Protect(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		requests  int
+		expected  int // status of the last request
+	}{
+		{"browser is allowed", "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Safari/605.1.15", 5, http.StatusOK},
+		{"allowlisted crawler is never throttled", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", 5, http.StatusOK},
+		{"denylisted scraper is blocked", "Mozilla/5.0 (compatible; SemrushBot/7~bl)", 1, http.StatusForbidden},
+		{"suspected bot within limit", "curl/8.4.0", 2, http.StatusOK},
+		{"suspected bot over limit is throttled", "curl/8.4.0", 3, http.StatusTooManyRequests},
+		{"empty user agent is throttled", "", 3, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupBotGuardRouter(newTestBotGuard())
+
+			var status int
+			for i := 0; i < tt.requests; i++ {
+				status = request(router, tt.userAgent)
+			}
+			if status != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestBotGuard_ReputationProvider(t *testing.T) {
+	browser := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0"
+
+	t.Run("blocks IPs with a bad reputation", func(t *testing.T) {
+		guard := newTestBotGuard().WithReputationProvider(stubReputation{verdict: middleware.BotVerdictBlock})
+		if status := request(setupBotGuardRouter(guard), browser); status != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", status)
+		}
+	})
+
+	t.Run("fails open when the provider errors", func(t *testing.T) {
+		guard := newTestBotGuard().WithReputationProvider(stubReputation{err: errors.New("provider unavailable")})
+		if status := request(setupBotGuardRouter(guard), browser); status != http.StatusOK {
+			t.Errorf("expected 200, got %d", status)
+		}
+	})
+}
+
+func TestBotGuard_Stats(t *testing.T) {
+	guard := newTestBotGuard()
+	router := setupBotGuardRouter(guard)
+
+	request(router, "SemrushBot")
+	for i := 0; i < 3; i++ {
+		request(router, "python-requests/2.31")
+	}
+
+	stats := guard.Stats()
+	if stats.Blocked != 1 || stats.Throttled != 1 {
+		t.Errorf("expected 1 blocked and 1 throttled, got %d and %d", stats.Blocked, stats.Throttled)
+	}
+	if stats.ByReason["user_agent:semrushbot"] != 1 || stats.ByReason["suspected_bot"] != 1 {
+		t.Errorf("unexpected reasons: %v", stats.ByReason)
+	}
+}
@@ -0,0 +1,175 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpserver "github.com/devchuckcamp/gocommerce-api/internal/http"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// stubCaptcha accepts one token, or fails every verification with err
+type stubCaptcha struct {
+	valid string
+	err   error
+}
+
+func (s stubCaptcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return token == s.valid, s.err
+}
+
+func setupCaptchaRouter(guard *middleware.CaptchaGuard) *gin.Engine {
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/auth/register", guard.Require(middleware.CaptchaRouteRegister), ok)
+	router.POST("/orders", guard.Require(middleware.CaptchaRouteCheckout), ok)
+	return router
+}
+
+func postWithCaptcha(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if token != "" {
+		req.Header.Set(middleware.CaptchaTokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return body.Error.Code
+}
+
+func TestCaptchaGuard_Require(t *testing.T) {
+	guard := middleware.NewCaptchaGuard(stubCaptcha{valid: "solved"}, middleware.CaptchaPolicy{
+		Routes: []string{middleware.CaptchaRouteRegister},
+	})
+	router := setupCaptchaRouter(guard)
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		expected int
+		code     string
+	}{
+		{name: "missing token", path: "/auth/register", expected: http.StatusForbidden, code: "captcha_required"},
+		{name: "wrong token", path: "/auth/register", token: "guess", expected: http.StatusForbidden, code: "captcha_failed"},
+		{name: "solved", path: "/auth/register", token: "solved", expected: http.StatusOK},
+		{name: "route not in policy", path: "/orders", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postWithCaptcha(router, tt.path, tt.token)
+			if rec.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rec.Code)
+			}
+			if tt.code != "" && errorCode(t, rec) != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, errorCode(t, rec))
+			}
+		})
+	}
+
+	// A nil guard means no CAPTCHA provider is configured
+	var disabled *middleware.CaptchaGuard
+	if rec := postWithCaptcha(setupCaptchaRouter(disabled), "/auth/register", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected a nil guard to let requests through, got %d", rec.Code)
+	}
+}
+
+func TestCaptchaGuard_ProviderUnavailable(t *testing.T) {
+	down := stubCaptcha{err: errors.New("connection refused")}
+	routes := []string{middleware.CaptchaRouteCheckout}
+
+	closed := setupCaptchaRouter(middleware.NewCaptchaGuard(down, middleware.CaptchaPolicy{Routes: routes}))
+	rec := postWithCaptcha(closed, "/orders", "solved")
+	if rec.Code != http.StatusServiceUnavailable || errorCode(t, rec) != "captcha_unavailable" {
+		t.Fatalf("expected 503 captcha_unavailable, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	open := setupCaptchaRouter(middleware.NewCaptchaGuard(down, middleware.CaptchaPolicy{Routes: routes, FailOpen: true}))
+	if rec := postWithCaptcha(open, "/orders", "solved"); rec.Code != http.StatusOK {
+		t.Fatalf("expected fail-open to let the request through, got %d", rec.Code)
+	}
+}
+
+func TestCaptchaGuard_CheckoutOnEveryVersion(t *testing.T) {
+	guard := middleware.NewCaptchaGuard(stubCaptcha{valid: "solved"}, middleware.CaptchaPolicy{
+		Routes: []string{middleware.CaptchaRouteCheckout},
+	})
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	httpserver.MountCreateOrder(router.Group("/api/v1/orders"), guard, ok)
+	httpserver.MountCreateOrder(router.Group("/api/v2/orders"), guard, ok)
+
+	for _, path := range []string{"/api/v1/orders", "/api/v2/orders"} {
+		rec := postWithCaptcha(router, path, "")
+		if rec.Code != http.StatusForbidden || errorCode(t, rec) != "captcha_required" {
+			t.Errorf("%s: expected 403 captcha_required without a token, got %d %s", path, rec.Code, rec.Body.String())
+		}
+		if rec := postWithCaptcha(router, path, "solved"); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected a solved CAPTCHA to place the order, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestCaptchaClient_Verify(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		success := r.PostForm.Get("secret") == "test-secret" && r.PostForm.Get("response") == "solved"
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": success})
+	}))
+	defer provider.Close()
+
+	client, err := services.NewCaptchaClient("turnstile", "test-secret", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.WithVerifyURL(provider.URL)
+
+	if ok, err := client.Verify(context.Background(), "solved", "203.0.113.7"); err != nil || !ok {
+		t.Errorf("expected the solved token to verify, got %v, %v", ok, err)
+	}
+	if ok, err := client.Verify(context.Background(), "guess", ""); err != nil || ok {
+		t.Errorf("expected the wrong token to be rejected without an error, got %v, %v", ok, err)
+	}
+
+	calls := 0
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": r.FormValue("response") == "solved"})
+	}))
+	defer flaky.Close()
+	client.WithVerifyURL(flaky.URL).WithRetry(retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if ok, err := client.Verify(context.Background(), "solved", ""); err != nil || !ok || calls != 2 {
+		t.Errorf("expected an unavailable provider to be asked again, got %v, %v after %d calls", ok, err, calls)
+	}
+
+	if _, err := services.NewCaptchaClient("recaptcha", "test-secret", time.Second); err == nil {
+		t.Error("expected an unsupported provider to be rejected")
+	}
+}
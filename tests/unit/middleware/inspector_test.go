@@ -0,0 +1,112 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/database"
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+)
+
+// dryRunDB builds statements without a database server, so queries are counted but never sent
+func dryRunDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	wrapped := &database.DB{DB: db}
+	wrapped.RecordQueries()
+	return wrapped
+}
+
+func setupInspectorRouter(inspector *middleware.RequestInspector, db *database.DB) *gin.Engine {
+	router := gin.New()
+	router.Use(inspector.Record())
+	router.GET("/products/:id", func(c *gin.Context) {
+		// One lookup per related row, the N+1 pattern the inspector exists to show
+		for _, id := range []string{"a", "b", "c"} {
+			var product database.Product
+			db.WithContext(c.Request.Context()).Where("id = ?", id).First(&product)
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.GET("/debug/requests", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func serve(router *gin.Engine, method, path string, header http.Header) {
+	req := httptest.NewRequest(method, path, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestRequestInspector_Record(t *testing.T) {
+	inspector := middleware.NewRequestInspector(10)
+	router := setupInspectorRouter(inspector, dryRunDB(t))
+
+	serve(router, http.MethodGet, "/products/p1?expand=brand", http.Header{"Authorization": {"Bearer secret-token"}})
+	serve(router, http.MethodGet, "/debug/requests", nil)
+
+	requests := inspector.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected only the product request to be recorded, got %d", len(requests))
+	}
+	recorded := requests[0]
+	if recorded.Route != "/products/:id" || recorded.Query != "expand=brand" || recorded.Status != http.StatusOK {
+		t.Errorf("unexpected request summary: %+v", recorded.Summary())
+	}
+	if !strings.Contains(recorded.ResponseBody, `"id":"p1"`) {
+		t.Errorf("expected the response body to be kept, got %q", recorded.ResponseBody)
+	}
+	if got := recorded.RequestHeaders["Authorization"]; len(got) != 1 || got[0] != "[redacted]" {
+		t.Errorf("expected the Authorization header to be redacted, got %v", got)
+	}
+
+	if recorded.SQLCount != 3 || len(recorded.Queries) != 3 {
+		t.Fatalf("expected 3 statements, got count %d with %d kept", recorded.SQLCount, len(recorded.Queries))
+	}
+	if len(recorded.RepeatedQueries) != 1 || recorded.RepeatedQueries[0].Count != 3 {
+		t.Errorf("expected the lookups to be grouped as one repeated query, got %+v", recorded.RepeatedQueries)
+	}
+}
+
+func TestRequestInspector_KeepsLastRequests(t *testing.T) {
+	inspector := middleware.NewRequestInspector(2)
+	router := setupInspectorRouter(inspector, dryRunDB(t))
+
+	for _, id := range []string{"p1", "p2", "p3"} {
+		serve(router, http.MethodGet, "/products/"+id, nil)
+	}
+
+	requests := inspector.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if requests[0].Path != "/products/p3" || requests[1].Path != "/products/p2" {
+		t.Errorf("expected the newest requests first, got %s and %s", requests[0].Path, requests[1].Path)
+	}
+	if _, ok := inspector.Request(requests[1].ID); !ok {
+		t.Errorf("expected request %s to be found by ID", requests[1].ID)
+	}
+
+	inspector.Clear()
+	if len(inspector.Requests()) != 0 {
+		t.Error("expected Clear to forget the recorded requests")
+	}
+}
@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+)
+
+// setupLoadShedderRouter sheds /catalog and protects /checkout; both wait on release when it is set
+func setupLoadShedderRouter(shedder *middleware.LoadShedder, delay time.Duration, release chan struct{}) *gin.Engine {
+	router := gin.New()
+	router.Use(shedder.Track())
+
+	handler := func(c *gin.Context) {
+		if release != nil {
+			<-release
+		}
+		time.Sleep(delay)
+		c.Status(http.StatusOK)
+	}
+	router.GET("/catalog", shedder.Shed(), handler)
+	router.GET("/checkout", handler)
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestLoadShedder_InFlight(t *testing.T) {
+	shedder := middleware.NewLoadShedder(middleware.LoadPolicy{MaxInFlight: 2})
+	release := make(chan struct{})
+	router := setupLoadShedderRouter(shedder, 0, release)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get(router, "/checkout")
+		}()
+	}
+	for shedder.Stats().InFlight < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := get(router, "/catalog")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected catalog request shed with 503, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After on shed request")
+	}
+
+	// Protected routes are never shed, even over the limit
+	wg.Add(1)
+	protected := make(chan int, 1)
+	go func() {
+		defer wg.Done()
+		protected <- get(router, "/checkout").Code
+	}()
+	close(release)
+	if code := <-protected; code != http.StatusOK {
+		t.Errorf("Expected protected request served, got %d", code)
+	}
+	wg.Wait()
+
+	if rec := get(router, "/catalog"); rec.Code != http.StatusOK {
+		t.Errorf("Expected catalog served once load dropped, got %d", rec.Code)
+	}
+
+	stats := shedder.Stats()
+	if stats.Shed != 1 || stats.ByReason["in_flight"] != 1 || stats.ByRoute["/catalog"] != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLoadShedder_Latency(t *testing.T) {
+	shedder := middleware.NewLoadShedder(middleware.LoadPolicy{MaxLatency: time.Millisecond})
+	router := setupLoadShedderRouter(shedder, 3*time.Millisecond, nil)
+
+	// A few slow requests are not enough to start shedding
+	get(router, "/checkout")
+	if rec := get(router, "/catalog"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected catalog served before enough samples, got %d", rec.Code)
+	}
+
+	for i := 0; i < 20; i++ {
+		get(router, "/checkout")
+	}
+
+	// Average latency is over twice the limit, so every low-priority request is shed
+	for i := 0; i < 5; i++ {
+		if rec := get(router, "/catalog"); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected catalog request shed on latency, got %d", rec.Code)
+		}
+	}
+	if rec := get(router, "/checkout"); rec.Code != http.StatusOK {
+		t.Errorf("Expected checkout served, got %d", rec.Code)
+	}
+
+	if stats := shedder.Stats(); stats.ByReason["latency"] != 5 {
+		t.Errorf("Expected 5 requests shed on latency, got %+v", stats)
+	}
+}
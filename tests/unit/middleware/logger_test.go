@@ -0,0 +1,106 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+)
+
+// captureLog sends the standard logger to a buffer for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func loggedRouter(opts middleware.LoggerOptions) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.LoggerWithOptions(opts))
+	router.GET("/api/v1/catalog/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/checkout", func(c *gin.Context) {
+		var body map[string]interface{}
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "cannot ship to jane@example.com"}})
+	})
+	return router
+}
+
+func TestLogger_SamplesSuccessfulCatalogRequests(t *testing.T) {
+	buf := captureLog(t)
+	router := loggedRouter(middleware.LoggerOptions{SampleRate: 0, SampledPaths: []string{"/catalog"}})
+
+	for i := 0; i < 20; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/catalog/products", nil))
+	}
+	if strings.Contains(buf.String(), "/catalog/products") {
+		t.Errorf("Expected successful catalog requests to be sampled out, got %q", buf.String())
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil))
+	if !strings.Contains(buf.String(), "[GET] /api/v1/orders") {
+		t.Errorf("Expected routes that aren't sampled to be logged, got %q", buf.String())
+	}
+
+	buf.Reset()
+	router = loggedRouter(middleware.LoggerOptions{SampleRate: 1, SampledPaths: []string{"/catalog"}})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/catalog/products", nil))
+	if !strings.Contains(buf.String(), "/catalog/products") {
+		t.Errorf("Expected every request logged at a sample rate of 1, got %q", buf.String())
+	}
+}
+
+func TestLogger_LogsFailuresInFullWithPIIRedacted(t *testing.T) {
+	buf := captureLog(t)
+	router := loggedRouter(middleware.LoggerOptions{SampleRate: 0, SampledPaths: []string{"/catalog", "/checkout"}})
+
+	body := `{"email":"jane@example.com","shipping_address":{"line1":"1 Main St","city":"Springfield"},"address_id":"addr-1","quantity":2}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/checkout?email=jane@example.com&coupon=SPRING", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	logged := buf.String()
+	if !strings.Contains(logged, "[POST] /api/v1/checkout") || !strings.Contains(logged, " - 422 ") {
+		t.Fatalf("Expected failed requests to be logged even on sampled routes, got %q", logged)
+	}
+	for _, leaked := range []string{"jane@example.com", "secret-token", "1 Main St", "Springfield"} {
+		if strings.Contains(logged, leaked) {
+			t.Errorf("Expected %q to be redacted from %q", leaked, logged)
+		}
+	}
+	for _, kept := range []string{"coupon=SPRING", `"address_id":"addr-1"`, `"quantity":2`, "cannot ship to [redacted]", "Authorization:[redacted]"} {
+		if !strings.Contains(logged, kept) {
+			t.Errorf("Expected the log to keep %q, got %q", kept, logged)
+		}
+	}
+}
+
+func TestRedactPII(t *testing.T) {
+	cases := map[string]string{
+		`{"password":"hunter2","name":"Jane"}`:         `{"password":"[redacted]","name":"Jane"}`,
+		`{"phone": "+1 555 0100", "order_id": "o-1"}`:  `{"phone":"[redacted]", "order_id": "o-1"}`,
+		`{"billing_address":{"street":"1 Main St"}}`:   `{"billing_address":"[redacted]"}`,
+		"reset sent to Jane.Doe+shop@mail.example.org": "reset sent to [redacted]",
+		"/api/v1/products/abc":                         "/api/v1/products/abc",
+	}
+	for input, want := range cases {
+		if got := middleware.RedactPII(input); got != want {
+			t.Errorf("RedactPII(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestMaintenance(t *testing.T) {
+	next := services.RuntimeSettings{}
+	runtimeConfig := services.NewRuntimeConfigService(mocks.NewMockConfigChangeRepository(), next, func() (services.RuntimeSettings, error) {
+		return next, nil
+	})
+
+	router := gin.New()
+	router.Use(middleware.Maintenance(runtimeConfig))
+	for _, path := range []string{"/health", "/api/v1/products", "/api/v1/admin/orders", "/api/v1/auth/login", "/api/v1/auth/register", "/api/v1/webhooks/carrier", "/api/v2/orders"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	if rec := get(router, "/api/v1/products"); rec.Code != http.StatusOK {
+		t.Fatalf("expected the API open outside maintenance, got %d", rec.Code)
+	}
+
+	next.MaintenanceMode = true
+	if _, err := runtimeConfig.Reload(context.Background(), services.ConfigChangeSourceSignal, nil); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	closed := []string{"/api/v1/products", "/api/v1/auth/register", "/api/v2/orders"}
+	for _, path := range closed {
+		if rec := get(router, path); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected %s closed for maintenance, got %d", path, rec.Code)
+		}
+	}
+	open := []string{"/health", "/api/v1/admin/orders", "/api/v1/auth/login", "/api/v1/webhooks/carrier"}
+	for _, path := range open {
+		if rec := get(router, path); rec.Code != http.StatusOK {
+			t.Errorf("expected %s open during maintenance, got %d", path, rec.Code)
+		}
+	}
+}
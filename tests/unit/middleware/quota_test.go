@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestQuota_APIKeyRequests(t *testing.T) {
+	tiers, _ := services.ParseQuotaTiers([]string{"api_key:2:0"})
+	quotaRepo := mocks.NewMockQuotaRepository()
+	apiKeys := services.NewAPIKeyService(mocks.NewMockAPIKeyRepository())
+	quotas := services.NewQuotaService(quotaRepo, tiers).WithAPIKeys(apiKeys)
+	_, secret, err := apiKeys.CreateKey(context.Background(), "Warehouse", []services.APIKeyScope{services.APIKeyScopeFulfillmentRead}, "admin-1")
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/fulfillment/orders",
+		middleware.NewAPIKeyMiddleware(apiKeys).WithQuotas(quotas).RequireScope(services.APIKeyScopeFulfillmentRead),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/fulfillment/orders", nil)
+		req.Header.Set(middleware.APIKeyHeader, secret)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the first request through, got %d", rec.Code)
+	}
+	if rec.Header().Get(middleware.QuotaLimitHeader) != "2" || rec.Header().Get(middleware.QuotaRemainingHeader) != "1" || rec.Header().Get(middleware.QuotaResetHeader) == "" {
+		t.Errorf("expected quota headers, got %v", rec.Header())
+	}
+
+	request()
+	rec = request()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the quota is used up, got %d", rec.Code)
+	}
+	if rec.Header().Get(middleware.QuotaRemainingHeader) != "0" || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected no requests remaining and a Retry-After, got %v", rec.Header())
+	}
+
+	quotaRepo.IncrementError = errors.New("database unavailable")
+	if rec := request(); rec.Code != http.StatusOK {
+		t.Errorf("expected quotas to fail open, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,202 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/http/middleware"
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+type recordingPurger struct {
+	purged [][]string
+}
+
+func (p *recordingPurger) Purge(ctx context.Context, keys []string) error {
+	p.purged = append(p.purged, keys)
+	return nil
+}
+
+// setupResponseCacheRouter caches a product route and a listing of two products, counting
+// how often the handlers run, and purges the product on PUT
+func setupResponseCacheRouter(cache *middleware.ResponseCache, calls map[string]int) *gin.Engine {
+	router := gin.New()
+	router.GET("/products/:id", cache.Cache(middleware.SurrogateKeyParam("product", "id", middleware.SurrogateKeyProducts)), func(c *gin.Context) {
+		calls[c.Param("id")]++
+		if c.Param("id") == "missing" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "calls": calls[c.Param("id")]})
+	})
+	router.GET("/products", cache.Cache(middleware.SurrogateKeys(middleware.SurrogateKeyProducts)), func(c *gin.Context) {
+		calls["list"]++
+		middleware.AddSurrogateKeys(c, "product:1", "product:2")
+		c.JSON(http.StatusOK, gin.H{"ids": []string{"1", "2"}})
+	})
+	router.PUT("/admin/products/:id", cache.PurgeOnSuccess(middleware.SurrogateKeyParam("product", "id")), func(c *gin.Context) {
+		if c.Param("id") == "invalid" {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func cachedRequest(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestResponseCache_ServesCachedResponses(t *testing.T) {
+	cache := middleware.NewResponseCache(time.Minute, 100)
+	calls := map[string]int{}
+	router := setupResponseCacheRouter(cache, calls)
+
+	first := cachedRequest(router, http.MethodGet, "/products/1?currency=EUR&fields=id")
+	if first.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected the first request to miss, got %q", first.Header().Get("X-Cache"))
+	}
+	if got := first.Header().Get("Surrogate-Key"); got != "catalog product:1 products" {
+		t.Errorf("expected the route's surrogate keys, got %q", got)
+	}
+	if got := first.Header().Get("Surrogate-Control"); got != "max-age=60" {
+		t.Errorf("expected the CDN to keep the response for the TTL, got %q", got)
+	}
+
+	// The same query in another order is served from the cache
+	second := cachedRequest(router, http.MethodGet, "/products/1?fields=id&currency=EUR")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() || calls["1"] != 1 {
+		t.Errorf("expected a cached response, got %q after %d calls", second.Header().Get("X-Cache"), calls["1"])
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") || second.Header().Get("Surrogate-Key") == "" {
+		t.Errorf("expected the cached response's headers, got %v", second.Header())
+	}
+
+	// Other queries, HAL and errors are not shared
+	cachedRequest(router, http.MethodGet, "/products/1?currency=USD")
+	hal := httptest.NewRequest(http.MethodGet, "/products/1?fields=id&currency=EUR", nil)
+	hal.Header.Set("Accept", "application/hal+json")
+	router.ServeHTTP(httptest.NewRecorder(), hal)
+	if calls["1"] != 3 {
+		t.Errorf("expected other variants to reach the handler, got %d calls", calls["1"])
+	}
+	cachedRequest(router, http.MethodGet, "/products/missing")
+	if rec := cachedRequest(router, http.MethodGet, "/products/missing"); rec.Code != http.StatusNotFound || calls["missing"] != 2 {
+		t.Errorf("expected errors not to be cached, got %d after %d calls", rec.Code, calls["missing"])
+	}
+	if stats := cache.Stats(); stats.Entries != 3 || stats.Hits != 1 {
+		t.Errorf("expected 3 entries and 1 hit, got %+v", stats)
+	}
+}
+
+func TestResponseCache_PurgeBySurrogateKey(t *testing.T) {
+	purger := &recordingPurger{}
+	cache := middleware.NewResponseCache(time.Minute, 100).WithPurger(purger)
+	calls := map[string]int{}
+	router := setupResponseCacheRouter(cache, calls)
+
+	cachedRequest(router, http.MethodGet, "/products/1")
+	cachedRequest(router, http.MethodGet, "/products/3")
+	listing := cachedRequest(router, http.MethodGet, "/products")
+	if !strings.Contains(listing.Header().Get("Surrogate-Key"), "product:2") {
+		t.Errorf("expected the listing tagged with its products, got %q", listing.Header().Get("Surrogate-Key"))
+	}
+
+	// A failed change purges nothing
+	cachedRequest(router, http.MethodPut, "/admin/products/invalid")
+	if len(purger.purged) != 0 {
+		t.Errorf("expected no purge for a failed change, got %v", purger.purged)
+	}
+
+	// Changing product 1 purges it and the listing, here and at the CDN, but not product 3
+	cachedRequest(router, http.MethodPut, "/admin/products/1")
+	if len(purger.purged) != 1 || purger.purged[0][0] != "product:1" {
+		t.Fatalf("expected product:1 purged at the CDN, got %v", purger.purged)
+	}
+	for _, path := range []string{"/products/1", "/products", "/products/3"} {
+		cachedRequest(router, http.MethodGet, path)
+	}
+	if calls["1"] != 2 || calls["list"] != 2 || calls["3"] != 1 {
+		t.Errorf("expected only product 1 and the listing reloaded, got %v", calls)
+	}
+
+	if err := cache.Purge(context.Background(), middleware.SurrogateKeyAll); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected purging %q to empty the cache, got %d entries", middleware.SurrogateKeyAll, stats.Entries)
+	}
+}
+
+func TestResponseCache_ExpiryAndCapacity(t *testing.T) {
+	cache := middleware.NewResponseCache(20*time.Millisecond, 1)
+	calls := map[string]int{}
+	router := setupResponseCacheRouter(cache, calls)
+
+	cachedRequest(router, http.MethodGet, "/products/1")
+	cachedRequest(router, http.MethodGet, "/products/2")
+	cachedRequest(router, http.MethodGet, "/products/2")
+	if calls["2"] != 2 {
+		t.Errorf("expected a full cache not to store more responses, got %d calls", calls["2"])
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	cachedRequest(router, http.MethodGet, "/products/2")
+	if rec := cachedRequest(router, http.MethodGet, "/products/2"); rec.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected an expired response to make room, got %q", rec.Header().Get("X-Cache"))
+	}
+	if rec := cachedRequest(router, http.MethodGet, "/products/1"); rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected the expired response to be reloaded, got %q", rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestCDNPurgeClient_Purge(t *testing.T) {
+	var header, auth string
+	var body struct {
+		SurrogateKeys []string `json:"surrogate_keys"`
+	}
+	status, calls := http.StatusOK, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		header, auth = r.Header.Get("Surrogate-Key"), r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := services.NewCDNPurgeClient(server.URL, "purge-token", time.Second)
+	if err := client.Purge(context.Background(), []string{"product:1", "products"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if header != "product:1 products" || len(body.SurrogateKeys) != 2 {
+		t.Errorf("expected the keys in the header and body, got %q and %v", header, body.SurrogateKeys)
+	}
+	if auth != "Bearer purge-token" {
+		t.Errorf("expected the token as a bearer token, got %q", auth)
+	}
+
+	status = http.StatusUnauthorized
+	if err := client.Purge(context.Background(), []string{"products"}); err == nil {
+		t.Error("expected an error when the purge endpoint refuses")
+	}
+
+	status, calls = http.StatusBadGateway, 0
+	client.WithRetry(retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if err := client.Purge(context.Background(), []string{"products"}); err == nil {
+		t.Error("expected an error once every attempt fails")
+	}
+	if calls != 3 || len(body.SurrogateKeys) != 1 {
+		t.Errorf("expected the purge sent again in full on each attempt, got %d calls with %v", calls, body.SurrogateKeys)
+	}
+}
@@ -0,0 +1,102 @@
+package moneymath_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
+)
+
+func usd(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: "USD"}
+}
+
+func TestAdd_OverflowAndCurrency(t *testing.T) {
+	if sum, err := moneymath.Add(usd(150), usd(-50)); err != nil || sum.Amount != 100 {
+		t.Errorf("Expected 100, got %d (%v)", sum.Amount, err)
+	}
+	if _, err := moneymath.Add(usd(math.MaxInt64), usd(1)); !errors.Is(err, moneymath.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if _, err := moneymath.Sub(usd(math.MinInt64+1), usd(2)); !errors.Is(err, moneymath.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow subtracting below the minimum, got %v", err)
+	}
+	if _, err := moneymath.Add(usd(1), money.Money{Amount: 1, Currency: "EUR"}); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := moneymath.Sum("USD", usd(1), money.Money{Amount: 1, Currency: "EUR"}); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected Sum to reject a second currency, got %v", err)
+	}
+}
+
+func TestMulInt_Overflow(t *testing.T) {
+	if product, err := moneymath.MulInt(usd(1999), 3); err != nil || product.Amount != 5997 {
+		t.Errorf("Expected 5997, got %d (%v)", product.Amount, err)
+	}
+	if _, err := moneymath.MulInt(usd(math.MaxInt64/2+1), 2); !errors.Is(err, moneymath.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if _, err := moneymath.MulInt(usd(math.MinInt64), -1); !errors.Is(err, moneymath.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow negating the minimum, got %v", err)
+	}
+}
+
+func TestMulRate_Rounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		rate     float64
+		rounding moneymath.Rounding
+		expected int64
+	}{
+		{"truncate drops the half", 25000, 0.0875, moneymath.Truncate, 2187},
+		{"half up rounds the half up", 25000, 0.0875, moneymath.HalfUp, 2188},
+		{"half even rounds to the even cent", 25000, 0.0875, moneymath.HalfEven, 2188},
+		{"half even keeps an even cent", 30, 0.05, moneymath.HalfEven, 2},  // 1.5 -> 2
+		{"half even rounds down to even", 50, 0.05, moneymath.HalfEven, 2}, // 2.5 -> 2
+		{"half up below half", 1049, 0.1, moneymath.HalfUp, 105},           // 104.9
+		{"decimal rate is exact", 100, 0.29, moneymath.Truncate, 29},       // float gives 28.999...
+		{"negative amounts round symmetrically", -50, 0.05, moneymath.HalfUp, -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := moneymath.MulRate(usd(tt.amount), tt.rate, tt.rounding)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Amount != tt.expected || result.Currency != "USD" {
+				t.Errorf("Expected %d USD, got %d %s", tt.expected, result.Amount, result.Currency)
+			}
+		})
+	}
+
+	if _, err := moneymath.MulRate(usd(math.MaxInt64), 2, moneymath.Truncate); !errors.Is(err, moneymath.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestProrate(t *testing.T) {
+	if share, err := moneymath.Prorate(usd(1000), 1, 3); err != nil || share.Amount != 333 {
+		t.Errorf("Expected 333, got %d (%v)", share.Amount, err)
+	}
+	// The intermediate product overflows int64 but the share does not
+	if share, err := moneymath.Prorate(usd(math.MaxInt64/2), 4, 8); err != nil || share.Amount != math.MaxInt64/4 {
+		t.Errorf("Expected %d, got %d (%v)", int64(math.MaxInt64/4), share.Amount, err)
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	if rounding, err := moneymath.ParseRounding(""); err != nil || rounding != moneymath.Truncate {
+		t.Errorf("Expected an empty mode to truncate, got %q (%v)", rounding, err)
+	}
+	if rounding, err := moneymath.ParseRounding("Half_Even"); err != nil || rounding != moneymath.HalfEven {
+		t.Errorf("Expected half_even, got %q (%v)", rounding, err)
+	}
+	if _, err := moneymath.ParseRounding("ceiling"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
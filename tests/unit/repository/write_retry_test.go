@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/repository"
+)
+
+// sqlStateError stands in for pgx's *pgconn.PgError
+type sqlStateError struct{ code string }
+
+func (e *sqlStateError) Error() string    { return "ERROR (SQLSTATE " + e.code + ")" }
+func (e *sqlStateError) SQLState() string { return e.code }
+
+// sqlServerError stands in for go-mssqldb's mssql.Error
+type sqlServerError struct{ number int32 }
+
+func (e *sqlServerError) Error() string         { return fmt.Sprintf("mssql: error %d", e.number) }
+func (e *sqlServerError) SQLErrorNumber() int32 { return e.number }
+
+func TestIsRetryableWriteError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"postgres serialization failure", &sqlStateError{"40001"}, true},
+		{"postgres deadlock", fmt.Errorf("saving order: %w", &sqlStateError{"40P01"}), true},
+		{"postgres unique violation", &sqlStateError{"23505"}, false},
+		{"sql server deadlock victim", &sqlServerError{1205}, true},
+		{"sql server timeout", &sqlServerError{-2}, false},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{"mysql duplicate key", errors.New("Error 1062 (23000): Duplicate entry"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repository.IsRetryableWriteError(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
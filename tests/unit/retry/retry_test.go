@@ -0,0 +1,155 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+)
+
+var errUnavailable = errors.New("provider unavailable")
+
+// failing returns a function that fails the given number of times before succeeding
+func failing(times int, calls *int) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		*calls++
+		if *calls <= times {
+			return errUnavailable
+		}
+		return nil
+	}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	var calls int
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if err := retry.Do(context.Background(), policy, failing(2, &calls)); err != nil {
+		t.Fatalf("Expected success on the third attempt, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDo_StopsAtMaxAttempts(t *testing.T) {
+	var calls int
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	err := retry.Do(context.Background(), policy, failing(10, &calls))
+	if err != errUnavailable {
+		t.Errorf("Expected the last error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDo_ZeroPolicyTriesOnce(t *testing.T) {
+	var calls int
+	if err := retry.Do(context.Background(), retry.Policy{}, failing(1, &calls)); err != errUnavailable {
+		t.Errorf("Expected the error from a single attempt, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestDo_SkipsNonRetryableErrors(t *testing.T) {
+	var calls int
+	policy := retry.Policy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		Retryable:   func(err error) bool { return err != errUnavailable },
+	}
+
+	if err := retry.Do(context.Background(), policy, failing(10, &calls)); err != errUnavailable {
+		t.Errorf("Expected the non-retryable error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retries, got %d attempts", calls)
+	}
+}
+
+func TestDo_BudgetExhausted(t *testing.T) {
+	var calls int
+	policy := retry.Policy{
+		MaxAttempts: 10,
+		BaseDelay:   20 * time.Millisecond,
+		MaxDelay:    20 * time.Millisecond,
+		Budget:      50 * time.Millisecond,
+	}
+
+	started := time.Now()
+	err := retry.Do(context.Background(), policy, failing(100, &calls))
+	elapsed := time.Since(started)
+
+	if !errors.Is(err, retry.ErrBudgetExhausted) {
+		t.Fatalf("Expected ErrBudgetExhausted, got %v", err)
+	}
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("Expected the last attempt's error to be wrapped, got %v", err)
+	}
+	if calls < 2 || calls >= 10 {
+		t.Errorf("Expected the budget to end retries early, got %d attempts", calls)
+	}
+	if elapsed > 100*time.Millisecond {
+		t.Errorf("Expected to stop within the budget, took %s", elapsed)
+	}
+}
+
+func TestDo_CallerDeadlineShorterThanBackoff(t *testing.T) {
+	var calls int
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Second, Budget: time.Minute}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err := retry.Do(ctx, policy, failing(10, &calls))
+
+	if !errors.Is(err, retry.ErrBudgetExhausted) {
+		t.Fatalf("Expected ErrBudgetExhausted, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retry that could not finish in time, got %d attempts", calls)
+	}
+	if time.Since(started) > 40*time.Millisecond {
+		t.Errorf("Expected to give up without waiting out the deadline, took %s", time.Since(started))
+	}
+}
+
+func TestDo_CallerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := retry.Policy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+
+	var calls int
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.Canceled) || errors.Is(err, retry.ErrBudgetExhausted) {
+		t.Errorf("Expected the caller's cancellation without budget exhaustion, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retries after cancellation, got %d attempts", calls)
+	}
+}
+
+func TestCall_ReturnsValue(t *testing.T) {
+	var calls int
+	value, err := retry.Call(context.Background(), retry.Policy{MaxAttempts: 2}, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errUnavailable
+		}
+		return "ok", nil
+	})
+	if err != nil || value != "ok" {
+		t.Errorf("Expected ok after one retry, got %q, %v", value, err)
+	}
+}
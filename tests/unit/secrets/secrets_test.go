@@ -0,0 +1,255 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/secrets"
+)
+
+// stubProvider serves secrets from a map and counts fetches
+type stubProvider struct {
+	values  map[string]string
+	err     error
+	fetches int
+}
+
+func (p *stubProvider) Fetch(ctx context.Context, path string) (string, error) {
+	p.fetches++
+	if p.err != nil {
+		return "", p.err
+	}
+	value, ok := p.values[path]
+	if !ok {
+		return "", secrets.ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func TestParseReference(t *testing.T) {
+	ref, ok, err := secrets.ParseReference("vault://secret/gocommerce/api#jwt_secret")
+	if err != nil || !ok {
+		t.Fatalf("Expected a reference, got ok=%v err=%v", ok, err)
+	}
+	if ref.Scheme != secrets.SchemeVault || ref.Path != "secret/gocommerce/api" || ref.Field != "jwt_secret" {
+		t.Errorf("Unexpected reference %+v", ref)
+	}
+
+	for _, value := range []string{"plain-secret", "postgres://user:pass@db/shop", "https://example.com"} {
+		if _, ok, err := secrets.ParseReference(value); ok || err != nil {
+			t.Errorf("Expected %q not to be a reference, got ok=%v err=%v", value, ok, err)
+		}
+	}
+
+	if _, _, err := secrets.ParseReference("vault://secret/gocommerce/api"); !errors.Is(err, secrets.ErrInvalidRef) {
+		t.Errorf("Expected a Vault reference without a field to be invalid, got %v", err)
+	}
+	if _, _, err := secrets.ParseReference("awssm://#password"); !errors.Is(err, secrets.ErrInvalidRef) {
+		t.Errorf("Expected a reference without a path to be invalid, got %v", err)
+	}
+}
+
+func TestResolver_ResolvesFieldsFromOneFetch(t *testing.T) {
+	provider := &stubProvider{values: map[string]string{"prod/db": `{"username":"shop","password":"s3cret","port":5432}`}}
+	resolver := secrets.NewResolver(time.Hour).WithProvider(secrets.SchemeAWS, provider)
+
+	password, err := resolver.Resolve(context.Background(), "awssm://prod/db#password")
+	if err != nil || password != "s3cret" {
+		t.Fatalf("Expected s3cret, got %q (%v)", password, err)
+	}
+	port, err := resolver.Resolve(context.Background(), "awssm://prod/db#port")
+	if err != nil || port != "5432" {
+		t.Fatalf("Expected 5432, got %q (%v)", port, err)
+	}
+	if provider.fetches != 1 {
+		t.Errorf("Expected fields of one secret to share a cached fetch, got %d fetches", provider.fetches)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), "awssm://prod/db#host"); !errors.Is(err, secrets.ErrFieldNotFound) {
+		t.Errorf("Expected ErrFieldNotFound, got %v", err)
+	}
+	if value, err := resolver.Resolve(context.Background(), "not-a-reference"); err != nil || value != "not-a-reference" {
+		t.Errorf("Expected plain values to pass through, got %q (%v)", value, err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "gcpsm://shop/jwt"); !errors.Is(err, secrets.ErrNoProvider) {
+		t.Errorf("Expected ErrNoProvider, got %v", err)
+	}
+}
+
+func TestResolver_ServesCachedValueWhileStoreIsDown(t *testing.T) {
+	provider := &stubProvider{values: map[string]string{"shop/jwt": "first"}}
+	resolver := secrets.NewResolver(time.Nanosecond).WithProvider(secrets.SchemeGCP, provider)
+
+	if _, err := resolver.Resolve(context.Background(), "gcpsm://shop/jwt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	provider.err = errors.New("connection refused")
+	time.Sleep(time.Millisecond)
+
+	value, err := resolver.Resolve(context.Background(), "gcpsm://shop/jwt")
+	if err != nil || value != "first" {
+		t.Errorf("Expected the cached value after the TTL while the store is down, got %q (%v)", value, err)
+	}
+	if provider.fetches != 2 {
+		t.Errorf("Expected an expired secret to be fetched again, got %d fetches", provider.fetches)
+	}
+}
+
+func TestResolver_RefreshReportsRotatedReferences(t *testing.T) {
+	provider := &stubProvider{values: map[string]string{
+		"prod/db":  `{"password":"old"}`,
+		"prod/jwt": "unchanged",
+	}}
+	resolver := secrets.NewResolver(0).WithProvider(secrets.SchemeAWS, provider)
+	for _, ref := range []string{"awssm://prod/db#password", "awssm://prod/jwt"} {
+		if _, err := resolver.Resolve(context.Background(), ref); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	provider.values["prod/db"] = `{"password":"new"}`
+	rotated, err := resolver.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rotated) != 1 || rotated[0] != "awssm://prod/db#password" {
+		t.Errorf("Expected only the database password to be rotated, got %v", rotated)
+	}
+	if value, _ := resolver.Resolve(context.Background(), "awssm://prod/db#password"); value != "new" {
+		t.Errorf("Expected the rotated value to be served, got %q", value)
+	}
+
+	provider.err = errors.New("throttled")
+	rotated, err = resolver.Refresh(context.Background())
+	if err == nil || len(rotated) != 0 {
+		t.Errorf("Expected a failed refresh to report the error and keep values, got %v (%v)", rotated, err)
+	}
+	if value, _ := resolver.Resolve(context.Background(), "awssm://prod/db#password"); value != "new" {
+		t.Errorf("Expected the cached value to survive a failed refresh, got %q", value)
+	}
+}
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root-token" || r.Header.Get("X-Vault-Namespace") != "shop" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/gocommerce/api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"jwt_secret":"from-vault"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	resolver := secrets.NewResolver(time.Minute).
+		WithProvider(secrets.SchemeVault, secrets.NewVaultProvider(server.URL, "root-token", "shop", time.Second))
+
+	value, err := resolver.Resolve(context.Background(), "vault://secret/gocommerce/api#jwt_secret")
+	if err != nil || value != "from-vault" {
+		t.Fatalf("Expected from-vault, got %q (%v)", value, err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "vault://secret/gocommerce/missing#jwt_secret"); !errors.Is(err, secrets.ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestAWSProvider_FetchSignsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(auth, "/us-east-1/secretsmanager/aws4_request") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" || r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var body struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.SecretId != "prod/db" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Name":"prod/db","SecretString":"{\"password\":\"from-aws\"}"}`))
+	}))
+	defer server.Close()
+
+	provider := secrets.NewAWSProvider("us-east-1", secrets.AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "session",
+	}, time.Second).WithEndpoint(server.URL)
+	resolver := secrets.NewResolver(time.Minute).WithProvider(secrets.SchemeAWS, provider)
+
+	value, err := resolver.Resolve(context.Background(), "awssm://prod/db#password")
+	if err != nil || value != "from-aws" {
+		t.Fatalf("Expected from-aws, got %q (%v)", value, err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "awssm://prod/other"); !errors.Is(err, secrets.ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestGCPProvider_FetchWithMetadataToken(t *testing.T) {
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		tokenRequests++
+		_, _ = w.Write([]byte(`{"access_token":"metadata-token","expires_in":3600,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/projects/shop-prod/secrets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer metadata-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		payload := map[string]string{
+			"/v1/projects/shop-prod/secrets/jwt-secret/versions/latest:access": "latest-value",
+			"/v1/projects/shop-prod/secrets/jwt-secret/versions/2:access":      "version-2",
+		}[r.URL.Path]
+		if payload == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":    r.URL.Path,
+			"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(payload))},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := secrets.NewGCPProvider("", time.Second).WithEndpoints(server.URL, server.URL+"/token")
+	resolver := secrets.NewResolver(time.Minute).WithProvider(secrets.SchemeGCP, provider)
+
+	for ref, want := range map[string]string{
+		"gcpsm://shop-prod/jwt-secret":   "latest-value",
+		"gcpsm://shop-prod/jwt-secret/2": "version-2",
+	} {
+		value, err := resolver.Resolve(context.Background(), ref)
+		if err != nil || value != want {
+			t.Errorf("Expected %s to resolve to %q, got %q (%v)", ref, want, value, err)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the metadata token to be cached, got %d token requests", tokenRequests)
+	}
+	if _, err := resolver.Resolve(context.Background(), "gcpsm://shop-prod/missing"); !errors.Is(err, secrets.ErrSecretNotFound) {
+		t.Errorf("Expected ErrSecretNotFound, got %v", err)
+	}
+}
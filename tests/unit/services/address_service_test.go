@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+func TestRuleBasedAddressValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name               string
+		address            orders.Address
+		expectDeliverable  bool
+		expectCorrected    bool
+		expectedPostalCode string
+		expectedCountry    string
+		expectedIssueField string
+	}{
+		{
+			name: "valid US address passes unchanged",
+			address: orders.Address{
+				FirstName: "John", LastName: "Doe", AddressLine1: "123 Main St",
+				City: "New York", State: "NY", PostalCode: "10001", Country: "US",
+			},
+			expectDeliverable:  true,
+			expectCorrected:    false,
+			expectedPostalCode: "10001",
+			expectedCountry:    "US",
+		},
+		{
+			name: "normalizes whitespace, case and country alias",
+			address: orders.Address{
+				FirstName: "John", LastName: "Doe", AddressLine1: "  123   Main St ",
+				City: "New York", State: "ny", PostalCode: "10001", Country: "usa",
+			},
+			expectDeliverable:  true,
+			expectCorrected:    true,
+			expectedPostalCode: "10001",
+			expectedCountry:    "US",
+		},
+		{
+			name: "formats Canadian postal code",
+			address: orders.Address{
+				FirstName: "Jane", LastName: "Doe", AddressLine1: "1 Yonge St",
+				City: "Toronto", State: "ON", PostalCode: "m5e1w7", Country: "CA",
+			},
+			expectDeliverable:  true,
+			expectCorrected:    true,
+			expectedPostalCode: "M5E 1W7",
+			expectedCountry:    "CA",
+		},
+		{
+			name: "rejects malformed US zip",
+			address: orders.Address{
+				FirstName: "John", LastName: "Doe", AddressLine1: "123 Main St",
+				City: "New York", State: "NY", PostalCode: "1000", Country: "US",
+			},
+			expectDeliverable:  false,
+			expectedPostalCode: "1000",
+			expectedCountry:    "US",
+			expectedIssueField: "postal_code",
+		},
+		{
+			name: "rejects unknown US state",
+			address: orders.Address{
+				FirstName: "John", LastName: "Doe", AddressLine1: "123 Main St",
+				City: "New York", State: "ZZ", PostalCode: "10001", Country: "US",
+			},
+			expectDeliverable:  false,
+			expectedPostalCode: "10001",
+			expectedCountry:    "US",
+			expectedIssueField: "state",
+		},
+	}
+
+	validator := services.NewRuleBasedAddressValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := validator.Validate(context.Background(), tt.address)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Deliverable != tt.expectDeliverable {
+				t.Errorf("expected deliverable=%v, got %v (issues: %v)", tt.expectDeliverable, result.Deliverable, result.Issues)
+			}
+			if tt.expectDeliverable && result.Corrected != tt.expectCorrected {
+				t.Errorf("expected corrected=%v, got %v", tt.expectCorrected, result.Corrected)
+			}
+			if result.Normalized.PostalCode != tt.expectedPostalCode {
+				t.Errorf("expected postal code %q, got %q", tt.expectedPostalCode, result.Normalized.PostalCode)
+			}
+			if result.Normalized.Country != tt.expectedCountry {
+				t.Errorf("expected country %q, got %q", tt.expectedCountry, result.Normalized.Country)
+			}
+			if tt.expectedIssueField != "" {
+				if len(result.Issues) == 0 || result.Issues[0].Field != tt.expectedIssueField {
+					t.Errorf("expected issue on %q, got %v", tt.expectedIssueField, result.Issues)
+				}
+			}
+		})
+	}
+}
+
+func TestAddressService_Normalize(t *testing.T) {
+	service := services.NewAddressService(services.NewRuleBasedAddressValidator())
+
+	_, err := service.Normalize(context.Background(), orders.Address{
+		FirstName: "John", LastName: "Doe", City: "New York", State: "NY", PostalCode: "10001", Country: "US",
+	})
+	validationErr, ok := err.(*services.AddressValidationError)
+	if !ok {
+		t.Fatalf("expected *AddressValidationError, got %v", err)
+	}
+	if validationErr.Issues[0].Field != "address1" {
+		t.Errorf("expected missing address1 issue, got %v", validationErr.Issues)
+	}
+}
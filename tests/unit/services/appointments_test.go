@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newAppointmentService(t *testing.T, cancelWindowHours int) (*services.AppointmentService, *mocks.MockAppointmentRepository, *mocks.MockProductRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	repo := mocks.NewMockAppointmentRepository()
+	appointments := services.NewAppointmentService(repo, productRepo)
+	product := &services.AppointmentProduct{ProductID: fixtures.ProductLaptop.ID, CancelWindowHours: cancelWindowHours}
+	if err := appointments.SetAppointmentProduct(context.Background(), product); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return appointments, repo, productRepo
+}
+
+func createAppointmentSlot(t *testing.T, appointments *services.AppointmentService, startsIn time.Duration, capacity int) *services.AppointmentSlot {
+	t.Helper()
+	start := time.Now().Add(startsIn).Truncate(time.Minute)
+	slot := &services.AppointmentSlot{
+		ProductID: fixtures.ProductLaptop.ID,
+		StartsAt:  start,
+		EndsAt:    start.Add(time.Hour),
+		Capacity:  capacity,
+		IsActive:  true,
+	}
+	if err := appointments.CreateSlot(context.Background(), slot); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return slot
+}
+
+func appointmentCart(id, slotID string, quantity int) *cart.Cart {
+	c := &cart.Cart{ID: id, UserID: fixtures.TestUserID}
+	c.AddItem(cart.CartItem{
+		ID: "item-1", ProductID: fixtures.ProductLaptop.ID, SKU: fixtures.ProductLaptop.SKU,
+		Price: money.Money{Amount: 5000, Currency: "USD"}, Quantity: quantity,
+		Attributes: map[string]string{services.AppointmentSlotAttribute: slotID},
+	})
+	return c
+}
+
+var appointmentAddress = orders.Address{FirstName: "Jane", LastName: "Doe", AddressLine1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"}
+
+func TestAppointments_SlotSelectionInCart(t *testing.T) {
+	ctx := context.Background()
+	appointments, _, productRepo := newAppointmentService(t, 0)
+	slot := createAppointmentSlot(t, appointments, 48*time.Hour, 2)
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil).
+		WithAppointments(appointments)
+	userCart, err := cartService.GetOrCreateCart(ctx, fixtures.TestUserID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	add := func(slotID string, quantity int) (*cart.Cart, error) {
+		return cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{
+			ProductID:  fixtures.ProductLaptop.ID,
+			Quantity:   quantity,
+			Attributes: map[string]string{services.AppointmentSlotAttribute: slotID},
+		})
+	}
+
+	if _, err := add("", 1); !errors.Is(err, services.ErrAppointmentSlotRequired) {
+		t.Errorf("expected ErrAppointmentSlotRequired, got %v", err)
+	}
+	if _, err := add("unknown", 1); !errors.Is(err, services.ErrAppointmentSlotUnavailable) {
+		t.Errorf("expected ErrAppointmentSlotUnavailable, got %v", err)
+	}
+	if _, err := add(slot.ID, 3); !errors.Is(err, services.ErrAppointmentSlotFull) {
+		t.Errorf("expected ErrAppointmentSlotFull, got %v", err)
+	}
+
+	updated, err := add(slot.ID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := updated.Items[0]
+	if item.Attributes[services.AppointmentStartsAttribute] != slot.StartsAt.UTC().Format(time.RFC3339) {
+		t.Errorf("expected the slot's start recorded on the item, got %v", item.Attributes)
+	}
+
+	if _, err := add(slot.ID, 1); !errors.Is(err, services.ErrAppointmentInCart) {
+		t.Errorf("expected ErrAppointmentInCart, got %v", err)
+	}
+	if _, err := cartService.UpdateItemQuantity(ctx, userCart.ID, item.ID, 3); !errors.Is(err, services.ErrAppointmentSlotFull) {
+		t.Errorf("expected ErrAppointmentSlotFull raising the quantity over the seats, got %v", err)
+	}
+	if _, err := cartService.UpdateItemQuantity(ctx, userCart.ID, item.ID, 2); err != nil {
+		t.Errorf("expected both seats to be bookable, got %v", err)
+	}
+}
+
+func TestAppointments_BookingsTakeSeatsUntilCanceled(t *testing.T) {
+	ctx := context.Background()
+	appointments, repo, _ := newAppointmentService(t, 0)
+	slot := createAppointmentSlot(t, appointments, 48*time.Hour, 3)
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil).WithAppointments(appointments)
+
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: appointmentCart("cart-1", slot.ID, 2), UserID: fixtures.TestUserID, ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bookings, err := appointments.OrderBookings(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].Quantity != 2 || bookings[0].SlotID != slot.ID || bookings[0].Status != services.AppointmentBooked {
+		t.Fatalf("expected the order's seats to be booked, got %+v", bookings)
+	}
+	if repo.Slots[slot.ID].Booked != 2 {
+		t.Errorf("expected 2 seats taken, got %d", repo.Slots[slot.ID].Booked)
+	}
+
+	_, err = orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: appointmentCart("cart-2", slot.ID, 2), UserID: fixtures.TestUserID, ShippingAddress: appointmentAddress})
+	if !errors.Is(err, services.ErrAppointmentSlotFull) {
+		t.Errorf("expected ErrAppointmentSlotFull for more seats than are left, got %v", err)
+	}
+
+	// Staff can't shrink the slot below the seats booked
+	if _, err := appointments.UpdateSlot(ctx, slot.ID, services.AppointmentSlot{StartsAt: slot.StartsAt, EndsAt: slot.EndsAt, Capacity: 1, IsActive: true}); !errors.Is(err, services.ErrInvalidAppointmentSlot) {
+		t.Errorf("expected ErrInvalidAppointmentSlot, got %v", err)
+	}
+
+	if _, err := orderService.CancelOrder(ctx, order.ID, "changed plans"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Slots[slot.ID].Booked != 0 {
+		t.Errorf("expected the seats freed, got %d booked", repo.Slots[slot.ID].Booked)
+	}
+	if bookings[0].Status != services.AppointmentCanceled || bookings[0].CanceledAt == nil {
+		t.Errorf("expected the booking canceled, got %+v", bookings[0])
+	}
+	available, err := appointments.AvailableSlots(ctx, fixtures.ProductLaptop.ID, time.Now(), time.Now().AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(available) != 1 || available[0].Remaining() != 3 {
+		t.Errorf("expected the slot listed with all 3 seats free, got %+v", available)
+	}
+}
+
+func TestAppointments_CancellationWindow(t *testing.T) {
+	ctx := context.Background()
+	appointments, _, _ := newAppointmentService(t, 48)
+	soon := createAppointmentSlot(t, appointments, 24*time.Hour, 5)
+	later := createAppointmentSlot(t, appointments, 96*time.Hour, 5)
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil).WithAppointments(appointments)
+	cancellations := services.NewOrderCancellationService(orderService, nil).WithAppointments(appointments)
+
+	soonOrder, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: appointmentCart("cart-1", soon.ID, 1), UserID: fixtures.TestUserID, ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	laterOrder, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: appointmentCart("cart-2", later.ID, 1), UserID: fixtures.TestUserID, ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	customer := services.CancelOrderRequest{Reason: "can't make it", CanceledBy: fixtures.TestUserID}
+	if _, err := cancellations.Cancel(ctx, soonOrder.ID, customer); !errors.Is(err, services.ErrAppointmentCancelWindowPassed) {
+		t.Errorf("expected ErrAppointmentCancelWindowPassed within 48 hours of the start, got %v", err)
+	}
+	if _, err := cancellations.Cancel(ctx, laterOrder.ID, customer); err != nil {
+		t.Errorf("expected the customer to cancel ahead of the window, got %v", err)
+	}
+	staff := services.CancelOrderRequest{Reason: "instructor ill", CanceledBy: "admin-1", Staff: true}
+	if _, err := cancellations.Cancel(ctx, soonOrder.ID, staff); err != nil {
+		t.Errorf("expected staff to cancel within the window, got %v", err)
+	}
+
+	if err := appointments.SetAppointmentProduct(ctx, &services.AppointmentProduct{ProductID: fixtures.ProductLaptop.ID, CancelWindowHours: 1000}); !errors.Is(err, services.ErrInvalidAppointmentProduct) {
+		t.Errorf("expected ErrInvalidAppointmentProduct, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newBarcodeService() *services.BarcodeService {
+	products := mocks.NewMockProductRepository()
+	products.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	variants := mocks.NewMockVariantRepository()
+	variants.Variants[fixtures.VariantTShirtSmallRed.ID] = fixtures.VariantTShirtSmallRed
+	variants.Variants[fixtures.VariantTShirtMediumBlue.ID] = fixtures.VariantTShirtMediumBlue
+
+	catalogService := services.NewCatalogService(products, variants, mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository())
+	repo := mocks.NewMockBarcodeRepository(fixtures.VariantTShirtSmallRed.ID, fixtures.VariantTShirtMediumBlue.ID)
+	return services.NewBarcodeService(repo, variants, catalogService)
+}
+
+func TestNormalizeBarcode(t *testing.T) {
+	valid := map[string]string{
+		"96385074":       "00000096385074", // GTIN-8
+		"036000291452":   "00036000291452", // UPC-A
+		"4006381333931":  "04006381333931", // EAN-13
+		"10614141000415": "10614141000415", // GTIN-14
+	}
+	for code, want := range valid {
+		got, err := services.NormalizeBarcode(code)
+		if err != nil || got != want {
+			t.Errorf("NormalizeBarcode(%q): expected %s, got %q (%v)", code, want, got, err)
+		}
+	}
+
+	invalid := []string{"", "4006381333932", "400638133393A", "12345", "040063813339310"}
+	for _, code := range invalid {
+		if _, err := services.NormalizeBarcode(code); err != services.ErrInvalidBarcode {
+			t.Errorf("Expected %q to be rejected, got %v", code, err)
+		}
+	}
+}
+
+func TestBarcode_AssignAndLookup(t *testing.T) {
+	service := newBarcodeService()
+	ctx := context.Background()
+
+	barcode, err := service.SetBarcode(ctx, fixtures.VariantTShirtSmallRed.ID, "036000291452")
+	if err != nil {
+		t.Fatalf("SetBarcode failed: %v", err)
+	}
+	if barcode != "00036000291452" {
+		t.Errorf("Expected the barcode stored as 14 digits, got %s", barcode)
+	}
+
+	// A UPC-A scanned in its EAN-13 form finds the same variant
+	scanned, err := service.Lookup(ctx, "0036000291452")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if scanned.Variant.ID != fixtures.VariantTShirtSmallRed.ID || scanned.Product.ID != fixtures.ProductTShirt.ID {
+		t.Errorf("Expected the small red t-shirt, got variant %s of product %s", scanned.Variant.ID, scanned.Product.ID)
+	}
+
+	if _, err := service.Lookup(ctx, "4006381333931"); err != services.ErrBarcodeNotFound {
+		t.Errorf("Expected ErrBarcodeNotFound, got %v", err)
+	}
+	if _, err := service.SetBarcode(ctx, "missing-variant", "4006381333931"); err != services.ErrVariantNotFound {
+		t.Errorf("Expected ErrVariantNotFound, got %v", err)
+	}
+}
+
+func TestBarcode_UniqueAcrossVariants(t *testing.T) {
+	service := newBarcodeService()
+	ctx := context.Background()
+
+	if _, err := service.SetBarcode(ctx, fixtures.VariantTShirtSmallRed.ID, "4006381333931"); err != nil {
+		t.Fatalf("SetBarcode failed: %v", err)
+	}
+	// Reassigning a variant its own barcode is allowed
+	if _, err := service.SetBarcode(ctx, fixtures.VariantTShirtSmallRed.ID, "4006381333931"); err != nil {
+		t.Errorf("Expected a variant to keep its own barcode, got %v", err)
+	}
+	if _, err := service.SetBarcode(ctx, fixtures.VariantTShirtMediumBlue.ID, "04006381333931"); err != services.ErrBarcodeTaken {
+		t.Errorf("Expected ErrBarcodeTaken, got %v", err)
+	}
+
+	if err := service.RemoveBarcode(ctx, fixtures.VariantTShirtSmallRed.ID); err != nil {
+		t.Fatalf("RemoveBarcode failed: %v", err)
+	}
+	if _, err := service.SetBarcode(ctx, fixtures.VariantTShirtMediumBlue.ID, "4006381333931"); err != nil {
+		t.Errorf("Expected the removed barcode to be free, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type bulkArchiveFixture struct {
+	svc      *services.BulkArchiveService
+	repo     *mocks.MockBulkArchiveRepository
+	products *mocks.MockProductRepository
+	variants *mocks.MockVariantRepository
+	changed  []string
+}
+
+func newBulkArchiveFixture(t *testing.T) *bulkArchiveFixture {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := jobs.NewRunner(1, 10)
+	runner.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+
+	f := &bulkArchiveFixture{
+		repo:     mocks.NewMockBulkArchiveRepository(),
+		products: mocks.NewMockProductRepository(),
+		variants: mocks.NewMockVariantRepository(),
+	}
+	f.svc = services.NewBulkArchiveService(f.repo, f.products, f.variants, runner).
+		WithBatchSize(2).
+		WithChangeHook(func(productIDs ...string) { f.changed = append(f.changed, productIDs...) })
+	return f
+}
+
+// wait polls a job until it finishes
+func (f *bulkArchiveFixture) wait(t *testing.T, id string) *services.BulkJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := f.svc.GetJob(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestBulkArchiveService_ArchiveProducts(t *testing.T) {
+	f := newBulkArchiveFixture(t)
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("prod-%d", i)
+		f.products.Products[id] = &catalog.Product{ID: id, Status: catalog.ProductStatusActive}
+	}
+	f.products.Products["prod-5"].Status = services.ProductStatusArchived
+
+	// Duplicates are archived once; a missing product is reported without stopping the job
+	job, err := f.svc.ArchiveProducts(context.Background(), "admin-1", []string{"prod-1", "prod-2", "prod-2", "prod-3", "prod-4", "prod-5", "missing"}, services.BulkArchiveFilter{})
+	if err != nil {
+		t.Fatalf("ArchiveProducts: %v", err)
+	}
+	if job.Status != services.BulkJobQueued || job.Total != 6 || job.CreatedBy != "admin-1" {
+		t.Errorf("expected a queued job of 6 by admin-1, got %+v", job)
+	}
+
+	done := f.wait(t, job.ID)
+	if done.Status != services.BulkJobCompleted {
+		t.Fatalf("expected completed, got %s (%s)", done.Status, done.Error)
+	}
+	if done.Processed != 6 || done.Archived != 4 || done.Failed != 1 || len(done.Errors) != 1 {
+		t.Errorf("expected 6 processed, 4 archived and 1 failed, got %+v", done)
+	}
+	for i := 1; i <= 4; i++ {
+		if status := f.products.Products[fmt.Sprintf("prod-%d", i)].Status; status != services.ProductStatusArchived {
+			t.Errorf("expected prod-%d archived, got %s", i, status)
+		}
+	}
+	sort.Strings(f.changed)
+	if fmt.Sprint(f.changed) != "[prod-1 prod-2 prod-3 prod-4]" {
+		t.Errorf("expected the archived products to be reported changed, got %v", f.changed)
+	}
+}
+
+func TestBulkArchiveService_ArchiveVariantsByFilter(t *testing.T) {
+	f := newBulkArchiveFixture(t)
+	f.variants.Variants["var-1"] = &catalog.Variant{ID: "var-1", ProductID: "prod-1", IsAvailable: true}
+	f.variants.Variants["var-2"] = &catalog.Variant{ID: "var-2", ProductID: "prod-1", IsAvailable: true}
+	f.variants.Variants["var-3"] = &catalog.Variant{ID: "var-3", ProductID: "prod-2", IsAvailable: true}
+	f.repo.VariantIDs = []string{"var-1", "var-2"}
+
+	filter := services.BulkArchiveFilter{ProductID: "prod-1"}
+	job, err := f.svc.ArchiveVariants(context.Background(), "admin-1", nil, filter)
+	if err != nil {
+		t.Fatalf("ArchiveVariants: %v", err)
+	}
+	if job.Filter == nil || *job.Filter != filter {
+		t.Errorf("expected the job to report its filter, got %+v", job.Filter)
+	}
+
+	done := f.wait(t, job.ID)
+	if done.Status != services.BulkJobCompleted || done.Total != 2 || done.Archived != 2 {
+		t.Fatalf("expected 2 of 2 variants archived, got %+v", done)
+	}
+	if f.variants.Variants["var-1"].IsAvailable || f.variants.Variants["var-2"].IsAvailable {
+		t.Error("expected the filtered variants to be archived")
+	}
+	if !f.variants.Variants["var-3"].IsAvailable {
+		t.Error("expected other variants to stay available")
+	}
+	if len(f.repo.Filters) != 1 || f.repo.Filters[0] != filter {
+		t.Errorf("expected the filter to select the variants, got %v", f.repo.Filters)
+	}
+	if fmt.Sprint(f.changed) != "[prod-1]" {
+		t.Errorf("expected prod-1 reported changed once, got %v", f.changed)
+	}
+}
+
+func TestBulkArchiveService_FailedLookup(t *testing.T) {
+	f := newBulkArchiveFixture(t)
+	f.repo.FindError = errors.New("database is down")
+
+	job, err := f.svc.ArchiveProducts(context.Background(), "admin-1", nil, services.BulkArchiveFilter{CategoryID: "cat-1"})
+	if err != nil {
+		t.Fatalf("ArchiveProducts: %v", err)
+	}
+	done := f.wait(t, job.ID)
+	if done.Status != services.BulkJobFailed || done.Error != "database is down" {
+		t.Errorf("expected the job to fail with the lookup error, got %+v", done)
+	}
+	if jobs := f.svc.ListJobs(context.Background()); len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Errorf("expected the failed job to be listed, got %v", jobs)
+	}
+}
+
+func TestBulkArchiveService_Validation(t *testing.T) {
+	f := newBulkArchiveFixture(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		start func() error
+	}{
+		{"nothing selected", func() error {
+			_, err := f.svc.ArchiveProducts(ctx, "admin-1", nil, services.BulkArchiveFilter{})
+			return err
+		}},
+		{"ids and filter", func() error {
+			_, err := f.svc.ArchiveProducts(ctx, "admin-1", []string{"prod-1"}, services.BulkArchiveFilter{BrandID: "brand-1"})
+			return err
+		}},
+		{"products by product", func() error {
+			_, err := f.svc.ArchiveProducts(ctx, "admin-1", nil, services.BulkArchiveFilter{ProductID: "prod-1"})
+			return err
+		}},
+		{"variants by status", func() error {
+			_, err := f.svc.ArchiveVariants(ctx, "admin-1", nil, services.BulkArchiveFilter{Status: "active"})
+			return err
+		}},
+		{"too many ids", func() error {
+			ids := make([]string, 10001)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("prod-%d", i)
+			}
+			_, err := f.svc.ArchiveProducts(ctx, "admin-1", ids, services.BulkArchiveFilter{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.start(); !errors.Is(err, services.ErrInvalidBulkArchive) {
+				t.Errorf("expected ErrInvalidBulkArchive, got %v", err)
+			}
+		})
+	}
+
+	if _, err := f.svc.GetJob(ctx, "missing"); !errors.Is(err, services.ErrBulkJobNotFound) {
+		t.Errorf("expected ErrBulkJobNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// stubTopSellers returns a fixed best-seller ranking
+type stubTopSellers []string
+
+func (s stubTopSellers) TopSellingProductIDs(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	if len(s) > limit {
+		return s[:limit], nil
+	}
+	return s, nil
+}
+
+func newWarmableCatalog() (*services.CatalogService, *mocks.MockProductRepository, *mocks.MockCategoryRepository) {
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductPhone.ID] = fixtures.ProductPhone
+	productRepo.SearchResults = []*catalog.Product{fixtures.ProductPhone, fixtures.ProductLaptop}
+
+	categoryRepo := mocks.NewMockCategoryRepository()
+	categoryRepo.Categories[fixtures.CategoryElectronics.ID] = fixtures.CategoryElectronics
+	categoryRepo.Categories[fixtures.CategoryClothing.ID] = fixtures.CategoryClothing
+
+	brandRepo := mocks.NewMockBrandRepository()
+	brandRepo.Brands[fixtures.BrandTechCorp.ID] = fixtures.BrandTechCorp
+
+	catalogService := services.NewCatalogService(productRepo, mocks.NewMockVariantRepository(), categoryRepo, brandRepo).
+		WithProductCache(services.NewProductCache(time.Minute), nil).
+		WithListCache(time.Minute)
+	return catalogService, productRepo, categoryRepo
+}
+
+func TestCacheWarmer_Warm(t *testing.T) {
+	catalogService, productRepo, categoryRepo := newWarmableCatalog()
+	warmer := services.NewCacheWarmer(catalogService, stubTopSellers{fixtures.ProductPhone.ID})
+
+	result, err := warmer.Warm(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if result.Products != 2 || result.Failed != 0 {
+		t.Errorf("Expected 2 warmed products, got %+v", result)
+	}
+	if result.Categories != 2 || result.Brands != 1 {
+		t.Errorf("Expected 2 categories and 1 brand, got %+v", result)
+	}
+
+	// Warmed entries are served without touching the repositories
+	delete(productRepo.Products, fixtures.ProductLaptop.ID)
+	delete(categoryRepo.Categories, fixtures.CategoryClothing.ID)
+	if _, err := catalogService.GetProduct(context.Background(), fixtures.ProductLaptop.ID); err != nil {
+		t.Errorf("Expected warmed product from cache, got %v", err)
+	}
+	categories, _ := catalogService.GetCategories(context.Background())
+	if len(categories) != 2 {
+		t.Errorf("Expected cached category list of 2, got %d", len(categories))
+	}
+
+	stats := catalogService.CacheStats()
+	if stats.Products == nil || stats.Products.Entries != 2 {
+		t.Errorf("Expected 2 cached products, got %+v", stats.Products)
+	}
+
+	catalogService.FlushCaches()
+	categories, _ = catalogService.GetCategories(context.Background())
+	if len(categories) != 1 {
+		t.Errorf("Expected category list reloaded after flush, got %d", len(categories))
+	}
+}
+
+func TestCacheWarmer_SkipsMissingProducts(t *testing.T) {
+	catalogService, _, _ := newWarmableCatalog()
+	warmer := services.NewCacheWarmer(catalogService, stubTopSellers{"prod-deleted", fixtures.ProductPhone.ID})
+
+	result, err := warmer.Warm(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if result.Products != 1 || result.Failed != 1 {
+		t.Errorf("Expected 1 warmed and 1 failed product, got %+v", result)
+	}
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestCalendar_EstimateDelivery(t *testing.T) {
+	ctx := context.Background()
+	calendar := services.NewCalendarService(mocks.NewMockCalendarRepository())
+
+	// Friday, before and after the default 14:00 cutoff
+	friday := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		orderedAt time.Time
+		want      services.DeliveryEstimate
+	}{
+		{"before cutoff ships same day", friday, services.DeliveryEstimate{ShipsOn: "2026-10-16", EarliestDelivery: "2026-10-20", LatestDelivery: "2026-10-21"}},
+		{"after cutoff ships next working day", friday.Add(5 * time.Hour), services.DeliveryEstimate{ShipsOn: "2026-10-19", EarliestDelivery: "2026-10-21", LatestDelivery: "2026-10-22"}},
+		{"weekend ships Monday", friday.AddDate(0, 0, 1), services.DeliveryEstimate{ShipsOn: "2026-10-19", EarliestDelivery: "2026-10-21", LatestDelivery: "2026-10-22"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calendar.EstimateDelivery(ctx, tt.orderedAt, 2, 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+
+	// A holiday on Monday pushes dispatch and transit past it
+	if _, err := calendar.AddHoliday(ctx, "2026-10-19", "Founders Day"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := calendar.EstimateDelivery(ctx, friday.Add(5*time.Hour), 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := services.DeliveryEstimate{ShipsOn: "2026-10-20", EarliestDelivery: "2026-10-22", LatestDelivery: "2026-10-23"}
+	if *got != want {
+		t.Errorf("expected %+v, got %+v", want, *got)
+	}
+
+	working, err := calendar.IsWorkingDay(ctx, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	if err != nil || working {
+		t.Errorf("expected the holiday not to be a working day, got %v, %v", working, err)
+	}
+}
+
+func TestCalendar_Update(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockCalendarRepository()
+	calendar := services.NewCalendarService(repo)
+
+	if _, err := calendar.UpdateCalendar(ctx, "Mars/Olympus", []time.Weekday{time.Monday}, "14:00"); err != services.ErrInvalidCalendar {
+		t.Errorf("expected ErrInvalidCalendar for an unknown timezone, got %v", err)
+	}
+	if _, err := calendar.UpdateCalendar(ctx, "UTC", nil, "14:00"); err != services.ErrInvalidCalendar {
+		t.Errorf("expected ErrInvalidCalendar without working days, got %v", err)
+	}
+	if _, err := calendar.UpdateCalendar(ctx, "UTC", []time.Weekday{time.Monday}, "2pm"); err != services.ErrInvalidCalendar {
+		t.Errorf("expected ErrInvalidCalendar for a bad cutoff, got %v", err)
+	}
+
+	// Saturdays become working days and the cutoff moves to 16:00
+	updated, err := calendar.UpdateCalendar(ctx, "UTC", []time.Weekday{time.Saturday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Monday}, "16:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.WorkingDays) != 6 || updated.WorkingDays[0] != time.Monday || updated.WorkingDays[5] != time.Saturday {
+		t.Errorf("expected six sorted working days, got %v", updated.WorkingDays)
+	}
+
+	got, err := calendar.EstimateDelivery(ctx, time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC), 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ShipsOn != "2026-10-16" || got.LatestDelivery != "2026-10-17" {
+		t.Errorf("expected same-day dispatch and Saturday delivery, got %+v", got)
+	}
+
+	if _, err := calendar.AddHoliday(ctx, "10/19/2026", "Founders Day"); err != services.ErrInvalidHoliday {
+		t.Errorf("expected ErrInvalidHoliday, got %v", err)
+	}
+	if err := calendar.RemoveHoliday(ctx, "2026-10-19"); err != services.ErrHolidayNotFound {
+		t.Errorf("expected ErrHolidayNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+var testMetadataPolicy = services.MetadataPolicy{
+	AllowedKeys:    []string{"campaign_id", "gift_message"},
+	MaxKeys:        2,
+	MaxValueLength: 20,
+}
+
+func TestMetadataPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata services.Metadata
+		wantErr  bool
+	}{
+		{name: "allowed keys", metadata: services.Metadata{"campaign_id": "spring-24", "gift_message": "Enjoy!"}},
+		{name: "empty", metadata: nil},
+		{name: "key not allowed", metadata: services.Metadata{"internal_flag": "1"}, wantErr: true},
+		{name: "too many keys", metadata: services.Metadata{"campaign_id": "a", "gift_message": "b", "other": "c"}, wantErr: true},
+		{name: "value too long", metadata: services.Metadata{"gift_message": strings.Repeat("x", 21)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testMetadataPolicy.Validate(tt.metadata)
+			if tt.wantErr && !errors.Is(err, services.ErrInvalidMetadata) {
+				t.Errorf("expected ErrInvalidMetadata, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCartMetadataService_SetItemMetadata(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockCartMetadataRepository()
+	svc := services.NewCartMetadataService(repo, testMetadataPolicy)
+	userCart := &cart.Cart{ID: "cart-1", Items: []cart.CartItem{{ID: "item-1", ProductID: "prod-1", Quantity: 1}}}
+
+	if err := svc.SetItemMetadata(ctx, userCart, "item-1", services.Metadata{"gift_message": "Happy birthday"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.SetItemMetadata(ctx, userCart, "item-2", services.Metadata{"gift_message": "Hi"}); err != cart.ErrItemNotFound {
+		t.Errorf("expected ErrItemNotFound for an item not in the cart, got %v", err)
+	}
+	if err := svc.SetItemMetadata(ctx, userCart, "item-1", services.Metadata{"secret": "x"}); !errors.Is(err, services.ErrInvalidMetadata) {
+		t.Errorf("expected ErrInvalidMetadata, got %v", err)
+	}
+
+	metadata, err := svc.GetCartMetadata(ctx, "cart-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Items["item-1"]["gift_message"] != "Happy birthday" {
+		t.Errorf("expected the item's gift message to be kept, got %v", metadata.Items)
+	}
+}
+
+func TestCartMetadataService_CopyToOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockCartMetadataRepository()
+	svc := services.NewCartMetadataService(repo, testMetadataPolicy)
+
+	userCart := &cart.Cart{ID: "cart-1", Items: []cart.CartItem{
+		{ID: "item-1", ProductID: "prod-1", Quantity: 1},
+		{ID: "item-2", ProductID: "prod-2", Quantity: 2},
+	}}
+	if err := svc.SetCartMetadata(ctx, userCart.ID, services.Metadata{"campaign_id": "spring-24"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.SetItemMetadata(ctx, userCart, "item-2", services.Metadata{"gift_message": "Enjoy!"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Order items are created in cart item order with new IDs
+	order := &orders.Order{ID: "order-1", Items: []orders.OrderItem{
+		{ID: "order-item-1", ProductID: "prod-1", Quantity: 1},
+		{ID: "order-item-2", ProductID: "prod-2", Quantity: 2},
+	}}
+	if err := svc.CopyToOrder(ctx, userCart, order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, err := svc.GetOrderMetadata(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.Metadata["campaign_id"] != "spring-24" {
+		t.Errorf("expected the cart's campaign ID on the order, got %v", metadata.Metadata)
+	}
+	if len(metadata.Items) != 1 || metadata.Items["order-item-2"]["gift_message"] != "Enjoy!" {
+		t.Errorf("expected the gift message on the second order item, got %v", metadata.Items)
+	}
+
+	// A cart without metadata leaves nothing on its order
+	if err := svc.CopyToOrder(ctx, &cart.Cart{ID: "cart-2"}, &orders.Order{ID: "order-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := repo.Orders["order-2"]; ok {
+		t.Error("expected no metadata to be saved for a cart without any")
+	}
+}
@@ -0,0 +1,140 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func setupCatalogHistory() (*services.CatalogHistoryService, *mocks.MockCatalogVersionRepository, *mocks.MockProductRepository, *mocks.MockVariantRepository) {
+	versionRepo := mocks.NewMockCatalogVersionRepository()
+	productRepo := mocks.NewMockProductRepository()
+	variantRepo := mocks.NewMockVariantRepository()
+	return services.NewCatalogHistoryService(versionRepo, productRepo, variantRepo), versionRepo, productRepo, variantRepo
+}
+
+func historyProduct(name string, price int64) *catalog.Product {
+	return &catalog.Product{
+		ID:        "prod-1",
+		SKU:       "TEE-1",
+		Name:      name,
+		BasePrice: money.Money{Amount: price, Currency: "USD"},
+		Status:    catalog.ProductStatusActive,
+	}
+}
+
+func historyVariant(id string, price int64) *catalog.Variant {
+	return &catalog.Variant{
+		ID:          id,
+		ProductID:   "prod-1",
+		SKU:         "TEE-1-" + id,
+		Name:        "Size " + id,
+		Price:       money.Money{Amount: price, Currency: "USD"},
+		IsAvailable: true,
+	}
+}
+
+func TestCatalogHistoryService_RecordsChanges(t *testing.T) {
+	history, versionRepo, _, _ := setupCatalogHistory()
+	ctx := services.WithCatalogActor(context.Background(), "admin-1")
+	products := history.Products()
+
+	if err := products.Save(ctx, historyProduct("Tee", 2000)); err != nil {
+		t.Fatalf("failed to save product: %v", err)
+	}
+	if err := products.Save(ctx, historyProduct("Classic Tee", 2500)); err != nil {
+		t.Fatalf("failed to update product: %v", err)
+	}
+	// A save that changes nothing is not a version
+	if err := products.Save(ctx, historyProduct("Classic Tee", 2500)); err != nil {
+		t.Fatalf("failed to save product: %v", err)
+	}
+	if err := history.Variants().Save(ctx, historyVariant("m", 2500)); err != nil {
+		t.Fatalf("failed to save variant: %v", err)
+	}
+
+	versions, _ := history.ListHistory(ctx, "prod-1", 10, 0)
+	if len(versions) != 3 || len(versionRepo.Versions) != 3 {
+		t.Fatalf("expected 3 versions, got %d", len(versions))
+	}
+
+	variant, updated, created := versions[0], versions[1], versions[2]
+	if created.Version != 1 || created.Action != services.CatalogChangeCreated || string(created.Before) != "null" {
+		t.Errorf("expected version 1 to record the creation, got %+v", created)
+	}
+	if variant.Version != 3 || variant.EntityType != services.CatalogEntityVariant || variant.EntityID != "m" {
+		t.Errorf("expected the variant change in the product's history, got %+v", variant)
+	}
+
+	if updated.Action != services.CatalogChangeUpdated || updated.ChangedBy != "admin-1" {
+		t.Errorf("expected an update by admin-1, got %s by %q", updated.Action, updated.ChangedBy)
+	}
+	if len(updated.Changes) != 2 {
+		t.Fatalf("expected name and base_price to change, got %v", updated.Changes)
+	}
+	if change := updated.Changes["name"]; change.From != "Tee" || change.To != "Classic Tee" {
+		t.Errorf("unexpected name change: %+v", change)
+	}
+}
+
+func TestCatalogHistoryService_Revert(t *testing.T) {
+	history, _, productRepo, variantRepo := setupCatalogHistory()
+	ctx := context.Background()
+	products, variants := history.Products(), history.Variants()
+
+	products.Save(ctx, historyProduct("Tee", 2000))
+	variants.Save(ctx, historyVariant("m", 2000))
+	variants.Save(ctx, historyVariant("l", 2000))
+
+	// A bad bulk edit after version 3: reprice everything, drop a size and add another
+	products.Save(ctx, historyProduct("Tee", 20))
+	variants.Save(ctx, historyVariant("m", 20))
+	variants.Delete(ctx, "l")
+	variants.Save(ctx, historyVariant("xl", 20))
+
+	var invalidated []string
+	history.WithRevertHook(func(ids ...string) { invalidated = append(invalidated, ids...) })
+
+	reverted, err := history.Revert(ctx, "prod-1", 3, "admin-1")
+	if err != nil {
+		t.Fatalf("failed to revert: %v", err)
+	}
+	if len(reverted) != 4 {
+		t.Fatalf("expected 4 records restored, got %d", len(reverted))
+	}
+	for _, v := range reverted {
+		if v.Action != services.CatalogChangeReverted || v.RevertedTo != 3 || v.ChangedBy != "admin-1" {
+			t.Errorf("expected a revert to version 3 by admin-1, got %+v", v)
+		}
+	}
+
+	if product := productRepo.Products["prod-1"]; product.BasePrice.Amount != 2000 {
+		t.Errorf("expected the product price restored to 2000, got %d", product.BasePrice.Amount)
+	}
+	if v, ok := variantRepo.Variants["m"]; !ok || v.Price.Amount != 2000 {
+		t.Errorf("expected variant m restored to 2000, got %+v", v)
+	}
+	if _, ok := variantRepo.Variants["l"]; !ok {
+		t.Error("expected the deleted variant l to be recreated")
+	}
+	if _, ok := variantRepo.Variants["xl"]; ok {
+		t.Error("expected variant xl, created after version 3, to be removed")
+	}
+	if len(invalidated) != 1 || invalidated[0] != "prod-1" {
+		t.Errorf("expected the revert hook to be called for prod-1, got %v", invalidated)
+	}
+
+	// Nothing has changed since the revert's last version
+	latest, _ := history.CountHistory(ctx, "prod-1")
+	if _, err := history.Revert(ctx, "prod-1", int(latest), "admin-1"); err != services.ErrNothingToRevert {
+		t.Errorf("expected ErrNothingToRevert, got %v", err)
+	}
+	if _, err := history.Revert(ctx, "prod-1", 99, "admin-1"); err != services.ErrCatalogVersionNotFound {
+		t.Errorf("expected ErrCatalogVersionNotFound, got %v", err)
+	}
+}
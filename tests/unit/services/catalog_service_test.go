@@ -190,6 +190,44 @@ func TestCatalogService_ListProducts(t *testing.T) {
 	}
 }
 
+func TestCatalogService_ListProductsBatchesSalePrices(t *testing.T) {
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductPhone.ID] = fixtures.ProductPhone
+	productRepo.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	resolver := mocks.NewMockSalePriceResolver()
+	resolver.AddPrice(fixtures.ProductPhone.ID, 69900, "USD")
+
+	svc := services.NewCatalogService(productRepo, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithSalePriceResolver(resolver)
+
+	result, err := svc.ListProducts(context.Background(), catalog.ProductFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolver.FindEffectivePricesCalls != 1 || resolver.FindEffectivePriceCalls != 0 {
+		t.Errorf("expected one batched lookup, got %d batched and %d single", resolver.FindEffectivePricesCalls, resolver.FindEffectivePriceCalls)
+	}
+	for _, product := range result {
+		onSale := product.ID == fixtures.ProductPhone.ID
+		if onSale != (product.SalePrice != nil) {
+			t.Errorf("product %s: expected sale price %v, got %+v", product.ID, onSale, product.SalePrice)
+		}
+	}
+
+	// A failed lookup lists the products without sale prices
+	resolver.FindEffectivePricesError = errors.New("database error")
+	result, err = svc.ListProducts(context.Background(), catalog.ProductFilter{})
+	if err != nil || len(result) != 3 {
+		t.Fatalf("expected 3 products despite the failed lookup, got %d (%v)", len(result), err)
+	}
+	for _, product := range result {
+		if product.SalePrice != nil {
+			t.Errorf("expected no sale price for %s, got %+v", product.ID, product.SalePrice)
+		}
+	}
+}
+
 func TestCatalogService_SearchProducts(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func setupCategoryCounts() (*services.CategoryCountService, *mocks.MockCategoryCountRepository, *mocks.MockProductRepository) {
+	parent := func(id string) *string { return &id }
+	categories := mocks.NewMockCategoryRepository()
+	categories.Categories["clothing"] = &catalog.Category{ID: "clothing", Name: "Clothing", DisplayOrder: 2}
+	categories.Categories["electronics"] = &catalog.Category{ID: "electronics", Name: "Electronics", DisplayOrder: 1}
+	categories.Categories["phones"] = &catalog.Category{ID: "phones", ParentID: parent("electronics"), Name: "Phones"}
+	categories.Categories["cases"] = &catalog.Category{ID: "cases", ParentID: parent("phones"), Name: "Cases"}
+	categories.Categories["laptops"] = &catalog.Category{ID: "laptops", ParentID: parent("electronics"), Name: "Laptops"}
+
+	products := mocks.NewMockProductRepository()
+	repo := mocks.NewMockCategoryCountRepository(products)
+	return services.NewCategoryCountService(repo, categories), repo, products
+}
+
+func countedProduct(id, categoryID string, status catalog.ProductStatus) *catalog.Product {
+	return &catalog.Product{ID: id, Name: id, CategoryID: categoryID, Status: status}
+}
+
+func TestCategoryCountService_MaintainsCounters(t *testing.T) {
+	counts, repo, _ := setupCategoryCounts()
+	products := counts.Products(mocks.NewMockProductRepository())
+	ctx := context.Background()
+
+	for _, product := range []*catalog.Product{
+		countedProduct("phone-1", "phones", catalog.ProductStatusActive),
+		countedProduct("phone-2", "phones", catalog.ProductStatusActive),
+		countedProduct("case-1", "cases", catalog.ProductStatusActive),
+		countedProduct("laptop-1", "laptops", catalog.ProductStatusDraft),
+	} {
+		if err := products.Save(ctx, product); err != nil {
+			t.Fatalf("failed to save %s: %v", product.ID, err)
+		}
+	}
+	if repo.Counts["phones"] != 2 || repo.Counts["cases"] != 1 || repo.Counts["laptops"] != 0 {
+		t.Fatalf("expected 2 phones, 1 case and no active laptops, got %v", repo.Counts)
+	}
+
+	// Editing a product in place counts it once; moving it moves its count
+	phone, _ := products.FindByID(ctx, "phone-1")
+	phone.Name = "Phone One"
+	if err := products.Save(ctx, phone); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if repo.Counts["phones"] != 2 {
+		t.Errorf("expected an edit to leave the count at 2, got %d", repo.Counts["phones"])
+	}
+	if err := products.Save(ctx, countedProduct("phone-2", "cases", catalog.ProductStatusActive)); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if repo.Counts["phones"] != 1 || repo.Counts["cases"] != 2 {
+		t.Errorf("expected the moved product counted in cases, got %v", repo.Counts)
+	}
+
+	// Activating counts a product, deactivating and deleting uncount it
+	if err := products.Save(ctx, countedProduct("laptop-1", "laptops", catalog.ProductStatusActive)); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := products.Save(ctx, countedProduct("case-1", "cases", catalog.ProductStatusDraft)); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if err := products.Delete(ctx, "phone-1"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if repo.Counts["laptops"] != 1 || repo.Counts["cases"] != 1 || repo.Counts["phones"] != 0 {
+		t.Errorf("expected 1 laptop, 1 case and no phones, got %v", repo.Counts)
+	}
+}
+
+func TestCategoryCountService_Tree(t *testing.T) {
+	counts, repo, _ := setupCategoryCounts()
+	repo.Counts["electronics"] = 1
+	repo.Counts["phones"] = 4
+	repo.Counts["cases"] = 3
+	repo.Counts["laptops"] = 2
+	repo.Counts["clothing"] = 5
+
+	tree, err := counts.Tree(context.Background())
+	if err != nil {
+		t.Fatalf("failed to build tree: %v", err)
+	}
+	if len(tree) != 2 || tree[0].ID != "electronics" || tree[1].ID != "clothing" {
+		t.Fatalf("expected electronics then clothing as roots, got %d roots", len(tree))
+	}
+	electronics := tree[0]
+	if electronics.ProductCount != 1 || electronics.TotalProductCount != 10 {
+		t.Errorf("expected electronics with 1 product and 10 in total, got %d and %d", electronics.ProductCount, electronics.TotalProductCount)
+	}
+	if len(electronics.Children) != 2 || electronics.Children[0].ID != "laptops" || electronics.Children[1].ID != "phones" {
+		t.Fatalf("expected laptops and phones under electronics, sorted by name")
+	}
+	phones := electronics.Children[1]
+	if phones.TotalProductCount != 7 || len(phones.Children) != 1 || phones.Children[0].TotalProductCount != 3 {
+		t.Errorf("expected phones with 7 products including 3 cases, got %d", phones.TotalProductCount)
+	}
+	if tree[1].TotalProductCount != 5 || len(tree[1].Children) != 0 {
+		t.Errorf("expected clothing with 5 products and no children, got %d", tree[1].TotalProductCount)
+	}
+}
+
+func TestCategoryCountService_Repair(t *testing.T) {
+	counts, repo, products := setupCategoryCounts()
+	products.Products["phone-1"] = countedProduct("phone-1", "phones", catalog.ProductStatusActive)
+	products.Products["phone-2"] = countedProduct("phone-2", "phones", catalog.ProductStatusActive)
+	products.Products["laptop-1"] = countedProduct("laptop-1", "laptops", catalog.ProductStatusDraft)
+
+	// Products written without the counters, plus a counter left behind
+	repo.Counts["phones"] = 1
+	repo.Counts["clothing"] = 3
+
+	fixed, err := counts.Repair(context.Background())
+	if err != nil {
+		t.Fatalf("failed to repair: %v", err)
+	}
+	if fixed != 2 || repo.Counts["phones"] != 2 || repo.Counts["clothing"] != 0 || repo.Counts["laptops"] != 0 {
+		t.Errorf("expected 2 counters fixed to 2 phones and no clothing, got %d fixed: %v", fixed, repo.Counts)
+	}
+	if fixed, _ := counts.Repair(context.Background()); fixed != 0 {
+		t.Errorf("expected nothing left to fix, got %d", fixed)
+	}
+}
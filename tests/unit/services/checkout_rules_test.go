@@ -0,0 +1,134 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newRuleCart(items ...cart.CartItem) *cart.Cart {
+	return &cart.Cart{ID: "cart-1", UserID: fixtures.TestUserID, Items: items}
+}
+
+// violationsByType indexes violations by rule type; each test cart fails a type at most once
+func violationsByType(violations []services.CheckoutRuleViolation) map[services.CheckoutRuleType]services.CheckoutRuleViolation {
+	byType := make(map[services.CheckoutRuleType]services.CheckoutRuleViolation, len(violations))
+	for _, violation := range violations {
+		byType[violation.Type] = violation
+	}
+	return byType
+}
+
+func TestCheckoutRules_Evaluate(t *testing.T) {
+	ctx := context.Background()
+	rules := services.NewCheckoutRuleService(mocks.NewMockCheckoutRuleRepository()).
+		WithCurrencyService(services.NewCurrencyService("USD", map[string]float64{"EUR": 0.5}))
+
+	create := []*services.CheckoutRule{
+		{Name: "Minimum order", Type: services.CheckoutRuleMinOrderValue, MinOrderValue: &money.Money{Amount: 5000, Currency: "usd"}, IsActive: true},
+		{Name: "No batteries abroad", Type: services.CheckoutRuleRestrictedCountries, ProductID: "prod-battery", Countries: []string{" ca", "MX"}, IsActive: true},
+		{Name: "Sold in sixes", Type: services.CheckoutRuleQuantityMultiple, ProductID: "prod-bottle", Multiple: 6, IsActive: true},
+		{Name: "Retired rule", Type: services.CheckoutRuleQuantityMultiple, ProductID: "prod-battery", Multiple: 4, IsActive: false},
+	}
+	for _, rule := range create {
+		if err := rules.CreateRule(ctx, rule); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if create[1].Countries[0] != "CA" || create[0].MinOrderValue.Currency != "USD" {
+		t.Errorf("expected country and currency codes to be normalized, got %v and %s", create[1].Countries, create[0].MinOrderValue.Currency)
+	}
+
+	// $30 of batteries and 7 bottles fail the minimum and the multiple; the country isn't known yet
+	c := newRuleCart(
+		cart.CartItem{ID: "item-1", ProductID: "prod-battery", Price: money.Money{Amount: 1000, Currency: "USD"}, Quantity: 1},
+		cart.CartItem{ID: "item-2", ProductID: "prod-bottle", Price: money.Money{Amount: 200, Currency: "USD"}, Quantity: 7},
+	)
+	violations, err := rules.Evaluate(ctx, c, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	byType := violationsByType(violations)
+	if minimum := byType[services.CheckoutRuleMinOrderValue]; minimum.Minimum == nil || minimum.Minimum.Amount != 5000 || minimum.Subtotal.Amount != 2400 {
+		t.Errorf("expected the minimum order value violation, got %+v", minimum)
+	}
+	if multiple := byType[services.CheckoutRuleQuantityMultiple]; multiple.Multiple != 6 || multiple.Quantity != 7 {
+		t.Errorf("expected the quantity multiple violation, got %+v", multiple)
+	}
+
+	// Shipping to Canada adds the country restriction
+	violations, err = rules.Evaluate(ctx, c, "ca")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restricted := violationsByType(violations)[services.CheckoutRuleRestrictedCountries]
+	if len(violations) != 3 || restricted.Country != "CA" || restricted.ProductID != "prod-battery" {
+		t.Errorf("expected the battery to be restricted in CA, got %+v", violations)
+	}
+
+	// The $50 minimum is €25 for a euro cart
+	euroCart := newRuleCart(cart.CartItem{ID: "item-3", ProductID: "prod-bottle", Price: money.Money{Amount: 500, Currency: "EUR"}, Quantity: 6})
+	if err := rules.Check(ctx, euroCart, "US"); err != nil {
+		t.Errorf("expected €30 of bottles shipped to the US to pass, got %v", err)
+	}
+}
+
+func TestCheckoutRules_OrderCreation(t *testing.T) {
+	ctx := context.Background()
+	rules := services.NewCheckoutRuleService(mocks.NewMockCheckoutRuleRepository())
+	if err := rules.CreateRule(ctx, &services.CheckoutRule{Name: "Minimum order", Type: services.CheckoutRuleMinOrderValue, MinOrderValue: &money.Money{Amount: 5000, Currency: "USD"}, IsActive: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), nil, nil, nil).WithCheckoutRules(rules)
+	_, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{
+		Cart:            newRuleCart(cart.CartItem{ID: "item-1", ProductID: "prod-1", Price: money.Money{Amount: 1000, Currency: "USD"}, Quantity: 2}),
+		UserID:          fixtures.TestUserID,
+		ShippingAddress: orders.Address{Country: "US"},
+	})
+	var ruleErr *services.CheckoutRuleError
+	if !errors.As(err, &ruleErr) || len(ruleErr.Violations) != 1 || ruleErr.Violations[0].Type != services.CheckoutRuleMinOrderValue {
+		t.Fatalf("expected the order to be rejected for its minimum order value, got %v", err)
+	}
+	if ruleErr.Error() != "minimum order value is "+ruleErr.Violations[0].Minimum.String() {
+		t.Errorf("expected a single violation's message as the error, got %q", ruleErr.Error())
+	}
+}
+
+func TestCheckoutRules_CreateRule(t *testing.T) {
+	ctx := context.Background()
+	rules := services.NewCheckoutRuleService(mocks.NewMockCheckoutRuleRepository())
+
+	invalid := []*services.CheckoutRule{
+		{Type: services.CheckoutRuleMinOrderValue, MinOrderValue: &money.Money{Amount: 5000, Currency: "USD"}},
+		{Name: "No amount", Type: services.CheckoutRuleMinOrderValue},
+		{Name: "Zero amount", Type: services.CheckoutRuleMinOrderValue, MinOrderValue: &money.Money{Amount: 0, Currency: "USD"}},
+		{Name: "No product", Type: services.CheckoutRuleRestrictedCountries, Countries: []string{"CA"}},
+		{Name: "Bad country", Type: services.CheckoutRuleRestrictedCountries, ProductID: "prod-1", Countries: []string{"CAN"}},
+		{Name: "Multiple of one", Type: services.CheckoutRuleQuantityMultiple, ProductID: "prod-1", Multiple: 1},
+		{Name: "Unknown", Type: "max_weight"},
+	}
+	for _, rule := range invalid {
+		if err := rules.CreateRule(ctx, rule); !errors.Is(err, services.ErrInvalidCheckoutRule) {
+			t.Errorf("expected ErrInvalidCheckoutRule for %+v, got %v", rule, err)
+		}
+	}
+
+	if err := rules.UpdateRule(ctx, &services.CheckoutRule{ID: "missing", Name: "Sold in pairs", Type: services.CheckoutRuleQuantityMultiple, ProductID: "prod-1", Multiple: 2}); err != services.ErrCheckoutRuleNotFound {
+		t.Errorf("expected ErrCheckoutRuleNotFound, got %v", err)
+	}
+	if err := rules.DeleteRule(ctx, "missing"); err != services.ErrCheckoutRuleNotFound {
+		t.Errorf("expected ErrCheckoutRuleNotFound, got %v", err)
+	}
+}
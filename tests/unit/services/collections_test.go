@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newCollectionService() (*services.CollectionService, *mocks.MockCollectionRepository) {
+	repo := mocks.NewMockCollectionRepository()
+	for _, product := range []*catalog.Product{fixtures.ProductLaptop, fixtures.ProductPhone, fixtures.ProductTShirt, fixtures.ProductInactive} {
+		repo.Products[product.ID] = product
+	}
+
+	catalogService := services.NewCatalogService(mocks.NewMockProductRepository(), mocks.NewMockVariantRepository(),
+		mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository())
+	return services.NewCollectionService(repo, catalogService), repo
+}
+
+func TestParseCollectionRule(t *testing.T) {
+	conditions, err := services.ParseCollectionRule("price < 49.99 AND category = cat-clothing and tag != clearance")
+	if err != nil {
+		t.Fatalf("ParseCollectionRule failed: %v", err)
+	}
+	if len(conditions) != 3 {
+		t.Fatalf("Expected 3 conditions, got %d", len(conditions))
+	}
+	if conditions[0].Field != "price" || conditions[0].Operator != "<" || conditions[0].Cents != 4999 {
+		t.Errorf("Expected price below 4999 cents, got %+v", conditions[0])
+	}
+
+	invalid := []string{"", "price < cheap", "colour = red", "category < cat-1", "price <"}
+	for _, rule := range invalid {
+		if _, err := services.ParseCollectionRule(rule); err == nil {
+			t.Errorf("Expected rule %q to be rejected", rule)
+		}
+	}
+}
+
+func TestCollection_RuleSelectsMatchingActiveProducts(t *testing.T) {
+	service, repo := newCollectionService()
+	ctx := context.Background()
+
+	collection := &services.Collection{
+		Slug:   "Electronics-Under-900",
+		Title:  "Electronics under $900",
+		Type:   services.CollectionRule,
+		Rule:   "price < 900 and category = cat-electronics",
+		Status: services.CollectionStatusPublished,
+	}
+	if err := service.SaveCollection(ctx, collection); err != nil {
+		t.Fatalf("SaveCollection failed: %v", err)
+	}
+
+	published, err := service.GetPublishedCollection(ctx, "electronics-under-900")
+	if err != nil {
+		t.Fatalf("GetPublishedCollection failed: %v", err)
+	}
+
+	// The laptop is too expensive and the inactive product is never shown
+	products, err := service.ListProducts(ctx, published, 20, 0)
+	if err != nil {
+		t.Fatalf("ListProducts failed: %v", err)
+	}
+	if len(products) != 1 || products[0].ID != fixtures.ProductPhone.ID {
+		t.Errorf("Expected only the phone, got %d products", len(products))
+	}
+
+	// Tags narrow rule collections as products are tagged
+	collection.Rule = "category = cat-electronics and tag = featured"
+	if err := service.SaveCollection(ctx, collection); err != nil {
+		t.Fatalf("SaveCollection failed: %v", err)
+	}
+	if _, err := service.SetProductTags(ctx, fixtures.ProductLaptop.ID, []string{" Featured ", "featured", "new"}); err != nil {
+		t.Fatalf("SetProductTags failed: %v", err)
+	}
+	if tags := repo.Tags[fixtures.ProductLaptop.ID]; len(tags) != 2 {
+		t.Errorf("Expected tags to be normalized and deduplicated, got %v", tags)
+	}
+
+	total, err := service.CountProducts(ctx, collection)
+	if err != nil {
+		t.Fatalf("CountProducts failed: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected 1 tagged product, got %d", total)
+	}
+}
+
+func TestCollection_ManualKeepsMerchandisedOrder(t *testing.T) {
+	service, _ := newCollectionService()
+	ctx := context.Background()
+
+	collection := &services.Collection{
+		Slug:       "staff-picks",
+		Title:      "Staff picks",
+		Type:       services.CollectionManual,
+		ProductIDs: []string{fixtures.ProductTShirt.ID, fixtures.ProductInactive.ID, fixtures.ProductLaptop.ID, fixtures.ProductTShirt.ID},
+		Status:     services.CollectionStatusPublished,
+	}
+	if err := service.SaveCollection(ctx, collection); err != nil {
+		t.Fatalf("SaveCollection failed: %v", err)
+	}
+	if len(collection.ProductIDs) != 3 {
+		t.Errorf("Expected duplicate product IDs to be dropped, got %v", collection.ProductIDs)
+	}
+
+	products, err := service.ListProducts(ctx, collection, 20, 0)
+	if err != nil {
+		t.Fatalf("ListProducts failed: %v", err)
+	}
+	if len(products) != 2 || products[0].ID != fixtures.ProductTShirt.ID || products[1].ID != fixtures.ProductLaptop.ID {
+		t.Errorf("Expected the t-shirt then the laptop, got %d products", len(products))
+	}
+}
+
+func TestCollection_Validation(t *testing.T) {
+	service, _ := newCollectionService()
+	ctx := context.Background()
+
+	draft := &services.Collection{Slug: "summer", Title: "Summer", Type: services.CollectionManual}
+	if err := service.SaveCollection(ctx, draft); err != nil {
+		t.Fatalf("SaveCollection failed: %v", err)
+	}
+	if draft.Status != services.CollectionStatusDraft {
+		t.Errorf("Expected new collections to default to draft, got %s", draft.Status)
+	}
+	if _, err := service.GetPublishedCollection(ctx, "summer"); err != services.ErrCollectionNotFound {
+		t.Errorf("Expected drafts to be hidden, got %v", err)
+	}
+
+	duplicate := &services.Collection{Slug: "summer", Title: "Summer again", Type: services.CollectionManual}
+	if err := service.SaveCollection(ctx, duplicate); err != services.ErrCollectionSlugTaken {
+		t.Errorf("Expected ErrCollectionSlugTaken, got %v", err)
+	}
+
+	badRule := &services.Collection{Slug: "cheap", Title: "Cheap", Type: services.CollectionRule, Rule: "price << 10"}
+	if err := service.SaveCollection(ctx, badRule); !errors.Is(err, services.ErrInvalidCollection) {
+		t.Errorf("Expected ErrInvalidCollection, got %v", err)
+	}
+
+	if _, err := service.SetProductTags(ctx, fixtures.ProductLaptop.ID, []string{"two words"}); err != services.ErrInvalidTag {
+		t.Errorf("Expected ErrInvalidTag, got %v", err)
+	}
+}
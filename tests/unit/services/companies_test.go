@@ -0,0 +1,257 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type companyFixture struct {
+	service   *services.CompanyService
+	repo      *mocks.MockCompanyRepository
+	orderRepo *mocks.MockOrderRepository
+	retries   *mocks.MockPaymentRetryRepository
+	gateway   *mocks.MockPaymentGateway
+	company   *services.Company
+}
+
+// newCompanyFixture sets up a company with a $500 approval threshold, a buyer and an approver
+func newCompanyFixture(t *testing.T) *companyFixture {
+	ctx := context.Background()
+	repo := mocks.NewMockCompanyRepository()
+	orderRepo := mocks.NewMockOrderRepository()
+	gateway := mocks.NewMockPaymentGateway()
+	retries := mocks.NewMockPaymentRetryRepository()
+
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+	paymentService := services.NewPaymentService(mocks.NewMockPaymentRepository(), gateway)
+	retryService := services.NewPaymentRetryService(retries, paymentService, orderService, services.PaymentRetryPolicy{MaxAttempts: 3})
+	service := services.NewCompanyService(repo, orderService).WithPaymentService(paymentService, retryService)
+
+	threshold := usd(50000)
+	company, err := service.CreateCompany(ctx, services.CompanyInput{Name: " Acme Corp ", ApprovalThreshold: &threshold})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.SetMember(ctx, company.ID, "buyer-1", services.CompanyRoleBuyer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.SetMember(ctx, company.ID, "approver-1", services.CompanyRoleApprover); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return &companyFixture{
+		service:   service,
+		repo:      repo,
+		orderRepo: orderRepo,
+		retries:   retries,
+		gateway:   gateway,
+		company:   company,
+	}
+}
+
+// placeOrder stores an order placed by a user and records it for their company
+func (f *companyFixture) placeOrder(t *testing.T, id, userID string, total money.Money) *services.CompanyOrder {
+	order := &orders.Order{
+		ID:              id,
+		OrderNumber:     "ORD-" + id,
+		UserID:          userID,
+		Status:          orders.OrderStatusPending,
+		Total:           total,
+		PaymentMethodID: "pm_" + id,
+	}
+	f.orderRepo.Orders[id] = order
+
+	record, err := f.service.RecordOrder(context.Background(), order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return record
+}
+
+func TestCompanyService_Members(t *testing.T) {
+	ctx := context.Background()
+	f := newCompanyFixture(t)
+
+	if f.company.Name != "Acme Corp" {
+		t.Errorf("expected the name trimmed, got %q", f.company.Name)
+	}
+	for name, input := range map[string]services.CompanyInput{
+		"no name":            {Name: "  "},
+		"negative threshold": {Name: "Acme", ApprovalThreshold: &money.Money{Amount: -1, Currency: "USD"}},
+		"no currency":        {Name: "Acme", ApprovalThreshold: &money.Money{Amount: 100}},
+	} {
+		if _, err := f.service.CreateCompany(ctx, input); err != services.ErrInvalidCompany {
+			t.Errorf("%s: expected ErrInvalidCompany, got %v", name, err)
+		}
+	}
+
+	other, err := f.service.CreateCompany(ctx, services.CompanyInput{Name: "Globex"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.service.SetMember(ctx, other.ID, "buyer-1", services.CompanyRoleBuyer); err != services.ErrAlreadyCompanyMember {
+		t.Errorf("expected ErrAlreadyCompanyMember, got %v", err)
+	}
+	if _, err := f.service.SetMember(ctx, f.company.ID, "buyer-2", "admin"); err != services.ErrInvalidCompanyRole {
+		t.Errorf("expected ErrInvalidCompanyRole, got %v", err)
+	}
+
+	// Changing a member's role keeps them in the company
+	member, err := f.service.SetMember(ctx, f.company.ID, "buyer-1", services.CompanyRoleApprover)
+	if err != nil || member.Role != services.CompanyRoleApprover {
+		t.Errorf("expected buyer-1 to become an approver, got %+v, %v", member, err)
+	}
+
+	if err := f.service.RemoveMember(ctx, other.ID, "buyer-1"); err != services.ErrNotCompanyMember {
+		t.Errorf("expected ErrNotCompanyMember removing a member of another company, got %v", err)
+	}
+	if err := f.service.RemoveMember(ctx, f.company.ID, "buyer-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.service.Membership(ctx, "buyer-1"); err != services.ErrNotCompanyMember {
+		t.Errorf("expected ErrNotCompanyMember after removal, got %v", err)
+	}
+}
+
+func TestCompanyService_SharedAddressBook(t *testing.T) {
+	ctx := context.Background()
+	f := newCompanyFixture(t)
+	address := orders.Address{FirstName: "Ada", LastName: "Lovelace", AddressLine1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"}
+
+	entry, err := f.service.AddAddress(ctx, "buyer-1", " Head office ", address)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Label != "Head office" || entry.CompanyID != f.company.ID {
+		t.Errorf("expected a trimmed label in the buyer's company, got %+v", entry)
+	}
+
+	// Every member sees and manages the same address book
+	addresses, err := f.service.ListAddresses(ctx, "approver-1")
+	if err != nil || len(addresses) != 1 {
+		t.Fatalf("expected the approver to see 1 address, got %d, %v", len(addresses), err)
+	}
+	if _, err := f.service.ListAddresses(ctx, "outsider"); err != services.ErrNotCompanyMember {
+		t.Errorf("expected ErrNotCompanyMember, got %v", err)
+	}
+
+	other, _ := f.service.CreateCompany(ctx, services.CompanyInput{Name: "Globex"})
+	if _, err := f.service.SetMember(ctx, other.ID, "outsider", services.CompanyRoleBuyer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.service.DeleteAddress(ctx, "outsider", entry.ID); err != services.ErrCompanyAddressNotFound {
+		t.Errorf("expected another company's address to be hidden, got %v", err)
+	}
+	if err := f.service.DeleteAddress(ctx, "approver-1", entry.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCompanyService_RecordOrder(t *testing.T) {
+	f := newCompanyFixture(t)
+
+	tests := []struct {
+		name   string
+		userID string
+		total  money.Money
+		want   services.ApprovalStatus
+	}{
+		{"buyer above threshold", "buyer-1", usd(50001), services.ApprovalPending},
+		{"buyer at threshold", "buyer-1", usd(50000), services.ApprovalNotRequired},
+		{"approver above threshold", "approver-1", usd(90000), services.ApprovalNotRequired},
+		{"other currency without rates", "buyer-1", money.Money{Amount: 100, Currency: "EUR"}, services.ApprovalPending},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := f.placeOrder(t, string(rune('a'+i)), tt.userID, tt.total)
+			if record == nil || record.ApprovalStatus != tt.want {
+				t.Errorf("expected %s, got %+v", tt.want, record)
+			}
+		})
+	}
+
+	if record := f.placeOrder(t, "z", "outsider", usd(90000)); record != nil {
+		t.Errorf("expected no company record for a non-member, got %+v", record)
+	}
+
+	pending, err := f.service.ListOrders(context.Background(), "approver-1", services.CompanyOrderFilter{ApprovalStatus: services.ApprovalPending})
+	if err != nil || len(pending) != 2 {
+		t.Errorf("expected the approver to see 2 pending orders, got %d, %v", len(pending), err)
+	}
+}
+
+func TestCompanyService_Approve(t *testing.T) {
+	ctx := context.Background()
+	f := newCompanyFixture(t)
+	f.placeOrder(t, "order-1", "buyer-1", usd(60000))
+
+	if _, err := f.service.Approve(ctx, "buyer-1", "order-1"); err != services.ErrNotCompanyApprover {
+		t.Errorf("expected ErrNotCompanyApprover for a buyer, got %v", err)
+	}
+	if f.orderRepo.Orders["order-1"].Status != orders.OrderStatusPending || len(f.gateway.Intents) != 0 {
+		t.Fatal("expected the order to stay uncharged until approved")
+	}
+
+	record, err := f.service.Approve(ctx, "approver-1", "order-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ApprovalStatus != services.ApprovalApproved || record.DecidedBy != "approver-1" || record.DecidedAt == nil {
+		t.Errorf("expected the approval recorded, got %+v", record)
+	}
+	if f.orderRepo.Orders["order-1"].Status != orders.OrderStatusPaid {
+		t.Errorf("expected the order charged and paid, got %s", f.orderRepo.Orders["order-1"].Status)
+	}
+
+	if _, err := f.service.Approve(ctx, "approver-1", "order-1"); err != services.ErrApprovalNotPending {
+		t.Errorf("expected ErrApprovalNotPending approving twice, got %v", err)
+	}
+
+	// A declined charge leaves the order approved and awaiting a payment retry
+	f.placeOrder(t, "order-2", "buyer-1", usd(60000))
+	f.gateway.DeclinedMethods["pm_order-2"] = true
+	record, err = f.service.Approve(ctx, "approver-1", "order-2")
+	if err != services.ErrTenderDeclined {
+		t.Fatalf("expected ErrTenderDeclined, got %v", err)
+	}
+	if record == nil || f.repo.Orders["order-2"].ApprovalStatus != services.ApprovalApproved {
+		t.Error("expected the approval to stand after a declined charge")
+	}
+	if retry := f.retries.Retries["order-2"]; retry == nil || retry.State != services.PaymentRetryStatePending {
+		t.Errorf("expected the order left for a payment retry, got %+v", retry)
+	}
+}
+
+func TestCompanyService_Reject(t *testing.T) {
+	ctx := context.Background()
+	f := newCompanyFixture(t)
+	f.placeOrder(t, "order-1", "buyer-1", usd(60000))
+
+	// An approver can't sign off an order they placed as a buyer
+	if _, err := f.service.SetMember(ctx, f.company.ID, "buyer-1", services.CompanyRoleApprover); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.service.Reject(ctx, "buyer-1", "order-1", "no"); err != services.ErrSelfApproval {
+		t.Errorf("expected ErrSelfApproval, got %v", err)
+	}
+
+	record, err := f.service.Reject(ctx, "approver-1", "order-1", " over budget ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ApprovalStatus != services.ApprovalRejected || record.Reason != "over budget" {
+		t.Errorf("expected the rejection recorded with its reason, got %+v", record)
+	}
+	if f.orderRepo.Orders["order-1"].Status != orders.OrderStatusCanceled {
+		t.Errorf("expected the order canceled, got %s", f.orderRepo.Orders["order-1"].Status)
+	}
+	if len(f.gateway.Intents) != 0 {
+		t.Error("expected a rejected order never to be charged")
+	}
+}
@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestConsent_LatestChoiceApplies(t *testing.T) {
+	ctx := context.Background()
+	consent := services.NewConsentService(mocks.NewMockConsentRepository(), "2025-01")
+
+	// Users who never chose are not contacted
+	allowed, err := consent.Allows(ctx, fixtures.TestUserID, services.ConsentMarketing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if allowed {
+		t.Fatal("expected no marketing consent before the user chose")
+	}
+
+	_, err = consent.Record(ctx, fixtures.TestUserID, []services.ConsentChoice{
+		{Purpose: services.ConsentMarketing, Granted: true},
+		{Purpose: services.ConsentAnalytics, Granted: false},
+	}, "", "cookie_banner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if allowed, _ := consent.Allows(ctx, fixtures.TestUserID, services.ConsentMarketing); !allowed {
+		t.Error("expected marketing consent after it was granted")
+	}
+	if allowed, _ := consent.Allows(ctx, fixtures.TestUserID, services.ConsentAnalytics); allowed {
+		t.Error("expected no analytics consent after it was declined")
+	}
+
+	// Withdrawing consent is a new event; the history keeps both
+	if _, err := consent.Record(ctx, fixtures.TestUserID, []services.ConsentChoice{{Purpose: services.ConsentMarketing, Granted: false}}, "", "account"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if allowed, _ := consent.Allows(ctx, fixtures.TestUserID, services.ConsentMarketing); allowed {
+		t.Error("expected marketing consent to be withdrawn")
+	}
+	history, _ := consent.History(ctx, fixtures.TestUserID)
+	if len(history) != 3 || history[0].Source != "account" || history[0].PolicyVersion != "2025-01" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestConsent_PreferencesAndPolicyVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockConsentRepository()
+
+	old := services.NewConsentService(repo, "2024-06")
+	if _, err := old.Record(ctx, fixtures.TestUserID, []services.ConsentChoice{{Purpose: services.ConsentAnalytics, Granted: true}}, "", "cookie_banner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	consent := services.NewConsentService(repo, "2025-01")
+	preferences, err := consent.Preferences(ctx, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preferences) != 2 {
+		t.Fatalf("expected a preference for each purpose, got %+v", preferences)
+	}
+	if marketing := preferences[0]; marketing.Purpose != services.ConsentMarketing || marketing.Granted || marketing.RecordedAt != nil {
+		t.Errorf("expected marketing to be unset, got %+v", marketing)
+	}
+	if analytics := preferences[1]; !analytics.Granted || !analytics.Outdated || analytics.PolicyVersion != "2024-06" {
+		t.Errorf("expected analytics granted under the old policy, got %+v", analytics)
+	}
+
+	if _, err := consent.Record(ctx, fixtures.TestUserID, []services.ConsentChoice{{Purpose: "newsletter", Granted: true}}, "", ""); err != services.ErrInvalidConsentPurpose {
+		t.Errorf("expected ErrInvalidConsentPurpose, got %v", err)
+	}
+	unversioned := services.NewConsentService(repo, "")
+	if _, err := unversioned.Record(ctx, fixtures.TestUserID, []services.ConsentChoice{{Purpose: services.ConsentMarketing, Granted: true}}, "", ""); err != services.ErrConsentPolicyRequired {
+		t.Errorf("expected ErrConsentPolicyRequired, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type contentReportFixture struct {
+	reports      *services.ContentReportService
+	reviews      *services.ReviewService
+	questions    *services.QuestionService
+	reviewRepo   *mocks.MockReviewRepository
+	questionRepo *mocks.MockQuestionRepository
+}
+
+func newContentReportFixture(t *testing.T, hideAfter int) *contentReportFixture {
+	t.Helper()
+	reviews, reviewRepo, _ := newReviewService(t, 2)
+	questions, questionRepo := newQuestionService(t)
+	reportRepo := mocks.NewMockContentReportRepository()
+	return &contentReportFixture{
+		reports:      services.NewContentReportService(reportRepo, reviewRepo, questionRepo, hideAfter),
+		reviews:      reviews,
+		questions:    questions,
+		reviewRepo:   reviewRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+func TestContentReportService_Report(t *testing.T) {
+	ctx := context.Background()
+	f := newContentReportFixture(t, 2)
+	review := writeReview(t, f.reviews, fixtures.TestUserID, fixtures.ProductLaptop.ID, 5)
+
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentReview, review.ID, services.ReportReasonSpam, ""); err != services.ErrReviewNotFound {
+		t.Errorf("expected a pending review reported as not found, got %v", err)
+	}
+	if _, err := f.reviews.Moderate(ctx, review.ID, "moderator", services.ReviewStatusApproved, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := f.reports.Report(ctx, fixtures.TestUserID, services.ReportContentReview, review.ID, services.ReportReasonSpam, ""); !errors.Is(err, services.ErrInvalidReport) {
+		t.Errorf("expected authors unable to report their own review, got %v", err)
+	}
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentReview, review.ID, "boring", ""); !errors.Is(err, services.ErrInvalidReport) {
+		t.Errorf("expected ErrInvalidReport for an unknown reason, got %v", err)
+	}
+	if _, err := f.reports.Report(ctx, "user-2", "photo", review.ID, services.ReportReasonSpam, ""); !errors.Is(err, services.ErrInvalidReport) {
+		t.Errorf("expected ErrInvalidReport for an unknown content type, got %v", err)
+	}
+
+	report, err := f.reports.Report(ctx, "user-2", services.ReportContentReview, review.ID, services.ReportReasonOffensive, " Insults the seller ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != services.ReportStatusOpen || report.Details != "Insults the seller" {
+		t.Errorf("expected an open report with trimmed details, got %+v", report)
+	}
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentReview, review.ID, services.ReportReasonSpam, ""); err != services.ErrAlreadyReported {
+		t.Errorf("expected ErrAlreadyReported for a second report from the same user, got %v", err)
+	}
+	if f.reviewRepo.Reviews[review.ID].Hidden {
+		t.Error("expected the review shown below the threshold")
+	}
+
+	if _, err := f.reports.Report(ctx, "user-3", services.ReportContentReview, review.ID, services.ReportReasonSpam, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.reviewRepo.Reviews[review.ID].Hidden {
+		t.Error("expected the review hidden at the threshold")
+	}
+	product, err := f.reviews.ProductReviews(ctx, fixtures.ProductLaptop.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.ReviewCount != 0 {
+		t.Errorf("expected the hidden review left off the product, got %d reviews", product.ReviewCount)
+	}
+
+	question, err := f.questions.AskQuestion(ctx, fixtures.TestUserID, fixtures.ProductLaptop.ID, "Is it heavy?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	answered, err := f.questions.Answer(ctx, question.ID, "user-2", false, "Buy my laptop instead")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	answerID := answered.Answers[0].ID
+	for _, userID := range []string{fixtures.TestUserID, "user-3"} {
+		if _, err := f.reports.Report(ctx, userID, services.ReportContentAnswer, answerID, services.ReportReasonSpam, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !f.questionRepo.Answers[answerID].Hidden || f.questionRepo.Questions[question.ID].Hidden {
+		t.Error("expected only the reported answer hidden")
+	}
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentQuestion, "missing", services.ReportReasonSpam, ""); err != services.ErrQuestionNotFound {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+}
+
+func TestContentReportService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	f := newContentReportFixture(t, 2)
+	question, err := f.questions.AskQuestion(ctx, fixtures.TestUserID, fixtures.ProductLaptop.ID, "Is it heavy?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := f.questions.AskQuestion(ctx, fixtures.TestUserID, fixtures.ProductLaptop.ID, "Does it come in red?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, userID := range []string{"user-2", "user-3"} {
+		if _, err := f.reports.Report(ctx, userID, services.ReportContentQuestion, question.ID, services.ReportReasonOffTopic, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentQuestion, other.ID, services.ReportReasonSpam, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	queue, err := f.reports.Queue(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue) != 2 || queue[0].ContentID != question.ID || queue[0].ReportCount != 2 || !queue[0].Hidden || queue[0].Excerpt != "Is it heavy?" {
+		t.Fatalf("expected the hidden, most reported question first, got %+v", queue)
+	}
+	if queue[1].ContentID != other.ID || queue[1].Hidden {
+		t.Errorf("expected the visible question second, got %+v", queue[1])
+	}
+
+	if _, err := f.reports.Resolve(ctx, services.ReportContentQuestion, question.ID, "moderator", "ignore"); !errors.Is(err, services.ErrInvalidReport) {
+		t.Errorf("expected ErrInvalidReport for an unknown action, got %v", err)
+	}
+	dismissed, err := f.reports.Resolve(ctx, services.ReportContentQuestion, question.ID, "moderator", services.ReportActionDismiss)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dismissed.Hidden || f.questionRepo.Questions[question.ID].Hidden {
+		t.Error("expected a dismissed question shown again")
+	}
+	if _, err := f.reports.Resolve(ctx, services.ReportContentQuestion, question.ID, "moderator", services.ReportActionDismiss); err != services.ErrNoOpenReports {
+		t.Errorf("expected ErrNoOpenReports once resolved, got %v", err)
+	}
+
+	removed, err := f.reports.Resolve(ctx, services.ReportContentQuestion, other.ID, "moderator", services.ReportActionRemove)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !removed.Hidden || !f.questionRepo.Questions[other.ID].Hidden {
+		t.Error("expected a removed question hidden")
+	}
+	if queue, _ := f.reports.Queue(ctx); len(queue) != 0 {
+		t.Errorf("expected an empty queue, got %d entries", len(queue))
+	}
+
+	// Dismissed reports no longer count towards hiding, but their users can't report again
+	if _, err := f.reports.Report(ctx, "user-2", services.ReportContentQuestion, question.ID, services.ReportReasonSpam, ""); err != services.ErrAlreadyReported {
+		t.Errorf("expected ErrAlreadyReported, got %v", err)
+	}
+	if _, err := f.reports.Report(ctx, "user-4", services.ReportContentQuestion, question.ID, services.ReportReasonSpam, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.questionRepo.Questions[question.ID].Hidden {
+		t.Error("expected one new report to leave the question shown")
+	}
+}
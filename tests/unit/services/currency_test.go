@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/pricing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestCurrencyService_Convert(t *testing.T) {
+	currency := services.NewCurrencyService("USD", map[string]float64{"EUR": 0.8, "GBP": 0.5})
+
+	tests := []struct {
+		name    string
+		amount  money.Money
+		to      string
+		want    int64
+		wantErr error
+	}{
+		{name: "base to quoted", amount: money.Money{Amount: 5000, Currency: "USD"}, to: "EUR", want: 4000},
+		{name: "quoted to base", amount: money.Money{Amount: 4000, Currency: "EUR"}, to: "USD", want: 5000},
+		{name: "between quoted currencies", amount: money.Money{Amount: 1000, Currency: "EUR"}, to: "GBP", want: 625},
+		{name: "rounds to the nearest minor unit", amount: money.Money{Amount: 333, Currency: "GBP"}, to: "EUR", want: 533},
+		{name: "same currency unchanged", amount: money.Money{Amount: 1234, Currency: "EUR"}, to: "eur", want: 1234},
+		{name: "unknown currency", amount: money.Money{Amount: 1000, Currency: "JPY"}, to: "USD", wantErr: services.ErrExchangeRateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currency.Convert(tt.amount, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && got.Amount != tt.want {
+				t.Errorf("expected %d, got %d %s", tt.want, got.Amount, got.Currency)
+			}
+		})
+	}
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	rates, err := services.ParseExchangeRates([]string{"eur:0.92", " GBP : 0.79 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rates["EUR"] != 0.92 || rates["GBP"] != 0.79 {
+		t.Errorf("expected EUR 0.92 and GBP 0.79, got %v", rates)
+	}
+
+	for _, entries := range [][]string{{"EUR"}, {"EURO:0.92"}, {"EUR:zero"}, {"EUR:-1"}, {"EUR:0.92", "EUR:0.93"}} {
+		if _, err := services.ParseExchangeRates(entries); err == nil {
+			t.Errorf("expected %v to be rejected", entries)
+		}
+	}
+}
+
+func TestPricingService_ConvertsMinPurchase(t *testing.T) {
+	ctx := context.Background()
+	promotions := mocks.NewMockPromotionRepository()
+	minimum := money.Money{Amount: 5000, Currency: "USD"}
+	promotions.Promotions = append(promotions.Promotions, &pricing.Promotion{
+		ID:           "promo-1",
+		Code:         "SAVE10",
+		DiscountType: pricing.DiscountTypePercentage,
+		Value:        0.10,
+		MinPurchase:  &minimum,
+		ValidFrom:    time.Now().Add(-time.Hour),
+		ValidTo:      time.Now().Add(time.Hour),
+		IsActive:     true,
+	})
+	currency := services.NewCurrencyService("USD", map[string]float64{"EUR": 0.8})
+
+	// The USD 50.00 minimum is EUR 40.00
+	eurCart := func(amount int64) *cart.Cart {
+		return &cart.Cart{ID: "cart-1", Items: []cart.CartItem{
+			{ID: "item-1", ProductID: "prod-1", Price: money.Money{Amount: amount, Currency: "EUR"}, Quantity: 1},
+		}}
+	}
+
+	tests := []struct {
+		name         string
+		currency     *services.CurrencyService
+		amount       int64
+		wantDiscount int64
+	}{
+		{name: "meets the converted minimum", currency: currency, amount: 4000, wantDiscount: 400},
+		{name: "below the converted minimum", currency: currency, amount: 3999, wantDiscount: 0},
+		{name: "no exchange rates", currency: nil, amount: 9000, wantDiscount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := services.NewPricingService(promotions, services.NewSimpleTaxCalculator(0), nil)
+			if tt.currency != nil {
+				svc.WithCurrencyService(tt.currency)
+			}
+			result, err := svc.PriceCart(ctx, pricing.PriceCartRequest{Cart: eurCart(tt.amount), PromotionCodes: []string{"SAVE10"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.DiscountTotal.Amount != tt.wantDiscount {
+				t.Errorf("expected discount %d, got %d", tt.wantDiscount, result.DiscountTotal.Amount)
+			}
+		})
+	}
+
+	svc := services.NewPricingService(promotions, services.NewSimpleTaxCalculator(0), nil).WithCurrencyService(currency)
+	if _, err := svc.ValidatePromotion(ctx, "SAVE10", money.Money{Amount: 4000, Currency: "EUR"}); err != nil {
+		t.Errorf("expected EUR 40.00 to meet the minimum, got %v", err)
+	}
+	if _, err := svc.ValidatePromotion(ctx, "SAVE10", money.Money{Amount: 3999, Currency: "EUR"}); err != pricing.ErrMinPurchaseNotMet {
+		t.Errorf("expected ErrMinPurchaseNotMet, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// pngHeader is enough of a PNG file for content type detection
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func strPtr(s string) *string { return &s }
+
+func TestCustomerService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockCustomerRepository()
+	svc := services.NewCustomerService(repo, mocks.NewMockMediaStorage(), 1024)
+
+	profile, err := svc.UpdateProfile(ctx, fixtures.TestUserID, services.ProfileUpdate{
+		FirstName: strPtr(" Ada "),
+		Phone:     strPtr("+1 (555) 010-2030"),
+		Birthday:  strPtr("1990-12-10"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.FirstName != "Ada" || profile.Phone != "+15550102030" || profile.Birthday != "1990-12-10" {
+		t.Errorf("expected a trimmed name and normalized phone, got %+v", profile)
+	}
+
+	// Fields left out are kept; empty strings clear
+	profile, err = svc.UpdateProfile(ctx, fixtures.TestUserID, services.ProfileUpdate{LastName: strPtr("Lovelace"), Phone: strPtr("")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.FirstName != "Ada" || profile.LastName != "Lovelace" || profile.Phone != "" {
+		t.Errorf("expected only the last name set and the phone cleared, got %+v", profile)
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	for name, update := range map[string]services.ProfileUpdate{
+		"long name":       {FirstName: strPtr(strings.Repeat("a", 101))},
+		"short phone":     {Phone: strPtr("12345")},
+		"letters":         {Phone: strPtr("call me")},
+		"bad birthday":    {Birthday: strPtr("1990-02-30")},
+		"future birthday": {Birthday: strPtr(tomorrow)},
+	} {
+		if _, err := svc.UpdateProfile(ctx, fixtures.TestUserID, update); err != services.ErrInvalidProfile {
+			t.Errorf("%s: expected ErrInvalidProfile, got %v", name, err)
+		}
+	}
+	if stored := repo.Profiles[fixtures.TestUserID]; stored.LastName != "Lovelace" || stored.Birthday != "1990-12-10" {
+		t.Errorf("expected invalid updates to leave the profile unchanged, got %+v", stored)
+	}
+}
+
+func TestCustomerService_SetAvatar(t *testing.T) {
+	ctx := context.Background()
+	media := mocks.NewMockMediaStorage()
+	svc := services.NewCustomerService(mocks.NewMockCustomerRepository(), media, 64)
+
+	first, err := svc.SetAvatar(ctx, fixtures.TestUserID, bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(first.AvatarURL, "/media/avatars/"+fixtures.TestUserID+"/") || !strings.HasSuffix(first.AvatarURL, ".png") {
+		t.Errorf("expected a PNG avatar URL under the user's folder, got %s", first.AvatarURL)
+	}
+	firstKey := first.AvatarKey
+
+	second, err := svc.SetAvatar(ctx, fixtures.TestUserID, bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.AvatarKey == firstKey {
+		t.Error("expected a new key for a new avatar")
+	}
+	if _, ok := media.Files[firstKey]; ok {
+		t.Error("expected the replaced avatar to be deleted")
+	}
+
+	if _, err := svc.SetAvatar(ctx, fixtures.TestUserID, strings.NewReader("<html>not an image</html>")); err != services.ErrInvalidAvatar {
+		t.Errorf("expected ErrInvalidAvatar for a non-image, got %v", err)
+	}
+	oversized := append(append([]byte(nil), pngHeader...), make([]byte, 64)...)
+	if _, err := svc.SetAvatar(ctx, fixtures.TestUserID, bytes.NewReader(oversized)); err != services.ErrInvalidAvatar {
+		t.Errorf("expected ErrInvalidAvatar for an oversized image, got %v", err)
+	}
+
+	removed, err := svc.RemoveAvatar(ctx, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed.AvatarURL != "" || len(media.Files) != 0 {
+		t.Errorf("expected the avatar removed, got %q with %d files stored", removed.AvatarURL, len(media.Files))
+	}
+}
+
+func TestLocalMediaStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := services.NewLocalMediaStorage(dir, "https://cdn.example.com/media/")
+
+	url, err := storage.Put(ctx, "avatars/user-001/a.png", bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://cdn.example.com/media/avatars/user-001/a.png" {
+		t.Errorf("unexpected URL %s", url)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "avatars", "user-001", "a.png")); err != nil || !bytes.Equal(data, pngHeader) {
+		t.Errorf("expected the file written under the media directory, got %v", err)
+	}
+
+	if err := storage.Delete(ctx, "avatars/user-001/a.png"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := storage.Delete(ctx, "avatars/user-001/a.png"); err != nil {
+		t.Errorf("expected deleting a missing file to succeed, got %v", err)
+	}
+
+	for _, key := range []string{"../escape.png", "avatars/../../escape.png", "/abs.png", ""} {
+		if _, err := storage.Put(ctx, key, bytes.NewReader(pngHeader)); err == nil {
+			t.Errorf("expected key %q to be refused", key)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newDeadLetterFixture(t *testing.T) (*services.DeadLetterService, *jobs.Runner, *mocks.MockDeadLetterRepository) {
+	t.Helper()
+	repo := mocks.NewMockDeadLetterRepository()
+	runner := jobs.NewRunner(1, 10)
+	svc := services.NewDeadLetterService(repo, runner)
+	runner.WithDeadLetter(svc.Record)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runner.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+	return svc, runner, repo
+}
+
+// waitForLetters polls until the dead letters matching the filter reach the wanted count
+func waitForLetters(t *testing.T, svc *services.DeadLetterService, filter services.DeadLetterFilter, want int) []*services.DeadLetter {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		letters, err := svc.ListDeadLetters(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("ListDeadLetters: %v", err)
+		}
+		if len(letters) == want {
+			return letters
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d dead letters matching %+v, got %d", want, filter, len(letters))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDeadLetterService_RecordsFailedJobs(t *testing.T) {
+	svc, runner, _ := newDeadLetterFixture(t)
+	failing := func(ctx context.Context) error { return errors.New("smtp timeout") }
+
+	jobsToRun := []jobs.Job{
+		{Name: "login-alert:user-1", Kind: services.LoginAlertJobKind, Payload: []byte(`{"user_id":"user-1"}`), Run: failing},
+		{Name: "cache-warm", Run: failing}, // no kind, only logged
+		{Name: "webhook:evt-1", Kind: services.WebhookJobKind, Payload: []byte(`{"event_id":"evt-1"}`), Run: func(ctx context.Context) error { panic("boom") }},
+		{Name: "webhook:evt-2", Kind: services.WebhookJobKind, Payload: []byte(`{"event_id":"evt-2"}`), Run: func(ctx context.Context) error { return nil }},
+	}
+	for _, job := range jobsToRun {
+		if err := runner.Enqueue(job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	letters := waitForLetters(t, svc, services.DeadLetterFilter{}, 2)
+	byName := map[string]*services.DeadLetter{}
+	for _, letter := range letters {
+		byName[letter.Name] = letter
+	}
+
+	alert := byName["login-alert:user-1"]
+	if alert == nil || alert.Status != services.DeadLetterStatusDead || alert.Attempts != 1 || alert.LastError != "smtp timeout" || string(alert.Payload) != `{"user_id":"user-1"}` {
+		t.Errorf("unexpected login alert dead letter %+v", alert)
+	}
+	if webhook := byName["webhook:evt-1"]; webhook == nil || webhook.LastError != "panic: boom" {
+		t.Errorf("expected the panicking webhook job to be dead-lettered, got %+v", webhook)
+	}
+
+	filtered := waitForLetters(t, svc, services.DeadLetterFilter{Kind: services.WebhookJobKind}, 1)
+	if filtered[0].Name != "webhook:evt-1" {
+		t.Errorf("expected the kind filter to keep the webhook, got %+v", filtered[0])
+	}
+}
+
+func TestDeadLetterService_Replay(t *testing.T) {
+	svc, _, repo := newDeadLetterFixture(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	var replayed atomic.Value
+	err := svc.RegisterReplayer("report", func(ctx context.Context, payload []byte) error {
+		replayed.Store(string(payload))
+		if calls.Add(1) == 1 {
+			return errors.New("still down")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterReplayer: %v", err)
+	}
+	if err := svc.RegisterReplayer("report", nil); !errors.Is(err, services.ErrDeadLetterReplayerExists) {
+		t.Errorf("expected ErrDeadLetterReplayerExists, got %v", err)
+	}
+
+	svc.Record(jobs.Job{Name: "report:daily", Kind: "report", Payload: []byte(`{"day":"2026-10-15"}`)}, errors.New("connection refused"))
+	letter := waitForLetters(t, svc, services.DeadLetterFilter{}, 1)[0]
+
+	// The first replay fails again and keeps the dead letter with another attempt
+	queued, err := svc.Replay(ctx, letter.ID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if queued.Status != services.DeadLetterStatusReplaying {
+		t.Errorf("expected status replaying, got %s", queued.Status)
+	}
+	waitForLetters(t, svc, services.DeadLetterFilter{Status: services.DeadLetterStatusDead}, 1)
+	failed, _ := svc.GetDeadLetter(ctx, letter.ID)
+	if failed.Attempts != 2 || failed.LastError != "still down" {
+		t.Errorf("expected a second failed attempt, got %+v", failed)
+	}
+	if got := replayed.Load(); got != `{"day":"2026-10-15"}` {
+		t.Errorf("expected the replayer to get the payload, got %v", got)
+	}
+
+	// The second succeeds
+	if _, err := svc.Replay(ctx, letter.ID); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	waitForLetters(t, svc, services.DeadLetterFilter{Status: services.DeadLetterStatusReplayed}, 1)
+	done, _ := svc.GetDeadLetter(ctx, letter.ID)
+	if done.ReplayedAt == nil || done.Attempts != 2 {
+		t.Errorf("expected the dead letter to be replayed, got %+v", done)
+	}
+
+	if _, err := svc.Replay(ctx, letter.ID); !errors.Is(err, services.ErrDeadLetterReplayed) {
+		t.Errorf("expected ErrDeadLetterReplayed, got %v", err)
+	}
+	if _, err := svc.Replay(ctx, "missing"); !errors.Is(err, services.ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+
+	svc.Record(jobs.Job{Name: "export:1", Kind: "export"}, errors.New("disk full"))
+	for _, l := range repo.Letters {
+		if l.Kind != "export" {
+			continue
+		}
+		if string(l.Payload) != "null" {
+			t.Errorf("expected an empty payload stored as null, got %s", l.Payload)
+		}
+		if _, err := svc.Replay(ctx, l.ID); !errors.Is(err, services.ErrDeadLetterKindUnknown) {
+			t.Errorf("expected ErrDeadLetterKindUnknown, got %v", err)
+		}
+	}
+}
+
+func TestWebhookService_ReplayDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	svc, provider, repo := newWebhookFixture(t)
+	body := []byte(`{"id":"evt_dlq","type":"shipment.delivered"}`)
+
+	event, _, err := svc.Receive(ctx, "carrier", signedHeader(body), body)
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	provider.processErr = errors.New("carrier API down")
+	if err := svc.Process(ctx, event.ID); err == nil {
+		t.Fatal("expected processing to fail")
+	}
+
+	provider.processErr = nil
+	if err := svc.ReplayDeadLetter(ctx, []byte(`{"event_id":"`+event.ID+`"}`)); err != nil {
+		t.Fatalf("ReplayDeadLetter: %v", err)
+	}
+	if stored := repo.Events[event.ID]; stored.Status != services.WebhookEventStatusProcessed || stored.Attempts != 2 {
+		t.Errorf("expected the event processed on its second attempt, got %+v", stored)
+	}
+
+	if err := svc.ReplayDeadLetter(ctx, []byte(`{}`)); !errors.Is(err, services.ErrInvalidWebhookPayload) {
+		t.Errorf("expected ErrInvalidWebhookPayload, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newTestSlot(id, region string, startsIn time.Duration, capacity, reserved int) *services.DeliverySlot {
+	start := time.Now().Add(startsIn)
+	return &services.DeliverySlot{
+		ID:       id,
+		Region:   region,
+		StartsAt: start,
+		EndsAt:   start.Add(3 * time.Hour),
+		Capacity: capacity,
+		Reserved: reserved,
+		IsActive: true,
+	}
+}
+
+func TestDeliveryService_AvailableSlots(t *testing.T) {
+	repo := mocks.NewMockDeliverySlotRepository()
+	repo.Slots["slot-sf"] = newTestSlot("slot-sf", "941", 24*time.Hour, 5, 0)
+	repo.Slots["slot-sf-full"] = newTestSlot("slot-sf-full", "941", 48*time.Hour, 2, 2)
+	repo.Slots["slot-ny"] = newTestSlot("slot-ny", "100", 24*time.Hour, 5, 0)
+	repo.Slots["slot-uk"] = newTestSlot("slot-uk", "SW1A", 24*time.Hour, 5, 0)
+
+	service := services.NewDeliveryService(repo)
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		postalCode string
+		expected   []string
+	}{
+		{
+			name:       "matches region prefix and skips full slots",
+			postalCode: "94107",
+			expected:   []string{"slot-sf"},
+		},
+		{
+			name:       "normalizes spacing and case",
+			postalCode: "sw1a 1aa",
+			expected:   []string{"slot-uk"},
+		},
+		{
+			name:       "no slots for unserved region",
+			postalCode: "60601",
+			expected:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots, err := service.AvailableSlots(context.Background(), tt.postalCode, now, now.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(slots) != len(tt.expected) {
+				t.Fatalf("expected %d slots, got %d", len(tt.expected), len(slots))
+			}
+			for i, id := range tt.expected {
+				if slots[i].ID != id {
+					t.Errorf("expected slot %s, got %s", id, slots[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliveryService_ReserveSlot(t *testing.T) {
+	tests := []struct {
+		name          string
+		slot          *services.DeliverySlot
+		slotID        string
+		postalCode    string
+		expectedError error
+	}{
+		{
+			name:       "reserve available slot",
+			slot:       newTestSlot("slot-1", "941", 24*time.Hour, 1, 0),
+			slotID:     "slot-1",
+			postalCode: "94107",
+		},
+		{
+			name:          "slot not found",
+			slot:          newTestSlot("slot-1", "941", 24*time.Hour, 1, 0),
+			slotID:        "missing",
+			postalCode:    "94107",
+			expectedError: services.ErrDeliverySlotNotFound,
+		},
+		{
+			name:          "slot at capacity",
+			slot:          newTestSlot("slot-1", "941", 24*time.Hour, 1, 1),
+			slotID:        "slot-1",
+			postalCode:    "94107",
+			expectedError: services.ErrDeliverySlotFull,
+		},
+		{
+			name:          "postal code outside region",
+			slot:          newTestSlot("slot-1", "941", 24*time.Hour, 1, 0),
+			slotID:        "slot-1",
+			postalCode:    "10001",
+			expectedError: services.ErrDeliverySlotUnavailable,
+		},
+		{
+			name:          "slot already started",
+			slot:          newTestSlot("slot-1", "941", -time.Hour, 1, 0),
+			slotID:        "slot-1",
+			postalCode:    "94107",
+			expectedError: services.ErrDeliverySlotUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockDeliverySlotRepository()
+			repo.Slots[tt.slot.ID] = tt.slot
+			service := services.NewDeliveryService(repo)
+
+			slot, err := service.ReserveSlot(context.Background(), tt.slotID, "order-1", tt.postalCode)
+			if err != tt.expectedError {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				return
+			}
+			if slot.Reserved != 1 {
+				t.Errorf("expected 1 reservation, got %d", slot.Reserved)
+			}
+			if repo.Bookings["order-1"] != tt.slotID {
+				t.Errorf("expected order to be linked to slot %s", tt.slotID)
+			}
+		})
+	}
+}
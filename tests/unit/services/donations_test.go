@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/pricing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newDonationService(t *testing.T) (*services.DonationService, *mocks.MockDonationRepository, *mocks.MockProductRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	repo := mocks.NewMockDonationRepository()
+	donations := services.NewDonationService(repo, productRepo)
+	maxAmount := money.Money{Amount: 10000}
+	donation := &services.DonationProduct{ProductID: fixtures.ProductTShirt.ID, MinAmount: money.Money{Amount: 100}, MaxAmount: &maxAmount}
+	if err := donations.SetDonation(context.Background(), donation); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return donations, repo, productRepo
+}
+
+func TestDonations_CartItemAmount(t *testing.T) {
+	ctx := context.Background()
+	donations, repo, productRepo := newDonationService(t)
+	if repo.Products[fixtures.ProductTShirt.ID].MinAmount.Currency != "USD" {
+		t.Fatalf("expected the limits in the product's currency, got %+v", repo.Products[fixtures.ProductTShirt.ID])
+	}
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil).
+		WithDonations(donations)
+	userCart, err := cartService.GetOrCreateCart(ctx, fixtures.TestUserID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	add := func(productID, amount string) (*cart.Cart, error) {
+		return cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{
+			ProductID:  productID,
+			Quantity:   1,
+			Attributes: map[string]string{services.DonationAmountAttribute: amount},
+		})
+	}
+
+	if _, err := add(fixtures.ProductTShirt.ID, ""); !errors.Is(err, services.ErrDonationAmountRequired) {
+		t.Errorf("expected ErrDonationAmountRequired, got %v", err)
+	}
+	for _, amount := range []string{"99", "10001", "-500", "ten dollars"} {
+		if _, err := add(fixtures.ProductTShirt.ID, amount); !errors.Is(err, services.ErrInvalidDonationAmount) {
+			t.Errorf("expected ErrInvalidDonationAmount for %q, got %v", amount, err)
+		}
+	}
+
+	updated, err := add(fixtures.ProductTShirt.ID, "2500")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item := updated.Items[0]; item.Price != (money.Money{Amount: 2500, Currency: "USD"}) {
+		t.Errorf("expected the item priced at the amount chosen, got %v", item.Price)
+	}
+	if _, err := add(fixtures.ProductTShirt.ID, "5000"); !errors.Is(err, services.ErrDonationInCart) {
+		t.Errorf("expected ErrDonationInCart, got %v", err)
+	}
+
+	// Other products keep their price and lose a donation amount sent for them
+	updated, err = add(fixtures.ProductLaptop.ID, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	laptop := updated.Items[1]
+	if laptop.Price != fixtures.ProductLaptop.BasePrice || laptop.Attributes[services.DonationAmountAttribute] != "" {
+		t.Errorf("expected the laptop at its own price without a donation amount, got %v %v", laptop.Price, laptop.Attributes)
+	}
+}
+
+func TestDonations_PricingExcludesFromPromotions(t *testing.T) {
+	ctx := context.Background()
+	donations, _, _ := newDonationService(t)
+	promotions := mocks.NewMockPromotionRepository()
+	minimum := money.Money{Amount: 5000, Currency: "USD"}
+	promotions.Promotions = append(promotions.Promotions, &pricing.Promotion{
+		ID:           "promo-1",
+		Code:         "SAVE10",
+		DiscountType: pricing.DiscountTypePercentage,
+		Value:        0.10,
+		MinPurchase:  &minimum,
+		ValidFrom:    time.Now().Add(-time.Hour),
+		ValidTo:      time.Now().Add(time.Hour),
+		IsActive:     true,
+	})
+	pricingService := services.NewPricingService(promotions, services.NewSimpleTaxCalculator(0), nil).WithDonations(donations)
+
+	donationCart := func(itemAmount, donationAmount int64, chosen string) *cart.Cart {
+		return &cart.Cart{ID: "cart-1", Items: []cart.CartItem{
+			{ID: "item-1", ProductID: fixtures.ProductLaptop.ID, Price: money.Money{Amount: itemAmount, Currency: "USD"}, Quantity: 1},
+			{ID: "item-2", ProductID: fixtures.ProductTShirt.ID, Name: fixtures.ProductTShirt.Name, Price: money.Money{Amount: donationAmount, Currency: "USD"}, Quantity: 1,
+				Attributes: map[string]string{services.DonationAmountAttribute: chosen}},
+		}}
+	}
+	price := func(c *cart.Cart) (*pricing.PricingResult, error) {
+		return pricingService.PriceCart(ctx, pricing.PriceCartRequest{Cart: c, PromotionCodes: []string{"SAVE10"}})
+	}
+
+	result, err := price(donationCart(6000, 2500, "2500"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DiscountTotal.Amount != 600 || result.Subtotal.Amount != 8500 {
+		t.Errorf("expected 10%% off the laptop only, got discount %d on %d", result.DiscountTotal.Amount, result.Subtotal.Amount)
+	}
+
+	// The donation doesn't count towards the promotion's minimum purchase
+	result, err = price(donationCart(4000, 2500, "2500"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.DiscountTotal.IsZero() {
+		t.Errorf("expected no discount below the minimum without the donation, got %d", result.DiscountTotal.Amount)
+	}
+	if len(promotions.Promotions[0].ExcludedProductIDs) != 0 {
+		t.Errorf("expected the stored promotion left as it was, got %v", promotions.Promotions[0].ExcludedProductIDs)
+	}
+
+	if _, err := price(donationCart(6000, 100, "2500")); !errors.Is(err, services.ErrInvalidDonationAmount) {
+		t.Errorf("expected ErrInvalidDonationAmount for an item not priced at its amount, got %v", err)
+	}
+	if err := donations.RemoveDonation(ctx, fixtures.ProductTShirt.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := price(donationCart(6000, 2500, "2500")); !errors.Is(err, services.ErrDonationClosed) {
+		t.Errorf("expected ErrDonationClosed once the product stops taking donations, got %v", err)
+	}
+}
+
+func TestDonations_SettingsValidation(t *testing.T) {
+	ctx := context.Background()
+	donations, _, _ := newDonationService(t)
+
+	below := money.Money{Amount: 50}
+	invalid := []*services.DonationProduct{
+		{ProductID: fixtures.ProductLaptop.ID, MinAmount: money.Money{Amount: 0}},
+		{ProductID: fixtures.ProductLaptop.ID, MinAmount: money.Money{Amount: 100}, MaxAmount: &below},
+	}
+	for _, donation := range invalid {
+		if err := donations.SetDonation(ctx, donation); !errors.Is(err, services.ErrInvalidDonation) {
+			t.Errorf("expected ErrInvalidDonation for %+v, got %v", donation, err)
+		}
+	}
+	if err := donations.SetDonation(ctx, &services.DonationProduct{ProductID: "missing", MinAmount: money.Money{Amount: 100}}); !errors.Is(err, services.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := donations.GetDonation(ctx, fixtures.ProductLaptop.ID); !errors.Is(err, services.ErrDonationNotFound) {
+		t.Errorf("expected ErrDonationNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newFulfillmentFixture returns a service over n orders with increasing UpdatedAt,
+// alternating paid and pending so only half are open
+func newFulfillmentFixture(n int) (*services.FulfillmentService, *mocks.MockOrderRepository, *mocks.MockFulfillmentRepository) {
+	orderRepo := mocks.NewMockOrderRepository()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		order := newTestOrder(5000)
+		order.ID = fmt.Sprintf("order-%02d", i)
+		order.Status = orders.OrderStatusPaid
+		if i%2 == 1 {
+			order.Status = orders.OrderStatusPending
+		}
+		order.UpdatedAt = base.Add(time.Duration(i) * time.Minute)
+		orderRepo.Orders[order.ID] = order
+	}
+
+	repo := mocks.NewMockFulfillmentRepository(orderRepo)
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+	return services.NewFulfillmentService(repo, orderService), orderRepo, repo
+}
+
+func TestFulfillmentService_OpenOrders(t *testing.T) {
+	ctx := context.Background()
+	svc, orderRepo, _ := newFulfillmentFixture(10)
+
+	var seen []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		result, err := svc.OpenOrders(ctx, cursor, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, order := range result.Orders {
+			seen = append(seen, order.ID)
+		}
+		cursor = result.NextCursor
+		if !result.HasMore {
+			break
+		}
+	}
+
+	expected := []string{"order-00", "order-02", "order-04", "order-06", "order-08"}
+	if fmt.Sprint(seen) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+
+	// An order paid after the last poll shows up from the saved cursor
+	late := orderRepo.Orders["order-09"]
+	late.Status = orders.OrderStatusPaid
+	late.UpdatedAt = late.UpdatedAt.Add(time.Hour)
+
+	result, err := svc.OpenOrders(ctx, cursor, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Orders) != 1 || result.Orders[0].ID != "order-09" {
+		t.Errorf("expected only order-09 after the cursor, got %d orders", len(result.Orders))
+	}
+
+	if _, err := svc.OpenOrders(ctx, "not-a-cursor", 2); err != services.ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestFulfillmentService_ConfirmShipment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("paid order moves to shipped", func(t *testing.T) {
+		svc, orderRepo, repo := newFulfillmentFixture(1)
+
+		shipment, err := svc.ConfirmShipment(ctx, "order-00", services.ShipmentRequest{Carrier: "UPS", TrackingNumber: "1Z999"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shipment.OrderID != "order-00" || len(repo.Shipments) != 1 {
+			t.Errorf("expected the shipment to be recorded, got %+v", repo.Shipments)
+		}
+		if status := orderRepo.Orders["order-00"].Status; status != orders.OrderStatusShipped {
+			t.Errorf("expected order to be shipped, got %s", status)
+		}
+	})
+
+	t.Run("retried confirmation returns the existing shipment", func(t *testing.T) {
+		svc, _, repo := newFulfillmentFixture(1)
+		req := services.ShipmentRequest{Carrier: "UPS", TrackingNumber: "1Z999"}
+
+		first, err := svc.ConfirmShipment(ctx, "order-00", req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := svc.ConfirmShipment(ctx, "order-00", req)
+		if err != nil {
+			t.Fatalf("unexpected error on retry: %v", err)
+		}
+		if second.ID != first.ID || len(repo.Shipments) != 1 {
+			t.Errorf("expected one shipment, got %d", len(repo.Shipments))
+		}
+	})
+
+	t.Run("unpaid order is rejected", func(t *testing.T) {
+		svc, _, _ := newFulfillmentFixture(2)
+
+		_, err := svc.ConfirmShipment(ctx, "order-01", services.ShipmentRequest{Carrier: "UPS", TrackingNumber: "1Z999"})
+		if err != services.ErrOrderNotFulfillable {
+			t.Errorf("expected ErrOrderNotFulfillable, got %v", err)
+		}
+	})
+
+	t.Run("tracking number is required", func(t *testing.T) {
+		svc, _, _ := newFulfillmentFixture(1)
+
+		_, err := svc.ConfirmShipment(ctx, "order-00", services.ShipmentRequest{Carrier: "UPS"})
+		if err != services.ErrInvalidShipment {
+			t.Errorf("expected ErrInvalidShipment, got %v", err)
+		}
+	})
+}
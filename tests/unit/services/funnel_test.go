@@ -0,0 +1,207 @@
+package services_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/retry"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type recordingFunnelNotifier struct {
+	alerts []*services.FunnelAlert
+}
+
+func (n *recordingFunnelNotifier) NotifyFunnelAlert(ctx context.Context, alert *services.FunnelAlert) error {
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// seedFunnel records count carts at each stage, converting by the given rates, at a time
+func seedFunnel(repo *mocks.MockFunnelRepository, prefix string, at time.Time, carts int, previewRate, orderRate, paidRate float64) {
+	ctx := context.Background()
+	previews := int(float64(carts) * previewRate)
+	orders := int(float64(previews) * orderRate)
+	paid := int(float64(orders) * paidRate)
+	for i := 0; i < carts; i++ {
+		repo.Record(ctx, services.FunnelStageCartCreated, fmt.Sprintf("%s-cart-%d", prefix, i), at)
+	}
+	for i := 0; i < previews; i++ {
+		repo.Record(ctx, services.FunnelStageCheckoutPreview, fmt.Sprintf("%s-cart-%d", prefix, i), at)
+	}
+	for i := 0; i < orders; i++ {
+		repo.Record(ctx, services.FunnelStageOrderCreated, fmt.Sprintf("%s-order-%d", prefix, i), at)
+	}
+	for i := 0; i < paid; i++ {
+		repo.Record(ctx, services.FunnelStagePaid, fmt.Sprintf("%s-order-%d", prefix, i), at)
+	}
+}
+
+func TestFunnel_RecordAndAdvance(t *testing.T) {
+	repo := mocks.NewMockFunnelRepository()
+	service := services.NewFunnelService(repo, services.FunnelPolicy{Window: time.Hour, Baseline: 24 * time.Hour, MaxDrop: 0.5})
+	ctx := context.Background()
+
+	service.Record(ctx, services.FunnelStageCartCreated, "cart-1")
+	service.Record(ctx, services.FunnelStageCartCreated, "cart-1")
+	service.Record(ctx, services.FunnelStageOrderCreated, "order-1")
+	service.Advance(ctx, services.FunnelStageOrderCreated, services.FunnelStagePaid, "order-1")
+	service.Advance(ctx, services.FunnelStageOrderCreated, services.FunnelStagePaid, "pos-order")
+
+	snapshot, err := service.Snapshot(ctx, 0)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if snapshot.Counts[services.FunnelStageCartCreated] != 1 {
+		t.Errorf("Expected a cart counted once, got %d", snapshot.Counts[services.FunnelStageCartCreated])
+	}
+	if snapshot.Counts[services.FunnelStagePaid] != 1 {
+		t.Errorf("Expected only orders placed at checkout counted as paid, got %d", snapshot.Counts[services.FunnelStagePaid])
+	}
+	if len(snapshot.Steps) != 3 || snapshot.Steps[2].Rate != 1 {
+		t.Errorf("Expected three steps with every order paid, got %+v", snapshot.Steps)
+	}
+
+	if _, err := service.Snapshot(ctx, time.Second); err != services.ErrInvalidFunnelWindow {
+		t.Errorf("Expected ErrInvalidFunnelWindow, got %v", err)
+	}
+}
+
+func TestFunnel_CheckAlertsOnDropOff(t *testing.T) {
+	repo := mocks.NewMockFunnelRepository()
+	notifier := &recordingFunnelNotifier{}
+	service := services.NewFunnelService(repo, services.FunnelPolicy{
+		Window:    time.Hour,
+		Baseline:  24 * time.Hour,
+		MaxDrop:   0.5,
+		MinSample: 20,
+	}).WithAlerts(notifier, nil)
+	ctx := context.Background()
+
+	// Half of previews became orders before; in the last hour only a tenth did
+	seedFunnel(repo, "before", time.Now().Add(-5*time.Hour), 200, 0.5, 0.5, 0.8)
+	seedFunnel(repo, "now", time.Now().Add(-10*time.Minute), 100, 0.5, 0.1, 0.8)
+
+	alerts, err := service.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("Expected one alert, got %+v", alerts)
+	}
+	alert := alerts[0]
+	if alert.From != services.FunnelStageCheckoutPreview || alert.To != services.FunnelStageOrderCreated {
+		t.Errorf("Expected the preview to order step alerted, got %s to %s", alert.From, alert.To)
+	}
+	if alert.Rate != 0.1 || alert.BaselineRate != 0.5 || alert.Entered != 50 {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+	if len(notifier.alerts) != 1 {
+		t.Errorf("Expected the alert sent, got %d", len(notifier.alerts))
+	}
+
+	if alerts, _ := service.Check(ctx); len(alerts) != 0 {
+		t.Errorf("Expected a step alerted once per window, got %+v", alerts)
+	}
+	listed, total, err := service.ListAlerts(ctx, 10, 0)
+	if err != nil || total != 1 || len(listed) != 1 {
+		t.Errorf("Expected the alert listed, got %v, %d, %v", listed, total, err)
+	}
+}
+
+func TestFunnel_CheckNeedsMinimumSample(t *testing.T) {
+	repo := mocks.NewMockFunnelRepository()
+	service := services.NewFunnelService(repo, services.FunnelPolicy{
+		Window:    time.Hour,
+		Baseline:  24 * time.Hour,
+		MaxDrop:   0.5,
+		MinSample: 100,
+	})
+
+	seedFunnel(repo, "before", time.Now().Add(-5*time.Hour), 200, 0.5, 0.5, 0.8)
+	seedFunnel(repo, "now", time.Now().Add(-10*time.Minute), 40, 0.5, 0, 0)
+
+	alerts, err := service.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("Expected no alerts below the minimum sample, got %+v", alerts)
+	}
+
+	deleted, err := service.Prune(context.Background())
+	if err != nil || deleted != 0 {
+		t.Errorf("Expected events within the baseline kept, got %d, %v", deleted, err)
+	}
+}
+
+func TestWebhookFunnelAlertNotifier(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := services.NewWebhookFunnelAlertNotifier(server.URL, "alert-secret", time.Second)
+	alert := &services.FunnelAlert{ID: "alert-1", From: services.FunnelStageOrderCreated, To: services.FunnelStagePaid, Rate: 0.2, BaselineRate: 0.9}
+	if err := notifier.NotifyFunnelAlert(context.Background(), alert); err != nil {
+		t.Fatalf("NotifyFunnelAlert failed: %v", err)
+	}
+
+	var event struct {
+		Type  string               `json:"type"`
+		Alert services.FunnelAlert `json:"alert"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("Expected a JSON body: %v", err)
+	}
+	if event.Type != "checkout_funnel.drop_off" || event.Alert.ID != "alert-1" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	mac := hmac.New(sha256.New, []byte("alert-secret"))
+	mac.Write(body)
+	if signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Expected the body signed, got %q", signature)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := services.NewWebhookFunnelAlertNotifier(failing.URL, "", time.Second).NotifyFunnelAlert(context.Background(), alert); err == nil {
+		t.Error("Expected an error from a failing webhook")
+	}
+
+	calls := 0
+	var retried []byte
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retried, _ = io.ReadAll(r.Body)
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer flaky.Close()
+	notifier = services.NewWebhookFunnelAlertNotifier(flaky.URL, "alert-secret", time.Second).
+		WithRetry(retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if err := notifier.NotifyFunnelAlert(context.Background(), alert); err != nil || calls != 2 {
+		t.Errorf("Expected the alert delivered on the second attempt, got %v after %d calls", err, calls)
+	}
+	if string(retried) != string(body) {
+		t.Errorf("Expected the retry to resend the same body, got %s", retried)
+	}
+}
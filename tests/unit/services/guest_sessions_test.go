@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+var guestSessionSecret = []byte("test-secret-key-that-is-at-least-32-chars")
+
+func TestGuestSessionService_IssueAndVerify(t *testing.T) {
+	svc := services.NewGuestSessionService(mocks.NewMockGuestSessionRepository(mocks.NewMockOrderRepository()), nil, mocks.NewMockCartRepository(), guestSessionSecret, time.Hour)
+
+	session := svc.Issue("")
+	sessionID, err := svc.Verify(session.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sessionID != session.ID {
+		t.Errorf("expected session %s, got %s", session.ID, sessionID)
+	}
+
+	// Renewing keeps the session
+	if renewed := svc.Issue(session.ID); renewed.ID != session.ID {
+		t.Errorf("expected a renewed token for session %s, got %s", session.ID, renewed.ID)
+	}
+
+	tampered := strings.Replace(session.Token, session.ID, "someone-else", 1)
+	if _, err := svc.Verify(tampered); err != services.ErrInvalidGuestSession {
+		t.Errorf("expected ErrInvalidGuestSession for a tampered token, got %v", err)
+	}
+	other := services.NewGuestSessionService(nil, nil, nil, []byte("another-secret-key-that-is-32-chars-long"), time.Hour)
+	if _, err := other.Verify(session.Token); err != services.ErrInvalidGuestSession {
+		t.Errorf("expected ErrInvalidGuestSession for a token signed with another secret, got %v", err)
+	}
+	expired := services.NewGuestSessionService(nil, nil, nil, guestSessionSecret, -time.Minute).Issue("")
+	if _, err := svc.Verify(expired.Token); err != services.ErrInvalidGuestSession {
+		t.Errorf("expected ErrInvalidGuestSession for an expired token, got %v", err)
+	}
+	if _, err := svc.Verify("not-a-token"); err != services.ErrInvalidGuestSession {
+		t.Errorf("expected ErrInvalidGuestSession for a malformed token, got %v", err)
+	}
+}
+
+func TestGuestSessionService_Claim(t *testing.T) {
+	ctx := context.Background()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductPhone.ID] = fixtures.ProductPhone
+	cartRepo := mocks.NewMockCartRepository()
+	orderRepo := mocks.NewMockOrderRepository()
+	viewRepo := mocks.NewMockRecentlyViewedRepository()
+
+	cartService := services.NewCartService(cartRepo, productRepo, mocks.NewMockVariantRepository(), nil)
+	recentlyViewed := services.NewRecentlyViewedService(viewRepo, productRepo)
+	svc := services.NewGuestSessionService(mocks.NewMockGuestSessionRepository(orderRepo), cartService, cartRepo, guestSessionSecret, time.Hour).
+		WithRecentlyViewed(recentlyViewed)
+
+	session := svc.Issue("")
+	guestID := services.GuestUserID(session.ID)
+
+	// The guest fills a cart, views products and checks out once
+	guestCart, err := cartService.GetOrCreateCart(ctx, "", session.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cartService.AddItem(ctx, guestCart.ID, cart.AddItemRequest{ProductID: fixtures.ProductLaptop.ID, Quantity: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, productID := range []string{fixtures.ProductLaptop.ID, fixtures.ProductPhone.ID} {
+		if err := recentlyViewed.RecordView(ctx, guestID, productID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := recentlyViewed.RecordView(ctx, guestID, "prod-missing"); err != services.ErrViewedProductNotFound {
+		t.Errorf("expected ErrViewedProductNotFound for an unknown product, got %v", err)
+	}
+	orderRepo.Orders["order-guest"] = &orders.Order{ID: "order-guest", UserID: guestID, Status: orders.OrderStatusPaid}
+
+	// The account already has a cart of its own
+	userCart, err := cartService.GetOrCreateCart(ctx, fixtures.TestUserID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{ProductID: fixtures.ProductPhone.ID, Quantity: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claim, err := svc.Claim(ctx, session.ID, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !claim.CartMerged || claim.OrdersClaimed != 1 {
+		t.Errorf("expected the cart merged and 1 order claimed, got %+v", claim)
+	}
+
+	merged, err := cartRepo.FindByUserID(ctx, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(merged.Items) != 2 {
+		t.Errorf("expected the guest item merged into the account's cart, got %d items", len(merged.Items))
+	}
+	if _, err := cartRepo.FindBySessionID(ctx, session.ID); err != cart.ErrCartNotFound {
+		t.Errorf("expected the guest cart removed, got %v", err)
+	}
+	if owner := orderRepo.Orders["order-guest"].UserID; owner != fixtures.TestUserID {
+		t.Errorf("expected the guest order to belong to the account, got %s", owner)
+	}
+
+	viewed, err := recentlyViewed.List(ctx, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(viewed) != 2 || viewed[0].Product.ID != fixtures.ProductPhone.ID {
+		t.Errorf("expected the guest's 2 views with the phone first, got %d", len(viewed))
+	}
+	if guestViews, _ := recentlyViewed.List(ctx, guestID); len(guestViews) != 0 {
+		t.Errorf("expected no views left on the guest session, got %d", len(guestViews))
+	}
+}
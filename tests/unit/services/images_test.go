@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestParseImagePresets(t *testing.T) {
+	presets, err := services.ParseImagePresets([]string{"thumb:160x160:webp", " Zoom :1600x0", "card_2x:0x960:AVIF"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []services.ImagePreset{
+		{Name: "thumb", Width: 160, Height: 160, Format: "webp"},
+		{Name: "zoom", Width: 1600, Height: 0},
+		{Name: "card_2x", Width: 0, Height: 960, Format: "avif"},
+	}
+	if len(presets) != len(expected) {
+		t.Fatalf("expected %d presets, got %+v", len(expected), presets)
+	}
+	for i := range expected {
+		if presets[i] != expected[i] {
+			t.Errorf("expected preset %+v, got %+v", expected[i], presets[i])
+		}
+	}
+
+	for _, entries := range [][]string{
+		{"thumb"},
+		{"thumb:160"},
+		{"thumb:0x0"},
+		{"thumb:160x160:gif"},
+		{"Thumb Nail:160x160"},
+		{"thumb:160x160", "thumb:320x320"},
+		{"thumb:-1x160"},
+	} {
+		if _, err := services.ParseImagePresets(entries); err == nil {
+			t.Errorf("expected %q to be rejected", entries)
+		}
+	}
+}
+
+func TestImageService_Renditions(t *testing.T) {
+	presets, _ := services.ParseImagePresets([]string{"thumb:160x160:webp", "zoom:1600x0"})
+	source := "https://images.example.com/laptop.jpg"
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(source))
+
+	// Unsigned URLs for a CDN that allows them
+	unsigned := services.NewImageService("https://img.example.com/", "", presets, nil, nil)
+	renditions := unsigned.Renditions([]string{source, "/media/products/laptop.jpg"})
+	if len(renditions) != 2 {
+		t.Fatalf("expected renditions for each image, got %v", renditions)
+	}
+	if got := renditions[0]["thumb"]; got != "https://img.example.com/insecure/rs:fit:160:160/"+encoded+".webp" {
+		t.Errorf("unexpected thumb URL %s", got)
+	}
+	if got := renditions[0]["zoom"]; got != "https://img.example.com/insecure/rs:fit:1600:0/"+encoded {
+		t.Errorf("unexpected zoom URL %s", got)
+	}
+	if renditions[1] != nil {
+		t.Errorf("expected no renditions for a relative path without a source URL, got %v", renditions[1])
+	}
+
+	// Signed URLs carry the HMAC-SHA256 of the salt and path
+	key, salt := []byte("secret-key"), []byte("secret-salt")
+	signed := services.NewImageService("https://img.example.com", "https://shop.example.com", presets, key, salt)
+	renditions = signed.Renditions([]string{"/media/products/laptop.jpg"})
+	path := "/rs:fit:160:160/" + base64.RawURLEncoding.EncodeToString([]byte("https://shop.example.com/media/products/laptop.jpg")) + ".webp"
+	mac := hmac.New(sha256.New, key)
+	mac.Write(salt)
+	mac.Write([]byte(path))
+	expected := "https://img.example.com/" + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) + path
+	if got := renditions[0]["thumb"]; got != expected {
+		t.Errorf("expected signed URL %s, got %s", expected, got)
+	}
+
+	if signed.Renditions(nil) != nil {
+		t.Error("expected no renditions for a product without images")
+	}
+}
+
+func TestCatalogService_ImageRenditions(t *testing.T) {
+	products := mocks.NewMockProductRepository()
+	products.Products["prod-1"] = &catalog.Product{ID: "prod-1", Name: "Laptop", Images: []string{"https://images.example.com/laptop.jpg"}}
+	presets, _ := services.ParseImagePresets([]string{"thumb:160x160:webp"})
+	svc := services.NewCatalogService(products, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithImages(services.NewImageService("https://img.example.com", "", presets, nil, nil))
+	ctx := context.Background()
+
+	product, err := svc.GetProduct(ctx, "prod-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(product.ImageRenditions) != 1 || product.ImageRenditions[0]["thumb"] == "" {
+		t.Fatalf("expected a thumb rendition of the product image, got %v", product.ImageRenditions)
+	}
+
+	listed, err := svc.ListProducts(ctx, catalog.ProductFilter{})
+	if err != nil || len(listed) != 1 || len(listed[0].ImageRenditions) != 1 {
+		t.Fatalf("expected listed products to have renditions, got %v (%v)", listed, err)
+	}
+	converted, err := svc.InCurrency(listed, "")
+	if err != nil || len(converted[0].ImageRenditions) != 1 {
+		t.Errorf("expected renditions kept by a currency conversion, got %v (%v)", converted, err)
+	}
+}
@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/inventory"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// stubStock is an in-memory inventory.Service
+type stubStock struct {
+	onHand   map[string]int
+	reserved map[string]map[string]int // reference -> SKU -> units
+}
+
+func newStubStock(onHand map[string]int) *stubStock {
+	return &stubStock{onHand: onHand, reserved: make(map[string]map[string]int)}
+}
+
+func (s *stubStock) GetAvailableStock(ctx context.Context, sku string) (int, error) {
+	reserved, _ := s.GetReservedStock(ctx, sku)
+	return s.onHand[sku] - reserved, nil
+}
+
+func (s *stubStock) GetReservedStock(ctx context.Context, sku string) (int, error) {
+	total := 0
+	for _, skus := range s.reserved {
+		total += skus[sku]
+	}
+	return total, nil
+}
+
+func (s *stubStock) Reserve(ctx context.Context, sku string, quantity int, referenceID string) error {
+	if available, _ := s.GetAvailableStock(ctx, sku); available < quantity {
+		return inventory.ErrInsufficientStock
+	}
+	if s.reserved[referenceID] == nil {
+		s.reserved[referenceID] = make(map[string]int)
+	}
+	s.reserved[referenceID][sku] += quantity
+	return nil
+}
+
+func (s *stubStock) Release(ctx context.Context, sku string, quantity int, referenceID string) error {
+	s.reserved[referenceID][sku] -= quantity
+	return nil
+}
+
+func (s *stubStock) Commit(ctx context.Context, referenceID string) error {
+	for sku, quantity := range s.reserved[referenceID] {
+		s.onHand[sku] -= quantity
+	}
+	delete(s.reserved, referenceID)
+	return nil
+}
+
+func (s *stubStock) AdjustStock(ctx context.Context, sku string, quantity int, reason string) error {
+	s.onHand[sku] += quantity
+	return nil
+}
+
+func TestRecordInventory_RecordsMovements(t *testing.T) {
+	ctx := context.Background()
+	history := mocks.NewMockInventoryHistoryRepository()
+	stock := services.RecordInventory(newStubStock(map[string]int{"TSHIRT-001": 10}), history)
+
+	if services.RecordInventory(nil, history) != nil {
+		t.Error("Expected no inventory service when stock is not tracked")
+	}
+
+	if err := stock.Reserve(ctx, "TSHIRT-001", 3, "order-1"); err != nil {
+		t.Fatalf("Reserve failed: %v", err)
+	}
+	if err := stock.Release(ctx, "TSHIRT-001", 1, "order-1"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := stock.Commit(ctx, "order-1"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := stock.AdjustStock(ctx, "TSHIRT-001", -1, "damage"); err != nil {
+		t.Fatalf("AdjustStock failed: %v", err)
+	}
+
+	// A failed change is not recorded
+	if err := stock.Reserve(ctx, "TSHIRT-001", 50, "order-2"); err != inventory.ErrInsufficientStock {
+		t.Fatalf("Expected ErrInsufficientStock, got %v", err)
+	}
+
+	expected := []struct {
+		movementType            services.StockMovementType
+		quantity, before, after int
+	}{
+		{services.StockMovementReservation, 3, 10, 10},
+		{services.StockMovementRelease, 1, 10, 10},
+		{services.StockMovementCommit, 2, 10, 8},
+		{services.StockMovementAdjustment, -1, 8, 7},
+	}
+	if len(history.Movements) != len(expected) {
+		t.Fatalf("Expected %d movements, got %d", len(expected), len(history.Movements))
+	}
+	for i, want := range expected {
+		got := history.Movements[i]
+		if got.Type != want.movementType || got.Quantity != want.quantity || got.OnHandBefore != want.before || got.OnHandAfter != want.after {
+			t.Errorf("Movement %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+	if history.Movements[3].Reason != "damage" {
+		t.Errorf("Expected the adjustment reason to be kept, got %q", history.Movements[3].Reason)
+	}
+}
+
+func TestInventoryHistory_UnexplainedChangeAndStockAt(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockInventoryHistoryRepository()
+	service := services.NewInventoryHistoryService(repo)
+
+	day1 := time.Date(2026, 1, 1, 23, 55, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	repo.Snapshots = []*services.InventorySnapshot{
+		{SKU: "TSHIRT-001", Date: "2026-01-01", OnHand: 10, TakenAt: day1},
+		{SKU: "TSHIRT-001", Date: "2026-01-02", OnHand: 6, TakenAt: day2},
+	}
+	sold := day1.Add(12 * time.Hour)
+	repo.Movements = []*services.StockMovement{
+		{SKU: "TSHIRT-001", Type: services.StockMovementCommit, Quantity: 2, OnHandBefore: 10, OnHandAfter: 8, CreatedAt: sold},
+	}
+
+	history, err := service.History(ctx, "TSHIRT-001", day1.AddDate(0, 0, -1), day2.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history.Snapshots) != 2 || len(history.Movements) != 1 {
+		t.Fatalf("Expected 2 snapshots and 1 movement, got %d and %d", len(history.Snapshots), len(history.Movements))
+	}
+	if history.Snapshots[0].UnexplainedChange != nil {
+		t.Error("Expected no unexplained change on the first snapshot")
+	}
+	// On-hand fell by 4 but only 2 were sold
+	if change := history.Snapshots[1].UnexplainedChange; change == nil || *change != -2 {
+		t.Errorf("Expected an unexplained change of -2, got %v", change)
+	}
+
+	stock, err := service.StockAt(ctx, "TSHIRT-001", sold.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("StockAt failed: %v", err)
+	}
+	if stock.OnHand != 8 || stock.Source != "movement" {
+		t.Errorf("Expected 8 on hand from the movement, got %d from %s", stock.OnHand, stock.Source)
+	}
+	stock, err = service.StockAt(ctx, "TSHIRT-001", day2.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("StockAt failed: %v", err)
+	}
+	if stock.OnHand != 6 || stock.Source != "snapshot" {
+		t.Errorf("Expected 6 on hand from the snapshot, got %d from %s", stock.OnHand, stock.Source)
+	}
+	if _, err := service.StockAt(ctx, "TSHIRT-001", day1.Add(-time.Hour)); err != services.ErrNoStockHistory {
+		t.Errorf("Expected ErrNoStockHistory, got %v", err)
+	}
+
+	if _, err := service.History(ctx, "TSHIRT-001", day2, day1); err != services.ErrInvalidStockHistoryRange {
+		t.Errorf("Expected ErrInvalidStockHistoryRange, got %v", err)
+	}
+}
+
+func TestInventoryHistory_TakeSnapshotsReplacesTheDay(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockInventoryHistoryRepository()
+	service := services.NewInventoryHistoryService(repo)
+
+	repo.Levels["TSHIRT-001"] = services.InventorySnapshot{OnHand: 10, Reserved: 2, Available: 8}
+	if _, err := service.TakeSnapshots(ctx); err != nil {
+		t.Fatalf("TakeSnapshots failed: %v", err)
+	}
+
+	repo.Levels["TSHIRT-001"] = services.InventorySnapshot{OnHand: 9, Reserved: 0, Available: 9}
+	recorded, err := service.TakeSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("TakeSnapshots failed: %v", err)
+	}
+	if recorded != 1 || len(repo.Snapshots) != 1 || repo.Snapshots[0].OnHand != 9 {
+		t.Errorf("Expected one snapshot per SKU per day with the latest level, got %d snapshots", len(repo.Snapshots))
+	}
+}
@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newInvoiceFixture gives the company fixture net-30 terms and an invoice service
+func newInvoiceFixture(t *testing.T) (*companyFixture, *services.InvoiceService, *mocks.MockInvoiceRepository) {
+	f := newCompanyFixture(t)
+	_, err := f.service.UpdateCompany(context.Background(), f.company.ID, services.CompanyInput{
+		Name:              f.company.Name,
+		ApprovalThreshold: f.company.ApprovalThreshold,
+		NetTermsDays:      30,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := mocks.NewMockInvoiceRepository()
+	invoices := services.NewInvoiceService(repo, f.service, services.NewOrderService(f.orderRepo, nil, nil, nil))
+	f.service.WithInvoiceService(invoices)
+	return f, invoices, repo
+}
+
+// invoiceOrder stores an order and opens its invoice
+func invoiceOrder(t *testing.T, f *companyFixture, invoices *services.InvoiceService, id, userID string, total int64) *services.Invoice {
+	order := &orders.Order{ID: id, OrderNumber: "ORD-" + id, UserID: userID, Status: orders.OrderStatusPending, Total: usd(total)}
+	f.orderRepo.Orders[id] = order
+
+	invoice, err := invoices.OpenInvoice(context.Background(), userID, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return invoice
+}
+
+func TestInvoiceService_OpenInvoice(t *testing.T) {
+	ctx := context.Background()
+	f, invoices, _ := newInvoiceFixture(t)
+
+	invoice := invoiceOrder(t, f, invoices, "order-1", "buyer-1", 12000)
+	if invoice.Number != "INV-order-1" || invoice.Status != services.InvoiceStatusOpen || invoice.CompanyID != f.company.ID {
+		t.Errorf("expected an open invoice for the company, got %+v", invoice)
+	}
+	if days := invoice.DueAt.Sub(invoice.IssuedAt).Hours() / 24; days != 30 || invoice.Balance() != usd(12000) {
+		t.Errorf("expected the full total due in 30 days, got %v days and %+v", days, invoice.Balance())
+	}
+
+	if _, err := invoices.NetTerms(ctx, "outsider"); err != services.ErrNetTermsUnavailable {
+		t.Errorf("expected ErrNetTermsUnavailable for a non-member, got %v", err)
+	}
+	other, _ := f.service.CreateCompany(ctx, services.CompanyInput{Name: "Globex"})
+	if _, err := f.service.SetMember(ctx, other.ID, "buyer-2", services.CompanyRoleBuyer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := invoices.NetTerms(ctx, "buyer-2"); err != services.ErrNetTermsUnavailable {
+		t.Errorf("expected ErrNetTermsUnavailable for a company without terms, got %v", err)
+	}
+	if _, err := f.service.CreateCompany(ctx, services.CompanyInput{Name: "Initech", NetTermsDays: 45}); err != services.ErrInvalidCompany {
+		t.Errorf("expected ErrInvalidCompany for net-45, got %v", err)
+	}
+}
+
+func TestInvoiceService_RecordPayment(t *testing.T) {
+	ctx := context.Background()
+	f, invoices, _ := newInvoiceFixture(t)
+	invoice := invoiceOrder(t, f, invoices, "order-1", "buyer-1", 12000)
+
+	if _, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 0}); err != services.ErrInvalidInvoicePayment {
+		t.Errorf("expected ErrInvalidInvoicePayment, got %v", err)
+	}
+	if _, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 12001}); err != services.ErrInvoiceOverpaid {
+		t.Errorf("expected ErrInvoiceOverpaid, got %v", err)
+	}
+
+	partial, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 5000, Reference: " WIRE-1 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if partial.Status != services.InvoiceStatusOpen || partial.Balance() != usd(7000) || len(partial.Payments) != 1 || partial.Payments[0].Reference != "WIRE-1" {
+		t.Errorf("expected a partly paid open invoice, got %+v", partial)
+	}
+	if f.orderRepo.Orders["order-1"].Status != orders.OrderStatusPending {
+		t.Error("expected the order to stay pending until the invoice is settled")
+	}
+
+	settled, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 7000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settled.Status != services.InvoiceStatusPaid || settled.PaidAt == nil || len(settled.Payments) != 2 {
+		t.Errorf("expected the invoice settled, got %+v", settled)
+	}
+	if f.orderRepo.Orders["order-1"].Status != orders.OrderStatusPaid {
+		t.Errorf("expected the order marked paid, got %s", f.orderRepo.Orders["order-1"].Status)
+	}
+
+	if _, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 1}); err != services.ErrInvoiceNotOpen {
+		t.Errorf("expected ErrInvoiceNotOpen paying a settled invoice, got %v", err)
+	}
+}
+
+func TestInvoiceService_OverdueReport(t *testing.T) {
+	ctx := context.Background()
+	f, invoices, repo := newInvoiceFixture(t)
+	now := time.Now()
+
+	late := invoiceOrder(t, f, invoices, "order-1", "buyer-1", 12000)
+	later := invoiceOrder(t, f, invoices, "order-2", "buyer-1", 3000)
+	invoiceOrder(t, f, invoices, "order-3", "buyer-1", 9000) // not yet due
+	repo.Invoices[late.ID].DueAt = now.AddDate(0, 0, -5)
+	repo.Invoices[later.ID].DueAt = now.AddDate(0, 0, -40)
+	if _, err := invoices.RecordPayment(ctx, "admin-1", late.ID, services.InvoicePaymentInput{Amount: 2000}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := invoices.OverdueReport(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Invoices) != 2 || report.Invoices[0].ID != later.ID || report.Invoices[0].DaysOverdue != 40 {
+		t.Fatalf("expected 2 overdue invoices, most overdue first, got %+v", report.Invoices)
+	}
+	if report.Invoices[1].Balance != usd(10000) {
+		t.Errorf("expected the balance left after a partial payment, got %+v", report.Invoices[1].Balance)
+	}
+	if len(report.Totals) != 1 || report.Totals[0] != usd(13000) {
+		t.Errorf("expected $130 overdue, got %+v", report.Totals)
+	}
+}
+
+func TestInvoiceService_RejectVoidsInvoice(t *testing.T) {
+	ctx := context.Background()
+	f, invoices, _ := newInvoiceFixture(t)
+	invoice := invoiceOrder(t, f, invoices, "order-1", "buyer-1", 60000)
+	if _, err := f.service.RecordOrder(ctx, f.orderRepo.Orders["order-1"]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := f.service.Reject(ctx, "approver-1", "order-1", "over budget"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	voided, err := invoices.GetInvoice(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if voided.Status != services.InvoiceStatusVoid || voided.VoidedAt == nil {
+		t.Errorf("expected the rejected order's invoice voided, got %+v", voided)
+	}
+	if _, err := invoices.RecordPayment(ctx, "admin-1", invoice.ID, services.InvoicePaymentInput{Amount: 100}); err != services.ErrInvoiceNotOpen {
+		t.Errorf("expected ErrInvoiceNotOpen paying a void invoice, got %v", err)
+	}
+}
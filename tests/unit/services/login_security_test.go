@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// recordingAlerts keeps the new-device alerts it is asked to send
+type recordingAlerts struct {
+	sent []services.NewDeviceLogin
+}
+
+func (r *recordingAlerts) NotifyNewDevice(ctx context.Context, login services.NewDeviceLogin) error {
+	r.sent = append(r.sent, login)
+	return nil
+}
+
+func TestLoginSecurity_ProgressiveLockout(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLoginSecurityRepository()
+	security := services.NewLoginSecurityService(repo, services.LoginSecurityPolicy{
+		MaxFailures: 3,
+		LockoutBase: time.Minute,
+		LockoutMax:  3 * time.Minute,
+	})
+	email := "Shopper@Example.com"
+
+	for i := 0; i < 2; i++ {
+		if err := security.RecordFailure(ctx, email); err != nil {
+			t.Fatalf("expected failure %d not to lock the account, got %v", i+1, err)
+		}
+	}
+	err := security.RecordFailure(ctx, email)
+	lockedErr, ok := err.(*services.AccountLockedError)
+	if !ok {
+		t.Fatalf("expected the third failure to lock the account, got %v", err)
+	}
+	if d := time.Until(lockedErr.Until); d < 50*time.Second || d > time.Minute {
+		t.Errorf("expected a first lockout of about a minute, got %v", d)
+	}
+	// Lockouts apply to the address whatever its case
+	if _, ok := security.CheckLocked(ctx, "shopper@example.com").(*services.AccountLockedError); !ok {
+		t.Fatal("expected the account to be locked")
+	}
+
+	// Each later lockout doubles, up to the maximum
+	expected := []time.Duration{2 * time.Minute, 3 * time.Minute}
+	for _, want := range expected {
+		for i := 0; i < 3; i++ {
+			err = security.RecordFailure(ctx, email)
+		}
+		lockedErr, ok = err.(*services.AccountLockedError)
+		if !ok {
+			t.Fatalf("expected another lockout, got %v", err)
+		}
+		if d := time.Until(lockedErr.Until); d < want-10*time.Second || d > want {
+			t.Errorf("expected a lockout of about %v, got %v", want, d)
+		}
+	}
+
+	if err := security.Unlock(ctx, email); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := security.CheckLocked(ctx, email); err != nil {
+		t.Fatalf("expected the unlocked account to accept logins, got %v", err)
+	}
+	if err := security.Unlock(ctx, email); err != services.ErrLoginLockoutNotFound {
+		t.Fatalf("expected ErrLoginLockoutNotFound, got %v", err)
+	}
+}
+
+func TestLoginSecurity_NewDeviceAlerts(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockLoginSecurityRepository()
+	alerts := &recordingAlerts{}
+	security := services.NewLoginSecurityService(repo, services.LoginSecurityPolicy{
+		MaxFailures: 5,
+		LockoutBase: time.Minute,
+		LockoutMax:  time.Hour,
+	}).WithAlerts(alerts, nil)
+
+	const laptop, phone = "Mozilla/5.0 (Macintosh)", "Mozilla/5.0 (iPhone)"
+
+	// A failed attempt is cleared by the next successful sign-in
+	if err := security.RecordFailure(ctx, "user@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := security.RecordLogin(ctx, fixtures.TestUserID, "user@example.com", laptop, "203.0.113.7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := security.Lockout(ctx, "user@example.com"); err != services.ErrLoginLockoutNotFound {
+		t.Errorf("expected the failed login count to be cleared, got %v", err)
+	}
+	if len(alerts.sent) != 0 {
+		t.Fatalf("expected no alert for the first device, got %d", len(alerts.sent))
+	}
+
+	if err := security.RecordLogin(ctx, fixtures.TestUserID, "user@example.com", laptop, "203.0.113.7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts.sent) != 0 {
+		t.Fatalf("expected no alert for a known device, got %d", len(alerts.sent))
+	}
+
+	if err := security.RecordLogin(ctx, fixtures.TestUserID, "user@example.com", phone, "198.51.100.20"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts.sent) != 1 {
+		t.Fatalf("expected one alert for the new device, got %d", len(alerts.sent))
+	}
+	if alert := alerts.sent[0]; alert.Email != "user@example.com" || alert.IPAddress != "198.51.100.20" || alert.UserAgent != phone {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func mockIntentRequest(paymentMethodID string) payments.IntentRequest {
+	return payments.IntentRequest{
+		Amount:          usd(5000),
+		Currency:        "USD",
+		PaymentMethodID: paymentMethodID,
+		OrderID:         "order-1",
+		Metadata:        map[string]string{"tender_id": "tender-1"},
+		CaptureMethod:   payments.CaptureMethodAutomatic,
+	}
+}
+
+func TestMockPaymentGateway_CreateIntent(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		mode            services.MockGatewayMode
+		paymentMethodID string
+		want            payments.IntentStatus
+		wantErr         error
+	}{
+		{"approves by default", services.MockGatewayApprove, "pm_anything", payments.IntentStatusSucceeded, nil},
+		{"declined test card", services.MockGatewayApprove, services.MockPaymentMethodDecline, payments.IntentStatusFailed, nil},
+		{"processing test card", services.MockGatewayApprove, services.MockPaymentMethodAsync, payments.IntentStatusProcessing, nil},
+		{"error test card", services.MockGatewayApprove, services.MockPaymentMethodError, "", services.ErrMockGatewayError},
+		{"no payment method stays pending", services.MockGatewayApprove, "", payments.IntentStatusPending, nil},
+		{"decline mode", services.MockGatewayDecline, "pm_anything", payments.IntentStatusFailed, nil},
+		{"approving test card beats decline mode", services.MockGatewayDecline, services.MockPaymentMethodApprove, payments.IntentStatusSucceeded, nil},
+		{"async mode", services.MockGatewayAsync, "pm_anything", payments.IntentStatusProcessing, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{Mode: tt.mode, WebhookDelay: time.Hour})
+			intent, err := gateway.CreateIntent(ctx, mockIntentRequest(tt.paymentMethodID))
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && intent.Status != tt.want {
+				t.Errorf("expected status %s, got %s", tt.want, intent.Status)
+			}
+		})
+	}
+
+	t.Run("failure rate declines every charge at 1", func(t *testing.T) {
+		gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{FailureRate: 1})
+		intent, err := gateway.CreateIntent(ctx, mockIntentRequest("pm_anything"))
+		if err != nil || intent.Status != payments.IntentStatusFailed {
+			t.Fatalf("expected a declined intent, got %+v, %v", intent, err)
+		}
+	})
+
+	t.Run("error rate fails every call at 1", func(t *testing.T) {
+		gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{ErrorRate: 1})
+		if _, err := gateway.CreateIntent(ctx, mockIntentRequest(services.MockPaymentMethodApprove)); err != services.ErrMockGatewayError {
+			t.Fatalf("expected ErrMockGatewayError, got %v", err)
+		}
+	})
+
+	t.Run("latency respects the context", func(t *testing.T) {
+		gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{Latency: time.Hour})
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if _, err := gateway.CreateIntent(ctx, mockIntentRequest("pm_anything")); err != context.DeadlineExceeded {
+			t.Fatalf("expected the call to time out, got %v", err)
+		}
+	})
+}
+
+func TestMockPaymentGateway_Refunds(t *testing.T) {
+	ctx := context.Background()
+	gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{})
+	intent, err := gateway.CreateIntent(ctx, mockIntentRequest(services.MockPaymentMethodApprove))
+	if err != nil {
+		t.Fatalf("CreateIntent: %v", err)
+	}
+
+	if _, err := gateway.CreateRefund(ctx, payments.RefundRequest{PaymentIntentID: intent.ID, Amount: usd(3000)}); err != nil {
+		t.Fatalf("expected partial refund to succeed, got %v", err)
+	}
+	if _, err := gateway.CreateRefund(ctx, payments.RefundRequest{PaymentIntentID: intent.ID, Amount: usd(3000)}); err != services.ErrMockNotRefundable {
+		t.Errorf("expected refunds past the captured amount to fail, got %v", err)
+	}
+}
+
+func TestMockPaymentGateway_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockWebhookEventRepository()
+	// The runner is never started; the test only checks that the events are accepted
+	webhooks := services.NewWebhookService(repo, jobs.NewRunner(1, 10))
+	if err := webhooks.RegisterProvider("payments", services.NewPaymentWebhookProvider("secret", nil)); err != nil {
+		t.Fatalf("RegisterProvider: %v", err)
+	}
+	gateway := services.NewMockPaymentGateway(services.MockGatewayOptions{}).
+		WithWebhooks(webhooks, "payments", "secret")
+
+	for _, paymentMethodID := range []string{services.MockPaymentMethodAsync, services.MockPaymentMethodAsyncDecline} {
+		if _, err := gateway.CreateIntent(ctx, mockIntentRequest(paymentMethodID)); err != nil {
+			t.Fatalf("CreateIntent: %v", err)
+		}
+	}
+
+	var events []*services.WebhookEvent
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		var err error
+		if events, err = webhooks.ListEvents(ctx, services.WebhookEventFilter{Provider: "payments"}); err != nil {
+			t.Fatalf("ListEvents: %v", err)
+		}
+		if len(events) == 2 {
+			break
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected two signed webhooks to be accepted, got %d", len(events))
+	}
+
+	types := map[string]bool{}
+	for _, event := range events {
+		var payload services.PaymentIntentWebhookPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if payload.Data.OrderID != "order-1" || payload.Data.TenderID != "tender-1" {
+			t.Errorf("expected the order and tender to be echoed, got %+v", payload.Data)
+		}
+		types[event.EventType] = true
+	}
+	if !types[services.PaymentEventIntentSucceeded] || !types[services.PaymentEventIntentFailed] {
+		t.Errorf("expected a succeeded and a failed event, got %v", types)
+	}
+}
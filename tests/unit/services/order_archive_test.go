@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newArchiveRepo holds five delivered orders last changed 13 months ago, one delivered last
+// month and one shipped two years ago
+func newArchiveRepo() *mocks.MockOrderArchiveRepository {
+	repo := mocks.NewMockOrderArchiveRepository()
+	old := time.Now().AddDate(0, -13, 0)
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("old-%d", i)
+		repo.Orders[id] = &orders.Order{ID: id, Status: orders.OrderStatusDelivered, UpdatedAt: old.Add(time.Duration(i) * time.Hour)}
+	}
+	repo.Orders["recent"] = &orders.Order{ID: "recent", Status: orders.OrderStatusDelivered, UpdatedAt: time.Now().AddDate(0, -1, 0)}
+	repo.Orders["shipped"] = &orders.Order{ID: "shipped", Status: orders.OrderStatusShipped, UpdatedAt: time.Now().AddDate(-2, 0, 0)}
+	return repo
+}
+
+func TestOrderArchive_MovesOldDeliveredOrdersInBatches(t *testing.T) {
+	repo := newArchiveRepo()
+	service := services.NewOrderArchiveService(repo, services.OrderArchivePolicy{AfterMonths: 12, BatchSize: 2})
+
+	report, err := service.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Orders != 5 || report.Archived != 5 {
+		t.Errorf("expected 5 orders archived, got %d moved and %d in the archive", report.Orders, report.Archived)
+	}
+	if fmt.Sprint(repo.Batches) != "[2 2 1]" {
+		t.Errorf("expected batches of 2 until one came up short, got %v", repo.Batches)
+	}
+	if _, ok := repo.Orders["recent"]; !ok {
+		t.Error("expected an order delivered last month kept")
+	}
+	if _, ok := repo.Orders["shipped"]; !ok {
+		t.Error("expected an order not yet delivered kept")
+	}
+	if want := time.Now().AddDate(0, -12, 0); report.Before == nil || want.Sub(*report.Before) > time.Minute {
+		t.Errorf("expected a cutoff 12 months ago, got %v", report.Before)
+	}
+}
+
+func TestOrderArchive_DryRunAndDisabled(t *testing.T) {
+	repo := newArchiveRepo()
+	service := services.NewOrderArchiveService(repo, services.OrderArchivePolicy{AfterMonths: 12, BatchSize: 2})
+
+	report, err := service.Run(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun || report.Orders != 5 || len(repo.Archived) != 0 {
+		t.Errorf("expected 5 orders reported and none moved, got %+v with %d archived", report, len(repo.Archived))
+	}
+
+	disabled := services.NewOrderArchiveService(repo, services.OrderArchivePolicy{})
+	report, err = disabled.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Orders != 0 || report.Before != nil || len(repo.Archived) != 0 {
+		t.Errorf("expected nothing archived with archiving off, got %+v", report)
+	}
+}
@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestOrderAttribution_Record(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockOrderAttributionRepository(mocks.NewMockOrderRepository())
+	svc := services.NewOrderAttributionService(repo)
+
+	attribution, err := svc.Record(ctx, "order-1", "", services.UTM{Source: " newsletter ", Campaign: "spring-sale"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attribution.Channel != services.ChannelWeb {
+		t.Errorf("expected orders without a channel to be web, got %s", attribution.Channel)
+	}
+	if attribution.UTM.Source != "newsletter" {
+		t.Errorf("expected UTM values to be trimmed, got %q", attribution.UTM.Source)
+	}
+
+	if _, err := svc.Record(ctx, "order-2", "fax", services.UTM{}); err != services.ErrInvalidAttribution {
+		t.Errorf("expected ErrInvalidAttribution for an unknown channel, got %v", err)
+	}
+	if _, err := svc.Record(ctx, "order-2", services.ChannelMobileApp, services.UTM{Term: strings.Repeat("x", 256)}); err != services.ErrInvalidAttribution {
+		t.Errorf("expected ErrInvalidAttribution for a long UTM value, got %v", err)
+	}
+	if found, _ := svc.GetOrderAttribution(ctx, "order-2"); found != nil {
+		t.Errorf("expected nothing recorded for invalid attribution, got %+v", found)
+	}
+}
+
+func TestOrderAttribution_Report(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := mocks.NewMockOrderRepository()
+	repo := mocks.NewMockOrderAttributionRepository(orderRepo)
+	svc := services.NewOrderAttributionService(repo)
+
+	now := time.Now()
+	placed := []struct {
+		id      string
+		channel services.OrderChannel
+		utm     services.UTM
+		status  orders.OrderStatus
+		total   int64
+		at      time.Time
+	}{
+		{"order-1", services.ChannelWeb, services.UTM{Source: "google", Medium: "cpc", Campaign: "spring-sale"}, orders.OrderStatusPaid, 5000, now},
+		{"order-2", services.ChannelWeb, services.UTM{Source: "google", Medium: "cpc", Campaign: "spring-sale"}, orders.OrderStatusDelivered, 3000, now},
+		{"order-3", services.ChannelWeb, services.UTM{}, orders.OrderStatusPaid, 1000, now},
+		{"order-4", services.ChannelMobileApp, services.UTM{Source: "push"}, orders.OrderStatusPaid, 2500, now},
+		{"order-5", services.ChannelPOS, services.UTM{}, orders.OrderStatusPaid, 12000, now},
+		// Canceled orders and orders outside the range don't count
+		{"order-6", services.ChannelWeb, services.UTM{Source: "google", Medium: "cpc", Campaign: "spring-sale"}, orders.OrderStatusCanceled, 9000, now},
+		{"order-7", services.ChannelMarketplace, services.UTM{}, orders.OrderStatusPaid, 4000, now.AddDate(0, 0, -10)},
+	}
+	for _, p := range placed {
+		orderRepo.Orders[p.id] = &orders.Order{ID: p.id, Status: p.status, Total: usd(p.total)}
+		repo.Attributions[p.id] = &services.OrderAttribution{OrderID: p.id, Channel: p.channel, UTM: p.utm, CreatedAt: p.at}
+	}
+
+	report, err := svc.Report(ctx, now.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantChannels := []struct {
+		channel services.OrderChannel
+		orders  int
+		revenue int64
+	}{
+		{services.ChannelPOS, 1, 12000},
+		{services.ChannelWeb, 3, 9000},
+		{services.ChannelMobileApp, 1, 2500},
+	}
+	if len(report.Channels) != len(wantChannels) {
+		t.Fatalf("expected %d channels, got %d", len(wantChannels), len(report.Channels))
+	}
+	for i, want := range wantChannels {
+		got := report.Channels[i]
+		if got.Channel != want.channel || got.Orders != want.orders || got.Revenue.Amount != want.revenue {
+			t.Errorf("channel %d: expected %s with %d orders and %d, got %s with %d orders and %d",
+				i, want.channel, want.orders, want.revenue, got.Channel, got.Orders, got.Revenue.Amount)
+		}
+	}
+
+	if len(report.Campaigns) != 2 {
+		t.Fatalf("expected 2 campaigns, got %d", len(report.Campaigns))
+	}
+	if c := report.Campaigns[0]; c.Source != "google" || c.Campaign != "spring-sale" || c.Orders != 2 || c.Revenue.Amount != 8000 {
+		t.Errorf("expected google spring-sale with 2 orders and 8000 first, got %+v", c)
+	}
+	if c := report.Campaigns[1]; c.Source != "push" || c.Orders != 1 || c.Revenue.Amount != 2500 {
+		t.Errorf("expected push with 1 order and 2500, got %+v", c)
+	}
+
+	if _, err := svc.Report(ctx, now, now.AddDate(0, 0, -1)); err != services.ErrInvalidReportRange {
+		t.Errorf("expected ErrInvalidReportRange for a range ending before it starts, got %v", err)
+	}
+	if _, err := svc.Report(ctx, now.AddDate(-1, 0, -1), now); err != services.ErrInvalidReportRange {
+		t.Errorf("expected ErrInvalidReportRange for a range over 366 days, got %v", err)
+	}
+}
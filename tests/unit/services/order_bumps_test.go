@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newOrderBumpService(t *testing.T) (*services.OrderBumpService, *mocks.MockOrderBumpRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	for _, product := range []*catalog.Product{fixtures.ProductLaptop, fixtures.ProductPhone, fixtures.ProductTShirt, fixtures.ProductInactive} {
+		productRepo.Products[product.ID] = product
+	}
+	repo := mocks.NewMockOrderBumpRepository()
+	return services.NewOrderBumpService(repo, productRepo), repo
+}
+
+func createOrderBump(t *testing.T, bumps *services.OrderBumpService, productID string, price int64, position int) *services.OrderBump {
+	t.Helper()
+	bump := &services.OrderBump{ProductID: productID, Headline: "Add one for less", Price: money.Money{Amount: price}, Position: position, IsActive: true}
+	if err := bumps.CreateBump(context.Background(), bump); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return bump
+}
+
+func laptopCart() *cart.Cart {
+	return &cart.Cart{ID: "cart-1", UserID: fixtures.TestUserID, Items: []cart.CartItem{
+		{ID: "item-1", ProductID: fixtures.ProductLaptop.ID, Name: fixtures.ProductLaptop.Name, Price: fixtures.ProductLaptop.BasePrice, Quantity: 1},
+	}}
+}
+
+func TestOrderBumps_Offers(t *testing.T) {
+	ctx := context.Background()
+	bumps, repo := newOrderBumpService(t)
+	tshirt := createOrderBump(t, bumps, fixtures.ProductTShirt.ID, 1500, 2)
+	phone := createOrderBump(t, bumps, fixtures.ProductPhone.ID, 59999, 1)
+	createOrderBump(t, bumps, fixtures.ProductLaptop.ID, 50000, 0)  // already in the cart
+	createOrderBump(t, bumps, fixtures.ProductInactive.ID, 1000, 0) // no longer for sale
+	paused := createOrderBump(t, bumps, fixtures.ProductTShirt.ID, 1000, 0)
+	paused.IsActive = false
+	repo.Bumps[paused.ID] = paused
+
+	if tshirt.Price.Currency != "USD" {
+		t.Errorf("expected the price in the product's currency, got %v", tshirt.Price)
+	}
+
+	offers, err := bumps.Offers(ctx, laptopCart())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(offers) != 2 || offers[0].ID != phone.ID || offers[1].ID != tshirt.ID {
+		t.Fatalf("expected the phone then the t-shirt offered, got %+v", offers)
+	}
+	if offers[1].Price.Amount != 1500 || offers[1].RegularPrice != fixtures.ProductTShirt.BasePrice || offers[1].Name != fixtures.ProductTShirt.Name {
+		t.Errorf("expected the t-shirt at its special and regular prices, got %+v", offers[1])
+	}
+
+	if offers, _ := bumps.Offers(ctx, &cart.Cart{ID: "empty"}); len(offers) != 0 {
+		t.Errorf("expected nothing offered for an empty cart, got %+v", offers)
+	}
+}
+
+func TestOrderBumps_AcceptedAtCheckout(t *testing.T) {
+	ctx := context.Background()
+	bumps, _ := newOrderBumpService(t)
+	bump := createOrderBump(t, bumps, fixtures.ProductTShirt.ID, 1500, 0)
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil)
+
+	stored := laptopCart()
+	withBump, err := bumps.Apply(ctx, stored, []string{bump.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.Items) != 1 {
+		t.Errorf("expected the stored cart left as it was, got %d items", len(stored.Items))
+	}
+
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: withBump, UserID: fixtures.TestUserID, ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order.Items) != 2 {
+		t.Fatalf("expected the laptop and the bump ordered, got %+v", order.Items)
+	}
+	item := order.Items[1]
+	if item.ProductID != fixtures.ProductTShirt.ID || item.Quantity != 1 || item.UnitPrice.Amount != 1500 || item.Attributes[services.OrderBumpAttribute] != bump.ID {
+		t.Errorf("expected one t-shirt at the bump's price, got %+v", item)
+	}
+	if order.Subtotal.Amount != fixtures.ProductLaptop.BasePrice.Amount+1500 {
+		t.Errorf("expected the bump in the subtotal, got %d", order.Subtotal.Amount)
+	}
+}
+
+func TestOrderBumps_Unavailable(t *testing.T) {
+	ctx := context.Background()
+	bumps, repo := newOrderBumpService(t)
+	bump := createOrderBump(t, bumps, fixtures.ProductTShirt.ID, 1500, 0)
+
+	if _, err := bumps.Apply(ctx, laptopCart(), []string{bump.ID, bump.ID}); !errors.Is(err, services.ErrInvalidOrderBump) {
+		t.Errorf("expected ErrInvalidOrderBump accepting a bump twice, got %v", err)
+	}
+	if _, err := bumps.Apply(ctx, laptopCart(), []string{"missing"}); !errors.Is(err, services.ErrOrderBumpUnavailable) {
+		t.Errorf("expected ErrOrderBumpUnavailable for an unknown bump, got %v", err)
+	}
+
+	withTShirt := laptopCart()
+	withTShirt.Items = append(withTShirt.Items, cart.CartItem{ID: "item-2", ProductID: fixtures.ProductTShirt.ID, Price: fixtures.ProductTShirt.BasePrice, Quantity: 1})
+	if _, err := bumps.Apply(ctx, withTShirt, []string{bump.ID}); !errors.Is(err, services.ErrOrderBumpUnavailable) {
+		t.Errorf("expected ErrOrderBumpUnavailable for a product already in the cart, got %v", err)
+	}
+
+	bump.IsActive = false
+	repo.Bumps[bump.ID] = bump
+	if _, err := bumps.Apply(ctx, laptopCart(), []string{bump.ID}); !errors.Is(err, services.ErrOrderBumpUnavailable) {
+		t.Errorf("expected ErrOrderBumpUnavailable for a paused bump, got %v", err)
+	}
+
+	invalid := []*services.OrderBump{
+		{ProductID: fixtures.ProductTShirt.ID, Headline: "  ", Price: money.Money{Amount: 100}},
+		{ProductID: fixtures.ProductTShirt.ID, Headline: "Add socks", Price: money.Money{Amount: 0}},
+	}
+	for _, candidate := range invalid {
+		if err := bumps.CreateBump(ctx, candidate); !errors.Is(err, services.ErrInvalidOrderBump) {
+			t.Errorf("expected ErrInvalidOrderBump for %+v, got %v", candidate, err)
+		}
+	}
+	if err := bumps.CreateBump(ctx, &services.OrderBump{ProductID: "missing", Headline: "Add socks", Price: money.Money{Amount: 100}}); !errors.Is(err, services.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+func TestOrderCancellation_RefundsPaidOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newRefundFixture(t)
+	svc := services.NewOrderCancellationService(f.orders, f.service)
+
+	// A partial refund was already issued; cancellation refunds the rest
+	amount := usd(3000)
+	if _, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{Amount: &amount}); err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+
+	cancellation, err := svc.Cancel(ctx, f.order.ID, services.CancelOrderRequest{Reason: " Ordered the wrong size ", CanceledBy: "user-1"})
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if cancellation.Order.Status != orders.OrderStatusCanceled || cancellation.Order.CanceledAt == nil {
+		t.Errorf("expected the order to be canceled, got %s", cancellation.Order.Status)
+	}
+	if cancellation.Refund == nil || cancellation.Refund.Amount != usd(7000) || cancellation.Refund.CreatedBy != "user-1" {
+		t.Fatalf("expected the remaining 7000 refunded, got %+v", cancellation.Refund)
+	}
+	if len(f.gateway.Refunds) != 3 {
+		t.Errorf("expected the partial refund and both tenders refunded at the gateway, got %+v", f.gateway.Refunds)
+	}
+
+	if _, err := svc.Cancel(ctx, f.order.ID, services.CancelOrderRequest{Reason: "again"}); !errors.Is(err, services.ErrOrderNotCancelable) {
+		t.Errorf("expected ErrOrderNotCancelable for a canceled order, got %v", err)
+	}
+}
+
+func TestOrderCancellation_StatusRules(t *testing.T) {
+	ctx := context.Background()
+	f := newRefundFixture(t)
+	svc := services.NewOrderCancellationService(f.orders, f.service)
+
+	if _, err := svc.Cancel(ctx, f.order.ID, services.CancelOrderRequest{Reason: "  "}); !errors.Is(err, services.ErrInvalidCancelReason) {
+		t.Errorf("expected ErrInvalidCancelReason, got %v", err)
+	}
+
+	// Customers can't cancel an order once it is being processed, but staff can
+	f.order.Status = orders.OrderStatusProcessing
+	if _, err := svc.Cancel(ctx, f.order.ID, services.CancelOrderRequest{Reason: "changed my mind"}); !errors.Is(err, services.ErrOrderNotCancelable) {
+		t.Errorf("expected ErrOrderNotCancelable for a customer, got %v", err)
+	}
+	if len(f.gateway.Refunds) != 0 {
+		t.Errorf("expected no refund for a refused cancellation, got %+v", f.gateway.Refunds)
+	}
+	cancellation, err := svc.Cancel(ctx, f.order.ID, services.CancelOrderRequest{Reason: "out of stock", Staff: true})
+	if err != nil {
+		t.Fatalf("staff Cancel: %v", err)
+	}
+	if cancellation.Refund == nil || cancellation.Refund.Amount != usd(10000) {
+		t.Errorf("expected a full refund, got %+v", cancellation.Refund)
+	}
+
+	// Nothing was paid for a pending order, so nothing is refunded
+	pending := newRefundFixture(t)
+	pending.order.Status = orders.OrderStatusPending
+	cancellation, err = services.NewOrderCancellationService(pending.orders, pending.service).
+		Cancel(ctx, pending.order.ID, services.CancelOrderRequest{Reason: "changed my mind"})
+	if err != nil || cancellation.Refund != nil {
+		t.Errorf("expected a pending order canceled without a refund, got %+v, %v", cancellation, err)
+	}
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type recordingConfirmations struct {
+	sent []string
+}
+
+func (r *recordingConfirmations) NotifyOrderConfirmation(ctx context.Context, email string, order *orders.Order, resend bool) error {
+	kind := "confirmation"
+	if resend {
+		kind = "resend"
+	}
+	r.sent = append(r.sent, kind+":"+order.OrderNumber+":"+email)
+	return nil
+}
+
+func customerEmails(ctx context.Context, userID string) (string, error) {
+	return userID + "@example.com", nil
+}
+
+func TestOrderConfirmation_SendAndResend(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository()
+	notifier := &recordingConfirmations{}
+	// No runner, so confirmations are sent inline
+	svc := services.NewOrderConfirmationService(repo, customerEmails, notifier, services.ConfirmationResendPolicy{
+		Cooldown:  0,
+		MaxResend: 2,
+		Window:    time.Hour,
+	})
+
+	order := exportOrder("o1", "user-1", time.Now())
+	repo.Orders[order.ID] = order
+	svc.Send(ctx, order)
+
+	if err := svc.Resend(ctx, order); err != nil {
+		t.Fatalf("first resend: %v", err)
+	}
+	if err := svc.Resend(ctx, order); err != nil {
+		t.Fatalf("second resend: %v", err)
+	}
+	var limited *services.ConfirmationResendLimitedError
+	if err := svc.Resend(ctx, order); !errors.As(err, &limited) || limited.RetryAt.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("expected the third resend in the window to be limited until it ends, got %v", err)
+	}
+
+	want := []string{"confirmation:ORD-o1:user-1@example.com", "resend:ORD-o1:user-1@example.com", "resend:ORD-o1:user-1@example.com"}
+	if len(notifier.sent) != len(want) {
+		t.Fatalf("expected %v, got %v", want, notifier.sent)
+	}
+	for i := range want {
+		if notifier.sent[i] != want[i] {
+			t.Errorf("email %d: expected %s, got %s", i, want[i], notifier.sent[i])
+		}
+	}
+
+	// Guest orders have no email address on file
+	guest := exportOrder("o2", "guest:abc", time.Now())
+	repo.Orders[guest.ID] = guest
+	svc.Send(ctx, guest)
+	if err := svc.Resend(ctx, guest); !errors.Is(err, services.ErrNoConfirmationEmail) {
+		t.Errorf("expected ErrNoConfirmationEmail for a guest order, got %v", err)
+	}
+	if len(notifier.sent) != 3 {
+		t.Errorf("expected no email for the guest order, got %v", notifier.sent)
+	}
+}
+
+func TestOrderConfirmation_ResendCooldown(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockOrderRepository()
+	svc := services.NewOrderConfirmationService(repo, customerEmails, &recordingConfirmations{}, services.ConfirmationResendPolicy{
+		Cooldown:  time.Minute,
+		MaxResend: 5,
+		Window:    time.Hour,
+	})
+	order := exportOrder("o1", "user-1", time.Now())
+	repo.Orders[order.ID] = order
+	other := exportOrder("o2", "user-1", time.Now())
+	repo.Orders[other.ID] = other
+
+	if err := svc.Resend(ctx, order); err != nil {
+		t.Fatalf("first resend: %v", err)
+	}
+	var limited *services.ConfirmationResendLimitedError
+	if err := svc.Resend(ctx, order); !errors.As(err, &limited) || limited.RetryAt.After(time.Now().Add(time.Minute)) {
+		t.Errorf("expected a resend within the cooldown to be limited for about a minute, got %v", err)
+	}
+	// The cooldown is per order
+	if err := svc.Resend(ctx, other); err != nil {
+		t.Errorf("expected another order's resend to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestOrderEvents_RecordsOrderProgress(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(5000)
+	order.Status = orders.OrderStatusPaid
+	orderRepo.Orders[order.ID] = order
+
+	eventRepo := mocks.NewMockOrderEventRepository()
+	events := services.NewOrderEventService(eventRepo)
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithEvents(events)
+	fulfillment := services.NewFulfillmentService(mocks.NewMockFulfillmentRepository(orderRepo), orderService).WithEvents(events)
+
+	if _, err := fulfillment.ConfirmShipment(ctx, order.ID, services.ShipmentRequest{Carrier: "UPS", TrackingNumber: "1Z999"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	timeline, err := events.Timeline(ctx, order.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(timeline) != 3 {
+		t.Fatalf("expected shipment, processing and shipped events, got %d", len(timeline))
+	}
+
+	shipment := timeline[0]
+	if shipment.Type != services.OrderEventShipmentCreated || shipment.Details["tracking_number"] != "1Z999" {
+		t.Errorf("expected the shipment event with its tracking number, got %+v", shipment)
+	}
+	for i, want := range []struct{ from, to orders.OrderStatus }{
+		{orders.OrderStatusPaid, orders.OrderStatusProcessing},
+		{orders.OrderStatusProcessing, orders.OrderStatusShipped},
+	} {
+		event := timeline[i+1]
+		if event.Type != services.OrderEventStatusChanged || event.FromStatus != want.from || event.Status != want.to {
+			t.Errorf("event %d: expected %s -> %s, got %s -> %s", i+1, want.from, want.to, event.FromStatus, event.Status)
+		}
+	}
+	if timeline[2].Message != "Order shipped" {
+		t.Errorf("expected a customer-facing message, got %q", timeline[2].Message)
+	}
+}
+
+func TestOrderEvents_CancelReason(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(5000)
+	orderRepo.Orders[order.ID] = order
+
+	eventRepo := mocks.NewMockOrderEventRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithEvents(services.NewOrderEventService(eventRepo))
+
+	if _, err := orderService.CancelOrder(ctx, order.ID, "payment not received"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// An invalid transition changes nothing and records nothing
+	if _, err := orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusShipped); err == nil {
+		t.Fatal("expected shipping a canceled order to fail")
+	}
+
+	if len(eventRepo.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(eventRepo.Events))
+	}
+	event := eventRepo.Events[0]
+	if event.Status != orders.OrderStatusCanceled || event.Details["reason"] != "payment not received" {
+		t.Errorf("expected a cancellation with its reason, got %+v", event)
+	}
+}
+
+func TestOrderEvents_InternalNotes(t *testing.T) {
+	ctx := context.Background()
+	events := services.NewOrderEventService(mocks.NewMockOrderEventRepository())
+
+	if _, err := events.AddNote(ctx, "order-1", "Your parcel was delayed by weather", false, "agent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := events.AddNote(ctx, "order-1", "Customer called twice, offer a discount", true, "agent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := events.AddNote(ctx, "order-1", "   ", false, "agent-1"); err != services.ErrInvalidOrderNote {
+		t.Errorf("expected ErrInvalidOrderNote for a blank note, got %v", err)
+	}
+
+	customer, _ := events.Timeline(ctx, "order-1", false)
+	if len(customer) != 1 || customer[0].Internal || customer[0].CreatedBy != "" {
+		t.Fatalf("expected the customer to see one note without its author, got %+v", customer)
+	}
+
+	staff, _ := events.Timeline(ctx, "order-1", true)
+	if len(staff) != 2 || staff[0].CreatedBy != "agent-1" {
+		t.Errorf("expected staff to see both notes with their author, got %+v", staff)
+	}
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newOrderExportService(t *testing.T, repo *mocks.MockOrderRepository) *services.OrderExportService {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := jobs.NewRunner(1, 10)
+	runner.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		runner.Wait()
+	})
+	return services.NewOrderExportService(repo, runner, t.TempDir(), []byte("export-secret")).
+		WithSyncLimit(3).
+		WithBatchSize(2)
+}
+
+func exportOrder(id, userID string, placed time.Time, items ...orders.OrderItem) *orders.Order {
+	return &orders.Order{
+		ID:          id,
+		OrderNumber: "ORD-" + id,
+		UserID:      userID,
+		Status:      orders.OrderStatusPaid,
+		Items:       items,
+		Subtotal:    money.Money{Amount: 2500, Currency: "USD"},
+		Total:       money.Money{Amount: 2750, Currency: "USD"},
+		CreatedAt:   placed,
+	}
+}
+
+func TestOrderExport_Sync(t *testing.T) {
+	repo := mocks.NewMockOrderRepository()
+	svc := newOrderExportService(t, repo)
+	day := time.Date(2026, 10, 1, 15, 0, 0, 0, time.Local)
+	variant := "var-1"
+
+	repo.Orders["o1"] = exportOrder("o1", "user-1", day,
+		orders.OrderItem{ProductID: "prod-1", VariantID: &variant, SKU: "TEE-M", Name: "=Tee", UnitPrice: money.Money{Amount: 1250, Currency: "USD"}, Quantity: 2, Total: money.Money{Amount: 2500, Currency: "USD"}},
+		orders.OrderItem{ProductID: "prod-2", SKU: "CAP", Name: "Cap", Quantity: 1},
+	)
+	repo.Orders["o2"] = exportOrder("o2", "user-1", day.AddDate(0, 0, -1))
+	repo.Orders["old"] = exportOrder("old", "user-1", day.AddDate(0, -2, 0))
+	repo.Orders["other"] = exportOrder("other", "user-2", day)
+
+	data, export, err := svc.Export(context.Background(), "user-1", day.AddDate(0, 0, -1), day, "")
+	if err != nil || export != nil {
+		t.Fatalf("expected a CSV within the request, got %+v, %v", export, err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+
+	// A header, two item rows for o1 and one empty-item row for o2, newest first; the other
+	// user's and out-of-range orders are left out
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d: %v", len(rows), rows)
+	}
+	if rows[1][0] != "ORD-o1" || rows[1][8] != "27.50" || rows[1][11] != "var-1" || rows[1][12] != "'=Tee" || rows[1][14] != "12.50" {
+		t.Errorf("unexpected first item row: %v", rows[1])
+	}
+	if rows[3][0] != "ORD-o2" || rows[3][9] != "" {
+		t.Errorf("expected o2 without items last, got %v", rows[3])
+	}
+
+	if _, _, err := svc.Export(context.Background(), "user-1", day, day.AddDate(0, 0, -1), ""); !errors.Is(err, services.ErrInvalidExportRange) {
+		t.Errorf("expected ErrInvalidExportRange, got %v", err)
+	}
+	if _, _, err := svc.Export(context.Background(), "user-1", day, day, "xlsx"); !errors.Is(err, services.ErrUnsupportedExportFormat) {
+		t.Errorf("expected ErrUnsupportedExportFormat, got %v", err)
+	}
+}
+
+func TestOrderExport_Async(t *testing.T) {
+	repo := mocks.NewMockOrderRepository()
+	svc := newOrderExportService(t, repo)
+	day := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("o%d", i)
+		repo.Orders[id] = exportOrder(id, "user-1", day.Add(-time.Duration(i)*time.Hour), orders.OrderItem{SKU: "SKU-" + id, Quantity: 1})
+	}
+
+	// Five orders are over the sync limit of three, so the export runs in the background
+	data, export, err := svc.Export(context.Background(), "user-1", day, day, "csv")
+	if err != nil || data != nil || export == nil {
+		t.Fatalf("expected a background export, got %d bytes, %+v, %v", len(data), export, err)
+	}
+	if _, _, err := svc.Export(context.Background(), "user-1", day, day, ""); err != nil && !errors.Is(err, services.ErrOrderExportInProgress) {
+		t.Errorf("expected a second export to start or be refused as in progress, got %v", err)
+	}
+
+	var done *services.OrderExport
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		done, err = svc.GetExport(context.Background(), "user-1", export.ID)
+		if err != nil {
+			t.Fatalf("GetExport: %v", err)
+		}
+		if done.FinishedAt != nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if done.Status != services.BulkJobCompleted || done.Orders != 5 || done.Rows != 5 || done.DownloadURL == "" {
+		t.Fatalf("expected a completed export of 5 orders with a link, got %+v", done)
+	}
+
+	// Another customer can't see it
+	if _, err := svc.GetExport(context.Background(), "user-2", export.ID); !errors.Is(err, services.ErrOrderExportNotFound) {
+		t.Errorf("expected ErrOrderExportNotFound for another user, got %v", err)
+	}
+
+	link, err := url.Parse(done.DownloadURL)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	query := link.Query()
+	path, filename, err := svc.Open(context.Background(), export.ID, query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if filename != "orders-2026-10-01-to-2026-10-01.csv" {
+		t.Errorf("unexpected filename %q", filename)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if rows, _ := csv.NewReader(strings.NewReader(string(contents))).ReadAll(); len(rows) != 6 || rows[1][9] != "SKU-o0" {
+		t.Errorf("expected a header and 5 rows, newest first, got %v", rows)
+	}
+
+	// A tampered signature or a past expiry is refused
+	if _, _, err := svc.Open(context.Background(), export.ID, query.Get("expires"), "bad"); !errors.Is(err, services.ErrInvalidExportLink) {
+		t.Errorf("expected ErrInvalidExportLink for a bad signature, got %v", err)
+	}
+	if _, _, err := svc.Open(context.Background(), export.ID, "1", query.Get("signature")); !errors.Is(err, services.ErrInvalidExportLink) {
+		t.Errorf("expected ErrInvalidExportLink for an expired link, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/pricing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// reconciledOrder returns an order whose totals add up: two items at 10.00 and one at
+// 5.00, 2.00 off the first line, 2.05 tax and 5.00 shipping
+func reconciledOrder() *orders.Order {
+	usd := func(amount int64) money.Money { return money.Money{Amount: amount, Currency: "USD"} }
+	return &orders.Order{
+		ID:     "order-1",
+		Status: orders.OrderStatusPending,
+		Items: []orders.OrderItem{
+			{ID: "item-1", UnitPrice: usd(1000), Quantity: 2, DiscountAmount: usd(200), TaxAmount: usd(160), Total: usd(1960)},
+			{ID: "item-2", UnitPrice: usd(500), Quantity: 1, DiscountAmount: usd(0), TaxAmount: usd(45), Total: usd(545)},
+		},
+		Subtotal:      usd(2500),
+		DiscountTotal: usd(200),
+		TaxTotal:      usd(205),
+		ShippingTotal: usd(500),
+		Total:         usd(3005),
+	}
+}
+
+func TestReconcileOrderTotals(t *testing.T) {
+	if mismatches := services.ReconcileOrderTotals(reconciledOrder()); len(mismatches) != 0 {
+		t.Fatalf("Expected a consistent order to reconcile, got %+v", mismatches)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(o *orders.Order)
+		field   string
+		itemID  string
+	}{
+		{"item total", func(o *orders.Order) { o.Items[0].Total.Amount = 2000 }, "item_total", "item-1"},
+		{"subtotal", func(o *orders.Order) { o.Subtotal.Amount = 2400; o.Total.Amount = 2905 }, "subtotal", ""},
+		{"discount total", func(o *orders.Order) { o.DiscountTotal.Amount = 0; o.Total.Amount = 3205 }, "discount_total", ""},
+		{"order total", func(o *orders.Order) { o.Total.Amount = 1 }, "total", ""},
+		{"negative tax", func(o *orders.Order) { o.TaxTotal.Amount = -205 }, "tax_total", ""},
+		{"mixed currency", func(o *orders.Order) { o.Items[1].UnitPrice.Currency = "EUR" }, "currency", "item-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := reconciledOrder()
+			tt.corrupt(order)
+
+			mismatches := services.ReconcileOrderTotals(order)
+			if len(mismatches) == 0 {
+				t.Fatal("Expected a mismatch")
+			}
+			if mismatches[0].Field != tt.field || mismatches[0].ItemID != tt.itemID {
+				t.Errorf("Expected %s %s first, got %+v", tt.field, tt.itemID, mismatches)
+			}
+		})
+	}
+}
+
+func TestReconcilingOrderRepository_Reject(t *testing.T) {
+	repo := mocks.NewMockOrderRepository()
+	reconciled := services.NewReconcilingOrderRepository(repo, services.ReconcileReject)
+
+	if err := reconciled.Save(context.Background(), reconciledOrder()); err != nil {
+		t.Fatalf("Expected a consistent order to save, got %v", err)
+	}
+
+	order := reconciledOrder()
+	order.ID = "order-2"
+	order.Total.Amount = 100
+	var totalsErr *services.OrderTotalsError
+	if err := reconciled.Save(context.Background(), order); !errors.As(err, &totalsErr) {
+		t.Fatalf("Expected OrderTotalsError, got %v", err)
+	}
+	if totalsErr.Mismatches[0].Expected.Amount != 3005 || totalsErr.Mismatches[0].Actual.Amount != 100 {
+		t.Errorf("Expected total 3005 against 100, got %+v", totalsErr.Mismatches[0])
+	}
+	if _, ok := repo.Orders["order-2"]; ok {
+		t.Error("Expected the rejected order not to be stored")
+	}
+
+	// An order stored before the check can still change status
+	repo.Orders["order-2"] = reconciledOrder()
+	order.Status = orders.OrderStatusCanceled
+	if err := reconciled.Save(context.Background(), order); err != nil {
+		t.Errorf("Expected a stored order to be flagged rather than rejected, got %v", err)
+	}
+}
+
+func TestReconcilingOrderRepository_Flag(t *testing.T) {
+	repo := mocks.NewMockOrderRepository()
+	eventRepo := mocks.NewMockOrderEventRepository()
+	reconciled := services.NewReconcilingOrderRepository(repo, services.ReconcileFlag).
+		WithEvents(services.NewOrderEventService(eventRepo))
+
+	order := reconciledOrder()
+	order.Total.Amount = 100
+	if err := reconciled.Save(context.Background(), order); err != nil {
+		t.Fatalf("Expected a flagged order to save, got %v", err)
+	}
+	if _, ok := repo.Orders["order-1"]; !ok {
+		t.Error("Expected the flagged order to be stored")
+	}
+	if len(eventRepo.Events) != 1 || !eventRepo.Events[0].Internal {
+		t.Fatalf("Expected one internal note, got %+v", eventRepo.Events)
+	}
+
+	// Later saves, such as status changes, are not noted again
+	order.Status = orders.OrderStatusPaid
+	if err := reconciled.Save(context.Background(), order); err != nil {
+		t.Fatalf("Expected a flagged order to save, got %v", err)
+	}
+	if len(eventRepo.Events) != 1 {
+		t.Errorf("Expected the mismatch to be noted once, got %d notes", len(eventRepo.Events))
+	}
+}
+
+func TestReconcilingOrderRepository_PricedOrdersReconcile(t *testing.T) {
+	ctx := context.Background()
+	promotions := mocks.NewMockPromotionRepository()
+	promotions.Promotions = append(promotions.Promotions, &pricing.Promotion{
+		ID: "promo-1", Code: "TENOFF", DiscountType: pricing.DiscountTypePercentage, Value: 0.10,
+		ValidFrom: time.Now().Add(-time.Hour), ValidTo: time.Now().Add(time.Hour), IsActive: true,
+	})
+	pricingService := services.NewPricingService(promotions, services.NewSimpleTaxCalculator(0.0875), nil)
+	repo := mocks.NewMockOrderRepository()
+	orderService := services.NewOrderService(services.NewReconcilingOrderRepository(repo, services.ReconcileReject), pricingService.Service, nil, nil)
+
+	c := &cart.Cart{ID: "cart-1", UserID: fixtures.TestUserID}
+	c.AddItem(cart.CartItem{ID: "item-1", ProductID: "prod-1", SKU: "SKU-1", Price: money.Money{Amount: 1999, Currency: "USD"}, Quantity: 3})
+	c.AddItem(cart.CartItem{ID: "item-2", ProductID: "prod-2", SKU: "SKU-2", Price: money.Money{Amount: 755, Currency: "USD"}, Quantity: 1})
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{
+		Cart:            c,
+		UserID:          fixtures.TestUserID,
+		PromotionCodes:  []string{"TENOFF"},
+		ShippingAddress: orders.Address{FirstName: "Jane", LastName: "Doe", AddressLine1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"},
+	})
+	if err != nil {
+		t.Fatalf("Expected a priced order to reconcile, got %v", err)
+	}
+	if order.DiscountTotal.IsZero() || order.Items[0].Total.IsZero() {
+		t.Errorf("Expected the order to carry a discount and item totals, got %+v", order)
+	}
+}
+
+func TestParseReconciliationMode(t *testing.T) {
+	if mode, err := services.ParseReconciliationMode(""); err != nil || mode != services.ReconcileReject {
+		t.Errorf("Expected reject by default, got %q (%v)", mode, err)
+	}
+	if _, err := services.ParseReconciliationMode("warn"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
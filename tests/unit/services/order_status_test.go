@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newOrderStatusService(t *testing.T, status orders.OrderStatus) (*services.OrderStatusService, *mocks.MockOrderEventRepository, *mocks.MockOrderStatusEventRepository, *orders.Order) {
+	t.Helper()
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(5000)
+	order.Status = status
+	orderRepo.Orders[order.ID] = order
+
+	eventRepo := mocks.NewMockOrderEventRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithEvents(services.NewOrderEventService(eventRepo))
+	cancellations := services.NewOrderCancellationService(orderService, nil)
+	historyRepo := mocks.NewMockOrderStatusEventRepository()
+	return services.NewOrderStatusService(orderService, cancellations, historyRepo), eventRepo, historyRepo, order
+}
+
+func TestOrderStatus_FollowsWorkflow(t *testing.T) {
+	ctx := context.Background()
+	svc, eventRepo, historyRepo, order := newOrderStatusService(t, orders.OrderStatusPaid)
+
+	for _, status := range []orders.OrderStatus{orders.OrderStatusProcessing, orders.OrderStatusShipped, orders.OrderStatusDelivered} {
+		updated, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: status, ChangedBy: "admin-1"})
+		if err != nil {
+			t.Fatalf("moving to %s: %v", status, err)
+		}
+		if updated.Status != status {
+			t.Errorf("expected %s, got %s", status, updated.Status)
+		}
+	}
+
+	if len(eventRepo.Events) != 3 {
+		t.Fatalf("expected a timeline event per change, got %d", len(eventRepo.Events))
+	}
+	for _, event := range eventRepo.Events {
+		if event.Type != services.OrderEventStatusChanged || event.CreatedBy != "admin-1" {
+			t.Errorf("expected a status change recorded as made by admin-1, got %+v", event)
+		}
+	}
+	if first := eventRepo.Events[0]; first.FromStatus != orders.OrderStatusPaid || first.Status != orders.OrderStatusProcessing {
+		t.Errorf("expected paid -> processing first, got %s -> %s", first.FromStatus, first.Status)
+	}
+
+	history, err := svc.History(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 3 || len(historyRepo.Events) != 3 {
+		t.Fatalf("expected a status history entry per change, got %d", len(history))
+	}
+	if last := history[2]; last.FromStatus != orders.OrderStatusShipped || last.ToStatus != orders.OrderStatusDelivered || last.ChangedBy != "admin-1" {
+		t.Errorf("expected shipped -> delivered by admin-1 last, got %+v", last)
+	}
+	if _, err := svc.History(ctx, "missing"); !errors.Is(err, orders.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound for a missing order, got %v", err)
+	}
+}
+
+func TestOrderStatus_ProcessesPendingOrders(t *testing.T) {
+	ctx := context.Background()
+	svc, eventRepo, historyRepo, order := newOrderStatusService(t, orders.OrderStatusPending)
+
+	processing, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: orders.OrderStatusProcessing, ChangedBy: "admin-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if processing.Status != orders.OrderStatusProcessing {
+		t.Errorf("expected the pending order processing, got %s", processing.Status)
+	}
+	if len(eventRepo.Events) != 1 || eventRepo.Events[0].FromStatus != orders.OrderStatusPending {
+		t.Errorf("expected pending -> processing on the timeline, got %+v", eventRepo.Events)
+	}
+	if len(historyRepo.Events) != 1 || historyRepo.Events[0].ToStatus != orders.OrderStatusProcessing {
+		t.Errorf("expected pending -> processing in the status history, got %+v", historyRepo.Events)
+	}
+
+	if _, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: orders.OrderStatusShipped, ChangedBy: "admin-1"}); err != nil {
+		t.Fatalf("expected the order to ship once processing, got %v", err)
+	}
+}
+
+func TestOrderStatus_RejectsIllegalTransitions(t *testing.T) {
+	ctx := context.Background()
+	svc, eventRepo, historyRepo, order := newOrderStatusService(t, orders.OrderStatusPaid)
+
+	_, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: orders.OrderStatusShipped, ChangedBy: "admin-1"})
+	var transitionErr *services.OrderStatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected an OrderStatusTransitionError, got %v", err)
+	}
+	if transitionErr.From != orders.OrderStatusPaid || transitionErr.To != orders.OrderStatusShipped || len(transitionErr.Allowed) != 2 {
+		t.Errorf("expected paid -> shipped refused with processing and canceled allowed, got %+v", transitionErr)
+	}
+	if order.Status != orders.OrderStatusPaid || len(eventRepo.Events) != 0 || len(historyRepo.Events) != 0 {
+		t.Errorf("expected nothing changed or recorded, got %s with %d events", order.Status, len(eventRepo.Events))
+	}
+
+	// Payment and refunds set paid and refunded, not staff
+	for _, status := range []orders.OrderStatus{orders.OrderStatusPending, orders.OrderStatusRefunded} {
+		if _, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: status}); !errors.As(err, &transitionErr) {
+			t.Errorf("expected moving to %s refused, got %v", status, err)
+		}
+	}
+	if allowed := services.AllowedStatuses(orders.OrderStatusDelivered); len(allowed) != 0 {
+		t.Errorf("expected nothing allowed from delivered, got %v", allowed)
+	}
+}
+
+func TestOrderStatus_CancelNeedsReason(t *testing.T) {
+	ctx := context.Background()
+	svc, eventRepo, historyRepo, order := newOrderStatusService(t, orders.OrderStatusPending)
+
+	if _, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: orders.OrderStatusCanceled, ChangedBy: "admin-1"}); !errors.Is(err, services.ErrInvalidCancelReason) {
+		t.Errorf("expected ErrInvalidCancelReason, got %v", err)
+	}
+	canceled, err := svc.ChangeStatus(ctx, order.ID, services.OrderStatusChange{Status: orders.OrderStatusCanceled, Reason: "duplicate order", ChangedBy: "admin-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if canceled.Status != orders.OrderStatusCanceled {
+		t.Errorf("expected the order canceled, got %s", canceled.Status)
+	}
+	if len(eventRepo.Events) != 1 || eventRepo.Events[0].Details["reason"] != "duplicate order" || eventRepo.Events[0].CreatedBy != "admin-1" {
+		t.Errorf("expected the cancellation recorded with its reason and actor, got %+v", eventRepo.Events)
+	}
+	if len(historyRepo.Events) != 1 || historyRepo.Events[0].Reason != "duplicate order" || historyRepo.Events[0].ToStatus != orders.OrderStatusCanceled {
+		t.Errorf("expected the cancellation in the status history with its reason, got %+v", historyRepo.Events)
+	}
+}
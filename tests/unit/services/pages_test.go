@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newPageService() (*services.PageService, *mocks.MockPageRepository) {
+	repo := mocks.NewMockPageRepository()
+	return services.NewPageService(repo), repo
+}
+
+func TestPage_SlugNormalization(t *testing.T) {
+	service, repo := newPageService()
+	ctx := context.Background()
+
+	page := &services.Page{Slug: " Shipping-Policy ", Title: "  Shipping Policy ", Body: "We ship worldwide."}
+	if err := service.SavePage(ctx, page); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	if page.Slug != "shipping-policy" {
+		t.Errorf("Expected slug shipping-policy, got %q", page.Slug)
+	}
+	if page.Title != "Shipping Policy" {
+		t.Errorf("Expected trimmed title, got %q", page.Title)
+	}
+	if page.Format != services.PageFormatMarkdown || page.Status != services.PageStatusDraft {
+		t.Errorf("Expected markdown draft by default, got %s %s", page.Format, page.Status)
+	}
+	if page.ID == "" || repo.Pages[page.ID] == nil {
+		t.Fatal("Expected the page to be saved with an ID")
+	}
+	if page.PublishedAt != nil {
+		t.Error("Expected drafts to have no published time")
+	}
+}
+
+func TestPage_Validation(t *testing.T) {
+	service, repo := newPageService()
+	ctx := context.Background()
+
+	invalid := []*services.Page{
+		{Slug: "", Title: "Empty slug"},
+		{Slug: "two words", Title: "Spaces"},
+		{Slug: "trailing-", Title: "Trailing hyphen"},
+		{Slug: "double--hyphen", Title: "Double hyphen"},
+		{Slug: "faq/shipping", Title: "Slash"},
+		{Slug: "faq", Title: "   "},
+		{Slug: "faq", Title: "FAQ", Format: "pdf"},
+		{Slug: "faq", Title: "FAQ", Status: "archived"},
+	}
+	for _, page := range invalid {
+		if err := service.SavePage(ctx, page); err != services.ErrInvalidPage {
+			t.Errorf("Expected ErrInvalidPage for slug %q title %q, got %v", page.Slug, page.Title, err)
+		}
+	}
+	if len(repo.Pages) != 0 {
+		t.Errorf("Expected invalid pages not to be saved, got %d", len(repo.Pages))
+	}
+}
+
+func TestPage_DuplicateSlug(t *testing.T) {
+	service, _ := newPageService()
+	ctx := context.Background()
+
+	faq := &services.Page{Slug: "faq", Title: "FAQ", Format: services.PageFormatHTML}
+	if err := service.SavePage(ctx, faq); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+
+	duplicate := &services.Page{Slug: "FAQ", Title: "Questions"}
+	if err := service.SavePage(ctx, duplicate); err != services.ErrPageSlugTaken {
+		t.Errorf("Expected ErrPageSlugTaken, got %v", err)
+	}
+
+	faq.Title = "Frequently Asked Questions"
+	if err := service.SavePage(ctx, faq); err != nil {
+		t.Errorf("Expected a page to keep its own slug, got %v", err)
+	}
+}
+
+func TestPage_DraftsAreHidden(t *testing.T) {
+	service, _ := newPageService()
+	ctx := context.Background()
+
+	about := &services.Page{Slug: "about", Title: "About us", Body: "Hello"}
+	if err := service.SavePage(ctx, about); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	if _, err := service.GetPublishedPage(ctx, "about"); err != services.ErrPageNotFound {
+		t.Errorf("Expected drafts to be hidden, got %v", err)
+	}
+	if _, err := service.GetPublishedPage(ctx, "missing"); err != services.ErrPageNotFound {
+		t.Errorf("Expected ErrPageNotFound for an unknown slug, got %v", err)
+	}
+
+	about.Status = services.PageStatusPublished
+	if err := service.SavePage(ctx, about); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	page, err := service.GetPublishedPage(ctx, "About")
+	if err != nil {
+		t.Fatalf("GetPublishedPage failed: %v", err)
+	}
+	if page.ID != about.ID {
+		t.Errorf("Expected page %s, got %s", about.ID, page.ID)
+	}
+}
+
+func TestPage_KeepsFirstPublishedAt(t *testing.T) {
+	service, _ := newPageService()
+	ctx := context.Background()
+
+	terms := &services.Page{Slug: "terms", Title: "Terms", Status: services.PageStatusPublished}
+	if err := service.SavePage(ctx, terms); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	if terms.PublishedAt == nil {
+		t.Fatal("Expected a published time when publishing")
+	}
+	publishedAt := *terms.PublishedAt
+
+	terms.Status = services.PageStatusDraft
+	if err := service.SavePage(ctx, terms); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	terms.Status = services.PageStatusPublished
+	terms.Body = "Updated terms"
+	if err := service.SavePage(ctx, terms); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+	if err := service.SavePage(ctx, terms); err != nil {
+		t.Fatalf("SavePage failed: %v", err)
+	}
+
+	if terms.PublishedAt == nil || !terms.PublishedAt.Equal(publishedAt) {
+		t.Errorf("Expected published time %v to be kept, got %v", publishedAt, terms.PublishedAt)
+	}
+	if terms.UpdatedAt.Before(publishedAt) {
+		t.Errorf("Expected updated time to move forward, got %v", terms.UpdatedAt)
+	}
+}
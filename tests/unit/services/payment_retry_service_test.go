@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type retryFixture struct {
+	service   *services.PaymentRetryService
+	retries   *mocks.MockPaymentRetryRepository
+	orderRepo *mocks.MockOrderRepository
+	gateway   *mocks.MockPaymentGateway
+	order     *orders.Order
+}
+
+func newRetryFixture(policy services.PaymentRetryPolicy) *retryFixture {
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(10000)
+	orderRepo.Orders[order.ID] = order
+
+	gateway := mocks.NewMockPaymentGateway()
+	retries := mocks.NewMockPaymentRetryRepository()
+	paymentService := services.NewPaymentService(mocks.NewMockPaymentRepository(), gateway)
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+
+	return &retryFixture{
+		service:   services.NewPaymentRetryService(retries, paymentService, orderService, policy),
+		retries:   retries,
+		orderRepo: orderRepo,
+		gateway:   gateway,
+		order:     order,
+	}
+}
+
+func TestPaymentRetryService_RecordFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 2, Window: time.Hour})
+
+	retry, err := f.service.RecordFailure(ctx, f.order.ID, "card declined")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retry.State != services.PaymentRetryStatePending || f.service.AttemptsRemaining(retry) != 1 {
+		t.Errorf("expected payment_pending with 1 attempt remaining, got %s with %d", retry.State, f.service.AttemptsRemaining(retry))
+	}
+	if f.order.Status != orders.OrderStatusPending {
+		t.Errorf("expected order to stay pending, got %s", f.order.Status)
+	}
+
+	if _, err := f.service.RecordFailure(ctx, f.order.ID, "card declined"); err != services.ErrPaymentRetriesExhausted {
+		t.Fatalf("expected ErrPaymentRetriesExhausted, got %v", err)
+	}
+	if f.order.Status != orders.OrderStatusCanceled {
+		t.Errorf("expected order to be canceled, got %s", f.order.Status)
+	}
+	if f.retries.Retries[f.order.ID].State != services.PaymentRetryStateCanceled {
+		t.Errorf("expected retry to be canceled, got %s", f.retries.Retries[f.order.ID].State)
+	}
+}
+
+func TestPaymentRetryService_RetryPayment(t *testing.T) {
+	ctx := context.Background()
+	card := services.TenderRequest{Type: services.TenderTypeCard, PaymentMethodID: "pm_new"}
+
+	t.Run("successful retry marks the order paid", func(t *testing.T) {
+		f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+		if _, err := f.service.RecordFailure(ctx, f.order.ID, "card declined"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tender, retry, err := f.service.RetryPayment(ctx, f.order, card)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tender.Amount != usd(10000) || tender.Status != services.TenderStatusCaptured {
+			t.Errorf("expected captured tender for the full balance, got %s for %d", tender.Status, tender.Amount.Amount)
+		}
+		if retry.State != services.PaymentRetryStateRecovered {
+			t.Errorf("expected recovered retry, got %s", retry.State)
+		}
+		if f.order.Status != orders.OrderStatusPaid {
+			t.Errorf("expected order to be paid, got %s", f.order.Status)
+		}
+	})
+
+	t.Run("declined retry counts against the policy", func(t *testing.T) {
+		f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+		f.gateway.DeclinedMethods["pm_new"] = true
+		if _, err := f.service.RecordFailure(ctx, f.order.ID, "card declined"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, retry, err := f.service.RetryPayment(ctx, f.order, card)
+		if err != services.ErrTenderDeclined {
+			t.Fatalf("expected ErrTenderDeclined, got %v", err)
+		}
+		if retry.Failures != 2 {
+			t.Errorf("expected 2 failures, got %d", retry.Failures)
+		}
+	})
+
+	t.Run("retry after the window cancels the order", func(t *testing.T) {
+		f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+		if _, err := f.service.RecordFailure(ctx, f.order.ID, "card declined"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.retries.Retries[f.order.ID].ExpiresAt = time.Now().Add(-time.Minute)
+
+		if _, _, err := f.service.RetryPayment(ctx, f.order, card); err != services.ErrPaymentRetriesExhausted {
+			t.Fatalf("expected ErrPaymentRetriesExhausted, got %v", err)
+		}
+		if f.order.Status != orders.OrderStatusCanceled {
+			t.Errorf("expected order to be canceled, got %s", f.order.Status)
+		}
+	})
+
+	t.Run("orders without a failed payment cannot be retried", func(t *testing.T) {
+		f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+		if _, _, err := f.service.RetryPayment(ctx, f.order, card); err != services.ErrPaymentNotPending {
+			t.Fatalf("expected ErrPaymentNotPending, got %v", err)
+		}
+	})
+}
+
+func TestPaymentRetryService_CancelExpired(t *testing.T) {
+	ctx := context.Background()
+	f := newRetryFixture(services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+	if _, err := f.service.RecordFailure(ctx, f.order.ID, "card declined"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	canceled, err := f.service.CancelExpired(ctx)
+	if err != nil || canceled != 0 {
+		t.Fatalf("expected nothing to cancel inside the window, got %d (%v)", canceled, err)
+	}
+
+	f.retries.Retries[f.order.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	canceled, err = f.service.CancelExpired(ctx)
+	if err != nil || canceled != 1 {
+		t.Fatalf("expected 1 canceled order, got %d (%v)", canceled, err)
+	}
+	if f.order.Status != orders.OrderStatusCanceled {
+		t.Errorf("expected order to be canceled, got %s", f.order.Status)
+	}
+}
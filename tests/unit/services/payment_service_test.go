@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func usd(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: "USD"}
+}
+
+func newTestOrder(total int64) *orders.Order {
+	return &orders.Order{
+		ID:          "order-1",
+		OrderNumber: "ORD-1",
+		Status:      orders.OrderStatusPending,
+		Total:       usd(total),
+	}
+}
+
+func TestPaymentService_SplitTenders(t *testing.T) {
+	service := services.NewPaymentService(mocks.NewMockPaymentRepository(), nil)
+	order := newTestOrder(10000)
+
+	tests := []struct {
+		name     string
+		requests []services.TenderRequest
+		wantErr  error
+	}{
+		{
+			name: "gift card and card cover the total",
+			requests: []services.TenderRequest{
+				{Type: services.TenderTypeGiftCard, PaymentMethodID: "gc_1", Amount: usd(2500)},
+				{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(7500)},
+			},
+		},
+		{
+			name: "tenders short of the total",
+			requests: []services.TenderRequest{
+				{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(5000)},
+				{Type: services.TenderTypeCard, PaymentMethodID: "pm_2", Amount: usd(4000)},
+			},
+			wantErr: services.ErrTenderTotalMismatch,
+		},
+		{
+			name: "currency mismatch",
+			requests: []services.TenderRequest{
+				{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: money.Money{Amount: 10000, Currency: "EUR"}},
+			},
+			wantErr: services.ErrInvalidTender,
+		},
+		{
+			name: "unknown tender type",
+			requests: []services.TenderRequest{
+				{Type: "crypto", PaymentMethodID: "pm_1", Amount: usd(10000)},
+			},
+			wantErr: services.ErrInvalidTender,
+		},
+		{
+			name:    "no tenders",
+			wantErr: services.ErrInvalidTender,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenders, err := service.SplitTenders(order, tt.requests)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && len(tenders) != len(tt.requests) {
+				t.Errorf("expected %d tenders, got %d", len(tt.requests), len(tenders))
+			}
+		})
+	}
+}
+
+func TestPaymentService_ChargeTenders(t *testing.T) {
+	ctx := context.Background()
+	split := []services.TenderRequest{
+		{Type: services.TenderTypeGiftCard, PaymentMethodID: "gc_1", Amount: usd(2500)},
+		{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(7500)},
+	}
+
+	t.Run("captures every tender and reconciles the total", func(t *testing.T) {
+		repo := mocks.NewMockPaymentRepository()
+		service := services.NewPaymentService(repo, mocks.NewMockPaymentGateway())
+		order := newTestOrder(10000)
+
+		if _, err := service.ChargeTenders(ctx, order, split); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		summary, err := service.Summary(ctx, order)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Captured != usd(10000) || !summary.IsFullyCaptured() {
+			t.Errorf("expected full capture, got captured %d balance %d", summary.Captured.Amount, summary.Balance.Amount)
+		}
+	})
+
+	t.Run("refunds captured tenders when a later tender is declined", func(t *testing.T) {
+		repo := mocks.NewMockPaymentRepository()
+		gateway := mocks.NewMockPaymentGateway()
+		gateway.DeclinedMethods["pm_1"] = true
+		service := services.NewPaymentService(repo, gateway)
+		order := newTestOrder(10000)
+
+		tenders, err := service.ChargeTenders(ctx, order, split)
+		if err != services.ErrTenderDeclined {
+			t.Fatalf("expected ErrTenderDeclined, got %v", err)
+		}
+		if tenders[0].Status != services.TenderStatusCanceled || tenders[0].RefundedAmount != usd(2500) {
+			t.Errorf("expected gift card tender to be refunded and voided, got %s", tenders[0].Status)
+		}
+		if tenders[1].Status != services.TenderStatusFailed {
+			t.Errorf("expected card tender to fail, got %s", tenders[1].Status)
+		}
+		if len(gateway.Refunds) != 1 || gateway.Refunds[0].Amount != usd(2500) {
+			t.Errorf("expected one refund of 2500, got %+v", gateway.Refunds)
+		}
+
+		summary, err := service.Summary(ctx, order)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Balance != usd(10000) {
+			t.Errorf("expected full balance due after rollback, got %d", summary.Balance.Amount)
+		}
+	})
+
+	t.Run("records pending tenders without a gateway", func(t *testing.T) {
+		repo := mocks.NewMockPaymentRepository()
+		service := services.NewPaymentService(repo, nil)
+		order := newTestOrder(10000)
+
+		tenders, err := service.ChargeTenders(ctx, order, split)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, tender := range tenders {
+			if tender.Status != services.TenderStatusPending {
+				t.Errorf("expected pending tender, got %s", tender.Status)
+			}
+		}
+	})
+}
+
+func TestPaymentService_RefundTender(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockPaymentRepository()
+	service := services.NewPaymentService(repo, mocks.NewMockPaymentGateway())
+	order := newTestOrder(10000)
+
+	tenders, err := service.ChargeTenders(ctx, order, []services.TenderRequest{
+		{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(10000)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tenderID := tenders[0].ID
+
+	tender, err := service.RefundTender(ctx, tenderID, usd(4000), payments.RefundReasonRequestedByCustomer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tender.Status != services.TenderStatusPartiallyRefunded || tender.Refundable() != usd(6000) {
+		t.Errorf("expected partial refund with 6000 remaining, got %s with %d", tender.Status, tender.Refundable().Amount)
+	}
+
+	if _, err := service.RefundTender(ctx, tenderID, usd(6001), payments.RefundReasonOther); err != services.ErrRefundExceedsCapture {
+		t.Errorf("expected ErrRefundExceedsCapture, got %v", err)
+	}
+
+	tender, err = service.RefundTender(ctx, tenderID, usd(6000), payments.RefundReasonOther)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tender.Status != services.TenderStatusRefunded {
+		t.Errorf("expected refunded tender, got %s", tender.Status)
+	}
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type paymentWebhookFixture struct {
+	provider *services.PaymentWebhookProvider
+	payments *mocks.MockPaymentRepository
+	retries  *mocks.MockPaymentRetryRepository
+	order    *orders.Order
+}
+
+func newPaymentWebhookFixture() *paymentWebhookFixture {
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(10000)
+	orderRepo.Orders[order.ID] = order
+
+	payments := mocks.NewMockPaymentRepository()
+	retries := mocks.NewMockPaymentRetryRepository()
+	paymentService := services.NewPaymentService(payments, nil)
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+	retryService := services.NewPaymentRetryService(retries, paymentService, orderService,
+		services.PaymentRetryPolicy{MaxAttempts: 3, Window: time.Hour})
+
+	return &paymentWebhookFixture{
+		provider: services.NewPaymentWebhookProvider("secret", nil).
+			WithPayments(paymentService, orderService).
+			WithPaymentRetryService(retryService),
+		payments: payments,
+		retries:  retries,
+		order:    order,
+	}
+}
+
+func (f *paymentWebhookFixture) process(t *testing.T, eventType string, amount int64) error {
+	t.Helper()
+	payload := services.PaymentIntentWebhookPayload{ID: "evt_1", Type: eventType}
+	payload.Data.PaymentIntentID = "pi_1"
+	payload.Data.OrderID = f.order.ID
+	payload.Data.Amount = amount
+	payload.Data.Currency = "usd"
+	payload.Data.FailureReason = "card_declined"
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	return f.provider.Process(context.Background(), &services.WebhookEvent{EventType: eventType, Payload: body})
+}
+
+func TestPaymentWebhookProvider_Capture(t *testing.T) {
+	t.Run("full capture marks the order paid", func(t *testing.T) {
+		f := newPaymentWebhookFixture()
+		if err := f.process(t, services.PaymentEventIntentSucceeded, 10000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.order.Status != orders.OrderStatusPaid {
+			t.Errorf("expected order to be paid, got %s", f.order.Status)
+		}
+		if len(f.payments.Tenders) != 1 {
+			t.Fatalf("expected the capture to be recorded as one tender, got %d", len(f.payments.Tenders))
+		}
+		for _, tender := range f.payments.Tenders {
+			if tender.Status != services.TenderStatusCaptured || tender.GatewayReference != "pi_1" {
+				t.Errorf("expected captured tender for pi_1, got %s for %q", tender.Status, tender.GatewayReference)
+			}
+		}
+	})
+
+	t.Run("redelivered capture is recorded once", func(t *testing.T) {
+		f := newPaymentWebhookFixture()
+		for i := 0; i < 2; i++ {
+			if err := f.process(t, services.PaymentEventIntentSucceeded, 10000); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(f.payments.Tenders) != 1 {
+			t.Errorf("expected one tender, got %d", len(f.payments.Tenders))
+		}
+	})
+
+	t.Run("partial capture leaves the order pending", func(t *testing.T) {
+		f := newPaymentWebhookFixture()
+		if err := f.process(t, services.PaymentEventIntentSucceeded, 4000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.order.Status != orders.OrderStatusPending {
+			t.Errorf("expected order to stay pending, got %s", f.order.Status)
+		}
+	})
+}
+
+func TestPaymentWebhookProvider_Failure(t *testing.T) {
+	t.Run("failed intent opens a payment retry", func(t *testing.T) {
+		f := newPaymentWebhookFixture()
+		if err := f.process(t, services.PaymentEventIntentFailed, 10000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		retry := f.retries.Retries[f.order.ID]
+		if retry == nil || retry.State != services.PaymentRetryStatePending {
+			t.Fatalf("expected a pending payment retry, got %+v", retry)
+		}
+	})
+
+	t.Run("late failure does not reopen a paid order", func(t *testing.T) {
+		f := newPaymentWebhookFixture()
+		if err := f.process(t, services.PaymentEventIntentSucceeded, 10000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := f.process(t, services.PaymentEventIntentFailed, 10000); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f.order.Status != orders.OrderStatusPaid {
+			t.Errorf("expected order to stay paid, got %s", f.order.Status)
+		}
+		if f.retries.Retries[f.order.ID] != nil {
+			t.Errorf("expected no payment retry for a paid order")
+		}
+	})
+}
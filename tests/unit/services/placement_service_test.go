@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newTestPlacement(title string, position int) *services.Placement {
+	return &services.Placement{
+		Slot:      "home_hero",
+		Title:     title,
+		ImageURL:  "https://cdn.example.com/banners/" + title + ".jpg",
+		TargetURL: "/catalog/sale",
+		Position:  position,
+		IsActive:  true,
+	}
+}
+
+func TestPlacement_IsLive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		active   bool
+		startsAt *time.Time
+		endsAt   *time.Time
+		expected bool
+	}{
+		{"active without schedule", true, nil, nil, true},
+		{"inactive", false, nil, nil, false},
+		{"within schedule", true, &past, &future, true},
+		{"not started", true, &future, nil, false},
+		{"ended", true, nil, &past, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement := &services.Placement{IsActive: tt.active, StartsAt: tt.startsAt, EndsAt: tt.endsAt}
+			if got := placement.IsLive(now); got != tt.expected {
+				t.Errorf("expected IsLive %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPlacementService_GetLivePlacements(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewPlacementService(mocks.NewMockPlacementRepository())
+
+	ended := time.Now().Add(-time.Minute)
+	expired := newTestPlacement("expired", 0)
+	expired.EndsAt = &ended
+
+	for _, placement := range []*services.Placement{newTestPlacement("second", 2), newTestPlacement("first", 1), expired} {
+		if err := service.SavePlacement(ctx, placement); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	other := newTestPlacement("tile", 0)
+	other.Slot = "home_tiles"
+	if err := service.SavePlacement(ctx, other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	live, err := service.GetLivePlacements(ctx, "HOME_HERO")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(live) != 2 || live[0].Title != "first" || live[1].Title != "second" {
+		t.Errorf("expected first and second in position order, got %d placements", len(live))
+	}
+}
+
+func TestPlacementService_SavePlacement(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewPlacementService(mocks.NewMockPlacementRepository())
+	start := time.Now()
+	end := start.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		modify   func(p *services.Placement)
+		expected error
+	}{
+		{"valid", func(p *services.Placement) {}, nil},
+		{"absolute target", func(p *services.Placement) { p.TargetURL = "https://example.com/sale" }, nil},
+		{"missing image", func(p *services.Placement) { p.ImageURL = "" }, services.ErrInvalidPlacement},
+		{"bad slot", func(p *services.Placement) { p.Slot = "home hero" }, services.ErrInvalidPlacement},
+		{"scheme-relative target", func(p *services.Placement) { p.TargetURL = "//evil.example.com" }, services.ErrInvalidPlacement},
+		{"javascript target", func(p *services.Placement) { p.TargetURL = "javascript:alert(1)" }, services.ErrInvalidPlacement},
+		{"ends before start", func(p *services.Placement) { p.StartsAt, p.EndsAt = &start, &end }, services.ErrInvalidSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placement := newTestPlacement("banner", 0)
+			tt.modify(placement)
+
+			err := service.SavePlacement(ctx, placement)
+			if err != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, err)
+			}
+			if err == nil && placement.ID == "" {
+				t.Error("expected an ID to be assigned")
+			}
+		})
+	}
+}
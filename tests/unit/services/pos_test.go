@@ -0,0 +1,228 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newPOSFixture returns a POS service over the t-shirt catalog
+func newPOSFixture() (*services.POSService, *mocks.MockOrderRepository, *mocks.MockPaymentRepository) {
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	productRepo.Products[fixtures.ProductInactive.ID] = fixtures.ProductInactive
+	variantRepo := mocks.NewMockVariantRepository()
+	variantRepo.Variants[fixtures.VariantTShirtSmallRed.ID] = fixtures.VariantTShirtSmallRed
+
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderRepo := mocks.NewMockOrderRepository()
+	paymentRepo := mocks.NewMockPaymentRepository()
+	svc := services.NewPOSService(
+		mocks.NewMockPOSRepository(orderRepo, paymentRepo),
+		productRepo,
+		variantRepo,
+		services.NewOrderService(orderRepo, pricingService.Service, nil, nil),
+		services.NewPaymentService(paymentRepo, nil),
+	)
+	return svc, orderRepo, paymentRepo
+}
+
+func newPOSSaleRequest(payments ...services.POSPayment) services.POSSaleRequest {
+	return services.POSSaleRequest{
+		RegisterID: "reg-1",
+		TerminalID: "term-1",
+		StoreAddress: orders.Address{
+			FirstName:    "Main Street",
+			LastName:     "Store",
+			AddressLine1: "1 Main St",
+			City:         "Springfield",
+			State:        "IL",
+			PostalCode:   "62701",
+			Country:      "US",
+		},
+		// 2 x 2999 variant + 1 x 2999 product = 8997
+		Items: []services.POSSaleItem{
+			{SKU: fixtures.VariantTShirtSmallRed.SKU, Quantity: 2},
+			{SKU: fixtures.ProductTShirt.SKU, Quantity: 1},
+		},
+		Payments: payments,
+	}
+}
+
+func TestPOSService_CreateSale(t *testing.T) {
+	ctx := context.Background()
+	svc, orderRepo, _ := newPOSFixture()
+
+	result, err := svc.CreateSale(ctx, newPOSSaleRequest(
+		services.POSPayment{Type: services.TenderTypeCard, Reference: "txn-42", Amount: 5000},
+		services.POSPayment{Type: services.TenderTypeCash, Amount: 3997},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Order.Total.Amount != 8997 {
+		t.Errorf("expected total 8997, got %d", result.Order.Total.Amount)
+	}
+	if result.Order.Status != orders.OrderStatusPaid {
+		t.Errorf("expected the order to be paid, got %s", result.Order.Status)
+	}
+	if result.Sale.RegisterID != "reg-1" || result.Sale.OrderID != result.Order.ID {
+		t.Errorf("expected the sale to record the register and order, got %+v", result.Sale)
+	}
+	if result.Order.Items[0].VariantID == nil || result.Order.Items[1].VariantID != nil {
+		t.Error("expected the first line rung up by variant SKU and the second by product SKU")
+	}
+
+	if len(result.Tenders) != 2 {
+		t.Fatalf("expected 2 tenders, got %d", len(result.Tenders))
+	}
+	for _, tender := range result.Tenders {
+		if tender.Status != services.TenderStatusCaptured || tender.CapturedAmount != tender.Amount {
+			t.Errorf("expected %s tender captured in full, got %s with %d captured", tender.Type, tender.Status, tender.CapturedAmount.Amount)
+		}
+		if tender.GatewayReference != "" {
+			t.Errorf("expected %s tender never sent to the gateway", tender.Type)
+		}
+	}
+	if result.Tenders[1].PaymentMethodID != "reg-1" {
+		t.Errorf("expected the cash tender referenced by register, got %q", result.Tenders[1].PaymentMethodID)
+	}
+
+	if stored := orderRepo.Orders[result.Order.ID]; stored.Status != orders.OrderStatusPaid {
+		t.Errorf("expected the stored order paid, got %s", stored.Status)
+	}
+}
+
+func TestPOSService_CreateSale_TotalMismatch(t *testing.T) {
+	ctx := context.Background()
+	svc, orderRepo, paymentRepo := newPOSFixture()
+
+	_, err := svc.CreateSale(ctx, newPOSSaleRequest(
+		services.POSPayment{Type: services.TenderTypeCash, Amount: 8000},
+	))
+	var mismatch *services.POSTotalMismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, services.ErrTenderTotalMismatch) {
+		t.Fatalf("expected POSTotalMismatchError, got %v", err)
+	}
+	if mismatch.OrderTotal.Amount != 8997 {
+		t.Errorf("expected the order total 8997 reported, got %d", mismatch.OrderTotal.Amount)
+	}
+
+	if len(orderRepo.Orders) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(orderRepo.Orders))
+	}
+	for _, order := range orderRepo.Orders {
+		if order.Status != orders.OrderStatusCanceled {
+			t.Errorf("expected the unpaid order canceled, got %s", order.Status)
+		}
+	}
+	if len(paymentRepo.Tenders) != 0 {
+		t.Errorf("expected no tenders recorded, got %d", len(paymentRepo.Tenders))
+	}
+}
+
+func TestPOSService_CreateSale_Invalid(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newPOSFixture()
+	cash := services.POSPayment{Type: services.TenderTypeCash, Amount: 8997}
+
+	tests := []struct {
+		name    string
+		modify  func(*services.POSSaleRequest)
+		wantErr error
+	}{
+		{"missing register", func(r *services.POSSaleRequest) { r.RegisterID = "" }, services.ErrInvalidPOSSale},
+		{"zero quantity", func(r *services.POSSaleRequest) { r.Items[0].Quantity = 0 }, services.ErrInvalidPOSSale},
+		{"incomplete address", func(r *services.POSSaleRequest) { r.StoreAddress.City = "" }, services.ErrInvalidPOSSale},
+		{"card without reference", func(r *services.POSSaleRequest) { r.Payments[0].Type = services.TenderTypeCard }, services.ErrInvalidPOSSale},
+		{"gift card", func(r *services.POSSaleRequest) { r.Payments[0].Type = services.TenderTypeGiftCard }, services.ErrInvalidPOSSale},
+		{"unknown SKU", func(r *services.POSSaleRequest) { r.Items[0].SKU = "NOPE-001" }, services.ErrPOSItemNotFound},
+		{"inactive product", func(r *services.POSSaleRequest) { r.Items[0].SKU = fixtures.ProductInactive.SKU }, services.ErrPOSItemNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newPOSSaleRequest(cash)
+			tt.modify(&req)
+			if _, err := svc.CreateSale(ctx, req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPOSService_RegisterSummary(t *testing.T) {
+	ctx := context.Background()
+	svc, orderRepo, paymentRepo := newPOSFixture()
+
+	first, err := svc.CreateSale(ctx, newPOSSaleRequest(
+		services.POSPayment{Type: services.TenderTypeCard, Reference: "txn-1", Amount: 8997},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.CreateSale(ctx, newPOSSaleRequest(
+		services.POSPayment{Type: services.TenderTypeCard, Reference: "txn-2", Amount: 4000},
+		services.POSPayment{Type: services.TenderTypeCash, Amount: 4997},
+	)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A mismatched sale is canceled and only counted
+	if _, err := svc.CreateSale(ctx, newPOSSaleRequest(
+		services.POSPayment{Type: services.TenderTypeCash, Amount: 100},
+	)); err == nil {
+		t.Fatal("expected a total mismatch")
+	}
+
+	// Part of the first card payment is refunded at the till
+	cardTender := first.Tenders[0]
+	cardTender.RefundedAmount = usd(1000)
+	if err := paymentRepo.Save(ctx, cardTender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summary, err := svc.RegisterSummary(ctx, "reg-1", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Sales != 2 || summary.CanceledSales != 1 {
+		t.Errorf("expected 2 sales and 1 canceled, got %d and %d", summary.Sales, summary.CanceledSales)
+	}
+	if summary.GrossSales.Amount != 17994 {
+		t.Errorf("expected gross 17994, got %d", summary.GrossSales.Amount)
+	}
+	if summary.Tenders[services.TenderTypeCard].Amount != 12997 || summary.Tenders[services.TenderTypeCash].Amount != 4997 {
+		t.Errorf("expected 12997 card and 4997 cash, got %v", summary.Tenders)
+	}
+	if summary.Refunded.Amount != 1000 || summary.NetCollected.Amount != 16994 {
+		t.Errorf("expected 1000 refunded and 16994 collected, got %d and %d", summary.Refunded.Amount, summary.NetCollected.Amount)
+	}
+	if summary.FirstSaleAt == nil || summary.LastSaleAt == nil {
+		t.Error("expected the first and last sale times")
+	}
+
+	// Other registers and days see none of these sales
+	other, err := svc.RegisterSummary(ctx, "reg-2", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.Sales != 0 || other.GrossSales.Currency != "USD" || len(orderRepo.Orders) != 3 {
+		t.Errorf("expected an empty USD summary for another register, got %+v", other)
+	}
+	yesterday, err := svc.RegisterSummary(ctx, "reg-1", time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if yesterday.Sales != 0 {
+		t.Errorf("expected no sales yesterday, got %d", yesterday.Sales)
+	}
+}
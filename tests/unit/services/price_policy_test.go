@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newTestPricePolicy(t *testing.T, entries ...string) *services.PricePolicy {
+	t.Helper()
+	rules, err := services.ParsePriceRoundingRules(entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return services.NewPricePolicy(rules)
+}
+
+func TestPricePolicy_Apply(t *testing.T) {
+	policy := newTestPricePolicy(t, "CHF:nearest:5", "usd:charm:99", "JPY:charm:990", "SEK:up:100", "NOK:down:100")
+
+	tests := []struct {
+		name  string
+		price money.Money
+		want  int64
+	}{
+		{"CHF rounds to 0.05", money.Money{Amount: 1233, Currency: "CHF"}, 1235},
+		{"CHF rounds down to 0.05", money.Money{Amount: 1232, Currency: "CHF"}, 1230},
+		{"charm rounds down to .99", money.Money{Amount: 1234, Currency: "USD"}, 1199},
+		{"charm rounds up to .99", money.Money{Amount: 1260, Currency: "USD"}, 1299},
+		{"charm price kept", money.Money{Amount: 1999, Currency: "USD"}, 1999},
+		{"charm never goes to zero", money.Money{Amount: 40, Currency: "USD"}, 99},
+		{"charm in thousands", money.Money{Amount: 2150, Currency: "JPY"}, 1990},
+		{"up to whole units", money.Money{Amount: 9901, Currency: "SEK"}, 10000},
+		{"down to whole units", money.Money{Amount: 9999, Currency: "NOK"}, 9900},
+		{"down never goes to zero", money.Money{Amount: 50, Currency: "NOK"}, 100},
+		{"no rule for the currency", money.Money{Amount: 1234, Currency: "EUR"}, 1234},
+		{"zero left alone", money.Money{Amount: 0, Currency: "USD"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Apply(tt.price)
+			if got.Amount != tt.want || got.Currency != tt.price.Currency {
+				t.Errorf("expected %d %s, got %d %s", tt.want, tt.price.Currency, got.Amount, got.Currency)
+			}
+		})
+	}
+}
+
+func TestParsePriceRoundingRules(t *testing.T) {
+	invalid := [][]string{
+		{"CHF:5"},
+		{"CHFR:nearest:5"},
+		{"CHF:sideways:5"},
+		{"CHF:nearest:0"},
+		{"CHF:nearest:x"},
+		{"CHF:nearest:5", "chf:up:10"},
+	}
+	for _, entries := range invalid {
+		if _, err := services.ParsePriceRoundingRules(entries); err == nil {
+			t.Errorf("expected %v to be rejected", entries)
+		}
+	}
+}
+
+func TestPricePolicy_ConvertedAndSalePrices(t *testing.T) {
+	ctx := context.Background()
+	policy := newTestPricePolicy(t, "CHF:nearest:5", "USD:charm:99")
+
+	// Converted display prices are rounded; thresholds converted with Convert stay exact
+	currency := services.NewCurrencyService("USD", map[string]float64{"CHF": 0.8843}).WithPricePolicy(policy)
+	price, err := currency.ConvertPrice(money.Money{Amount: 2999, Currency: "USD"}, "CHF")
+	if err != nil || price.Amount != 2650 || price.Currency != "CHF" {
+		t.Errorf("expected 26.50 CHF, got %+v (%v)", price, err)
+	}
+	if exact, _ := currency.Convert(money.Money{Amount: 2999, Currency: "USD"}, "CHF"); exact.Amount != 2652 {
+		t.Errorf("expected Convert to stay exact at 26.52 CHF, got %d", exact.Amount)
+	}
+	if _, err := currency.ConvertPrice(money.Money{Amount: 2999, Currency: "USD"}, "JPY"); !errors.Is(err, services.ErrExchangeRateNotFound) {
+		t.Errorf("expected ErrExchangeRateNotFound, got %v", err)
+	}
+
+	// Sale prices from the price table are rounded wherever they are resolved
+	prices := mocks.NewMockProductPriceRepository()
+	prices.AddPrice(fixtures.ProductTShirt.ID, 2240, "USD")
+	rounded := policy.RoundedPrices(prices)
+	salePrice, err := rounded.FindEffectivePrice(ctx, fixtures.ProductTShirt.ID, nil, time.Now())
+	if err != nil || salePrice.Price.Amount != 2199 {
+		t.Errorf("expected the sale price rounded to 21.99, got %+v (%v)", salePrice, err)
+	}
+	if prices.Prices[fixtures.ProductTShirt.ID].Price.Amount != 2240 {
+		t.Error("expected the stored price to be left as it is")
+	}
+	variantPrices, err := rounded.FindEffectiveVariantPrices(ctx, fixtures.ProductTShirt.ID, []string{"var-s", "var-m"}, time.Now())
+	if err != nil || len(variantPrices) != 2 || variantPrices["var-m"].Price.Amount != 2199 {
+		t.Errorf("expected both variant prices rounded to 21.99, got %+v (%v)", variantPrices, err)
+	}
+
+	products := mocks.NewMockProductRepository()
+	products.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	catalog := services.NewCatalogService(products, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithSalePriceResolver(rounded).
+		WithCurrencyService(currency)
+	product, err := catalog.GetProduct(ctx, fixtures.ProductTShirt.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.SalePrice == nil || product.SalePrice.Amount != 2199 {
+		t.Errorf("expected the catalog sale price 21.99, got %+v", product.SalePrice)
+	}
+
+	converted, err := catalog.InCurrency([]*services.ProductResponse{product}, "CHF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if converted[0].BasePrice.Currency != "CHF" || converted[0].BasePrice.Amount%5 != 0 {
+		t.Errorf("expected the base price in CHF 0.05 steps, got %+v", converted[0].BasePrice)
+	}
+	if converted[0].SalePrice.Amount != 1945 {
+		t.Errorf("expected the sale price 19.45 CHF, got %+v", converted[0].SalePrice)
+	}
+	if product.BasePrice.Currency != "USD" {
+		t.Error("expected the cached product to be left in its own currency")
+	}
+}
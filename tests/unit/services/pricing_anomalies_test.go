@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// recordingPricingAlerts records the anomalies admins were alerted about
+type recordingPricingAlerts struct {
+	anomalies []*services.PricingAnomaly
+}
+
+func (n *recordingPricingAlerts) NotifyPricingAnomaly(ctx context.Context, anomaly *services.PricingAnomaly) error {
+	n.anomalies = append(n.anomalies, anomaly)
+	return nil
+}
+
+func setupPricingAnomalies() (*services.PricingAnomalyService, *mocks.MockPricingAnomalyRepository, *recordingPricingAlerts) {
+	repo := mocks.NewMockPricingAnomalyRepository()
+	alerts := &recordingPricingAlerts{}
+	return services.NewPricingAnomalyService(repo, alerts, 0.8), repo, alerts
+}
+
+func TestPricingAnomalyService_CheckPrice(t *testing.T) {
+	anomalies, _, _ := setupPricingAnomalies()
+	usd := func(amount int64) *money.Money { return &money.Money{Amount: amount, Currency: "USD"} }
+
+	tests := []struct {
+		name    string
+		old     *money.Money
+		updated money.Money
+		reason  services.PricingAnomalyReason
+	}{
+		{"price set to zero", usd(2000), *usd(0), services.AnomalyPriceZero},
+		{"new product at zero", nil, *usd(0), services.AnomalyPriceZero},
+		{"cut by more than 80%", usd(2000), *usd(399), services.AnomalyPriceDrop},
+		{"cut by exactly 80%", usd(2000), *usd(400), ""},
+		{"ordinary sale", usd(2000), *usd(1500), ""},
+		{"price raised", usd(2000), *usd(2500), ""},
+		{"already free", usd(0), *usd(0), ""},
+		{"currency changed", usd(2000), money.Money{Amount: 100, Currency: "JPY"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, suspicious := anomalies.CheckPrice(tt.old, tt.updated)
+			if reason != tt.reason || suspicious != (tt.reason != "") {
+				t.Errorf("expected %q, got %q (suspicious %v)", tt.reason, reason, suspicious)
+			}
+		})
+	}
+}
+
+func TestPricingAnomalyService_HoldsPriceChanges(t *testing.T) {
+	anomalies, repo, alerts := setupPricingAnomalies()
+	productRepo := mocks.NewMockProductRepository()
+	variantRepo := mocks.NewMockVariantRepository()
+	productRepo.Products["prod-1"] = historyProduct("Tee", 2000)
+	variantRepo.Variants["m"] = historyVariant("m", 2000)
+	history := services.NewCatalogHistoryService(mocks.NewMockCatalogVersionRepository(), anomalies.GuardProducts(productRepo), anomalies.GuardVariants(variantRepo))
+	anomalies.WithCatalogWriters(history.Products(), history.Variants())
+	ctx := context.Background()
+
+	// An ordinary change goes straight through
+	if err := history.Products().Save(ctx, historyProduct("Tee", 1800)); err != nil {
+		t.Fatalf("failed to save product: %v", err)
+	}
+
+	err := history.Products().Save(ctx, historyProduct("Tee", 0))
+	var held *services.PriceChangeHeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("expected the zero price to be held, got %v", err)
+	}
+	if productRepo.Products["prod-1"].BasePrice.Amount != 1800 {
+		t.Errorf("expected the held price not to be saved, got %d", productRepo.Products["prod-1"].BasePrice.Amount)
+	}
+	if held.Anomaly.Reason != services.AnomalyPriceZero || held.Anomaly.OldPrice.Amount != 1800 || len(alerts.anomalies) != 1 {
+		t.Errorf("expected a price_zero anomaly from 1800 with an alert, got %+v", held.Anomaly)
+	}
+
+	err = history.Variants().Save(ctx, historyVariant("m", 100))
+	if !errors.As(err, &held) || held.Anomaly.Reason != services.AnomalyPriceDrop || held.Anomaly.VariantID != "m" {
+		t.Fatalf("expected the variant price drop to be held, got %v", err)
+	}
+	variantAnomaly := held.Anomaly
+
+	// Approving applies the held change; rejecting discards it
+	approved, err := anomalies.Approve(ctx, variantAnomaly.ID, "admin-1")
+	if err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	if approved.Status != services.AnomalyApproved || approved.ReviewedBy != "admin-1" || variantRepo.Variants["m"].Price.Amount != 100 {
+		t.Errorf("expected the variant at 100 after approval, got %d (%s)", variantRepo.Variants["m"].Price.Amount, approved.Status)
+	}
+	versions, _ := history.ListHistory(ctx, "prod-1", 10, 0)
+	if len(versions) != 2 || versions[0].ChangedBy != "admin-1" {
+		t.Errorf("expected the approved change recorded by admin-1, got %d versions", len(versions))
+	}
+
+	productAnomalyID := ""
+	for id, anomaly := range repo.Anomalies {
+		if anomaly.Kind == services.AnomalyProductPrice {
+			productAnomalyID = id
+		}
+	}
+	if _, err := anomalies.Reject(ctx, productAnomalyID, "admin-1"); err != nil {
+		t.Fatalf("failed to reject: %v", err)
+	}
+	if productRepo.Products["prod-1"].BasePrice.Amount != 1800 {
+		t.Errorf("expected the rejected price to be discarded, got %d", productRepo.Products["prod-1"].BasePrice.Amount)
+	}
+	if _, err := anomalies.Approve(ctx, productAnomalyID, "admin-1"); err != services.ErrPricingAnomalyReviewed {
+		t.Errorf("expected ErrPricingAnomalyReviewed, got %v", err)
+	}
+}
+
+func TestPricingAnomalyService_HoldsOrders(t *testing.T) {
+	anomalies, _, alerts := setupPricingAnomalies()
+	orderRepo := mocks.NewMockOrderRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithPricingAnomalies(anomalies)
+	anomalies.WithOrderService(orderService)
+	fulfillment := services.NewFulfillmentService(mocks.NewMockFulfillmentRepository(orderRepo), orderService).WithPricingAnomalies(anomalies)
+	ctx := context.Background()
+
+	usd := func(amount int64) money.Money { return money.Money{Amount: amount, Currency: "USD"} }
+	order := &orders.Order{
+		ID:            "order-1",
+		OrderNumber:   "ORD-1",
+		Status:        orders.OrderStatusPaid,
+		Subtotal:      usd(10000),
+		DiscountTotal: usd(9500),
+		Total:         usd(500),
+		CreatedAt:     time.Now(),
+	}
+	orderRepo.Orders[order.ID] = order
+
+	// A 20% discount is not suspicious
+	fair := *order
+	fair.DiscountTotal = usd(2000)
+	if anomaly, err := anomalies.CheckOrder(ctx, &fair); err != nil || anomaly != nil {
+		t.Fatalf("expected no anomaly for a 20%% discount, got %+v (%v)", anomaly, err)
+	}
+
+	anomaly, err := anomalies.CheckOrder(ctx, order)
+	if err != nil || anomaly == nil {
+		t.Fatalf("expected the order to be held, got %v", err)
+	}
+	if anomaly.Reason != services.AnomalyOrderBelowValue || anomaly.OrderTotal.Amount != 500 || anomaly.ItemValue.Amount != 10000 || len(alerts.anomalies) != 1 {
+		t.Errorf("expected an order_below_value anomaly paying 500 of 10000, got %+v", anomaly)
+	}
+
+	if _, err := orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusProcessing); err != services.ErrOrderOnHold {
+		t.Errorf("expected a held order not to move to processing, got %v", err)
+	}
+	if _, err := fulfillment.ConfirmShipment(ctx, order.ID, services.ShipmentRequest{Carrier: "UPS", TrackingNumber: "1Z1"}); err != services.ErrOrderOnHold {
+		t.Errorf("expected a held order not to ship, got %v", err)
+	}
+
+	if _, err := anomalies.Reject(ctx, anomaly.ID, "admin-1"); err != nil {
+		t.Fatalf("failed to reject: %v", err)
+	}
+	if orderRepo.Orders[order.ID].Status != orders.OrderStatusCanceled {
+		t.Errorf("expected the rejected order to be canceled, got %s", orderRepo.Orders[order.ID].Status)
+	}
+}
+
+func TestPricingAnomalyService_ApproveReleasesOrder(t *testing.T) {
+	anomalies, _, _ := setupPricingAnomalies()
+	orderRepo := mocks.NewMockOrderRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithPricingAnomalies(anomalies)
+	ctx := context.Background()
+
+	order := &orders.Order{
+		ID:            "order-1",
+		Status:        orders.OrderStatusPaid,
+		Subtotal:      money.Money{Amount: 10000, Currency: "USD"},
+		DiscountTotal: money.Money{Amount: 10000, Currency: "USD"},
+	}
+	orderRepo.Orders[order.ID] = order
+	anomaly, _ := anomalies.CheckOrder(ctx, order)
+	if anomaly == nil {
+		t.Fatal("expected a fully discounted order to be held")
+	}
+
+	if _, err := anomalies.Approve(ctx, anomaly.ID, "admin-1"); err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	if _, err := orderService.UpdateStatus(ctx, order.ID, orders.OrderStatusProcessing); err != nil {
+		t.Errorf("expected the approved order to move to processing, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+func TestProductCache_CoalescesConcurrentMisses(t *testing.T) {
+	cache := services.NewProductCache(time.Minute)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*services.ProductResponse, error) {
+		loads.Add(1)
+		<-release
+		return &services.ProductResponse{}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Get(context.Background(), "prod-1", load); err != nil {
+				t.Errorf("Get failed: %v", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("Expected 1 load for concurrent misses, got %d", got)
+	}
+
+	if _, err := cache.Get(context.Background(), "prod-1", load); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := loads.Load(); got != 1 {
+		t.Errorf("Expected cached read to skip the load, got %d loads", got)
+	}
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Hits < 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestProductCache_Invalidate(t *testing.T) {
+	cache := services.NewProductCache(time.Minute)
+
+	var loads int
+	load := func(ctx context.Context) (*services.ProductResponse, error) {
+		loads++
+		return &services.ProductResponse{}, nil
+	}
+
+	ctx := context.Background()
+	cache.Get(ctx, "prod-1", load)
+	cache.Get(ctx, "prod-2", load)
+
+	cache.Invalidate("prod-1")
+	cache.Get(ctx, "prod-1", load)
+	cache.Get(ctx, "prod-2", load)
+	if loads != 3 {
+		t.Errorf("Expected only the invalidated product to reload, got %d loads", loads)
+	}
+
+	cache.Flush()
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Expected empty cache after flush, got %d entries", stats.Entries)
+	}
+}
+
+func TestProductCache_Expires(t *testing.T) {
+	cache := services.NewProductCache(10 * time.Millisecond)
+
+	var loads int
+	load := func(ctx context.Context) (*services.ProductResponse, error) {
+		loads++
+		return &services.ProductResponse{}, nil
+	}
+
+	cache.Get(context.Background(), "prod-1", load)
+	time.Sleep(20 * time.Millisecond)
+	cache.Get(context.Background(), "prod-1", load)
+	if loads != 2 {
+		t.Errorf("Expected expired entry to reload, got %d loads", loads)
+	}
+}
@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestProductMediaService_SetMedia(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockProductMediaRepository()
+	var changed []string
+	svc := services.NewProductMediaService(repo).WithChangeHook(func(ids ...string) { changed = append(changed, ids...) })
+
+	media, err := svc.SetMedia(ctx, "prod-1", []*services.ProductMedia{
+		{Type: " Video ", URL: "https://cdn.example.com/laptop.mp4", PosterURL: "https://cdn.example.com/laptop.jpg", MimeType: "Video/MP4"},
+		{Type: services.MediaImage, URL: "/media/products/laptop.jpg", Alt: "Laptop"},
+		{Type: services.MediaModel3D, URL: "https://cdn.example.com/laptop.glb"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if media[0].Type != services.MediaVideo || media[0].MimeType != "video/mp4" {
+		t.Errorf("expected the type and mime type normalized, got %+v", media[0])
+	}
+	for i, item := range media {
+		if item.Position != i {
+			t.Errorf("expected item %d at position %d, got %d", i, i, item.Position)
+		}
+	}
+	if len(repo.Media["prod-1"]) != 3 || len(changed) != 1 || changed[0] != "prod-1" {
+		t.Errorf("expected the gallery stored and the product reported changed, got %v", changed)
+	}
+
+	invalid := [][]*services.ProductMedia{
+		{{Type: "audio", URL: "https://cdn.example.com/a.mp3"}},
+		{{Type: services.MediaImage, URL: "https://cdn.example.com/a.jpg", PosterURL: "https://cdn.example.com/b.jpg"}},
+		{{Type: services.MediaVideo, URL: "javascript:alert(1)"}},
+		{{Type: services.MediaVideo, URL: "https://cdn.example.com/a.mp4", PosterURL: "ftp://cdn.example.com/a.jpg"}},
+		{nil},
+	}
+	for _, items := range invalid {
+		if _, err := svc.SetMedia(ctx, "prod-1", items); !errors.Is(err, services.ErrInvalidProductMedia) {
+			t.Errorf("expected %+v to be invalid, got %v", items[0], err)
+		}
+	}
+	if len(repo.Media["prod-1"]) != 3 || len(changed) != 1 {
+		t.Error("expected invalid media to leave the gallery unchanged")
+	}
+}
+
+func TestCatalogService_Media(t *testing.T) {
+	ctx := context.Background()
+	products := mocks.NewMockProductRepository()
+	products.Products["prod-1"] = &catalog.Product{ID: "prod-1", Name: "Laptop", Images: []string{"https://images.example.com/laptop.jpg"}}
+	products.Products["prod-2"] = &catalog.Product{ID: "prod-2", Name: "Phone", Images: []string{"https://images.example.com/phone.jpg"}}
+	repo := mocks.NewMockProductMediaRepository()
+	svc := services.NewCatalogService(products, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithProductCache(services.NewProductCache(time.Minute), nil)
+	media := services.NewProductMediaService(repo).WithChangeHook(svc.InvalidateProducts)
+	svc.WithMedia(media)
+
+	// Products without a gallery list their images
+	product, err := svc.GetProduct(ctx, "prod-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(product.Media) != 1 || product.Media[0].Type != services.MediaImage || product.Media[0].URL != product.Images[0] {
+		t.Fatalf("expected the image as media, got %+v", product.Media)
+	}
+
+	// A saved gallery replaces them, and the cached product is refreshed
+	if _, err := media.SetMedia(ctx, "prod-1", []*services.ProductMedia{
+		{Type: services.MediaVideo, URL: "https://cdn.example.com/laptop.mp4", PosterURL: "https://cdn.example.com/poster.jpg"},
+		{Type: services.MediaImage, URL: "https://images.example.com/laptop.jpg"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product, err = svc.GetProduct(ctx, "prod-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(product.Media) != 2 || product.Media[0].Type != services.MediaVideo || product.Media[0].PosterURL == "" {
+		t.Errorf("expected the saved gallery, got %+v", product.Media)
+	}
+
+	listed, err := svc.ListProducts(ctx, catalog.ProductFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, product := range listed {
+		expected := map[string]int{"prod-1": 2, "prod-2": 1}[product.ID]
+		if len(product.Media) != expected {
+			t.Errorf("expected %d media items for %s, got %+v", expected, product.ID, product.Media)
+		}
+	}
+
+	// Media that cannot be loaded falls back to the images
+	repo.FindByProductsError = errors.New("database error")
+	listed, err = svc.ListProducts(ctx, catalog.ProductFilter{})
+	if err != nil || len(listed) != 2 || len(listed[0].Media) != 1 {
+		t.Errorf("expected listed products with image media, got %v (%v)", listed, err)
+	}
+}
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newQuestionService(t *testing.T) (*services.QuestionService, *mocks.MockQuestionRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	repo := mocks.NewMockQuestionRepository()
+	return services.NewQuestionService(repo, productRepo, services.NewProfanityFilter(nil)), repo
+}
+
+func TestQuestionService_AskAndAnswer(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newQuestionService(t)
+
+	question, err := svc.AskQuestion(ctx, fixtures.TestUserID, fixtures.ProductLaptop.ID, " Does the shit fan stay quiet? ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if question.Body != "Does the **** fan stay quiet?" || !question.Filtered {
+		t.Errorf("expected a trimmed, masked question flagged as filtered, got %+v", question)
+	}
+
+	if _, err := svc.AskQuestion(ctx, fixtures.TestUserID, "missing", "Hello?"); err != services.ErrProductNotFound {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+	for name, text := range map[string]string{"empty": "  ", "too long": strings.Repeat("a", 2001)} {
+		if _, err := svc.AskQuestion(ctx, fixtures.TestUserID, fixtures.ProductLaptop.ID, text); err != services.ErrInvalidQuestion {
+			t.Errorf("%s: expected ErrInvalidQuestion, got %v", name, err)
+		}
+	}
+
+	if _, err := svc.Answer(ctx, question.ID, "user-2", false, "Mine is quiet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	answered, err := svc.Answer(ctx, question.ID, "staff-1", true, "It is rated at 22 dB")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(answered.Answers) != 2 || answered.Answers[0].Staff || !answered.Answers[1].Staff {
+		t.Errorf("expected a customer answer then a staff answer, got %+v", answered.Answers)
+	}
+	if _, err := svc.Answer(ctx, "missing", "user-2", false, "Hello"); err != services.ErrQuestionNotFound {
+		t.Errorf("expected ErrQuestionNotFound, got %v", err)
+	}
+
+	// Hidden questions and answers are left out
+	repo.Answers[answered.Answers[0].ID].Hidden = true
+	questions, err := svc.ProductQuestions(ctx, fixtures.ProductLaptop.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(questions) != 1 || len(questions[0].Answers) != 1 || !questions[0].Answers[0].Staff {
+		t.Errorf("expected the question with only the staff answer, got %+v", questions)
+	}
+
+	repo.Questions[question.ID].Hidden = true
+	if questions, _ := svc.ProductQuestions(ctx, fixtures.ProductLaptop.ID); len(questions) != 0 {
+		t.Errorf("expected the hidden question left out, got %d questions", len(questions))
+	}
+	if _, err := svc.GetQuestion(ctx, question.ID); err != services.ErrQuestionNotFound {
+		t.Errorf("expected a hidden question reported as not found, got %v", err)
+	}
+	if _, err := svc.Answer(ctx, question.ID, "user-2", false, "Hello"); err != services.ErrQuestionNotFound {
+		t.Errorf("expected hidden questions closed to answers, got %v", err)
+	}
+}
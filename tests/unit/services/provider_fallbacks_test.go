@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/shipping"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/breaker"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+// flakyRates fails with err until it is cleared, counting calls
+type flakyRates struct {
+	err   error
+	calls int
+}
+
+func (f *flakyRates) GetRate(ctx context.Context, req shipping.RateRequest) (*shipping.ShippingRate, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &shipping.ShippingRate{MethodID: req.ShippingMethodID, Cost: money.Money{Amount: 1500, Currency: "USD"}}, nil
+}
+
+func (f *flakyRates) GetAvailableRates(ctx context.Context, req shipping.RateRequest) ([]*shipping.ShippingRate, error) {
+	rate, err := f.GetRate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return []*shipping.ShippingRate{rate}, nil
+}
+
+func TestFallbackRateCalculator_OpensAndRecovers(t *testing.T) {
+	primary := &flakyRates{err: errors.New("carrier API timeout")}
+	b := breaker.New("shipping", breaker.Policy{FailureThreshold: 2, Cooldown: 20 * time.Millisecond})
+	calc := services.NewFallbackRateCalculator(primary, services.NewFlatRateShipping(money.Money{Amount: 999, Currency: "USD"}), b)
+	req := shipping.RateRequest{ShippingMethodID: "express"}
+
+	for i := 0; i < 3; i++ {
+		rate, err := calc.GetRate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Expected fallback rate, got error %v", err)
+		}
+		if rate.Cost.Amount != 999 || rate.MethodID != "express" {
+			t.Errorf("Expected flat rate for the requested method, got %+v", rate)
+		}
+	}
+	if primary.calls != 2 {
+		t.Errorf("Expected the open breaker to stop calling the provider after 2 failures, got %d calls", primary.calls)
+	}
+	if status := b.Status(); status.State != breaker.StateOpen || status.Rejected != 1 {
+		t.Errorf("Expected open breaker with 1 rejected call, got %+v", status)
+	}
+
+	// After the cooldown one trial call goes through and closes the breaker
+	primary.err = nil
+	time.Sleep(25 * time.Millisecond)
+	rate, err := calc.GetRate(context.Background(), req)
+	if err != nil || rate.Cost.Amount != 1500 {
+		t.Fatalf("Expected provider rate after recovery, got %+v, %v", rate, err)
+	}
+	if status := b.Status(); status.State != breaker.StateClosed {
+		t.Errorf("Expected closed breaker after a successful trial, got %s", status.State)
+	}
+}
+
+func TestFallbackRateCalculator_IgnoresProviderAnswers(t *testing.T) {
+	primary := &flakyRates{err: services.ErrShippingMethodUnavailable}
+	b := breaker.New("shipping", breaker.Policy{FailureThreshold: 1}.Ignoring(services.ErrShippingMethodUnavailable))
+	calc := services.NewFallbackRateCalculator(primary, services.NewFlatRateShipping(money.Money{Amount: 999, Currency: "USD"}), b)
+
+	for i := 0; i < 3; i++ {
+		if _, err := calc.GetRate(context.Background(), shipping.RateRequest{}); err != services.ErrShippingMethodUnavailable {
+			t.Fatalf("Expected the provider's answer passed through, got %v", err)
+		}
+	}
+	if status := b.Status(); status.State != breaker.StateClosed || status.Failures != 0 {
+		t.Errorf("Expected ignored errors to leave the breaker closed, got %+v", status)
+	}
+}
@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestPurchaseLimits_CartAndCheckout(t *testing.T) {
+	ctx := context.Background()
+	productID := fixtures.ProductLaptop.ID
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[productID] = fixtures.ProductLaptop
+
+	limitRepo := mocks.NewMockPurchaseLimitRepository()
+	orderRepo := mocks.NewMockOrderRepository()
+	limits := services.NewPurchaseLimitService(limitRepo, orderRepo)
+	if err := limits.SetLimit(ctx, &services.PurchaseLimit{ProductID: productID, MaxPerOrder: 2, MaxPerCustomer: 3, PeriodDays: 30}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil).
+		WithPurchaseLimits(limits)
+	userCart, err := cartService.GetOrCreateCart(ctx, fixtures.TestUserID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{ProductID: productID, Quantity: 2}); err != nil {
+		t.Fatalf("expected two units within the per-order limit, got %v", err)
+	}
+	_, err = cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{ProductID: productID, Quantity: 1})
+	limitErr, ok := err.(*services.PurchaseLimitError)
+	if !ok || limitErr.PerCustomer || limitErr.Limit != 2 {
+		t.Fatalf("expected the per-order limit, got %v", err)
+	}
+
+	// Two units bought last week leave one for this customer this month
+	orderRepo.Orders["order-earlier"] = &orders.Order{
+		ID:        "order-earlier",
+		UserID:    fixtures.TestUserID,
+		Status:    orders.OrderStatusDelivered,
+		Items:     []orders.OrderItem{{ProductID: productID, Quantity: 2}},
+		CreatedAt: time.Now().AddDate(0, 0, -7),
+	}
+	// Canceled orders and purchases before the period don't count
+	orderRepo.Orders["order-canceled"] = &orders.Order{
+		ID:        "order-canceled",
+		UserID:    fixtures.TestUserID,
+		Status:    orders.OrderStatusCanceled,
+		Items:     []orders.OrderItem{{ProductID: productID, Quantity: 2}},
+		CreatedAt: time.Now().AddDate(0, 0, -1),
+	}
+	orderRepo.Orders["order-old"] = &orders.Order{
+		ID:        "order-old",
+		UserID:    fixtures.TestUserID,
+		Status:    orders.OrderStatusDelivered,
+		Items:     []orders.OrderItem{{ProductID: productID, Quantity: 3}},
+		CreatedAt: time.Now().AddDate(0, 0, -60),
+	}
+
+	current, err := cartService.GetCart(ctx, userCart.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil).WithPurchaseLimits(limits)
+	_, err = orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: current, UserID: fixtures.TestUserID})
+	limitErr, ok = err.(*services.PurchaseLimitError)
+	if !ok || !limitErr.PerCustomer || limitErr.Remaining != 1 {
+		t.Fatalf("expected the per-customer limit with 1 remaining, got %v", err)
+	}
+
+	if _, err := cartService.UpdateItemQuantity(ctx, userCart.ID, current.Items[0].ID, 1); err != nil {
+		t.Errorf("expected lowering the quantity to be allowed, got %v", err)
+	}
+	if err := limits.CheckCart(ctx, fixtures.TestUserID, current); err != nil {
+		t.Errorf("expected one more unit to be allowed, got %v", err)
+	}
+
+	// Guests have no history, so only the per-order limit applies
+	if err := limits.Check(ctx, "", map[string]int{productID: 2}); err != nil {
+		t.Errorf("expected guests to be held to the per-order limit only, got %v", err)
+	}
+}
+
+func TestPurchaseLimits_SetLimit(t *testing.T) {
+	ctx := context.Background()
+	limits := services.NewPurchaseLimitService(mocks.NewMockPurchaseLimitRepository(), mocks.NewMockOrderRepository())
+
+	invalid := []*services.PurchaseLimit{
+		{ProductID: "prod-1"},
+		{ProductID: "prod-1", MaxPerOrder: -1, MaxPerCustomer: 5},
+		{ProductID: "prod-1", MaxPerCustomer: 5, PeriodDays: -7},
+		{MaxPerOrder: 2},
+	}
+	for _, limit := range invalid {
+		if err := limits.SetLimit(ctx, limit); err != services.ErrInvalidPurchaseLimit {
+			t.Errorf("expected ErrInvalidPurchaseLimit for %+v, got %v", limit, err)
+		}
+	}
+
+	if err := limits.RemoveLimit(ctx, "prod-1"); err != services.ErrPurchaseLimitNotFound {
+		t.Errorf("expected ErrPurchaseLimitNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newQuotaService(t *testing.T, entries ...string) (*services.QuotaService, *mocks.MockQuotaRepository, *services.APIKeyService) {
+	t.Helper()
+	tiers, err := services.ParseQuotaTiers(entries)
+	if err != nil {
+		t.Fatalf("ParseQuotaTiers failed: %v", err)
+	}
+	repo := mocks.NewMockQuotaRepository()
+	apiKeys := services.NewAPIKeyService(mocks.NewMockAPIKeyRepository())
+	return services.NewQuotaService(repo, tiers).WithAPIKeys(apiKeys), repo, apiKeys
+}
+
+func TestQuota_ParseTiers(t *testing.T) {
+	tiers, err := services.ParseQuotaTiers([]string{"Partner:100000:2000000", " api_key : 1000 : 0 "})
+	if err != nil {
+		t.Fatalf("ParseQuotaTiers failed: %v", err)
+	}
+	if tiers["partner"] != (services.QuotaLimits{Daily: 100000, Monthly: 2000000}) {
+		t.Errorf("Expected partner limits, got %+v", tiers["partner"])
+	}
+	if tiers["api_key"] != (services.QuotaLimits{Daily: 1000}) {
+		t.Errorf("Expected api_key limits, got %+v", tiers["api_key"])
+	}
+
+	for _, entries := range [][]string{{"partner:100"}, {"partner:x:1"}, {"partner:1:-1"}, {":1:1"}, {"a:1:1", "A:2:2"}} {
+		if _, err := services.ParseQuotaTiers(entries); err == nil {
+			t.Errorf("Expected %v rejected", entries)
+		}
+	}
+}
+
+func TestQuota_ConsumeUser(t *testing.T) {
+	service, _, _ := newQuotaService(t, "customer:2:0", "partner:5:100")
+	ctx := context.Background()
+
+	status, err := service.ConsumeUser(ctx, "user-1", []string{"guest"})
+	if status != nil || err != nil {
+		t.Errorf("Expected users without a tier not limited, got %+v, %v", status, err)
+	}
+
+	status, err = service.ConsumeUser(ctx, "user-1", []string{"customer", "Partner"})
+	if err != nil {
+		t.Fatalf("ConsumeUser failed: %v", err)
+	}
+	if status.Tier != "partner" {
+		t.Errorf("Expected the most generous tier, got %s", status.Tier)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.ConsumeUser(ctx, "user-2", []string{"customer"}); err != nil {
+			t.Fatalf("Expected request %d within the quota, got %v", i+1, err)
+		}
+	}
+	status, err = service.ConsumeUser(ctx, "user-2", []string{"customer"})
+	if !errors.Is(err, services.ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	binding := status.Binding()
+	if binding == nil || binding.Period != services.QuotaPeriodDay || binding.Remaining != 0 || binding.Requests != 3 {
+		t.Errorf("Expected the daily window used up, got %+v", binding)
+	}
+
+	if err := service.Reset(ctx, services.UserQuotaSubject("user-2")); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := service.ConsumeUser(ctx, "user-2", []string{"customer"}); err != nil {
+		t.Errorf("Expected the full quota after a reset, got %v", err)
+	}
+}
+
+func TestQuota_APIKeyTierAndUsage(t *testing.T) {
+	service, repo, apiKeys := newQuotaService(t, "api_key:1:0", "partner:10:0")
+	ctx := context.Background()
+
+	key, _, err := apiKeys.CreateKey(ctx, "Warehouse", []services.APIKeyScope{services.APIKeyScopeFulfillmentRead}, "admin-1")
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	if _, err := service.ConsumeAPIKey(ctx, key); err != nil {
+		t.Fatalf("ConsumeAPIKey failed: %v", err)
+	}
+	if _, err := service.ConsumeAPIKey(ctx, key); !errors.Is(err, services.ErrQuotaExceeded) {
+		t.Fatalf("Expected the api_key tier used up, got %v", err)
+	}
+
+	if _, err := service.SetAPIKeyTier(ctx, key.ID, "gold"); err != services.ErrUnknownQuotaTier {
+		t.Errorf("Expected ErrUnknownQuotaTier, got %v", err)
+	}
+	key, err = service.SetAPIKeyTier(ctx, key.ID, "Partner")
+	if err != nil {
+		t.Fatalf("SetAPIKeyTier failed: %v", err)
+	}
+	status, err := service.ConsumeAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("Expected the partner tier to allow more requests, got %v", err)
+	}
+	if status.Tier != "partner" || status.Binding().Remaining != 7 {
+		t.Errorf("Expected 7 partner requests left, got %+v", status.Binding())
+	}
+
+	usage, err := service.APIKeyUsage(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("APIKeyUsage failed: %v", err)
+	}
+	if usage.Tier != "partner" || len(usage.Usage) != 2 || usage.Usage[0].Requests != 3 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+	if _, err := service.APIKeyUsage(ctx, "no-such-key"); err != services.ErrAPIKeyNotFound {
+		t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	counters, total, err := service.ListUsage(ctx, services.QuotaPeriodDay, 10, 0)
+	if err != nil || total != 1 || len(counters) != 1 || counters[0].Requests != 3 {
+		t.Errorf("Expected one daily counter, got %v, %d, %v", counters, total, err)
+	}
+	if _, _, err := service.ListUsage(ctx, "week", 10, 0); err != services.ErrInvalidQuotaPeriod {
+		t.Errorf("Expected ErrInvalidQuotaPeriod, got %v", err)
+	}
+
+	repo.IncrementError = errors.New("database unavailable")
+	if _, err := service.ConsumeAPIKey(ctx, key); err == nil || errors.Is(err, services.ErrQuotaExceeded) {
+		t.Errorf("Expected the repository error, got %v", err)
+	}
+}
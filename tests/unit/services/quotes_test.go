@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newRequestedQuote requests a quote for 10 widgets at $20 and 2 gadgets at $50
+func newRequestedQuote(t *testing.T) (*services.QuoteService, *mocks.MockQuoteRepository, *services.Quote) {
+	repo := mocks.NewMockQuoteRepository()
+	svc := services.NewQuoteService(repo, 7*24*time.Hour)
+
+	buyerCart := &cart.Cart{ID: "cart-1", UserID: "buyer-1", Items: []cart.CartItem{
+		{ID: "item-1", ProductID: "widget", SKU: "W-1", Name: "Widget", Price: usd(2000), Quantity: 10},
+		{ID: "item-2", ProductID: "gadget", SKU: "G-1", Name: "Gadget", Price: usd(5000), Quantity: 2},
+	}}
+	quote, err := svc.RequestQuote(context.Background(), "buyer-1", buyerCart, " Bulk order for Q3 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return svc, repo, quote
+}
+
+func TestQuoteService_RequestQuote(t *testing.T) {
+	svc, _, quote := newRequestedQuote(t)
+
+	if quote.Status != services.QuoteStatusRequested || quote.Note != "Bulk order for Q3" {
+		t.Errorf("expected a requested quote with a trimmed note, got %+v", quote)
+	}
+	if quote.Subtotal != usd(30000) || quote.Items[0].QuotedPrice != quote.Items[0].ListPrice {
+		t.Errorf("expected the quote to start at the cart prices, got %+v", quote)
+	}
+
+	if _, err := svc.RequestQuote(context.Background(), "buyer-1", &cart.Cart{}, ""); err != services.ErrEmptyQuoteRequest {
+		t.Errorf("expected ErrEmptyQuoteRequest, got %v", err)
+	}
+	if _, err := svc.GetUserQuote(context.Background(), "buyer-2", quote.ID); err != services.ErrQuoteNotFound {
+		t.Errorf("expected another buyer's quote to be hidden, got %v", err)
+	}
+	if _, err := svc.QuoteCart(context.Background(), "buyer-1", quote.ID); err != services.ErrQuoteNotSent {
+		t.Errorf("expected ErrQuoteNotSent before sales prices the quote, got %v", err)
+	}
+}
+
+func TestQuoteService_SendQuote(t *testing.T) {
+	ctx := context.Background()
+	svc, _, quote := newRequestedQuote(t)
+	widget := quote.Items[0].ID
+
+	past := time.Now().Add(-time.Hour)
+	for name, offer := range map[string]services.QuoteOffer{
+		"negative price": {Prices: map[string]int64{widget: -1}},
+		"unknown item":   {Prices: map[string]int64{"item-x": 100}},
+		"past validity":  {ValidUntil: &past},
+	} {
+		if _, err := svc.SendQuote(ctx, "sales-1", quote.ID, offer); err != services.ErrInvalidQuoteOffer {
+			t.Errorf("%s: expected ErrInvalidQuoteOffer, got %v", name, err)
+		}
+	}
+
+	sent, err := svc.SendQuote(ctx, "sales-1", quote.ID, services.QuoteOffer{Prices: map[string]int64{widget: 1500}, Note: "10% off widgets"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.Status != services.QuoteStatusSent || sent.SentBy != "sales-1" || sent.ValidUntil == nil {
+		t.Errorf("expected the quote sent with a validity, got %+v", sent)
+	}
+	// Unlisted items keep their price
+	if sent.Items[0].QuotedPrice != usd(1500) || sent.Items[1].QuotedPrice != usd(5000) || sent.Subtotal != usd(25000) {
+		t.Errorf("expected the widget repriced and the subtotal recalculated, got %+v", sent)
+	}
+
+	// The quoted prices are what the buyer checks out at
+	quoted, err := svc.QuoteCart(ctx, "buyer-1", quote.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quoted.ID != quote.ID || len(quoted.Items) != 2 || quoted.Items[0].Price != usd(1500) || quoted.Items[0].Quantity != 10 {
+		t.Errorf("expected a cart at the quoted prices, got %+v", quoted)
+	}
+}
+
+func TestQuoteService_AcceptQuote(t *testing.T) {
+	ctx := context.Background()
+	svc, _, quote := newRequestedQuote(t)
+
+	if _, err := svc.AcceptQuote(ctx, quote.ID, "order-1"); err != services.ErrQuoteNotOpen {
+		t.Errorf("expected an unpriced quote not to be accepted, got %v", err)
+	}
+	if _, err := svc.SendQuote(ctx, "sales-1", quote.ID, services.QuoteOffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	accepted, err := svc.AcceptQuote(ctx, quote.ID, "order-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accepted.Status != services.QuoteStatusAccepted || accepted.OrderID != "order-1" || accepted.AcceptedAt == nil {
+		t.Errorf("expected the quote accepted with its order, got %+v", accepted)
+	}
+
+	// A quote becomes one order only
+	if _, err := svc.AcceptQuote(ctx, quote.ID, "order-2"); err != services.ErrQuoteNotOpen {
+		t.Errorf("expected ErrQuoteNotOpen accepting twice, got %v", err)
+	}
+	if _, err := svc.QuoteCart(ctx, "buyer-1", quote.ID); err != services.ErrQuoteNotOpen {
+		t.Errorf("expected ErrQuoteNotOpen checking out an accepted quote, got %v", err)
+	}
+	if _, err := svc.SendQuote(ctx, "sales-1", quote.ID, services.QuoteOffer{}); err != services.ErrQuoteNotOpen {
+		t.Errorf("expected ErrQuoteNotOpen repricing an accepted quote, got %v", err)
+	}
+}
+
+func TestQuoteService_Expired(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, quote := newRequestedQuote(t)
+	if _, err := svc.SendQuote(ctx, "sales-1", quote.ID, services.QuoteOffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	repo.Quotes[quote.ID].ValidUntil = &past
+	if _, err := svc.QuoteCart(ctx, "buyer-1", quote.ID); err != services.ErrQuoteExpired {
+		t.Errorf("expected ErrQuoteExpired, got %v", err)
+	}
+
+	// Sales can send an expired quote again with a new validity
+	resent, err := svc.SendQuote(ctx, "sales-1", quote.ID, services.QuoteOffer{})
+	if err != nil || resent.IsExpired(time.Now()) {
+		t.Fatalf("expected the quote reopened, got %+v, %v", resent, err)
+	}
+	if _, err := svc.QuoteCart(ctx, "buyer-1", quote.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestQuoteService_DeclineQuote(t *testing.T) {
+	ctx := context.Background()
+	svc, _, quote := newRequestedQuote(t)
+
+	if _, err := svc.DeclineQuote(ctx, "buyer-2", quote.ID); err != services.ErrQuoteNotFound {
+		t.Errorf("expected ErrQuoteNotFound for another buyer, got %v", err)
+	}
+	declined, err := svc.DeclineQuote(ctx, "buyer-1", quote.ID)
+	if err != nil || declined.Status != services.QuoteStatusDeclined {
+		t.Fatalf("expected the quote declined, got %+v, %v", declined, err)
+	}
+	if _, err := svc.DeclineQuote(ctx, "buyer-1", quote.ID); err != services.ErrQuoteNotOpen {
+		t.Errorf("expected ErrQuoteNotOpen declining twice, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type refundFixture struct {
+	service *services.RefundService
+	orders  *services.OrderService
+	refunds *mocks.MockRefundRepository
+	gateway *mocks.MockPaymentGateway
+	order   *orders.Order
+}
+
+// newRefundFixture returns a paid order of $100.00 with two items, captured
+// on a $25.00 gift card and a $75.00 card
+func newRefundFixture(t *testing.T) *refundFixture {
+	ctx := context.Background()
+
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(10000)
+	order.Items = []orders.OrderItem{
+		{ID: "item-1", Quantity: 2, Total: usd(6000)},
+		{ID: "item-2", Quantity: 1, Total: usd(4000)},
+	}
+	orderRepo.Orders[order.ID] = order
+
+	gateway := mocks.NewMockPaymentGateway()
+	paymentRepo := mocks.NewMockPaymentRepository()
+	paymentService := services.NewPaymentService(paymentRepo, gateway)
+	if _, err := paymentService.ChargeTenders(ctx, order, []services.TenderRequest{
+		{Type: services.TenderTypeGiftCard, PaymentMethodID: "gc_1", Amount: usd(2500)},
+		{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(7500)},
+	}); err != nil {
+		t.Fatalf("failed to charge tenders: %v", err)
+	}
+	order.Status = orders.OrderStatusPaid
+
+	refunds := mocks.NewMockRefundRepository()
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+
+	return &refundFixture{
+		service: services.NewRefundService(refunds, paymentService, paymentRepo, orderService),
+		orders:  orderService,
+		refunds: refunds,
+		gateway: gateway,
+		order:   order,
+	}
+}
+
+func TestRefundService_CreateRefund(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("partial amount is refunded to the most recent tender", func(t *testing.T) {
+		f := newRefundFixture(t)
+		amount := usd(3000)
+
+		refund, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{Amount: &amount})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(refund.Allocations) != 1 || refund.Allocations[0].Amount != usd(3000) {
+			t.Errorf("expected a single allocation of 3000, got %+v", refund.Allocations)
+		}
+		if len(f.gateway.Refunds) != 1 || f.gateway.Refunds[0].Amount != usd(3000) {
+			t.Errorf("expected one gateway refund of 3000, got %+v", f.gateway.Refunds)
+		}
+		if f.order.Status != orders.OrderStatusPaid {
+			t.Errorf("expected order to stay paid, got %s", f.order.Status)
+		}
+
+		totals, err := f.service.Totals(ctx, f.order)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if totals.Refunded != usd(3000) || totals.Net != usd(7000) {
+			t.Errorf("expected refunded 3000 and net 7000, got %d and %d", totals.Refunded.Amount, totals.Net.Amount)
+		}
+	})
+
+	t.Run("lines are refunded pro rata", func(t *testing.T) {
+		f := newRefundFixture(t)
+
+		refund, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{
+			Lines: []services.RefundLineRequest{{OrderItemID: "item-1", Quantity: 1}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if refund.Amount != usd(3000) {
+			t.Errorf("expected refund of 3000, got %d", refund.Amount.Amount)
+		}
+
+		_, err = f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{
+			Lines: []services.RefundLineRequest{{OrderItemID: "item-1", Quantity: 2}},
+		})
+		if err != services.ErrRefundQuantityExceeded {
+			t.Errorf("expected ErrRefundQuantityExceeded, got %v", err)
+		}
+
+		_, err = f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{
+			Lines: []services.RefundLineRequest{{OrderItemID: "item-9", Quantity: 1}},
+		})
+		if err != services.ErrRefundItemNotFound {
+			t.Errorf("expected ErrRefundItemNotFound, got %v", err)
+		}
+	})
+
+	t.Run("full refund spans tenders and marks the order refunded", func(t *testing.T) {
+		f := newRefundFixture(t)
+		amount := usd(1000)
+		if _, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{Amount: &amount}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		refund, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if refund.Amount != usd(9000) || len(refund.Allocations) != 2 {
+			t.Errorf("expected 9000 across two tenders, got %d across %d", refund.Amount.Amount, len(refund.Allocations))
+		}
+		if f.order.Status != orders.OrderStatusRefunded {
+			t.Errorf("expected order to be refunded, got %s", f.order.Status)
+		}
+
+		if _, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{}); err != services.ErrOrderNotRefundable {
+			t.Errorf("expected ErrOrderNotRefundable, got %v", err)
+		}
+	})
+
+	t.Run("rejects amounts above what is left to refund", func(t *testing.T) {
+		f := newRefundFixture(t)
+		amount := usd(10001)
+
+		if _, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{Amount: &amount}); err != services.ErrRefundExceedsPaid {
+			t.Errorf("expected ErrRefundExceedsPaid, got %v", err)
+		}
+		if len(f.gateway.Refunds) != 0 || len(f.refunds.Refunds) != 0 {
+			t.Errorf("expected nothing to be refunded")
+		}
+	})
+
+	t.Run("rejects unpaid orders", func(t *testing.T) {
+		f := newRefundFixture(t)
+		f.order.Status = orders.OrderStatusPending
+
+		if _, err := f.service.CreateRefund(ctx, f.order.ID, services.CreateRefundRequest{}); err != services.ErrOrderNotRefundable {
+			t.Errorf("expected ErrOrderNotRefundable, got %v", err)
+		}
+	})
+}
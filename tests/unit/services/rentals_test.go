@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// rentalDate returns the YYYY-MM-DD date days from today
+func rentalDate(days int) string {
+	return time.Now().UTC().AddDate(0, 0, days).Format("2006-01-02")
+}
+
+func newRentalService(t *testing.T) (*services.RentalService, *mocks.MockRentalRepository, *mocks.MockProductRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	repo := mocks.NewMockRentalRepository()
+	rentals := services.NewRentalService(repo, productRepo, mocks.NewMockVariantRepository())
+	rental := &services.RentalProduct{ProductID: fixtures.ProductLaptop.ID, Units: 2, MinDays: 2, MaxDays: 14, BufferDays: 1}
+	if err := rentals.SetRental(context.Background(), rental); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rentals, repo, productRepo
+}
+
+func TestRentals_CartItemPeriod(t *testing.T) {
+	ctx := context.Background()
+	rentals, _, productRepo := newRentalService(t)
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil).
+		WithRentals(rentals)
+	userCart, err := cartService.GetOrCreateCart(ctx, fixtures.TestUserID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	add := func(start, end string, quantity int) (*cart.Cart, error) {
+		return cartService.AddItem(ctx, userCart.ID, cart.AddItemRequest{
+			ProductID:  fixtures.ProductLaptop.ID,
+			Quantity:   quantity,
+			Attributes: map[string]string{services.RentalStartAttribute: start, services.RentalEndAttribute: end},
+		})
+	}
+
+	if _, err := add("", "", 1); !errors.Is(err, services.ErrRentalPeriodRequired) {
+		t.Errorf("expected ErrRentalPeriodRequired, got %v", err)
+	}
+	invalid := [][2]string{
+		{rentalDate(-1), rentalDate(2)}, // started yesterday
+		{rentalDate(5), rentalDate(4)},  // ends before it starts
+		{rentalDate(5), rentalDate(5)},  // shorter than the minimum
+		{rentalDate(5), rentalDate(30)}, // longer than the maximum
+		{"next week", rentalDate(10)},   // not a date
+	}
+	for _, period := range invalid {
+		if _, err := add(period[0], period[1], 1); !errors.Is(err, services.ErrInvalidRentalPeriod) {
+			t.Errorf("expected ErrInvalidRentalPeriod for %v, got %v", period, err)
+		}
+	}
+	var conflictErr *services.RentalConflictError
+	if _, err := add(rentalDate(5), rentalDate(7), 3); !errors.As(err, &conflictErr) || conflictErr.Available != 2 {
+		t.Errorf("expected a conflict with 2 units available, got %v", err)
+	}
+
+	updated, err := add(rentalDate(5), rentalDate(7), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := updated.Items[0]
+	if item.Attributes[services.RentalDaysAttribute] != "3" {
+		t.Errorf("expected the period recorded as 3 days, got %v", item.Attributes)
+	}
+	if want := (money.Money{Amount: 3 * fixtures.ProductLaptop.BasePrice.Amount, Currency: "USD"}); item.Price != want {
+		t.Errorf("expected the item priced at 3 daily rates, got %v", item.Price)
+	}
+
+	if _, err := add(rentalDate(8), rentalDate(10), 1); !errors.Is(err, services.ErrRentalInCart) {
+		t.Errorf("expected ErrRentalInCart, got %v", err)
+	}
+	if _, err := cartService.UpdateItemQuantity(ctx, userCart.ID, item.ID, 3); !errors.As(err, &conflictErr) {
+		t.Errorf("expected a conflict raising the quantity over the units, got %v", err)
+	}
+	if _, err := cartService.UpdateItemQuantity(ctx, userCart.ID, item.ID, 2); err != nil {
+		t.Errorf("expected both units to be rentable, got %v", err)
+	}
+}
+
+func TestRentals_BookingsBlockDatesUntilCanceled(t *testing.T) {
+	ctx := context.Background()
+	rentals, repo, _ := newRentalService(t)
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil).WithRentals(rentals)
+
+	rentalCart := func(start, end string, quantity int) *cart.Cart {
+		c := &cart.Cart{ID: "cart-" + start, UserID: fixtures.TestUserID}
+		c.AddItem(cart.CartItem{
+			ID: "item-1", ProductID: fixtures.ProductLaptop.ID, SKU: fixtures.ProductLaptop.SKU,
+			Price: money.Money{Amount: 30000, Currency: "USD"}, Quantity: quantity,
+			Attributes: map[string]string{services.RentalStartAttribute: start, services.RentalEndAttribute: end},
+		})
+		return c
+	}
+	address := orders.Address{FirstName: "Jane", LastName: "Doe", AddressLine1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"}
+
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: rentalCart(rentalDate(5), rentalDate(7), 2), UserID: fixtures.TestUserID, ShippingAddress: address})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Bookings) != 1 || repo.Bookings[0].OrderID != order.ID || repo.Bookings[0].Quantity != 2 {
+		t.Fatalf("expected the order's rental to be booked, got %+v", repo.Bookings)
+	}
+	if order.Items[0].Attributes[services.RentalStartAttribute] != rentalDate(5) {
+		t.Errorf("expected the rental period on the order line, got %v", order.Items[0].Attributes)
+	}
+
+	calendar, err := rentals.Calendar(ctx, fixtures.ProductLaptop.ID, "", rentalDate(4), rentalDate(9))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Days 5 to 7 are booked, and day 8 is the buffer day after the rental
+	want := []int{2, 0, 0, 0, 0, 2}
+	for i, day := range calendar.Days {
+		if day.Available != want[i] {
+			t.Errorf("expected %d units free on %s, got %d", want[i], day.Date, day.Available)
+		}
+	}
+
+	// A rental starting on the buffer day conflicts; one starting the day after doesn't
+	var conflictErr *services.RentalConflictError
+	_, err = orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: rentalCart(rentalDate(8), rentalDate(9), 1), UserID: fixtures.TestUserID, ShippingAddress: address})
+	if !errors.As(err, &conflictErr) || conflictErr.Available != 0 {
+		t.Errorf("expected a conflict with no units available, got %v", err)
+	}
+	if err := rentals.CheckCart(ctx, rentalCart(rentalDate(9), rentalDate(10), 2)); err != nil {
+		t.Errorf("expected the dates after the buffer to be free, got %v", err)
+	}
+
+	if _, err := orderService.CancelOrder(ctx, order.ID, "changed plans"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Bookings) != 0 {
+		t.Errorf("expected canceling the order to free its dates, got %+v", repo.Bookings)
+	}
+}
+
+func TestRentals_SettingsAndCalendarRange(t *testing.T) {
+	ctx := context.Background()
+	rentals, _, _ := newRentalService(t)
+
+	invalid := []*services.RentalProduct{
+		{ProductID: fixtures.ProductLaptop.ID},
+		{ProductID: fixtures.ProductLaptop.ID, Units: 1, MinDays: 5, MaxDays: 3},
+		{ProductID: fixtures.ProductLaptop.ID, Units: 1, BufferDays: 31},
+		{ProductID: fixtures.ProductLaptop.ID, Units: 1, SKUUnits: map[string]int{"OTHER-SKU": 1}},
+	}
+	for _, rental := range invalid {
+		if err := rentals.SetRental(ctx, rental); !errors.Is(err, services.ErrInvalidRental) {
+			t.Errorf("expected ErrInvalidRental for %+v, got %v", rental, err)
+		}
+	}
+	if err := rentals.SetRental(ctx, &services.RentalProduct{ProductID: "prod-missing", Units: 1}); !errors.Is(err, services.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	calendar, err := rentals.Calendar(ctx, fixtures.ProductLaptop.ID, "", "", "")
+	if err != nil || len(calendar.Days) != 30 || calendar.Days[0].Date != rentalDate(0) {
+		t.Errorf("expected the next 30 days by default, got %+v (%v)", calendar, err)
+	}
+	for _, r := range [][2]string{{rentalDate(-1), ""}, {rentalDate(0), rentalDate(100)}, {"tomorrow", ""}} {
+		if _, err := rentals.Calendar(ctx, fixtures.ProductLaptop.ID, "", r[0], r[1]); !errors.Is(err, services.ErrInvalidCalendarRange) {
+			t.Errorf("expected ErrInvalidCalendarRange for %v, got %v", r, err)
+		}
+	}
+	if _, err := rentals.Calendar(ctx, fixtures.ProductLaptop.ID, "OTHER-SKU", "", ""); !errors.Is(err, services.ErrRentalSKUNotFound) {
+		t.Errorf("expected ErrRentalSKUNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestRetention_DryRunChangesNothing(t *testing.T) {
+	repo := mocks.NewMockRetentionRepository()
+	repo.Affected["guest-carts"] = 12
+	repo.Affected["webhook-payloads"] = 40
+	retention := services.NewRetentionService(repo, services.RetentionPolicy{
+		GuestCarts:      90 * 24 * time.Hour,
+		WebhookPayloads: 30 * 24 * time.Hour,
+	})
+
+	report, err := retention.Run(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun {
+		t.Error("expected the report to be marked as a dry run")
+	}
+	if len(repo.Applied) != 0 {
+		t.Errorf("expected a dry run to change nothing, got %v", repo.Applied)
+	}
+
+	// IP addresses are kept forever, so that rule doesn't run
+	if len(report.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", report.Rules)
+	}
+	if _, ok := repo.Before["ip-addresses"]; ok {
+		t.Error("expected the disabled IP address rule to be skipped")
+	}
+	if rule := report.Rules[0]; rule.Rule != "guest-carts" || rule.Affected != 12 {
+		t.Errorf("unexpected guest cart result: %+v", rule)
+	}
+	if rule := report.Rules[1]; rule.Rule != "webhook-payloads" || rule.Affected != 40 {
+		t.Errorf("unexpected webhook payload result: %+v", rule)
+	}
+}
+
+func TestRetention_RunAppliesCutoffs(t *testing.T) {
+	repo := mocks.NewMockRetentionRepository()
+	retention := services.NewRetentionService(repo, services.RetentionPolicy{
+		GuestCarts:      90 * 24 * time.Hour,
+		WebhookPayloads: 30 * 24 * time.Hour,
+		IPAddresses:     7 * 24 * time.Hour,
+	})
+
+	if _, err := retention.Run(context.Background(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]time.Duration{
+		"guest-carts":      90 * 24 * time.Hour,
+		"webhook-payloads": 30 * 24 * time.Hour,
+		"ip-addresses":     7 * 24 * time.Hour,
+	}
+	for rule, period := range expected {
+		if !repo.Applied[rule] {
+			t.Errorf("expected %s to be applied", rule)
+		}
+		if age := time.Since(repo.Before[rule]); age < period || age > period+time.Minute {
+			t.Errorf("expected %s to apply to data older than %v, got %v", rule, period, age)
+		}
+	}
+}
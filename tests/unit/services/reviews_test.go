@@ -0,0 +1,212 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newReviewService(t *testing.T, maxPhotos int) (*services.ReviewService, *mocks.MockReviewRepository, *mocks.MockMediaStorage) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	repo := mocks.NewMockReviewRepository()
+	media := mocks.NewMockMediaStorage()
+	filter := services.NewProfanityFilter([]string{"rubbish"})
+	return services.NewReviewService(repo, productRepo, media, filter, maxPhotos, 64), repo, media
+}
+
+func writeReview(t *testing.T, svc *services.ReviewService, userID, productID string, rating int) *services.Review {
+	t.Helper()
+	review, err := svc.WriteReview(context.Background(), userID, services.WriteReviewRequest{
+		ProductID: productID,
+		Rating:    rating,
+		Title:     "Review",
+		Body:      "Does what it says",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return review
+}
+
+func TestProfanityFilter_Clean(t *testing.T) {
+	filter := services.NewProfanityFilter([]string{" Rubbish "})
+
+	tests := []struct {
+		text     string
+		expected string
+		masked   bool
+	}{
+		{"Works great", "Works great", false},
+		{"This is SHIT, really", "This is ****, really", true},
+		{"Total rubbish.", "Total *******.", true},
+		{"Made in Scunthorpe, a classic", "Made in Scunthorpe, a classic", false},
+		{"shit-tier fuck", "****-tier ****", true},
+	}
+	for _, tt := range tests {
+		cleaned, masked := filter.Clean(tt.text)
+		if cleaned != tt.expected || masked != tt.masked {
+			t.Errorf("Clean(%q) = %q, %v; expected %q, %v", tt.text, cleaned, masked, tt.expected, tt.masked)
+		}
+	}
+}
+
+func TestReviewService_WriteReview(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newReviewService(t, 2)
+
+	review, err := svc.WriteReview(ctx, fixtures.TestUserID, services.WriteReviewRequest{
+		ProductID: fixtures.ProductLaptop.ID,
+		Rating:    4,
+		Title:     " Fast ",
+		Body:      "Fast, but the fan is shit",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.Status != services.ReviewStatusPending {
+		t.Errorf("expected a pending review, got %s", review.Status)
+	}
+	if review.Title != "Fast" || review.Body != "Fast, but the fan is ****" || !review.Filtered {
+		t.Errorf("expected a trimmed title and masked body flagged as filtered, got %+v", review)
+	}
+
+	if _, err := svc.WriteReview(ctx, fixtures.TestUserID, services.WriteReviewRequest{
+		ProductID: fixtures.ProductLaptop.ID, Rating: 5, Body: "Again",
+	}); err != services.ErrReviewExists {
+		t.Errorf("expected ErrReviewExists for a second review, got %v", err)
+	}
+
+	for name, req := range map[string]services.WriteReviewRequest{
+		"rating too low":  {ProductID: fixtures.ProductTShirt.ID, Rating: 0, Body: "Fine"},
+		"rating too high": {ProductID: fixtures.ProductTShirt.ID, Rating: 6, Body: "Fine"},
+		"empty body":      {ProductID: fixtures.ProductTShirt.ID, Rating: 3, Body: "  "},
+		"long title":      {ProductID: fixtures.ProductTShirt.ID, Rating: 3, Title: strings.Repeat("a", 256), Body: "Fine"},
+	} {
+		if _, err := svc.WriteReview(ctx, fixtures.TestUserID, req); !errors.Is(err, services.ErrInvalidReview) {
+			t.Errorf("%s: expected ErrInvalidReview, got %v", name, err)
+		}
+	}
+	if _, err := svc.WriteReview(ctx, fixtures.TestUserID, services.WriteReviewRequest{
+		ProductID: "missing", Rating: 3, Body: "Fine",
+	}); err != services.ErrProductNotFound {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestReviewService_Photos(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, media := newReviewService(t, 2)
+	review := writeReview(t, svc, fixtures.TestUserID, fixtures.ProductLaptop.ID, 5)
+	if _, err := svc.Moderate(ctx, review.ID, "moderator", services.ReviewStatusApproved, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.AddPhoto(ctx, "someone-else", review.ID, bytes.NewReader(pngHeader)); err != services.ErrReviewNotFound {
+		t.Errorf("expected other users' reviews reported as not found, got %v", err)
+	}
+	if _, err := svc.AddPhoto(ctx, fixtures.TestUserID, review.ID, strings.NewReader("not an image")); err != services.ErrInvalidReviewPhoto {
+		t.Errorf("expected ErrInvalidReviewPhoto for a text file, got %v", err)
+	}
+	if _, err := svc.AddPhoto(ctx, fixtures.TestUserID, review.ID, bytes.NewReader(append(pngHeader, make([]byte, 64)...))); err != services.ErrInvalidReviewPhoto {
+		t.Errorf("expected ErrInvalidReviewPhoto for an oversized image, got %v", err)
+	}
+
+	withPhoto, err := svc.AddPhoto(ctx, fixtures.TestUserID, review.ID, bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	photo := withPhoto.Photos[0]
+	if !strings.HasPrefix(photo.URL, "/media/reviews/"+review.ID+"/") || !strings.HasSuffix(photo.URL, ".png") {
+		t.Errorf("expected a PNG under the review's folder, got %s", photo.URL)
+	}
+	if _, ok := media.Files[photo.Key]; !ok {
+		t.Errorf("expected the photo stored under %s", photo.Key)
+	}
+	if withPhoto.Status != services.ReviewStatusPending || repo.Reviews[review.ID].Status != services.ReviewStatusPending {
+		t.Errorf("expected a new photo to send the approved review back to moderation, got %s", withPhoto.Status)
+	}
+
+	if _, err := svc.AddPhoto(ctx, fixtures.TestUserID, review.ID, bytes.NewReader(pngHeader)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.AddPhoto(ctx, fixtures.TestUserID, review.ID, bytes.NewReader(pngHeader)); err != services.ErrReviewPhotoLimit {
+		t.Errorf("expected ErrReviewPhotoLimit past two photos, got %v", err)
+	}
+
+	if _, err := svc.RemovePhoto(ctx, "someone-else", false, review.ID, photo.ID); err != services.ErrReviewNotFound {
+		t.Errorf("expected other users unable to remove the photo, got %v", err)
+	}
+	removed, err := svc.RemovePhoto(ctx, "moderator", true, review.ID, photo.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed.Photos) != 1 || len(media.Files) != 1 {
+		t.Errorf("expected one photo left in the review and in storage, got %d and %d", len(removed.Photos), len(media.Files))
+	}
+	if _, err := svc.RemovePhoto(ctx, fixtures.TestUserID, false, review.ID, photo.ID); err != services.ErrReviewPhotoNotFound {
+		t.Errorf("expected ErrReviewPhotoNotFound for a removed photo, got %v", err)
+	}
+}
+
+func TestReviewService_Moderation(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newReviewService(t, 2)
+	first := writeReview(t, svc, "user-1", fixtures.ProductLaptop.ID, 5)
+	second := writeReview(t, svc, "user-2", fixtures.ProductLaptop.ID, 2)
+	third := writeReview(t, svc, "user-3", fixtures.ProductLaptop.ID, 4)
+
+	product, err := svc.ProductReviews(ctx, fixtures.ProductLaptop.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.ReviewCount != 0 || product.AverageRating != 0 {
+		t.Errorf("expected pending reviews hidden from the product, got %+v", product)
+	}
+
+	result, err := svc.ModerateBulk(ctx, []string{first.ID, second.ID, "missing", first.ID}, "moderator", services.ReviewStatusApproved, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Updated) != 2 || len(result.NotFound) != 1 || result.NotFound[0] != "missing" {
+		t.Errorf("expected two approved reviews and one not found, got %+v", result)
+	}
+
+	rejected, err := svc.Moderate(ctx, third.ID, "moderator", services.ReviewStatusRejected, " Off topic ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rejected.ModerationNote != "Off topic" || rejected.ModeratedBy != "moderator" || rejected.ModeratedAt == nil {
+		t.Errorf("expected the moderator and note recorded, got %+v", rejected)
+	}
+
+	product, err = svc.ProductReviews(ctx, fixtures.ProductLaptop.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.ReviewCount != 2 || product.AverageRating != 3.5 {
+		t.Errorf("expected two approved reviews averaging 3.5, got %d averaging %v", product.ReviewCount, product.AverageRating)
+	}
+
+	queue, err := svc.ListReviews(ctx, services.ReviewStatusRejected)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue) != 1 || queue[0].ID != third.ID {
+		t.Errorf("expected the rejected review in the queue, got %d reviews", len(queue))
+	}
+	if _, err := svc.ListReviews(ctx, "hidden"); !errors.Is(err, services.ErrInvalidReview) {
+		t.Errorf("expected ErrInvalidReview for an unknown status, got %v", err)
+	}
+	if _, err := svc.ModerateBulk(ctx, []string{first.ID}, "moderator", "hidden", ""); !errors.Is(err, services.ErrInvalidReview) {
+		t.Errorf("expected ErrInvalidReview for an unknown bulk status, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+var initialRuntimeSettings = services.RuntimeSettings{
+	BotThrottlePerMinute: 60,
+	LoadShedMaxInFlight:  500,
+	LoadShedMaxLatency:   2 * time.Second,
+	TaxRate:              0.0875,
+	FallbackTaxRate:      0.0875,
+	SpellCorrection:      true,
+}
+
+// newRuntimeConfigFixture returns a service whose reloads read *next, or fail with *loadErr
+func newRuntimeConfigFixture(next *services.RuntimeSettings, loadErr *error) (*services.RuntimeConfigService, *mocks.MockConfigChangeRepository) {
+	repo := mocks.NewMockConfigChangeRepository()
+	svc := services.NewRuntimeConfigService(repo, initialRuntimeSettings, func() (services.RuntimeSettings, error) {
+		if *loadErr != nil {
+			return services.RuntimeSettings{}, *loadErr
+		}
+		return *next, nil
+	})
+	return svc, repo
+}
+
+func TestRuntimeConfigService_Reload(t *testing.T) {
+	ctx := context.Background()
+	next := initialRuntimeSettings
+	var loadErr error
+	svc, repo := newRuntimeConfigFixture(&next, &loadErr)
+
+	var applied []services.RuntimeSettings
+	svc.OnChange(func(settings services.RuntimeSettings) { applied = append(applied, settings) })
+
+	// Nothing changed: nothing audited or applied
+	changes, err := svc.Reload(ctx, services.ConfigChangeSourceSignal, nil)
+	if err != nil || len(changes) != 0 || len(applied) != 0 || svc.ReloadedAt() != nil {
+		t.Fatalf("expected an unchanged reload to do nothing, got %v, %v", changes, err)
+	}
+
+	next.MaintenanceMode = true
+	next.MaintenanceMessage = "Back at 10:00 UTC"
+	next.TaxRate = 0.07
+	adminID := "admin-1"
+	changes, err = svc.Reload(ctx, services.ConfigChangeSourceAdmin, &adminID)
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	want := map[string][2]string{
+		"MAINTENANCE_MESSAGE": {"", "Back at 10:00 UTC"},
+		"MAINTENANCE_MODE":    {"false", "true"},
+		"TAX_RATE":            {"0.0875", "0.07"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for _, change := range changes {
+		values, ok := want[change.Setting]
+		if !ok || change.OldValue != values[0] || change.NewValue != values[1] {
+			t.Errorf("unexpected change %+v", change)
+		}
+		if change.Source != services.ConfigChangeSourceAdmin || change.ActorID == nil || *change.ActorID != adminID {
+			t.Errorf("expected the change attributed to the admin, got %+v", change)
+		}
+	}
+	if len(repo.Changes) != 3 {
+		t.Errorf("expected the changes audited, got %d", len(repo.Changes))
+	}
+	if len(applied) != 1 || applied[0].TaxRate != 0.07 || !svc.Current().MaintenanceMode || svc.ReloadedAt() == nil {
+		t.Errorf("expected the new settings applied, got %+v", applied)
+	}
+
+	listed, err := svc.ListChanges(ctx, services.ConfigChangeFilter{Setting: "TAX_RATE"})
+	if err != nil || len(listed) != 1 || listed[0].NewValue != "0.07" {
+		t.Errorf("expected the tax rate change listed, got %+v, %v", listed, err)
+	}
+}
+
+func TestRuntimeConfigService_ReloadRejected(t *testing.T) {
+	ctx := context.Background()
+	next := initialRuntimeSettings
+	next.BotThrottlePerMinute = 10
+	loadErr := errors.New("TAX_RATE and FALLBACK_TAX_RATE must be at least 0 and below 1")
+	svc, repo := newRuntimeConfigFixture(&next, &loadErr)
+
+	hooked := false
+	svc.OnChange(func(services.RuntimeSettings) { hooked = true })
+
+	// An invalid configuration changes nothing
+	if _, err := svc.Reload(ctx, services.ConfigChangeSourceSignal, nil); !errors.Is(err, services.ErrInvalidRuntimeConfig) {
+		t.Errorf("expected ErrInvalidRuntimeConfig, got %v", err)
+	}
+
+	// Neither does one whose changes can't be audited
+	loadErr = nil
+	repo.CreateError = errors.New("connection reset")
+	if _, err := svc.Reload(ctx, services.ConfigChangeSourceSignal, nil); err == nil {
+		t.Error("expected the reload to fail when changes can't be recorded")
+	}
+
+	if hooked || svc.Current().BotThrottlePerMinute != 60 {
+		t.Errorf("expected the settings unchanged, got %+v", svc.Current())
+	}
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newSearchRuleProducts() nameSearchProductRepository {
+	products := nameSearchProductRepository{mocks.NewMockProductRepository()}
+	for _, product := range []*catalog.Product{fixtures.ProductLaptop, fixtures.ProductPhone, fixtures.ProductTShirt, fixtures.ProductInactive} {
+		products.Products[product.ID] = product
+	}
+	products.Products["prod-sleeve-001"] = &catalog.Product{ID: "prod-sleeve-001", Name: "Laptop Sleeve", Status: fixtures.StatusActive}
+	return products
+}
+
+func productIDs(products []*services.ProductResponse) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
+
+func TestSearchRuleService_Save(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSearchRuleRepository()
+	rules := services.NewSearchRuleService(repo, newSearchRuleProducts())
+
+	set := &services.SynonymSet{Terms: []string{" Tee ", "T-Shirt", "tee", ""}}
+	if err := rules.SaveSynonymSet(ctx, set); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if set.ID == "" || !reflect.DeepEqual(set.Terms, []string{"tee", "t-shirt"}) {
+		t.Errorf("expected the terms normalized and deduplicated, got %q", set.Terms)
+	}
+	if err := rules.SaveSynonymSet(ctx, &services.SynonymSet{Terms: []string{"tee", "TEE"}}); !errors.Is(err, services.ErrInvalidSynonymSet) {
+		t.Errorf("expected a set with one distinct term to be invalid, got %v", err)
+	}
+	if err := rules.SaveSynonymSet(ctx, &services.SynonymSet{ID: "missing", Terms: []string{"a", "b"}}); !errors.Is(err, services.ErrSynonymSetNotFound) {
+		t.Errorf("expected updating a missing set to fail, got %v", err)
+	}
+
+	rule := &services.SearchRule{Query: "  Laptop ", PinnedProductIDs: []string{fixtures.ProductPhone.ID, fixtures.ProductPhone.ID}, IsActive: true}
+	if err := rules.SaveRule(ctx, rule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Query != "laptop" || len(rule.PinnedProductIDs) != 1 {
+		t.Errorf("expected the query and products normalized, got %q %v", rule.Query, rule.PinnedProductIDs)
+	}
+
+	invalid := []*services.SearchRule{
+		{Query: "laptop", BoostedProductIDs: []string{fixtures.ProductLaptop.ID}},
+		{Query: "phone"},
+		{Query: "phone", PinnedProductIDs: []string{"prod-missing"}},
+		{Query: " ", PinnedProductIDs: []string{fixtures.ProductPhone.ID}},
+	}
+	for _, rule := range invalid {
+		if err := rules.SaveRule(ctx, rule); !errors.Is(err, services.ErrInvalidSearchRule) {
+			t.Errorf("expected rule %+v to be invalid, got %v", rule, err)
+		}
+	}
+
+	// Updating a rule keeps its creation time and may keep its own query
+	createdAt := rule.CreatedAt
+	update := &services.SearchRule{ID: rule.ID, Query: "laptop", BoostedProductIDs: []string{fixtures.ProductLaptop.ID}}
+	if err := rules.SaveRule(ctx, update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !update.CreatedAt.Equal(createdAt) || len(repo.Rules) != 1 {
+		t.Errorf("expected the rule replaced in place, got %d rules", len(repo.Rules))
+	}
+}
+
+func TestSearchRuleService_Plan(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockSearchRuleRepository()
+	rules := services.NewSearchRuleService(repo, newSearchRuleProducts()).WithCache(time.Minute)
+
+	if err := rules.SaveSynonymSet(ctx, &services.SynonymSet{Terms: []string{"tee", "t-shirt", "tshirt"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rules.SaveRule(ctx, &services.SearchRule{Query: "red tee", PinnedProductIDs: []string{fixtures.ProductTShirt.ID}, IsActive: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rules.SaveRule(ctx, &services.SearchRule{Query: "laptop", PinnedProductIDs: []string{fixtures.ProductPhone.ID}, IsActive: false}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := rules.Plan(ctx, "Red  TEE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(plan.Keywords, []string{"red tee", "red t-shirt", "red tshirt"}) {
+		t.Errorf("expected the synonyms expanded, got %q", plan.Keywords)
+	}
+	if !reflect.DeepEqual(plan.Pinned, []string{fixtures.ProductTShirt.ID}) {
+		t.Errorf("expected the rule's pinned product, got %v", plan.Pinned)
+	}
+
+	// Synonyms only replace whole words, and inactive rules do not apply
+	for _, keyword := range []string{"teepee", "laptop"} {
+		plan, err := rules.Plan(ctx, keyword)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(plan.Keywords) != 1 || len(plan.Pinned) != 0 {
+			t.Errorf("expected %q to be searched as is, got %+v", keyword, plan)
+		}
+	}
+
+	// The cache is reloaded only after a change
+	if repo.FindSynonymSetsCalls != 1 {
+		t.Errorf("expected the synonyms loaded once, got %d loads", repo.FindSynonymSetsCalls)
+	}
+	if err := rules.SaveSynonymSet(ctx, &services.SynonymSet{Terms: []string{"laptop", "notebook"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan, _ := rules.Plan(ctx, "laptop"); len(plan.Keywords) != 2 || repo.FindSynonymSetsCalls != 2 {
+		t.Errorf("expected a saved set to apply at once, got %q after %d loads", plan.Keywords, repo.FindSynonymSetsCalls)
+	}
+}
+
+func TestCatalogService_MerchandisedSearch(t *testing.T) {
+	ctx := context.Background()
+	products := newSearchRuleProducts()
+	repo := mocks.NewMockSearchRuleRepository()
+	rules := services.NewSearchRuleService(repo, products)
+	svc := services.NewCatalogService(products, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithSearchRules(rules)
+	active := fixtures.StatusActive
+
+	if err := rules.SaveSynonymSet(ctx, &services.SynonymSet{Terms: []string{"tee", "t-shirt"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := svc.SearchProducts(ctx, "tee", catalog.ProductFilter{Status: &active})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(productIDs(results), []string{fixtures.ProductTShirt.ID}) {
+		t.Errorf("expected a synonym search to find the t-shirt, got %v", productIDs(results))
+	}
+
+	// Pinned products come first in order, inactive ones are skipped, then boosted matches
+	if err := rules.SaveRule(ctx, &services.SearchRule{
+		Query:             "laptop",
+		PinnedProductIDs:  []string{fixtures.ProductInactive.ID, fixtures.ProductPhone.ID},
+		BoostedProductIDs: []string{"prod-sleeve-001"},
+		IsActive:          true,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err = svc.SearchProducts(ctx, "Laptop", catalog.ProductFilter{Status: &active})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{fixtures.ProductPhone.ID, "prod-sleeve-001", fixtures.ProductLaptop.ID}
+	if !reflect.DeepEqual(productIDs(results), expected) {
+		t.Errorf("expected %v, got %v", expected, productIDs(results))
+	}
+
+	// Pages run through the pinned products and on into the matches
+	pages := [][]string{}
+	for offset := 0; offset < 4; offset += 2 {
+		page, err := svc.SearchProducts(ctx, "laptop", catalog.ProductFilter{Status: &active, Limit: 2, Offset: offset})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pages = append(pages, productIDs(page))
+	}
+	if !reflect.DeepEqual(pages, [][]string{expected[:2], expected[2:]}) {
+		t.Errorf("expected pages %v and %v, got %v", expected[:2], expected[2:], pages)
+	}
+
+	// Rules that cannot be loaded leave the search as it is
+	repo.FindSynonymSetsError = errors.New("database error")
+	results, err = svc.SearchProducts(ctx, "laptop", catalog.ProductFilter{Status: &active})
+	if err != nil || len(results) != 2 {
+		t.Errorf("expected the plain search to run, got %v (%v)", productIDs(results), err)
+	}
+}
@@ -0,0 +1,52 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestSuggestionService_Suggest(t *testing.T) {
+	repo := mocks.NewMockSuggestionRepository(
+		&services.Suggestion{Type: services.SuggestProduct, ID: "p-1", Text: "Gaming Laptop Pro"},
+		&services.Suggestion{Type: services.SuggestProduct, ID: "p-2", Text: "Laptop Sleeve 15\""},
+		&services.Suggestion{Type: services.SuggestProduct, ID: "p-3", Text: "Laptop"},
+		&services.Suggestion{Type: services.SuggestProduct, ID: "p-4", Text: "Desk Lamp"},
+		&services.Suggestion{Type: services.SuggestCategory, ID: "c-1", Text: "Laptops", Slug: "laptops"},
+		&services.Suggestion{Type: services.SuggestBrand, ID: "b-1", Text: "LapTech", Slug: "laptech"},
+	)
+	service := services.NewSuggestionService(repo)
+	ctx := context.Background()
+
+	suggestions, err := service.Suggest(ctx, "  LAPTOP ", 10)
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	want := []string{"p-3", "c-1", "p-2", "p-1"}
+	if len(suggestions) != len(want) {
+		t.Fatalf("expected %d suggestions, got %d", len(want), len(suggestions))
+	}
+	for i, id := range want {
+		if suggestions[i].ID != id {
+			t.Errorf("suggestion %d: expected %s, got %s (%s)", i, id, suggestions[i].ID, suggestions[i].Text)
+		}
+	}
+
+	// Equal matches rank categories, then brands, then products
+	suggestions, _ = service.Suggest(ctx, "lap", 10)
+	if len(suggestions) != 5 || suggestions[0].ID != "c-1" || suggestions[1].ID != "b-1" || suggestions[2].ID != "p-3" {
+		t.Errorf("expected the category, then the brand, then the shortest product, got %+v", suggestions)
+	}
+	if suggestions, _ := service.Suggest(ctx, "lap", 2); len(suggestions) != 2 {
+		t.Errorf("expected the limit to apply, got %d suggestions", len(suggestions))
+	}
+
+	if _, err := service.Suggest(ctx, "   ", 10); err != services.ErrSuggestQueryRequired {
+		t.Errorf("expected ErrSuggestQueryRequired for an empty query, got %v", err)
+	}
+	if suggestions, err := service.Suggest(ctx, "l", 10); err != nil || len(suggestions) != 0 {
+		t.Errorf("expected no suggestions for one character, got %d (%v)", len(suggestions), err)
+	}
+}
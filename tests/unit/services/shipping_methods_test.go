@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/shipping"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newTableRateMethod() *services.ShippingMethod {
+	free := usd(10000)
+	return &services.ShippingMethod{
+		ID:       "standard",
+		Name:     "Standard",
+		FlatRate: usd(900),
+		RateTiers: []services.ShippingRateTier{
+			{MinSubtotal: usd(5000), Cost: usd(400)},
+			{MinSubtotal: usd(2500), Cost: usd(600)},
+		},
+		FreeShippingMin: &free,
+		IsActive:        true,
+	}
+}
+
+func TestShippingMethod_Cost(t *testing.T) {
+	method := newTableRateMethod()
+
+	cases := []struct {
+		name     string
+		subtotal money.Money
+		want     int64
+	}{
+		{"below every tier", usd(1000), 900},
+		{"first tier", usd(2500), 600},
+		{"highest tier reached", usd(7500), 400},
+		{"free shipping minimum", usd(10000), 0},
+		{"no subtotal", money.Money{}, 900},
+		{"other currency", money.Money{Amount: 20000, Currency: "EUR"}, 900},
+	}
+	for _, tc := range cases {
+		if got := method.Cost(tc.subtotal); got.Amount != tc.want || got.Currency != "USD" {
+			t.Errorf("%s: expected %d USD, got %+v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestShippingMethodService_SaveMethod(t *testing.T) {
+	repo := mocks.NewMockShippingMethodRepository()
+	svc := services.NewShippingMethodService(repo)
+	ctx := context.Background()
+
+	method := newTableRateMethod()
+	method.ID = " Standard "
+	method.Countries = []string{"us", " ca"}
+	if err := svc.SaveMethod(ctx, method); err != nil {
+		t.Fatalf("SaveMethod: %v", err)
+	}
+	saved := repo.Methods["standard"]
+	if saved == nil || saved.Countries[0] != "US" || saved.Countries[1] != "CA" {
+		t.Fatalf("expected the id lowercased and countries uppercased, got %+v", saved)
+	}
+	if saved.RateTiers[0].MinSubtotal.Amount != 2500 {
+		t.Errorf("expected tiers sorted by minimum subtotal, got %+v", saved.RateTiers)
+	}
+
+	invalid := []*services.ShippingMethod{
+		{ID: "", Name: "No id", FlatRate: usd(100)},
+		{ID: "two words", Name: "Spaces", FlatRate: usd(100)},
+		{ID: "nameless", FlatRate: usd(100)},
+		{ID: "negative", Name: "Negative", FlatRate: usd(-1)},
+		{ID: "days", Name: "Days", FlatRate: usd(100), EstimatedDaysMin: 5, EstimatedDaysMax: 2},
+		{ID: "mixed", Name: "Mixed", FlatRate: usd(100), RateTiers: []services.ShippingRateTier{{MinSubtotal: usd(100), Cost: money.Money{Amount: 50, Currency: "EUR"}}}},
+	}
+	for _, m := range invalid {
+		if err := svc.SaveMethod(ctx, m); !errors.Is(err, services.ErrInvalidShippingMethod) {
+			t.Errorf("%s: expected ErrInvalidShippingMethod, got %v", m.ID, err)
+		}
+	}
+}
+
+func TestShippingZoneService_FallsBackToMethods(t *testing.T) {
+	zones := mocks.NewMockShippingZoneRepository()
+	zones.Zones["us"] = newTestZone("us", "US", "", 0, nil, "standard")
+	methods := mocks.NewMockShippingMethodRepository()
+
+	domestic := newTableRateMethod()
+	domestic.Countries = []string{"US", "CA"}
+	methods.Methods["standard"] = domestic
+	methods.Methods["worldwide"] = &services.ShippingMethod{ID: "worldwide", Name: "Worldwide", FlatRate: usd(2500), IsActive: true, SortOrder: 1}
+	methods.Methods["retired"] = &services.ShippingMethod{ID: "retired", Name: "Retired", FlatRate: usd(100)}
+
+	svc := services.NewShippingZoneService(zones).
+		WithMethods(services.NewShippingMethodService(methods))
+
+	// A zone covering the destination takes precedence over the methods
+	rates, err := svc.GetAvailableRates(context.Background(), shipping.RateRequest{DestinationAddress: shipping.Address{Country: "US"}})
+	if err != nil || len(rates) != 1 || rates[0].Cost.Amount != 500 {
+		t.Fatalf("expected the US zone's rate, got %+v, %v", rates, err)
+	}
+
+	// Elsewhere the methods serving the country are priced against the order subtotal
+	ctx := services.WithShippingSubtotal(context.Background(), usd(3000))
+	rates, err = svc.GetAvailableRates(ctx, shipping.RateRequest{DestinationAddress: shipping.Address{Country: "ca"}})
+	if err != nil {
+		t.Fatalf("GetAvailableRates: %v", err)
+	}
+	if len(rates) != 2 || rates[0].MethodID != "standard" || rates[0].Cost.Amount != 600 || rates[1].MethodID != "worldwide" {
+		t.Fatalf("expected standard at the 25.00 tier then worldwide, got %+v", rates)
+	}
+
+	rate, err := svc.GetRate(ctx, shipping.RateRequest{DestinationAddress: shipping.Address{Country: "DE"}, ShippingMethodID: "standard"})
+	if !errors.Is(err, services.ErrShippingMethodUnavailable) {
+		t.Errorf("expected standard to be unavailable in DE, got %+v, %v", rate, err)
+	}
+	rate, err = svc.GetRate(ctx, shipping.RateRequest{DestinationAddress: shipping.Address{Country: "DE"}, ShippingMethodID: "worldwide"})
+	if err != nil || rate.Cost.Amount != 2500 {
+		t.Errorf("expected worldwide at its flat rate in DE, got %+v, %v", rate, err)
+	}
+
+	// Without a method serving the country there is still no zone
+	delete(methods.Methods, "worldwide")
+	if _, err := svc.ResolveZone(ctx, shipping.Address{Country: "DE"}); !errors.Is(err, services.ErrShippingZoneNotFound) {
+		t.Errorf("expected ErrShippingZoneNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/orders"
+	"github.com/devchuckcamp/gocommerce/shipping"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// newRestrictionFixture sets up a US zone with ground and air rates from two carriers, and
+// a lithium battery that ships by ground with UPS only, never to PO boxes, to buyers over 18
+func newRestrictionFixture(t *testing.T) *services.ShippingRestrictionService {
+	t.Helper()
+	zones := mocks.NewMockShippingZoneRepository()
+	zones.Zones["us"] = &services.ShippingZone{
+		ID:       "us",
+		Name:     "United States",
+		Country:  "US",
+		IsActive: true,
+		Rates: []services.ShippingZoneRate{
+			{ID: "r1", MethodID: "ups_ground", Carrier: "UPS", Cost: money.Money{Amount: 500, Currency: "USD"}},
+			{ID: "r2", MethodID: "ups_air", Carrier: "UPS", Air: true, Cost: money.Money{Amount: 1500, Currency: "USD"}},
+			{ID: "r3", MethodID: "usps_ground", Carrier: "USPS", Cost: money.Money{Amount: 400, Currency: "USD"}},
+		},
+	}
+
+	restrictions := services.NewShippingRestrictionService(mocks.NewMockShippingRestrictionRepository(), services.NewShippingZoneService(zones))
+	err := restrictions.SetRestriction(context.Background(), &services.ShippingRestriction{
+		ProductID: "prod-battery",
+		NoAir:     true,
+		Carriers:  []string{" ups "},
+		NoPOBox:   true,
+		MinAge:    18,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return restrictions
+}
+
+func restrictionCart(productIDs ...string) *cart.Cart {
+	c := &cart.Cart{ID: "cart-1"}
+	for _, productID := range productIDs {
+		c.Items = append(c.Items, cart.CartItem{ID: "item-" + productID, ProductID: productID, Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}})
+	}
+	return c
+}
+
+func TestShippingRestrictions_AllowedRates(t *testing.T) {
+	ctx := context.Background()
+	restrictions := newRestrictionFixture(t)
+	destination := shipping.Address{Country: "US", PostalCode: "94107"}
+
+	rates, err := restrictions.AllowedRates(ctx, restrictionCart("prod-book"), destination)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 3 {
+		t.Errorf("expected every rate for unrestricted products, got %d", len(rates))
+	}
+
+	rates, err = restrictions.AllowedRates(ctx, restrictionCart("prod-book", "prod-battery"), destination)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 1 || rates[0].MethodID != "ups_ground" {
+		t.Errorf("expected only UPS ground for the battery, got %+v", rates)
+	}
+}
+
+func TestShippingRestrictions_CheckOrder(t *testing.T) {
+	ctx := context.Background()
+	restrictions := newRestrictionFixture(t)
+	adult := time.Now().AddDate(-30, 0, 0).Format("2006-01-02")
+	minor := time.Now().AddDate(-18, 0, 1).Format("2006-01-02")
+	street := orders.Address{AddressLine1: "1 Market St", City: "San Francisco", State: "CA", PostalCode: "94107", Country: "US"}
+	poBox := orders.Address{AddressLine1: "P.O. Box 12", City: "San Francisco", State: "CA", PostalCode: "94107", Country: "US"}
+
+	c := restrictionCart("prod-battery")
+	if err := restrictions.CheckOrder(ctx, c, services.ShippingOrder{Address: street, ShippingMethodID: "ups_ground", DateOfBirth: adult}); err != nil {
+		t.Fatalf("expected UPS ground to a street address for an adult to pass, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		order services.ShippingOrder
+		want  []string
+	}{
+		{"by air", services.ShippingOrder{Address: street, ShippingMethodID: "ups_air", DateOfBirth: adult}, []string{services.RestrictionNoAir}},
+		{"other carrier", services.ShippingOrder{Address: street, ShippingMethodID: "usps_ground", DateOfBirth: adult}, []string{services.RestrictionCarriers}},
+		{"po box", services.ShippingOrder{Address: poBox, ShippingMethodID: "ups_ground", DateOfBirth: adult}, []string{services.RestrictionNoPOBox}},
+		{"no date of birth", services.ShippingOrder{Address: street, ShippingMethodID: "ups_ground"}, []string{services.RestrictionMinAge}},
+		{"under age", services.ShippingOrder{Address: street, ShippingMethodID: "ups_ground", DateOfBirth: minor}, []string{services.RestrictionMinAge}},
+		{"everything", services.ShippingOrder{Address: poBox, ShippingMethodID: "ups_air"}, []string{services.RestrictionNoAir, services.RestrictionNoPOBox, services.RestrictionMinAge}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := restrictions.CheckOrder(ctx, c, tt.order)
+			var restrictionErr *services.ShippingRestrictionError
+			if !errors.As(err, &restrictionErr) || len(restrictionErr.Violations) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			for i, violation := range restrictionErr.Violations {
+				if violation.Restriction != tt.want[i] || violation.ProductID != "prod-battery" {
+					t.Errorf("expected %s for prod-battery, got %+v", tt.want[i], violation)
+				}
+			}
+		})
+	}
+
+	if err := restrictions.CheckOrder(ctx, restrictionCart("prod-book"), services.ShippingOrder{Address: poBox, ShippingMethodID: "usps_ground"}); err != nil {
+		t.Errorf("expected unrestricted products to pass, got %v", err)
+	}
+	if err := restrictions.CheckOrder(ctx, c, services.ShippingOrder{Address: street, ShippingMethodID: "courier", DateOfBirth: adult}); err != services.ErrShippingMethodUnavailable {
+		t.Errorf("expected ErrShippingMethodUnavailable, got %v", err)
+	}
+}
+
+func TestShippingRestrictions_SetRestriction(t *testing.T) {
+	ctx := context.Background()
+	restrictions := services.NewShippingRestrictionService(mocks.NewMockShippingRestrictionRepository(), nil)
+
+	invalid := []*services.ShippingRestriction{
+		{ProductID: "prod-1"},
+		{NoAir: true},
+		{ProductID: "prod-1", MinAge: -1},
+		{ProductID: "prod-1", MinAge: 200},
+		{ProductID: "prod-1", Carriers: []string{"UPS", " "}},
+	}
+	for _, restriction := range invalid {
+		if err := restrictions.SetRestriction(ctx, restriction); err != services.ErrInvalidShippingRestriction {
+			t.Errorf("expected ErrInvalidShippingRestriction for %+v, got %v", restriction, err)
+		}
+	}
+
+	if err := restrictions.RemoveRestriction(ctx, "prod-1"); err != services.ErrShippingRestrictionNotFound {
+		t.Errorf("expected ErrShippingRestrictionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/money"
+	"github.com/devchuckcamp/gocommerce/shipping"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newTestZone(id, country, state string, priority int, patterns []string, methodIDs ...string) *services.ShippingZone {
+	rates := make([]services.ShippingZoneRate, len(methodIDs))
+	for i, methodID := range methodIDs {
+		rates[i] = services.ShippingZoneRate{
+			ID:         id + "-" + methodID,
+			MethodID:   methodID,
+			MethodName: methodID,
+			Cost:       money.Money{Amount: int64(500 * (i + 1)), Currency: "USD"},
+		}
+	}
+	return &services.ShippingZone{
+		ID:                 id,
+		Name:               id,
+		Country:            country,
+		State:              state,
+		PostalCodePatterns: patterns,
+		Priority:           priority,
+		IsActive:           true,
+		Rates:              rates,
+	}
+}
+
+func TestShippingZoneService_ResolveZone(t *testing.T) {
+	repo := mocks.NewMockShippingZoneRepository()
+	repo.Zones["us"] = newTestZone("us", "US", "", 0, nil, "standard", "express")
+	repo.Zones["us-ca"] = newTestZone("us-ca", "US", "CA", 0, nil, "standard")
+	repo.Zones["bay-area"] = newTestZone("bay-area", "US", "CA", 0, []string{"940*", "941*"}, "same_day")
+	repo.Zones["remote"] = newTestZone("remote", "US", "", 10, []string{"99501"}, "freight")
+	repo.Zones["inactive"] = newTestZone("inactive", "GB", "", 0, nil, "standard")
+	repo.Zones["inactive"].IsActive = false
+
+	service := services.NewShippingZoneService(repo)
+
+	tests := []struct {
+		name     string
+		address  shipping.Address
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "country-wide zone",
+			address:  shipping.Address{Country: "US", State: "NY", PostalCode: "10001"},
+			expected: "us",
+		},
+		{
+			name:     "state zone beats country zone",
+			address:  shipping.Address{Country: "US", State: "CA", PostalCode: "90001"},
+			expected: "us-ca",
+		},
+		{
+			name:     "postal code prefix beats state zone",
+			address:  shipping.Address{Country: "us", State: "ca", PostalCode: "94107"},
+			expected: "bay-area",
+		},
+		{
+			name:     "priority beats specificity",
+			address:  shipping.Address{Country: "US", State: "AK", PostalCode: "99501"},
+			expected: "remote",
+		},
+		{
+			name:    "inactive zones are ignored",
+			address: shipping.Address{Country: "GB", PostalCode: "SW1A 1AA"},
+			wantErr: services.ErrShippingZoneNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := service.ResolveZone(context.Background(), tt.address)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && zone.ID != tt.expected {
+				t.Errorf("expected zone %s, got %s", tt.expected, zone.ID)
+			}
+		})
+	}
+}
+
+func TestShippingZoneService_GetRate(t *testing.T) {
+	repo := mocks.NewMockShippingZoneRepository()
+	repo.Zones["us"] = newTestZone("us", "US", "", 0, nil, "standard", "express")
+	repo.Zones["bay-area"] = newTestZone("bay-area", "US", "", 0, []string{"941*"}, "same_day")
+
+	service := services.NewShippingZoneService(repo)
+
+	tests := []struct {
+		name     string
+		address  shipping.Address
+		methodID string
+		wantErr  error
+	}{
+		{
+			name:     "method offered in zone",
+			address:  shipping.Address{Country: "US", PostalCode: "10001"},
+			methodID: "express",
+		},
+		{
+			name:     "method restricted to another zone",
+			address:  shipping.Address{Country: "US", PostalCode: "10001"},
+			methodID: "same_day",
+			wantErr:  services.ErrShippingMethodUnavailable,
+		},
+		{
+			name:     "zone overrides country-wide methods",
+			address:  shipping.Address{Country: "US", PostalCode: "94107"},
+			methodID: "standard",
+			wantErr:  services.ErrShippingMethodUnavailable,
+		},
+		{
+			name:     "no zone for destination",
+			address:  shipping.Address{Country: "CA", PostalCode: "M5V 2T6"},
+			methodID: "standard",
+			wantErr:  services.ErrShippingMethodUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := service.GetRate(context.Background(), shipping.RateRequest{
+				DestinationAddress: tt.address,
+				ShippingMethodID:   tt.methodID,
+			})
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && rate.MethodID != tt.methodID {
+				t.Errorf("expected method %s, got %s", tt.methodID, rate.MethodID)
+			}
+		})
+	}
+}
+
+func TestShippingZoneService_SaveZone(t *testing.T) {
+	repo := mocks.NewMockShippingZoneRepository()
+	service := services.NewShippingZoneService(repo)
+
+	zone := newTestZone("", "us", "ca", 0, nil, "standard")
+	zone.Name = "California"
+	if err := service.SaveZone(context.Background(), zone); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zone.ID == "" {
+		t.Error("expected zone ID to be assigned")
+	}
+	if zone.Country != "US" || zone.State != "CA" {
+		t.Errorf("expected uppercased country/state, got %s/%s", zone.Country, zone.State)
+	}
+
+	invalid := newTestZone("", "US", "", 0, nil)
+	invalid.Name = "No rates"
+	if err := service.SaveZone(context.Background(), invalid); err != services.ErrInvalidShippingZone {
+		t.Errorf("expected ErrInvalidShippingZone, got %v", err)
+	}
+}
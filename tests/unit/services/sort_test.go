@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"name", "price", "created_at"}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    services.Sort
+		wantErr bool
+	}{
+		{"empty keeps the default order", "", nil, false},
+		{"ascending", "name", services.Sort{{Field: "name"}}, false},
+		{"descending and ascending", "-created_at, Name", services.Sort{{Field: "created_at", Desc: true}, {Field: "name"}}, false},
+		{"empty keys are skipped", "price,,", services.Sort{{Field: "price"}}, false},
+		{"field not on the list", "cost_price", nil, true},
+		{"sql is rejected", "name;drop table products", nil, true},
+		{"field sorted twice", "name,-name", nil, true},
+		{"too many fields", "name,price,created_at,-name", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.ParseSort(tt.raw, allowed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSort_String(t *testing.T) {
+	sort := services.Sort{{Field: "price", Desc: true}, {Field: "name"}}
+	if got := sort.String(); got != "-price,name" {
+		t.Errorf("expected -price,name, got %q", got)
+	}
+
+	parsed, err := services.ParseSort(sort.String(), services.ProductSortFields)
+	if err != nil || !reflect.DeepEqual(parsed, sort) {
+		t.Errorf("expected the sort to parse back to %v, got %v (%v)", sort, parsed, err)
+	}
+	if got := services.Sort(nil).String(); got != "" {
+		t.Errorf("expected an empty sort to render empty, got %q", got)
+	}
+}
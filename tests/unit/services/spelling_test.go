@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+// nameSearchProductRepository searches product names for the keyword, like the database
+type nameSearchProductRepository struct {
+	*mocks.MockProductRepository
+}
+
+func (r nameSearchProductRepository) Search(ctx context.Context, query string, filter catalog.ProductFilter) ([]*catalog.Product, error) {
+	products := []*catalog.Product{}
+	for _, product := range r.Products {
+		if strings.Contains(strings.ToLower(product.Name), strings.ToLower(query)) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func TestSpellingService_Correct(t *testing.T) {
+	terms := mocks.NewMockSearchTermRepository("wireless", "laptop", "mouse")
+	terms.Corrections["labtop"] = "laptop"
+	terms.Corrections["mosue"] = "mouse"
+	spelling := services.NewSpellingService(terms)
+	ctx := context.Background()
+
+	tests := []struct {
+		query     string
+		corrected string
+	}{
+		{"Wireless  Labtop", "wireless laptop"},
+		{"labtop mosue", "laptop mouse"},
+		{"wireless laptop", ""},
+		{"qzxv", ""},
+		{"tv", ""},
+	}
+	for _, tt := range tests {
+		corrected, ok, err := spelling.Correct(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("Correct(%q) failed: %v", tt.query, err)
+		}
+		if corrected != tt.corrected || ok != (tt.corrected != "") {
+			t.Errorf("Correct(%q): expected %q, got %q (%v)", tt.query, tt.corrected, corrected, ok)
+		}
+	}
+}
+
+func TestCatalogService_CorrectedSearch(t *testing.T) {
+	products := nameSearchProductRepository{mocks.NewMockProductRepository()}
+	products.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	terms := mocks.NewMockSearchTermRepository("professional", "laptop")
+	terms.Corrections["lapptop"] = "laptop"
+
+	svc := services.NewCatalogService(products, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository())
+	ctx := context.Background()
+
+	// Without spelling correction there is nothing to retry
+	if corrected, _, err := svc.CorrectedSearch(ctx, "lapptop", catalog.ProductFilter{}, nil); corrected != "" || err != nil {
+		t.Fatalf("expected no correction without spelling, got %q (%v)", corrected, err)
+	}
+
+	svc.WithSpelling(services.NewSpellingService(terms))
+	corrected, results, err := svc.CorrectedSearch(ctx, "lapptop", catalog.ProductFilter{}, nil)
+	if err != nil || corrected != "laptop" {
+		t.Fatalf("expected the search corrected to laptop, got %q (%v)", corrected, err)
+	}
+	if len(results) != 1 || results[0].ID != fixtures.ProductLaptop.ID {
+		t.Errorf("expected the corrected search to find the laptop, got %d products", len(results))
+	}
+
+	// A correction that finds nothing either, or fails, is no correction
+	terms.Corrections["qzxv"] = "zzzz"
+	if corrected, results, _ := svc.CorrectedSearch(ctx, "qzxv", catalog.ProductFilter{}, nil); corrected != "" || results != nil {
+		t.Errorf("expected no correction when it finds nothing, got %q", corrected)
+	}
+	terms.ClosestError = errors.New("database error")
+	if corrected, _, err := svc.CorrectedSearch(ctx, "lapptop", catalog.ProductFilter{}, nil); corrected != "" || err != nil {
+		t.Errorf("expected a failed correction to be skipped, got %q (%v)", corrected, err)
+	}
+}
@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/payments"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestStoreCreditService_Grant(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockStoreCreditRepository()
+	service := services.NewStoreCreditService(repo)
+
+	txn, err := service.Grant(ctx, services.GrantStoreCreditRequest{
+		UserID:    "user-1",
+		Amount:    usd(1500),
+		Reason:    services.StoreCreditReasonRefundToCredit,
+		CreatedBy: "admin-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txn.Type != services.StoreCreditTypeGrant || txn.BalanceAfter != usd(1500) {
+		t.Errorf("expected a grant leaving 1500, got %s leaving %d", txn.Type, txn.BalanceAfter.Amount)
+	}
+
+	if _, err := service.Grant(ctx, services.GrantStoreCreditRequest{UserID: "user-1", Amount: usd(0)}); err != services.ErrInvalidStoreCredit {
+		t.Errorf("expected ErrInvalidStoreCredit for a zero grant, got %v", err)
+	}
+	if _, err := service.Grant(ctx, services.GrantStoreCreditRequest{UserID: "user-1", Amount: usd(100), Reason: "bribe"}); err != services.ErrInvalidStoreCredit {
+		t.Errorf("expected ErrInvalidStoreCredit for an unknown reason, got %v", err)
+	}
+}
+
+func TestStoreCreditService_Available(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewStoreCreditService(mocks.NewMockStoreCreditRepository())
+	if _, err := service.Grant(ctx, services.GrantStoreCreditRequest{UserID: "user-1", Amount: usd(2500)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		total    int64
+		expected int64
+	}{
+		{"balance below total", 10000, 2500},
+		{"balance above total", 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			available, err := service.Available(ctx, "user-1", usd(tt.total))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if available != usd(tt.expected) {
+				t.Errorf("expected %d available, got %d", tt.expected, available.Amount)
+			}
+		})
+	}
+}
+
+func TestPaymentService_StoreCreditTender(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(t *testing.T, credit int64) (*services.PaymentService, *mocks.MockStoreCreditRepository, *mocks.MockPaymentGateway) {
+		repo := mocks.NewMockStoreCreditRepository()
+		storeCredit := services.NewStoreCreditService(repo)
+		if _, err := storeCredit.Grant(ctx, services.GrantStoreCreditRequest{UserID: "user-1", Amount: usd(credit)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		gateway := mocks.NewMockPaymentGateway()
+		service := services.NewPaymentService(mocks.NewMockPaymentRepository(), gateway).WithStoreCreditService(storeCredit)
+		return service, repo, gateway
+	}
+	split := []services.TenderRequest{
+		{Type: services.TenderTypeStoreCredit, PaymentMethodID: "user-1", Amount: usd(3000)},
+		{Type: services.TenderTypeCard, PaymentMethodID: "pm_1", Amount: usd(7000)},
+	}
+
+	t.Run("redeems credit from the wallet and charges the rest", func(t *testing.T) {
+		service, repo, gateway := newService(t, 5000)
+		order := newTestOrder(10000)
+
+		tenders, err := service.ChargeTenders(ctx, order, split)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tenders[0].Status != services.TenderStatusCaptured || tenders[0].GatewayReference == "" {
+			t.Errorf("expected store credit tender to be captured against a ledger entry, got %s", tenders[0].Status)
+		}
+		if balance := repo.Balances["user-1:USD"].Balance; balance != usd(2000) {
+			t.Errorf("expected 2000 left in the wallet, got %d", balance.Amount)
+		}
+		if len(gateway.Intents) != 1 {
+			t.Errorf("expected only the card to reach the gateway, got %d intents", len(gateway.Intents))
+		}
+
+		if _, err := service.RefundTender(ctx, tenders[0].ID, usd(1000), payments.RefundReasonRequestedByCustomer); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if balance := repo.Balances["user-1:USD"].Balance; balance != usd(3000) {
+			t.Errorf("expected refund to restore credit to 3000, got %d", balance.Amount)
+		}
+		if len(gateway.Refunds) != 0 {
+			t.Errorf("expected no gateway refund for store credit, got %d", len(gateway.Refunds))
+		}
+	})
+
+	t.Run("declines when the wallet cannot cover the tender", func(t *testing.T) {
+		service, repo, gateway := newService(t, 1000)
+		order := newTestOrder(10000)
+
+		tenders, err := service.ChargeTenders(ctx, order, split)
+		if err != services.ErrTenderDeclined {
+			t.Fatalf("expected ErrTenderDeclined, got %v", err)
+		}
+		if tenders[0].Status != services.TenderStatusFailed {
+			t.Errorf("expected store credit tender to fail, got %s", tenders[0].Status)
+		}
+		if balance := repo.Balances["user-1:USD"].Balance; balance != usd(1000) {
+			t.Errorf("expected wallet to be untouched, got %d", balance.Amount)
+		}
+		if len(gateway.Intents) != 0 {
+			t.Errorf("expected the card not to be charged, got %d intents", len(gateway.Intents))
+		}
+	})
+}
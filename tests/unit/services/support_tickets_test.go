@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+const supportAgentID = "support-agent"
+
+func newSupportTicketService(t *testing.T) (*services.SupportTicketService, *mocks.MockSupportTicketRepository) {
+	t.Helper()
+	orderRepo := mocks.NewMockOrderRepository()
+	order := newTestOrder(10000)
+	order.UserID = fixtures.TestUserID
+	orderRepo.Orders[order.ID] = order
+	orderService := services.NewOrderService(orderRepo, nil, nil, nil)
+	repo := mocks.NewMockSupportTicketRepository()
+	return services.NewSupportTicketService(repo, orderService), repo
+}
+
+func TestSupportTickets_OpenAgainstOwnOrder(t *testing.T) {
+	ctx := context.Background()
+	tickets, _ := newSupportTicketService(t)
+
+	ticket, err := tickets.OpenTicket(ctx, fixtures.TestUserID, "order-1", services.OpenTicketRequest{
+		Subject: "  Box arrived crushed ",
+		Message: "The mug inside is broken.",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ticket.Status != services.TicketStatusOpen || ticket.Category != services.TicketCategoryOther || ticket.Subject != "Box arrived crushed" {
+		t.Errorf("expected an open ticket in the default category, got %+v", ticket)
+	}
+	if len(ticket.Messages) != 1 || ticket.Messages[0].Staff {
+		t.Errorf("expected the customer's first message, got %+v", ticket.Messages)
+	}
+
+	// Staff see the ticket, with its conversation, on the order
+	onOrder, err := tickets.OrderTickets(ctx, "order-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(onOrder) != 1 || onOrder[0].ID != ticket.ID || len(onOrder[0].Messages) != 1 {
+		t.Errorf("expected the ticket linked to the order, got %+v", onOrder)
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		orderID string
+		req     services.OpenTicketRequest
+		wantErr error
+	}{
+		{"another user's order", "someone-else", "order-1", services.OpenTicketRequest{Subject: "Hi", Message: "Hi"}, orders.ErrOrderNotFound},
+		{"missing order", fixtures.TestUserID, "missing", services.OpenTicketRequest{Subject: "Hi", Message: "Hi"}, orders.ErrOrderNotFound},
+		{"blank subject", fixtures.TestUserID, "order-1", services.OpenTicketRequest{Subject: "  ", Message: "Hi"}, services.ErrInvalidTicket},
+		{"unknown category", fixtures.TestUserID, "order-1", services.OpenTicketRequest{Subject: "Hi", Category: "refund", Message: "Hi"}, services.ErrInvalidTicket},
+		{"blank message", fixtures.TestUserID, "order-1", services.OpenTicketRequest{Subject: "Hi", Message: ""}, services.ErrInvalidTicket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tickets.OpenTicket(ctx, tt.userID, tt.orderID, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSupportTickets_Conversation(t *testing.T) {
+	ctx := context.Background()
+	tickets, repo := newSupportTicketService(t)
+	ticket, err := tickets.OpenTicket(ctx, fixtures.TestUserID, "order-1", services.OpenTicketRequest{
+		Subject:  "Wrong size",
+		Category: services.TicketCategoryWrongItem,
+		Message:  "I ordered a medium.",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A staff reply waits on the customer; the customer's answer puts it back in the queue
+	replied, err := tickets.Reply(ctx, ticket.ID, supportAgentID, true, "Sorry! A replacement is on its way.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replied.Status != services.TicketStatusPending || len(replied.Messages) != 2 || !replied.Messages[1].Staff {
+		t.Errorf("expected a pending ticket with support's reply, got %+v", replied)
+	}
+	queue, err := tickets.ListTickets(ctx, services.TicketStatusOpen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue) != 0 {
+		t.Errorf("expected no open tickets while waiting on the customer, got %d", len(queue))
+	}
+	if _, err := tickets.Reply(ctx, ticket.ID, fixtures.TestUserID, false, "Thanks, when will it arrive?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Tickets[ticket.ID].Status != services.TicketStatusOpen {
+		t.Errorf("expected the ticket open again, got %s", repo.Tickets[ticket.ID].Status)
+	}
+
+	// Closing ends the conversation for good
+	closed, err := tickets.SetStatus(ctx, ticket.ID, services.TicketStatusClosed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closed.ClosedAt == nil {
+		t.Error("expected the closing time recorded")
+	}
+	if _, err := tickets.Reply(ctx, ticket.ID, fixtures.TestUserID, false, "Still waiting"); !errors.Is(err, services.ErrTicketClosed) {
+		t.Errorf("expected ErrTicketClosed replying to a closed ticket, got %v", err)
+	}
+	if _, err := tickets.SetStatus(ctx, ticket.ID, services.TicketStatusOpen); !errors.Is(err, services.ErrTicketClosed) {
+		t.Errorf("expected ErrTicketClosed reopening a closed ticket, got %v", err)
+	}
+	if _, err := tickets.ListTickets(ctx, "waiting"); !errors.Is(err, services.ErrInvalidTicket) {
+		t.Errorf("expected ErrInvalidTicket for an unknown status, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/internal/startup"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+type systemStatusFixture struct {
+	svc       *services.SystemStatusService
+	repo      *mocks.MockSystemStatusRepository
+	runner    *jobs.Runner
+	scheduler *jobs.Scheduler
+	webhooks  *mocks.MockWebhookEventRepository
+	letters   *mocks.MockDeadLetterRepository
+	catalog   *services.CatalogService
+}
+
+func newSystemStatusFixture(t *testing.T) *systemStatusFixture {
+	t.Helper()
+	f := &systemStatusFixture{
+		repo:     mocks.NewMockSystemStatusRepository(),
+		runner:   jobs.NewRunner(2, 10),
+		webhooks: mocks.NewMockWebhookEventRepository(),
+		letters:  mocks.NewMockDeadLetterRepository(),
+	}
+	f.scheduler = jobs.NewScheduler(f.runner)
+
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	f.catalog = services.NewCatalogService(productRepo, mocks.NewMockVariantRepository(), mocks.NewMockCategoryRepository(), mocks.NewMockBrandRepository()).
+		WithProductCache(services.NewProductCache(time.Minute), nil)
+
+	f.svc = services.NewSystemStatusService(
+		f.repo,
+		f.runner,
+		f.scheduler,
+		services.NewWebhookService(f.webhooks, f.runner),
+		services.NewDeadLetterService(f.letters, f.runner),
+		f.catalog,
+	)
+	return f
+}
+
+func TestSystemStatusService_Status(t *testing.T) {
+	f := newSystemStatusFixture(t)
+	ctx := context.Background()
+
+	// A scheduled task that fails, run to completion
+	if err := f.scheduler.Register("report", "Nightly report", "@daily", func(ctx context.Context) error { return errors.New("smtp down") }); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	f.runner.Start(runCtx)
+	if _, err := f.scheduler.Trigger("report"); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for f.runner.Stats().Failed == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	f.runner.Wait()
+
+	// Two queued jobs that no worker will pick up
+	for i := 0; i < 2; i++ {
+		if err := f.runner.Enqueue(jobs.Job{Name: "queued", Run: func(ctx context.Context) error { return nil }}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	f.webhooks.Events["evt-1"] = &services.WebhookEvent{ID: "evt-1", Status: services.WebhookEventStatusPending}
+	f.webhooks.Events["evt-2"] = &services.WebhookEvent{ID: "evt-2", Status: services.WebhookEventStatusFailed}
+	f.webhooks.Events["evt-3"] = &services.WebhookEvent{ID: "evt-3", Status: services.WebhookEventStatusProcessed}
+	f.letters.Letters["dl-1"] = &services.DeadLetter{ID: "dl-1", Status: services.DeadLetterStatusDead}
+	f.letters.Letters["dl-2"] = &services.DeadLetter{ID: "dl-2", Status: services.DeadLetterStatusReplayed}
+
+	// One miss then three hits on the product cache
+	for i := 0; i < 4; i++ {
+		if _, err := f.catalog.GetProduct(ctx, fixtures.ProductLaptop.ID); err != nil {
+			t.Fatalf("GetProduct: %v", err)
+		}
+	}
+	f.svc.WithCache("responses", func() services.CacheStats { return services.CacheStats{Entries: 5, Hits: 1, Misses: 2} })
+
+	f.repo.Migration = &services.AppliedMigration{Version: "946", Name: "create_dead_letters"}
+	f.repo.DBLatency = services.DBLatency{Samples: 10, P50: 1.5, P95: 4, P99: 9}
+
+	status := f.svc.Status(ctx)
+
+	jobsStatus := status.Jobs
+	if jobsStatus.Workers != 2 || jobsStatus.Queued != 2 || jobsStatus.Capacity != 10 || jobsStatus.Failed != 1 || jobsStatus.Succeeded != 0 {
+		t.Errorf("unexpected runner stats %+v", jobsStatus.RunnerStats)
+	}
+	if jobsStatus.DeadLetters != 1 || len(jobsStatus.FailingTasks) != 1 || jobsStatus.FailingTasks[0] != "report" {
+		t.Errorf("expected one dead letter and the failing task, got %+v", jobsStatus)
+	}
+	if status.Webhooks.Pending != 1 || status.Webhooks.Failed != 1 {
+		t.Errorf("expected one pending and one failed webhook, got %+v", status.Webhooks)
+	}
+
+	if len(status.Caches) != 2 || status.Caches[0].Name != "products" || status.Caches[1].Name != "responses" {
+		t.Fatalf("expected the product and response caches by name, got %+v", status.Caches)
+	}
+	if products := status.Caches[0]; products.Hits != 3 || products.Misses != 1 || products.HitRate != 0.75 {
+		t.Errorf("unexpected product cache status %+v", products)
+	}
+	if responses := status.Caches[1]; responses.HitRate != 0.3333 {
+		t.Errorf("expected the response hit rate rounded to 0.3333, got %v", responses.HitRate)
+	}
+
+	if status.Database.LastMigration == nil || status.Database.LastMigration.Version != "946" || status.Database.Latency.P99 != 9 {
+		t.Errorf("unexpected database status %+v", status.Database)
+	}
+	if status.Errors != nil {
+		t.Errorf("expected no errors, got %v", status.Errors)
+	}
+}
+
+func TestSystemStatusService_ReportsFailingSections(t *testing.T) {
+	f := newSystemStatusFixture(t)
+	f.repo.MigrationError = errors.New("permission denied for table gocommerce_migrations")
+	f.svc.WithStartupReport(&startup.Report{
+		Mode:     startup.ModeDegraded,
+		Degraded: true,
+		Checks:   []startup.Result{{Name: "provider:smtp", Error: "connection refused"}},
+	})
+
+	status := f.svc.Status(context.Background())
+	if status.Errors["migrations"] != "permission denied for table gocommerce_migrations" {
+		t.Errorf("expected the migration error to be reported, got %v", status.Errors)
+	}
+	if status.Database.LastMigration != nil || status.Jobs.Capacity != 10 {
+		t.Errorf("expected the other sections to still be reported, got %+v", status)
+	}
+	if status.Startup == nil || !status.Startup.Degraded || status.Startup.Failed()[0] != "provider:smtp" {
+		t.Errorf("expected the degraded startup to be reported, got %+v", status.Startup)
+	}
+}
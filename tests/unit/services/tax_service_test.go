@@ -2,11 +2,13 @@ package services_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/devchuckcamp/gocommerce/money"
 	"github.com/devchuckcamp/gocommerce/tax"
 
+	"github.com/devchuckcamp/gocommerce-api/internal/moneymath"
 	"github.com/devchuckcamp/gocommerce-api/internal/services"
 )
 
@@ -206,3 +208,47 @@ func TestSimpleTaxCalculator_GetRatesForAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleTaxCalculator_Rounding(t *testing.T) {
+	req := tax.CalculationRequest{
+		LineItems: []tax.TaxableItem{
+			{ID: "item-1", Amount: money.Money{Amount: 12500, Currency: "USD"}, Quantity: 2, IsTaxable: true},
+		},
+		ShippingCost: money.Money{Amount: 0, Currency: "USD"},
+	}
+
+	// 25000 * 0.0875 = 2187.5
+	truncated, err := services.NewSimpleTaxCalculator(0.0875).Calculate(context.Background(), req)
+	if err != nil || truncated.TotalTax.Amount != 2187 {
+		t.Fatalf("Expected truncated tax 2187, got %+v (%v)", truncated, err)
+	}
+	bankers, err := services.NewSimpleTaxCalculator(0.0875).WithRounding(moneymath.HalfEven).Calculate(context.Background(), req)
+	if err != nil || bankers.TotalTax.Amount != 2188 {
+		t.Fatalf("Expected banker's rounded tax 2188, got %+v (%v)", bankers, err)
+	}
+	if bankers.LineItemTaxes[0].TaxAmount.Amount != 2188 {
+		t.Errorf("Expected line item tax 2188, got %d", bankers.LineItemTaxes[0].TaxAmount.Amount)
+	}
+}
+
+func TestSimpleTaxCalculator_RejectsMixedCurrencies(t *testing.T) {
+	calculator := services.NewSimpleTaxCalculator(0.0875)
+
+	_, err := calculator.Calculate(context.Background(), tax.CalculationRequest{
+		LineItems: []tax.TaxableItem{
+			{ID: "item-1", Amount: money.Money{Amount: 1000, Currency: "USD"}, Quantity: 1, IsTaxable: true},
+			{ID: "item-2", Amount: money.Money{Amount: 1000, Currency: "EUR"}, Quantity: 1, IsTaxable: true},
+		},
+	})
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch for mixed line items, got %v", err)
+	}
+
+	_, err = calculator.Calculate(context.Background(), tax.CalculationRequest{
+		LineItems:    []tax.TaxableItem{{ID: "item-1", Amount: money.Money{Amount: 1000, Currency: "USD"}, Quantity: 1, IsTaxable: true}},
+		ShippingCost: money.Money{Amount: 500, Currency: "EUR"},
+	})
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch for shipping in another currency, got %v", err)
+	}
+}
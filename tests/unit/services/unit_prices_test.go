@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestComputeUnitPrice(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		quantity float64
+		measure  string
+		want     int64
+		per      string
+	}{
+		{299, 500, "g", 598, "kg"},
+		{149, 1.5, "l", 99, "l"},
+		{89, 33, "cl", 270, "l"},
+		{1999, 250, "ml", 7996, "l"},
+		{450, 2, "kg", 225, "kg"},
+		{1250, 5, "m2", 250, "m2"},
+		{399, 150, "cm", 266, "m"},
+	}
+	for _, tt := range tests {
+		unitPrice, err := services.ComputeUnitPrice(money.Money{Amount: tt.amount, Currency: "EUR"}, tt.quantity, tt.measure)
+		if err != nil {
+			t.Fatalf("ComputeUnitPrice(%d, %v %s): unexpected error: %v", tt.amount, tt.quantity, tt.measure, err)
+		}
+		if unitPrice.Price.Amount != tt.want || unitPrice.Price.Currency != "EUR" || unitPrice.Per != tt.per {
+			t.Errorf("ComputeUnitPrice(%d, %v %s): expected %d EUR per %s, got %+v", tt.amount, tt.quantity, tt.measure, tt.want, tt.per, unitPrice)
+		}
+	}
+
+	for _, measure := range []string{"", "lb", "oz"} {
+		if _, err := services.ComputeUnitPrice(money.Money{Amount: 100, Currency: "EUR"}, 1, measure); err != services.ErrInvalidUnitMeasure {
+			t.Errorf("expected ErrInvalidUnitMeasure for %q, got %v", measure, err)
+		}
+	}
+	if _, err := services.ComputeUnitPrice(money.Money{Amount: 100, Currency: "EUR"}, 0, "kg"); err != services.ErrInvalidUnitMeasure {
+		t.Errorf("expected ErrInvalidUnitMeasure for a zero quantity, got %v", err)
+	}
+}
+
+func TestUnitPriceService_ProductVariants(t *testing.T) {
+	ctx := context.Background()
+	variants := mocks.NewMockVariantRepository()
+	variants.Variants["var-coffee-250"] = &catalog.Variant{ID: "var-coffee-250", ProductID: "prod-coffee", SKU: "COFFEE-250", Price: money.Money{Amount: 699, Currency: "EUR"}}
+	variants.Variants["var-coffee-1000"] = &catalog.Variant{ID: "var-coffee-1000", ProductID: "prod-coffee", SKU: "COFFEE-1000", Price: money.Money{Amount: 2199, Currency: "EUR"}}
+	variants.Variants["var-coffee-gift"] = &catalog.Variant{ID: "var-coffee-gift", ProductID: "prod-coffee", SKU: "COFFEE-GIFT", Price: money.Money{Amount: 2999, Currency: "EUR"}}
+
+	repo := mocks.NewMockUnitPriceRepository("prod-coffee", "var-coffee-250", "var-coffee-1000", "var-coffee-gift")
+	service := services.NewUnitPriceService(repo, variants)
+	if err := service.SetUnit(ctx, &services.VariantUnit{VariantID: "var-coffee-250", Quantity: 250, Measure: " G "}); err != nil {
+		t.Fatalf("SetUnit failed: %v", err)
+	}
+	if err := service.SetUnit(ctx, &services.VariantUnit{VariantID: "var-coffee-1000", Quantity: 1, Measure: "kg"}); err != nil {
+		t.Fatalf("SetUnit failed: %v", err)
+	}
+
+	responses, err := service.ProductVariants(ctx, "prod-coffee")
+	if err != nil {
+		t.Fatalf("ProductVariants failed: %v", err)
+	}
+	byID := make(map[string]*services.VariantResponse)
+	for _, response := range responses {
+		byID[response.ID] = response
+	}
+	if len(byID) != 3 {
+		t.Fatalf("expected 3 variants, got %d", len(byID))
+	}
+	if unitPrice := byID["var-coffee-250"].UnitPrice; unitPrice == nil || unitPrice.Price.Amount != 2796 || unitPrice.Per != "kg" {
+		t.Errorf("expected 27.96 EUR per kg for 250 g, got %+v", unitPrice)
+	}
+	if byID["var-coffee-250"].UnitMeasure != "g" || byID["var-coffee-250"].UnitQuantity != 250 {
+		t.Errorf("expected the normalized unit 250 g, got %v %s", byID["var-coffee-250"].UnitQuantity, byID["var-coffee-250"].UnitMeasure)
+	}
+	if unitPrice := byID["var-coffee-1000"].UnitPrice; unitPrice == nil || unitPrice.Price.Amount != 2199 {
+		t.Errorf("expected 21.99 EUR per kg for 1 kg, got %+v", unitPrice)
+	}
+	if byID["var-coffee-gift"].UnitPrice != nil {
+		t.Errorf("expected no unit price for a variant without a unit, got %+v", byID["var-coffee-gift"].UnitPrice)
+	}
+
+	// Unit prices follow the sale price while it applies
+	resolver := mocks.NewMockSalePriceResolver()
+	resolver.AddPrice("prod-coffee", 599, "EUR")
+	service.WithSalePriceResolver(resolver)
+	unitPrice, err := service.VariantUnitPrice(ctx, variants.Variants["var-coffee-250"])
+	if err != nil || unitPrice == nil || unitPrice.Price.Amount != 2396 {
+		t.Errorf("expected 23.96 EUR per kg on sale, got %+v (%v)", unitPrice, err)
+	}
+	responses, err = service.ProductVariants(ctx, "prod-coffee")
+	if err != nil || resolver.FindEffectiveVariantPricesCalls != 1 || resolver.FindEffectivePriceCalls != 1 {
+		t.Errorf("expected the variants priced in one batched lookup, got %d batched and %d single (%v)", resolver.FindEffectiveVariantPricesCalls, resolver.FindEffectivePriceCalls, err)
+	}
+	for _, response := range responses {
+		if response.SalePrice == nil || response.SalePrice.Amount != 599 {
+			t.Errorf("expected variant %s on sale at 599, got %+v", response.ID, response.SalePrice)
+		}
+	}
+
+	if err := service.RemoveUnit(ctx, "var-coffee-250"); err != nil {
+		t.Fatalf("RemoveUnit failed: %v", err)
+	}
+	if unitPrice, _ := service.VariantUnitPrice(ctx, variants.Variants["var-coffee-250"]); unitPrice != nil {
+		t.Errorf("expected no unit price after removing the unit, got %+v", unitPrice)
+	}
+}
+
+func TestUnitPriceService_SetUnit(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewUnitPriceService(mocks.NewMockUnitPriceRepository("prod-1", "var-1"), mocks.NewMockVariantRepository())
+
+	invalid := []*services.VariantUnit{
+		{VariantID: "var-1", Quantity: 0, Measure: "kg"},
+		{VariantID: "var-1", Quantity: -1, Measure: "kg"},
+		{VariantID: "var-1", Quantity: 1, Measure: "lb"},
+	}
+	for _, unit := range invalid {
+		if err := service.SetUnit(ctx, unit); err != services.ErrInvalidUnitMeasure {
+			t.Errorf("expected ErrInvalidUnitMeasure for %+v, got %v", unit, err)
+		}
+	}
+	if err := service.SetUnit(ctx, &services.VariantUnit{VariantID: "var-missing", Quantity: 1, Measure: "kg"}); err != services.ErrVariantNotFound {
+		t.Errorf("expected ErrVariantNotFound, got %v", err)
+	}
+}
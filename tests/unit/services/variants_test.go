@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce/catalog"
+	"github.com/devchuckcamp/gocommerce/money"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func newVariantService() (*services.VariantService, *mocks.MockVariantRepository, *[]string) {
+	products := mocks.NewMockProductRepository()
+	products.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	variants := mocks.NewMockVariantRepository()
+	variants.Variants["var-s-red"] = &catalog.Variant{
+		ID:          "var-s-red",
+		ProductID:   fixtures.ProductTShirt.ID,
+		SKU:         "TEE-S-RED",
+		Name:        "Tee - S Red",
+		Price:       money.Money{Amount: 2999, Currency: "USD"},
+		Attributes:  map[string]string{"size": "S", "color": "Red"},
+		IsAvailable: true,
+		CreatedAt:   time.Now().Add(-time.Hour),
+	}
+
+	var changed []string
+	service := services.NewVariantService(variants, products).
+		WithChangeHook(func(productIDs ...string) { changed = append(changed, productIDs...) })
+	return service, variants, &changed
+}
+
+func TestVariant_CreateAndOptions(t *testing.T) {
+	service, _, changed := newVariantService()
+	ctx := context.Background()
+
+	variant, err := service.CreateVariant(ctx, fixtures.ProductTShirt.ID, services.VariantInput{
+		SKU:        " TEE-M-BLUE ",
+		Name:       "Tee - M Blue",
+		Price:      3199,
+		Attributes: map[string]string{"Size": "M", "color": " Blue "},
+	})
+	if err != nil {
+		t.Fatalf("CreateVariant failed: %v", err)
+	}
+	if variant.SKU != "TEE-M-BLUE" || variant.Price.Currency != "USD" || !variant.IsAvailable {
+		t.Errorf("Expected a trimmed SKU, the product currency and an available variant, got %+v", variant)
+	}
+	if variant.Attributes["size"] != "M" || variant.Attributes["color"] != "Blue" {
+		t.Errorf("Expected attribute names lowercased and values trimmed, got %v", variant.Attributes)
+	}
+	if len(*changed) != 1 || (*changed)[0] != fixtures.ProductTShirt.ID {
+		t.Errorf("Expected the product reported as changed, got %v", *changed)
+	}
+
+	options, err := service.Options(ctx, fixtures.ProductTShirt.ID)
+	if err != nil {
+		t.Fatalf("Options failed: %v", err)
+	}
+	want := []services.VariantOption{
+		{Name: "color", Values: []string{"Red", "Blue"}},
+		{Name: "size", Values: []string{"S", "M"}},
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("Expected options %v, got %v", want, options)
+	}
+}
+
+func TestVariant_CreateValidatesAttributes(t *testing.T) {
+	service, _, _ := newVariantService()
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		input services.VariantInput
+		want  error
+	}{
+		"missing price": {
+			services.VariantInput{SKU: "TEE-L-RED", Name: "Tee", Attributes: map[string]string{"size": "L", "color": "Red"}},
+			services.ErrInvalidVariant,
+		},
+		"empty value": {
+			services.VariantInput{SKU: "TEE-L-RED", Name: "Tee", Price: 2999, Attributes: map[string]string{"size": "", "color": "Red"}},
+			services.ErrInvalidVariantAttributes,
+		},
+		"missing option": {
+			services.VariantInput{SKU: "TEE-L", Name: "Tee", Price: 2999, Attributes: map[string]string{"size": "L"}},
+			services.ErrVariantAttributesMismatch,
+		},
+		"same options": {
+			services.VariantInput{SKU: "TEE-S-RED-2", Name: "Tee", Price: 2999, Attributes: map[string]string{"size": "s", "color": "RED"}},
+			services.ErrDuplicateVariantOptions,
+		},
+		"taken SKU": {
+			services.VariantInput{SKU: "TEE-S-RED", Name: "Tee", Price: 2999, Attributes: map[string]string{"size": "L", "color": "Red"}},
+			services.ErrVariantSKUTaken,
+		},
+	}
+	for name, tc := range cases {
+		if _, err := service.CreateVariant(ctx, fixtures.ProductTShirt.ID, tc.input); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+
+	if _, err := service.CreateVariant(ctx, "no-such-product", services.VariantInput{SKU: "X", Name: "X", Price: 1}); err != services.ErrProductNotFound {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestVariant_UpdateAndDelete(t *testing.T) {
+	service, variants, changed := newVariantService()
+	ctx := context.Background()
+
+	unavailable := false
+	updated, err := service.UpdateVariant(ctx, "var-s-red", services.VariantInput{
+		SKU:         "TEE-S-RED",
+		Name:        "Tee - Small Red",
+		Price:       2499,
+		Attributes:  map[string]string{"size": "S", "color": "Red"},
+		IsAvailable: &unavailable,
+	})
+	if err != nil {
+		t.Fatalf("Expected a variant to keep its own SKU and attributes, got %v", err)
+	}
+	if updated.Name != "Tee - Small Red" || updated.Price.Amount != 2499 || updated.IsAvailable {
+		t.Errorf("Unexpected update %+v", updated)
+	}
+	if variants.Variants["var-s-red"].Price.Amount != 2499 {
+		t.Error("Expected the update saved")
+	}
+
+	options, _ := service.Options(ctx, fixtures.ProductTShirt.ID)
+	if len(options) != 0 {
+		t.Errorf("Expected unavailable variants left out of the options, got %v", options)
+	}
+
+	if err := service.DeleteVariant(ctx, "var-s-red"); err != nil {
+		t.Fatalf("DeleteVariant failed: %v", err)
+	}
+	if _, ok := variants.Variants["var-s-red"]; ok {
+		t.Error("Expected the variant deleted")
+	}
+	if err := service.DeleteVariant(ctx, "var-s-red"); err != services.ErrVariantNotFound {
+		t.Errorf("Expected ErrVariantNotFound, got %v", err)
+	}
+	if len(*changed) != 2 {
+		t.Errorf("Expected the product reported as changed by the update and delete, got %v", *changed)
+	}
+}
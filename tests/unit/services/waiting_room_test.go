@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+func TestWaitingRoom_QueueAndAdmission(t *testing.T) {
+	ctx := context.Background()
+	productID := fixtures.ProductLaptop.ID
+	repo := mocks.NewMockWaitingRoomRepository()
+	waitingRoom := services.NewWaitingRoomService(repo, services.WaitingRoomPolicy{
+		Products:      []string{productID},
+		AdmitBatch:    1,
+		AdmitInterval: 30 * time.Second,
+		AccessTTL:     10 * time.Minute,
+	})
+
+	if err := waitingRoom.Authorize(ctx, "prod-other", fixtures.TestUserID, ""); err != nil {
+		t.Fatalf("expected products that aren't drops to need no token, got %v", err)
+	}
+	if _, err := waitingRoom.Join(ctx, "prod-other", fixtures.TestUserID); err != services.ErrNotADrop {
+		t.Fatalf("expected ErrNotADrop, got %v", err)
+	}
+
+	first, err := waitingRoom.Join(ctx, productID, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := waitingRoom.Join(ctx, productID, "user-second")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Position != 1 || second.Position != 2 {
+		t.Fatalf("expected positions 1 and 2, got %d and %d", first.Position, second.Position)
+	}
+	if second.EstimatedWaitSeconds != 60 {
+		t.Fatalf("expected the second customer to wait two 30s batches, got %ds", second.EstimatedWaitSeconds)
+	}
+
+	// Joining again keeps the customer's place
+	again, err := waitingRoom.Join(ctx, productID, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Token != first.Token || again.Position != 1 {
+		t.Fatalf("expected the same ticket at position 1, got %+v", again)
+	}
+
+	if err := waitingRoom.Authorize(ctx, productID, fixtures.TestUserID, ""); err != services.ErrWaitingRoomRequired {
+		t.Fatalf("expected ErrWaitingRoomRequired without a token, got %v", err)
+	}
+	if err := waitingRoom.Authorize(ctx, productID, fixtures.TestUserID, first.Token); err != services.ErrWaitingRoomNotAdmitted {
+		t.Fatalf("expected ErrWaitingRoomNotAdmitted before admission, got %v", err)
+	}
+
+	admitted, err := waitingRoom.AdmitNext(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if admitted != 1 {
+		t.Fatalf("expected one customer admitted per batch, got %d", admitted)
+	}
+	if err := waitingRoom.Authorize(ctx, productID, fixtures.TestUserID, first.Token); err != nil {
+		t.Fatalf("expected the admitted token to be accepted, got %v", err)
+	}
+	if err := waitingRoom.Authorize(ctx, productID, "user-second", first.Token); err != services.ErrWaitingRoomRequired {
+		t.Fatalf("expected another customer's token to be refused, got %v", err)
+	}
+
+	status, err := waitingRoom.Status(ctx, productID, "user-second")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != services.WaitingRoomWaiting || status.Position != 1 {
+		t.Fatalf("expected the second customer to move up to position 1, got %+v", status)
+	}
+}
+
+func TestWaitingRoom_ExpiredAccessRejoins(t *testing.T) {
+	ctx := context.Background()
+	productID := fixtures.ProductLaptop.ID
+	repo := mocks.NewMockWaitingRoomRepository()
+	waitingRoom := services.NewWaitingRoomService(repo, services.WaitingRoomPolicy{
+		Products:      []string{productID},
+		AdmitBatch:    10,
+		AdmitInterval: time.Minute,
+		AccessTTL:     time.Minute,
+	})
+
+	ticket, err := waitingRoom.Join(ctx, productID, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := waitingRoom.AdmitNext(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Access ran out a second ago
+	stored := repo.Tickets[productID+"/"+fixtures.TestUserID]
+	expiredAt := time.Now().Add(-time.Second)
+	stored.ExpiresAt = &expiredAt
+
+	if err := waitingRoom.Authorize(ctx, productID, fixtures.TestUserID, ticket.Token); err != services.ErrWaitingRoomExpired {
+		t.Fatalf("expected ErrWaitingRoomExpired, got %v", err)
+	}
+
+	rejoined, err := waitingRoom.Join(ctx, productID, fixtures.TestUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rejoined.Token == ticket.Token || rejoined.Status != services.WaitingRoomWaiting || rejoined.Position != 1 {
+		t.Fatalf("expected a new waiting ticket, got %+v", rejoined)
+	}
+	if err := waitingRoom.Authorize(ctx, productID, fixtures.TestUserID, ticket.Token); err != services.ErrWaitingRoomRequired {
+		t.Fatalf("expected the old token to be refused, got %v", err)
+	}
+}
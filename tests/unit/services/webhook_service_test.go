@@ -0,0 +1,166 @@
+package services_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/jobs"
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// stubWebhookProvider verifies with a shared secret and records what it processes
+type stubWebhookProvider struct {
+	services.HMACVerifier
+	processErr error
+	processed  []string
+}
+
+func (p *stubWebhookProvider) Identify(body []byte) (string, string, error) {
+	var payload struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", "", err
+	}
+	return payload.ID, payload.Type, nil
+}
+
+func (p *stubWebhookProvider) Process(ctx context.Context, event *services.WebhookEvent) error {
+	p.processed = append(p.processed, event.ExternalID)
+	return p.processErr
+}
+
+func newWebhookFixture(t *testing.T) (*services.WebhookService, *stubWebhookProvider, *mocks.MockWebhookEventRepository) {
+	t.Helper()
+	repo := mocks.NewMockWebhookEventRepository()
+	// The runner is never started; tests drive processing through Process
+	svc := services.NewWebhookService(repo, jobs.NewRunner(1, 10))
+	provider := &stubWebhookProvider{
+		HMACVerifier: services.HMACVerifier{Header: "X-Signature", Secret: []byte(testWebhookSecret)},
+	}
+	if err := svc.RegisterProvider("carrier", provider); err != nil {
+		t.Fatalf("RegisterProvider: %v", err)
+	}
+	return svc, provider, repo
+}
+
+func signedHeader(body []byte) http.Header {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestWebhookService_Receive(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"id":"evt_1","type":"shipment.delivered"}`)
+
+	t.Run("stores a verified event as pending", func(t *testing.T) {
+		svc, _, repo := newWebhookFixture(t)
+
+		event, duplicate, err := svc.Receive(ctx, "Carrier", signedHeader(body), body)
+		if err != nil {
+			t.Fatalf("Receive: %v", err)
+		}
+		if duplicate {
+			t.Error("first delivery reported as duplicate")
+		}
+		if event.Provider != "carrier" || event.ExternalID != "evt_1" || event.EventType != "shipment.delivered" {
+			t.Errorf("unexpected event %+v", event)
+		}
+		if stored := repo.Events[event.ID]; stored == nil || stored.Status != services.WebhookEventStatusPending {
+			t.Errorf("event not stored as pending: %+v", stored)
+		}
+	})
+
+	t.Run("redelivery returns the stored event", func(t *testing.T) {
+		svc, _, repo := newWebhookFixture(t)
+
+		first, _, _ := svc.Receive(ctx, "carrier", signedHeader(body), body)
+		again, duplicate, err := svc.Receive(ctx, "carrier", signedHeader(body), body)
+		if err != nil {
+			t.Fatalf("Receive: %v", err)
+		}
+		if !duplicate || again.ID != first.ID {
+			t.Errorf("expected duplicate of %s, got %s (duplicate=%v)", first.ID, again.ID, duplicate)
+		}
+		if len(repo.Events) != 1 {
+			t.Errorf("expected 1 stored event, got %d", len(repo.Events))
+		}
+	})
+
+	t.Run("rejects bad signatures, payloads and providers", func(t *testing.T) {
+		svc, _, _ := newWebhookFixture(t)
+
+		if _, _, err := svc.Receive(ctx, "carrier", http.Header{"X-Signature": {"sha256=00"}}, body); err != services.ErrInvalidWebhookSignature {
+			t.Errorf("bad signature: expected ErrInvalidWebhookSignature, got %v", err)
+		}
+		garbled := []byte(`{"id":`)
+		if _, _, err := svc.Receive(ctx, "carrier", signedHeader(garbled), garbled); err != services.ErrInvalidWebhookPayload {
+			t.Errorf("bad payload: expected ErrInvalidWebhookPayload, got %v", err)
+		}
+		if _, _, err := svc.Receive(ctx, "unknown", signedHeader(body), body); err != services.ErrWebhookProviderUnknown {
+			t.Errorf("unknown provider: expected ErrWebhookProviderUnknown, got %v", err)
+		}
+	})
+}
+
+func TestWebhookService_ProcessAndReplay(t *testing.T) {
+	ctx := context.Background()
+	svc, provider, repo := newWebhookFixture(t)
+	body := []byte(`{"id":"evt_2","type":"shipment.exception"}`)
+
+	event, _, err := svc.Receive(ctx, "carrier", signedHeader(body), body)
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+
+	provider.processErr = errors.New("carrier API unavailable")
+	if err := svc.Process(ctx, event.ID); err == nil {
+		t.Fatal("expected processing error")
+	}
+	failed := repo.Events[event.ID]
+	if failed.Status != services.WebhookEventStatusFailed || failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("expected failed event with 1 attempt, got %+v", failed)
+	}
+
+	replayed, err := svc.Replay(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if replayed.Status != services.WebhookEventStatusPending || replayed.LastError != "" {
+		t.Errorf("expected replayed event to be pending, got %+v", replayed)
+	}
+
+	provider.processErr = nil
+	if err := svc.Process(ctx, event.ID); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	processed := repo.Events[event.ID]
+	if processed.Status != services.WebhookEventStatusProcessed || processed.Attempts != 2 || processed.ProcessedAt == nil {
+		t.Errorf("expected processed event after 2 attempts, got %+v", processed)
+	}
+
+	// Processing again is a no-op
+	if err := svc.Process(ctx, event.ID); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(provider.processed) != 2 {
+		t.Errorf("expected provider to run twice, ran %d times", len(provider.processed))
+	}
+
+	if _, err := svc.Replay(ctx, "missing"); err != services.ErrWebhookEventNotFound {
+		t.Errorf("expected ErrWebhookEventNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devchuckcamp/gocommerce/cart"
+	"github.com/devchuckcamp/gocommerce/orders"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/services"
+	"github.com/devchuckcamp/gocommerce-api/tests/fixtures"
+	"github.com/devchuckcamp/gocommerce-api/tests/mocks"
+)
+
+const registryOwnerID = "registry-owner"
+
+func newWishlistService(t *testing.T) (*services.WishlistService, *mocks.MockWishlistRepository, *mocks.MockProductRepository) {
+	t.Helper()
+	productRepo := mocks.NewMockProductRepository()
+	productRepo.Products[fixtures.ProductLaptop.ID] = fixtures.ProductLaptop
+	productRepo.Products[fixtures.ProductTShirt.ID] = fixtures.ProductTShirt
+	repo := mocks.NewMockWishlistRepository()
+	return services.NewWishlistService(repo, productRepo), repo, productRepo
+}
+
+// createRegistry creates a public wishlist wanting two t-shirts
+func createRegistry(t *testing.T, wishlists *services.WishlistService) (*services.Wishlist, *services.WishlistItem) {
+	t.Helper()
+	ctx := context.Background()
+	wishlist, err := wishlists.CreateWishlist(ctx, registryOwnerID, services.WishlistRequest{Name: "Wedding", IsPublic: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wishlist, err = wishlists.AddItem(ctx, registryOwnerID, wishlist.ID, services.WishlistItemRequest{ProductID: fixtures.ProductTShirt.ID, Quantity: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return wishlist, &wishlist.Items[0]
+}
+
+func registryCart(id, itemID string, quantity int) *cart.Cart {
+	c := &cart.Cart{ID: id, UserID: fixtures.TestUserID}
+	c.AddItem(cart.CartItem{
+		ID: "item-1", ProductID: fixtures.ProductTShirt.ID, SKU: fixtures.ProductTShirt.SKU,
+		Price: fixtures.ProductTShirt.BasePrice, Quantity: quantity,
+		Attributes: map[string]string{services.WishlistItemAttribute: itemID},
+	})
+	return c
+}
+
+func TestWishlists_PublicRegistry(t *testing.T) {
+	ctx := context.Background()
+	wishlists, _, _ := newWishlistService(t)
+
+	private, err := wishlists.CreateWishlist(ctx, registryOwnerID, services.WishlistRequest{Name: "Just for me"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if private.ShareToken != "" {
+		t.Errorf("expected no share token for a private wishlist, got %q", private.ShareToken)
+	}
+	if _, err := wishlists.GetWishlist(ctx, fixtures.TestUserID, private.ID); !errors.Is(err, services.ErrWishlistNotFound) {
+		t.Errorf("expected another user's wishlist reported as not found, got %v", err)
+	}
+
+	shared, err := wishlists.UpdateWishlist(ctx, registryOwnerID, private.ID, services.WishlistRequest{Name: "Birthday", IsPublic: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared.ShareToken == "" {
+		t.Fatal("expected a share token once public")
+	}
+	if _, err := wishlists.AddItem(ctx, registryOwnerID, shared.ID, services.WishlistItemRequest{ProductID: fixtures.ProductLaptop.ID, Quantity: 1, Note: "silver please"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	registry, err := wishlists.GetRegistry(ctx, shared.ShareToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if registry.Name != "Birthday" || len(registry.Items) != 1 || registry.Items[0].Note != "silver please" {
+		t.Errorf("expected the registry with its item, got %+v", registry)
+	}
+
+	// Going private hides the registry; going public again keeps the link shared
+	token := shared.ShareToken
+	if _, err := wishlists.UpdateWishlist(ctx, registryOwnerID, shared.ID, services.WishlistRequest{Name: "Birthday"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := wishlists.GetRegistry(ctx, token); !errors.Is(err, services.ErrWishlistNotFound) {
+		t.Errorf("expected a private wishlist's registry reported as not found, got %v", err)
+	}
+	again, err := wishlists.UpdateWishlist(ctx, registryOwnerID, shared.ID, services.WishlistRequest{Name: "Birthday", IsPublic: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.ShareToken != token {
+		t.Errorf("expected the share token kept, got %q instead of %q", again.ShareToken, token)
+	}
+
+	if _, err := wishlists.AddItem(ctx, registryOwnerID, shared.ID, services.WishlistItemRequest{ProductID: "missing", Quantity: 1}); !errors.Is(err, services.ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+}
+
+func TestWishlists_GuestsBuyForTheOwner(t *testing.T) {
+	ctx := context.Background()
+	wishlists, repo, productRepo := newWishlistService(t)
+	_, item := createRegistry(t, wishlists)
+	cartService := services.NewCartService(mocks.NewMockCartRepository(), productRepo, mocks.NewMockVariantRepository(), nil).
+		WithWishlists(wishlists)
+	guestCart, err := cartService.GetOrCreateCart(ctx, "guest-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	add := func(productID, itemID string, quantity int) (*cart.Cart, error) {
+		req := cart.AddItemRequest{ProductID: productID, Quantity: quantity}
+		if itemID != "" {
+			req.Attributes = map[string]string{services.WishlistItemAttribute: itemID}
+		}
+		return cartService.AddItem(ctx, guestCart.ID, req)
+	}
+
+	if _, err := add(fixtures.ProductTShirt.ID, item.ID, 3); !errors.Is(err, services.ErrRegistryItemPurchased) {
+		t.Errorf("expected ErrRegistryItemPurchased for more than are wanted, got %v", err)
+	}
+	if _, err := add(fixtures.ProductLaptop.ID, item.ID, 1); !errors.Is(err, services.ErrRegistryItemUnavailable) {
+		t.Errorf("expected ErrRegistryItemUnavailable for another product, got %v", err)
+	}
+	updated, err := add(fixtures.ProductTShirt.ID, item.ID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := add(fixtures.ProductTShirt.ID, "", 1); !errors.Is(err, services.ErrRegistryItemInCart) {
+		t.Errorf("expected ErrRegistryItemInCart mixing own units with the gift's, got %v", err)
+	}
+	if _, err := add(fixtures.ProductTShirt.ID, item.ID, 2); !errors.Is(err, services.ErrRegistryItemPurchased) {
+		t.Errorf("expected ErrRegistryItemPurchased counting the units already in the cart, got %v", err)
+	}
+	if _, err := cartService.UpdateItemQuantity(ctx, guestCart.ID, updated.Items[0].ID, 3); !errors.Is(err, services.ErrRegistryItemPurchased) {
+		t.Errorf("expected ErrRegistryItemPurchased raising the quantity, got %v", err)
+	}
+
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil).WithWishlists(wishlists)
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: registryCart("cart-1", item.ID, 2), UserID: "guest-1", ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Items[item.ID].Purchased != 2 {
+		t.Fatalf("expected both units marked bought, got %d", repo.Items[item.ID].Purchased)
+	}
+	if len(repo.Purchases) != 1 || repo.Purchases[0].BuyerID != "guest-1" || repo.Purchases[0].OrderID != order.ID {
+		t.Errorf("expected the purchase recorded for the guest's order, got %+v", repo.Purchases)
+	}
+
+	// The owner sees what was bought and can't want fewer than that
+	owned, err := wishlists.GetWishlist(ctx, registryOwnerID, item.WishlistID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owned.Items[0].Purchased != 2 || owned.Items[0].Remaining() != 0 {
+		t.Errorf("expected the item fully bought, got %+v", owned.Items[0])
+	}
+	if _, err := wishlists.UpdateItem(ctx, registryOwnerID, item.WishlistID, item.ID, 1, ""); !errors.Is(err, services.ErrInvalidWishlistItem) {
+		t.Errorf("expected ErrInvalidWishlistItem below the units bought, got %v", err)
+	}
+}
+
+func TestWishlists_NoDuplicatePurchases(t *testing.T) {
+	ctx := context.Background()
+	wishlists, repo, _ := newWishlistService(t)
+	_, item := createRegistry(t, wishlists)
+	pricingService := services.NewPricingService(mocks.NewMockPromotionRepository(), services.NewSimpleTaxCalculator(0), nil)
+	orderService := services.NewOrderService(mocks.NewMockOrderRepository(), pricingService.Service, nil, nil).WithWishlists(wishlists)
+
+	// Two guests have the gift in their carts; the second checkout finds it already bought
+	first := registryCart("cart-1", item.ID, 2)
+	second := registryCart("cart-2", item.ID, 1)
+	order, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: first, UserID: "guest-1", ShippingAddress: appointmentAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: second, UserID: "guest-2", ShippingAddress: appointmentAddress}); !errors.Is(err, services.ErrRegistryItemPurchased) {
+		t.Errorf("expected ErrRegistryItemPurchased for a gift already bought, got %v", err)
+	}
+	if repo.Items[item.ID].Purchased != 2 {
+		t.Errorf("expected still 2 units bought, got %d", repo.Items[item.ID].Purchased)
+	}
+
+	// Canceling gives the units back to the registry
+	if _, err := orderService.CancelOrder(ctx, order.ID, "changed mind"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Items[item.ID].Purchased != 0 || repo.Purchases[0].Status != services.WishlistPurchaseCanceled {
+		t.Errorf("expected the units given back, got %d bought and %+v", repo.Items[item.ID].Purchased, repo.Purchases[0])
+	}
+	if _, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: second, UserID: "guest-2", ShippingAddress: appointmentAddress}); err != nil {
+		t.Errorf("expected the gift buyable again, got %v", err)
+	}
+
+	// Private wishlists can't be bought from
+	wishlist := repo.Wishlists[item.WishlistID]
+	wishlist.IsPublic = false
+	if _, err := orderService.CreateFromCart(ctx, orders.CreateOrderRequest{Cart: registryCart("cart-3", item.ID, 1), UserID: "guest-3", ShippingAddress: appointmentAddress}); !errors.Is(err, services.ErrRegistryItemUnavailable) {
+		t.Errorf("expected ErrRegistryItemUnavailable for a private wishlist, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package startup_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/startup"
+)
+
+// checks returns a passing and a failing check, counting how often they run
+func checks(runs *int) []startup.Check {
+	return []startup.Check{
+		{Name: "schema", Run: func(ctx context.Context) error { *runs++; return nil }},
+		{Name: "provider:smtp", Run: func(ctx context.Context) error { *runs++; return errors.New("connection refused") }},
+	}
+}
+
+func TestRun_FailModeRefusesToStart(t *testing.T) {
+	var runs int
+	report, err := startup.Run(context.Background(), startup.ModeFail, checks(&runs))
+	if !errors.Is(err, startup.ErrChecksFailed) {
+		t.Fatalf("Expected ErrChecksFailed, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "provider:smtp") {
+		t.Errorf("Expected the error to name the failed check, got %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("Expected every check to run, got %d", runs)
+	}
+	if report.Degraded {
+		t.Error("Expected a refused start not to be marked degraded")
+	}
+	if report.Checks[1].OK || report.Checks[1].Error != "connection refused" {
+		t.Errorf("Unexpected result %+v", report.Checks[1])
+	}
+}
+
+func TestRun_DegradedModeStarts(t *testing.T) {
+	var runs int
+	report, err := startup.Run(context.Background(), startup.ModeDegraded, checks(&runs))
+	if err != nil {
+		t.Fatalf("Expected to start degraded, got %v", err)
+	}
+	if !report.Degraded {
+		t.Error("Expected the report to be marked degraded")
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0] != "provider:smtp" {
+		t.Errorf("Expected provider:smtp to have failed, got %v", failed)
+	}
+
+	passing := checks(&runs)[:1]
+	if report, err := startup.Run(context.Background(), startup.ModeFail, passing); err != nil || report.Degraded {
+		t.Errorf("Expected passing checks to start normally, got %+v, %v", report, err)
+	}
+}
+
+func TestRun_OffSkipsChecks(t *testing.T) {
+	var runs int
+	report, err := startup.Run(context.Background(), startup.ModeOff, checks(&runs))
+	if err != nil || runs != 0 || len(report.Checks) != 0 {
+		t.Errorf("Expected no checks to run, got %d runs, %v", runs, err)
+	}
+}
+
+func TestSecretsCheck(t *testing.T) {
+	env := map[string]string{"PAYMENT_WEBHOOK_SECRET": "whsec", "SMTP_PASSWORD": " "}
+	lookup := func(name string) string { return env[name] }
+
+	err := startup.SecretsCheck([]string{"PAYMENT_WEBHOOK_SECRET", "SMTP_PASSWORD", "CDN_PURGE_TOKEN"}, lookup).Run(context.Background())
+	if err == nil || err.Error() != "missing SMTP_PASSWORD, CDN_PURGE_TOKEN" {
+		t.Errorf("Expected the blank and unset secrets to be named, got %v", err)
+	}
+	if err := startup.SecretsCheck([]string{"PAYMENT_WEBHOOK_SECRET"}, lookup).Run(context.Background()); err != nil {
+		t.Errorf("Expected a set secret to pass, got %v", err)
+	}
+}
+
+func TestDialCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := listener.Addr().String()
+
+	if err := startup.DialCheck("provider:smtp", addr, time.Second).Run(context.Background()); err != nil {
+		t.Errorf("Expected a listening provider to pass, got %v", err)
+	}
+	listener.Close()
+	if err := startup.DialCheck("provider:smtp", addr, time.Second).Run(context.Background()); err == nil {
+		t.Error("Expected a closed port to fail")
+	}
+}
+
+func TestURLAddr(t *testing.T) {
+	cases := map[string]string{
+		"https://api.hcaptcha.com/siteverify": "api.hcaptcha.com:443",
+		"http://imgproxy.internal":            "imgproxy.internal:80",
+		"http://localhost:8081/purge":         "localhost:8081",
+	}
+	for raw, want := range cases {
+		if got, err := startup.URLAddr(raw); err != nil || got != want {
+			t.Errorf("URLAddr(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"imgproxy.internal", "ftp://files.example.com"} {
+		if _, err := startup.URLAddr(raw); err == nil {
+			t.Errorf("Expected URLAddr(%q) to fail", raw)
+		}
+	}
+}
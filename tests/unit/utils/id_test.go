@@ -0,0 +1,78 @@
+package utils_test
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/devchuckcamp/gocommerce-api/internal/utils"
+)
+
+var ulidPattern = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+func TestNewULID_SortsByTime(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = utils.NewULID(start.Add(time.Duration(i) * time.Millisecond))
+		if !ulidPattern.MatchString(ids[i]) {
+			t.Fatalf("expected a 26 character Crockford base32 ULID, got %q", ids[i])
+		}
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("expected ULIDs to sort by time, got %v", ids)
+	}
+
+	// The leading 10 characters are the timestamp
+	if a, b := utils.NewULID(start), utils.NewULID(start); a[:10] != b[:10] || a == b {
+		t.Errorf("expected the same time prefix with different randomness, got %s and %s", a, b)
+	}
+	if got := utils.NewULID(time.UnixMilli(0))[:10]; got != "0000000000" {
+		t.Errorf("expected the epoch to encode as zeros, got %s", got)
+	}
+}
+
+func TestGenerateID_Strategies(t *testing.T) {
+	defer utils.SetIDStrategy(utils.IDUUIDv4)
+
+	utils.SetIDStrategy(utils.IDUUIDv4)
+	if id, err := uuid.Parse(utils.GenerateID()); err != nil || id.Version() != 4 {
+		t.Errorf("expected a UUID v4, got %v (%v)", id, err)
+	}
+
+	utils.SetIDStrategy(utils.IDUUIDv7)
+	first := utils.GenerateID()
+	time.Sleep(2 * time.Millisecond)
+	second := utils.GenerateID()
+	if id, err := uuid.Parse(first); err != nil || id.Version() != 7 {
+		t.Errorf("expected a UUID v7, got %v (%v)", id, err)
+	}
+	if first >= second {
+		t.Errorf("expected UUID v7 IDs to sort by creation, got %s then %s", first, second)
+	}
+
+	utils.SetIDStrategy(utils.IDULID)
+	if id := utils.GenerateID(); !ulidPattern.MatchString(id) {
+		t.Errorf("expected a ULID, got %q", id)
+	}
+}
+
+func TestParseIDStrategy(t *testing.T) {
+	tests := map[string]utils.IDStrategy{
+		"":       utils.IDUUIDv4,
+		"uuidv4": utils.IDUUIDv4,
+		"UUIDv7": utils.IDUUIDv7,
+		" ulid ": utils.IDULID,
+	}
+	for input, want := range tests {
+		if got, err := utils.ParseIDStrategy(input); err != nil || got != want {
+			t.Errorf("ParseIDStrategy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := utils.ParseIDStrategy("snowflake"); err == nil {
+		t.Error("expected an unknown strategy to fail")
+	}
+}